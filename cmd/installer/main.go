@@ -12,6 +12,17 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/daemon"
+	"github.com/Nomadcxx/jellysink/internal/ui"
+)
+
+// Version information (set via -ldflags during build)
+var (
+	version   = "dev"
+	commit    = "unknown"
+	buildTime = "unknown"
 )
 
 // Theme colors - RAMA
@@ -409,6 +420,23 @@ func (m model) renderConfigPrompt() string {
 func (m model) renderInstalling() string {
 	var b strings.Builder
 
+	// Overall progress uses the same bar as the jellysink scan/clean screens
+	progress := ui.Progress{Total: len(m.tasks)}
+	for _, task := range m.tasks {
+		switch task.status {
+		case statusComplete, statusSkipped:
+			progress.Current++
+		case statusFailed:
+			progress.Current++
+			progress.Errors++
+		}
+	}
+	if progress.Total > 0 {
+		progress.Percent = float64(progress.Current) * 100 / float64(progress.Total)
+	}
+	b.WriteString(progress.View(0))
+	b.WriteString("\n")
+
 	// Render all tasks with their current status
 	for i, task := range m.tasks {
 		var line string
@@ -555,15 +583,41 @@ func checkPrivileges(m *model) error {
 	return nil
 }
 
+// gitOutput returns the trimmed output of a git command run in the
+// checkout, or fallback when git can't answer
+func gitOutput(fallback string, args ...string) string {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil || strings.TrimSpace(string(out)) == "" {
+		return fallback
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// versionLDFlags stamps version, commit and build time the way the Makefile
+// does, so installed binaries report the checkout they were built from.
+// The build time is the commit's, which keeps rebuilds identical
+func versionLDFlags() string {
+	built := buildTime
+	if epoch, err := strconv.ParseInt(gitOutput("", "log", "-1", "--format=%ct"), 10, 64); err == nil {
+		built = time.Unix(epoch, 0).UTC().Format("2006-01-02_15:04:05")
+	}
+	return fmt.Sprintf("-X main.version=%s -X main.commit=%s -X main.buildTime=%s -s -w",
+		gitOutput(version, "describe", "--tags", "--always", "--dirty"),
+		gitOutput(commit, "rev-parse", "--short", "HEAD"),
+		built)
+}
+
 func buildBinaries(m *model) error {
+	ldflags := versionLDFlags()
+
 	// Build main binary
-	cmd := exec.Command("go", "build", "-buildvcs=false", "-o", "jellysink", "./cmd/jellysink/")
+	cmd := exec.Command("go", "build", "-buildvcs=false", "-trimpath", "-ldflags", ldflags, "-o", "jellysink", "./cmd/jellysink/")
 	if output, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to build jellysink: %s", string(output))
 	}
 
 	// Build daemon
-	cmd = exec.Command("go", "build", "-buildvcs=false", "-o", "jellysinkd", "./cmd/jellysinkd/")
+	cmd = exec.Command("go", "build", "-buildvcs=false", "-trimpath", "-ldflags", ldflags, "-o", "jellysinkd", "./cmd/jellysinkd/")
 	if output, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to build jellysinkd: %s", string(output))
 	}
@@ -630,6 +684,8 @@ paths = ["/path/to/your/tvshows"]
 
 [daemon]
 scan_frequency = "weekly"  # daily, weekly, biweekly
+scan_time = "02:00"        # when jellysinkd --daemon scans (weekly/biweekly on Sundays)
+scan_schedule = ""         # overrides both: a cron expression or OnCalendar string, e.g. "Mon,Thu 03:30"
 `
 
 	if err := os.WriteFile(configPath, []byte(defaultConfig), 0644); err != nil {
@@ -673,6 +729,14 @@ func installSystemdFiles(m *model) error {
 		}
 	}
 
+	// The shipped timer runs weekly at 02:00; a kept config may schedule
+	// scans differently, so regenerate the timer from it
+	if cfg, err := config.Load(); err == nil {
+		if schedule, err := daemon.ConfiguredSchedule(cfg.Daemon); err == nil {
+			return daemon.InstallSystemdTimer(daemon.SystemdTimerPath, schedule)
+		}
+	}
+
 	// Reload systemd
 	cmd := exec.Command("systemctl", "daemon-reload")
 	if err := cmd.Run(); err != nil {
@@ -721,6 +785,13 @@ func removeSystemdFiles(m *model) error {
 }
 
 func main() {
+	if len(os.Args) > 1 && (os.Args[1] == "--version" || os.Args[1] == "version") {
+		fmt.Printf("install-jellysink %s\n", version)
+		fmt.Printf("  Commit:     %s\n", commit)
+		fmt.Printf("  Built:      %s\n", buildTime)
+		return
+	}
+
 	// Check for Go
 	if _, err := exec.LookPath("go"); err != nil {
 		fmt.Println("Error: Go is not installed or not in PATH")
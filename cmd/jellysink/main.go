@@ -1,14 +1,20 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
@@ -16,16 +22,49 @@ import (
 	"github.com/Nomadcxx/jellysink/internal/cleaner"
 	"github.com/Nomadcxx/jellysink/internal/config"
 	"github.com/Nomadcxx/jellysink/internal/daemon"
+	"github.com/Nomadcxx/jellysink/internal/fixtures"
+	"github.com/Nomadcxx/jellysink/internal/oplog"
 	"github.com/Nomadcxx/jellysink/internal/reporter"
 	"github.com/Nomadcxx/jellysink/internal/scanner"
+	"github.com/Nomadcxx/jellysink/internal/schema"
+	"github.com/Nomadcxx/jellysink/internal/tags"
 	"github.com/Nomadcxx/jellysink/internal/ui"
 )
 
 var (
-	cfgFile string
-	dryRun  bool
-	quiet   bool
-	verbose bool
+	cfgFile     string
+	dryRun      bool
+	quiet       bool
+	verbose     bool
+	minSeverity string
+	explainID   string
+	wasteFormat string
+	wasteDepth  int
+	demoNoTUI   bool
+	noTUI       bool
+	forceClean  bool
+	planOutput  string
+	planJSON    bool
+	diffJSON    bool
+	exportFmt   string
+	exportOut   string
+	exportThumb bool
+	diffPending bool
+	tagFilter   string
+	untag       bool
+	cleanJunk   bool
+	deleteJunk  bool
+	incremental bool
+	resumeScan  string
+	scanLibrary []string
+	assumeYes   bool
+	runJSON     bool
+	auditSince  string
+	auditUntil  string
+	auditFormat string
+	auditOut    string
+	invFormat   string
+	invOut      string
 
 	// Version information (set via -ldflags during build)
 	version   = "dev"
@@ -33,14 +72,173 @@ var (
 	buildTime = "unknown"
 )
 
-const exampleConfig = `[libraries.movies]
+const exampleConfig = `data_dir = ""  # reports, caches, journal and trash; default $XDG_DATA_HOME/jellysink or ~/.local/share/jellysink (JELLYSINK_DATA_DIR overrides)
+
+[libraries]
+# exclude_dirs = ["@eaDir", "#recycle", ".@__thumb", ".Recycle.Bin"]  # folder names never scanned; default covers common NAS/system folders
+
+[libraries.movies]
 paths = ["/path/to/your/movies"]
+# duplicate_scope = "library"  # overrides [duplicates] scope for movies
 
 [libraries.tv]
 paths = ["/path/to/your/tvshows"]
+# duplicate_scope = "same-folder"  # overrides [duplicates] scope for TV
+
+# [libraries.anime]
+# paths = ["/path/to/your/anime"]  # scanned as TV with absolute numbering, [Group] tags and CRC suffixes
+# lookup = "anilist"               # check series titles and years against AniList (no key needed)
+
+# [libraries.tv.overrides]  # any library can replace [daemon] and [duplicates] settings
+# scan_frequency = "daily"
+
+# [[libraries.library]]     # more libraries, each scanned by type with its own settings
+# name = "4k"
+# type = "movies"           # movies, tv or anime
+# paths = ["/path/to/your/4k-movies"]
+# [libraries.library.overrides]
+# scan_frequency = "biweekly"
+# strategy = "multi-version"
+
+[scan]
+exclude = []               # never scanned or cleaned: globs like "**/Extras/**", "*.iso", or "re:" regular expressions; see also .jellysinkignore files
+allow_versions = false     # keep other editions and resolutions of a movie as Jellyfin versions instead of duplicates
+bitrate_outlier_factor = 0 # e.g. 3: report episodes 3x larger or smaller per minute than the rest of their season; 0 = off
+
+[languages]
+required_audio = []       # e.g. ["eng"]: report files without an audio track in any of these (needs ffprobe)
+required_subtitles = []   # report files without a subtitle track in any of these
+wanted = []               # other languages worth keeping besides the required ones
+max_unwanted_tracks = 0   # report files with more audio/subtitle tracks in other languages than this; 0 = off
 
 [daemon]
 scan_frequency = "weekly"  # daily, weekly, biweekly
+scan_time = "02:00"        # when jellysinkd --daemon scans (weekly/biweekly on Sundays)
+scan_schedule = ""         # overrides both: a cron expression or OnCalendar string, e.g. "Mon,Thu 03:30"
+terminal_command = ""      # opens reports for review, e.g. "alacritty -e {command}"; empty tries kitty, alacritty, gnome-terminal, konsole, xterm, then notify-send
+http_addr = ""             # e.g. "127.0.0.1:8787" to serve jellysinkd --daemon's status/control API
+http_token = ""            # bearer token the API requires; set one if http_addr is reachable by others
+auto_clean_severities = ["info", "warn", "error"]  # compliance issues auto-clean may fix
+auto_clean_confirm_scans = 2  # scans in a row a duplicate group must appear in unchanged before auto-clean deletes it
+watch_debounce = 30        # seconds without changes before jellysinkd --watch processes them
+watch_auto_fix = false     # let jellysinkd --watch fix the naming of new files right away
+defer_while_streaming = false  # hold scheduled scans and auto-cleans while anyone streams from [jellyfin]
+streaming_idle_minutes = 10    # minutes Jellyfin must stay idle before held work starts
+
+[autoclean]
+identical_only = false  # delete a duplicate only when its content matches the kept copy byte for byte
+min_confidence = 0.0    # fix only compliance issues whose suggested name is at least this confident, e.g. 0.9
+min_age_days = 0        # never touch files modified within this many days
+max_delete_gb = 0.0     # stop deleting duplicates once a run would free more than this; 0 = no limit
+
+[progress]
+cli_min_severity = "info"     # debug, info, warn, error, critical
+tui_min_severity = "info"
+daemon_min_severity = "warn"
+
+[reports]
+dir = ""                          # default scan_results in data_dir; may be a mounted share
+filename_template = "{timestamp}" # {timestamp}, {date}, {time}, {library}, {host}
+
+[naming]
+profile = "jellyfin"  # jellyfin ("Season 01", "Show S01E01") or emby ("Season 1", "Show - S01E01")
+# lowercase_words = ["a", "an", "the", "and", "of", "in", "on", "to"]  # kept lowercase mid-title; default covers English articles/short prepositions
+episode_titles = false  # suggest "Show S01E01 - Pilot.mkv" using TVDB episode titles (needs [api.tvdb])
+show_folder_years = false  # suggest renaming "Firefly" to "Firefly (2002)" using the first-air year from TVDB or TMDB
+auto_resolve_margin = 0.0  # resolve API title conflicts when one title's confidence leads by more than this, e.g. 0.3; 0 = always review
+
+[ui]
+sort_locale = ""              # e.g. "en", "de", "sv": locale-aware order for report titles; empty = byte order
+ignore_articles = false       # sort "The Matrix" under M
+show_banner = true            # ASCII banner atop TUI screens 100+ columns wide; false shows a one-line title
+first_run_walkthrough = true  # until the first clean, only dry runs (with a walkthrough of what they found); false unlocks full cleans
+
+[api]
+failure_threshold = 3  # consecutive network failures before TVDB/OMDB/TMDB are skipped for the rest of a scan
+cache_ttl_days = 30    # reuse verified lookups across scans for this long (api_cache.json); 0 = this scan only
+# proxy_url = "http://proxy.lan:3128"  # default uses HTTP_PROXY/HTTPS_PROXY/NO_PROXY
+# ca_bundle = "/etc/ssl/certs/corp-ca.pem"  # extra PEM CAs for TLS-intercepting proxies
+
+[api.tvdb]
+enabled = false
+api_key = ""
+
+[api.omdb]
+enabled = false
+api_key = ""
+
+[api.tmdb]
+enabled = false
+api_key = ""  # v3 API key or v4 read access token; also verifies movie titles
+
+[jellyfin]
+url = ""         # e.g. http://localhost:8096
+api_key = ""     # Jellyfin Dashboard > API Keys
+compare = false  # report files Jellyfin failed to match and items whose files are gone
+refresh_after_clean = true  # ask Jellyfin to rescan its libraries after a clean changes files
+# path_mappings = ["/data/media=/mnt/media"]  # server_prefix=local_prefix when Jellyfin runs in a container
+
+[sonarr]
+url = ""      # e.g. http://localhost:8989; its show names win over TVDB/OMDB
+api_key = ""  # Sonarr Settings > General > Security
+rescan_after_rename = true  # point Sonarr at renamed show folders and rescan them
+# path_mappings = ["/tv=/mnt/media/tv"]  # arr_prefix=local_prefix when Sonarr runs in a container
+
+[radarr]
+url = ""      # e.g. http://localhost:7878; its movie names are used for suggested renames
+api_key = ""  # Radarr Settings > General > Security
+rescan_after_rename = true  # point Radarr at renamed movie folders and rescan them
+# path_mappings = ["/movies=/mnt/media/movies"]
+
+[tags]
+keep = ["keep", "keep-4k"]   # tagged files are kept over their duplicates and never deleted
+protected = ["never-touch"]  # tagged files and folders are never deleted or renamed
+
+[duplicates]
+strategy = "delete"  # or "multi-version": keep every movie copy, renamed as Jellyfin versions
+scope = "cross-library"  # or "library": copies in different library paths are not duplicates; "same-folder": only copies side by side
+ffprobe = ""         # empty finds ffprobe on PATH to rank copies by codec, bitrate and resolution; "off" uses filenames
+merge_companions = true  # move subtitles and artwork the keeper lacks out of deleted copies' folders; C toggles a group in the TUI
+
+[cleaner]
+trash_dir = ""       # default trash in data_dir; deleted files are moved here, not unlinked
+retention_days = 14  # daemon runs purge trash older than this; 0 keeps it until "jellysink trash empty"
+protected_paths = [] # e.g. ["/mnt/media/movies/Favourites"]: never deleted or renamed, whatever a report suggests
+confirm_phrase = "yes"  # what cleans, undos and trash empties ask you to type; --yes skips the question
+hardlink_duplicates = false  # replace duplicates with hardlinks to the kept copy: every path stays, the space is freed
+
+[performance]
+low_memory = false   # for 512MB-1GB NAS boxes: fewer ffprobe workers, capped logs, no Jellyfin compare, streamed report JSON
+cloud_safe = true    # libraries on rclone/s3fs/gcsfuse mounts: no ffprobe reads, paced listings, batched renames
+cloud_list_rate = 4  # folder listings per second scans make on a cloud mount
+
+[notify.email]
+host = ""          # SMTP server, e.g. "smtp.example.com"; jellysinkd emails a summary after each scan
+port = 587
+tls = "starttls"   # starttls, tls (implicit, usually port 465) or none
+username = ""
+password = ""
+from = ""          # default username, or jellysink@<hostname>
+to = []            # e.g. ["you@example.com"]
+
+# jellysinkd posts scan, clean (auto-clean finished) and error events to each
+# target below; events = [] sends all three. template is a Go template for the
+# message text over the event's fields, e.g. "{{.Duplicates}} duplicates, {{gb .SpaceToFree}}"
+[notify.webhook]
+url = ""           # receives a JSON POST with the event's fields and the message
+events = []
+template = ""
+
+[notify.discord]
+webhook_url = ""   # channel settings > Integrations > Webhooks
+events = []
+template = ""
+
+[notify.telegram]
+bot_token = ""     # from @BotFather
+chat_id = ""       # numeric chat ID, or "@channelname"
+events = []
+template = ""
 `
 
 var rootCmd = &cobra.Command{
@@ -56,6 +254,13 @@ var scanCmd = &cobra.Command{
 	Run:   runScan,
 }
 
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Scan the libraries and clean the findings in one go (--dry-run only previews the clean)",
+	Args:  cobra.NoArgs,
+	Run:   runRun,
+}
+
 var viewCmd = &cobra.Command{
 	Use:   "view <report-file>",
 	Short: "View a scan report in the TUI",
@@ -70,12 +275,166 @@ var cleanCmd = &cobra.Command{
 	Run:   runClean,
 }
 
+var planCmd = &cobra.Command{
+	Use:   "plan <report-file>",
+	Short: "Write a report's clean operations as an editable numbered plan",
+	Args:  cobra.ExactArgs(1),
+	Run:   runPlan,
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <older-report> [newer-report]",
+	Short: "Show what is new, resolved or still pending between two scan reports (one report: against its previous scan)",
+	Args:  cobra.RangeArgs(1, 2),
+	Run:   runDiff,
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export <report-file>",
+	Short: "Export a report as a self-contained HTML page, Markdown or CSV for sharing",
+	Args:  cobra.ExactArgs(1),
+	Run:   runExport,
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply <plan-file>",
+	Short: "Clean only the operations still listed in a plan",
+	Args:  cobra.ExactArgs(1),
+	Run:   runApply,
+}
+
+var tagCmd = &cobra.Command{
+	Use:   "tag <path> [tag...]",
+	Short: "Tag a file or folder (lists its tags when none are given)",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   runTag,
+}
+
+var artifactsCmd = &cobra.Command{
+	Use:   "artifacts <report-file>",
+	Short: "Preview deleting a report's leftover junk (empty folders, samples, partial downloads); --delete removes it",
+	Args:  cobra.ExactArgs(1),
+	Run:   runArtifacts,
+}
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [clean-id|last]",
+	Short: "Undo a clean from its journal (lists recent cleans when no ID is given)",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runUndo,
+}
+
+var attachCmd = &cobra.Command{
+	Use:   "attach",
+	Short: "Watch the live progress of a scan that is already running",
+	Args:  cobra.NoArgs,
+	Run:   runAttach,
+}
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List, restore or empty the files cleans moved to the trash",
+}
+
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List trashed files and folders by clean",
+	Args:  cobra.NoArgs,
+	Run:   runTrashList,
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore <clean-id> [path...]",
+	Short: "Move a clean's trashed files back (only those at or under the given original paths)",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   runTrashRestore,
+}
+
+var trashEmptyCmd = &cobra.Command{
+	Use:   "empty [clean-id]",
+	Short: "Permanently delete everything in the trash, or one clean's trash",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runTrashEmpty,
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Account for what jellysink did to the libraries",
+}
+
+var auditExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export every logged scan, clean, rename, undo and trash purge of a period as CSV or JSON",
+	Args:  cobra.NoArgs,
+	Run:   runAuditExport,
+}
+
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Work with the files a scan reads from the libraries",
+}
+
+var inventoryExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export every video file with its size, mtime, parsed title, episode and resolution as CSV or JSON lines",
+	Args:  cobra.NoArgs,
+	Run:   runInventoryExport,
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show library sizes, how fast they grow and when each mount runs out of space",
+	Args:  cobra.NoArgs,
+	Run:   runStats,
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect or clear the TVDB/OMDB/TMDB lookup cache kept between scans",
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show how many lookups are cached per provider and how old they are",
+	Args:  cobra.NoArgs,
+	Run:   runCacheStats,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete the cached lookups so the next scan asks the APIs again",
+	Args:  cobra.NoArgs,
+	Run:   runCacheClear,
+}
+
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Show configuration file location and contents",
 	Run:   runConfig,
 }
 
+var schemaCmd = &cobra.Command{
+	Use:       "schema <report|config|plan|preview>",
+	Short:     "Print the JSON Schema of the report, config, plan or preview format",
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{"report", "config", "plan", "preview"},
+	Run:       runSchema,
+}
+
+var demoCmd = &cobra.Command{
+	Use:   "demo [sandbox-dir]",
+	Short: "Explore jellysink in the TUI against a sandbox library with messy sample content",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runDemo,
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the installation after an upgrade and list fixes, most important first",
+	Args:  cobra.NoArgs,
+	Run:   runDoctor,
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version information",
@@ -88,14 +447,87 @@ var versionCmd = &cobra.Command{
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.config/jellysink/config.toml)")
+	for _, cmd := range []*cobra.Command{cleanCmd, runCmd, applyCmd, artifactsCmd, undoCmd, trashEmptyCmd} {
+		cmd.Flags().BoolVar(&assumeYes, "yes", false, "proceed without asking for confirmation (needed when stdin is not a terminal)")
+		cmd.Flags().BoolVar(&assumeYes, "assume-yes", false, "same as --yes")
+	}
 	cleanCmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be deleted without actually deleting")
 	scanCmd.Flags().BoolVar(&quiet, "quiet", false, "minimal output (errors only)")
 	scanCmd.Flags().BoolVar(&verbose, "verbose", false, "detailed output (debug info)")
+	viewCmd.Flags().StringVar(&minSeverity, "min-severity", "", "only show compliance issues at or above this severity (info, warn, error)")
+	cleanCmd.Flags().StringVar(&minSeverity, "min-severity", "", "only fix compliance issues at or above this severity (info, warn, error)")
+	viewCmd.Flags().StringVar(&explainID, "explain", "", "print why each finding was produced instead of opening the TUI (optionally --explain=<finding-id>)")
+	viewCmd.Flags().Lookup("explain").NoOptDefVal = "all"
+	viewCmd.Flags().StringVar(&wasteFormat, "waste", "", "print reclaimable space per folder instead of opening the TUI (text, csv or json)")
+	viewCmd.Flags().Lookup("waste").NoOptDefVal = "text"
+	viewCmd.Flags().IntVar(&wasteDepth, "waste-depth", 0, "limit --waste text/csv output to this many folder levels (0 = all)")
+	scanCmd.Flags().BoolVar(&noTUI, "no-tui", false, "plain timestamped log output for screen/nohup/log files")
+	scanCmd.Flags().BoolVar(&incremental, "incremental", false, "only rescan folders with files changed since the last incremental scan")
+	scanCmd.Flags().StringVar(&resumeScan, "resume", "", "finish the cancelled scan that wrote this partial report")
+	scanCmd.Flags().StringSliceVar(&scanLibrary, "library", nil, "scan only these libraries, with their overrides (e.g. --library tv,anime)")
+	runCmd.Flags().BoolVar(&dryRun, "dry-run", false, "scan and simulate the clean without changing any file")
+	runCmd.Flags().BoolVar(&runJSON, "json", false, "print the dry-run preview as JSON (see jellysink schema preview); progress goes to stderr")
+	runCmd.Flags().BoolVar(&quiet, "quiet", false, "minimal scan output (errors only)")
+	runCmd.Flags().BoolVar(&verbose, "verbose", false, "detailed scan output (debug info)")
+	runCmd.Flags().StringVar(&minSeverity, "min-severity", "", "only clean compliance issues at or above this severity (info, warn, error)")
+	runCmd.Flags().StringVar(&tagFilter, "tag", "", "only clean findings on files or folders with this tag")
+	runCmd.Flags().BoolVar(&cleanJunk, "junk", false, "also delete orphaned show and season folders (no video files left)")
+	runCmd.Flags().BoolVar(&noTUI, "no-tui", false, "plain timestamped log output for screen/nohup/log files")
+	attachCmd.Flags().BoolVar(&noTUI, "no-tui", false, "plain timestamped log output for screen/nohup/log files")
+	cleanCmd.Flags().BoolVar(&forceClean, "force", false, "clean a report that has already been cleaned")
+	cleanCmd.Flags().BoolVar(&noTUI, "no-tui", false, "plain timestamped log output for screen/nohup/log files")
+	planCmd.Flags().StringVarP(&planOutput, "output", "o", "", "write the plan to this file instead of stdout")
+	planCmd.Flags().BoolVar(&planJSON, "json", false, "write the plan as JSON (see jellysink schema plan) instead of numbered lines")
+	planCmd.Flags().StringVar(&minSeverity, "min-severity", "", "only list compliance fixes at or above this severity (info, warn, error)")
+	diffCmd.Flags().BoolVar(&diffJSON, "json", false, "write the diff as JSON instead of text")
+	diffCmd.Flags().BoolVar(&diffPending, "pending", false, "also list the findings still pending in both reports")
+	exportCmd.Flags().StringVar(&exportFmt, "format", reporter.ExportHTML, "export format: html, md or csv")
+	exportCmd.Flags().StringVarP(&exportOut, "output", "o", "", "write the export to this file instead of stdout")
+	exportCmd.Flags().BoolVar(&exportThumb, "thumbnails", false, "embed a frame of each duplicate (html only, needs ffmpeg)")
+	exportCmd.Flags().StringVar(&minSeverity, "min-severity", "", "only export compliance issues at or above this severity (info, warn, error)")
+	exportCmd.Flags().StringVar(&tagFilter, "tag", "", "only export findings on files or folders with this tag")
+	applyCmd.Flags().BoolVar(&forceClean, "force", false, "apply a plan for a report that has already been cleaned")
+	applyCmd.Flags().BoolVar(&noTUI, "no-tui", false, "plain timestamped log output for screen/nohup/log files")
+	viewCmd.Flags().StringVar(&tagFilter, "tag", "", "only show findings on files or folders with this tag")
+	cleanCmd.Flags().StringVar(&tagFilter, "tag", "", "only clean findings on files or folders with this tag")
+	cleanCmd.Flags().BoolVar(&cleanJunk, "junk", false, "also delete orphaned show and season folders (no video files left)")
+	planCmd.Flags().StringVar(&tagFilter, "tag", "", "only list findings on files or folders with this tag")
+	artifactsCmd.Flags().BoolVar(&deleteJunk, "delete", false, "move the artifacts to the trash instead of only previewing")
+	artifactsCmd.Flags().StringVar(&tagFilter, "tag", "", "only delete artifacts with this tag")
+	auditExportCmd.Flags().StringVar(&auditSince, "since", "", "first day to export (YYYY-MM-DD or RFC 3339 time; default: everything logged)")
+	auditExportCmd.Flags().StringVar(&auditUntil, "until", "", "last day to export (YYYY-MM-DD, inclusive, or RFC 3339 time; default: now)")
+	auditExportCmd.Flags().StringVar(&auditFormat, "format", oplog.AuditCSV, "export format: csv or json")
+	auditExportCmd.Flags().StringVarP(&auditOut, "output", "o", "", "write the export to this file instead of stdout")
+	inventoryExportCmd.Flags().StringVar(&invFormat, "format", scanner.InventoryCSV, "export format: csv or jsonl")
+	inventoryExportCmd.Flags().StringVarP(&invOut, "output", "o", "", "write the export to this file instead of stdout")
+	tagCmd.Flags().BoolVar(&untag, "remove", false, "remove the given tags instead of adding them")
+	demoCmd.Flags().BoolVar(&demoNoTUI, "scan-only", false, "scan the sandbox and write report.json without launching the TUI")
 
 	rootCmd.AddCommand(scanCmd)
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(attachCmd)
 	rootCmd.AddCommand(viewCmd)
 	rootCmd.AddCommand(cleanCmd)
+	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(artifactsCmd)
+	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(statsCmd)
+	trashCmd.AddCommand(trashListCmd, trashRestoreCmd, trashEmptyCmd)
+	rootCmd.AddCommand(trashCmd)
+	auditCmd.AddCommand(auditExportCmd)
+	rootCmd.AddCommand(auditCmd)
+	inventoryCmd.AddCommand(inventoryExportCmd)
+	rootCmd.AddCommand(inventoryCmd)
+	cacheCmd.AddCommand(cacheStatsCmd, cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(demoCmd)
+	rootCmd.AddCommand(doctorCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
@@ -154,20 +586,23 @@ func runTUI(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	// Load config
-	cfg, err := loadConfig()
+	// Load config; one that doesn't load or validate opens safe mode, which
+	// shows the error and leaves the file alone until it is fixed
+	var model tea.Model
+	cfg, err := config.Load()
+	if err == nil {
+		err = cfg.Validate()
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-		fmt.Fprintf(os.Stderr, "Creating default config...\n")
-		cfg = config.DefaultConfig()
-		if err := config.Save(cfg); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to create config: %v\n", err)
-			os.Exit(1)
-		}
+		path, _ := config.ConfigPath()
+		model = ui.NewSafeModeRouter(path, err, applyConfig)
+	} else {
+		applyConfig(cfg)
+		migrateDataDir()
+		model = ui.NewMenuRouter(cfg)
 	}
 
 	// Launch main menu TUI
-	model := ui.NewMenuModel(cfg)
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
@@ -188,24 +623,34 @@ func runScan(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Create context with cancellation support (Ctrl+C)
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	logLevel, filter, err := scanOutputLevel(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if incremental && resumeScan != "" {
+		fmt.Fprintf(os.Stderr, "Error: --incremental and --resume are mutually exclusive\n")
+		os.Exit(1)
+	}
+	if len(scanLibrary) > 0 {
+		if cfg, err = cfg.ForLibraries(scanLibrary...); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
-	// Handle interrupt signals for graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-sigChan
-		fmt.Println("\nCancelling scan...")
-		cancel()
-	}()
+	path := scanLibraries(cfg, logLevel, filter, os.Stdout)
+
+	printLine(os.Stdout, "\n✓ Scan complete! Report saved to:\n  %s\n", path)
+	printLine(os.Stdout, "View report with: jellysink view %s", path)
+}
 
-	// Determine log level from flags
+// scanOutputLevel returns the log level and progress filter from --quiet,
+// --verbose and the configured CLI minimum, which the flags override
+func scanOutputLevel(cfg *config.Config) (scanner.LogLevel, scanner.ProgressFilter, error) {
 	logLevel := scanner.LogLevelNormal
 	if quiet && verbose {
-		fmt.Fprintf(os.Stderr, "Error: --quiet and --verbose are mutually exclusive\n")
-		os.Exit(1)
+		return logLevel, scanner.ProgressFilter{}, fmt.Errorf("--quiet and --verbose are mutually exclusive")
 	}
 	if quiet {
 		logLevel = scanner.LogLevelQuiet
@@ -214,10 +659,44 @@ func runScan(cmd *cobra.Command, args []string) {
 		logLevel = scanner.LogLevelVerbose
 	}
 
+	filter := scanner.ProgressFilter{MinSeverity: logLevel.MinSeverity()}
+	if !quiet && !verbose && cfg.Progress.CLIMinSeverity != "" {
+		if sev, err := scanner.ParseProgressSeverity(cfg.Progress.CLIMinSeverity); err == nil {
+			filter.MinSeverity = sev
+		}
+		if filter.MinSeverity == scanner.SeverityDebug {
+			logLevel = scanner.LogLevelVerbose
+		}
+	}
+	return logLevel, filter, nil
+}
+
+// scanLibraries scans the configured libraries, printing progress to out,
+// and returns the path of the saved report. Exits when the scan fails or
+// is cancelled with Ctrl+C
+func scanLibraries(cfg *config.Config, logLevel scanner.LogLevel, filter scanner.ProgressFilter, out io.Writer) string {
+	// Create context with cancellation support (Ctrl+C)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle interrupt signals for graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer func() {
+		signal.Stop(sigChan)
+		close(sigChan)
+	}()
+	go func() {
+		if _, ok := <-sigChan; ok {
+			printLine(out, "\nCancelling scan...")
+			cancel()
+		}
+	}()
+
 	// Set the global log level for progress reporters
 	scanner.SetDefaultLogLevel(logLevel)
 
-	fmt.Println("Starting scan...")
+	printLine(out, "Starting scan...")
 
 	// Create progress channel
 	progressCh := make(chan scanner.ScanProgress, 100)
@@ -227,121 +706,1232 @@ func runScan(cmd *cobra.Command, args []string) {
 		path string
 		err  error
 	}
-	resultCh := make(chan scanResult)
+	resultCh := make(chan scanResult, 1)
 
 	go func() {
 		d := daemon.New(cfg)
+		d.SetIncremental(incremental)
+		d.SetResume(resumeScan)
 		path, err := d.RunScanWithProgress(ctx, progressCh)
 		resultCh <- scanResult{path, err}
 		close(progressCh)
 	}()
 
 	// Display progress with log level filtering
-	lastOperation := ""
-	for progress := range progressCh {
-		// Apply log level filtering
-		shouldShow := false
-		switch logLevel {
-		case scanner.LogLevelQuiet:
-			shouldShow = progress.Severity == "error" || progress.Severity == "critical"
-		case scanner.LogLevelNormal:
-			shouldShow = progress.Severity != "debug"
-		case scanner.LogLevelVerbose:
-			shouldShow = true
-		}
-
-		if !shouldShow {
-			continue
+	printScanProgress(progressCh, filter, logLevel, out)
+
+	// Get result
+	result := <-resultCh
+	if result.err != nil {
+		if errors.Is(result.err, context.Canceled) {
+			printLine(os.Stderr, "\nScan cancelled by user")
+			if result.path != "" {
+				printLine(os.Stderr, "Partial report saved to:\n  %s", result.path)
+				printLine(os.Stderr, "Resume with: jellysink scan --resume %s", result.path)
+			}
+			os.Exit(130) // Exit code 130 for SIGINT
+		}
+		printLine(os.Stderr, "\nScan failed: %v", result.err)
+		os.Exit(1)
+	}
+	return result.path
+}
+
+func runRun(cmd *cobra.Command, args []string) {
+	if runJSON && !dryRun {
+		fmt.Fprintf(os.Stderr, "Error: --json needs --dry-run\n")
+		os.Exit(1)
+	}
+	// A preview changes nothing, so cron and CI jobs can run it unprivileged
+	if !dryRun && !isRunningAsRoot() {
+		reexecWithSudo()
+		return
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	logLevel, filter, err := scanOutputLevel(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Keep stdout for the JSON document alone
+	out := io.Writer(os.Stdout)
+	if runJSON {
+		out = os.Stderr
+	}
+	reportPath := scanLibraries(cfg, logLevel, filter, out)
+	printLine(out, "\n✓ Scan complete! Report saved to:\n  %s", reportPath)
+
+	report, err := loadReport(reportPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading report: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := applySeverityFilter(&report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := applyTagFilter(&report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !dryRun {
+		performClean(report, reportPath)
+		return
+	}
+
+	printLine(out, "\nSimulating the clean...")
+	preview, err := previewClean(report, reportPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error during dry run: %v\n", err)
+		os.Exit(1)
+	}
+
+	if runJSON {
+		err = reporter.WritePreviewJSON(os.Stdout, preview)
+	} else {
+		fmt.Println()
+		err = reporter.WritePreview(os.Stdout, preview)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing preview: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The clean would hit errors; fail the job before anyone runs it for real
+	if len(preview.Errors) > 0 {
+		os.Exit(2)
+	}
+}
+
+// previewClean simulates cleaning report with the cleaner's dry run, which
+// checks every operation could be carried out without changing any file
+func previewClean(report reporter.Report, reportPath string) (reporter.Preview, error) {
+	config := cleaner.DefaultConfig()
+	config.DryRun = true
+	if cleanJunk {
+		config.OrphanFolders = report.OrphanFolders
+	}
+
+	result, err := cleaner.Clean(report.MovieDuplicates, report.TVDuplicates, report.ComplianceIssues, config)
+	if err != nil {
+		return reporter.Preview{}, err
+	}
+
+	preview := reporter.Preview{
+		Report:           reportPath,
+		TotalDuplicates:  report.TotalDuplicates,
+		FilesToDelete:    report.TotalFilesToDelete,
+		ComplianceIssues: len(report.ComplianceIssues),
+		SpaceToFree:      report.SpaceToFree,
+	}
+	if cleanJunk {
+		preview.OrphanFolders = len(report.OrphanFolders)
+	}
+	for _, op := range result.Operations {
+		status := op.Status
+		if status == "" && op.Completed {
+			status = reporter.PreviewReady
+		} else if status == "" {
+			status = reporter.PreviewFailed
 		}
+		preview.Operations = append(preview.Operations, reporter.PreviewOperation{
+			Action: op.Type,
+			Source: op.Source,
+			Target: op.Destination,
+			Status: status,
+		})
+	}
+	for _, err := range result.Errors {
+		preview.Errors = append(preview.Errors, err.Error())
+	}
+	return preview, nil
+}
 
+// printScanProgress prints scan progress passing filter to out until
+// progressCh closes; errors go to stderr
+func printScanProgress(progressCh <-chan scanner.ScanProgress, filter scanner.ProgressFilter, logLevel scanner.LogLevel, out io.Writer) {
+	var lastOperation scanner.ProgressOperation
+	for progress := range scanner.FilterProgress(progressCh, filter) {
 		// Format output based on severity
-		if progress.Severity == "error" || progress.Severity == "critical" {
-			fmt.Fprintf(os.Stderr, "✗ %s\n", progress.Message)
+		if progress.Severity.IsError() {
+			printLine(os.Stderr, "✗ %s", progress.Message)
 		} else if progress.Operation != lastOperation {
-			fmt.Printf("\n%s...\n", progress.Message)
+			printLine(out, "\n%s...", progress.Message)
 			lastOperation = progress.Operation
 		} else if logLevel == scanner.LogLevelVerbose || progress.Current%50 == 0 || progress.Stage == "complete" {
-			fmt.Printf("  %.1f%% - %s\n", progress.Percentage, progress.Message)
+			printLine(out, "  %.1f%% - %s", progress.Percentage, progress.Message)
 		}
 	}
+}
+
+func runAttach(cmd *cobra.Command, args []string) {
+	// Ctrl+C only detaches; the scan keeps running
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	d := daemon.New(nil)
+	if !d.ScanRunning() {
+		fmt.Fprintln(os.Stderr, "No scan is running.")
+		os.Exit(1)
+	}
+	printLine(os.Stdout, "Attached to the running scan (Ctrl+C to detach)...")
+
+	progressCh := make(chan scanner.ScanProgress, 100)
+	type attachResult struct {
+		path string
+		err  error
+	}
+	resultCh := make(chan attachResult, 1)
+	go func() {
+		path, err := d.Attach(ctx, progressCh)
+		resultCh <- attachResult{path, err}
+		close(progressCh)
+	}()
+
+	printScanProgress(progressCh, scanner.ProgressFilter{MinSeverity: scanner.SeverityInfo}, scanner.LogLevelNormal, os.Stdout)
+
+	result := <-resultCh
+	switch {
+	case result.err == context.Canceled:
+		printLine(os.Stdout, "\nDetached - the scan keeps running")
+	case result.err == daemon.ErrNoScanRunning:
+		printLine(os.Stderr, "\nThe scan finished before attaching")
+		os.Exit(1)
+	case result.err != nil:
+		printLine(os.Stderr, "\nScan failed: %v", result.err)
+		os.Exit(1)
+	default:
+		printLine(os.Stdout, "\n✓ Scan complete! Report saved to:\n  %s\n", result.path)
+		printLine(os.Stdout, "View report with: jellysink view %s", result.path)
+	}
+}
+
+func runDemo(cmd *cobra.Command, args []string) {
+	// Sandbox lives in a fresh temp dir unless one is given
+	var root string
+	var err error
+	if len(args) > 0 {
+		root = args[0]
+		err = os.MkdirAll(root, 0755)
+	} else {
+		root, err = os.MkdirTemp("", "jellysink-demo-*")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating sandbox: %v\n", err)
+		os.Exit(1)
+	}
+
+	lib, err := fixtures.Generate(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating sandbox: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Sandbox library created at %s\n", root)
+	fmt.Printf("  Movies: %s (%d files)\n", lib.MoviesPath, len(fixtures.MovieFiles))
+	fmt.Printf("  TV:     %s (%d files)\n", lib.TVPath, len(fixtures.TVFiles))
+
+	// Config only points at the sandbox; the user's real config is never read
+	cfg := config.DefaultConfig()
+	cfg.Libraries.Movies.Paths = []string{lib.MoviesPath}
+	cfg.Libraries.TV.Paths = []string{lib.TVPath}
+
+	if demoNoTUI {
+		runDemoScan(root, cfg)
+		return
+	}
+
+	// Redirect HOME into the sandbox so config saves, reports, backups and
+	// operation logs written by the TUI stay inside it
+	sandboxHome := filepath.Join(root, "home")
+	if err := os.MkdirAll(sandboxHome, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating sandbox: %v\n", err)
+		os.Exit(1)
+	}
+	os.Setenv("HOME", sandboxHome)
+	os.Unsetenv("SUDO_USER")
+	if err := config.Save(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing sandbox config: %v\n", err)
+		os.Exit(1)
+	}
+
+	ui.EnableDemoMode()
+	model := ui.NewMenuRouter(cfg)
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nDemo finished. Remove the sandbox when done: rm -rf %s\n", root)
+}
+
+// runDemoScan scans the sandbox and writes report.json next to it
+func runDemoScan(root string, cfg *config.Config) {
+	fmt.Println("\nScanning sandbox...")
+
+	result, err := scanner.RunFullScan(context.Background(), cfg.Libraries.Movies.Paths, cfg.Libraries.TV.Paths, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Scan failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	report := daemon.BuildReport(cfg, result)
+	reportPath := filepath.Join(root, "report.json")
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding report: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(reportPath, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\n✓ Found %d duplicate groups, %d compliance issues, %d ambiguous TV shows\n",
+		report.TotalDuplicates, len(report.ComplianceIssues), len(report.AmbiguousTVShows))
+	fmt.Printf("Report saved to:\n  %s\n\n", reportPath)
+	fmt.Println("Try it out (only sandbox files are touched):")
+	fmt.Printf("  jellysink view %s\n", reportPath)
+	fmt.Printf("  jellysink view --explain %s\n", reportPath)
+	fmt.Printf("  jellysink clean --dry-run %s\n", reportPath)
+	fmt.Printf("\nRemove the sandbox when done: rm -rf %s\n", root)
+}
+
+func runView(cmd *cobra.Command, args []string) {
+	loadReportConfig()
+
+	reportPath := args[0]
+
+	// Load the report
+	report, err := loadReport(reportPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading report: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := applySeverityFilter(&report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := applyTagFilter(&report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if explainID != "" {
+		if err := printExplanations(report, explainID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if wasteFormat != "" {
+		if err := printWastedSpace(report, wasteFormat, wasteDepth); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Create TUI model
+	model := ui.NewModel(report)
+	model.SetReportPath(reportPath)
+
+	// Run the Bubble Tea program
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	finalModel, err := p.Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Check if user pressed Enter (clean operation)
+	m := finalModel.(ui.Model)
+	if m.ShouldClean() {
+		resolvedConflicts := m.GetResolvedConflicts()
+		hasResolvedConflicts := false
+		for _, c := range resolvedConflicts {
+			if c.UserDecision != 0 {
+				hasResolvedConflicts = true
+				break
+			}
+		}
+
+		if hasResolvedConflicts {
+			performConflictRenames(report, resolvedConflicts)
+		} else {
+			editedTitles := m.GetEditedTitles()
+			if len(editedTitles) > 0 {
+				performManualRenames(report, editedTitles)
+			} else if err := ensureNotCleaned(report, false); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			} else {
+				performClean(report, reportPath)
+			}
+		}
+	}
+}
+
+func runClean(cmd *cobra.Command, args []string) {
+	// Check for root access (unless dry-run)
+	if !dryRun && !isRunningAsRoot() {
+		reexecWithSudo()
+		return
+	}
+
+	loadReportConfig()
+
+	reportPath := args[0]
+
+	report, err := loadReport(reportPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading report: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := ensureNotCleaned(report, forceClean); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := applySeverityFilter(&report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := applyTagFilter(&report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	performClean(report, reportPath)
+}
+
+func runPlan(cmd *cobra.Command, args []string) {
+	reportPath, err := filepath.Abs(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	loadReportConfig()
+
+	report, err := loadReport(reportPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading report: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := applySeverityFilter(&report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := applyTagFilter(&report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	writePlan := reporter.WritePlan
+	if planJSON {
+		writePlan = reporter.WritePlanJSON
+	}
+
+	if planOutput == "" {
+		if err := writePlan(os.Stdout, report, reportPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing plan: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	f, err := os.Create(planOutput)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating plan: %v\n", err)
+		os.Exit(1)
+	}
+	if err := writePlan(f, report, reportPath); err != nil {
+		f.Close()
+		fmt.Fprintf(os.Stderr, "Error writing plan: %v\n", err)
+		os.Exit(1)
+	}
+	if err := f.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing plan: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Plan written to %s\n", planOutput)
+	fmt.Printf("Delete the lines you do not approve, then run: jellysink apply %s\n", planOutput)
+}
+
+func runDiff(cmd *cobra.Command, args []string) {
+	// One report is compared with the previous scan recorded in it
+	olderPath, newerPath := "", args[0]
+	if len(args) == 2 {
+		olderPath, newerPath = args[0], args[1]
+	}
+
+	diff, err := reporter.LoadDiff(olderPath, newerPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if diffJSON {
+		err = reporter.WriteDiffJSON(os.Stdout, diff)
+	} else {
+		err = reporter.WriteDiff(os.Stdout, diff, diffPending)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing diff: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runExport(cmd *cobra.Command, args []string) {
+	loadReportConfig()
+
+	report, err := loadReport(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading report: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := applySeverityFilter(&report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := applyTagFilter(&report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if exportThumb && strings.ToLower(exportFmt) != reporter.ExportHTML {
+		fmt.Fprintf(os.Stderr, "Error: --thumbnails needs --format html\n")
+		os.Exit(1)
+	}
+	if exportThumb && !scanner.ThumbnailsAvailable() {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", scanner.ErrNoFFmpeg)
+		os.Exit(1)
+	}
+	export := func(w io.Writer) error {
+		if exportThumb {
+			return reporter.WriteReportHTMLThumbnails(w, report, embedThumbnail)
+		}
+		return reporter.Export(w, report, exportFmt)
+	}
+
+	if exportOut == "" {
+		if err := export(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Check the format before creating the file
+	if err := reporter.Export(io.Discard, reporter.Report{}, exportFmt); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	f, err := os.Create(exportOut)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating export: %v\n", err)
+		os.Exit(1)
+	}
+	if err := export(f); err != nil {
+		f.Close()
+		fmt.Fprintf(os.Stderr, "Error writing export: %v\n", err)
+		os.Exit(1)
+	}
+	if err := f.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing export: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Report exported to %s\n", exportOut)
+}
+
+// embedThumbnail grabs a frame of a duplicate as a data: URL, so the export
+// previews it without the files at hand; files without a frame get none
+func embedThumbnail(groupID string, index int, path string, duration time.Duration) string {
+	data, err := scanner.Thumbnail(context.Background(), scanner.DefaultThumbnailDir(), path, duration)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return ""
+	}
+	return "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+func runApply(cmd *cobra.Command, args []string) {
+	if !isRunningAsRoot() {
+		reexecWithSudo()
+		return
+	}
+
+	loadReportConfig()
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening plan: %v\n", err)
+		os.Exit(1)
+	}
+	reportPath, ops, err := reporter.ReadPlan(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading plan: %v\n", err)
+		os.Exit(1)
+	}
+
+	report, err := loadReport(reportPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading report: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := ensureNotCleaned(report, forceClean); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	report, err = reporter.ApplyPlan(report, ops)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\nRegenerate the plan with: jellysink plan %s\n", err, reportPath)
+		os.Exit(1)
+	}
+
+	performClean(report, reportPath)
+}
+
+func runTag(cmd *cobra.Command, args []string) {
+	loadReportConfig()
+	rules := tags.CurrentRules()
+	path, err := filepath.Abs(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(args) > 1 {
+		for _, tag := range args[1:] {
+			if untag {
+				if !rules.Store.Remove(path, tag) {
+					fmt.Fprintf(os.Stderr, "Warning: %s is not tagged %q\n", path, tag)
+				}
+			} else if err := rules.Store.Add(path, tag); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if err := rules.Store.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving tags: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Printf("%s: %s\n", path, formatTags(rules.Store.Effective(path)))
+}
+
+func runUndo(cmd *cobra.Command, args []string) {
+	if !isRunningAsRoot() {
+		reexecWithSudo()
+		return
+	}
+
+	loadReportConfig()
+	config := cleaner.DefaultConfig()
+
+	if len(args) == 0 {
+		journals, err := cleaner.ListJournals(config.JournalDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(journals) == 0 {
+			fmt.Println("No cleans to undo.")
+			return
+		}
+		fmt.Println("Recent cleans:")
+		for _, j := range journals {
+			status := fmt.Sprintf("%d operations", len(j.Entries))
+			if j.UndoneAt != nil {
+				status += ", undone " + j.UndoneAt.Format("2006-01-02 15:04")
+			} else if pending := j.Pending(); pending < len(j.Entries) {
+				status += fmt.Sprintf(", %d still to undo", pending)
+			}
+			fmt.Printf("  %s  %s  (%s)\n", j.ID, j.StartedAt.Format("2006-01-02 15:04"), status)
+		}
+		fmt.Println("\nRun: jellysink undo <clean-id>   (or: jellysink undo last)")
+		return
+	}
+
+	id := args[0]
+	if id == "last" {
+		latest, err := cleaner.LatestJournal(config.JournalDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		id = latest.ID
+	}
+
+	j, err := cleaner.LoadJournal(config.JournalDir, id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Undo clean %s from %s: %d operations to restore\n", j.ID, j.StartedAt.Format("2006-01-02 15:04"), j.Pending())
+	if !confirm("Are you sure you want to proceed?") {
+		fmt.Println("Undo cancelled.")
+		return
+	}
+
+	result, err := cleaner.Undo(config, id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\n✓ Restored: %d\n", result.Restored)
+	if result.LibraryRefreshed {
+		fmt.Println("✓ Jellyfin library scan requested")
+	} else if result.RefreshErr != nil {
+		fmt.Printf("⚠ Jellyfin library scan request failed: %v\n", result.RefreshErr)
+	}
+	if len(result.Errors) > 0 {
+		fmt.Printf("\n⚠ Not restored: %d (run the undo again once resolved)\n", len(result.Errors))
+		for i, err := range result.Errors {
+			fmt.Printf("  %d. %v\n", i+1, err)
+		}
+	}
+}
+
+// apiCacheTTL returns the configured lifetime of cached lookups
+func apiCacheTTL() time.Duration {
+	cfg := config.DefaultConfig()
+	if path, err := config.ConfigPath(); err == nil {
+		if _, err := os.Stat(path); err == nil {
+			if loaded, err := config.Load(); err == nil {
+				cfg = loaded
+			}
+		}
+	}
+	return time.Duration(cfg.API.CacheTTLDays) * 24 * time.Hour
+}
+
+func runStats(cmd *cobra.Command, args []string) {
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	if len(cfg.GetAllPaths()) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no library paths configured\n")
+		os.Exit(1)
+	}
+
+	capacity, err := daemon.RecordCapacity(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if capacity.Samples == nil && err != nil {
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	for _, stats := range capacity.Libraries {
+		label := "Movies"
+		if stats.LibraryType == "tv" {
+			label = "TV"
+		}
+		fmt.Printf("%s: %s\n", label, stats.Path)
+		fmt.Printf("  Files: %d   Size: %s\n", stats.FileCount, formatBytes(stats.TotalSize))
+		perDay, span, ok := scanner.LibraryGrowth(capacity.Samples, stats.Path, now)
+		switch {
+		case !ok:
+			fmt.Printf("  Growth: not known yet (%d days of samples, needs a week)\n", int(span.Hours()/24))
+		case perDay < 0:
+			fmt.Printf("  Growth: shrinking %s/day over %d days\n", formatBytes(int64(-perDay)), int(span.Hours()/24))
+		default:
+			fmt.Printf("  Growth: %s/day over %d days\n", formatBytes(int64(perDay)), int(span.Hours()/24))
+		}
+	}
+
+	fmt.Printf("\nCapacity:\n")
+	for _, p := range capacity.Projections {
+		fmt.Printf("  %s\n", daemon.CapacityLine(p))
+	}
+	fmt.Printf("\nSizes are recorded in %s by every jellysink stats and jellysinkd scan.\n", scanner.DefaultStatsHistoryPath())
+}
+
+func runCacheStats(cmd *cobra.Command, args []string) {
+	ttl := apiCacheTTL()
+	stats, err := scanner.ReadAPICacheStats(scanner.DefaultAPICachePath(), ttl)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("API cache: %s\n", stats.Path)
+	if ttl > 0 {
+		fmt.Printf("  Lookups are reused for %d days\n", int(ttl.Hours()/24))
+	} else {
+		fmt.Printf("  Disabled (cache_ttl_days = 0): lookups are only reused within a scan\n")
+	}
+	if stats.Entries == 0 {
+		fmt.Println("  No cached lookups.")
+		return
+	}
+
+	fmt.Printf("  Entries: %d (%s)\n", stats.Entries, formatBytes(stats.Size))
+	for _, name := range stats.ProviderNames() {
+		fmt.Printf("    %-5s %d\n", strings.ToUpper(name), stats.Providers[name])
+	}
+	if stats.Expired > 0 {
+		fmt.Printf("  Expired: %d (dropped after the next scan)\n", stats.Expired)
+	}
+	fmt.Printf("  Oldest: %s\n", stats.Oldest.Format("2006-01-02 15:04"))
+	fmt.Printf("  Newest: %s\n", stats.Newest.Format("2006-01-02 15:04"))
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) {
+	path := scanner.DefaultAPICachePath()
+	// A cache that no longer parses is removed all the same
+	stats, _ := scanner.ReadAPICacheStats(path, 0)
+	if err := scanner.RemoveAPICache(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ Cleared %d cached lookups\n", stats.Entries)
+}
+
+func runAuditExport(cmd *cobra.Command, args []string) {
+	since, err := parseAuditTime(auditSince, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid --since: %v\n", err)
+		os.Exit(1)
+	}
+	until, err := parseAuditTime(auditUntil, true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid --until: %v\n", err)
+		os.Exit(1)
+	}
+	// Check the format before reading the logs or creating the file
+	if err := oplog.WriteAudit(io.Discard, nil, auditFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	loadReportConfig()
+	entries, err := oplog.Audit(oplog.DataDir(), since, until)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if auditOut == "" {
+		if err := oplog.WriteAudit(os.Stdout, entries, auditFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	f, err := os.Create(auditOut)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating export: %v\n", err)
+		os.Exit(1)
+	}
+	if err := oplog.WriteAudit(f, entries, auditFormat); err != nil {
+		f.Close()
+		fmt.Fprintf(os.Stderr, "Error writing export: %v\n", err)
+		os.Exit(1)
+	}
+	if err := f.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing export: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%d logged operations exported to %s\n", len(entries), auditOut)
+}
+
+func runInventoryExport(cmd *cobra.Command, args []string) {
+	// Check the format before walking the libraries or creating the file
+	if err := scanner.WriteInventory(io.Discard, nil, invFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	records, err := scanner.InventoryRecords(context.Background(), cfg.MoviePaths(), cfg.ShowPaths(), nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if invOut == "" {
+		if err := scanner.WriteInventory(os.Stdout, records, invFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	f, err := os.Create(invOut)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating export: %v\n", err)
+		os.Exit(1)
+	}
+	if err := scanner.WriteInventory(f, records, invFormat); err != nil {
+		f.Close()
+		fmt.Fprintf(os.Stderr, "Error writing export: %v\n", err)
+		os.Exit(1)
+	}
+	if err := f.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing export: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%d video files exported to %s\n", len(records), invOut)
+}
+
+// parseAuditTime parses an --since or --until value: a day in local time,
+// or an RFC 3339 time. A day ending the period (end) includes all of it.
+// An empty value is the zero time, which leaves that end open
+func parseAuditTime(value string, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if day, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		if end {
+			day = day.AddDate(0, 0, 1)
+		}
+		return day, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither YYYY-MM-DD nor an RFC 3339 time", value)
+	}
+	return t, nil
+}
+
+func runTrashList(cmd *cobra.Command, args []string) {
+	if !isRunningAsRoot() {
+		reexecWithSudo()
+		return
+	}
+
+	loadReportConfig()
+	items, err := cleaner.ListTrash(cleaner.DefaultConfig())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(items) == 0 {
+		fmt.Println("The trash is empty.")
+		return
+	}
+
+	var total int64
+	for i, item := range items {
+		if i == 0 || items[i-1].JournalID != item.JournalID {
+			fmt.Printf("\nClean %s (deleted %s)\n", item.JournalID, item.DeletedAt.Format("2006-01-02 15:04"))
+		}
+		kind := ""
+		if item.Type == "delete-folder" {
+			kind = " [folder]"
+		}
+		fmt.Printf("  %s%s  %s\n", item.Original, kind, formatBytes(item.Size))
+		total += item.Size
+	}
+	fmt.Printf("\n%d items, %s\n", len(items), formatBytes(total))
+	fmt.Println("Restore with: jellysink trash restore <clean-id> [path...]   Free the space with: jellysink trash empty [clean-id]")
+}
+
+func runTrashRestore(cmd *cobra.Command, args []string) {
+	if !isRunningAsRoot() {
+		reexecWithSudo()
+		return
+	}
+
+	loadReportConfig()
+	result, err := cleaner.RestoreTrash(cleaner.DefaultConfig(), args[0], args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✓ Restored: %d\n", result.Restored)
+	if result.LibraryRefreshed {
+		fmt.Println("✓ Jellyfin library scan requested")
+	} else if result.RefreshErr != nil {
+		fmt.Printf("⚠ Jellyfin library scan request failed: %v\n", result.RefreshErr)
+	}
+	if len(result.Errors) > 0 {
+		fmt.Printf("\n⚠ Not restored: %d\n", len(result.Errors))
+		for i, err := range result.Errors {
+			fmt.Printf("  %d. %v\n", i+1, err)
+		}
+	}
+}
+
+// runArtifacts previews removing a report's artifacts, checking each one
+// is still there and still junk, and with --delete moves them to the trash
+func runArtifacts(cmd *cobra.Command, args []string) {
+	if deleteJunk && !isRunningAsRoot() {
+		reexecWithSudo()
+		return
+	}
+
+	loadReportConfig()
+
+	report, err := loadReport(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading report: %v\n", err)
+		os.Exit(1)
+	}
+	if err := applyTagFilter(&report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(report.Artifacts) == 0 {
+		fmt.Println("No artifacts in this report.")
+		return
+	}
+
+	config := cleaner.DefaultConfig()
+	config.Artifacts = report.Artifacts
+	config.DryRun = true
+	preview, err := cleaner.Clean(nil, nil, nil, config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Tagged paths are refused before an operation is planned; protected
+	// ones are planned as skipped
+	ops := make(map[string]cleaner.Operation, len(preview.Operations))
+	for _, op := range preview.Operations {
+		ops[op.Source] = op
+	}
+	ready, size := 0, int64(0)
+	for _, artifact := range report.Artifacts {
+		op, planned := ops[artifact.Path]
+		if !planned || !op.Completed {
+			reason := op.Error
+			switch {
+			case !planned:
+				reason = "tagged"
+			case op.Status != "":
+				reason = op.Status
+			}
+			fmt.Printf("  ✗ [%s] %s: %s\n", strings.ToUpper(artifact.Kind), artifact.Path, reason)
+			continue
+		}
+		ready++
+		size += artifact.Size
+		fmt.Printf("  • [%s] %s (%s)\n", strings.ToUpper(artifact.Kind), artifact.Path, formatBytes(artifact.Size))
+	}
+	fmt.Printf("\n%d of %d artifacts can be deleted (%s).\n", ready, len(report.Artifacts), formatBytes(size))
+	for _, warning := range preview.CloudWarnings {
+		fmt.Printf("⚠ %s\n", warning)
+	}
+	if !deleteJunk {
+		fmt.Printf("Dry run: nothing was deleted. Delete them with: jellysink artifacts --delete %s\n", args[0])
+		return
+	}
+	if ready == 0 {
+		return
+	}
 
-	// Get result
-	result := <-resultCh
-	if result.err != nil {
-		if result.err == context.Canceled {
-			fmt.Fprintf(os.Stderr, "\nScan cancelled by user\n")
-			os.Exit(130) // Exit code 130 for SIGINT
-		}
-		fmt.Fprintf(os.Stderr, "\nScan failed: %v\n", result.err)
-		os.Exit(1)
+	if !confirm("Move them to the trash?") {
+		fmt.Println("Deletion cancelled.")
+		return
 	}
 
-	fmt.Printf("\n✓ Scan complete! Report saved to:\n  %s\n\n", result.path)
-	fmt.Printf("View report with: jellysink view %s\n", result.path)
+	config.DryRun = false
+	result, err := cleaner.Clean(nil, nil, nil, config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("\n✓ Artifacts deleted: %d (%s freed)\n", result.ArtifactsRemoved, formatBytes(result.SpaceFreed))
+	if result.JournalID != "" {
+		fmt.Printf("✓ Undo with: jellysink undo %s\n", result.JournalID)
+	}
+	if len(result.Errors) > 0 {
+		fmt.Printf("\n⚠ Errors: %d\n", len(result.Errors))
+		for i, err := range result.Errors {
+			fmt.Printf("  %d. %v\n", i+1, err)
+		}
+	}
 }
 
-func runView(cmd *cobra.Command, args []string) {
-	reportPath := args[0]
+func runTrashEmpty(cmd *cobra.Command, args []string) {
+	if !isRunningAsRoot() {
+		reexecWithSudo()
+		return
+	}
 
-	// Load the report
-	report, err := loadReport(reportPath)
+	loadReportConfig()
+	config := cleaner.DefaultConfig()
+	id := ""
+	if len(args) > 0 {
+		id = args[0]
+	}
+
+	items, err := cleaner.ListTrash(config)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading report: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	count, size := 0, int64(0)
+	for _, item := range items {
+		if id == "" || item.JournalID == id {
+			count++
+			size += item.Size
+		}
+	}
+	if count == 0 {
+		fmt.Println("Nothing to empty.")
+		return
+	}
 
-	// Create TUI model
-	model := ui.NewModel(report)
+	fmt.Printf("Permanently delete %d trashed items (%s)? These cleans can no longer restore them.\n", count, formatBytes(size))
+	if !confirm("Are you sure you want to proceed?") {
+		fmt.Println("Empty cancelled.")
+		return
+	}
 
-	// Run the Bubble Tea program
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	finalModel, err := p.Run()
+	result, err := cleaner.EmptyTrash(config, id)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
-
-	// Check if user pressed Enter (clean operation)
-	m := finalModel.(ui.Model)
-	if m.ShouldClean() {
-		resolvedConflicts := m.GetResolvedConflicts()
-		hasResolvedConflicts := false
-		for _, c := range resolvedConflicts {
-			if c.UserDecision != 0 {
-				hasResolvedConflicts = true
-				break
-			}
+	fmt.Printf("\n✓ Deleted: %d (%s freed)\n", result.Purged, formatBytes(result.SpaceFreed))
+	if len(result.Errors) > 0 {
+		fmt.Printf("\n⚠ Errors: %d\n", len(result.Errors))
+		for i, err := range result.Errors {
+			fmt.Printf("  %d. %v\n", i+1, err)
 		}
+	}
+}
 
-		if hasResolvedConflicts {
-			performConflictRenames(report, resolvedConflicts)
-		} else {
-			editedTitles := m.GetEditedTitles()
-			if len(editedTitles) > 0 {
-				performManualRenames(report, editedTitles)
-			} else {
-				performClean(report)
-			}
-		}
+// formatTags joins tags for display, or "no tags"
+func formatTags(list []string) string {
+	if len(list) == 0 {
+		return "no tags"
 	}
+	return strings.Join(list, ", ")
 }
 
-func runClean(cmd *cobra.Command, args []string) {
-	// Check for root access (unless dry-run)
-	if !dryRun && !isRunningAsRoot() {
-		reexecWithSudo()
-		return
+// ensureNotCleaned refuses simulated reports, and reports that were already
+// cleaned unless forced
+// Re-running a clean retries deletes that already happened and floods the
+// output with "file not found" errors
+func ensureNotCleaned(report reporter.Report, force bool) error {
+	if report.Simulated {
+		return fmt.Errorf("report was simulated by jellysinkd --test; its files do not exist")
+	}
+	if report.Cleaned == nil || force {
+		return nil
 	}
+	return fmt.Errorf("report already %s\nRun a new scan, or pass --force to clean this report again", report.Cleaned.Banner())
+}
 
-	reportPath := args[0]
+// schemaDocument returns the JSON Schema of one of the formats
+// `jellysink schema` documents, generated from the types that read and write it
+func schemaDocument(format string) (map[string]any, error) {
+	switch format {
+	case "report":
+		return schema.Generate(reporter.Report{}, schema.Options{
+			Title:       "jellysink scan report",
+			Description: "JSON report written by jellysink scan and jellysinkd",
+			Required:    true,
+		}), nil
+	case "config":
+		return schema.Generate(config.DefaultConfig(), schema.Options{
+			Title:       "jellysink config",
+			Description: "~/.config/jellysink/config.toml; defaults apply to missing keys",
+			Tag:         "toml",
+			Strict:      true,
+		}), nil
+	case "plan":
+		return schema.Generate(reporter.Plan{}, schema.Options{
+			Title:       "jellysink clean plan",
+			Description: "Plan written by jellysink plan --json and accepted by jellysink apply; actions are delete, rename, reorganize and version, and all but delete need a target",
+			Required:    true,
+		}), nil
+	case "preview":
+		return schema.Generate(reporter.Preview{}, schema.Options{
+			Title:       "jellysink clean preview",
+			Description: "Preview written by jellysink run --dry-run --json; operation statuses are ready, failed, or why the clean would defer or skip the operation",
+			Required:    true,
+		}), nil
+	}
+	return nil, fmt.Errorf("unknown format %q (want report, config, plan or preview)", format)
+}
 
-	report, err := loadReport(reportPath)
+func runSchema(cmd *cobra.Command, args []string) {
+	doc, err := schemaDocument(args[0])
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading report: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	fmt.Println(string(data))
+}
+
+// listOrNone joins a config list for display, or "none" when it is empty
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
+
+// runDoctor checks the installation and exits 1 when a check fails. A
+// config that doesn't load is reported, and the other checks use defaults
+func runDoctor(cmd *cobra.Command, args []string) {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	applyConfig(cfg)
 
-	performClean(report)
+	fmt.Printf("jellysink %s: checking the installation...\n", version)
+	results := daemon.RunDoctor(cfg, err, daemon.DoctorOptions{Version: version, Commit: commit})
+	daemon.PrintDoctorResults(os.Stdout, results)
+	if daemon.DoctorFailed(results) {
+		os.Exit(1)
+	}
 }
 
 func runConfig(cmd *cobra.Command, args []string) {
@@ -366,6 +1956,12 @@ func runConfig(cmd *cobra.Command, args []string) {
 	}
 
 	fmt.Println("Current configuration:")
+	oplog.SetDataDir(cfg.DataDir)
+	dataDir := oplog.DataDir()
+	if os.Getenv(oplog.DataDirEnv) != "" {
+		dataDir += " (from " + oplog.DataDirEnv + ")"
+	}
+	fmt.Printf("\nData directory: %s\n", dataDir)
 	fmt.Printf("\nMovie libraries (%d):\n", len(cfg.Libraries.Movies.Paths))
 	for _, path := range cfg.Libraries.Movies.Paths {
 		fmt.Printf("  - %s\n", path)
@@ -376,12 +1972,252 @@ func runConfig(cmd *cobra.Command, args []string) {
 		fmt.Printf("  - %s\n", path)
 	}
 
+	if len(cfg.Libraries.Anime.Paths) > 0 {
+		fmt.Printf("\nAnime libraries (%d):\n", len(cfg.Libraries.Anime.Paths))
+		for _, path := range cfg.Libraries.Anime.Paths {
+			fmt.Printf("  - %s\n", path)
+		}
+		lookup := cfg.Libraries.Anime.Lookup
+		if lookup == "" {
+			lookup = "off"
+		}
+		fmt.Printf("  Title lookup: %s\n", lookup)
+	}
+
+	for _, named := range cfg.Libraries.Named {
+		fmt.Printf("\nLibrary %s (%s, %d):\n", named.Name, named.Type, len(named.Paths))
+		for _, path := range named.Paths {
+			fmt.Printf("  - %s\n", path)
+		}
+	}
+	if cfg.HasLibraryOverrides() {
+		if schedules, err := daemon.LibrarySchedules(cfg); err == nil {
+			fmt.Println("\nLibrary scans (jellysinkd --daemon):")
+			for _, s := range schedules {
+				fmt.Printf("  %s: %s, strategy %s, merge_companions %v\n", strings.Join(s.Libraries, ", "), s.Schedule, s.Config.Duplicates.Strategy, s.Config.Duplicates.MergeCompanions)
+			}
+		}
+	}
+
+	excluded := cfg.Libraries.ExcludeDirs
+	if excluded == nil {
+		excluded = scanner.DefaultExcludedDirs
+	}
+	fmt.Printf("\nExcluded folders: %s\n", strings.Join(excluded, ", "))
+	if len(cfg.Scan.Exclude) > 0 {
+		fmt.Printf("Exclude patterns: %s\n", strings.Join(cfg.Scan.Exclude, ", "))
+	}
+	if cfg.Scan.AllowVersions {
+		fmt.Println("Movie versions: other editions and resolutions are kept")
+	}
+	if f := cfg.Scan.BitrateOutlierFactor; f > 0 {
+		fmt.Printf("Bitrate outliers: episodes %gx off their season's median\n", f)
+	}
+	if rules := daemon.NewLanguageRules(cfg); rules.Enabled() {
+		fmt.Printf("Languages: required audio %s, required subtitles %s, wanted %s, max unwanted tracks %d\n",
+			listOrNone(rules.RequiredAudio), listOrNone(rules.RequiredSubtitles), listOrNone(rules.Wanted), rules.MaxUnwantedTracks)
+	}
+	if len(cfg.Cleaner.ProtectedPaths) > 0 {
+		fmt.Printf("Protected paths: %s\n", strings.Join(cfg.Cleaner.ProtectedPaths, ", "))
+	}
+	if phrase := cfg.Cleaner.ConfirmPhrase; phrase != "" && phrase != "yes" {
+		fmt.Printf("Confirmation phrase: %q\n", phrase)
+	}
+	if cfg.Cleaner.HardlinkDuplicates {
+		fmt.Printf("Duplicates: replaced with hardlinks to the kept copy\n")
+	}
+
 	fmt.Printf("\nDaemon settings:\n")
-	fmt.Printf("  Scan frequency: %s\n", cfg.Daemon.ScanFrequency)
+	if cfg.Daemon.ScanSchedule != "" {
+		fmt.Printf("  Scan schedule: %s\n", cfg.Daemon.ScanSchedule)
+	} else {
+		fmt.Printf("  Scan frequency: %s\n", cfg.Daemon.ScanFrequency)
+		fmt.Printf("  Scan time: %s\n", cfg.Daemon.ScanTime)
+	}
+	if status, err := daemon.ReadServiceStatus(); err == nil {
+		fmt.Printf("  jellysinkd --daemon: %s (pid %d)\n", status.State, status.PID)
+		if !status.NextScan.IsZero() {
+			fmt.Printf("  Next scan: %s\n", status.NextScan.Local().Format("Mon 2006-01-02 15:04"))
+		}
+	}
+	fmt.Printf("  Review terminal: %s\n", daemon.TerminalStatus(cfg.Daemon.TerminalCommand))
+	fmt.Printf("  Auto-clean severities: %v\n", cfg.Daemon.AutoCleanSeverities)
+	fmt.Printf("  Auto-clean confirms duplicates over: %d scans\n", cfg.Daemon.AutoCleanConfirmScans)
+	fmt.Printf("  Auto-clean policy: %s\n", strings.Join(daemon.PolicyRules(cfg.AutoClean), "; "))
+	fmt.Printf("  Watch debounce: %ds (auto-fix new files: %v)\n", cfg.Daemon.WatchDebounce, cfg.Daemon.WatchAutoFix)
+	if cfg.Daemon.DeferWhileStreaming {
+		fmt.Printf("  Defer while streaming: on (until Jellyfin is idle for %d min)\n", cfg.Daemon.StreamingIdleMinutes)
+	} else {
+		fmt.Printf("  Defer while streaming: off\n")
+	}
+
+	reporter.SetOutput(cfg.Reports)
+	fmt.Printf("\nReports:\n")
+	fmt.Printf("  Directory: %s\n", reporter.ReportDir())
+	fmt.Printf("  Filename template: %s\n", cfg.Reports.FilenameTemplate)
+
+	fmt.Printf("\nNaming profile: %s\n", cfg.Naming.Profile)
+
+	lowercase := cfg.Naming.LowercaseWords
+	if lowercase == nil {
+		lowercase = scanner.DefaultLowercaseWords
+	}
+	fmt.Printf("Lowercase title words: %s\n", strings.Join(lowercase, ", "))
+	fmt.Printf("Episode titles: %v\n", cfg.Naming.EpisodeTitles)
+	fmt.Printf("Show folder years: %v\n", cfg.Naming.ShowFolderYears)
+	if cfg.Naming.AutoResolveMargin > 0 {
+		fmt.Printf("Auto-resolve title conflicts: confidence lead above %.2f\n", cfg.Naming.AutoResolveMargin)
+	} else {
+		fmt.Println("Auto-resolve title conflicts: off")
+	}
+
+	fmt.Printf("\nReport ordering:\n")
+	if cfg.UI.SortLocale != "" {
+		fmt.Printf("  Sort locale: %s\n", cfg.UI.SortLocale)
+	} else {
+		fmt.Printf("  Sort locale: (byte order)\n")
+	}
+	fmt.Printf("  Ignore articles: %v\n", cfg.UI.IgnoreArticles)
+
+	fmt.Printf("\nTUI:\n")
+	if cfg.UI.ShowBanner {
+		fmt.Printf("  Banner: ASCII art (one-line title below %d columns)\n", ui.BannerMinWidth)
+	} else {
+		fmt.Printf("  Banner: one-line title\n")
+	}
+	fmt.Printf("  First-run walkthrough: %v\n", cfg.UI.FirstRunWalkthrough)
+
+	fmt.Printf("\nAPI verification:\n")
+	fmt.Printf("  TVDB enabled: %v\n", cfg.API.TVDB.Enabled)
+	fmt.Printf("  OMDB enabled: %v\n", cfg.API.OMDB.Enabled)
+	fmt.Printf("  TMDB enabled: %v\n", cfg.API.TMDB.Enabled)
+	fmt.Printf("  Failure threshold: %d\n", cfg.API.FailureThreshold)
+	if cfg.API.CacheTTLDays > 0 {
+		fmt.Printf("  Cache: %s (%d days)\n", scanner.DefaultAPICachePath(), cfg.API.CacheTTLDays)
+	} else {
+		fmt.Printf("  Cache: this scan only\n")
+	}
+	if cfg.API.ProxyURL != "" {
+		fmt.Printf("  Proxy: %s\n", cfg.API.ProxyURL)
+	} else {
+		fmt.Printf("  Proxy: from environment\n")
+	}
+	if cfg.API.CABundle != "" {
+		fmt.Printf("  CA bundle: %s\n", cfg.API.CABundle)
+	}
+
+	fmt.Printf("\nJellyfin:\n")
+	if cfg.Jellyfin.URL == "" {
+		fmt.Printf("  Server: not configured\n")
+	} else {
+		fmt.Printf("  Server: %s\n", cfg.Jellyfin.URL)
+		fmt.Printf("  Compare scans: %v\n", cfg.Jellyfin.Compare)
+		fmt.Printf("  Refresh after clean: %v\n", cfg.Jellyfin.RefreshAfterClean)
+		for _, mapping := range cfg.Jellyfin.PathMappings {
+			fmt.Printf("  Path mapping: %s\n", mapping)
+		}
+	}
+
+	for _, arr := range []struct {
+		name string
+		cfg  config.ArrConfig
+	}{{"Sonarr", cfg.Sonarr}, {"Radarr", cfg.Radarr}} {
+		fmt.Printf("\n%s:\n", arr.name)
+		if arr.cfg.URL == "" {
+			fmt.Printf("  Server: not configured\n")
+			continue
+		}
+		fmt.Printf("  Server: %s\n", arr.cfg.URL)
+		fmt.Printf("  Rescan after rename: %v\n", arr.cfg.RescanAfterRename)
+		for _, mapping := range arr.cfg.PathMappings {
+			fmt.Printf("  Path mapping: %s\n", mapping)
+		}
+	}
+
+	fmt.Printf("\nTags:\n")
+	fmt.Printf("  Keep: %s\n", strings.Join(cfg.Tags.Keep, ", "))
+	fmt.Printf("  Protected: %s\n", strings.Join(cfg.Tags.Protected, ", "))
+	fmt.Printf("  Tag file: %s\n", tags.DefaultPath())
+
+	fmt.Printf("\nDuplicates:\n")
+	fmt.Printf("  Strategy: %s\n", cfg.Duplicates.Strategy)
+	fmt.Printf("  Merge subtitles and artwork into keepers: %v\n", cfg.Duplicates.MergeCompanions)
+	scopes := daemon.NewDuplicateScopes(cfg)
+	fmt.Printf("  Scope: movies %s, TV %s\n", scopes.Movies, scopes.TV)
+	scanner.SetFFprobe(cfg.Duplicates.FFprobe)
+	fmt.Printf("  ffprobe: %s\n", scanner.FFprobeStatus())
+
+	fmt.Printf("\nEmail notifications:\n")
+	if email := cfg.Notify.Email; email.Enabled() {
+		fmt.Printf("  Server: %s:%d (%s)\n", email.Host, email.Port, email.TLS)
+		fmt.Printf("  To: %s\n", strings.Join(email.To, ", "))
+	} else {
+		fmt.Printf("  Server: not configured\n")
+	}
+
+	fmt.Printf("\nEvent notifications:\n")
+	printTarget := func(name string, enabled bool, events []string) {
+		switch {
+		case !enabled:
+			fmt.Printf("  %s: not configured\n", name)
+		case len(events) == 0:
+			fmt.Printf("  %s: scan, clean and error events\n", name)
+		default:
+			fmt.Printf("  %s: %s events\n", name, strings.Join(events, ", "))
+		}
+	}
+	printTarget("Webhook", cfg.Notify.Webhook.URL != "", cfg.Notify.Webhook.Events)
+	printTarget("Discord", cfg.Notify.Discord.WebhookURL != "", cfg.Notify.Discord.Events)
+	printTarget("Telegram", cfg.Notify.Telegram.BotToken != "", cfg.Notify.Telegram.Events)
+
+	fmt.Printf("\nPerformance:\n")
+	fmt.Printf("  Low memory: %v\n", cfg.Performance.LowMemory)
+	if cfg.Performance.CloudSafe {
+		fmt.Printf("  Cloud-safe profile: on (%d folder listings/s)\n", cfg.Performance.CloudListRate)
+		for _, line := range scanner.DescribeCloudPaths(cfg.GetAllPaths()) {
+			fmt.Printf("    %s\n", line)
+		}
+	} else {
+		fmt.Printf("  Cloud-safe profile: off\n")
+	}
 }
 
 func loadConfig() (*config.Config, error) {
-	return config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	applyConfig(cfg)
+	migrateDataDir()
+	return cfg, nil
+}
+
+// migrateDataDir moves data from ~/.local/share/jellysink the first time
+// the data directory is set elsewhere
+func migrateDataDir() {
+	if m, err := oplog.MigrateDataDir(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else if m != nil {
+		fmt.Fprintln(os.Stderr, m)
+	}
+}
+
+// applyConfig installs the package-level settings derived from cfg
+func applyConfig(cfg *config.Config) {
+	daemon.ApplyConfig(cfg)
+	confirmPhrase = cfg.Cleaner.ConfirmPhrase
+	ui.SetShowBanner(cfg.UI.ShowBanner)
+	ui.SetWalkthrough(ui.Walkthrough{
+		Enabled:               cfg.UI.FirstRunWalkthrough,
+		AutoCleanSeverities:   cfg.Daemon.AutoCleanSeverities,
+		AutoCleanConfirmScans: cfg.Daemon.AutoCleanConfirmScans,
+		IdenticalOnly:         cfg.AutoClean.IdenticalOnly,
+		MinAgeDays:            cfg.AutoClean.MinAgeDays,
+		MaxDeleteGB:           cfg.AutoClean.MaxDeleteGB,
+		ProtectedPaths:        cfg.Cleaner.ProtectedPaths,
+		ProtectedTags:         cfg.Tags.Protected,
+	})
+	ui.SetLibraryPaths(cfg.MoviePaths(), cfg.ShowPaths())
 }
 
 func getLongDescription() string {
@@ -391,17 +2227,121 @@ func getLongDescription() string {
 }
 
 func loadReport(path string) (reporter.Report, error) {
-	data, err := os.ReadFile(path)
+	report, err := reporter.ReadReport(path)
+	if err != nil {
+		return reporter.Report{}, err
+	}
+
+	// Files tagged since the scan take over the keeper slot
+	reporter.ApplyKeepTags(&report, tags.CurrentRules())
+	// Totals follow the current duplicate strategy
+	report.RecountTotals()
+	return report, nil
+}
+
+// loadReportConfig applies the config for commands that work from a report
+// (tags, protected paths, the trash, in-use checks, the post-clean refresh)
+// without creating a config file. The defaults apply only when there is no
+// config file; one that doesn't load stops the command, since cleaning
+// without the user's protected_paths and tags could delete what they guard
+func loadReportConfig() {
+	cfg := config.DefaultConfig()
+	if path, err := config.ConfigPath(); err == nil {
+		if _, err := os.Stat(path); err == nil {
+			loaded, err := config.Load()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Fix %s, or run jellysink without arguments to open it in safe mode\n", path)
+				os.Exit(1)
+			}
+			cfg = loaded
+		}
+	}
+	applyConfig(cfg)
+	migrateDataDir()
+}
+
+// applyTagFilter keeps only findings on paths carrying --tag
+func applyTagFilter(report *reporter.Report) error {
+	if tagFilter == "" {
+		return nil
+	}
+
+	tag, err := tags.Normalize(tagFilter)
 	if err != nil {
-		return reporter.Report{}, fmt.Errorf("failed to read report file: %w", err)
+		return err
 	}
+	var store *tags.Store
+	if rules := tags.CurrentRules(); rules != nil {
+		store = rules.Store
+	}
+	*report = reporter.FilterByTag(*report, store, tag)
+	return nil
+}
 
-	var report reporter.Report
-	if err := json.Unmarshal(data, &report); err != nil {
-		return reporter.Report{}, fmt.Errorf("failed to parse report: %w", err)
+// applySeverityFilter drops compliance issues below --min-severity
+func applySeverityFilter(report *reporter.Report) error {
+	if minSeverity == "" {
+		return nil
 	}
 
-	return report, nil
+	severity, err := scanner.ParseIssueSeverity(minSeverity)
+	if err != nil {
+		return err
+	}
+
+	report.ComplianceIssues = scanner.FilterIssuesByMinSeverity(report.ComplianceIssues, severity)
+	return nil
+}
+
+// printExplanations prints the explain output for every finding, or only the
+// finding matching id when id is not "all"
+func printExplanations(report reporter.Report, id string) error {
+	var explanations []scanner.Explanation
+	for _, dup := range report.MovieDuplicates {
+		explanations = append(explanations, scanner.ExplainMovieDuplicate(dup))
+	}
+	for _, dup := range report.TVDuplicates {
+		explanations = append(explanations, scanner.ExplainTVDuplicate(dup))
+	}
+	for _, issue := range report.ComplianceIssues {
+		explanations = append(explanations, scanner.ExplainComplianceIssue(issue))
+	}
+
+	printed := 0
+	for _, exp := range explanations {
+		if id != "all" && exp.FindingID != id {
+			continue
+		}
+		if printed > 0 {
+			fmt.Println()
+		}
+		fmt.Print(exp.String())
+		printed++
+	}
+
+	if printed == 0 {
+		if id != "all" {
+			return fmt.Errorf("no finding with ID %s in report", id)
+		}
+		fmt.Println("No findings to explain.")
+	}
+	return nil
+}
+
+// printWastedSpace writes the wasted-space-by-folder tree to stdout
+func printWastedSpace(report reporter.Report, format string, depth int) error {
+	tree := reporter.BuildWasteTree(report)
+	switch strings.ToLower(format) {
+	case "text":
+		return reporter.WriteWasteText(os.Stdout, tree, depth)
+	case "csv":
+		return reporter.WriteWasteCSV(os.Stdout, tree, depth)
+	case "json":
+		return reporter.WriteWasteJSON(os.Stdout, tree)
+	default:
+		return fmt.Errorf("invalid --waste format: %s (must be text, csv, or json)", format)
+	}
 }
 
 func performConflictRenames(report reporter.Report, conflicts []*scanner.TVTitleResolution) {
@@ -419,6 +2359,8 @@ func performConflictRenames(report reporter.Report, conflicts []*scanner.TVTitle
 	totalResults := []interface{}{}
 	successCount := 0
 	errorCount := 0
+	logPath := oplog.DefaultPath(oplog.RenameLog)
+	var logErr error
 
 	for _, conflict := range conflicts {
 		if conflict.UserDecision == 0 {
@@ -465,6 +2407,9 @@ func performConflictRenames(report reporter.Report, conflicts []*scanner.TVTitle
 				errorCount++
 				continue
 			}
+			if err := scanner.LogRenames(logPath, results); err != nil {
+				logErr = err
+			}
 
 			// Verify results contain at least one successful operation
 			showSuccessCount := 0
@@ -495,9 +2440,7 @@ func performConflictRenames(report reporter.Report, conflicts []*scanner.TVTitle
 		fmt.Printf("✗ Errors: %d\n", errorCount)
 	}
 
-	home, _ := os.UserHomeDir()
-	logPath := filepath.Join(home, ".local/share/jellysink/rename.log")
-	fmt.Printf("\nOperation log saved to: %s\n", logPath)
+	printRenameLog(logPath, logErr)
 }
 
 func performManualRenames(report reporter.Report, editedTitles map[int]string) {
@@ -505,11 +2448,7 @@ func performManualRenames(report reporter.Report, editedTitles map[int]string) {
 	fmt.Printf("Shows to rename: %d\n\n", len(editedTitles))
 
 	// Confirm with user
-	fmt.Print("Are you sure you want to proceed? (yes/no): ")
-	var response string
-	fmt.Scanln(&response)
-
-	if response != "yes" {
+	if !confirm("Are you sure you want to proceed?") {
 		fmt.Println("Rename cancelled.")
 		return
 	}
@@ -517,6 +2456,8 @@ func performManualRenames(report reporter.Report, editedTitles map[int]string) {
 	totalResults := []interface{}{}
 	successCount := 0
 	errorCount := 0
+	logPath := oplog.DefaultPath(oplog.RenameLog)
+	var logErr error
 
 	// Apply each rename
 	for idx, newTitle := range editedTitles {
@@ -545,6 +2486,9 @@ func performManualRenames(report reporter.Report, editedTitles map[int]string) {
 				errorCount++
 				continue
 			}
+			if err := scanner.LogRenames(logPath, results); err != nil {
+				logErr = err
+			}
 
 			for _, result := range results {
 				totalResults = append(totalResults, result)
@@ -570,32 +2514,54 @@ func performManualRenames(report reporter.Report, editedTitles map[int]string) {
 		fmt.Printf("✗ Errors: %d\n", errorCount)
 	}
 
-	// Save operation log
-	home, _ := os.UserHomeDir()
-	logPath := filepath.Join(home, ".local/share/jellysink/rename.log")
-	fmt.Printf("\nOperation log saved to: %s\n", logPath)
+	printRenameLog(logPath, logErr)
 }
 
-func performClean(report reporter.Report) {
-	fmt.Println("\nStarting cleanup operation...")
-	fmt.Printf("Duplicates to delete: %d files\n", report.TotalFilesToDelete)
-	fmt.Printf("Compliance issues to fix: %d\n", len(report.ComplianceIssues))
-	fmt.Printf("Space to free: %s\n\n", formatBytes(report.SpaceToFree))
+// printRenameLog points at the rename log, or says why it is incomplete
+func printRenameLog(logPath string, logErr error) {
+	if logErr != nil {
+		printLine(os.Stderr, "⚠ Could not write operation log: %v", logErr)
+		return
+	}
+	printLine(os.Stdout, "\nOperation log saved to: %s", logPath)
+}
 
-	// Confirm with user
-	fmt.Print("Are you sure you want to proceed? (yes/no): ")
-	var response string
-	fmt.Scanln(&response)
+func performClean(report reporter.Report, reportPath string) {
+	if report.Cleaned != nil {
+		printLine(os.Stdout, "\n⚠ This report was already %s", report.Cleaned.Banner())
+	}
 
-	if response != "yes" {
-		fmt.Println("Cleanup cancelled.")
-		return
+	printLine(os.Stdout, "\nStarting cleanup operation...")
+	printLine(os.Stdout, "Duplicates to delete: %d files", report.TotalFilesToDelete)
+	printLine(os.Stdout, "Compliance issues to fix: %d", len(report.ComplianceIssues))
+	if cleanJunk {
+		printLine(os.Stdout, "Orphaned folders to delete: %d", len(report.OrphanFolders))
+	} else if len(report.OrphanFolders) > 0 {
+		printLine(os.Stdout, "Orphaned folders found: %d (add --junk to delete them)", len(report.OrphanFolders))
 	}
+	printLine(os.Stdout, "Space to free: %s\n", formatBytes(report.SpaceToFree))
 
-	// Execute cleanup
 	config := cleaner.DefaultConfig()
 	config.DryRun = false
+	if cleanJunk {
+		config.OrphanFolders = report.OrphanFolders
+	}
+
+	// Moves on cloud mounts can mean re-uploading whole files
+	if warnings := cleaner.CloudWarnings(report.MovieDuplicates, report.TVDuplicates, report.ComplianceIssues, config); len(warnings) > 0 {
+		for _, warning := range warnings {
+			printLine(os.Stdout, "⚠ %s", warning)
+		}
+		printLine(os.Stdout, "")
+	}
+
+	// Confirm with user
+	if !confirm("Are you sure you want to proceed?") {
+		printLine(os.Stdout, "Cleanup cancelled.")
+		return
+	}
 
+	// Execute cleanup
 	result, err := cleaner.Clean(
 		report.MovieDuplicates,
 		report.TVDuplicates,
@@ -604,27 +2570,143 @@ func performClean(report reporter.Report) {
 	)
 
 	if err != nil {
-		fmt.Printf("Error during cleanup: %v\n", err)
+		printLine(os.Stdout, "Error during cleanup: %v", err)
 		os.Exit(1)
 	}
 
 	// Show results
-	fmt.Println("\nCleanup completed!")
-	fmt.Printf("✓ Duplicates deleted: %d\n", result.DuplicatesDeleted)
-	fmt.Printf("✓ Compliance issues fixed: %d\n", result.ComplianceFixed)
-	fmt.Printf("✓ Space freed: %s\n", formatBytes(result.SpaceFreed))
+	printLine(os.Stdout, "\nCleanup completed!")
+	printLine(os.Stdout, "✓ Duplicates deleted: %d", result.DuplicatesDeleted)
+	if result.DuplicatesLinked > 0 {
+		printLine(os.Stdout, "✓ Duplicates replaced with hardlinks: %d", result.DuplicatesLinked)
+	}
+	if result.VersionsKept > 0 {
+		printLine(os.Stdout, "✓ Copies kept as Jellyfin versions: %d", result.VersionsKept)
+	}
+	if result.CompanionsMerged > 0 {
+		printLine(os.Stdout, "✓ Subtitles and artwork moved to keepers: %d", result.CompanionsMerged)
+	}
+	printLine(os.Stdout, "✓ Compliance issues fixed: %d", result.ComplianceFixed)
+	if cleanJunk {
+		printLine(os.Stdout, "✓ Orphaned folders deleted: %d", result.FoldersRemoved)
+	}
+	printLine(os.Stdout, "✓ Space freed: %s", formatBytes(result.SpaceFreed))
+	if result.JournalID != "" {
+		printLine(os.Stdout, "✓ Undo with: jellysink undo %s", result.JournalID)
+	}
+	if result.LibraryRefreshed {
+		printLine(os.Stdout, "✓ Jellyfin library scan requested")
+	} else if result.RefreshErr != nil {
+		printLine(os.Stdout, "⚠ Jellyfin library scan request failed: %v", result.RefreshErr)
+	}
+
+	if len(result.Deferred) > 0 {
+		printLine(os.Stdout, "\n⚠ Deferred (in use): %d", len(result.Deferred))
+		for i, path := range result.Deferred {
+			printLine(os.Stdout, "  %d. %s", i+1, path)
+		}
+		printLine(os.Stdout, "Run the clean again with --force once playback has finished.")
+	}
+
+	if len(result.Importing) > 0 {
+		printLine(os.Stdout, "\n⚠ Deferred (import in progress): %d", len(result.Importing))
+		for i, path := range result.Importing {
+			printLine(os.Stdout, "  %d. %s", i+1, path)
+		}
+		printLine(os.Stdout, "Sonarr, Radarr or a download client is writing into these folders; the next scan picks them up again.")
+	}
+
+	if len(result.Protected) > 0 {
+		printLine(os.Stdout, "\n⚠ Skipped (protected path): %d", len(result.Protected))
+		for i, path := range result.Protected {
+			printLine(os.Stdout, "  %d. %s", i+1, path)
+		}
+	}
 
 	if len(result.Errors) > 0 {
-		fmt.Printf("\n⚠ Errors encountered: %d\n", len(result.Errors))
+		printLine(os.Stdout, "\n⚠ Errors encountered: %d", len(result.Errors))
 		for i, err := range result.Errors {
-			fmt.Printf("  %d. %v\n", i+1, err)
+			printLine(os.Stdout, "  %d. %v", i+1, err)
 		}
 	}
 
-	// Save operation log location
-	home, _ := os.UserHomeDir()
-	logPath := filepath.Join(home, ".local/share/jellysink/operations.log")
-	fmt.Printf("\nOperation log saved to: %s\n", logPath)
+	// Record the clean in the report so later views show it was processed
+	summary := reporter.NewCleanSummary(result.DuplicatesDeleted, result.ComplianceFixed, result.SpaceFreed, result.Errors)
+	summary.Deferred = result.Deferred
+	summary.Importing = result.Importing
+	summary.Protected = result.Protected
+	summary.VersionsKept = result.VersionsKept
+	summary.FoldersRemoved = result.FoldersRemoved
+	if err := reporter.MarkCleaned(reportPath, summary); err != nil {
+		printLine(os.Stderr, "⚠ Could not mark report as cleaned: %v", err)
+	}
+
+	printLine(os.Stdout, "\nOperation log saved to: %s", config.LogPath)
+}
+
+// plainMarks are the ASCII tags --no-tui prints in place of status glyphs
+var plainMarks = strings.NewReplacer("✓", "[OK]", "✗", "[ERROR]", "⚠", "[WARN]")
+
+// confirmPhrase is what confirm asks for (cleaner.confirm_phrase)
+var confirmPhrase = "yes"
+
+// confirm asks before a change, exiting when the answer cannot be read
+func confirm(prompt string) bool {
+	info, err := os.Stdin.Stat()
+	terminal := err == nil && info.Mode()&os.ModeCharDevice != 0
+	ok, err := readConfirmation(os.Stdin, os.Stdout, terminal, prompt)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return ok
+}
+
+// readConfirmation prints prompt and reports whether the answer is the
+// confirmation phrase. --yes answers for the user; without it, input that
+// is not a terminal is refused rather than read, so piped or scheduled
+// runs fail at once instead of hanging or acting on stray input
+func readConfirmation(in io.Reader, out io.Writer, terminal bool, prompt string) (bool, error) {
+	phrase := confirmPhrase
+	if phrase == "" {
+		phrase = "yes"
+	}
+	if assumeYes {
+		printLine(out, "%s yes (--yes)", prompt)
+		return true, nil
+	}
+	if !terminal {
+		return false, fmt.Errorf("confirmation needed but stdin is not a terminal; rerun with --yes to proceed without asking")
+	}
+
+	question := prompt + " (yes/no):"
+	if phrase != "yes" {
+		question = fmt.Sprintf("%s Type %q to proceed:", prompt, phrase)
+	}
+	if noTUI {
+		printLine(out, "%s", question)
+	} else {
+		fmt.Fprint(out, question+" ")
+	}
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	return strings.TrimSpace(answer) == phrase, nil
+}
+
+// printLine writes one scan/clean output line. With --no-tui every line is
+// timestamped, spacer lines are dropped and glyphs become ASCII tags so
+// screen/nohup logs stay readable after the run
+func printLine(w io.Writer, format string, args ...interface{}) {
+	line := fmt.Sprintf(format, args...)
+	if !noTUI {
+		fmt.Fprintln(w, line)
+		return
+	}
+	stamp := time.Now().Format("2006-01-02 15:04:05")
+	for _, l := range strings.Split(line, "\n") {
+		if strings.TrimSpace(l) != "" {
+			fmt.Fprintf(w, "%s %s\n", stamp, plainMarks.Replace(l))
+		}
+	}
 }
 
 func formatBytes(bytes int64) string {
@@ -1,10 +1,18 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Nomadcxx/jellysink/internal/config"
 	"github.com/Nomadcxx/jellysink/internal/daemon"
+	"github.com/Nomadcxx/jellysink/internal/reporter"
 	"github.com/Nomadcxx/jellysink/internal/scanner"
 )
 
@@ -92,3 +100,170 @@ func TestDaemonConfigAppliesWhenNoCLIFlag(t *testing.T) {
 		t.Errorf("Expected LogLevelVerbose (from config), got %v", actualLogLevel)
 	}
 }
+
+func TestPrintLinePlainOutput(t *testing.T) {
+	defer func() { noTUI = false }()
+
+	var buf bytes.Buffer
+	noTUI = false
+	printLine(&buf, "\n✓ Scan complete! Report saved to:\n  %s\n", "/tmp/r.json")
+	if buf.String() != "\n✓ Scan complete! Report saved to:\n  /tmp/r.json\n\n" {
+		t.Errorf("Default output changed: %q", buf.String())
+	}
+
+	buf.Reset()
+	noTUI = true
+	printLine(&buf, "\n✓ Scan complete! Report saved to:\n  %s\n", "/tmp/r.json")
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected spacer lines dropped, got %q", buf.String())
+	}
+	stamped := regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2} `)
+	for _, line := range lines {
+		if !stamped.MatchString(line) {
+			t.Errorf("Expected timestamp prefix: %q", line)
+		}
+	}
+	if !strings.Contains(lines[0], "[OK] Scan complete!") || strings.Contains(buf.String(), "✓") {
+		t.Errorf("Expected ASCII status tag, got %q", lines[0])
+	}
+}
+
+func TestReadConfirmation(t *testing.T) {
+	defer func() { assumeYes, confirmPhrase = false, "yes" }()
+
+	tests := []struct {
+		name     string
+		phrase   string
+		yes      bool
+		terminal bool
+		input    string
+		want     bool
+		wantErr  bool
+	}{
+		{"yes confirms", "yes", false, true, "yes\n", true, false},
+		{"anything else cancels", "yes", false, true, "y\n", false, false},
+		{"no input cancels", "yes", false, true, "", false, false},
+		{"custom phrase", "delete my files", false, true, "  delete my files\n", true, false},
+		{"yes is not the custom phrase", "delete my files", false, true, "yes\n", false, false},
+		{"--yes skips the question", "delete my files", true, false, "", true, false},
+		{"no terminal refused", "yes", false, false, "yes\n", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assumeYes, confirmPhrase = tt.yes, tt.phrase
+			var out bytes.Buffer
+			got, err := readConfirmation(strings.NewReader(tt.input), &out, tt.terminal, "Proceed?")
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("readConfirmation() = %v, %v; want %v (error %v)", got, err, tt.want, tt.wantErr)
+			}
+			if tt.phrase != "yes" && tt.terminal && !strings.Contains(out.String(), tt.phrase) {
+				t.Errorf("Expected the prompt to name the phrase, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestEnsureNotCleaned(t *testing.T) {
+	report := reporter.Report{}
+	if err := ensureNotCleaned(report, false); err != nil {
+		t.Errorf("Expected fresh report to be cleanable, got %v", err)
+	}
+
+	summary := reporter.NewCleanSummary(4, 1, 1024, nil)
+	report.Cleaned = &summary
+	err := ensureNotCleaned(report, false)
+	if err == nil {
+		t.Fatal("Expected already-cleaned report to be refused")
+	}
+	if !strings.Contains(err.Error(), "CLEANED on") || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("Expected banner and --force hint, got %q", err.Error())
+	}
+
+	if err := ensureNotCleaned(report, true); err != nil {
+		t.Errorf("Expected --force to allow re-cleaning, got %v", err)
+	}
+}
+
+func TestSchemaDocument(t *testing.T) {
+	for format, property := range map[string]string{"report": "ComplianceIssues", "config": "daemon", "plan": "operations", "preview": "errors"} {
+		doc, err := schemaDocument(format)
+		if err != nil {
+			t.Fatalf("schemaDocument(%s) failed: %v", format, err)
+		}
+		if _, err := json.Marshal(doc); err != nil {
+			t.Errorf("%s schema does not encode: %v", format, err)
+		}
+		if _, ok := doc["properties"].(map[string]any)[property]; !ok {
+			t.Errorf("Expected %s schema to describe %s", format, property)
+		}
+	}
+	if _, err := schemaDocument("journal"); err == nil {
+		t.Error("Expected an unknown format to be rejected")
+	}
+}
+
+func TestPreviewClean(t *testing.T) {
+	dir := t.TempDir()
+	keeper := filepath.Join(dir, "Heat (1995)", "Heat (1995).mkv")
+	extra := filepath.Join(dir, "Heat (1995)", "Heat.1995.720p.mkv")
+	for _, path := range []string{keeper, extra} {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte("heat"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	report := reporter.Report{
+		MovieDuplicates: []scanner.MovieDuplicate{{
+			NormalizedName: "heat",
+			Year:           "1995",
+			Files:          []scanner.MovieFile{{Path: keeper, Size: 4}, {Path: extra, Size: 4}},
+		}},
+		ComplianceIssues: []scanner.ComplianceIssue{{
+			Path: filepath.Join(dir, "missing.mkv"), Type: "movie", Problem: "Loose movie",
+			SuggestedAction: "reorganize", SuggestedPath: filepath.Join(dir, "Missing", "Missing.mkv"),
+		}},
+		TotalDuplicates:    1,
+		TotalFilesToDelete: 1,
+		SpaceToFree:        4,
+	}
+
+	preview, err := previewClean(report, "/reports/scan.json")
+	if err != nil {
+		t.Fatalf("previewClean() failed: %v", err)
+	}
+	if preview.Report != "/reports/scan.json" || preview.FilesToDelete != 1 || preview.ComplianceIssues != 1 {
+		t.Errorf("Expected the report totals carried over, got %+v", preview)
+	}
+	if preview.Count(reporter.PreviewReady) != 1 || preview.Operations[0].Source != extra {
+		t.Errorf("Expected the extra copy's delete ready, got %+v", preview.Operations)
+	}
+	if len(preview.Errors) == 0 {
+		t.Error("Expected the missing file's fix reported as an error")
+	}
+	if _, err := os.Stat(extra); err != nil {
+		t.Errorf("Expected the dry run to leave the files alone: %v", err)
+	}
+}
+
+func TestParseAuditTime(t *testing.T) {
+	if got, err := parseAuditTime("", false); err != nil || !got.IsZero() {
+		t.Errorf("Expected an empty value to leave the period open, got %v, %v", got, err)
+	}
+	since, err := parseAuditTime("2024-01-01", false)
+	if err != nil || !since.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("Expected the start of the day, got %v, %v", since, err)
+	}
+	// --until takes in the whole day
+	until, err := parseAuditTime("2024-01-31", true)
+	if err != nil || !until.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("Expected the end of the day, got %v, %v", until, err)
+	}
+	exact, err := parseAuditTime("2024-01-31T18:30:00Z", true)
+	if err != nil || !exact.Equal(time.Date(2024, 1, 31, 18, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected an RFC 3339 time kept as given, got %v, %v", exact, err)
+	}
+	if _, err := parseAuditTime("last week", false); err == nil {
+		t.Error("Expected an unparseable time rejected")
+	}
+}
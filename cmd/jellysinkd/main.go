@@ -2,16 +2,21 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/Nomadcxx/jellysink/internal/config"
 	"github.com/Nomadcxx/jellysink/internal/daemon"
+	"github.com/Nomadcxx/jellysink/internal/notify"
+	"github.com/Nomadcxx/jellysink/internal/oplog"
 	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
 )
 
 var (
@@ -21,12 +26,38 @@ var (
 	buildTime = "unknown"
 
 	// CLI flags
-	testMode = flag.Bool("test", false, "Test mode: run scan and launch kitty to verify workflow")
+	testMode   = flag.Bool("test", false, "Test mode: scan a synthetic in-memory library, then run the review/auto-clean workflow without changing any file")
+	daemonMode = flag.Bool("daemon", false, "Keep running and scan on the daemon.scan_frequency/scan_time schedule (SIGHUP reloads the config)")
+	selfTest   = flag.Bool("self-test", false, "Validate config, library access, API keys and data dir, then scan a built-in fixture")
+	watchMode  = flag.Bool("watch", false, "Keep running and process new or renamed files under the library paths as they arrive (Linux)")
+	showVer    = flag.Bool("version", false, "Show version information and exit")
+
+	// Synthetic library used by --test
+	simDefaults  = scanner.DefaultSimulatedLibrary()
+	testMovies   = flag.Int("test-movies", simDefaults.Movies, "Test mode: number of simulated movies")
+	testShows    = flag.Int("test-shows", simDefaults.Shows, "Test mode: number of simulated TV shows")
+	testEpisodes = flag.Int("test-episodes", simDefaults.EpisodesPerShow, "Test mode: simulated episodes per show")
+	testFileSize = flag.Int64("test-file-size", simDefaults.FileSizeMB, "Test mode: simulated movie file size in MB")
+	testDelay    = flag.Duration("test-delay", simDefaults.Delay, "Test mode: pause per simulated file, e.g. 50ms, to watch progress")
 )
 
 func main() {
 	flag.Parse()
 
+	if *showVer {
+		fmt.Printf("jellysinkd %s\n", version)
+		fmt.Printf("  Commit:     %s\n", commit)
+		fmt.Printf("  Built:      %s\n", buildTime)
+		return
+	}
+	if *selfTest {
+		os.Exit(runSelfTest())
+	}
+	if *testMode && (*testMovies < 0 || *testShows < 0 || *testEpisodes < 0 || *testFileSize < 0 || *testDelay < 0) {
+		fmt.Fprintln(os.Stderr, "Error: --test-* sizes and delay must not be negative")
+		os.Exit(2)
+	}
+
 	// Load configuration
 	cfg, err := loadConfig()
 	if err != nil {
@@ -41,6 +72,17 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *daemonMode && *watchMode {
+		fmt.Fprintln(os.Stderr, "Error: --daemon and --watch are separate modes; run one of each to combine them")
+		os.Exit(2)
+	}
+	if *daemonMode {
+		os.Exit(runDaemon(cfg))
+	}
+	if *watchMode {
+		os.Exit(runWatch(cfg))
+	}
+
 	// Create context with cancellation support
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -54,31 +96,83 @@ func main() {
 		cancel()
 	}()
 
-	// Create daemon instance
-	d := daemon.New(cfg)
-
-	// Run scan
+	// Run scan, held while Jellyfin is streaming (daemon.defer_while_streaming)
+	var gate *daemon.StreamingGate
 	if *testMode {
-		fmt.Println("jellysinkd: Running in TEST MODE...")
+		fmt.Println("jellysinkd: Running in TEST MODE (simulated library, no changes made)...")
 	} else {
+		gate = daemon.NewStreamingGate(cfg)
+		if gate != nil {
+			if err := gate.Wait(ctx, "scan", logDaemon); err != nil {
+				fmt.Fprintf(os.Stderr, "Scan cancelled by signal\n")
+				os.Exit(130)
+			}
+		}
 		fmt.Println("jellysinkd: Starting scheduled scan...")
 	}
 
-	reportPath, err := d.RunScan(ctx)
-	if err != nil {
-		if err == context.Canceled {
+	if _, err := runScan(ctx, cfg, gate); err != nil {
+		if errors.Is(err, context.Canceled) {
 			fmt.Fprintf(os.Stderr, "Scan cancelled by signal\n")
 			os.Exit(130)
 		}
-		fmt.Fprintf(os.Stderr, "Scan failed: %v\n", err)
+		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
+}
+
+// runScan runs one scan and its follow-up: report cleanup, trash purge and
+// then auto-clean (headless) or launching the TUI for review. A non-nil gate
+// holds the auto-clean while Jellyfin is streaming. In --test mode it scans
+// the simulated library and only reports what auto-clean would do. Failures
+// other than cancellation are posted to the [notify] targets
+func runScan(ctx context.Context, cfg *config.Config, gate *daemon.StreamingGate) (reportPath string, err error) {
+	defer func() {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			postEvent(cfg, daemon.ErrorEvent(err))
+		}
+	}()
+
+	// Create daemon instance
+	d := daemon.New(cfg)
+
+	// Forward progress at or above progress.daemon_min_severity to stdout (journal)
+	filter := scanner.ProgressFilter{MinSeverity: scanner.SeverityWarn}
+	if cfg.Progress.DaemonMinSeverity != "" {
+		if sev, err := scanner.ParseProgressSeverity(cfg.Progress.DaemonMinSeverity); err == nil {
+			filter.MinSeverity = sev
+		}
+	}
+	progressCh := make(chan scanner.ScanProgress, 100)
+	logDone := make(chan struct{})
+	go func() {
+		defer close(logDone)
+		for p := range scanner.FilterProgress(progressCh, filter) {
+			fmt.Printf("[%s] %s: %s\n", strings.ToUpper(string(p.Severity)), p.Operation, p.Message)
+		}
+	}()
+
+	if *testMode {
+		reportPath, err = d.RunSimulation(ctx, simulatedLibrary(), progressCh)
+	} else {
+		reportPath, err = d.RunScanWithProgress(ctx, progressCh)
+	}
+	close(progressCh)
+	<-logDone
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			if reportPath != "" {
+				fmt.Printf("Partial report saved to: %s\n", reportPath)
+			}
+			return "", err
+		}
+		return "", fmt.Errorf("scan failed: %w", err)
+	}
 
 	// Load report to get statistics
 	report, err := loadReport(reportPath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading report: %v\n", err)
-		os.Exit(1)
+		return reportPath, fmt.Errorf("error loading report: %w", err)
 	}
 
 	fmt.Printf("Scan complete! Found %d duplicate groups", report.TotalDuplicates)
@@ -89,48 +183,502 @@ func main() {
 	}
 	fmt.Printf("Report saved to: %s\n", reportPath)
 
-	// Clean up old reports (30+ days)
-	if err := daemon.CleanupOldReports(); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to clean old reports: %v\n", err)
+	// Library sizes feed the capacity projection in stats and the summary email
+	if !*testMode {
+		capacity, err := daemon.RecordCapacity(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		for _, p := range capacity.Projections {
+			fmt.Printf("Capacity: %s\n", daemon.CapacityLine(p))
+		}
+	}
+
+	if cfg.Notify.Email.Enabled() {
+		if err := daemon.EmailReport(cfg, report, reportPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else {
+			fmt.Printf("Scan summary emailed to %s\n", strings.Join(cfg.Notify.Email.To, ", "))
+		}
+	}
+	postEvent(cfg, daemon.ScanEvent(cfg, report, reportPath))
+
+	if *testMode {
+		fmt.Println("TEST MODE: skipping report cleanup and trash purge")
+	} else {
+		cleanupAfterScan(cfg)
 	}
 
 	// Determine workflow: headless auto-clean or interactive review
-	if d.IsHeadless() && !*testMode {
+	if d.IsHeadless() {
+		if *testMode {
+			fmt.Println("Headless mode detected - simulating auto-clean...")
+			d.SimulateAutoClean(report)
+			return reportPath, nil
+		}
 		fmt.Println("Headless mode detected - running auto-clean...")
-		if err := d.AutoClean(report); err != nil {
-			fmt.Fprintf(os.Stderr, "Auto-clean failed: %v\n", err)
-			os.Exit(1)
+		if gate != nil {
+			if err := gate.Wait(ctx, "auto-clean", logDaemon); err != nil {
+				return reportPath, err
+			}
 		}
+		result, err := d.AutoClean(report)
+		if err != nil {
+			return reportPath, fmt.Errorf("auto-clean failed: %w", err)
+		}
+		postEvent(cfg, daemon.CleanEvent(result))
 	} else {
-		// Interactive mode: launch kitty with report
-		fmt.Println("Launching kitty for interactive review...")
-		if err := daemon.NotifyUser(reportPath); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to launch kitty: %v\n", err)
+		// Interactive mode: open the report in a terminal
+		fmt.Println("Opening the report for interactive review...")
+		how, err := daemon.NotifyUser(cfg, report, reportPath)
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "View report manually with: jellysink view %s\n", reportPath)
-			os.Exit(1)
+			return reportPath, fmt.Errorf("failed to open the report for review: %w", err)
 		}
+		fmt.Printf("Report %s\n", how)
 
 		if *testMode {
-			fmt.Println("\n✓ TEST MODE: Kitty launched successfully!")
-			fmt.Println("  Check if kitty window opened with the scan report.")
+			fmt.Println("\n✓ TEST MODE: review launched successfully!")
+			fmt.Println("  Check that the simulated scan report opened (or its notification appeared).")
 		}
 	}
+
+	return reportPath, nil
 }
 
-func loadConfig() (*config.Config, error) {
-	return config.Load()
+// postEvent sends event to the [notify] webhook, Discord and Telegram
+// targets; a target that fails is logged and does not fail the scan
+func postEvent(cfg *config.Config, event notify.Event) {
+	names, err := daemon.PostEvent(cfg, event)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else if len(names) > 0 {
+		fmt.Printf("Posted %s event to %s\n", event.Kind, strings.Join(names, ", "))
+	}
 }
 
-func loadReport(path string) (reporter.Report, error) {
-	data, err := os.ReadFile(path)
+// logDaemon prints a jellysinkd status line
+func logDaemon(msg string) {
+	fmt.Printf("jellysinkd: %s\n", msg)
+}
+
+// cleanupAfterScan removes old reports and purges expired trash
+func cleanupAfterScan(cfg *config.Config) {
+	// Clean up old reports (30+ days)
+	if err := daemon.CleanupOldReports(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to clean old reports: %v\n", err)
+	}
+	// Duplicate previews not viewed for as long go with them
+	if _, err := scanner.PruneThumbnails(scanner.DefaultThumbnailDir(), 30*24*time.Hour); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to prune thumbnails: %v\n", err)
+	}
+
+	// Permanently delete trash past cleaner.retention_days
+	purged, err := daemon.PurgeExpiredTrash(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to purge trash: %v\n", err)
+	}
+	if purged.Purged > 0 {
+		fmt.Printf("Purged %d trashed items older than %d days (%.2f GB)\n",
+			purged.Purged, cfg.Cleaner.RetentionDays, float64(purged.SpaceFreed)/(1024*1024*1024))
+	}
+	for _, err := range purged.Errors {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// simulatedLibrary returns the synthetic library sized by the --test-* flags
+func simulatedLibrary() scanner.SimulatedLibrary {
+	return scanner.SimulatedLibrary{
+		Movies:          *testMovies,
+		Shows:           *testShows,
+		EpisodesPerShow: *testEpisodes,
+		FileSizeMB:      *testFileSize,
+		Delay:           *testDelay,
+	}
+}
+
+// syncTimer keeps an installed jellysink.timer on the configured schedule,
+// so switching between the timer and --daemon doesn't change when scans run
+func syncTimer(schedule daemon.Schedule) {
+	if rewritten, err := daemon.SyncSystemdTimer(daemon.SystemdTimerPath, schedule); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: jellysink.timer not updated to the new schedule: %v\n", err)
+	} else if rewritten {
+		fmt.Printf("jellysinkd: jellysink.timer now scans %s\n", schedule)
+	}
+}
+
+// runDaemon keeps jellysinkd running, scanning each set of libraries on its
+// configured schedule until SIGINT/SIGTERM. SIGHUP reloads the config, after
+// the current scan if one is running
+func runDaemon(cfg *config.Config) int {
+	pidFile, err := daemon.AcquirePIDFile()
 	if err != nil {
-		return reporter.Report{}, fmt.Errorf("failed to read report: %w", err)
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
 	}
+	defer pidFile.Release()
 
-	var report reporter.Report
-	if err := json.Unmarshal(data, &report); err != nil {
-		return reporter.Report{}, fmt.Errorf("failed to parse report: %w", err)
+	schedule, err := daemon.ConfiguredSchedule(cfg.Daemon)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		return 1
+	}
+	schedules, err := daemon.LibrarySchedules(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		return 1
 	}
+	syncTimer(schedule)
 
-	return report, nil
+	stopCh := make(chan os.Signal, 1)
+	signal.Notify(stopCh, os.Interrupt, syscall.SIGTERM)
+	reloadCh := make(chan os.Signal, 1)
+	signal.Notify(reloadCh, syscall.SIGHUP)
+
+	status := daemon.ServiceStatus{
+		PID:       os.Getpid(),
+		State:     daemon.ServiceIdle,
+		Schedule:  daemon.DescribeSchedules(schedules),
+		StartedAt: time.Now(),
+	}
+	// The status/control API is optional (daemon.http_addr)
+	var api *daemon.API
+	var scanRequests <-chan struct{}
+	if cfg.Daemon.HTTPAddr != "" {
+		api = daemon.NewAPI(cfg.Daemon.HTTPAddr, cfg.Daemon.HTTPToken)
+		scanRequests = api.ScanRequests()
+		apiCtx, stopAPI := context.WithCancel(context.Background())
+		defer stopAPI()
+		go func() {
+			if err := api.ListenAndServe(apiCtx); err != nil {
+				fmt.Fprintf(os.Stderr, "jellysinkd: %v\n", err)
+			}
+		}()
+	}
+
+	writeStatus := func() {
+		if api != nil {
+			api.SetStatus(status)
+		}
+		if err := daemon.WriteServiceStatus(status); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	defer func() {
+		status.State = daemon.ServiceStopped
+		status.NextScan = time.Time{}
+		writeStatus()
+	}()
+
+	reload := func() {
+		newCfg, err := config.Load()
+		if err == nil {
+			err = newCfg.Validate()
+		}
+		var newSchedule daemon.Schedule
+		var newSchedules []daemon.LibrarySchedule
+		if err == nil {
+			newSchedule, err = daemon.ConfiguredSchedule(newCfg.Daemon)
+		}
+		if err == nil {
+			newSchedules, err = daemon.LibrarySchedules(newCfg)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "jellysinkd: config reload failed, keeping the current config: %v\n", err)
+			status.LastError = fmt.Sprintf("config reload failed: %v", err)
+			return
+		}
+		if newCfg.Daemon.HTTPAddr != cfg.Daemon.HTTPAddr || newCfg.Daemon.HTTPToken != cfg.Daemon.HTTPToken {
+			fmt.Println("jellysinkd: http_addr and http_token changes take effect after a restart")
+		}
+		if newCfg.DataDir != cfg.DataDir {
+			fmt.Println("jellysinkd: data_dir changes take effect after a restart")
+			newCfg.DataDir = cfg.DataDir
+		}
+		applyConfig(newCfg)
+		cfg, schedule, schedules = newCfg, newSchedule, newSchedules
+		syncTimer(schedule)
+		status.Schedule = daemon.DescribeSchedules(schedules)
+		status.ReloadedAt = time.Now()
+		status.LastError = ""
+		fmt.Printf("jellysinkd: config reloaded, scanning %s\n", status.Schedule)
+	}
+
+	// holdWhileStreaming keeps a due scan waiting while Jellyfin is
+	// streaming (daemon.defer_while_streaming). It returns false when the
+	// daemon is stopped meanwhile
+	holdWhileStreaming := func() bool {
+		gate := daemon.NewStreamingGate(cfg)
+		logged := false
+		for gate != nil {
+			check := gate.Check(context.Background(), time.Now())
+			if check.Err != nil {
+				fmt.Fprintf(os.Stderr, "jellysinkd: Jellyfin session check failed, not holding the scan: %v\n", check.Err)
+			}
+			if check.Ready {
+				if logged {
+					fmt.Println("jellysinkd: Jellyfin is idle, starting the held scan")
+				}
+				return true
+			}
+			if !logged {
+				fmt.Printf("jellysinkd: holding the scheduled scan while Jellyfin is streaming (%d active)\n", check.Streams)
+				logged = true
+			}
+			status.State = daemon.ServiceDeferred
+			status.NextScan = time.Time{}
+			writeStatus()
+
+			switch waitUntil(check.Recheck, stopCh, reloadCh, scanRequests) {
+			case waitStopped:
+				return false
+			case waitReload:
+				// The reloaded config may turn deferral off; the gate
+				// restarts its idle count either way
+				reload()
+				gate = daemon.NewStreamingGate(cfg)
+			case waitRequested:
+				fmt.Println("jellysinkd: scan requested through the API, no longer holding it")
+				return true
+			}
+		}
+		return true
+	}
+
+	fmt.Printf("jellysinkd: running as a daemon (pid %d), scanning %s\n", status.PID, status.Schedule)
+	for {
+		next, due := daemon.NextDue(schedules, time.Now())
+		status.State = daemon.ServiceIdle
+		status.NextScan = next
+		writeStatus()
+		fmt.Printf("jellysinkd: next scan at %s\n", next.Format("Mon 2006-01-02 15:04 MST"))
+
+		switch waitUntil(next, stopCh, reloadCh, scanRequests) {
+		case waitStopped:
+			fmt.Println("jellysinkd: shutting down")
+			return 0
+		case waitReload:
+			reload()
+			continue
+		case waitRequested:
+			// A requested scan covers every library, each on its own settings
+			due = schedules
+			fmt.Println("jellysinkd: Starting scan requested through the API...")
+		default:
+			if !holdWhileStreaming() {
+				fmt.Println("jellysinkd: shutting down")
+				return 0
+			}
+			fmt.Println("jellysinkd: Starting scheduled scan...")
+		}
+
+		status.State = daemon.ServiceScanning
+		status.NextScan = time.Time{}
+		writeStatus()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		type outcome struct {
+			reportPath string
+			err        error
+		}
+		done := make(chan outcome, 1)
+		gate := daemon.NewStreamingGate(cfg)
+		go func() {
+			var result outcome
+			for _, s := range due {
+				if len(s.Libraries) > 0 {
+					fmt.Printf("jellysinkd: scanning %s\n", strings.Join(s.Libraries, ", "))
+				}
+				reportPath, err := runScan(ctx, s.Config, gate)
+				if reportPath != "" {
+					result.reportPath = reportPath
+				}
+				if err != nil && result.err == nil {
+					result.err = err
+				}
+				if ctx.Err() != nil {
+					break
+				}
+			}
+			done <- result
+		}()
+
+		reloadPending := false
+		var result outcome
+	scanning:
+		for {
+			select {
+			case result = <-done:
+				break scanning
+			case <-reloadCh:
+				fmt.Println("jellysinkd: config reload queued until the scan finishes")
+				reloadPending = true
+			case <-stopCh:
+				fmt.Println("\njellysinkd: Cancelling scan...")
+				cancel()
+				<-done
+				fmt.Println("jellysinkd: shutting down")
+				return 0
+			}
+		}
+		cancel()
+
+		status.LastScan = time.Now()
+		status.LastReport = result.reportPath
+		status.LastError = ""
+		if result.err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", result.err)
+			status.LastError = result.err.Error()
+		}
+		if reloadPending {
+			reload()
+		}
+	}
+}
+
+// runWatch keeps a rolling report up to date as files under the library
+// paths change, until SIGINT/SIGTERM
+func runWatch(cfg *config.Config) int {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		fmt.Println("\njellysinkd: stopping the watch")
+		cancel()
+	}()
+
+	filter := scanner.ProgressFilter{MinSeverity: scanner.SeverityWarn}
+	if cfg.Progress.DaemonMinSeverity != "" {
+		if sev, err := scanner.ParseProgressSeverity(cfg.Progress.DaemonMinSeverity); err == nil {
+			filter.MinSeverity = sev
+		}
+	}
+	progressCh := make(chan scanner.ScanProgress, 100)
+	logDone := make(chan struct{})
+	go func() {
+		defer close(logDone)
+		for p := range scanner.FilterProgress(progressCh, filter) {
+			fmt.Printf("[%s] %s: %s\n", strings.ToUpper(string(p.Severity)), p.Operation, p.Message)
+		}
+	}()
+
+	fmt.Printf("jellysinkd: watching the library paths (changes processed after %ds without more)\n", cfg.Daemon.WatchDebounce)
+	err := daemon.New(cfg).Watch(ctx, progressCh, func(batch daemon.WatchBatch) {
+		if batch.Err != nil {
+			fmt.Fprintf(os.Stderr, "jellysinkd: scan failed: %v\n", batch.Err)
+			return
+		}
+		if len(batch.Changed) == 0 {
+			fmt.Printf("jellysinkd: caught up, report saved to: %s\n", batch.ReportPath)
+		} else {
+			fmt.Printf("jellysinkd: processed %d new or changed paths, report saved to: %s\n", len(batch.Changed), batch.ReportPath)
+		}
+		if batch.Fixed > 0 {
+			fmt.Printf("jellysinkd: fixed the naming of %d new files\n", batch.Fixed)
+		}
+		for _, err := range batch.FixErrors {
+			fmt.Fprintf(os.Stderr, "jellysinkd: fix failed: %v\n", err)
+		}
+	})
+	close(progressCh)
+	<-logDone
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jellysinkd: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// daemonWakeInterval bounds each sleep, so a scan falls due on time after
+// the machine was suspended (timers do not count suspended time)
+const daemonWakeInterval = time.Minute
+
+type waitResult int
+
+const (
+	waitDue waitResult = iota
+	waitStopped
+	waitReload
+	waitRequested
+)
+
+// waitUntil sleeps until next by the wall clock, or until a signal or an
+// API scan request arrives
+func waitUntil(next time.Time, stopCh, reloadCh <-chan os.Signal, scanRequests <-chan struct{}) waitResult {
+	for {
+		remaining := time.Until(next)
+		if remaining <= 0 {
+			return waitDue
+		}
+		if remaining > daemonWakeInterval {
+			remaining = daemonWakeInterval
+		}
+
+		timer := time.NewTimer(remaining)
+		select {
+		case <-stopCh:
+			timer.Stop()
+			return waitStopped
+		case <-reloadCh:
+			timer.Stop()
+			return waitReload
+		case <-scanRequests:
+			timer.Stop()
+			return waitRequested
+		case <-timer.C:
+		}
+	}
+}
+
+// runSelfTest runs the installation self-test and returns the exit code
+func runSelfTest() int {
+	fmt.Printf("jellysinkd %s: running self-test...\n", version)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Printf("[FAIL] config: %v\n", err)
+		return 1
+	}
+
+	results := daemon.RunSelfTest(context.Background(), cfg)
+	daemon.PrintSelfTestResults(os.Stdout, results)
+
+	if daemon.SelfTestFailed(results) {
+		fmt.Println("\nSelf-test FAILED")
+		return 1
+	}
+	fmt.Println("\nSelf-test passed")
+	return 0
+}
+
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	applyConfig(cfg)
+	migrateDataDir()
+	return cfg, nil
+}
+
+// migrateDataDir moves data from ~/.local/share/jellysink the first time
+// the data directory is set elsewhere
+func migrateDataDir() {
+	if m, err := oplog.MigrateDataDir(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else if m != nil {
+		fmt.Fprintln(os.Stderr, m)
+	}
+}
+
+// applyConfig installs the package-level settings derived from cfg
+func applyConfig(cfg *config.Config) {
+	daemon.ApplyConfig(cfg)
+}
+
+func loadReport(path string) (reporter.Report, error) {
+	return reporter.ReadReport(path)
 }
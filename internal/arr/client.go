@@ -0,0 +1,134 @@
+// Package arr talks to the v3 REST API of Sonarr and Radarr
+package arr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Kind selects which of the two applications a client talks to
+type Kind string
+
+const (
+	Sonarr Kind = "Sonarr"
+	Radarr Kind = "Radarr"
+)
+
+// Client is a minimal Sonarr/Radarr API client authenticated with an API key
+type Client struct {
+	Kind       Kind
+	BaseURL    string
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// Item is a series (Sonarr) or movie (Radarr) as returned by the API
+type Item struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Year  int    `json:"year"`
+	Path  string `json:"path"` // the item's folder as the application sees it
+}
+
+// NewClient creates a client for the application at baseURL (e.g. http://localhost:8989)
+func NewClient(kind Kind, baseURL, apiKey string) *Client {
+	return &Client{
+		Kind:       kind,
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// resource is the API resource holding the application's items
+func (c *Client) resource() string {
+	if c.Kind == Radarr {
+		return "/api/v3/movie"
+	}
+	return "/api/v3/series"
+}
+
+// Items returns every series or movie the application manages
+func (c *Client) Items(ctx context.Context) ([]Item, error) {
+	var items []Item
+	if err := c.do(ctx, http.MethodGet, c.resource(), nil, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Relocate points item id at a new folder without moving any files, for
+// folders jellysink renamed on disk
+func (c *Client) Relocate(ctx context.Context, id int, path string) error {
+	// PUT needs the whole resource, so fields this client does not model
+	// are carried over as they are
+	resource := fmt.Sprintf("%s/%d", c.resource(), id)
+	var item map[string]any
+	if err := c.do(ctx, http.MethodGet, resource, nil, &item); err != nil {
+		return err
+	}
+	item["path"] = path
+	return c.do(ctx, http.MethodPut, resource+"?moveFiles=false", item, nil)
+}
+
+// Rescan asks the application to rescan item id's folder from disk
+func (c *Client) Rescan(ctx context.Context, id int) error {
+	command := map[string]any{"name": "RescanSeries", "seriesId": id}
+	if c.Kind == Radarr {
+		command = map[string]any{"name": "RescanMovie", "movieId": id}
+	}
+	return c.do(ctx, http.MethodPost, "/api/v3/command", command, nil)
+}
+
+// do sends an authenticated request with an optional JSON body and decodes
+// a JSON response into out when out is non-nil
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	if c.BaseURL == "" || c.APIKey == "" {
+		return fmt.Errorf("%s url and api_key must be configured", strings.ToLower(string(c.Kind)))
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s request: %w", c.Kind, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Api-Key", c.APIKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", c.Kind, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%s rejected the API key (status %d)", c.Kind, resp.StatusCode)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("%s returned status %d for %s", c.Kind, resp.StatusCode, path)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", c.Kind, err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package arr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestItemsAuthenticates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/api/v3/movie" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`[{"id":7,"title":"Heat","year":1995,"path":"/movies/Heat (1995)","monitored":true}]`))
+	}))
+	defer server.Close()
+
+	items, err := NewClient(Radarr, server.URL+"/", "secret").Items(context.Background())
+	if err != nil {
+		t.Fatalf("Items failed: %v", err)
+	}
+	if len(items) != 1 || items[0] != (Item{ID: 7, Title: "Heat", Year: 1995, Path: "/movies/Heat (1995)"}) {
+		t.Errorf("Items() = %+v", items)
+	}
+
+	_, err = NewClient(Radarr, server.URL, "wrong").Items(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Radarr rejected the API key") {
+		t.Errorf("Expected API key error, got %v", err)
+	}
+}
+
+func TestClientRequiresConfiguration(t *testing.T) {
+	if _, err := NewClient(Sonarr, "", "").Items(context.Background()); err == nil {
+		t.Error("Expected error without url and api_key")
+	}
+}
+
+func TestRelocateKeepsOtherFieldsAndRescan(t *testing.T) {
+	var put map[string]any
+	var command map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/series/3":
+			w.Write([]byte(`{"id":3,"title":"Degrassi","path":"/tv/Degrassi","qualityProfileId":4}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v3/series/3":
+			if r.URL.Query().Get("moveFiles") != "false" {
+				t.Errorf("Expected moveFiles=false, got %s", r.URL)
+			}
+			json.NewDecoder(r.Body).Decode(&put)
+			w.Write([]byte(`{}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/command":
+			json.NewDecoder(r.Body).Decode(&command)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Sonarr, server.URL, "secret")
+	if err := client.Relocate(context.Background(), 3, "/tv/Degrassi (2001)"); err != nil {
+		t.Fatalf("Relocate failed: %v", err)
+	}
+	if put["path"] != "/tv/Degrassi (2001)" || put["qualityProfileId"] != float64(4) {
+		t.Errorf("Expected the series updated in full with the new path, got %v", put)
+	}
+
+	if err := client.Rescan(context.Background(), 3); err != nil {
+		t.Fatalf("Rescan failed: %v", err)
+	}
+	if command["name"] != "RescanSeries" || command["seriesId"] != float64(3) {
+		t.Errorf("Unexpected command %v", command)
+	}
+}
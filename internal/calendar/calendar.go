@@ -0,0 +1,309 @@
+// Package calendar parses the scan schedules of daemon.scan_schedule: cron
+// expressions and the systemd OnCalendar strings the jellysink timer uses
+package calendar
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Spec is a parsed schedule: the minutes, hours, days, months and weekdays
+// a scan may start on. A time matches when every field does
+type Spec struct {
+	text     string
+	minutes  uint64 // bits 0-59
+	hours    uint64 // bits 0-23
+	days     uint64 // bits 1-31
+	months   uint64 // bits 1-12
+	weekdays uint64 // bits 0-6, Sunday first
+}
+
+// field is the range one schedule field accepts
+type field struct {
+	name     string
+	min, max int
+	names    []string // names for min, min+1, ... (weekdays and months)
+	long     []string // full names, for weekdays
+}
+
+var (
+	minuteField  = field{name: "minute", min: 0, max: 59}
+	hourField    = field{name: "hour", min: 0, max: 23}
+	dayField     = field{name: "day of month", min: 1, max: 31}
+	monthField   = field{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	weekdayField = field{name: "weekday", min: 0, max: 6, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"},
+		long: []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}}
+	// cronWeekdayField also takes 7 for Sunday
+	cronWeekdayField = field{name: "weekday", min: 0, max: 7, names: weekdayField.names, long: weekdayField.long}
+)
+
+// weekdayNames are the names OnCalendar uses, Sunday first
+var weekdayNames = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// shorthands are the OnCalendar shorthands systemd expands the same way
+var shorthands = map[string]string{
+	"hourly":  "*-*-* *:00:00",
+	"daily":   "*-*-* 00:00:00",
+	"weekly":  "Mon *-*-* 00:00:00",
+	"monthly": "*-*-01 00:00:00",
+}
+
+// Parse parses a cron expression ("30 3 * * 1,4": minute, hour, day of
+// month, month, weekday) or an OnCalendar string ("Mon,Thu 03:30",
+// "*-*-01 04:00"). Seconds must be zero and years left open, since scans
+// are scheduled to the minute. A cron expression limiting both the day of
+// the month and the weekday is refused: cron runs when either matches,
+// systemd only when both do
+func Parse(text string) (Spec, error) {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return Spec{}, fmt.Errorf("empty schedule")
+	}
+	var spec Spec
+	var err error
+	if fields := strings.Fields(trimmed); len(fields) == 5 && !strings.Contains(trimmed, ":") {
+		spec, err = parseCron(fields)
+	} else {
+		spec, err = parseOnCalendar(trimmed)
+	}
+	if err != nil {
+		return Spec{}, fmt.Errorf("invalid schedule %q: %w", text, err)
+	}
+	spec.text = trimmed
+	return spec, nil
+}
+
+// parseCron parses the five fields of a cron expression
+func parseCron(fields []string) (Spec, error) {
+	var spec Spec
+	var err error
+	if spec.minutes, err = parseSet(fields[0], minuteField, "-"); err != nil {
+		return spec, err
+	}
+	if spec.hours, err = parseSet(fields[1], hourField, "-"); err != nil {
+		return spec, err
+	}
+	if spec.days, err = parseSet(fields[2], dayField, "-"); err != nil {
+		return spec, err
+	}
+	if spec.months, err = parseSet(fields[3], monthField, "-"); err != nil {
+		return spec, err
+	}
+	if spec.weekdays, err = parseSet(fields[4], cronWeekdayField, "-"); err != nil {
+		return spec, err
+	}
+	// Sunday is 0 or 7
+	if spec.weekdays&(1<<7) != 0 {
+		spec.weekdays = spec.weekdays&^(1<<7) | 1
+	}
+	if fields[2] != "*" && fields[4] != "*" {
+		return spec, fmt.Errorf("limit the day of the month or the weekday, not both")
+	}
+	return spec, nil
+}
+
+// parseOnCalendar parses "[weekdays] [date] [time]", each part optional
+// but at least one given; the time defaults to midnight
+func parseOnCalendar(text string) (Spec, error) {
+	if expanded, ok := shorthands[strings.ToLower(text)]; ok {
+		text = expanded
+	}
+	spec := Spec{
+		days:     fullSet(dayField),
+		months:   fullSet(monthField),
+		weekdays: fullSet(weekdayField),
+		hours:    1,
+		minutes:  1,
+	}
+	parts := strings.Fields(text)
+	if len(parts) > 3 {
+		return spec, fmt.Errorf("expected [weekdays] [date] [time]")
+	}
+	var err error
+	if len(parts) > 0 && isLetter(parts[0][0]) {
+		if spec.weekdays, err = parseSet(parts[0], weekdayField, ".."); err != nil {
+			return spec, err
+		}
+		parts = parts[1:]
+	}
+	if len(parts) > 0 && !strings.Contains(parts[0], ":") {
+		date := strings.Split(parts[0], "-")
+		if len(date) == 2 {
+			date = append([]string{"*"}, date...)
+		}
+		if len(date) != 3 {
+			return spec, fmt.Errorf("date %q must be YYYY-MM-DD or MM-DD", parts[0])
+		}
+		if date[0] != "*" {
+			return spec, fmt.Errorf("year must be *")
+		}
+		if spec.months, err = parseSet(date[1], monthField, ".."); err != nil {
+			return spec, err
+		}
+		if spec.days, err = parseSet(date[2], dayField, ".."); err != nil {
+			return spec, err
+		}
+		parts = parts[1:]
+	}
+	if len(parts) > 0 {
+		clock := strings.Split(parts[0], ":")
+		if len(clock) < 2 || len(clock) > 3 {
+			return spec, fmt.Errorf("time %q must be HH:MM or HH:MM:SS", parts[0])
+		}
+		if len(clock) == 3 && strings.TrimLeft(clock[2], "0") != "" {
+			return spec, fmt.Errorf("seconds must be 00")
+		}
+		if spec.hours, err = parseSet(clock[0], hourField, ".."); err != nil {
+			return spec, err
+		}
+		if spec.minutes, err = parseSet(clock[1], minuteField, ".."); err != nil {
+			return spec, err
+		}
+		parts = parts[1:]
+	}
+	if len(parts) > 0 {
+		return spec, fmt.Errorf("unexpected %q", parts[0])
+	}
+	return spec, nil
+}
+
+// parseSet parses a comma-separated list of values, ranges (joined by
+// rangeSep) and steps ("*/15", "1-5/2") into a bit set
+func parseSet(text string, f field, rangeSep string) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(text, ",") {
+		step := 1
+		if before, after, ok := strings.Cut(item, "/"); ok {
+			n, err := strconv.Atoi(after)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid %s step %q", f.name, after)
+			}
+			item, step = before, n
+		}
+		lo, hi := f.min, f.max
+		if item != "*" {
+			first, last, isRange := strings.Cut(item, rangeSep)
+			var err error
+			if lo, err = f.value(first); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = f.value(last); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// "5/15" runs from 5 to the end of the range
+				hi = f.max
+			}
+			if hi < lo {
+				// Weekday ranges wrap round the week ("Sat..Sun")
+				if f.long == nil {
+					return 0, fmt.Errorf("invalid %s range %q", f.name, item)
+				}
+				hi += f.max - f.min + 1
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << (f.min + (v-f.min)%(f.max-f.min+1))
+		}
+	}
+	return set, nil
+}
+
+// value parses one number or name of the field
+func (f field) value(text string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(text, name) || (i < len(f.long) && strings.EqualFold(text, f.long[i])) {
+			return f.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("invalid %s %q (must be %d-%d)", f.name, text, f.min, f.max)
+	}
+	return n, nil
+}
+
+// fullSet returns the set of every value of f
+func fullSet(f field) uint64 {
+	var set uint64
+	for v := f.min; v <= f.max; v++ {
+		set |= 1 << v
+	}
+	return set
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// maxSearchDays bounds Next; the rarest schedule, February 29 on a given
+// weekday, comes round within 28 years
+const maxSearchDays = 366 * 28
+
+// Next returns the first start time strictly after after, in after's
+// location, or the zero time when the schedule never matches
+func (s Spec) Next(after time.Time) time.Time {
+	year, month, day := after.Date()
+	for i := 0; i <= maxSearchDays; i++ {
+		date := time.Date(year, month, day+i, 0, 0, 0, 0, after.Location())
+		if s.days&(1<<date.Day()) == 0 || s.months&(1<<int(date.Month())) == 0 || s.weekdays&(1<<int(date.Weekday())) == 0 {
+			continue
+		}
+		for hour := 0; hour < 24; hour++ {
+			if s.hours&(1<<hour) == 0 {
+				continue
+			}
+			for minute := 0; minute < 60; minute++ {
+				if s.minutes&(1<<minute) == 0 {
+					continue
+				}
+				candidate := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, after.Location())
+				if candidate.After(after) {
+					return candidate
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// OnCalendar renders the schedule for a systemd timer's OnCalendar=
+func (s Spec) OnCalendar() string {
+	var sb strings.Builder
+	if s.weekdays != fullSet(weekdayField) {
+		var names []string
+		for v := 0; v <= 6; v++ {
+			if s.weekdays&(1<<v) != 0 {
+				names = append(names, weekdayNames[v])
+			}
+		}
+		sb.WriteString(strings.Join(names, ",") + " ")
+	}
+	sb.WriteString("*-" + formatSet(s.months, monthField) + "-" + formatSet(s.days, dayField) + " ")
+	sb.WriteString(formatSet(s.hours, hourField) + ":" + formatSet(s.minutes, minuteField) + ":00")
+	return sb.String()
+}
+
+// formatSet lists the values of set, or "*" when it holds them all
+func formatSet(set uint64, f field) string {
+	if set == fullSet(f) {
+		return "*"
+	}
+	values := make([]string, 0, bits.OnesCount64(set))
+	for v := f.min; v <= f.max; v++ {
+		if set&(1<<v) != 0 {
+			values = append(values, fmt.Sprintf("%02d", v))
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+// String returns the schedule as it was written
+func (s Spec) String() string {
+	return s.text
+}
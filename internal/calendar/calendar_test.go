@@ -0,0 +1,89 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseNext(t *testing.T) {
+	// Wednesday 2026-10-14 12:00
+	after := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		schedule   string
+		next       time.Time
+		onCalendar string
+	}{
+		{"Mon,Thu 03:30", at(10, 15, 3, 30), "Mon,Thu *-*-* 03:30:00"},
+		{"30 3 * * 1,4", at(10, 15, 3, 30), "Mon,Thu *-*-* 03:30:00"},
+		{"Sat..Sun *-*-* 02:00:00", at(10, 17, 2, 0), "Sun,Sat *-*-* 02:00:00"},
+		{"0 2 * * 0", at(10, 18, 2, 0), "Sun *-*-* 02:00:00"},
+		{"0 2 * * 7", at(10, 18, 2, 0), "Sun *-*-* 02:00:00"},
+		{"*-*-01 04:00", at(11, 1, 4, 0), "*-*-01 04:00:00"},
+		{"0 4 1 * *", at(11, 1, 4, 0), "*-*-01 04:00:00"},
+		{"*/15 * * * *", at(10, 14, 12, 15), "*-*-* *:00,15,30,45:00"},
+		{"daily", at(10, 15, 0, 0), "*-*-* 00:00:00"},
+		{"Friday", at(10, 16, 0, 0), "Fri *-*-* 00:00:00"},
+		{"12-25 06:00", at(12, 25, 6, 0), "*-12-25 06:00:00"},
+		{"0 12 * jan-mar 1-5", time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC), "Mon,Tue,Wed,Thu,Fri *-01,02,03-* 12:00:00"},
+	}
+	for _, tt := range tests {
+		spec, err := Parse(tt.schedule)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.schedule, err)
+			continue
+		}
+		if got := spec.Next(after); !got.Equal(tt.next) {
+			t.Errorf("%q: Next() = %s, want %s", tt.schedule, got, tt.next)
+		}
+		if got := spec.OnCalendar(); got != tt.onCalendar {
+			t.Errorf("%q: OnCalendar() = %q, want %q", tt.schedule, got, tt.onCalendar)
+		}
+		// The rendered timer schedules the same times
+		rendered, err := Parse(spec.OnCalendar())
+		if err != nil || !rendered.Next(after).Equal(tt.next) {
+			t.Errorf("%q: rendered %q does not round-trip: %v", tt.schedule, spec.OnCalendar(), err)
+		}
+	}
+
+	// A leap day is found years ahead
+	leap, _ := Parse("*-02-29 00:00")
+	if got := leap.Next(after); !got.Equal(time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the next leap day, got %s", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, schedule := range []string{
+		"",
+		"Mon 25:00",
+		"Mon 03:30:15",
+		"2027-*-* 03:00",
+		"Funday 03:00",
+		"60 * * * *",
+		"0 3 1 * 1",
+		"0 3 * * 1-8",
+		"*/0 * * * *",
+		"Mon *-*-* 03:00 extra",
+	} {
+		if _, err := Parse(schedule); err == nil {
+			t.Errorf("Expected Parse(%q) to fail", schedule)
+		}
+	}
+
+	// A schedule that never matches says so through a zero Next
+	never, err := Parse("*-02-30 00:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !never.Next(time.Now()).IsZero() {
+		t.Error("Expected February 30 never to come")
+	}
+	if _, err := Parse("0 3 1 * 1"); err == nil || !strings.Contains(err.Error(), "not both") {
+		t.Errorf("Expected the day of month and weekday conflict explained, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package cleaner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// removeArtifacts moves leftover junk files and folders to the trash.
+// Each one is checked again first, so a folder that gained a video or a
+// download that resumed since the scan is kept.
+// Returns the number of operations processed
+func removeArtifacts(artifacts []scanner.Artifact, config Config, journal *Journal, result *CleanResult, pr *scanner.ProgressReporter) int {
+	for _, artifact := range artifacts {
+		op := Operation{
+			Type:      "delete",
+			Source:    artifact.Path,
+			Size:      artifact.Size,
+			Timestamp: time.Now(),
+		}
+		if artifact.Kind == scanner.ArtifactEmptyFolder || artifact.Kind == scanner.ArtifactLeftoverFolder {
+			op.Type = "delete-folder"
+		}
+		if skipIfProtected(op, config, result, pr) {
+			continue
+		}
+		if err := tagRefusal(artifact.Path, config, true); err != nil {
+			result.Errors = append(result.Errors, err)
+			if pr != nil {
+				pr.LogError(err, err.Error())
+			}
+			continue
+		}
+
+		err := scanner.CheckArtifact(artifact)
+		if err == nil && !config.DryRun {
+			err = moveToTrash(artifact.Path, op.Type, config, journal)
+		}
+		if err != nil {
+			err = fmt.Errorf("cannot delete %s: %w", artifact.Path, err)
+			result.Errors = append(result.Errors, err)
+			op.Error = err.Error()
+			if pr != nil {
+				pr.LogError(err, err.Error())
+			}
+		} else {
+			op.Completed = true
+			if !config.DryRun {
+				result.ArtifactsRemoved++
+				result.SpaceFreed += artifact.Size
+			}
+			if pr != nil {
+				verb := "Deleted"
+				if config.DryRun {
+					verb = "Would delete"
+				}
+				pr.Send(scanner.SeverityInfo, fmt.Sprintf("%s %s: %s", verb, artifact.Kind, artifact.Path))
+			}
+		}
+		result.Operations = append(result.Operations, op)
+	}
+	return len(artifacts)
+}
@@ -8,7 +8,9 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/Nomadcxx/jellysink/internal/oplog"
 	"github.com/Nomadcxx/jellysink/internal/scanner"
+	"github.com/Nomadcxx/jellysink/internal/tags"
 )
 
 const (
@@ -20,28 +22,51 @@ const (
 // CleanResult represents the result of a cleaning operation
 type CleanResult struct {
 	DuplicatesDeleted int
+	DuplicatesLinked  int // duplicates replaced with hardlinks to their keeper
 	ComplianceFixed   int
+	VersionsKept      int // copies renamed into Jellyfin's multi-version layout
+	FoldersRemoved    int // orphaned show/season folders deleted
+	ArtifactsRemoved  int // leftover junk files and folders deleted
+	CompanionsMerged  int // subtitles and artwork of deleted duplicates moved to the keeper
 	SpaceFreed        int64
 	Errors            []error
 	Operations        []Operation // For rollback capability
+	Deferred          []string    // paths skipped because they were in use
+	Importing         []string    // paths skipped while an import wrote into their folder
+	Protected         []string    // paths skipped because they are under a protected path
+	LibraryRefreshed  bool        // a media server library scan was requested
+	JournalID         string      // undo journal of a real clean; "" when nothing changed
+	RefreshErr        error       // why the library scan request failed
+	CloudWarnings     []string    // moves on cloud mounts a remote may carry out by uploading again
 	DryRun            bool
 }
 
 // Operation represents a single filesystem operation
 type Operation struct {
-	Type        string // "delete", "rename", "move"
+	Type        string // "delete", "link", "rename", "move", "version", "delete-folder"; journals also record "merge" for sidecars moved to a keeper
 	Source      string // Original path
 	Destination string // New path (for rename/move)
+	Size        int64  // bytes deleted; 0 for renames and moves
 	Timestamp   time.Time
 	Completed   bool
+	Status      string // DeferredInUse or DeferredImporting when postponed, SkippedProtected when refused
+	Error       string // why the operation failed
 }
 
 // Config holds cleaner configuration
 type Config struct {
-	DryRun         bool
-	MaxSizeGB      int64 // Maximum total size to delete in one operation
-	ProtectedPaths []string
-	LogPath        string // Path to operation log for rollback
+	DryRun             bool
+	MaxSizeGB          int64                  // Maximum total size to delete in one operation
+	ProtectedPaths     []string               // never deleted, renamed or moved; a folder holding one is protected too
+	LogPath            string                 // JSON-lines log of every operation of a real clean
+	JournalDir         string                 // undo journals of real cleans
+	TrashDir           string                 // deleted files are moved here so a clean can be undone
+	HardlinkDuplicates bool                   // duplicates are replaced with hardlinks to their keeper instead of deleted
+	InUse              InUseChecker           // files in use are deferred; nil skips the check
+	Tags               *tags.Rules            // keep-tagged files are never deleted, protected-tagged paths never touched
+	Refresh            LibraryRefresher       // asked to rescan after a clean changes files; nil skips it
+	OrphanFolders      []scanner.OrphanFolder // video-less show/season folders to delete as well; nil skips them
+	Artifacts          []scanner.Artifact     // leftover junk files and folders to delete as well; nil skips them
 }
 
 // DefaultConfig returns safe default configuration
@@ -50,7 +75,7 @@ func DefaultConfig() Config {
 	return Config{
 		DryRun:    false,
 		MaxSizeGB: DefaultMaxSizeGB,
-		ProtectedPaths: []string{
+		ProtectedPaths: append([]string{
 			// System directories
 			"/usr", "/etc", "/bin", "/sbin", "/boot",
 			"/sys", "/proc", "/dev", "/run",
@@ -60,8 +85,14 @@ func DefaultConfig() Config {
 			"/root",
 			// Windows system paths (for cross-platform safety)
 			"C:\\Windows", "C:\\Program Files", "C:\\Program Files (x86)",
-		},
-		LogPath: filepath.Join(home, ".local/share/jellysink/operations.log"),
+		}, configuredProtectedPaths(home)...),
+		LogPath:            oplog.DefaultPath(oplog.OperationsLog),
+		JournalDir:         filepath.Join(oplog.DataDir(), "journal"),
+		TrashDir:           resolveTrashDir(home),
+		HardlinkDuplicates: hardlinkDuplicatesEnabled(),
+		InUse:              getInUseChecker(),
+		Tags:               tags.CurrentRules(),
+		Refresh:            getLibraryRefresher(),
 	}
 }
 
@@ -85,9 +116,12 @@ func CleanWithProgress(duplicates []scanner.MovieDuplicate, tvDuplicates []scann
 	// Create progress reporter
 	var pr *scanner.ProgressReporter
 	if progressCh != nil {
-		pr = scanner.NewProgressReporterWithInterval(progressCh, "cleaning", 200*time.Millisecond)
+		pr = scanner.NewProgressReporterWithInterval(progressCh, scanner.OpCleaning, 200*time.Millisecond)
 	}
 
+	// Groups excluded in the TUI keep every file
+	duplicates, tvDuplicates = includedDuplicates(duplicates, tvDuplicates)
+
 	// Calculate total operations (deletes + compliance fixes)
 	totalOps := 0
 	for _, dup := range duplicates {
@@ -101,6 +135,8 @@ func CleanWithProgress(duplicates []scanner.MovieDuplicate, tvDuplicates []scann
 		}
 	}
 	totalOps += len(compliance)
+	totalOps += len(config.OrphanFolders)
+	totalOps += len(config.Artifacts)
 
 	if pr != nil {
 		pr.Start(totalOps, fmt.Sprintf("Preparing cleanup (%d operations)", totalOps))
@@ -113,17 +149,46 @@ func CleanWithProgress(duplicates []scanner.MovieDuplicate, tvDuplicates []scann
 			totalSize/(1024*1024*1024), config.MaxSizeGB)
 	}
 
+	// Moves between folders on cloud mounts may be uploaded again
+	result.CloudWarnings = CloudWarnings(duplicates, tvDuplicates, compliance, config)
+	if pr != nil {
+		for _, warning := range result.CloudWarnings {
+			pr.Send(scanner.SeverityWarn, warning)
+		}
+	}
+
+	// Real cleans journal every change; deletes go to the trash so they can be undone
+	var journal *Journal
+	if !config.DryRun {
+		journal = newJournal(config.JournalDir)
+	}
+
 	processed := 0
 
 	// Process duplicate deletions
 	for _, dup := range duplicates {
+		if dup.KeepsAllVersions() {
+			processed += mergeVersions(dup, config, journal, &result, pr)
+			continue
+		}
+
 		// Skip first file (keeper)
+		freed := dup.ReclaimableSizes()
 		for i := 1; i < len(dup.Files); i++ {
 			file := dup.Files[i]
 
-			// Safety check
-			if isProtectedPath(file.Path, config.ProtectedPaths) {
-				err := fmt.Errorf("refusing to delete protected path: %s", file.Path)
+			// Safety checks
+			op := Operation{
+				Type:      "delete",
+				Source:    file.Path,
+				Size:      file.Size,
+				Timestamp: time.Now(),
+			}
+			if skipIfProtected(op, config, &result, pr) {
+				processed++
+				continue
+			}
+			if err := tagRefusal(file.Path, config, true); err != nil {
 				result.Errors = append(result.Errors, err)
 				if pr != nil {
 					pr.LogError(err, err.Error())
@@ -131,28 +196,41 @@ func CleanWithProgress(duplicates []scanner.MovieDuplicate, tvDuplicates []scann
 				continue
 			}
 
-			op := Operation{
-				Type:      "delete",
-				Source:    file.Path,
-				Timestamp: time.Now(),
+			if deferIfImporting(op, &result, pr) || deferIfInUse(op, config, &result, pr) {
+				processed++
+				continue
+			}
+
+			if config.HardlinkDuplicates {
+				if file.Inode == "" || file.Inode != dup.Files[0].Inode {
+					linkDuplicate(op, dup.Files[0].Path, freed[i], config, journal, &result, pr, processed+1)
+				}
+				processed++
+				continue
+			}
+
+			var companions []scanner.CompanionMove
+			if dup.MergesCompanions() {
+				companions = scanner.CompanionMerges(file.Path, dup.Files[0].Path)
 			}
 
 			if !config.DryRun {
-				if err := os.Remove(file.Path); err != nil {
+				if err := moveToTrash(file.Path, "delete", config, journal); err != nil {
 					result.Errors = append(result.Errors,
 						fmt.Errorf("failed to delete %s: %w", file.Path, err))
 					op.Completed = false
+					op.Error = err.Error()
 					if pr != nil {
 						pr.LogError(err, fmt.Sprintf("Failed to delete: %s", file.Path))
 					}
 				} else {
 					op.Completed = true
 					result.DuplicatesDeleted++
-					result.SpaceFreed += file.Size
+					result.SpaceFreed += freed[i]
 					if pr != nil {
 						pr.Update(processed+1, fmt.Sprintf("Deleted: %s", file.Path))
 					}
-
+					mergeCompanions(companions, config, journal, &result, pr)
 				}
 			} else {
 				// Dry run: check permissions and accessibility without deleting
@@ -168,6 +246,7 @@ func CleanWithProgress(duplicates []scanner.MovieDuplicate, tvDuplicates []scann
 					if pr != nil {
 						pr.Update(processed+1, fmt.Sprintf("Would delete: %s", file.Path))
 					}
+					mergeCompanions(companions, config, journal, &result, pr)
 				}
 			}
 
@@ -181,11 +260,21 @@ func CleanWithProgress(duplicates []scanner.MovieDuplicate, tvDuplicates []scann
 
 	// Process TV duplicates
 	for _, dup := range tvDuplicates {
+		freed := dup.ReclaimableSizes()
 		for i := 1; i < len(dup.Files); i++ {
 			file := dup.Files[i]
 
-			if isProtectedPath(file.Path, config.ProtectedPaths) {
-				err := fmt.Errorf("refusing to delete protected path: %s", file.Path)
+			op := Operation{
+				Type:      "delete",
+				Source:    file.Path,
+				Size:      file.Size,
+				Timestamp: time.Now(),
+			}
+			if skipIfProtected(op, config, &result, pr) {
+				processed++
+				continue
+			}
+			if err := tagRefusal(file.Path, config, true); err != nil {
 				result.Errors = append(result.Errors, err)
 				if pr != nil {
 					pr.LogError(err, err.Error())
@@ -193,28 +282,41 @@ func CleanWithProgress(duplicates []scanner.MovieDuplicate, tvDuplicates []scann
 				continue
 			}
 
-			op := Operation{
-				Type:      "delete",
-				Source:    file.Path,
-				Timestamp: time.Now(),
+			if deferIfImporting(op, &result, pr) || deferIfInUse(op, config, &result, pr) {
+				processed++
+				continue
+			}
+
+			if config.HardlinkDuplicates {
+				if file.Inode == "" || file.Inode != dup.Files[0].Inode {
+					linkDuplicate(op, dup.Files[0].Path, freed[i], config, journal, &result, pr, processed+1)
+				}
+				processed++
+				continue
+			}
+
+			var companions []scanner.CompanionMove
+			if dup.MergesCompanions() {
+				companions = scanner.CompanionMerges(file.Path, dup.Files[0].Path)
 			}
 
 			if !config.DryRun {
-				if err := os.Remove(file.Path); err != nil {
+				if err := moveToTrash(file.Path, "delete", config, journal); err != nil {
 					result.Errors = append(result.Errors,
 						fmt.Errorf("failed to delete %s: %w", file.Path, err))
 					op.Completed = false
+					op.Error = err.Error()
 					if pr != nil {
 						pr.LogError(err, fmt.Sprintf("Failed to delete: %s", file.Path))
 					}
 				} else {
 					op.Completed = true
 					result.DuplicatesDeleted++
-					result.SpaceFreed += file.Size
+					result.SpaceFreed += freed[i]
 					if pr != nil {
 						pr.Update(processed+1, fmt.Sprintf("Deleted: %s", file.Path))
 					}
-
+					mergeCompanions(companions, config, journal, &result, pr)
 				}
 			} else {
 				// Dry run: check permissions and accessibility without deleting
@@ -230,6 +332,7 @@ func CleanWithProgress(duplicates []scanner.MovieDuplicate, tvDuplicates []scann
 					if pr != nil {
 						pr.Update(processed+1, fmt.Sprintf("Would delete: %s", file.Path))
 					}
+					mergeCompanions(companions, config, journal, &result, pr)
 				}
 			}
 
@@ -241,8 +344,13 @@ func CleanWithProgress(duplicates []scanner.MovieDuplicate, tvDuplicates []scann
 		}
 	}
 
-	// Process compliance fixes using scanner's Apply functions
+	// Process compliance fixes using scanner's Apply functions; renames on
+	// cloud mounts are applied in batches. Fixes below a show folder renamed
+	// earlier in the clean follow it there
+	var batcher cloudBatcher
+	var renamed scanner.PathRebaser
 	for i, issue := range compliance {
+		issue.Path, issue.SuggestedPath = renamed.Rebase(issue.Path), renamed.Rebase(issue.SuggestedPath)
 		// Skip manual review items (collisions, sample files, etc.)
 		if issue.SuggestedAction == "manual_review" {
 			err := fmt.Errorf("skipped (needs manual review): %s - %s", issue.Path, issue.Problem)
@@ -253,8 +361,26 @@ func CleanWithProgress(duplicates []scanner.MovieDuplicate, tvDuplicates []scann
 			continue
 		}
 
-		if isProtectedPath(issue.Path, config.ProtectedPaths) {
-			err := fmt.Errorf("refusing to modify protected path: %s", issue.Path)
+		pending := Operation{
+			Type:        issue.SuggestedAction,
+			Source:      issue.Path,
+			Destination: issue.SuggestedPath,
+			Timestamp:   time.Now(),
+		}
+		if skipIfProtected(pending, config, &result, pr) {
+			processed++
+			continue
+		}
+		if err := tagRefusal(issue.Path, config, false); err != nil {
+			result.Errors = append(result.Errors, err)
+			if pr != nil {
+				pr.LogError(err, err.Error())
+			}
+			continue
+		}
+		// Nothing is moved into a frozen folder either
+		if reason := frozenReason(issue.SuggestedPath, config); reason != "" {
+			err := fmt.Errorf("refusing to move %s into %s: %s", issue.Path, issue.SuggestedPath, reason)
 			result.Errors = append(result.Errors, err)
 			if pr != nil {
 				pr.LogError(err, err.Error())
@@ -265,7 +391,17 @@ func CleanWithProgress(duplicates []scanner.MovieDuplicate, tvDuplicates []scann
 		var op Operation
 		var err error
 
+		if deferIfImporting(pending, &result, pr) || deferIfInUse(pending, config, &result, pr) {
+			processed++
+			continue
+		}
+
 		// Use scanner's Apply functions which handle folder detection
+		// A target that already exists is the same file hardlinked, and the
+		// apply only unlinks the source
+		_, statErr := os.Stat(issue.SuggestedPath)
+		unlinked := statErr == nil
+		companions := scanner.CompanionMoves(issue.Path, issue.SuggestedPath)
 		if !config.DryRun {
 			// Progress indicator
 			if pr != nil && len(compliance) > 5 && i%5 == 0 {
@@ -306,6 +442,7 @@ func CleanWithProgress(duplicates []scanner.MovieDuplicate, tvDuplicates []scann
 		if err != nil {
 			result.Errors = append(result.Errors, err)
 			op.Completed = false
+			op.Error = err.Error()
 			if pr != nil {
 				pr.LogError(err, fmt.Sprintf("Failed to apply compliance: %s", issue.Path))
 			}
@@ -313,6 +450,18 @@ func CleanWithProgress(duplicates []scanner.MovieDuplicate, tvDuplicates []scann
 			op.Completed = true
 			if !config.DryRun {
 				result.ComplianceFixed++
+				if issue.SuggestedAction == "rename" {
+					batcher.renamed(issue.Path)
+				}
+				if scanner.IsShowFolderRename(issue) {
+					renamed.Add(issue.Path, issue.SuggestedPath)
+				}
+				if unlinked {
+					journal.record("unlink", issue.Path, issue.SuggestedPath)
+				} else {
+					journal.record(op.Type, issue.Path, issue.SuggestedPath)
+				}
+				journal.recordCompanions(op.Type, companions)
 			}
 			if pr != nil && !config.DryRun {
 				pr.Update(processed+1, fmt.Sprintf("Fixed compliance: %s", issue.Path))
@@ -326,13 +475,58 @@ func CleanWithProgress(duplicates []scanner.MovieDuplicate, tvDuplicates []scann
 		}
 	}
 
+	// Remove orphaned folders and artifacts last, once their episodes are settled
+	processed += removeOrphanFolders(config.OrphanFolders, config, journal, &result, pr)
+	if pr != nil && len(config.OrphanFolders) > 0 {
+		pr.Update(processed, fmt.Sprintf("Processed %d/%d", processed, totalOps))
+	}
+	processed += removeArtifacts(config.Artifacts, config, journal, &result, pr)
+	if pr != nil && len(config.Artifacts) > 0 {
+		pr.Update(processed, fmt.Sprintf("Processed %d/%d", processed, totalOps))
+	}
+
+	if journal != nil && len(journal.Entries) > 0 {
+		result.JournalID = journal.ID
+		if journal.saveErr != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("failed to write undo journal: %w", journal.saveErr))
+		}
+	}
+
+	notifyRenames(config, &result, pr)
+	refreshLibrary(config, &result, pr)
+
 	// Final progress message
 	if !config.DryRun && len(compliance) > 0 {
 		fmt.Printf("Fixed %d compliance issues\n", result.ComplianceFixed)
 	}
 
 	if pr != nil {
-		pr.Complete(fmt.Sprintf("Finished cleanup: %d deleted, %d fixed", result.DuplicatesDeleted, result.ComplianceFixed))
+		msg := fmt.Sprintf("Finished cleanup: %d deleted, %d fixed", result.DuplicatesDeleted, result.ComplianceFixed)
+		if result.DuplicatesLinked > 0 {
+			msg += fmt.Sprintf(", %d replaced with hardlinks", result.DuplicatesLinked)
+		}
+		if result.VersionsKept > 0 {
+			msg += fmt.Sprintf(", %d kept as versions", result.VersionsKept)
+		}
+		if result.FoldersRemoved > 0 {
+			msg += fmt.Sprintf(", %d orphaned folders removed", result.FoldersRemoved)
+		}
+		if result.ArtifactsRemoved > 0 {
+			msg += fmt.Sprintf(", %d artifacts removed", result.ArtifactsRemoved)
+		}
+		if result.CompanionsMerged > 0 {
+			msg += fmt.Sprintf(", %d subtitles and artwork moved to keepers", result.CompanionsMerged)
+		}
+		if len(result.Deferred) > 0 {
+			msg += fmt.Sprintf(", %d deferred (in use)", len(result.Deferred))
+		}
+		if len(result.Importing) > 0 {
+			msg += fmt.Sprintf(", %d deferred (import in progress)", len(result.Importing))
+		}
+		if len(result.Protected) > 0 {
+			msg += fmt.Sprintf(", %d skipped (protected)", len(result.Protected))
+		}
+		pr.Complete(msg)
 	}
 
 	// Write operation log (for potential rollback)
@@ -523,11 +717,31 @@ func cleanupEmptyDirs(dir string) {
 	}
 }
 
+// includedDuplicates drops the duplicate groups excluded from cleaning
+func includedDuplicates(movies []scanner.MovieDuplicate, tv []scanner.TVDuplicate) ([]scanner.MovieDuplicate, []scanner.TVDuplicate) {
+	var includedMovies []scanner.MovieDuplicate
+	for _, dup := range movies {
+		if !dup.Excluded {
+			includedMovies = append(includedMovies, dup)
+		}
+	}
+	var includedTV []scanner.TVDuplicate
+	for _, dup := range tv {
+		if !dup.Excluded {
+			includedTV = append(includedTV, dup)
+		}
+	}
+	return includedMovies, includedTV
+}
+
 // calculateTotalSize calculates total bytes to be deleted
 func calculateTotalSize(movies []scanner.MovieDuplicate, tv []scanner.TVDuplicate) int64 {
 	var total int64
 
 	for _, dup := range movies {
+		if dup.KeepsAllVersions() {
+			continue
+		}
 		for i := 1; i < len(dup.Files); i++ {
 			total += dup.Files[i].Size
 		}
@@ -542,14 +756,40 @@ func calculateTotalSize(movies []scanner.MovieDuplicate, tv []scanner.TVDuplicat
 	return total
 }
 
-// isProtectedPath checks if path is in protected list
-func isProtectedPath(path string, protected []string) bool {
-	for _, p := range protected {
-		if strings.HasPrefix(path, p) {
-			return true
+// tagRefusal returns why the tag rules forbid touching path, or nil
+// Keep tags only block deletes; protected tags, frozen folders and
+// exclusions (scan.exclude, .jellysinkignore) block every change
+func tagRefusal(path string, config Config, deleting bool) error {
+	if reason := frozenReason(path, config); reason != "" {
+		return fmt.Errorf("refusing to modify %s: %s", path, reason)
+	}
+	if by := scanner.ExcludedBy(path, false); by != "" {
+		return fmt.Errorf("refusing to modify %s: excluded by %s", path, by)
+	}
+	if tag := config.Tags.ProtectingTag(path); tag != "" {
+		return fmt.Errorf("refusing to modify %s: tagged %q", path, tag)
+	}
+	if deleting {
+		if tag := config.Tags.KeepTag(path); tag != "" {
+			return fmt.Errorf("refusing to delete %s: tagged %q", path, tag)
 		}
 	}
-	return false
+	return nil
+}
+
+// frozenReason returns why path is frozen, by a marker file or the freeze
+// tag, or ""
+func frozenReason(path string, config Config) string {
+	if path == "" {
+		return ""
+	}
+	if marker := scanner.FreezeMarkerFor(path); marker != "" {
+		return "frozen by " + marker
+	}
+	if config.Tags.Frozen(path) {
+		return fmt.Sprintf("tagged %q", tags.FreezeTag)
+	}
+	return ""
 }
 
 // checkFileAccessible checks if a file can be deleted without actually deleting it
@@ -578,7 +818,11 @@ func checkFileAccessible(path string) error {
 		return fmt.Errorf("parent directory not writable (permissions: %o)", parentInfo.Mode().Perm())
 	}
 
-	// Try to open file for reading to verify access
+	// Try to open file for reading to verify access; on a cloud mount that
+	// could start a download
+	if onCloudMount(path) {
+		return nil
+	}
 	f, err := os.Open(path)
 	if err != nil {
 		return fmt.Errorf("cannot open file: %w", err)
@@ -638,8 +882,9 @@ func checkRenameAccessible(oldPath, newPath string) error {
 		}
 	}
 
-	// If it's a file, try opening it to verify access
-	if !info.IsDir() {
+	// If it's a file, try opening it to verify access (not on a cloud mount,
+	// where that could start a download)
+	if !info.IsDir() && !onCloudMount(oldPath) {
 		f, err := os.Open(oldPath)
 		if err != nil {
 			return fmt.Errorf("cannot open source file: %w", err)
@@ -650,38 +895,31 @@ func checkRenameAccessible(oldPath, newPath string) error {
 	return nil
 }
 
-// writeOperationLog writes operations to log file for rollback capability
+// writeOperationLog appends every operation of a real clean to the
+// operation log, failed, deferred and skipped ones included
 func writeOperationLog(ops []Operation, logPath string) error {
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
-		return err
-	}
-
-	// Open log file (append mode) with user-only permissions for security
-	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	// Write operations
+	entries := make([]oplog.Entry, 0, len(ops))
 	for _, op := range ops {
-		if !op.Completed {
-			continue
+		entry := oplog.Entry{
+			Timestamp: op.Timestamp,
+			Op:        op.Type,
+			OldPath:   op.Source,
+			NewPath:   op.Destination,
+			Size:      op.Size,
+			Result:    oplog.ResultOK,
 		}
-
-		line := fmt.Sprintf("%s|%s|%s|%s\n",
-			op.Timestamp.Format(time.RFC3339),
-			op.Type,
-			op.Source,
-			op.Destination)
-
-		if _, err := f.WriteString(line); err != nil {
-			return err
+		switch {
+		case op.Status == DeferredInUse, op.Status == DeferredImporting:
+			entry.Result = oplog.ResultDeferred
+		case op.Status == SkippedProtected:
+			entry.Result = oplog.ResultSkipped
+		case !op.Completed:
+			entry.Result = oplog.ResultFailed
+			entry.Error = op.Error
 		}
+		entries = append(entries, entry)
 	}
-
-	return nil
+	return oplog.Append(logPath, entries...)
 }
 
 // CleanDuplicatesOnly performs only duplicate deletion (no compliance fixes)
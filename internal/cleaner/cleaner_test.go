@@ -1,11 +1,15 @@
 package cleaner
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/Nomadcxx/jellysink/internal/oplog"
 	"github.com/Nomadcxx/jellysink/internal/scanner"
+	"github.com/Nomadcxx/jellysink/internal/tags"
 )
 
 func TestIsProtectedPath(t *testing.T) {
@@ -20,6 +24,11 @@ func TestIsProtectedPath(t *testing.T) {
 		{"/home/user/file", true},
 		{"/mnt/storage/file", false},
 		{"/tmp/file", false},
+		{"/usr", true},
+		{"/usrlocal/file", false},
+		{"/homework/file", false},
+		// A folder holding a protected path goes with it
+		{"/", true},
 	}
 
 	for _, tt := range tests {
@@ -151,6 +160,42 @@ func TestCleanDuplicates(t *testing.T) {
 	}
 }
 
+func TestCleanSkipsExcludedGroups(t *testing.T) {
+	tmpDir := t.TempDir()
+	keepFile := filepath.Join(tmpDir, "keep.mkv")
+	excludedFile := filepath.Join(tmpDir, "excluded.mkv")
+	os.WriteFile(keepFile, []byte("keeper"), 0644)
+	os.WriteFile(excludedFile, []byte("kept as well"), 0644)
+
+	duplicates := []scanner.MovieDuplicate{{
+		Excluded: true,
+		Files: []scanner.MovieFile{
+			{Path: keepFile, Size: 100},
+			{Path: excludedFile, Size: 50},
+		},
+	}}
+	tvDuplicates := []scanner.TVDuplicate{{
+		Excluded: true,
+		Files: []scanner.TVFile{
+			{Path: keepFile, Size: 100},
+			{Path: excludedFile, Size: 50},
+		},
+	}}
+
+	config := DefaultConfig()
+	config.DryRun = false
+	result, err := Clean(duplicates, tvDuplicates, []scanner.ComplianceIssue{}, config)
+	if err != nil {
+		t.Fatalf("Clean() error: %v", err)
+	}
+	if _, err := os.Stat(excludedFile); err != nil {
+		t.Errorf("Expected the excluded group's files kept: %v", err)
+	}
+	if result.DuplicatesDeleted != 0 || len(result.Operations) != 0 {
+		t.Errorf("Expected nothing done to excluded groups, got %d deleted, %d operations", result.DuplicatesDeleted, len(result.Operations))
+	}
+}
+
 func TestPerformRename(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -306,9 +351,546 @@ func TestCleanProtectedPath(t *testing.T) {
 		t.Error("Protected file was deleted")
 	}
 
-	// Check that error was recorded
-	if len(result.Errors) == 0 {
-		t.Error("Expected error for protected path, got none")
+	// The refusal is a skipped operation, not an error
+	if len(result.Errors) != 0 {
+		t.Errorf("Expected no errors, got %v", result.Errors)
+	}
+	if len(result.Protected) != 1 || result.Protected[0] != protectedFile {
+		t.Errorf("Expected %s skipped as protected, got %v", protectedFile, result.Protected)
+	}
+	if len(result.Operations) != 1 || result.Operations[0].Status != SkippedProtected || result.Operations[0].Completed {
+		t.Errorf("Expected one skipped operation, got %+v", result.Operations)
+	}
+}
+
+func TestCleanConfiguredProtectedPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	keepDir := filepath.Join(tmpDir, "Favourites")
+	keeper := filepath.Join(tmpDir, "Heat (1995)", "Heat (1995).mkv")
+	protectedCopy := filepath.Join(keepDir, "Heat (1995)", "Heat.1995.720p.mkv")
+	similar := filepath.Join(tmpDir, "Favourites Old", "Heat.1995.480p.mkv")
+	misnamed := filepath.Join(tmpDir, "Alien.1979.mkv")
+	for _, path := range []string{keeper, protectedCopy, similar, misnamed} {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte("content"), 0644)
+	}
+
+	SetProtectedPaths([]string{keepDir})
+	t.Cleanup(func() { SetProtectedPaths(nil) })
+
+	duplicates := []scanner.MovieDuplicate{{
+		Files: []scanner.MovieFile{
+			{Path: keeper, Size: 100},
+			{Path: protectedCopy, Size: 50},
+			{Path: similar, Size: 40},
+		},
+	}}
+	// A report cannot move files into a protected folder either
+	compliance := []scanner.ComplianceIssue{{
+		Path:            misnamed,
+		Type:            "movie",
+		SuggestedAction: "rename",
+		SuggestedPath:   filepath.Join(keepDir, "Alien (1979)", "Alien (1979).mkv"),
+	}}
+
+	config := DefaultConfig()
+	config.DryRun = false
+	config.InUse = nil
+	config.LogPath = filepath.Join(tmpDir, "operations.log")
+	config.JournalDir = filepath.Join(tmpDir, "journal")
+	config.TrashDir = filepath.Join(tmpDir, ".trash")
+
+	result, err := Clean(duplicates, nil, compliance, config)
+	if err != nil {
+		t.Fatalf("Clean() error: %v", err)
+	}
+
+	for _, path := range []string{keeper, protectedCopy, misnamed} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to be left alone: %v", path, err)
+		}
+	}
+	// Only whole path components are protected
+	if _, err := os.Stat(similar); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be deleted", similar)
+	}
+	if result.DuplicatesDeleted != 1 || result.ComplianceFixed != 0 || len(result.Errors) != 0 {
+		t.Errorf("Unexpected result: %d deleted, %d fixed, errors %v", result.DuplicatesDeleted, result.ComplianceFixed, result.Errors)
+	}
+	if len(result.Protected) != 2 || result.Protected[0] != protectedCopy || result.Protected[1] != misnamed {
+		t.Errorf("Expected the protected copy and the move into the protected folder skipped, got %v", result.Protected)
+	}
+
+	entries, err := oplog.Read(config.LogPath)
+	if err != nil {
+		t.Fatalf("failed to read operation log: %v", err)
+	}
+	skipped := 0
+	for _, entry := range entries {
+		if entry.Result == oplog.ResultSkipped {
+			skipped++
+		}
+	}
+	if skipped != 2 {
+		t.Errorf("Expected 2 skipped entries in the operation log, got %d of %+v", skipped, entries)
+	}
+}
+
+func TestCleanTaggedPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	keeper := filepath.Join(tmpDir, "keeper.mkv")
+	kept := filepath.Join(tmpDir, "kept.mkv")
+	untagged := filepath.Join(tmpDir, "untagged.mkv")
+	protectedDir := filepath.Join(tmpDir, "Alien")
+	protectedFile := filepath.Join(protectedDir, "alien.mkv")
+	os.MkdirAll(protectedDir, 0755)
+	for _, path := range []string{keeper, kept, untagged, protectedFile} {
+		os.WriteFile(path, []byte("video"), 0644)
+	}
+
+	store, _ := tags.Load(filepath.Join(tmpDir, "tags.json"))
+	store.Add(kept, "keep-4k")
+	store.Add(protectedDir, "never-touch")
+
+	duplicates := []scanner.MovieDuplicate{{
+		Files: []scanner.MovieFile{{Path: keeper, Size: 100}, {Path: kept, Size: 50}, {Path: untagged, Size: 50}},
+	}}
+	compliance := []scanner.ComplianceIssue{{
+		Type:            "movie",
+		Path:            protectedFile,
+		SuggestedPath:   filepath.Join(protectedDir, "Alien (1979).mkv"),
+		SuggestedAction: "rename",
+	}}
+
+	config := DefaultConfig()
+	config.LogPath = filepath.Join(tmpDir, "operations.log")
+	config.InUse = nil
+	config.Tags = &tags.Rules{Store: store, Keep: []string{"keep-4k"}, Protected: []string{"never-touch"}}
+
+	result, err := Clean(duplicates, nil, compliance, config)
+	if err != nil {
+		t.Fatalf("Clean() error: %v", err)
+	}
+
+	if _, err := os.Stat(kept); err != nil {
+		t.Error("Keep-tagged file was deleted")
+	}
+	if _, err := os.Stat(untagged); !os.IsNotExist(err) {
+		t.Error("Expected untagged duplicate deleted")
+	}
+	if _, err := os.Stat(protectedFile); err != nil {
+		t.Error("File in a never-touch folder was renamed")
+	}
+	if result.DuplicatesDeleted != 1 || result.ComplianceFixed != 0 || len(result.Errors) != 2 {
+		t.Errorf("Expected 1 delete and 2 refusals, got %+v", result)
+	}
+}
+
+func TestCleanFrozenFolders(t *testing.T) {
+	tmpDir := t.TempDir()
+	markedDir := filepath.Join(tmpDir, "Heat (1995)")
+	taggedDir := filepath.Join(tmpDir, "Alien (1979)")
+	os.MkdirAll(markedDir, 0755)
+	os.MkdirAll(taggedDir, 0755)
+	os.WriteFile(filepath.Join(markedDir, scanner.FreezeMarker), nil, 0644)
+	keeper := filepath.Join(markedDir, "Heat (1995) 2160p.mkv")
+	extra := filepath.Join(markedDir, "Heat (1995) 720p.mkv")
+	loose := filepath.Join(tmpDir, "heat.1995.mkv")
+	alien := filepath.Join(taggedDir, "alien.mkv")
+	for _, path := range []string{keeper, extra, loose, alien} {
+		os.WriteFile(path, []byte("video"), 0644)
+	}
+
+	store, _ := tags.Load(filepath.Join(tmpDir, "tags.json"))
+	store.Add(taggedDir, tags.FreezeTag)
+
+	duplicates := []scanner.MovieDuplicate{{
+		Files: []scanner.MovieFile{{Path: keeper, Size: 100}, {Path: extra, Size: 50}},
+	}}
+	compliance := []scanner.ComplianceIssue{
+		{Type: "movie", Path: alien, SuggestedPath: filepath.Join(taggedDir, "Alien (1979).mkv"), SuggestedAction: "rename"},
+		// Moving a loose file into a frozen folder changes it too
+		{Type: "movie", Path: loose, SuggestedPath: filepath.Join(markedDir, "Heat (1995).mkv"), SuggestedAction: "reorganize"},
+	}
+
+	config := DefaultConfig()
+	config.LogPath = filepath.Join(tmpDir, "operations.log")
+	config.InUse = nil
+	config.Tags = &tags.Rules{Store: store}
+
+	result, err := Clean(duplicates, nil, compliance, config)
+	if err != nil {
+		t.Fatalf("Clean() error: %v", err)
+	}
+	for _, path := range []string{extra, alien, loose} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s left alone, got %v", path, err)
+		}
+	}
+	if result.DuplicatesDeleted != 0 || result.ComplianceFixed != 0 || len(result.Errors) != 3 {
+		t.Errorf("Expected 3 refusals, got %+v", result)
+	}
+	for _, err := range result.Errors {
+		if !strings.Contains(err.Error(), "frozen") {
+			t.Errorf("Expected a frozen refusal, got %v", err)
+		}
+	}
+}
+
+func TestCleanExcludedPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	movieDir := filepath.Join(tmpDir, "Heat (1995)")
+	os.MkdirAll(movieDir, 0755)
+	// Excluded after the scan that found them
+	os.WriteFile(filepath.Join(movieDir, scanner.IgnoreFile), []byte("*REMUX*\n"), 0644)
+	keeper := filepath.Join(movieDir, "Heat (1995) 2160p.mkv")
+	remux := filepath.Join(movieDir, "Heat.1995.REMUX.mkv")
+	for _, path := range []string{keeper, remux} {
+		os.WriteFile(path, []byte("video"), 0644)
+	}
+
+	duplicates := []scanner.MovieDuplicate{{
+		Files: []scanner.MovieFile{{Path: keeper, Size: 100}, {Path: remux, Size: 50}},
+	}}
+	compliance := []scanner.ComplianceIssue{
+		{Type: "movie", Path: remux, SuggestedPath: filepath.Join(movieDir, "Heat (1995).mkv"), SuggestedAction: "rename"},
+	}
+
+	config := DefaultConfig()
+	config.LogPath = filepath.Join(tmpDir, "operations.log")
+	config.InUse = nil
+
+	result, err := Clean(duplicates, nil, compliance, config)
+	if err != nil {
+		t.Fatalf("Clean() error: %v", err)
+	}
+	if _, err := os.Stat(remux); err != nil {
+		t.Errorf("Expected the excluded remux left alone, got %v", err)
+	}
+	if result.DuplicatesDeleted != 0 || result.ComplianceFixed != 0 || len(result.Errors) != 2 {
+		t.Errorf("Expected 2 refusals, got %+v", result)
+	}
+	for _, err := range result.Errors {
+		if !strings.Contains(err.Error(), "excluded by") {
+			t.Errorf("Expected an exclusion refusal, got %v", err)
+		}
+	}
+}
+
+func TestCleanDefersImports(t *testing.T) {
+	tmpDir := t.TempDir()
+	busyDir := filepath.Join(tmpDir, "Heat (1995)")
+	idleDir := filepath.Join(tmpDir, "Alien (1979)")
+	os.MkdirAll(busyDir, 0755)
+	os.MkdirAll(idleDir, 0755)
+	keeper := filepath.Join(busyDir, "Heat (1995) 2160p.mkv")
+	extra := filepath.Join(busyDir, "Heat (1995) 720p.mkv")
+	loose := filepath.Join(tmpDir, "heat.1995.mkv")
+	alienKeeper := filepath.Join(idleDir, "Alien (1979) 2160p.mkv")
+	alienExtra := filepath.Join(idleDir, "Alien (1979) 720p.mkv")
+	for _, path := range []string{keeper, extra, loose, alienKeeper, alienExtra} {
+		os.WriteFile(path, []byte("video"), 0644)
+	}
+	// Radarr is copying an upgrade into the Heat folder
+	os.WriteFile(filepath.Join(busyDir, "Heat (1995) 1080p.mkv.partial~"), []byte("vid"), 0644)
+
+	duplicates := []scanner.MovieDuplicate{
+		{Files: []scanner.MovieFile{{Path: keeper, Size: 100}, {Path: extra, Size: 50}}},
+		{Files: []scanner.MovieFile{{Path: alienKeeper, Size: 100}, {Path: alienExtra, Size: 50}}},
+	}
+	compliance := []scanner.ComplianceIssue{
+		{Type: "movie", Path: loose, SuggestedPath: filepath.Join(busyDir, "Heat (1995).mkv"), SuggestedAction: "reorganize"},
+	}
+
+	config := DefaultConfig()
+	config.LogPath = filepath.Join(tmpDir, "operations.log")
+	config.TrashDir = filepath.Join(tmpDir, "trash")
+	config.JournalDir = filepath.Join(tmpDir, "journal")
+	config.InUse = nil
+	config.Refresh = nil
+
+	result, err := Clean(duplicates, nil, compliance, config)
+	if err != nil {
+		t.Fatalf("Clean() error: %v", err)
+	}
+	for _, path := range []string{extra, loose} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s left alone during the import, got %v", path, err)
+		}
+	}
+	if _, err := os.Stat(alienExtra); !os.IsNotExist(err) {
+		t.Errorf("Expected the idle folder cleaned, got %v", err)
+	}
+	if len(result.Importing) != 2 || result.DuplicatesDeleted != 1 || len(result.Errors) != 0 {
+		t.Errorf("Expected 2 deferred imports and 1 delete, got %+v", result)
+	}
+	for _, op := range result.Operations {
+		if op.Source != alienExtra && op.Status != DeferredImporting {
+			t.Errorf("Expected %s deferred, got status %q", op.Source, op.Status)
+		}
+	}
+}
+
+func TestCleanRefreshesLibrary(t *testing.T) {
+	tmpDir := t.TempDir()
+	keepFile := filepath.Join(tmpDir, "keep.mkv")
+	deleteFile := filepath.Join(tmpDir, "delete.mkv")
+	os.WriteFile(keepFile, []byte("keeper"), 0644)
+	os.WriteFile(deleteFile, []byte("delete me"), 0644)
+
+	duplicates := []scanner.MovieDuplicate{{
+		Files: []scanner.MovieFile{{Path: keepFile, Size: 100}, {Path: deleteFile, Size: 50}},
+	}}
+
+	calls := 0
+	config := DefaultConfig()
+	config.LogPath = filepath.Join(tmpDir, "operations.log")
+	config.InUse = nil
+	config.Refresh = RefreshFunc(func() error { calls++; return nil })
+	config.DryRun = true
+
+	// A dry run changes nothing, so no refresh is requested
+	result, err := Clean(duplicates, nil, nil, config)
+	if err != nil {
+		t.Fatalf("Clean() dry run error: %v", err)
+	}
+	if calls != 0 || result.LibraryRefreshed {
+		t.Errorf("Dry run requested a library refresh")
+	}
+
+	config.DryRun = false
+	result, err = Clean(duplicates, nil, nil, config)
+	if err != nil {
+		t.Fatalf("Clean() error: %v", err)
+	}
+	if calls != 1 || !result.LibraryRefreshed {
+		t.Errorf("Expected one library refresh, got %d calls (refreshed=%v)", calls, result.LibraryRefreshed)
+	}
+
+	// Failures are reported on the result without failing the clean
+	os.WriteFile(deleteFile, []byte("delete me"), 0644)
+	config.Refresh = RefreshFunc(func() error { return fmt.Errorf("connection refused") })
+	result, err = Clean(duplicates, nil, nil, config)
+	if err != nil {
+		t.Fatalf("Clean() with failing refresh error: %v", err)
+	}
+	if result.RefreshErr == nil || result.LibraryRefreshed {
+		t.Errorf("Expected refresh error on the result, got %+v", result)
+	}
+}
+
+func TestCleanKeepsVersions(t *testing.T) {
+	tmpDir := t.TempDir()
+	movieDir := filepath.Join(tmpDir, "Heat (1995)")
+	otherDir := filepath.Join(tmpDir, "Heat.1995.1080p.BluRay")
+	os.MkdirAll(movieDir, 0755)
+	os.MkdirAll(otherDir, 0755)
+	keeper := filepath.Join(movieDir, "Heat (1995).mkv")
+	other := filepath.Join(otherDir, "heat.mkv")
+	os.WriteFile(keeper, []byte("keeper"), 0644)
+	os.WriteFile(other, []byte("copy"), 0644)
+
+	duplicates := []scanner.MovieDuplicate{{
+		Files: []scanner.MovieFile{
+			{Path: keeper, Size: 100, Resolution: "2160p"},
+			{Path: other, Size: 50, Resolution: "1080p"},
+		},
+		Strategy: scanner.StrategyMultiVersion,
+	}}
+
+	config := DefaultConfig()
+	config.LogPath = filepath.Join(tmpDir, "operations.log")
+	config.InUse = nil
+	config.Refresh = nil
+
+	result, err := Clean(duplicates, nil, nil, config)
+	if err != nil {
+		t.Fatalf("Clean() error: %v", err)
+	}
+
+	for _, name := range []string{"Heat (1995) - 2160p.mkv", "Heat (1995) - 1080p.mkv"} {
+		if _, err := os.Stat(filepath.Join(movieDir, name)); err != nil {
+			t.Errorf("Expected version %s: %v", name, err)
+		}
+	}
+	if result.VersionsKept != 2 || result.DuplicatesDeleted != 0 || result.SpaceFreed != 0 {
+		t.Errorf("Expected 2 versions kept and nothing deleted, got %+v", result)
+	}
+	if _, err := os.Stat(otherDir); !os.IsNotExist(err) {
+		t.Error("Expected the emptied release folder to be removed")
+	}
+}
+
+func TestCleanMergesCompanions(t *testing.T) {
+	tmpDir := t.TempDir()
+	keeper := filepath.Join(tmpDir, "tv", "Firefly", "Season 01", "Firefly S01E01.mkv")
+	extra := filepath.Join(tmpDir, "tv", "Firefly.S01.720p", "firefly.s01e01.720p.mkv")
+	subtitle := filepath.Join(filepath.Dir(extra), "firefly.s01e01.720p.en.srt")
+	merged := filepath.Join(filepath.Dir(keeper), "Firefly S01E01.en.srt")
+	for path, content := range map[string]string{keeper: "keeper", extra: "extra", subtitle: "subs"} {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+
+	config := DefaultConfig()
+	config.LogPath = filepath.Join(tmpDir, "operations.log")
+	config.JournalDir = filepath.Join(tmpDir, "journal")
+	config.TrashDir = filepath.Join(tmpDir, "trash")
+	config.InUse = nil
+	config.Refresh = nil
+
+	group := scanner.TVDuplicate{
+		ShowName: "Firefly", Season: 1, Episode: 1,
+		Files: []scanner.TVFile{{Path: keeper, Size: 6}, {Path: extra, Size: 5}},
+	}
+	off := false
+	group.MergeCompanions = &off
+
+	// A group opted out keeps the subtitle where it is; a dry run only reports
+	config.DryRun = true
+	if result, err := Clean(nil, []scanner.TVDuplicate{group}, nil, config); err != nil || result.CompanionsMerged != 0 {
+		t.Fatalf("Clean() = %+v, %v", result, err)
+	}
+	group.MergeCompanions = nil
+	if result, err := Clean(nil, []scanner.TVDuplicate{group}, nil, config); err != nil || result.CompanionsMerged != 0 {
+		t.Fatalf("Clean() = %+v, %v; want a dry run to move nothing", result, err)
+	}
+	if _, err := os.Stat(subtitle); err != nil {
+		t.Fatalf("Expected the dry run to leave the subtitle: %v", err)
+	}
+
+	config.DryRun = false
+	result, err := Clean(nil, []scanner.TVDuplicate{group}, nil, config)
+	if err != nil || len(result.Errors) > 0 {
+		t.Fatalf("Clean() = %+v, %v", result, err)
+	}
+	if result.CompanionsMerged != 1 || result.DuplicatesDeleted != 1 {
+		t.Errorf("Expected the copy deleted and its subtitle merged, got %+v", result)
+	}
+	if data, err := os.ReadFile(merged); err != nil || string(data) != "subs" {
+		t.Fatalf("Expected the subtitle next to the keeper, got %q, %v", data, err)
+	}
+
+	// Undo puts the subtitle back with its video
+	undo, err := Undo(config, result.JournalID)
+	if err != nil || undo.Restored != 2 {
+		t.Fatalf("Undo() = %+v, %v", undo, err)
+	}
+	for _, path := range []string{extra, subtitle} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s restored: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(merged); !os.IsNotExist(err) {
+		t.Error("Expected the merged subtitle moved back")
+	}
+}
+
+func TestCleanRemovesOrphanFolders(t *testing.T) {
+	tmpDir := t.TempDir()
+	emptyShow := filepath.Join(tmpDir, "Firefly (2002)")
+	emptySeason := filepath.Join(tmpDir, "Lost (2004)", "Season 02")
+	refilled := filepath.Join(tmpDir, "Lost (2004)", "Season 03")
+	for _, dir := range []string{emptyShow, emptySeason, refilled} {
+		os.MkdirAll(dir, 0755)
+	}
+	os.WriteFile(filepath.Join(emptyShow, "tvshow.nfo"), []byte("nfo"), 0644)
+	os.WriteFile(filepath.Join(emptySeason, "poster.jpg"), []byte("art"), 0644)
+	// Episodes arrived in Season 03 after the scan
+	os.WriteFile(filepath.Join(refilled, "Lost (2004) S03E01.mkv"), []byte("video"), 0644)
+
+	config := DefaultConfig()
+	config.DryRun = true
+	config.LogPath = filepath.Join(tmpDir, "operations.log")
+	config.InUse = nil
+	config.Refresh = nil
+	config.OrphanFolders = []scanner.OrphanFolder{
+		{Path: emptyShow, Kind: "show", Files: 1, Size: 3},
+		{Path: emptySeason, Kind: "season", Files: 1, Size: 3},
+		{Path: refilled, Kind: "season"},
+	}
+
+	result, err := Clean(nil, nil, nil, config)
+	if err != nil {
+		t.Fatalf("Clean() dry run error: %v", err)
+	}
+	if _, err := os.Stat(emptyShow); err != nil {
+		t.Error("Dry run should not delete folders")
+	}
+	if len(result.Errors) != 1 {
+		t.Errorf("Expected the refilled season to be refused, got %v", result.Errors)
+	}
+
+	config.DryRun = false
+	result, err = Clean(nil, nil, nil, config)
+	if err != nil {
+		t.Fatalf("Clean() error: %v", err)
+	}
+	if result.FoldersRemoved != 2 || result.SpaceFreed != 6 {
+		t.Errorf("Expected 2 folders removed freeing 6 bytes, got %+v", result)
+	}
+	for _, dir := range []string{emptyShow, emptySeason} {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be deleted", dir)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(refilled, "Lost (2004) S03E01.mkv")); err != nil {
+		t.Error("Season with episodes must be kept")
+	}
+}
+
+func TestCleanRemovesArtifacts(t *testing.T) {
+	tmpDir := t.TempDir()
+	leftover := filepath.Join(tmpDir, "Alien (1979)")
+	refilled := filepath.Join(tmpDir, "Heat (1995)")
+	junk := filepath.Join(tmpDir, "Arrival (2016)", "RARBG.txt")
+	for _, dir := range []string{leftover, refilled, filepath.Dir(junk)} {
+		os.MkdirAll(dir, 0755)
+	}
+	os.WriteFile(filepath.Join(leftover, "Alien (1979).nfo"), []byte("nfo"), 0644)
+	os.WriteFile(junk, []byte("ad"), 0644)
+	// The movie arrived in Heat (1995) after the scan
+	os.WriteFile(filepath.Join(refilled, "Heat (1995).mkv"), []byte("video"), 0644)
+
+	config := DefaultConfig()
+	config.DryRun = true
+	config.LogPath = filepath.Join(tmpDir, "operations.log")
+	config.JournalDir = filepath.Join(tmpDir, "journal")
+	config.TrashDir = filepath.Join(tmpDir, "trash")
+	config.InUse = nil
+	config.Refresh = nil
+	config.Artifacts = []scanner.Artifact{
+		{Path: leftover, Kind: scanner.ArtifactLeftoverFolder, Files: 1, Size: 3},
+		{Path: refilled, Kind: scanner.ArtifactEmptyFolder},
+		{Path: junk, Kind: scanner.ArtifactJunk, Size: 2},
+	}
+
+	result, err := Clean(nil, nil, nil, config)
+	if err != nil {
+		t.Fatalf("Clean() dry run error: %v", err)
+	}
+	if _, err := os.Stat(junk); err != nil {
+		t.Error("Dry run should not delete artifacts")
+	}
+	if len(result.Errors) != 1 || len(result.Operations) != 3 {
+		t.Errorf("Expected the refilled folder to be refused, got %v", result.Errors)
+	}
+
+	config.DryRun = false
+	result, err = Clean(nil, nil, nil, config)
+	if err != nil {
+		t.Fatalf("Clean() error: %v", err)
+	}
+	if result.ArtifactsRemoved != 2 || result.SpaceFreed != 5 || result.JournalID == "" {
+		t.Errorf("Expected 2 artifacts removed freeing 5 bytes, got %+v", result)
+	}
+	for _, path := range []string{leftover, junk} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be deleted", path)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(refilled, "Heat (1995).mkv")); err != nil {
+		t.Error("Folder with a video must be kept")
 	}
 }
 
@@ -335,3 +917,34 @@ func TestCleanSizeLimit(t *testing.T) {
 		t.Error("Expected error for size limit exceeded, got none")
 	}
 }
+
+func TestCleanFollowsRenamedShowFolders(t *testing.T) {
+	tv := t.TempDir()
+	show := filepath.Join(tv, "Firefly")
+	episode := filepath.Join(show, "Season 1", "Firefly S01E01.mkv")
+	os.MkdirAll(filepath.Dir(episode), 0755)
+	os.WriteFile(episode, []byte("content"), 0644)
+
+	issues := []scanner.ComplianceIssue{
+		{Path: show, Type: "tv", Problem: "Show folder has no year", Rule: scanner.RuleTVShowFolderYear,
+			SuggestedPath: filepath.Join(tv, "Firefly (2002)"), SuggestedAction: "rename"},
+		{Path: episode, Type: "tv", Problem: "Season folder", Rule: scanner.RuleTVSeasonFolder,
+			SuggestedPath: filepath.Join(show, "Season 01", "Firefly S01E01.mkv"), SuggestedAction: "reorganize"},
+	}
+	config := DefaultConfig()
+	config.DryRun = false
+
+	result, err := Clean(nil, nil, issues, config)
+	if err != nil {
+		t.Fatalf("Clean() error: %v", err)
+	}
+	if len(result.Errors) != 0 || result.ComplianceFixed != 2 {
+		t.Fatalf("Expected both fixes applied, got %d fixed, errors %v", result.ComplianceFixed, result.Errors)
+	}
+	if _, err := os.Stat(filepath.Join(tv, "Firefly (2002)", "Season 01", "Firefly S01E01.mkv")); err != nil {
+		t.Errorf("Episode not in the renamed show folder: %v", err)
+	}
+	if _, err := os.Stat(show); !os.IsNotExist(err) {
+		t.Error("Old show folder still exists")
+	}
+}
@@ -0,0 +1,155 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// Cleans go easy on cloud mounts (see scanner.CloudMount): renames within a
+// folder are applied in batches with a pause between them, dry runs don't
+// open files there, and moves between folders are warned about up front,
+// since a remote without server-side moves carries them out by downloading
+// and uploading the whole file again
+
+const (
+	// cloudRenameBatch is how many renames on a cloud mount are applied
+	// before pausing
+	cloudRenameBatch = 20
+	// largeCloudMove is the size from which a move on a cloud mount is
+	// warned about by name
+	largeCloudMove = 1 << 30
+)
+
+var (
+	// cloudMountOf finds the cloud mount a path is on; tests replace it
+	cloudMountOf = scanner.CloudMount
+	// cloudPause waits between rename batches on a cloud mount; tests replace it
+	cloudPause = func() { time.Sleep(2 * time.Second) }
+)
+
+// cloudBatcher counts the renames applied on cloud mounts and pauses after
+// every full batch, so the remote's API rate limits are not run into
+type cloudBatcher struct {
+	renames int
+}
+
+// renamed records a rename of path, pausing when it completes a batch
+func (b *cloudBatcher) renamed(path string) {
+	if !onCloudMount(path) {
+		return
+	}
+	b.renames++
+	if b.renames%cloudRenameBatch == 0 {
+		cloudPause()
+	}
+}
+
+// onCloudMount reports whether path is on a cloud mount
+func onCloudMount(path string) bool {
+	_, _, cloud := cloudMountOf(path)
+	return cloud
+}
+
+// cloudMove is a clean operation that moves file contents to another
+// folder on a cloud mount
+type cloudMove struct {
+	source string
+	target string // "" for the trash
+	size   int64
+}
+
+// CloudWarnings describes the operations of a clean that move files or
+// folders to another folder on a cloud mount: moves into the trash,
+// version merges into the keeper's folder and reorganized files, plus any
+// folder rename. Renaming a file within its folder is cheap everywhere and
+// left out
+func CloudWarnings(duplicates []scanner.MovieDuplicate, tvDuplicates []scanner.TVDuplicate,
+	compliance []scanner.ComplianceIssue, config Config) []string {
+	duplicates, tvDuplicates = includedDuplicates(duplicates, tvDuplicates)
+	var mounts []string
+	fsTypes := make(map[string]string)
+	moves := make(map[string][]cloudMove)
+	add := func(source, target string, size int64) {
+		point, fsType, ok := cloudMountOf(source)
+		if !ok {
+			return
+		}
+		if _, seen := fsTypes[point]; !seen {
+			mounts = append(mounts, point)
+			fsTypes[point] = fsType
+		}
+		moves[point] = append(moves[point], cloudMove{source: source, target: target, size: size})
+	}
+
+	for _, dup := range duplicates {
+		if !dup.KeepsAllVersions() {
+			for i := 1; i < len(dup.Files); i++ {
+				add(dup.Files[i].Path, "", dup.Files[i].Size)
+			}
+			continue
+		}
+		renames, err := scanner.MultiVersionPlan(dup)
+		if err != nil {
+			continue
+		}
+		for _, r := range renames {
+			if filepath.Dir(r.Source) == filepath.Dir(r.Target) {
+				continue
+			}
+			for _, file := range dup.Files {
+				if file.Path == r.Source {
+					add(r.Source, r.Target, file.Size)
+				}
+			}
+		}
+	}
+	for _, dup := range tvDuplicates {
+		for i := 1; i < len(dup.Files); i++ {
+			add(dup.Files[i].Path, "", dup.Files[i].Size)
+		}
+	}
+	for _, issue := range compliance {
+		if issue.SuggestedAction == "manual_review" || !onCloudMount(issue.Path) {
+			continue
+		}
+		info, err := os.Stat(issue.Path)
+		switch {
+		case err != nil:
+		case info.IsDir():
+			add(issue.Path, issue.SuggestedPath, pathSize(issue.Path))
+		case filepath.Dir(issue.Path) != filepath.Dir(issue.SuggestedPath):
+			add(issue.Path, issue.SuggestedPath, info.Size())
+		}
+	}
+	for _, folder := range config.OrphanFolders {
+		add(folder.Path, "", folder.Size)
+	}
+	for _, artifact := range config.Artifacts {
+		add(artifact.Path, "", artifact.Size)
+	}
+
+	var warnings []string
+	for _, point := range mounts {
+		var total int64
+		for _, move := range moves[point] {
+			total += move.size
+		}
+		warnings = append(warnings, fmt.Sprintf("Cloud mount %s (%s): %d moves to other folders carry %.2f GB; a remote without server-side moves downloads and uploads all of it again",
+			point, fsTypes[point], len(moves[point]), float64(total)/(1024*1024*1024)))
+		for _, move := range moves[point] {
+			if move.size < largeCloudMove {
+				continue
+			}
+			target := move.target
+			if target == "" {
+				target = "the trash"
+			}
+			warnings = append(warnings, fmt.Sprintf("  %.2f GB: %s -> %s", float64(move.size)/(1024*1024*1024), move.source, target))
+		}
+	}
+	return warnings
+}
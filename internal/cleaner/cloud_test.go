@@ -0,0 +1,87 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// fakeCloudMount makes everything below dir count as an rclone mount for the
+// rest of the test
+func fakeCloudMount(t *testing.T, dir string) {
+	t.Helper()
+	orig := cloudMountOf
+	cloudMountOf = func(path string) (string, string, bool) {
+		if strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return dir, "fuse.rclone", true
+		}
+		return "", "", false
+	}
+	t.Cleanup(func() { cloudMountOf = orig })
+}
+
+func TestCloudWarnings(t *testing.T) {
+	cloud := t.TempDir()
+	fakeCloudMount(t, cloud)
+
+	movie := filepath.Join(cloud, "Movies", "Alien (1979)")
+	os.MkdirAll(movie, 0755)
+	rename := filepath.Join(movie, "alien.1979.mkv")
+	os.WriteFile(rename, []byte("video"), 0644)
+	loose := filepath.Join(cloud, "Movies", "Heat.1995.mkv")
+	os.WriteFile(loose, []byte("video content"), 0644)
+
+	duplicates := []scanner.MovieDuplicate{
+		{Files: []scanner.MovieFile{
+			{Path: filepath.Join(movie, "Alien (1979) - 2160p.mkv"), Size: 40 << 30},
+			{Path: filepath.Join(movie, "Alien (1979) - 1080p.mkv"), Size: 3 << 30},
+		}},
+		{Files: []scanner.MovieFile{
+			{Path: "/srv/media/Movies/Heat (1995)/Heat (1995).mkv", Size: 8 << 30},
+			{Path: "/srv/media/Movies/Heat (1995)/Heat.1995.720p.mkv", Size: 2 << 30},
+		}},
+	}
+	compliance := []scanner.ComplianceIssue{
+		// Renamed in place: cheap everywhere
+		{Path: rename, SuggestedPath: filepath.Join(movie, "Alien (1979).mkv"), SuggestedAction: "rename"},
+		// Moved into its own folder: uploaded again
+		{Path: loose, SuggestedPath: filepath.Join(cloud, "Movies", "Heat (1995)", "Heat (1995).mkv"), SuggestedAction: "reorganize"},
+	}
+
+	warnings := CloudWarnings(duplicates, nil, compliance, Config{})
+	if len(warnings) != 2 {
+		t.Fatalf("CloudWarnings() = %q, want a summary and one large move", warnings)
+	}
+	if !strings.Contains(warnings[0], "Cloud mount "+cloud+" (fuse.rclone): 2 moves") {
+		t.Errorf("Unexpected summary: %q", warnings[0])
+	}
+	if !strings.Contains(warnings[1], "3.00 GB: "+duplicates[0].Files[1].Path+" -> the trash") {
+		t.Errorf("Unexpected large move: %q", warnings[1])
+	}
+
+	// Nothing to warn about off cloud mounts
+	if warnings := CloudWarnings(duplicates[1:], nil, nil, Config{}); len(warnings) != 0 {
+		t.Errorf("CloudWarnings() = %q, want nothing", warnings)
+	}
+}
+
+func TestCloudBatcher(t *testing.T) {
+	cloud := t.TempDir()
+	fakeCloudMount(t, cloud)
+	pauses := 0
+	orig := cloudPause
+	cloudPause = func() { pauses++ }
+	t.Cleanup(func() { cloudPause = orig })
+
+	var batcher cloudBatcher
+	for i := 0; i < 45; i++ {
+		batcher.renamed(filepath.Join(cloud, "Movies", "a.mkv"))
+		batcher.renamed("/srv/media/Movies/a.mkv")
+	}
+	if pauses != 2 {
+		t.Errorf("Expected a pause after each 20 cloud renames, got %d pauses for 45", pauses)
+	}
+}
@@ -0,0 +1,47 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// mergeCompanions moves the subtitles and artwork of a deleted duplicate
+// that its keeper lacks into the keeper's folder, named after the keeper.
+// moves come from scanner.CompanionMerges, taken before the duplicate was
+// deleted. Sidecars under protected paths or tags stay where they are
+func mergeCompanions(moves []scanner.CompanionMove, config Config, journal *Journal, result *CleanResult, pr *scanner.ProgressReporter) {
+	var allowed []scanner.CompanionMove
+	for _, move := range moves {
+		if isProtectedPath(move.Source, config.ProtectedPaths) || isProtectedPath(move.Target, config.ProtectedPaths) {
+			continue
+		}
+		if tagRefusal(move.Source, config, false) != nil || tagRefusal(move.Target, config, false) != nil {
+			continue
+		}
+		allowed = append(allowed, move)
+	}
+
+	if config.DryRun {
+		for _, move := range allowed {
+			if pr != nil {
+				pr.Send(scanner.SeverityInfo, fmt.Sprintf("Would move to keeper: %s -> %s", move.Source, filepath.Base(move.Target)))
+			}
+		}
+		return
+	}
+
+	scanner.ApplyCompanionMoves(allowed)
+	for _, move := range allowed {
+		if _, err := os.Lstat(move.Source); err == nil {
+			continue
+		}
+		result.CompanionsMerged++
+		if pr != nil {
+			pr.Send(scanner.SeverityInfo, fmt.Sprintf("Moved to keeper: %s -> %s", move.Source, filepath.Base(move.Target)))
+		}
+	}
+	journal.recordCompanions("merge", allowed)
+}
@@ -0,0 +1,109 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// hardlinkDuplicates is the [cleaner] hardlink_duplicates DefaultConfig uses
+var (
+	hardlinkDuplicates   bool
+	hardlinkDuplicatesMu sync.RWMutex
+)
+
+// linkFile creates a hardlink; tests replace it
+var linkFile = os.Link
+
+// SetHardlinkDuplicates makes DefaultConfig replace duplicates with
+// hardlinks to their keeper instead of deleting them
+func SetHardlinkDuplicates(enabled bool) {
+	hardlinkDuplicatesMu.Lock()
+	defer hardlinkDuplicatesMu.Unlock()
+	hardlinkDuplicates = enabled
+}
+
+func hardlinkDuplicatesEnabled() bool {
+	hardlinkDuplicatesMu.RLock()
+	defer hardlinkDuplicatesMu.RUnlock()
+	return hardlinkDuplicates
+}
+
+// linkDuplicate replaces the duplicate op.Source with a hardlink to keeper:
+// the copy goes to the trash and its path then names the keeper's file, so
+// whatever points at the path keeps working. The journal records both, and
+// an undo removes the link before restoring the copy. When the link can't
+// be made the copy is put back, so a failure never turns into a delete.
+// freed is the space the copy's removal frees
+func linkDuplicate(op Operation, keeper string, freed int64, config Config, journal *Journal, result *CleanResult, pr *scanner.ProgressReporter, progress int) {
+	op.Type = "link"
+	op.Destination = keeper
+	op.Timestamp = time.Now()
+
+	err := checkLinkable(op.Source, keeper)
+	if err == nil && !config.DryRun {
+		if err = moveToTrash(op.Source, "delete", config, journal); err == nil {
+			if linkErr := linkFile(keeper, op.Source); linkErr != nil {
+				err = restoreFromTrash(op.Source, journal, linkErr)
+			} else {
+				journal.record("link", op.Source, keeper)
+			}
+		}
+	}
+
+	if err != nil {
+		err = fmt.Errorf("cannot replace %s with a hardlink: %w", op.Source, err)
+		result.Errors = append(result.Errors, err)
+		op.Error = err.Error()
+		if pr != nil {
+			pr.LogError(err, err.Error())
+		}
+	} else {
+		op.Completed = true
+		if config.DryRun {
+			if pr != nil {
+				pr.Update(progress, fmt.Sprintf("Would hardlink: %s", op.Source))
+			}
+		} else {
+			result.DuplicatesLinked++
+			result.SpaceFreed += freed
+			if pr != nil {
+				pr.Update(progress, fmt.Sprintf("Hardlinked: %s", op.Source))
+			}
+		}
+	}
+	result.Operations = append(result.Operations, op)
+}
+
+// restoreFromTrash moves the copy just trashed for path back in place and
+// drops its journal entry after linking failed with linkErr
+func restoreFromTrash(path string, journal *Journal, linkErr error) error {
+	entry, ok := journal.last()
+	if !ok || entry.Source != path {
+		return fmt.Errorf("linking failed: %w", linkErr)
+	}
+	if err := os.Rename(entry.Destination, path); err != nil {
+		return fmt.Errorf("linking failed (%v) and the copy is still in the trash at %s: %w", linkErr, entry.Destination, err)
+	}
+	journal.dropLast()
+	return fmt.Errorf("linking failed, the copy was left in place: %w", linkErr)
+}
+
+// checkLinkable returns why path can't be replaced with a hardlink to
+// keeper: hardlinks can't cross filesystems
+func checkLinkable(path, keeper string) error {
+	if err := checkFileAccessible(path); err != nil {
+		return err
+	}
+	keeperDev, ok := deviceOf(keeper)
+	if !ok {
+		return fmt.Errorf("cannot access %s", keeper)
+	}
+	if dev, ok := deviceOf(path); !ok || dev != keeperDev {
+		return fmt.Errorf("it is on another filesystem than %s", keeper)
+	}
+	return nil
+}
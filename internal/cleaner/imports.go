@@ -0,0 +1,28 @@
+package cleaner
+
+import "github.com/Nomadcxx/jellysink/internal/scanner"
+
+// DeferredImporting is the status of an operation postponed because
+// Sonarr, Radarr or a download client is writing into its folder
+const DeferredImporting = "deferred: import in progress"
+
+// deferIfImporting reports whether an import is in progress in the folder
+// op reads from or writes to, recording the operation as deferred if so.
+// The next scan finds the folder again once the import has finished
+func deferIfImporting(op Operation, result *CleanResult, pr *scanner.ProgressReporter) bool {
+	file := scanner.ImportInProgress(op.Source)
+	if file == "" && op.Destination != "" {
+		file = scanner.ImportInProgress(op.Destination)
+	}
+	if file == "" {
+		return false
+	}
+
+	op.Status = DeferredImporting
+	result.Operations = append(result.Operations, op)
+	result.Importing = append(result.Importing, op.Source)
+	if pr != nil {
+		pr.Send(scanner.SeverityWarn, "Deferred (import in progress: "+file+"): "+op.Source)
+	}
+	return true
+}
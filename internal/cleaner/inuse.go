@@ -0,0 +1,142 @@
+package cleaner
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// DeferredInUse is the status of an operation postponed because its file is in use
+const DeferredInUse = "deferred: in use"
+
+// InUseChecker reports whether a file (or any file inside a folder) is in use
+type InUseChecker interface {
+	InUse(path string) (bool, error)
+}
+
+// LsofChecker asks lsof whether any process holds the path open. Without
+// lsof on PATH nothing is reported as in use
+type LsofChecker struct{}
+
+// InUse runs lsof on path (recursively for folders)
+func (LsofChecker) InUse(path string) (bool, error) {
+	lsof, err := exec.LookPath("lsof")
+	if err != nil {
+		return false, nil
+	}
+
+	args := []string{"-t", "--", path}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		args = []string{"-t", "+D", path}
+	}
+	out, err := exec.Command(lsof, args...).Output()
+	if len(strings.TrimSpace(string(out))) > 0 {
+		return true, nil
+	}
+
+	// lsof exits 1 when no process has the path open
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return false, err
+	}
+	return false, nil
+}
+
+// PlayingChecker treats files a media server reports as playing as in use.
+// Playing is re-queried at most once per MaxAge; while it fails, Fallback
+// (usually LsofChecker) is asked instead
+type PlayingChecker struct {
+	Playing  func() ([]string, error)
+	Fallback InUseChecker
+	MaxAge   time.Duration
+
+	mu      sync.Mutex
+	paths   []string
+	err     error
+	fetched time.Time
+}
+
+// InUse reports whether path is, or contains, a playing file
+func (c *PlayingChecker) InUse(path string) (bool, error) {
+	paths, err := c.playing()
+	if err != nil {
+		if c.Fallback != nil {
+			return c.Fallback.InUse(path)
+		}
+		return false, err
+	}
+
+	path = filepath.Clean(path)
+	for _, p := range paths {
+		p = filepath.Clean(p)
+		if p == path || strings.HasPrefix(p, path+string(filepath.Separator)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *PlayingChecker) playing() ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	maxAge := c.MaxAge
+	if maxAge <= 0 {
+		maxAge = 10 * time.Second
+	}
+	if c.fetched.IsZero() || time.Since(c.fetched) > maxAge {
+		c.paths, c.err = c.Playing()
+		c.fetched = time.Now()
+	}
+	return c.paths, c.err
+}
+
+// defaultInUse is the checker DefaultConfig installs
+var (
+	defaultInUse   InUseChecker = LsofChecker{}
+	defaultInUseMu sync.RWMutex
+)
+
+// SetInUseChecker sets the checker used by DefaultConfig (nil disables the check)
+func SetInUseChecker(c InUseChecker) {
+	defaultInUseMu.Lock()
+	defer defaultInUseMu.Unlock()
+	defaultInUse = c
+}
+
+func getInUseChecker() InUseChecker {
+	defaultInUseMu.RLock()
+	defer defaultInUseMu.RUnlock()
+	return defaultInUse
+}
+
+// deferIfInUse reports whether path is in use, recording the operation as
+// deferred if so. A failing check is logged and does not block the operation
+func deferIfInUse(op Operation, config Config, result *CleanResult, pr *scanner.ProgressReporter) bool {
+	if config.InUse == nil {
+		return false
+	}
+	inUse, err := config.InUse.InUse(op.Source)
+	if err != nil {
+		if pr != nil {
+			pr.Send(scanner.SeverityWarn, "Could not check whether "+op.Source+" is in use: "+err.Error())
+		}
+		return false
+	}
+	if !inUse {
+		return false
+	}
+
+	op.Status = DeferredInUse
+	result.Operations = append(result.Operations, op)
+	result.Deferred = append(result.Deferred, op.Source)
+	if pr != nil {
+		pr.Send(scanner.SeverityWarn, "Deferred (in use): "+op.Source)
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+package cleaner
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// pathSetChecker reports a fixed set of paths as in use
+type pathSetChecker map[string]bool
+
+func (c pathSetChecker) InUse(path string) (bool, error) { return c[path], nil }
+
+func TestCleanDefersInUseFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	keep := filepath.Join(tmpDir, "keep.mkv")
+	playing := filepath.Join(tmpDir, "playing.mkv")
+	idle := filepath.Join(tmpDir, "idle.mkv")
+	for _, path := range []string{keep, playing, idle} {
+		os.WriteFile(path, []byte("video"), 0644)
+	}
+
+	duplicates := []scanner.MovieDuplicate{{
+		Files: []scanner.MovieFile{{Path: keep, Size: 100}, {Path: playing, Size: 50}, {Path: idle, Size: 50}},
+	}}
+	compliance := []scanner.ComplianceIssue{{
+		Type:            "movie",
+		Path:            keep,
+		SuggestedPath:   filepath.Join(tmpDir, "Keep (2020).mkv"),
+		SuggestedAction: "rename",
+	}}
+
+	config := DefaultConfig()
+	config.LogPath = filepath.Join(tmpDir, "operations.log")
+	config.InUse = pathSetChecker{playing: true, keep: true}
+
+	result, err := Clean(duplicates, nil, compliance, config)
+	if err != nil {
+		t.Fatalf("Clean() error: %v", err)
+	}
+
+	if _, err := os.Stat(playing); err != nil {
+		t.Error("Expected in-use file left in place")
+	}
+	if _, err := os.Stat(idle); !os.IsNotExist(err) {
+		t.Error("Expected idle duplicate deleted")
+	}
+	if _, err := os.Stat(keep); err != nil {
+		t.Error("Expected in-use file not renamed")
+	}
+	if result.DuplicatesDeleted != 1 || result.ComplianceFixed != 0 || len(result.Errors) != 0 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if len(result.Deferred) != 2 || result.Deferred[0] != playing || result.Deferred[1] != keep {
+		t.Errorf("Expected playing and keep deferred, got %v", result.Deferred)
+	}
+
+	deferred := 0
+	for _, op := range result.Operations {
+		if op.Status == DeferredInUse {
+			deferred++
+			if op.Completed {
+				t.Errorf("Deferred operation marked completed: %+v", op)
+			}
+		}
+	}
+	if deferred != 2 {
+		t.Errorf("Expected 2 operations with status %q, got %d", DeferredInUse, deferred)
+	}
+
+	entries, err := oplog.Read(config.LogPath)
+	if err != nil {
+		t.Fatalf("Expected an operation log: %v", err)
+	}
+	want := []struct{ op, path, result string }{
+		{"delete", playing, oplog.ResultDeferred},
+		{"delete", idle, oplog.ResultOK},
+		{"rename", keep, oplog.ResultDeferred},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %d log entries, got %+v", len(want), entries)
+	}
+	for i, w := range want {
+		if e := entries[i]; e.Op != w.op || e.OldPath != w.path || e.Result != w.result {
+			t.Errorf("entry %d = %+v, want %s %s %s", i, e, w.op, w.path, w.result)
+		}
+	}
+}
+
+func TestPlayingChecker(t *testing.T) {
+	calls := 0
+	playingErr := error(nil)
+	c := &PlayingChecker{
+		Playing: func() ([]string, error) {
+			calls++
+			return []string{"/media/tv/Firefly/Season 01/Firefly S01E01.mkv"}, playingErr
+		},
+		Fallback: pathSetChecker{"/media/fallback.mkv": true},
+	}
+
+	for path, want := range map[string]bool{
+		"/media/tv/Firefly/Season 01/Firefly S01E01.mkv": true,
+		"/media/tv/Firefly": true, // folder containing a playing file
+		"/media/tv/Firefly/Season 01/Firefly S01E02.mkv": false,
+		"/media/tv/Fire": false,
+	} {
+		if got, err := c.InUse(path); got != want || err != nil {
+			t.Errorf("InUse(%q) = %v, %v; want %v", path, got, err, want)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected sessions queried once within MaxAge, got %d", calls)
+	}
+
+	// An unreachable server falls back to the local check
+	playingErr = errors.New("connection refused")
+	c = &PlayingChecker{Playing: c.Playing, Fallback: c.Fallback}
+	if got, _ := c.InUse("/media/fallback.mkv"); !got {
+		t.Error("Expected fallback checker used when the server fails")
+	}
+}
+
+func TestLsofChecker(t *testing.T) {
+	if _, err := exec.LookPath("lsof"); err != nil {
+		t.Skip("lsof not installed")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "open.mkv")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if inUse, err := (LsofChecker{}).InUse(path); !inUse || err != nil {
+		t.Errorf("Expected open file in use, got %v, %v", inUse, err)
+	}
+	if inUse, err := (LsofChecker{}).InUse(dir); !inUse || err != nil {
+		t.Errorf("Expected folder with an open file in use, got %v, %v", inUse, err)
+	}
+
+	f.Close()
+	if inUse, err := (LsofChecker{}).InUse(path); inUse || err != nil {
+		t.Errorf("Expected closed file not in use, got %v, %v", inUse, err)
+	}
+}
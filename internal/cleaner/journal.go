@@ -0,0 +1,335 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// Journal records the completed operations of one real clean so that
+// `jellysink undo` can replay them in reverse
+type Journal struct {
+	ID        string
+	StartedAt time.Time
+	Entries   []JournalEntry
+	UndoneAt  *time.Time `json:",omitempty"` // set once nothing is left to undo
+
+	path    string
+	saveErr error // first failure to save, reported once the clean finishes
+}
+
+// JournalEntry is one reversible operation: Source was moved to Destination,
+// which is the trash location for deletes
+type JournalEntry struct {
+	Type        string // cleaner operation type, "unlink" for a removed hardlink or "link" for a duplicate replaced with one
+	Source      string
+	Destination string
+	Timestamp   time.Time
+	Undone      bool `json:",omitempty"`
+	Purged      bool `json:",omitempty"` // the trashed file was permanently deleted
+}
+
+// UndoResult summarizes an undo
+type UndoResult struct {
+	JournalID        string
+	Restored         int
+	Errors           []error
+	LibraryRefreshed bool  // a media server library scan was requested
+	RefreshErr       error // why the library scan request failed
+}
+
+// newJournal starts an unsaved journal in dir; the file is written with the first entry
+func newJournal(dir string) *Journal {
+	now := time.Now()
+	id := now.Format("20060102-150405")
+	for n := 2; ; n++ {
+		if _, err := os.Stat(filepath.Join(dir, id+".json")); os.IsNotExist(err) {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", now.Format("20060102-150405"), n)
+	}
+	return &Journal{
+		ID:        id,
+		StartedAt: now,
+		path:      filepath.Join(dir, id+".json"),
+	}
+}
+
+// record appends a completed operation and saves the journal so a crash
+// mid-clean still leaves everything done so far undoable. A nil journal
+// (dry run) records nothing
+func (j *Journal) record(opType, source, destination string) {
+	if j == nil {
+		return
+	}
+	j.Entries = append(j.Entries, JournalEntry{
+		Type:        opType,
+		Source:      source,
+		Destination: destination,
+		Timestamp:   time.Now(),
+	})
+	if err := j.save(); err != nil && j.saveErr == nil {
+		j.saveErr = err
+	}
+}
+
+// last returns the most recently recorded entry
+func (j *Journal) last() (JournalEntry, bool) {
+	if j == nil || len(j.Entries) == 0 {
+		return JournalEntry{}, false
+	}
+	return j.Entries[len(j.Entries)-1], true
+}
+
+// dropLast removes the most recently recorded entry once the operation it
+// records has been reverted, and saves the journal
+func (j *Journal) dropLast() {
+	if j == nil || len(j.Entries) == 0 {
+		return
+	}
+	j.Entries = j.Entries[:len(j.Entries)-1]
+	if err := j.save(); err != nil && j.saveErr == nil {
+		j.saveErr = err
+	}
+}
+
+// recordCompanions records the sidecars that followed a video as opType
+// operations, so undo puts them back too. Sidecars left behind are skipped
+func (j *Journal) recordCompanions(opType string, moves []scanner.CompanionMove) {
+	for _, move := range moves {
+		if _, err := os.Lstat(move.Source); err == nil {
+			continue
+		}
+		if _, err := os.Lstat(move.Target); err != nil {
+			continue
+		}
+		j.record(opType, move.Source, move.Target)
+	}
+}
+
+// save writes the journal atomically
+func (j *Journal) save() error {
+	if err := os.MkdirAll(filepath.Dir(j.path), 0700); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal: %w", err)
+	}
+	tmpPath := j.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace journal: %w", err)
+	}
+	return nil
+}
+
+// Pending returns the number of entries that can still be undone
+func (j *Journal) Pending() int {
+	pending := 0
+	for _, entry := range j.Entries {
+		if !entry.Undone && !entry.Purged {
+			pending++
+		}
+	}
+	return pending
+}
+
+// LoadJournal reads the journal with the given ID from dir
+func LoadJournal(dir, id string) (*Journal, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return nil, fmt.Errorf("invalid journal ID: %q", id)
+	}
+	path := filepath.Join(dir, id+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	var j Journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("failed to parse journal: %w", err)
+	}
+	j.path = path
+	return &j, nil
+}
+
+// ListJournals returns the journals in dir, newest first
+// A missing directory means no clean has been journaled yet
+func ListJournals(dir string) ([]*Journal, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal directory: %w", err)
+	}
+
+	var journals []*Journal
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		j, err := LoadJournal(dir, strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		journals = append(journals, j)
+	}
+	sort.Slice(journals, func(a, b int) bool { return journals[a].StartedAt.After(journals[b].StartedAt) })
+	return journals, nil
+}
+
+// LatestJournal returns the newest journal that still has operations to undo
+func LatestJournal(dir string) (*Journal, error) {
+	journals, err := ListJournals(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, j := range journals {
+		if j.Pending() > 0 {
+			return j, nil
+		}
+	}
+	return nil, fmt.Errorf("no clean to undo")
+}
+
+// Undo restores the operations of journal id in reverse order: trashed
+// files move back, renamed and moved files return to their old names.
+// Entries that cannot be restored are reported and left pending, so a
+// later undo retries just those
+func Undo(config Config, id string) (UndoResult, error) {
+	j, err := LoadJournal(config.JournalDir, id)
+	if err != nil {
+		return UndoResult{JournalID: id}, err
+	}
+	if j.Pending() == 0 {
+		return UndoResult{JournalID: id}, fmt.Errorf("clean %s has already been undone", id)
+	}
+	return undoEntries(config, j, func(JournalEntry) bool { return true }), nil
+}
+
+// undoEntries restores the pending entries of j selected by match, newest
+// first, then saves j and requests a library refresh if anything came back
+func undoEntries(config Config, j *Journal, match func(JournalEntry) bool) UndoResult {
+	result := UndoResult{JournalID: j.ID}
+
+	var logged []oplog.Entry
+	for i := len(j.Entries) - 1; i >= 0; i-- {
+		entry := &j.Entries[i]
+		if entry.Undone || entry.Purged || !match(*entry) {
+			continue
+		}
+		logEntry := oplog.Entry{Timestamp: time.Now(), Op: "undo", OldPath: entry.Destination, NewPath: entry.Source, Detail: entry.Type, Result: oplog.ResultOK}
+		if err := undoEntry(*entry, j.ID); err != nil {
+			result.Errors = append(result.Errors, err)
+			logEntry.Result, logEntry.Error = oplog.ResultFailed, err.Error()
+			logged = append(logged, logEntry)
+			continue
+		}
+		logged = append(logged, logEntry)
+		entry.Undone = true
+		result.Restored++
+	}
+	if err := appendLog(config, logged); err != nil {
+		result.Errors = append(result.Errors, err)
+	}
+
+	if j.Pending() == 0 {
+		now := time.Now()
+		j.UndoneAt = &now
+	}
+	if err := j.save(); err != nil {
+		result.Errors = append(result.Errors, err)
+	}
+
+	if result.Restored > 0 && config.Refresh != nil {
+		if err := config.Refresh.RefreshLibrary(); err != nil {
+			result.RefreshErr = err
+		} else {
+			result.LibraryRefreshed = true
+		}
+	}
+	return result
+}
+
+// undoEntry reverses one journal entry of clean id without overwriting anything
+func undoEntry(entry JournalEntry, id string) error {
+	if entry.Type == "link" {
+		// Source was made a hardlink of Destination; the copy it replaced
+		// comes back with the delete journaled before it
+		source, err := os.Lstat(entry.Source)
+		if err != nil {
+			return fmt.Errorf("cannot restore %s: %w", entry.Source, err)
+		}
+		if keeper, err := os.Stat(entry.Destination); err != nil || !os.SameFile(source, keeper) {
+			return fmt.Errorf("cannot restore %s: no longer a hardlink of %s", entry.Source, entry.Destination)
+		}
+		if err := os.Remove(entry.Source); err != nil {
+			return fmt.Errorf("cannot restore %s: %w", entry.Source, err)
+		}
+		return nil
+	}
+
+	if _, err := os.Lstat(entry.Source); err == nil {
+		return fmt.Errorf("cannot restore %s: path exists", entry.Source)
+	}
+	if _, err := os.Lstat(entry.Destination); err != nil {
+		return fmt.Errorf("cannot restore %s: %w", entry.Source, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(entry.Source), 0755); err != nil {
+		return fmt.Errorf("cannot restore %s: %w", entry.Source, err)
+	}
+
+	if entry.Type == "unlink" {
+		// The source was a hardlink of the file now at Destination
+		if err := os.Link(entry.Destination, entry.Source); err != nil {
+			return fmt.Errorf("cannot restore %s: %w", entry.Source, err)
+		}
+		return nil
+	}
+
+	if err := os.Rename(entry.Destination, entry.Source); err != nil {
+		return fmt.Errorf("cannot restore %s: %w", entry.Source, err)
+	}
+	if isTrashed(entry) {
+		removeEmptyTrashDirs(filepath.Dir(entry.Destination), id)
+	} else {
+		removeEmptyParents(filepath.Dir(entry.Destination), 2)
+	}
+	return nil
+}
+
+// appendLog adds entries to the operation log of config; configs without
+// a log (tests) skip it
+func appendLog(config Config, entries []oplog.Entry) error {
+	if config.LogPath == "" {
+		return nil
+	}
+	if err := oplog.Append(config.LogPath, entries...); err != nil {
+		return fmt.Errorf("failed to write operation log: %w", err)
+	}
+	return nil
+}
+
+// removeEmptyParents removes dir and up to levels-1 of its parents while they are empty
+func removeEmptyParents(dir string, levels int) {
+	for i := 0; i < levels; i++ {
+		if entries, err := os.ReadDir(dir); err != nil || len(entries) > 0 {
+			return
+		}
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
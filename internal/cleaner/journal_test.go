@@ -0,0 +1,284 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestUndoClean(t *testing.T) {
+	tmpDir := t.TempDir()
+	library := filepath.Join(tmpDir, "movies")
+	keeper := filepath.Join(library, "Heat (1995)", "Heat (1995).mkv")
+	extra := filepath.Join(library, "Heat.1995.720p", "heat.mkv")
+	misnamed := filepath.Join(library, "Alien (1979)", "alien.1979.mkv")
+	fixed := filepath.Join(library, "Alien (1979)", "Alien (1979).mkv")
+	orphan := filepath.Join(tmpDir, "tv", "Firefly (2002)")
+	for path, content := range map[string]string{
+		keeper:                                   "keeper",
+		extra:                                    "extra",
+		misnamed:                                 "alien",
+		filepath.Join(orphan, "tvshow.nfo"):      "nfo",
+		filepath.Join(orphan, "Season 01/.keep"): "",
+	} {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+
+	config := DefaultConfig()
+	config.LogPath = filepath.Join(tmpDir, "data", "operations.log")
+	config.JournalDir = filepath.Join(tmpDir, "data", "journal")
+	config.TrashDir = filepath.Join(tmpDir, "data", "trash")
+	config.InUse = nil
+	config.Refresh = nil
+	config.OrphanFolders = []scanner.OrphanFolder{{Path: orphan, Kind: "show"}}
+
+	duplicates := []scanner.MovieDuplicate{{
+		Files: []scanner.MovieFile{{Path: keeper, Size: 6}, {Path: extra, Size: 5}},
+	}}
+	issues := []scanner.ComplianceIssue{{
+		Type: "movie", Path: misnamed, SuggestedPath: fixed, SuggestedAction: "rename",
+	}}
+
+	result, err := Clean(duplicates, nil, issues, config)
+	if err != nil {
+		t.Fatalf("Clean() error: %v", err)
+	}
+	if len(result.Errors) > 0 || result.JournalID == "" {
+		t.Fatalf("Expected a clean journal, got %+v", result)
+	}
+	for _, path := range []string{extra, misnamed, orphan} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("Expected %s to be gone after the clean", path)
+		}
+	}
+	trashed := filepath.Join(config.TrashDir, result.JournalID, strings.TrimPrefix(extra, "/"))
+	if _, err := os.Stat(trashed); err != nil {
+		t.Errorf("Expected the deleted file in the trash: %v", err)
+	}
+
+	latest, err := LatestJournal(config.JournalDir)
+	if err != nil || latest.ID != result.JournalID || latest.Pending() != 3 {
+		t.Fatalf("LatestJournal() = %+v, %v", latest, err)
+	}
+
+	undo, err := Undo(config, result.JournalID)
+	if err != nil {
+		t.Fatalf("Undo() error: %v", err)
+	}
+	if undo.Restored != 3 || len(undo.Errors) > 0 {
+		t.Fatalf("Expected 3 operations restored, got %+v", undo)
+	}
+	logged := make(map[string]int)
+	entries, _ := oplog.Read(config.LogPath)
+	for _, entry := range entries {
+		logged[entry.Op]++
+		if entry.Op == "delete" && entry.Size != 5 {
+			t.Errorf("Expected the delete logged with the file's size, got %+v", entry)
+		}
+	}
+	if logged["delete"] != 1 || logged["undo"] != 3 {
+		t.Errorf("Expected the delete and the 3 undone operations logged, got %v", logged)
+	}
+	for path, want := range map[string]string{extra: "extra", misnamed: "alien", filepath.Join(orphan, "tvshow.nfo"): "nfo"} {
+		if data, err := os.ReadFile(path); err != nil || string(data) != want {
+			t.Errorf("Expected %s restored, got %q, %v", path, data, err)
+		}
+	}
+	if _, err := os.Stat(fixed); !os.IsNotExist(err) {
+		t.Error("Expected the compliance rename reverted")
+	}
+
+	if _, err := Undo(config, result.JournalID); err == nil {
+		t.Error("Expected an error undoing the same clean twice")
+	}
+	if _, err := LatestJournal(config.JournalDir); err == nil {
+		t.Error("Expected no clean left to undo")
+	}
+}
+
+func TestUndoKeepsConflicts(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := filepath.Join(tmpDir, "movies", "old.mkv")
+	os.MkdirAll(filepath.Dir(source), 0755)
+	os.WriteFile(source, []byte("old"), 0644)
+
+	config := DefaultConfig()
+	config.LogPath = filepath.Join(tmpDir, "operations.log")
+	config.JournalDir = filepath.Join(tmpDir, "journal")
+	config.TrashDir = filepath.Join(tmpDir, "trash")
+	config.InUse = nil
+	config.Refresh = nil
+
+	j := newJournal(config.JournalDir)
+	if err := moveToTrash(source, "delete", config, j); err != nil {
+		t.Fatalf("moveToTrash() error: %v", err)
+	}
+
+	// A new file took the old name since the clean
+	os.WriteFile(source, []byte("new"), 0644)
+	result, err := Undo(config, j.ID)
+	if err != nil {
+		t.Fatalf("Undo() error: %v", err)
+	}
+	if result.Restored != 0 || len(result.Errors) != 1 {
+		t.Errorf("Expected the conflicting restore to be refused, got %+v", result)
+	}
+	if data, _ := os.ReadFile(source); string(data) != "new" {
+		t.Error("Undo must never overwrite files")
+	}
+
+	// Once the path is free the pending entry is retried
+	os.Remove(source)
+	if result, err := Undo(config, j.ID); err != nil || result.Restored != 1 {
+		t.Errorf("Expected the retry to restore the file, got %+v, %v", result, err)
+	}
+	if data, _ := os.ReadFile(source); string(data) != "old" {
+		t.Error("Expected the trashed file back")
+	}
+}
+
+func TestUndoRestoresSidecars(t *testing.T) {
+	tmpDir := t.TempDir()
+	misnamed := filepath.Join(tmpDir, "movies", "Alien.1979.720p", "alien.1979.720p.mkv")
+	subtitle := filepath.Join(tmpDir, "movies", "Alien.1979.720p", "alien.1979.720p.en.srt")
+	fixed := filepath.Join(tmpDir, "movies", "Alien (1979)", "Alien (1979).mkv")
+	os.MkdirAll(filepath.Dir(misnamed), 0755)
+	os.WriteFile(misnamed, []byte("alien"), 0644)
+	os.WriteFile(subtitle, []byte("subs"), 0644)
+
+	config := DefaultConfig()
+	config.LogPath = filepath.Join(tmpDir, "operations.log")
+	config.JournalDir = filepath.Join(tmpDir, "journal")
+	config.TrashDir = filepath.Join(tmpDir, "trash")
+	config.InUse = nil
+	config.Refresh = nil
+
+	issues := []scanner.ComplianceIssue{{
+		Type: "movie", Path: misnamed, SuggestedPath: fixed, SuggestedAction: "reorganize",
+	}}
+	result, err := Clean(nil, nil, issues, config)
+	if err != nil || len(result.Errors) > 0 {
+		t.Fatalf("Clean() = %+v, %v", result, err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "movies", "Alien (1979)", "Alien (1979).en.srt")); err != nil {
+		t.Fatalf("Expected the subtitle moved with its video: %v", err)
+	}
+
+	undo, err := Undo(config, result.JournalID)
+	if err != nil || undo.Restored != 2 {
+		t.Fatalf("Expected the video and subtitle restored, got %+v, %v", undo, err)
+	}
+	if data, err := os.ReadFile(subtitle); err != nil || string(data) != "subs" {
+		t.Errorf("Expected the subtitle back, got %q, %v", data, err)
+	}
+}
+
+func TestLoadJournalRejectsPaths(t *testing.T) {
+	for _, id := range []string{"", "../secrets", "a/b"} {
+		if _, err := LoadJournal(t.TempDir(), id); err == nil {
+			t.Errorf("LoadJournal(%q) should be rejected", id)
+		}
+	}
+}
+
+func TestHardlinkDuplicatesUndo(t *testing.T) {
+	tmpDir := t.TempDir()
+	keeper := filepath.Join(tmpDir, "movies", "Heat (1995)", "Heat (1995).mkv")
+	copied := filepath.Join(tmpDir, "movies", "Heat.1995.720p", "heat.mkv")
+	for path, content := range map[string]string{keeper: "keeper", copied: "copy"} {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+
+	config := DefaultConfig()
+	config.LogPath = filepath.Join(tmpDir, "data", "operations.log")
+	config.JournalDir = filepath.Join(tmpDir, "data", "journal")
+	config.TrashDir = filepath.Join(tmpDir, "data", "trash")
+	config.InUse = nil
+	config.Refresh = nil
+	config.HardlinkDuplicates = true
+
+	duplicates := []scanner.MovieDuplicate{{
+		Files: []scanner.MovieFile{{Path: keeper, Size: 6}, {Path: copied, Size: 4}},
+	}}
+	result, err := Clean(duplicates, nil, nil, config)
+	if err != nil {
+		t.Fatalf("Clean() error: %v", err)
+	}
+	if len(result.Errors) > 0 || result.DuplicatesLinked != 1 || result.DuplicatesDeleted != 0 || result.SpaceFreed != 4 {
+		t.Fatalf("Expected the copy replaced with a hardlink, got %+v", result)
+	}
+	keeperInfo, _ := os.Stat(keeper)
+	copyInfo, err := os.Stat(copied)
+	if err != nil || !os.SameFile(keeperInfo, copyInfo) {
+		t.Fatalf("Expected %s to be a hardlink of the keeper, got %v", copied, err)
+	}
+
+	undo, err := Undo(config, result.JournalID)
+	if err != nil {
+		t.Fatalf("Undo() error: %v", err)
+	}
+	if undo.Restored != 2 || len(undo.Errors) > 0 {
+		t.Fatalf("Expected the link and the delete undone, got %+v", undo)
+	}
+	if data, err := os.ReadFile(copied); err != nil || string(data) != "copy" {
+		t.Errorf("Expected the copy restored, got %q, %v", data, err)
+	}
+	if data, err := os.ReadFile(keeper); err != nil || string(data) != "keeper" {
+		t.Errorf("Expected the keeper untouched, got %q, %v", data, err)
+	}
+}
+
+func TestHardlinkDuplicatesLinkFailureKeepsCopy(t *testing.T) {
+	tmpDir := t.TempDir()
+	keeper := filepath.Join(tmpDir, "movies", "Heat (1995)", "Heat (1995).mkv")
+	copied := filepath.Join(tmpDir, "movies", "Heat.1995.720p", "heat.mkv")
+	for path, content := range map[string]string{keeper: "keeper", copied: "copy"} {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+
+	origLink := linkFile
+	defer func() { linkFile = origLink }()
+	linkFile = func(oldname, newname string) error {
+		return &os.LinkError{Op: "link", Old: oldname, New: newname, Err: syscall.EMLINK}
+	}
+
+	config := DefaultConfig()
+	config.LogPath = filepath.Join(tmpDir, "data", "operations.log")
+	config.JournalDir = filepath.Join(tmpDir, "data", "journal")
+	config.TrashDir = filepath.Join(tmpDir, "data", "trash")
+	config.InUse = nil
+	config.Refresh = nil
+	config.HardlinkDuplicates = true
+
+	duplicates := []scanner.MovieDuplicate{{
+		Files: []scanner.MovieFile{{Path: keeper, Size: 6}, {Path: copied, Size: 4}},
+	}}
+	result, err := Clean(duplicates, nil, nil, config)
+	if err != nil {
+		t.Fatalf("Clean() error: %v", err)
+	}
+	if len(result.Errors) != 1 || result.DuplicatesLinked != 0 || result.DuplicatesDeleted != 0 || result.SpaceFreed != 0 {
+		t.Fatalf("Expected one link error and nothing freed, got %+v", result)
+	}
+	if data, err := os.ReadFile(copied); err != nil || string(data) != "copy" {
+		t.Errorf("Expected the copy left in place, got %q, %v", data, err)
+	}
+
+	if result.JournalID != "" {
+		journal, err := LoadJournal(config.JournalDir, result.JournalID)
+		if err != nil {
+			t.Fatalf("LoadJournal() error: %v", err)
+		}
+		if len(journal.Entries) != 0 {
+			t.Errorf("Expected the trash entry dropped, got %+v", journal.Entries)
+		}
+	}
+}
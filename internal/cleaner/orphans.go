@@ -0,0 +1,93 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// removeOrphanFolders moves show and season folders that hold no video
+// files, together with their leftover nfo/artwork, to the trash. Each
+// folder is checked again first so anything that gained episodes since
+// the scan is kept.
+// Returns the number of operations processed
+func removeOrphanFolders(orphans []scanner.OrphanFolder, config Config, journal *Journal, result *CleanResult, pr *scanner.ProgressReporter) int {
+	for _, orphan := range orphans {
+		op := Operation{
+			Type:      "delete-folder",
+			Source:    orphan.Path,
+			Size:      orphan.Size,
+			Timestamp: time.Now(),
+		}
+		if skipIfProtected(op, config, result, pr) {
+			continue
+		}
+		if err := tagRefusal(orphan.Path, config, true); err != nil {
+			result.Errors = append(result.Errors, err)
+			if pr != nil {
+				pr.LogError(err, err.Error())
+			}
+			continue
+		}
+
+		err := checkFolderRemovable(orphan.Path)
+		if err == nil && !config.DryRun {
+			err = moveToTrash(orphan.Path, op.Type, config, journal)
+		}
+		if err != nil {
+			err = fmt.Errorf("cannot delete folder %s: %w", orphan.Path, err)
+			result.Errors = append(result.Errors, err)
+			op.Error = err.Error()
+			if pr != nil {
+				pr.LogError(err, err.Error())
+			}
+		} else {
+			op.Completed = true
+			if !config.DryRun {
+				result.FoldersRemoved++
+				result.SpaceFreed += orphan.Size
+			}
+			if pr != nil {
+				verb := "Deleted orphaned folder"
+				if config.DryRun {
+					verb = "Would delete orphaned folder"
+				}
+				pr.Send(scanner.SeverityInfo, fmt.Sprintf("%s: %s", verb, orphan.Path))
+			}
+		}
+		result.Operations = append(result.Operations, op)
+	}
+	return len(orphans)
+}
+
+// checkFolderRemovable verifies dir is still a folder without video files
+// and that its parent is writable
+func checkFolderRemovable(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("cannot access folder: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("path is not a directory")
+	}
+
+	orphaned, err := scanner.IsOrphanFolder(dir)
+	if err != nil {
+		return fmt.Errorf("cannot read folder: %w", err)
+	}
+	if !orphaned {
+		return fmt.Errorf("folder contains video files again")
+	}
+
+	parentInfo, err := os.Stat(filepath.Dir(dir))
+	if err != nil {
+		return fmt.Errorf("cannot access parent directory: %w", err)
+	}
+	if parentInfo.Mode().Perm()&0200 == 0 {
+		return fmt.Errorf("parent directory not writable (permissions: %o)", parentInfo.Mode().Perm())
+	}
+	return nil
+}
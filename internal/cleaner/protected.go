@@ -0,0 +1,100 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// SkippedProtected is the status of an operation refused because it would
+// delete, rename or move something under a protected path
+const SkippedProtected = "skipped: protected"
+
+// protectedPathsSetting is the [cleaner] protected_paths DefaultConfig adds
+// to the system directories
+var (
+	protectedPathsSetting []string
+	protectedPathsMu      sync.RWMutex
+)
+
+// SetProtectedPaths sets the paths DefaultConfig protects on top of the
+// system directories. A leading ~/ is relative to the home dir
+func SetProtectedPaths(paths []string) {
+	protectedPathsMu.Lock()
+	defer protectedPathsMu.Unlock()
+	protectedPathsSetting = append([]string(nil), paths...)
+}
+
+// configuredProtectedPaths returns the paths set with SetProtectedPaths,
+// expanded against home
+func configuredProtectedPaths(home string) []string {
+	protectedPathsMu.RLock()
+	defer protectedPathsMu.RUnlock()
+
+	paths := make([]string, 0, len(protectedPathsSetting))
+	for _, p := range protectedPathsSetting {
+		if strings.HasPrefix(p, "~/") {
+			p = filepath.Join(home, p[2:])
+		}
+		paths = append(paths, filepath.Clean(p))
+	}
+	return paths
+}
+
+// isProtectedPath reports whether path is, or is inside, a protected path
+func isProtectedPath(path string, protected []string) bool {
+	return protectedBy(path, protected) != ""
+}
+
+// protectedBy returns the protected path that path is, is inside or
+// contains, or "" when none. A folder holding a protected path is protected
+// too, as deleting or moving it would take the protected path with it
+func protectedBy(path string, protected []string) string {
+	if path == "" {
+		return ""
+	}
+	path = filepath.Clean(path)
+	for _, p := range protected {
+		p = filepath.Clean(p)
+		if withinPath(path, p) || withinPath(p, path) {
+			return p
+		}
+	}
+	return ""
+}
+
+// withinPath reports whether path is dir or lies inside it. Paths are
+// compared a whole component at a time so /var does not cover /various
+func withinPath(path, dir string) bool {
+	if path == dir {
+		return true
+	}
+	if !strings.HasSuffix(dir, string(os.PathSeparator)) {
+		dir += string(os.PathSeparator)
+	}
+	return strings.HasPrefix(path, dir)
+}
+
+// skipIfProtected reports whether op reads from or writes to a protected
+// path, recording it as a skipped operation if so. Nothing in a report can
+// override this
+func skipIfProtected(op Operation, config Config, result *CleanResult, pr *scanner.ProgressReporter) bool {
+	p := protectedBy(op.Source, config.ProtectedPaths)
+	if p == "" {
+		p = protectedBy(op.Destination, config.ProtectedPaths)
+	}
+	if p == "" {
+		return false
+	}
+
+	op.Status = SkippedProtected
+	result.Operations = append(result.Operations, op)
+	result.Protected = append(result.Protected, op.Source)
+	if pr != nil {
+		pr.Send(scanner.SeverityWarn, "Skipped (protected path "+p+"): "+op.Source)
+	}
+	return true
+}
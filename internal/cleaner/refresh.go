@@ -0,0 +1,78 @@
+package cleaner
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// LibraryRefresher asks a media server to rescan its libraries after a clean
+type LibraryRefresher interface {
+	RefreshLibrary() error
+}
+
+// RefreshFunc adapts a function to LibraryRefresher
+type RefreshFunc func() error
+
+// RefreshLibrary calls f
+func (f RefreshFunc) RefreshLibrary() error { return f() }
+
+// defaultRefresher is the refresher DefaultConfig installs
+var (
+	defaultRefresher   LibraryRefresher
+	defaultRefresherMu sync.RWMutex
+)
+
+// SetLibraryRefresher sets the refresher used by DefaultConfig (nil disables it)
+func SetLibraryRefresher(r LibraryRefresher) {
+	defaultRefresherMu.Lock()
+	defer defaultRefresherMu.Unlock()
+	defaultRefresher = r
+}
+
+func getLibraryRefresher() LibraryRefresher {
+	defaultRefresherMu.RLock()
+	defer defaultRefresherMu.RUnlock()
+	return defaultRefresher
+}
+
+// refreshLibrary requests a library scan once a real clean changed files
+// A failed request is recorded on the result but does not fail the clean
+func refreshLibrary(config Config, result *CleanResult, pr *scanner.ProgressReporter) {
+	if config.DryRun || config.Refresh == nil || result.DuplicatesDeleted+result.DuplicatesLinked+result.ComplianceFixed+result.VersionsKept+result.FoldersRemoved == 0 {
+		return
+	}
+
+	if err := config.Refresh.RefreshLibrary(); err != nil {
+		result.RefreshErr = err
+		if pr != nil {
+			pr.Send(scanner.SeverityWarn, "Library refresh request failed: "+err.Error())
+		}
+		return
+	}
+	result.LibraryRefreshed = true
+	if pr != nil {
+		pr.Send(scanner.SeverityInfo, "Requested a media server library scan")
+	}
+}
+
+// notifyRenames tells library managers (Sonarr/Radarr) where a real clean
+// moved files. A failure is recorded but does not fail the clean
+func notifyRenames(config Config, result *CleanResult, pr *scanner.ProgressReporter) {
+	if config.DryRun {
+		return
+	}
+	var renames []scanner.RenameResult
+	for _, op := range result.Operations {
+		if op.Completed && op.Destination != "" {
+			renames = append(renames, scanner.RenameResult{OldPath: op.Source, NewPath: op.Destination, Success: true})
+		}
+	}
+	if err := scanner.NotifyRenames(renames); err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("library manager not updated: %w", err))
+		if pr != nil {
+			pr.Send(scanner.SeverityWarn, "Library manager not updated: "+err.Error())
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package cleaner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
+)
+
+// fsTrashDirName is the trash folder created at the top of a filesystem
+// that does not hold TrashDir; hidden so scans skip it
+const fsTrashDirName = ".jellysink-trash"
+
+// trashDirSetting is the [cleaner] trash_dir DefaultConfig resolves
+var (
+	trashDirSetting string
+	trashDirMu      sync.RWMutex
+)
+
+// SetTrashDir sets the trash directory used by DefaultConfig. Empty keeps
+// the trash folder of the data directory; a leading ~/ is relative to the
+// home dir
+func SetTrashDir(dir string) {
+	trashDirMu.Lock()
+	defer trashDirMu.Unlock()
+	trashDirSetting = dir
+}
+
+// resolveTrashDir returns the configured trash directory for home
+func resolveTrashDir(home string) string {
+	trashDirMu.RLock()
+	dir := trashDirSetting
+	trashDirMu.RUnlock()
+
+	switch {
+	case dir == "":
+		return filepath.Join(oplog.DataDir(), oplog.TrashDirName)
+	case strings.HasPrefix(dir, "~/"):
+		return filepath.Join(home, dir[2:])
+	default:
+		return filepath.Clean(dir)
+	}
+}
+
+// TrashItem is a file or folder a clean moved to the trash
+type TrashItem struct {
+	JournalID string
+	Type      string // "delete" or "delete-folder"
+	Original  string // where it was deleted from
+	TrashPath string
+	DeletedAt time.Time
+	Size      int64
+}
+
+// PurgeResult summarizes a permanent deletion from the trash
+type PurgeResult struct {
+	Purged     int
+	SpaceFreed int64
+	Errors     []error
+}
+
+// moveToTrash moves path (a file or folder) into the trash for journal j
+// instead of deleting it, and records the move so it can be undone
+func moveToTrash(path, opType string, config Config, j *Journal) error {
+	target, err := trashPath(path, config.TrashDir, j.ID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		return fmt.Errorf("failed to create trash directory: %w", err)
+	}
+	if err := os.Rename(path, target); err != nil {
+		return fmt.Errorf("failed to move to trash: %w", err)
+	}
+	j.record(opType, path, target)
+	return nil
+}
+
+// trashPath returns where path is kept in the trash. TrashDir is used when
+// it shares path's filesystem, so trashing is a cheap rename; otherwise the
+// trash lives at the top of path's own filesystem
+func trashPath(path, trashDir, journalID string) (string, error) {
+	dev, ok := deviceOf(filepath.Dir(path))
+	if !ok {
+		return "", fmt.Errorf("cannot access %s", filepath.Dir(path))
+	}
+
+	if trashDev, ok := deviceOf(existingAncestor(trashDir)); ok && trashDev == dev {
+		return filepath.Join(trashDir, journalID, strings.TrimPrefix(path, string(filepath.Separator))), nil
+	}
+
+	top := filesystemTop(filepath.Dir(path), dev)
+	rel, err := filepath.Rel(top, path)
+	if err != nil {
+		return "", fmt.Errorf("cannot place %s in the trash: %w", path, err)
+	}
+	return filepath.Join(top, fsTrashDirName, journalID, rel), nil
+}
+
+// deviceOf returns the device ID of the filesystem holding path
+func deviceOf(path string) (uint64, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, false
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(stat.Dev), true
+}
+
+// existingAncestor returns path or its nearest parent that exists
+func existingAncestor(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// filesystemTop walks up from dir to the highest folder on the same device
+func filesystemTop(dir string, dev uint64) string {
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		if parentDev, ok := deviceOf(parent); !ok || parentDev != dev {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// isTrashed reports whether entry moved a file or folder into the trash
+func isTrashed(entry JournalEntry) bool {
+	return entry.Type == "delete" || entry.Type == "delete-folder"
+}
+
+// ListTrash returns what journaled cleans still hold in the trash, newest
+// clean first
+func ListTrash(config Config) ([]TrashItem, error) {
+	journals, err := ListJournals(config.JournalDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []TrashItem
+	for _, j := range journals {
+		for _, entry := range j.Entries {
+			if !isTrashed(entry) || entry.Undone || entry.Purged {
+				continue
+			}
+			if _, err := os.Lstat(entry.Destination); err != nil {
+				continue
+			}
+			items = append(items, TrashItem{
+				JournalID: j.ID,
+				Type:      entry.Type,
+				Original:  entry.Source,
+				TrashPath: entry.Destination,
+				DeletedAt: entry.Timestamp,
+				Size:      pathSize(entry.Destination),
+			})
+		}
+	}
+	return items, nil
+}
+
+// RestoreTrash moves what clean id trashed back to where it was deleted
+// from; with paths given, only items at or under those original paths
+func RestoreTrash(config Config, id string, paths []string) (UndoResult, error) {
+	j, err := LoadJournal(config.JournalDir, id)
+	if err != nil {
+		return UndoResult{JournalID: id}, err
+	}
+
+	match := func(entry JournalEntry) bool {
+		if !isTrashed(entry) {
+			return false
+		}
+		if len(paths) == 0 {
+			return true
+		}
+		for _, path := range paths {
+			path = filepath.Clean(path)
+			if entry.Source == path || strings.HasPrefix(entry.Source, path+string(filepath.Separator)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	found := false
+	for _, entry := range j.Entries {
+		if !entry.Undone && !entry.Purged && match(entry) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return UndoResult{JournalID: id}, fmt.Errorf("nothing from clean %s to restore from the trash", id)
+	}
+	return undoEntries(config, j, match), nil
+}
+
+// PurgeTrash permanently deletes trashed files and folders deleted before cutoff
+func PurgeTrash(config Config, cutoff time.Time) (PurgeResult, error) {
+	return purgeTrash(config, func(_ *Journal, entry JournalEntry) bool {
+		return entry.Timestamp.Before(cutoff)
+	})
+}
+
+// EmptyTrash permanently deletes everything in the trash, or only what
+// clean id trashed when id is set
+func EmptyTrash(config Config, id string) (PurgeResult, error) {
+	if id != "" {
+		if _, err := LoadJournal(config.JournalDir, id); err != nil {
+			return PurgeResult{}, err
+		}
+	}
+	return purgeTrash(config, func(j *Journal, _ JournalEntry) bool {
+		return id == "" || j.ID == id
+	})
+}
+
+// purgeTrash deletes the trashed entries selected by match and marks them
+// purged, so undo skips them
+func purgeTrash(config Config, match func(*Journal, JournalEntry) bool) (PurgeResult, error) {
+	var result PurgeResult
+
+	journals, err := ListJournals(config.JournalDir)
+	if err != nil {
+		return result, err
+	}
+	var logged []oplog.Entry
+	for _, j := range journals {
+		changed := false
+		for i := range j.Entries {
+			entry := &j.Entries[i]
+			if !isTrashed(*entry) || entry.Undone || entry.Purged || !match(j, *entry) {
+				continue
+			}
+
+			// Already gone (emptied by hand) only needs the journal updated
+			if _, err := os.Lstat(entry.Destination); err == nil {
+				size := pathSize(entry.Destination)
+				logEntry := oplog.Entry{Timestamp: time.Now(), Op: "purge", OldPath: entry.Destination, Size: size, Result: oplog.ResultOK}
+				if err := os.RemoveAll(entry.Destination); err != nil {
+					result.Errors = append(result.Errors, fmt.Errorf("failed to purge %s: %w", entry.Destination, err))
+					logEntry.Result, logEntry.Error = oplog.ResultFailed, err.Error()
+					logged = append(logged, logEntry)
+					continue
+				}
+				logged = append(logged, logEntry)
+				removeEmptyTrashDirs(filepath.Dir(entry.Destination), j.ID)
+				result.Purged++
+				result.SpaceFreed += size
+			}
+			entry.Purged = true
+			changed = true
+		}
+		if changed {
+			if err := j.save(); err != nil {
+				result.Errors = append(result.Errors, err)
+			}
+		}
+	}
+	if err := appendLog(config, logged); err != nil {
+		result.Errors = append(result.Errors, err)
+	}
+	return result, nil
+}
+
+// removeEmptyTrashDirs removes the folders left empty in the trash of
+// clean id, from dir up to and including the clean's own folder
+func removeEmptyTrashDirs(dir, id string) {
+	top := dir
+	for filepath.Base(top) != id {
+		parent := filepath.Dir(top)
+		if parent == top {
+			return
+		}
+		top = parent
+	}
+
+	for {
+		if entries, err := os.ReadDir(dir); err != nil || len(entries) > 0 {
+			return
+		}
+		if err := os.Remove(dir); err != nil || dir == top {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// pathSize returns the size of a file, or of everything under a folder
+func pathSize(path string) int64 {
+	var size int64
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
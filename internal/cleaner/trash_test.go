@@ -0,0 +1,134 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
+)
+
+func TestTrashListPurgeAndRestore(t *testing.T) {
+	tmpDir := t.TempDir()
+	old := filepath.Join(tmpDir, "movies", "Heat.1995.720p", "heat.mkv")
+	recent := filepath.Join(tmpDir, "movies", "Alien.1979.DVDRip", "alien.mkv")
+	for _, path := range []string{old, recent} {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte("video"), 0644)
+	}
+
+	config := DefaultConfig()
+	config.JournalDir = filepath.Join(tmpDir, "journal")
+	config.TrashDir = filepath.Join(tmpDir, "trash")
+	config.LogPath = filepath.Join(tmpDir, "operations.log")
+	config.Refresh = nil
+
+	j := newJournal(config.JournalDir)
+	for _, path := range []string{old, recent} {
+		if err := moveToTrash(path, "delete", config, j); err != nil {
+			t.Fatalf("moveToTrash() error: %v", err)
+		}
+	}
+	j.Entries[0].Timestamp = time.Now().AddDate(0, 0, -20)
+	j.save()
+
+	items, err := ListTrash(config)
+	if err != nil || len(items) != 2 {
+		t.Fatalf("ListTrash() = %+v, %v; want 2 items", items, err)
+	}
+	if items[0].Original != old || items[0].Size != 5 {
+		t.Errorf("Unexpected trash item: %+v", items[0])
+	}
+
+	// Only trash past the retention window is purged
+	purged, err := PurgeTrash(config, time.Now().AddDate(0, 0, -14))
+	if err != nil || purged.Purged != 1 || purged.SpaceFreed != 5 {
+		t.Fatalf("PurgeTrash() = %+v, %v; want the old item purged", purged, err)
+	}
+	if _, err := os.Stat(items[0].TrashPath); !os.IsNotExist(err) {
+		t.Error("Expected the old item gone from the trash")
+	}
+	if _, err := os.Stat(items[1].TrashPath); err != nil {
+		t.Error("Expected the recent item kept in the trash")
+	}
+	entries, err := oplog.Read(config.LogPath)
+	if err != nil || len(entries) != 1 || entries[0].Op != "purge" || entries[0].Size != 5 {
+		t.Errorf("Expected the purge logged with its size, got %+v, %v", entries, err)
+	}
+
+	// Purged entries are no longer undoable
+	loaded, _ := LoadJournal(config.JournalDir, j.ID)
+	if loaded.Pending() != 1 {
+		t.Errorf("Expected 1 entry left to undo, got %d", loaded.Pending())
+	}
+
+	if _, err := RestoreTrash(config, j.ID, []string{filepath.Join(tmpDir, "tv")}); err == nil {
+		t.Error("Expected an error restoring a path the clean never trashed")
+	}
+	result, err := RestoreTrash(config, j.ID, []string{filepath.Dir(recent)})
+	if err != nil || result.Restored != 1 {
+		t.Fatalf("RestoreTrash() = %+v, %v", result, err)
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Error("Expected the recent file restored")
+	}
+	if _, err := os.Stat(filepath.Join(config.TrashDir, j.ID)); !os.IsNotExist(err) {
+		t.Error("Expected the clean's trash folder removed once empty")
+	}
+	if items, _ := ListTrash(config); len(items) != 0 {
+		t.Errorf("Expected an empty trash, got %+v", items)
+	}
+}
+
+func TestEmptyTrashByClean(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := DefaultConfig()
+	config.JournalDir = filepath.Join(tmpDir, "journal")
+	config.TrashDir = filepath.Join(tmpDir, "trash")
+	config.LogPath = filepath.Join(tmpDir, "operations.log")
+
+	var journals []*Journal
+	for _, name := range []string{"one.mkv", "two.mkv"} {
+		path := filepath.Join(tmpDir, "movies", name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(name), 0644)
+
+		j := newJournal(config.JournalDir)
+		j.ID += "-" + name[:3]
+		j.path = filepath.Join(config.JournalDir, j.ID+".json")
+		if err := moveToTrash(path, "delete", config, j); err != nil {
+			t.Fatalf("moveToTrash() error: %v", err)
+		}
+		journals = append(journals, j)
+	}
+
+	if _, err := EmptyTrash(config, "missing"); err == nil {
+		t.Error("Expected an error emptying an unknown clean")
+	}
+	result, err := EmptyTrash(config, journals[0].ID)
+	if err != nil || result.Purged != 1 {
+		t.Fatalf("EmptyTrash() = %+v, %v", result, err)
+	}
+	items, _ := ListTrash(config)
+	if len(items) != 1 || items[0].JournalID != journals[1].ID {
+		t.Errorf("Expected only the other clean's trash left, got %+v", items)
+	}
+
+	if result, _ := EmptyTrash(config, ""); result.Purged != 1 {
+		t.Errorf("Expected the rest of the trash emptied, got %+v", result)
+	}
+}
+
+func TestSetTrashDir(t *testing.T) {
+	defer SetTrashDir("")
+
+	SetTrashDir("~/media-trash")
+	if got := resolveTrashDir("/home/user"); got != "/home/user/media-trash" {
+		t.Errorf("resolveTrashDir() = %s", got)
+	}
+	SetTrashDir("/srv/trash/")
+	if got := DefaultConfig().TrashDir; got != "/srv/trash" {
+		t.Errorf("DefaultConfig().TrashDir = %s", got)
+	}
+}
@@ -0,0 +1,77 @@
+package cleaner
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// mergeVersions renames every copy in a multi-version group into the
+// keeper's folder as a Jellyfin version instead of deleting the extras.
+// Returns the number of operations processed
+func mergeVersions(dup scanner.MovieDuplicate, config Config, journal *Journal, result *CleanResult, pr *scanner.ProgressReporter) int {
+	renames, err := scanner.MultiVersionPlan(dup)
+	if err != nil {
+		err = fmt.Errorf("cannot keep versions of %s: %w", dup.Files[0].Path, err)
+		result.Errors = append(result.Errors, err)
+		if pr != nil {
+			pr.LogError(err, err.Error())
+		}
+		return len(dup.Files) - 1
+	}
+
+	for _, r := range renames {
+		op := Operation{
+			Type:        "version",
+			Source:      r.Source,
+			Destination: r.Target,
+			Timestamp:   time.Now(),
+		}
+		if skipIfProtected(op, config, result, pr) {
+			continue
+		}
+		if err := tagRefusal(r.Source, config, false); err != nil {
+			result.Errors = append(result.Errors, err)
+			if pr != nil {
+				pr.LogError(err, err.Error())
+			}
+			continue
+		}
+
+		if deferIfImporting(op, result, pr) || deferIfInUse(op, config, result, pr) {
+			continue
+		}
+
+		companions := scanner.CompanionMoves(r.Source, r.Target)
+		if !config.DryRun {
+			err = scanner.ApplyVersionRename(r)
+		} else {
+			err = checkRenameAccessible(r.Source, r.Target)
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+			op.Error = err.Error()
+			if pr != nil {
+				pr.LogError(err, fmt.Sprintf("Failed to keep version: %s", r.Source))
+			}
+		} else {
+			op.Completed = true
+			if !config.DryRun {
+				result.VersionsKept++
+				journal.record(op.Type, r.Source, r.Target)
+				journal.recordCompanions(op.Type, companions)
+			}
+			if pr != nil {
+				verb := "Kept as version"
+				if config.DryRun {
+					verb = "Would keep as version"
+				}
+				pr.Send(scanner.SeverityInfo, fmt.Sprintf("%s: %s -> %s", verb, r.Source, filepath.Base(r.Target)))
+			}
+		}
+		result.Operations = append(result.Operations, op)
+	}
+	return len(dup.Files) - 1
+}
@@ -2,46 +2,264 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
+	"golang.org/x/text/language"
+
+	"github.com/Nomadcxx/jellysink/internal/calendar"
+	"github.com/Nomadcxx/jellysink/internal/ignore"
+	"github.com/Nomadcxx/jellysink/internal/notify"
 )
 
 // Config holds all jellysink configuration
 type Config struct {
-	Libraries LibraryConfig `toml:"libraries"`
-	Daemon    DaemonConfig  `toml:"daemon"`
-	API       APIConfig     `toml:"api"`
+	DataDir     string            `toml:"data_dir"` // reports, caches, journal and trash; empty = $XDG_DATA_HOME/jellysink or ~/.local/share/jellysink (JELLYSINK_DATA_DIR overrides)
+	Libraries   LibraryConfig     `toml:"libraries"`
+	Scan        ScanConfig        `toml:"scan"`
+	Languages   LanguagesConfig   `toml:"languages"`
+	Daemon      DaemonConfig      `toml:"daemon"`
+	AutoClean   AutoCleanConfig   `toml:"autoclean"`
+	API         APIConfig         `toml:"api"`
+	Progress    ProgressConfig    `toml:"progress"`
+	Reports     ReportsConfig     `toml:"reports"`
+	Naming      NamingConfig      `toml:"naming"`
+	UI          UIConfig          `toml:"ui"`
+	Jellyfin    JellyfinConfig    `toml:"jellyfin"`
+	Sonarr      ArrConfig         `toml:"sonarr"`
+	Radarr      ArrConfig         `toml:"radarr"`
+	Tags        TagsConfig        `toml:"tags"`
+	Duplicates  DuplicatesConfig  `toml:"duplicates"`
+	Cleaner     CleanerConfig     `toml:"cleaner"`
+	Performance PerformanceConfig `toml:"performance"`
+	Notify      NotifyConfig      `toml:"notify"`
+
+	Only []string `toml:"-"` // set by ForLibraries: the libraries this copy scans
 }
 
 // LibraryConfig defines media library paths
 type LibraryConfig struct {
-	Movies MovieLibrary `toml:"movies"`
-	TV     TVLibrary    `toml:"tv"`
+	Movies      MovieLibrary   `toml:"movies"`
+	TV          TVLibrary      `toml:"tv"`
+	Anime       AnimeLibrary   `toml:"anime"`
+	Named       []NamedLibrary `toml:"library"`      // further libraries, each [[libraries.library]] with a name and type
+	ExcludeDirs []string       `toml:"exclude_dirs"` // folder names skipped during walks; unset = NAS/system defaults, [] = none
 }
 
 // MovieLibrary holds movie library paths
 type MovieLibrary struct {
-	Paths []string `toml:"paths"`
+	Paths          []string         `toml:"paths"`
+	DuplicateScope string           `toml:"duplicate_scope"` // overrides [duplicates] scope for movies; empty = use it
+	Overrides      LibraryOverrides `toml:"overrides"`
 }
 
 // TVLibrary holds TV show library paths
 type TVLibrary struct {
-	Paths []string `toml:"paths"`
+	Paths          []string         `toml:"paths"`
+	DuplicateScope string           `toml:"duplicate_scope"` // overrides [duplicates] scope for TV; empty = use it
+	Overrides      LibraryOverrides `toml:"overrides"`
+}
+
+// AnimeLibrary holds anime library paths, scanned as TV shows with anime
+// naming: absolute episode numbers, [Group] prefixes and CRC suffixes.
+// Duplicates follow the TV duplicate scope
+type AnimeLibrary struct {
+	Paths     []string         `toml:"paths"`
+	Lookup    string           `toml:"lookup"` // "anilist" checks series titles and years against AniList; empty = off
+	Overrides LibraryOverrides `toml:"overrides"`
+}
+
+// ScanConfig sets which paths scans skip and cleans never touch, besides
+// libraries.exclude_dirs and .jellysinkignore files
+type ScanConfig struct {
+	Exclude              []string `toml:"exclude"`                // globs ("**/Extras/**", "*.iso") or "re:" regular expressions matched against full paths
+	AllowVersions        bool     `toml:"allow_versions"`         // copies of a movie in another edition or resolution are Jellyfin versions, not duplicates
+	BitrateOutlierFactor float64  `toml:"bitrate_outlier_factor"` // report episodes this many times larger or smaller per minute than their season's median; 0 = off
+}
+
+// LanguagesConfig lists the audio and subtitle languages files should
+// carry; scans with ffprobe report files that don't as library quality
+// findings. Empty lists and 0 leave a check off
+type LanguagesConfig struct {
+	RequiredAudio     []string `toml:"required_audio"`      // ISO 639 codes ("eng", "en"); report files with no audio track in any of them
+	RequiredSubtitles []string `toml:"required_subtitles"`  // report files with no subtitle track in any of these
+	Wanted            []string `toml:"wanted"`              // other languages worth keeping besides the required ones
+	MaxUnwantedTracks int      `toml:"max_unwanted_tracks"` // report files with more audio and subtitle tracks in other languages than this
 }
 
 // DaemonConfig holds daemon scheduling and behavior settings
 type DaemonConfig struct {
-	ScanFrequency    string `toml:"scan_frequency"`     // daily, weekly, biweekly
-	ReportOnComplete bool   `toml:"report_on_complete"` // launch TUI on scan complete
-	LogLevel         string `toml:"log_level"`          // quiet, normal, verbose
+	ScanFrequency         string   `toml:"scan_frequency"`           // daily, weekly, biweekly
+	ScanTime              string   `toml:"scan_time"`                // HH:MM local time scans start in jellysinkd --daemon
+	ScanSchedule          string   `toml:"scan_schedule"`            // cron expression or OnCalendar string ("Mon,Thu 03:30"); overrides scan_frequency and scan_time
+	ReportOnComplete      bool     `toml:"report_on_complete"`       // launch TUI on scan complete
+	TerminalCommand       string   `toml:"terminal_command"`         // opens the report for review, e.g. "alacritty -e {command}"; empty = first of kitty, alacritty, gnome-terminal, konsole, xterm
+	LogLevel              string   `toml:"log_level"`                // quiet, normal, verbose
+	AutoCleanSeverities   []string `toml:"auto_clean_severities"`    // compliance severities auto-clean may fix (info, warn, error)
+	AutoCleanConfirmScans int      `toml:"auto_clean_confirm_scans"` // scans in a row a duplicate group must appear in unchanged before auto-clean deletes it
+	HTTPAddr              string   `toml:"http_addr"`                // jellysinkd --daemon status/control API, e.g. 127.0.0.1:8787; empty = off
+	HTTPToken             string   `toml:"http_token"`               // bearer token the API requires; empty = none
+	WatchDebounce         int      `toml:"watch_debounce"`           // seconds without changes before jellysinkd --watch processes them
+	WatchAutoFix          bool     `toml:"watch_auto_fix"`           // jellysinkd --watch fixes compliance of new files (auto_clean_severities apply)
+	DeferWhileStreaming   bool     `toml:"defer_while_streaming"`    // hold scheduled scans and auto-cleans while anyone streams from Jellyfin (needs jellyfin url and api_key)
+	StreamingIdleMinutes  int      `toml:"streaming_idle_minutes"`   // minutes Jellyfin must stay idle before held work starts
+}
+
+// AutoCleanConfig limits what jellysinkd's unattended auto-clean may do,
+// on top of daemon.auto_clean_severities and auto_clean_confirm_scans.
+// Zero values leave a rule off
+type AutoCleanConfig struct {
+	IdenticalOnly bool    `toml:"identical_only"` // delete a duplicate only when its content matches the kept copy byte for byte
+	MinConfidence float64 `toml:"min_confidence"` // fix only compliance issues whose suggested name is at least this confident (0-1)
+	MinAgeDays    int     `toml:"min_age_days"`   // never touch files modified within this many days
+	MaxDeleteGB   float64 `toml:"max_delete_gb"`  // stop deleting duplicates once a run would free more than this
+}
+
+// ProgressConfig sets the minimum progress message severity for each output channel
+// Severities: debug, info, warn, error, critical
+type ProgressConfig struct {
+	CLIMinSeverity    string `toml:"cli_min_severity"`    // `jellysink scan` output (overridden by --quiet/--verbose)
+	TUIMinSeverity    string `toml:"tui_min_severity"`    // TUI scan log
+	DaemonMinSeverity string `toml:"daemon_min_severity"` // jellysinkd output (journal)
+}
+
+// ReportsConfig controls where scan reports are written and how they are named
+type ReportsConfig struct {
+	Dir              string `toml:"dir"`               // empty = scan_results in the data dir; may be a mounted network share
+	FilenameTemplate string `toml:"filename_template"` // placeholders: {timestamp}, {date}, {time}, {library}, {host}
+}
+
+// NamingConfig selects the media server naming convention
+type NamingConfig struct {
+	Profile           string   `toml:"profile"`             // jellyfin or emby
+	LowercaseWords    []string `toml:"lowercase_words"`     // words kept lowercase mid-title; unset = articles/short prepositions, [] = none
+	EpisodeTitles     bool     `toml:"episode_titles"`      // append TVDB episode titles to suggested episode filenames (needs api.tvdb)
+	ShowFolderYears   bool     `toml:"show_folder_years"`   // suggest adding the first-air year to show folders without "(Year)" (needs api.tvdb or api.tmdb)
+	AutoResolveMargin float64  `toml:"auto_resolve_margin"` // resolve folder/filename title conflicts without review when one title's confidence leads by more than this and an API matched it; 0 = always review
+}
+
+// UIConfig sets how reports list titles in the TUI and exports
+type UIConfig struct {
+	SortLocale          string `toml:"sort_locale"`           // BCP 47 language tag for collating titles, e.g. "en", "de", "sv"; empty = byte order
+	IgnoreArticles      bool   `toml:"ignore_articles"`       // sort "The Matrix" under M (leading The/A/An)
+	ShowBanner          bool   `toml:"show_banner"`           // ASCII banner atop TUI screens 100+ columns wide; false = one-line title everywhere
+	FirstRunWalkthrough bool   `toml:"first_run_walkthrough"` // until the first clean, the TUI only dry-runs, then walks through the results; false unlocks full cleans
 }
 
 // APIConfig holds API keys for metadata services
 type APIConfig struct {
-	TVDB TVDBConfig `toml:"tvdb"`
-	OMDB OMDBConfig `toml:"omdb"`
+	TVDB             TVDBConfig `toml:"tvdb"`
+	OMDB             OMDBConfig `toml:"omdb"`
+	TMDB             TMDBConfig `toml:"tmdb"`              // last TV fallback; also verifies movie titles Radarr does not manage
+	FailureThreshold int        `toml:"failure_threshold"` // consecutive unreachable-API failures before a provider is skipped for the scan
+	CacheTTLDays     int        `toml:"cache_ttl_days"`    // days a verified lookup is reused across runs (api_cache.json in the data dir); 0 = this run only
+	ProxyURL         string     `toml:"proxy_url"`         // http://, https:// or socks5://; empty = HTTP(S)_PROXY env vars
+	CABundle         string     `toml:"ca_bundle"`         // extra PEM CA certificates, e.g. for a TLS-intercepting proxy
+}
+
+// JellyfinConfig connects jellysink to a Jellyfin server
+type JellyfinConfig struct {
+	URL               string   `toml:"url"`                 // e.g. http://localhost:8096
+	APIKey            string   `toml:"api_key"`             // Dashboard > API Keys
+	Compare           bool     `toml:"compare"`             // compare scans against the items Jellyfin knows about
+	PathMappings      []string `toml:"path_mappings"`       // "server_prefix=local_prefix" when Jellyfin sees the library under other paths
+	RefreshAfterClean bool     `toml:"refresh_after_clean"` // ask Jellyfin to rescan its libraries once a clean changes files
+}
+
+// ArrConfig connects jellysink to Sonarr (shows) or Radarr (movies), whose
+// names take precedence over TVDB/OMDB lookups
+type ArrConfig struct {
+	URL               string   `toml:"url"`                 // e.g. http://localhost:8989 (Sonarr) or http://localhost:7878 (Radarr)
+	APIKey            string   `toml:"api_key"`             // Settings > General > Security
+	PathMappings      []string `toml:"path_mappings"`       // "arr_prefix=local_prefix" when it sees the library under other paths
+	RescanAfterRename bool     `toml:"rescan_after_rename"` // point it at folders jellysink renamed and ask it to rescan them
+}
+
+// TagsConfig names the item tags that change how cleans treat a file
+type TagsConfig struct {
+	Keep      []string `toml:"keep"`      // tagged files win the keeper slot of their duplicate group and are never deleted
+	Protected []string `toml:"protected"` // tagged files and folders are never deleted, renamed or moved
+}
+
+// DuplicatesConfig sets how cleans resolve movie duplicate groups
+type DuplicatesConfig struct {
+	Strategy        string `toml:"strategy"`         // delete (keep the best copy) or multi-version (keep all as Jellyfin versions)
+	Scope           string `toml:"scope"`            // where copies are matched: same-folder, library or cross-library (every path of the library)
+	FFprobe         string `toml:"ffprobe"`          // ffprobe binary for ranking copies by their streams; empty = look up on PATH, "off" = filename only
+	MergeCompanions bool   `toml:"merge_companions"` // move subtitles and artwork the keeper lacks out of deleted copies' folders; groups can opt out in the TUI
+}
+
+// CleanerConfig sets where cleans put deleted files, how long they stay and
+// what cleans never touch
+type CleanerConfig struct {
+	TrashDir           string   `toml:"trash_dir"`           // empty = trash in the data dir; files on other filesystems use a .jellysink-trash folder there
+	RetentionDays      int      `toml:"retention_days"`      // daemon runs purge trash older than this; 0 keeps it until emptied
+	ProtectedPaths     []string `toml:"protected_paths"`     // never deleted, renamed or moved, whatever a report suggests; scans still see them
+	ConfirmPhrase      string   `toml:"confirm_phrase"`      // typed to confirm cleans, undos and trash empties; empty = "yes"
+	HardlinkDuplicates bool     `toml:"hardlink_duplicates"` // replace duplicates with hardlinks to the keeper instead of deleting them
+}
+
+// PerformanceConfig trades scan speed for a smaller memory footprint and
+// gentler use of cloud-backed mounts
+type PerformanceConfig struct {
+	LowMemory     bool `toml:"low_memory"`      // for 512MB-1GB NAS boxes: fewer workers, capped logs, no Jellyfin compare, streamed report JSON
+	CloudSafe     bool `toml:"cloud_safe"`      // libraries on rclone/s3fs/gcsfuse mounts: no ffprobe reads, paced listings, batched renames
+	CloudListRate int  `toml:"cloud_list_rate"` // folder listings per second scans make on a cloud mount
+}
+
+// NotifyConfig sets how jellysinkd tells you a scan finished
+type NotifyConfig struct {
+	Email    EmailConfig    `toml:"email"`
+	Webhook  WebhookConfig  `toml:"webhook"`
+	Discord  DiscordConfig  `toml:"discord"`
+	Telegram TelegramConfig `toml:"telegram"`
+}
+
+// WebhookConfig POSTs each jellysinkd event as JSON; empty url = off
+type WebhookConfig struct {
+	URL      string   `toml:"url"`
+	Events   []string `toml:"events"`   // scan, clean and/or error; empty = all
+	Template string   `toml:"template"` // Go template for the message text; empty = built-in summary
+}
+
+// DiscordConfig posts each jellysinkd event to a Discord channel webhook;
+// empty webhook_url = off
+type DiscordConfig struct {
+	WebhookURL string   `toml:"webhook_url"`
+	Events     []string `toml:"events"`
+	Template   string   `toml:"template"`
+}
+
+// TelegramConfig sends each jellysinkd event to a Telegram chat through a
+// bot; empty bot_token = off
+type TelegramConfig struct {
+	BotToken string   `toml:"bot_token"` // from @BotFather
+	ChatID   string   `toml:"chat_id"`   // numeric chat ID, or @channelname
+	Events   []string `toml:"events"`
+	Template string   `toml:"template"`
+}
+
+// EmailConfig sends a summary email after each jellysinkd scan; empty host
+// = off
+type EmailConfig struct {
+	Host     string   `toml:"host"`     // SMTP server, e.g. smtp.example.com
+	Port     int      `toml:"port"`     // 587 (STARTTLS) or 465 (implicit TLS)
+	TLS      string   `toml:"tls"`      // starttls (required: servers without it are refused), tls (implicit) or none
+	Username string   `toml:"username"` // empty = no auth
+	Password string   `toml:"password"`
+	From     string   `toml:"from"` // empty = username, or jellysink@<hostname>
+	To       []string `toml:"to"`   // recipients
+}
+
+// Enabled reports whether scan summaries are emailed
+func (e EmailConfig) Enabled() bool {
+	return e.Host != ""
 }
 
 // TVDBConfig holds TVDB API configuration
@@ -56,6 +274,12 @@ type OMDBConfig struct {
 	Enabled bool   `toml:"enabled"`
 }
 
+// TMDBConfig holds TMDB API configuration
+type TMDBConfig struct {
+	APIKey  string `toml:"api_key"` // v3 API key or v4 read access token
+	Enabled bool   `toml:"enabled"`
+}
+
 // DefaultConfig returns a config with sensible defaults
 func DefaultConfig() *Config {
 	return &Config{
@@ -68,9 +292,26 @@ func DefaultConfig() *Config {
 			},
 		},
 		Daemon: DaemonConfig{
-			ScanFrequency:    "weekly",
-			ReportOnComplete: true,
-			LogLevel:         "normal",
+			ScanFrequency:         "weekly",
+			ScanTime:              "02:00",
+			ReportOnComplete:      true,
+			LogLevel:              "normal",
+			AutoCleanSeverities:   []string{"info", "warn", "error"},
+			AutoCleanConfirmScans: 2,
+			WatchDebounce:         30,
+			StreamingIdleMinutes:  10,
+		},
+		Progress: ProgressConfig{
+			CLIMinSeverity:    "info",
+			TUIMinSeverity:    "info",
+			DaemonMinSeverity: "warn",
+		},
+		Reports: ReportsConfig{
+			Dir:              "",
+			FilenameTemplate: DefaultReportFilenameTemplate,
+		},
+		Naming: NamingConfig{
+			Profile: "jellyfin",
 		},
 		API: APIConfig{
 			TVDB: TVDBConfig{
@@ -81,6 +322,48 @@ func DefaultConfig() *Config {
 				APIKey:  "",
 				Enabled: false,
 			},
+			TMDB: TMDBConfig{
+				APIKey:  "",
+				Enabled: false,
+			},
+			FailureThreshold: 3,
+			CacheTTLDays:     30,
+		},
+		Jellyfin: JellyfinConfig{
+			RefreshAfterClean: true,
+		},
+		Sonarr: ArrConfig{
+			RescanAfterRename: true,
+		},
+		Radarr: ArrConfig{
+			RescanAfterRename: true,
+		},
+		Tags: TagsConfig{
+			Keep:      []string{"keep", "keep-4k"},
+			Protected: []string{"never-touch"},
+		},
+		Duplicates: DuplicatesConfig{
+			Strategy:        "delete",
+			Scope:           "cross-library",
+			MergeCompanions: true,
+		},
+		Cleaner: CleanerConfig{
+			RetentionDays: 14,
+			ConfirmPhrase: "yes",
+		},
+		Performance: PerformanceConfig{
+			CloudSafe:     true,
+			CloudListRate: 4,
+		},
+		UI: UIConfig{
+			ShowBanner:          true,
+			FirstRunWalkthrough: true,
+		},
+		Notify: NotifyConfig{
+			Email: EmailConfig{
+				Port: 587,
+				TLS:  "starttls",
+			},
 		},
 	}
 }
@@ -143,13 +426,14 @@ func Load() (*Config, error) {
 		return cfg, nil
 	}
 
-	// Load existing config
-	var cfg Config
-	if _, err := toml.DecodeFile(configFile, &cfg); err != nil {
+	// Load existing config on top of defaults so keys added in newer
+	// versions get sensible values
+	cfg := DefaultConfig()
+	if _, err := toml.DecodeFile(configFile, cfg); err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	return &cfg, nil
+	return cfg, nil
 }
 
 // Save writes the config to disk
@@ -182,6 +466,11 @@ func Save(cfg *Config) error {
 
 // Validate checks if the config is valid
 func (c *Config) Validate() error {
+	// Check the data directory (empty uses the default)
+	if c.DataDir != "" && !filepath.IsAbs(c.DataDir) && !strings.HasPrefix(c.DataDir, "~/") {
+		return fmt.Errorf("invalid data_dir: %s (must be an absolute path or start with ~/)", c.DataDir)
+	}
+
 	// Check scan frequency
 	validFrequencies := map[string]bool{
 		"daily":    true,
@@ -193,14 +482,332 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid scan frequency: %s (must be daily, weekly, or biweekly)", c.Daemon.ScanFrequency)
 	}
 
+	// Check scan time (empty uses 02:00)
+	if c.Daemon.ScanTime != "" {
+		if _, err := time.Parse("15:04", c.Daemon.ScanTime); err != nil {
+			return fmt.Errorf("invalid scan time: %s (must be HH:MM, 24-hour)", c.Daemon.ScanTime)
+		}
+	}
+
+	// Check the custom schedule (empty uses scan_frequency and scan_time)
+	if c.Daemon.ScanSchedule != "" {
+		if _, err := calendar.Parse(c.Daemon.ScanSchedule); err != nil {
+			return fmt.Errorf("invalid scan_schedule: %w", err)
+		}
+	}
+
+	// Check API bind address (empty keeps the API off)
+	if c.Daemon.HTTPAddr != "" {
+		if _, port, err := net.SplitHostPort(c.Daemon.HTTPAddr); err != nil || port == "" {
+			return fmt.Errorf("invalid daemon http_addr: %s (must be host:port, e.g. 127.0.0.1:8787)", c.Daemon.HTTPAddr)
+		}
+	}
+
+	// Check watch debounce
+	if c.Daemon.WatchDebounce < 1 {
+		return fmt.Errorf("invalid daemon watch_debounce: %d (must be at least 1 second)", c.Daemon.WatchDebounce)
+	}
+
+	// Check streaming deferral (it asks Jellyfin for active sessions)
+	if c.Daemon.StreamingIdleMinutes < 0 {
+		return fmt.Errorf("invalid daemon streaming_idle_minutes: %d (must be 0 or more)", c.Daemon.StreamingIdleMinutes)
+	}
+	if c.Daemon.DeferWhileStreaming && (c.Jellyfin.URL == "" || c.Jellyfin.APIKey == "") {
+		return fmt.Errorf("daemon defer_while_streaming requires jellyfin url and api_key")
+	}
+
+	// Check auto-clean severities
+	validSeverities := map[string]bool{
+		"info":  true,
+		"warn":  true,
+		"error": true,
+	}
+	for _, sev := range c.Daemon.AutoCleanSeverities {
+		if !validSeverities[sev] {
+			return fmt.Errorf("invalid auto-clean severity: %s (must be info, warn, or error)", sev)
+		}
+	}
+
+	// Check auto-clean confirmation (1 deletes duplicates on first sight)
+	if c.Daemon.AutoCleanConfirmScans < 1 {
+		return fmt.Errorf("invalid daemon auto_clean_confirm_scans: %d (must be at least 1)", c.Daemon.AutoCleanConfirmScans)
+	}
+
+	// Check the auto-clean policy
+	if mc := c.AutoClean.MinConfidence; mc < 0 || mc > 1 {
+		return fmt.Errorf("invalid autoclean min_confidence: %g (must be 0-1)", mc)
+	}
+	if c.AutoClean.MinAgeDays < 0 {
+		return fmt.Errorf("invalid autoclean min_age_days: %d (must be 0 or more)", c.AutoClean.MinAgeDays)
+	}
+	if c.AutoClean.MaxDeleteGB < 0 {
+		return fmt.Errorf("invalid autoclean max_delete_gb: %g (must be 0 or more)", c.AutoClean.MaxDeleteGB)
+	}
+
+	// Check the language rules
+	for _, list := range []struct {
+		key   string
+		codes []string
+	}{
+		{"required_audio", c.Languages.RequiredAudio},
+		{"required_subtitles", c.Languages.RequiredSubtitles},
+		{"wanted", c.Languages.Wanted},
+	} {
+		for _, code := range list.codes {
+			if !validLanguageCode(code) {
+				return fmt.Errorf("invalid languages %s entry: %q (must be a 2- or 3-letter ISO 639 code)", list.key, code)
+			}
+		}
+	}
+	if c.Languages.MaxUnwantedTracks < 0 {
+		return fmt.Errorf("invalid languages max_unwanted_tracks: %d (must be 0 or more)", c.Languages.MaxUnwantedTracks)
+	}
+
+	// Check per-channel progress severities (empty uses the channel default)
+	validProgressSeverities := map[string]bool{
+		"debug":    true,
+		"info":     true,
+		"warn":     true,
+		"error":    true,
+		"critical": true,
+	}
+	for name, sev := range map[string]string{
+		"cli_min_severity":    c.Progress.CLIMinSeverity,
+		"tui_min_severity":    c.Progress.TUIMinSeverity,
+		"daemon_min_severity": c.Progress.DaemonMinSeverity,
+	} {
+		if sev != "" && !validProgressSeverities[sev] {
+			return fmt.Errorf("invalid progress %s: %s (must be debug, info, warn, error, or critical)", name, sev)
+		}
+	}
+
+	// Check report output settings
+	if c.Reports.Dir != "" && !filepath.IsAbs(c.Reports.Dir) && !strings.HasPrefix(c.Reports.Dir, "~/") {
+		return fmt.Errorf("invalid reports dir: %s (must be an absolute path or start with ~/)", c.Reports.Dir)
+	}
+	if err := ValidateReportFilenameTemplate(c.Reports.FilenameTemplate); err != nil {
+		return err
+	}
+
+	// Check excluded folder names (matched against a single path component)
+	for _, name := range c.Libraries.ExcludeDirs {
+		if strings.TrimSpace(name) == "" || strings.ContainsAny(name, `/\`) {
+			return fmt.Errorf("invalid exclude_dirs entry: %q (must be a folder name, not a path)", name)
+		}
+	}
+
+	// Check exclusion patterns
+	for _, pattern := range c.Scan.Exclude {
+		if _, err := ignore.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid scan exclude pattern: %q (%v)", pattern, err)
+		}
+	}
+	if f := c.Scan.BitrateOutlierFactor; f != 0 && f <= 1 {
+		return fmt.Errorf("invalid scan bitrate_outlier_factor: %g (must be 0 for off, or more than 1)", f)
+	}
+
+	// Check naming profile (empty uses jellyfin)
+	if c.Naming.Profile != "" && c.Naming.Profile != "jellyfin" && c.Naming.Profile != "emby" {
+		return fmt.Errorf("invalid naming profile: %s (must be jellyfin or emby)", c.Naming.Profile)
+	}
+
+	// Check sort locale (empty sorts by byte order)
+	if c.UI.SortLocale != "" {
+		if _, err := language.Parse(c.UI.SortLocale); err != nil {
+			return fmt.Errorf("invalid ui sort_locale: %s (must be a language tag, e.g. en or de-CH)", c.UI.SortLocale)
+		}
+	}
+
+	// Check duplicate scopes (empty uses cross-library, or [duplicates] scope per library)
+	validScopes := map[string]bool{"same-folder": true, "library": true, "cross-library": true}
+	for name, scope := range map[string]string{
+		"duplicates scope":                 c.Duplicates.Scope,
+		"libraries.movies duplicate_scope": c.Libraries.Movies.DuplicateScope,
+		"libraries.tv duplicate_scope":     c.Libraries.TV.DuplicateScope,
+	} {
+		if scope != "" && !validScopes[scope] {
+			return fmt.Errorf("invalid %s: %s (must be same-folder, library or cross-library)", name, scope)
+		}
+	}
+
+	// Check named libraries and per-library overrides
+	if err := c.validateLibraries(); err != nil {
+		return err
+	}
+
+	// Check anime title lookup (empty disables it)
+	if c.Libraries.Anime.Lookup != "" && c.Libraries.Anime.Lookup != "anilist" {
+		return fmt.Errorf("invalid libraries.anime lookup: %s (must be anilist or empty)", c.Libraries.Anime.Lookup)
+	}
+
+	// Check duplicate strategy (empty uses delete)
+	if c.Duplicates.Strategy != "" && c.Duplicates.Strategy != "delete" && c.Duplicates.Strategy != "multi-version" {
+		return fmt.Errorf("invalid duplicates strategy: %s (must be delete or multi-version)", c.Duplicates.Strategy)
+	}
+
+	// Check ffprobe (empty looks it up on PATH)
+	if c.Duplicates.FFprobe != "" && c.Duplicates.FFprobe != "off" && !filepath.IsAbs(c.Duplicates.FFprobe) {
+		return fmt.Errorf("invalid duplicates ffprobe: %s (must be an absolute path or off)", c.Duplicates.FFprobe)
+	}
+
+	// Check trash settings
+	if c.Cleaner.TrashDir != "" && !filepath.IsAbs(c.Cleaner.TrashDir) && !strings.HasPrefix(c.Cleaner.TrashDir, "~/") {
+		return fmt.Errorf("invalid cleaner trash_dir: %s (must be an absolute path or start with ~/)", c.Cleaner.TrashDir)
+	}
+	if c.Cleaner.RetentionDays < 0 {
+		return fmt.Errorf("invalid cleaner retention_days: %d (must be 0 or more)", c.Cleaner.RetentionDays)
+	}
+	for _, path := range c.Cleaner.ProtectedPaths {
+		if !filepath.IsAbs(path) && !strings.HasPrefix(path, "~/") {
+			return fmt.Errorf("invalid cleaner protected_paths entry: %q (must be an absolute path or start with ~/)", path)
+		}
+	}
+
+	// Check cloud mount listing rate
+	if c.Performance.CloudListRate < 1 {
+		return fmt.Errorf("invalid performance cloud_list_rate: %d (must be at least 1)", c.Performance.CloudListRate)
+	}
+
+	// Check API circuit breaker threshold
+	if c.API.FailureThreshold < 1 {
+		return fmt.Errorf("invalid api failure_threshold: %d (must be at least 1)", c.API.FailureThreshold)
+	}
+
+	// Check API cache lifetime
+	if c.API.CacheTTLDays < 0 {
+		return fmt.Errorf("invalid api cache_ttl_days: %d (must be 0 or more)", c.API.CacheTTLDays)
+	}
+
+	// Check API proxy and CA bundle
+	if c.API.ProxyURL != "" {
+		u, err := url.Parse(c.API.ProxyURL)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") {
+			return fmt.Errorf("invalid api proxy_url: %s (must be http://, https:// or socks5://host:port)", c.API.ProxyURL)
+		}
+	}
+	if c.API.CABundle != "" {
+		if _, err := os.Stat(c.API.CABundle); err != nil {
+			return fmt.Errorf("api ca_bundle not accessible: %s: %w", c.API.CABundle, err)
+		}
+	}
+
+	// Check Jellyfin server settings
+	if c.Jellyfin.URL != "" {
+		u, err := url.Parse(c.Jellyfin.URL)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid jellyfin url: %s (must be http:// or https://host:port)", c.Jellyfin.URL)
+		}
+	}
+	if c.Jellyfin.Compare && (c.Jellyfin.URL == "" || c.Jellyfin.APIKey == "") {
+		return fmt.Errorf("jellyfin compare requires jellyfin url and api_key")
+	}
+	for _, mapping := range c.Jellyfin.PathMappings {
+		server, local, ok := strings.Cut(mapping, "=")
+		if !ok || strings.TrimSpace(server) == "" || strings.TrimSpace(local) == "" {
+			return fmt.Errorf("invalid jellyfin path_mappings entry: %q (must be server_prefix=local_prefix)", mapping)
+		}
+	}
+
+	// Check Sonarr/Radarr settings
+	for _, arr := range []struct {
+		name string
+		cfg  ArrConfig
+	}{{"sonarr", c.Sonarr}, {"radarr", c.Radarr}} {
+		if arr.cfg.URL != "" {
+			u, err := url.Parse(arr.cfg.URL)
+			if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+				return fmt.Errorf("invalid %s url: %s (must be http:// or https://host:port)", arr.name, arr.cfg.URL)
+			}
+			if arr.cfg.APIKey == "" {
+				return fmt.Errorf("%s url requires %s api_key", arr.name, arr.name)
+			}
+		}
+		for _, mapping := range arr.cfg.PathMappings {
+			server, local, ok := strings.Cut(mapping, "=")
+			if !ok || strings.TrimSpace(server) == "" || strings.TrimSpace(local) == "" {
+				return fmt.Errorf("invalid %s path_mappings entry: %q (must be arr_prefix=local_prefix)", arr.name, mapping)
+			}
+		}
+	}
+
+	// Check tag names (lowercase words joined by - or _)
+	for _, tag := range append(append([]string{}, c.Tags.Keep...), c.Tags.Protected...) {
+		if !validTag(tag) {
+			return fmt.Errorf("invalid tag: %q (use lowercase letters, digits, - and _)", tag)
+		}
+	}
+
+	// Check title-case exceptions (each entry is a single word)
+	for _, word := range c.Naming.LowercaseWords {
+		if w := strings.TrimSpace(word); w == "" || strings.ContainsAny(w, " \t") {
+			return fmt.Errorf("invalid lowercase_words entry: %q (must be a single word)", word)
+		}
+	}
+
+	// Check auto-resolution margin (0 disables it)
+	if c.Naming.AutoResolveMargin < 0 || c.Naming.AutoResolveMargin >= 1 {
+		return fmt.Errorf("invalid naming auto_resolve_margin: %v (must be 0 or more and below 1)", c.Naming.AutoResolveMargin)
+	}
+
+	// Check the terminal that opens reports for review (empty = detect one)
+	if tc := c.Daemon.TerminalCommand; tc != "" {
+		if !strings.Contains(tc, "{command}") && !strings.Contains(tc, "{report}") {
+			return fmt.Errorf("invalid daemon terminal_command: %q (must contain {command} or {report})", tc)
+		}
+	}
+
+	// Check summary email settings (empty host turns them off)
+	if email := c.Notify.Email; email.Enabled() {
+		if email.Port < 1 || email.Port > 65535 {
+			return fmt.Errorf("invalid notify.email port: %d (must be 1-65535)", email.Port)
+		}
+		if email.TLS != "" && email.TLS != "starttls" && email.TLS != "tls" && email.TLS != "none" {
+			return fmt.Errorf("invalid notify.email tls: %s (must be starttls, tls or none)", email.TLS)
+		}
+		if len(email.To) == 0 {
+			return fmt.Errorf("notify.email host requires notify.email to")
+		}
+		for _, addr := range append([]string{email.From}, email.To...) {
+			if addr == "" {
+				continue
+			}
+			if _, err := mail.ParseAddress(addr); err != nil {
+				return fmt.Errorf("invalid notify.email address: %q (%v)", addr, err)
+			}
+		}
+		if email.Password != "" && email.Username == "" {
+			return fmt.Errorf("notify.email password requires notify.email username")
+		}
+	} else if len(c.Notify.Email.To) > 0 {
+		return fmt.Errorf("notify.email to requires notify.email host")
+	}
+
+	// Check webhook, Discord and Telegram targets
+	if err := validateNotifyTarget("notify.webhook", "url", c.Notify.Webhook.URL, c.Notify.Webhook.Events, c.Notify.Webhook.Template); err != nil {
+		return err
+	}
+	if err := validateNotifyTarget("notify.discord", "webhook_url", c.Notify.Discord.WebhookURL, c.Notify.Discord.Events, c.Notify.Discord.Template); err != nil {
+		return err
+	}
+	if tg := c.Notify.Telegram; tg.BotToken != "" || tg.ChatID != "" {
+		if tg.BotToken == "" || tg.ChatID == "" {
+			return fmt.Errorf("notify.telegram requires both bot_token and chat_id")
+		}
+		if strings.ContainsAny(tg.BotToken, "/?# ") {
+			return fmt.Errorf("invalid notify.telegram bot_token (must be the token from @BotFather)")
+		}
+		if err := validateNotifyTarget("notify.telegram", "", "", tg.Events, tg.Template); err != nil {
+			return err
+		}
+	}
+
 	// Check that at least one library path is configured
-	if len(c.Libraries.Movies.Paths) == 0 && len(c.Libraries.TV.Paths) == 0 {
+	if len(c.GetAllPaths()) == 0 {
 		return fmt.Errorf("no library paths configured")
 	}
 
 	// Validate all paths exist and are readable
-	allPaths := append(c.Libraries.Movies.Paths, c.Libraries.TV.Paths...)
-	for _, path := range allPaths {
+	for _, path := range c.GetAllPaths() {
 		info, err := os.Stat(path)
 		if err != nil {
 			return fmt.Errorf("library path %s: %w", path, err)
@@ -279,7 +886,82 @@ func (c *Config) RemoveTVPath(path string) error {
 	return fmt.Errorf("path not found: %s", path)
 }
 
+// validateNotifyTarget checks a [notify.*] target's URL (when urlKey is
+// set), events and message template
+func validateNotifyTarget(section, urlKey, rawURL string, events []string, tmpl string) error {
+	if urlKey != "" {
+		if rawURL == "" {
+			if len(events) > 0 || tmpl != "" {
+				return fmt.Errorf("%s events and template require %s %s", section, section, urlKey)
+			}
+			return nil
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid %s %s (must be an http or https URL)", section, urlKey)
+		}
+	}
+	for _, e := range events {
+		if _, err := notify.ParseKind(e); err != nil {
+			return fmt.Errorf("invalid %s events: %w", section, err)
+		}
+	}
+	if _, err := notify.ParseTemplate(tmpl); err != nil {
+		return fmt.Errorf("%s: %w", section, err)
+	}
+	return nil
+}
+
 // GetAllPaths returns all configured library paths
 func (c *Config) GetAllPaths() []string {
-	return append(c.Libraries.Movies.Paths, c.Libraries.TV.Paths...)
+	return append(c.MoviePaths(), c.ShowPaths()...)
+}
+
+// ShowPaths returns the TV and anime library paths, which are both scanned
+// as shows
+func (c *Config) ShowPaths() []string {
+	return append(c.pathsOfType("tv"), c.AnimePaths()...)
+}
+
+// validTag reports whether tag is a non-empty run of a-z, 0-9, - and _
+func validTag(tag string) bool {
+	if tag == "" {
+		return false
+	}
+	for _, r := range tag {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// MovieDuplicateScope returns the scope movie duplicates are matched in
+func (c *Config) MovieDuplicateScope() string {
+	if c.Libraries.Movies.DuplicateScope != "" {
+		return c.Libraries.Movies.DuplicateScope
+	}
+	return c.Duplicates.Scope
+}
+
+// TVDuplicateScope returns the scope TV duplicates are matched in
+func (c *Config) TVDuplicateScope() string {
+	if c.Libraries.TV.DuplicateScope != "" {
+		return c.Libraries.TV.DuplicateScope
+	}
+	return c.Duplicates.Scope
+}
+
+// validLanguageCode reports whether code looks like an ISO 639-1 or 639-2
+// language code
+func validLanguageCode(code string) bool {
+	if len(code) < 2 || len(code) > 3 {
+		return false
+	}
+	for _, r := range code {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
 }
@@ -1,7 +1,11 @@
 package config
 
 import (
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -15,6 +19,14 @@ func TestDefaultConfig(t *testing.T) {
 		t.Error("expected ReportOnComplete to be true")
 	}
 
+	if !cfg.UI.ShowBanner {
+		t.Error("expected ShowBanner to be true")
+	}
+
+	if !cfg.UI.FirstRunWalkthrough {
+		t.Error("expected FirstRunWalkthrough to be true")
+	}
+
 	if len(cfg.Libraries.Movies.Paths) != 0 {
 		t.Errorf("expected empty movie paths, got %d", len(cfg.Libraries.Movies.Paths))
 	}
@@ -130,6 +142,491 @@ func TestValidate(t *testing.T) {
 	if err := cfg.Validate(); err != nil {
 		t.Errorf("validation failed: %v", err)
 	}
+
+	// Scan time must be HH:MM
+	for _, bad := range []string{"2am", "25:00", "2:00:00"} {
+		cfg.Daemon.ScanTime = bad
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected validation to fail with scan time %q", bad)
+		}
+	}
+	cfg.Daemon.ScanTime = "23:30"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with scan time: %v", err)
+	}
+
+	// A custom schedule is a cron expression or an OnCalendar string
+	for _, good := range []string{"Mon,Thu 03:30", "30 3 * * 1,4", "*-*-01 04:00"} {
+		cfg.Daemon.ScanSchedule = good
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("validation failed with scan schedule %q: %v", good, err)
+		}
+	}
+	for _, bad := range []string{"Mon 25:00", "0 3 1 * 1", "sometimes"} {
+		cfg.Daemon.ScanSchedule = bad
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected validation to fail with scan schedule %q", bad)
+		}
+	}
+	cfg.Daemon.ScanSchedule = ""
+
+	// API address must be host:port
+	cfg.Daemon.HTTPAddr = "localhost"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with http_addr without a port")
+	}
+	cfg.Daemon.HTTPAddr = "127.0.0.1:8787"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with http_addr: %v", err)
+	}
+
+	// Watch mode waits at least a second for changes to settle
+	cfg.Daemon.WatchDebounce = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with watch_debounce of 0")
+	}
+	cfg.Daemon.WatchDebounce = 30
+
+	// Cloud mounts are listed at least once a second
+	cfg.Performance.CloudListRate = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with cloud_list_rate of 0")
+	}
+	cfg.Performance.CloudListRate = 4
+
+	// Deferring while streaming needs a Jellyfin server to ask
+	cfg.Daemon.DeferWhileStreaming = true
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with defer_while_streaming and no jellyfin server")
+	}
+	cfg.Daemon.DeferWhileStreaming = false
+	cfg.Daemon.StreamingIdleMinutes = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with negative streaming_idle_minutes")
+	}
+	cfg.Daemon.StreamingIdleMinutes = 10
+
+	// Auto-clean needs at least the current scan to confirm a duplicate
+	cfg.Daemon.AutoCleanConfirmScans = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with auto_clean_confirm_scans of 0")
+	}
+	cfg.Daemon.AutoCleanConfirmScans = 2
+
+	// Auto-clean policy bounds
+	cfg.AutoClean.MinConfidence = 1.5
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with autoclean min_confidence above 1")
+	}
+	cfg.AutoClean.MinConfidence = 0.9
+	cfg.AutoClean.MaxDeleteGB = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with negative autoclean max_delete_gb")
+	}
+	cfg.AutoClean.MaxDeleteGB = 50
+
+	// Exclusion patterns must compile
+	cfg.Scan.Exclude = []string{"**/Extras/**", "re:(unclosed"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with an invalid exclude regular expression")
+	}
+	cfg.Scan.Exclude = []string{"**/Extras/**", "*.iso"}
+
+	// data_dir must not depend on the working directory
+	cfg.DataDir = "jellysink-data"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with a relative data_dir")
+	}
+	cfg.DataDir = "~/jellysink-data"
+
+	// Invalid auto-clean severity
+	cfg.Daemon.AutoCleanSeverities = []string{"warn", "fatal"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with invalid auto-clean severity")
+	}
+
+	// Invalid progress channel severity
+	cfg.Daemon.AutoCleanSeverities = []string{"warn"}
+	cfg.Progress.TUIMinSeverity = "loud"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with invalid progress severity")
+	}
+
+	// Relative report dir
+	cfg.Progress.TUIMinSeverity = "info"
+	cfg.Reports.Dir = "reports"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with relative reports dir")
+	}
+	cfg.Reports.Dir = "~/jellysink-reports"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with ~/ reports dir: %v", err)
+	}
+
+	// Unknown naming profile
+	cfg.Naming.Profile = "kodi"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with unknown naming profile")
+	}
+	cfg.Naming.Profile = "emby"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with emby profile: %v", err)
+	}
+
+	// Sort locale must be a language tag
+	cfg.UI.SortLocale = "not a locale"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with invalid sort locale")
+	}
+	cfg.UI.SortLocale = "de-CH"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with de-CH sort locale: %v", err)
+	}
+
+	// Excluded folders must be bare names
+	cfg.Libraries.ExcludeDirs = []string{"@eaDir", "extras/featurettes"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with path in exclude_dirs")
+	}
+	cfg.Libraries.ExcludeDirs = []string{"@eaDir", " "}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with blank exclude_dirs entry")
+	}
+	cfg.Libraries.ExcludeDirs = []string{}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with empty exclude_dirs: %v", err)
+	}
+
+	// Circuit breaker needs at least one failure to trip
+	cfg.API.FailureThreshold = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with zero api failure_threshold")
+	}
+	cfg.API.FailureThreshold = 3
+
+	// A cache TTL of 0 keeps lookups for one scan; negative is a typo
+	cfg.API.CacheTTLDays = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with negative api cache_ttl_days")
+	}
+	cfg.API.CacheTTLDays = 0
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with cache_ttl_days 0: %v", err)
+	}
+
+	// Proxy must be a full URL with a supported scheme; CA bundle must exist
+	for _, proxy := range []string{"proxy.lan:3128", "ftp://proxy.lan", "http://"} {
+		cfg.API.ProxyURL = proxy
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected validation to fail with proxy_url %q", proxy)
+		}
+	}
+	cfg.API.ProxyURL = "socks5://proxy.lan:1080"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with socks5 proxy: %v", err)
+	}
+	cfg.API.ProxyURL = ""
+	cfg.API.CABundle = filepath.Join(t.TempDir(), "missing.pem")
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with missing ca_bundle")
+	}
+	cfg.API.CABundle = ""
+
+	// Title-case exceptions must be single words
+	cfg.Naming.LowercaseWords = []string{"of", "the rings"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with multi-word lowercase_words entry")
+	}
+	cfg.Naming.LowercaseWords = []string{"of", ""}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with blank lowercase_words entry")
+	}
+	cfg.Naming.LowercaseWords = []string{"of", "the", "und"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with lowercase_words: %v", err)
+	}
+
+	// Auto-resolution margin is a confidence difference
+	cfg.Naming.AutoResolveMargin = 1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with auto_resolve_margin of 1")
+	}
+	cfg.Naming.AutoResolveMargin = -0.1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with negative auto_resolve_margin")
+	}
+	cfg.Naming.AutoResolveMargin = 0.3
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with auto_resolve_margin: %v", err)
+	}
+	cfg.Naming.AutoResolveMargin = 0
+
+	// Jellyfin comparison needs a server URL and API key
+	cfg.Jellyfin.Compare = true
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with jellyfin compare but no server")
+	}
+	cfg.Jellyfin.URL = "localhost:8096"
+	cfg.Jellyfin.APIKey = "key"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with jellyfin url missing a scheme")
+	}
+	cfg.Jellyfin.URL = "http://localhost:8096"
+	cfg.Jellyfin.PathMappings = []string{"/data/media"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with malformed path_mappings entry")
+	}
+	cfg.Jellyfin.PathMappings = []string{"/data/media=/mnt/media"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with jellyfin settings: %v", err)
+	}
+
+	// Sonarr/Radarr need an http(s) URL with an API key
+	cfg.Sonarr.URL = "http://localhost:8989"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with sonarr url but no api_key")
+	}
+	cfg.Sonarr.APIKey = "key"
+	cfg.Radarr.URL = "radarr:7878"
+	cfg.Radarr.APIKey = "key"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with radarr url missing a scheme")
+	}
+	cfg.Radarr.URL = "http://radarr:7878"
+	cfg.Radarr.PathMappings = []string{"/movies"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with malformed radarr path_mappings entry")
+	}
+	cfg.Radarr.PathMappings = []string{"/movies=/mnt/STORAGE1/MOVIES"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with sonarr/radarr settings: %v", err)
+	}
+
+	// Tag names are lowercase words
+	cfg.Tags.Protected = []string{"Never Touch"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with invalid tag name")
+	}
+	cfg.Tags.Protected = []string{"never-touch", "archive_2020"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with tag names: %v", err)
+	}
+
+	// Duplicate strategy
+	cfg.Duplicates.Strategy = "merge"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with unknown duplicates strategy")
+	}
+	cfg.Duplicates.Strategy = "multi-version"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with multi-version strategy: %v", err)
+	}
+	cfg.Duplicates.Scope = "everywhere"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with unknown duplicates scope")
+	}
+	cfg.Duplicates.Scope = "library"
+	cfg.Libraries.TV.DuplicateScope = "same-show"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with unknown TV duplicate_scope")
+	}
+	cfg.Libraries.TV.DuplicateScope = "same-folder"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with duplicate scopes: %v", err)
+	}
+	if cfg.MovieDuplicateScope() != "library" || cfg.TVDuplicateScope() != "same-folder" {
+		t.Errorf("expected the TV library's scope to override [duplicates], got %s and %s", cfg.MovieDuplicateScope(), cfg.TVDuplicateScope())
+	}
+	cfg.Libraries.Anime.Lookup = "anidb"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with unknown anime lookup")
+	}
+	cfg.Libraries.Anime.Lookup = "anilist"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with AniList lookup: %v", err)
+	}
+	cfg.Duplicates.FFprobe = "ffprobe"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with relative ffprobe path")
+	}
+	for _, ffprobe := range []string{"", "off", "/usr/local/bin/ffprobe"} {
+		cfg.Duplicates.FFprobe = ffprobe
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("validation failed with ffprobe %q: %v", ffprobe, err)
+		}
+	}
+
+	// Trash location and retention
+	cfg.Cleaner.TrashDir = "trash"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with relative trash_dir")
+	}
+	cfg.Cleaner.TrashDir = "~/media-trash"
+	cfg.Cleaner.RetentionDays = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with negative retention_days")
+	}
+	cfg.Cleaner.RetentionDays = 0
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with trash settings: %v", err)
+	}
+
+	// Protected paths must not depend on the working directory
+	cfg.Cleaner.ProtectedPaths = []string{"/mnt/media/movies/Favourites", "Archive"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with relative protected path")
+	}
+	cfg.Cleaner.ProtectedPaths = []string{"/mnt/media/movies/Favourites", "~/media/keep"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with protected paths: %v", err)
+	}
+	cfg.Cleaner.ProtectedPaths = nil
+
+	// A review terminal command must say where the report goes
+	cfg.Daemon.TerminalCommand = "alacritty -e jellysink view"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with terminal_command lacking {command}")
+	}
+	cfg.Daemon.TerminalCommand = "alacritty -e {command}"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with terminal_command: %v", err)
+	}
+
+	// Summary emails need a server and somewhere to send them
+	cfg.Notify.Email.To = []string{"admin@example.com"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation to fail with notify.email to and no host")
+	}
+	cfg.Notify.Email.Host = "smtp.example.com"
+	for name, broken := range map[string]func(e *EmailConfig){
+		"port":      func(e *EmailConfig) { e.Port = 0 },
+		"tls":       func(e *EmailConfig) { e.TLS = "ssl" },
+		"to":        func(e *EmailConfig) { e.To = nil },
+		"recipient": func(e *EmailConfig) { e.To = []string{"not an address"} },
+		"password":  func(e *EmailConfig) { e.Password = "secret" },
+	} {
+		saved := cfg.Notify.Email
+		broken(&cfg.Notify.Email)
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected validation to fail with bad notify.email %s", name)
+		}
+		cfg.Notify.Email = saved
+	}
+	cfg.Notify.Email.From = "Jellysink <jellysink@example.com>"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with email settings: %v", err)
+	}
+
+	// Webhook, Discord and Telegram targets
+	cfg.Notify.Webhook = WebhookConfig{URL: "https://hooks.example.com/jellysink", Events: []string{"scan", "error"}}
+	cfg.Notify.Discord = DiscordConfig{WebhookURL: "https://discord.com/api/webhooks/1/abc", Template: "{{.Duplicates}} duplicates, {{gb .SpaceToFree}}"}
+	cfg.Notify.Telegram = TelegramConfig{BotToken: "123:abc", ChatID: "-100123"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with notification targets: %v", err)
+	}
+	for name, broken := range map[string]func(n *NotifyConfig){
+		"webhook url":        func(n *NotifyConfig) { n.Webhook.URL = "hooks.example.com" },
+		"webhook event":      func(n *NotifyConfig) { n.Webhook.Events = []string{"finished"} },
+		"webhook no url":     func(n *NotifyConfig) { n.Webhook.URL = "" },
+		"discord template":   func(n *NotifyConfig) { n.Discord.Template = "{{.Duplicats}}" },
+		"discord syntax":     func(n *NotifyConfig) { n.Discord.Template = "{{if .Partial}}" },
+		"telegram chat":      func(n *NotifyConfig) { n.Telegram.ChatID = "" },
+		"telegram token":     func(n *NotifyConfig) { n.Telegram.BotToken = "" },
+		"telegram bad token": func(n *NotifyConfig) { n.Telegram.BotToken = "123/abc" },
+	} {
+		saved := cfg.Notify
+		broken(&cfg.Notify)
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected validation to fail with bad notify %s", name)
+		}
+		cfg.Notify = saved
+	}
+
+	// Bitrate outliers are off at 0, and otherwise more than 1x off the median
+	for _, good := range []float64{0, 2.5} {
+		cfg.Scan.BitrateOutlierFactor = good
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("validation failed with bitrate_outlier_factor %g: %v", good, err)
+		}
+	}
+	for _, bad := range []float64{1, 0.5, -2} {
+		cfg.Scan.BitrateOutlierFactor = bad
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected validation to fail with bitrate_outlier_factor %g", bad)
+		}
+	}
+	cfg.Scan.BitrateOutlierFactor = 0
+
+	// Languages are ISO 639 codes
+	cfg.Languages = LanguagesConfig{RequiredAudio: []string{"en"}, RequiredSubtitles: []string{"eng"}, Wanted: []string{"jpn"}, MaxUnwantedTracks: 3}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("validation failed with languages: %v", err)
+	}
+	for name, broken := range map[string]func(l *LanguagesConfig){
+		"audio":     func(l *LanguagesConfig) { l.RequiredAudio = []string{"english"} },
+		"subtitles": func(l *LanguagesConfig) { l.RequiredSubtitles = []string{""} },
+		"wanted":    func(l *LanguagesConfig) { l.Wanted = []string{"e1"} },
+		"max":       func(l *LanguagesConfig) { l.MaxUnwantedTracks = -1 },
+	} {
+		saved := cfg.Languages
+		broken(&cfg.Languages)
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected validation to fail with bad languages %s", name)
+		}
+		cfg.Languages = saved
+	}
+}
+
+func TestValidateReportFilenameTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		wantErr  bool
+	}{
+		{"{timestamp}", false},
+		{"{host}_{library}_{date}_{time}", false},
+		{"scan-{timestamp}", false},
+		{"", true},
+		{"{date}", true},             // successive scans on the same day would collide
+		{"{timestamp}_{user}", true}, // unknown placeholder
+		{"reports/{timestamp}", true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateReportFilenameTemplate(tt.template)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateReportFilenameTemplate(%q) error = %v, wantErr %v", tt.template, err, tt.wantErr)
+		}
+	}
+}
+
+func TestReportsConfigFilenameAndDir(t *testing.T) {
+	ts := time.Date(2024, 3, 9, 14, 5, 7, 0, time.UTC)
+
+	reports := ReportsConfig{FilenameTemplate: "{library}_{date}_{time}"}
+	if got := reports.Filename(ts, "tv"); got != "tv_20240309_140507" {
+		t.Errorf("Filename() = %q, want tv_20240309_140507", got)
+	}
+	if got := reports.Filename(ts, ""); got != "library_20240309_140507" {
+		t.Errorf("Filename() with empty library = %q", got)
+	}
+
+	// Invalid templates fall back to the default rather than clobbering reports
+	reports.FilenameTemplate = "{date}"
+	if got := reports.Filename(ts, "tv"); got != "20240309_140507" {
+		t.Errorf("Filename() with invalid template = %q, want default", got)
+	}
+
+	home := "/home/alice"
+	t.Setenv(oplog.DataDirEnv, "/srv/jellysink")
+	if got := (ReportsConfig{}).ResolveDir(home); got != "/srv/jellysink/scan_results" {
+		t.Errorf("ResolveDir() default = %q, want scan_results in the data directory", got)
+	}
+	if got := (ReportsConfig{Dir: "~/reports"}).ResolveDir(home); got != "/home/alice/reports" {
+		t.Errorf("ResolveDir() with ~ = %q", got)
+	}
+	if got := (ReportsConfig{Dir: "/mnt/nas/jellysink/"}).ResolveDir(home); got != "/mnt/nas/jellysink" {
+		t.Errorf("ResolveDir() with share path = %q", got)
+	}
 }
 
 func TestSaveAndLoad(t *testing.T) {
@@ -168,3 +665,81 @@ func TestGetAllPaths(t *testing.T) {
 		t.Error("not all paths found in GetAllPaths()")
 	}
 }
+
+func TestForLibraries(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Libraries.Movies.Paths = []string{"/media/movies"}
+	cfg.Libraries.TV.Paths = []string{"/media/tv"}
+	cfg.Libraries.TV.Overrides.ScanFrequency = "daily"
+	merge := false
+	cfg.Libraries.Named = []NamedLibrary{{
+		Name:      "4k",
+		Type:      "movies",
+		Paths:     []string{"/media/4k"},
+		Overrides: LibraryOverrides{ScanSchedule: "*-*-01 04:00", Strategy: "multi-version", MergeCompanions: &merge},
+	}}
+	if err := cfg.validateLibraries(); err != nil {
+		t.Fatalf("validateLibraries() = %v", err)
+	}
+
+	if got := cfg.MoviePaths(); len(got) != 2 || got[1] != "/media/4k" {
+		t.Errorf("MoviePaths() = %v, want the movies and 4k paths", got)
+	}
+	if groups := cfg.LibraryGroups(); len(groups) != 3 {
+		t.Errorf("LibraryGroups() = %v, want movies, tv and 4k apart", groups)
+	}
+
+	scoped, err := cfg.ForLibraries("4k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scoped.MoviePaths()) != 1 || len(scoped.ShowPaths()) != 0 {
+		t.Errorf("ForLibraries(4k) scans %v and %v, want only /media/4k", scoped.MoviePaths(), scoped.ShowPaths())
+	}
+	if scoped.Daemon.ScanSchedule != "*-*-01 04:00" || scoped.Duplicates.Strategy != "multi-version" || scoped.Duplicates.MergeCompanions {
+		t.Errorf("ForLibraries(4k) did not apply the overrides: %+v %+v", scoped.Daemon, scoped.Duplicates)
+	}
+	if cfg.Duplicates.Strategy == "multi-version" {
+		t.Error("ForLibraries changed the original config")
+	}
+
+	scoped, err = cfg.ForLibraries("tv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scoped.Daemon.ScanFrequency != "daily" || scoped.Daemon.ScanTime != cfg.Daemon.ScanTime {
+		t.Errorf("ForLibraries(tv) schedule = %+v, want daily at the global time", scoped.Daemon)
+	}
+
+	if _, err := cfg.ForLibraries("movies", "tv"); err == nil {
+		t.Error("expected libraries with different overrides to be refused together")
+	}
+	if _, err := cfg.ForLibraries("music"); err == nil {
+		t.Error("expected an unknown library to be refused")
+	}
+}
+
+func TestValidateLibraries(t *testing.T) {
+	tests := []struct {
+		name    string
+		library NamedLibrary
+	}{
+		{"bad name", NamedLibrary{Name: "4K Movies", Type: "movies", Paths: []string{"/a"}}},
+		{"builtin name", NamedLibrary{Name: "tv", Type: "tv", Paths: []string{"/a"}}},
+		{"bad type", NamedLibrary{Name: "music", Type: "audio", Paths: []string{"/a"}}},
+		{"bad scope", NamedLibrary{Name: "4k", Type: "movies", Paths: []string{"/a"}, DuplicateScope: "everywhere"}},
+		{"bad frequency", NamedLibrary{Name: "4k", Type: "movies", Paths: []string{"/a"}, Overrides: LibraryOverrides{ScanFrequency: "monthly"}}},
+		{"bad time", NamedLibrary{Name: "4k", Type: "movies", Paths: []string{"/a"}, Overrides: LibraryOverrides{ScanTime: "4am"}}},
+		{"bad schedule", NamedLibrary{Name: "4k", Type: "movies", Paths: []string{"/a"}, Overrides: LibraryOverrides{ScanSchedule: "sometimes"}}},
+		{"bad strategy", NamedLibrary{Name: "4k", Type: "movies", Paths: []string{"/a"}, Overrides: LibraryOverrides{Strategy: "keep"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Libraries.Named = []NamedLibrary{tt.library}
+			if err := cfg.validateLibraries(); err == nil {
+				t.Errorf("expected %+v to be invalid", tt.library)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/calendar"
+)
+
+// LibraryOverrides replaces [daemon] and [duplicates] settings for one
+// library. Empty values keep the global setting
+type LibraryOverrides struct {
+	ScanFrequency   string `toml:"scan_frequency"`   // daily, weekly, biweekly
+	ScanTime        string `toml:"scan_time"`        // HH:MM
+	ScanSchedule    string `toml:"scan_schedule"`    // cron expression or OnCalendar string; overrides the library's frequency and time
+	Strategy        string `toml:"strategy"`         // duplicate strategy: delete or multi-version (movies only)
+	MergeCompanions *bool  `toml:"merge_companions"` // move subtitles and artwork the keeper lacks out of deleted copies' folders
+}
+
+// NamedLibrary is a library beyond movies, tv and anime, e.g. a separate
+// 4K movie collection with its own schedule
+type NamedLibrary struct {
+	Name           string           `toml:"name"` // used in logs, reports and `jellysink scan --library`
+	Type           string           `toml:"type"` // movies, tv or anime: how its paths are scanned
+	Paths          []string         `toml:"paths"`
+	DuplicateScope string           `toml:"duplicate_scope"` // overrides [duplicates] scope; empty = use it
+	Overrides      LibraryOverrides `toml:"overrides"`
+}
+
+// Library is one configured library, built in or named
+type Library struct {
+	Name           string
+	Type           string // movies, tv or anime
+	Paths          []string
+	DuplicateScope string
+	Overrides      LibraryOverrides
+}
+
+// libraryNameRegex limits library names to what fits a report filename
+var libraryNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// LibraryList returns the libraries with paths: movies, tv and anime, then
+// the named libraries in config order
+func (c *Config) LibraryList() []Library {
+	var libraries []Library
+	builtin := []Library{
+		{Name: "movies", Type: "movies", Paths: c.Libraries.Movies.Paths, DuplicateScope: c.Libraries.Movies.DuplicateScope, Overrides: c.Libraries.Movies.Overrides},
+		{Name: "tv", Type: "tv", Paths: c.Libraries.TV.Paths, DuplicateScope: c.Libraries.TV.DuplicateScope, Overrides: c.Libraries.TV.Overrides},
+		{Name: "anime", Type: "anime", Paths: c.Libraries.Anime.Paths, Overrides: c.Libraries.Anime.Overrides},
+	}
+	for _, library := range builtin {
+		if len(library.Paths) > 0 {
+			libraries = append(libraries, library)
+		}
+	}
+	for _, named := range c.Libraries.Named {
+		if len(named.Paths) == 0 {
+			continue
+		}
+		libraries = append(libraries, Library{
+			Name:           named.Name,
+			Type:           named.Type,
+			Paths:          named.Paths,
+			DuplicateScope: named.DuplicateScope,
+			Overrides:      named.Overrides,
+		})
+	}
+	return libraries
+}
+
+// MoviePaths returns the paths of every movie library
+func (c *Config) MoviePaths() []string {
+	return c.pathsOfType("movies")
+}
+
+// AnimePaths returns the paths of every anime library
+func (c *Config) AnimePaths() []string {
+	return c.pathsOfType("anime")
+}
+
+// pathsOfType returns the paths of the libraries of one type
+func (c *Config) pathsOfType(kind string) []string {
+	var paths []string
+	for _, library := range c.LibraryList() {
+		if library.Type == kind {
+			paths = append(paths, library.Paths...)
+		}
+	}
+	return paths
+}
+
+// ForLibraries returns a copy of the config that holds only the named
+// libraries, folded into movies, tv and anime, with their overrides applied
+// to [daemon] and [duplicates]. Libraries scanned together must share
+// their overrides; LibraryGroups puts them together that way
+func (c *Config) ForLibraries(names ...string) (*Config, error) {
+	byName := make(map[string]Library)
+	for _, library := range c.LibraryList() {
+		byName[library.Name] = library
+	}
+
+	scoped := *c
+	scoped.Libraries.Movies = MovieLibrary{}
+	scoped.Libraries.TV = TVLibrary{}
+	scoped.Libraries.Anime = AnimeLibrary{Lookup: c.Libraries.Anime.Lookup}
+	scoped.Libraries.Named = nil
+	scoped.Only = nil
+
+	var overrides *LibraryOverrides
+	for _, name := range names {
+		library, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("no library named %q (configured: %s)", name, strings.Join(c.LibraryNames(), ", "))
+		}
+		if overrides != nil && library.groupKey() != byName[names[0]].groupKey() {
+			return nil, fmt.Errorf("libraries %s have different settings and are scanned separately", strings.Join(names, ", "))
+		}
+		overrides = &library.Overrides
+
+		switch library.Type {
+		case "movies":
+			scoped.Libraries.Movies.Paths = append(scoped.Libraries.Movies.Paths, library.Paths...)
+			if library.DuplicateScope != "" {
+				scoped.Libraries.Movies.DuplicateScope = library.DuplicateScope
+			}
+		case "tv":
+			scoped.Libraries.TV.Paths = append(scoped.Libraries.TV.Paths, library.Paths...)
+			if library.DuplicateScope != "" {
+				scoped.Libraries.TV.DuplicateScope = library.DuplicateScope
+			}
+		case "anime":
+			scoped.Libraries.Anime.Paths = append(scoped.Libraries.Anime.Paths, library.Paths...)
+			if library.DuplicateScope != "" {
+				scoped.Libraries.TV.DuplicateScope = library.DuplicateScope
+			}
+		}
+		scoped.Only = append(scoped.Only, name)
+	}
+
+	if overrides != nil {
+		if overrides.ScanSchedule != "" {
+			scoped.Daemon.ScanSchedule = overrides.ScanSchedule
+		} else if overrides.ScanFrequency != "" || overrides.ScanTime != "" {
+			scoped.Daemon.ScanSchedule = ""
+			if overrides.ScanFrequency != "" {
+				scoped.Daemon.ScanFrequency = overrides.ScanFrequency
+			}
+			if overrides.ScanTime != "" {
+				scoped.Daemon.ScanTime = overrides.ScanTime
+			}
+		}
+		if overrides.Strategy != "" {
+			scoped.Duplicates.Strategy = overrides.Strategy
+		}
+		if overrides.MergeCompanions != nil {
+			scoped.Duplicates.MergeCompanions = *overrides.MergeCompanions
+		}
+	}
+	return &scoped, nil
+}
+
+// LibraryNames returns the names of the libraries with paths
+func (c *Config) LibraryNames() []string {
+	var names []string
+	for _, library := range c.LibraryList() {
+		names = append(names, library.Name)
+	}
+	return names
+}
+
+// LibraryGroups splits the libraries into the sets that are scanned
+// together: libraries with the same overrides and duplicate scope, which
+// includes libraries without any. A config without overrides has one group
+// of every library
+func (c *Config) LibraryGroups() [][]string {
+	var groups [][]string
+	index := make(map[string]int)
+	for _, library := range c.LibraryList() {
+		key := library.groupKey()
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], library.Name)
+	}
+	return groups
+}
+
+// HasLibraryOverrides reports whether any library is scanned with settings
+// of its own, rather than everything together on the global ones
+func (c *Config) HasLibraryOverrides() bool {
+	plain := Library{}.groupKey()
+	for _, library := range c.LibraryList() {
+		if library.groupKey() != plain {
+			return true
+		}
+	}
+	return false
+}
+
+// groupKey identifies the settings a library is scanned with. The built-in
+// libraries' duplicate_scope already applies to every scan, so only a named
+// library's own scope sets it apart
+func (l Library) groupKey() string {
+	o := l.Overrides
+	merge := ""
+	if o.MergeCompanions != nil {
+		merge = fmt.Sprint(*o.MergeCompanions)
+	}
+	scope := ""
+	if l.Name != l.Type {
+		scope = l.DuplicateScope
+	}
+	return strings.Join([]string{o.ScanFrequency, o.ScanTime, o.ScanSchedule, o.Strategy, merge, scope}, "\x00")
+}
+
+// validateLibraries checks the named libraries and every library's overrides
+func (c *Config) validateLibraries() error {
+	seen := map[string]bool{"movies": true, "tv": true, "anime": true}
+	for _, named := range c.Libraries.Named {
+		if !libraryNameRegex.MatchString(named.Name) {
+			return fmt.Errorf("invalid library name %q (must be lowercase letters, digits, - and _)", named.Name)
+		}
+		if seen[named.Name] {
+			return fmt.Errorf("duplicate library name %q", named.Name)
+		}
+		seen[named.Name] = true
+		if named.Type != "movies" && named.Type != "tv" && named.Type != "anime" {
+			return fmt.Errorf("invalid library %s type: %q (must be movies, tv or anime)", named.Name, named.Type)
+		}
+		if named.DuplicateScope != "" && named.DuplicateScope != "same-folder" && named.DuplicateScope != "library" && named.DuplicateScope != "cross-library" {
+			return fmt.Errorf("invalid library %s duplicate_scope: %s (must be same-folder, library or cross-library)", named.Name, named.DuplicateScope)
+		}
+	}
+
+	for _, library := range c.LibraryList() {
+		o := library.Overrides
+		if o.ScanFrequency != "" && o.ScanFrequency != "daily" && o.ScanFrequency != "weekly" && o.ScanFrequency != "biweekly" {
+			return fmt.Errorf("invalid library %s scan_frequency: %s (must be daily, weekly, or biweekly)", library.Name, o.ScanFrequency)
+		}
+		if o.ScanTime != "" {
+			if _, err := time.Parse("15:04", o.ScanTime); err != nil {
+				return fmt.Errorf("invalid library %s scan_time: %s (must be HH:MM, 24-hour)", library.Name, o.ScanTime)
+			}
+		}
+		if o.ScanSchedule != "" {
+			if _, err := calendar.Parse(o.ScanSchedule); err != nil {
+				return fmt.Errorf("invalid library %s scan_schedule: %w", library.Name, err)
+			}
+		}
+		if o.Strategy != "" && o.Strategy != "delete" && o.Strategy != "multi-version" {
+			return fmt.Errorf("invalid library %s strategy: %s (must be delete or multi-version)", library.Name, o.Strategy)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
+)
+
+// DefaultReportFilenameTemplate keeps the historical YYYYMMDD_HHMMSS report names
+const DefaultReportFilenameTemplate = "{timestamp}"
+
+// ReportSubdir is the default report directory, in the data directory
+const ReportSubdir = "scan_results"
+
+// reportPlaceholderRegex matches {name} placeholders in a filename template
+var reportPlaceholderRegex = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// reportPlaceholders are the placeholders a filename template may use
+var reportPlaceholders = map[string]bool{
+	"timestamp": true, // 20060102_150405
+	"date":      true, // 20060102
+	"time":      true, // 150405
+	"library":   true, // movies, tv or mixed
+	"host":      true, // machine hostname, to tell reports apart on a shared dir
+}
+
+// ValidateReportFilenameTemplate checks a reports.filename_template value
+// Templates must produce a single filename and include the time of day so
+// successive scans do not overwrite each other
+func ValidateReportFilenameTemplate(template string) error {
+	if strings.TrimSpace(template) == "" {
+		return fmt.Errorf("invalid reports filename_template: must not be empty")
+	}
+	if strings.ContainsAny(template, `/\`) {
+		return fmt.Errorf("invalid reports filename_template: %s (must not contain path separators)", template)
+	}
+
+	hasTime := false
+	for _, match := range reportPlaceholderRegex.FindAllStringSubmatch(template, -1) {
+		if !reportPlaceholders[match[1]] {
+			return fmt.Errorf("invalid reports filename_template: unknown placeholder {%s} (use {timestamp}, {date}, {time}, {library} or {host})", match[1])
+		}
+		if match[1] == "timestamp" || match[1] == "time" {
+			hasTime = true
+		}
+	}
+	if !hasTime {
+		return fmt.Errorf("invalid reports filename_template: %s (must include {timestamp} or {time})", template)
+	}
+
+	return nil
+}
+
+// ResolveDir returns the report directory, expanding ~/ against home
+// An empty Dir resolves to scan_results in the data directory
+func (r ReportsConfig) ResolveDir(home string) string {
+	switch {
+	case r.Dir == "":
+		return filepath.Join(oplog.DataDir(), ReportSubdir)
+	case strings.HasPrefix(r.Dir, "~/"):
+		return filepath.Join(home, r.Dir[2:])
+	default:
+		return filepath.Clean(r.Dir)
+	}
+}
+
+// Filename expands the filename template for a report (without extension)
+// Falls back to the default template if the configured one is invalid
+func (r ReportsConfig) Filename(ts time.Time, libraryType string) string {
+	template := r.FilenameTemplate
+	if ValidateReportFilenameTemplate(template) != nil {
+		template = DefaultReportFilenameTemplate
+	}
+
+	if libraryType == "" {
+		libraryType = "library"
+	}
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "localhost"
+	}
+
+	values := map[string]string{
+		"timestamp": ts.Format("20060102_150405"),
+		"date":      ts.Format("20060102"),
+		"time":      ts.Format("150405"),
+		"library":   sanitizeFilenamePart(libraryType),
+		"host":      sanitizeFilenamePart(host),
+	}
+	return reportPlaceholderRegex.ReplaceAllStringFunc(template, func(placeholder string) string {
+		return values[placeholder[1:len(placeholder)-1]]
+	})
+}
+
+// sanitizeFilenamePart replaces characters that are unsafe in filenames on
+// common network shares (SMB/NFS) with underscores
+func sanitizeFilenamePart(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ':
+			return '_'
+		}
+		return r
+	}, s)
+}
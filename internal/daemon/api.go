@@ -0,0 +1,472 @@
+package daemon
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// jellysinkd --daemon can serve a small HTTP API (daemon.http_addr) so
+// dashboards and the TUI can ask the running daemon what it is doing:
+//
+//	GET  /status          service status and whether a scan is running
+//	GET  /reports         saved reports, newest first
+//	GET  /reports/{name}  one report's JSON
+//	GET  /reports/{name}/view  the report as a web page, with duplicate previews
+//	GET  /reports/{name}/thumbnails/{group}/{index}  one duplicate's preview frame
+//	POST /scan            start a scan now
+//	GET  /progress        server-sent events for the running scan
+//
+// With daemon.http_token set, every request needs "Authorization: Bearer <token>",
+// or ?token=<token> from a browser, which the view page passes on to its images
+
+const (
+	apiShutdownTimeout = 5 * time.Second
+	apiClientTimeout   = 3 * time.Second
+)
+
+// ErrAPIUnauthorized is returned when jellysinkd rejects the API token
+var ErrAPIUnauthorized = errors.New("jellysinkd API rejected the token")
+
+// APIStatus is the /status response
+type APIStatus struct {
+	ServiceStatus
+	ScanRunning bool // a scan is running here or in another jellysink process
+}
+
+// ReportInfo is one saved report in the /reports response
+type ReportInfo struct {
+	Name    string
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// API serves the HTTP status/control endpoints of a running jellysinkd
+type API struct {
+	daemon *Daemon // only for attaching to and detecting running scans
+	addr   string
+	token  string
+
+	mu     sync.Mutex
+	status ServiceStatus
+
+	scanRequests chan struct{}
+
+	thumbnailDir string
+}
+
+// NewAPI creates the API for addr; token may be empty to allow every request
+func NewAPI(addr, token string) *API {
+	return newAPI(defaultStateDir(), addr, token)
+}
+
+func newAPI(stateDir, addr, token string) *API {
+	return &API{
+		daemon:       &Daemon{stateDir: stateDir},
+		addr:         addr,
+		token:        token,
+		scanRequests: make(chan struct{}, 1),
+		thumbnailDir: filepath.Join(stateDir, scanner.ThumbnailDirName),
+	}
+}
+
+// SetStatus publishes the service's current status
+func (a *API) SetStatus(status ServiceStatus) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.status = status
+}
+
+func (a *API) currentStatus() ServiceStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.status
+}
+
+// ScanRequests delivers a value for each scan requested with POST /scan.
+// Requests made before the last one was picked up are merged
+func (a *API) ScanRequests() <-chan struct{} {
+	return a.scanRequests
+}
+
+// ListenAndServe serves the API until ctx is cancelled
+func (a *API) ListenAndServe(ctx context.Context) error {
+	listener, err := net.Listen("tcp", a.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", a.addr, err)
+	}
+	fmt.Printf("jellysinkd: API listening on %s\n", listener.Addr())
+
+	server := &http.Server{
+		Handler:           a.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), apiShutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("API server failed: %w", err)
+	}
+	return nil
+}
+
+// Handler returns the API's routes
+func (a *API) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", a.handleStatus)
+	mux.HandleFunc("GET /reports", a.handleReports)
+	mux.HandleFunc("GET /reports/{name}", a.handleReport)
+	mux.HandleFunc("GET /reports/{name}/view", a.handleReportView)
+	mux.HandleFunc("GET /reports/{name}/thumbnails/{group}/{index}", a.handleThumbnail)
+	mux.HandleFunc("POST /scan", a.handleScan)
+	mux.HandleFunc("GET /progress", a.handleProgress)
+	return a.authorize(mux)
+}
+
+// authorize rejects requests without the configured bearer token
+func (a *API) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.token != "" {
+			got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if got == "" {
+				got = r.URL.Query().Get("token")
+			}
+			if subtle.ConstantTimeCompare([]byte(got), []byte(a.token)) != 1 {
+				writeAPIError(w, http.StatusUnauthorized, "missing or invalid token")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (a *API) handleStatus(w http.ResponseWriter, r *http.Request) {
+	status := a.currentStatus()
+	writeJSON(w, http.StatusOK, APIStatus{
+		ServiceStatus: status,
+		ScanRunning:   status.State == ServiceScanning || a.daemon.ScanRunning(),
+	})
+}
+
+func (a *API) handleReports(w http.ResponseWriter, r *http.Request) {
+	reports, err := listReports(GetReportDir())
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, reports)
+}
+
+// reportPath returns the saved report the request names, or writes a 400
+func reportPath(w http.ResponseWriter, r *http.Request) (string, bool) {
+	name := r.PathValue("name")
+	if name != filepath.Base(name) || !strings.HasSuffix(name, ".json") {
+		writeAPIError(w, http.StatusBadRequest, "invalid report name")
+		return "", false
+	}
+	return filepath.Join(GetReportDir(), name), true
+}
+
+// loadAPIReport reads the report the request names, or writes the error
+func loadAPIReport(w http.ResponseWriter, r *http.Request) (reporter.Report, bool) {
+	path, ok := reportPath(w, r)
+	if !ok {
+		return reporter.Report{}, false
+	}
+	report, err := reporter.ReadReport(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			writeAPIError(w, http.StatusNotFound, "report not found")
+		} else {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+		}
+		return reporter.Report{}, false
+	}
+	return report, true
+}
+
+func (a *API) handleReport(w http.ResponseWriter, r *http.Request) {
+	path, ok := reportPath(w, r)
+	if !ok {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			writeAPIError(w, http.StatusNotFound, "report not found")
+			return
+		}
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// handleReportView renders the report as an HTML page whose duplicates
+// table previews each file through handleThumbnail
+func (a *API) handleReportView(w http.ResponseWriter, r *http.Request) {
+	report, ok := loadAPIReport(w, r)
+	if !ok {
+		return
+	}
+	query := ""
+	if token := r.URL.Query().Get("token"); token != "" {
+		query = "?token=" + url.QueryEscape(token)
+	}
+	thumbnail := func(groupID string, index int, path string, duration time.Duration) string {
+		if report.Simulated || groupID == "" {
+			return ""
+		}
+		// Relative to /reports/{name}/view
+		return fmt.Sprintf("thumbnails/%s/%d%s", url.PathEscape(groupID), index, query)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := reporter.WriteReportHTMLThumbnails(w, report, thumbnail); err != nil {
+		fmt.Fprintf(os.Stderr, "jellysinkd: failed to render report view: %v\n", err)
+	}
+}
+
+// handleThumbnail serves a preview frame of one file of a duplicate group.
+// Only files a report lists can be previewed
+func (a *API) handleThumbnail(w http.ResponseWriter, r *http.Request) {
+	report, ok := loadAPIReport(w, r)
+	if !ok {
+		return
+	}
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid file index")
+		return
+	}
+	path, duration, found := duplicateFile(report, r.PathValue("group"), index)
+	if !found {
+		writeAPIError(w, http.StatusNotFound, "no such duplicate in the report")
+		return
+	}
+	data, err := scanner.Thumbnail(r.Context(), a.thumbnailDir, path, duration)
+	if err != nil {
+		code := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			code = http.StatusNotFound
+		case errors.Is(err, scanner.ErrNoFFmpeg):
+			code = http.StatusNotImplemented
+		}
+		writeAPIError(w, code, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	w.Write(data)
+}
+
+// duplicateFile returns the path and probed duration of file index of the
+// duplicate group with id groupID
+func duplicateFile(report reporter.Report, groupID string, index int) (string, time.Duration, bool) {
+	var path string
+	var probe *scanner.MediaInfo
+	for _, dup := range report.MovieDuplicates {
+		id := dup.ID
+		if id == "" {
+			id = scanner.MovieDuplicateID(dup)
+		}
+		if id == groupID && index >= 0 && index < len(dup.Files) {
+			path, probe = dup.Files[index].Path, dup.Files[index].Probe
+		}
+	}
+	for _, dup := range report.TVDuplicates {
+		id := dup.ID
+		if id == "" {
+			id = scanner.TVDuplicateID(dup)
+		}
+		if id == groupID && index >= 0 && index < len(dup.Files) {
+			path, probe = dup.Files[index].Path, dup.Files[index].Probe
+		}
+	}
+	if path == "" {
+		return "", 0, false
+	}
+	if probe != nil {
+		return path, probe.Duration, true
+	}
+	return path, 0, true
+}
+
+func (a *API) handleScan(w http.ResponseWriter, r *http.Request) {
+	if a.currentStatus().State == ServiceScanning || a.daemon.ScanRunning() {
+		writeAPIError(w, http.StatusConflict, "a scan is already running")
+		return
+	}
+	select {
+	case a.scanRequests <- struct{}{}:
+	default:
+		// A requested scan has not started yet; this one joins it
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{"Status": "scan requested"})
+}
+
+// handleProgress streams the running scan's progress as server-sent events:
+// "progress" events carry a ScanProgress, then one "done" event carries the
+// report path or "error" the failure
+func (a *API) handleProgress(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeAPIError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+	if !a.daemon.ScanRunning() {
+		writeAPIError(w, http.StatusConflict, ErrNoScanRunning.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	progressCh := make(chan scanner.ScanProgress, 100)
+	type outcome struct {
+		reportPath string
+		err        error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		reportPath, err := a.daemon.Attach(r.Context(), progressCh)
+		close(progressCh)
+		done <- outcome{reportPath, err}
+	}()
+
+	for p := range progressCh {
+		writeEvent(w, "progress", p)
+		flusher.Flush()
+	}
+
+	result := <-done
+	switch {
+	case r.Context().Err() != nil:
+		return
+	case result.err != nil:
+		writeEvent(w, "error", map[string]string{"Error": result.err.Error()})
+	default:
+		writeEvent(w, "done", map[string]string{"ReportPath": result.reportPath})
+	}
+	flusher.Flush()
+}
+
+// listReports returns the JSON reports in dir, newest first
+func listReports(dir string) ([]ReportInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []ReportInfo{}, nil
+		}
+		return nil, fmt.Errorf("failed to read report directory: %w", err)
+	}
+
+	reports := []ReportInfo{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		reports = append(reports, ReportInfo{
+			Name:    entry.Name(),
+			Path:    filepath.Join(dir, entry.Name()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].ModTime.After(reports[j].ModTime)
+	})
+	return reports, nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeAPIError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]string{"Error": msg})
+}
+
+// writeEvent writes one server-sent event with a JSON payload
+func writeEvent(w http.ResponseWriter, event string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
+
+// FetchAPIStatus asks the jellysinkd serving the API on addr for its status
+func FetchAPIStatus(ctx context.Context, addr, token string) (*APIStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, apiClientTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL(addr, "/status"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API address %s: %w", addr, err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("jellysinkd API unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrAPIUnauthorized
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct{ Error string }
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return nil, fmt.Errorf("jellysinkd API returned %s: %s", resp.Status, apiErr.Error)
+	}
+	var status APIStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to parse API status: %w", err)
+	}
+	return &status, nil
+}
+
+// apiURL turns a bind address into a URL on this machine; wildcard and
+// empty hosts are reached on loopback
+func apiURL(addr, path string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "http://" + addr + path
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, port) + path
+}
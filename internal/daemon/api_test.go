@@ -0,0 +1,201 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestAPIStatusAndScanRequests(t *testing.T) {
+	api := newAPI(t.TempDir(), "", "secret")
+	server := httptest.NewServer(api.Handler())
+	defer server.Close()
+
+	resp, _ := http.Get(server.URL + "/status")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without the token, got %s", resp.Status)
+	}
+
+	api.SetStatus(ServiceStatus{PID: 42, State: ServiceIdle, Schedule: "daily at 02:00"})
+	addr := strings.TrimPrefix(server.URL, "http://")
+	status, err := FetchAPIStatus(context.Background(), addr, "secret")
+	if err != nil || status.PID != 42 || status.State != ServiceIdle || status.ScanRunning {
+		t.Fatalf("FetchAPIStatus() = %+v, %v", status, err)
+	}
+
+	post := func() int {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/scan", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if code := post(); code != http.StatusAccepted {
+		t.Fatalf("POST /scan = %d, want 202", code)
+	}
+	// A second request before the first is picked up joins it
+	if code := post(); code != http.StatusAccepted {
+		t.Errorf("POST /scan = %d, want 202", code)
+	}
+	select {
+	case <-api.ScanRequests():
+	default:
+		t.Fatal("Expected a scan request")
+	}
+	select {
+	case <-api.ScanRequests():
+		t.Error("Expected the queued requests merged")
+	default:
+	}
+
+	api.SetStatus(ServiceStatus{State: ServiceScanning})
+	if code := post(); code != http.StatusConflict {
+		t.Errorf("POST /scan while scanning = %d, want 409", code)
+	}
+}
+
+func TestAPIReports(t *testing.T) {
+	reportDir := t.TempDir()
+	reporter.SetOutput(config.ReportsConfig{Dir: reportDir})
+	defer reporter.SetOutput(config.ReportsConfig{})
+
+	older := filepath.Join(reportDir, "old.json")
+	newer := filepath.Join(reportDir, "new.json")
+	os.WriteFile(older, []byte(`{"TotalDuplicates":1}`), 0644)
+	os.WriteFile(newer, []byte(`{"TotalDuplicates":2}`), 0644)
+	os.WriteFile(filepath.Join(reportDir, "notes.txt"), []byte("x"), 0644)
+	os.Chtimes(older, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour))
+
+	server := httptest.NewServer(newAPI(t.TempDir(), "", "").Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/reports")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reports []ReportInfo
+	json.NewDecoder(resp.Body).Decode(&reports)
+	resp.Body.Close()
+	if len(reports) != 2 || reports[0].Name != "new.json" || reports[1].Name != "old.json" {
+		t.Fatalf("GET /reports = %+v, want the JSON reports newest first", reports)
+	}
+
+	resp, _ = http.Get(server.URL + "/reports/old.json")
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != `{"TotalDuplicates":1}` {
+		t.Errorf("GET /reports/old.json = %s %s", resp.Status, body)
+	}
+	for _, name := range []string{"missing.json", "notes.txt", "..%2Fsecret.json"} {
+		resp, _ := http.Get(server.URL + "/reports/" + name)
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			t.Errorf("GET /reports/%s succeeded", name)
+		}
+	}
+}
+
+func TestAPIProgressStream(t *testing.T) {
+	stateDir := t.TempDir()
+	server := httptest.NewServer(newAPI(stateDir, "", "").Handler())
+	defer server.Close()
+
+	resp, _ := http.Get(server.URL + "/progress")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("GET /progress with no scan = %s, want 409", resp.Status)
+	}
+
+	d := &Daemon{stateDir: stateDir}
+	hub := d.serveAttach(nil)
+	line, _ := encodeEvent(scanEvent{Progress: &scanner.ScanProgress{Message: "Scanning movies"}})
+	hub.publish(line)
+
+	resp, err := http.Get(server.URL + "/progress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %s", ct)
+	}
+
+	line, _ = encodeEvent(scanEvent{Done: true, ReportPath: "/reports/scan.json", FinishedAt: time.Now()})
+	hub.finish(line)
+	defer hub.close()
+
+	body, _ := io.ReadAll(resp.Body)
+	stream := string(body)
+	if !strings.Contains(stream, "event: progress\ndata: ") || !strings.Contains(stream, `"Message":"Scanning movies"`) {
+		t.Errorf("Expected a progress event, got %q", stream)
+	}
+	if !strings.HasSuffix(stream, "event: done\ndata: {\"ReportPath\":\"/reports/scan.json\"}\n\n") {
+		t.Errorf("Expected the stream to end with the report, got %q", stream)
+	}
+}
+
+func TestAPIReportView(t *testing.T) {
+	reportDir := t.TempDir()
+	reporter.SetOutput(config.ReportsConfig{Dir: reportDir})
+	defer reporter.SetOutput(config.ReportsConfig{})
+
+	report := reporter.Report{
+		LibraryType: "movies",
+		MovieDuplicates: []scanner.MovieDuplicate{{ID: "m-heat", NormalizedName: "heat", Files: []scanner.MovieFile{
+			{Path: "/movies/Heat (1995)/Heat 2160p.mkv"},
+			{Path: "/movies/Heat (1995)/Heat 1080p.mkv"},
+		}}},
+	}
+	if err := reporter.WriteReport(filepath.Join(reportDir, "movies.json"), report); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(newAPI(t.TempDir(), "", "secret").Handler())
+	defer server.Close()
+	get := func(path string) (int, string) {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	// A browser passes the token in the query, and the page hands it to its images
+	code, page := get("/reports/movies.json/view?token=secret")
+	if code != http.StatusOK || !strings.Contains(page, `<img src="thumbnails/m-heat/1?token=secret"`) {
+		t.Errorf("GET /reports/movies.json/view = %d:\n%s", code, page)
+	}
+	if code, _ := get("/reports/movies.json/view"); code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without the token, got %d", code)
+	}
+
+	// Only files the report lists can be previewed
+	for path, want := range map[string]int{
+		"/reports/movies.json/thumbnails/m-other/0?token=secret": http.StatusNotFound,
+		"/reports/movies.json/thumbnails/m-heat/2?token=secret":  http.StatusNotFound,
+		"/reports/movies.json/thumbnails/m-heat/x?token=secret":  http.StatusBadRequest,
+		"/reports/missing.json/thumbnails/m-heat/0?token=secret": http.StatusNotFound,
+		"/reports/movies.json/thumbnails/m-heat/0?token=secret":  http.StatusNotFound, // listed, but gone from disk
+	} {
+		if code, body := get(path); code != want {
+			t.Errorf("GET %s = %d %s, want %d", path, code, body, want)
+		}
+	}
+}
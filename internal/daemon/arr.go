@@ -0,0 +1,243 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/arr"
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/jellyfin"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// arrRequestTimeout bounds each Sonarr/Radarr request made for a scan or rename
+const arrRequestTimeout = 30 * time.Second
+
+// arrLibrary is the series or movies one Sonarr/Radarr instance manages,
+// fetched on first use and kept for the life of the library
+type arrLibrary struct {
+	client   *arr.Client
+	mappings []jellyfin.PathMapping
+
+	once  sync.Once
+	items []arr.Item
+	err   error
+}
+
+// newArrLibrary returns the library for a configured instance, or nil when
+// its URL and API key are not both set
+func newArrLibrary(kind arr.Kind, cfg config.ArrConfig) *arrLibrary {
+	if cfg.URL == "" || cfg.APIKey == "" {
+		return nil
+	}
+	var mappings []jellyfin.PathMapping
+	for _, s := range cfg.PathMappings {
+		// Validate already rejected malformed entries
+		if m, err := jellyfin.ParsePathMapping(s); err == nil {
+			mappings = append(mappings, m)
+		}
+	}
+	return &arrLibrary{client: arr.NewClient(kind, cfg.URL, cfg.APIKey), mappings: mappings}
+}
+
+func (l *arrLibrary) load() ([]arr.Item, error) {
+	l.once.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), arrRequestTimeout)
+		defer cancel()
+		l.items, l.err = l.client.Items(ctx)
+	})
+	return l.items, l.err
+}
+
+// Name identifies the instance in scan messages
+func (l *arrLibrary) Name() string {
+	return string(l.client.Kind)
+}
+
+// Title implements scanner.TitleAuthority
+func (l *arrLibrary) Title(folder string, candidates ...string) (string, string, bool, error) {
+	items, err := l.load()
+	if err != nil {
+		return "", "", false, err
+	}
+	item := l.match(items, folder, candidates)
+	if item == nil {
+		return "", "", false, nil
+	}
+	year := ""
+	if item.Year > 0 {
+		year = strconv.Itoa(item.Year)
+	}
+	return item.Title, year, true, nil
+}
+
+// match finds the item stored in folder, or else the one whose title
+// matches a candidate, preferring the candidate's year when titles repeat
+// (remakes)
+func (l *arrLibrary) match(items []arr.Item, folder string, candidates []string) *arr.Item {
+	if folder != "" {
+		for i := range items {
+			if l.localPath(&items[i]) == filepath.Clean(folder) {
+				return &items[i]
+			}
+		}
+	}
+
+	titles := make([]string, 0, len(items))
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+	title := jellyfin.MatchTitle(titles, candidates...)
+	if title == "" {
+		return nil
+	}
+	var match *arr.Item
+	for i := range items {
+		if items[i].Title != title {
+			continue
+		}
+		if match == nil {
+			match = &items[i]
+		}
+		for _, candidate := range candidates {
+			if year := scanner.ExtractYear(candidate); year != "" && year == strconv.Itoa(items[i].Year) {
+				return &items[i]
+			}
+		}
+	}
+	return match
+}
+
+// owner returns the item whose folder is path or contains it
+func (l *arrLibrary) owner(items []arr.Item, path string) *arr.Item {
+	for i := range items {
+		folder := l.localPath(&items[i])
+		if path == folder || strings.HasPrefix(path, folder+string(filepath.Separator)) {
+			return &items[i]
+		}
+	}
+	return nil
+}
+
+func (l *arrLibrary) localPath(item *arr.Item) string {
+	return jellyfin.LocalPath(item.Path, l.mappings)
+}
+
+// follow points items at the folders their files were renamed into and
+// asks for a rescan of each item touched
+func (l *arrLibrary) follow(ctx context.Context, renames []scanner.RenameResult) error {
+	items, err := l.client.Items(ctx)
+	if err != nil {
+		return err
+	}
+
+	type update struct {
+		item   *arr.Item
+		folder string // new local folder; "" when the item stays put
+	}
+	var order []int
+	updates := make(map[int]*update)
+	for _, r := range renames {
+		item := l.owner(items, filepath.Clean(r.OldPath))
+		if item == nil {
+			continue
+		}
+		u, ok := updates[item.ID]
+		if !ok {
+			u = &update{item: item}
+			updates[item.ID] = u
+			order = append(order, item.ID)
+		}
+		if folder := movedFolder(l.localPath(item), r.OldPath, r.NewPath); folder != "" {
+			u.folder = folder
+		}
+	}
+
+	var errs []error
+	for _, id := range order {
+		u := updates[id]
+		if u.folder != "" {
+			if err := l.client.Relocate(ctx, id, jellyfin.ServerPath(u.folder, l.mappings)); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", u.item.Title, err))
+				continue
+			}
+		}
+		if err := l.client.Rescan(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", u.item.Title, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// movedFolder returns the folder an item in folder lives in after oldPath
+// (inside it) was renamed to newPath, or "" when it has not moved. The
+// item's folder is the part of newPath at the same depth as folder is in
+// oldPath, e.g. renaming "Show/Season 1/x.mkv" to "Show (2001)/Season 01/x.mkv"
+// moves the show to "Show (2001)"
+func movedFolder(folder, oldPath, newPath string) string {
+	rel, err := filepath.Rel(folder, filepath.Clean(oldPath))
+	if err != nil {
+		return ""
+	}
+	moved := filepath.Clean(newPath)
+	if rel != "." {
+		for range strings.Split(rel, string(filepath.Separator)) {
+			moved = filepath.Dir(moved)
+		}
+	}
+	if moved == folder {
+		return ""
+	}
+	return moved
+}
+
+// arrNotifier follows renames in every instance with rescan_after_rename
+type arrNotifier []*arrLibrary
+
+// NotifyRenames implements scanner.RenameNotifier
+func (n arrNotifier) NotifyRenames(renames []scanner.RenameResult) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*arrRequestTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, lib := range n {
+		if err := lib.follow(ctx, renames); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NewTitleAuthorities returns Sonarr and Radarr as scanner title
+// authorities; either is nil when not configured
+func NewTitleAuthorities(cfg *config.Config) (series, movies scanner.TitleAuthority) {
+	if lib := newArrLibrary(arr.Sonarr, cfg.Sonarr); lib != nil {
+		series = lib
+	}
+	if lib := newArrLibrary(arr.Radarr, cfg.Radarr); lib != nil {
+		movies = lib
+	}
+	return series, movies
+}
+
+// NewRenameNotifier returns the scanner's rename notifier for the Sonarr and
+// Radarr instances with rescan_after_rename, or nil when there are none
+func NewRenameNotifier(cfg *config.Config) scanner.RenameNotifier {
+	var n arrNotifier
+	if lib := newArrLibrary(arr.Sonarr, cfg.Sonarr); lib != nil && cfg.Sonarr.RescanAfterRename {
+		n = append(n, lib)
+	}
+	if lib := newArrLibrary(arr.Radarr, cfg.Radarr); lib != nil && cfg.Radarr.RescanAfterRename {
+		n = append(n, lib)
+	}
+	if len(n) == 0 {
+		return nil
+	}
+	return n
+}
@@ -0,0 +1,122 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// fakeArr serves a fixed item list and records relocations and rescans
+type fakeArr struct {
+	mu       sync.Mutex
+	items    string
+	paths    map[string]string // item id -> path set with PUT
+	commands []map[string]any
+}
+
+func (f *fakeArr) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.Method == http.MethodGet && (r.URL.Path == "/api/v3/series" || r.URL.Path == "/api/v3/movie"):
+		w.Write([]byte(f.items))
+	case r.Method == http.MethodGet:
+		w.Write([]byte(`{"id":1}`))
+	case r.Method == http.MethodPut:
+		var item map[string]any
+		json.NewDecoder(r.Body).Decode(&item)
+		f.paths[r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]] = item["path"].(string)
+	case r.Method == http.MethodPost:
+		var command map[string]any
+		json.NewDecoder(r.Body).Decode(&command)
+		f.commands = append(f.commands, command)
+	}
+}
+
+func TestArrTitleAuthority(t *testing.T) {
+	radarr := &fakeArr{items: `[
+		{"id":1,"title":"Dune","year":1984,"path":"/data/movies/Dune (1984)"},
+		{"id":2,"title":"Dune","year":2021,"path":"/data/movies/Dune (2021)"},
+		{"id":3,"title":"Heat","year":1995,"path":"/data/movies/Heat.1995.1080p-GRP"}
+	]`}
+	server := httptest.NewServer(radarr)
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Radarr = config.ArrConfig{URL: server.URL, APIKey: "key", PathMappings: []string{"/data/movies=/mnt/MOVIES"}}
+	series, movies := NewTitleAuthorities(cfg)
+	if series != nil || movies == nil {
+		t.Fatalf("Expected only Radarr configured, got %v, %v", series, movies)
+	}
+
+	title, year, ok, err := movies.Title("/mnt/MOVIES/Heat.1995.1080p-GRP", "Heat (1995)")
+	if err != nil || !ok || title != "Heat" || year != "1995" {
+		t.Errorf("Title() by folder = %q, %q, %v, %v", title, year, ok, err)
+	}
+	title, year, ok, _ = movies.Title("/mnt/OTHER/Dune.2021", "Dune (2021)")
+	if !ok || title != "Dune" || year != "2021" {
+		t.Errorf("Expected the remake matched by year, got %q, %q, %v", title, year, ok)
+	}
+	if _, _, ok, _ := movies.Title("", "Alien (1979)"); ok {
+		t.Error("Expected no match for a movie Radarr does not manage")
+	}
+}
+
+func TestArrFollowsRenames(t *testing.T) {
+	sonarr := &fakeArr{
+		items: `[{"id":4,"title":"Degrassi","year":2001,"path":"/tv/Degrassi"},{"id":5,"title":"Lost","year":2004,"path":"/tv/Lost (2004)"}]`,
+		paths: make(map[string]string),
+	}
+	server := httptest.NewServer(sonarr)
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Sonarr = config.ArrConfig{URL: server.URL, APIKey: "key", PathMappings: []string{"/tv=/mnt/TV"}, RescanAfterRename: true}
+	notifier := NewRenameNotifier(cfg)
+	if notifier == nil {
+		t.Fatal("Expected a notifier for Sonarr")
+	}
+
+	err := notifier.NotifyRenames([]scanner.RenameResult{
+		{OldPath: "/mnt/TV/Degrassi/Season 1/Degrassi S01E01.mkv", NewPath: "/mnt/TV/Degrassi (2001)/Season 01/Degrassi (2001) S01E01.mkv", Success: true},
+		{OldPath: "/mnt/TV/Lost (2004)/Season 01/lost.s01e01.mkv", NewPath: "/mnt/TV/Lost (2004)/Season 01/Lost (2004) S01E01.mkv", Success: true},
+		{OldPath: "/mnt/TV/Unmanaged/a.mkv", NewPath: "/mnt/TV/Unmanaged/b.mkv", Success: true},
+	})
+	if err != nil {
+		t.Fatalf("NotifyRenames failed: %v", err)
+	}
+
+	if len(sonarr.paths) != 1 || sonarr.paths["4"] != "/tv/Degrassi (2001)" {
+		t.Errorf("Expected only the moved show relocated in Sonarr's paths, got %v", sonarr.paths)
+	}
+	if len(sonarr.commands) != 2 || sonarr.commands[0]["seriesId"] != float64(4) || sonarr.commands[1]["seriesId"] != float64(5) {
+		t.Errorf("Expected rescans of both touched shows, got %v", sonarr.commands)
+	}
+
+	cfg.Sonarr.RescanAfterRename = false
+	if NewRenameNotifier(cfg) != nil {
+		t.Error("Expected no notifier with rescan_after_rename off")
+	}
+}
+
+func TestMovedFolder(t *testing.T) {
+	tests := []struct {
+		folder, oldPath, newPath, expected string
+	}{
+		{"/tv/Show", "/tv/Show", "/tv/Show (2001)", "/tv/Show (2001)"},
+		{"/tv/Show", "/tv/Show/Season 1/x.mkv", "/tv/Show (2001)/Season 01/x.mkv", "/tv/Show (2001)"},
+		{"/tv/Show", "/tv/Show/Season 01/x.mkv", "/tv/Show/Season 01/y.mkv", ""},
+		{"/movies/Heat-GRP", "/movies/Heat-GRP/heat.mkv", "/movies/Heat (1995)/Heat (1995).mkv", "/movies/Heat (1995)"},
+	}
+	for _, tt := range tests {
+		if got := movedFolder(tt.folder, tt.oldPath, tt.newPath); got != tt.expected {
+			t.Errorf("movedFolder(%s, %s, %s) = %q, want %q", tt.folder, tt.oldPath, tt.newPath, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// While scanning, the process holding the scan lock also serves its
+// progress on scan.sock in the data dir. A client that attaches gets the
+// recent progress, then every new line and finally the scan's outcome, in
+// the same JSON lines as the progress file
+
+const (
+	scanSocketName     = "scan.sock"
+	attachHistory      = 1000 // progress lines replayed to a client that attaches (fewer in low-memory mode)
+	attachBuffer       = 256  // lines queued per client before it is dropped
+	attachWriteTimeout = 10 * time.Second
+	attachDrainTimeout = 2 * time.Second // how long a finished scan waits for clients to read its outcome
+)
+
+// ErrNoScanRunning is returned by Attach when no scan is in progress
+var ErrNoScanRunning = errors.New("no scan is running")
+
+// progressHub streams the running scan's progress to attached clients
+type progressHub struct {
+	listener   net.Listener
+	acceptDone chan struct{}
+	streams    sync.WaitGroup
+
+	mu       sync.Mutex
+	history  [][]byte
+	clients  map[chan []byte]bool
+	finished bool
+}
+
+// serveAttach opens the attach socket for the scan about to run. A socket
+// that cannot be opened only means nobody can attach
+func (d *Daemon) serveAttach(progressCh chan<- scanner.ScanProgress) *progressHub {
+	path := filepath.Join(d.stateDir, scanSocketName)
+	// A socket left here is stale: its scan no longer holds the lock
+	os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		notify(progressCh, scanner.SeverityWarn, fmt.Sprintf("Attaching to this scan unavailable: %v", err))
+		return nil
+	}
+	shareWithOwner(d.stateDir, path)
+
+	hub := &progressHub{
+		listener:   listener,
+		acceptDone: make(chan struct{}),
+		clients:    make(map[chan []byte]bool),
+	}
+	go hub.accept()
+	return hub
+}
+
+// accept starts a stream for every client until the listener closes
+func (h *progressHub) accept() {
+	defer close(h.acceptDone)
+	for {
+		conn, err := h.listener.Accept()
+		if err != nil {
+			return
+		}
+		h.streams.Add(1)
+		go h.stream(conn)
+	}
+}
+
+// stream writes the history and then the live lines to one client
+func (h *progressHub) stream(conn net.Conn) {
+	defer h.streams.Done()
+	defer conn.Close()
+
+	history, ch := h.subscribe()
+	write := func(line []byte) bool {
+		conn.SetWriteDeadline(time.Now().Add(attachWriteTimeout))
+		_, err := conn.Write(line)
+		return err == nil
+	}
+	for _, line := range history {
+		if !write(line) {
+			h.unsubscribe(ch)
+			return
+		}
+	}
+	for line := range ch {
+		if !write(line) {
+			h.unsubscribe(ch)
+			return
+		}
+	}
+}
+
+// subscribe returns the lines so far and a channel for the ones to come,
+// closed once the scan has finished
+func (h *progressHub) subscribe() ([][]byte, chan []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	history := append([][]byte(nil), h.history...)
+	ch := make(chan []byte, attachBuffer)
+	if h.finished {
+		close(ch)
+	} else {
+		h.clients[ch] = true
+	}
+	return history, ch
+}
+
+func (h *progressHub) unsubscribe(ch chan []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.clients[ch] {
+		delete(h.clients, ch)
+		close(ch)
+	}
+}
+
+// publish sends a line to every attached client. A nil hub publishes nothing
+func (h *progressHub) publish(line []byte) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.history = append(h.history, line)
+	if limit := scanner.LogLimit(attachHistory); len(h.history) > limit {
+		h.history = h.history[len(h.history)-limit:]
+	}
+	for ch := range h.clients {
+		select {
+		case ch <- line:
+		default:
+			// A client this far behind would stall the scan; drop it
+			delete(h.clients, ch)
+			close(ch)
+		}
+	}
+}
+
+// finish publishes the scan's outcome and ends every stream after it
+func (h *progressHub) finish(line []byte) {
+	if h == nil {
+		return
+	}
+	h.publish(line)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.clients {
+		close(ch)
+	}
+	h.clients = nil
+	h.finished = true
+}
+
+// close stops accepting clients and gives attached ones a moment to read
+// the outcome before the process moves on
+func (h *progressHub) close() {
+	if h == nil {
+		return
+	}
+	h.listener.Close()
+	<-h.acceptDone
+
+	drained := make(chan struct{})
+	go func() {
+		h.streams.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-time.After(attachDrainTimeout):
+	}
+}
+
+// Attach connects to the scan running in another process and relays its
+// progress to progressCh until it finishes, returning its report path
+func (d *Daemon) Attach(ctx context.Context, progressCh chan<- scanner.ScanProgress) (string, error) {
+	conn, err := net.Dial("unix", filepath.Join(d.stateDir, scanSocketName))
+	if err != nil {
+		return "", ErrNoScanRunning
+	}
+	defer conn.Close()
+
+	// Closing the connection unblocks the read when ctx is cancelled
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			return "", fmt.Errorf("lost connection to the running scan: %w", err)
+		}
+
+		var event scanEvent
+		if json.Unmarshal(line, &event) != nil {
+			continue
+		}
+		if event.Done {
+			if event.Error != "" {
+				return "", errors.New(event.Error)
+			}
+			return event.ReportPath, nil
+		}
+		relayProgress([]scanEvent{event}, progressCh)
+	}
+}
+
+// ScanRunning reports whether a scan is in progress that Attach can join
+func (d *Daemon) ScanRunning() bool {
+	conn, err := net.Dial("unix", filepath.Join(d.stateDir, scanSocketName))
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestAttachRelaysRunningScan(t *testing.T) {
+	d := &Daemon{stateDir: t.TempDir()}
+	if d.ScanRunning() {
+		t.Fatal("Expected no scan running before the socket is served")
+	}
+	if _, err := d.Attach(context.Background(), nil); err != ErrNoScanRunning {
+		t.Fatalf("Attach() error = %v, want ErrNoScanRunning", err)
+	}
+
+	hub := d.serveAttach(nil)
+	if hub == nil {
+		t.Fatal("serveAttach() failed")
+	}
+	publish := func(event scanEvent) {
+		line, err := encodeEvent(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.Done {
+			hub.finish(line)
+		} else {
+			hub.publish(line)
+		}
+	}
+	publish(scanEvent{Progress: &scanner.ScanProgress{Message: "Counting files"}})
+
+	progressCh := make(chan scanner.ScanProgress, 10)
+	type result struct {
+		path string
+		err  error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		path, err := d.Attach(context.Background(), progressCh)
+		resultCh <- result{path, err}
+	}()
+
+	// Progress from before attaching is replayed, then live lines follow
+	for _, want := range []string{"Counting files", "Scanning movies"} {
+		select {
+		case p := <-progressCh:
+			if p.Message != want || !p.Relayed {
+				t.Errorf("Got %+v, want relayed %q", p, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for %q", want)
+		}
+		if want == "Counting files" {
+			publish(scanEvent{Progress: &scanner.ScanProgress{Message: "Scanning movies"}})
+		}
+	}
+
+	publish(scanEvent{Done: true, ReportPath: "/reports/scan.json", FinishedAt: time.Now()})
+	hub.close()
+	select {
+	case r := <-resultCh:
+		if r.err != nil || r.path != "/reports/scan.json" {
+			t.Errorf("Attach() = %q, %v; want the scan's report", r.path, r.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Attach() did not return after the scan finished")
+	}
+	if d.ScanRunning() {
+		t.Error("Expected the socket closed once the scan finished")
+	}
+}
+
+func TestAttachReportsFailedScan(t *testing.T) {
+	d := &Daemon{stateDir: t.TempDir()}
+	hub := d.serveAttach(nil)
+	line, _ := encodeEvent(scanEvent{Done: true, Error: "scan failed: disk gone", FinishedAt: time.Now()})
+	hub.finish(line)
+	defer hub.close()
+
+	// A client arriving after the outcome still gets it
+	if _, err := d.Attach(context.Background(), nil); err == nil || err.Error() != "scan failed: disk gone" {
+		t.Errorf("Attach() error = %v, want the scan's failure", err)
+	}
+}
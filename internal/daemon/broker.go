@@ -0,0 +1,294 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// Only one scan runs at a time across jellysinkd, the CLI and the TUI. The
+// process scanning holds an flock on scan.lock in the data dir and mirrors
+// its progress into scan.progress as JSON lines (and to attached clients,
+// see attach.go). A scan requested meanwhile queues behind it, relays that
+// file to its own progress channel and is answered with the running scan's
+// report instead of scanning again
+
+const (
+	scanLockName       = "scan.lock"
+	scanProgressName   = "scan.progress"
+	brokerPollInterval = 250 * time.Millisecond
+)
+
+// scanEvent is one line of the progress file: a progress message, or the
+// scan's outcome on the final line
+type scanEvent struct {
+	Progress   *scanner.ScanProgress `json:",omitempty"`
+	Done       bool                  `json:",omitempty"`
+	ReportPath string                `json:",omitempty"`
+	Error      string                `json:",omitempty"`
+	FinishedAt time.Time
+}
+
+// defaultStateDir returns the data dir shared by jellysinkd and by the CLI
+// and TUI running with sudo
+func defaultStateDir() string {
+	return oplog.DataDir()
+}
+
+// scanLock is the held scan lock
+type scanLock struct {
+	file *os.File
+}
+
+// tryScanLock takes the scan lock without blocking; held is false when
+// another process is scanning
+func tryScanLock(dir string) (lock *scanLock, held bool, err error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, false, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, scanLockName)
+	file, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open scan lock: %w", err)
+	}
+	shareWithOwner(dir, path)
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return &scanLock{file: file}, true, nil
+}
+
+// release frees the lock for the next queued scan
+func (l *scanLock) release() {
+	syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+	l.file.Close()
+}
+
+// shareWithOwner hands a file created with sudo to the owner of dir, so
+// jellysinkd running as that user can still lock and write it
+func shareWithOwner(dir, path string) {
+	if os.Geteuid() != 0 {
+		return
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok && stat.Uid != 0 {
+		os.Chown(path, int(stat.Uid), int(stat.Gid))
+	}
+}
+
+// publishScan runs the scan while holding the lock, mirroring its progress
+// and outcome into the progress file for queued requests and to clients
+// attached on the socket
+func (d *Daemon) publishScan(ctx context.Context, progressCh chan<- scanner.ScanProgress) (string, error) {
+	hub := d.serveAttach(progressCh)
+	defer hub.close()
+
+	var w io.Writer = io.Discard
+	path := filepath.Join(d.stateDir, scanProgressName)
+	if file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644); err != nil {
+		// Queued requests then see no progress and scan again themselves
+		notify(progressCh, scanner.SeverityWarn, fmt.Sprintf("Scan progress not shared: %v", err))
+	} else {
+		defer file.Close()
+		shareWithOwner(d.stateDir, path)
+		w = file
+	}
+
+	teeCh := make(chan scanner.ScanProgress, 100)
+	teeDone := make(chan struct{})
+	go func() {
+		defer close(teeDone)
+		teeProgress(teeCh, w, hub, progressCh)
+	}()
+
+	reportPath, scanErr := d.scan(ctx, teeCh)
+	close(teeCh)
+	<-teeDone
+
+	done := scanEvent{Done: true, ReportPath: reportPath, FinishedAt: time.Now()}
+	if scanErr != nil {
+		done.Error = scanErr.Error()
+	}
+	if line, err := encodeEvent(done); err == nil {
+		w.Write(line)
+		hub.finish(line)
+	}
+	return reportPath, scanErr
+}
+
+// teeProgress writes each message from in to w as a JSON line, publishes it
+// to attached clients and forwards it to out (when set) until in is closed
+func teeProgress(in <-chan scanner.ScanProgress, w io.Writer, hub *progressHub, out chan<- scanner.ScanProgress) {
+	for p := range in {
+		if line, err := encodeEvent(scanEvent{Progress: &p}); err == nil {
+			w.Write(line)
+			hub.publish(line)
+		}
+		if out != nil {
+			out <- p
+		}
+	}
+}
+
+// encodeEvent returns event as one newline-terminated JSON line
+func encodeEvent(event scanEvent) ([]byte, error) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
+
+// followRunningScan queues behind the process holding the scan lock and
+// relays its progress until the lock is free. It returns holding the lock,
+// with the finished scan's report when that scan succeeded after queuedAt
+func (d *Daemon) followRunningScan(ctx context.Context, progressCh chan<- scanner.ScanProgress, queuedAt time.Time) (*scanLock, string, error) {
+	notify(progressCh, scanner.SeverityWarn, "A scan is already running - queued behind it, showing its progress")
+
+	path := filepath.Join(d.stateDir, scanProgressName)
+	tail := &progressTail{path: path}
+	defer tail.close()
+
+	ticker := time.NewTicker(brokerPollInterval)
+	defer ticker.Stop()
+	for {
+		relayProgress(tail.next(), progressCh)
+
+		lock, held, err := tryScanLock(d.stateDir)
+		if err != nil {
+			return nil, "", err
+		}
+		if held {
+			relayProgress(tail.next(), progressCh)
+			// Nobody else writes the file while we hold the lock
+			if done := lastScanOutcome(path); done != nil && done.Error == "" && !done.FinishedAt.Before(queuedAt) {
+				if _, err := os.Stat(done.ReportPath); err == nil {
+					return lock, done.ReportPath, nil
+				}
+			}
+			notify(progressCh, scanner.SeverityWarn, "The running scan did not finish - starting a new scan")
+			return lock, "", nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// relayProgress forwards the progress lines of events to progressCh
+func relayProgress(events []scanEvent, progressCh chan<- scanner.ScanProgress) {
+	if progressCh == nil {
+		return
+	}
+	for _, event := range events {
+		if event.Progress != nil {
+			p := *event.Progress
+			p.Relayed = true
+			progressCh <- p
+		}
+	}
+}
+
+// lastScanOutcome returns the final line of the progress file, or nil when
+// the scan that wrote it never finished
+func lastScanOutcome(path string) *scanEvent {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var outcome *scanEvent
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var event scanEvent
+		if json.Unmarshal(line, &event) == nil && event.Done {
+			outcome = &event
+		}
+	}
+	return outcome
+}
+
+// progressTail reads the lines appended to the progress file since the
+// last call, restarting when a new scan truncates it
+type progressTail struct {
+	path    string
+	file    *os.File
+	offset  int64
+	partial []byte
+}
+
+// next returns the complete events written since the previous call
+func (t *progressTail) next() []scanEvent {
+	if t.file == nil {
+		file, err := os.Open(t.path)
+		if err != nil {
+			return nil
+		}
+		t.file = file
+	}
+	info, err := t.file.Stat()
+	if err != nil {
+		return nil
+	}
+	if info.Size() < t.offset {
+		t.offset = 0
+		t.partial = nil
+	}
+
+	data, err := io.ReadAll(io.NewSectionReader(t.file, t.offset, info.Size()-t.offset))
+	if err != nil {
+		return nil
+	}
+	t.offset += int64(len(data))
+
+	lines := bytes.Split(append(t.partial, data...), []byte("\n"))
+	// The last piece is an unfinished line (or empty); keep it for later
+	t.partial = append([]byte(nil), lines[len(lines)-1]...)
+
+	var events []scanEvent
+	for _, line := range lines[:len(lines)-1] {
+		var event scanEvent
+		if json.Unmarshal(line, &event) == nil {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func (t *progressTail) close() {
+	if t.file != nil {
+		t.file.Close()
+	}
+}
+
+// notify sends a whole-scan message to progressCh when there is one
+func notify(progressCh chan<- scanner.ScanProgress, severity scanner.ProgressSeverity, message string) {
+	if progressCh == nil {
+		return
+	}
+	progressCh <- scanner.ScanProgress{
+		Operation: scanner.OpScan,
+		Stage:     "scanning",
+		Message:   message,
+		Severity:  severity,
+	}
+}
@@ -0,0 +1,115 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestQueuedScanFollowsRunningScan(t *testing.T) {
+	d := &Daemon{config: config.DefaultConfig(), stateDir: t.TempDir()}
+
+	// Another process is scanning
+	lock, held, err := tryScanLock(d.stateDir)
+	if err != nil || !held {
+		t.Fatalf("tryScanLock() = %v, %v", held, err)
+	}
+	progressFile, err := os.Create(filepath.Join(d.stateDir, scanProgressName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer progressFile.Close()
+	enc := json.NewEncoder(progressFile)
+	enc.Encode(scanEvent{Progress: &scanner.ScanProgress{Operation: scanner.OpScanningMovies, Message: "Scanning movies"}})
+
+	progressCh := make(chan scanner.ScanProgress, 10)
+	type result struct {
+		path string
+		err  error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		path, err := d.RunScanWithProgress(context.Background(), progressCh)
+		resultCh <- result{path, err}
+	}()
+
+	// The queued request relays the running scan's progress
+	var relayed scanner.ScanProgress
+	for relayed.Message != "Scanning movies" {
+		select {
+		case relayed = <-progressCh:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected the running scan's progress to be relayed")
+		}
+	}
+	if !relayed.Relayed {
+		t.Error("Expected relayed progress to be marked as such")
+	}
+
+	// The running scan finishes and its report answers the queued request
+	reportPath := filepath.Join(d.stateDir, "report.json")
+	os.WriteFile(reportPath, []byte("{}"), 0644)
+	enc.Encode(scanEvent{Done: true, ReportPath: reportPath, FinishedAt: time.Now()})
+	lock.release()
+
+	select {
+	case r := <-resultCh:
+		if r.err != nil || r.path != reportPath {
+			t.Errorf("RunScanWithProgress() = %q, %v; want the running scan's report", r.path, r.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Queued scan did not finish after the lock was released")
+	}
+
+	// The queued request gave the lock back
+	if lock, held, _ := tryScanLock(d.stateDir); !held {
+		t.Error("Expected the scan lock to be free")
+	} else {
+		lock.release()
+	}
+}
+
+func TestQueuedScanStopsOnCancel(t *testing.T) {
+	d := &Daemon{config: config.DefaultConfig(), stateDir: t.TempDir()}
+	lock, _, err := tryScanLock(d.stateDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lock.release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := d.RunScanWithProgress(ctx, nil); err != context.DeadlineExceeded {
+		t.Errorf("Expected the queued scan to stop with its context, got %v", err)
+	}
+}
+
+func TestProgressTailKeepsPartialLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), scanProgressName)
+	os.WriteFile(path, []byte(`{"Progress":{"Message":"one"}}`+"\n"+`{"Progress":{"Mess`), 0644)
+
+	tail := &progressTail{path: path}
+	defer tail.close()
+	if events := tail.next(); len(events) != 1 || events[0].Progress.Message != "one" {
+		t.Fatalf("Expected only the complete line, got %+v", events)
+	}
+
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(`age":"two"}}` + "\n")
+	f.Close()
+	if events := tail.next(); len(events) != 1 || events[0].Progress.Message != "two" {
+		t.Errorf("Expected the finished line, got %+v", events)
+	}
+
+	// A new scan truncates the file
+	os.WriteFile(path, []byte(`{"Progress":{"Message":"new"}}`+"\n"), 0644)
+	if events := tail.next(); len(events) != 1 || events[0].Progress.Message != "new" {
+		t.Errorf("Expected the tail to restart after truncation, got %+v", events)
+	}
+}
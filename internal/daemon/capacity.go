@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// CapacityWarnDays is how close to full a mount is flagged
+const CapacityWarnDays = 30
+
+// statsHistoryPath is where library sizes are recorded (replaced in tests)
+var statsHistoryPath = scanner.DefaultStatsHistoryPath
+
+// Capacity is the library sizes just recorded and the projection they make
+type Capacity struct {
+	Libraries   []scanner.LibraryStats
+	Samples     []scanner.LibrarySample // the whole stats history, oldest first
+	Projections []scanner.CapacityProjection
+}
+
+// RecordCapacity adds the current size of every configured library to the
+// stats history and projects when the mounts holding them fill up.
+// Libraries that cannot be read are left out and reported in the error
+func RecordCapacity(cfg *config.Config) (Capacity, error) {
+	var capacity Capacity
+	var failures []error
+	collect := func(paths []string, libraryType string) {
+		for _, path := range paths {
+			s, err := scanner.CollectLibraryStats(path, libraryType, 0)
+			if err != nil {
+				failures = append(failures, err)
+				continue
+			}
+			capacity.Libraries = append(capacity.Libraries, s)
+		}
+	}
+	collect(cfg.MoviePaths(), "movies")
+	collect(cfg.ShowPaths(), "tv")
+
+	now := time.Now()
+	samples, err := scanner.RecordLibraryStats(statsHistoryPath(), capacity.Libraries, now)
+	if err != nil {
+		return capacity, err
+	}
+	capacity.Samples = samples
+	capacity.Projections = scanner.ProjectCapacity(cfg.GetAllPaths(), samples, now)
+	if len(failures) > 0 {
+		return capacity, fmt.Errorf("library sizes not recorded: %w", failures[0])
+	}
+	return capacity, nil
+}
+
+// capacityProjections projects when the library mounts fill up from the
+// stats history as last recorded
+func capacityProjections(cfg *config.Config) ([]scanner.CapacityProjection, error) {
+	samples, err := scanner.LoadStatsHistory(statsHistoryPath())
+	if err != nil {
+		return nil, err
+	}
+	return scanner.ProjectCapacity(cfg.GetAllPaths(), samples, time.Now()), nil
+}
+
+// CapacityLine formats a projection, flagging mounts that fill up within
+// CapacityWarnDays
+func CapacityLine(p scanner.CapacityProjection) string {
+	if days := p.DaysLeft(); days >= 0 && days < CapacityWarnDays {
+		return "LOW SPACE " + p.String()
+	}
+	return p.String()
+}
@@ -0,0 +1,38 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+)
+
+// confirmDuplicates narrows report to the duplicate groups that appeared
+// unchanged in the daemon.auto_clean_confirm_scans-1 scans before it, so a
+// one-off parsing glitch never gets a file deleted. Without enough earlier
+// reports every group is held
+func (d *Daemon) confirmDuplicates(report reporter.Report) reporter.Report {
+	need := d.config.Daemon.AutoCleanConfirmScans - 1
+	if need <= 0 || len(report.MovieDuplicates)+len(report.TVDuplicates) == 0 {
+		return report
+	}
+
+	_, earlier, err := reporter.PreviousReports(reporter.ReportDir(), "", report, need)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if len(earlier) < need {
+		held := len(report.MovieDuplicates) + len(report.TVDuplicates)
+		fmt.Printf("Holding %d duplicate group(s): auto_clean_confirm_scans = %d but only %d earlier scan(s) of this library were found\n",
+			held, d.config.Daemon.AutoCleanConfirmScans, len(earlier))
+		report.MovieDuplicates, report.TVDuplicates = nil, nil
+		report.RecountTotals()
+		return report
+	}
+
+	confirmed, held := reporter.ConfirmDuplicates(report, earlier)
+	if held > 0 {
+		fmt.Printf("Holding %d duplicate group(s) not yet seen unchanged in %d scans in a row\n", held, d.config.Daemon.AutoCleanConfirmScans)
+	}
+	return confirmed
+}
@@ -0,0 +1,85 @@
+package daemon
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// confirmTestReport is a scan of /movies at day with the Heat and Alien
+// duplicate groups; alienKeeper is the copy the scan would keep
+func confirmTestReport(day int, alienKeeper string) reporter.Report {
+	alien := []scanner.MovieFile{
+		{Path: "/movies/Alien (1979)/Alien (1979) 1080p.mkv", Size: 2000},
+		{Path: "/movies/Alien (1979)/Alien (1979) 720p.mkv", Size: 1000},
+	}
+	if alienKeeper != alien[0].Path {
+		alien[0], alien[1] = alien[1], alien[0]
+	}
+	report := reporter.Report{
+		Timestamp:    time.Date(2026, 3, day, 2, 0, 0, 0, time.UTC),
+		LibraryType:  "movies",
+		LibraryPaths: []string{"/movies"},
+		MovieDuplicates: []scanner.MovieDuplicate{
+			{NormalizedName: "heat", Year: "1995", Files: []scanner.MovieFile{
+				{Path: "/movies/Heat (1995)/Heat (1995) 2160p.mkv", Size: 4000},
+				{Path: "/movies/Heat (1995)/Heat (1995) 1080p.mkv", Size: 2000},
+			}},
+			{NormalizedName: "alien", Year: "1979", Files: alien},
+		},
+	}
+	report.RecountTotals()
+	return report
+}
+
+func TestConfirmDuplicates(t *testing.T) {
+	reportDir := t.TempDir()
+	reporter.SetOutput(config.ReportsConfig{Dir: reportDir})
+	defer reporter.SetOutput(config.ReportsConfig{})
+
+	cfg := config.DefaultConfig()
+	cfg.Daemon.AutoCleanConfirmScans = 3
+	d := &Daemon{config: cfg}
+
+	latest := confirmTestReport(15, "/movies/Alien (1979)/Alien (1979) 1080p.mkv")
+
+	// Without two earlier scans nothing is deleted yet
+	if got := d.confirmDuplicates(latest); len(got.MovieDuplicates) != 0 || got.TotalFilesToDelete != 0 {
+		t.Errorf("Expected every group held without earlier scans, got %d", len(got.MovieDuplicates))
+	}
+
+	// A week ago the Alien group picked the other keeper, as a parsing
+	// glitch would; Heat was the same all three times
+	for day, keeper := range map[int]string{
+		1: "/movies/Alien (1979)/Alien (1979) 1080p.mkv",
+		8: "/movies/Alien (1979)/Alien (1979) 720p.mkv",
+	} {
+		path := filepath.Join(reportDir, fmt.Sprintf("movies_%02d.json", day))
+		if err := reporter.WriteReport(path, confirmTestReport(day, keeper)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Other libraries do not count
+	other := confirmTestReport(10, "/movies/Alien (1979)/Alien (1979) 1080p.mkv")
+	other.LibraryPaths = []string{"/other"}
+	reporter.WriteReport(filepath.Join(reportDir, "movies_other.json"), other)
+
+	got := d.confirmDuplicates(latest)
+	if len(got.MovieDuplicates) != 1 || got.MovieDuplicates[0].NormalizedName != "heat" {
+		t.Fatalf("Expected only the Heat group confirmed, got %+v", got.MovieDuplicates)
+	}
+	if got.TotalFilesToDelete != 1 || got.SpaceToFree != 2000 {
+		t.Errorf("Expected totals recounted for the confirmed group, got %d files, %d bytes", got.TotalFilesToDelete, got.SpaceToFree)
+	}
+
+	// auto_clean_confirm_scans = 1 deletes on first sight
+	cfg.Daemon.AutoCleanConfirmScans = 1
+	if got := d.confirmDuplicates(latest); len(got.MovieDuplicates) != 2 {
+		t.Errorf("Expected no confirmation with auto_clean_confirm_scans = 1, got %d groups", len(got.MovieDuplicates))
+	}
+}
@@ -2,22 +2,32 @@ package daemon
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"time"
 
 	"github.com/Nomadcxx/jellysink/internal/cleaner"
 	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/ignore"
+	"github.com/Nomadcxx/jellysink/internal/oplog"
 	"github.com/Nomadcxx/jellysink/internal/reporter"
 	"github.com/Nomadcxx/jellysink/internal/scanner"
+	"github.com/Nomadcxx/jellysink/internal/tags"
 )
 
 // Daemon represents the background service
 type Daemon struct {
 	config       *config.Config
 	headlessMode bool
+	stateDir     string // holds the scan lock and shared progress
+	incremental  bool   // rescan only what changed since the last incremental scan
+	indexPath    string // scan index incremental scans compare against
+	resumeFrom   string // partial report of the cancelled scan to finish
+
+	indexedReport string // report the scan index was last saved with by this daemon
 }
 
 // New creates a new daemon instance
@@ -33,12 +43,129 @@ func New(cfg *config.Config) *Daemon {
 		}
 	}
 
+	// Where data lives, what cleans leave alone and how libraries are read
+	if cfg != nil {
+		ApplyConfig(cfg)
+	}
+
+	// Compliance checks follow the configured naming profile
+	if cfg != nil && cfg.Naming.Profile != "" {
+		if profile, err := scanner.ParseNamingProfile(cfg.Naming.Profile); err == nil {
+			scanner.SetNamingProfile(profile)
+		}
+	}
+	// Unset exclude_dirs keeps the scanner's NAS/system defaults
+	if cfg != nil && cfg.Libraries.ExcludeDirs != nil {
+		scanner.SetExcludedDirs(cfg.Libraries.ExcludeDirs)
+	}
+	// Ambiguous TV titles (and, with TMDB, movie titles) are verified with
+	// the enabled API providers
+	if cfg != nil {
+		var tvdbKey, omdbKey, tmdbKey string
+		if cfg.API.TVDB.Enabled {
+			tvdbKey = cfg.API.TVDB.APIKey
+		}
+		if cfg.API.OMDB.Enabled {
+			omdbKey = cfg.API.OMDB.APIKey
+		}
+		if cfg.API.TMDB.Enabled {
+			tmdbKey = cfg.API.TMDB.APIKey
+		}
+		scanner.SetAPIKeys(tvdbKey, omdbKey, tmdbKey)
+		scanner.SetAPIFailureThreshold(cfg.API.FailureThreshold)
+		scanner.SetAPICachePersistence(scanner.DefaultAPICachePath(), time.Duration(cfg.API.CacheTTLDays)*24*time.Hour)
+		if err := scanner.SetAPIHTTPOptions(cfg.API.ProxyURL, cfg.API.CABundle); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: API proxy/CA settings ignored: %v\n", err)
+		}
+	}
+	// Sonarr/Radarr names win over TVDB/OMDB
+	if cfg != nil {
+		scanner.SetTitleAuthorities(NewTitleAuthorities(cfg))
+	}
+	// Unset lowercase_words keeps the scanner's English small-word list
+	if cfg != nil && cfg.Naming.LowercaseWords != nil {
+		scanner.SetLowercaseWords(cfg.Naming.LowercaseWords)
+	}
+	// Suggested episode names get TVDB episode titles when enabled
+	if cfg != nil {
+		scanner.SetEpisodeTitles(cfg.Naming.EpisodeTitles)
+	}
+	// Show folders without a year get one from TVDB or TMDB when enabled
+	if cfg != nil {
+		scanner.SetShowFolderYears(cfg.Naming.ShowFolderYears)
+	}
+	// API title conflicts with a clear confidence lead skip manual review
+	if cfg != nil {
+		scanner.SetAutoResolveMargin(cfg.Naming.AutoResolveMargin)
+	}
+	// Report sections are collated for ui.sort_locale
+	if cfg != nil {
+		if err := scanner.SetSortOptions(cfg.UI.SortLocale, cfg.UI.IgnoreArticles); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ui sort settings ignored: %v\n", err)
+		}
+	}
+
 	return &Daemon{
 		config:       cfg,
 		headlessMode: detectHeadlessMode(),
+		stateDir:     defaultStateDir(),
+		indexPath:    scanner.DefaultIndexPath(),
 	}
 }
 
+// ApplyConfig installs the settings of cfg that every scan and clean relies
+// on, whichever binary runs it: where data and reports are kept, what cleans
+// leave alone, defer or link instead of delete, and how duplicates are
+// matched and libraries read. New applies it as well, so a clean can't run
+// without protected_paths just because its caller skipped a step
+func ApplyConfig(cfg *config.Config) {
+	oplog.SetDataDir(cfg.DataDir)
+	// Report dir and filename template come from [reports]
+	reporter.SetOutput(cfg.Reports)
+
+	// Cleans defer files that are playing in Jellyfin or held open locally,
+	// then ask Jellyfin to rescan
+	cleaner.SetInUseChecker(NewInUseChecker(cfg))
+	cleaner.SetLibraryRefresher(NewLibraryRefresher(cfg))
+	cleaner.SetTrashDir(cfg.Cleaner.TrashDir)
+	cleaner.SetProtectedPaths(cfg.Cleaner.ProtectedPaths)
+	cleaner.SetHardlinkDuplicates(cfg.Cleaner.HardlinkDuplicates)
+	// Tags steer which duplicate is kept and which paths cleans leave alone
+	tags.SetRules(NewTagRules(cfg))
+	// Sonarr/Radarr follow renamed folders
+	scanner.SetRenameNotifier(NewRenameNotifier(cfg))
+
+	// Movie duplicate groups without their own choice follow [duplicates]
+	if cfg.Duplicates.Strategy != "" {
+		if strategy, err := scanner.ParseDuplicateStrategy(cfg.Duplicates.Strategy); err == nil {
+			scanner.SetDuplicateStrategy(strategy)
+		}
+	}
+	// Copies are matched within [duplicates] scope unless a library sets its own
+	scanner.SetDuplicateScopes(NewDuplicateScopes(cfg))
+	// Groups without their own choice follow [duplicates] merge_companions
+	scanner.SetMergeCompanions(cfg.Duplicates.MergeCompanions)
+	// Anime libraries are scanned as TV with anime numbering and naming
+	scanner.SetAnimeLibraries(cfg.AnimePaths())
+	scanner.SetAniListLookup(cfg.Libraries.Anime.Lookup == "anilist")
+	// Duplicates are ranked on their probed streams unless ffprobe is off
+	scanner.SetFFprobe(cfg.Duplicates.FFprobe)
+	// Other editions and resolutions of a movie can be kept as versions
+	scanner.SetAllowVersions(cfg.Scan.AllowVersions)
+	// Episodes far off their season's size per minute are reported
+	scanner.SetBitrateOutlierFactor(cfg.Scan.BitrateOutlierFactor)
+	// Probed audio and subtitle languages are checked against [languages]
+	scanner.SetLanguageRules(NewLanguageRules(cfg))
+	// scan.exclude patterns apply on top of .jellysinkignore files
+	if patterns, err := ignore.CompileAll(cfg.Scan.Exclude); err == nil {
+		scanner.SetExcludePatterns(patterns)
+	}
+	// Small NAS boxes trade scan speed for memory
+	scanner.SetLowMemory(cfg.Performance.LowMemory)
+	// Libraries on cloud mounts are read and listed gently
+	scanner.SetCloudSafe(cfg.Performance.CloudSafe, cfg.Performance.CloudListRate)
+}
+
 // detectHeadlessMode checks if running in a headless environment (no display available)
 func detectHeadlessMode() bool {
 	display := os.Getenv("DISPLAY")
@@ -58,19 +185,132 @@ func (d *Daemon) RunScan(ctx context.Context) (string, error) {
 }
 
 // RunScanWithProgress executes a full scan with progress reporting
+// A request made while another process is scanning is queued: it relays
+// that scan's progress and returns its report, scanning itself only when
+// the running scan fails or is cancelled
+// A scan cancelled after some sections finished returns the path of the
+// partial report it saved along with the cancellation error
 func (d *Daemon) RunScanWithProgress(ctx context.Context, progressCh chan<- scanner.ScanProgress) (string, error) {
+	queuedAt := time.Now()
+	lock, held, err := tryScanLock(d.stateDir)
+	if err != nil {
+		// Without the lock scans just are not serialized
+		notify(progressCh, scanner.SeverityWarn, fmt.Sprintf("Scan queueing unavailable: %v", err))
+		return d.scan(ctx, progressCh)
+	}
+	if !held {
+		var reportPath string
+		lock, reportPath, err = d.followRunningScan(ctx, progressCh, queuedAt)
+		if err != nil {
+			return "", err
+		}
+		if reportPath != "" {
+			lock.release()
+			return reportPath, nil
+		}
+	}
+	defer lock.release()
+
+	return d.publishScan(ctx, progressCh)
+}
+
+// scan runs the scan itself and saves the report
+func (d *Daemon) scan(ctx context.Context, progressCh chan<- scanner.ScanProgress) (reportPath string, err error) {
+	var report reporter.Report
+	defer func() { logScan(progressCh, reportPath, report, err) }()
+
 	// Use orchestrator for coordinated scanning with progress
-	scanResult, err := scanner.RunFullScan(
-		ctx,
-		d.config.Libraries.Movies.Paths,
-		d.config.Libraries.TV.Paths,
-		progressCh,
+	var (
+		idx        *scanner.ScanIndex
+		scanResult *scanner.ScanResult
 	)
+	if d.resumeFrom != "" {
+		scanResult, err = d.scanResumed(ctx, progressCh)
+	} else if d.incremental {
+		idx, scanResult, err = d.scanIncremental(ctx, progressCh)
+	} else {
+		scanResult, err = scanner.RunFullScan(
+			ctx,
+			d.config.MoviePaths(),
+			d.config.ShowPaths(),
+			progressCh,
+		)
+	}
+	// Keep what a cancelled scan finished rather than discard it
+	var cancelled *scanner.CancelledScanError
+	if errors.As(err, &cancelled) {
+		reportPath, saveErr := d.savePartialReport(cancelled, progressCh)
+		if saveErr != nil {
+			return "", fmt.Errorf("%w (partial report not saved: %v)", err, saveErr)
+		}
+		return reportPath, err
+	}
 	if err != nil {
 		return "", fmt.Errorf("scan failed: %w", err)
 	}
 
-	// Build report from scan result
+	report = BuildReport(d.config, scanResult)
+
+	// Optionally check what Jellyfin actually picked up. The comparison
+	// holds every Jellyfin item in memory, so low-memory mode skips it
+	if d.config.Jellyfin.Compare {
+		if scanner.LowMemory() {
+			notify(progressCh, scanner.SeverityWarn, "Jellyfin compare skipped in low-memory mode")
+		} else {
+			report.Jellyfin = CompareWithJellyfin(ctx, d.config, progressCh)
+		}
+	}
+	// Point out which ambiguous shows Jellyfin already knows by name
+	CrossCheckTitles(ctx, d.config, report.AmbiguousTVShows, progressCh)
+
+	// What changed since the previous scan of these libraries
+	diff := diffWithPrevious(&report, progressCh)
+
+	// Save report with progress
+	reportPath, err = d.saveReportWithProgress(report, progressCh)
+	if err != nil {
+		return "", fmt.Errorf("failed to save report: %w", err)
+	}
+	if diff != nil {
+		if err := writeDiff(reportPath, *diff); err != nil {
+			notify(progressCh, scanner.SeverityWarn, err.Error())
+		}
+	}
+
+	// The next incremental scan builds on this report
+	if idx != nil {
+		idx.Report = reportPath
+		if err := idx.Save(d.indexPath); err != nil {
+			notify(progressCh, scanner.SeverityWarn, fmt.Sprintf("Scan index not saved: %v", err))
+		} else {
+			d.indexedReport = reportPath
+		}
+	}
+
+	return reportPath, nil
+}
+
+// logScan records a scan in the scan log: the report it saved and what it
+// found, or why it stopped
+func logScan(progressCh chan<- scanner.ScanProgress, reportPath string, report reporter.Report, scanErr error) {
+	entry := oplog.Entry{Timestamp: time.Now(), Op: "scan", OldPath: reportPath, Result: oplog.ResultOK}
+	switch {
+	case errors.Is(scanErr, context.Canceled):
+		entry.Result = oplog.ResultCancelled
+	case scanErr != nil:
+		entry.Result, entry.Error = oplog.ResultFailed, scanErr.Error()
+	default:
+		entry.Size = report.SpaceToFree
+		entry.Detail = fmt.Sprintf("%d duplicate group(s), %d file(s) to delete, %d compliance issue(s)",
+			report.TotalDuplicates, report.TotalFilesToDelete, len(report.ComplianceIssues))
+	}
+	if err := oplog.Append(oplog.DefaultPath(oplog.ScanLog), entry); err != nil {
+		notify(progressCh, scanner.SeverityWarn, fmt.Sprintf("Scan not logged: %v", err))
+	}
+}
+
+// BuildReport converts a scan result into a report for the configured libraries
+func BuildReport(cfg *config.Config, scanResult *scanner.ScanResult) reporter.Report {
 	report := reporter.Report{
 		Timestamp:          time.Now(),
 		LibraryPaths:       []string{},
@@ -78,33 +318,57 @@ func (d *Daemon) RunScanWithProgress(ctx context.Context, progressCh chan<- scan
 		TVDuplicates:       scanResult.TVDuplicates,
 		ComplianceIssues:   scanResult.ComplianceIssues,
 		AmbiguousTVShows:   scanResult.AmbiguousTVShows,
+		AmbiguousMovies:    scanResult.AmbiguousMovies,
+		OrphanFolders:      scanResult.OrphanFolders,
+		Artifacts:          scanResult.Artifacts,
+		Quality:            scanResult.Quality,
+		Completeness:       scanResult.Completeness,
+		APIDiagnostics:     scanResult.APIDiagnostics,
+		PassTimings:        scanResult.PassTimings,
 		TotalDuplicates:    scanResult.TotalDuplicates,
 		TotalFilesToDelete: scanResult.TotalFilesToDelete,
 		SpaceToFree:        scanResult.SpaceToFree,
 	}
 
 	// Set library type and paths
-	if len(d.config.Libraries.Movies.Paths) > 0 {
+	if moviePaths := cfg.MoviePaths(); len(moviePaths) > 0 {
 		report.LibraryType = "movies"
-		report.LibraryPaths = d.config.Libraries.Movies.Paths
+		report.LibraryPaths = moviePaths
 	}
-	if len(d.config.Libraries.TV.Paths) > 0 {
+	if showPaths := cfg.ShowPaths(); len(showPaths) > 0 {
 		if report.LibraryType == "" {
 			report.LibraryType = "tv"
-			report.LibraryPaths = d.config.Libraries.TV.Paths
+			report.LibraryPaths = showPaths
 		} else {
 			report.LibraryType = "mixed"
-			report.LibraryPaths = append(report.LibraryPaths, d.config.Libraries.TV.Paths...)
+			report.LibraryPaths = append(report.LibraryPaths, showPaths...)
 		}
 	}
 
-	// Save report with progress
-	reportPath, err := d.saveReportWithProgress(report, progressCh)
-	if err != nil {
-		return "", fmt.Errorf("failed to save report: %w", err)
+	// A scan of some libraries carries their overrides in each group, so a
+	// later clean of the report honors them whatever the global settings
+	if len(cfg.Only) > 0 {
+		report.Libraries = cfg.Only
+		merge := cfg.Duplicates.MergeCompanions
+		strategy, _ := scanner.ParseDuplicateStrategy(cfg.Duplicates.Strategy)
+		for i := range report.MovieDuplicates {
+			dup := &report.MovieDuplicates[i]
+			if dup.Strategy == "" {
+				dup.Strategy = strategy
+			}
+			if dup.MergeCompanions == nil {
+				dup.MergeCompanions = &merge
+			}
+		}
+		for i := range report.TVDuplicates {
+			if report.TVDuplicates[i].MergeCompanions == nil {
+				report.TVDuplicates[i].MergeCompanions = &merge
+			}
+		}
 	}
 
-	return reportPath, nil
+	reporter.ApplyKeepTags(&report, tags.CurrentRules())
+	return report
 }
 
 // saveReport saves the report as JSON
@@ -116,54 +380,37 @@ func (d *Daemon) saveReport(report reporter.Report) (string, error) {
 func (d *Daemon) saveReportWithProgress(report reporter.Report, progressCh chan<- scanner.ScanProgress) (string, error) {
 	var pr *scanner.ProgressReporter
 	if progressCh != nil {
-		pr = scanner.NewProgressReporter(progressCh, "report_generation")
+		pr = scanner.NewProgressReporter(progressCh, scanner.OpReportGeneration)
 		pr.Update(0, "Saving report")
 	}
 
-	// Get report directory - use real user's home when running as root
-	home, err := getRealUserHome()
+	// Get report directory ([reports] dir, real user's home when running as root)
+	reportDir, err := reporter.EnsureReportDir()
 	if err != nil {
-		if pr != nil {
-			pr.LogError(err, "Failed to get home directory")
-		}
-		return "", fmt.Errorf("failed to get home directory: %w", err)
-	}
-
-	reportDir := filepath.Join(home, ".local/share/jellysink/scan_results")
-	if err := os.MkdirAll(reportDir, 0755); err != nil {
 		if pr != nil {
 			pr.LogError(err, "Failed to create report directory")
 		}
-		return "", fmt.Errorf("failed to create report directory: %w", err)
+		return "", err
 	}
 
 	if pr != nil {
 		pr.Update(25, "Formatting JSON report")
 	}
 
-	// Generate filename with timestamp
-	timestamp := report.Timestamp.Format("20060102_150405")
-	reportPath := filepath.Join(reportDir, timestamp+".json")
-
-	// Marshal to JSON
-	data, err := json.MarshalIndent(report, "", "  ")
-	if err != nil {
-		if pr != nil {
-			pr.LogError(err, "Failed to marshal report to JSON")
-		}
-		return "", fmt.Errorf("failed to marshal report: %w", err)
-	}
+	// Generate filename from reports.filename_template
+	reportPath := filepath.Join(reportDir, reporter.BaseName(report.Timestamp, report.LibraryType)+".json")
 
 	if pr != nil {
 		pr.Update(50, "Writing JSON report to disk")
 	}
 
-	// Write to file
-	if err := os.WriteFile(reportPath, data, 0644); err != nil {
+	// Written to a temp file and renamed so readers on a shared report dir
+	// never pick up a half-written report
+	if err := reporter.WriteReport(reportPath, report); err != nil {
 		if pr != nil {
 			pr.LogError(err, "Failed to write JSON report")
 		}
-		return "", fmt.Errorf("failed to write report: %w", err)
+		return "", err
 	}
 
 	if pr != nil {
@@ -187,27 +434,9 @@ func (d *Daemon) saveReportWithProgress(report reporter.Report, progressCh chan<
 	return reportPath, nil
 }
 
-// getRealUserHome returns the real user's home directory
-// When running as root via sudo, returns SUDO_USER's home
-// Otherwise returns current user's home
-func getRealUserHome() (string, error) {
-	// Check if running as root via sudo
-	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" {
-		// Return the real user's home directory
-		return filepath.Join("/home", sudoUser), nil
-	}
-
-	// Not running via sudo, use current user's home
-	return os.UserHomeDir()
-}
-
 // GetReportDir returns the directory where reports are stored
 func GetReportDir() string {
-	home, err := getRealUserHome()
-	if err != nil {
-		return "/tmp/jellysink/scan_results"
-	}
-	return filepath.Join(home, ".local/share/jellysink/scan_results")
+	return reporter.ReportDir()
 }
 
 // CleanupOldReports removes reports older than 30 days
@@ -246,29 +475,89 @@ func CleanupOldReports() error {
 	return nil
 }
 
+// PurgeExpiredTrash permanently deletes trash older than
+// cleaner.retention_days; a retention of 0 keeps the trash
+func PurgeExpiredTrash(cfg *config.Config) (cleaner.PurgeResult, error) {
+	if cfg.Cleaner.RetentionDays <= 0 {
+		return cleaner.PurgeResult{}, nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -cfg.Cleaner.RetentionDays)
+	return cleaner.PurgeTrash(cleaner.DefaultConfig(), cutoff)
+}
+
 // AutoClean performs automatic cleanup of duplicates and compliance issues
 // Used in headless mode or when user enables auto-clean in config
-func (d *Daemon) AutoClean(report reporter.Report) error {
+func (d *Daemon) AutoClean(report reporter.Report) (cleaner.CleanResult, error) {
 	fmt.Println("Running auto-clean (headless mode)...")
 
 	cleanerCfg := cleaner.DefaultConfig()
 	cleanerCfg.DryRun = false
 
+	// Only delete duplicate groups the previous scans found the same way
+	report = d.confirmDuplicates(report)
+
+	// Only touch compliance issues whose severity is allowed for auto-clean
+	issues := scanner.FilterIssuesBySeverities(report.ComplianceIssues, d.config.Daemon.AutoCleanSeverities)
+	if skipped := len(report.ComplianceIssues) - len(issues); skipped > 0 {
+		fmt.Printf("Skipping %d compliance issue(s) outside auto_clean_severities %v\n", skipped, d.config.Daemon.AutoCleanSeverities)
+	}
+
+	// Then hold what the [autoclean] policy rules out
+	report, issues = d.applyPolicy(report, issues, true)
+
 	result, err := cleaner.Clean(
 		report.MovieDuplicates,
 		report.TVDuplicates,
-		report.ComplianceIssues,
+		issues,
 		cleanerCfg,
 	)
 
 	if err != nil {
-		return fmt.Errorf("auto-clean failed: %w", err)
+		return result, fmt.Errorf("auto-clean failed: %w", err)
 	}
 
+	for _, warning := range result.CloudWarnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
 	fmt.Printf("Auto-clean complete:\n")
 	fmt.Printf("  Duplicates deleted: %d\n", result.DuplicatesDeleted)
+	if result.DuplicatesLinked > 0 {
+		fmt.Printf("  Duplicates replaced with hardlinks: %d\n", result.DuplicatesLinked)
+	}
+	if result.VersionsKept > 0 {
+		fmt.Printf("  Copies kept as versions: %d\n", result.VersionsKept)
+	}
+	if result.CompanionsMerged > 0 {
+		fmt.Printf("  Subtitles and artwork moved to keepers: %d\n", result.CompanionsMerged)
+	}
 	fmt.Printf("  Compliance fixed: %d\n", result.ComplianceFixed)
 	fmt.Printf("  Space freed: %.2f GB\n", float64(result.SpaceFreed)/(1024*1024*1024))
+	if result.JournalID != "" {
+		fmt.Printf("  Undo with: jellysink undo %s\n", result.JournalID)
+	}
+	if result.LibraryRefreshed {
+		fmt.Printf("  Jellyfin library scan requested\n")
+	} else if result.RefreshErr != nil {
+		fmt.Printf("  Jellyfin library scan request failed: %v\n", result.RefreshErr)
+	}
+	if len(result.Deferred) > 0 {
+		fmt.Printf("  Deferred (in use): %d\n", len(result.Deferred))
+		for _, path := range result.Deferred {
+			fmt.Printf("    - %s\n", path)
+		}
+	}
+	if len(result.Importing) > 0 {
+		fmt.Printf("  Deferred (import in progress): %d\n", len(result.Importing))
+		for _, path := range result.Importing {
+			fmt.Printf("    - %s\n", path)
+		}
+	}
+	if len(result.Protected) > 0 {
+		fmt.Printf("  Skipped (protected path): %d\n", len(result.Protected))
+		for _, path := range result.Protected {
+			fmt.Printf("    - %s\n", path)
+		}
+	}
 
 	if len(result.Errors) > 0 {
 		fmt.Printf("  Errors: %d\n", len(result.Errors))
@@ -277,54 +566,100 @@ func (d *Daemon) AutoClean(report reporter.Report) error {
 		}
 	}
 
-	return nil
+	return result, nil
 }
 
-// GenerateSystemdTimer creates systemd timer configuration based on scan frequency
-func GenerateSystemdTimer(frequency string) (string, error) {
-	var onCalendar string
-
-	switch frequency {
-	case "daily":
-		onCalendar = "*-*-* 02:00:00"
-	case "weekly":
-		onCalendar = "Sun *-*-* 02:00:00"
-	case "biweekly":
-		onCalendar = "Sun/2 *-*-* 02:00:00"
-	default:
-		return "", fmt.Errorf("invalid scan frequency: %s (must be daily, weekly, or biweekly)", frequency)
-	}
+// SystemdTimerPath is where the jellysink timer unit is installed
+const SystemdTimerPath = "/etc/systemd/system/jellysink.timer"
+
+// SystemdServicePath is where the jellysink service unit is installed
+const SystemdServicePath = "/etc/systemd/system/jellysink.service"
+
+// SystemdServiceUnit is the service unit shipped as systemd/jellysink.service
+const SystemdServiceUnit = `[Unit]
+Description=Jellysink media library scan service
+After=graphical-session.target
+Documentation=https://github.com/Nomadcxx/jellysink
+
+[Service]
+Type=oneshot
+User=%u
+ExecStart=/usr/local/bin/jellysinkd
+
+# Environment variables for GUI launching
+Environment=DISPLAY=%E{DISPLAY}
+Environment=WAYLAND_DISPLAY=%E{WAYLAND_DISPLAY}
+Environment=XDG_RUNTIME_DIR=%t
 
-	timer := fmt.Sprintf(`[Unit]
+# Security hardening
+PrivateTmp=true
+NoNewPrivileges=true
+ProtectSystem=strict
+ProtectHome=read-only
+ReadWritePaths=%h/.local/share/jellysink %h/.config/jellysink
+
+[Install]
+WantedBy=graphical-session.target
+`
+
+// SystemdTimerUnit is the default timer shipped as systemd/jellysink.timer;
+// installs regenerate it for the configured schedule
+const SystemdTimerUnit = `[Unit]
 Description=Jellysink media library scan timer
 Requires=jellysink.service
 
 [Timer]
-OnCalendar=%s
+# Default: Run weekly on Sunday at 2 AM
+OnCalendar=Sun *-*-* 02:00:00
 Persistent=true
 
 [Install]
 WantedBy=timers.target
-`, onCalendar)
+`
 
-	return timer, nil
-}
+// GenerateSystemdTimer creates the systemd timer unit for a schedule
+func GenerateSystemdTimer(schedule Schedule) string {
+	return fmt.Sprintf(`[Unit]
+Description=Jellysink media library scan timer
+Requires=jellysink.service
 
-// InstallSystemdTimer writes the systemd timer file
-func InstallSystemdTimer(frequency string) error {
-	timerContent, err := GenerateSystemdTimer(frequency)
-	if err != nil {
-		return err
-	}
+[Timer]
+OnCalendar=%s
+Persistent=true
 
-	timerPath := "/etc/systemd/system/jellysink.timer"
+[Install]
+WantedBy=timers.target
+`, schedule.OnCalendar())
+}
 
-	if err := os.WriteFile(timerPath, []byte(timerContent), 0644); err != nil {
+// InstallSystemdTimer writes the systemd timer unit for a schedule to path
+// and has systemd reload it
+func InstallSystemdTimer(path string, schedule Schedule) error {
+	if err := os.WriteFile(path, []byte(GenerateSystemdTimer(schedule)), 0644); err != nil {
 		return fmt.Errorf("failed to write timer file: %w", err)
 	}
-
-	fmt.Printf("Systemd timer installed at %s\n", timerPath)
-	fmt.Println("Run 'sudo systemctl daemon-reload && sudo systemctl enable --now jellysink.timer' to activate")
-
+	if err := exec.Command("systemctl", "daemon-reload").Run(); err != nil {
+		return fmt.Errorf("failed to reload systemd: %w", err)
+	}
 	return nil
 }
+
+// SyncSystemdTimer rewrites an installed timer unit at path when the
+// schedule has changed. It reports whether the unit was rewritten; a timer
+// that was never installed is left alone
+func SyncSystemdTimer(path string, schedule Schedule) (bool, error) {
+	current, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read timer file: %w", err)
+	}
+	if string(current) == GenerateSystemdTimer(schedule) {
+		return false, nil
+	}
+	if err := InstallSystemdTimer(path, schedule); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -1,8 +1,11 @@
 package daemon
 
 import (
+	"path/filepath"
+	"slices"
 	"testing"
 
+	"github.com/Nomadcxx/jellysink/internal/cleaner"
 	"github.com/Nomadcxx/jellysink/internal/config"
 	"github.com/Nomadcxx/jellysink/internal/scanner"
 )
@@ -92,3 +95,18 @@ func TestDaemonLogLevelConfiguration(t *testing.T) {
 		t.Errorf("Expected LogLevelVerbose (CLI precedence), got %v", actualLogLevel)
 	}
 }
+
+func TestNewAppliesCleanerSettings(t *testing.T) {
+	t.Cleanup(func() {
+		cleaner.SetProtectedPaths(nil)
+		cleaner.SetHardlinkDuplicates(false)
+	})
+	keep := filepath.Join(t.TempDir(), "keep")
+	cfg := &config.Config{Cleaner: config.CleanerConfig{ProtectedPaths: []string{keep}, HardlinkDuplicates: true}}
+
+	New(cfg)
+	got := cleaner.DefaultConfig()
+	if !slices.Contains(got.ProtectedPaths, keep) || !got.HardlinkDuplicates {
+		t.Errorf("Expected New() to apply the cleaner settings, got protected %v, hardlinks %v", got.ProtectedPaths, got.HardlinkDuplicates)
+	}
+}
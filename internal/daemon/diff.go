@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// diffWithPrevious compares report with the previous report of the same
+// libraries and stores the summary in it. It returns nil when there is no
+// previous report to compare with
+func diffWithPrevious(report *reporter.Report, progressCh chan<- scanner.ScanProgress) *reporter.ReportDiff {
+	previousPath, previous, err := reporter.PreviousReport(reporter.ReportDir(), "", *report)
+	if err != nil {
+		notify(progressCh, scanner.SeverityWarn, fmt.Sprintf("Not compared with the previous report: %v", err))
+		return nil
+	}
+	if previousPath == "" {
+		return nil
+	}
+
+	diff := reporter.DiffReports(previous, *report)
+	diff.Old = previousPath
+	changes := diff.Changes()
+	report.Changes = &changes
+	notify(progressCh, scanner.SeverityInfo, changes.Headline())
+	return &diff
+}
+
+// writeDiff writes diff as text next to the report at reportPath
+func writeDiff(reportPath string, diff reporter.ReportDiff) error {
+	diff.New = reportPath
+	f, err := os.Create(reporter.DiffPath(reportPath))
+	if err != nil {
+		return fmt.Errorf("failed to write report diff: %w", err)
+	}
+	err = reporter.WriteDiff(f, diff, false)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write report diff: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,426 @@
+package daemon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/oplog"
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+	"github.com/Nomadcxx/jellysink/internal/tags"
+)
+
+// DoctorResult is the outcome of one doctor check and, for warnings and
+// failures, what to do about it
+type DoctorResult struct {
+	SelfTestResult
+	Fix string
+}
+
+// DoctorOptions identifies the jellysink binary running the doctor
+type DoctorOptions struct {
+	Version string
+	Commit  string
+}
+
+// doctorAPITimeout bounds the probe of jellysinkd's status API
+const doctorAPITimeout = 2 * time.Second
+
+// daemonVersion runs the jellysinkd binary at path with -version;
+// overridable for tests
+var daemonVersion = func(path string) (string, error) {
+	out, err := exec.Command(path, "-version").Output()
+	return string(out), err
+}
+
+// RunDoctor checks an installation after an upgrade: the config, that
+// jellysink and jellysinkd come from one build, ownership of the data,
+// report and config dirs, the installed systemd units against the shipped
+// ones, that jellysinkd's status API answers, that the newest report and the
+// stored data still read, and the API credentials. cfgErr is why the config failed to load; cfg then holds the
+// defaults. Checks are returned most important first
+func RunDoctor(cfg *config.Config, cfgErr error, opts DoctorOptions) []DoctorResult {
+	var results []DoctorResult
+
+	results = append(results, checkDoctorConfig(cfg, cfgErr))
+	results = append(results, checkDoctorVersions(opts, daemonBinary(SystemdServicePath)))
+	uid := serviceUID()
+	for _, dir := range doctorDirs() {
+		results = append(results, checkDoctorOwnership(dir, uid))
+	}
+	results = append(results, checkDoctorService(SystemdServicePath))
+	results = append(results, checkDoctorTimer(SystemdTimerPath, cfg.Daemon))
+	results = append(results, checkDoctorStatusAPI(cfg.Daemon))
+	results = append(results, checkDoctorReport(GetReportDir()))
+	results = append(results, checkDoctorIndex(scanner.DefaultIndexPath()))
+	results = append(results, checkDoctorAPICache(scanner.DefaultAPICachePath()))
+	results = append(results, checkDoctorTags(tags.DefaultPath()))
+	results = append(results, checkDoctorMigration(oplog.LegacyDataDir(), oplog.DataDir()))
+	for _, r := range checkSelfTestAPI(cfg) {
+		result := DoctorResult{SelfTestResult: r}
+		if r.Status == SelfTestFail {
+			result.Fix = "Check the key under Configure API Keys, and that this host reaches the API (api proxy_url and ca_bundle)"
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// DoctorFailed reports whether any check failed
+func DoctorFailed(results []DoctorResult) bool {
+	for _, r := range results {
+		if r.Status == SelfTestFail {
+			return true
+		}
+	}
+	return false
+}
+
+// DoctorFixes returns the checks that need fixing: failures, then
+// warnings, each in check order
+func DoctorFixes(results []DoctorResult) []DoctorResult {
+	var fixes []DoctorResult
+	for _, r := range results {
+		if r.Fix != "" && (r.Status == SelfTestFail || r.Status == SelfTestWarn) {
+			fixes = append(fixes, r)
+		}
+	}
+	sort.SliceStable(fixes, func(i, j int) bool {
+		return fixes[i].Status == SelfTestFail && fixes[j].Status != SelfTestFail
+	})
+	return fixes
+}
+
+// PrintDoctorResults writes one line per check, then the fix list
+func PrintDoctorResults(w io.Writer, results []DoctorResult) {
+	checks := make([]SelfTestResult, len(results))
+	for i, r := range results {
+		checks[i] = r.SelfTestResult
+	}
+	PrintSelfTestResults(w, checks)
+
+	fixes := DoctorFixes(results)
+	if len(fixes) == 0 {
+		fmt.Fprintln(w, "\nNo problems found.")
+		return
+	}
+	fmt.Fprintln(w, "\nFixes, most important first:")
+	for i, r := range fixes {
+		fmt.Fprintf(w, "  %d. [%s] %s: %s\n", i+1, strings.ToUpper(r.Status), r.Name, r.Fix)
+	}
+}
+
+func checkDoctorConfig(cfg *config.Config, cfgErr error) DoctorResult {
+	if cfgErr != nil {
+		return DoctorResult{SelfTestResult{"config", SelfTestFail, cfgErr.Error()},
+			"Fix config.toml; running jellysink without arguments opens it in safe mode"}
+	}
+	result := DoctorResult{SelfTestResult: checkSelfTestConfig(cfg)}
+	if result.Status == SelfTestFail {
+		result.Fix = "Fix config.toml; running jellysink without arguments opens it in safe mode"
+	}
+	return result
+}
+
+// daemonBinary returns the jellysinkd the service unit at unitPath starts,
+// or the one on PATH; "" when neither exists
+func daemonBinary(unitPath string) string {
+	if data, err := os.ReadFile(unitPath); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "ExecStart="); ok {
+				if fields := strings.Fields(rest); len(fields) > 0 {
+					if _, err := os.Stat(fields[0]); err == nil {
+						return fields[0]
+					}
+				}
+			}
+		}
+	}
+	if path, err := exec.LookPath("jellysinkd"); err == nil {
+		return path
+	}
+	return ""
+}
+
+// checkDoctorVersions compares jellysink with the jellysinkd at path: both
+// come from one build, or the daemon may write reports and caches the
+// client can't read
+func checkDoctorVersions(opts DoctorOptions, path string) DoctorResult {
+	fix := "Install jellysink and jellysinkd from the same release (make install, or both binaries of one release archive)"
+	if path == "" {
+		return DoctorResult{SelfTestResult{"versions", SelfTestWarn, "jellysinkd not found: scheduled scans can't run"}, fix}
+	}
+	out, err := daemonVersion(path)
+	if err != nil {
+		return DoctorResult{SelfTestResult{"versions", SelfTestFail, fmt.Sprintf("cannot run %s -version: %v", path, err)}, fix}
+	}
+
+	var version, commit string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 2 && fields[0] == "jellysinkd":
+			version = fields[1]
+		case len(fields) == 2 && fields[0] == "Commit:":
+			commit = fields[1]
+		}
+	}
+	if version != opts.Version || commit != opts.Commit {
+		return DoctorResult{SelfTestResult{"versions", SelfTestFail,
+			fmt.Sprintf("jellysink is %s (%s) but %s is %s (%s)", opts.Version, opts.Commit, path, version, commit)}, fix}
+	}
+	return DoctorResult{SelfTestResult: SelfTestResult{"versions", SelfTestOK,
+		fmt.Sprintf("jellysink and %s are both %s (%s)", path, version, commit)}}
+}
+
+// doctorDirs returns the data, report and config dirs, leaving out those
+// inside another
+func doctorDirs() []string {
+	candidates := []string{oplog.DataDir(), GetReportDir()}
+	if path, err := config.ConfigPath(); err == nil {
+		candidates = append(candidates, filepath.Dir(path))
+	}
+
+	var dirs []string
+	for _, dir := range candidates {
+		inside := false
+		for _, kept := range dirs {
+			if dir == kept || strings.HasPrefix(dir, kept+string(filepath.Separator)) {
+				inside = true
+			}
+		}
+		if !inside {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// serviceUID returns the user jellysinkd runs as: the one who ran sudo, or
+// the current user
+func serviceUID() int {
+	if uid, err := strconv.Atoi(os.Getenv("SUDO_UID")); err == nil {
+		return uid
+	}
+	return os.Getuid()
+}
+
+// checkDoctorOwnership checks that dir and everything in it belongs to uid.
+// Files a run as root left behind can't be written by jellysinkd running as
+// the user. Trash folders are skipped: trashed files keep their library
+// owners
+func checkDoctorOwnership(dir string, uid int) DoctorResult {
+	name := "permissions " + dir
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return DoctorResult{SelfTestResult: SelfTestResult{name, SelfTestSkip, "not created yet"}}
+	}
+	owner := strconv.Itoa(uid)
+	if u, err := user.LookupId(owner); err == nil {
+		owner = u.Username
+	}
+	fix := fmt.Sprintf("sudo chown -R %s %s && sudo chmod -R u+rwX %s", owner, dir, dir)
+	if err != nil {
+		return DoctorResult{SelfTestResult{name, SelfTestFail, err.Error()}, fix}
+	}
+	if info.Mode().Perm()&0700 != 0700 {
+		return DoctorResult{SelfTestResult{name, SelfTestFail, fmt.Sprintf("mode %o: its owner can't read and write it", info.Mode().Perm())}, fix}
+	}
+
+	var foreign int
+	var example string
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && path != dir && d.Name() == oplog.TrashDirName {
+			return filepath.SkipDir
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok && int(stat.Uid) != uid {
+			if foreign == 0 {
+				example = path
+			}
+			foreign++
+		}
+		return nil
+	})
+	if foreign > 0 {
+		return DoctorResult{SelfTestResult{name, SelfTestFail,
+			fmt.Sprintf("%d entries not owned by %s, e.g. %s", foreign, owner, example)}, fix}
+	}
+	return DoctorResult{SelfTestResult: SelfTestResult{name, SelfTestOK, "owned by " + owner}}
+}
+
+// unitHash returns the start of a unit file's SHA-256
+func unitHash(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// checkDoctorService compares the installed service unit at path with the
+// one shipped with this version
+func checkDoctorService(path string) DoctorResult {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return DoctorResult{SelfTestResult: SelfTestResult{"systemd service", SelfTestSkip, "not installed"}}
+	}
+	fix := "Reinstall the service unit of this version (make install or install-jellysink), then sudo systemctl daemon-reload"
+	if err != nil {
+		return DoctorResult{SelfTestResult{"systemd service", SelfTestFail, err.Error()}, fix}
+	}
+	if installed, shipped := unitHash(string(data)), unitHash(SystemdServiceUnit); installed != shipped {
+		return DoctorResult{SelfTestResult{"systemd service", SelfTestWarn,
+			fmt.Sprintf("%s (sha256 %s) differs from the shipped unit (sha256 %s)", path, installed, shipped)}, fix}
+	}
+	return DoctorResult{SelfTestResult: SelfTestResult{"systemd service", SelfTestOK, "matches the shipped unit (sha256 " + unitHash(SystemdServiceUnit) + ")"}}
+}
+
+// checkDoctorTimer compares the installed timer unit at path with the one
+// jellysink writes for the configured schedule; the shipped timer passes
+// while the schedule is its default
+func checkDoctorTimer(path string, cfg config.DaemonConfig) DoctorResult {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return DoctorResult{SelfTestResult: SelfTestResult{"systemd timer", SelfTestSkip, "not installed"}}
+	}
+	fix := "Save the schedule again under Configure Frequency, which rewrites the timer"
+	if err != nil {
+		return DoctorResult{SelfTestResult{"systemd timer", SelfTestFail, err.Error()}, fix}
+	}
+
+	installed := string(data)
+	if schedule, err := ConfiguredSchedule(cfg); err == nil {
+		expected := GenerateSystemdTimer(schedule)
+		if unitHash(installed) == unitHash(expected) ||
+			(installed == SystemdTimerUnit && strings.Contains(SystemdTimerUnit, "OnCalendar="+schedule.OnCalendar()+"\n")) {
+			return DoctorResult{SelfTestResult: SelfTestResult{"systemd timer", SelfTestOK, "scans " + schedule.String()}}
+		}
+		return DoctorResult{SelfTestResult{"systemd timer", SelfTestWarn,
+			fmt.Sprintf("%s (sha256 %s) doesn't match the configured schedule (sha256 %s)", path, unitHash(installed), unitHash(expected))}, fix}
+	}
+	if installed != SystemdTimerUnit {
+		return DoctorResult{SelfTestResult{"systemd timer", SelfTestWarn,
+			fmt.Sprintf("%s (sha256 %s) differs from the shipped timer (sha256 %s)", path, unitHash(installed), unitHash(SystemdTimerUnit))}, fix}
+	}
+	return DoctorResult{SelfTestResult: SelfTestResult{"systemd timer", SelfTestOK, "matches the shipped timer"}}
+}
+
+// checkDoctorStatusAPI asks the running jellysinkd for its status on
+// http_addr with the configured token; skipped while the API is off
+func checkDoctorStatusAPI(cfg config.DaemonConfig) DoctorResult {
+	if cfg.HTTPAddr == "" {
+		return DoctorResult{SelfTestResult: SelfTestResult{"status api", SelfTestSkip, "daemon http_addr not set"}}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), doctorAPITimeout)
+	defer cancel()
+
+	status, err := FetchAPIStatus(ctx, cfg.HTTPAddr, cfg.HTTPToken)
+	if errors.Is(err, ErrAPIUnauthorized) {
+		return DoctorResult{SelfTestResult{"status api", SelfTestFail, fmt.Sprintf("%s: %v", cfg.HTTPAddr, err)},
+			"Set daemon http_token to the token the running jellysinkd --daemon was started with, or restart it to pick up the new token"}
+	}
+	if err != nil {
+		return DoctorResult{SelfTestResult{"status api", SelfTestFail, err.Error()},
+			"Start jellysinkd --daemon, or check its log for why it isn't serving " + cfg.HTTPAddr}
+	}
+	return DoctorResult{SelfTestResult: SelfTestResult{"status api", SelfTestOK,
+		fmt.Sprintf("jellysinkd (pid %d) answers on %s: %s", status.PID, cfg.HTTPAddr, status.State)}}
+}
+
+// checkDoctorReport reads the newest JSON report in dir with this version
+func checkDoctorReport(dir string) DoctorResult {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return DoctorResult{SelfTestResult{"report schema", SelfTestFail, err.Error()}, "Check the [reports] dir is mounted and readable"}
+	}
+	var newest string
+	var newestInfo os.FileInfo
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && (newestInfo == nil || info.ModTime().After(newestInfo.ModTime())) {
+			newest, newestInfo = filepath.Join(dir, entry.Name()), info
+		}
+	}
+	if newest == "" {
+		return DoctorResult{SelfTestResult: SelfTestResult{"report schema", SelfTestSkip, "no reports yet"}}
+	}
+	if _, err := reporter.ReadReport(newest); err != nil {
+		return DoctorResult{SelfTestResult{"report schema", SelfTestFail, fmt.Sprintf("%s: %v", newest, err)},
+			"Run jellysink scan to write a report this version reads"}
+	}
+	return DoctorResult{SelfTestResult: SelfTestResult{"report schema", SelfTestOK, filepath.Base(newest) + " reads with this version"}}
+}
+
+// checkDoctorIndex checks the incremental scan index at path is in this
+// version's format
+func checkDoctorIndex(path string) DoctorResult {
+	current, err := scanner.ScanIndexCurrent(path)
+	if err != nil {
+		return DoctorResult{SelfTestResult{"scan index", SelfTestFail, err.Error()},
+			fmt.Sprintf("Delete %s; the next incremental scan rebuilds it", path)}
+	}
+	if !current {
+		return DoctorResult{SelfTestResult{"scan index", SelfTestWarn, "written by another version: the next incremental scan rescans every file"},
+			"Run jellysink scan --incremental once to rebuild it"}
+	}
+	return DoctorResult{SelfTestResult: SelfTestResult{"scan index", SelfTestOK, "in this version's format"}}
+}
+
+// checkDoctorAPICache checks the API cache at path still reads
+func checkDoctorAPICache(path string) DoctorResult {
+	stats, err := scanner.ReadAPICacheStats(path, 0)
+	if err != nil {
+		return DoctorResult{SelfTestResult{"api cache", SelfTestFail, err.Error()},
+			"Run jellysink cache clear; lookups are fetched again on the next scan"}
+	}
+	return DoctorResult{SelfTestResult: SelfTestResult{"api cache", SelfTestOK, fmt.Sprintf("%d cached lookups read with this version", stats.Entries)}}
+}
+
+// checkDoctorTags checks the tag file at path still reads
+func checkDoctorTags(path string) DoctorResult {
+	if _, err := tags.Load(path); err != nil {
+		return DoctorResult{SelfTestResult{"tags", SelfTestFail, err.Error()},
+			fmt.Sprintf("Repair or remove %s; tags decide which copies cleans keep", path)}
+	}
+	return DoctorResult{SelfTestResult: SelfTestResult{"tags", SelfTestOK, "read with this version"}}
+}
+
+// checkDoctorMigration reports data still in the legacy data dir after
+// data_dir moved it elsewhere
+func checkDoctorMigration(legacy, dataDir string) DoctorResult {
+	if legacy == dataDir {
+		return DoctorResult{SelfTestResult: SelfTestResult{"data migration", SelfTestOK, "data dir is " + dataDir}}
+	}
+	entries, err := os.ReadDir(legacy)
+	if err != nil || len(entries) == 0 {
+		return DoctorResult{SelfTestResult: SelfTestResult{"data migration", SelfTestOK, "nothing left in " + legacy}}
+	}
+	if existing, err := os.ReadDir(dataDir); err != nil || len(existing) == 0 {
+		return DoctorResult{SelfTestResult{"data migration", SelfTestWarn, fmt.Sprintf("%s moves to %s on the next run", legacy, dataDir)},
+			"Run any jellysink command once to move it"}
+	}
+	return DoctorResult{SelfTestResult{"data migration", SelfTestWarn, fmt.Sprintf("%s still holds data but %s is in use", legacy, dataDir)},
+		fmt.Sprintf("Move what you still need from %s into %s, then remove it", legacy, dataDir)}
+}
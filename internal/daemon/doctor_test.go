@@ -0,0 +1,159 @@
+package daemon
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+)
+
+func TestShippedSystemdUnits(t *testing.T) {
+	for file, embedded := range map[string]string{
+		"jellysink.service": SystemdServiceUnit,
+		"jellysink.timer":   SystemdTimerUnit,
+	} {
+		shipped, err := os.ReadFile(filepath.Join("..", "..", "systemd", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(shipped) != embedded {
+			t.Errorf("systemd/%s and the unit the doctor compares against differ", file)
+		}
+	}
+}
+
+func TestDoctorVersions(t *testing.T) {
+	orig := daemonVersion
+	defer func() { daemonVersion = orig }()
+	daemonVersion = func(string) (string, error) {
+		return "jellysinkd v1.4.0\n  Commit:     abc1234\n  Built:      2026-10-01_12:00:00\n", nil
+	}
+
+	if r := checkDoctorVersions(DoctorOptions{Version: "v1.4.0", Commit: "abc1234"}, "/usr/local/bin/jellysinkd"); r.Status != SelfTestOK {
+		t.Errorf("Expected matching builds to pass, got %+v", r)
+	}
+	if r := checkDoctorVersions(DoctorOptions{Version: "v1.5.0", Commit: "def5678"}, "/usr/local/bin/jellysinkd"); r.Status != SelfTestFail || r.Fix == "" {
+		t.Errorf("Expected an older jellysinkd to fail with a fix, got %+v", r)
+	}
+
+	daemonVersion = func(string) (string, error) { return "", errors.New("exec format error") }
+	if r := checkDoctorVersions(DoctorOptions{Version: "v1.5.0"}, "/usr/local/bin/jellysinkd"); r.Status != SelfTestFail {
+		t.Errorf("Expected a jellysinkd that doesn't run to fail, got %+v", r)
+	}
+}
+
+func TestDoctorSystemdUnits(t *testing.T) {
+	dir := t.TempDir()
+	service := filepath.Join(dir, "jellysink.service")
+	timer := filepath.Join(dir, "jellysink.timer")
+
+	if r := checkDoctorService(service); r.Status != SelfTestSkip {
+		t.Errorf("Expected a missing unit to be skipped, got %+v", r)
+	}
+	os.WriteFile(service, []byte(SystemdServiceUnit), 0644)
+	if r := checkDoctorService(service); r.Status != SelfTestOK {
+		t.Errorf("Expected the shipped unit to pass, got %+v", r)
+	}
+	os.WriteFile(service, []byte(strings.Replace(SystemdServiceUnit, "ProtectHome=read-only", "", 1)), 0644)
+	if r := checkDoctorService(service); r.Status != SelfTestWarn || r.Fix == "" {
+		t.Errorf("Expected an edited unit to warn, got %+v", r)
+	}
+
+	daily := config.DefaultConfig().Daemon
+	daily.ScanFrequency = "daily"
+	os.WriteFile(timer, []byte(SystemdTimerUnit), 0644)
+	if r := checkDoctorTimer(timer, config.DefaultConfig().Daemon); r.Status != SelfTestOK {
+		t.Errorf("Expected the shipped timer to pass on the default schedule, got %+v", r)
+	}
+	if r := checkDoctorTimer(timer, daily); r.Status != SelfTestWarn {
+		t.Errorf("Expected the shipped timer to warn on a daily schedule, got %+v", r)
+	}
+	schedule, _ := ConfiguredSchedule(daily)
+	os.WriteFile(timer, []byte(GenerateSystemdTimer(schedule)), 0644)
+	if r := checkDoctorTimer(timer, daily); r.Status != SelfTestOK {
+		t.Errorf("Expected the generated timer to pass, got %+v", r)
+	}
+}
+
+func TestDoctorStatusAPI(t *testing.T) {
+	if r := checkDoctorStatusAPI(config.DaemonConfig{}); r.Status != SelfTestSkip {
+		t.Errorf("Expected the check skipped without http_addr, got %+v", r)
+	}
+
+	api := newAPI(t.TempDir(), "", "secret")
+	api.SetStatus(ServiceStatus{PID: 42, State: ServiceIdle})
+	server := httptest.NewServer(api.Handler())
+	addr := strings.TrimPrefix(server.URL, "http://")
+
+	if r := checkDoctorStatusAPI(config.DaemonConfig{HTTPAddr: addr, HTTPToken: "secret"}); r.Status != SelfTestOK {
+		t.Errorf("Expected a running API to pass, got %+v", r)
+	}
+	r := checkDoctorStatusAPI(config.DaemonConfig{HTTPAddr: addr, HTTPToken: "stale"})
+	if r.Status != SelfTestFail || !strings.Contains(r.Fix, "http_token") {
+		t.Errorf("Expected a token mismatch to fail with a token fix, got %+v", r)
+	}
+
+	server.Close()
+	if r := checkDoctorStatusAPI(config.DaemonConfig{HTTPAddr: addr, HTTPToken: "secret"}); r.Status != SelfTestFail || r.Fix == "" {
+		t.Errorf("Expected an unreachable API to fail with a fix, got %+v", r)
+	}
+}
+
+func TestDoctorDataChecks(t *testing.T) {
+	dir := t.TempDir()
+
+	index := filepath.Join(dir, "index.json")
+	os.WriteFile(index, []byte(`{"Version": 0, "Files": {}}`), 0644)
+	if r := checkDoctorIndex(index); r.Status != SelfTestWarn {
+		t.Errorf("Expected an index of another version to warn, got %+v", r)
+	}
+	os.WriteFile(index, []byte(`{"Version": `), 0644)
+	if r := checkDoctorIndex(index); r.Status != SelfTestFail {
+		t.Errorf("Expected a truncated index to fail, got %+v", r)
+	}
+
+	reports := filepath.Join(dir, "reports")
+	os.MkdirAll(reports, 0755)
+	if r := checkDoctorReport(reports); r.Status != SelfTestSkip {
+		t.Errorf("Expected no reports to be skipped, got %+v", r)
+	}
+	os.WriteFile(filepath.Join(reports, "20261015_020000.json"), []byte(`{"MovieDuplicates": "broken"}`), 0644)
+	if r := checkDoctorReport(reports); r.Status != SelfTestFail {
+		t.Errorf("Expected an unreadable report to fail, got %+v", r)
+	}
+
+	if r := checkDoctorOwnership(reports, os.Getuid()); r.Status != SelfTestOK {
+		t.Errorf("Expected the test's own files to pass, got %+v", r)
+	}
+	if r := checkDoctorOwnership(reports, os.Getuid()+1); r.Status != SelfTestFail || !strings.Contains(r.Fix, "chown -R") {
+		t.Errorf("Expected files of another user to fail with a chown fix, got %+v", r)
+	}
+}
+
+func TestDoctorFixesOrder(t *testing.T) {
+	results := []DoctorResult{
+		{SelfTestResult{"versions", SelfTestWarn, "jellysinkd not found"}, "install it"},
+		{SelfTestResult{"systemd service", SelfTestOK, "matches"}, ""},
+		{SelfTestResult{"scan index", SelfTestFail, "truncated"}, "delete it"},
+		{SelfTestResult{"tags", SelfTestFail, "corrupt"}, "repair it"},
+	}
+	fixes := DoctorFixes(results)
+	var names []string
+	for _, r := range fixes {
+		names = append(names, r.Name)
+	}
+	if got := strings.Join(names, ", "); got != "scan index, tags, versions" {
+		t.Errorf("DoctorFixes() order = %s, want failures first in check order", got)
+	}
+
+	var out bytes.Buffer
+	PrintDoctorResults(&out, results)
+	if !strings.Contains(out.String(), "1. [FAIL] scan index: delete it") {
+		t.Errorf("Expected the numbered fix list, got:\n%s", out.String())
+	}
+}
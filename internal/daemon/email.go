@@ -0,0 +1,182 @@
+package daemon
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+)
+
+// emailTimeout bounds connecting to the SMTP server
+const emailTimeout = 30 * time.Second
+
+// sendMail delivers msg through the [notify.email] server (replaced in tests)
+var sendMail = deliverMail
+
+// EmailReport sends the [notify.email] recipients a summary of the scan
+// saved at reportPath
+func EmailReport(cfg *config.Config, report reporter.Report, reportPath string) error {
+	email := cfg.Notify.Email
+	if !email.Enabled() {
+		return nil
+	}
+	from := emailSender(email)
+	msg := emailMessage(from, email.To, emailSubject(report), emailBody(cfg, report, reportPath), time.Now())
+	if err := sendMail(email, from, email.To, msg); err != nil {
+		return fmt.Errorf("failed to email scan summary via %s: %w", email.Host, err)
+	}
+	return nil
+}
+
+// emailSender returns the From address: from, username when it is an
+// address, or jellysink@<hostname>
+func emailSender(email config.EmailConfig) string {
+	if email.From != "" {
+		return email.From
+	}
+	if strings.Contains(email.Username, "@") {
+		return email.Username
+	}
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "localhost"
+	}
+	return "jellysink@" + host
+}
+
+// emailSubject sums up the scan in one line
+func emailSubject(report reporter.Report) string {
+	library := report.LibraryType
+	if library == "" {
+		library = "library"
+	}
+	return fmt.Sprintf("jellysink %s scan: %d duplicate groups, %.2f GB reclaimable, %d compliance issues",
+		library, report.TotalDuplicates, float64(report.SpaceToFree)/(1024*1024*1024), len(report.ComplianceIssues))
+}
+
+// emailBody lists the scan totals and where to find the report
+func emailBody(cfg *config.Config, report reporter.Report, reportPath string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "jellysink finished scanning %s at %s.\n\n",
+		strings.Join(report.LibraryPaths, ", "), report.Timestamp.Local().Format("Mon 2006-01-02 15:04"))
+	fmt.Fprintf(&b, "Duplicate groups: %d (%d files to delete)\n", report.TotalDuplicates, report.TotalFilesToDelete)
+	fmt.Fprintf(&b, "Space reclaimable: %.2f GB\n", float64(report.SpaceToFree)/(1024*1024*1024))
+	fmt.Fprintf(&b, "Compliance issues: %d\n", len(report.ComplianceIssues))
+	if report.Changes != nil {
+		fmt.Fprintf(&b, "%s\n", report.Changes.Headline())
+	}
+	if report.Partial != nil {
+		fmt.Fprintf(&b, "\n%s\n", report.Partial.Banner())
+	}
+
+	if projections, err := capacityProjections(cfg); err == nil && len(projections) > 0 {
+		fmt.Fprintf(&b, "\nCapacity:\n")
+		for _, p := range projections {
+			fmt.Fprintf(&b, "  %s\n", CapacityLine(p))
+		}
+	}
+
+	fmt.Fprintf(&b, "\nReport: %s\n", reportPath)
+	fmt.Fprintf(&b, "Review it with: jellysink view %s\n", reportPath)
+	if cfg.Daemon.HTTPAddr != "" {
+		fmt.Fprintf(&b, "Over the jellysinkd API: %s\n", apiURL(cfg.Daemon.HTTPAddr, "/reports/"+filepath.Base(reportPath)))
+	}
+	return b.String()
+}
+
+// emailMessage builds a plain-text RFC 5322 message with CRLF line endings
+func emailMessage(from string, to []string, subject, body string, date time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	for _, line := range strings.Split(strings.TrimRight(body, "\n"), "\n") {
+		buf.WriteString(line + "\r\n")
+	}
+	return buf.Bytes()
+}
+
+// deliverMail sends msg over SMTP, with implicit TLS, STARTTLS or in the
+// clear as [notify.email] tls says. Reports name library paths and hosts,
+// so unless tls is "none" a server that doesn't offer STARTTLS gets nothing
+func deliverMail(email config.EmailConfig, from string, to []string, msg []byte) error {
+	addr := net.JoinHostPort(email.Host, strconv.Itoa(email.Port))
+	tlsConfig := &tls.Config{ServerName: email.Host}
+
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: emailTimeout}
+	if email.TLS == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(2 * emailTimeout))
+
+	client, err := smtp.NewClient(conn, email.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if email.TLS != "tls" && email.TLS != "none" {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("%s does not offer STARTTLS; set notify.email tls = \"tls\" for implicit TLS, or \"none\" to send unencrypted", addr)
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if email.Username != "" {
+		// PlainAuth refuses to send the password unencrypted except to localhost
+		if err := client.Auth(smtp.PlainAuth("", email.Username, email.Password, email.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(envelopeAddress(from)); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(envelopeAddress(rcpt)); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// envelopeAddress strips the display name from "Name <user@host>"
+func envelopeAddress(addr string) string {
+	if parsed, err := mail.ParseAddress(addr); err == nil {
+		return parsed.Address
+	}
+	return addr
+}
@@ -0,0 +1,161 @@
+package daemon
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// fakeSMTP accepts one message and sends its envelope and data on the
+// returned channel
+func fakeSMTP(t *testing.T) (int, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	got := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		var received strings.Builder
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RCPT"):
+				received.WriteString(strings.TrimSpace(line) + "\n")
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				for {
+					data, err := r.ReadString('\n')
+					if err != nil || data == ".\r\n" {
+						break
+					}
+					received.WriteString(data)
+				}
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				got <- received.String()
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port, got
+}
+
+func TestEmailReport(t *testing.T) {
+	port, got := fakeSMTP(t)
+
+	cfg := config.DefaultConfig()
+	cfg.Daemon.HTTPAddr = "0.0.0.0:8787"
+	cfg.Notify.Email = config.EmailConfig{
+		Host: "127.0.0.1",
+		Port: port,
+		TLS:  "none",
+		From: "Jellysink <jellysink@nas.lan>",
+		To:   []string{"admin@example.com", "Other <other@example.com>"},
+	}
+
+	report := reporter.Report{
+		Timestamp:    time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC),
+		LibraryType:  "movies",
+		LibraryPaths: []string{"/movies"},
+		MovieDuplicates: []scanner.MovieDuplicate{
+			{NormalizedName: "heat", Year: "1995", Files: []scanner.MovieFile{
+				{Path: "/movies/Heat (1995)/Heat (1995) 2160p.mkv", Size: 4 << 30},
+				{Path: "/movies/Heat (1995)/Heat (1995) 1080p.mkv", Size: 2 << 30},
+			}},
+		},
+		ComplianceIssues: []scanner.ComplianceIssue{{Path: "/movies/heat.mkv"}},
+	}
+	report.RecountTotals()
+
+	reportPath := "/var/lib/jellysink/scan_results/movies_20260301.json"
+	if err := EmailReport(cfg, report, reportPath); err != nil {
+		t.Fatalf("EmailReport() error = %v", err)
+	}
+
+	var msg string
+	select {
+	case msg = <-got:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fake SMTP server received no message")
+	}
+	for _, want := range []string{
+		"MAIL FROM:<jellysink@nas.lan>",
+		"RCPT TO:<other@example.com>",
+		"To: admin@example.com, Other <other@example.com>\r\n",
+		"Subject: jellysink movies scan: 1 duplicate groups, 2.00 GB reclaimable, 1 compliance issues\r\n",
+		"Duplicate groups: 1 (1 files to delete)\r\n",
+		"Review it with: jellysink view " + reportPath + "\r\n",
+		"http://127.0.0.1:8787/reports/movies_20260301.json",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Email missing %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestEmailReportDisabled(t *testing.T) {
+	called := false
+	sendMail = func(config.EmailConfig, string, []string, []byte) error { called = true; return nil }
+	t.Cleanup(func() { sendMail = deliverMail })
+
+	if err := EmailReport(config.DefaultConfig(), reporter.Report{}, "/tmp/report.json"); err != nil || called {
+		t.Errorf("Expected no email without notify.email host, got err=%v sent=%v", err, called)
+	}
+
+	// A server that refuses the connection is reported, not fatal to the scan
+	sendMail = deliverMail
+	cfg := config.DefaultConfig()
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+	cfg.Notify.Email = config.EmailConfig{Host: "127.0.0.1", Port: port, TLS: "none", To: []string{"admin@example.com"}}
+	if err := EmailReport(cfg, reporter.Report{}, "/tmp/report.json"); err == nil || !strings.Contains(err.Error(), "127.0.0.1") {
+		t.Errorf("Expected a delivery error naming the server, got %v", err)
+	}
+}
+
+func TestEmailReportRequiresSTARTTLS(t *testing.T) {
+	for _, mode := range []string{"", "starttls"} {
+		port, got := fakeSMTP(t)
+		cfg := config.DefaultConfig()
+		cfg.Notify.Email = config.EmailConfig{Host: "127.0.0.1", Port: port, TLS: mode, To: []string{"admin@example.com"}}
+
+		err := EmailReport(cfg, reporter.Report{}, "/tmp/report.json")
+		if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+			t.Errorf("tls %q: expected an error about missing STARTTLS, got %v", mode, err)
+		}
+		select {
+		case msg := <-got:
+			t.Errorf("tls %q: expected nothing sent in the clear, got:\n%s", mode, msg)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
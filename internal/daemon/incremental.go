@@ -0,0 +1,103 @@
+package daemon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// SetIncremental makes scans rescan only the folders with files that
+// changed since the last incremental scan (scan --incremental)
+func (d *Daemon) SetIncremental(incremental bool) {
+	d.incremental = incremental
+}
+
+// scanIncremental runs an incremental scan on top of the scan index and
+// the report it was saved with. The returned index is saved with the new
+// report
+func (d *Daemon) scanIncremental(ctx context.Context, progressCh chan<- scanner.ScanProgress) (*scanner.ScanIndex, *scanner.ScanResult, error) {
+	idx, err := scanner.LoadScanIndex(d.indexPath)
+	if err != nil {
+		notify(progressCh, scanner.SeverityWarn, fmt.Sprintf("Scan index rebuilt: %v", err))
+		idx = scanner.NewScanIndex()
+	}
+
+	var previous *scanner.ScanResult
+	settings := scanSettings(d.config)
+	if idx.Settings != settings {
+		if idx.Report != "" {
+			notify(progressCh, scanner.SeverityInfo, "Settings changed since the last incremental scan, rescanning everything")
+		}
+		idx.Reset(settings)
+	} else if idx.Report != "" {
+		if previous, err = loadPreviousResult(idx.Report); err != nil {
+			notify(progressCh, scanner.SeverityWarn, fmt.Sprintf("Rescanning everything: %v", err))
+		}
+	}
+
+	result, err := scanner.RunIncrementalScan(ctx,
+		d.config.MoviePaths(),
+		d.config.ShowPaths(),
+		idx, previous, progressCh)
+	if err != nil {
+		return nil, nil, err
+	}
+	return idx, result, nil
+}
+
+// scanSettings fingerprints the settings that shape scan findings; findings
+// made under other settings are not reused
+func scanSettings(cfg *config.Config) string {
+	data, _ := json.Marshal(struct {
+		Libraries  config.LibraryConfig
+		Scan       config.ScanConfig
+		Languages  config.LanguagesConfig
+		Naming     config.NamingConfig
+		Duplicates config.DuplicatesConfig
+		API        config.APIConfig
+		Sonarr     config.ArrConfig
+		Radarr     config.ArrConfig
+	}{cfg.Libraries, cfg.Scan, cfg.Languages, cfg.Naming, cfg.Duplicates, cfg.API, cfg.Sonarr, cfg.Radarr})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadPreviousResult reads the findings of the report an incremental scan
+// builds on
+func loadPreviousResult(path string) (*scanner.ScanResult, error) {
+	report, err := readReport(path)
+	if err != nil {
+		return nil, err
+	}
+	return resultOf(report), nil
+}
+
+// readReport reads the JSON report at path
+func readReport(path string) (reporter.Report, error) {
+	report, err := reporter.ReadReport(path)
+	if err != nil {
+		return report, fmt.Errorf("previous report %s: %w", path, err)
+	}
+	return report, nil
+}
+
+// resultOf returns the findings of report as a scan result
+func resultOf(report reporter.Report) *scanner.ScanResult {
+	return &scanner.ScanResult{
+		MovieDuplicates:  report.MovieDuplicates,
+		TVDuplicates:     report.TVDuplicates,
+		ComplianceIssues: report.ComplianceIssues,
+		AmbiguousTVShows: report.AmbiguousTVShows,
+		AmbiguousMovies:  report.AmbiguousMovies,
+		OrphanFolders:    report.OrphanFolders,
+		Artifacts:        report.Artifacts,
+		Quality:          report.Quality,
+		Completeness:     report.Completeness,
+	}
+}
@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestIncrementalScanSettingsChange(t *testing.T) {
+	changes := map[string]func(*config.Config){
+		"scan.allow_versions":           func(c *config.Config) { c.Scan.AllowVersions = true },
+		"scan.exclude":                  func(c *config.Config) { c.Scan.Exclude = []string{"**/Extras/**"} },
+		"scan.bitrate_outlier_factor":   func(c *config.Config) { c.Scan.BitrateOutlierFactor = 3 },
+		"languages.required_audio":      func(c *config.Config) { c.Languages.RequiredAudio = []string{"eng"} },
+		"languages.max_unwanted_tracks": func(c *config.Config) { c.Languages.MaxUnwantedTracks = 2 },
+	}
+
+	for name, change := range changes {
+		t.Run(name, func(t *testing.T) {
+			library := t.TempDir()
+			os.MkdirAll(filepath.Join(library, "Heat (1995)"), 0755)
+			os.WriteFile(filepath.Join(library, "Heat (1995)", "Heat (1995).mkv"), []byte("heat"), 0644)
+
+			before := config.DefaultConfig()
+			before.Libraries.Movies.Paths = []string{library}
+			after := config.DefaultConfig()
+			after.Libraries.Movies.Paths = []string{library}
+			change(after)
+			if scanSettings(before) == scanSettings(after) {
+				t.Fatal("Expected the settings fingerprint to change")
+			}
+
+			// The index points at findings made under the old settings
+			indexPath := filepath.Join(t.TempDir(), "scan_index.json")
+			idx := scanner.NewScanIndex()
+			idx.Reset(scanSettings(before))
+			idx.Report = filepath.Join(t.TempDir(), "previous.json")
+			if err := idx.Save(indexPath); err != nil {
+				t.Fatal(err)
+			}
+
+			progressCh := make(chan scanner.ScanProgress)
+			var messages []string
+			done := make(chan struct{})
+			go func() {
+				for p := range progressCh {
+					messages = append(messages, p.Message)
+				}
+				close(done)
+			}()
+			d := &Daemon{config: after, indexPath: indexPath}
+			_, _, err := d.scanIncremental(context.Background(), progressCh)
+			close(progressCh)
+			<-done
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			joined := strings.Join(messages, "\n")
+			if !strings.Contains(joined, "Settings changed since the last incremental scan") {
+				t.Errorf("Expected the previous findings dropped, got:\n%s", joined)
+			}
+			if strings.Contains(joined, "previous report") {
+				t.Errorf("Expected the previous report not to be read, got:\n%s", joined)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// inotifyMask is what a library watch listens for: files finished or moved
+// in, and anything created, moved out or deleted
+const inotifyMask = syscall.IN_CREATE | syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_FROM |
+	syscall.IN_MOVED_TO | syscall.IN_DELETE | syscall.IN_DELETE_SELF | syscall.IN_ONLYDIR
+
+// inotifyWatcher watches every folder under the library roots; folders
+// created or moved in later are watched as they appear
+type inotifyWatcher struct {
+	file   *os.File
+	events chan watchEvent
+	closed chan struct{}
+
+	mu   sync.Mutex
+	dirs map[int32]string // watch descriptor -> folder
+}
+
+// newInotifyWatcher starts watching roots
+func newInotifyWatcher(roots []string) (libraryWatcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start inotify: %w", err)
+	}
+	w := &inotifyWatcher{
+		// Non-blocking, so Close interrupts a pending Read
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		events: make(chan watchEvent, 256),
+		closed: make(chan struct{}),
+		dirs:   make(map[int32]string),
+	}
+	for _, root := range roots {
+		if err := w.addTree(root); err != nil {
+			w.file.Close()
+			return nil, err
+		}
+	}
+	go w.read()
+	return w, nil
+}
+
+func (w *inotifyWatcher) Events() <-chan watchEvent {
+	return w.events
+}
+
+func (w *inotifyWatcher) Close() error {
+	close(w.closed)
+	return w.file.Close()
+}
+
+// send forwards event unless the watcher was closed meanwhile
+func (w *inotifyWatcher) send(event watchEvent) {
+	select {
+	case w.events <- event:
+	case <-w.closed:
+	}
+}
+
+// addTree watches dir and the folders under it, skipping the ones scans
+// ignore. Unreadable subfolders are skipped; an unreadable root is an error
+func (w *inotifyWatcher) addTree(dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if path == dir {
+				return fmt.Errorf("failed to watch %s: %w", dir, err)
+			}
+			return filepath.SkipDir
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != dir && scanner.IsIgnoredDir(path) {
+			return filepath.SkipDir
+		}
+		wd, err := syscall.InotifyAddWatch(int(w.file.Fd()), path, inotifyMask)
+		if err != nil {
+			if errors.Is(err, syscall.ENOSPC) {
+				return fmt.Errorf("failed to watch %s: inotify watch limit reached (raise fs.inotify.max_user_watches): %w", path, err)
+			}
+			if path == dir {
+				return fmt.Errorf("failed to watch %s: %w", path, err)
+			}
+			return filepath.SkipDir
+		}
+		w.mu.Lock()
+		w.dirs[int32(wd)] = path
+		w.mu.Unlock()
+		return nil
+	})
+}
+
+// read turns inotify events into watch events until the watcher is closed
+func (w *inotifyWatcher) read() {
+	defer close(w.events)
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			return
+		}
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			raw := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameStart := offset + syscall.SizeofInotifyEvent
+			name := strings.TrimRight(string(buf[nameStart:nameStart+int(raw.Len)]), "\x00")
+			offset = nameStart + int(raw.Len)
+			w.handle(raw.Wd, raw.Mask, name)
+		}
+	}
+}
+
+// handle forwards one inotify event
+func (w *inotifyWatcher) handle(wd int32, mask uint32, name string) {
+	if mask&syscall.IN_Q_OVERFLOW != 0 {
+		// Events were lost; an incremental scan still finds the changes
+		w.send(watchEvent{Op: watchOverflow})
+		return
+	}
+
+	w.mu.Lock()
+	dir, ok := w.dirs[wd]
+	if mask&syscall.IN_IGNORED != 0 {
+		delete(w.dirs, wd)
+	}
+	w.mu.Unlock()
+	if !ok || name == "" {
+		return
+	}
+	path := filepath.Join(dir, name)
+
+	isDir := mask&syscall.IN_ISDIR != 0
+	switch {
+	case mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0:
+		if isDir {
+			if scanner.IsIgnoredDir(path) {
+				return
+			}
+			// Files can land in the new folder before it is watched, so
+			// the whole folder counts as changed
+			w.addTree(path)
+		}
+		op := watchCreated
+		if mask&syscall.IN_MOVED_TO != 0 {
+			op = watchMoved
+		}
+		w.send(watchEvent{Path: path, Op: op, Dir: isDir})
+	case mask&syscall.IN_CLOSE_WRITE != 0:
+		w.send(watchEvent{Path: path, Op: watchWritten})
+	case mask&(syscall.IN_MOVED_FROM|syscall.IN_DELETE) != 0:
+		w.send(watchEvent{Path: path, Op: watchRemoved, Dir: isDir})
+	}
+}
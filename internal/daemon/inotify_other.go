@@ -0,0 +1,10 @@
+//go:build !linux
+
+package daemon
+
+import "errors"
+
+// newInotifyWatcher is only available on Linux
+func newInotifyWatcher(roots []string) (libraryWatcher, error) {
+	return nil, errors.New("watch mode needs inotify, which is only available on Linux")
+}
@@ -0,0 +1,170 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/cleaner"
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/jellyfin"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// CompareWithJellyfin checks the configured libraries against the items the
+// Jellyfin server knows about. Server errors are recorded on the comparison
+// rather than failing the scan
+func CompareWithJellyfin(ctx context.Context, cfg *config.Config, progressCh chan<- scanner.ScanProgress) *jellyfin.Comparison {
+	var pr *scanner.ProgressReporter
+	if progressCh != nil {
+		pr = scanner.NewProgressReporter(progressCh, scanner.OpJellyfinCompare)
+		pr.Update(0, "Comparing against Jellyfin")
+	}
+
+	result, err := compareWithJellyfin(ctx, cfg, pr)
+	if err != nil {
+		if pr != nil {
+			pr.LogError(err, "Jellyfin comparison failed")
+		}
+		return &jellyfin.Comparison{ServerURL: cfg.Jellyfin.URL, Error: err.Error()}
+	}
+
+	if pr != nil {
+		pr.Complete(fmt.Sprintf("Jellyfin comparison: %d on disk but not in Jellyfin, %d in Jellyfin but missing on disk",
+			len(result.MissingFromServer), len(result.MissingOnDisk)))
+	}
+	return result
+}
+
+func compareWithJellyfin(ctx context.Context, cfg *config.Config, pr *scanner.ProgressReporter) (*jellyfin.Comparison, error) {
+	mappings, err := pathMappings(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	client := jellyfin.NewClient(cfg.Jellyfin.URL, cfg.Jellyfin.APIKey)
+	items, err := client.MediaItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if pr != nil {
+		pr.Update(50, fmt.Sprintf("Jellyfin lists %d items, listing files on disk", len(items)))
+	}
+
+	libraryPaths := cfg.GetAllPaths()
+	var localFiles []string
+	for _, path := range libraryPaths {
+		files, err := scanner.ListVideoFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		localFiles = append(localFiles, files...)
+	}
+
+	result := jellyfin.Compare(items, localFiles, libraryPaths, mappings)
+	result.ServerURL = cfg.Jellyfin.URL
+	return result, nil
+}
+
+// pathMappings parses [jellyfin] path_mappings
+func pathMappings(cfg *config.Config) ([]jellyfin.PathMapping, error) {
+	var mappings []jellyfin.PathMapping
+	for _, s := range cfg.Jellyfin.PathMappings {
+		m, err := jellyfin.ParsePathMapping(s)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, m)
+	}
+	return mappings, nil
+}
+
+// NewInUseChecker returns the cleaner's in-use check for cfg: files playing
+// on the Jellyfin server when one is configured, with lsof as the fallback
+// when the server cannot be asked
+func NewInUseChecker(cfg *config.Config) cleaner.InUseChecker {
+	if !jellyfinConfigured(cfg) {
+		return cleaner.LsofChecker{}
+	}
+	mappings, err := pathMappings(cfg)
+	if err != nil {
+		return cleaner.LsofChecker{}
+	}
+
+	client := jellyfin.NewClient(cfg.Jellyfin.URL, cfg.Jellyfin.APIKey)
+	return &cleaner.PlayingChecker{
+		Playing: func() ([]string, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			paths, err := client.PlayingPaths(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for i, p := range paths {
+				paths[i] = jellyfin.LocalPath(p, mappings)
+			}
+			return paths, nil
+		},
+		Fallback: cleaner.LsofChecker{},
+	}
+}
+
+// jellyfinConfigured reports whether cfg has the server URL and API key
+func jellyfinConfigured(cfg *config.Config) bool {
+	return cfg != nil && cfg.Jellyfin.URL != "" && cfg.Jellyfin.APIKey != ""
+}
+
+// NewLibraryRefresher returns the cleaner's post-clean library scan request,
+// or nil when Jellyfin is not configured or refresh_after_clean is off
+func NewLibraryRefresher(cfg *config.Config) cleaner.LibraryRefresher {
+	if !jellyfinConfigured(cfg) || !cfg.Jellyfin.RefreshAfterClean {
+		return nil
+	}
+
+	client := jellyfin.NewClient(cfg.Jellyfin.URL, cfg.Jellyfin.APIKey)
+	return cleaner.RefreshFunc(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return client.RefreshLibrary(ctx)
+	})
+}
+
+// CrossCheckTitles records, for each ambiguous TV show, the series title
+// Jellyfin already uses for it. The check is advisory: server errors are
+// logged and leave the resolutions unchanged
+func CrossCheckTitles(ctx context.Context, cfg *config.Config, resolutions []*scanner.TVTitleResolution, progressCh chan<- scanner.ScanProgress) {
+	if !jellyfinConfigured(cfg) || len(resolutions) == 0 {
+		return
+	}
+
+	var pr *scanner.ProgressReporter
+	if progressCh != nil {
+		pr = scanner.NewProgressReporter(progressCh, scanner.OpJellyfinCompare)
+	}
+
+	series, err := jellyfin.NewClient(cfg.Jellyfin.URL, cfg.Jellyfin.APIKey).SeriesNames(ctx)
+	if err != nil {
+		if pr != nil {
+			pr.LogError(err, "Jellyfin title cross-check failed")
+		}
+		return
+	}
+
+	matched := 0
+	for _, res := range resolutions {
+		candidates := []string{res.ResolvedTitle}
+		if res.FolderMatch != nil {
+			candidates = append(candidates, res.FolderMatch.Title)
+		}
+		if res.FilenameMatch != nil {
+			candidates = append(candidates, res.FilenameMatch.Title)
+		}
+		res.JellyfinTitle = jellyfin.MatchTitle(series, candidates...)
+		if res.JellyfinTitle != "" {
+			matched++
+		}
+	}
+	if pr != nil {
+		pr.Complete(fmt.Sprintf("Jellyfin already has %d of %d ambiguous shows", matched, len(resolutions)))
+	}
+}
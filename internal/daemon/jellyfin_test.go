@@ -0,0 +1,111 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestCompareWithJellyfin(t *testing.T) {
+	library := t.TempDir()
+	matched := filepath.Join(library, "Heat (1995)", "Heat (1995).mkv")
+	unmatched := filepath.Join(library, "heat.2.2025.1080p.mkv")
+	for _, path := range []string{matched, unmatched} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("video"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"Items":[{"Id":"1","Name":"Heat","Type":"Movie","Path":%q},{"Id":"2","Name":"Alien","Type":"Movie","Path":%q}],"TotalRecordCount":2}`,
+			"/data/Heat (1995)/Heat (1995).mkv", "/data/Alien (1979)/Alien (1979).mkv")
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Libraries.Movies.Paths = []string{library}
+	cfg.Jellyfin = config.JellyfinConfig{
+		URL:          server.URL,
+		APIKey:       "key",
+		Compare:      true,
+		PathMappings: []string{"/data=" + library},
+	}
+
+	cmp := CompareWithJellyfin(context.Background(), cfg, nil)
+	if cmp.Error != "" {
+		t.Fatalf("Unexpected comparison error: %s", cmp.Error)
+	}
+	if len(cmp.MissingFromServer) != 1 || cmp.MissingFromServer[0] != unmatched {
+		t.Errorf("Expected %s unmatched, got %v", unmatched, cmp.MissingFromServer)
+	}
+	if len(cmp.MissingOnDisk) != 1 || cmp.MissingOnDisk[0].Name != "Alien" {
+		t.Errorf("Expected Alien missing on disk, got %+v", cmp.MissingOnDisk)
+	}
+
+	// An unreachable server is recorded on the report, not a scan failure
+	server.Close()
+	cmp = CompareWithJellyfin(context.Background(), cfg, nil)
+	if !strings.Contains(cmp.Error, "Jellyfin request failed") {
+		t.Errorf("Expected request error recorded, got %+v", cmp)
+	}
+}
+
+func TestCrossCheckTitlesAndRefresher(t *testing.T) {
+	var refreshed int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/Library/Refresh":
+			refreshed++
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/Items" && r.URL.Query().Get("IncludeItemTypes") == "Series":
+			fmt.Fprint(w, `{"Items":[{"Id":"1","Name":"Degrassi: The Next Generation","Type":"Series"}],"TotalRecordCount":1}`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Jellyfin.URL = server.URL
+	cfg.Jellyfin.APIKey = "key"
+
+	resolutions := []*scanner.TVTitleResolution{
+		{
+			ResolvedTitle: "Degrassi",
+			FolderMatch:   &scanner.TVTitleMatch{Title: "Degrassi"},
+			FilenameMatch: &scanner.TVTitleMatch{Title: "Degrassi The Next Generation"},
+		},
+		{ResolvedTitle: "Firefly"},
+	}
+	CrossCheckTitles(context.Background(), cfg, resolutions, nil)
+	if resolutions[0].JellyfinTitle != "Degrassi: The Next Generation" {
+		t.Errorf("Expected the Jellyfin series title, got %q", resolutions[0].JellyfinTitle)
+	}
+	if resolutions[1].JellyfinTitle != "" {
+		t.Errorf("Expected no match for Firefly, got %q", resolutions[1].JellyfinTitle)
+	}
+
+	refresher := NewLibraryRefresher(cfg)
+	if refresher == nil {
+		t.Fatal("Expected a refresher with url and api_key set")
+	}
+	if err := refresher.RefreshLibrary(); err != nil || refreshed != 1 {
+		t.Errorf("Expected one refresh request, got %d (%v)", refreshed, err)
+	}
+
+	cfg.Jellyfin.RefreshAfterClean = false
+	if NewLibraryRefresher(cfg) != nil {
+		t.Error("Expected no refresher with refresh_after_clean off")
+	}
+}
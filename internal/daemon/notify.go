@@ -1,59 +1,257 @@
 package daemon
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/cleaner"
+	"github.com/Nomadcxx/jellysink/internal/config"
+	notifications "github.com/Nomadcxx/jellysink/internal/notify" // broker.go has a notify func
+	"github.com/Nomadcxx/jellysink/internal/reporter"
 )
 
-// NotifyUser launches kitty with the scan report (this IS the notification)
-func NotifyUser(reportPath string) error {
-	return LaunchTUI(reportPath)
+// terminalTemplates are tried in order when daemon.terminal_command is
+// empty. {command} expands to the jellysink view command line
+var terminalTemplates = []string{
+	"kitty --hold {command}",
+	"alacritty --hold -e {command}",
+	"gnome-terminal --wait -- {command}",
+	"konsole --hold -e {command}",
+	"xterm -hold -e {command}",
 }
 
-// LaunchTUI opens kitty terminal with the TUI to review the report
-func LaunchTUI(reportPath string) error {
-	// Check if kitty is available
-	kittyPath, err := exec.LookPath("kitty")
-	if err != nil {
-		return fmt.Errorf("kitty terminal not found (required for jellysink daemon): %w", err)
+// lookPath and startCommand find and start programs (replaced in tests).
+// Started programs are reaped in the background so jellysinkd --daemon
+// does not collect zombies
+var (
+	lookPath     = exec.LookPath
+	startCommand = func(cmd *exec.Cmd) error {
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		go cmd.Wait()
+		return nil
 	}
+)
 
-	// Get jellysink binary path
-	binaryPath, err := exec.LookPath("jellysink")
+// NotifyUser opens the report for review in a terminal. Without a terminal
+// it falls back to a desktop notification naming the report. It returns
+// what it did, e.g. "opened in kitty"
+func NotifyUser(cfg *config.Config, report reporter.Report, reportPath string) (string, error) {
+	terminal, err := LaunchTUI(cfg.Daemon.TerminalCommand, reportPath)
+	if err == nil {
+		return "opened in " + terminal, nil
+	}
+	if notifyErr := desktopNotify(report, reportPath); notifyErr != nil {
+		return "", fmt.Errorf("%w; desktop notification failed too: %v", err, notifyErr)
+	}
+	return fmt.Sprintf("sent a desktop notification (%v)", err), nil
+}
+
+// LaunchTUI opens a terminal running "jellysink view" on the report, with
+// daemon.terminal_command or the first terminal found installed, and
+// returns the terminal's name. It does not wait for the terminal to close
+func LaunchTUI(terminalCommand, reportPath string) (string, error) {
+	binaryPath, err := lookPath("jellysink")
 	if err != nil {
 		// Try local build
 		wd, _ := os.Getwd()
 		binaryPath = filepath.Join(wd, "jellysink")
 		if _, err := os.Stat(binaryPath); err != nil {
-			return fmt.Errorf("jellysink binary not found: %w", err)
+			return "", fmt.Errorf("jellysink binary not found: %w", err)
 		}
 	}
 
-	// Launch TUI in kitty
-	// Use kitty's proper syntax: kitty [options] [program-to-run [program-args]]
-	// jellysink uses "view <report-file>" command to display reports
-	cmd := exec.Command(kittyPath, "--hold", binaryPath, "view", reportPath)
+	templates := terminalTemplates
+	if terminalCommand != "" {
+		templates = []string{terminalCommand}
+	}
+	for _, tmpl := range templates {
+		args, err := TerminalArgs(tmpl, binaryPath, reportPath)
+		if err != nil {
+			return "", err
+		}
+		terminal, err := lookPath(args[0])
+		if err != nil {
+			if terminalCommand != "" {
+				return "", fmt.Errorf("terminal %s not found (daemon.terminal_command): %w", args[0], err)
+			}
+			continue
+		}
+		// The terminal inherits DISPLAY, WAYLAND_DISPLAY and XDG_RUNTIME_DIR
+		// (critical for a GUI started from systemd)
+		cmd := exec.Command(terminal, args[1:]...)
+		cmd.Env = os.Environ()
+		if err := startCommand(cmd); err != nil {
+			return "", fmt.Errorf("failed to launch %s with jellysink: %w", filepath.Base(args[0]), err)
+		}
+		return filepath.Base(args[0]), nil
+	}
+	return "", fmt.Errorf("no terminal found (tried kitty, alacritty, gnome-terminal, konsole and xterm; set daemon.terminal_command)")
+}
+
+// TerminalStatus describes where reports open for review, for config output
+func TerminalStatus(terminalCommand string) string {
+	if terminalCommand != "" {
+		return terminalCommand
+	}
+	for _, tmpl := range terminalTemplates {
+		name := strings.Fields(tmpl)[0]
+		if _, err := lookPath(name); err == nil {
+			return name + " (detected)"
+		}
+	}
+	if _, err := lookPath("notify-send"); err == nil {
+		return "no terminal found, desktop notification via notify-send"
+	}
+	return "no terminal or notify-send found"
+}
 
-	// Explicitly pass display environment variables (critical for GUI from systemd)
-	env := os.Environ()
-	if display := os.Getenv("DISPLAY"); display != "" {
-		env = append(env, fmt.Sprintf("DISPLAY=%s", display))
+// TerminalArgs expands a terminal_command template into the program and its
+// arguments. {command} becomes the jellysink view command line as separate
+// arguments, {jellysink} the binary and {report} the report path
+func TerminalArgs(template, binaryPath, reportPath string) ([]string, error) {
+	fields := strings.Fields(template)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty terminal command")
 	}
-	if waylandDisplay := os.Getenv("WAYLAND_DISPLAY"); waylandDisplay != "" {
-		env = append(env, fmt.Sprintf("WAYLAND_DISPLAY=%s", waylandDisplay))
+	var args []string
+	found := false
+	for _, field := range fields {
+		if field == "{command}" {
+			args = append(args, binaryPath, "view", reportPath)
+			found = true
+			continue
+		}
+		if strings.Contains(field, "{report}") {
+			found = true
+		}
+		field = strings.ReplaceAll(field, "{jellysink}", binaryPath)
+		args = append(args, strings.ReplaceAll(field, "{report}", reportPath))
 	}
-	if xdgRuntime := os.Getenv("XDG_RUNTIME_DIR"); xdgRuntime != "" {
-		env = append(env, fmt.Sprintf("XDG_RUNTIME_DIR=%s", xdgRuntime))
+	if !found {
+		return nil, fmt.Errorf("terminal command %q has no {command} or {report}", template)
 	}
-	cmd.Env = env
+	return args, nil
+}
 
-	// Start the process (non-blocking, like sysc-walls does)
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to launch kitty with jellysink: %w", err)
+// desktopNotify posts a notification with notify-send naming the report
+func desktopNotify(report reporter.Report, reportPath string) error {
+	notifySend, err := lookPath("notify-send")
+	if err != nil {
+		return fmt.Errorf("notify-send not found: %w", err)
 	}
+	cmd := exec.Command(notifySend, "--app-name=jellysink", "--icon=dialog-information",
+		emailSubject(report), "Review it with: jellysink view "+reportPath)
+	cmd.Env = os.Environ()
+	return startCommand(cmd)
+}
+
+// NewDispatcher builds the [notify] webhook, Discord and Telegram targets,
+// or returns nil when none is configured
+func NewDispatcher(cfg *config.Config) (*notifications.Dispatcher, error) {
+	n := cfg.Notify
+	var targets []notifications.Target
+	add := func(notifier notifications.Notifier, events []string, text string) error {
+		target := notifications.Target{Notifier: notifier}
+		for _, e := range events {
+			kind, err := notifications.ParseKind(e)
+			if err != nil {
+				return fmt.Errorf("notify.%s: %w", notifier.Name(), err)
+			}
+			target.Events = append(target.Events, kind)
+		}
+		tmpl, err := notifications.ParseTemplate(text)
+		if err != nil {
+			return fmt.Errorf("notify.%s: %w", notifier.Name(), err)
+		}
+		target.Template = tmpl
+		targets = append(targets, target)
+		return nil
+	}
+	if n.Webhook.URL != "" {
+		if err := add(&notifications.Webhook{URL: n.Webhook.URL}, n.Webhook.Events, n.Webhook.Template); err != nil {
+			return nil, err
+		}
+	}
+	if n.Discord.WebhookURL != "" {
+		if err := add(&notifications.Discord{WebhookURL: n.Discord.WebhookURL}, n.Discord.Events, n.Discord.Template); err != nil {
+			return nil, err
+		}
+	}
+	if n.Telegram.BotToken != "" {
+		if err := add(&notifications.Telegram{BotToken: n.Telegram.BotToken, ChatID: n.Telegram.ChatID}, n.Telegram.Events, n.Telegram.Template); err != nil {
+			return nil, err
+		}
+	}
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	return &notifications.Dispatcher{Targets: targets}, nil
+}
+
+// PostEvent sends event to the configured targets that receive it and
+// returns their names. No configured target is not an error
+func PostEvent(cfg *config.Config, event notifications.Event) ([]string, error) {
+	dispatcher, err := NewDispatcher(cfg)
+	if err != nil || dispatcher == nil {
+		return nil, err
+	}
+	names := dispatcher.Names(event.Kind)
+	if len(names) == 0 {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := dispatcher.Send(ctx, event); err != nil {
+		return names, fmt.Errorf("failed to post %s event: %w", event.Kind, err)
+	}
+	return names, nil
+}
+
+// newEvent starts an event of kind k stamped with this host
+func newEvent(k notifications.Kind) notifications.Event {
+	host, _ := os.Hostname()
+	return notifications.Event{Kind: k, Time: time.Now(), Host: host}
+}
+
+// ScanEvent summarises the scan saved at reportPath
+func ScanEvent(cfg *config.Config, report reporter.Report, reportPath string) notifications.Event {
+	event := newEvent(notifications.ScanComplete)
+	event.Library = report.LibraryType
+	event.ReportPath = reportPath
+	if cfg.Daemon.HTTPAddr != "" {
+		event.ReportURL = apiURL(cfg.Daemon.HTTPAddr, "/reports/"+filepath.Base(reportPath))
+	}
+	event.Duplicates = report.TotalDuplicates
+	event.FilesToDelete = report.TotalFilesToDelete
+	event.SpaceToFree = report.SpaceToFree
+	event.ComplianceIssues = len(report.ComplianceIssues)
+	event.Partial = report.Partial != nil
+	event.Simulated = report.Simulated
+	return event
+}
+
+// CleanEvent summarises an auto-clean
+func CleanEvent(result cleaner.CleanResult) notifications.Event {
+	event := newEvent(notifications.CleanComplete)
+	event.DuplicatesDeleted = result.DuplicatesDeleted
+	event.ComplianceFixed = result.ComplianceFixed
+	event.SpaceFreed = result.SpaceFreed
+	event.Deferred = len(result.Deferred) + len(result.Importing)
+	event.Errors = len(result.Errors)
+	event.JournalID = result.JournalID
+	return event
+}
 
-	// Don't wait for the process - let it run independently
-	return nil
+// ErrorEvent reports a failed scan or auto-clean
+func ErrorEvent(err error) notifications.Event {
+	event := newEvent(notifications.Failure)
+	event.Error = err.Error()
+	return event
 }
@@ -0,0 +1,121 @@
+package daemon
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/cleaner"
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+)
+
+func TestNewDispatcher(t *testing.T) {
+	cfg := config.DefaultConfig()
+	if d, err := NewDispatcher(cfg); d != nil || err != nil {
+		t.Errorf("Expected no dispatcher without targets, got %v, %v", d, err)
+	}
+	if names, err := PostEvent(cfg, ErrorEvent(errors.New("scan failed"))); names != nil || err != nil {
+		t.Errorf("Expected nothing posted without targets, got %v, %v", names, err)
+	}
+
+	cfg.Notify.Discord.WebhookURL = "https://discord.com/api/webhooks/1/abc"
+	cfg.Notify.Telegram = config.TelegramConfig{BotToken: "1:x", ChatID: "42", Events: []string{"error"}}
+	d, err := NewDispatcher(cfg)
+	if err != nil || d == nil || len(d.Targets) != 2 {
+		t.Fatalf("NewDispatcher() = %v, %v; want Discord and Telegram", d, err)
+	}
+	if names := d.Names("clean"); len(names) != 1 || names[0] != "discord" {
+		t.Errorf("Names(clean) = %v, want only discord", names)
+	}
+
+	cfg.Notify.Webhook.URL = "https://hooks.example.com"
+	cfg.Notify.Webhook.Template = "{{.Nope}}"
+	if _, err := NewDispatcher(cfg); err == nil {
+		t.Error("Expected a broken template to fail")
+	}
+}
+
+func TestEvents(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Daemon.HTTPAddr = ":8787"
+	report := reporter.Report{LibraryType: "tv", TotalDuplicates: 2, SpaceToFree: 1 << 30, Partial: &reporter.PartialScan{}}
+	event := ScanEvent(cfg, report, "/data/scan_results/tv_20260301.json")
+	if event.Kind != "scan" || event.Library != "tv" || event.Duplicates != 2 || !event.Partial ||
+		event.ReportURL != "http://127.0.0.1:8787/reports/tv_20260301.json" {
+		t.Errorf("Unexpected scan event %+v", event)
+	}
+
+	clean := CleanEvent(cleaner.CleanResult{DuplicatesDeleted: 3, Deferred: []string{"a"}, Importing: []string{"b"}, Errors: []error{errors.New("x")}})
+	if clean.Kind != "clean" || clean.DuplicatesDeleted != 3 || clean.Deferred != 2 || clean.Errors != 1 {
+		t.Errorf("Unexpected clean event %+v", clean)
+	}
+}
+
+func TestTerminalArgs(t *testing.T) {
+	tests := []struct {
+		template string
+		want     []string
+	}{
+		{"kitty --hold {command}", []string{"kitty", "--hold", "/usr/bin/jellysink", "view", "/r/movies.json"}},
+		{"wezterm start -- {jellysink} view {report}", []string{"wezterm", "start", "--", "/usr/bin/jellysink", "view", "/r/movies.json"}},
+		{"foot --title=jellysink:{report} {command}", []string{"foot", "--title=jellysink:/r/movies.json", "/usr/bin/jellysink", "view", "/r/movies.json"}},
+	}
+	for _, tt := range tests {
+		got, err := TerminalArgs(tt.template, "/usr/bin/jellysink", "/r/movies.json")
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("TerminalArgs(%q) = %q, %v; want %q", tt.template, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "xterm -e jellysink"} {
+		if _, err := TerminalArgs(bad, "/usr/bin/jellysink", "/r/movies.json"); err == nil {
+			t.Errorf("Expected TerminalArgs(%q) to fail", bad)
+		}
+	}
+}
+
+func TestNotifyUser(t *testing.T) {
+	installed := map[string]bool{"jellysink": true}
+	var started [][]string
+	origLookPath, origStart := lookPath, startCommand
+	lookPath = func(name string) (string, error) {
+		if installed[name] {
+			return filepath.Join("/usr/bin", name), nil
+		}
+		return "", exec.ErrNotFound
+	}
+	startCommand = func(cmd *exec.Cmd) error {
+		started = append(started, cmd.Args)
+		return nil
+	}
+	t.Cleanup(func() { lookPath, startCommand = origLookPath, origStart })
+
+	cfg := config.DefaultConfig()
+	report := reporter.Report{LibraryType: "movies", TotalDuplicates: 2}
+
+	// The first installed terminal is used
+	installed["konsole"], installed["xterm"] = true, true
+	how, err := NotifyUser(cfg, report, "/r/movies.json")
+	if err != nil || how != "opened in konsole" || strings.Join(started[0], " ") != "/usr/bin/konsole --hold -e /usr/bin/jellysink view /r/movies.json" {
+		t.Errorf("NotifyUser() = %q, %v, started %q", how, err, started)
+	}
+
+	// A configured terminal that is missing falls back to a desktop notification
+	started = nil
+	cfg.Daemon.TerminalCommand = "wezterm start -- {command}"
+	installed["notify-send"] = true
+	how, err = NotifyUser(cfg, report, "/r/movies.json")
+	if err != nil || !strings.HasPrefix(how, "sent a desktop notification") || len(started) != 1 ||
+		started[0][0] != "/usr/bin/notify-send" || started[0][len(started[0])-1] != "Review it with: jellysink view /r/movies.json" {
+		t.Errorf("NotifyUser() = %q, %v, started %q", how, err, started)
+	}
+
+	// With neither, the error names both failures
+	installed["notify-send"] = false
+	if _, err := NotifyUser(cfg, report, "/r/movies.json"); err == nil || !strings.Contains(err.Error(), "wezterm") || !strings.Contains(err.Error(), "notify-send") {
+		t.Errorf("Expected an error naming the terminal and notify-send, got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package daemon
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// PolicyRules describes the [autoclean] rules in effect, or "off"
+func PolicyRules(policy config.AutoCleanConfig) []string {
+	var rules []string
+	if policy.IdenticalOnly {
+		rules = append(rules, "identical duplicates only")
+	}
+	if policy.MinConfidence > 0 {
+		rules = append(rules, fmt.Sprintf("compliance fixes at least %.2f confident", policy.MinConfidence))
+	}
+	if policy.MinAgeDays > 0 {
+		rules = append(rules, fmt.Sprintf("files unchanged for %d days", policy.MinAgeDays))
+	}
+	if policy.MaxDeleteGB > 0 {
+		rules = append(rules, fmt.Sprintf("at most %.2f GB deleted per run", policy.MaxDeleteGB))
+	}
+	if len(rules) == 0 {
+		return []string{"off"}
+	}
+	return rules
+}
+
+// applyPolicy narrows report's duplicate groups and issues to what the
+// [autoclean] rules let an unattended clean touch. Held groups and skipped
+// issues stay in the report for the next run or a manual clean. Without
+// checkFiles the rules that read the library (min_age_days, identical_only)
+// are left out, as a simulation has no files
+func (d *Daemon) applyPolicy(report reporter.Report, issues []scanner.ComplianceIssue, checkFiles bool) (reporter.Report, []scanner.ComplianceIssue) {
+	policy := d.config.AutoClean
+	cutoff := time.Now().AddDate(0, 0, -policy.MinAgeDays)
+	recent := func(path string) bool {
+		if !checkFiles || policy.MinAgeDays <= 0 {
+			return false
+		}
+		info, err := os.Stat(path)
+		// A file that cannot be checked is left alone too
+		return err != nil || info.ModTime().After(cutoff)
+	}
+
+	if policy.MinConfidence > 0 {
+		kept := issues[:0:0]
+		for _, issue := range issues {
+			if issue.EffectiveConfidence() >= policy.MinConfidence {
+				kept = append(kept, issue)
+			}
+		}
+		if skipped := len(issues) - len(kept); skipped > 0 {
+			fmt.Printf("Skipping %d compliance issue(s) below autoclean min_confidence %.2f\n", skipped, policy.MinConfidence)
+		}
+		issues = kept
+	}
+	if checkFiles && policy.MinAgeDays > 0 {
+		kept := issues[:0:0]
+		for _, issue := range issues {
+			if !recent(issue.Path) {
+				kept = append(kept, issue)
+			}
+		}
+		if skipped := len(issues) - len(kept); skipped > 0 {
+			fmt.Printf("Skipping %d compliance issue(s) on files changed in the last %d days\n", skipped, policy.MinAgeDays)
+		}
+		issues = kept
+	}
+
+	var budget int64 = -1
+	if policy.MaxDeleteGB > 0 {
+		budget = int64(policy.MaxDeleteGB * (1 << 30))
+	}
+	heldRecent, heldDifferent, heldBudget := 0, 0, 0
+	// allow reports whether the group with these paths and sizes, keeper
+	// first, may be deleted, and charges it to the per-run budget
+	allow := func(paths []string, sizes []int64) bool {
+		for _, path := range paths {
+			if recent(path) {
+				heldRecent++
+				return false
+			}
+		}
+		if checkFiles && policy.IdenticalOnly {
+			for i := 1; i < len(paths); i++ {
+				if same, err := identicalFiles(paths[0], paths[i]); !same {
+					if err != nil {
+						fmt.Printf("Warning: %v\n", err)
+					}
+					heldDifferent++
+					return false
+				}
+			}
+		}
+		var size int64
+		for _, s := range sizes[1:] {
+			size += s
+		}
+		if budget >= 0 {
+			if size > budget {
+				heldBudget++
+				return false
+			}
+			budget -= size
+		}
+		return true
+	}
+
+	var movies []scanner.MovieDuplicate
+	for _, dup := range report.MovieDuplicates {
+		if dup.Excluded || dup.KeepsAllVersions() || len(dup.Files) < 2 {
+			movies = append(movies, dup)
+			continue
+		}
+		paths, sizes := make([]string, len(dup.Files)), make([]int64, len(dup.Files))
+		for i, f := range dup.Files {
+			paths[i], sizes[i] = f.Path, f.Size
+		}
+		if allow(paths, sizes) {
+			movies = append(movies, dup)
+		}
+	}
+	var tv []scanner.TVDuplicate
+	for _, dup := range report.TVDuplicates {
+		if dup.Excluded || len(dup.Files) < 2 {
+			tv = append(tv, dup)
+			continue
+		}
+		paths, sizes := make([]string, len(dup.Files)), make([]int64, len(dup.Files))
+		for i, f := range dup.Files {
+			paths[i], sizes[i] = f.Path, f.Size
+		}
+		if allow(paths, sizes) {
+			tv = append(tv, dup)
+		}
+	}
+
+	if heldRecent > 0 {
+		fmt.Printf("Holding %d duplicate group(s) with files changed in the last %d days\n", heldRecent, policy.MinAgeDays)
+	}
+	if heldDifferent > 0 {
+		fmt.Printf("Holding %d duplicate group(s) whose copies are not identical (autoclean identical_only)\n", heldDifferent)
+	}
+	if heldBudget > 0 {
+		fmt.Printf("Holding %d duplicate group(s) over autoclean max_delete_gb %.2f until the next run\n", heldBudget, policy.MaxDeleteGB)
+	}
+	if heldRecent+heldDifferent+heldBudget > 0 {
+		report.MovieDuplicates, report.TVDuplicates = movies, tv
+		report.RecountTotals()
+	}
+	return report, issues
+}
+
+// identicalFiles reports whether a and b have the same content. Copies of
+// different sizes are told apart without reading them; files on a cloud
+// mount are not read while performance.cloud_safe is on
+func identicalFiles(a, b string) (bool, error) {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	if os.SameFile(infoA, infoB) {
+		return true, nil
+	}
+	if infoA.Size() != infoB.Size() {
+		return false, nil
+	}
+	if scanner.CloudSafe() {
+		for _, path := range []string{a, b} {
+			if point, _, ok := scanner.CloudMount(path); ok {
+				return false, fmt.Errorf("not comparing %s: %s is a cloud mount (performance.cloud_safe)", path, point)
+			}
+		}
+	}
+
+	fa, err := os.Open(a)
+	if err != nil {
+		return false, err
+	}
+	defer fa.Close()
+	fb, err := os.Open(b)
+	if err != nil {
+		return false, err
+	}
+	defer fb.Close()
+
+	bufA, bufB := make([]byte, 1<<20), make([]byte, 1<<20)
+	for {
+		na, errA := io.ReadFull(fa, bufA)
+		nb, errB := io.ReadFull(fb, bufB)
+		if na != nb || !bytes.Equal(bufA[:na], bufB[:nb]) {
+			return false, nil
+		}
+		if errA == io.EOF || errA == io.ErrUnexpectedEOF {
+			return errB == io.EOF || errB == io.ErrUnexpectedEOF, nil
+		}
+		if errA != nil {
+			return false, fmt.Errorf("failed to read %s: %w", a, errA)
+		}
+		if errB != nil {
+			return false, fmt.Errorf("failed to read %s: %w", b, errB)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// policyFile writes content to name under dir, modified age ago
+func policyFile(t *testing.T, dir, name, content string, age time.Duration) scanner.MovieFile {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-age)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	return scanner.MovieFile{Path: path, Size: int64(len(content))}
+}
+
+func TestApplyPolicy(t *testing.T) {
+	dir := t.TempDir()
+	month := 30 * 24 * time.Hour
+	report := reporter.Report{MovieDuplicates: []scanner.MovieDuplicate{
+		// Byte-identical copies, untouched for a month
+		{NormalizedName: "heat", Files: []scanner.MovieFile{
+			policyFile(t, dir, "heat.mkv", "same frames", month),
+			policyFile(t, dir, "heat copy.mkv", "same frames", month),
+		}},
+		// Same size, different content
+		{NormalizedName: "alien", Files: []scanner.MovieFile{
+			policyFile(t, dir, "alien 1080p.mkv", "frames one", month),
+			policyFile(t, dir, "alien 720p.mkv", "frames two", month),
+		}},
+		// Identical, but the copy arrived yesterday
+		{NormalizedName: "ronin", Files: []scanner.MovieFile{
+			policyFile(t, dir, "ronin.mkv", "ronin", month),
+			policyFile(t, dir, "ronin copy.mkv", "ronin", 24*time.Hour),
+		}},
+	}}
+	report.RecountTotals()
+	issues := []scanner.ComplianceIssue{
+		{Path: filepath.Join(dir, "heat.mkv"), SuggestedAction: "rename"},
+		{Path: filepath.Join(dir, "alien 720p.mkv"), SuggestedAction: "reorganize", Confidence: 0.6},
+		{Path: filepath.Join(dir, "ronin copy.mkv"), SuggestedAction: "rename"},
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.AutoClean = config.AutoCleanConfig{IdenticalOnly: true, MinConfidence: 0.9, MinAgeDays: 7}
+	d := &Daemon{config: cfg}
+
+	got, kept := d.applyPolicy(report, issues, true)
+	if len(got.MovieDuplicates) != 1 || got.MovieDuplicates[0].NormalizedName != "heat" || got.TotalFilesToDelete != 1 {
+		t.Errorf("Expected only the identical, settled Heat group, got %+v", got.MovieDuplicates)
+	}
+	if len(kept) != 1 || kept[0].Path != issues[0].Path {
+		t.Errorf("Expected only the confident, settled issue, got %+v", kept)
+	}
+
+	// A simulation has no files to check, so only the confidence rule applies
+	got, kept = d.applyPolicy(report, issues, false)
+	if len(got.MovieDuplicates) != 3 || len(kept) != 2 {
+		t.Errorf("Simulation kept %d groups and %d issues, want 3 and 2", len(got.MovieDuplicates), len(kept))
+	}
+
+	// The per-run cap holds groups that would go past it, in report order
+	cfg.AutoClean = config.AutoCleanConfig{MaxDeleteGB: 16.0 / (1 << 30)}
+	got, _ = d.applyPolicy(report, issues, true)
+	if len(got.MovieDuplicates) != 2 || got.SpaceToFree != 16 {
+		t.Errorf("Expected Heat and Ronin under a 16-byte cap, got %d groups freeing %d", len(got.MovieDuplicates), got.SpaceToFree)
+	}
+}
+
+func TestPolicyRules(t *testing.T) {
+	if got := PolicyRules(config.AutoCleanConfig{}); len(got) != 1 || got[0] != "off" {
+		t.Errorf("PolicyRules(zero) = %v, want off", got)
+	}
+	got := strings.Join(PolicyRules(config.AutoCleanConfig{IdenticalOnly: true, MinConfidence: 0.9, MinAgeDays: 7, MaxDeleteGB: 50}), "; ")
+	want := "identical duplicates only; compliance fixes at least 0.90 confident; files unchanged for 7 days; at most 50.00 GB deleted per run"
+	if got != want {
+		t.Errorf("PolicyRules = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,45 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// SetResume makes scans finish the cancelled scan that wrote the partial
+// report at path instead of starting over (scan --resume)
+func (d *Daemon) SetResume(path string) {
+	d.resumeFrom = path
+}
+
+// scanResumed scans the sections the partial report is missing and
+// reuses the ones it completed
+func (d *Daemon) scanResumed(ctx context.Context, progressCh chan<- scanner.ScanProgress) (*scanner.ScanResult, error) {
+	report, err := readReport(d.resumeFrom)
+	if err != nil {
+		return nil, err
+	}
+	if report.Partial == nil {
+		return nil, fmt.Errorf("%s is not a partial report; only cancelled scans can be resumed", d.resumeFrom)
+	}
+	if report.Partial.Resume != scanSettings(d.config) {
+		return nil, fmt.Errorf("settings changed since the cancelled scan; run a full scan instead")
+	}
+	return scanner.RunResumedScan(ctx,
+		d.config.MoviePaths(),
+		d.config.ShowPaths(),
+		resultOf(report), report.Partial.Completed, progressCh)
+}
+
+// savePartialReport saves the findings of a scan cancelled after some
+// sections finished, flagged as partial so it can be resumed
+func (d *Daemon) savePartialReport(cancelled *scanner.CancelledScanError, progressCh chan<- scanner.ScanProgress) (string, error) {
+	report := BuildReport(d.config, cancelled.Result)
+	report.Partial = &reporter.PartialScan{
+		Completed: cancelled.Completed,
+		Resume:    scanSettings(d.config),
+	}
+	return d.saveReportWithProgress(report, progressCh)
+}
@@ -0,0 +1,98 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/oplog"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestResumeFinishesPartialReport(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SUDO_USER", "")
+
+	library := filepath.Join(home, "movies")
+	os.MkdirAll(filepath.Join(library, "Up.2009.720p-GRP"), 0755)
+	os.WriteFile(filepath.Join(library, "Up.2009.720p-GRP", "Up.2009.720p-GRP.mkv"), []byte("up"), 0644)
+
+	cfg := config.DefaultConfig()
+	cfg.Libraries.Movies.Paths = []string{library}
+	d := &Daemon{config: cfg, stateDir: t.TempDir()}
+
+	// A scan cancelled after the movie duplicates, with a group planted to
+	// show it is reused rather than scanned again
+	planted := scanner.MovieDuplicate{NormalizedName: "heat", Year: "1995", Files: []scanner.MovieFile{
+		{Path: filepath.Join(library, "Heat (1995)", "Heat (1995).mkv"), Size: 2, Resolution: "1080p"},
+		{Path: filepath.Join(library, "Heat.1995", "Heat.1995.mkv"), Size: 1, Resolution: "720p"},
+	}}
+	partialPath, err := d.savePartialReport(&scanner.CancelledScanError{
+		Result:    &scanner.ScanResult{MovieDuplicates: []scanner.MovieDuplicate{planted}, TotalDuplicates: 1},
+		Completed: []scanner.ScanSection{scanner.SectionMovieDuplicates},
+		Err:       context.Canceled,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Reports are named by the second; keep the resumed one from replacing it
+	moved := filepath.Join(t.TempDir(), "partial.json")
+	if err := os.Rename(partialPath, moved); err != nil {
+		t.Fatal(err)
+	}
+	partialPath = moved
+	partial, err := readReport(partialPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partial.Partial == nil || partial.Partial.Resume != scanSettings(cfg) {
+		t.Fatalf("Expected the report flagged partial with a resume token, got %+v", partial.Partial)
+	}
+	if banner := partial.Partial.Banner(); !strings.HasPrefix(banner, "PARTIAL SCAN: cancelled after 1 of 8 sections") {
+		t.Errorf("Unexpected banner %q", banner)
+	}
+
+	// Resuming scans the remaining sections into a complete report
+	d.SetResume(partialPath)
+	reportPath, err := d.scan(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := readReport(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Partial != nil {
+		t.Error("Expected the resumed report to be complete")
+	}
+	if len(report.MovieDuplicates) != 1 || report.MovieDuplicates[0].NormalizedName != "heat" {
+		t.Errorf("Expected the completed section reused, got %+v", report.MovieDuplicates)
+	}
+	if len(report.ComplianceIssues) != 1 {
+		t.Errorf("Expected the movie compliance section scanned, got %+v", report.ComplianceIssues)
+	}
+
+	// The scan is logged with its report and what it found
+	logged, err := oplog.Read(oplog.DefaultPath(oplog.ScanLog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logged) != 1 || logged[0].Op != "scan" || logged[0].Result != oplog.ResultOK || logged[0].OldPath != reportPath {
+		t.Errorf("Expected the resumed scan logged with its report, got %+v", logged)
+	}
+
+	// Neither a complete report nor changed settings can be resumed
+	d.SetResume(reportPath)
+	if _, err := d.scanResumed(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "not a partial report") {
+		t.Errorf("Expected a complete report refused, got %v", err)
+	}
+	d.SetResume(partialPath)
+	cfg.Naming.AutoResolveMargin = 0.5
+	if _, err := d.scanResumed(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "settings changed") {
+		t.Errorf("Expected changed settings refused, got %v", err)
+	}
+}
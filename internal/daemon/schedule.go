@@ -0,0 +1,195 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/calendar"
+	"github.com/Nomadcxx/jellysink/internal/config"
+)
+
+// epochSunday is the first Sunday after the Unix epoch; biweekly scans run
+// on Sundays an even number of weeks after it
+var epochSunday = time.Date(1970, 1, 4, 0, 0, 0, 0, time.UTC)
+
+// Schedule is when jellysinkd --daemon and the systemd timer scan: every
+// day, every Sunday or every other Sunday at a local time, or a custom
+// calendar
+type Schedule struct {
+	Frequency string // daily, weekly, biweekly
+	Hour      int
+	Minute    int
+	Calendar  *calendar.Spec // daemon.scan_schedule; overrides the rest when set
+}
+
+// ConfiguredSchedule builds the schedule of the [daemon] section:
+// scan_schedule when it is set, otherwise scan_frequency and scan_time
+func ConfiguredSchedule(cfg config.DaemonConfig) (Schedule, error) {
+	if cfg.ScanSchedule == "" {
+		return ParseSchedule(cfg.ScanFrequency, cfg.ScanTime)
+	}
+	spec, err := calendar.Parse(cfg.ScanSchedule)
+	if err != nil {
+		return Schedule{}, err
+	}
+	return Schedule{Calendar: &spec}, nil
+}
+
+// ParseSchedule builds a schedule from daemon.scan_frequency and
+// daemon.scan_time (HH:MM, 24-hour; empty = 02:00)
+func ParseSchedule(frequency, scanTime string) (Schedule, error) {
+	switch frequency {
+	case "daily", "weekly", "biweekly":
+	default:
+		return Schedule{}, fmt.Errorf("invalid scan frequency: %s (must be daily, weekly, or biweekly)", frequency)
+	}
+	if scanTime == "" {
+		scanTime = "02:00"
+	}
+	t, err := time.Parse("15:04", scanTime)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid scan time: %s (must be HH:MM)", scanTime)
+	}
+	return Schedule{Frequency: frequency, Hour: t.Hour(), Minute: t.Minute()}, nil
+}
+
+// Next returns the first scan time strictly after after, in after's location
+func (s Schedule) Next(after time.Time) time.Time {
+	if s.Calendar != nil {
+		return s.Calendar.Next(after)
+	}
+	year, month, day := after.Date()
+	// Biweekly needs at most 14 days ahead; one more covers today's slot
+	// having passed
+	for i := 0; i <= 14; i++ {
+		candidate := time.Date(year, month, day+i, s.Hour, s.Minute, 0, 0, after.Location())
+		if candidate.After(after) && s.runsOn(candidate) {
+			return candidate
+		}
+	}
+	return time.Time{}
+}
+
+// runsOn reports whether the schedule scans on t's date
+func (s Schedule) runsOn(t time.Time) bool {
+	switch s.Frequency {
+	case "weekly":
+		return t.Weekday() == time.Sunday
+	case "biweekly":
+		if t.Weekday() != time.Sunday {
+			return false
+		}
+		year, month, day := t.Date()
+		days := int(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Sub(epochSunday).Hours() / 24)
+		return (days/7)%2 == 0
+	}
+	return true
+}
+
+// NextN returns the next n scan times after after, for previews
+func (s Schedule) NextN(after time.Time, n int) []time.Time {
+	var times []time.Time
+	for len(times) < n {
+		if after = s.Next(after); after.IsZero() {
+			break
+		}
+		times = append(times, after)
+	}
+	return times
+}
+
+// OnCalendar renders the schedule for the systemd timer's OnCalendar=
+func (s Schedule) OnCalendar() string {
+	if s.Calendar != nil {
+		return s.Calendar.OnCalendar()
+	}
+	switch s.Frequency {
+	case "weekly":
+		return fmt.Sprintf("Sun *-*-* %02d:%02d:00", s.Hour, s.Minute)
+	case "biweekly":
+		return fmt.Sprintf("Sun/2 *-*-* %02d:%02d:00", s.Hour, s.Minute)
+	}
+	return fmt.Sprintf("*-*-* %02d:%02d:00", s.Hour, s.Minute)
+}
+
+// String describes the schedule, e.g. "weekly on Sunday at 02:00"
+func (s Schedule) String() string {
+	if s.Calendar != nil {
+		return fmt.Sprintf("on calendar %s", s.Calendar.OnCalendar())
+	}
+	switch s.Frequency {
+	case "weekly":
+		return fmt.Sprintf("weekly on Sunday at %02d:%02d", s.Hour, s.Minute)
+	case "biweekly":
+		return fmt.Sprintf("every other Sunday at %02d:%02d", s.Hour, s.Minute)
+	}
+	return fmt.Sprintf("daily at %02d:%02d", s.Hour, s.Minute)
+}
+
+// LibrarySchedule is a set of libraries jellysinkd --daemon scans together:
+// the config scoped to them and their schedule
+type LibrarySchedule struct {
+	Libraries []string // empty = every library, on the global settings
+	Config    *config.Config
+	Schedule  Schedule
+}
+
+// LibrarySchedules splits the configured libraries into the sets scanned
+// on their own schedules and settings. Without library overrides that is a
+// single set of everything on the [daemon] schedule
+func LibrarySchedules(cfg *config.Config) ([]LibrarySchedule, error) {
+	if !cfg.HasLibraryOverrides() {
+		schedule, err := ConfiguredSchedule(cfg.Daemon)
+		if err != nil {
+			return nil, err
+		}
+		return []LibrarySchedule{{Config: cfg, Schedule: schedule}}, nil
+	}
+
+	var schedules []LibrarySchedule
+	for _, group := range cfg.LibraryGroups() {
+		scoped, err := cfg.ForLibraries(group...)
+		if err != nil {
+			return nil, err
+		}
+		schedule, err := ConfiguredSchedule(scoped.Daemon)
+		if err != nil {
+			return nil, fmt.Errorf("library %s: %w", strings.Join(group, ", "), err)
+		}
+		schedules = append(schedules, LibrarySchedule{Libraries: group, Config: scoped, Schedule: schedule})
+	}
+	return schedules, nil
+}
+
+// NextDue returns the earliest scan time after after and the library sets
+// due then
+func NextDue(schedules []LibrarySchedule, after time.Time) (time.Time, []LibrarySchedule) {
+	var next time.Time
+	var due []LibrarySchedule
+	for _, s := range schedules {
+		t := s.Schedule.Next(after)
+		switch {
+		case t.IsZero():
+		case next.IsZero() || t.Before(next):
+			next, due = t, []LibrarySchedule{s}
+		case t.Equal(next):
+			due = append(due, s)
+		}
+	}
+	return next, due
+}
+
+// DescribeSchedules describes the schedules, naming the libraries when they
+// are scanned separately, e.g. "tv: daily at 02:00; movies: weekly on Sunday at 03:00"
+func DescribeSchedules(schedules []LibrarySchedule) string {
+	var parts []string
+	for _, s := range schedules {
+		if len(s.Libraries) == 0 {
+			parts = append(parts, s.Schedule.String())
+		} else {
+			parts = append(parts, fmt.Sprintf("%s: %s", strings.Join(s.Libraries, ", "), s.Schedule))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
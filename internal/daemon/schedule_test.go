@@ -0,0 +1,154 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+)
+
+func TestParseSchedule(t *testing.T) {
+	s, err := ParseSchedule("weekly", "")
+	if err != nil || s.Hour != 2 || s.Minute != 0 {
+		t.Errorf("ParseSchedule() = %+v, %v; want 02:00 by default", s, err)
+	}
+	if _, err := ParseSchedule("hourly", "02:00"); err == nil {
+		t.Error("Expected an error for an unknown frequency")
+	}
+	if _, err := ParseSchedule("daily", "25:00"); err == nil {
+		t.Error("Expected an error for an invalid time")
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	loc := time.UTC
+	// Wednesday 2026-10-14
+	wed := func(hour, minute int) time.Time { return time.Date(2026, 10, 14, hour, minute, 0, 0, loc) }
+
+	tests := []struct {
+		frequency string
+		scanTime  string
+		after     time.Time
+		expected  time.Time
+	}{
+		{"daily", "03:30", wed(1, 0), wed(3, 30)},
+		{"daily", "03:30", wed(3, 30), time.Date(2026, 10, 15, 3, 30, 0, 0, loc)},
+		{"weekly", "02:00", wed(12, 0), time.Date(2026, 10, 18, 2, 0, 0, 0, loc)},
+		{"weekly", "02:00", time.Date(2026, 10, 18, 1, 59, 0, 0, loc), time.Date(2026, 10, 18, 2, 0, 0, 0, loc)},
+		{"weekly", "02:00", time.Date(2026, 10, 18, 2, 0, 0, 0, loc), time.Date(2026, 10, 25, 2, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.frequency, tt.scanTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := s.Next(tt.after); !got.Equal(tt.expected) {
+			t.Errorf("%s at %s after %s: Next() = %s, want %s", tt.frequency, tt.scanTime, tt.after, got, tt.expected)
+		}
+	}
+}
+
+func TestScheduleBiweekly(t *testing.T) {
+	s, _ := ParseSchedule("biweekly", "02:00")
+	first := s.Next(time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC))
+	if first.Weekday() != time.Sunday {
+		t.Fatalf("Next() = %s, want a Sunday", first)
+	}
+	second := s.Next(first)
+	if gap := second.Sub(first); gap != 14*24*time.Hour {
+		t.Errorf("Expected biweekly scans 14 days apart, got %s", gap)
+	}
+	// The fortnight keeps its rhythm across the year end
+	third := s.Next(time.Date(2026, 12, 30, 0, 0, 0, 0, time.UTC))
+	if prev := third.AddDate(0, 0, -14); !s.runsOn(prev) || s.runsOn(third.AddDate(0, 0, -7)) {
+		t.Errorf("Expected scans on %s and %s only", prev, third)
+	}
+}
+
+func TestConfiguredSchedule(t *testing.T) {
+	cfg := config.DefaultConfig().Daemon
+	s, err := ConfiguredSchedule(cfg)
+	if err != nil || s.OnCalendar() != "Sun *-*-* 02:00:00" {
+		t.Errorf("ConfiguredSchedule() = %q, %v; want the weekly preset", s.OnCalendar(), err)
+	}
+
+	cfg.ScanSchedule = "Mon,Thu 03:30"
+	s, err = ConfiguredSchedule(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.OnCalendar(); got != "Mon,Thu *-*-* 03:30:00" {
+		t.Errorf("OnCalendar() = %q, want the custom schedule", got)
+	}
+	// Wednesday 2026-10-14 -> Thursday, then the following Monday
+	next := s.NextN(time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC), 2)
+	if len(next) != 2 || next[0].Day() != 15 || next[1].Day() != 19 {
+		t.Errorf("NextN() = %v, want Oct 15 and Oct 19 at 03:30", next)
+	}
+
+	cfg.ScanSchedule = "sometimes"
+	if _, err := ConfiguredSchedule(cfg); err == nil {
+		t.Error("Expected an error for an invalid scan_schedule")
+	}
+}
+
+func TestSyncSystemdTimer(t *testing.T) {
+	s, _ := ParseSchedule("daily", "04:15")
+	path := filepath.Join(t.TempDir(), "jellysink.timer")
+
+	// Not installed: nothing to keep in step
+	if rewritten, err := SyncSystemdTimer(path, s); err != nil || rewritten {
+		t.Errorf("SyncSystemdTimer() = %v, %v; want no rewrite without a timer", rewritten, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected no timer to be created")
+	}
+
+	// Already on the schedule: left alone
+	if err := os.WriteFile(path, []byte(GenerateSystemdTimer(s)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if rewritten, err := SyncSystemdTimer(path, s); err != nil || rewritten {
+		t.Errorf("SyncSystemdTimer() = %v, %v; want no rewrite for an unchanged schedule", rewritten, err)
+	}
+	if !strings.Contains(GenerateSystemdTimer(s), "OnCalendar=*-*-* 04:15:00\n") {
+		t.Errorf("GenerateSystemdTimer() = %q, want the daily 04:15 calendar", GenerateSystemdTimer(s))
+	}
+}
+
+func TestLibrarySchedules(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Libraries.Movies.Paths = []string{"/media/movies"}
+	cfg.Libraries.TV.Paths = []string{"/media/tv"}
+
+	schedules, err := LibrarySchedules(cfg)
+	if err != nil || len(schedules) != 1 || schedules[0].Config != cfg {
+		t.Fatalf("LibrarySchedules() = %+v, %v; want one set on the global config", schedules, err)
+	}
+
+	cfg.Libraries.TV.Overrides.ScanFrequency = "daily"
+	schedules, err = LibrarySchedules(cfg)
+	if err != nil || len(schedules) != 2 {
+		t.Fatalf("LibrarySchedules() = %+v, %v; want movies and tv apart", schedules, err)
+	}
+
+	// From Saturday 2026-10-17 noon
+	next, due := NextDue(schedules, time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local))
+	if next.Day() != 18 {
+		t.Errorf("NextDue() = %s, want Oct 18 at 02:00", next)
+	}
+	// Both are due at 02:00 on Sunday
+	if len(due) != 2 {
+		t.Errorf("NextDue() = %+v, want movies and tv together on Sunday", due)
+	}
+	next, due = NextDue(schedules, next)
+	if next.Day() != 19 || len(due) != 1 || due[0].Libraries[0] != "tv" {
+		t.Errorf("NextDue() after Sunday = %s, %+v; want tv alone on Monday", next, due)
+	}
+	if got := DescribeSchedules(schedules); got != "movies: weekly on Sunday at 02:00; tv: daily at 02:00" {
+		t.Errorf("DescribeSchedules() = %q", got)
+	}
+}
@@ -0,0 +1,29 @@
+package daemon
+
+import (
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// NewDuplicateScopes returns the duplicate scopes cfg sets; a library's own
+// duplicate_scope overrides [duplicates] scope
+func NewDuplicateScopes(cfg *config.Config) scanner.DuplicateScopes {
+	scopes := scanner.DuplicateScopes{Movies: scanner.ScopeCrossLibrary, TV: scanner.ScopeCrossLibrary}
+	if scope, err := scanner.ParseDuplicateScope(cfg.MovieDuplicateScope()); err == nil {
+		scopes.Movies = scope
+	}
+	if scope, err := scanner.ParseDuplicateScope(cfg.TVDuplicateScope()); err == nil {
+		scopes.TV = scope
+	}
+	return scopes
+}
+
+// NewLanguageRules returns the [languages] rules scans check files against
+func NewLanguageRules(cfg *config.Config) scanner.LanguageRules {
+	return scanner.LanguageRules{
+		RequiredAudio:     cfg.Languages.RequiredAudio,
+		RequiredSubtitles: cfg.Languages.RequiredSubtitles,
+		Wanted:            cfg.Languages.Wanted,
+		MaxUnwantedTracks: cfg.Languages.MaxUnwantedTracks,
+	}
+}
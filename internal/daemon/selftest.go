@@ -0,0 +1,235 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// Self-test check statuses
+const (
+	SelfTestOK   = "ok"
+	SelfTestWarn = "warn"
+	SelfTestFail = "fail"
+	SelfTestSkip = "skip"
+)
+
+// SelfTestResult is the outcome of a single self-test check
+type SelfTestResult struct {
+	Name   string
+	Status string
+	Detail string
+}
+
+// selfTestFixture is a tiny library tree scanned by the self-test
+// It contains exactly one movie duplicate group and one TV duplicate group
+var selfTestFixture = []string{
+	"movies/The Matrix (1999)/The Matrix (1999).mkv",
+	"movies/The.Matrix.1999.720p.BluRay.x264-GROUP/The.Matrix.1999.720p.BluRay.x264-GROUP.mkv",
+	"tv/Lost/Season 01/Lost S01E01.mkv",
+	"tv/Lost/Season 01/Lost.S01E01.720p.HDTV.x264-GROUP.mkv",
+}
+
+// selfTestExpectedDuplicates is the number of duplicate groups in selfTestFixture
+const selfTestExpectedDuplicates = 2
+
+// Overridable for tests so the self-test never touches the network
+var (
+	tvdbLogin = func(apiKey string) error {
+		return scanner.NewTVDBClient(apiKey).Login()
+	}
+	omdbVerify = func(apiKey string) error {
+		return scanner.NewOMDBClient(apiKey).VerifyKey()
+	}
+	tmdbVerify = func(apiKey string) error {
+		return scanner.NewTMDBClient(apiKey).VerifyKey()
+	}
+)
+
+// RunSelfTest validates an installation: config, library access as the
+// current (service) user, API credentials, data dir writes and a scan of an
+// embedded fixture tree. Intended to be run after install or upgrade
+func RunSelfTest(ctx context.Context, cfg *config.Config) []SelfTestResult {
+	var results []SelfTestResult
+
+	results = append(results, checkSelfTestConfig(cfg))
+	results = append(results, checkSelfTestLibraries(cfg)...)
+	results = append(results, checkSelfTestAPI(cfg)...)
+	results = append(results, checkSelfTestDataDir())
+	results = append(results, checkSelfTestScan(ctx))
+
+	return results
+}
+
+// SelfTestFailed reports whether any check failed
+func SelfTestFailed(results []SelfTestResult) bool {
+	for _, r := range results {
+		if r.Status == SelfTestFail {
+			return true
+		}
+	}
+	return false
+}
+
+// PrintSelfTestResults writes one line per check
+func PrintSelfTestResults(w io.Writer, results []SelfTestResult) {
+	labels := map[string]string{
+		SelfTestOK:   "[ OK ]",
+		SelfTestWarn: "[WARN]",
+		SelfTestFail: "[FAIL]",
+		SelfTestSkip: "[SKIP]",
+	}
+	for _, r := range results {
+		fmt.Fprintf(w, "%s %s: %s\n", labels[r.Status], r.Name, r.Detail)
+	}
+}
+
+func checkSelfTestConfig(cfg *config.Config) SelfTestResult {
+	if err := cfg.Validate(); err != nil {
+		return SelfTestResult{"config", SelfTestFail, err.Error()}
+	}
+	return SelfTestResult{"config", SelfTestOK, "configuration is valid"}
+}
+
+func checkSelfTestLibraries(cfg *config.Config) []SelfTestResult {
+	username := "unknown"
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	}
+
+	var results []SelfTestResult
+	for _, path := range cfg.GetAllPaths() {
+		name := "library " + path
+		info, err := os.Stat(path)
+		if err != nil {
+			results = append(results, SelfTestResult{name, SelfTestFail, fmt.Sprintf("not accessible as %s: %v", username, err)})
+			continue
+		}
+		if !info.IsDir() {
+			results = append(results, SelfTestResult{name, SelfTestFail, "not a directory"})
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			results = append(results, SelfTestResult{name, SelfTestFail, fmt.Sprintf("not readable as %s: %v", username, err)})
+			continue
+		}
+		if len(entries) == 0 {
+			results = append(results, SelfTestResult{name, SelfTestWarn, fmt.Sprintf("readable as %s but empty", username)})
+			continue
+		}
+		results = append(results, SelfTestResult{name, SelfTestOK, fmt.Sprintf("readable as %s (%d entries)", username, len(entries))})
+	}
+	return results
+}
+
+func checkSelfTestAPI(cfg *config.Config) []SelfTestResult {
+	var results []SelfTestResult
+
+	// Logins go through the configured proxy / CA bundle, like scans do
+	if cfg.API.ProxyURL != "" || cfg.API.CABundle != "" {
+		if err := scanner.SetAPIHTTPOptions(cfg.API.ProxyURL, cfg.API.CABundle); err != nil {
+			results = append(results, SelfTestResult{"api http", SelfTestFail, err.Error()})
+		} else {
+			results = append(results, SelfTestResult{"api http", SelfTestOK, "proxy/CA bundle loaded"})
+		}
+	}
+
+	if cfg.API.TVDB.Enabled {
+		if err := tvdbLogin(cfg.API.TVDB.APIKey); err != nil {
+			results = append(results, SelfTestResult{"tvdb login", SelfTestFail, err.Error()})
+		} else {
+			results = append(results, SelfTestResult{"tvdb login", SelfTestOK, "authenticated"})
+		}
+	} else {
+		results = append(results, SelfTestResult{"tvdb login", SelfTestSkip, "TVDB disabled"})
+	}
+
+	if cfg.API.OMDB.Enabled {
+		if err := omdbVerify(cfg.API.OMDB.APIKey); err != nil {
+			results = append(results, SelfTestResult{"omdb key", SelfTestFail, err.Error()})
+		} else {
+			results = append(results, SelfTestResult{"omdb key", SelfTestOK, "accepted"})
+		}
+	} else {
+		results = append(results, SelfTestResult{"omdb key", SelfTestSkip, "OMDB disabled"})
+	}
+
+	if cfg.API.TMDB.Enabled {
+		if err := tmdbVerify(cfg.API.TMDB.APIKey); err != nil {
+			results = append(results, SelfTestResult{"tmdb key", SelfTestFail, err.Error()})
+		} else {
+			results = append(results, SelfTestResult{"tmdb key", SelfTestOK, "accepted"})
+		}
+	} else {
+		results = append(results, SelfTestResult{"tmdb key", SelfTestSkip, "TMDB disabled"})
+	}
+
+	return results
+}
+
+func checkSelfTestDataDir() SelfTestResult {
+	dir := GetReportDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return SelfTestResult{"data dir", SelfTestFail, fmt.Sprintf("cannot create %s: %v", dir, err)}
+	}
+
+	f, err := os.CreateTemp(dir, ".selftest-*")
+	if err != nil {
+		return SelfTestResult{"data dir", SelfTestFail, fmt.Sprintf("cannot write to %s: %v", dir, err)}
+	}
+	name := f.Name()
+	_, writeErr := f.WriteString("jellysink self-test\n")
+	closeErr := f.Close()
+	removeErr := os.Remove(name)
+
+	if writeErr != nil {
+		return SelfTestResult{"data dir", SelfTestFail, fmt.Sprintf("write failed: %v", writeErr)}
+	}
+	if closeErr != nil {
+		return SelfTestResult{"data dir", SelfTestFail, fmt.Sprintf("close failed: %v", closeErr)}
+	}
+	if removeErr != nil {
+		return SelfTestResult{"data dir", SelfTestFail, fmt.Sprintf("delete failed: %v", removeErr)}
+	}
+
+	return SelfTestResult{"data dir", SelfTestOK, fmt.Sprintf("%s is writable", dir)}
+}
+
+func checkSelfTestScan(ctx context.Context) SelfTestResult {
+	root, err := os.MkdirTemp("", "jellysink-selftest-*")
+	if err != nil {
+		return SelfTestResult{"fixture scan", SelfTestFail, fmt.Sprintf("failed to create fixture dir: %v", err)}
+	}
+	defer os.RemoveAll(root)
+
+	for _, rel := range selfTestFixture {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return SelfTestResult{"fixture scan", SelfTestFail, fmt.Sprintf("failed to create fixture: %v", err)}
+		}
+		if err := os.WriteFile(path, []byte("jellysink"), 0644); err != nil {
+			return SelfTestResult{"fixture scan", SelfTestFail, fmt.Sprintf("failed to create fixture: %v", err)}
+		}
+	}
+
+	result, err := scanner.RunFullScan(ctx,
+		[]string{filepath.Join(root, "movies")},
+		[]string{filepath.Join(root, "tv")},
+		nil,
+	)
+	if err != nil {
+		return SelfTestResult{"fixture scan", SelfTestFail, err.Error()}
+	}
+	if result.TotalDuplicates != selfTestExpectedDuplicates {
+		return SelfTestResult{"fixture scan", SelfTestFail, fmt.Sprintf("expected %d duplicate groups, found %d", selfTestExpectedDuplicates, result.TotalDuplicates)}
+	}
+
+	return SelfTestResult{"fixture scan", SelfTestOK, fmt.Sprintf("found %d duplicate groups and %d compliance issues", result.TotalDuplicates, len(result.ComplianceIssues))}
+}
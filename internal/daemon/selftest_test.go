@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+)
+
+func TestRunSelfTest(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SUDO_USER", "")
+
+	library := filepath.Join(home, "movies")
+	if err := os.MkdirAll(filepath.Join(library, "Film (2020)"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Libraries.Movies.Paths = []string{library}
+
+	results := RunSelfTest(context.Background(), cfg)
+	for _, r := range results {
+		if r.Status == SelfTestFail {
+			t.Errorf("Unexpected failure in %s: %s", r.Name, r.Detail)
+		}
+	}
+
+	// Data dir probe file must be cleaned up
+	entries, err := os.ReadDir(GetReportDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected data dir to be empty after self-test, found %d entries", len(entries))
+	}
+}
+
+func TestRunSelfTestReportsFailures(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SUDO_USER", "")
+
+	origLogin := tvdbLogin
+	defer func() { tvdbLogin = origLogin }()
+	tvdbLogin = func(string) error { return errors.New("invalid key") }
+
+	cfg := config.DefaultConfig()
+	cfg.Libraries.Movies.Paths = []string{filepath.Join(t.TempDir(), "missing")}
+	cfg.API.TVDB.Enabled = true
+	cfg.API.TVDB.APIKey = "bad"
+
+	results := RunSelfTest(context.Background(), cfg)
+	if !SelfTestFailed(results) {
+		t.Fatal("Expected self-test to fail")
+	}
+
+	failed := make(map[string]bool)
+	for _, r := range results {
+		if r.Status == SelfTestFail {
+			failed[r.Name] = true
+		}
+	}
+	if !failed["tvdb login"] {
+		t.Error("Expected tvdb login failure")
+	}
+	if !failed["library "+cfg.Libraries.Movies.Paths[0]] {
+		t.Error("Expected missing library failure")
+	}
+}
@@ -0,0 +1,154 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// jellysinkd --daemon holds an flock on jellysinkd.pid (which holds its
+// PID) for as long as it runs, and keeps jellysinkd.status up to date so
+// other tools can see what it is doing without talking to it
+
+const (
+	pidFileName    = "jellysinkd.pid"
+	statusFileName = "jellysinkd.status"
+)
+
+// Service states reported in the status file
+const (
+	ServiceIdle     = "idle"
+	ServiceDeferred = "deferred" // a due scan is held while Jellyfin is streaming
+	ServiceScanning = "scanning"
+	ServiceStopped  = "stopped"
+)
+
+// ServiceStatus is what a long-running jellysinkd reports about itself
+type ServiceStatus struct {
+	PID        int
+	State      string // idle, deferred, scanning or stopped
+	Schedule   string
+	StartedAt  time.Time
+	ReloadedAt time.Time // last SIGHUP config reload
+	NextScan   time.Time // zero while deferred, scanning or stopped
+	LastScan   time.Time
+	LastReport string `json:",omitempty"`
+	LastError  string `json:",omitempty"` // error of the last scan or reload, cleared by the next success
+}
+
+// PIDFile is the held jellysinkd.pid
+type PIDFile struct {
+	file *os.File
+	path string
+}
+
+// AcquirePIDFile records this process as the running jellysinkd, failing
+// when another one already is
+func AcquirePIDFile() (*PIDFile, error) {
+	return acquirePIDFile(defaultStateDir())
+}
+
+func acquirePIDFile(dir string) (*PIDFile, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, pidFileName)
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PID file: %w", err)
+	}
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		data, _ := os.ReadFile(path)
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, fmt.Errorf("jellysinkd is already running (pid %s)", strings.TrimSpace(string(data)))
+		}
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+
+	if err := file.Truncate(0); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write PID file: %w", err)
+	}
+	if _, err := file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write PID file: %w", err)
+	}
+	return &PIDFile{file: file, path: path}, nil
+}
+
+// Release removes the PID file on shutdown
+func (p *PIDFile) Release() {
+	os.Remove(p.path)
+	syscall.Flock(int(p.file.Fd()), syscall.LOCK_UN)
+	p.file.Close()
+}
+
+// WriteServiceStatus replaces the status file
+func WriteServiceStatus(status ServiceStatus) error {
+	return writeServiceStatus(defaultStateDir(), status)
+}
+
+func writeServiceStatus(dir string, status ServiceStatus) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode status: %w", err)
+	}
+
+	// Readers never see a half-written file
+	path := filepath.Join(dir, statusFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write status: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write status: %w", err)
+	}
+	return nil
+}
+
+// ReadServiceStatus returns the last status jellysinkd --daemon wrote. A
+// status left by a process that is gone reads as stopped
+func ReadServiceStatus() (*ServiceStatus, error) {
+	return readServiceStatus(defaultStateDir())
+}
+
+func readServiceStatus(dir string) (*ServiceStatus, error) {
+	data, err := os.ReadFile(filepath.Join(dir, statusFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read status: %w", err)
+	}
+	var status ServiceStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse status: %w", err)
+	}
+
+	if !servicePIDHeld(dir) {
+		status.State = ServiceStopped
+		status.NextScan = time.Time{}
+	}
+	return &status, nil
+}
+
+// servicePIDHeld reports whether a jellysinkd still holds the PID file
+func servicePIDHeld(dir string) bool {
+	file, err := os.Open(filepath.Join(dir, pidFileName))
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_SH|syscall.LOCK_NB); err != nil {
+		return errors.Is(err, syscall.EWOULDBLOCK)
+	}
+	syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+	return false
+}
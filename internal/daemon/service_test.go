@@ -0,0 +1,51 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPIDFileAndStatus(t *testing.T) {
+	dir := t.TempDir()
+
+	pid, err := acquirePIDFile(dir)
+	if err != nil {
+		t.Fatalf("acquirePIDFile() error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, pidFileName))
+	if strings.TrimSpace(string(data)) != strconv.Itoa(os.Getpid()) {
+		t.Errorf("PID file holds %q, want our PID", data)
+	}
+	if _, err := acquirePIDFile(dir); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("Expected a second daemon refused, got %v", err)
+	}
+
+	next := time.Date(2026, 10, 18, 2, 0, 0, 0, time.UTC)
+	if err := writeServiceStatus(dir, ServiceStatus{PID: os.Getpid(), State: ServiceIdle, NextScan: next}); err != nil {
+		t.Fatalf("writeServiceStatus() error: %v", err)
+	}
+	status, err := readServiceStatus(dir)
+	if err != nil || status.State != ServiceIdle || !status.NextScan.Equal(next) {
+		t.Fatalf("readServiceStatus() = %+v, %v", status, err)
+	}
+
+	// Once the daemon is gone its last status reads as stopped
+	pid.Release()
+	status, err = readServiceStatus(dir)
+	if err != nil || status.State != ServiceStopped || !status.NextScan.IsZero() {
+		t.Errorf("readServiceStatus() after release = %+v, %v", status, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, pidFileName)); !os.IsNotExist(err) {
+		t.Error("Expected the PID file removed on release")
+	}
+
+	if pid, err := acquirePIDFile(dir); err != nil {
+		t.Errorf("Expected a new daemon to start after release, got %v", err)
+	} else {
+		pid.Release()
+	}
+}
@@ -0,0 +1,60 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// RunSimulation scans a synthetic in-memory library instead of the
+// configured ones and saves the report like a real scan's. It neither takes
+// the scan lock nor queries Jellyfin or the metadata APIs, so it is safe to
+// run next to a real scan
+func (d *Daemon) RunSimulation(ctx context.Context, lib scanner.SimulatedLibrary, progressCh chan<- scanner.ScanProgress) (string, error) {
+	scanResult, err := scanner.SimulateScan(ctx, lib, progressCh)
+	if err != nil {
+		return "", fmt.Errorf("simulated scan failed: %w", err)
+	}
+
+	simCfg := *d.config
+	simCfg.Libraries.Movies.Paths = []string{lib.MoviesPath()}
+	simCfg.Libraries.TV.Paths = []string{lib.TVPath()}
+	simCfg.Libraries.Anime.Paths = nil
+	report := BuildReport(&simCfg, scanResult)
+	report.Simulated = true
+
+	reportPath, err := d.saveReportWithProgress(report, progressCh)
+	if err != nil {
+		return "", fmt.Errorf("failed to save report: %w", err)
+	}
+	return reportPath, nil
+}
+
+// SimulateAutoClean prints what AutoClean would do with report, following
+// the same auto_clean_severities filter and [autoclean] rules that need no
+// files, without touching any file
+func (d *Daemon) SimulateAutoClean(report reporter.Report) {
+	fmt.Println("Simulating auto-clean (headless mode, no changes made)...")
+
+	issues := scanner.FilterIssuesBySeverities(report.ComplianceIssues, d.config.Daemon.AutoCleanSeverities)
+	if skipped := len(report.ComplianceIssues) - len(issues); skipped > 0 {
+		fmt.Printf("Would skip %d compliance issue(s) outside auto_clean_severities %v\n", skipped, d.config.Daemon.AutoCleanSeverities)
+	}
+	report, issues = d.applyPolicy(report, issues, false)
+
+	fmt.Printf("Auto-clean would:\n")
+	fmt.Printf("  Delete duplicates: %d\n", report.TotalFilesToDelete)
+	if confirm := d.config.Daemon.AutoCleanConfirmScans; confirm > 1 {
+		fmt.Printf("    (only groups found unchanged in %d scans in a row)\n", confirm)
+	}
+	if policy := d.config.AutoClean; policy.IdenticalOnly || policy.MinAgeDays > 0 {
+		fmt.Printf("    (only groups whose files pass autoclean identical_only and min_age_days)\n")
+	}
+	fmt.Printf("  Fix compliance issues: %d\n", len(issues))
+	fmt.Printf("  Free space: %.2f GB\n", float64(report.SpaceToFree)/(1024*1024*1024))
+	if jellyfinConfigured(d.config) && d.config.Jellyfin.RefreshAfterClean {
+		fmt.Printf("  Then request a Jellyfin library scan\n")
+	}
+}
@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestRunSimulation(t *testing.T) {
+	reportDir := t.TempDir()
+	reporter.SetOutput(config.ReportsConfig{Dir: reportDir})
+	defer reporter.SetOutput(config.ReportsConfig{})
+
+	cfg := config.DefaultConfig()
+	cfg.Libraries.Movies.Paths = []string{"/mnt/movies"}
+	d := &Daemon{config: cfg}
+
+	lib := scanner.SimulatedLibrary{Movies: 10, Shows: 2, EpisodesPerShow: 4, FileSizeMB: 100}
+	reportPath, err := d.RunSimulation(context.Background(), lib, nil)
+	if err != nil {
+		t.Fatalf("RunSimulation failed: %v", err)
+	}
+	if filepath.Dir(reportPath) != reportDir {
+		t.Errorf("Expected the report in %s, got %s", reportDir, reportPath)
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report reporter.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if !report.Simulated || report.LibraryType != "mixed" {
+		t.Errorf("Expected a simulated mixed report, got simulated=%v type=%s", report.Simulated, report.LibraryType)
+	}
+	for _, path := range report.LibraryPaths {
+		if !strings.HasPrefix(path, scanner.SimulatedRoot) {
+			t.Errorf("Expected only simulated library paths, got %v", report.LibraryPaths)
+		}
+	}
+	if report.TotalDuplicates != 4 {
+		t.Errorf("Expected 2 movie and 2 episode duplicates, got %d", report.TotalDuplicates)
+	}
+	if cfg.Libraries.Movies.Paths[0] != "/mnt/movies" {
+		t.Error("Expected the configured libraries left alone")
+	}
+}
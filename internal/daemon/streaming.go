@@ -0,0 +1,113 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/jellyfin"
+)
+
+// streamingPollInterval is how often a held scan or clean asks Jellyfin
+// whether anyone is still streaming
+const streamingPollInterval = time.Minute
+
+// StreamingGate holds scheduled scans and auto-cleans while anyone is
+// streaming from Jellyfin (daemon.defer_while_streaming). Work is only held
+// once a stream was seen; it then waits for the server to stay idle for
+// daemon.streaming_idle_minutes
+type StreamingGate struct {
+	Idle    time.Duration                          // how long the server must stay idle
+	Streams func(ctx context.Context) (int, error) // active stream count
+
+	held      bool      // a stream was seen since work was last let through
+	idleSince time.Time // when the server last went idle while held
+}
+
+// GateCheck is the outcome of one StreamingGate.Check
+type GateCheck struct {
+	Ready   bool      // the held work may start
+	Streams int       // streams playing at the check
+	Recheck time.Time // when to check again if not ready
+	Err     error     // Jellyfin could not be asked; work is let through
+}
+
+// NewStreamingGate returns the gate for cfg, or nil when
+// defer_while_streaming is off or Jellyfin is not configured
+func NewStreamingGate(cfg *config.Config) *StreamingGate {
+	if !jellyfinConfigured(cfg) || !cfg.Daemon.DeferWhileStreaming {
+		return nil
+	}
+	client := jellyfin.NewClient(cfg.Jellyfin.URL, cfg.Jellyfin.APIKey)
+	return &StreamingGate{
+		Idle: time.Duration(cfg.Daemon.StreamingIdleMinutes) * time.Minute,
+		Streams: func(ctx context.Context) (int, error) {
+			ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			defer cancel()
+			return client.ActiveStreams(ctx)
+		},
+	}
+}
+
+// Check asks Jellyfin whether held work may start at now. An unreachable
+// server never holds work back: a scan that never runs is worse than one
+// that runs during a stream
+func (g *StreamingGate) Check(ctx context.Context, now time.Time) GateCheck {
+	streams, err := g.Streams(ctx)
+	if err != nil {
+		g.held, g.idleSince = false, time.Time{}
+		return GateCheck{Ready: true, Err: err}
+	}
+
+	switch {
+	case streams > 0:
+		g.held, g.idleSince = true, time.Time{}
+	case !g.held:
+		return GateCheck{Ready: true}
+	case g.idleSince.IsZero():
+		g.idleSince = now
+	}
+	if streams == 0 && now.Sub(g.idleSince) >= g.Idle {
+		g.held, g.idleSince = false, time.Time{}
+		return GateCheck{Ready: true}
+	}
+
+	recheck := now.Add(streamingPollInterval)
+	if streams == 0 {
+		if resume := g.idleSince.Add(g.Idle); resume.Before(recheck) {
+			recheck = resume
+		}
+	}
+	return GateCheck{Streams: streams, Recheck: recheck}
+}
+
+// Wait blocks until held work may start or ctx is cancelled. what names
+// the work in the messages passed to logf, e.g. "scan" or "auto-clean"
+func (g *StreamingGate) Wait(ctx context.Context, what string, logf func(string)) error {
+	logged := false
+	for {
+		check := g.Check(ctx, time.Now())
+		if check.Err != nil && ctx.Err() == nil {
+			logf(fmt.Sprintf("Jellyfin session check failed, not holding the %s: %v", what, check.Err))
+		}
+		if check.Ready {
+			if logged {
+				logf(fmt.Sprintf("Jellyfin is idle, starting the %s", what))
+			}
+			return ctx.Err()
+		}
+		if !logged {
+			logf(fmt.Sprintf("Holding the %s while Jellyfin is streaming (%d active)", what, check.Streams))
+			logged = true
+		}
+
+		timer := time.NewTimer(time.Until(check.Recheck))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
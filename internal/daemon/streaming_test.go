@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+)
+
+func TestStreamingGate(t *testing.T) {
+	streams := []int{0, 2, 1, 0, 0, 1, 0, 0}
+	var streamsErr error
+	gate := &StreamingGate{
+		Idle: 10 * time.Minute,
+		Streams: func(ctx context.Context) (int, error) {
+			n := streams[0]
+			streams = streams[1:]
+			return n, streamsErr
+		},
+	}
+	start := time.Date(2026, 10, 14, 2, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	// Nobody streaming: the work starts right away
+	if check := gate.Check(context.Background(), at(0)); !check.Ready {
+		t.Fatalf("Expected an idle server to let work through, got %+v", check)
+	}
+
+	steps := []struct {
+		minute  int
+		ready   bool
+		recheck int
+	}{
+		{0, false, 1},   // 2 streams: held
+		{1, false, 2},   // 1 stream
+		{2, false, 3},   // idle from minute 2
+		{11, false, 12}, // idle 9 minutes
+		{12, false, 13}, // a stream restarts the idle count
+		{13, false, 14}, // idle from minute 13
+		{23, true, 0},   // idle 10 minutes
+	}
+	for _, step := range steps {
+		check := gate.Check(context.Background(), at(step.minute))
+		if check.Ready != step.ready {
+			t.Fatalf("minute %d: Ready = %v, want %v", step.minute, check.Ready, step.ready)
+		}
+		if !step.ready && !check.Recheck.Equal(at(step.recheck)) {
+			t.Errorf("minute %d: Recheck = %v, want minute %d", step.minute, check.Recheck, step.recheck)
+		}
+	}
+
+	// A server that cannot be asked holds nothing back
+	streams, streamsErr = []int{1}, errors.New("connection refused")
+	if check := gate.Check(context.Background(), at(30)); !check.Ready || check.Err == nil {
+		t.Errorf("Expected work let through with the error, got %+v", check)
+	}
+}
+
+func TestStreamingGateIdleRecheck(t *testing.T) {
+	// The last recheck falls on the end of the idle time, not the next poll
+	streams := []int{1, 0}
+	gate := &StreamingGate{
+		Idle:    30 * time.Second,
+		Streams: func(ctx context.Context) (int, error) { n := streams[0]; streams = streams[1:]; return n, nil },
+	}
+	now := time.Date(2026, 10, 14, 2, 0, 0, 0, time.UTC)
+	gate.Check(context.Background(), now)
+	if check := gate.Check(context.Background(), now); check.Ready || !check.Recheck.Equal(now.Add(30*time.Second)) {
+		t.Errorf("Expected a recheck when the idle time ends, got %+v", check)
+	}
+}
+
+func TestNewStreamingGate(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Daemon.DeferWhileStreaming = true
+	if NewStreamingGate(cfg) != nil {
+		t.Error("Expected no gate without a Jellyfin server")
+	}
+	cfg.Jellyfin.URL, cfg.Jellyfin.APIKey = "http://localhost:8096", "secret"
+	if gate := NewStreamingGate(cfg); gate == nil || gate.Idle != 10*time.Minute {
+		t.Errorf("Expected a gate idling 10 minutes, got %+v", gate)
+	}
+	cfg.Daemon.DeferWhileStreaming = false
+	if NewStreamingGate(cfg) != nil {
+		t.Error("Expected no gate with defer_while_streaming off")
+	}
+}
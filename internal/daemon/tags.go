@@ -0,0 +1,20 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/tags"
+)
+
+// NewTagRules loads the tag store and pairs it with the [tags] keep and
+// protected lists. An unreadable tag file is reported and leaves the store
+// empty so scans and cleans still run
+func NewTagRules(cfg *config.Config) *tags.Rules {
+	store, err := tags.Load(tags.DefaultPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return &tags.Rules{Store: store, Keep: cfg.Tags.Keep, Protected: cfg.Tags.Protected}
+}
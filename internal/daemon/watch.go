@@ -0,0 +1,199 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/cleaner"
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// watchOp is what happened to a path under a watched library
+type watchOp int
+
+const (
+	watchCreated  watchOp = iota // created; a file may still be being written
+	watchWritten                 // file closed after writing
+	watchMoved                   // moved in, complete
+	watchRemoved                 // deleted or moved out
+	watchOverflow                // events were lost
+)
+
+// watchEvent is one change under a watched library
+type watchEvent struct {
+	Path string
+	Op   watchOp
+	Dir  bool
+}
+
+// libraryWatcher reports changes under the library paths
+type libraryWatcher interface {
+	Events() <-chan watchEvent
+	Close() error
+}
+
+// newLibraryWatcher starts watching the library roots (swapped out by tests)
+var newLibraryWatcher = newInotifyWatcher
+
+// WatchBatch is the outcome of processing one batch of library changes
+type WatchBatch struct {
+	Changed    []string // files and folders added, written or moved in
+	ReportPath string   // report covering the whole library after the batch
+	Fixed      int      // compliance issues auto-fixed in the changed files
+	FixErrors  []error  // compliance fixes that failed
+	Err        error    // the batch could not be scanned
+}
+
+// Watch keeps a rolling report up to date as files under the library paths
+// change, until ctx is cancelled. A batch of changes is processed by an
+// incremental scan once none arrive for daemon.watch_debounce seconds; the
+// first scan runs straight away to catch up. Each batch's report replaces
+// the previous batch's. onBatch is called after every batch
+func (d *Daemon) Watch(ctx context.Context, progressCh chan<- scanner.ScanProgress, onBatch func(WatchBatch)) error {
+	var roots []string
+	roots = append(roots, d.config.MoviePaths()...)
+	roots = append(roots, d.config.ShowPaths()...)
+	if len(roots) == 0 {
+		return errors.New("no library paths to watch")
+	}
+	w, err := newLibraryWatcher(roots)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	d.SetIncremental(true)
+	debounce := time.Duration(d.config.Daemon.WatchDebounce) * time.Second
+	changes := newWatchChanges()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	var previous string // report of the last batch this watch scanned itself
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-w.Events():
+			if !ok {
+				return errors.New("library watch stopped unexpectedly")
+			}
+			changes.add(event)
+			timer.Reset(debounce)
+		case <-timer.C:
+			changed := changes.take()
+			batch := d.runWatchBatch(ctx, progressCh, changed, changes.writing)
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			// A report from a scan another process ran is not ours to replace
+			if batch.ReportPath != "" && batch.ReportPath == d.indexedReport {
+				if previous != "" && previous != batch.ReportPath {
+					if err := reporter.RemoveReport(previous); err != nil {
+						notify(progressCh, scanner.SeverityWarn, fmt.Sprintf("Superseded report not removed: %v", err))
+					}
+				}
+				previous = batch.ReportPath
+			}
+			onBatch(batch)
+		}
+	}
+}
+
+// runWatchBatch scans the library incrementally and, with
+// daemon.watch_auto_fix, fixes the compliance issues of the changed files
+func (d *Daemon) runWatchBatch(ctx context.Context, progressCh chan<- scanner.ScanProgress, changed []string, writing map[string]bool) WatchBatch {
+	batch := WatchBatch{Changed: changed}
+	reportPath, err := d.RunScanWithProgress(ctx, progressCh)
+	if err != nil {
+		batch.Err = err
+		return batch
+	}
+	batch.ReportPath = reportPath
+	if !d.config.Daemon.WatchAutoFix || len(changed) == 0 {
+		return batch
+	}
+
+	report, err := readReport(reportPath)
+	if err != nil {
+		batch.Err = err
+		return batch
+	}
+	issues := scanner.FilterIssuesBySeverities(changedIssues(report.ComplianceIssues, changed, writing), d.config.Daemon.AutoCleanSeverities)
+	if len(issues) == 0 {
+		return batch
+	}
+
+	cleanerCfg := cleaner.DefaultConfig()
+	cleanerCfg.DryRun = false
+	result, err := cleaner.CleanWithProgress(nil, nil, issues, cleanerCfg, progressCh)
+	if err != nil {
+		batch.FixErrors = append(batch.FixErrors, err)
+		return batch
+	}
+	batch.Fixed = result.ComplianceFixed
+	batch.FixErrors = result.Errors
+	return batch
+}
+
+// changedIssues returns the issues of files that are, or are inside,
+// changed paths. Files still being written are left alone
+func changedIssues(issues []scanner.ComplianceIssue, changed []string, writing map[string]bool) []scanner.ComplianceIssue {
+	var matched []scanner.ComplianceIssue
+	for _, issue := range issues {
+		if writing[issue.Path] {
+			continue
+		}
+		for _, path := range changed {
+			if issue.Path == path || strings.HasPrefix(issue.Path, path+string(filepath.Separator)) {
+				matched = append(matched, issue)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// watchChanges collects the changes of the batch being debounced
+type watchChanges struct {
+	changed map[string]bool // paths added, written or moved in this batch
+	writing map[string]bool // files created but not closed yet, across batches
+}
+
+func newWatchChanges() *watchChanges {
+	return &watchChanges{changed: make(map[string]bool), writing: make(map[string]bool)}
+}
+
+// add records event
+func (c *watchChanges) add(event watchEvent) {
+	switch event.Op {
+	case watchCreated:
+		c.changed[event.Path] = true
+		if !event.Dir {
+			c.writing[event.Path] = true
+		}
+	case watchWritten, watchMoved:
+		c.changed[event.Path] = true
+		delete(c.writing, event.Path)
+	case watchRemoved:
+		delete(c.changed, event.Path)
+		delete(c.writing, event.Path)
+	}
+}
+
+// take returns the batch's changed paths, sorted, and starts a new batch
+func (c *watchChanges) take() []string {
+	changed := make([]string, 0, len(c.changed))
+	for path := range c.changed {
+		changed = append(changed, path)
+	}
+	sort.Strings(changed)
+	c.changed = make(map[string]bool)
+	return changed
+}
@@ -0,0 +1,165 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// fakeWatcher hands Watch the events a test sends
+type fakeWatcher struct {
+	events chan watchEvent
+}
+
+func (w *fakeWatcher) Events() <-chan watchEvent { return w.events }
+func (w *fakeWatcher) Close() error              { return nil }
+
+func TestWatchChangesSkipFilesStillBeingWritten(t *testing.T) {
+	changes := newWatchChanges()
+	changes.add(watchEvent{Path: "/movies/Up.2009-GRP", Op: watchCreated, Dir: true})
+	changes.add(watchEvent{Path: "/movies/Up.2009-GRP/Up.2009-GRP.mkv", Op: watchCreated})
+	changes.add(watchEvent{Path: "/movies/Heat.1995-GRP", Op: watchMoved, Dir: true})
+	changes.add(watchEvent{Path: "/movies/Alien.1979-GRP", Op: watchMoved, Dir: true})
+	changes.add(watchEvent{Path: "/movies/Alien.1979-GRP", Op: watchRemoved, Dir: true})
+
+	changed := changes.take()
+	if len(changed) != 3 || changed[0] != "/movies/Heat.1995-GRP" {
+		t.Fatalf("Expected the created and moved-in paths, got %v", changed)
+	}
+	if len(changes.take()) != 0 {
+		t.Error("Expected take to start a new batch")
+	}
+
+	issues := []scanner.ComplianceIssue{
+		{Path: "/movies/Up.2009-GRP/Up.2009-GRP.mkv"},
+		{Path: "/movies/Heat.1995-GRP/Heat.1995-GRP.mkv"},
+		{Path: "/movies/Heat (1995)/Heat.mkv"},
+	}
+	matched := changedIssues(issues, changed, changes.writing)
+	if len(matched) != 1 || matched[0].Path != "/movies/Heat.1995-GRP/Heat.1995-GRP.mkv" {
+		t.Errorf("Expected only the finished new file's issue, got %+v", matched)
+	}
+
+	// Once the download is closed its issue can be fixed too
+	changes.add(watchEvent{Path: "/movies/Up.2009-GRP/Up.2009-GRP.mkv", Op: watchWritten})
+	if matched := changedIssues(issues, changes.take(), changes.writing); len(matched) != 1 || matched[0].Path != issues[0].Path {
+		t.Errorf("Expected the written file's issue, got %+v", matched)
+	}
+}
+
+func TestWatchFixesNewDownloads(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SUDO_USER", "")
+
+	library := filepath.Join(home, "movies")
+	os.MkdirAll(filepath.Join(library, "Heat (1995)"), 0755)
+	os.WriteFile(filepath.Join(library, "Heat (1995)", "Heat (1995).mkv"), []byte("heat"), 0644)
+
+	watcher := &fakeWatcher{events: make(chan watchEvent, 10)}
+	original := newLibraryWatcher
+	newLibraryWatcher = func(roots []string) (libraryWatcher, error) { return watcher, nil }
+	defer func() { newLibraryWatcher = original }()
+
+	cfg := config.DefaultConfig()
+	cfg.Libraries.Movies.Paths = []string{library}
+	cfg.Daemon.WatchDebounce = 1
+	cfg.Daemon.WatchAutoFix = true
+	d := New(cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	batches := make(chan WatchBatch, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- d.Watch(ctx, nil, func(batch WatchBatch) { batches <- batch })
+	}()
+	next := func() WatchBatch {
+		t.Helper()
+		select {
+		case batch := <-batches:
+			if batch.Err != nil {
+				t.Fatalf("Unexpected batch error: %v", batch.Err)
+			}
+			return batch
+		case <-time.After(10 * time.Second):
+			t.Fatal("Expected a watch batch")
+		}
+		return WatchBatch{}
+	}
+
+	// The watch catches up first
+	first := next()
+	if len(first.Changed) != 0 || first.ReportPath == "" {
+		t.Fatalf("Expected a catch-up scan, got %+v", first)
+	}
+
+	// A download moved into the library is renamed once it settles
+	time.Sleep(time.Second) // reports are named by the second
+	release := filepath.Join(library, "Up.2009.720p.WEB-DL-GRP")
+	os.MkdirAll(release, 0755)
+	os.WriteFile(filepath.Join(release, "Up.2009.720p.WEB-DL-GRP.mkv"), []byte("up"), 0644)
+	watcher.events <- watchEvent{Path: release, Op: watchMoved, Dir: true}
+
+	second := next()
+	if len(second.Changed) != 1 || second.Fixed != 1 || len(second.FixErrors) != 0 {
+		t.Fatalf("Expected the new download fixed, got %+v", second)
+	}
+	if _, err := os.Stat(filepath.Join(library, "Up (2009)", "Up (2009).mkv")); err != nil {
+		t.Errorf("Expected the download renamed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(library, "Heat (1995)", "Heat (1995).mkv")); err != nil {
+		t.Errorf("Expected the untouched movie left alone: %v", err)
+	}
+
+	// The rolling report replaces the one before it
+	if _, err := os.Stat(first.ReportPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the superseded report removed, got %v", err)
+	}
+	if _, err := os.Stat(second.ReportPath); err != nil {
+		t.Errorf("Expected the latest report kept: %v", err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Watch() = %v, want nil after cancel", err)
+	}
+}
+
+func TestInotifyWatcherReportsNewFolders(t *testing.T) {
+	root := t.TempDir()
+	w, err := newInotifyWatcher([]string{root})
+	if err != nil {
+		t.Skipf("inotify unavailable: %v", err)
+	}
+	defer w.Close()
+
+	release := filepath.Join(root, "Up.2009-GRP")
+	os.Mkdir(release, 0755)
+	expect := func(path string, op watchOp) {
+		t.Helper()
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case event := <-w.Events():
+				if event.Path == path && event.Op == op {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("Expected event %d for %s", op, path)
+			}
+		}
+	}
+	expect(release, watchCreated)
+
+	// The new folder is watched as well
+	file := filepath.Join(release, "Up.2009-GRP.mkv")
+	os.WriteFile(file, []byte("up"), 0644)
+	expect(file, watchWritten)
+	os.Remove(file)
+	expect(file, watchRemoved)
+}
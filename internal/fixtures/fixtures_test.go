@@ -0,0 +1,73 @@
+package fixtures
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestGenerateMatchesExpectations(t *testing.T) {
+	lib, err := Generate(t.TempDir())
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(lib.MoviesPath, MovieFiles[0].Path))
+	if err != nil {
+		t.Fatalf("Expected fixture file to exist: %v", err)
+	}
+	if info.Size() != MovieFiles[0].SizeMB*1024*1024 {
+		t.Errorf("Expected apparent size %d MB, got %d bytes", MovieFiles[0].SizeMB, info.Size())
+	}
+
+	result, err := scanner.RunFullScan(context.Background(), []string{lib.MoviesPath}, []string{lib.TVPath}, nil)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if len(result.MovieDuplicates) != ExpectedMovieDuplicates {
+		t.Errorf("Expected %d movie duplicate groups, got %d", ExpectedMovieDuplicates, len(result.MovieDuplicates))
+	}
+	if len(result.TVDuplicates) != ExpectedTVDuplicates {
+		t.Errorf("Expected %d TV duplicate groups, got %d", ExpectedTVDuplicates, len(result.TVDuplicates))
+	}
+	if len(result.AmbiguousTVShows) != ExpectedAmbiguousShows {
+		t.Errorf("Expected %d ambiguous shows, got %d", ExpectedAmbiguousShows, len(result.AmbiguousTVShows))
+	}
+	if len(result.ComplianceIssues) == 0 {
+		t.Error("Expected compliance issues in fixture library")
+	}
+}
+
+func TestMockAPI(t *testing.T) {
+	api := NewMockAPI()
+	defer api.Close()
+	defer api.Install()()
+
+	tvdb := scanner.NewTVDBClient(MockAPIKey)
+	if err := tvdb.Login(); err != nil {
+		t.Fatalf("Mock TVDB login failed: %v", err)
+	}
+	series, err := tvdb.SearchSeriesWithRetry("breaking bad", 0)
+	if err != nil {
+		t.Fatalf("Mock TVDB search failed: %v", err)
+	}
+	if len(series) != 1 || series[0].Name != "Breaking Bad" {
+		t.Errorf("Expected Breaking Bad, got %+v", series)
+	}
+
+	if err := scanner.NewTVDBClient("wrong").Login(); err == nil {
+		t.Error("Expected login with wrong key to fail")
+	}
+
+	omdb := scanner.NewOMDBClient(MockAPIKey)
+	if err := omdb.VerifyKey(); err != nil {
+		t.Errorf("Mock OMDB key check failed: %v", err)
+	}
+	if err := scanner.NewOMDBClient("wrong").VerifyKey(); err == nil {
+		t.Error("Expected OMDB key check with wrong key to fail")
+	}
+}
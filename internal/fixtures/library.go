@@ -0,0 +1,95 @@
+// Package fixtures generates a synthetic media library and a mock metadata
+// API so scans, reports and cleaning can be exercised without real media
+package fixtures
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// File is a single video file in the synthetic library
+type File struct {
+	Path   string // Relative to the library root
+	SizeMB int64  // Apparent size (files are sparse)
+}
+
+// MovieFiles is the synthetic movie library: duplicates, release-group
+// folders, a loose file in the root and a cosmetic year issue
+var MovieFiles = []File{
+	{"The Matrix (1999)/The Matrix (1999) 1080p.mkv", 40},
+	{"The.Matrix.1999.720p.BluRay.x264-GROUP/The.Matrix.1999.720p.BluRay.x264-GROUP.mkv", 20},
+	{"Inception (2010)/Inception (2010) 2160p.mkv", 80},
+	{"Inception 2010/Inception.2010.720p.WEB-DL.mkv", 15},
+	{"Blade.Runner.2049.2017.1080p.BluRay.x265-RARBG/Blade.Runner.2049.2017.1080p.BluRay.x265-RARBG.mkv", 35},
+	{"Heat 1995/Heat 1995.mkv", 30},
+	{"Arrival (2016)/Arrival (2016).mkv", 30},
+	{"Alien.1979.1080p.BluRay.x264.mkv", 30},
+}
+
+// TVFiles is the synthetic TV library: a duplicate episode, release-group
+// filenames and a folder/filename title conflict
+var TVFiles = []File{
+	{"Lost/Season 01/Lost S01E01 1080p.mkv", 12},
+	{"Lost/Season 01/Lost.S01E01.480p.SDTV.XviD-GROUP.mkv", 6},
+	{"Lost/Season 01/Lost S01E02.mkv", 12},
+	{"Breaking Bad/Season 01/Breaking.Bad.S01E01.1080p.WEB-DL.x264-NTb.mkv", 10},
+	{"Star Trek/Season 01/Star Trek The Next Generation S01E01.mkv", 10},
+	{"The Office/Season 02/The Office S02E01.mkv", 8},
+}
+
+// Expected findings when scanning a freshly generated library
+const (
+	ExpectedMovieDuplicates = 2
+	ExpectedTVDuplicates    = 1
+	ExpectedAmbiguousShows  = 1
+)
+
+// Library is a generated synthetic library on disk
+type Library struct {
+	Root       string
+	MoviesPath string
+	TVPath     string
+}
+
+// Generate creates the synthetic library under root
+// Files are sparse so the tree takes almost no disk space
+func Generate(root string) (*Library, error) {
+	lib := &Library{
+		Root:       root,
+		MoviesPath: filepath.Join(root, "movies"),
+		TVPath:     filepath.Join(root, "tv"),
+	}
+
+	if err := writeFiles(lib.MoviesPath, MovieFiles); err != nil {
+		return nil, fmt.Errorf("failed to create movie fixtures: %w", err)
+	}
+	if err := writeFiles(lib.TVPath, TVFiles); err != nil {
+		return nil, fmt.Errorf("failed to create TV fixtures: %w", err)
+	}
+
+	return lib, nil
+}
+
+// writeFiles creates each file under base with its apparent size
+func writeFiles(base string, files []File) error {
+	for _, file := range files {
+		path := filepath.Join(base, file.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := f.Truncate(file.SizeMB * 1024 * 1024); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// MockAPIKey is the only API key the mock server accepts
+const MockAPIKey = "jellysink-mock-key"
+
+// mockToken is the bearer token issued by the mock TVDB login
+const mockToken = "jellysink-mock-token"
+
+// MockSeries is a series known to the mock metadata API
+type MockSeries struct {
+	ID     string
+	Name   string
+	Year   string
+	ImdbID string
+}
+
+// Series is the catalogue served by the mock API
+var Series = []MockSeries{
+	{"81189", "Breaking Bad", "2008", "tt0903747"},
+	{"73739", "Lost", "2004", "tt0411008"},
+	{"71470", "Star Trek", "1966", "tt0060028"},
+	{"71470-tng", "Star Trek: The Next Generation", "1987", "tt0092455"},
+	{"73244", "The Office", "2005", "tt0386676"},
+}
+
+// MockAPI is a fake TVDB v4 + OMDB server
+// TVDB lives under /tvdb and OMDB under /omdb/
+type MockAPI struct {
+	Server *httptest.Server
+}
+
+// NewMockAPI starts a mock metadata server
+func NewMockAPI() *MockAPI {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tvdb/login", handleTVDBLogin)
+	mux.HandleFunc("/tvdb/search", handleTVDBSearch)
+	mux.HandleFunc("/omdb/", handleOMDB)
+
+	return &MockAPI{Server: httptest.NewServer(mux)}
+}
+
+// TVDBURL returns the base URL to use in place of the real TVDB API
+func (m *MockAPI) TVDBURL() string {
+	return m.Server.URL + "/tvdb"
+}
+
+// OMDBURL returns the base URL to use in place of the real OMDB API
+func (m *MockAPI) OMDBURL() string {
+	return m.Server.URL + "/omdb/"
+}
+
+// Install points new scanner API clients at the mock server
+// The returned function restores the previous base URLs
+func (m *MockAPI) Install() func() {
+	prevTVDB, prevOMDB := scanner.TVDBBaseURL, scanner.OMDBBaseURL
+	scanner.TVDBBaseURL = m.TVDBURL()
+	scanner.OMDBBaseURL = m.OMDBURL()
+	return func() {
+		scanner.TVDBBaseURL = prevTVDB
+		scanner.OMDBBaseURL = prevOMDB
+	}
+}
+
+// Close shuts down the mock server
+func (m *MockAPI) Close() {
+	m.Server.Close()
+}
+
+// lookupSeries finds series whose name matches the query, ignoring case
+// and punctuation
+func lookupSeries(query string) []MockSeries {
+	want := simplifyTitle(query)
+	var matches []MockSeries
+	for _, s := range Series {
+		if simplifyTitle(s.Name) == want {
+			matches = append(matches, s)
+		}
+	}
+	return matches
+}
+
+// simplifyTitle lowercases a title and drops everything except letters and digits
+func simplifyTitle(title string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func handleTVDBLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"status": "failure"})
+		return
+	}
+
+	var payload map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload["apikey"] != MockAPIKey {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"status": "failure", "message": "invalid api key"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "success",
+		"data":   map[string]string{"token": mockToken},
+	})
+}
+
+func handleTVDBSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+mockToken {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"status": "failure"})
+		return
+	}
+
+	var data []scanner.TVDBSeries
+	for _, s := range lookupSeries(r.URL.Query().Get("query")) {
+		data = append(data, scanner.TVDBSeries{ID: s.ID, TVDBID: s.ID, Name: s.Name, Year: s.Year})
+	}
+
+	writeJSON(w, http.StatusOK, scanner.TVDBSearchResult{Status: "success", Data: data})
+}
+
+func handleOMDB(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("apikey") != MockAPIKey {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"Response": "False", "Error": "Invalid API key!"})
+		return
+	}
+
+	var found *MockSeries
+	if id := query.Get("i"); id != "" {
+		for i := range Series {
+			if Series[i].ImdbID == id {
+				found = &Series[i]
+				break
+			}
+		}
+	} else if matches := lookupSeries(query.Get("t")); len(matches) > 0 {
+		found = &matches[0]
+	}
+
+	if found == nil {
+		writeJSON(w, http.StatusOK, map[string]string{"Response": "False", "Error": "Series not found!"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, scanner.OMDBSeries{Title: found.Name, Year: found.Year, ImdbID: found.ImdbID, Type: "series"})
+}
@@ -0,0 +1,155 @@
+// Package ignore matches paths against the exclusion patterns of the [scan]
+// config section and .jellysinkignore files
+package ignore
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Pattern is one exclusion. A pattern starting with "re:" is a regular
+// expression searched for in the slash-separated path. Any other pattern is
+// a case-insensitive glob: "*" and "?" stay within a path segment and "**"
+// spans any number of them. A glob matches the end of the path, so
+// "Extras/**" matches the contents of every Extras folder, unless it starts
+// with "/", which anchors it at the start. A glob ending in "/" matches
+// folders only. Folders are matched with a trailing "/"
+type Pattern struct {
+	text     string
+	re       *regexp.Regexp
+	segs     []string
+	anchored bool
+	dirOnly  bool
+}
+
+// Compile parses one pattern
+func Compile(text string) (Pattern, error) {
+	p := Pattern{text: text}
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return p, fmt.Errorf("empty pattern")
+	}
+	if expr, ok := strings.CutPrefix(trimmed, "re:"); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return p, fmt.Errorf("invalid regular expression %q: %w", expr, err)
+		}
+		p.re = re
+		return p, nil
+	}
+
+	glob := strings.ToLower(trimmed)
+	glob, p.anchored = strings.CutPrefix(glob, "/")
+	glob, p.dirOnly = strings.CutSuffix(glob, "/")
+	if glob == "" {
+		return p, fmt.Errorf("pattern %q matches nothing", text)
+	}
+	p.segs = strings.Split(glob, "/")
+	for _, seg := range p.segs {
+		if _, err := path.Match(seg, ""); err != nil {
+			return p, fmt.Errorf("invalid glob %q: %w", text, err)
+		}
+	}
+	return p, nil
+}
+
+// CompileAll parses every pattern in texts
+func CompileAll(texts []string) ([]Pattern, error) {
+	patterns := make([]Pattern, 0, len(texts))
+	for _, text := range texts {
+		p, err := Compile(text)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// Parse reads patterns one per line, skipping blank lines and lines
+// starting with "#"
+func Parse(r io.Reader) ([]Pattern, error) {
+	var patterns []Pattern
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		p, err := Compile(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, sc.Err()
+}
+
+// String returns the pattern as written
+func (p Pattern) String() string {
+	return p.text
+}
+
+// Match reports whether the pattern matches the slash-separated name, a
+// folder when isDir
+func (p Pattern) Match(name string, isDir bool) bool {
+	if isDir {
+		name = strings.TrimSuffix(name, "/") + "/"
+	}
+	if p.re != nil {
+		return p.re.MatchString(name)
+	}
+	if p.dirOnly && !isDir {
+		return false
+	}
+
+	segs := strings.Split(strings.TrimPrefix(strings.ToLower(name), "/"), "/")
+	candidates := [][]string{segs}
+	// A folder also matches as a plain name, so "Extras" skips the folder
+	if isDir {
+		candidates = append(candidates, segs[:len(segs)-1])
+	}
+	pattern := p.segs
+	if p.dirOnly {
+		pattern = append(pattern[:len(pattern):len(pattern)], "")
+	}
+	for _, s := range candidates {
+		if p.anchored {
+			if matchSegs(pattern, s) {
+				return true
+			}
+			continue
+		}
+		for i := range s {
+			if matchSegs(pattern, s[i:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchSegs matches path segments against pattern segments, "**" taking
+// any number of them
+func matchSegs(pattern, segs []string) bool {
+	if len(pattern) == 0 {
+		return len(segs) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segs); i++ {
+			if matchSegs(pattern[1:], segs[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segs) == 0 {
+		return false
+	}
+	ok, _ := path.Match(pattern[0], segs[0])
+	return ok && matchSegs(pattern[1:], segs[1:])
+}
@@ -0,0 +1,62 @@
+package ignore
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		isDir   bool
+		want    bool
+	}{
+		{"**/Extras/**", "/tv/Show/Extras/Bloopers.mkv", false, true},
+		{"**/Extras/**", "/tv/Show/Extras", true, true},
+		{"**/Extras/**", "/tv/Show/Season 01/Extras.mkv", false, false},
+		{"*.iso", "/movies/Heat (1995)/HEAT.ISO", false, true},
+		{"*.iso", "/movies/Heat (1995)", true, false},
+		{"Remux/", "/movies/Remux", true, true},
+		{"Remux/", "/movies/Remux", false, false},
+		{"Breaking Bad", "/tv/Breaking Bad", true, true},
+		{"Breaking Bad", "/tv/Breaking Bad 2", true, false},
+		{"Season */*.nfo", "/tv/Show/Season 01/tvshow.nfo", false, true},
+		{"/mnt/archive/**", "/mnt/archive/4K/film.mkv", false, true},
+		{"/archive/**", "/mnt/archive/4K/film.mkv", false, false},
+		{"archive/**", "/mnt/archive/4K/film.mkv", false, true},
+		{`re:(?i)\bremux\b.*\.mkv$`, "/movies/Heat (1995)/Heat REMUX 2160p.mkv", false, true},
+		{"re:/Featurettes/$", "/movies/Heat (1995)/Featurettes", true, true},
+	}
+	for _, tt := range tests {
+		p, err := Compile(tt.pattern)
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", tt.pattern, err)
+		}
+		if got := p.Match(tt.name, tt.isDir); got != tt.want {
+			t.Errorf("%q.Match(%q, dir=%v) = %v, want %v", tt.pattern, tt.name, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, bad := range []string{"", "  ", "/", "[abc", "re:(unclosed"} {
+		if _, err := Compile(bad); err == nil {
+			t.Errorf("Compile(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	patterns, err := Parse(strings.NewReader("# trailers stay\n\nTrailers/\n  *.iso  \n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(patterns) != 2 || patterns[0].String() != "Trailers/" || patterns[1].String() != "*.iso" {
+		t.Errorf("Parse returned %v", patterns)
+	}
+
+	if _, err := Parse(strings.NewReader("*.iso\n[bad\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected an error naming line 2, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+// Package jellyfin talks to a Jellyfin server's REST API
+package jellyfin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// itemsPageSize bounds how many items one /Items request returns
+const itemsPageSize = 1000
+
+// Client is a minimal Jellyfin API client authenticated with an API key
+type Client struct {
+	BaseURL    string
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// Item is a library item as returned by /Items
+type Item struct {
+	ID         string `json:"Id"`
+	Name       string `json:"Name"`
+	Type       string `json:"Type"` // Movie or Episode
+	Path       string `json:"Path,omitempty"`
+	SeriesName string `json:"SeriesName,omitempty"`
+}
+
+// itemsResponse is one page of /Items results
+type itemsResponse struct {
+	Items            []Item `json:"Items"`
+	TotalRecordCount int    `json:"TotalRecordCount"`
+}
+
+// NewClient creates a client for the server at baseURL (e.g. http://localhost:8096)
+func NewClient(baseURL, apiKey string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// get performs an authenticated GET and decodes the JSON response into out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	return c.do(ctx, http.MethodGet, path, query, out)
+}
+
+// post performs an authenticated POST without a body, ignoring any response
+func (c *Client) post(ctx context.Context, path string, query url.Values) error {
+	return c.do(ctx, http.MethodPost, path, query, nil)
+}
+
+// do sends an authenticated request and decodes a JSON response into out
+// when out is non-nil
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, out any) error {
+	if c.BaseURL == "" || c.APIKey == "" {
+		return fmt.Errorf("jellyfin url and api_key must be configured")
+	}
+
+	apiURL := c.BaseURL + path
+	if len(query) > 0 {
+		apiURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Emby-Token", c.APIKey)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Jellyfin request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("Jellyfin rejected the API key (status %d)", resp.StatusCode)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("Jellyfin returned status %d for %s", resp.StatusCode, path)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse Jellyfin response: %w", err)
+	}
+	return nil
+}
+
+// MediaItems returns every movie and episode the server knows about, paging
+// through /Items; virtual items (e.g. missing episodes) have an empty Path
+func (c *Client) MediaItems(ctx context.Context) ([]Item, error) {
+	return c.items(ctx, "Movie,Episode", "Path")
+}
+
+// SeriesNames returns the title of every TV series in the server's libraries
+func (c *Client) SeriesNames(ctx context.Context) ([]string, error) {
+	items, err := c.items(ctx, "Series", "")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names, nil
+}
+
+// RefreshLibrary asks the server to scan all of its libraries for changes
+func (c *Client) RefreshLibrary(ctx context.Context) error {
+	return c.post(ctx, "/Library/Refresh", nil)
+}
+
+// items pages through /Items for the given item types
+func (c *Client) items(ctx context.Context, types, fields string) ([]Item, error) {
+	var items []Item
+	for start := 0; ; start += itemsPageSize {
+		query := url.Values{}
+		query.Set("Recursive", "true")
+		query.Set("IncludeItemTypes", types)
+		if fields != "" {
+			query.Set("Fields", fields)
+		}
+		query.Set("StartIndex", fmt.Sprintf("%d", start))
+		query.Set("Limit", fmt.Sprintf("%d", itemsPageSize))
+
+		var page itemsResponse
+		if err := c.get(ctx, "/Items", query, &page); err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+		if len(page.Items) == 0 || len(items) >= page.TotalRecordCount {
+			return items, nil
+		}
+	}
+}
+
+// session is an active client session as returned by /Sessions
+type session struct {
+	NowPlayingItem *Item `json:"NowPlayingItem"`
+}
+
+// sessions returns the client sessions active in the last 16 minutes
+func (c *Client) sessions(ctx context.Context) ([]session, error) {
+	query := url.Values{}
+	query.Set("ActiveWithinSeconds", "960")
+
+	var sessions []session
+	if err := c.get(ctx, "/Sessions", query, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// PlayingPaths returns the server-side paths of items currently being played
+func (c *Client) PlayingPaths(ctx context.Context) ([]string, error) {
+	sessions, err := c.sessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, s := range sessions {
+		if s.NowPlayingItem != nil && s.NowPlayingItem.Path != "" {
+			paths = append(paths, s.NowPlayingItem.Path)
+		}
+	}
+	return paths, nil
+}
+
+// ActiveStreams returns how many sessions are playing something, paused
+// playback included
+func (c *Client) ActiveStreams(ctx context.Context) (int, error) {
+	sessions, err := c.sessions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	streams := 0
+	for _, s := range sessions {
+		if s.NowPlayingItem != nil {
+			streams++
+		}
+	}
+	return streams, nil
+}
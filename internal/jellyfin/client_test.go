@@ -0,0 +1,106 @@
+package jellyfin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMediaItemsPagesAndAuthenticates(t *testing.T) {
+	const total = itemsPageSize + 2
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("X-Emby-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/Items" || r.URL.Query().Get("IncludeItemTypes") != "Movie,Episode" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		start, _ := strconv.Atoi(r.URL.Query().Get("StartIndex"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("Limit"))
+		page := itemsResponse{TotalRecordCount: total}
+		for i := start; i < total && i < start+limit; i++ {
+			page.Items = append(page.Items, Item{ID: strconv.Itoa(i), Type: "Movie", Path: "/media/" + strconv.Itoa(i) + ".mkv"})
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	items, err := NewClient(server.URL+"/", "secret").MediaItems(context.Background())
+	if err != nil {
+		t.Fatalf("MediaItems failed: %v", err)
+	}
+	if len(items) != total || requests != 2 {
+		t.Errorf("Expected %d items over 2 requests, got %d over %d", total, len(items), requests)
+	}
+
+	_, err = NewClient(server.URL, "wrong").MediaItems(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "rejected the API key") {
+		t.Errorf("Expected API key error, got %v", err)
+	}
+}
+
+func TestClientRequiresConfiguration(t *testing.T) {
+	if _, err := NewClient("", "").MediaItems(context.Background()); err == nil {
+		t.Error("Expected error without url and api_key")
+	}
+}
+
+func TestPlayingPaths(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/Sessions" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`[{"NowPlayingItem":{"Id":"1","Name":"Heat","Type":"Movie","Path":"/data/Heat (1995)/Heat (1995).mkv"}},{"DeviceName":"idle"}]`))
+	}))
+	defer server.Close()
+
+	paths, err := NewClient(server.URL, "secret").PlayingPaths(context.Background())
+	if err != nil {
+		t.Fatalf("PlayingPaths failed: %v", err)
+	}
+	if len(paths) != 1 || paths[0] != "/data/Heat (1995)/Heat (1995).mkv" {
+		t.Errorf("Expected the one playing path, got %v", paths)
+	}
+
+	// Sessions without a playing item are idle
+	streams, err := NewClient(server.URL, "secret").ActiveStreams(context.Background())
+	if err != nil || streams != 1 {
+		t.Errorf("ActiveStreams = %d, %v; want 1", streams, err)
+	}
+}
+
+func TestRefreshLibraryAndSeriesNames(t *testing.T) {
+	var refreshed bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/Library/Refresh":
+			if r.Method != http.MethodPost {
+				t.Errorf("Expected POST for a refresh, got %s", r.Method)
+			}
+			refreshed = true
+			w.WriteHeader(http.StatusNoContent)
+		case "/Items":
+			if r.URL.Query().Get("IncludeItemTypes") != "Series" {
+				t.Errorf("Unexpected request %s", r.URL)
+			}
+			w.Write([]byte(`{"Items":[{"Id":"1","Name":"Firefly","Type":"Series"},{"Id":"2","Name":"Heat","Type":"Series"}],"TotalRecordCount":2}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "secret")
+	if err := client.RefreshLibrary(context.Background()); err != nil || !refreshed {
+		t.Errorf("Expected refresh requested, got %v", err)
+	}
+	names, err := client.SeriesNames(context.Background())
+	if err != nil || len(names) != 2 || names[0] != "Firefly" {
+		t.Errorf("SeriesNames = %v, %v", names, err)
+	}
+}
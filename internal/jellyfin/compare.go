@@ -0,0 +1,125 @@
+package jellyfin
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PathMapping translates a path prefix as the Jellyfin server sees it (e.g.
+// inside a container) into the same folder on this machine
+type PathMapping struct {
+	Server string
+	Local  string
+}
+
+// ParsePathMapping parses a "server_prefix=local_prefix" mapping
+func ParsePathMapping(s string) (PathMapping, error) {
+	server, local, ok := strings.Cut(s, "=")
+	server, local = strings.TrimSpace(server), strings.TrimSpace(local)
+	if !ok || server == "" || local == "" {
+		return PathMapping{}, fmt.Errorf("invalid path mapping %q (must be server_prefix=local_prefix)", s)
+	}
+	return PathMapping{Server: filepath.Clean(server), Local: filepath.Clean(local)}, nil
+}
+
+// LocalPath maps a server path onto this machine using the first matching prefix
+func LocalPath(serverPath string, mappings []PathMapping) string {
+	p := filepath.Clean(serverPath)
+	for _, m := range mappings {
+		if rest, ok := underPrefix(p, m.Server); ok {
+			return filepath.Join(m.Local, rest)
+		}
+	}
+	return p
+}
+
+// ServerPath maps a path on this machine back to the server's view, the
+// reverse of LocalPath
+func ServerPath(localPath string, mappings []PathMapping) string {
+	p := filepath.Clean(localPath)
+	for _, m := range mappings {
+		if rest, ok := underPrefix(p, m.Local); ok {
+			return filepath.Join(m.Server, rest)
+		}
+	}
+	return p
+}
+
+// underPrefix reports whether path is prefix or inside it, returning the remainder
+func underPrefix(path, prefix string) (string, bool) {
+	if path == prefix {
+		return "", true
+	}
+	if strings.HasPrefix(path, strings.TrimSuffix(prefix, string(filepath.Separator))+string(filepath.Separator)) {
+		return path[len(prefix):], true
+	}
+	return "", false
+}
+
+// Comparison is the result of checking on-disk video files against the items
+// Jellyfin knows about
+type Comparison struct {
+	ServerURL         string
+	ServerItems       int      // server items with a file under the scanned libraries
+	DiskFiles         int      // video files found on disk
+	MissingFromServer []string // on disk but unknown to Jellyfin (failed matches, usually naming)
+	MissingOnDisk     []Item   // listed by Jellyfin but the file no longer exists
+	Error             string   `json:",omitempty"` // set when the server could not be queried
+}
+
+// HasFindings reports whether the server and disk disagree
+func (c *Comparison) HasFindings() bool {
+	return c != nil && (len(c.MissingFromServer) > 0 || len(c.MissingOnDisk) > 0)
+}
+
+// Compare matches server items against local video files. Only items whose
+// (mapped) path lies under one of libraryPaths are considered, so other
+// Jellyfin libraries do not show up as missing
+func Compare(items []Item, localFiles, libraryPaths []string, mappings []PathMapping) *Comparison {
+	result := &Comparison{DiskFiles: len(localFiles)}
+
+	onDisk := make(map[string]bool, len(localFiles))
+	for _, f := range localFiles {
+		onDisk[filepath.Clean(f)] = true
+	}
+
+	known := make(map[string]bool, len(items))
+	for _, item := range items {
+		if item.Path == "" {
+			continue
+		}
+		local := LocalPath(item.Path, mappings)
+		if !inLibraries(local, libraryPaths) {
+			continue
+		}
+		result.ServerItems++
+		known[local] = true
+		if !onDisk[local] {
+			item.Path = local
+			result.MissingOnDisk = append(result.MissingOnDisk, item)
+		}
+	}
+
+	for _, f := range localFiles {
+		if f = filepath.Clean(f); !known[f] {
+			result.MissingFromServer = append(result.MissingFromServer, f)
+		}
+	}
+
+	sort.Strings(result.MissingFromServer)
+	sort.Slice(result.MissingOnDisk, func(i, j int) bool {
+		return result.MissingOnDisk[i].Path < result.MissingOnDisk[j].Path
+	})
+	return result
+}
+
+func inLibraries(path string, libraryPaths []string) bool {
+	for _, lib := range libraryPaths {
+		if _, ok := underPrefix(path, filepath.Clean(lib)); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package jellyfin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	mappings := []PathMapping{{Server: "/data/movies", Local: "/mnt/media/movies"}}
+	items := []Item{
+		{Name: "Heat", Path: "/data/movies/Heat (1995)/Heat (1995).mkv"},
+		{Name: "Alien", Path: "/data/movies/Alien (1979)/Alien (1979).mkv"},
+		{Name: "Missing Episode"},                             // virtual item, no file
+		{Name: "Song", Path: "/data/music/Artist/Song.flac"},  // another library
+		{Name: "Elsewhere", Path: "/mnt/other/Elsewhere.mkv"}, // outside the scanned libraries
+	}
+	local := []string{
+		"/mnt/media/movies/Heat (1995)/Heat (1995).mkv",
+		"/mnt/media/movies/heat.2.2025.1080p.mkv",
+	}
+
+	cmp := Compare(items, local, []string{"/mnt/media/movies"}, mappings)
+	if cmp.ServerItems != 2 || cmp.DiskFiles != 2 {
+		t.Errorf("Expected 2 server items and 2 disk files, got %d and %d", cmp.ServerItems, cmp.DiskFiles)
+	}
+	if want := []string{"/mnt/media/movies/heat.2.2025.1080p.mkv"}; !reflect.DeepEqual(cmp.MissingFromServer, want) {
+		t.Errorf("MissingFromServer = %v, want %v", cmp.MissingFromServer, want)
+	}
+	if len(cmp.MissingOnDisk) != 1 || cmp.MissingOnDisk[0].Path != "/mnt/media/movies/Alien (1979)/Alien (1979).mkv" {
+		t.Errorf("Expected Alien missing on disk at its local path, got %+v", cmp.MissingOnDisk)
+	}
+	if !cmp.HasFindings() {
+		t.Error("Expected findings")
+	}
+}
+
+func TestParsePathMapping(t *testing.T) {
+	m, err := ParsePathMapping(" /data/media/ = /mnt/media ")
+	if err != nil || m.Server != "/data/media" || m.Local != "/mnt/media" {
+		t.Errorf("Unexpected mapping %+v (%v)", m, err)
+	}
+	for _, bad := range []string{"/data/media", "=/mnt/media", "/data/media="} {
+		if _, err := ParsePathMapping(bad); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+
+	// Prefixes match whole path components only
+	if got := LocalPath("/data/media2/a.mkv", []PathMapping{m}); got != "/data/media2/a.mkv" {
+		t.Errorf("Expected /data/media2 left unmapped, got %s", got)
+	}
+}
@@ -0,0 +1,44 @@
+package jellyfin
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// trailingYear matches a "(2001)" style year suffix
+var trailingYear = regexp.MustCompile(`\s*\(\d{4}\)$`)
+
+// titleKey reduces a title to lowercase letters and digits, without any
+// trailing year, so "Marvel's Agents of S.H.I.E.L.D. (2013)" and
+// "Marvels Agents of SHIELD" compare equal
+func titleKey(title string) string {
+	title = trailingYear.ReplaceAllString(strings.TrimSpace(title), "")
+	var sb strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// MatchTitle returns the first of known (titles the server already has)
+// matching one of candidates, ignoring case, punctuation and a trailing
+// year, or "" when none does. Candidates are tried in order
+func MatchTitle(known []string, candidates ...string) string {
+	byKey := make(map[string]string, len(known))
+	for _, title := range known {
+		if key := titleKey(title); key != "" {
+			if _, ok := byKey[key]; !ok {
+				byKey[key] = title
+			}
+		}
+	}
+	for _, candidate := range candidates {
+		if title, ok := byKey[titleKey(candidate)]; ok {
+			return title
+		}
+	}
+	return ""
+}
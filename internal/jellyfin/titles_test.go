@@ -0,0 +1,24 @@
+package jellyfin
+
+import "testing"
+
+func TestMatchTitle(t *testing.T) {
+	known := []string{"Marvel's Agents of S.H.I.E.L.D.", "Doctor Who (2005)", "Doctor Who"}
+
+	tests := []struct {
+		candidates []string
+		want       string
+	}{
+		{[]string{"Marvels Agents of SHIELD"}, "Marvel's Agents of S.H.I.E.L.D."},
+		{[]string{"doctor who (2005)"}, "Doctor Who (2005)"},
+		{[]string{"Dr Who"}, ""},
+		{[]string{"Doctor.Who"}, "Doctor Who (2005)"},
+		{[]string{"Unknown", "Doctor Who"}, "Doctor Who (2005)"},
+		{[]string{""}, ""},
+	}
+	for _, tt := range tests {
+		if got := MatchTitle(known, tt.candidates...); got != tt.want {
+			t.Errorf("MatchTitle(%v) = %q, want %q", tt.candidates, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,361 @@
+// Package jsonstream reads and writes large JSON documents one element at
+// a time. A struct's slice and map fields are encoded and decoded entry by
+// entry instead of being held as one []byte, so a report with tens of
+// thousands of issues never needs a second full copy in memory
+package jsonstream
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// field is one exported struct field and its JSON name
+type field struct {
+	index     int
+	name      string
+	omitEmpty bool
+}
+
+// structFields returns the JSON fields of t, or false when t has embedded
+// fields or tag options the streaming codec doesn't mirror
+func structFields(t reflect.Type) ([]field, bool) {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous {
+			return nil, false
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = sf.Name
+		}
+		f := field{index: i, name: name}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "":
+			case "omitempty":
+				f.omitEmpty = true
+			default:
+				return nil, false
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields, true
+}
+
+// isEmpty mirrors encoding/json's omitempty test
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// streamable reports whether v is a slice or string-keyed map whose
+// entries are written one at a time
+func streamable(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice:
+		return !v.IsNil() && v.Len() > 0 && v.Type().Elem().Kind() != reflect.Uint8
+	case reflect.Map:
+		return !v.IsNil() && v.Len() > 0 && v.Type().Key().Kind() == reflect.String
+	}
+	return false
+}
+
+// encoder writes values with the layout of json.Marshal (indent "") or
+// json.MarshalIndent(v, "", indent)
+type encoder struct {
+	w      *bufio.Writer
+	indent string
+}
+
+// value writes v nested depth levels deep
+func (e *encoder) value(v any, depth int) error {
+	var data []byte
+	var err error
+	if e.indent == "" {
+		data, err = json.Marshal(v)
+	} else {
+		data, err = json.MarshalIndent(v, strings.Repeat(e.indent, depth), e.indent)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+// newline starts a line depth levels deep; compact output has none
+func (e *encoder) newline(depth int) {
+	if e.indent != "" {
+		e.w.WriteByte('\n')
+		e.w.WriteString(strings.Repeat(e.indent, depth))
+	}
+}
+
+// colon separates an object key from its value
+func (e *encoder) colon() {
+	if e.indent == "" {
+		e.w.WriteByte(':')
+	} else {
+		e.w.WriteString(": ")
+	}
+}
+
+// key writes an object key
+func (e *encoder) key(name string) error {
+	data, err := json.Marshal(name)
+	if err != nil {
+		return err
+	}
+	e.w.Write(data)
+	e.colon()
+	return nil
+}
+
+// entries writes a slice or map one entry at a time, depth levels deep
+func (e *encoder) entries(v reflect.Value, depth int) error {
+	if v.Kind() == reflect.Slice {
+		e.w.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				e.w.WriteByte(',')
+			}
+			e.newline(depth + 1)
+			if err := e.value(v.Index(i).Interface(), depth+1); err != nil {
+				return err
+			}
+		}
+		e.newline(depth)
+		return e.w.WriteByte(']')
+	}
+
+	keys := make([]string, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	e.w.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			e.w.WriteByte(',')
+		}
+		e.newline(depth + 1)
+		if err := e.key(k); err != nil {
+			return err
+		}
+		elem := v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key()))
+		if err := e.value(elem.Interface(), depth+1); err != nil {
+			return err
+		}
+	}
+	e.newline(depth)
+	return e.w.WriteByte('}')
+}
+
+// Encode writes v to w. The output is byte for byte what json.Marshal
+// (indent "") or json.MarshalIndent(v, "", indent) produce, but the
+// entries of v's slice and map fields are marshaled one at a time.
+// Values that aren't structs are marshaled whole
+func Encode(w io.Writer, v any, indent string) error {
+	bw := bufio.NewWriter(w)
+	e := &encoder{w: bw, indent: indent}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	fields, ok := []field(nil), false
+	if rv.Kind() == reflect.Struct && !implementsMarshaler(rv) {
+		fields, ok = structFields(rv.Type())
+	}
+	if !ok {
+		if err := e.value(v, 0); err != nil {
+			return err
+		}
+		return bw.Flush()
+	}
+
+	bw.WriteByte('{')
+	written := 0
+	for _, f := range fields {
+		fv := rv.Field(f.index)
+		if f.omitEmpty && isEmpty(fv) {
+			continue
+		}
+		if written > 0 {
+			bw.WriteByte(',')
+		}
+		written++
+		e.newline(1)
+		if err := e.key(f.name); err != nil {
+			return err
+		}
+		var err error
+		if streamable(fv) {
+			err = e.entries(fv, 1)
+		} else {
+			err = e.value(fv.Interface(), 1)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", f.name, err)
+		}
+	}
+	if written > 0 {
+		e.newline(0)
+	}
+	bw.WriteByte('}')
+	return bw.Flush()
+}
+
+// implementsMarshaler reports whether v controls its own encoding
+func implementsMarshaler(v reflect.Value) bool {
+	marshaler := reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	return v.Type().Implements(marshaler) || reflect.PointerTo(v.Type()).Implements(marshaler)
+}
+
+// Decode reads a JSON object from r into the struct v points to. Slice
+// and string-keyed map fields are decoded one entry at a time rather than
+// buffering their whole text; unknown keys are skipped
+func Decode(r io.Reader, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("jsonstream: Decode needs a non-nil pointer, got %T", v)
+	}
+	dec := json.NewDecoder(r)
+	rv = rv.Elem()
+	fields, ok := []field(nil), false
+	if rv.Kind() == reflect.Struct && !implementsUnmarshaler(rv) {
+		fields, ok = structFields(rv.Type())
+	}
+	if !ok {
+		return dec.Decode(v)
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("jsonstream: expected object, got %v", tok)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, _ := tok.(string)
+		fv, found := lookupField(rv, fields, name)
+		if !found {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := decodeField(dec, fv); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", name, err)
+		}
+	}
+	_, err = dec.Token() // closing brace
+	return err
+}
+
+// implementsUnmarshaler reports whether v controls its own decoding
+func implementsUnmarshaler(v reflect.Value) bool {
+	unmarshaler := reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	return reflect.PointerTo(v.Type()).Implements(unmarshaler)
+}
+
+// lookupField finds the field for a key the way encoding/json does: an
+// exact name match first, then a case-insensitive one
+func lookupField(rv reflect.Value, fields []field, name string) (reflect.Value, bool) {
+	for _, f := range fields {
+		if f.name == name {
+			return rv.Field(f.index), true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, name) {
+			return rv.Field(f.index), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// decodeField reads one field value, entry by entry for slices and maps
+func decodeField(dec *json.Decoder, fv reflect.Value) error {
+	t := fv.Type()
+	sliceField := t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8
+	mapField := t.Kind() == reflect.Map && t.Key().Kind() == reflect.String
+	if !sliceField && !mapField {
+		return dec.Decode(fv.Addr().Interface())
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	open := json.Delim('[')
+	if mapField {
+		open = json.Delim('{')
+	}
+	switch {
+	case tok == nil:
+		fv.Set(reflect.Zero(t))
+		return nil
+	case tok != open:
+		return fmt.Errorf("jsonstream: cannot decode %v into %s", tok, t)
+	}
+
+	if sliceField {
+		fv.Set(reflect.MakeSlice(t, 0, 0))
+		for dec.More() {
+			elem := reflect.New(t.Elem())
+			if err := dec.Decode(elem.Interface()); err != nil {
+				return err
+			}
+			fv.Set(reflect.Append(fv, elem.Elem()))
+		}
+	} else {
+		if fv.IsNil() {
+			fv.Set(reflect.MakeMap(t))
+		}
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := tok.(string)
+			elem := reflect.New(t.Elem())
+			if err := dec.Decode(elem.Interface()); err != nil {
+				return err
+			}
+			fv.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), elem.Elem())
+		}
+	}
+	_, err = dec.Token() // closing bracket or brace
+	return err
+}
@@ -0,0 +1,93 @@
+package jsonstream
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type entry struct {
+	Path string
+	Size int64 `json:",omitempty"`
+}
+
+type document struct {
+	Timestamp time.Time
+	Paths     []string
+	Entries   []entry
+	Pointers  []*entry
+	Empty     []entry
+	Files     map[string]*entry
+	Note      string `json:"note,omitempty"`
+	Count     int
+	Raw       []byte
+	Nested    *entry `json:",omitempty"`
+	hidden    int
+}
+
+func sampleDocument() document {
+	return document{
+		Timestamp: time.Date(2025, 6, 1, 8, 30, 0, 0, time.UTC),
+		Paths:     []string{"/movies", "/tv <&>"},
+		Entries:   []entry{{Path: "/movies/a.mkv", Size: 10}, {Path: "/movies/b.mkv"}},
+		Pointers:  []*entry{{Path: "/x"}, nil},
+		Empty:     []entry{},
+		Files:     map[string]*entry{"b": {Path: "/b"}, "a": {Path: "/a", Size: 1}},
+		Count:     3,
+		Raw:       []byte("bytes"),
+	}
+}
+
+func TestEncodeMatchesEncodingJSON(t *testing.T) {
+	docs := map[string]document{"full": sampleDocument(), "zero": {}}
+	for name, doc := range docs {
+		for _, indent := range []string{"", "  "} {
+			var want []byte
+			if indent == "" {
+				want, _ = json.Marshal(doc)
+			} else {
+				want, _ = json.MarshalIndent(doc, "", indent)
+			}
+			var got bytes.Buffer
+			if err := Encode(&got, &doc, indent); err != nil {
+				t.Fatalf("%s: Encode() error: %v", name, err)
+			}
+			if got.String() != string(want) {
+				t.Errorf("%s (indent %q):\ngot  %s\nwant %s", name, indent, got.String(), want)
+			}
+		}
+	}
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	doc := sampleDocument()
+	data, _ := json.MarshalIndent(doc, "", "  ")
+
+	var got document
+	if err := Decode(bytes.NewReader(data), &got); err != nil {
+		t.Fatalf("Decode() error: %v", err)
+	}
+	var want document
+	json.Unmarshal(data, &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestDecodeToleratesNullsAndUnknownKeys(t *testing.T) {
+	input := `{"paths": null, "FILES": {"x": {"Path": "/x"}}, "Unknown": [1, {"a": 2}], "Count": 7}`
+	got := document{Paths: []string{"stale"}}
+	if err := Decode(strings.NewReader(input), &got); err != nil {
+		t.Fatalf("Decode() error: %v", err)
+	}
+	if got.Paths != nil || got.Count != 7 || got.Files["x"].Path != "/x" {
+		t.Errorf("Decode() = %+v", got)
+	}
+
+	if err := Decode(strings.NewReader(`{"Entries": "nope"}`), &got); err == nil {
+		t.Error("Expected an error for a string where an array belongs")
+	}
+}
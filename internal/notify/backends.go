@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Webhook POSTs each event as JSON, with the rendered message in "message"
+type Webhook struct {
+	URL        string
+	HTTPClient *http.Client // nil = a client with a 30s timeout
+}
+
+// Name identifies the webhook in logs
+func (w *Webhook) Name() string { return "webhook" }
+
+// Send posts the event's fields and message
+func (w *Webhook) Send(ctx context.Context, event Event, message string) error {
+	payload := struct {
+		Event
+		Message string `json:"message"`
+	}{event, message}
+	return postJSON(ctx, w.HTTPClient, w.URL, payload)
+}
+
+// discordLimit is the most characters a Discord message may hold
+const discordLimit = 2000
+
+// Discord posts the message to a Discord channel webhook
+type Discord struct {
+	WebhookURL string
+	HTTPClient *http.Client
+}
+
+// Name identifies Discord in logs
+func (d *Discord) Name() string { return "discord" }
+
+// Send posts the message as the webhook's content
+func (d *Discord) Send(ctx context.Context, event Event, message string) error {
+	payload := map[string]any{
+		"username": "jellysink",
+		"content":  truncate(message, discordLimit),
+		// Never ping @everyone or roles from a file name
+		"allowed_mentions": map[string]any{"parse": []string{}},
+	}
+	return postJSON(ctx, d.HTTPClient, d.WebhookURL, payload)
+}
+
+// telegramLimit is the most characters a Telegram message may hold
+const telegramLimit = 4096
+
+// TelegramAPI is the Bot API endpoint
+const TelegramAPI = "https://api.telegram.org"
+
+// Telegram sends the message to a chat through a bot
+type Telegram struct {
+	BotToken   string
+	ChatID     string // numeric ID, or @channelname
+	APIURL     string // empty = TelegramAPI
+	HTTPClient *http.Client
+}
+
+// Name identifies Telegram in logs
+func (t *Telegram) Name() string { return "telegram" }
+
+// Send calls sendMessage as plain text, so names with underscores or
+// asterisks are not read as markup
+func (t *Telegram) Send(ctx context.Context, event Event, message string) error {
+	api := t.APIURL
+	if api == "" {
+		api = TelegramAPI
+	}
+	payload := map[string]any{
+		"chat_id":                  telegramChat(t.ChatID),
+		"text":                     truncate(message, telegramLimit),
+		"disable_web_page_preview": true,
+	}
+	return postJSON(ctx, t.HTTPClient, strings.TrimRight(api, "/")+"/bot"+t.BotToken+"/sendMessage", payload)
+}
+
+// telegramChat sends numeric chat IDs as numbers and @channel names as strings
+func telegramChat(id string) any {
+	if v, err := strconv.ParseInt(id, 10, 64); err == nil {
+		return v
+	}
+	return id
+}
@@ -0,0 +1,222 @@
+// Package notify posts jellysinkd events to a generic webhook, Discord and
+// Telegram
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Kind is what happened
+type Kind string
+
+const (
+	ScanComplete  Kind = "scan"  // a scan saved its report
+	CleanComplete Kind = "clean" // auto-clean finished
+	Failure       Kind = "error" // a scan or auto-clean failed
+)
+
+// Kinds lists every event kind
+var Kinds = []Kind{ScanComplete, CleanComplete, Failure}
+
+// ParseKind checks s names an event kind
+func ParseKind(s string) (Kind, error) {
+	for _, k := range Kinds {
+		if string(k) == s {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("unknown event %q (must be scan, clean or error)", s)
+}
+
+// Event is what the message template renders and the webhook receives.
+// Fields that do not apply to the kind are left zero
+type Event struct {
+	Kind Kind      `json:"event"`
+	Time time.Time `json:"time"`
+	Host string    `json:"host"`
+
+	// Scan
+	Library          string `json:"library,omitempty"` // movies, tv or mixed
+	ReportPath       string `json:"report_path,omitempty"`
+	ReportURL        string `json:"report_url,omitempty"` // on the jellysinkd API, when http_addr is set
+	Duplicates       int    `json:"duplicates,omitempty"`
+	FilesToDelete    int    `json:"files_to_delete,omitempty"`
+	SpaceToFree      int64  `json:"space_to_free,omitempty"`
+	ComplianceIssues int    `json:"compliance_issues,omitempty"`
+	Partial          bool   `json:"partial,omitempty"`
+	Simulated        bool   `json:"simulated,omitempty"`
+
+	// Clean
+	DuplicatesDeleted int    `json:"duplicates_deleted,omitempty"`
+	ComplianceFixed   int    `json:"compliance_fixed,omitempty"`
+	SpaceFreed        int64  `json:"space_freed,omitempty"`
+	Deferred          int    `json:"deferred,omitempty"` // operations left for the next clean
+	Errors            int    `json:"errors,omitempty"`
+	JournalID         string `json:"journal_id,omitempty"`
+
+	// Failure
+	Error string `json:"error,omitempty"`
+}
+
+// DefaultTemplate is the message text when a target sets no template
+const DefaultTemplate = `{{if eq .Kind "scan"}}jellysink {{.Library}} scan on {{.Host}}{{if .Simulated}} (test){{end}}: {{.Duplicates}} duplicate groups, {{gb .SpaceToFree}} reclaimable, {{.ComplianceIssues}} compliance issues
+{{- if .Partial}}
+Partial scan: some folders could not be read{{end}}
+{{- if .ReportURL}}
+Report: {{.ReportURL}}{{else if .ReportPath}}
+Review it with: jellysink view {{.ReportPath}}{{end}}
+{{- else if eq .Kind "clean"}}jellysink auto-clean on {{.Host}}: {{.DuplicatesDeleted}} duplicates deleted, {{.ComplianceFixed}} compliance issues fixed, {{gb .SpaceFreed}} freed
+{{- if .Deferred}}, {{.Deferred}} deferred{{end}}{{if .Errors}}, {{.Errors}} errors{{end}}
+{{- if .JournalID}}
+Undo with: jellysink undo {{.JournalID}}{{end}}
+{{- else}}jellysink on {{.Host}} failed: {{.Error}}{{end}}`
+
+// funcs are available to message templates
+var funcs = template.FuncMap{
+	// gb renders a byte count as "1.23 GB"
+	"gb": func(bytes int64) string { return fmt.Sprintf("%.2f GB", float64(bytes)/(1024*1024*1024)) },
+}
+
+// ParseTemplate parses a message template; empty text is DefaultTemplate.
+// The template is tried on an event of each kind, so a misspelt field fails
+// here rather than when jellysinkd has something to report
+func ParseTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+	tmpl, err := template.New("message").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message template: %w", err)
+	}
+	for _, k := range Kinds {
+		if err := tmpl.Execute(io.Discard, Event{Kind: k}); err != nil {
+			return nil, fmt.Errorf("invalid message template: %w", err)
+		}
+	}
+	return tmpl, nil
+}
+
+// Notifier delivers a rendered message for an event
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, event Event, message string) error
+}
+
+// Target is a notifier, the events it receives and its message template
+type Target struct {
+	Notifier Notifier
+	Events   []Kind // empty = every kind
+	Template *template.Template
+}
+
+// wants reports whether the target receives events of kind k
+func (t Target) wants(k Kind) bool {
+	if len(t.Events) == 0 {
+		return true
+	}
+	for _, e := range t.Events {
+		if e == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Dispatcher sends each event to every target that receives it
+type Dispatcher struct {
+	Targets []Target
+}
+
+// Names lists the targets that receive events of kind k
+func (d *Dispatcher) Names(k Kind) []string {
+	var names []string
+	for _, t := range d.Targets {
+		if t.wants(k) {
+			names = append(names, t.Notifier.Name())
+		}
+	}
+	return names
+}
+
+// Send renders event for each target that receives it and delivers it. One
+// target failing does not stop the others; their errors are joined
+func (d *Dispatcher) Send(ctx context.Context, event Event) error {
+	var errs []error
+	for _, t := range d.Targets {
+		if !t.wants(event.Kind) {
+			continue
+		}
+		tmpl := t.Template
+		if tmpl == nil {
+			tmpl, _ = ParseTemplate("")
+		}
+		var msg strings.Builder
+		if err := tmpl.Execute(&msg, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: failed to render message: %w", t.Notifier.Name(), err))
+			continue
+		}
+		if err := t.Notifier.Send(ctx, event, strings.TrimSpace(msg.String())); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", t.Notifier.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// httpClient posts for backends that set no HTTPClient
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// postJSON POSTs body as JSON to target and fails on a non-2xx status. The
+// URL is left out of errors, as Discord and Telegram URLs hold secrets
+func postJSON(ctx context.Context, client *http.Client, target string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", redact(err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "jellysink")
+	if client == nil {
+		client = httpClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", redact(err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("server returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// redact drops the URL from a *url.Error
+func redact(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// truncate cuts message to at most limit runes, marking the cut
+func truncate(message string, limit int) string {
+	runes := []rune(message)
+	if len(runes) <= limit {
+		return message
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,135 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recorder serves status and keeps each request's path and JSON body
+func recorder(t *testing.T, status int) (*httptest.Server, *[]string, *[]map[string]any) {
+	t.Helper()
+	var paths []string
+	var bodies []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Request body is not JSON: %v", err)
+		}
+		paths = append(paths, r.URL.Path)
+		bodies = append(bodies, body)
+		w.WriteHeader(status)
+		w.Write([]byte(`{"ok":false,"description":"chat not found"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &paths, &bodies
+}
+
+func scanEvent() Event {
+	return Event{
+		Kind:             ScanComplete,
+		Time:             time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC),
+		Host:             "nas",
+		Library:          "movies",
+		ReportPath:       "/data/scan_results/movies.json",
+		Duplicates:       3,
+		SpaceToFree:      3 << 30,
+		ComplianceIssues: 2,
+	}
+}
+
+func TestDefaultTemplate(t *testing.T) {
+	tmpl, err := ParseTemplate("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		event Event
+		want  string
+	}{
+		{scanEvent(), "jellysink movies scan on nas: 3 duplicate groups, 3.00 GB reclaimable, 2 compliance issues\nReview it with: jellysink view /data/scan_results/movies.json"},
+		{Event{Kind: CleanComplete, Host: "nas", DuplicatesDeleted: 4, SpaceFreed: 1 << 30, Errors: 1, JournalID: "20260301-020000"},
+			"jellysink auto-clean on nas: 4 duplicates deleted, 0 compliance issues fixed, 1.00 GB freed, 1 errors\nUndo with: jellysink undo 20260301-020000"},
+		{Event{Kind: Failure, Host: "nas", Error: "scan failed: permission denied"}, "jellysink on nas failed: scan failed: permission denied"},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := tmpl.Execute(&b, tt.event); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(b.String()); got != tt.want {
+			t.Errorf("%s message = %q, want %q", tt.event.Kind, got, tt.want)
+		}
+	}
+
+	if _, err := ParseTemplate("{{.Duplicats}}"); err == nil {
+		t.Error("Expected a misspelt field to fail parsing")
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	webhook, webhookPaths, webhookBodies := recorder(t, http.StatusOK)
+	discord, _, discordBodies := recorder(t, http.StatusNoContent)
+	telegram, telegramPaths, _ := recorder(t, http.StatusBadRequest)
+
+	custom, err := ParseTemplate("{{.Duplicates}} duplicates on {{.Host}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &Dispatcher{Targets: []Target{
+		{Notifier: &Webhook{URL: webhook.URL + "/hook"}},
+		{Notifier: &Discord{WebhookURL: discord.URL}, Template: custom},
+		{Notifier: &Telegram{BotToken: "123:secret", ChatID: "-10042", APIURL: telegram.URL}, Events: []Kind{Failure}},
+	}}
+
+	if names := d.Names(ScanComplete); strings.Join(names, ",") != "webhook,discord" {
+		t.Errorf("Names(scan) = %v, want webhook and discord", names)
+	}
+	if err := d.Send(context.Background(), scanEvent()); err != nil {
+		t.Fatalf("Send(scan) error = %v", err)
+	}
+	if len(*webhookBodies) != 1 || (*webhookPaths)[0] != "/hook" {
+		t.Fatalf("Webhook received %v", *webhookPaths)
+	}
+	body := (*webhookBodies)[0]
+	if body["event"] != "scan" || body["duplicates"] != float64(3) || !strings.HasPrefix(body["message"].(string), "jellysink movies scan") {
+		t.Errorf("Unexpected webhook payload %v", body)
+	}
+	if len(*discordBodies) != 1 || (*discordBodies)[0]["content"] != "3 duplicates on nas" {
+		t.Errorf("Unexpected Discord payload %v", *discordBodies)
+	}
+	if len(*telegramPaths) != 0 {
+		t.Error("Telegram received a scan event it does not subscribe to")
+	}
+
+	// A failing target is reported without its secret URL; the others still get the event
+	err = d.Send(context.Background(), Event{Kind: Failure, Host: "nas", Error: "disk full"})
+	if err == nil || !strings.Contains(err.Error(), "telegram: server returned 400") || strings.Contains(err.Error(), "secret") {
+		t.Errorf("Expected a redacted Telegram error, got %v", err)
+	}
+	if len(*telegramPaths) != 1 || (*telegramPaths)[0] != "/bot123:secret/sendMessage" {
+		t.Errorf("Telegram received %v", *telegramPaths)
+	}
+	if len(*webhookBodies) != 2 {
+		t.Errorf("Webhook received %d events, want 2", len(*webhookBodies))
+	}
+}
+
+func TestTelegramPayload(t *testing.T) {
+	srv, _, bodies := recorder(t, http.StatusOK)
+	tg := &Telegram{BotToken: "1:x", ChatID: "@mediachannel", APIURL: srv.URL}
+	if err := tg.Send(context.Background(), Event{}, strings.Repeat("a", 5000)); err != nil {
+		t.Fatal(err)
+	}
+	body := (*bodies)[0]
+	if body["chat_id"] != "@mediachannel" || len([]rune(body["text"].(string))) != telegramLimit {
+		t.Errorf("Unexpected Telegram payload: chat_id %v, %d characters", body["chat_id"], len(body["text"].(string)))
+	}
+	if got := telegramChat("-100123"); got != int64(-100123) {
+		t.Errorf("telegramChat(-100123) = %#v, want a number", got)
+	}
+}
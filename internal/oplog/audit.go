@@ -0,0 +1,89 @@
+package oplog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Audit export formats
+const (
+	AuditCSV  = "csv"
+	AuditJSON = "json"
+)
+
+// auditLogs names the log each log file's entries are exported under
+var auditLogs = []struct{ name, file string }{
+	{"scan", ScanLog},
+	{"clean", OperationsLog},
+	{"rename", RenameLog},
+}
+
+// AuditEntry is a logged operation and the log it came from
+type AuditEntry struct {
+	Log string `json:"log"` // scan, clean or rename
+	Entry
+}
+
+// Audit returns every entry the logs in dir hold from since up to until,
+// rotated logs included, oldest first. A zero until means no end. Logs that
+// do not exist yet are skipped
+func Audit(dir string, since, until time.Time) ([]AuditEntry, error) {
+	var entries []AuditEntry
+	for _, log := range auditLogs {
+		path := filepath.Join(dir, log.file)
+		// Oldest rotated log first
+		for n := maxBackups; n >= 0; n-- {
+			file := path
+			if n > 0 {
+				file = backupPath(path, n)
+			}
+			if _, err := os.Stat(file); os.IsNotExist(err) {
+				continue
+			}
+			logged, err := Read(file)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range logged {
+				if entry.Timestamp.Before(since) || (!until.IsZero() && !entry.Timestamp.Before(until)) {
+					continue
+				}
+				entries = append(entries, AuditEntry{Log: log.name, Entry: entry})
+			}
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Timestamp.Before(entries[j].Timestamp) })
+	return entries, nil
+}
+
+// WriteAudit writes entries as CSV with a header row, or as a JSON array
+func WriteAudit(w io.Writer, entries []AuditEntry, format string) error {
+	switch format {
+	case AuditCSV:
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"timestamp", "log", "op", "old_path", "new_path", "size", "detail", "result", "error"})
+		for _, e := range entries {
+			cw.Write([]string{
+				e.Timestamp.Format(time.RFC3339), e.Log, e.Op, e.OldPath, e.NewPath,
+				strconv.FormatInt(e.Size, 10), e.Detail, e.Result, e.Error,
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	case AuditJSON:
+		if entries == nil {
+			entries = []AuditEntry{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	return fmt.Errorf("unknown audit format %q (want csv or json)", format)
+}
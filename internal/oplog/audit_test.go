@@ -0,0 +1,79 @@
+package oplog
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAudit(t *testing.T) {
+	dir := t.TempDir()
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 12, 0, 0, 0, time.UTC) }
+
+	// A rotated clean log holds the oldest operations
+	if err := Append(backupPath(filepath.Join(dir, OperationsLog), 1),
+		Entry{Timestamp: day(1), Op: "delete", OldPath: "/movies/old.mkv", Size: 100, Result: ResultOK}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(filepath.Join(dir, OperationsLog),
+		Entry{Timestamp: day(3), Op: "delete", OldPath: "/movies/Heat, 720p.mkv", Size: 2048, Result: ResultOK},
+		Entry{Timestamp: day(5), Op: "purge", OldPath: "/trash/Heat, 720p.mkv", Size: 2048, Result: ResultOK}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(filepath.Join(dir, ScanLog),
+		Entry{Timestamp: day(2), Op: "scan", OldPath: "/reports/scan.json", Size: 2048, Detail: "1 duplicate group(s)", Result: ResultOK}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(filepath.Join(dir, RenameLog),
+		Entry{Timestamp: day(4), Op: "rename", OldPath: "/tv/Show", NewPath: "/tv/Show (2020)", Result: ResultFailed, Error: "permission denied"}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Audit(dir, day(2), day(5))
+	if err != nil {
+		t.Fatalf("Audit() failed: %v", err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Log+" "+e.Op)
+	}
+	if strings.Join(got, ", ") != "scan scan, clean delete, rename rename" {
+		t.Errorf("Expected the period's entries from every log in time order, got %v", got)
+	}
+
+	all, err := Audit(dir, time.Time{}, time.Time{})
+	if err != nil || len(all) != 5 || all[0].OldPath != "/movies/old.mkv" {
+		t.Errorf("Expected rotated logs included, got %+v, %v", all, err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteAudit(&buf, entries, AuditCSV); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "timestamp,log,op,old_path") {
+		t.Fatalf("Expected a header and 3 rows, got:\n%s", buf.String())
+	}
+	if lines[2] != `2025-03-03T12:00:00Z,clean,delete,"/movies/Heat, 720p.mkv",,2048,,ok,` {
+		t.Errorf("Unexpected CSV row: %s", lines[2])
+	}
+
+	buf.Reset()
+	if err := WriteAudit(&buf, nil, AuditJSON); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded == nil {
+		t.Errorf("Expected an empty JSON array, got %s (%v)", buf.String(), err)
+	}
+	if err := WriteAudit(&buf, entries, "xml"); err == nil {
+		t.Error("Expected an unknown format rejected")
+	}
+
+	if _, err := Audit(filepath.Join(dir, "missing"), time.Time{}, time.Time{}); err != nil {
+		t.Errorf("Expected missing logs skipped, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package oplog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// DataDirEnv overrides the data directory, and the config's data_dir
+const DataDirEnv = "JELLYSINK_DATA_DIR"
+
+// TrashDirName is the trash folder in the data directory. A migration
+// leaves it behind rather than copy deleted media across filesystems
+const TrashDirName = "trash"
+
+var (
+	dataDirSetting string
+	dataDirMu      sync.RWMutex
+)
+
+// SetDataDir sets the data directory from the config's data_dir; empty
+// uses the default. A leading ~/ is relative to the invoking user's home
+func SetDataDir(dir string) {
+	dataDirMu.Lock()
+	defer dataDirMu.Unlock()
+	dataDirSetting = dir
+}
+
+// userHome returns the home of the invoking user, also under sudo
+func userHome() string {
+	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" {
+		return filepath.Join("/home", sudoUser)
+	}
+	home, _ := os.UserHomeDir()
+	return home
+}
+
+// expandHome resolves a leading ~/ against the invoking user's home
+func expandHome(dir string) string {
+	if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+		return filepath.Join(userHome(), rest)
+	}
+	return filepath.Clean(dir)
+}
+
+// LegacyDataDir is ~/.local/share/jellysink of the invoking user, where
+// data lived before it could be moved
+func LegacyDataDir() string {
+	return filepath.Join(userHome(), ".local/share/jellysink")
+}
+
+// Migration is what MigrateDataDir moved
+type Migration struct {
+	From, To string
+	Moved    []string // entries now in To
+	LeftOver []string // entries left in From (the trash, across filesystems)
+}
+
+// MigrateDataDir moves the contents of LegacyDataDir into DataDir the first
+// time DataDir points elsewhere: while the new directory is missing or
+// empty and the old one is not. Entries are renamed, or copied and then
+// removed across filesystems. Returns nil when there was nothing to move
+func MigrateDataDir() (*Migration, error) {
+	from, to := LegacyDataDir(), DataDir()
+	if from == to {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(from)
+	if err != nil || len(entries) == 0 {
+		return nil, nil
+	}
+	if existing, err := os.ReadDir(to); err == nil && len(existing) > 0 {
+		return nil, nil
+	}
+	if err := os.MkdirAll(to, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	m := &Migration{From: from, To: to}
+	for _, entry := range entries {
+		src, dst := filepath.Join(from, entry.Name()), filepath.Join(to, entry.Name())
+		err := os.Rename(src, dst)
+		if errors.Is(err, syscall.EXDEV) {
+			if entry.Name() == TrashDirName {
+				m.LeftOver = append(m.LeftOver, src)
+				continue
+			}
+			if err = copyTree(src, dst); err == nil {
+				err = os.RemoveAll(src)
+			}
+		}
+		if err != nil {
+			return m, fmt.Errorf("failed to move %s to %s: %w", src, to, err)
+		}
+		m.Moved = append(m.Moved, entry.Name())
+	}
+	if len(m.LeftOver) == 0 {
+		os.Remove(from)
+	}
+	return m, nil
+}
+
+// copyTree copies the file or folder src to dst, keeping modes and
+// modification times
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case !d.Type().IsRegular():
+			// Sockets and the like are recreated by whoever owns them
+			return nil
+		}
+		if err := copyFile(path, target, info.Mode().Perm()); err != nil {
+			return err
+		}
+		return os.Chtimes(target, info.ModTime(), info.ModTime())
+	})
+}
+
+// copyFile copies the regular file src to dst
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// String describes the migration for the log
+func (m *Migration) String() string {
+	msg := fmt.Sprintf("Moved jellysink data from %s to %s (%s)", m.From, m.To, strings.Join(m.Moved, ", "))
+	for _, left := range m.LeftOver {
+		msg += fmt.Sprintf("\nLeft %s in place, as it is on another filesystem; set cleaner.trash_dir to it to keep restoring from it", left)
+	}
+	return msg
+}
@@ -0,0 +1,101 @@
+package oplog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// dataDirEnv points the home and XDG variables at a temp dir
+func dataDirEnv(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SUDO_USER", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv(DataDirEnv, "")
+	t.Cleanup(func() { SetDataDir("") })
+	return home
+}
+
+func TestDataDir(t *testing.T) {
+	home := dataDirEnv(t)
+
+	if got, want := DataDir(), filepath.Join(home, ".local/share/jellysink"); got != want {
+		t.Errorf("Default DataDir() = %s, want %s", got, want)
+	}
+	t.Setenv("XDG_DATA_HOME", "/xdg/data")
+	if got := DataDir(); got != "/xdg/data/jellysink" {
+		t.Errorf("DataDir() with XDG_DATA_HOME = %s", got)
+	}
+	SetDataDir("~/state/jellysink")
+	if got, want := DataDir(), filepath.Join(home, "state/jellysink"); got != want {
+		t.Errorf("DataDir() with data_dir = %s, want %s", got, want)
+	}
+	t.Setenv(DataDirEnv, "/volume/jellysink/")
+	if got := DataDir(); got != "/volume/jellysink" {
+		t.Errorf("DataDir() with %s = %s", DataDirEnv, got)
+	}
+}
+
+func TestMigrateDataDir(t *testing.T) {
+	home := dataDirEnv(t)
+	legacy := LegacyDataDir()
+	files := map[string]string{
+		OperationsLog:              "{}\n",
+		"scan_results/report.json": "{}",
+		"journal/20260301.json":    "[]",
+	}
+	for rel, content := range files {
+		path := filepath.Join(legacy, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Nothing moves while the data directory is the legacy one
+	if m, err := MigrateDataDir(); m != nil || err != nil {
+		t.Fatalf("MigrateDataDir() in place = %v, %v", m, err)
+	}
+
+	target := filepath.Join(home, "volume", "jellysink")
+	SetDataDir(target)
+	m, err := MigrateDataDir()
+	if err != nil || m == nil || len(m.Moved) != 3 {
+		t.Fatalf("MigrateDataDir() = %v, %v", m, err)
+	}
+	for rel, content := range files {
+		if data, err := os.ReadFile(filepath.Join(target, rel)); err != nil || string(data) != content {
+			t.Errorf("Expected %s migrated, got %q, %v", rel, data, err)
+		}
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("Expected the emptied legacy directory removed, got %v", err)
+	}
+
+	// A data directory in use is never merged into
+	os.MkdirAll(legacy, 0755)
+	os.WriteFile(filepath.Join(legacy, RenameLog), nil, 0644)
+	if m, err := MigrateDataDir(); m != nil || err != nil {
+		t.Errorf("MigrateDataDir() into a used directory = %v, %v", m, err)
+	}
+}
+
+func TestCopyTree(t *testing.T) {
+	src, dst := t.TempDir(), filepath.Join(t.TempDir(), "copy")
+	os.MkdirAll(filepath.Join(src, "journal"), 0700)
+	os.WriteFile(filepath.Join(src, "journal", "a.json"), []byte("[]"), 0600)
+	os.Symlink("journal/a.json", filepath.Join(src, "latest"))
+
+	if err := copyTree(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(dst, "journal", "a.json"))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the file copied with its mode, got %v, %v", info, err)
+	}
+	if link, err := os.Readlink(filepath.Join(dst, "latest")); err != nil || link != "journal/a.json" {
+		t.Errorf("Expected the symlink copied, got %q, %v", link, err)
+	}
+}
@@ -0,0 +1,180 @@
+package oplog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Every change jellysink makes to a library is appended to a log as one
+// JSON object per line: cleans, undos and trash purges to operations.log,
+// show renames to rename.log, and scans to scan.log. A log that would grow past maxSize is first moved to
+// <name>.1, shifting older logs up and keeping maxBackups of them
+
+// Log file names in the jellysink data directory
+const (
+	OperationsLog = "operations.log"
+	RenameLog     = "rename.log"
+	ScanLog       = "scan.log"
+)
+
+// Entry results
+const (
+	ResultOK        = "ok"
+	ResultFailed    = "failed"
+	ResultDeferred  = "deferred"  // skipped because the file was in use
+	ResultSkipped   = "skipped"   // refused because the path is protected
+	ResultCancelled = "cancelled" // a scan stopped before it finished
+)
+
+var (
+	maxSize    int64 = 10 << 20
+	maxBackups       = 3
+)
+
+// appendMu serializes appends and rotation within this process
+var appendMu sync.Mutex
+
+// Entry is one logged operation
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Op        string    `json:"op"`       // delete, link, rename, move, version, delete-folder, undo, purge, scan, ...
+	OldPath   string    `json:"old_path"` // the report, for scans
+	NewPath   string    `json:"new_path,omitempty"`
+	Size      int64     `json:"size,omitempty"`   // bytes deleted or purged; reclaimable space found, for scans
+	Detail    string    `json:"detail,omitempty"` // what a scan found, or the operation an undo reverted
+	Result    string    `json:"result"`           // ok, failed, deferred, skipped or cancelled
+	Error     string    `json:"error,omitempty"`
+}
+
+// DataDir returns the jellysink data directory: JELLYSINK_DATA_DIR, else
+// the configured data_dir (SetDataDir), else $XDG_DATA_HOME/jellysink, else
+// LegacyDataDir
+func DataDir() string {
+	if dir := os.Getenv(DataDirEnv); dir != "" {
+		return expandHome(dir)
+	}
+	dataDirMu.RLock()
+	dir := dataDirSetting
+	dataDirMu.RUnlock()
+	if dir != "" {
+		return expandHome(dir)
+	}
+	// sudo keeps the caller's environment only in part, so the invoking
+	// user's default is used rather than a half-inherited XDG_DATA_HOME
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" && filepath.IsAbs(xdg) && os.Getenv("SUDO_USER") == "" {
+		return filepath.Join(xdg, "jellysink")
+	}
+	return LegacyDataDir()
+}
+
+// DefaultPath returns where the named log is kept, e.g. DefaultPath(RenameLog)
+func DefaultPath(name string) string {
+	return filepath.Join(DataDir(), name)
+}
+
+// Append adds entries to the log at path, rotating it first when full
+func Append(path string, entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var data []byte
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to encode log entry: %w", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	appendMu.Lock()
+	defer appendMu.Unlock()
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	if err := rotate(path, int64(len(data))); err != nil {
+		return err
+	}
+
+	// User-only permissions: the log lists every path in the library
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+	shareWithOwner(dir, path)
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// rotate moves the log aside when adding pending bytes would take it past
+// maxSize
+func rotate(path string, pending int64) error {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() == 0 || info.Size()+pending <= maxSize {
+		return nil
+	}
+
+	os.Remove(backupPath(path, maxBackups))
+	for i := maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(backupPath(path, i), backupPath(path, i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate %s: %w", path, err)
+		}
+	}
+	if err := os.Rename(path, backupPath(path, 1)); err != nil {
+		return fmt.Errorf("failed to rotate %s: %w", path, err)
+	}
+	return nil
+}
+
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+// Read returns the entries in the log at path, oldest first. Lines that do
+// not parse (e.g. from older releases) are skipped
+func Read(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var entries []Entry
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil || entry.Op == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// shareWithOwner hands a log created under sudo to the owner of its
+// directory so the user can still read it
+func shareWithOwner(dir, path string) {
+	if os.Geteuid() != 0 {
+		return
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok && stat.Uid != 0 {
+		os.Chown(path, int(stat.Uid), int(stat.Gid))
+	}
+}
@@ -0,0 +1,81 @@
+package oplog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendWritesJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", RenameLog)
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	err := Append(path,
+		Entry{Timestamp: at, Op: "rename", OldPath: "/tv/Show", NewPath: "/tv/Show (2020)", Result: ResultOK},
+		Entry{Timestamp: at, Op: "rename", OldPath: "/tv/Show/a.mkv", NewPath: "/tv/Show/b.mkv", Result: ResultFailed, Error: "permission denied"},
+	)
+	if err != nil {
+		t.Fatalf("Append() error: %v", err)
+	}
+	if err := Append(path, Entry{Timestamp: at, Op: "delete", OldPath: "/movies/x.mkv", Result: ResultOK}); err != nil {
+		t.Fatalf("Append() error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d:\n%s", len(lines), data)
+	}
+	want := `{"timestamp":"2025-03-01T12:00:00Z","op":"delete","old_path":"/movies/x.mkv","result":"ok"}`
+	if lines[2] != want {
+		t.Errorf("line = %s, want %s", lines[2], want)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("Expected a user-only log, got %v", info.Mode().Perm())
+	}
+
+	entries, err := Read(path)
+	if err != nil || len(entries) != 3 || entries[1].Error != "permission denied" {
+		t.Errorf("Read() = %+v, %v", entries, err)
+	}
+}
+
+func TestReadSkipsOldFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), OperationsLog)
+	os.WriteFile(path, []byte("2025-01-01T00:00:00Z|delete|/movies/x.mkv|\n"), 0600)
+	Append(path, Entry{Op: "delete", OldPath: "/movies/y.mkv", Result: ResultOK})
+
+	entries, err := Read(path)
+	if err != nil || len(entries) != 1 || entries[0].OldPath != "/movies/y.mkv" {
+		t.Errorf("Read() = %+v, %v", entries, err)
+	}
+}
+
+func TestAppendRotates(t *testing.T) {
+	originalSize, originalBackups := maxSize, maxBackups
+	maxSize, maxBackups = 200, 2
+	defer func() { maxSize, maxBackups = originalSize, originalBackups }()
+
+	path := filepath.Join(t.TempDir(), OperationsLog)
+	for i := 0; i < 10; i++ {
+		entry := Entry{Op: "delete", OldPath: "/movies/" + strings.Repeat("x", 60) + ".mkv", Result: ResultOK}
+		if err := Append(path, entry); err != nil {
+			t.Fatalf("Append() error: %v", err)
+		}
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("Expected %s: %v", filepath.Base(name), err)
+		}
+		if info.Size() > maxSize {
+			t.Errorf("%s is %d bytes, over the %d limit", filepath.Base(name), info.Size(), maxSize)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("Expected only maxBackups old logs kept")
+	}
+}
@@ -0,0 +1,72 @@
+package reporter
+
+import (
+	"fmt"
+	"time"
+)
+
+// CleanSummary records the outcome of a real (non dry-run) clean of a report
+type CleanSummary struct {
+	CleanedAt         time.Time
+	DuplicatesDeleted int
+	ComplianceFixed   int // files renamed or moved
+	SpaceFreed        int64
+	Errors            []string
+	Deferred          []string `json:",omitempty"` // paths left alone because they were in use
+	Importing         []string `json:",omitempty"` // paths left alone while an import wrote into their folder
+	Protected         []string `json:",omitempty"` // paths left alone because they are under a protected path
+	VersionsKept      int      `json:",omitempty"` // copies renamed into Jellyfin's multi-version layout
+	FoldersRemoved    int      `json:",omitempty"` // orphaned show/season folders deleted
+	ArtifactsRemoved  int      `json:",omitempty"` // leftover junk files and folders deleted
+}
+
+// NewCleanSummary builds a summary timestamped now from cleaner results
+func NewCleanSummary(deleted, fixed int, freed int64, errs []error) CleanSummary {
+	summary := CleanSummary{
+		CleanedAt:         time.Now(),
+		DuplicatesDeleted: deleted,
+		ComplianceFixed:   fixed,
+		SpaceFreed:        freed,
+	}
+	for _, err := range errs {
+		summary.Errors = append(summary.Errors, err.Error())
+	}
+	return summary
+}
+
+// Banner returns the one-line "CLEANED on <date>" notice shown with the report
+func (s CleanSummary) Banner() string {
+	banner := fmt.Sprintf("CLEANED on %s: %d deleted, %d renamed, %s freed, %d errors",
+		s.CleanedAt.Format("2006-01-02 15:04"), s.DuplicatesDeleted, s.ComplianceFixed,
+		formatBytes(s.SpaceFreed), len(s.Errors))
+	if s.VersionsKept > 0 {
+		banner += fmt.Sprintf(", %d kept as versions", s.VersionsKept)
+	}
+	if s.FoldersRemoved > 0 {
+		banner += fmt.Sprintf(", %d orphaned folders removed", s.FoldersRemoved)
+	}
+	if s.ArtifactsRemoved > 0 {
+		banner += fmt.Sprintf(", %d artifacts removed", s.ArtifactsRemoved)
+	}
+	if len(s.Deferred) > 0 {
+		banner += fmt.Sprintf(", %d deferred (in use, clean again with --force)", len(s.Deferred))
+	}
+	if len(s.Importing) > 0 {
+		banner += fmt.Sprintf(", %d deferred (import in progress, left for the next scan)", len(s.Importing))
+	}
+	if len(s.Protected) > 0 {
+		banner += fmt.Sprintf(", %d skipped (protected)", len(s.Protected))
+	}
+	return banner
+}
+
+// MarkCleaned writes summary into the JSON report at path
+// The file is replaced atomically so a crash never leaves a truncated report
+func MarkCleaned(path string, summary CleanSummary) error {
+	report, err := ReadReport(path)
+	if err != nil {
+		return err
+	}
+	report.Cleaned = &summary
+	return WriteReport(path, report)
+}
@@ -0,0 +1,77 @@
+package reporter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarkCleaned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	original := Report{
+		Timestamp:          time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		LibraryType:        "movies",
+		LibraryPaths:       []string{"/media/movies"},
+		TotalFilesToDelete: 3,
+	}
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "Cleaned") {
+		t.Error("Expected uncleaned reports to omit the Cleaned field")
+	}
+
+	summary := NewCleanSummary(3, 2, 3*1024*1024*1024, []error{errors.New("permission denied")})
+	if err := MarkCleaned(path, summary); err != nil {
+		t.Fatalf("MarkCleaned failed: %v", err)
+	}
+
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Report
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Cleaned == nil {
+		t.Fatal("Expected clean summary in report")
+	}
+	if got.Cleaned.DuplicatesDeleted != 3 || got.Cleaned.ComplianceFixed != 2 || len(got.Cleaned.Errors) != 1 {
+		t.Errorf("Unexpected summary: %+v", got.Cleaned)
+	}
+	if got.TotalFilesToDelete != 3 || got.LibraryPaths[0] != "/media/movies" {
+		t.Errorf("Report contents changed: %+v", got)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Expected temp file to be renamed away")
+	}
+
+	banner := got.Cleaned.Banner()
+	if !strings.HasPrefix(banner, "CLEANED on ") || !strings.Contains(banner, "3 deleted, 2 renamed") || !strings.Contains(banner, "1 errors") {
+		t.Errorf("Unexpected banner: %s", banner)
+	}
+	if strings.Contains(banner, "deferred") {
+		t.Errorf("Expected no deferred note without deferred files: %s", banner)
+	}
+	summary.Deferred = []string{"/media/movies/Heat (1995)/Heat (1995).mkv"}
+	if banner := summary.Banner(); !strings.Contains(banner, "1 deferred (in use") {
+		t.Errorf("Expected deferred count in banner: %s", banner)
+	}
+	summary.Protected = []string{"/media/movies/Favourites/Alien (1979)/Alien (1979).mkv"}
+	if banner := summary.Banner(); !strings.Contains(banner, "1 skipped (protected)") {
+		t.Errorf("Expected protected count in banner: %s", banner)
+	}
+
+	if err := MarkCleaned(filepath.Join(t.TempDir(), "missing.json"), summary); err == nil {
+		t.Error("Expected error for missing report")
+	}
+}
@@ -0,0 +1,83 @@
+package reporter
+
+import "github.com/Nomadcxx/jellysink/internal/scanner"
+
+// ConfirmDuplicates keeps only the duplicate groups of report that appear
+// identically in every one of earlier: same stable ID, same files and the
+// same keeper (and, for movies, strategy). A group that changed between
+// scans may come from a parsing glitch, so it waits for the next scan.
+// Returns the narrowed report and the number of groups held back
+func ConfirmDuplicates(report Report, earlier []Report) (Report, int) {
+	held := 0
+
+	seenMovies := make([]map[string]bool, len(earlier))
+	seenTV := make([]map[string]bool, len(earlier))
+	for i, previous := range earlier {
+		seenMovies[i] = make(map[string]bool, len(previous.MovieDuplicates))
+		for _, dup := range previous.MovieDuplicates {
+			seenMovies[i][movieSignature(dup)] = true
+		}
+		seenTV[i] = make(map[string]bool, len(previous.TVDuplicates))
+		for _, dup := range previous.TVDuplicates {
+			seenTV[i][tvSignature(dup)] = true
+		}
+	}
+	seenInAll := func(seen []map[string]bool, signature string) bool {
+		for _, s := range seen {
+			if !s[signature] {
+				return false
+			}
+		}
+		return true
+	}
+
+	var movies []scanner.MovieDuplicate
+	for _, dup := range report.MovieDuplicates {
+		if seenInAll(seenMovies, movieSignature(dup)) {
+			movies = append(movies, dup)
+		} else {
+			held++
+		}
+	}
+	var tv []scanner.TVDuplicate
+	for _, dup := range report.TVDuplicates {
+		if seenInAll(seenTV, tvSignature(dup)) {
+			tv = append(tv, dup)
+		} else {
+			held++
+		}
+	}
+
+	report.MovieDuplicates, report.TVDuplicates = movies, tv
+	report.RecountTotals()
+	return report, held
+}
+
+// movieSignature identifies a movie duplicate group and what a clean
+// would do to it
+func movieSignature(dup scanner.MovieDuplicate) string {
+	id := dup.ID
+	if id == "" {
+		id = scanner.MovieDuplicateID(dup)
+	}
+	keeper := ""
+	if len(dup.Files) > 0 {
+		keeper = dup.Files[0].Path
+	}
+	return id + "\x00" + string(dup.Strategy) + "\x00" + keeper + "\x00" +
+		groupKey(len(dup.Files), func(j int) string { return dup.Files[j].Path })
+}
+
+// tvSignature identifies a TV duplicate group and its keeper
+func tvSignature(dup scanner.TVDuplicate) string {
+	id := dup.ID
+	if id == "" {
+		id = scanner.TVDuplicateID(dup)
+	}
+	keeper := ""
+	if len(dup.Files) > 0 {
+		keeper = dup.Files[0].Path
+	}
+	return id + "\x00" + keeper + "\x00" +
+		groupKey(len(dup.Files), func(j int) string { return dup.Files[j].Path })
+}
@@ -0,0 +1,102 @@
+package reporter
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SaveDuplicateDecisions writes the keeper, strategy, exclusion and
+// companion merge of each duplicate group in report into the JSON report at
+// path, so a later `jellysink clean` of that file honors them. report may hold a subset of
+// the file's groups (e.g. narrowed by --tag); groups are matched by their
+// files, and one missing from the file is an error
+func SaveDuplicateDecisions(path string, report Report) error {
+	saved, err := ReadReport(path)
+	if err != nil {
+		return err
+	}
+
+	movies := make(map[string]int, len(saved.MovieDuplicates))
+	for i, dup := range saved.MovieDuplicates {
+		movies[groupKey(len(dup.Files), func(j int) string { return dup.Files[j].Path })] = i
+	}
+	for _, dup := range report.MovieDuplicates {
+		i, ok := movies[groupKey(len(dup.Files), func(j int) string { return dup.Files[j].Path })]
+		if !ok {
+			return fmt.Errorf("duplicate group %s is not in %s", filepath.Dir(dup.Files[0].Path), path)
+		}
+		target := &saved.MovieDuplicates[i]
+		target.Files, target.Strategy, target.Excluded = dup.Files, dup.Strategy, dup.Excluded
+		target.MergeCompanions = dup.MergeCompanions
+	}
+
+	tv := make(map[string]int, len(saved.TVDuplicates))
+	for i, dup := range saved.TVDuplicates {
+		tv[groupKey(len(dup.Files), func(j int) string { return dup.Files[j].Path })] = i
+	}
+	for _, dup := range report.TVDuplicates {
+		i, ok := tv[groupKey(len(dup.Files), func(j int) string { return dup.Files[j].Path })]
+		if !ok {
+			return fmt.Errorf("duplicate group %s %s is not in %s", dup.ShowName, dup.EpisodeLabel(), path)
+		}
+		target := &saved.TVDuplicates[i]
+		target.Files, target.Excluded, target.MergeCompanions = dup.Files, dup.Excluded, dup.MergeCompanions
+	}
+
+	saved.RecountTotals()
+	return WriteReport(path, saved)
+}
+
+// SaveMovieTitleDecisions writes the movie title review of report into the
+// JSON report at path: each reviewed movie's decision, and its compliance
+// issue as the decision left it, so a later `jellysink clean` of that file
+// renames it to the chosen title or leaves it alone
+func SaveMovieTitleDecisions(path string, report Report) error {
+	saved, err := ReadReport(path)
+	if err != nil {
+		return err
+	}
+
+	movies := make(map[string]int, len(saved.AmbiguousMovies))
+	for i, movie := range saved.AmbiguousMovies {
+		movies[movie.Path] = i
+	}
+	issues := make(map[string]int, len(saved.ComplianceIssues))
+	for i, issue := range saved.ComplianceIssues {
+		if issue.Type == "movie" {
+			issues[issue.Path] = i
+		}
+	}
+	reviewed := make(map[string]bool, len(report.AmbiguousMovies))
+	for _, movie := range report.AmbiguousMovies {
+		i, ok := movies[movie.Path]
+		if !ok {
+			return fmt.Errorf("movie %s is not in %s", movie.Path, path)
+		}
+		target := saved.AmbiguousMovies[i]
+		target.UserDecision, target.CustomTitle = movie.UserDecision, movie.CustomTitle
+		reviewed[movie.Path] = true
+	}
+	for _, issue := range report.ComplianceIssues {
+		if issue.Type != "movie" || !reviewed[issue.Path] {
+			continue
+		}
+		if i, ok := issues[issue.Path]; ok {
+			saved.ComplianceIssues[i] = issue
+		}
+	}
+
+	return WriteReport(path, saved)
+}
+
+// groupKey identifies a duplicate group by its files, whatever their order
+func groupKey(n int, path func(int) string) string {
+	paths := make([]string, n)
+	for j := range paths {
+		paths[j] = path(j)
+	}
+	sort.Strings(paths)
+	return strings.Join(paths, "\x00")
+}
@@ -0,0 +1,374 @@
+package reporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// Finding kinds compared by DiffReports
+const (
+	FindingDuplicate    = "duplicate"     // movie or TV duplicate group
+	FindingCompliance   = "compliance"    // naming issue
+	FindingOrphanFolder = "orphan-folder" // show/season folder without videos
+	FindingArtifact     = "artifact"      // leftover junk
+)
+
+// DiffFinding is one finding of a compared report
+type DiffFinding struct {
+	Kind  string // one of the Finding* kinds
+	ID    string // stable finding ID, or the path for folders and artifacts
+	Title string // what the finding is about, e.g. "Heat (1995)" or the problem
+	Path  string // the keeper, issue or folder path
+	Size  int64  `json:",omitempty"` // bytes a clean would free
+}
+
+// DiffCount counts one kind of finding across two reports
+type DiffCount struct {
+	New      int // only in the newer report
+	Resolved int // only in the older report
+	Pending  int // in both
+}
+
+// ReportChanges summarizes a ReportDiff. Scans store it in their report,
+// compared against the previous report of the same library
+type ReportChanges struct {
+	Previous          string    // report compared against
+	PreviousTime      time.Time // when that report was scanned
+	Duplicates        DiffCount
+	Compliance        DiffCount
+	OrphanFolders     DiffCount
+	Artifacts         DiffCount
+	SpaceToFreeBefore int64 // reclaimable space in the previous report
+	SpaceFreed        int64 // freed by cleaning the previous report
+}
+
+// ReportDiff compares an older and a newer scan report
+type ReportDiff struct {
+	Old, New          string    // report paths
+	OldTime, NewTime  time.Time // when the reports were scanned
+	Added             []DiffFinding
+	Resolved          []DiffFinding
+	Pending           []DiffFinding
+	SpaceToFreeBefore int64
+	SpaceToFreeAfter  int64
+	SpaceFreed        int64 // freed by cleaning the older report
+}
+
+// reportFindings lists the findings of report that a diff compares
+func reportFindings(report Report) []DiffFinding {
+	var findings []DiffFinding
+	for _, dup := range report.MovieDuplicates {
+		id := dup.ID
+		if id == "" {
+			id = scanner.MovieDuplicateID(dup)
+		}
+		title := dup.NormalizedName
+		if dup.Year != "" {
+			title += " (" + dup.Year + ")"
+		}
+		finding := DiffFinding{Kind: FindingDuplicate, ID: id, Title: title}
+		for i, file := range dup.Files {
+			if i == 0 {
+				finding.Path = file.Path
+			} else {
+				finding.Size += file.Size
+			}
+		}
+		findings = append(findings, finding)
+	}
+	for _, dup := range report.TVDuplicates {
+		id := dup.ID
+		if id == "" {
+			id = scanner.TVDuplicateID(dup)
+		}
+		finding := DiffFinding{Kind: FindingDuplicate, ID: id, Title: dup.ShowName + " " + dup.EpisodeLabel()}
+		for i, file := range dup.Files {
+			if i == 0 {
+				finding.Path = file.Path
+			} else {
+				finding.Size += file.Size
+			}
+		}
+		findings = append(findings, finding)
+	}
+	for _, issue := range report.ComplianceIssues {
+		id := issue.ID
+		if id == "" {
+			id = scanner.ComplianceIssueID(issue)
+		}
+		findings = append(findings, DiffFinding{Kind: FindingCompliance, ID: id, Title: issue.Problem, Path: issue.Path})
+	}
+	for _, orphan := range report.OrphanFolders {
+		findings = append(findings, DiffFinding{Kind: FindingOrphanFolder, ID: orphan.Path, Title: orphan.Kind + " folder without videos", Path: orphan.Path, Size: orphan.Size})
+	}
+	for _, artifact := range report.Artifacts {
+		findings = append(findings, DiffFinding{Kind: FindingArtifact, ID: artifact.Path, Title: artifact.Kind, Path: artifact.Path, Size: artifact.Size})
+	}
+	return findings
+}
+
+// DiffReports compares the findings of an older and a newer report of the
+// same library. Findings are matched by their stable IDs, so a duplicate
+// group that gained or lost a copy is still pending
+func DiffReports(older, newer Report) ReportDiff {
+	diff := ReportDiff{
+		OldTime:           older.Timestamp,
+		NewTime:           newer.Timestamp,
+		SpaceToFreeBefore: older.SpaceToFree,
+		SpaceToFreeAfter:  newer.SpaceToFree,
+	}
+	if older.Cleaned != nil {
+		diff.SpaceFreed = older.Cleaned.SpaceFreed
+	}
+
+	before := make(map[string]bool)
+	for _, finding := range reportFindings(older) {
+		before[finding.Kind+"\x00"+finding.ID] = true
+	}
+	after := make(map[string]bool)
+	for _, finding := range reportFindings(newer) {
+		key := finding.Kind + "\x00" + finding.ID
+		after[key] = true
+		if before[key] {
+			diff.Pending = append(diff.Pending, finding)
+		} else {
+			diff.Added = append(diff.Added, finding)
+		}
+	}
+	for _, finding := range reportFindings(older) {
+		if !after[finding.Kind+"\x00"+finding.ID] {
+			diff.Resolved = append(diff.Resolved, finding)
+		}
+	}
+	return diff
+}
+
+// Changes summarizes the diff, counting each kind of finding
+func (d ReportDiff) Changes() ReportChanges {
+	changes := ReportChanges{
+		Previous:          d.Old,
+		PreviousTime:      d.OldTime,
+		SpaceToFreeBefore: d.SpaceToFreeBefore,
+		SpaceFreed:        d.SpaceFreed,
+	}
+	for _, finding := range d.Added {
+		if c := changes.count(finding.Kind); c != nil {
+			c.New++
+		}
+	}
+	for _, finding := range d.Resolved {
+		if c := changes.count(finding.Kind); c != nil {
+			c.Resolved++
+		}
+	}
+	for _, finding := range d.Pending {
+		if c := changes.count(finding.Kind); c != nil {
+			c.Pending++
+		}
+	}
+	return changes
+}
+
+// count returns the counter for a finding kind
+func (c *ReportChanges) count(kind string) *DiffCount {
+	switch kind {
+	case FindingDuplicate:
+		return &c.Duplicates
+	case FindingCompliance:
+		return &c.Compliance
+	case FindingOrphanFolder:
+		return &c.OrphanFolders
+	case FindingArtifact:
+		return &c.Artifacts
+	}
+	return nil
+}
+
+// Headline sums the changes up in one line
+func (c ReportChanges) Headline() string {
+	return fmt.Sprintf("Since %s: %d new duplicates, %d resolved; %d new naming issues, %d fixed; %s freed",
+		c.PreviousTime.Format("2006-01-02 15:04"),
+		c.Duplicates.New, c.Duplicates.Resolved, c.Compliance.New, c.Compliance.Resolved, formatBytes(c.SpaceFreed))
+}
+
+// WriteDiff writes diff as text: a table of counts, the reclaimable space
+// and the new and resolved findings; pending findings are listed too when
+// pending is set
+func WriteDiff(w io.Writer, diff ReportDiff, pending bool) error {
+	bw := bufio.NewWriter(w)
+	changes := diff.Changes()
+
+	fmt.Fprintln(bw, "REPORT DIFF")
+	fmt.Fprintf(bw, "Before: %s  %s\n", diff.OldTime.Format("2006-01-02 15:04"), diff.Old)
+	fmt.Fprintf(bw, "After:  %s  %s\n\n", diff.NewTime.Format("2006-01-02 15:04"), diff.New)
+
+	fmt.Fprintf(bw, "%-18s %8s %9s %8s\n", "", "New", "Resolved", "Pending")
+	for _, row := range []struct {
+		label string
+		count DiffCount
+	}{
+		{"Duplicates", changes.Duplicates},
+		{"Naming issues", changes.Compliance},
+		{"Orphaned folders", changes.OrphanFolders},
+		{"Artifacts", changes.Artifacts},
+	} {
+		fmt.Fprintf(bw, "%-18s %8d %9d %8d\n", row.label, row.count.New, row.count.Resolved, row.count.Pending)
+	}
+	fmt.Fprintf(bw, "\nReclaimable space: %s -> %s\n", formatBytes(diff.SpaceToFreeBefore), formatBytes(diff.SpaceToFreeAfter))
+	if diff.SpaceFreed > 0 {
+		fmt.Fprintf(bw, "Freed by cleaning the earlier report: %s\n", formatBytes(diff.SpaceFreed))
+	}
+
+	writeFindings(bw, "NEW", diff.Added)
+	writeFindings(bw, "RESOLVED", diff.Resolved)
+	if pending {
+		writeFindings(bw, "STILL PENDING", diff.Pending)
+	} else if len(diff.Pending) > 0 {
+		fmt.Fprintf(bw, "\n%d findings still pending (list them with --pending)\n", len(diff.Pending))
+	}
+	return bw.Flush()
+}
+
+// WriteDiffJSON writes diff as indented JSON
+func WriteDiffJSON(w io.Writer, diff ReportDiff) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(diff)
+}
+
+// LoadDiff reads the reports at olderPath and newerPath and compares them.
+// With olderPath empty the newer report is compared with the report its
+// scan recorded as the previous one
+func LoadDiff(olderPath, newerPath string) (ReportDiff, error) {
+	newer, err := ReadReport(newerPath)
+	if err != nil {
+		return ReportDiff{}, err
+	}
+	if olderPath == "" {
+		if newer.Changes == nil || newer.Changes.Previous == "" {
+			return ReportDiff{}, fmt.Errorf("%s has no previous report recorded; name one to compare with", newerPath)
+		}
+		olderPath = newer.Changes.Previous
+	}
+	older, err := ReadReport(olderPath)
+	if err != nil {
+		return ReportDiff{}, err
+	}
+	if older.Timestamp.After(newer.Timestamp) {
+		older, newer = newer, older
+		olderPath, newerPath = newerPath, olderPath
+	}
+
+	diff := DiffReports(older, newer)
+	diff.Old, diff.New = olderPath, newerPath
+	return diff, nil
+}
+
+// writeFindings lists findings under a heading, grouped by kind
+func writeFindings(w io.Writer, heading string, findings []DiffFinding) {
+	if len(findings) == 0 {
+		return
+	}
+	sorted := append([]DiffFinding(nil), findings...)
+	order := map[string]int{FindingDuplicate: 0, FindingCompliance: 1, FindingOrphanFolder: 2, FindingArtifact: 3}
+	sort.SliceStable(sorted, func(i, j int) bool { return order[sorted[i].Kind] < order[sorted[j].Kind] })
+
+	fmt.Fprintf(w, "\n%s (%d)\n%s\n", heading, len(sorted), strings.Repeat("=", len(heading)))
+	for _, finding := range sorted {
+		line := fmt.Sprintf("  [%s] %s", finding.Kind, finding.Title)
+		if finding.Size > 0 {
+			line += fmt.Sprintf(" (%s)", formatBytes(finding.Size))
+		}
+		fmt.Fprintln(w, line)
+		if finding.Path != "" {
+			fmt.Fprintf(w, "          %s\n", finding.Path)
+		}
+	}
+}
+
+// DiffPath returns the text diff file written alongside the report at path
+func DiffPath(reportPath string) string {
+	return strings.TrimSuffix(reportPath, ".json") + "_diff.txt"
+}
+
+// PreviousReport finds the newest report in dir, other than except, of the
+// same library as report and neither partial nor simulated, to diff
+// report against. It returns "" when there is none
+func PreviousReport(dir, except string, report Report) (string, Report, error) {
+	paths, reports, err := PreviousReports(dir, except, report, 1)
+	if err != nil || len(paths) == 0 {
+		return "", Report{}, err
+	}
+	return paths[0], reports[0], nil
+}
+
+// PreviousReports returns up to n reports in dir, newest first, that were
+// scanned before report, cover the same library and are neither partial
+// nor simulated. except is left out
+func PreviousReports(dir, except string, report Report, n int) ([]string, []Report, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to read report directory: %w", err)
+	}
+
+	type candidate struct {
+		path    string
+		modTime time.Time
+	}
+	var candidates []candidate
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") || path == except {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			candidates = append(candidates, candidate{path, info.ModTime()})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].modTime.After(candidates[j].modTime) })
+
+	var paths []string
+	var reports []Report
+	for _, c := range candidates {
+		if len(paths) == n {
+			break
+		}
+		previous, err := ReadReport(c.path)
+		if err != nil || previous.Partial != nil || previous.Simulated || !previous.Timestamp.Before(report.Timestamp) {
+			continue
+		}
+		if previous.LibraryType == report.LibraryType && sameLibraryPaths(previous.LibraryPaths, report.LibraryPaths) {
+			paths = append(paths, c.path)
+			reports = append(reports, previous)
+		}
+	}
+	return paths, reports, nil
+}
+
+// sameLibraryPaths reports whether two reports cover the same library paths
+func sameLibraryPaths(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
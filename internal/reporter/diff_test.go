@@ -0,0 +1,150 @@
+package reporter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// diffTestReports returns two scans of the same library: Firefly's duplicate
+// and the padded season folder were fixed in between, and a new movie
+// duplicate and an artifact turned up
+func diffTestReports() (Report, Report) {
+	older := planTestReport()
+	older.LibraryType = "movies"
+	older.LibraryPaths = []string{"/movies", "/tv"}
+	older.Timestamp = time.Date(2026, 3, 1, 4, 0, 0, 0, time.UTC)
+	older.Cleaned = &CleanSummary{SpaceFreed: 800}
+
+	newer := planTestReport()
+	newer.LibraryType = "movies"
+	newer.LibraryPaths = []string{"/tv", "/movies"}
+	newer.Timestamp = time.Date(2026, 3, 8, 4, 0, 0, 0, time.UTC)
+	newer.TVDuplicates = nil
+	newer.ComplianceIssues = newer.ComplianceIssues[:1]
+	newer.MovieDuplicates = append(newer.MovieDuplicates, scanner.MovieDuplicate{
+		NormalizedName: "alien",
+		Year:           "1979",
+		Files: []scanner.MovieFile{
+			{Path: "/movies/Alien (1979)/Alien (1979).mkv", Size: 3000},
+			{Path: "/movies/alien.1979.mkv", Size: 1500},
+		},
+	})
+	newer.Artifacts = []scanner.Artifact{{Path: "/movies/Alien (1979)/alien.part", Kind: scanner.ArtifactPartialDownload, Size: 10}}
+	// The Heat group lost a copy but is still the same finding
+	newer.MovieDuplicates[0].Files = newer.MovieDuplicates[0].Files[:2]
+	newer.RecountTotals()
+	return older, newer
+}
+
+func TestDiffReports(t *testing.T) {
+	older, newer := diffTestReports()
+	diff := DiffReports(older, newer)
+
+	changes := diff.Changes()
+	if changes.Duplicates != (DiffCount{New: 1, Resolved: 1, Pending: 1}) {
+		t.Errorf("Duplicates = %+v, want 1 new, 1 resolved, 1 pending", changes.Duplicates)
+	}
+	if changes.Compliance != (DiffCount{Resolved: 2, Pending: 1}) {
+		t.Errorf("Compliance = %+v, want 2 resolved, 1 pending", changes.Compliance)
+	}
+	if changes.Artifacts != (DiffCount{New: 1}) {
+		t.Errorf("Artifacts = %+v, want 1 new", changes.Artifacts)
+	}
+	if diff.SpaceFreed != 800 || diff.SpaceToFreeBefore != 3300 || diff.SpaceToFreeAfter != newer.SpaceToFree {
+		t.Errorf("Space = %d freed, %d -> %d", diff.SpaceFreed, diff.SpaceToFreeBefore, diff.SpaceToFreeAfter)
+	}
+
+	headline := changes.Headline()
+	if !strings.Contains(headline, "Since 2026-03-01 04:00: 1 new duplicates, 1 resolved; 0 new naming issues, 2 fixed") {
+		t.Errorf("Headline() = %q", headline)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDiff(&buf, diff, false); err != nil {
+		t.Fatalf("WriteDiff failed: %v", err)
+	}
+	text := buf.String()
+	for _, want := range []string{
+		"[duplicate] alien (1979) (1.46 KB)",
+		"[duplicate] firefly S01E01",
+		"[compliance] Season folder not padded",
+		"[artifact] " + scanner.ArtifactPartialDownload,
+		"2 findings still pending (list them with --pending)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Diff missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "STILL PENDING") {
+		t.Error("Expected pending findings to be listed only with --pending")
+	}
+
+	buf.Reset()
+	if err := WriteDiff(&buf, diff, true); err != nil {
+		t.Fatalf("WriteDiff failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "STILL PENDING (2)") {
+		t.Errorf("Expected the pending findings listed:\n%s", buf.String())
+	}
+}
+
+func TestLoadDiff(t *testing.T) {
+	dir := t.TempDir()
+	older, newer := diffTestReports()
+	olderPath := filepath.Join(dir, "movies_20260301.json")
+	newerPath := filepath.Join(dir, "movies_20260308.json")
+	if err := WriteReport(olderPath, older); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reports of other libraries, partial and simulated scans are skipped
+	other := older
+	other.LibraryPaths = []string{"/elsewhere"}
+	WriteReport(filepath.Join(dir, "movies_other.json"), other)
+	simulated := older
+	simulated.Simulated = true
+	WriteReport(filepath.Join(dir, "movies_simulated.json"), simulated)
+
+	previousPath, _, err := PreviousReport(dir, newerPath, newer)
+	if err != nil || previousPath != olderPath {
+		t.Fatalf("PreviousReport() = %q, %v, want %q", previousPath, err, olderPath)
+	}
+
+	if _, err := LoadDiff("", olderPath); err == nil {
+		t.Error("Expected an error diffing a report with no previous report recorded")
+	}
+
+	diff := DiffReports(older, newer)
+	changes := diff.Changes()
+	changes.Previous = olderPath
+	newer.Changes = &changes
+	if err := WriteReport(newerPath, newer); err != nil {
+		t.Fatal(err)
+	}
+
+	// One report diffs against its previous scan; two are ordered by time
+	for _, args := range [][2]string{{"", newerPath}, {newerPath, olderPath}} {
+		got, err := LoadDiff(args[0], args[1])
+		if err != nil {
+			t.Fatalf("LoadDiff(%q, %q) failed: %v", args[0], args[1], err)
+		}
+		if got.Old != olderPath || got.New != newerPath || len(got.Added) != 2 || len(got.Resolved) != 3 {
+			t.Errorf("LoadDiff(%q, %q) = %s -> %s, %d added, %d resolved", args[0], args[1], got.Old, got.New, len(got.Added), len(got.Resolved))
+		}
+	}
+
+	// Removing the report removes its diff too
+	os.WriteFile(DiffPath(newerPath), []byte("diff"), 0644)
+	if err := RemoveReport(newerPath); err != nil {
+		t.Fatalf("RemoveReport failed: %v", err)
+	}
+	if _, err := os.Stat(DiffPath(newerPath)); !os.IsNotExist(err) {
+		t.Error("Expected RemoveReport to remove the diff file")
+	}
+}
@@ -0,0 +1,306 @@
+package reporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// Export formats written by Export
+const (
+	ExportHTML     = "html"
+	ExportMarkdown = "md"
+	ExportCSV      = "csv"
+)
+
+// exportFile is one file of a duplicate group, flattened for export
+type exportFile struct {
+	GroupID    string
+	Group      string // "Heat (1995)" or "Firefly S01E01"
+	Action     string // KEEP, DELETE or VERSION
+	Path       string
+	Resolution string
+	Size       int64
+	Index      int           // position in the group; 0 is the keeper
+	Duration   time.Duration // from ffprobe, 0 when not probed
+	Thumbnail  template.URL  // preview image, set by WriteReportHTMLThumbnails
+}
+
+// probedDuration returns how long ffprobe found a file to run, or 0
+func probedDuration(probe *scanner.MediaInfo) time.Duration {
+	if probe == nil {
+		return 0
+	}
+	return probe.Duration
+}
+
+// exportFiles flattens the duplicate groups of report, keepers first
+func exportFiles(report Report) []exportFile {
+	var files []exportFile
+	for _, dup := range report.MovieDuplicates {
+		id := dup.ID
+		if id == "" {
+			id = scanner.MovieDuplicateID(dup)
+		}
+		group := dup.NormalizedName
+		if dup.Year != "" {
+			group += " (" + dup.Year + ")"
+		}
+		for i, file := range dup.Files {
+			action := "DELETE"
+			switch {
+			case i == 0 || dup.Excluded:
+				action = "KEEP"
+			case dup.KeepsAllVersions():
+				action = "VERSION"
+			}
+			files = append(files, exportFile{GroupID: id, Group: group, Action: action, Path: file.Path,
+				Resolution: file.Resolution, Size: file.Size, Index: i, Duration: probedDuration(file.Probe)})
+		}
+	}
+	for _, dup := range report.TVDuplicates {
+		id := dup.ID
+		if id == "" {
+			id = scanner.TVDuplicateID(dup)
+		}
+		group := dup.ShowName + " " + dup.EpisodeLabel()
+		for i, file := range dup.Files {
+			action := "DELETE"
+			if i == 0 || dup.Excluded {
+				action = "KEEP"
+			}
+			files = append(files, exportFile{GroupID: id, Group: group, Action: action, Path: file.Path,
+				Resolution: file.Resolution, Size: file.Size, Index: i, Duration: probedDuration(file.Probe)})
+		}
+	}
+	return files
+}
+
+// Export writes report as a self-contained HTML page, Markdown or CSV, for
+// sharing or attaching to a notification
+func Export(w io.Writer, report Report, format string) error {
+	switch strings.ToLower(format) {
+	case ExportHTML:
+		return WriteReportHTML(w, report)
+	case ExportMarkdown, "markdown":
+		return WriteReportMarkdown(w, report)
+	case ExportCSV:
+		return WriteReportCSV(w, report)
+	default:
+		return fmt.Errorf("invalid export format: %s (must be html, md, or csv)", format)
+	}
+}
+
+// WriteReportCSV writes one row per duplicate file and per compliance
+// issue; the kind column tells them apart
+func WriteReportCSV(w io.Writer, report Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"kind", "id", "title", "action", "path", "bytes", "resolution", "severity", "suggested_path"}); err != nil {
+		return err
+	}
+	for _, file := range exportFiles(report) {
+		record := []string{"duplicate", file.GroupID, file.Group, file.Action, file.Path,
+			strconv.FormatInt(file.Size, 10), file.Resolution, "", ""}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	for _, issue := range report.ComplianceIssues {
+		id := issue.ID
+		if id == "" {
+			id = scanner.ComplianceIssueID(issue)
+		}
+		record := []string{"compliance", id, issue.Problem, issue.SuggestedAction, issue.Path,
+			"", "", issue.Severity, issue.SuggestedPath}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteReportMarkdown writes the report as Markdown: the totals, then a
+// table of duplicate files and one of compliance issues
+func WriteReportMarkdown(w io.Writer, report Report) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "# Jellysink report: %s\n\n", report.LibraryType)
+	fmt.Fprintf(bw, "- Scanned: %s\n", report.Timestamp.Format("2006-01-02 15:04"))
+	fmt.Fprintf(bw, "- Libraries: %s\n", mdCell(strings.Join(report.LibraryPaths, ", ")))
+	fmt.Fprintf(bw, "- Duplicate groups: %d (%d files to delete, %s to free)\n", report.TotalDuplicates, report.TotalFilesToDelete, formatBytes(report.SpaceToFree))
+	fmt.Fprintf(bw, "- Compliance issues: %d\n", len(report.ComplianceIssues))
+	if report.Changes != nil {
+		fmt.Fprintf(bw, "- %s\n", mdCell(report.Changes.Headline()))
+	}
+
+	fmt.Fprintf(bw, "\n## Duplicates\n\n")
+	if files := exportFiles(report); len(files) == 0 {
+		fmt.Fprintln(bw, "No duplicates found.")
+	} else {
+		fmt.Fprintln(bw, "| Title | Action | Size | Resolution | Path |")
+		fmt.Fprintln(bw, "|---|---|---:|---|---|")
+		for _, file := range files {
+			fmt.Fprintf(bw, "| %s | %s | %s | %s | `%s` |\n",
+				mdCell(file.Group), file.Action, formatBytes(file.Size), mdCell(file.Resolution), mdCode(file.Path))
+		}
+	}
+
+	fmt.Fprintf(bw, "\n## Compliance issues\n\n")
+	if len(report.ComplianceIssues) == 0 {
+		fmt.Fprintln(bw, "No compliance issues found.")
+	} else {
+		fmt.Fprintln(bw, "| Severity | Problem | Action | Path | Suggested path |")
+		fmt.Fprintln(bw, "|---|---|---|---|---|")
+		for _, issue := range report.ComplianceIssues {
+			fmt.Fprintf(bw, "| %s | %s | %s | `%s` | `%s` |\n",
+				mdCell(issue.Severity), mdCell(issue.Problem), mdCell(issue.SuggestedAction), mdCode(issue.Path), mdCode(issue.SuggestedPath))
+		}
+	}
+	return bw.Flush()
+}
+
+// mdCell escapes text for a Markdown table cell
+func mdCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+// mdCode escapes a path for an inline code span in a table cell
+func mdCode(s string) string {
+	return strings.NewReplacer("|", `\|`, "`", "'", "\n", " ").Replace(s)
+}
+
+// WriteReportHTML writes the report as one HTML page with inline styles and
+// tables that sort when a column header is clicked
+func WriteReportHTML(w io.Writer, report Report) error {
+	return WriteReportHTMLThumbnails(w, report, nil)
+}
+
+// ThumbnailFunc returns the image URL previewing one file of a duplicate
+// group (index 0 is the keeper), such as a data: URL or an API route, or ""
+// for none. The URL is trusted and written into the page as it is
+type ThumbnailFunc func(groupID string, index int, path string, duration time.Duration) string
+
+// WriteReportHTMLThumbnails is WriteReportHTML with a preview column in the
+// duplicates table, so copies can be compared by eye before deleting. A nil
+// thumbnail writes no previews
+func WriteReportHTMLThumbnails(w io.Writer, report Report, thumbnail ThumbnailFunc) error {
+	files := exportFiles(report)
+	if thumbnail != nil {
+		for i := range files {
+			f := &files[i]
+			f.Thumbnail = template.URL(thumbnail(f.GroupID, f.Index, f.Path, f.Duration))
+		}
+	}
+	data := struct {
+		Report     Report
+		Files      []exportFile
+		Previews   bool
+		SpaceFree  string
+		Headline   string
+		Libraries  string
+		Compliance []scanner.ComplianceIssue
+	}{
+		Report:     report,
+		Files:      files,
+		Previews:   thumbnail != nil,
+		SpaceFree:  formatBytes(report.SpaceToFree),
+		Libraries:  strings.Join(report.LibraryPaths, ", "),
+		Compliance: report.ComplianceIssues,
+	}
+	if report.Changes != nil {
+		data.Headline = report.Changes.Headline()
+	}
+	return exportTemplate.Execute(w, data)
+}
+
+var exportTemplate = template.Must(template.New("export").Funcs(template.FuncMap{
+	"bytes": formatBytes,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Jellysink report: {{.Report.LibraryType}} {{.Report.Timestamp.Format "2006-01-02 15:04"}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; font-size: 0.9em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+th { background: #eee; cursor: pointer; user-select: none; }
+td.num { text-align: right; white-space: nowrap; }
+td.path { font-family: monospace; word-break: break-all; }
+td.preview { width: 320px; padding: 0.2em; }
+td.preview img { display: block; max-width: 320px; }
+.KEEP { color: #2a7a2a; font-weight: bold; }
+.DELETE { color: #b22; font-weight: bold; }
+.VERSION { color: #27a; font-weight: bold; }
+.error { color: #b22; } .warn { color: #b70; } .info { color: #27a; }
+</style>
+</head>
+<body>
+<h1>Jellysink report: {{.Report.LibraryType}}</h1>
+<ul>
+<li>Scanned: {{.Report.Timestamp.Format "2006-01-02 15:04"}}</li>
+<li>Libraries: {{.Libraries}}</li>
+<li>Duplicate groups: {{.Report.TotalDuplicates}} ({{.Report.TotalFilesToDelete}} files to delete, {{.SpaceFree}} to free)</li>
+<li>Compliance issues: {{len .Compliance}}</li>
+{{- if .Headline}}
+<li>{{.Headline}}</li>
+{{- end}}
+</ul>
+<h2>Duplicates</h2>
+{{- if .Files}}
+<table class="sortable">
+<thead><tr><th>Title</th><th>Action</th><th>Size</th><th>Resolution</th><th>Path</th>{{if .Previews}}<th>Preview</th>{{end}}</tr></thead>
+<tbody>
+{{- range .Files}}
+<tr><td>{{.Group}}</td><td class="{{.Action}}">{{.Action}}</td><td class="num" data-sort="{{.Size}}">{{bytes .Size}}</td><td>{{.Resolution}}</td><td class="path">{{.Path}}</td>
+{{- if $.Previews}}<td class="preview">{{if .Thumbnail}}<img src="{{.Thumbnail}}" alt="" loading="lazy" onerror="this.replaceWith('no preview')">{{end}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+{{- else}}
+<p>No duplicates found.</p>
+{{- end}}
+<h2>Compliance issues</h2>
+{{- if .Compliance}}
+<table class="sortable">
+<thead><tr><th>Severity</th><th>Problem</th><th>Action</th><th>Path</th><th>Suggested path</th></tr></thead>
+<tbody>
+{{- range .Compliance}}
+<tr><td class="{{.Severity}}">{{.Severity}}</td><td>{{.Problem}}</td><td>{{.SuggestedAction}}</td><td class="path">{{.Path}}</td><td class="path">{{.SuggestedPath}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- else}}
+<p>No compliance issues found.</p>
+{{- end}}
+<script>
+document.querySelectorAll("table.sortable th").forEach(function (th) {
+  th.addEventListener("click", function () {
+    var index = Array.prototype.indexOf.call(th.parentNode.children, th);
+    var tbody = th.closest("table").tBodies[0];
+    var rows = Array.prototype.slice.call(tbody.rows);
+    var asc = th.dataset.order !== "asc";
+    th.dataset.order = asc ? "asc" : "desc";
+    var key = function (row) {
+      var cell = row.cells[index];
+      return cell.dataset.sort !== undefined ? Number(cell.dataset.sort) : cell.textContent.toLowerCase();
+    };
+    rows.sort(function (a, b) {
+      var x = key(a), y = key(b);
+      return (x < y ? -1 : x > y ? 1 : 0) * (asc ? 1 : -1);
+    });
+    rows.forEach(function (row) { tbody.appendChild(row); });
+  });
+});
+</script>
+</body>
+</html>
+`))
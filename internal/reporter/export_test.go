@@ -0,0 +1,117 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func exportTestReport() Report {
+	report := Report{
+		Timestamp:    time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC),
+		LibraryType:  "movies",
+		LibraryPaths: []string{"/movies"},
+		MovieDuplicates: []scanner.MovieDuplicate{
+			{NormalizedName: "heat", Year: "1995", Files: []scanner.MovieFile{
+				{Path: "/movies/Heat (1995)/Heat (1995) 2160p.mkv", Size: 4000, Resolution: "2160p"},
+				{Path: "/movies/Heat (1995)/Heat|Cut <b>.mkv", Size: 2000, Resolution: "1080p"},
+			}},
+		},
+		ComplianceIssues: []scanner.ComplianceIssue{
+			{Path: "/movies/heat.1995.mkv", Problem: "Not in a title folder", SuggestedAction: "move",
+				SuggestedPath: "/movies/Heat (1995)/Heat (1995).mkv", Severity: "warn"},
+		},
+	}
+	report.RecountTotals()
+	return report
+}
+
+func TestExportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, exportTestReport(), ExportCSV); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Export wrote invalid CSV: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("Expected a header and 3 rows, got %d", len(records))
+	}
+	if got := records[1]; got[0] != "duplicate" || got[3] != "KEEP" || got[5] != "4000" {
+		t.Errorf("Unexpected keeper row %v", got)
+	}
+	if got := records[2]; got[3] != "DELETE" || got[4] != "/movies/Heat (1995)/Heat|Cut <b>.mkv" {
+		t.Errorf("Unexpected duplicate row %v", got)
+	}
+	if got := records[3]; got[0] != "compliance" || got[7] != "warn" || got[8] != "/movies/Heat (1995)/Heat (1995).mkv" {
+		t.Errorf("Unexpected compliance row %v", got)
+	}
+}
+
+func TestExportMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, exportTestReport(), "markdown"); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# Jellysink report: movies",
+		"| heat (1995) | DELETE | 1.95 KB | 1080p | `/movies/Heat (1995)/Heat\\|Cut <b>.mkv` |",
+		"| warn | Not in a title folder | move |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Markdown export missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestExportHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, exportTestReport(), "HTML"); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "<b>.mkv") {
+		t.Error("Expected paths escaped in the HTML export")
+	}
+	for _, want := range []string{`<table class="sortable">`, `data-sort="2000"`, "Heat|Cut &lt;b&gt;.mkv", "<script>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML export missing %q", want)
+		}
+	}
+
+	if err := Export(&buf, Report{}, "pdf"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+	if strings.Contains(out, "<th>Preview</th>") {
+		t.Error("Expected no preview column without thumbnails")
+	}
+}
+
+func TestExportHTMLThumbnails(t *testing.T) {
+	var calls []string
+	thumbnail := func(groupID string, index int, path string, duration time.Duration) string {
+		calls = append(calls, fmt.Sprintf("%d %s", index, path))
+		if index == 1 {
+			return ""
+		}
+		return "data:image/jpeg;base64,AAAA"
+	}
+	var buf bytes.Buffer
+	if err := WriteReportHTMLThumbnails(&buf, exportTestReport(), thumbnail); err != nil {
+		t.Fatalf("WriteReportHTMLThumbnails() error = %v", err)
+	}
+	out := buf.String()
+	if len(calls) != 2 || calls[0] != "0 /movies/Heat (1995)/Heat (1995) 2160p.mkv" {
+		t.Errorf("Unexpected thumbnail calls %v", calls)
+	}
+	if !strings.Contains(out, "<th>Preview</th>") || strings.Count(out, `<img src="data:image/jpeg;base64,AAAA"`) != 1 {
+		t.Errorf("Expected one embedded preview:\n%s", out)
+	}
+}
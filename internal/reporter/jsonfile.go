@@ -0,0 +1,72 @@
+package reporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Nomadcxx/jellysink/internal/jsonstream"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// ReadReport loads the JSON report at path. In low-memory mode it is
+// decoded entry by entry from the file instead of read into memory whole
+func ReadReport(path string) (Report, error) {
+	var report Report
+	if scanner.LowMemory() {
+		f, err := os.Open(path)
+		if err != nil {
+			return report, fmt.Errorf("failed to read report: %w", err)
+		}
+		defer f.Close()
+		if err := jsonstream.Decode(bufio.NewReader(f), &report); err != nil {
+			return Report{}, fmt.Errorf("failed to parse report: %w", err)
+		}
+		return report, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return report, fmt.Errorf("failed to read report: %w", err)
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return Report{}, fmt.Errorf("failed to parse report: %w", err)
+	}
+	return report, nil
+}
+
+// WriteReport saves report to path as indented JSON. It is written to a
+// temp file and renamed so readers never see a half-written report; in
+// low-memory mode it is encoded entry by entry straight to that file
+func WriteReport(path string, report Report) error {
+	tmpPath := path + ".tmp"
+	if scanner.LowMemory() {
+		f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		err = jsonstream.Encode(f, &report, "  ")
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(tmpPath)
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	} else {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace report: %w", err)
+	}
+	return nil
+}
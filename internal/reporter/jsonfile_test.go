@@ -0,0 +1,59 @@
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestWriteReportLowMemory(t *testing.T) {
+	dir := t.TempDir()
+	report := Report{
+		Timestamp:    time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC),
+		LibraryType:  "movies",
+		LibraryPaths: []string{"/media/movies"},
+		MovieDuplicates: []scanner.MovieDuplicate{{
+			NormalizedName: "heat",
+			Year:           "1995",
+			Files:          []scanner.MovieFile{{Path: "/media/movies/Heat (1995)/Heat.mkv", Size: 100}},
+		}},
+		ComplianceIssues: []scanner.ComplianceIssue{{Path: "/media/movies/heat.mkv", Type: "movie", Problem: "Missing year"}},
+		TotalDuplicates:  1,
+		Cleaned:          &CleanSummary{DuplicatesDeleted: 1},
+	}
+
+	normal := filepath.Join(dir, "normal.json")
+	if err := WriteReport(normal, report); err != nil {
+		t.Fatalf("WriteReport() error: %v", err)
+	}
+
+	scanner.SetLowMemory(true)
+	defer scanner.SetLowMemory(false)
+	streamed := filepath.Join(dir, "streamed.json")
+	if err := WriteReport(streamed, report); err != nil {
+		t.Fatalf("WriteReport() in low-memory mode error: %v", err)
+	}
+
+	want, _ := os.ReadFile(normal)
+	got, _ := os.ReadFile(streamed)
+	if string(got) != string(want) {
+		t.Errorf("Streamed report differs:\n%s\nwant\n%s", got, want)
+	}
+	if _, err := os.Stat(streamed + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Expected temp file to be renamed away")
+	}
+
+	loaded, err := ReadReport(streamed)
+	if err != nil {
+		t.Fatalf("ReadReport() error: %v", err)
+	}
+	scanner.SetLowMemory(false)
+	expected, _ := ReadReport(normal)
+	if !reflect.DeepEqual(loaded, expected) {
+		t.Errorf("ReadReport() = %+v\nwant %+v", loaded, expected)
+	}
+}
@@ -0,0 +1,31 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// PartialScan marks a report written by a cancelled scan. Only the
+// Completed sections were scanned; the others are empty, not clean
+type PartialScan struct {
+	Completed []scanner.ScanSection
+	Resume    string // token scan --resume checks before reusing the completed sections
+}
+
+// Banner returns the one-line "PARTIAL SCAN" notice shown with the report
+func (p PartialScan) Banner() string {
+	var missing []string
+	done := make(map[scanner.ScanSection]bool, len(p.Completed))
+	for _, section := range p.Completed {
+		done[section] = true
+	}
+	for _, section := range scanner.ScanSections {
+		if !done[section] {
+			missing = append(missing, strings.ReplaceAll(string(section), "_", " "))
+		}
+	}
+	return fmt.Sprintf("PARTIAL SCAN: cancelled after %d of %d sections, not scanned: %s",
+		len(p.Completed), len(scanner.ScanSections), strings.Join(missing, ", "))
+}
@@ -0,0 +1,439 @@
+package reporter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// Plan actions, matching the cleaner's operation types
+const (
+	PlanDelete     = "delete"
+	PlanRename     = "rename"
+	PlanReorganize = "reorganize"
+	PlanVersion    = "version" // rename a duplicate into the keeper's folder as a Jellyfin version
+)
+
+// planReportPrefix marks the header line naming the report a plan was built from
+const planReportPrefix = "# report: "
+
+// PlanOperation is one numbered line of a review plan
+type PlanOperation struct {
+	Number int    `json:"number,omitempty"`
+	Action string `json:"action"` // PlanDelete, PlanRename, PlanReorganize or PlanVersion
+	Source string `json:"source"`
+	Target string `json:"target,omitempty"` // destination for renames, reorganizes and versions
+}
+
+// Plan is the JSON form of a review plan (`jellysink plan --json`), which
+// apply accepts in place of the numbered text
+type Plan struct {
+	Report     string          `json:"report"` // report the plan was built from
+	Operations []PlanOperation `json:"operations"`
+}
+
+// String formats the operation as it appears in a plan file
+func (op PlanOperation) String() string {
+	line := fmt.Sprintf("%d. %s %s", op.Number, op.Action, op.Source)
+	if op.Target != "" {
+		line += " -> " + op.Target
+	}
+	return line
+}
+
+// key identifies the operation independently of its number
+func (op PlanOperation) key() string {
+	return op.Action + "\x00" + filepath.Clean(op.Source) + "\x00" + op.Target
+}
+
+// importing returns a file Sonarr, Radarr or a download client is still
+// writing next to any of paths, or ""
+func importing(paths ...string) string {
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if file := scanner.ImportInProgress(path); file != "" {
+			return file
+		}
+	}
+	return ""
+}
+
+// movieFilePaths returns the paths of every copy in dup
+func movieFilePaths(dup scanner.MovieDuplicate) []string {
+	paths := make([]string, len(dup.Files))
+	for i, file := range dup.Files {
+		paths[i] = file.Path
+	}
+	return paths
+}
+
+// tvFilePaths returns the paths of every copy in dup
+func tvFilePaths(dup scanner.TVDuplicate) []string {
+	paths := make([]string, len(dup.Files))
+	for i, file := range dup.Files {
+		paths[i] = file.Path
+	}
+	return paths
+}
+
+// WritePlan writes the report's clean operations as a numbered, editable
+// plan. Users delete the lines they disapprove of and feed the file back
+// with `jellysink apply`; lines starting with # are comments
+func WritePlan(w io.Writer, report Report, reportPath string) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "# jellysink clean plan")
+	fmt.Fprintln(bw, planReportPrefix+reportPath)
+	fmt.Fprintln(bw, "#")
+	fmt.Fprintln(bw, "# Delete the lines of any operation you do not approve, then run:")
+	fmt.Fprintln(bw, "#   jellysink apply <this-file>")
+	fmt.Fprintln(bw, "# Lines starting with # are ignored. Do not edit the paths.")
+
+	n := 0
+	next := func(action, source, target string) string {
+		n++
+		return PlanOperation{Number: n, Action: action, Source: source, Target: target}.String()
+	}
+
+	if len(report.MovieDuplicates) > 0 {
+		fmt.Fprintln(bw, "\n## Movie duplicates")
+		for _, dup := range report.MovieDuplicates {
+			if len(dup.Files) < 2 {
+				continue
+			}
+			if dup.Excluded {
+				fmt.Fprintf(bw, "\n# %s (%s) - excluded from cleaning, keep all: %s\n", dup.NormalizedName, dup.Year, dup.Files[0].Path)
+				continue
+			}
+			if file := importing(movieFilePaths(dup)...); file != "" {
+				fmt.Fprintf(bw, "\n# %s (%s) - import in progress (%s), left for the next scan\n", dup.NormalizedName, dup.Year, file)
+				continue
+			}
+			if dup.KeepsAllVersions() {
+				renames, err := scanner.MultiVersionPlan(dup)
+				if err != nil {
+					fmt.Fprintf(bw, "\n# %s (%s) - cannot keep as versions, not applied: %v\n", dup.NormalizedName, dup.Year, err)
+					continue
+				}
+				fmt.Fprintf(bw, "\n# %s (%s) - keep all as versions in: %s\n", dup.NormalizedName, dup.Year, filepath.Dir(dup.Files[0].Path))
+				fmt.Fprintln(bw, "# approve all of these renames or none")
+				for _, r := range renames {
+					fmt.Fprintln(bw, next(PlanVersion, r.Source, r.Target))
+				}
+				continue
+			}
+			fmt.Fprintf(bw, "\n# %s (%s) - keep: %s\n", dup.NormalizedName, dup.Year, dup.Files[0].Path)
+			for _, file := range dup.Files[1:] {
+				fmt.Fprintln(bw, next(PlanDelete, file.Path, ""))
+			}
+		}
+	}
+
+	if len(report.TVDuplicates) > 0 {
+		fmt.Fprintln(bw, "\n## TV duplicates")
+		for _, dup := range report.TVDuplicates {
+			if len(dup.Files) < 2 {
+				continue
+			}
+			if dup.Excluded {
+				fmt.Fprintf(bw, "\n# %s %s - excluded from cleaning, keep all: %s\n", dup.ShowName, dup.EpisodeLabel(), dup.Files[0].Path)
+				continue
+			}
+			if file := importing(tvFilePaths(dup)...); file != "" {
+				fmt.Fprintf(bw, "\n# %s %s - import in progress (%s), left for the next scan\n", dup.ShowName, dup.EpisodeLabel(), file)
+				continue
+			}
+			fmt.Fprintf(bw, "\n# %s %s - keep: %s\n", dup.ShowName, dup.EpisodeLabel(), dup.Files[0].Path)
+			for _, file := range dup.Files[1:] {
+				fmt.Fprintln(bw, next(PlanDelete, file.Path, ""))
+			}
+		}
+	}
+
+	if len(report.ComplianceIssues) > 0 {
+		fmt.Fprintln(bw, "\n## Compliance fixes")
+		renames := scanner.ShowFolderRenames(report.ComplianceIssues)
+		for _, issue := range report.ComplianceIssues {
+			fmt.Fprintf(bw, "\n# [%s] %s\n", issue.EffectiveSeverity(), issue.Problem)
+			if scanner.IsShowFolderRename(issue) {
+				fmt.Fprintln(bw, "# fixes below this folder are listed at their paths after the rename")
+			}
+			if issue.SuggestedAction == "manual_review" {
+				fmt.Fprintf(bw, "# needs manual review, not applied: %s\n", issue.Path)
+				continue
+			}
+			if file := importing(issue.Path, issue.SuggestedPath); file != "" {
+				fmt.Fprintf(bw, "# import in progress (%s), left for the next scan: %s\n", file, issue.Path)
+				continue
+			}
+			listed := rebaseIssue(issue, renames)
+			fmt.Fprintln(bw, next(issue.SuggestedAction, listed.Path, listed.SuggestedPath))
+		}
+	}
+
+	if n == 0 {
+		fmt.Fprintln(bw, "\n# Nothing to clean")
+	}
+
+	return bw.Flush()
+}
+
+// WritePlanJSON writes the operations WritePlan would list as a Plan
+func WritePlanJSON(w io.Writer, report Report, reportPath string) error {
+	var text bytes.Buffer
+	if err := WritePlan(&text, report, reportPath); err != nil {
+		return err
+	}
+	_, ops, err := ReadPlan(&text)
+	if err != nil {
+		return err
+	}
+	if ops == nil {
+		ops = []PlanOperation{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Plan{Report: reportPath, Operations: ops})
+}
+
+// ReadPlan parses a plan written by WritePlan or WritePlanJSON, returning the
+// report path from its header and the operations still listed
+func ReadPlan(r io.Reader) (string, []PlanOperation, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return readPlanJSON(data)
+	}
+
+	var reportPath string
+	var ops []PlanOperation
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, planReportPrefix) && reportPath == "" {
+			reportPath = strings.TrimSpace(strings.TrimPrefix(line, planReportPrefix))
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		op, err := parsePlanLine(line)
+		if err != nil {
+			return "", nil, fmt.Errorf("plan line %d: %w", lineNo, err)
+		}
+		ops = append(ops, op)
+	}
+	if err := sc.Err(); err != nil {
+		return "", nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+	if reportPath == "" {
+		return "", nil, fmt.Errorf("plan has no %q header line", strings.TrimSpace(planReportPrefix))
+	}
+	return reportPath, ops, nil
+}
+
+// readPlanJSON parses a Plan, checking each operation as the text parser does
+func readPlanJSON(data []byte) (string, []PlanOperation, error) {
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return "", nil, fmt.Errorf("invalid JSON plan: %w", err)
+	}
+	for i, op := range plan.Operations {
+		if err := op.validate(); err != nil {
+			return "", nil, fmt.Errorf("plan operation %d: %w", i+1, err)
+		}
+	}
+	if plan.Report == "" {
+		return "", nil, fmt.Errorf("plan has no report")
+	}
+	return plan.Report, plan.Operations, nil
+}
+
+// validate checks the action is known and has the paths it needs
+func (op PlanOperation) validate() error {
+	switch op.Action {
+	case PlanDelete:
+	case PlanRename, PlanReorganize, PlanVersion:
+		if op.Target == "" {
+			return fmt.Errorf("%s of %q has no target", op.Action, op.Source)
+		}
+	default:
+		return fmt.Errorf("unknown action %q", op.Action)
+	}
+	if op.Source == "" {
+		return fmt.Errorf("%s has no path", op.Action)
+	}
+	return nil
+}
+
+// parsePlanLine parses "N. action source [-> target]"
+func parsePlanLine(line string) (PlanOperation, error) {
+	var op PlanOperation
+
+	if num, rest, ok := strings.Cut(line, ". "); ok {
+		if n, err := strconv.Atoi(num); err == nil {
+			op.Number = n
+			line = rest
+		}
+	}
+
+	action, rest, ok := strings.Cut(strings.TrimSpace(line), " ")
+	if !ok {
+		return op, fmt.Errorf("expected \"<action> <path>\", got %q", line)
+	}
+	op.Action = action
+	rest = strings.TrimSpace(rest)
+
+	switch action {
+	case PlanDelete:
+		op.Source = rest
+	case PlanRename, PlanReorganize, PlanVersion:
+		source, target, ok := strings.Cut(rest, " -> ")
+		if !ok {
+			return op, fmt.Errorf("%s needs \"<path> -> <new path>\", got %q", action, rest)
+		}
+		op.Source = strings.TrimSpace(source)
+		op.Target = strings.TrimSpace(target)
+	default:
+		return op, fmt.Errorf("unknown action %q", action)
+	}
+
+	if op.Source == "" {
+		return op, fmt.Errorf("%s has no path", action)
+	}
+	return op, nil
+}
+
+// ApplyPlan narrows report to the operations approved in a plan. Duplicate
+// groups keep their first file and only the approved deletes; multi-version
+// groups are kept whole when all their renames are approved; excluded
+// groups are never cleaned; compliance issues are kept only when approved.
+// Operations that do not match the report (edited paths, a plan for a
+// different report) are an error
+func ApplyPlan(report Report, ops []PlanOperation) (Report, error) {
+	approved := make(map[string]bool, len(ops))
+	for _, op := range ops {
+		approved[op.key()] = true
+	}
+	matched := make(map[string]bool, len(ops))
+
+	isApproved := func(op PlanOperation) bool {
+		k := op.key()
+		if approved[k] {
+			matched[k] = true
+			return true
+		}
+		return false
+	}
+
+	filtered := report
+	filtered.MovieDuplicates = nil
+	filtered.TVDuplicates = nil
+	filtered.ComplianceIssues = nil
+
+	for _, dup := range report.MovieDuplicates {
+		if len(dup.Files) < 2 || dup.Excluded {
+			continue
+		}
+		if dup.KeepsAllVersions() {
+			kept, err := approveVersions(dup, isApproved)
+			if err != nil {
+				return Report{}, err
+			}
+			if kept {
+				dup.Strategy = scanner.StrategyMultiVersion
+				filtered.MovieDuplicates = append(filtered.MovieDuplicates, dup)
+			}
+			continue
+		}
+		files := []scanner.MovieFile{dup.Files[0]}
+		for _, file := range dup.Files[1:] {
+			if isApproved(PlanOperation{Action: PlanDelete, Source: file.Path}) {
+				files = append(files, file)
+			}
+		}
+		if len(files) > 1 {
+			dup.Files = files
+			filtered.MovieDuplicates = append(filtered.MovieDuplicates, dup)
+		}
+	}
+
+	for _, dup := range report.TVDuplicates {
+		if len(dup.Files) < 2 || dup.Excluded {
+			continue
+		}
+		files := []scanner.TVFile{dup.Files[0]}
+		for _, file := range dup.Files[1:] {
+			if isApproved(PlanOperation{Action: PlanDelete, Source: file.Path}) {
+				files = append(files, file)
+			}
+		}
+		if len(files) > 1 {
+			dup.Files = files
+			filtered.TVDuplicates = append(filtered.TVDuplicates, dup)
+		}
+	}
+
+	renames := scanner.ShowFolderRenames(report.ComplianceIssues)
+	for _, issue := range report.ComplianceIssues {
+		listed := rebaseIssue(issue, renames)
+		op := PlanOperation{Action: issue.SuggestedAction, Source: listed.Path, Target: listed.SuggestedPath}
+		if issue.SuggestedAction != "manual_review" && isApproved(op) {
+			filtered.ComplianceIssues = append(filtered.ComplianceIssues, issue)
+		}
+	}
+
+	filtered.RecountTotals()
+
+	for _, op := range ops {
+		if !matched[op.key()] {
+			return Report{}, fmt.Errorf("plan operation does not match the report: %s", op)
+		}
+	}
+	return filtered, nil
+}
+
+// rebaseIssue returns issue with the paths a plan lists for it: below show
+// folders renamed by the plan, where they are after the renames
+func rebaseIssue(issue scanner.ComplianceIssue, renames *scanner.PathRebaser) scanner.ComplianceIssue {
+	if !scanner.IsShowFolderRename(issue) {
+		issue.Path, issue.SuggestedPath = renames.Rebase(issue.Path), renames.Rebase(issue.SuggestedPath)
+	}
+	return issue
+}
+
+// approveVersions reports whether every version rename of dup is approved,
+// failing when only some of them are
+func approveVersions(dup scanner.MovieDuplicate, isApproved func(PlanOperation) bool) (bool, error) {
+	renames, err := scanner.MultiVersionPlan(dup)
+	if err != nil || len(renames) == 0 {
+		return false, nil
+	}
+	approved := 0
+	for _, r := range renames {
+		if isApproved(PlanOperation{Action: PlanVersion, Source: r.Source, Target: r.Target}) {
+			approved++
+		}
+	}
+	if approved > 0 && approved < len(renames) {
+		return false, fmt.Errorf("plan approves only %d of %d version renames for %s; keep all or none", approved, len(renames), filepath.Dir(dup.Files[0].Path))
+	}
+	return approved == len(renames), nil
+}
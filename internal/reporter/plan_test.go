@@ -0,0 +1,346 @@
+package reporter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func planTestReport() Report {
+	return Report{
+		MovieDuplicates: []scanner.MovieDuplicate{{
+			NormalizedName: "heat",
+			Year:           "1995",
+			Files: []scanner.MovieFile{
+				{Path: "/movies/Heat (1995)/Heat (1995) 2160p.mkv", Size: 4000},
+				{Path: "/movies/Heat (1995)/Heat (1995) 1080p.mkv", Size: 2000},
+				{Path: "/movies/heat.1995.720p.mkv", Size: 1000},
+			},
+		}},
+		TVDuplicates: []scanner.TVDuplicate{{
+			ShowName: "firefly",
+			Season:   1,
+			Episode:  1,
+			Files: []scanner.TVFile{
+				{Path: "/tv/Firefly/Season 01/Firefly S01E01.mkv", Size: 500},
+				{Path: "/tv/Firefly/Season 01/firefly.s01e01.x264.mkv", Size: 300},
+			},
+		}},
+		ComplianceIssues: []scanner.ComplianceIssue{
+			{Path: "/movies/heat.1995.720p.mkv", Problem: "Loose movie", SuggestedAction: "reorganize",
+				SuggestedPath: "/movies/Heat (1995)/Heat (1995).mkv"},
+			{Path: "/tv/Firefly/Season 1", Problem: "Season folder not padded", SuggestedAction: "rename",
+				SuggestedPath: "/tv/Firefly/Season 01"},
+			{Path: "/movies/Heat (1995)/sample.mkv", Problem: "Sample file", SuggestedAction: "manual_review"},
+		},
+		TotalDuplicates:    2,
+		TotalFilesToDelete: 3,
+		SpaceToFree:        3300,
+	}
+}
+
+func TestPlanRoundTrip(t *testing.T) {
+	report := planTestReport()
+
+	var buf bytes.Buffer
+	if err := WritePlan(&buf, report, "/reports/scan.json"); err != nil {
+		t.Fatalf("WritePlan failed: %v", err)
+	}
+	plan := buf.String()
+	for _, want := range []string{
+		"# report: /reports/scan.json",
+		"1. delete /movies/Heat (1995)/Heat (1995) 1080p.mkv",
+		"3. delete /tv/Firefly/Season 01/firefly.s01e01.x264.mkv",
+		"4. reorganize /movies/heat.1995.720p.mkv -> /movies/Heat (1995)/Heat (1995).mkv",
+		"5. rename /tv/Firefly/Season 1 -> /tv/Firefly/Season 01",
+		"# needs manual review, not applied: /movies/Heat (1995)/sample.mkv",
+	} {
+		if !strings.Contains(plan, want) {
+			t.Errorf("Plan missing %q:\n%s", want, plan)
+		}
+	}
+
+	reportPath, ops, err := ReadPlan(strings.NewReader(plan))
+	if err != nil {
+		t.Fatalf("ReadPlan failed: %v", err)
+	}
+	if reportPath != "/reports/scan.json" {
+		t.Errorf("Expected report path /reports/scan.json, got %q", reportPath)
+	}
+	if len(ops) != 5 {
+		t.Fatalf("Expected 5 operations, got %d", len(ops))
+	}
+
+	filtered, err := ApplyPlan(report, ops)
+	if err != nil {
+		t.Fatalf("ApplyPlan failed: %v", err)
+	}
+	if filtered.TotalFilesToDelete != 3 || filtered.SpaceToFree != 3300 || len(filtered.ComplianceIssues) != 2 {
+		t.Errorf("Unedited plan changed the report: %d files, %d bytes, %d issues",
+			filtered.TotalFilesToDelete, filtered.SpaceToFree, len(filtered.ComplianceIssues))
+	}
+}
+
+func TestApplyPlanSkipsRemovedLines(t *testing.T) {
+	report := planTestReport()
+
+	var buf bytes.Buffer
+	if err := WritePlan(&buf, report, "/reports/scan.json"); err != nil {
+		t.Fatalf("WritePlan failed: %v", err)
+	}
+
+	// Disapprove the 1080p delete, the whole TV group and the season rename
+	var kept []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.HasPrefix(line, "1. ") || strings.HasPrefix(line, "3. ") || strings.HasPrefix(line, "5. ") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	_, ops, err := ReadPlan(strings.NewReader(strings.Join(kept, "\n")))
+	if err != nil {
+		t.Fatalf("ReadPlan failed: %v", err)
+	}
+	filtered, err := ApplyPlan(report, ops)
+	if err != nil {
+		t.Fatalf("ApplyPlan failed: %v", err)
+	}
+
+	if len(filtered.MovieDuplicates) != 1 || len(filtered.TVDuplicates) != 0 {
+		t.Fatalf("Expected 1 movie group and no TV groups, got %d and %d",
+			len(filtered.MovieDuplicates), len(filtered.TVDuplicates))
+	}
+	files := filtered.MovieDuplicates[0].Files
+	if len(files) != 2 || files[0].Path != report.MovieDuplicates[0].Files[0].Path || files[1].Path != "/movies/heat.1995.720p.mkv" {
+		t.Errorf("Expected the keeper and the 720p delete, got %+v", files)
+	}
+	if filtered.TotalDuplicates != 1 || filtered.TotalFilesToDelete != 1 || filtered.SpaceToFree != 1000 {
+		t.Errorf("Totals not recomputed: %d groups, %d files, %d bytes",
+			filtered.TotalDuplicates, filtered.TotalFilesToDelete, filtered.SpaceToFree)
+	}
+	if len(filtered.ComplianceIssues) != 1 || filtered.ComplianceIssues[0].SuggestedAction != "reorganize" {
+		t.Errorf("Expected only the reorganize fix, got %+v", filtered.ComplianceIssues)
+	}
+}
+
+func TestPlanLeavesOutExcludedGroups(t *testing.T) {
+	report := planTestReport()
+	report.TVDuplicates[0].Excluded = true
+	report.RecountTotals()
+
+	var buf bytes.Buffer
+	if err := WritePlan(&buf, report, "/reports/scan.json"); err != nil {
+		t.Fatalf("WritePlan failed: %v", err)
+	}
+	plan := buf.String()
+	if !strings.Contains(plan, "# firefly S01E01 - excluded from cleaning, keep all: /tv/Firefly/Season 01/Firefly S01E01.mkv") {
+		t.Errorf("Expected the excluded group noted:\n%s", plan)
+	}
+	if strings.Contains(plan, "delete /tv/") {
+		t.Errorf("Expected no deletes for the excluded group:\n%s", plan)
+	}
+
+	// A hand-written delete in an excluded group does not match the report
+	ops := []PlanOperation{{Action: PlanDelete, Source: "/tv/Firefly/Season 01/firefly.s01e01.x264.mkv"}}
+	if _, err := ApplyPlan(report, ops); err == nil {
+		t.Error("Expected a delete in an excluded group to be rejected")
+	}
+}
+
+func TestPlanLeavesOutImportingFolders(t *testing.T) {
+	tmpDir := t.TempDir()
+	busy := filepath.Join(tmpDir, "Heat (1995)")
+	os.MkdirAll(busy, 0755)
+	os.WriteFile(filepath.Join(busy, "Heat (1995) 2160p.mkv.partial~"), nil, 0644)
+
+	report := Report{
+		MovieDuplicates: []scanner.MovieDuplicate{{
+			NormalizedName: "heat",
+			Year:           "1995",
+			Files: []scanner.MovieFile{
+				{Path: filepath.Join(busy, "Heat (1995) 1080p.mkv")},
+				{Path: filepath.Join(busy, "Heat (1995) 720p.mkv")},
+			},
+		}},
+		ComplianceIssues: []scanner.ComplianceIssue{
+			{Path: filepath.Join(tmpDir, "heat.mkv"), Problem: "Loose movie", SuggestedAction: "reorganize",
+				SuggestedPath: filepath.Join(busy, "Heat (1995).mkv")},
+			{Path: filepath.Join(tmpDir, "Alien 1979"), Problem: "Missing year parentheses", SuggestedAction: "rename",
+				SuggestedPath: filepath.Join(tmpDir, "Alien (1979)")},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WritePlan(&buf, report, "/reports/scan.json"); err != nil {
+		t.Fatalf("WritePlan failed: %v", err)
+	}
+	_, ops, err := ReadPlan(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadPlan failed: %v", err)
+	}
+	if len(ops) != 1 || ops[0].Action != PlanRename {
+		t.Errorf("Expected only the idle rename planned, got %+v", ops)
+	}
+	if !strings.Contains(buf.String(), "import in progress") {
+		t.Errorf("Expected the importing folder noted in the plan:\n%s", buf.String())
+	}
+}
+
+func TestApplyPlanRejectsEditedLines(t *testing.T) {
+	report := planTestReport()
+
+	tests := []struct {
+		name string
+		plan string
+	}{
+		{"edited target", "# report: r.json\n5. rename /tv/Firefly/Season 1 -> /tv/Firefly/S1\n"},
+		{"unknown path", "# report: r.json\n1. delete /movies/Other.mkv\n"},
+		{"keeper deleted", "# report: r.json\n1. delete /movies/Heat (1995)/Heat (1995) 2160p.mkv\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ops, err := ReadPlan(strings.NewReader(tt.plan))
+			if err != nil {
+				t.Fatalf("ReadPlan failed: %v", err)
+			}
+			if _, err := ApplyPlan(report, ops); err == nil {
+				t.Error("Expected an error for an operation not in the report")
+			}
+		})
+	}
+}
+
+func TestReadPlanErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		plan string
+	}{
+		{"no header", "1. delete /movies/a.mkv\n"},
+		{"unknown action", "# report: r.json\n1. move /movies/a.mkv\n"},
+		{"rename without target", "# report: r.json\n1. rename /movies/a.mkv\n"},
+		{"JSON without report", `{"operations":[{"action":"delete","source":"/movies/a.mkv"}]}`},
+		{"JSON unknown action", `{"report":"r.json","operations":[{"action":"move","source":"/movies/a.mkv"}]}`},
+		{"JSON rename without target", `{"report":"r.json","operations":[{"action":"rename","source":"/movies/a.mkv"}]}`},
+		{"malformed JSON", `{"report":`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ReadPlan(strings.NewReader(tt.plan)); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestPlanJSONRoundTrip(t *testing.T) {
+	report := planTestReport()
+
+	var text, js bytes.Buffer
+	if err := WritePlan(&text, report, "/reports/scan.json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := WritePlanJSON(&js, report, "/reports/scan.json"); err != nil {
+		t.Fatalf("WritePlanJSON failed: %v", err)
+	}
+	if !strings.Contains(js.String(), `"action": "reorganize"`) {
+		t.Errorf("Expected lowercase JSON fields, got:\n%s", js.String())
+	}
+
+	_, textOps, _ := ReadPlan(&text)
+	reportPath, jsonOps, err := ReadPlan(&js)
+	if err != nil {
+		t.Fatalf("ReadPlan of JSON failed: %v", err)
+	}
+	if reportPath != "/reports/scan.json" || len(jsonOps) != len(textOps) {
+		t.Fatalf("Expected the text plan's %d operations, got %s with %+v", len(textOps), reportPath, jsonOps)
+	}
+	for i := range textOps {
+		if jsonOps[i] != textOps[i] {
+			t.Errorf("Operation %d: JSON %+v, text %+v", i+1, jsonOps[i], textOps[i])
+		}
+	}
+}
+
+func TestPlanMultiVersion(t *testing.T) {
+	report := planTestReport()
+	report.MovieDuplicates[0].Strategy = scanner.StrategyMultiVersion
+	report.MovieDuplicates[0].Files[0].Resolution = "2160p"
+	report.MovieDuplicates[0].Files[1].Resolution = "1080p"
+	report.MovieDuplicates[0].Files[2].Resolution = "720p"
+
+	var buf bytes.Buffer
+	if err := WritePlan(&buf, report, "/reports/scan.json"); err != nil {
+		t.Fatalf("WritePlan failed: %v", err)
+	}
+	plan := buf.String()
+	for _, want := range []string{
+		"1. version /movies/Heat (1995)/Heat (1995) 2160p.mkv -> /movies/Heat (1995)/Heat (1995) - 2160p.mkv",
+		"3. version /movies/heat.1995.720p.mkv -> /movies/Heat (1995)/Heat (1995) - 720p.mkv",
+	} {
+		if !strings.Contains(plan, want) {
+			t.Errorf("plan missing %q:\n%s", want, plan)
+		}
+	}
+
+	_, ops, err := ReadPlan(strings.NewReader(plan))
+	if err != nil {
+		t.Fatalf("ReadPlan failed: %v", err)
+	}
+	filtered, err := ApplyPlan(report, ops)
+	if err != nil {
+		t.Fatalf("ApplyPlan failed: %v", err)
+	}
+	if len(filtered.MovieDuplicates) != 1 || len(filtered.MovieDuplicates[0].Files) != 3 {
+		t.Errorf("Expected the whole multi-version group, got %+v", filtered.MovieDuplicates)
+	}
+	if filtered.SpaceToFree != 300 {
+		t.Errorf("Expected only the TV delete counted, got %d bytes", filtered.SpaceToFree)
+	}
+
+	// Approving only some of a group's renames is refused
+	if _, err := ApplyPlan(report, ops[1:]); err == nil {
+		t.Error("Expected an error for a partly approved multi-version group")
+	}
+}
+
+func TestPlanListsFixesBelowRenamedShowFolders(t *testing.T) {
+	report := Report{ComplianceIssues: []scanner.ComplianceIssue{
+		{Path: "/tv/Firefly", Type: "tv", Problem: "Show folder has no year", Rule: scanner.RuleTVShowFolderYear,
+			SuggestedAction: "rename", SuggestedPath: "/tv/Firefly (2002)"},
+		{Path: "/tv/Firefly/Season 1/Firefly S01E01.mkv", Type: "tv", Problem: "Season folder", Rule: scanner.RuleTVSeasonFolder,
+			SuggestedAction: "reorganize", SuggestedPath: "/tv/Firefly/Season 01/Firefly S01E01.mkv"},
+	}}
+
+	var buf bytes.Buffer
+	if err := WritePlan(&buf, report, "/reports/scan.json"); err != nil {
+		t.Fatalf("WritePlan failed: %v", err)
+	}
+	plan := buf.String()
+	for _, want := range []string{
+		"1. rename /tv/Firefly -> /tv/Firefly (2002)",
+		"2. reorganize /tv/Firefly (2002)/Season 1/Firefly S01E01.mkv -> /tv/Firefly (2002)/Season 01/Firefly S01E01.mkv",
+	} {
+		if !strings.Contains(plan, want) {
+			t.Errorf("Plan missing %q:\n%s", want, plan)
+		}
+	}
+
+	// The episode fix is still approved on its own; the report keeps the
+	// paths on disk, which the clean rebases as it renames folders
+	_, ops, err := ReadPlan(strings.NewReader(plan))
+	if err != nil {
+		t.Fatalf("ReadPlan failed: %v", err)
+	}
+	filtered, err := ApplyPlan(report, ops[1:])
+	if err != nil {
+		t.Fatalf("ApplyPlan failed: %v", err)
+	}
+	if len(filtered.ComplianceIssues) != 1 || filtered.ComplianceIssues[0].Path != "/tv/Firefly/Season 1/Firefly S01E01.mkv" {
+		t.Errorf("Expected only the episode fix at its path on disk, got %+v", filtered.ComplianceIssues)
+	}
+}
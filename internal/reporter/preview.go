@@ -0,0 +1,94 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Preview operation statuses besides the cleaner's deferred and skipped ones
+const (
+	PreviewReady  = "ready"  // the clean would carry the operation out
+	PreviewFailed = "failed" // the dry run found it would fail; see Errors
+)
+
+// PreviewOperation is one operation a simulated clean would carry out
+type PreviewOperation struct {
+	Action string `json:"action"` // delete, rename, reorganize, version or delete-folder
+	Source string `json:"source"`
+	Target string `json:"target,omitempty"` // destination for renames, reorganizes and versions
+	Status string `json:"status"`           // PreviewReady, PreviewFailed, or why the clean would leave it
+}
+
+// Preview is the outcome of `jellysink run --dry-run`: a fresh scan and the
+// clean it would lead to, simulated without touching any file
+type Preview struct {
+	Report           string             `json:"report"` // report the scan wrote
+	TotalDuplicates  int                `json:"total_duplicates"`
+	FilesToDelete    int                `json:"files_to_delete"`
+	ComplianceIssues int                `json:"compliance_issues"`
+	OrphanFolders    int                `json:"orphan_folders"`
+	SpaceToFree      int64              `json:"space_to_free"`
+	Operations       []PreviewOperation `json:"operations"`
+	Errors           []string           `json:"errors"` // operations the clean could not carry out
+}
+
+// Count returns how many operations have status
+func (p Preview) Count(status string) int {
+	n := 0
+	for _, op := range p.Operations {
+		if op.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// WritePreview writes p as the text `jellysink run --dry-run` prints
+func WritePreview(w io.Writer, p Preview) error {
+	fmt.Fprintf(w, "Report: %s\n\n", p.Report)
+	fmt.Fprintf(w, "Duplicate groups: %d (%d files to delete)\n", p.TotalDuplicates, p.FilesToDelete)
+	fmt.Fprintf(w, "Compliance issues: %d\n", p.ComplianceIssues)
+	if p.OrphanFolders > 0 {
+		fmt.Fprintf(w, "Orphaned folders: %d\n", p.OrphanFolders)
+	}
+	fmt.Fprintf(w, "Space to free: %s\n", formatBytes(p.SpaceToFree))
+
+	if len(p.Operations) == 0 {
+		fmt.Fprintln(w, "\nNothing to clean.")
+	} else {
+		fmt.Fprintf(w, "\nThe clean would run %d of %d operation(s):\n", p.Count(PreviewReady), len(p.Operations))
+		for i, op := range p.Operations {
+			line := fmt.Sprintf("  %d. %s %s", i+1, op.Action, op.Source)
+			if op.Target != "" {
+				line += " -> " + op.Target
+			}
+			if op.Status != PreviewReady {
+				line += " [" + op.Status + "]"
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+
+	if len(p.Errors) > 0 {
+		fmt.Fprintf(w, "\nErrors: %d\n", len(p.Errors))
+		for i, err := range p.Errors {
+			fmt.Fprintf(w, "  %d. %s\n", i+1, err)
+		}
+	}
+	_, err := fmt.Fprintln(w, "\nDry run: no files were changed.")
+	return err
+}
+
+// WritePreviewJSON writes p as indented JSON
+func WritePreviewJSON(w io.Writer, p Preview) error {
+	if p.Operations == nil {
+		p.Operations = []PreviewOperation{}
+	}
+	if p.Errors == nil {
+		p.Errors = []string{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
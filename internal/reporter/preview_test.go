@@ -0,0 +1,56 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWritePreview(t *testing.T) {
+	p := Preview{
+		Report:          "/reports/scan.json",
+		TotalDuplicates: 1,
+		FilesToDelete:   1,
+		SpaceToFree:     2048,
+		Operations: []PreviewOperation{
+			{Action: "delete", Source: "/movies/heat.720p.mkv", Status: PreviewReady},
+			{Action: "rename", Source: "/tv/Firefly/Season 1", Target: "/tv/Firefly/Season 01", Status: PreviewFailed},
+			{Action: "delete", Source: "/movies/keep/heat.mkv", Status: "skipped: protected"},
+		},
+		Errors: []string{"cannot rename /tv/Firefly/Season 1: permission denied"},
+	}
+
+	var text bytes.Buffer
+	if err := WritePreview(&text, p); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Report: /reports/scan.json",
+		"would run 1 of 3 operation(s)",
+		"rename /tv/Firefly/Season 1 -> /tv/Firefly/Season 01 [failed]",
+		"[skipped: protected]",
+		"Errors: 1",
+		"no files were changed",
+	} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("Expected %q in the preview, got:\n%s", want, text.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WritePreviewJSON(&buf, Preview{Report: p.Report}); err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Preview JSON does not decode: %v", err)
+	}
+	// Empty lists stay lists so scripts can index them
+	if ops, ok := decoded["operations"].([]any); !ok || len(ops) != 0 {
+		t.Errorf("Expected an empty operations list, got %v", decoded["operations"])
+	}
+	if errs, ok := decoded["errors"].([]any); !ok || len(errs) != 0 {
+		t.Errorf("Expected an empty errors list, got %v", decoded["errors"])
+	}
+}
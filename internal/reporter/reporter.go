@@ -8,6 +8,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/jellyfin"
 	"github.com/Nomadcxx/jellysink/internal/scanner"
 )
 
@@ -23,14 +25,37 @@ type Report struct {
 	Timestamp          time.Time
 	LibraryType        string // "movies" or "tv"
 	LibraryPaths       []string
+	Libraries          []string `json:",omitempty"` // names of the libraries scanned, when not all of them were
 	MovieDuplicates    []scanner.MovieDuplicate
 	TVDuplicates       []scanner.TVDuplicate
 	ComplianceIssues   []scanner.ComplianceIssue
-	AmbiguousTVShows   []*scanner.TVTitleResolution // TV shows needing manual review
-	LooseFiles         []scanner.LooseFile          // Files not in proper Jellyfin structure
+	AmbiguousTVShows   []*scanner.TVTitleResolution    // TV shows needing manual review
+	AmbiguousMovies    []*scanner.MovieTitleResolution `json:",omitempty"` // movie titles TMDB/OMDB did not confirm
+	LooseFiles         []scanner.LooseFile             // Files not in proper Jellyfin structure
+	OrphanFolders      []scanner.OrphanFolder          `json:",omitempty"` // TV show/season folders without video files
+	Artifacts          []scanner.Artifact              `json:",omitempty"` // leftover junk, removed only by "jellysink artifacts --delete"
+	Quality            []scanner.QualityFinding        `json:",omitempty"` // informational library-quality findings; never cleaned
+	Completeness       []scanner.ShowCompleteness      `json:",omitempty"` // aired episodes on disk per show, from TVDB
 	TotalDuplicates    int
 	TotalFilesToDelete int
 	SpaceToFree        int64
+	Cleaned            *CleanSummary              `json:",omitempty"` // set once the report has been cleaned
+	APIDiagnostics     []scanner.APIProviderStats `json:",omitempty"` // per-provider API lookup outcomes
+	PassTimings        []scanner.PassTiming       `json:",omitempty"` // how long the library walk and each section took
+	Jellyfin           *jellyfin.Comparison       `json:",omitempty"` // on-disk files vs Jellyfin items, when compare is enabled
+	Simulated          bool                       `json:",omitempty"` // built by jellysinkd --test from a synthetic library; its paths do not exist
+	Partial            *PartialScan               `json:",omitempty"` // set when the scan was cancelled before every section finished
+	Changes            *ReportChanges             `json:",omitempty"` // compared with the previous scan of the same libraries
+}
+
+// APIDegraded reports whether any API provider failed or was skipped during the scan
+func (r Report) APIDegraded() bool {
+	for _, d := range r.APIDiagnostics {
+		if d.Degraded() {
+			return true
+		}
+	}
+	return false
 }
 
 // ReportFiles holds paths to generated report files
@@ -40,17 +65,28 @@ type ReportFiles struct {
 	Compliance string // Detailed compliance report (F2)
 }
 
+// RemoveReport deletes the JSON report at path and the text reports
+// written alongside it
+func RemoveReport(path string) error {
+	base := strings.TrimSuffix(path, ".json")
+	for _, file := range []string{path, base + "_summary.txt", base + "_duplicates.txt", base + "_compliance.txt", DiffPath(path)} {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove report: %w", err)
+		}
+	}
+	return nil
+}
+
 // Generate creates a timestamped report file (legacy - generates single comprehensive report)
 func Generate(report Report) (string, error) {
 	// Create report directory
-	reportDir := getReportDir()
-	if err := os.MkdirAll(reportDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create report directory: %w", err)
+	reportDir, err := EnsureReportDir()
+	if err != nil {
+		return "", err
 	}
 
-	// Generate filename with timestamp
-	timestamp := report.Timestamp.Format("20060102_150405")
-	filename := filepath.Join(reportDir, timestamp+".txt")
+	// Generate filename from the configured template
+	filename := filepath.Join(reportDir, BaseName(report.Timestamp, report.LibraryType)+".txt")
 
 	// Build report content
 	content := buildReportContent(report)
@@ -74,20 +110,20 @@ func GenerateDetailedWithProgress(report Report, pr *scanner.ProgressReporter) (
 		pr.Update(0, "Creating report directory")
 	}
 
-	reportDir := getReportDir()
-	if err := os.MkdirAll(reportDir, 0755); err != nil {
+	reportDir, err := EnsureReportDir()
+	if err != nil {
 		if pr != nil {
 			pr.LogError(err, "Failed to create report directory")
 		}
-		return ReportFiles{}, fmt.Errorf("failed to create report directory: %w", err)
+		return ReportFiles{}, err
 	}
 
-	timestamp := report.Timestamp.Format("20060102_150405")
+	baseName := BaseName(report.Timestamp, report.LibraryType)
 
 	files := ReportFiles{
-		Summary:    filepath.Join(reportDir, timestamp+"_summary.txt"),
-		Duplicates: filepath.Join(reportDir, timestamp+"_duplicates.txt"),
-		Compliance: filepath.Join(reportDir, timestamp+"_compliance.txt"),
+		Summary:    filepath.Join(reportDir, baseName+"_summary.txt"),
+		Duplicates: filepath.Join(reportDir, baseName+"_duplicates.txt"),
+		Compliance: filepath.Join(reportDir, baseName+"_compliance.txt"),
 	}
 
 	if pr != nil {
@@ -136,19 +172,62 @@ func GenerateDetailedWithProgress(report Report, pr *scanner.ProgressReporter) (
 	return files, nil
 }
 
-// getReportDir returns the report directory path
-func getReportDir() string {
-	// If running with sudo, use the real user's home directory
-	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" {
-		home := "/home/" + sudoUser
-		return filepath.Join(home, ".local/share/jellysink/scan_results")
-	}
+// output holds the [reports] settings used by every report writer
+var output = config.DefaultConfig().Reports
 
-	home, err := os.UserHomeDir()
-	if err != nil {
+// SetOutput applies the [reports] config section (directory and filename template)
+func SetOutput(reports config.ReportsConfig) {
+	output = reports
+}
+
+// ReportDir returns the configured report directory
+// Uses the real user's home when running with sudo
+func ReportDir() string {
+	home, err := realUserHome()
+	if err != nil && output.Dir == "" {
 		return "/tmp/jellysink/scan_results"
 	}
-	return filepath.Join(home, ".local/share/jellysink/scan_results")
+	return output.ResolveDir(home)
+}
+
+// EnsureReportDir creates the report directory and returns it
+// If a configured directory (e.g. an unmounted network share) cannot be
+// created, reports fall back to the default local directory so a scan is
+// never lost
+func EnsureReportDir() (string, error) {
+	reportDir := ReportDir()
+	err := os.MkdirAll(reportDir, 0755)
+	if err == nil {
+		return reportDir, nil
+	}
+	if output.Dir == "" {
+		return "", fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	home, homeErr := realUserHome()
+	if homeErr != nil {
+		return "", fmt.Errorf("failed to create report directory: %w", err)
+	}
+	fallback := config.ReportsConfig{}.ResolveDir(home)
+	if fallbackErr := os.MkdirAll(fallback, 0755); fallbackErr != nil {
+		return "", fmt.Errorf("failed to create report directory: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Warning: report directory %s unavailable (%v), writing to %s\n", reportDir, err, fallback)
+	return fallback, nil
+}
+
+// BaseName returns the report filename without extension, expanded from
+// reports.filename_template
+func BaseName(ts time.Time, libraryType string) string {
+	return output.Filename(ts, libraryType)
+}
+
+// realUserHome returns SUDO_USER's home when running with sudo
+func realUserHome() (string, error) {
+	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" {
+		return filepath.Join("/home", sudoUser), nil
+	}
+	return os.UserHomeDir()
 }
 
 // buildReportContent generates the report text
@@ -161,6 +240,9 @@ func buildReportContent(report Report) string {
 	sb.WriteString(fmt.Sprintf("Generated: %s\n", report.Timestamp.Format("2006-01-02 15:04:05")))
 	sb.WriteString(fmt.Sprintf("Library Type: %s\n", report.LibraryType))
 	sb.WriteString(fmt.Sprintf("Library Paths: %s\n", strings.Join(report.LibraryPaths, ", ")))
+	if report.Partial != nil {
+		sb.WriteString(report.Partial.Banner() + "\n")
+	}
 	sb.WriteString("\n")
 
 	// Summary
@@ -208,13 +290,58 @@ func buildReportContent(report Report) string {
 		sb.WriteString("COMPLIANCE ISSUES\n")
 		sb.WriteString(strings.Repeat("=", 80) + "\n")
 		for i, issue := range report.ComplianceIssues {
-			sb.WriteString(fmt.Sprintf("%d. [%s] %s\n", i+1, strings.ToUpper(issue.Type), issue.Problem))
+			sb.WriteString(fmt.Sprintf("%d. [%s] [%s] %s\n", i+1, strings.ToUpper(issue.Type), strings.ToUpper(issue.EffectiveSeverity()), issue.Problem))
 			sb.WriteString(fmt.Sprintf("   Current:  %s\n", issue.Path))
 			sb.WriteString(fmt.Sprintf("   Suggested: %s\n", issue.SuggestedPath))
 			sb.WriteString(fmt.Sprintf("   Action: %s\n\n", issue.SuggestedAction))
 		}
 	}
 
+	if len(report.OrphanFolders) > 0 {
+		sb.WriteString("ORPHANED FOLDERS\n")
+		sb.WriteString(strings.Repeat("=", 80) + "\n")
+		for i, orphan := range report.OrphanFolders {
+			sb.WriteString(fmt.Sprintf("%d. [%s] %s (%d leftover files, %s)\n",
+				i+1, strings.ToUpper(orphan.Kind), orphan.Path, orphan.Files, formatBytes(orphan.Size)))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(report.Artifacts) > 0 {
+		sb.WriteString("ARTIFACTS\n")
+		sb.WriteString(strings.Repeat("=", 80) + "\n")
+		for i, artifact := range report.Artifacts {
+			sb.WriteString(fmt.Sprintf("%d. [%s] %s (%s)\n",
+				i+1, strings.ToUpper(artifact.Kind), artifact.Path, formatBytes(artifact.Size)))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(report.Quality) > 0 {
+		sb.WriteString("LIBRARY QUALITY (INFORMATIONAL)\n")
+		sb.WriteString(strings.Repeat("=", 80) + "\n")
+		for i, finding := range report.Quality {
+			sb.WriteString(fmt.Sprintf("%d. [%s] %s: %s\n", i+1, strings.ToUpper(finding.Type), finding.Path, finding.Detail))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(report.Completeness) > 0 {
+		sb.WriteString("SERIES COMPLETENESS (TVDB AIRED EPISODES)\n")
+		sb.WriteString(strings.Repeat("=", 80) + "\n")
+		for i, show := range report.Completeness {
+			sb.WriteString(fmt.Sprintf("%d. %s: %.0f%% (%d of %d aired episodes)\n",
+				i+1, show.Path, show.Percent(), show.Have, show.Aired))
+			for _, season := range show.Seasons {
+				if len(season.Missing) > 0 {
+					sb.WriteString(fmt.Sprintf("   Season %02d: %d of %d, missing %s\n",
+						season.Season, season.Have, season.Aired, FormatEpisodeNumbers(season.Missing)))
+				}
+			}
+		}
+		sb.WriteString("\n")
+	}
+
 	// Footer with deletion list (machine-readable section)
 	sb.WriteString("\n")
 	sb.WriteString(strings.Repeat("=", 80) + "\n")
@@ -245,11 +372,15 @@ type Offender struct {
 }
 
 // GetTopOffenders returns top duplicate groups by space saved (up to MaxTopOffenders)
+// Groups excluded from cleaning save nothing and are left out
 func GetTopOffenders(report Report) []Offender {
 	var offenders []Offender
 
 	// Add movie duplicates
 	for _, dup := range report.MovieDuplicates {
+		if dup.Excluded {
+			continue
+		}
 		space := int64(0)
 		for i := 1; i < len(dup.Files); i++ {
 			space += dup.Files[i].Size
@@ -269,12 +400,15 @@ func GetTopOffenders(report Report) []Offender {
 
 	// Add TV duplicates
 	for _, dup := range report.TVDuplicates {
+		if dup.Excluded {
+			continue
+		}
 		space := int64(0)
 		for i := 1; i < len(dup.Files); i++ {
 			space += dup.Files[i].Size
 		}
 
-		name := fmt.Sprintf("%s S%02dE%02d", dup.ShowName, dup.Season, dup.Episode)
+		name := dup.ShowName + " " + dup.EpisodeLabel()
 
 		offenders = append(offenders, Offender{
 			Name:        name,
@@ -283,9 +417,12 @@ func GetTopOffenders(report Report) []Offender {
 		})
 	}
 
-	// Sort by space descending
+	// Sort by space descending, name breaks ties so output is deterministic
 	sort.Slice(offenders, func(i, j int) bool {
-		return offenders[i].SpaceToFree > offenders[j].SpaceToFree
+		if offenders[i].SpaceToFree != offenders[j].SpaceToFree {
+			return offenders[i].SpaceToFree > offenders[j].SpaceToFree
+		}
+		return offenders[i].Name < offenders[j].Name
 	})
 
 	// Return top N offenders
@@ -303,12 +440,30 @@ func formatMovieDuplicate(dup scanner.MovieDuplicate) string {
 	if dup.Year != "" {
 		title = title + " (" + dup.Year + ")"
 	}
+	if dup.Version != "" {
+		title += " - " + dup.Version
+	}
 
 	sb.WriteString(fmt.Sprintf("%s (%d versions):\n", title, len(dup.Files)))
+	if dup.ID != "" {
+		sb.WriteString(fmt.Sprintf("  ID:     %s\n", dup.ID))
+	}
+	sb.WriteString(fmt.Sprintf("  Scope:  %s\n", dup.Scope.Describe(dup.Within)))
+	if dup.Excluded {
+		sb.WriteString("  Excluded from cleaning: every file is kept\n")
+	}
+	if dup.Hardlinked() {
+		sb.WriteString("  Hardlinks of one file: cleaning removes paths but frees no space\n")
+	}
 
+	if dup.KeepsAllVersions() && !dup.Excluded {
+		return sb.String() + formatMultiVersion(dup)
+	}
+
+	freed := dup.ReclaimableSizes()
 	for i, file := range dup.Files {
 		marker := "  DELETE:"
-		if i == 0 {
+		if i == 0 || dup.Excluded {
 			marker = "  KEEP:  "
 		}
 
@@ -318,8 +473,53 @@ func formatMovieDuplicate(dup scanner.MovieDuplicate) string {
 			file.Resolution,
 			filepath.Base(file.Path)))
 		sb.WriteString(fmt.Sprintf("          %s\n", file.Path))
+		sb.WriteString(formatLink(i, file.Inode, freed[i], dup.Excluded))
+		sb.WriteString(formatProbe(file.Probe))
+	}
+
+	return sb.String()
+}
+
+// formatLink notes under a delete candidate's path that it is a hardlink
+// whose deletion frees no space
+func formatLink(i int, inode string, freed int64, excluded bool) string {
+	if i == 0 || excluded || inode == "" || freed > 0 {
+		return ""
+	}
+	return "          hardlink: another link stays, frees no space\n"
+}
+
+// formatProbe shows a file's probed streams under its path
+func formatProbe(info *scanner.MediaInfo) string {
+	if info == nil {
+		return ""
+	}
+	return fmt.Sprintf("          probed: %s\n", info.Summary())
+}
+
+// formatMultiVersion lists the renames that keep every copy as a Jellyfin version
+func formatMultiVersion(dup scanner.MovieDuplicate) string {
+	var sb strings.Builder
+
+	renames, err := scanner.MultiVersionPlan(dup)
+	if err != nil {
+		sb.WriteString(fmt.Sprintf("  Keep all as versions - not possible: %v\n", err))
+	} else {
+		sb.WriteString(fmt.Sprintf("  Keep all as versions in: %s\n", filepath.Dir(dup.Files[0].Path)))
 	}
 
+	targets := make(map[string]string, len(renames))
+	for _, r := range renames {
+		targets[r.Source] = r.Target
+	}
+	for _, file := range dup.Files {
+		sb.WriteString(fmt.Sprintf("  VERSION: [%s] [%s] %s\n", formatBytes(file.Size), file.Resolution, filepath.Base(file.Path)))
+		if target, ok := targets[file.Path]; ok {
+			sb.WriteString(fmt.Sprintf("          -> %s\n", filepath.Base(target)))
+		}
+		sb.WriteString(fmt.Sprintf("          %s\n", file.Path))
+		sb.WriteString(formatProbe(file.Probe))
+	}
 	return sb.String()
 }
 
@@ -327,12 +527,23 @@ func formatMovieDuplicate(dup scanner.MovieDuplicate) string {
 func formatTVDuplicate(dup scanner.TVDuplicate) string {
 	var sb strings.Builder
 
-	title := fmt.Sprintf("%s S%02dE%02d", dup.ShowName, dup.Season, dup.Episode)
+	title := dup.ShowName + " " + dup.EpisodeLabel()
 	sb.WriteString(fmt.Sprintf("%s (%d versions):\n", title, len(dup.Files)))
+	if dup.ID != "" {
+		sb.WriteString(fmt.Sprintf("  ID:     %s\n", dup.ID))
+	}
+	sb.WriteString(fmt.Sprintf("  Scope:  %s\n", dup.Scope.Describe(dup.Within)))
+	if dup.Excluded {
+		sb.WriteString("  Excluded from cleaning: every file is kept\n")
+	}
+	if dup.Hardlinked() {
+		sb.WriteString("  Hardlinks of one file: cleaning removes paths but frees no space\n")
+	}
 
+	freed := dup.ReclaimableSizes()
 	for i, file := range dup.Files {
 		marker := "  DELETE:"
-		if i == 0 {
+		if i == 0 || dup.Excluded {
 			marker = "  KEEP:  "
 		}
 
@@ -343,6 +554,8 @@ func formatTVDuplicate(dup scanner.TVDuplicate) string {
 			file.Source,
 			filepath.Base(file.Path)))
 		sb.WriteString(fmt.Sprintf("          %s\n", file.Path))
+		sb.WriteString(formatLink(i, file.Inode, freed[i], dup.Excluded))
+		sb.WriteString(formatProbe(file.Probe))
 	}
 
 	return sb.String()
@@ -373,6 +586,13 @@ func buildSummaryReport(report Report) string {
 	sb.WriteString(fmt.Sprintf("Generated: %s\n", report.Timestamp.Format("2006-01-02 15:04:05")))
 	sb.WriteString(fmt.Sprintf("Library Type: %s\n", report.LibraryType))
 	sb.WriteString(fmt.Sprintf("Library Paths: %s\n\n", strings.Join(report.LibraryPaths, ", ")))
+	if report.Partial != nil {
+		sb.WriteString(report.Partial.Banner() + "\n\n")
+	}
+	if report.Changes != nil {
+		sb.WriteString(report.Changes.Headline() + "\n")
+		sb.WriteString(fmt.Sprintf("Compared with: %s (jellysink diff <report> lists every change)\n\n", report.Changes.Previous))
+	}
 
 	// Duplicates summary with examples
 	sb.WriteString("DUPLICATES\n")
@@ -402,16 +622,30 @@ func buildSummaryReport(report Report) string {
 
 	// Count manual intervention items (ambiguous but not API-verified)
 	manualInterventionCount := 0
+	offlineCount := 0
 	for _, res := range report.AmbiguousTVShows {
 		if !res.APIVerified {
 			manualInterventionCount++
 		}
+		if res.APIStatus != "" {
+			offlineCount++
+		}
 	}
 	if manualInterventionCount > 0 {
 		sb.WriteString(fmt.Sprintf("Items needing manual review: %d\n", manualInterventionCount))
 	}
+	if len(report.AmbiguousMovies) > 0 {
+		sb.WriteString(fmt.Sprintf("Movie titles to review: %d\n", len(report.AmbiguousMovies)))
+	}
+	if offlineCount > 0 {
+		sb.WriteString(fmt.Sprintf("API verification %s for %d shows (retried on the next scan)\n", scanner.ErrAPIOffline, offlineCount))
+	}
 	sb.WriteString("\n")
 
+	writeAPIDiagnostics(&sb, report.APIDiagnostics)
+	writePassTimings(&sb, report.PassTimings)
+	writeJellyfinComparison(&sb, report.Jellyfin)
+
 	// Loose files summary
 	if len(report.LooseFiles) > 0 {
 		sb.WriteString("LOOSE FILES\n")
@@ -474,6 +708,11 @@ func buildSummaryReport(report Report) string {
 		sb.WriteString("\n")
 	}
 
+	writeOrphanFolders(&sb, report.OrphanFolders)
+	writeArtifacts(&sb, report.Artifacts)
+	writeQuality(&sb, report.Quality)
+	writeCompleteness(&sb, report.Completeness)
+
 	// Actions
 	sb.WriteString("ACTIONS\n")
 	sb.WriteString(strings.Repeat("-", 80) + "\n")
@@ -485,12 +724,267 @@ func buildSummaryReport(report Report) string {
 	if len(report.LooseFiles) > 0 {
 		sb.WriteString("  [F4] Organize loose files (move to proper structure)\n")
 	}
-	sb.WriteString("  [Enter] Clean (delete duplicates + fix compliance)\n")
+	if report.Jellyfin.HasFindings() {
+		sb.WriteString("  [F5] Jellyfin fixes (renames that let Jellyfin match unmatched files)\n")
+	}
+	if len(report.OrphanFolders) > 0 {
+		sb.WriteString("  [Enter] Clean (delete duplicates + fix compliance + remove orphaned folders)\n")
+	} else {
+		sb.WriteString("  [Enter] Clean (delete duplicates + fix compliance)\n")
+	}
 	sb.WriteString("  [Esc] Skip cleaning\n")
 
 	return sb.String()
 }
 
+// writeOrphanFolders summarizes show and season folders left without videos
+func writeOrphanFolders(sb *strings.Builder, orphans []scanner.OrphanFolder) {
+	if len(orphans) == 0 {
+		return
+	}
+
+	shows, seasons := 0, 0
+	for _, orphan := range orphans {
+		if orphan.Kind == "show" {
+			shows++
+		} else {
+			seasons++
+		}
+	}
+
+	sb.WriteString("ORPHANED FOLDERS\n")
+	sb.WriteString(strings.Repeat("-", 80) + "\n")
+	sb.WriteString(fmt.Sprintf("Show folders without videos: %d\n", shows))
+	sb.WriteString(fmt.Sprintf("Season folders without episodes: %d\n\n", seasons))
+
+	sb.WriteString(fmt.Sprintf("Examples (first %d):\n", MaxExampleOffenders))
+	limit := MaxExampleOffenders
+	if len(orphans) < limit {
+		limit = len(orphans)
+	}
+	for i := 0; i < limit; i++ {
+		sb.WriteString(fmt.Sprintf("  %d. [%s] %s\n", i+1, strings.ToUpper(orphans[i].Kind), orphans[i].Path))
+	}
+	sb.WriteString("\n")
+}
+
+// writeArtifacts summarizes leftover junk by kind
+func writeArtifacts(sb *strings.Builder, artifacts []scanner.Artifact) {
+	if len(artifacts) == 0 {
+		return
+	}
+
+	counts := make(map[string]int)
+	var size int64
+	for _, artifact := range artifacts {
+		counts[artifact.Kind]++
+		size += artifact.Size
+	}
+
+	sb.WriteString("ARTIFACTS\n")
+	sb.WriteString(strings.Repeat("-", 80) + "\n")
+	for _, kind := range []struct{ kind, label string }{
+		{scanner.ArtifactEmptyFolder, "Empty folders"},
+		{scanner.ArtifactLeftoverFolder, "Movie folders with only leftovers"},
+		{scanner.ArtifactMetadata, "nfo/artwork without a video"},
+		{scanner.ArtifactJunk, "Release junk files"},
+		{scanner.ArtifactSample, "Sample clips"},
+		{scanner.ArtifactPartialDownload, "Abandoned partial downloads"},
+	} {
+		if counts[kind.kind] > 0 {
+			sb.WriteString(fmt.Sprintf("%s: %d\n", kind.label, counts[kind.kind]))
+		}
+	}
+	sb.WriteString(fmt.Sprintf("Space taken: %s\n\n", formatBytes(size)))
+
+	sb.WriteString(fmt.Sprintf("Examples (first %d):\n", MaxExampleOffenders))
+	limit := MaxExampleOffenders
+	if len(artifacts) < limit {
+		limit = len(artifacts)
+	}
+	for i := 0; i < limit; i++ {
+		sb.WriteString(fmt.Sprintf("  %d. [%s] %s\n", i+1, strings.ToUpper(artifacts[i].Kind), artifacts[i].Path))
+	}
+	sb.WriteString("Preview their removal with: jellysink artifacts <report>\n\n")
+}
+
+// writeQuality summarizes the library-quality findings by kind
+func writeQuality(sb *strings.Builder, findings []scanner.QualityFinding) {
+	if len(findings) == 0 {
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, finding := range findings {
+		counts[finding.Kind]++
+	}
+
+	sb.WriteString("LIBRARY QUALITY (INFORMATIONAL)\n")
+	sb.WriteString(strings.Repeat("-", 80) + "\n")
+	for _, kind := range qualityKinds {
+		if counts[kind.kind] > 0 {
+			sb.WriteString(fmt.Sprintf("%s: %d\n", kind.label, counts[kind.kind]))
+		}
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(fmt.Sprintf("Examples (first %d):\n", MaxExampleOffenders))
+	limit := MaxExampleOffenders
+	if len(findings) < limit {
+		limit = len(findings)
+	}
+	for i := 0; i < limit; i++ {
+		sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, filepath.Base(findings[i].Path)))
+		sb.WriteString(fmt.Sprintf("     %s\n", findings[i].Detail))
+	}
+	sb.WriteString("Cleans leave these files alone; the full list is in the compliance report.\n\n")
+}
+
+// writeCompleteness summarizes how complete the shows are against TVDB,
+// listing the least complete ones
+func writeCompleteness(sb *strings.Builder, shows []scanner.ShowCompleteness) {
+	if len(shows) == 0 {
+		return
+	}
+
+	var have, aired int
+	var incomplete []scanner.ShowCompleteness
+	for _, show := range shows {
+		have += show.Have
+		aired += show.Aired
+		if !show.Complete() {
+			incomplete = append(incomplete, show)
+		}
+	}
+	sort.SliceStable(incomplete, func(i, j int) bool {
+		return incomplete[i].Percent() < incomplete[j].Percent()
+	})
+
+	sb.WriteString("SERIES COMPLETENESS\n")
+	sb.WriteString(strings.Repeat("-", 80) + "\n")
+	sb.WriteString(fmt.Sprintf("Shows compared with TVDB: %d (%d complete)\n", len(shows), len(shows)-len(incomplete)))
+	sb.WriteString(fmt.Sprintf("Aired episodes on disk: %d of %d\n\n", have, aired))
+	if len(incomplete) == 0 {
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf("Least complete (first %d):\n", MaxExampleOffenders))
+	limit := MaxExampleOffenders
+	if len(incomplete) < limit {
+		limit = len(incomplete)
+	}
+	for i := 0; i < limit; i++ {
+		show := incomplete[i]
+		sb.WriteString(fmt.Sprintf("  %d. %s: %.0f%% (%d of %d)\n", i+1, filepath.Base(show.Path), show.Percent(), show.Have, show.Aired))
+	}
+	sb.WriteString("The missing episodes are listed in the full report.\n\n")
+}
+
+// FormatEpisodeNumbers renders sorted episode numbers with runs collapsed,
+// e.g. "E01-E03, E07"
+func FormatEpisodeNumbers(numbers []int) string {
+	var parts []string
+	for i := 0; i < len(numbers); {
+		j := i
+		for j+1 < len(numbers) && numbers[j+1] == numbers[j]+1 {
+			j++
+		}
+		if j == i {
+			parts = append(parts, fmt.Sprintf("E%02d", numbers[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("E%02d-E%02d", numbers[i], numbers[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
+
+// qualityKinds labels the library-quality finding kinds, in report order
+var qualityKinds = []struct{ kind, label string }{
+	{scanner.QualityMissingAudio, "Files missing a required audio language"},
+	{scanner.QualityMissingSubtitles, "Files missing a required subtitle language"},
+	{scanner.QualityUnwantedTracks, "Files with many unwanted language tracks"},
+	{scanner.QualityBitrateOutlier, "Quality inconsistencies (episodes unlike the rest of their season)"},
+}
+
+// writeAPIDiagnostics lists per-provider lookup counts and the last error
+func writeAPIDiagnostics(sb *strings.Builder, diags []scanner.APIProviderStats) {
+	if len(diags) == 0 {
+		return
+	}
+	sb.WriteString("API DIAGNOSTICS\n")
+	sb.WriteString(strings.Repeat("-", 80) + "\n")
+	for _, d := range diags {
+		status := "ok"
+		if d.Degraded() {
+			status = "DEGRADED"
+		}
+		sb.WriteString(fmt.Sprintf("%-6s %-8s %d succeeded, %d failed, %d skipped (offline)\n",
+			d.Provider+":", status, d.Successes, d.Failures, d.Skipped))
+		if d.LastError != "" {
+			sb.WriteString(fmt.Sprintf("       Last error: %s\n", d.LastError))
+		}
+	}
+	sb.WriteString("\n")
+}
+
+// writePassTimings lists how long the library walk and each section took
+func writePassTimings(sb *strings.Builder, timings []scanner.PassTiming) {
+	if len(timings) == 0 {
+		return
+	}
+	sb.WriteString("SCAN TIMING\n")
+	sb.WriteString(strings.Repeat("-", 80) + "\n")
+	var total time.Duration
+	for _, t := range timings {
+		sb.WriteString(fmt.Sprintf("%-18s %s\n", t.Pass+":", t.Duration.Round(time.Millisecond)))
+		total += t.Duration
+	}
+	sb.WriteString(fmt.Sprintf("%-18s %s\n\n", "total:", total.Round(time.Millisecond)))
+}
+
+// writeJellyfinComparison summarizes where the disk and the Jellyfin server disagree
+func writeJellyfinComparison(sb *strings.Builder, cmp *jellyfin.Comparison) {
+	if cmp == nil {
+		return
+	}
+	sb.WriteString("JELLYFIN COMPARISON\n")
+	sb.WriteString(strings.Repeat("-", 80) + "\n")
+	if cmp.Error != "" {
+		sb.WriteString(fmt.Sprintf("Comparison failed: %s\n\n", cmp.Error))
+		return
+	}
+	sb.WriteString(fmt.Sprintf("Server: %s (%d items, %d files on disk)\n", cmp.ServerURL, cmp.ServerItems, cmp.DiskFiles))
+	sb.WriteString(fmt.Sprintf("On disk but not in Jellyfin: %d\n", len(cmp.MissingFromServer)))
+	sb.WriteString(fmt.Sprintf("In Jellyfin but missing on disk: %d\n", len(cmp.MissingOnDisk)))
+	if !cmp.HasFindings() {
+		sb.WriteString("\n")
+		return
+	}
+
+	if len(cmp.MissingFromServer) > 0 {
+		sb.WriteString("\nNot matched by Jellyfin (usually a naming problem, see compliance issues):\n")
+		limit := min(len(cmp.MissingFromServer), MaxExampleOffenders)
+		for i := 0; i < limit; i++ {
+			sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, cmp.MissingFromServer[i]))
+		}
+		if len(cmp.MissingFromServer) > limit {
+			sb.WriteString(fmt.Sprintf("  ... and %d more\n", len(cmp.MissingFromServer)-limit))
+		}
+	}
+	if len(cmp.MissingOnDisk) > 0 {
+		sb.WriteString("\nListed by Jellyfin but gone from disk (rescan the library in Jellyfin):\n")
+		limit := min(len(cmp.MissingOnDisk), MaxExampleOffenders)
+		for i := 0; i < limit; i++ {
+			sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, cmp.MissingOnDisk[i].Path))
+		}
+		if len(cmp.MissingOnDisk) > limit {
+			sb.WriteString(fmt.Sprintf("  ... and %d more\n", len(cmp.MissingOnDisk)-limit))
+		}
+	}
+	sb.WriteString("\n")
+}
+
 // buildDuplicatesReport generates detailed duplicates report (F1)
 func buildDuplicatesReport(report Report) string {
 	var sb strings.Builder
@@ -532,9 +1026,12 @@ func buildComplianceReport(report Report) string {
 	sb.WriteString(strings.Repeat("=", 80) + "\n")
 	sb.WriteString(fmt.Sprintf("Generated: %s\n\n", report.Timestamp.Format("2006-01-02 15:04:05")))
 
-	if len(report.ComplianceIssues) == 0 && len(report.AmbiguousTVShows) == 0 {
+	if len(report.ComplianceIssues) == 0 && len(report.AmbiguousTVShows) == 0 && len(report.AmbiguousMovies) == 0 {
 		sb.WriteString("No compliance issues found. All files follow Jellyfin naming conventions.\n")
-		return sb.String()
+		if len(report.Quality) == 0 {
+			return sb.String()
+		}
+		sb.WriteString("\n")
 	}
 
 	// Separate ambiguous shows into API-verified and manual intervention needed
@@ -582,7 +1079,14 @@ func buildComplianceReport(report Report) string {
 			}
 			sb.WriteString(fmt.Sprintf(" [confidence: %.2f]\n", res.FilenameMatch.Confidence))
 
-			sb.WriteString(fmt.Sprintf("   Issue:    %s\n\n", res.Reason))
+			sb.WriteString(fmt.Sprintf("   Issue:    %s\n", res.Reason))
+			if res.APIStatus != "" {
+				sb.WriteString(fmt.Sprintf("   API:      %s\n", res.APIStatus))
+			}
+			if res.JellyfinTitle != "" {
+				sb.WriteString(fmt.Sprintf("   Jellyfin: %s\n", res.JellyfinTitle))
+			}
+			sb.WriteString("\n")
 		}
 
 		sb.WriteString("\n")
@@ -624,6 +1128,26 @@ func buildComplianceReport(report Report) string {
 		sb.WriteString("\n")
 	}
 
+	// Movies whose suggested title TMDB/OMDB did not confirm
+	if len(report.AmbiguousMovies) > 0 {
+		sb.WriteString("MOVIE TITLES TO REVIEW\n")
+		sb.WriteString(strings.Repeat("=", 80) + "\n")
+		sb.WriteString(fmt.Sprintf("Total movies to review: %d\n\n", len(report.AmbiguousMovies)))
+		sb.WriteString("The title these movies would be renamed to was not found on TMDB/OMDB.\n")
+		sb.WriteString("Press F7 to choose the title for each one.\n\n")
+
+		for i, res := range report.AmbiguousMovies {
+			sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, res.Path))
+			sb.WriteString(fmt.Sprintf("   Suggested: %s\n", formatTitleYear(res.Title, res.Year)))
+			if res.APITitle != "" {
+				sb.WriteString(fmt.Sprintf("   %-9s  %s\n", res.Provider+":", formatTitleYear(res.APITitle, res.APIYear)))
+			}
+			sb.WriteString(fmt.Sprintf("   Issue:     %s\n\n", res.Reason))
+		}
+
+		sb.WriteString("\n")
+	}
+
 	// Standard Compliance Issues Section
 	if len(report.ComplianceIssues) > 0 {
 		sb.WriteString("NON-COMPLIANT FILES AND FOLDERS\n")
@@ -631,12 +1155,48 @@ func buildComplianceReport(report Report) string {
 		sb.WriteString(fmt.Sprintf("Total issues: %d\n\n", len(report.ComplianceIssues)))
 
 		for i, issue := range report.ComplianceIssues {
-			sb.WriteString(fmt.Sprintf("%d. [%s] %s\n", i+1, strings.ToUpper(issue.Type), issue.Problem))
+			sb.WriteString(fmt.Sprintf("%d. [%s] [%s] %s\n", i+1, strings.ToUpper(issue.Type), strings.ToUpper(issue.EffectiveSeverity()), issue.Problem))
 			sb.WriteString(fmt.Sprintf("   Current:  %s\n", issue.Path))
 			sb.WriteString(fmt.Sprintf("   Fixed:    %s\n", issue.SuggestedPath))
-			sb.WriteString(fmt.Sprintf("   Action:   %s\n\n", issue.SuggestedAction))
+			sb.WriteString(fmt.Sprintf("   Action:   %s\n", issue.SuggestedAction))
+			if issue.ID != "" {
+				sb.WriteString(fmt.Sprintf("   ID:       %s\n", issue.ID))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	// Library quality: informational, never cleaned
+	if len(report.Quality) > 0 {
+		sb.WriteString("LIBRARY QUALITY (INFORMATIONAL)\n")
+		sb.WriteString(strings.Repeat("=", 80) + "\n")
+		sb.WriteString(fmt.Sprintf("Total findings: %d\n\n", len(report.Quality)))
+		for _, kind := range qualityKinds {
+			n := 0
+			for _, finding := range report.Quality {
+				if finding.Kind != kind.kind {
+					continue
+				}
+				if n == 0 {
+					sb.WriteString(kind.label + ":\n")
+				}
+				n++
+				sb.WriteString(fmt.Sprintf("%d. [%s] %s\n", n, strings.ToUpper(finding.Type), finding.Path))
+				sb.WriteString(fmt.Sprintf("   %s\n", finding.Detail))
+			}
+			if n > 0 {
+				sb.WriteString("\n")
+			}
 		}
 	}
 
 	return sb.String()
 }
+
+// formatTitleYear renders "Title (Year)", or the bare title without a year
+func formatTitleYear(title, year string) string {
+	if year == "" {
+		return title
+	}
+	return fmt.Sprintf("%s (%s)", title, year)
+}
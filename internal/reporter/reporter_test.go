@@ -2,10 +2,14 @@ package reporter
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/jellyfin"
+	"github.com/Nomadcxx/jellysink/internal/oplog"
 	"github.com/Nomadcxx/jellysink/internal/scanner"
 )
 
@@ -183,6 +187,48 @@ func TestGenerate(t *testing.T) {
 	os.Remove(filename)
 }
 
+func TestReportOutputConfig(t *testing.T) {
+	defer SetOutput(config.DefaultConfig().Reports)
+
+	dir := filepath.Join(t.TempDir(), "share", "reports")
+	SetOutput(config.ReportsConfig{Dir: dir, FilenameTemplate: "{library}_{timestamp}"})
+
+	report := Report{
+		Timestamp:   time.Date(2024, 3, 9, 14, 5, 7, 0, time.UTC),
+		LibraryType: "movies",
+	}
+	files, err := GenerateDetailed(report)
+	if err != nil {
+		t.Fatalf("GenerateDetailed() error: %v", err)
+	}
+	if want := filepath.Join(dir, "movies_20240309_140507_summary.txt"); files.Summary != want {
+		t.Errorf("Summary path = %s, want %s", files.Summary, want)
+	}
+	if _, err := os.Stat(files.Compliance); err != nil {
+		t.Errorf("Compliance report not written to configured dir: %v", err)
+	}
+
+	// An unavailable share falls back to the default local directory
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SUDO_USER", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv(oplog.DataDirEnv, "")
+	blocker := filepath.Join(t.TempDir(), "not-a-dir")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	SetOutput(config.ReportsConfig{Dir: filepath.Join(blocker, "reports"), FilenameTemplate: "{timestamp}"})
+
+	got, err := EnsureReportDir()
+	if err != nil {
+		t.Fatalf("EnsureReportDir() error: %v", err)
+	}
+	if want := filepath.Join(home, ".local/share/jellysink", config.ReportSubdir); got != want {
+		t.Errorf("EnsureReportDir() fallback = %s, want %s", got, want)
+	}
+}
+
 func TestFormatMovieDuplicate(t *testing.T) {
 	dup := scanner.MovieDuplicate{
 		NormalizedName: "test movie",
@@ -248,3 +294,147 @@ func TestFormatTVDuplicate(t *testing.T) {
 		t.Error("Formatted output missing keeper path")
 	}
 }
+
+func TestReportsShowOfflineAPI(t *testing.T) {
+	report := Report{
+		Timestamp: time.Date(2025, 1, 20, 14, 30, 0, 0, time.UTC),
+		AmbiguousTVShows: []*scanner.TVTitleResolution{
+			{
+				ResolvedTitle: "Degrassi",
+				FolderMatch:   &scanner.TVTitleMatch{Title: "Degrassi"},
+				FilenameMatch: &scanner.TVTitleMatch{Title: "Degrassi the Next Generation"},
+				IsAmbiguous:   true,
+				APIStatus:     scanner.ErrAPIOffline.Error(),
+				Reason:        "Conflicting titles",
+			},
+		},
+	}
+
+	if summary := buildSummaryReport(report); !strings.Contains(summary, "API verification skipped: offline for 1 shows") {
+		t.Errorf("Summary missing offline notice:\n%s", summary)
+	}
+	if compliance := buildComplianceReport(report); !strings.Contains(compliance, "API:      skipped: offline") {
+		t.Errorf("Compliance report missing offline status:\n%s", compliance)
+	}
+}
+
+func TestSummaryAPIDiagnostics(t *testing.T) {
+	report := Report{Timestamp: time.Date(2025, 1, 20, 14, 30, 0, 0, time.UTC)}
+	if report.APIDegraded() || strings.Contains(buildSummaryReport(report), "API DIAGNOSTICS") {
+		t.Error("Expected no API diagnostics without lookups")
+	}
+
+	report.APIDiagnostics = []scanner.APIProviderStats{
+		{Provider: "OMDB", Successes: 4},
+		{Provider: "TVDB", Successes: 2, Failures: 1, Skipped: 5, LastError: "API request failed: connection refused"},
+	}
+	if !report.APIDegraded() {
+		t.Error("Expected report to be degraded")
+	}
+	summary := buildSummaryReport(report)
+	for _, want := range []string{
+		"API DIAGNOSTICS",
+		"OMDB:  ok       4 succeeded, 0 failed, 0 skipped (offline)",
+		"TVDB:  DEGRADED 2 succeeded, 1 failed, 5 skipped (offline)",
+		"Last error: API request failed: connection refused",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Summary missing %q:\n%s", want, summary)
+		}
+	}
+}
+
+func TestSummaryJellyfinComparison(t *testing.T) {
+	report := Report{Timestamp: time.Date(2025, 1, 20, 14, 30, 0, 0, time.UTC)}
+	if strings.Contains(buildSummaryReport(report), "JELLYFIN COMPARISON") {
+		t.Error("Expected no Jellyfin section when compare is disabled")
+	}
+
+	report.Jellyfin = &jellyfin.Comparison{
+		ServerURL:         "http://localhost:8096",
+		ServerItems:       2,
+		DiskFiles:         2,
+		MissingFromServer: []string{"/media/movies/heat.1995.mkv"},
+		MissingOnDisk:     []jellyfin.Item{{Name: "Alien", Path: "/media/movies/Alien (1979)/Alien (1979).mkv"}},
+	}
+	summary := buildSummaryReport(report)
+	for _, want := range []string{
+		"JELLYFIN COMPARISON",
+		"On disk but not in Jellyfin: 1",
+		"In Jellyfin but missing on disk: 1",
+		"1. /media/movies/heat.1995.mkv",
+		"1. /media/movies/Alien (1979)/Alien (1979).mkv",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Summary missing %q:\n%s", want, summary)
+		}
+	}
+
+	report.Jellyfin = &jellyfin.Comparison{Error: "Jellyfin rejected the API key (status 401)"}
+	if summary := buildSummaryReport(report); !strings.Contains(summary, "Comparison failed: Jellyfin rejected the API key") {
+		t.Errorf("Summary missing comparison error:\n%s", summary)
+	}
+}
+
+func TestReportsListOrphanFolders(t *testing.T) {
+	report := Report{Timestamp: time.Date(2025, 1, 20, 14, 30, 0, 0, time.UTC)}
+	if strings.Contains(buildSummaryReport(report), "ORPHANED FOLDERS") {
+		t.Error("Expected no orphan section without orphaned folders")
+	}
+
+	report.OrphanFolders = []scanner.OrphanFolder{
+		{Path: "/media/tv/Firefly (2002)", Kind: "show", Files: 2, Size: 2048},
+		{Path: "/media/tv/Lost (2004)/Season 02", Kind: "season", Files: 1, Size: 10},
+	}
+	summary := buildSummaryReport(report)
+	for _, want := range []string{
+		"Show folders without videos: 1",
+		"Season folders without episodes: 1",
+		"2. [SEASON] /media/tv/Lost (2004)/Season 02",
+		"remove orphaned folders",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Summary missing %q:\n%s", want, summary)
+		}
+	}
+
+	content := buildReportContent(report)
+	if !strings.Contains(content, "1. [SHOW] /media/tv/Firefly (2002) (2 leftover files, 2.00 KB)") {
+		t.Errorf("Report missing orphan details:\n%s", content)
+	}
+	// Folders are removed only on request, never listed with the deletions
+	if strings.Contains(strings.Split(content, "DELETION LIST")[1], "Firefly") {
+		t.Error("Orphaned folders should not be in the deletion list")
+	}
+}
+
+func TestReportsListSeriesCompleteness(t *testing.T) {
+	report := Report{Timestamp: time.Date(2025, 1, 20, 14, 30, 0, 0, time.UTC)}
+	report.Completeness = []scanner.ShowCompleteness{
+		{Path: "/media/tv/Firefly (2002)", Have: 14, Aired: 14, Seasons: []scanner.SeasonCompleteness{{Season: 1, Have: 14, Aired: 14}}},
+		{Path: "/media/tv/Lost (2004)", Have: 20, Aired: 25, Seasons: []scanner.SeasonCompleteness{
+			{Season: 1, Have: 20, Aired: 25, Missing: []int{3, 4, 5, 9, 12}},
+		}},
+	}
+
+	summary := buildSummaryReport(report)
+	for _, want := range []string{
+		"Shows compared with TVDB: 2 (1 complete)",
+		"Aired episodes on disk: 34 of 39",
+		"1. Lost (2004): 80% (20 of 25)",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Summary missing %q:\n%s", want, summary)
+		}
+	}
+
+	content := buildReportContent(report)
+	for _, want := range []string{
+		"1. /media/tv/Firefly (2002): 100% (14 of 14 aired episodes)",
+		"   Season 01: 20 of 25, missing E03-E05, E09, E12",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Report missing %q:\n%s", want, content)
+		}
+	}
+}
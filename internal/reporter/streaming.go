@@ -27,23 +27,23 @@ type StreamingReporter struct {
 
 // NewStreamingReporter creates a new streaming reporter
 func NewStreamingReporter(libraryType string, libraryPaths []string) (*StreamingReporter, error) {
-	reportDir := getReportDir()
-	if err := os.MkdirAll(reportDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create report directory: %w", err)
+	reportDir, err := EnsureReportDir()
+	if err != nil {
+		return nil, err
 	}
 
 	timestamp := time.Now()
-	timestampStr := timestamp.Format("20060102_150405")
+	baseName := BaseName(timestamp, libraryType)
 
 	// Create summary file
-	summaryPath := filepath.Join(reportDir, timestampStr+"_summary.txt")
+	summaryPath := filepath.Join(reportDir, baseName+"_summary.txt")
 	summaryFile, err := os.Create(summaryPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create summary file: %w", err)
 	}
 
 	// Create detail file
-	detailPath := filepath.Join(reportDir, timestampStr+"_duplicates.txt")
+	detailPath := filepath.Join(reportDir, baseName+"_duplicates.txt")
 	detailFile, err := os.Create(detailPath)
 	if err != nil {
 		summaryFile.Close()
@@ -133,6 +133,9 @@ func (sr *StreamingReporter) WriteMovieDuplicate(ctx context.Context, dup scanne
 		content += fmt.Sprintf("    %s %s\n", status, file.Path)
 		content += fmt.Sprintf("           Size: %s, Resolution: %s\n",
 			formatBytes(file.Size), file.Resolution)
+		if file.Probe != nil {
+			content += fmt.Sprintf("           Probed: %s\n", file.Probe.Summary())
+		}
 	}
 	content += fmt.Sprintf("\n")
 
@@ -166,7 +169,7 @@ func (sr *StreamingReporter) WriteTVDuplicate(ctx context.Context, dup scanner.T
 	}
 
 	// Write to detail file
-	content := fmt.Sprintf("TV Show: %s S%02dE%02d\n", dup.ShowName, dup.Season, dup.Episode)
+	content := fmt.Sprintf("TV Show: %s %s\n", dup.ShowName, dup.EpisodeLabel())
 	content += fmt.Sprintf("  Duplicate versions found: %d\n", len(dup.Files))
 	content += fmt.Sprintf("  Files to delete: %d\n", filesToDelete)
 	content += fmt.Sprintf("  Space to free: %s\n", formatBytes(sr.calculateTVGroupSpace(dup)))
@@ -180,6 +183,9 @@ func (sr *StreamingReporter) WriteTVDuplicate(ctx context.Context, dup scanner.T
 		content += fmt.Sprintf("    %s %s\n", status, file.Path)
 		content += fmt.Sprintf("           Size: %s, Resolution: %s, Source: %s\n",
 			formatBytes(file.Size), file.Resolution, file.Source)
+		if file.Probe != nil {
+			content += fmt.Sprintf("           Probed: %s\n", file.Probe.Summary())
+		}
 	}
 	content += fmt.Sprintf("\n")
 
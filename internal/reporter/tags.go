@@ -0,0 +1,93 @@
+package reporter
+
+import "github.com/Nomadcxx/jellysink/internal/tags"
+
+// RecountTotals recomputes the duplicate totals from the duplicate groups
+// Movie groups kept as multi-version sets and excluded groups delete nothing,
+// and hardlinks of files that stay free no space
+func (r *Report) RecountTotals() {
+	r.TotalDuplicates = len(r.MovieDuplicates) + len(r.TVDuplicates)
+	r.TotalFilesToDelete = 0
+	r.SpaceToFree = 0
+	for _, dup := range r.MovieDuplicates {
+		if dup.KeepsAllVersions() || dup.Excluded {
+			continue
+		}
+		for i := 1; i < len(dup.Files); i++ {
+			r.TotalFilesToDelete++
+		}
+		r.SpaceToFree += dup.Reclaimable()
+	}
+	for _, dup := range r.TVDuplicates {
+		if dup.Excluded {
+			continue
+		}
+		for i := 1; i < len(dup.Files); i++ {
+			r.TotalFilesToDelete++
+		}
+		r.SpaceToFree += dup.Reclaimable()
+	}
+}
+
+// ApplyKeepTags resolves the duplicate groups of policy-tagged movies and
+// shows by their policy, then moves keep-tagged files into the keeper slot
+// of their groups, and recounts the totals when anything changed
+func ApplyKeepTags(report *Report, rules *tags.Rules) {
+	changed := rules.ApplyPolicies(report.MovieDuplicates, report.TVDuplicates)
+	changed += rules.PreferKeepers(report.MovieDuplicates, report.TVDuplicates)
+	if changed > 0 {
+		report.RecountTotals()
+	}
+}
+
+// FilterByTag narrows report to the findings touching a path tagged tag:
+// duplicate groups with a tagged file, tagged compliance issues and loose files
+func FilterByTag(report Report, store *tags.Store, tag string) Report {
+	filtered := report
+	filtered.MovieDuplicates = nil
+	filtered.TVDuplicates = nil
+	filtered.ComplianceIssues = nil
+	filtered.LooseFiles = nil
+	filtered.OrphanFolders = nil
+	filtered.Artifacts = nil
+
+	for _, dup := range report.MovieDuplicates {
+		for _, file := range dup.Files {
+			if store.Has(file.Path, tag) {
+				filtered.MovieDuplicates = append(filtered.MovieDuplicates, dup)
+				break
+			}
+		}
+	}
+	for _, dup := range report.TVDuplicates {
+		for _, file := range dup.Files {
+			if store.Has(file.Path, tag) {
+				filtered.TVDuplicates = append(filtered.TVDuplicates, dup)
+				break
+			}
+		}
+	}
+	for _, issue := range report.ComplianceIssues {
+		if store.Has(issue.Path, tag) {
+			filtered.ComplianceIssues = append(filtered.ComplianceIssues, issue)
+		}
+	}
+	for _, loose := range report.LooseFiles {
+		if store.Has(loose.Path, tag) {
+			filtered.LooseFiles = append(filtered.LooseFiles, loose)
+		}
+	}
+	for _, orphan := range report.OrphanFolders {
+		if store.Has(orphan.Path, tag) {
+			filtered.OrphanFolders = append(filtered.OrphanFolders, orphan)
+		}
+	}
+	for _, artifact := range report.Artifacts {
+		if store.Has(artifact.Path, tag) {
+			filtered.Artifacts = append(filtered.Artifacts, artifact)
+		}
+	}
+
+	filtered.RecountTotals()
+	return filtered
+}
@@ -0,0 +1,43 @@
+package reporter
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/tags"
+)
+
+func TestApplyKeepTagsAndFilterByTag(t *testing.T) {
+	store, _ := tags.Load(filepath.Join(t.TempDir(), "tags.json"))
+	store.Add("/movies/heat.1995.720p.mkv", "keep")
+	store.Add("/tv/Firefly", "to-replace")
+
+	report := planTestReport()
+	ApplyKeepTags(&report, &tags.Rules{Store: store, Keep: []string{"keep"}})
+
+	files := report.MovieDuplicates[0].Files
+	if files[0].Path != "/movies/heat.1995.720p.mkv" {
+		t.Fatalf("Expected the keep-tagged file first, got %s", files[0].Path)
+	}
+	// 2160p (4000) and 1080p (2000) are now the deletes, plus the TV copy (300)
+	if report.TotalFilesToDelete != 3 || report.SpaceToFree != 6300 {
+		t.Errorf("Totals not recounted: %d files, %d bytes", report.TotalFilesToDelete, report.SpaceToFree)
+	}
+
+	filtered := FilterByTag(report, store, "to-replace")
+	if len(filtered.MovieDuplicates) != 0 || len(filtered.TVDuplicates) != 1 {
+		t.Errorf("Expected only the Firefly group, got %d movie and %d TV groups",
+			len(filtered.MovieDuplicates), len(filtered.TVDuplicates))
+	}
+	if len(filtered.ComplianceIssues) != 1 || filtered.ComplianceIssues[0].Path != "/tv/Firefly/Season 1" {
+		t.Errorf("Expected only the Firefly season issue, got %+v", filtered.ComplianceIssues)
+	}
+	if filtered.TotalDuplicates != 1 || filtered.TotalFilesToDelete != 1 || filtered.SpaceToFree != 300 {
+		t.Errorf("Filtered totals wrong: %d groups, %d files, %d bytes",
+			filtered.TotalDuplicates, filtered.TotalFilesToDelete, filtered.SpaceToFree)
+	}
+
+	if got := FilterByTag(report, nil, "to-replace"); len(got.ComplianceIssues) != 0 {
+		t.Error("Expected no findings without a tag store")
+	}
+}
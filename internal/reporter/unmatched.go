@@ -0,0 +1,71 @@
+package reporter
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// UnmatchedFix is a file Jellyfin failed to match together with the
+// compliance issues whose renames should let Jellyfin pick it up
+type UnmatchedFix struct {
+	Path   string
+	Issues []scanner.ComplianceIssue // issues on the file or one of its folders, most severe first
+}
+
+// Severity returns the most severe issue's severity, or "" without suggestions
+func (f UnmatchedFix) Severity() string {
+	if len(f.Issues) == 0 {
+		return ""
+	}
+	return f.Issues[0].EffectiveSeverity()
+}
+
+// UnmatchedFixes cross-references files Jellyfin did not match with the
+// report's compliance issues. Files with suggested fixes come first, ordered
+// by severity and then by how many fixes apply; files without suggestions
+// follow, as they need a manual look
+func UnmatchedFixes(report Report) []UnmatchedFix {
+	if report.Jellyfin == nil || len(report.Jellyfin.MissingFromServer) == 0 {
+		return nil
+	}
+
+	// Issues are reported on files or folders; index them by path so each
+	// file only looks up itself and its ancestors
+	byPath := make(map[string][]scanner.ComplianceIssue, len(report.ComplianceIssues))
+	for _, issue := range report.ComplianceIssues {
+		p := filepath.Clean(issue.Path)
+		byPath[p] = append(byPath[p], issue)
+	}
+
+	fixes := make([]UnmatchedFix, 0, len(report.Jellyfin.MissingFromServer))
+	for _, path := range report.Jellyfin.MissingFromServer {
+		fix := UnmatchedFix{Path: path}
+		for p := filepath.Clean(path); ; p = filepath.Dir(p) {
+			fix.Issues = append(fix.Issues, byPath[p]...)
+			if p == filepath.Dir(p) {
+				break
+			}
+		}
+		sort.SliceStable(fix.Issues, func(i, j int) bool {
+			return scanner.IssueSeverityRank(fix.Issues[i].EffectiveSeverity()) > scanner.IssueSeverityRank(fix.Issues[j].EffectiveSeverity())
+		})
+		fixes = append(fixes, fix)
+	}
+
+	sort.SliceStable(fixes, func(i, j int) bool {
+		a, b := fixes[i], fixes[j]
+		if (len(a.Issues) > 0) != (len(b.Issues) > 0) {
+			return len(a.Issues) > 0
+		}
+		if ra, rb := scanner.IssueSeverityRank(a.Severity()), scanner.IssueSeverityRank(b.Severity()); ra != rb {
+			return ra > rb
+		}
+		if len(a.Issues) != len(b.Issues) {
+			return len(a.Issues) > len(b.Issues)
+		}
+		return a.Path < b.Path
+	})
+	return fixes
+}
@@ -0,0 +1,52 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/jellyfin"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestUnmatchedFixes(t *testing.T) {
+	if UnmatchedFixes(Report{}) != nil {
+		t.Error("Expected no fixes without a Jellyfin comparison")
+	}
+
+	report := Report{
+		ComplianceIssues: []scanner.ComplianceIssue{
+			{Path: "/tv/firefly/Season 1", Problem: "Season folder not padded", Severity: scanner.IssueSeverityInfo},
+			{Path: "/tv/firefly/Season 1/firefly.s01e01.mkv", Problem: "Episode filename", Severity: scanner.IssueSeverityWarn},
+			{Path: "/movies/heat.1995.mkv", Problem: "Loose movie", Severity: scanner.IssueSeverityError},
+			{Path: "/movies/matched.mkv", Problem: "Matched anyway", Severity: scanner.IssueSeverityError},
+		},
+		Jellyfin: &jellyfin.Comparison{
+			MissingFromServer: []string{
+				"/tv/firefly/Season 1/firefly.s01e01.mkv",
+				"/movies/Unknown (2020)/Unknown (2020).mkv",
+				"/movies/heat.1995.mkv",
+				"/tv/firefly/Season 1/firefly.s01e02.mkv",
+			},
+		},
+	}
+
+	fixes := UnmatchedFixes(report)
+	want := []struct {
+		path     string
+		issues   int
+		severity string
+	}{
+		{"/movies/heat.1995.mkv", 1, scanner.IssueSeverityError},
+		{"/tv/firefly/Season 1/firefly.s01e01.mkv", 2, scanner.IssueSeverityWarn},
+		{"/tv/firefly/Season 1/firefly.s01e02.mkv", 1, scanner.IssueSeverityInfo},
+		{"/movies/Unknown (2020)/Unknown (2020).mkv", 0, ""},
+	}
+	if len(fixes) != len(want) {
+		t.Fatalf("Expected %d fixes, got %d", len(want), len(fixes))
+	}
+	for i, w := range want {
+		if fixes[i].Path != w.path || len(fixes[i].Issues) != w.issues || fixes[i].Severity() != w.severity {
+			t.Errorf("Fix %d = %s (%d issues, %q), want %s (%d issues, %q)",
+				i, fixes[i].Path, len(fixes[i].Issues), fixes[i].Severity(), w.path, w.issues, w.severity)
+		}
+	}
+}
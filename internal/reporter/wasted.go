@@ -0,0 +1,191 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WasteNode is a directory in the wasted-space tree
+// Bytes and Files cover every delete candidate at or below the directory
+type WasteNode struct {
+	Path     string       `json:"path"`
+	Name     string       `json:"name"`
+	Bytes    int64        `json:"bytes"`
+	Files    int          `json:"files"`
+	Children []*WasteNode `json:"children,omitempty"`
+
+	index map[string]*WasteNode
+}
+
+// WasteRow is a flattened tree node with its depth, for rendering and export
+type WasteRow struct {
+	Depth int
+	Node  *WasteNode
+}
+
+// BuildWasteTree aggregates reclaimable space from duplicate groups by directory
+// Every file except the keeper (index 0) is counted against each folder from
+// its library root down to its parent directory, at the space deleting it
+// frees: hardlinks of files that stay count as nothing
+func BuildWasteTree(report Report) *WasteNode {
+	root := newWasteNode("", "All libraries")
+
+	add := func(path string, size int64) {
+		libRoot := wasteLibraryRoot(path, report.LibraryPaths)
+		root.Bytes += size
+		root.Files++
+
+		node := root.child(libRoot, libRoot)
+		node.Bytes += size
+		node.Files++
+
+		rel, err := filepath.Rel(libRoot, filepath.Dir(path))
+		if err != nil || rel == "." {
+			return
+		}
+		current := libRoot
+		for _, part := range strings.Split(rel, string(filepath.Separator)) {
+			current = filepath.Join(current, part)
+			node = node.child(current, part)
+			node.Bytes += size
+			node.Files++
+		}
+	}
+
+	for _, dup := range report.MovieDuplicates {
+		sizes := dup.ReclaimableSizes()
+		for i := 1; i < len(dup.Files); i++ {
+			add(dup.Files[i].Path, sizes[i])
+		}
+	}
+	for _, dup := range report.TVDuplicates {
+		sizes := dup.ReclaimableSizes()
+		for i := 1; i < len(dup.Files); i++ {
+			add(dup.Files[i].Path, sizes[i])
+		}
+	}
+
+	root.sort()
+	return root
+}
+
+// wasteLibraryRoot returns the configured library containing path
+// Files outside every library are grouped under their top-level directory
+func wasteLibraryRoot(path string, libraryPaths []string) string {
+	best := ""
+	for _, lib := range libraryPaths {
+		lib = filepath.Clean(lib)
+		if (path == lib || strings.HasPrefix(path, lib+string(filepath.Separator))) && len(lib) > len(best) {
+			best = lib
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(filepath.Dir(path), string(filepath.Separator)), string(filepath.Separator), 2)
+	return string(filepath.Separator) + parts[0]
+}
+
+func newWasteNode(path, name string) *WasteNode {
+	return &WasteNode{Path: path, Name: name, index: make(map[string]*WasteNode)}
+}
+
+// child returns the child node for path, creating it if needed
+func (n *WasteNode) child(path, name string) *WasteNode {
+	if c, ok := n.index[path]; ok {
+		return c
+	}
+	c := newWasteNode(path, name)
+	n.index[path] = c
+	n.Children = append(n.Children, c)
+	return c
+}
+
+// sort orders children by reclaimable space, largest first, recursively
+func (n *WasteNode) sort() {
+	sort.Slice(n.Children, func(i, j int) bool {
+		if n.Children[i].Bytes != n.Children[j].Bytes {
+			return n.Children[i].Bytes > n.Children[j].Bytes
+		}
+		return n.Children[i].Path < n.Children[j].Path
+	})
+	for _, c := range n.Children {
+		c.sort()
+	}
+}
+
+// Flatten walks the tree depth-first down to maxDepth (0 = unlimited)
+// The root itself is not included
+func (n *WasteNode) Flatten(maxDepth int) []WasteRow {
+	var rows []WasteRow
+	var walk func(node *WasteNode, depth int)
+	walk = func(node *WasteNode, depth int) {
+		for _, c := range node.Children {
+			rows = append(rows, WasteRow{Depth: depth, Node: c})
+			if maxDepth == 0 || depth+1 < maxDepth {
+				walk(c, depth+1)
+			}
+		}
+	}
+	walk(n, 0)
+	return rows
+}
+
+// WriteWasteText writes the tree as an indented listing with share of the total
+func WriteWasteText(w io.Writer, tree *WasteNode, maxDepth int) error {
+	if tree.Files == 0 || tree.Bytes == 0 {
+		_, err := fmt.Fprintln(w, "No reclaimable space found.")
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "Reclaimable space: %s in %d files\n\n", formatBytes(tree.Bytes), tree.Files); err != nil {
+		return err
+	}
+	for _, row := range tree.Flatten(maxDepth) {
+		name := row.Node.Name
+		if row.Depth == 0 {
+			name = row.Node.Path
+		}
+		share := float64(row.Node.Bytes) / float64(tree.Bytes) * 100
+		if _, err := fmt.Fprintf(w, "%10s %5.1f%% %4d  %s%s\n",
+			formatBytes(row.Node.Bytes), share, row.Node.Files, strings.Repeat("  ", row.Depth), name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteWasteCSV writes one row per directory: path, depth, bytes, files
+func WriteWasteCSV(w io.Writer, tree *WasteNode, maxDepth int) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"path", "depth", "bytes", "files"}); err != nil {
+		return err
+	}
+	for _, row := range tree.Flatten(maxDepth) {
+		record := []string{
+			row.Node.Path,
+			strconv.Itoa(row.Depth),
+			strconv.FormatInt(row.Node.Bytes, 10),
+			strconv.Itoa(row.Node.Files),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteWasteJSON writes the full tree as indented JSON
+func WriteWasteJSON(w io.Writer, tree *WasteNode) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(tree)
+}
@@ -0,0 +1,131 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func wasteTestReport() Report {
+	return Report{
+		LibraryPaths: []string{"/media/movies", "/media/tv"},
+		MovieDuplicates: []scanner.MovieDuplicate{
+			{
+				NormalizedName: "alien",
+				Files: []scanner.MovieFile{
+					{Path: "/media/movies/Alien (1979)/Alien.2160p.mkv", Size: 5000},
+					{Path: "/media/movies/Alien (1979)/Alien.720p.mkv", Size: 1000},
+				},
+			},
+		},
+		TVDuplicates: []scanner.TVDuplicate{
+			{
+				ShowName: "lost", Season: 1, Episode: 1,
+				Files: []scanner.TVFile{
+					{Path: "/media/tv/Lost/Season 01/Lost S01E01 1080p.mkv", Size: 900},
+					{Path: "/media/tv/Lost/Season 01/Lost S01E01 720p.mkv", Size: 400},
+					{Path: "/media/tv/Lost/Season 01/Lost S01E01 480p.mkv", Size: 200},
+				},
+			},
+			{
+				ShowName: "lost", Season: 2, Episode: 1,
+				Files: []scanner.TVFile{
+					{Path: "/media/tv/Lost/Season 02/Lost S02E01 1080p.mkv", Size: 900},
+					{Path: "/media/tv/Lost/Season 02/Lost S02E01 720p.mkv", Size: 2000},
+				},
+			},
+		},
+	}
+}
+
+func TestBuildWasteTree(t *testing.T) {
+	tree := BuildWasteTree(wasteTestReport())
+
+	// Keepers are excluded: 1000 + 400 + 200 + 2000
+	if tree.Bytes != 3600 || tree.Files != 4 {
+		t.Fatalf("Expected 3600 bytes in 4 files, got %d in %d", tree.Bytes, tree.Files)
+	}
+
+	if len(tree.Children) != 2 {
+		t.Fatalf("Expected 2 library roots, got %d", len(tree.Children))
+	}
+	tv := tree.Children[0]
+	if tv.Path != "/media/tv" || tv.Bytes != 2600 {
+		t.Errorf("Expected TV library first with 2600 bytes, got %s with %d", tv.Path, tv.Bytes)
+	}
+
+	lost := tv.Children[0]
+	if lost.Name != "Lost" || lost.Files != 3 {
+		t.Errorf("Expected Lost folder with 3 files, got %s with %d", lost.Name, lost.Files)
+	}
+	if len(lost.Children) != 2 || lost.Children[0].Name != "Season 02" || lost.Children[0].Bytes != 2000 {
+		t.Errorf("Expected Season 02 to be the hottest season, got %+v", lost.Children)
+	}
+
+	rows := tree.Flatten(2)
+	for _, row := range rows {
+		if row.Depth >= 2 {
+			t.Errorf("Flatten(2) returned row at depth %d: %s", row.Depth, row.Node.Path)
+		}
+	}
+	if len(tree.Flatten(0)) != 6 {
+		t.Errorf("Expected 6 folders in the full tree, got %d", len(tree.Flatten(0)))
+	}
+}
+
+func TestBuildWasteTreeOutsideLibraries(t *testing.T) {
+	report := Report{
+		MovieDuplicates: []scanner.MovieDuplicate{
+			{Files: []scanner.MovieFile{
+				{Path: "/mnt/a/Film/keep.mkv", Size: 10},
+				{Path: "/mnt/a/Film/drop.mkv", Size: 5},
+			}},
+		},
+	}
+
+	tree := BuildWasteTree(report)
+	if len(tree.Children) != 1 || tree.Children[0].Path != "/mnt" {
+		t.Fatalf("Expected files outside libraries grouped under /mnt, got %+v", tree.Children)
+	}
+}
+
+func TestWriteWasteExports(t *testing.T) {
+	tree := BuildWasteTree(wasteTestReport())
+
+	var text bytes.Buffer
+	if err := WriteWasteText(&text, tree, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "/media/tv") || !strings.Contains(text.String(), "    Season 02") {
+		t.Errorf("Unexpected text export:\n%s", text.String())
+	}
+
+	var csvOut bytes.Buffer
+	if err := WriteWasteCSV(&csvOut, tree, 1); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	if len(lines) != 3 || lines[0] != "path,depth,bytes,files" || lines[1] != "/media/tv,0,2600,3" {
+		t.Errorf("Unexpected CSV export:\n%s", csvOut.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := WriteWasteJSON(&jsonOut, tree); err != nil {
+		t.Fatal(err)
+	}
+	var decoded WasteNode
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil {
+		t.Fatalf("JSON export did not round-trip: %v", err)
+	}
+	if decoded.Bytes != 3600 || len(decoded.Children) != 2 {
+		t.Errorf("Unexpected decoded tree: %+v", decoded)
+	}
+
+	var empty bytes.Buffer
+	if err := WriteWasteText(&empty, BuildWasteTree(Report{}), 0); err != nil || !strings.Contains(empty.String(), "No reclaimable") {
+		t.Errorf("Expected empty-tree message, got %q (%v)", empty.String(), err)
+	}
+}
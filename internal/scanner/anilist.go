@@ -0,0 +1,200 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// AniListClient looks up anime series on AniList, which needs no API key
+type AniListClient struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// AniListMedia is an anime series from an AniList search
+type AniListMedia struct {
+	ID    int `json:"id"`
+	Title struct {
+		Romaji        string `json:"romaji"`
+		English       string `json:"english"`
+		Native        string `json:"native"`
+		UserPreferred string `json:"userPreferred"`
+	} `json:"title"`
+	Synonyms  []string `json:"synonyms"`
+	StartDate struct {
+		Year int `json:"year"`
+	} `json:"startDate"`
+}
+
+// DisplayTitle returns the title AniList prefers, usually the romaji one
+func (m AniListMedia) DisplayTitle() string {
+	if m.Title.UserPreferred != "" {
+		return m.Title.UserPreferred
+	}
+	return m.Title.Romaji
+}
+
+// Year returns the year the series started, or "" when unknown
+func (m AniListMedia) Year() string {
+	if m.StartDate.Year == 0 {
+		return ""
+	}
+	return strconv.Itoa(m.StartDate.Year)
+}
+
+// Matches reports whether name is one of the series' titles or synonyms
+func (m AniListMedia) Matches(name string) bool {
+	want := NormalizeName(name)
+	titles := append([]string{m.Title.Romaji, m.Title.English, m.Title.Native, m.Title.UserPreferred}, m.Synonyms...)
+	for _, title := range titles {
+		if title != "" && NormalizeName(title) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// aniListSearchQuery finds the best anime match for $search
+const aniListSearchQuery = `query ($search: String) {
+  Media(search: $search, type: ANIME) {
+    id
+    title { romaji english native userPreferred }
+    synonyms
+    startDate { year }
+  }
+}`
+
+// NewAniListClient creates a new AniList API client
+func NewAniListClient() *AniListClient {
+	return &AniListClient{
+		BaseURL:    AniListBaseURL,
+		HTTPClient: newAPIHTTPClient(),
+	}
+}
+
+// LookupTitle returns AniList's title and start year for the series called
+// name. Only a series that lists name among its titles or synonyms counts,
+// so a loose search hit never renames a show. Outcomes are cached
+func (c *AniListClient) LookupTitle(name string) (title, year string, err error) {
+	cacheKey := "anilist:" + name
+	if cached, ok := globalAPICache.Get(cacheKey); ok {
+		if cached.Verified {
+			return cached.Title, cached.Year, nil
+		}
+		return "", "", fmt.Errorf("cached: %s", cached.Reason)
+	}
+
+	media, err := c.SearchWithRetry(name, 3)
+	if err != nil {
+		return "", "", err
+	}
+	if media == nil || !media.Matches(name) {
+		reason := fmt.Sprintf("no AniList series titled %q", name)
+		globalAPICache.Set(cacheKey, &APICacheEntry{Verified: false, Reason: reason, Timestamp: time.Now()})
+		return "", "", fmt.Errorf("%s", reason)
+	}
+
+	title = sanitizeEpisodeTitle(media.DisplayTitle())
+	globalAPICache.Set(cacheKey, &APICacheEntry{
+		Title:      title,
+		Year:       media.Year(),
+		ID:         strconv.Itoa(media.ID),
+		Verified:   true,
+		Confidence: 0.95,
+		Timestamp:  time.Now(),
+	})
+	return title, media.Year(), nil
+}
+
+// SearchWithRetry returns AniList's best anime match for name, or nil when
+// it has none
+func (c *AniListClient) SearchWithRetry(name string, maxRetries int) (*AniListMedia, error) {
+	body, err := json.Marshal(map[string]any{
+		"query":     aniListSearchQuery,
+		"variables": map[string]string{"search": name},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode query: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		// Offline: give up without caching so the next scan tries again
+		if err := aniListBreaker.Allow(); err != nil {
+			if lastErr == nil {
+				apiStats.skip(aniListBreaker.Name)
+			} else {
+				apiStats.record(aniListBreaker.Name, lastErr)
+			}
+			return nil, err
+		}
+		if attempt > 0 {
+			backoff := time.Duration(math.Pow(2, float64(attempt-1))) * time.Second
+			time.Sleep(backoff)
+		}
+
+		req, err := http.NewRequest("POST", c.BaseURL, bytes.NewReader(body))
+		if err != nil {
+			lastErr = fmt.Errorf("failed to create request: %w", err)
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			aniListBreaker.Failure()
+			lastErr = fmt.Errorf("API request failed: %w", describeRequestError(err))
+			continue
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			aniListBreaker.Failure()
+		} else {
+			aniListBreaker.Success()
+		}
+
+		// AniList answers a search without a match with 404
+		if resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			apiStats.record(aniListBreaker.Name, nil)
+			return nil, nil
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("rate limited")
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			respBody, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			lastErr = fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
+			continue
+		}
+
+		var result struct {
+			Data struct {
+				Media *AniListMedia `json:"Media"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("failed to parse response: %w", err)
+			continue
+		}
+		resp.Body.Close()
+
+		apiStats.record(aniListBreaker.Name, nil)
+		return result.Data.Media, nil
+	}
+
+	apiStats.record(aniListBreaker.Name, lastErr)
+	return nil, fmt.Errorf("failed after %d retries: %w", maxRetries, lastErr)
+}
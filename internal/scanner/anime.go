@@ -0,0 +1,263 @@
+package scanner
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// AnimeEpisode is an episode parsed from an anime release filename
+type AnimeEpisode struct {
+	Show     string // series title with the release tags removed
+	Year     string // year from a "(YYYY)" tag, if any
+	Group    string // fansub or release group from a leading [Group] tag
+	CRC      string // CRC32 tag, e.g. "ABCD1234"
+	Season   int    // season of an S##E## episode; 0 when Absolute
+	Episode  int    // episode number, counted across the series when Absolute
+	Absolute bool   // numbered across the series rather than per season
+}
+
+var (
+	animeGroupRegex = regexp.MustCompile(`^\s*\[([^\]]+)\]\s*`)
+	animeCRCRegex   = regexp.MustCompile(`[\[(]([0-9A-Fa-f]{8})[\])]`)
+	animeTagRegex   = regexp.MustCompile(`\[[^\]]*\]|\([^)]*\)`)
+
+	// "Show - 12", "Show - 12v2", "Show - 12 - Episode Title"
+	animeDashEpisodeRegex = regexp.MustCompile(`(?i)^(.+)\s+-\s+(?:ep?\.?\s*|episode\s+|#)?(\d{1,4})(?:v\d{1,2})?(?:\s+end)?(?:\s+-\s+.*)?$`)
+	// "Show E12", "Show Ep 12", "Show Episode 12"
+	animePrefixEpisodeRegex = regexp.MustCompile(`(?i)^(.+?)\s+(?:ep?\.?\s*|episode\s+)(\d{1,4})(?:v\d{1,2})?(?:\s+.*)?$`)
+	// "Show 12"
+	animeBareEpisodeRegex = regexp.MustCompile(`(?i)^(.+)\s+(\d{1,4})(?:v\d{1,2})?$`)
+	// "Show S2" or "Show Season 2" ahead of an episode number
+	animeSeasonRegex = regexp.MustCompile(`(?i)\s+(?:s|season\s+)(\d{1,2})$`)
+)
+
+// ParseAnimeFilename parses an anime release filename such as
+// "[SubsPlease] Frieren - 12 (1080p) [ABCD1234].mkv". Episodes tagged
+// S##E## keep their season; others are numbered across the series
+func ParseAnimeFilename(filename string) (AnimeEpisode, bool) {
+	var ep AnimeEpisode
+	name := strings.TrimSuffix(filename, filepath.Ext(filename))
+
+	if m := animeGroupRegex.FindStringSubmatch(name); m != nil {
+		ep.Group = strings.TrimSpace(m[1])
+		name = name[len(m[0]):]
+	}
+	if m := animeCRCRegex.FindAllStringSubmatch(name, -1); len(m) > 0 {
+		ep.CRC = strings.ToUpper(m[len(m)-1][1])
+	}
+	ep.Year = extractYearFromTitle(name)
+
+	name = strings.ReplaceAll(name, "_", " ")
+	if !strings.Contains(name, " ") {
+		name = strings.ReplaceAll(name, ".", " ")
+	}
+	// Tags go after the separators are settled, since they leave spaces
+	name = animeTagRegex.ReplaceAllString(name, " ")
+	name = strings.TrimSpace(collapseSpacesRegex.ReplaceAllString(name, " "))
+
+	var show string
+	if season, episode, found := ExtractEpisodeInfo(name); found {
+		loc := episodeSERegex.FindStringIndex(name)
+		if loc == nil {
+			loc = episodeXRegex.FindStringIndex(name)
+		}
+		show = name[:loc[0]]
+		ep.Season, ep.Episode = season, episode
+	} else {
+		m := animeDashEpisodeRegex.FindStringSubmatch(name)
+		if m == nil {
+			m = animePrefixEpisodeRegex.FindStringSubmatch(name)
+		}
+		if m == nil {
+			// A bare trailing number is only an episode when it can't be a year
+			if bare := animeBareEpisodeRegex.FindStringSubmatch(name); bare != nil && !isYearNumber(bare[2]) {
+				m = bare
+			}
+		}
+		if m == nil {
+			return ep, false
+		}
+		show = m[1]
+		ep.Episode, _ = strconv.Atoi(m[2])
+		ep.Absolute = true
+
+		// "Show S2 - 05" is the fifth episode of season 2
+		if sm := animeSeasonRegex.FindStringSubmatchIndex(show); sm != nil {
+			ep.Season, _ = strconv.Atoi(show[sm[2]:sm[3]])
+			ep.Absolute = false
+			show = show[:sm[0]]
+		}
+	}
+
+	ep.Show = strings.TrimSpace(strings.TrimRight(show, " -."))
+	return ep, ep.Show != ""
+}
+
+// isYearNumber reports whether a bare number reads as a year (1900-2099)
+func isYearNumber(s string) bool {
+	return len(s) == 4 && s >= "1900" && s <= "2099"
+}
+
+// Libraries holding anime (SetAnimeLibraries) and whether their series
+// titles are looked up on AniList
+var (
+	animeLibraries map[string]bool
+	aniListLookup  bool
+	animeMu        sync.RWMutex
+)
+
+// SetAnimeLibraries marks the TV library paths that hold anime; their
+// episodes are parsed and named with anime conventions
+func SetAnimeLibraries(paths []string) {
+	animeMu.Lock()
+	defer animeMu.Unlock()
+	animeLibraries = make(map[string]bool, len(paths))
+	for _, path := range paths {
+		animeLibraries[filepath.Clean(path)] = true
+	}
+}
+
+// SetAniListLookup sets whether anime series titles are checked against AniList
+func SetAniListLookup(enabled bool) {
+	animeMu.Lock()
+	defer animeMu.Unlock()
+	aniListLookup = enabled
+}
+
+// isAnimeLibrary reports whether libPath was set as an anime library
+func isAnimeLibrary(libPath string) bool {
+	animeMu.RLock()
+	defer animeMu.RUnlock()
+	return animeLibraries[filepath.Clean(libPath)]
+}
+
+// aniListEnabled reports whether AniList lookups are on
+func aniListEnabled() bool {
+	animeMu.RLock()
+	defer animeMu.RUnlock()
+	return aniListLookup
+}
+
+// AbsoluteEpisodeFilename returns the filename of an episode numbered
+// across the series, e.g. "Frieren (2023) - 12.mkv"; Jellyfin and Emby
+// both read it as an absolute episode number
+func (p NamingProfile) AbsoluteEpisodeFilename(show string, episode int, ext string) string {
+	return fmt.Sprintf("%s - %02d%s", show, episode, ext)
+}
+
+// animeLocalTitle returns the series title and year of the anime episode
+// at path without asking AniList. A show folder that isn't a release name
+// wins over the title in the filename
+func animeLocalTitle(libRoot, path string, ep AnimeEpisode) (title, year string) {
+	title, year = ep.Show, ep.Year
+	rel, err := filepath.Rel(libRoot, path)
+	if err != nil {
+		return title, year
+	}
+	parts := strings.Split(rel, string(filepath.Separator))
+	if len(parts) < 2 {
+		return title, year
+	}
+	folder := parts[0]
+	if strings.ContainsAny(folder, "[]") || isReleaseGroupFolder(folder) {
+		return title, year
+	}
+	folderYear := extractYearFromTitle(folder)
+	folderTitle := strings.TrimSpace(collapseSpacesRegex.ReplaceAllString(animeTagRegex.ReplaceAllString(folder, " "), " "))
+	if folderTitle == "" {
+		return title, year
+	}
+	if folderYear == "" {
+		folderYear = year
+	}
+	return folderTitle, folderYear
+}
+
+// animeShowTitle is animeLocalTitle, with AniList's spelling and year when
+// lookups are on and AniList knows the title
+func animeShowTitle(libRoot, path string, ep AnimeEpisode) (title, year string) {
+	title, year = animeLocalTitle(libRoot, path, ep)
+	if !aniListEnabled() {
+		return title, year
+	}
+	if canonical, aniYear, err := NewAniListClient().LookupTitle(title); err == nil {
+		title = canonical
+		if year == "" {
+			year = aniYear
+		}
+	}
+	return title, year
+}
+
+// checkAnimeCompliance checks an episode in an anime library. Episodes
+// numbered across the series belong directly in "Show (Year)" as
+// "Show (Year) - 12"; S##E## episodes follow the TV season layout
+func checkAnimeCompliance(filePath, libRoot string) *ComplianceIssue {
+	filename := filepath.Base(filePath)
+	ep, ok := ParseAnimeFilename(filename)
+	if !ok {
+		return nil
+	}
+	show, year := animeShowTitle(libRoot, filePath, ep)
+	showFolder := sanitizeEpisodeTitle(show)
+	if year != "" {
+		showFolder = fmt.Sprintf("%s (%s)", showFolder, year)
+	}
+
+	profile := GetNamingProfile()
+	ext := filepath.Ext(filePath)
+	dir := filepath.Dir(filePath)
+	expectedDir := filepath.Join(libRoot, showFolder)
+	expectedName := profile.AbsoluteEpisodeFilename(showFolder, ep.Episode, ext)
+	inPlace := dir == expectedDir
+	if !ep.Absolute {
+		expectedDir = filepath.Join(expectedDir, profile.SeasonFolder(ep.Season))
+		expectedName = suggestedEpisodeFilename(profile, showFolder, ep.Season, ep.Episode, ext)
+		inPlace = filepath.Dir(dir) == filepath.Join(libRoot, showFolder) && profile.IsSeasonFolder(filepath.Base(dir), ep.Season)
+	}
+
+	if !inPlace {
+		problem := fmt.Sprintf("Not in its '%s' show folder (found: %s)", showFolder, filepath.Base(dir))
+		if !ep.Absolute {
+			problem = fmt.Sprintf("Not in proper '%s' folder (found: %s)", filepath.Join(showFolder, profile.SeasonFolder(ep.Season)), filepath.Base(dir))
+		}
+		return &ComplianceIssue{
+			Path:            filePath,
+			Type:            "tv",
+			Problem:         problem,
+			Severity:        IssueSeverityError,
+			Rule:            RuleAnimeEpisodeFolder,
+			SuggestedPath:   filepath.Join(expectedDir, expectedName),
+			SuggestedAction: "reorganize",
+		}
+	}
+
+	if ep.Group != "" || ep.CRC != "" || strings.ContainsAny(filename, "[]") {
+		var tags []string
+		if ep.Group != "" {
+			tags = append(tags, "["+ep.Group+"]")
+		}
+		if ep.CRC != "" {
+			tags = append(tags, "CRC "+ep.CRC)
+		}
+		problem := "Release tags in anime filename"
+		if len(tags) > 0 {
+			problem += " (" + strings.Join(tags, ", ") + ")"
+		}
+		return &ComplianceIssue{
+			Path:            filePath,
+			Type:            "tv",
+			Problem:         problem,
+			Severity:        IssueSeverityWarn,
+			Rule:            RuleAnimeReleaseTags,
+			SuggestedPath:   filepath.Join(dir, expectedName),
+			SuggestedAction: "rename",
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package scanner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseAnimeFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     AnimeEpisode
+	}{
+		{"[SubsPlease] Sousou no Frieren - 12 (1080p) [ABCD1234].mkv",
+			AnimeEpisode{Show: "Sousou no Frieren", Group: "SubsPlease", CRC: "ABCD1234", Episode: 12, Absolute: true}},
+		{"[Erai-raws] One Piece - 1085v2 [1080p][Multiple Subtitle][DEADBEEF].mkv",
+			AnimeEpisode{Show: "One Piece", Group: "Erai-raws", CRC: "DEADBEEF", Episode: 1085, Absolute: true}},
+		{"[Group].Cowboy.Bebop.-.05.[BD.1920x1080].mkv",
+			AnimeEpisode{Show: "Cowboy Bebop", Group: "Group", Episode: 5, Absolute: true}},
+		{"[SubsPlease] Spy x Family S2 - 05 (720p).mkv",
+			AnimeEpisode{Show: "Spy x Family", Group: "SubsPlease", Season: 2, Episode: 5}},
+		{"Frieren (2023) - 03 - The Land Where the Dead Sleep.mkv",
+			AnimeEpisode{Show: "Frieren", Year: "2023", Episode: 3, Absolute: true}},
+		{"Mob Psycho 100 S01E04.mkv",
+			AnimeEpisode{Show: "Mob Psycho 100", Season: 1, Episode: 4}},
+		{"Naruto Episode 220.mkv",
+			AnimeEpisode{Show: "Naruto", Episode: 220, Absolute: true}},
+	}
+	for _, tt := range tests {
+		got, ok := ParseAnimeFilename(tt.filename)
+		if !ok || got != tt.want {
+			t.Errorf("ParseAnimeFilename(%q) = %+v, %v, want %+v", tt.filename, got, ok, tt.want)
+		}
+	}
+
+	// A trailing year or a bare title is not an episode
+	for _, filename := range []string{"Akira 1988.mkv", "[Group] Akira [1080p].mkv"} {
+		if got, ok := ParseAnimeFilename(filename); ok {
+			t.Errorf("ParseAnimeFilename(%q) = %+v, expected no episode", filename, got)
+		}
+	}
+}
+
+func TestAnimeLibraryScan(t *testing.T) {
+	root := t.TempDir()
+	anime := filepath.Join(root, "anime")
+	write := func(rel string) string {
+		path := filepath.Join(anime, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	compliant := write("Frieren (2023)/Frieren (2023) - 01.mkv")
+	tagged := write("Frieren (2023)/[SubsPlease] Frieren - 02 (1080p) [ABCD1234].mkv")
+	batch := write("[Judas] Frieren [1080p]/[Judas] Frieren - 01 [DEADBEEF].mkv")
+	seasonal := write("[SubsPlease] Spy x Family S2 - 05 (720p).mkv")
+
+	SetAnimeLibraries([]string{anime})
+	t.Cleanup(func() { SetAnimeLibraries(nil) })
+
+	issues, err := ScanTVCompliance([]string{anime})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byPath := make(map[string]ComplianceIssue)
+	for _, issue := range issues {
+		byPath[issue.Path] = issue
+	}
+	if _, ok := byPath[compliant]; ok {
+		t.Errorf("Expected the compliant episode left alone, got %+v", byPath[compliant])
+	}
+	if issue := byPath[tagged]; issue.Rule != RuleAnimeReleaseTags || issue.SuggestedPath != filepath.Join(anime, "Frieren (2023)", "Frieren (2023) - 02.mkv") {
+		t.Errorf("Expected the tagged episode renamed in place, got %+v", issue)
+	}
+	if issue := byPath[batch]; issue.Rule != RuleAnimeEpisodeFolder || issue.SuggestedPath != filepath.Join(anime, "Frieren", "Frieren - 01.mkv") {
+		t.Errorf("Expected the batch release moved into a show folder, got %+v", issue)
+	}
+	if issue := byPath[seasonal]; issue.Rule != RuleAnimeEpisodeFolder || issue.SuggestedPath != filepath.Join(anime, "Spy x Family", "Season 02", "Spy x Family S02E05.mkv") {
+		t.Errorf("Expected the S2 episode moved into its season folder, got %+v", issue)
+	}
+
+	// The batch release and the clean copy of episode 1 are one group,
+	// numbered across the series
+	dups, err := ScanTVShows([]string{anime})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dups) != 1 || !dups[0].Absolute || dups[0].Episode != 1 || len(dups[0].Files) != 2 {
+		t.Fatalf("Expected one absolute duplicate group for episode 1, got %+v", dups)
+	}
+	if label := dups[0].EpisodeLabel(); label != "E01" {
+		t.Errorf("EpisodeLabel() = %q, want E01", label)
+	}
+	if TVDuplicateID(dups[0]) == TVDuplicateID(TVDuplicate{ShowName: dups[0].ShowName, Episode: 1}) {
+		t.Error("Expected absolute episodes to get IDs apart from season 0")
+	}
+}
+
+func TestAniListLookupTitle(t *testing.T) {
+	var searches []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]string `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		search := req.Variables["search"]
+		searches = append(searches, search)
+		if search == "Unknown Show" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"data":{"Media":{"id":154587,
+			"title":{"romaji":"Sousou no Frieren","english":"Frieren: Beyond Journey's End","userPreferred":"Sousou no Frieren"},
+			"synonyms":["Frieren"],"startDate":{"year":2023}}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	origURL := AniListBaseURL
+	AniListBaseURL = server.URL
+	ClearAPICache()
+	ResetAPICircuit()
+	t.Cleanup(func() {
+		AniListBaseURL = origURL
+		ClearAPICache()
+		ResetAPICircuit()
+	})
+
+	client := NewAniListClient()
+	title, year, err := client.LookupTitle("Frieren")
+	if err != nil || title != "Sousou no Frieren" || year != "2023" {
+		t.Errorf("LookupTitle() = %q, %q, %v", title, year, err)
+	}
+	if _, _, err := client.LookupTitle("Frieren"); err != nil || len(searches) != 1 {
+		t.Errorf("Expected the second lookup cached, got %d searches (%v)", len(searches), err)
+	}
+
+	// A search hit that doesn't carry the title is not trusted
+	if _, _, err := client.LookupTitle("Beyond the Boundary"); err == nil {
+		t.Error("Expected a loose match rejected")
+	}
+	if _, _, err := client.LookupTitle("Unknown Show"); err == nil {
+		t.Error("Expected no match for an unknown show")
+	}
+
+	// With lookups on, compliance suggests AniList's title and year
+	anime := t.TempDir()
+	path := filepath.Join(anime, "[SubsPlease] Frieren - 04 (1080p).mkv")
+	if err := os.WriteFile(path, []byte("ep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	SetAniListLookup(true)
+	t.Cleanup(func() { SetAniListLookup(false) })
+	issue := checkAnimeCompliance(path, anime)
+	want := filepath.Join(anime, "Sousou no Frieren (2023)", "Sousou no Frieren (2023) - 04.mkv")
+	if issue == nil || issue.SuggestedPath != want {
+		t.Errorf("Expected %s suggested, got %+v", want, issue)
+	}
+}
@@ -0,0 +1,218 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
+)
+
+// APICacheFile is the persisted API cache in the data directory
+const APICacheFile = "api_cache.json"
+
+// DefaultAPICacheTTL is how long a verified lookup is reused (api.cache_ttl_days)
+const DefaultAPICacheTTL = 30 * 24 * time.Hour
+
+// DefaultAPICachePath returns where verified lookups are kept between runs
+func DefaultAPICachePath() string {
+	return filepath.Join(oplog.DataDir(), APICacheFile)
+}
+
+// File the session cache is loaded from and saved to; empty = session-only
+var (
+	apiCachePath   string
+	apiCachePathMu sync.RWMutex
+)
+
+// SetAPICachePersistence keeps verified lookups in path between runs, each
+// valid for ttl. An empty path or a ttl of 0 keeps the cache session-only
+func SetAPICachePersistence(path string, ttl time.Duration) {
+	if ttl <= 0 {
+		path, ttl = "", 0
+	}
+	apiCachePathMu.Lock()
+	apiCachePath = path
+	apiCachePathMu.Unlock()
+	globalAPICache.setTTL(ttl)
+}
+
+// persistedAPICachePath returns the path set by SetAPICachePersistence
+func persistedAPICachePath() string {
+	apiCachePathMu.RLock()
+	defer apiCachePathMu.RUnlock()
+	return apiCachePath
+}
+
+// LoadAPICache merges the persisted lookups that are still valid into the
+// session cache. A missing file, or no persistence, loads nothing
+func LoadAPICache() error {
+	path := persistedAPICachePath()
+	if path == "" {
+		return nil
+	}
+	entries, err := readAPICacheFile(path)
+	if err != nil {
+		return err
+	}
+
+	c := globalAPICache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range entries {
+		if _, ok := c.cache[key]; ok || !entry.Verified || c.expired(entry) {
+			continue
+		}
+		c.cache[key] = entry
+	}
+	return nil
+}
+
+// SaveAPICache writes the session's verified, unexpired lookups to the
+// persisted cache. Failed lookups are never persisted so the next run
+// retries them
+func SaveAPICache() error {
+	path := persistedAPICachePath()
+	if path == "" {
+		return nil
+	}
+
+	c := globalAPICache
+	c.mu.RLock()
+	entries := make(map[string]*APICacheEntry)
+	for key, entry := range c.cache {
+		if entry.Verified && !c.expired(entry) {
+			entries[key] = entry
+		}
+	}
+	c.mu.RUnlock()
+
+	// Nothing learned and nothing to update: don't create an empty file
+	if len(entries) == 0 {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil
+		}
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal API cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create API cache directory: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write API cache: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace API cache: %w", err)
+	}
+	return nil
+}
+
+// APICacheStats summarizes a persisted API cache file
+type APICacheStats struct {
+	Path      string
+	Size      int64
+	Entries   int
+	Expired   int            // older than the TTL; dropped on the next save
+	Providers map[string]int // entries per provider, e.g. "tvdb"
+	Oldest    time.Time
+	Newest    time.Time
+}
+
+// ReadAPICacheStats reads the persisted cache at path, counting entries
+// older than ttl as expired. A missing file has no entries
+func ReadAPICacheStats(path string, ttl time.Duration) (APICacheStats, error) {
+	stats := APICacheStats{Path: path, Providers: make(map[string]int)}
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return stats, nil
+	}
+	if err != nil {
+		return stats, fmt.Errorf("failed to read API cache: %w", err)
+	}
+	stats.Size = info.Size()
+
+	entries, err := readAPICacheFile(path)
+	if err != nil {
+		return stats, err
+	}
+	c := &APICache{ttl: ttl}
+	for key, entry := range entries {
+		stats.Entries++
+		if c.expired(entry) {
+			stats.Expired++
+		}
+		provider, _, _ := strings.Cut(key, ":")
+		stats.Providers[provider]++
+		if stats.Oldest.IsZero() || entry.Timestamp.Before(stats.Oldest) {
+			stats.Oldest = entry.Timestamp
+		}
+		if entry.Timestamp.After(stats.Newest) {
+			stats.Newest = entry.Timestamp
+		}
+	}
+	return stats, nil
+}
+
+// ProviderNames returns the providers in stats, sorted
+func (s APICacheStats) ProviderNames() []string {
+	names := make([]string, 0, len(s.Providers))
+	for name := range s.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RemoveAPICache deletes the persisted cache at path and clears the session
+// cache, so every title is looked up again. A missing file is not an error
+func RemoveAPICache(path string) error {
+	ClearAPICache()
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove API cache: %w", err)
+	}
+	return nil
+}
+
+// readAPICacheFile decodes the persisted cache; a missing file is empty
+func readAPICacheFile(path string) (map[string]*APICacheEntry, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API cache: %w", err)
+	}
+	var entries map[string]*APICacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse API cache %s: %w", path, err)
+	}
+	for key, entry := range entries {
+		if entry == nil {
+			delete(entries, key)
+		}
+	}
+	return entries, nil
+}
+
+// setTTL sets how long verified entries stay valid
+func (c *APICache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+}
+
+// expired reports whether a verified entry has outlived the TTL; callers
+// hold c.mu. Failed lookups are dropped every scan instead (DropFailures)
+func (c *APICache) expired(entry *APICacheEntry) bool {
+	return entry.Verified && c.ttl > 0 && time.Since(entry.Timestamp) > c.ttl
+}
@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAPICachePersistsAcrossRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), APICacheFile)
+	SetAPICachePersistence(path, 24*time.Hour)
+	ClearAPICache()
+	defer func() {
+		SetAPICachePersistence("", 0)
+		ClearAPICache()
+	}()
+
+	globalAPICache.Set("tvdb:lost", &APICacheEntry{Title: "Lost", ID: "4", Verified: true, Timestamp: time.Now()})
+	globalAPICache.Set("tmdb:tv:lost|", &APICacheEntry{Title: "Lost", ID: "4607", Verified: true, Timestamp: time.Now().Add(-48 * time.Hour)})
+	globalAPICache.Set("omdb:lost", &APICacheEntry{Reason: "rate limited", Timestamp: time.Now()})
+	if err := SaveAPICache(); err != nil {
+		t.Fatalf("SaveAPICache failed: %v", err)
+	}
+
+	// The next run starts with an empty session cache
+	ClearAPICache()
+	if err := LoadAPICache(); err != nil {
+		t.Fatalf("LoadAPICache failed: %v", err)
+	}
+	if entry, ok := globalAPICache.Get("tvdb:lost"); !ok || entry.ID != "4" {
+		t.Errorf("Expected the verified lookup reloaded, got %+v", entry)
+	}
+	if _, ok := globalAPICache.Get("tmdb:tv:lost|"); ok {
+		t.Error("Expected the expired lookup dropped")
+	}
+	if _, ok := globalAPICache.Get("omdb:lost"); ok {
+		t.Error("Expected the failed lookup not persisted")
+	}
+
+	stats, err := ReadAPICacheStats(path, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Entries != 1 || stats.Providers["tvdb"] != 1 || stats.Expired != 0 || stats.Size == 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	if err := RemoveAPICache(path); err != nil {
+		t.Fatalf("RemoveAPICache failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected the cache file removed")
+	}
+	if _, ok := globalAPICache.Get("tvdb:lost"); ok {
+		t.Error("Expected the session cache cleared")
+	}
+	if err := RemoveAPICache(path); err != nil {
+		t.Errorf("Expected removing a missing cache to succeed, got %v", err)
+	}
+}
+
+func TestAPICacheSessionOnly(t *testing.T) {
+	dir := t.TempDir()
+	SetAPICachePersistence(filepath.Join(dir, APICacheFile), 0)
+	ClearAPICache()
+	defer ClearAPICache()
+
+	globalAPICache.Set("tvdb:lost", &APICacheEntry{Title: "Lost", Verified: true, Timestamp: time.Now().Add(-365 * 24 * time.Hour)})
+	if _, ok := globalAPICache.Get("tvdb:lost"); !ok {
+		t.Error("Expected session entries not to expire without a TTL")
+	}
+	if err := SaveAPICache(); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected nothing written with a TTL of 0, got %v", entries)
+	}
+}
@@ -0,0 +1,76 @@
+package scanner
+
+import (
+	"sort"
+	"sync"
+)
+
+// APIProviderStats summarizes one metadata provider's lookups during a scan
+type APIProviderStats struct {
+	Provider  string
+	Successes int    // lookups the API answered (including "not found")
+	Failures  int    // lookups that failed after retries
+	Skipped   int    // lookups not attempted because the provider was offline
+	LastError string `json:",omitempty"`
+}
+
+// Degraded reports whether any lookup failed or was skipped
+func (s APIProviderStats) Degraded() bool {
+	return s.Failures > 0 || s.Skipped > 0
+}
+
+// apiStatsTracker collects per-provider lookup outcomes for the current scan
+type apiStatsTracker struct {
+	mu    sync.Mutex
+	stats map[string]*APIProviderStats
+}
+
+var apiStats = &apiStatsTracker{stats: make(map[string]*APIProviderStats)}
+
+func (t *apiStatsTracker) entry(provider string) *APIProviderStats {
+	s, ok := t.stats[provider]
+	if !ok {
+		s = &APIProviderStats{Provider: provider}
+		t.stats[provider] = s
+	}
+	return s
+}
+
+// record counts a finished lookup; a nil err is a success
+func (t *apiStatsTracker) record(provider string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.entry(provider)
+	if err == nil {
+		s.Successes++
+		return
+	}
+	s.Failures++
+	s.LastError = err.Error()
+}
+
+// skip counts a lookup refused by the provider's circuit breaker
+func (t *apiStatsTracker) skip(provider string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entry(provider).Skipped++
+}
+
+func (t *apiStatsTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stats = make(map[string]*APIProviderStats)
+}
+
+// APIDiagnostics returns per-provider lookup counts since the scan started,
+// sorted by provider; providers that were never queried are omitted
+func APIDiagnostics() []APIProviderStats {
+	apiStats.mu.Lock()
+	defer apiStats.mu.Unlock()
+	diags := make([]APIProviderStats, 0, len(apiStats.stats))
+	for _, s := range apiStats.stats {
+		diags = append(diags, *s)
+	}
+	sort.Slice(diags, func(i, j int) bool { return diags[i].Provider < diags[j].Provider })
+	return diags
+}
@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIDiagnostics(t *testing.T) {
+	ClearAPICache()
+	ResetAPICircuit()
+	SetAPIFailureThreshold(1)
+	defer func() {
+		SetAPIFailureThreshold(DefaultAPIFailureThreshold)
+		ResetAPICircuit()
+		ClearAPICache()
+	}()
+
+	origURL := OMDBBaseURL
+	defer func() { OMDBBaseURL = origURL }()
+
+	resolution := func() *TVTitleResolution {
+		return &TVTitleResolution{
+			FolderMatch:   &TVTitleMatch{Title: "Firefly"},
+			FilenameMatch: &TVTitleMatch{Title: "Firefly Serenity"},
+			IsAmbiguous:   true,
+		}
+	}
+
+	// Offline: the folder lookup fails and trips the breaker, the filename lookup is skipped
+	server := httptest.NewServer(omdbOKHandler())
+	OMDBBaseURL = server.URL
+	server.Close()
+	VerifyTVShowTitle(resolution(), "", "test-key", "")
+
+	diags := APIDiagnostics()
+	if len(diags) != 1 || diags[0].Provider != "OMDB" {
+		t.Fatalf("Expected OMDB diagnostics only, got %+v", diags)
+	}
+	if d := diags[0]; d.Failures != 1 || d.Skipped != 1 || d.Successes != 0 || !d.Degraded() || !strings.Contains(d.LastError, "API request failed") {
+		t.Errorf("Unexpected offline diagnostics: %+v", d)
+	}
+
+	// A new scan starts clean and counts answered lookups as successes
+	ResetAPICircuit()
+	if len(APIDiagnostics()) != 0 {
+		t.Error("Expected ResetAPICircuit to clear diagnostics")
+	}
+	server = httptest.NewServer(omdbOKHandler())
+	defer server.Close()
+	OMDBBaseURL = server.URL
+	if err := VerifyTVShowTitle(resolution(), "", "test-key", ""); err != nil {
+		t.Fatalf("Expected verification to succeed, got %v", err)
+	}
+	if d := APIDiagnostics()[0]; d.Successes != 2 || d.Degraded() {
+		t.Errorf("Unexpected healthy diagnostics: %+v", d)
+	}
+}
+
+func TestAPIDiagnosticsOMDBErrors(t *testing.T) {
+	ClearAPICache()
+	ResetAPICircuit()
+	defer ResetAPICircuit()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.RawQuery, "Missing") {
+			w.Write([]byte(`{"Response":"False","Error":"Series not found!"}`))
+			return
+		}
+		w.Write([]byte(`{"Response":"False","Error":"Invalid API key!"}`))
+	}))
+	defer server.Close()
+
+	client := NewOMDBClient("test-key")
+	client.BaseURL = server.URL
+	client.SearchSeriesWithRetry("Missing Show", 0)
+	client.SearchSeriesWithRetry("Serenity", 0)
+
+	d := APIDiagnostics()[0]
+	if d.Successes != 1 || d.Failures != 1 || d.LastError != "OMDB error: Invalid API key!" {
+		t.Errorf("Expected not-found as success and bad key as failure, got %+v", d)
+	}
+}
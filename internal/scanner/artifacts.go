@@ -0,0 +1,271 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Artifact kinds
+const (
+	ArtifactEmptyFolder     = "empty-folder"      // folder with nothing in it
+	ArtifactLeftoverFolder  = "leftover-folder"   // movie folder holding only nfo/artwork and junk
+	ArtifactMetadata        = "orphaned-metadata" // nfo, artwork or subtitle in a folder without videos
+	ArtifactJunk            = "junk"              // release group notes and ads, e.g. RARBG.txt
+	ArtifactSample          = "sample"            // sample clip left next to the release
+	ArtifactPartialDownload = "partial-download"  // unfinished download or import, e.g. .part, .!qB or .partial~
+)
+
+// Artifact is leftover junk in a library folder that can be deleted
+// without touching a video Jellyfin plays
+type Artifact struct {
+	Path  string // Full path to the file or folder
+	Kind  string // one of the Artifact* kinds
+	Files int    `json:",omitempty"` // files inside a leftover folder
+	Size  int64  // Total size on disk
+}
+
+const (
+	// partialDownloadAge is how long a partial download must sit untouched
+	// before it counts as abandoned rather than in progress
+	partialDownloadAge = 24 * time.Hour
+	// maxSampleSize keeps full-length videos with "sample" in the title
+	// from being taken for sample clips
+	maxSampleSize = 500 << 20
+)
+
+var (
+	// Extensions download clients give files still being written, and
+	// Sonarr/Radarr give a file they are copying into the library
+	partialDownloadExts = map[string]bool{
+		".part": true, ".!qb": true, ".!ut": true, ".!bt": true, ".crdownload": true,
+		".partial": true, ".aria2": true, ".partial~": true,
+	}
+	// Notes, ads and shortcuts release groups ship with their releases
+	junkFileRegex = regexp.MustCompile(`(?i)^(rarbg(_do_not_mirror)?\.(txt|exe)|rarbg\.com\.txt|torrent[ ._-]downloaded[ ._-]from.*\.txt|(www\.)?yts[a-z]*\.[a-z]+.*\.(jpg|txt)|ytsproxies\.com\.txt|.*\.(url|lnk|website))$`)
+	// "Movie.sample.mkv", "sample-movie.mkv"
+	sampleNameRegex = regexp.MustCompile(`(?i)(^|[ ._-])sample([ ._-]|$)`)
+	// Folders that hold only a release's sample clip
+	sampleFolderRegex = regexp.MustCompile(`(?i)^samples?$`)
+)
+
+// artifactKind classifies a file that isn't worth keeping, or returns ""
+// for anything else. Sidecars count as ArtifactMetadata candidates; they are
+// only reported when their folder holds no video
+func artifactKind(path string, info os.FileInfo, now time.Time) string {
+	name := filepath.Base(path)
+	ext := strings.ToLower(filepath.Ext(name))
+	switch {
+	case partialDownloadExts[ext]:
+		if now.Sub(info.ModTime()) < partialDownloadAge {
+			return ""
+		}
+		return ArtifactPartialDownload
+	case junkFileRegex.MatchString(name):
+		return ArtifactJunk
+	case isVideoFile(path):
+		if isNamedTrailer(path) {
+			return ""
+		}
+		sample := sampleNameRegex.MatchString(fileStem(path)) || sampleFolderRegex.MatchString(filepath.Base(filepath.Dir(path)))
+		if sample && info.Size() < maxSampleSize {
+			return ArtifactSample
+		}
+		return ""
+	case isSidecarFile(path):
+		return ArtifactMetadata
+	}
+	return ""
+}
+
+// artifactTree is what a folder tree holds, as far as artifacts go
+type artifactTree struct {
+	videos   int        // video files, samples excepted
+	files    []Artifact // junk, samples, partial downloads and metadata candidates
+	empty    []string   // topmost empty folders below the tree's root
+	contents int        // entries of any kind
+	other    bool       // holds files that are neither videos nor artifacts
+}
+
+// scanArtifactTree collects the artifacts below dir. In a TV show folder
+// (tvShow) season folders without episodes are left to the orphaned folder scan
+func scanArtifactTree(dir string, tvShow bool, now time.Time) artifactTree {
+	var tree artifactTree
+	paceListing(dir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		// An unreadable folder is never reported, nor anything around it
+		return artifactTree{contents: 1, other: true}
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		tree.contents++
+		if entry.IsDir() {
+			if IsIgnoredDir(path) {
+				tree.other = true
+				continue
+			}
+			sub := scanArtifactTree(path, false, now)
+			switch {
+			case tvShow && sub.videos == 0 && seasonFolderPattern.MatchString(entry.Name()):
+				// Left to the orphaned folder scan
+			case sub.videos == 0 && !sub.other && len(sub.files) == 0:
+				// Nothing but empty folders inside
+				tree.empty = append(tree.empty, path)
+			default:
+				tree.videos += sub.videos
+				tree.files = append(tree.files, sub.files...)
+				tree.empty = append(tree.empty, sub.empty...)
+				tree.other = tree.other || sub.other
+			}
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || IsIgnoredFile(path) {
+			tree.other = true
+			continue
+		}
+		if kind := artifactKind(path, info, now); kind != "" {
+			tree.files = append(tree.files, Artifact{Path: path, Kind: kind, Size: info.Size()})
+		} else if isVideoFile(path) {
+			tree.videos++
+		} else {
+			tree.other = true
+		}
+	}
+	return tree
+}
+
+// unitArtifacts returns the artifacts in one top-level folder of a library.
+// A movie folder without videos that holds nothing but artifacts is reported
+// whole; TV show folders without videos are left to the orphaned folder scan
+func unitArtifacts(unit string, tv bool, now time.Time) []Artifact {
+	tree := scanArtifactTree(unit, tv, now)
+	switch {
+	case tree.contents == 0:
+		if tv {
+			return nil
+		}
+		return []Artifact{{Path: unit, Kind: ArtifactEmptyFolder}}
+	case tree.videos == 0 && tv:
+		return nil
+	case tree.videos == 0 && !tree.other:
+		kind := ArtifactLeftoverFolder
+		if len(tree.files) == 0 {
+			kind = ArtifactEmptyFolder
+		}
+		folder := Artifact{Path: unit, Kind: kind, Files: len(tree.files)}
+		for _, file := range tree.files {
+			folder.Size += file.Size
+		}
+		return []Artifact{folder}
+	}
+
+	var artifacts []Artifact
+	for _, file := range tree.files {
+		// Sidecars next to videos are the orphaned sidecar check's business
+		if file.Kind != ArtifactMetadata || tree.videos == 0 {
+			artifacts = append(artifacts, file)
+		}
+	}
+	for _, dir := range tree.empty {
+		artifacts = append(artifacts, Artifact{Path: dir, Kind: ArtifactEmptyFolder})
+	}
+	return artifacts
+}
+
+// ScanArtifacts finds leftover junk in the given movie and TV library roots
+func ScanArtifacts(moviePaths, tvPaths []string) ([]Artifact, error) {
+	return ScanArtifactsWithProgress(moviePaths, tvPaths, nil)
+}
+
+// ScanArtifactsWithProgress finds empty folders, nfo and artwork without a
+// video, release group junk, samples and abandoned partial downloads.
+// Artifacts are cheap to find, so incremental scans look everywhere
+func ScanArtifactsWithProgress(moviePaths, tvPaths []string, progressCh chan<- ScanProgress) ([]Artifact, error) {
+	var pr *ProgressReporter
+	if progressCh != nil {
+		pr = NewProgressReporterWithInterval(progressCh, OpScanningArtifacts, 200*time.Millisecond)
+		pr.StageUpdate("artifacts", "Looking for leftover junk files and empty folders...")
+	}
+
+	now := time.Now()
+	var artifacts []Artifact
+	scan := func(libPath string, tv bool) {
+		paceListing(libPath)
+		entries, err := os.ReadDir(libPath)
+		if err != nil {
+			if pr != nil {
+				pr.Send(SeverityWarn, fmt.Sprintf("Skipping inaccessible path: %s", libPath))
+			}
+			return
+		}
+		for _, entry := range entries {
+			path := filepath.Join(libPath, entry.Name())
+			if entry.IsDir() {
+				if !IsIgnoredDir(path) {
+					artifacts = append(artifacts, unitArtifacts(path, tv, now)...)
+				}
+				continue
+			}
+			// Loose files in the library root: sidecars there are left alone
+			info, err := entry.Info()
+			if err != nil || IsIgnoredFile(path) {
+				continue
+			}
+			if kind := artifactKind(path, info, now); kind != "" && kind != ArtifactMetadata {
+				artifacts = append(artifacts, Artifact{Path: path, Kind: kind, Size: info.Size()})
+			}
+		}
+	}
+	for _, libPath := range moviePaths {
+		scan(libPath, false)
+	}
+	for _, libPath := range tvPaths {
+		scan(libPath, true)
+	}
+
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Path < artifacts[j].Path })
+
+	if pr != nil {
+		pr.Send(SeverityInfo, fmt.Sprintf("Found %d leftover artifacts", len(artifacts)))
+	}
+	return artifacts, nil
+}
+
+// CheckArtifact reports whether the artifact at a.Path is still one, so
+// cleaners never delete something that changed since the scan
+func CheckArtifact(a Artifact) error {
+	info, err := os.Lstat(a.Path)
+	if err != nil {
+		return fmt.Errorf("cannot access: %w", err)
+	}
+	switch a.Kind {
+	case ArtifactEmptyFolder, ArtifactLeftoverFolder:
+		if !info.IsDir() {
+			return fmt.Errorf("path is not a directory")
+		}
+		tree := scanArtifactTree(a.Path, false, time.Now())
+		if tree.videos > 0 || tree.other {
+			return fmt.Errorf("folder holds more than leftovers now")
+		}
+		if a.Kind == ArtifactEmptyFolder && len(tree.files) > 0 {
+			return fmt.Errorf("folder is no longer empty")
+		}
+	default:
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("path is not a regular file")
+		}
+		if kind := artifactKind(a.Path, info, time.Now()); kind != a.Kind {
+			return fmt.Errorf("file is no longer a %s artifact", a.Kind)
+		}
+		if a.Kind == ArtifactMetadata && len(folderVideos(filepath.Dir(a.Path))) > 0 {
+			return fmt.Errorf("folder holds a video again")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScanArtifacts(t *testing.T) {
+	movies, tv := t.TempDir(), t.TempDir()
+	libraries := map[string]map[string]string{
+		movies: {
+			"Heat (1995)/Heat (1995).mkv":          "video",
+			"Heat (1995)/RARBG.txt":                "ad",
+			"Heat (1995)/Heat (1995).nfo":          "nfo",
+			"Heat (1995)/Sample/heat.sample.mkv":   "sample",
+			"Heat (1995)/Heat (1995).mkv.part":     "stale",
+			"Heat (1995)/Subs/English.srt":         "subs",
+			"Alien (1979)/Alien (1979).nfo":        "nfo",
+			"Alien (1979)/Alien (1979)-poster.jpg": "art",
+			"Alien (1979)/WWW.YTS.MX.jpg":          "ad",
+			"Notes (2001)/notes.txt":               "keep",
+			"Notes (2001)/poster.jpg":              "art",
+			"Arrival (2016).mkv.!qB":               "downloading",
+			"Arrival (2016).nfo":                   "root sidecar",
+			".Trash/Old/RARBG.txt":                 "ignored",
+		},
+		tv: {
+			"Lost (2004)/Season 01/Lost S01E01.mkv":                     "video",
+			"Lost (2004)/Season 01/Torrent Downloaded From Example.txt": "ad",
+			"Lost (2004)/Season 02/season.nfo":                          "orphan season",
+			"Firefly (2002)/tvshow.nfo":                                 "orphan show",
+		},
+	}
+	for root, files := range libraries {
+		for rel, content := range files {
+			path := filepath.Join(root, rel)
+			os.MkdirAll(filepath.Dir(path), 0755)
+			os.WriteFile(path, []byte(content), 0644)
+		}
+	}
+	old := time.Now().Add(-2 * partialDownloadAge)
+	os.Chtimes(filepath.Join(movies, "Heat (1995)/Heat (1995).mkv.part"), old, old)
+	os.MkdirAll(filepath.Join(movies, "Heat (1995)/Extras/Empty"), 0755)
+	os.MkdirAll(filepath.Join(movies, "Empty (2000)"), 0755)
+	os.MkdirAll(filepath.Join(tv, "Empty Show (2020)"), 0755)
+	os.MkdirAll(filepath.Join(tv, "Lost (2004)/Season 03"), 0755)
+
+	artifacts, err := ScanArtifacts([]string{movies}, []string{tv})
+	if err != nil {
+		t.Fatalf("ScanArtifacts() error: %v", err)
+	}
+
+	want := []Artifact{
+		{Path: filepath.Join(movies, "Alien (1979)"), Kind: ArtifactLeftoverFolder, Files: 3, Size: 8},
+		{Path: filepath.Join(movies, "Empty (2000)"), Kind: ArtifactEmptyFolder},
+		{Path: filepath.Join(movies, "Heat (1995)/Extras"), Kind: ArtifactEmptyFolder},
+		{Path: filepath.Join(movies, "Heat (1995)/Heat (1995).mkv.part"), Kind: ArtifactPartialDownload, Size: 5},
+		{Path: filepath.Join(movies, "Heat (1995)/RARBG.txt"), Kind: ArtifactJunk, Size: 2},
+		{Path: filepath.Join(movies, "Heat (1995)/Sample/heat.sample.mkv"), Kind: ArtifactSample, Size: 6},
+		{Path: filepath.Join(movies, "Notes (2001)/poster.jpg"), Kind: ArtifactMetadata, Size: 3},
+		{Path: filepath.Join(tv, "Lost (2004)/Season 01/Torrent Downloaded From Example.txt"), Kind: ArtifactJunk, Size: 2},
+	}
+	if len(artifacts) != len(want) {
+		t.Fatalf("ScanArtifacts() = %+v\nwant %+v", artifacts, want)
+	}
+	for i := range want {
+		if artifacts[i] != want[i] {
+			t.Errorf("artifact %d = %+v, want %+v", i, artifacts[i], want[i])
+		}
+	}
+}
+
+func TestCheckArtifact(t *testing.T) {
+	dir := t.TempDir()
+	leftover := filepath.Join(dir, "Alien (1979)")
+	os.MkdirAll(leftover, 0755)
+	os.WriteFile(filepath.Join(leftover, "Alien (1979).nfo"), []byte("nfo"), 0644)
+	junk := filepath.Join(dir, "RARBG.txt")
+	os.WriteFile(junk, []byte("ad"), 0644)
+
+	if err := CheckArtifact(Artifact{Path: leftover, Kind: ArtifactLeftoverFolder}); err != nil {
+		t.Errorf("CheckArtifact() leftover folder error: %v", err)
+	}
+	if err := CheckArtifact(Artifact{Path: leftover, Kind: ArtifactEmptyFolder}); err == nil {
+		t.Error("Expected a folder with files to no longer count as empty")
+	}
+	if err := CheckArtifact(Artifact{Path: junk, Kind: ArtifactJunk}); err != nil {
+		t.Errorf("CheckArtifact() junk error: %v", err)
+	}
+
+	// The movie arrived after the scan
+	os.WriteFile(filepath.Join(leftover, "Alien (1979).mkv"), []byte("video"), 0644)
+	if err := CheckArtifact(Artifact{Path: leftover, Kind: ArtifactLeftoverFolder}); err == nil {
+		t.Error("Expected a folder with a video to be kept")
+	}
+	if err := CheckArtifact(Artifact{Path: filepath.Join(dir, "gone.part"), Kind: ArtifactPartialDownload}); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
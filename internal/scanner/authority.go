@@ -0,0 +1,177 @@
+package scanner
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+)
+
+// TitleAuthority is a library manager whose names win over TVDB/OMDB
+// lookups and filename guesses: Sonarr for shows, Radarr for movies
+type TitleAuthority interface {
+	Name() string
+	// Title returns the title and year the manager uses for the show or
+	// movie stored in folder, or failing that matching one of candidates;
+	// ok is false when it does not manage it
+	Title(folder string, candidates ...string) (title, year string, ok bool, err error)
+}
+
+// RenameNotifier is told about files and folders jellysink renamed, so
+// library managers can follow them instead of reporting them missing
+type RenameNotifier interface {
+	NotifyRenames(renames []RenameResult) error
+}
+
+var (
+	renameNotifier   RenameNotifier
+	renameNotifierMu sync.RWMutex
+)
+
+// SetRenameNotifier sets who NotifyRenames tells (nil disables it)
+func SetRenameNotifier(n RenameNotifier) {
+	renameNotifierMu.Lock()
+	defer renameNotifierMu.Unlock()
+	renameNotifier = n
+}
+
+// NotifyRenames passes the successful renames to the notifier set with
+// SetRenameNotifier
+func NotifyRenames(renames []RenameResult) error {
+	renameNotifierMu.RLock()
+	n := renameNotifier
+	renameNotifierMu.RUnlock()
+
+	var done []RenameResult
+	for _, r := range renames {
+		if r.Success && r.NewPath != "" && r.NewPath != r.OldPath {
+			done = append(done, r)
+		}
+	}
+	if n == nil || len(done) == 0 {
+		return nil
+	}
+	return n.NotifyRenames(done)
+}
+
+var (
+	seriesAuthority TitleAuthority
+	movieAuthority  TitleAuthority
+	authorityFailed = make(map[string]bool) // authorities that failed this scan
+	authorityMu     sync.RWMutex
+)
+
+// SetTitleAuthorities sets the managers consulted during scans; nil skips one
+func SetTitleAuthorities(series, movies TitleAuthority) {
+	authorityMu.Lock()
+	defer authorityMu.Unlock()
+	seriesAuthority, movieAuthority = series, movies
+}
+
+// titleAuthorities returns the managers set by SetTitleAuthorities
+func titleAuthorities() (series, movies TitleAuthority) {
+	authorityMu.RLock()
+	defer authorityMu.RUnlock()
+	return seriesAuthority, movieAuthority
+}
+
+// resetTitleAuthorities gives authorities that failed last scan another chance
+func resetTitleAuthorities() {
+	authorityMu.Lock()
+	defer authorityMu.Unlock()
+	authorityFailed = make(map[string]bool)
+}
+
+// authorityTitle asks a for the title of folder, made safe for filenames.
+// An authority that fails is reported once and skipped for the rest of the
+// scan, leaving the lookup to TVDB/OMDB and the filename
+func authorityTitle(a TitleAuthority, folder string, candidates []string, pr *ProgressReporter) (title, year string, ok bool) {
+	if a == nil {
+		return "", "", false
+	}
+	authorityMu.RLock()
+	failed := authorityFailed[a.Name()]
+	authorityMu.RUnlock()
+	if failed {
+		return "", "", false
+	}
+
+	title, year, ok, err := a.Title(folder, candidates...)
+	if err != nil {
+		authorityMu.Lock()
+		authorityFailed[a.Name()] = true
+		authorityMu.Unlock()
+		if pr != nil {
+			pr.SendSeverityImmediate(SeverityWarn, fmt.Sprintf("%s unavailable (%v), skipping it for the rest of this scan", a.Name(), err))
+		}
+		return "", "", false
+	}
+	if !ok {
+		return "", "", false
+	}
+	// Managed titles may hold characters filenames cannot, e.g. "Star Trek: Picard"
+	title = sanitizeEpisodeTitle(title)
+	return title, year, title != ""
+}
+
+// resolveWithAuthority settles an ambiguous show with the title its series
+// manager uses, reporting whether it did
+func resolveWithAuthority(resolution *TVTitleResolution, showFolder string, pr *ProgressReporter) bool {
+	series, _ := titleAuthorities()
+	if series == nil {
+		return false
+	}
+
+	var candidates []string
+	if resolution.FolderMatch != nil {
+		candidates = append(candidates, resolution.FolderMatch.Title)
+	}
+	if resolution.FilenameMatch != nil {
+		candidates = append(candidates, resolution.FilenameMatch.Title)
+	}
+	title, _, ok := authorityTitle(series, showFolder, candidates, pr)
+	if !ok {
+		return false
+	}
+
+	resolution.ResolvedTitle = title
+	resolution.APIVerified = true
+	resolution.IsAmbiguous = false
+	resolution.Confidence = 1.0
+	resolution.Reason = fmt.Sprintf("%s manages this show as '%s'", series.Name(), title)
+	if pr != nil {
+		pr.SendSeverityImmediate(SeverityInfo, fmt.Sprintf("%s verified: %s", series.Name(), title))
+	}
+	return true
+}
+
+// preferManagedMovieName renames issue's suggested folder and file to the
+// name the movie manager uses for the movie, reporting whether it manages it
+func preferManagedMovieName(issue *ComplianceIssue, libRoot string, pr *ProgressReporter) bool {
+	_, movies := titleAuthorities()
+	if movies == nil || issue.SuggestedAction != "reorganize" {
+		return false
+	}
+
+	// A file loose in the library root has no folder of its own
+	folder := filepath.Dir(issue.Path)
+	if folder == libRoot {
+		folder = ""
+	}
+	suggestedDir := filepath.Dir(issue.SuggestedPath)
+	title, year, ok := authorityTitle(movies, folder, []string{filepath.Base(suggestedDir)}, pr)
+	if !ok {
+		return false
+	}
+
+	name := title
+	if year != "" && year != "0" {
+		name = fmt.Sprintf("%s (%s)", title, year)
+	}
+	if name == filepath.Base(suggestedDir) {
+		return true
+	}
+	newDir := filepath.Join(filepath.Dir(suggestedDir), name)
+	issue.SuggestedPath = filepath.Join(newDir, name+filepath.Ext(issue.Path))
+	issue.Problem += fmt.Sprintf(" (named as in %s)", movies.Name())
+	return true
+}
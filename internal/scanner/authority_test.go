@@ -0,0 +1,110 @@
+package scanner
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeAuthority answers from titles keyed by folder and counts lookups
+type fakeAuthority struct {
+	titles  map[string][2]string // folder -> title, year
+	err     error
+	lookups int
+}
+
+func (a *fakeAuthority) Name() string { return "Sonarr" }
+
+func (a *fakeAuthority) Title(folder string, candidates ...string) (string, string, bool, error) {
+	a.lookups++
+	if a.err != nil {
+		return "", "", false, a.err
+	}
+	t, ok := a.titles[folder]
+	return t[0], t[1], ok, nil
+}
+
+func useAuthorities(t *testing.T, series, movies TitleAuthority) {
+	t.Helper()
+	SetTitleAuthorities(series, movies)
+	resetTitleAuthorities()
+	t.Cleanup(func() {
+		SetTitleAuthorities(nil, nil)
+		resetTitleAuthorities()
+	})
+}
+
+func TestAuthorityResolvesAmbiguousShow(t *testing.T) {
+	sonarr := &fakeAuthority{titles: map[string][2]string{
+		"/tv/Degrassi": {"Degrassi: The Next Generation", "2001"},
+	}}
+	useAuthorities(t, sonarr, nil)
+
+	checked := make(map[string]*TVTitleResolution)
+	first := &TVTitleResolution{
+		FolderMatch:   &TVTitleMatch{Title: "Degrassi"},
+		FilenameMatch: &TVTitleMatch{Title: "Degrassi The Next Generation"},
+		IsAmbiguous:   true,
+	}
+	verifyAmbiguousShow(first, "/tv/Degrassi", checked, nil)
+	if first.ResolvedTitle != "Degrassi The Next Generation" || first.IsAmbiguous || !first.APIVerified {
+		t.Fatalf("Expected Sonarr's title made filename safe, got %+v", first)
+	}
+
+	second := &TVTitleResolution{IsAmbiguous: true}
+	verifyAmbiguousShow(second, "/tv/Degrassi", checked, nil)
+	if second.ResolvedTitle != first.ResolvedTitle || sonarr.lookups != 1 {
+		t.Errorf("Expected the folder's other episodes to reuse the lookup, got %+v after %d lookups", second, sonarr.lookups)
+	}
+
+	unknown := &TVTitleResolution{IsAmbiguous: true, FolderMatch: &TVTitleMatch{Title: "Other"}}
+	verifyAmbiguousShow(unknown, "/tv/Other", checked, nil)
+	if !unknown.IsAmbiguous || unknown.APIVerified {
+		t.Errorf("Expected a show Sonarr does not manage left ambiguous, got %+v", unknown)
+	}
+}
+
+func TestAuthorityFailureSkipsItForTheScan(t *testing.T) {
+	radarr := &fakeAuthority{err: errors.New("connection refused")}
+	useAuthorities(t, nil, radarr)
+
+	for i := 0; i < 3; i++ {
+		issue := &ComplianceIssue{Path: "/movies/heat.mkv", SuggestedPath: "/movies/Heat (1995)/Heat (1995).mkv", SuggestedAction: "reorganize"}
+		preferManagedMovieName(issue, "/movies", nil)
+		if issue.SuggestedPath != "/movies/Heat (1995)/Heat (1995).mkv" {
+			t.Errorf("Expected the cleaned name kept, got %s", issue.SuggestedPath)
+		}
+	}
+	if radarr.lookups != 1 {
+		t.Errorf("Expected one lookup before skipping Radarr, got %d", radarr.lookups)
+	}
+
+	ResetAPICircuit()
+	preferManagedMovieName(&ComplianceIssue{Path: "/movies/x/x.mkv", SuggestedPath: "/movies/X/X.mkv", SuggestedAction: "reorganize"}, "/movies", nil)
+	if radarr.lookups != 2 {
+		t.Error("Expected the next scan to try Radarr again")
+	}
+}
+
+func TestPreferManagedMovieName(t *testing.T) {
+	radarr := &fakeAuthority{titles: map[string][2]string{
+		"/movies/Mission.Impossible.1996.1080p-GRP": {"Mission: Impossible", "1996"},
+	}}
+	useAuthorities(t, nil, radarr)
+
+	issue := &ComplianceIssue{
+		Path:            "/movies/Mission.Impossible.1996.1080p-GRP/mi.mkv",
+		Problem:         "Release group folder naming",
+		SuggestedPath:   "/movies/Mission Impossible (1996)/Mission Impossible (1996).mkv",
+		SuggestedAction: "reorganize",
+	}
+	preferManagedMovieName(issue, "/movies", nil)
+	if issue.SuggestedPath != "/movies/Mission Impossible (1996)/Mission Impossible (1996).mkv" {
+		t.Errorf("Expected an unchanged suggestion when Radarr agrees, got %s", issue.SuggestedPath)
+	}
+
+	radarr.titles["/movies/Mission.Impossible.1996.1080p-GRP"] = [2]string{"Mission: Impossible - Rogue Nation", "2015"}
+	preferManagedMovieName(issue, "/movies", nil)
+	if issue.SuggestedPath != "/movies/Mission Impossible - Rogue Nation (2015)/Mission Impossible - Rogue Nation (2015).mkv" {
+		t.Errorf("Expected Radarr's name, got %s", issue.SuggestedPath)
+	}
+}
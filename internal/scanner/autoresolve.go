@@ -0,0 +1,63 @@
+package scanner
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+var (
+	autoResolveMargin   float64
+	autoResolveMarginMu sync.RWMutex
+)
+
+// SetAutoResolveMargin sets how far one side's title confidence must lead
+// the other's for an API conflict to be resolved without manual review
+// (naming.auto_resolve_margin); 0 sends every conflict to review
+func SetAutoResolveMargin(margin float64) {
+	autoResolveMarginMu.Lock()
+	defer autoResolveMarginMu.Unlock()
+	autoResolveMargin = margin
+}
+
+// currentAutoResolveMargin returns the margin set by SetAutoResolveMargin
+func currentAutoResolveMargin() float64 {
+	autoResolveMarginMu.RLock()
+	defer autoResolveMarginMu.RUnlock()
+	return autoResolveMargin
+}
+
+// autoResolveConflict settles a folder/filename conflict in favor of the
+// more confident title when it leads by more than the margin and an API
+// found a series under that title. Reports whether it resolved anything
+func autoResolveConflict(resolution *TVTitleResolution, pr *ProgressReporter) bool {
+	margin := currentAutoResolveMargin()
+	if margin <= 0 || !resolution.IsAmbiguous || resolution.FolderMatch == nil || resolution.FilenameMatch == nil {
+		return false
+	}
+
+	winner, loser := resolution.FolderMatch, resolution.FilenameMatch
+	if loser.Confidence > winner.Confidence {
+		winner, loser = loser, winner
+	}
+	// Scores are sums of 0.1 steps; round so 1.0-0.7 is not above 0.3
+	lead := math.Round((winner.Confidence-loser.Confidence)*100) / 100
+	if lead <= margin {
+		return false
+	}
+	// The API must have matched the winning title itself, not a similar one
+	if winner.APITitle == "" || NormalizeName(winner.APITitle) != NormalizeName(winner.Title) {
+		return false
+	}
+
+	resolution.ResolvedTitle = winner.APITitle
+	resolution.IsAmbiguous = false
+	resolution.APIVerified = true
+	resolution.Confidence = winner.Confidence
+	resolution.Reason = fmt.Sprintf("Auto-resolved to %s title '%s' (%.0f%% vs %.0f%% confidence, API-verified)",
+		winner.Source, winner.APITitle, winner.Confidence*100, loser.Confidence*100)
+	if pr != nil {
+		pr.SendSeverityImmediate(SeverityInfo, resolution.Reason)
+	}
+	return true
+}
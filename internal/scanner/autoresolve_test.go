@@ -0,0 +1,87 @@
+package scanner
+
+import "testing"
+
+func TestAutoResolveConflict(t *testing.T) {
+	conflict := func(folderConf, filenameConf float64, folderAPI, filenameAPI string) *TVTitleResolution {
+		return &TVTitleResolution{
+			ResolvedTitle: folderAPI,
+			FolderMatch:   &TVTitleMatch{Title: "Degrassi", Source: "folder", Confidence: folderConf, APITitle: folderAPI},
+			FilenameMatch: &TVTitleMatch{Title: "Degrassi the Next Generation", Source: "filename", Confidence: filenameConf, APITitle: filenameAPI},
+			APIVerified:   true,
+			IsAmbiguous:   true,
+			Confidence:    0.6,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		margin     float64
+		resolution *TVTitleResolution
+		want       string // "" = left for review
+	}{
+		{"disabled", 0, conflict(0.7, 1.0, "Degrassi", "Degrassi: The Next Generation"), ""},
+		{"lead above margin", 0.2, conflict(0.7, 1.0, "Degrassi", "Degrassi: The Next Generation"), "Degrassi: The Next Generation"},
+		{"folder leads", 0.2, conflict(1.0, 0.7, "Degrassi", "Degrassi: The Next Generation"), "Degrassi"},
+		{"lead within margin", 0.3, conflict(0.7, 1.0, "Degrassi", "Degrassi: The Next Generation"), ""},
+		{"winner not API-verified", 0.2, conflict(0.7, 1.0, "Degrassi", ""), ""},
+		{"API matched a different title", 0.2, conflict(0.7, 1.0, "Degrassi", "Degrassi High"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetAutoResolveMargin(tt.margin)
+			defer SetAutoResolveMargin(0)
+
+			resolved := autoResolveConflict(tt.resolution, nil)
+			if resolved != (tt.want != "") {
+				t.Fatalf("Expected resolved=%v, got %v (%s)", tt.want != "", resolved, tt.resolution.Reason)
+			}
+			if !resolved {
+				if !tt.resolution.IsAmbiguous {
+					t.Error("Expected the conflict left for review")
+				}
+				return
+			}
+			if tt.resolution.IsAmbiguous || !tt.resolution.APIVerified || tt.resolution.ResolvedTitle != tt.want {
+				t.Errorf("Expected resolution to %s, got %+v", tt.want, tt.resolution)
+			}
+		})
+	}
+}
+
+func TestVerifyAmbiguousShowAutoResolves(t *testing.T) {
+	tmdbServer(t, map[string][]TMDBResult{
+		"/search/tv?Degrassi":                     {{ID: 1, Name: "Degrassi", FirstAirDate: "2016-01-04"}},
+		"/search/tv?Degrassi the Next Generation": {{ID: 2, Name: "Degrassi: The Next Generation", FirstAirDate: "2001-10-14"}},
+	})
+	SetAPIKeys("", "", "test-key")
+	defer SetAPIKeys("", "", "")
+	SetAutoResolveMargin(0.2)
+	defer SetAutoResolveMargin(0)
+
+	newResolution := func() *TVTitleResolution {
+		return &TVTitleResolution{
+			ResolvedTitle: "Degrassi",
+			FolderMatch:   &TVTitleMatch{Title: "Degrassi", Source: "folder", Confidence: 0.7},
+			FilenameMatch: &TVTitleMatch{Title: "Degrassi the Next Generation", Source: "filename", Confidence: 1.0},
+			IsAmbiguous:   true,
+		}
+	}
+
+	checked := make(map[string]*TVTitleResolution)
+	first := newResolution()
+	verifyAmbiguousShow(first, "/tv/Degrassi", checked, nil)
+	if first.IsAmbiguous || first.ResolvedTitle != "Degrassi: The Next Generation" {
+		t.Fatalf("Expected the conflict auto-resolved to the filename series, got %+v", first)
+	}
+	if first.FolderMatch.APITitle != "Degrassi" || first.FilenameMatch.APITitle != "Degrassi: The Next Generation" {
+		t.Errorf("Expected both API titles recorded, got %q and %q", first.FolderMatch.APITitle, first.FilenameMatch.APITitle)
+	}
+
+	// Later episodes of the show take the same answer
+	next := newResolution()
+	verifyAmbiguousShow(next, "/tv/Degrassi", checked, nil)
+	if next.IsAmbiguous || next.ResolvedTitle != first.ResolvedTitle {
+		t.Errorf("Expected the next episode to share the resolution, got %+v", next)
+	}
+}
@@ -0,0 +1,250 @@
+package scanner
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Probing reads every file in a duplicate group, and how many reads pay off
+// at once depends on the storage: SSDs keep up with many, HDDs thrash when
+// their heads are pulled between files and SMB shares queue requests anyway.
+// Each mount gets its own worker count, starting from its storage class and
+// tuned on measured throughput over the first seconds of probing
+
+const (
+	tuneWindow  = 2 * time.Second // throughput is measured over windows this long
+	tuneWindows = 3               // windows after which a mount's worker count is fixed
+)
+
+// storageClass is the kind of storage a mount sits on
+type storageClass string
+
+const (
+	storageNetwork storageClass = "network share"
+	storageCloud   storageClass = "cloud mount"
+	storageHDD     storageClass = "HDD"
+	storageSSD     storageClass = "SSD"
+	storageUnknown storageClass = "unknown storage"
+)
+
+// workerRange returns the worker count probing starts with on class, and
+// the most tuning may raise it to. Low-memory mode starts at one worker
+// and stops at two
+func (c storageClass) workerRange() (initial, max int) {
+	switch c {
+	case storageNetwork, storageCloud:
+		initial, max = 1, 1
+	case storageHDD:
+		initial, max = 2, 3
+	case storageSSD:
+		initial, max = 4, 8
+	default:
+		initial, max = probeWorkers, 8
+	}
+	if LowMemory() {
+		return 1, lowMemoryWorkers(max)
+	}
+	return initial, max
+}
+
+// networkFSTypes are the filesystems served over the network
+var networkFSTypes = map[string]bool{
+	"cifs": true, "smb3": true, "smbfs": true, "nfs": true, "nfs4": true,
+	"9p": true, "afpfs": true, "davfs": true, "fuse.sshfs": true,
+}
+
+// Where mounts and their block devices are described (swapped out by tests)
+var (
+	mountInfoPath  = "/proc/self/mountinfo"
+	sysDevBlockDir = "/sys/dev/block"
+)
+
+// mountInfo is one mounted filesystem
+type mountInfo struct {
+	Point  string // mount point
+	FSType string // e.g. ext4, cifs
+	Device string // major:minor of the block device
+}
+
+// label names the mount in progress messages
+func (m mountInfo) label() string {
+	if m.Point == "" {
+		return "library"
+	}
+	return m.Point
+}
+
+// mountEscapes undoes the octal escapes mountinfo uses in paths
+var mountEscapes = strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`)
+
+// parseMountInfo reads /proc/self/mountinfo lines such as
+// "36 35 8:1 / /mnt/media rw,noatime shared:1 - ext4 /dev/sda1 rw"
+func parseMountInfo(r io.Reader) []mountInfo {
+	var mounts []mountInfo
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		sep := -1
+		for i, field := range fields {
+			if field == "-" {
+				sep = i
+				break
+			}
+		}
+		if len(fields) < 5 || sep < 0 || sep+1 >= len(fields) {
+			continue
+		}
+		mounts = append(mounts, mountInfo{
+			Point:  mountEscapes.Replace(fields[4]),
+			FSType: fields[sep+1],
+			Device: fields[2],
+		})
+	}
+	return mounts
+}
+
+// loadMounts reads the mount table; nil where there is none (non-Linux)
+func loadMounts() []mountInfo {
+	f, err := os.Open(mountInfoPath)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	return parseMountInfo(f)
+}
+
+// mountFor returns the mount path is on: the longest mount point holding it,
+// the last one listed when mounts are stacked
+func mountFor(mounts []mountInfo, path string) mountInfo {
+	var best mountInfo
+	found := false
+	for _, m := range mounts {
+		inside := path == m.Point || m.Point == "/" || strings.HasPrefix(path, m.Point+string(filepath.Separator))
+		if inside && (!found || len(m.Point) >= len(best.Point)) {
+			best, found = m, true
+		}
+	}
+	return best
+}
+
+// classifyMount tells cloud mounts, network shares, HDDs and SSDs apart
+// from the filesystem type and the block device's rotational flag
+func classifyMount(m mountInfo) storageClass {
+	if cloudFSTypes[m.FSType] {
+		return storageCloud
+	}
+	if networkFSTypes[m.FSType] {
+		return storageNetwork
+	}
+	if m.Device == "" {
+		return storageUnknown
+	}
+	rotational, err := os.ReadFile(filepath.Join(sysDevBlockDir, m.Device, "queue", "rotational"))
+	if err != nil {
+		// Partitions keep the queue settings on their parent disk
+		if dev, linkErr := filepath.EvalSymlinks(filepath.Join(sysDevBlockDir, m.Device)); linkErr == nil {
+			rotational, err = os.ReadFile(filepath.Join(filepath.Dir(dev), "queue", "rotational"))
+		}
+	}
+	if err != nil {
+		return storageUnknown
+	}
+	switch strings.TrimSpace(string(rotational)) {
+	case "1":
+		return storageHDD
+	case "0":
+		return storageSSD
+	}
+	return storageUnknown
+}
+
+// mountTuner caps the probes running at once on one mount, adjusting the
+// cap on measured throughput until it settles
+type mountTuner struct {
+	mount mountInfo
+	class storageClass
+	max   int
+	now   func() time.Time
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	limit    int       // probes allowed at once
+	active   int       // probes running
+	started  time.Time // start of the current window
+	done     int       // probes finished in the current window
+	lastRate float64   // files/s of the previous window
+	windows  int
+	settled  bool
+}
+
+// newMountTuner starts m at its storage class's initial worker count
+func newMountTuner(m mountInfo, class storageClass, now func() time.Time) *mountTuner {
+	initial, max := class.workerRange()
+	t := &mountTuner{mount: m, class: class, max: max, limit: initial, now: now, started: now()}
+	t.cond = sync.NewCond(&t.mu)
+	return t
+}
+
+// acquire waits for a free slot under the current cap
+func (t *mountTuner) acquire() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for t.active >= t.limit {
+		t.cond.Wait()
+	}
+	t.active++
+}
+
+// release frees a slot. When it closes a measuring window it returns what
+// tuning made of it, else ""
+func (t *mountTuner) release() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active--
+	t.done++
+	defer t.cond.Broadcast()
+
+	if t.settled {
+		return ""
+	}
+	elapsed := t.now().Sub(t.started)
+	if elapsed < tuneWindow {
+		return ""
+	}
+	msg := t.adjust(float64(t.done) / elapsed.Seconds())
+	t.done = 0
+	t.started = t.now()
+	return msg
+}
+
+// adjust feeds one window's throughput to the tuner: it adds a worker while
+// that keeps raising throughput by over 10%, takes back a step that lowered
+// it, and settles once neither applies or tuneWindows windows have passed
+func (t *mountTuner) adjust(rate float64) string {
+	t.windows++
+	prev := t.lastRate
+	t.lastRate = rate
+
+	if t.limit < t.max && t.windows < tuneWindows && (prev == 0 || rate > prev*1.1) {
+		t.limit++
+		return fmt.Sprintf("Probing %s: %.1f files/s with %d workers, trying %d", t.mount.label(), rate, t.limit-1, t.limit)
+	}
+	if prev != 0 && rate < prev*0.9 && t.limit > 1 {
+		t.limit--
+	}
+	t.settled = true
+	return fmt.Sprintf("Probing %s (%s): settled on %d workers at %.1f files/s", t.mount.label(), t.class, t.limit, rate)
+}
+
+// workers returns the current cap
+func (t *mountTuner) workers() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.limit
+}
@@ -0,0 +1,112 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMountClassification(t *testing.T) {
+	mounts := parseMountInfo(strings.NewReader(`22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+36 22 259:2 / /mnt/fast rw,noatime shared:5 - xfs /dev/nvme0n1p2 rw
+41 22 0:52 / /mnt/nas\040share rw,relatime shared:9 - cifs //nas/media rw,vers=3.0
+42 22 8:16 / /mnt/fast/archive rw,relatime shared:10 - ext4 /dev/sdb rw
+`))
+	if len(mounts) != 4 || mounts[2].Point != "/mnt/nas share" || mounts[2].FSType != "cifs" {
+		t.Fatalf("parseMountInfo() = %+v", mounts)
+	}
+
+	// A fake sysfs: sda (rotational) with partition sda1, nvme0n1 with
+	// partition p2, and sdb (rotational) as a whole disk
+	sys := t.TempDir()
+	disk := func(name, rotational string) {
+		os.MkdirAll(filepath.Join(sys, "devices", name, "queue"), 0755)
+		os.WriteFile(filepath.Join(sys, "devices", name, "queue", "rotational"), []byte(rotational+"\n"), 0644)
+	}
+	disk("sda", "1")
+	disk("nvme0n1", "0")
+	disk("sdb", "1")
+	os.MkdirAll(filepath.Join(sys, "devices", "sda", "sda1"), 0755)
+	os.MkdirAll(filepath.Join(sys, "devices", "nvme0n1", "nvme0n1p2"), 0755)
+	os.MkdirAll(filepath.Join(sys, "block"), 0755)
+	os.Symlink(filepath.Join(sys, "devices", "sda", "sda1"), filepath.Join(sys, "block", "8:1"))
+	os.Symlink(filepath.Join(sys, "devices", "nvme0n1", "nvme0n1p2"), filepath.Join(sys, "block", "259:2"))
+	os.Symlink(filepath.Join(sys, "devices", "sdb"), filepath.Join(sys, "block", "8:16"))
+	orig := sysDevBlockDir
+	sysDevBlockDir = filepath.Join(sys, "block")
+	t.Cleanup(func() { sysDevBlockDir = orig })
+
+	tests := []struct {
+		path  string
+		point string
+		class storageClass
+	}{
+		{"/home/user/movies/a.mkv", "/", storageHDD},
+		{"/mnt/fast/movies/a.mkv", "/mnt/fast", storageSSD},
+		{"/mnt/fast/archive/a.mkv", "/mnt/fast/archive", storageHDD},
+		{"/mnt/nas share/tv/a.mkv", "/mnt/nas share", storageNetwork},
+		{"/mnt/fastest/a.mkv", "/", storageHDD},
+	}
+	for _, tt := range tests {
+		m := mountFor(mounts, tt.path)
+		if m.Point != tt.point {
+			t.Errorf("mountFor(%s) = %s, want %s", tt.path, m.Point, tt.point)
+		}
+		if class := classifyMount(m); class != tt.class {
+			t.Errorf("classifyMount(%s) = %s, want %s", m.Point, class, tt.class)
+		}
+	}
+	if class := classifyMount(mountInfo{Point: "/mnt/zfs", FSType: "zfs", Device: "0:60"}); class != storageUnknown {
+		t.Errorf("Expected a device without sysfs entry unknown, got %s", class)
+	}
+	if m := mountFor(nil, "/mnt/media/a.mkv"); m.label() != "library" {
+		t.Errorf("Expected an unlabeled mount without a mount table, got %+v", m)
+	}
+}
+
+func TestMountTuner(t *testing.T) {
+	clock := time.Unix(0, 0)
+	now := func() time.Time { return clock }
+	// window runs n probes finishing over one tuning window
+	window := func(tuner *mountTuner, n int) string {
+		var msg string
+		for i := 0; i < n; i++ {
+			tuner.acquire()
+			if i == n-1 {
+				clock = clock.Add(tuneWindow)
+			}
+			msg = tuner.release()
+		}
+		return msg
+	}
+
+	// SSDs climb while throughput keeps rising and stop at the first flat window
+	ssd := newMountTuner(mountInfo{Point: "/mnt/fast"}, storageSSD, now)
+	if ssd.workers() != 4 {
+		t.Fatalf("Expected SSDs to start at 4 workers, got %d", ssd.workers())
+	}
+	if msg := window(ssd, 20); ssd.workers() != 5 || !strings.Contains(msg, "trying 5") {
+		t.Errorf("Expected a step up after the first window, got %d (%q)", ssd.workers(), msg)
+	}
+	if msg := window(ssd, 21); ssd.workers() != 5 || !strings.Contains(msg, "settled on 5") {
+		t.Errorf("Expected flat throughput to settle, got %d (%q)", ssd.workers(), msg)
+	}
+	if msg := window(ssd, 40); msg != "" || ssd.workers() != 5 {
+		t.Errorf("Expected a settled mount left alone, got %d (%q)", ssd.workers(), msg)
+	}
+
+	// A step up that lowers throughput is taken back
+	hdd := newMountTuner(mountInfo{Point: "/mnt/disk"}, storageHDD, now)
+	window(hdd, 10)
+	if msg := window(hdd, 6); hdd.workers() != 2 || !strings.Contains(msg, "settled on 2") {
+		t.Errorf("Expected the HDD back at 2 workers, got %d (%q)", hdd.workers(), msg)
+	}
+
+	// Network shares are probed one file at a time
+	nas := newMountTuner(mountInfo{Point: "/mnt/nas"}, storageNetwork, now)
+	if msg := window(nas, 5); nas.workers() != 1 || !strings.Contains(msg, "settled on 1") {
+		t.Errorf("Expected a share serialized, got %d (%q)", nas.workers(), msg)
+	}
+}
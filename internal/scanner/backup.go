@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
 )
 
 type FileEntry struct {
@@ -44,12 +46,7 @@ type BackupSnapshot struct {
 }
 
 func GetBackupDir() (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
-	}
-
-	backupDir := filepath.Join(homeDir, ".local", "share", "jellysink", "backups")
+	backupDir := filepath.Join(oplog.DataDir(), "backups")
 
 	if err := os.MkdirAll(backupDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create backup directory: %w", err)
@@ -61,7 +58,7 @@ func GetBackupDir() (string, error) {
 func CreateBackup(libraryType string, paths []string, progressCh chan<- ScanProgress) (*BackupSnapshot, error) {
 	var pr *ProgressReporter
 	if progressCh != nil {
-		pr = NewProgressReporterWithInterval(progressCh, "backup_library", 500*time.Millisecond)
+		pr = NewProgressReporterWithInterval(progressCh, OpBackupLibrary, 500*time.Millisecond)
 		pr.StageUpdate("validating", "Validating library paths for backup...")
 	}
 
@@ -94,7 +91,7 @@ func CreateBackup(libraryType string, paths []string, progressCh chan<- ScanProg
 	for _, libPath := range paths {
 		if _, err := os.Stat(libPath); err != nil {
 			if pr != nil {
-				pr.Send("warn", fmt.Sprintf("Skipping inaccessible path: %s", libPath))
+				pr.Send(SeverityWarn, fmt.Sprintf("Skipping inaccessible path: %s", libPath))
 			}
 			continue
 		}
@@ -102,11 +99,15 @@ func CreateBackup(libraryType string, paths []string, progressCh chan<- ScanProg
 		err := filepath.Walk(libPath, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				if pr != nil {
-					pr.Send("warn", fmt.Sprintf("Error accessing %s: %v", path, err))
+					pr.Send(SeverityWarn, fmt.Sprintf("Error accessing %s: %v", path, err))
 				}
 				return nil
 			}
 
+			if skip, skipErr := walkSkip(libPath, path, info); skip {
+				return skipErr
+			}
+
 			if !info.IsDir() && isVideoFile(path) {
 				relPath, _ := filepath.Rel(libPath, path)
 
@@ -226,7 +227,7 @@ func ListBackups() ([]*BackupSnapshot, error) {
 func (b *BackupSnapshot) VerifyIntegrity(progressCh chan<- ScanProgress) (bool, []string) {
 	var pr *ProgressReporter
 	if progressCh != nil {
-		pr = NewProgressReporterWithInterval(progressCh, "verify_backup", 500*time.Millisecond)
+		pr = NewProgressReporterWithInterval(progressCh, OpVerifyBackup, 500*time.Millisecond)
 		pr.StageUpdate("verifying", "Verifying backup integrity...")
 		pr.Start(len(b.Metadata.Entries), fmt.Sprintf("Checking %d files...", len(b.Metadata.Entries)))
 	}
@@ -294,7 +295,7 @@ func (b *BackupSnapshot) Save() error {
 func RevertBackup(backupID string, progressCh chan<- ScanProgress) error {
 	var pr *ProgressReporter
 	if progressCh != nil {
-		pr = NewProgressReporterWithInterval(progressCh, "revert_backup", 500*time.Millisecond)
+		pr = NewProgressReporterWithInterval(progressCh, OpRevertBackup, 500*time.Millisecond)
 		pr.StageUpdate("loading", "Loading backup...")
 	}
 
@@ -308,7 +309,7 @@ func RevertBackup(backupID string, progressCh chan<- ScanProgress) error {
 
 	if len(snapshot.Metadata.Operations) == 0 {
 		if pr != nil {
-			pr.Send("warn", "No operations recorded in backup (nothing to revert)")
+			pr.Send(SeverityWarn, "No operations recorded in backup (nothing to revert)")
 		}
 		return fmt.Errorf("no operations to revert")
 	}
@@ -337,7 +338,7 @@ func RevertBackup(backupID string, progressCh chan<- ScanProgress) error {
 			revertErr = os.Rename(op.NewPath, op.OldPath)
 		case "delete":
 			if pr != nil {
-				pr.Send("warn", fmt.Sprintf("Cannot restore deleted file: %s", op.OldPath))
+				pr.Send(SeverityWarn, fmt.Sprintf("Cannot restore deleted file: %s", op.OldPath))
 			}
 			failed++
 			continue
@@ -346,7 +347,7 @@ func RevertBackup(backupID string, progressCh chan<- ScanProgress) error {
 		if revertErr != nil {
 			failed++
 			if pr != nil {
-				pr.Send("error", fmt.Sprintf("Failed to revert %s: %v", op.OldPath, revertErr))
+				pr.Send(SeverityError, fmt.Sprintf("Failed to revert %s: %v", op.OldPath, revertErr))
 			}
 		} else {
 			reverted++
@@ -0,0 +1,252 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
+)
+
+// StatsHistoryFile records library sizes over time, in the data directory
+const StatsHistoryFile = "stats_history.json"
+
+const (
+	// statsHistoryAge is how long library size samples are kept
+	statsHistoryAge = 365 * 24 * time.Hour
+	// statsSampleInterval is the least time between two kept samples of a
+	// library; a newer sample replaces one taken within it
+	statsSampleInterval = time.Hour
+	// growthWindow is how far back growth rates look
+	growthWindow = 90 * 24 * time.Hour
+	// growthMinSpan is the least history a growth rate is estimated from
+	growthMinSpan = 6 * 24 * time.Hour
+)
+
+// DefaultStatsHistoryPath returns where library size samples are kept
+func DefaultStatsHistoryPath() string {
+	return filepath.Join(oplog.DataDir(), StatsHistoryFile)
+}
+
+// LibrarySample is the size of one library at one time
+type LibrarySample struct {
+	Time      time.Time
+	Path      string
+	FileCount int
+	TotalSize int64
+}
+
+// statsHistory is the stats history file
+type statsHistory struct {
+	Samples []LibrarySample
+}
+
+// LoadStatsHistory reads the library size samples at path, oldest first.
+// A missing history is empty
+func LoadStatsHistory(path string) ([]LibrarySample, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stats history: %w", err)
+	}
+	var history statsHistory
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse stats history %s: %w", path, err)
+	}
+	return history.Samples, nil
+}
+
+// RecordLibraryStats adds a sample of each library in stats, taken at, to
+// the history at path and returns the history. Samples older than a year
+// are dropped, and a sample within an hour of the last one replaces it
+func RecordLibraryStats(path string, stats []LibraryStats, at time.Time) ([]LibrarySample, error) {
+	samples, err := LoadStatsHistory(path)
+	if err != nil {
+		return nil, err
+	}
+
+	replaced := make(map[string]bool, len(stats))
+	for _, s := range stats {
+		replaced[s.Path] = true
+	}
+	kept := samples[:0]
+	for _, sample := range samples {
+		if at.Sub(sample.Time) > statsHistoryAge {
+			continue
+		}
+		if replaced[sample.Path] && at.Sub(sample.Time) < statsSampleInterval {
+			continue
+		}
+		kept = append(kept, sample)
+	}
+	for _, s := range stats {
+		kept = append(kept, LibrarySample{Time: at, Path: s.Path, FileCount: s.FileCount, TotalSize: s.TotalSize})
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].Time.Before(kept[j].Time) })
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create stats history directory: %w", err)
+	}
+	data, err := json.MarshalIndent(statsHistory{Samples: kept}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal stats history: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write stats history: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("failed to replace stats history: %w", err)
+	}
+	return kept, nil
+}
+
+// LibraryGrowth returns how fast the library at path grew, in bytes per
+// day, fitted over the last 90 days of samples. ok is false until the
+// samples span at least six days
+func LibraryGrowth(samples []LibrarySample, path string, now time.Time) (perDay float64, span time.Duration, ok bool) {
+	var xs, ys []float64
+	var first, last time.Time
+	for _, sample := range samples {
+		if sample.Path != path || now.Sub(sample.Time) > growthWindow {
+			continue
+		}
+		if first.IsZero() || sample.Time.Before(first) {
+			first = sample.Time
+		}
+		if sample.Time.After(last) {
+			last = sample.Time
+		}
+		xs = append(xs, sample.Time.Sub(now).Hours()/24)
+		ys = append(ys, float64(sample.TotalSize))
+	}
+	span = last.Sub(first)
+	if len(xs) < 2 || span < growthMinSpan {
+		return 0, span, false
+	}
+
+	// Least-squares slope, so one clean or one big import does not swing it
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(len(xs))
+	meanY /= float64(len(ys))
+	var num, den float64
+	for i := range xs {
+		num += (xs[i] - meanX) * (ys[i] - meanY)
+		den += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+	if den == 0 {
+		return 0, span, false
+	}
+	return num / den, span, true
+}
+
+// CapacityProjection is when the filesystem holding some libraries runs
+// out of space at their combined growth rate
+type CapacityProjection struct {
+	Mount        string   // mount point, or the first library path without a mount table
+	Libraries    []string // configured libraries on the mount
+	Total        int64
+	Free         int64   // space available to jellysink's user
+	GrowthPerDay float64 // bytes per day, summed over libraries with enough history
+	Known        bool    // at least one library has enough history for a growth rate
+	FullOn       time.Time
+	Err          string `json:",omitempty"` // why free space could not be read
+}
+
+// DaysLeft returns the days until the mount is full, or -1 when it is not
+// growing or its growth is not known yet
+func (p CapacityProjection) DaysLeft() float64 {
+	if !p.Known || p.GrowthPerDay <= 0 || p.Err != "" {
+		return -1
+	}
+	return float64(p.Free) / p.GrowthPerDay
+}
+
+// String describes the projection in one line
+func (p CapacityProjection) String() string {
+	if p.Err != "" {
+		return fmt.Sprintf("%s: free space unavailable: %s", p.Mount, p.Err)
+	}
+	line := fmt.Sprintf("%s: %s free of %s", p.Mount, formatCapacity(p.Free), formatCapacity(p.Total))
+	switch days := p.DaysLeft(); {
+	case !p.Known:
+		line += ", growth not known yet (needs a week of samples)"
+	case days < 0:
+		line += ", not growing"
+	case p.FullOn.IsZero():
+		line += fmt.Sprintf(", growing %s/day, not full within a century", formatCapacity(int64(p.GrowthPerDay)))
+	default:
+		line += fmt.Sprintf(", growing %s/day, full in ~%.0f days (%s)",
+			formatCapacity(int64(p.GrowthPerDay)), days, p.FullOn.Format("2006-01-02"))
+	}
+	return line
+}
+
+// diskUsage returns the size of the filesystem holding path and the space
+// available on it (replaced in tests)
+var diskUsage = func(path string) (total, free int64, err error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, 0, err
+	}
+	return int64(st.Blocks) * int64(st.Bsize), int64(st.Bavail) * int64(st.Bsize), nil
+}
+
+// ProjectCapacity groups libraryPaths by the mount they are on and projects
+// when each mount fills up, from its free space now and the growth of its
+// libraries in samples
+func ProjectCapacity(libraryPaths []string, samples []LibrarySample, now time.Time) []CapacityProjection {
+	mounts := loadMounts()
+	var projections []CapacityProjection
+	index := make(map[string]int)
+	for _, path := range libraryPaths {
+		point := mountFor(mounts, path).Point
+		if point == "" {
+			point = path
+		}
+		i, ok := index[point]
+		if !ok {
+			i = len(projections)
+			index[point] = i
+			p := CapacityProjection{Mount: point}
+			total, free, err := diskUsage(path)
+			if err != nil {
+				p.Err = err.Error()
+			}
+			p.Total, p.Free = total, free
+			projections = append(projections, p)
+		}
+		p := &projections[i]
+		p.Libraries = append(p.Libraries, path)
+		if perDay, _, ok := LibraryGrowth(samples, path, now); ok {
+			p.GrowthPerDay += perDay
+			p.Known = true
+		}
+	}
+	for i := range projections {
+		// Past a century the date means nothing (and overflows a Duration)
+		if days := projections[i].DaysLeft(); days >= 0 && days < 36500 {
+			projections[i].FullOn = now.Add(time.Duration(days * float64(24*time.Hour)))
+		}
+	}
+	return projections
+}
+
+// formatCapacity renders a size in GB, or TB from 1 TB up
+func formatCapacity(bytes int64) string {
+	if bytes >= 1<<40 {
+		return fmt.Sprintf("%.2f TB", float64(bytes)/(1<<40))
+	}
+	return fmt.Sprintf("%.1f GB", float64(bytes)/(1<<30))
+}
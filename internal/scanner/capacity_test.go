@@ -0,0 +1,104 @@
+package scanner
+
+import (
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordLibraryStats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), StatsHistoryFile)
+	now := time.Date(2026, 6, 1, 3, 0, 0, 0, time.UTC)
+
+	stats := []LibraryStats{{Path: "/movies", FileCount: 10, TotalSize: 100}}
+	if _, err := RecordLibraryStats(path, stats, now.Add(-400*24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RecordLibraryStats(path, stats, now.Add(-24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RecordLibraryStats(path, stats, now.Add(-30*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	stats[0].TotalSize = 200
+	samples, err := RecordLibraryStats(path, stats, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The year-old sample is dropped and the half-hour-old one replaced
+	if len(samples) != 2 || !samples[0].Time.Equal(now.Add(-24*time.Hour)) || samples[1].TotalSize != 200 {
+		t.Errorf("Unexpected history: %+v", samples)
+	}
+	loaded, err := LoadStatsHistory(path)
+	if err != nil || len(loaded) != 2 {
+		t.Errorf("LoadStatsHistory() = %d samples, %v; want 2", len(loaded), err)
+	}
+	if missing, err := LoadStatsHistory(filepath.Join(t.TempDir(), "none.json")); err != nil || missing != nil {
+		t.Errorf("Expected an empty history for a missing file, got %v, %v", missing, err)
+	}
+}
+
+func TestLibraryGrowth(t *testing.T) {
+	now := time.Date(2026, 6, 1, 3, 0, 0, 0, time.UTC)
+	const gb = 1 << 30
+	var samples []LibrarySample
+	for day := 10; day >= 0; day-- {
+		samples = append(samples, LibrarySample{
+			Time:      now.Add(-time.Duration(day) * 24 * time.Hour),
+			Path:      "/movies",
+			TotalSize: int64(100-day) * gb,
+		})
+	}
+	samples = append(samples, LibrarySample{Time: now, Path: "/tv", TotalSize: gb})
+
+	perDay, span, ok := LibraryGrowth(samples, "/movies", now)
+	if !ok || math.Abs(perDay-gb) > 1 || span != 10*24*time.Hour {
+		t.Errorf("LibraryGrowth(/movies) = %v, %v, %v; want 1 GB/day over 10 days", perDay, span, ok)
+	}
+	if _, _, ok := LibraryGrowth(samples, "/tv", now); ok {
+		t.Error("Expected no growth rate from a single sample")
+	}
+	if _, _, ok := LibraryGrowth(samples[6:], "/movies", now); ok {
+		t.Error("Expected no growth rate from under six days of samples")
+	}
+}
+
+func TestProjectCapacity(t *testing.T) {
+	const gb = 1 << 30
+	orig := diskUsage
+	diskUsage = func(path string) (int64, int64, error) { return 1000 * gb, 20 * gb, nil }
+	t.Cleanup(func() { diskUsage = orig })
+
+	now := time.Date(2026, 6, 1, 3, 0, 0, 0, time.UTC)
+	var samples []LibrarySample
+	for day := 7; day >= 0; day-- {
+		samples = append(samples, LibrarySample{
+			Time:      now.Add(-time.Duration(day) * 24 * time.Hour),
+			Path:      "/nonexistent-jellysink/movies",
+			TotalSize: int64(100-day) * gb,
+		})
+	}
+
+	projections := ProjectCapacity([]string{"/nonexistent-jellysink/movies"}, samples, now)
+	if len(projections) != 1 {
+		t.Fatalf("Expected one projection, got %+v", projections)
+	}
+	p := projections[0]
+	if days := p.DaysLeft(); math.Abs(days-20) > 0.01 {
+		t.Errorf("DaysLeft() = %v, want 20", days)
+	}
+	if want := now.AddDate(0, 0, 20).Format("2006-01-02"); p.FullOn.Format("2006-01-02") != want {
+		t.Errorf("FullOn = %v, want %s", p.FullOn, want)
+	}
+	if line := p.String(); !strings.Contains(line, "20.0 GB free of 1000.0 GB, growing 1.0 GB/day, full in ~20 days") {
+		t.Errorf("Unexpected projection line %q", line)
+	}
+
+	unknown := ProjectCapacity([]string{"/nonexistent-jellysink/tv"}, samples, now)
+	if unknown[0].DaysLeft() != -1 || !strings.Contains(unknown[0].String(), "growth not known yet") {
+		t.Errorf("Expected unknown growth for a library without history, got %q", unknown[0].String())
+	}
+}
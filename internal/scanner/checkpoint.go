@@ -0,0 +1,160 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+)
+
+// ScanSection is a part of the scan whose findings stand on their own
+type ScanSection string
+
+const (
+	SectionMovieDuplicates ScanSection = "movie_duplicates"
+	SectionTVDuplicates    ScanSection = "tv_duplicates"
+	SectionMovieCompliance ScanSection = "movie_compliance"
+	SectionTVCompliance    ScanSection = "tv_compliance"
+	SectionOrphans         ScanSection = "orphans"
+	SectionArtifacts       ScanSection = "artifacts"
+	SectionQuality         ScanSection = "quality"
+	SectionCompleteness    ScanSection = "completeness"
+)
+
+// ScanSections lists the sections in the order a scan runs them
+var ScanSections = []ScanSection{
+	SectionMovieDuplicates,
+	SectionTVDuplicates,
+	SectionMovieCompliance,
+	SectionTVCompliance,
+	SectionOrphans,
+	SectionArtifacts,
+	SectionQuality,
+	SectionCompleteness,
+}
+
+// CancelledScanError is returned when a scan is cancelled after at least
+// one section finished. Result holds the findings of the Completed
+// sections only; errors.Is(err, context.Canceled) still holds
+type CancelledScanError struct {
+	Result    *ScanResult
+	Completed []ScanSection
+	Err       error
+}
+
+func (e *CancelledScanError) Error() string {
+	return fmt.Sprintf("scan cancelled after %d of %d sections: %v", len(e.Completed), len(ScanSections), e.Err)
+}
+
+func (e *CancelledScanError) Unwrap() error {
+	return e.Err
+}
+
+// RunResumedScan finishes a cancelled scan: the completed sections are
+// taken from previous and only the others are scanned
+func RunResumedScan(ctx context.Context, moviePaths, tvPaths []string, previous *ScanResult, completed []ScanSection, progressCh chan<- ScanProgress) (*ScanResult, error) {
+	resume := &resumedScan{previous: previous, completed: make(map[ScanSection]bool)}
+	for _, section := range completed {
+		resume.completed[section] = true
+	}
+	if progressCh != nil {
+		NewProgressReporter(progressCh, OpReportGeneration).SendSeverityImmediate(SeverityInfo,
+			fmt.Sprintf("Resuming a cancelled scan, %d of %d sections already done", len(resume.completed), len(ScanSections)))
+	}
+	return runScan(ctx, moviePaths, tvPaths, nil, resume, progressCh)
+}
+
+// resumedScan is the part of a cancelled scan a resumed one reuses
+type resumedScan struct {
+	previous  *ScanResult
+	completed map[ScanSection]bool
+}
+
+// done reports whether section was completed by the cancelled scan
+func (r *resumedScan) done(section ScanSection) bool {
+	return r != nil && r.completed[section]
+}
+
+// reuse copies section's findings from the cancelled scan into result
+func (r *resumedScan) reuse(section ScanSection, result *ScanResult) {
+	prev := r.previous
+	switch section {
+	case SectionMovieDuplicates:
+		result.MovieDuplicates = prev.MovieDuplicates
+	case SectionTVDuplicates:
+		result.TVDuplicates = prev.TVDuplicates
+	case SectionMovieCompliance, SectionTVCompliance:
+		for _, issue := range prev.ComplianceIssues {
+			if issueSection(issue) == section {
+				result.ComplianceIssues = append(result.ComplianceIssues, issue)
+			}
+		}
+		if section == SectionTVCompliance {
+			result.AmbiguousTVShows = prev.AmbiguousTVShows
+		} else {
+			result.AmbiguousMovies = prev.AmbiguousMovies
+		}
+	case SectionOrphans:
+		result.OrphanFolders = prev.OrphanFolders
+	case SectionArtifacts:
+		result.Artifacts = prev.Artifacts
+	case SectionQuality:
+		result.Quality = prev.Quality
+	case SectionCompleteness:
+		result.Completeness = prev.Completeness
+	}
+}
+
+// issueSection returns the compliance section that found issue
+func issueSection(issue ComplianceIssue) ScanSection {
+	if issue.Type == "tv" {
+		return SectionTVCompliance
+	}
+	return SectionMovieCompliance
+}
+
+// checkpoint ends a scan cancelled with err, keeping the findings of the
+// completed sections. Nothing is kept when no section finished
+func checkpoint(result *ScanResult, completed []ScanSection, err error) error {
+	if len(completed) == 0 {
+		return err
+	}
+	done := make(map[ScanSection]bool, len(completed))
+	for _, section := range completed {
+		done[section] = true
+	}
+
+	partial := &ScanResult{APIDiagnostics: APIDiagnostics(), PassTimings: result.PassTimings}
+	if done[SectionMovieDuplicates] {
+		partial.MovieDuplicates = result.MovieDuplicates
+	}
+	if done[SectionTVDuplicates] {
+		partial.TVDuplicates = result.TVDuplicates
+	}
+	for _, issue := range result.ComplianceIssues {
+		if done[issueSection(issue)] {
+			partial.ComplianceIssues = append(partial.ComplianceIssues, issue)
+		}
+	}
+	if done[SectionTVCompliance] {
+		partial.AmbiguousTVShows = result.AmbiguousTVShows
+	}
+	if done[SectionMovieCompliance] {
+		partial.AmbiguousMovies = result.AmbiguousMovies
+	}
+	if done[SectionOrphans] {
+		partial.OrphanFolders = result.OrphanFolders
+	}
+	if done[SectionArtifacts] {
+		partial.Artifacts = result.Artifacts
+	}
+	if done[SectionQuality] {
+		partial.Quality = result.Quality
+	}
+	if done[SectionCompleteness] {
+		partial.Completeness = result.Completeness
+	}
+
+	AssignIDs(partial)
+	SortResults(partial)
+	tallyResult(partial)
+	return &CancelledScanError{Result: partial, Completed: completed, Err: err}
+}
@@ -0,0 +1,105 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCancelledScanKeepsCompletedSections(t *testing.T) {
+	root := t.TempDir()
+	movies, tv := filepath.Join(root, "movies"), filepath.Join(root, "tv")
+	for rel, content := range map[string]string{
+		"movies/Heat (1995)/Heat (1995).mkv":                 "keeper",
+		"movies/Heat.1995.1080p-GRP/Heat.1995.1080p-GRP.mkv": "copy",
+		"tv/Lost (2004)/Season 01/Lost (2004) S01E01.mkv":    "pilot",
+		"tv/Lost (2004)/Season 01/Lost.2004.S01E01.720p.mkv": "pilot copy",
+		"tv/Lost (2004)/Season 01/Lost.2004.S01E02.HDTV.mkv": "second",
+		"tv/Firefly (2002)/tvshow.nfo":                       "nfo",
+	} {
+		path := filepath.Join(root, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fakeFFprobe(t, map[string]string{
+		"Heat (1995).mkv":           probeJSON("hevc", 1920, 1080, 9_000_000, 6, 10200),
+		"Heat.1995.1080p-GRP.mkv":   probeJSON("h264", 1920, 1080, 4_000_000, 2, 10200),
+		"Lost (2004) S01E01.mkv":    probeJSON("h264", 1280, 720, 3_000_000, 2, 2600),
+		"Lost.2004.S01E01.720p.mkv": probeJSON("h264", 1280, 720, 2_000_000, 2, 2600),
+	})
+	var probed []string
+	var onProbe func(path string)
+	inner := runFFprobe
+	runFFprobe = func(ctx context.Context, binary, path string) ([]byte, error) {
+		probed = append(probed, filepath.Base(path))
+		if onProbe != nil {
+			onProbe(path)
+		}
+		return inner(ctx, binary, path)
+	}
+	t.Cleanup(func() { runFFprobe = inner })
+
+	full, err := RunFullScan(context.Background(), []string{movies}, []string{tv}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(full.MovieDuplicates) != 1 || len(full.TVDuplicates) != 1 {
+		t.Fatalf("Expected a movie and an episode duplicate, got %d and %d", len(full.MovieDuplicates), len(full.TVDuplicates))
+	}
+
+	// Cancelling while the episodes are probed keeps only the movie duplicates
+	ctx, cancel := context.WithCancel(context.Background())
+	onProbe = func(path string) {
+		if strings.HasPrefix(filepath.Base(path), "Lost") {
+			cancel()
+		}
+	}
+	_, err = RunFullScan(ctx, []string{movies}, []string{tv}, nil)
+	onProbe = nil
+	var cancelled *CancelledScanError
+	if !errors.As(err, &cancelled) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected a cancelled scan checkpoint, got %v", err)
+	}
+	if len(cancelled.Completed) != 1 || cancelled.Completed[0] != SectionMovieDuplicates {
+		t.Fatalf("Expected only the movie duplicates completed, got %v", cancelled.Completed)
+	}
+	partial := cancelled.Result
+	if len(partial.MovieDuplicates) != 1 || len(partial.TVDuplicates) != 0 || len(partial.ComplianceIssues) != 0 || len(partial.OrphanFolders) != 0 {
+		t.Errorf("Expected the partial result limited to the completed section, got %+v", partial)
+	}
+	if partial.TotalDuplicates != 1 || partial.SpaceToFree == 0 {
+		t.Errorf("Expected the partial result tallied, got %d groups and %d bytes", partial.TotalDuplicates, partial.SpaceToFree)
+	}
+
+	// Resuming scans the rest without probing the movies again
+	probed = nil
+	resumed, err := RunResumedScan(context.Background(), []string{movies}, []string{tv}, partial, cancelled.Completed, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range probed {
+		if strings.HasPrefix(name, "Heat") {
+			t.Errorf("Expected the completed movie section reused, but %s was probed", name)
+		}
+	}
+	if len(resumed.MovieDuplicates) != len(full.MovieDuplicates) || len(resumed.TVDuplicates) != len(full.TVDuplicates) ||
+		len(resumed.ComplianceIssues) != len(full.ComplianceIssues) || len(resumed.OrphanFolders) != len(full.OrphanFolders) {
+		t.Errorf("Expected the resumed scan to match a full scan, got %+v", resumed)
+	}
+	if resumed.MovieDuplicates[0].ID != full.MovieDuplicates[0].ID {
+		t.Errorf("Expected the reused duplicate to keep its ID, got %s", resumed.MovieDuplicates[0].ID)
+	}
+
+	// Cancelling before any section finishes leaves nothing to keep
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := RunFullScan(ctx, []string{movies}, []string{tv}, nil); errors.As(err, &cancelled) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a plain cancellation, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// ErrAPIOffline is returned instead of calling a metadata API whose circuit
+// breaker has tripped; its text is what the report shows for skipped lookups
+var ErrAPIOffline = errors.New("skipped: offline")
+
+// DefaultAPIFailureThreshold is how many consecutive unreachable-API
+// failures trip a provider's circuit breaker
+const DefaultAPIFailureThreshold = 3
+
+// CircuitBreaker stops calls to an API after consecutive failures so an
+// unreachable provider costs a few requests per scan instead of a full
+// retry/backoff cycle for every ambiguous title
+type CircuitBreaker struct {
+	Name      string
+	mu        sync.Mutex
+	threshold int
+	failures  int
+	open      bool
+}
+
+// NewCircuitBreaker returns a closed breaker that trips after threshold
+// consecutive failures (values below 1 use DefaultAPIFailureThreshold)
+func NewCircuitBreaker(name string, threshold int) *CircuitBreaker {
+	b := &CircuitBreaker{Name: name}
+	b.SetThreshold(threshold)
+	return b
+}
+
+// SetThreshold changes the number of consecutive failures that trip the breaker
+func (b *CircuitBreaker) SetThreshold(threshold int) {
+	if threshold < 1 {
+		threshold = DefaultAPIFailureThreshold
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.threshold = threshold
+}
+
+// Allow reports whether a request may be attempted, returning ErrAPIOffline
+// (wrapped with the provider name) once the breaker has tripped
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.open {
+		return fmt.Errorf("%s %w", b.Name, ErrAPIOffline)
+	}
+	return nil
+}
+
+// Success records a response from the API and clears the failure count
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+}
+
+// Failure records an unreachable-API failure and reports whether it tripped the breaker
+func (b *CircuitBreaker) Failure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if !b.open && b.failures >= b.threshold {
+		b.open = true
+		return true
+	}
+	return false
+}
+
+// IsOpen reports whether the breaker has tripped
+func (b *CircuitBreaker) IsOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open
+}
+
+// Reset closes the breaker so the next scan tries the API again
+func (b *CircuitBreaker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.open = false
+}
+
+// Session-scoped breakers shared by every TVDB/OMDB/TMDB/AniList client
+var (
+	tvdbBreaker    = NewCircuitBreaker("TVDB", DefaultAPIFailureThreshold)
+	omdbBreaker    = NewCircuitBreaker("OMDB", DefaultAPIFailureThreshold)
+	tmdbBreaker    = NewCircuitBreaker("TMDB", DefaultAPIFailureThreshold)
+	aniListBreaker = NewCircuitBreaker("AniList", DefaultAPIFailureThreshold)
+)
+
+// SetAPIFailureThreshold sets how many consecutive failures trip each
+// provider's breaker (api.failure_threshold)
+func SetAPIFailureThreshold(threshold int) {
+	tvdbBreaker.SetThreshold(threshold)
+	omdbBreaker.SetThreshold(threshold)
+	tmdbBreaker.SetThreshold(threshold)
+	aniListBreaker.SetThreshold(threshold)
+}
+
+// ResetAPICircuit closes all provider breakers, forgets cached lookup
+// failures and clears API diagnostics, so an API (or title authority) that
+// was offline during the last scan is retried
+func ResetAPICircuit() {
+	tvdbBreaker.Reset()
+	omdbBreaker.Reset()
+	tmdbBreaker.Reset()
+	aniListBreaker.Reset()
+	globalAPICache.DropFailures()
+	apiStats.reset()
+	resetTitleAuthorities()
+}
+
+// isUnreachable reports whether err means the API could not be reached at
+// all (DNS, connection refused, timeout) rather than a rejected request
+func isUnreachable(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,141 @@
+package scanner
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerTrips(t *testing.T) {
+	b := NewCircuitBreaker("TVDB", 3)
+
+	b.Failure()
+	b.Failure()
+	b.Success() // a response resets the streak
+	b.Failure()
+	b.Failure()
+	if b.IsOpen() || b.Allow() != nil {
+		t.Fatal("Expected breaker closed before 3 consecutive failures")
+	}
+
+	if !b.Failure() {
+		t.Error("Expected third consecutive failure to trip the breaker")
+	}
+	err := b.Allow()
+	if !errors.Is(err, ErrAPIOffline) || err.Error() != "TVDB skipped: offline" {
+		t.Errorf("Expected offline error, got %v", err)
+	}
+	if b.Failure() {
+		t.Error("Expected an open breaker not to report tripping again")
+	}
+
+	b.Reset()
+	if b.IsOpen() || b.Allow() != nil {
+		t.Error("Expected Reset to close the breaker")
+	}
+
+	if NewCircuitBreaker("OMDB", 0).threshold != DefaultAPIFailureThreshold {
+		t.Error("Expected invalid threshold to fall back to the default")
+	}
+}
+
+func TestVerifySkipsOfflineAPI(t *testing.T) {
+	ClearAPICache()
+	ResetAPICircuit()
+	SetAPIFailureThreshold(1)
+	defer func() {
+		SetAPIFailureThreshold(DefaultAPIFailureThreshold)
+		ResetAPICircuit()
+		ClearAPICache()
+	}()
+
+	// A closed server refuses connections like an unreachable API
+	server := httptest.NewServer(http.NotFoundHandler())
+	origURL := OMDBBaseURL
+	OMDBBaseURL = server.URL
+	server.Close()
+	defer func() { OMDBBaseURL = origURL }()
+
+	newResolution := func() *TVTitleResolution {
+		return &TVTitleResolution{
+			ResolvedTitle: "Degrassi",
+			FolderMatch:   &TVTitleMatch{Title: "Degrassi"},
+			FilenameMatch: &TVTitleMatch{Title: "Degrassi the Next Generation"},
+			IsAmbiguous:   true,
+		}
+	}
+
+	res := newResolution()
+	err := VerifyTVShowTitle(res, "", "test-key", "")
+	if !errors.Is(err, ErrAPIOffline) {
+		t.Fatalf("Expected offline error, got %v", err)
+	}
+	if res.APIStatus != "skipped: offline" || res.APIVerified || !res.IsAmbiguous {
+		t.Errorf("Expected unverified ambiguous title marked offline, got %+v", res)
+	}
+
+	// Later titles skip straight away instead of retrying with backoff
+	start := time.Now()
+	res = newResolution()
+	if err := VerifyTVShowTitle(res, "", "test-key", ""); !errors.Is(err, ErrAPIOffline) {
+		t.Errorf("Expected offline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Expected offline skip without network calls, took %v", elapsed)
+	}
+
+	// The next scan tries the API again
+	ResetAPICircuit()
+	if omdbBreaker.IsOpen() {
+		t.Error("Expected ResetAPICircuit to close the OMDB breaker")
+	}
+}
+
+func TestAPIServerErrorsTripBreaker(t *testing.T) {
+	ClearAPICache()
+	ResetAPICircuit()
+	SetAPIFailureThreshold(1)
+	defer func() {
+		SetAPIFailureThreshold(DefaultAPIFailureThreshold)
+		ResetAPICircuit()
+		ClearAPICache()
+	}()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewOMDBClient("test-key")
+	client.BaseURL = server.URL
+	if _, err := client.SearchSeriesWithRetry("Firefly", 3); !errors.Is(err, ErrAPIOffline) {
+		t.Errorf("Expected offline error after a 5xx, got %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected a single request before the breaker tripped, got %d", got)
+	}
+	if _, ok := globalAPICache.Get("omdb:Firefly"); ok {
+		t.Error("Expected offline failures not to be cached")
+	}
+}
+
+func TestAPICacheDropFailures(t *testing.T) {
+	ClearAPICache()
+	defer ClearAPICache()
+
+	globalAPICache.Set("tvdb:Firefly", &APICacheEntry{Title: "Firefly", Verified: true})
+	globalAPICache.Set("tvdb:Fireflyy", &APICacheEntry{Verified: false, Reason: "not found"})
+	globalAPICache.DropFailures()
+
+	if _, ok := globalAPICache.Get("tvdb:Firefly"); !ok {
+		t.Error("Expected verified entry kept")
+	}
+	if _, ok := globalAPICache.Get("tvdb:Fireflyy"); ok {
+		t.Error("Expected failed entry dropped")
+	}
+}
@@ -0,0 +1,118 @@
+package scanner
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Cloud-backed FUSE mounts (rclone, s3fs, gcsfuse and the like) turn every
+// folder listing into API calls and every read into a download. Library
+// paths on them get a cloud-safe profile (performance.cloud_safe): ffprobe
+// never reads their files, folder listings are paced to
+// performance.cloud_list_rate per second, and cleans batch their renames
+// and warn before moving file contents the remote may upload again
+
+// DefaultCloudListRate is how many folder listings per second scans make
+// on a cloud mount unless performance.cloud_list_rate says otherwise
+const DefaultCloudListRate = 4
+
+// cloudFSTypes are the FUSE filesystems that front a cloud storage API
+var cloudFSTypes = map[string]bool{
+	"fuse.rclone": true, "fuse.s3fs": true, "fuse.gcsfuse": true, "fuse.goofys": true,
+	"fuse.geesefs": true, "fuse.mountpoint-s3": true, "fuse.blobfuse": true, "fuse.blobfuse2": true,
+	"fuse.google-drive-ocamlfuse": true, "fuse.onedriver": true,
+}
+
+var (
+	cloudSafe     = true
+	cloudListRate = DefaultCloudListRate
+	cloudMounts   []mountInfo // the mount table, read on first use
+	cloudLoaded   bool
+	cloudNext     time.Time // when the next paced listing may start
+	cloudMu       sync.Mutex
+
+	// cloudSleep waits out a paced listing; tests replace it
+	cloudSleep = time.Sleep
+)
+
+// SetCloudSafe turns the cloud-safe profile on or off and sets the folder
+// listings per second it allows on a cloud mount (0 keeps the default).
+// The mount table is read again on next use
+func SetCloudSafe(enabled bool, listRate int) {
+	cloudMu.Lock()
+	defer cloudMu.Unlock()
+	if listRate <= 0 {
+		listRate = DefaultCloudListRate
+	}
+	cloudSafe, cloudListRate = enabled, listRate
+	cloudMounts, cloudLoaded = nil, false
+}
+
+// CloudSafe reports whether the cloud-safe profile is on
+func CloudSafe() bool {
+	cloudMu.Lock()
+	defer cloudMu.Unlock()
+	return cloudSafe
+}
+
+// cloudMount returns the mount path is on when that is a cloud mount and
+// the cloud-safe profile is on
+func cloudMount(path string) (mountInfo, bool) {
+	cloudMu.Lock()
+	defer cloudMu.Unlock()
+	if !cloudSafe {
+		return mountInfo{}, false
+	}
+	if !cloudLoaded {
+		cloudMounts, cloudLoaded = loadMounts(), true
+	}
+	m := mountFor(cloudMounts, path)
+	return m, cloudFSTypes[m.FSType]
+}
+
+// CloudMount returns the mount point and filesystem type of the cloud
+// mount path is on; ok is false when it is on none or the profile is off
+func CloudMount(path string) (point, fsType string, ok bool) {
+	m, ok := cloudMount(path)
+	return m.Point, m.FSType, ok
+}
+
+// paceListing waits until dir may be listed when it is on a cloud mount,
+// so a walk lists at most cloud_list_rate folders per second across all
+// scans in this process
+func paceListing(dir string) {
+	if _, ok := cloudMount(dir); !ok {
+		return
+	}
+	cloudMu.Lock()
+	now := time.Now()
+	if cloudNext.Before(now) {
+		cloudNext = now
+	}
+	wait := cloudNext.Sub(now)
+	cloudNext = cloudNext.Add(time.Second / time.Duration(cloudListRate))
+	cloudMu.Unlock()
+
+	if wait > 0 {
+		cloudSleep(wait)
+	}
+}
+
+// DescribeCloudPaths returns a line for each of paths on a cloud mount,
+// naming what the cloud-safe profile changes there
+func DescribeCloudPaths(paths []string) []string {
+	var lines []string
+	for _, path := range paths {
+		m, ok := cloudMount(path)
+		if !ok {
+			continue
+		}
+		cloudMu.Lock()
+		rate := cloudListRate
+		cloudMu.Unlock()
+		lines = append(lines, fmt.Sprintf("%s is on a cloud mount (%s at %s): cloud-safe profile on, no ffprobe reads, at most %d folder listings/s",
+			path, m.FSType, m.Point, rate))
+	}
+	return lines
+}
@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeCloudMounts points the mount table at one with an rclone mount on
+// /mnt/gdrive for the rest of the test
+func fakeCloudMounts(t *testing.T) {
+	t.Helper()
+	table := filepath.Join(t.TempDir(), "mountinfo")
+	os.WriteFile(table, []byte(`22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+50 22 0:60 / /mnt/gdrive rw,nosuid,nodev,relatime shared:20 - fuse.rclone gdrive: rw,user_id=0,group_id=0
+`), 0644)
+	orig := mountInfoPath
+	mountInfoPath = table
+	SetCloudSafe(true, 0)
+	t.Cleanup(func() {
+		mountInfoPath = orig
+		SetCloudSafe(true, 0)
+	})
+}
+
+func TestCloudMount(t *testing.T) {
+	fakeCloudMounts(t)
+
+	point, fsType, ok := CloudMount("/mnt/gdrive/Movies/Alien (1979)/Alien (1979).mkv")
+	if !ok || point != "/mnt/gdrive" || fsType != "fuse.rclone" {
+		t.Errorf("CloudMount() = %s, %s, %v, want /mnt/gdrive, fuse.rclone, true", point, fsType, ok)
+	}
+	if _, _, ok := CloudMount("/srv/media/Movies"); ok {
+		t.Error("Expected a local path not to be on a cloud mount")
+	}
+	if class := classifyMount(mountInfo{Point: "/mnt/gdrive", FSType: "fuse.rclone"}); class != storageCloud {
+		t.Errorf("classifyMount(fuse.rclone) = %s, want %s", class, storageCloud)
+	}
+
+	lines := DescribeCloudPaths([]string{"/srv/media/Movies", "/mnt/gdrive/TV"})
+	if len(lines) != 1 || !strings.Contains(lines[0], "/mnt/gdrive/TV is on a cloud mount (fuse.rclone") {
+		t.Errorf("DescribeCloudPaths() = %q", lines)
+	}
+
+	// With the profile off cloud mounts are treated like any other
+	SetCloudSafe(false, 0)
+	if _, _, ok := CloudMount("/mnt/gdrive/TV"); ok || CloudSafe() {
+		t.Error("Expected no cloud mounts with the cloud-safe profile off")
+	}
+	if lines := DescribeCloudPaths([]string{"/mnt/gdrive/TV"}); len(lines) != 0 {
+		t.Errorf("DescribeCloudPaths() = %q, want nothing", lines)
+	}
+}
+
+func TestPaceListing(t *testing.T) {
+	fakeCloudMounts(t)
+	SetCloudSafe(true, 2)
+
+	var waited time.Duration
+	orig := cloudSleep
+	cloudSleep = func(d time.Duration) { waited += d }
+	t.Cleanup(func() { cloudSleep = orig })
+	cloudMu.Lock()
+	cloudNext = time.Time{}
+	cloudMu.Unlock()
+
+	// Local folders are listed as fast as they come
+	for i := 0; i < 5; i++ {
+		paceListing("/srv/media/Movies")
+	}
+	if waited != 0 {
+		t.Fatalf("Expected no pacing off cloud mounts, waited %v", waited)
+	}
+
+	// 2 listings per second: five in a row wait 0, 0.5, 1, 1.5 and 2 seconds,
+	// as the fake sleep never lets time pass
+	for i := 0; i < 5; i++ {
+		paceListing("/mnt/gdrive/Movies")
+	}
+	if waited < 4500*time.Millisecond || waited > 5*time.Second {
+		t.Errorf("Expected about 5s of pacing over five listings at 2/s, waited %v", waited)
+	}
+}
@@ -0,0 +1,154 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SeasonCompleteness compares the episodes of a season on disk with the
+// ones TVDB lists as aired
+type SeasonCompleteness struct {
+	Season  int
+	Have    int   // aired episodes found on disk
+	Aired   int   // episodes TVDB lists as aired by the time of the scan
+	Missing []int `json:",omitempty"` // aired episode numbers not on disk
+}
+
+// ShowCompleteness is how many of a series' aired episodes its show folder
+// holds. Specials (season 0) are not counted
+type ShowCompleteness struct {
+	Path    string // show folder
+	Seasons []SeasonCompleteness
+	Have    int
+	Aired   int
+}
+
+// Percent returns the share of aired episodes on disk, 0-100
+func (c ShowCompleteness) Percent() float64 {
+	if c.Aired == 0 {
+		return 100
+	}
+	return float64(c.Have) * 100 / float64(c.Aired)
+}
+
+// Complete reports whether every aired episode is on disk
+func (c ShowCompleteness) Complete() bool {
+	return c.Have >= c.Aired
+}
+
+// completenessEnabled reports whether scans can compare shows with TVDB
+func completenessEnabled() bool {
+	tvdbKey, _, _ := apiKeys()
+	return tvdbKey != ""
+}
+
+// scanCompletenessInventory compares the episodes in each show folder below
+// the TV libraries with the episodes TVDB lists as aired by now. Shows TVDB
+// has no matching series for, and anime libraries, whose numbering TVDB's
+// default order often doesn't follow, are left out
+func scanCompletenessInventory(inv *inventory, tvPaths []string, now time.Time, progressCh chan<- ScanProgress) []ShowCompleteness {
+	if !completenessEnabled() {
+		return nil
+	}
+
+	var shows []string
+	onDisk := make(map[string]map[episodeKey]bool)
+	for _, libPath := range tvPaths {
+		lib := inv.library(libPath)
+		if lib.err != nil || isAnimeLibrary(libPath) {
+			continue
+		}
+		for _, file := range lib.files {
+			if !isVideoFile(file.path) || isSampleFile(file.path) || isExtraFile(file.path, file.info.Size()) {
+				continue
+			}
+			rel, err := filepath.Rel(libPath, file.path)
+			if err != nil || !strings.Contains(rel, string(filepath.Separator)) {
+				continue
+			}
+			season, episode, found := ExtractEpisodeInfo(filepath.Base(file.path))
+			if !found {
+				continue
+			}
+			show := filepath.Join(libPath, strings.SplitN(rel, string(filepath.Separator), 2)[0])
+			if onDisk[show] == nil {
+				shows = append(shows, show)
+				onDisk[show] = make(map[episodeKey]bool)
+			}
+			onDisk[show][episodeKey{season, episode}] = true
+		}
+	}
+	if len(shows) == 0 {
+		return nil
+	}
+
+	var pr *ProgressReporter
+	if progressCh != nil {
+		pr = NewProgressReporterWithInterval(progressCh, OpComplianceTV, 200*time.Millisecond)
+		pr.Start(len(shows), fmt.Sprintf("Comparing %d shows with TVDB episode lists...", len(shows)))
+	}
+
+	today := now.Format("2006-01-02")
+	var results []ShowCompleteness
+	for i, show := range shows {
+		if pr != nil {
+			pr.Update(i+1, fmt.Sprintf("Checking: %s", filepath.Base(show)))
+		}
+		episodes, err := showEpisodes(filepath.Base(show))
+		if errors.Is(err, ErrAPIOffline) {
+			if pr != nil {
+				pr.SendSeverityImmediate(SeverityWarn, "TVDB is unreachable: series completeness left out of this scan")
+			}
+			return nil
+		}
+		if completeness, ok := showCompleteness(show, episodes, onDisk[show], today); ok {
+			results = append(results, completeness)
+		}
+	}
+
+	if pr != nil {
+		pr.Complete(fmt.Sprintf("Compared %d shows with TVDB", len(results)))
+	}
+	return results
+}
+
+// showCompleteness counts the aired episodes of a series found on disk, by
+// season. ok is false when TVDB lists no aired episodes for it
+func showCompleteness(show string, episodes []TVDBEpisode, onDisk map[episodeKey]bool, today string) (ShowCompleteness, bool) {
+	bySeason := make(map[int]*SeasonCompleteness)
+	for _, ep := range episodes {
+		if ep.SeasonNumber <= 0 || ep.Number <= 0 || ep.Aired == "" || ep.Aired > today {
+			continue
+		}
+		season := bySeason[ep.SeasonNumber]
+		if season == nil {
+			season = &SeasonCompleteness{Season: ep.SeasonNumber}
+			bySeason[ep.SeasonNumber] = season
+		}
+		season.Aired++
+		if onDisk[episodeKey{ep.SeasonNumber, ep.Number}] {
+			season.Have++
+		} else {
+			season.Missing = append(season.Missing, ep.Number)
+		}
+	}
+	if len(bySeason) == 0 {
+		return ShowCompleteness{}, false
+	}
+
+	result := ShowCompleteness{Path: show}
+	for _, season := range bySeason {
+		sort.Ints(season.Missing)
+		result.Seasons = append(result.Seasons, *season)
+		result.Have += season.Have
+		result.Aired += season.Aired
+	}
+	sort.Slice(result.Seasons, func(i, j int) bool {
+		return result.Seasons[i].Season < result.Seasons[j].Season
+	})
+	return result, true
+}
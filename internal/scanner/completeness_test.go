@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSeriesCompleteness(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"success","data":{"token":"test-token"}}`)
+	})
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("query") != "Lost" {
+			fmt.Fprint(w, `{"status":"success","data":[]}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"success","data":[{"id":"series-73739","tvdb_id":"73739","name":"Lost","year":"2004"}]}`)
+	})
+	mux.HandleFunc("/series/73739/episodes/default/eng", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"success","data":{"episodes":[
+			{"id":1,"name":"Special","seasonNumber":0,"number":1,"aired":"2004-09-01"},
+			{"id":2,"name":"Pilot (1)","seasonNumber":1,"number":1,"aired":"2004-09-22"},
+			{"id":3,"name":"Pilot (2)","seasonNumber":1,"number":2,"aired":"2004-09-29"},
+			{"id":4,"name":"Tabula Rasa","seasonNumber":1,"number":3,"aired":"2004-10-06"},
+			{"id":5,"name":"Walkabout","seasonNumber":1,"number":4,"aired":"2004-10-13"},
+			{"id":6,"name":"Man of Science","seasonNumber":2,"number":1,"aired":"2005-09-21"},
+			{"id":7,"name":"Adrift","seasonNumber":2,"number":2,"aired":"2005-09-28"},
+			{"id":8,"name":"Unaired","seasonNumber":2,"number":3,"aired":""}]},
+			"links":{"next":null}}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	origURL, origInterval := TVDBBaseURL, tvdbEpisodeLimiter.interval
+	TVDBBaseURL, tvdbEpisodeLimiter.interval = server.URL, time.Millisecond
+	ClearAPICache()
+	ResetAPICircuit()
+	defer func() {
+		TVDBBaseURL, tvdbEpisodeLimiter.interval = origURL, origInterval
+		SetAPIKeys("", "", "")
+		ClearAPICache()
+	}()
+
+	root := t.TempDir()
+	for _, rel := range []string{
+		"Lost (2004)/Season 01/Lost (2004) S01E01.mkv",
+		"Lost (2004)/Season 01/Lost (2004) S01E02.mkv",
+		"Lost (2004)/Season 01/Lost (2004) S01E04.mkv",
+		"Lost (2004)/Season 01/Lost.S01E04.Behind.The.Scenes.mkv",
+		"Lost (2004)/Season 02/Lost (2004) S02E01.mkv",
+		"Lost (2004)/Season 02/Lost (2004) S02E02.mkv",
+		"Unknown Show (2020)/Season 01/Unknown Show (2020) S01E01.mkv",
+	} {
+		path := filepath.Join(root, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	inv, err := takeInventory(context.Background(), []string{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2005, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	// Without a TVDB key nothing is compared
+	if got := scanCompletenessInventory(inv, []string{root}, now, nil); got != nil {
+		t.Fatalf("Expected no completeness without a TVDB key, got %+v", got)
+	}
+
+	SetAPIKeys("test-key", "", "")
+	got := scanCompletenessInventory(inv, []string{root}, now, nil)
+	want := []ShowCompleteness{{
+		Path: filepath.Join(root, "Lost (2004)"),
+		Seasons: []SeasonCompleteness{
+			{Season: 1, Have: 3, Aired: 4, Missing: []int{3}},
+			{Season: 2, Have: 2, Aired: 2},
+		},
+		Have:  5,
+		Aired: 6,
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("scanCompletenessInventory() = %+v, want %+v", got, want)
+	}
+	if percent := got[0].Percent(); percent < 83 || percent > 84 || got[0].Complete() {
+		t.Errorf("Expected Lost 83%% complete, got %.1f%%", percent)
+	}
+}
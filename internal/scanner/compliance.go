@@ -1,7 +1,9 @@
 package scanner
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -12,19 +14,51 @@ import (
 
 // ComplianceIssue represents a naming compliance problem
 type ComplianceIssue struct {
-	Path            string // Current path
-	Type            string // "movie" or "tv"
-	Problem         string // Description of the issue
-	SuggestedPath   string // Suggested compliant path
-	SuggestedAction string // "rename" or "reorganize"
+	ID              string  // Stable issue ID (hash of type and current path)
+	Path            string  // Current path
+	Type            string  // "movie" or "tv"
+	Problem         string  // Description of the issue
+	Severity        string  // "info", "warn" or "error"
+	Rule            string  // Rule that produced the issue (see Rule* constants)
+	SuggestedPath   string  // Suggested compliant path
+	SuggestedAction string  // "rename" or "reorganize"
+	Confidence      float64 `json:",omitempty"` // confidence in the suggested show title (TV); 0 = not recorded
 }
 
+// Compliance rule identifiers recorded on each issue so findings can be explained
+const (
+	RuleMovieReleaseGroupFolder = "movie.release_group_folder"
+	RuleMovieInLibraryRoot      = "movie.library_root"
+	RuleMovieFolderMismatch     = "movie.folder_filename_mismatch"
+	RuleMovieYearFormat         = "movie.year_format"
+	RuleMovieTargetCollision    = "movie.target_collision"
+	RuleMovieTrailerName        = "movie.trailer_name"
+	RuleMovieExtraFolder        = "movie.extra_folder"
+	RuleTVSeasonFolder          = "tv.season_folder"
+	RuleTVReleaseGroupFilename  = "tv.release_group_filename"
+	RuleTVTitleMismatch         = "tv.title_mismatch"
+	RuleTVExtraFolder           = "tv.extra_folder"
+	RuleTVSpecialEpisode        = "tv.special_episode"
+	RuleTVDateEpisode           = "tv.date_episode"
+	RuleTVShowFolderYear        = "tv.show_folder_year"
+	RuleAnimeEpisodeFolder      = "anime.episode_folder"
+	RuleAnimeReleaseTags        = "anime.release_tags"
+	RuleOrphanedSidecar         = "sidecar.orphaned"
+)
+
 // TVComplianceResult holds both compliance issues and ambiguous shows
 type TVComplianceResult struct {
 	Issues           []ComplianceIssue
 	AmbiguousTVShows []*TVTitleResolution
 }
 
+// MovieComplianceResult holds movie compliance issues and the movies whose
+// suggested titles TMDB or OMDB did not confirm
+type MovieComplianceResult struct {
+	Issues          []ComplianceIssue
+	AmbiguousMovies []*MovieTitleResolution
+}
+
 // ScanMovieCompliance scans for non-Jellyfin-compliant movie folders
 // Expected format: Movie Name (Year)/Movie Name (Year).ext
 // excludePaths: list of file paths to skip (e.g., files marked for deletion in duplicate scan)
@@ -34,20 +68,32 @@ func ScanMovieCompliance(paths []string, excludePaths ...string) ([]ComplianceIs
 
 // ScanMovieComplianceWithProgress scans for compliance issues with progress reporting
 func ScanMovieComplianceWithProgress(paths []string, progressCh chan<- ScanProgress, excludePaths ...string) ([]ComplianceIssue, error) {
+	inv, err := takeInventory(context.Background(), paths, nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := scanMovieComplianceInventory(inv, paths, progressCh, excludePaths...)
+	if err != nil {
+		return nil, err
+	}
+	return result.Issues, nil
+}
+
+// scanMovieComplianceInventory checks the movie files an inventory holds
+// below paths against the naming conventions
+func scanMovieComplianceInventory(inv *inventory, paths []string, progressCh chan<- ScanProgress, excludePaths ...string) (*MovieComplianceResult, error) {
 	var pr *ProgressReporter
 	if progressCh != nil {
-		pr = NewProgressReporterWithInterval(progressCh, "compliance_movies", 200*time.Millisecond)
-		pr.send(0, "Counting movie files for compliance check...")
-
-		total, err := CountVideoFilesWithProgress(paths, pr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to count files: %w", err)
-		}
+		pr = NewProgressReporterWithInterval(progressCh, OpComplianceMovies, 200*time.Millisecond)
+		total := inv.videos(paths)
 		pr.Start(total, fmt.Sprintf("Checking %d movie files for compliance...", total))
 	}
 
 	var issues []ComplianceIssue
+	var ambiguous []*MovieTitleResolution
 	targetPaths := make(map[string]string) // suggestedPath -> originalPath
+	allowVersions := GetAllowVersions()
+	videosIn := make(folderVideoCache)
 	filesProcessed := 0
 
 	// Build exclusion set for fast lookup
@@ -59,23 +105,25 @@ func ScanMovieComplianceWithProgress(paths []string, progressCh chan<- ScanProgr
 	}
 
 	for _, libPath := range paths {
-		if _, err := os.Stat(libPath); err != nil {
-			return nil, fmt.Errorf("library path not accessible: %s: %w", libPath, err)
+		lib := inv.library(libPath)
+		if lib.err != nil {
+			return nil, fmt.Errorf("library path not accessible: %s: %w", libPath, lib.err)
 		}
 
-		err := filepath.Walk(libPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
+		for _, file := range lib.files {
+			path, info := file.path, file.info
 
-			// Skip the library root itself
-			if path == libPath {
-				return nil
+			// Subtitles, nfo and artwork must belong to a video in their folder
+			if isSidecarFile(path) {
+				if issue := checkOrphanedSidecar(path, "movie", videosIn.videos(filepath.Dir(path))); issue != nil {
+					issues = append(issues, *issue)
+				}
+				continue
 			}
 
 			// Only check video files
-			if info.IsDir() || !isVideoFile(path) {
-				return nil
+			if !isVideoFile(path) {
+				continue
 			}
 
 			filesProcessed++
@@ -85,22 +133,55 @@ func ScanMovieComplianceWithProgress(paths []string, progressCh chan<- ScanProgr
 
 			// Skip files marked for deletion in duplicate scan
 			if excludeSet[path] {
-				return nil
+				continue
 			}
 
-			// Skip sample files - they should be deleted, not renamed
-			if isSampleFile(path) {
-				return nil
+			// Trailers and other extras are never renamed as the movie;
+			// only their name or folder can need fixing
+			var issue *ComplianceIssue
+			if isTrailerFile(path, info.Size()) {
+				issue = checkStrayTrailer(path, libPath)
+			} else if isExtraFile(path, info.Size()) {
+				issue = checkStrayExtra(path, libPath, "movie")
+			} else if isSampleFile(path) {
+				// Skip sample files - they should be deleted, not renamed
+				continue
+			} else if issue = checkMovieCompliance(path, libPath); issue != nil {
+				// The movie manager's (Radarr's) name wins over the cleaned
+				// filename; otherwise TMDB or OMDB can confirm the title. An
+				// unconfirmed title is held back from automatic cleans
+				if !preferManagedMovieName(issue, libPath, pr) {
+					if resolution := verifyMovieName(issue, pr); resolution != nil {
+						issue.Problem += fmt.Sprintf(" (title not confirmed by %s)", resolution.Provider)
+						issue.Confidence = unconfirmedMovieConfidence
+						ambiguous = append(ambiguous, resolution)
+					}
+				}
+				// With allow_versions, an edition keeps its label in the new name
+				if allowVersions && issue.SuggestedAction == "reorganize" {
+					issue.SuggestedPath = versionName(issue.SuggestedPath, path)
+				}
 			}
 
-			// Check if this is compliant
-			issue := checkMovieCompliance(path, libPath)
 			if issue != nil {
+				// Another version of the movie already wants this target:
+				// with allow_versions this copy is named as a version of it
+				if _, exists := targetPaths[issue.SuggestedPath]; exists && allowVersions && issue.Type == "movie" {
+					if label := versionKey(MovieFile{Path: path, Resolution: ExtractResolution(path)}); label != "" {
+						if target := withVersionLabel(issue.SuggestedPath, sanitizeEpisodeTitle(label)); targetPaths[target] == "" {
+							issue.SuggestedPath = target
+							issue.Problem += " (kept as a version)"
+						}
+					}
+				}
+
 				// Check for collision: another file already wants this target path
 				if existingSource, exists := targetPaths[issue.SuggestedPath]; exists {
 					// Collision detected! Skip this one and add warning to existing issue
 					issue.Problem = fmt.Sprintf("COLLISION: Multiple files want same target (also: %s)", filepath.Base(existingSource))
 					issue.SuggestedAction = "manual_review"
+					issue.Severity = IssueSeverityError
+					issue.Rule = RuleMovieTargetCollision
 				} else {
 					// No collision, track this target
 					targetPaths[issue.SuggestedPath] = path
@@ -109,19 +190,16 @@ func ScanMovieComplianceWithProgress(paths []string, progressCh chan<- ScanProgr
 				issues = append(issues, *issue)
 			}
 
-			return nil
-		})
-
-		if err != nil {
-			return nil, fmt.Errorf("error scanning %s: %w", libPath, err)
 		}
 	}
 
+	retargetOrphanedSidecars(issues)
+
 	if pr != nil {
-		pr.Complete(fmt.Sprintf("Found %d compliance issues", len(issues)))
+		pr.Complete(fmt.Sprintf("Found %d compliance issues, %d movie titles to review", len(issues), len(ambiguous)))
 	}
 
-	return issues, nil
+	return &MovieComplianceResult{Issues: issues, AmbiguousMovies: ambiguous}, nil
 }
 
 // checkMovieCompliance checks if a movie file follows Jellyfin conventions
@@ -141,6 +219,8 @@ func checkMovieCompliance(filePath, libRoot string) *ComplianceIssue {
 			Path:            filePath,
 			Type:            "movie",
 			Problem:         "Release group folder naming (contains resolution/codec/source markers)",
+			Severity:        IssueSeverityWarn,
+			Rule:            RuleMovieReleaseGroupFolder,
 			SuggestedPath:   suggestedPath,
 			SuggestedAction: "reorganize",
 		}
@@ -158,6 +238,8 @@ func checkMovieCompliance(filePath, libRoot string) *ComplianceIssue {
 			Path:            filePath,
 			Type:            "movie",
 			Problem:         "Movie file directly in library root (should be in subfolder)",
+			Severity:        IssueSeverityError,
+			Rule:            RuleMovieInLibraryRoot,
 			SuggestedPath:   suggestedPath,
 			SuggestedAction: "reorganize",
 		}
@@ -165,7 +247,8 @@ func checkMovieCompliance(filePath, libRoot string) *ComplianceIssue {
 
 	// Check if parent directory name matches filename (minus extension)
 	filenameNoExt := strings.TrimSuffix(filename, filepath.Ext(filename))
-	if parentDir != filenameNoExt {
+	_, isVersion := versionLabel(parentDir, filePath)
+	if parentDir != filenameNoExt && !isVersion {
 		// If parent dir is clean (has year in parentheses) prefer it as the source of truth
 		if hasYearInParentheses(parentDir) {
 			// Use parent dir as source of truth
@@ -173,12 +256,16 @@ func checkMovieCompliance(filePath, libRoot string) *ComplianceIssue {
 			suggestedDir := filepath.Join(filepath.Dir(filepath.Dir(filePath)), cleanName)
 			suggestedPath := filepath.Join(suggestedDir, cleanName+filepath.Ext(filePath))
 
-			// Only suggest a change if filename does not already match that parent dir's cleaned name
-			if CleanMovieName(filenameNoExt) != cleanName {
+			// Only suggest a change if filename does not already match that
+			// parent dir's cleaned name, or with allow_versions, when it
+			// names an edition Jellyfin won't show as a version
+			if CleanMovieName(filenameNoExt) != cleanName || (GetAllowVersions() && fileEdition(filePath) != "") {
 				return &ComplianceIssue{
 					Path:            filePath,
 					Type:            "movie",
 					Problem:         "Folder name doesn't match filename",
+					Severity:        IssueSeverityWarn,
+					Rule:            RuleMovieFolderMismatch,
 					SuggestedPath:   suggestedPath,
 					SuggestedAction: "reorganize",
 				}
@@ -198,6 +285,8 @@ func checkMovieCompliance(filePath, libRoot string) *ComplianceIssue {
 				Path:            filePath,
 				Type:            "movie",
 				Problem:         "Folder name doesn't match filename",
+				Severity:        IssueSeverityWarn,
+				Rule:            RuleMovieFolderMismatch,
 				SuggestedPath:   suggestedPath,
 				SuggestedAction: "reorganize",
 			}
@@ -218,6 +307,8 @@ func checkMovieCompliance(filePath, libRoot string) *ComplianceIssue {
 			Path:            filePath,
 			Type:            "movie",
 			Problem:         "Year not in parentheses format",
+			Severity:        IssueSeverityInfo,
+			Rule:            RuleMovieYearFormat,
 			SuggestedPath:   suggestedPath,
 			SuggestedAction: "reorganize",
 		}
@@ -244,22 +335,31 @@ func ScanTVComplianceWithProgress(paths []string, progressCh chan<- ScanProgress
 
 // ScanTVComplianceWithAmbiguous scans for TV compliance issues and collects ambiguous shows
 func ScanTVComplianceWithAmbiguous(paths []string, progressCh chan<- ScanProgress, excludePaths ...string) (*TVComplianceResult, error) {
+	inv, err := takeInventory(context.Background(), paths, nil)
+	if err != nil {
+		return nil, err
+	}
+	return scanTVComplianceInventory(inv, paths, progressCh, excludePaths...)
+}
+
+// scanTVComplianceInventory checks the episode files an inventory holds
+// below paths against the naming conventions and collects ambiguous shows
+func scanTVComplianceInventory(inv *inventory, paths []string, progressCh chan<- ScanProgress, excludePaths ...string) (*TVComplianceResult, error) {
 	var pr *ProgressReporter
 	if progressCh != nil {
-		pr = NewProgressReporterWithInterval(progressCh, "compliance_tv", 200*time.Millisecond)
-		pr.send(0, "Counting TV files for compliance check...")
-
-		total, err := CountVideoFilesWithProgress(paths, pr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to count files: %w", err)
-		}
+		pr = NewProgressReporterWithInterval(progressCh, OpComplianceTV, 200*time.Millisecond)
+		total := inv.videos(paths)
 		pr.Start(total, fmt.Sprintf("Checking %d TV files for compliance...", total))
 	}
 
 	var issues []ComplianceIssue
 	var ambiguousShows []*TVTitleResolution
-	seenAmbiguous := make(map[string]bool) // Deduplicate ambiguous shows by folder path
+	seenAmbiguous := make(map[string]bool)            // Deduplicate ambiguous shows by folder path
+	apiChecked := make(map[string]*TVTitleResolution) // Show folder -> first API-checked resolution
+	videosIn := make(folderVideoCache)
 	filesProcessed := 0
+	var showDirs []string // show folders holding episodes, for SetShowFolderYears
+	seenShowDirs := make(map[string]bool)
 
 	// Build exclusion set for fast lookup
 	excludeSet := make(map[string]bool)
@@ -270,23 +370,26 @@ func ScanTVComplianceWithAmbiguous(paths []string, progressCh chan<- ScanProgres
 	}
 
 	for _, libPath := range paths {
-		if _, err := os.Stat(libPath); err != nil {
-			return nil, fmt.Errorf("library path not accessible: %s: %w", libPath, err)
+		lib := inv.library(libPath)
+		if lib.err != nil {
+			return nil, fmt.Errorf("library path not accessible: %s: %w", libPath, lib.err)
 		}
+		anime := isAnimeLibrary(libPath)
 
-		err := filepath.Walk(libPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
+		for _, file := range lib.files {
+			path := file.path
 
-			// Skip the library root itself
-			if path == libPath {
-				return nil
+			// Subtitles, nfo and artwork must belong to a video in their folder
+			if isSidecarFile(path) {
+				if issue := checkOrphanedSidecar(path, "tv", videosIn.videos(filepath.Dir(path))); issue != nil {
+					issues = append(issues, *issue)
+				}
+				continue
 			}
 
 			// Only check video files
-			if info.IsDir() || !isVideoFile(path) {
-				return nil
+			if !isVideoFile(path) {
+				continue
 			}
 
 			filesProcessed++
@@ -296,28 +399,55 @@ func ScanTVComplianceWithAmbiguous(paths []string, progressCh chan<- ScanProgres
 
 			// Skip files marked for deletion in duplicate scan
 			if excludeSet[path] {
-				return nil
+				continue
+			}
+
+			// Extras are never renamed as episodes; only their folder can
+			// need fixing
+			if isExtraFile(path, file.info.Size()) {
+				if issue := checkStrayExtra(path, libPath, "tv"); issue != nil {
+					issues = append(issues, *issue)
+				}
+				continue
 			}
 
 			// Skip sample files - they should be deleted, not renamed
 			if isSampleFile(path) {
-				return nil
+				continue
 			}
 
-			// Must have S##E## pattern to be a TV episode
+			// Anime follows its own numbering and naming (SetAnimeLibraries)
+			if anime {
+				if issue := checkAnimeCompliance(path, libPath); issue != nil {
+					issues = append(issues, *issue)
+				}
+				continue
+			}
+
+			if showDir := showFolderOf(path, libPath); showDir != "" && !seenShowDirs[showDir] {
+				seenShowDirs[showDir] = true
+				showDirs = append(showDirs, showDir)
+			}
+
+			// Must have S##E## pattern to be a TV episode; untagged specials
+			// and daily episodes named by air date get their own rules
 			season, episode, found := ExtractEpisodeInfo(filepath.Base(path))
 			if !found {
-				// Not a TV episode format, skip
-				return nil
+				if issue := checkSpecial(path, libPath); issue != nil {
+					issues = append(issues, *issue)
+				} else if issue := checkDateEpisode(path, libPath); issue != nil {
+					issues = append(issues, *issue)
+				}
+				continue
 			}
 
 			// Get title resolution
 			resolution := ResolveTVShowTitle(path, libPath)
 
-			// Try API verification for ambiguous titles
-			// Note: API keys should be loaded from config in production
-			// For now, we skip API calls to avoid blocking - this should be improved
-			// by passing config through the call chain
+			// Try verification for ambiguous titles (SetTitleAuthorities, SetAPIKeys)
+			if resolution.IsAmbiguous {
+				verifyAmbiguousShow(resolution, filepath.Dir(filepath.Dir(path)), apiChecked, pr)
+			}
 
 			// Collect ambiguous shows (not API-verified) for manual intervention
 			if resolution.IsAmbiguous && !resolution.APIVerified {
@@ -331,9 +461,9 @@ func ScanTVComplianceWithAmbiguous(paths []string, progressCh chan<- ScanProgres
 				if !strings.HasPrefix(showFolder, libPath) || showFolder == libPath {
 					// Loose file or invalid structure - skip for manual intervention
 					if pr != nil {
-						pr.SendSeverityImmediate("warn", fmt.Sprintf("Skipping loose file (not in proper Show/Season structure): %s", path))
+						pr.SendSeverityImmediate(SeverityWarn, fmt.Sprintf("Skipping loose file (not in proper Show/Season structure): %s", path))
 					}
-					return nil
+					continue
 				}
 
 				// Additional safety: showFolder should be exactly 1 level below libPath
@@ -341,9 +471,9 @@ func ScanTVComplianceWithAmbiguous(paths []string, progressCh chan<- ScanProgres
 				if err != nil || strings.Contains(relPath, string(filepath.Separator)) {
 					// Too deep or invalid path relationship
 					if pr != nil {
-						pr.SendSeverityImmediate("warn", fmt.Sprintf("Skipping file with invalid folder depth: %s", path))
+						pr.SendSeverityImmediate(SeverityWarn, fmt.Sprintf("Skipping file with invalid folder depth: %s", path))
 					}
-					return nil
+					continue
 				}
 
 				if !seenAmbiguous[showFolder] {
@@ -369,14 +499,16 @@ func ScanTVComplianceWithAmbiguous(paths []string, progressCh chan<- ScanProgres
 				issues = append(issues, *issue)
 			}
 
-			return nil
-		})
-
-		if err != nil {
-			return nil, fmt.Errorf("error scanning %s: %w", libPath, err)
 		}
 	}
 
+	retargetOrphanedSidecars(issues)
+
+	// Show folder renames go first, ahead of the fixes below them
+	if showFolderYearsEnabled() {
+		issues = append(checkShowFolderYears(showDirs, pr), issues...)
+	}
+
 	if pr != nil {
 		pr.Complete(fmt.Sprintf("Found %d compliance issues, %d ambiguous shows", len(issues), len(ambiguousShows)))
 	}
@@ -387,6 +519,37 @@ func ScanTVComplianceWithAmbiguous(paths []string, progressCh chan<- ScanProgres
 	}, nil
 }
 
+// verifyAmbiguousShow verifies the first ambiguous file of a show folder via
+// the series manager (Sonarr), then TVDB/OMDB/TMDB, and reuses that outcome for
+// the folder's other episodes
+func verifyAmbiguousShow(resolution *TVTitleResolution, showFolder string, checked map[string]*TVTitleResolution, pr *ProgressReporter) {
+	prev, ok := checked[showFolder]
+	if !ok {
+		if resolveWithAuthority(resolution, showFolder, pr) {
+			checked[showFolder] = resolution
+			return
+		}
+		tvdbKey, omdbKey, tmdbKey := apiKeys()
+		if tvdbKey == "" && omdbKey == "" && tmdbKey == "" {
+			return
+		}
+		// Failures are logged through pr; the title stays ambiguous for manual review
+		VerifyTVShowTitleWithReporter(resolution, tvdbKey, omdbKey, tmdbKey, pr)
+		// A conflict with a clear confidence lead skips review (SetAutoResolveMargin)
+		autoResolveConflict(resolution, pr)
+		checked[showFolder] = resolution
+		return
+	}
+	if prev.APIVerified || prev.APIStatus != "" {
+		resolution.ResolvedTitle = prev.ResolvedTitle
+		resolution.APIVerified = prev.APIVerified
+		resolution.APIStatus = prev.APIStatus
+		resolution.IsAmbiguous = prev.IsAmbiguous
+		resolution.Confidence = prev.Confidence
+		resolution.Reason = prev.Reason
+	}
+}
+
 // checkTVCompliance checks if a TV episode file follows Jellyfin conventions
 func checkTVCompliance(filePath, libRoot string, season, episode int) *ComplianceIssue {
 	resolution := ResolveTVShowTitle(filePath, libRoot)
@@ -405,13 +568,14 @@ func checkTVComplianceWithResolution(filePath, libRoot string, season, episode i
 		cleanShowName = resolution.ResolvedTitle
 	}
 
-	expectedSeasonDir := fmt.Sprintf("Season %02d", season)
-	if seasonDir != expectedSeasonDir {
+	profile := GetNamingProfile()
+	expectedSeasonDir := profile.SeasonFolder(season)
+	if !profile.IsSeasonFolder(seasonDir, season) {
 		suggestedDir := filepath.Join(libRoot, cleanShowName, expectedSeasonDir)
-		suggestedFilename := fmt.Sprintf("%s S%02dE%02d%s", cleanShowName, season, episode, filepath.Ext(filePath))
+		suggestedFilename := suggestedEpisodeFilename(profile, cleanShowName, season, episode, filepath.Ext(filePath))
 		suggestedPath := filepath.Join(suggestedDir, suggestedFilename)
 
-		problem := fmt.Sprintf("Not in proper 'Season %02d' folder (found: %s)", season, seasonDir)
+		problem := fmt.Sprintf("Not in proper '%s' folder (found: %s)", expectedSeasonDir, seasonDir)
 		if resolution.IsAmbiguous {
 			problem += fmt.Sprintf(" [AMBIGUOUS: %s]", resolution.Reason)
 		}
@@ -420,13 +584,16 @@ func checkTVComplianceWithResolution(filePath, libRoot string, season, episode i
 			Path:            filePath,
 			Type:            "tv",
 			Problem:         problem,
+			Severity:        IssueSeverityError,
+			Rule:            RuleTVSeasonFolder,
 			SuggestedPath:   suggestedPath,
 			SuggestedAction: "reorganize",
+			Confidence:      titleConfidence(resolution),
 		}
 	}
 
 	if isReleaseGroupFolder(filename) {
-		suggestedFilename := fmt.Sprintf("%s S%02dE%02d%s", cleanShowName, season, episode, filepath.Ext(filePath))
+		suggestedFilename := suggestedEpisodeFilename(profile, cleanShowName, season, episode, filepath.Ext(filePath))
 		suggestedPath := filepath.Join(filepath.Dir(filePath), suggestedFilename)
 
 		problem := "Release group naming in filename"
@@ -438,19 +605,24 @@ func checkTVComplianceWithResolution(filePath, libRoot string, season, episode i
 			Path:            filePath,
 			Type:            "tv",
 			Problem:         problem,
+			Severity:        IssueSeverityWarn,
+			Rule:            RuleTVReleaseGroupFilename,
 			SuggestedPath:   suggestedPath,
 			SuggestedAction: "rename",
+			Confidence:      titleConfidence(resolution),
 		}
 	}
 
 	if resolution.IsAmbiguous && (resolution.FolderMatch.Title != resolution.FilenameMatch.Title) {
-		suggestedFilename := fmt.Sprintf("%s S%02dE%02d%s", cleanShowName, season, episode, filepath.Ext(filePath))
+		suggestedFilename := suggestedEpisodeFilename(profile, cleanShowName, season, episode, filepath.Ext(filePath))
 		suggestedPath := filepath.Join(filepath.Dir(filePath), suggestedFilename)
 
 		return &ComplianceIssue{
 			Path:            filePath,
 			Type:            "tv",
 			Problem:         fmt.Sprintf("Title mismatch: %s", resolution.Reason),
+			Severity:        IssueSeverityError,
+			Rule:            RuleTVTitleMismatch,
 			SuggestedPath:   suggestedPath,
 			SuggestedAction: "manual_review",
 		}
@@ -459,35 +631,27 @@ func checkTVComplianceWithResolution(filePath, libRoot string, season, episode i
 	return nil
 }
 
-// isSampleFile checks if a file is a sample/trailer/extra (should be deleted, not fixed)
-func isSampleFile(path string) bool {
-	filename := strings.ToLower(filepath.Base(path))
-	// Remove dots and other separators for matching
-	normalized := strings.ReplaceAll(filename, ".", " ")
-	normalized = strings.ReplaceAll(normalized, "_", " ")
-
-	sampleMarkers := []string{
-		"sample",
-		"trailer",
-		"extra",
-		"deleted scene",
-		"behind the scenes",
-		"making of",
-		"interview",
-		"featurette",
-	}
-
-	for _, marker := range sampleMarkers {
-		if strings.Contains(normalized, marker) {
-			return true
-		}
-	}
+// titleConfidence is the resolution's confidence as recorded on an issue:
+// within 0-1 and above 0, so it is not mistaken for an unrecorded one
+func titleConfidence(resolution *TVTitleResolution) float64 {
+	return math.Min(math.Max(resolution.Confidence, 0.01), 1)
+}
 
-	return false
+// isSampleFile reports whether a video is a release's sample clip, which is
+// never renamed; trailers and other extras are told apart by isExtraFile
+func isSampleFile(path string) bool {
+	return sampleNameRegex.MatchString(fileStem(path)) || sampleFolderRegex.MatchString(filepath.Base(filepath.Dir(path)))
 }
 
 // isReleaseGroupFolder checks if a folder name contains release group markers
 func isReleaseGroupFolder(name string) bool {
+	return len(releaseGroupReasons(name)) > 0
+}
+
+// releaseGroupReasons lists every release marker found in a name
+// Used by isReleaseGroupFolder and by explain mode to show matched tokens
+func releaseGroupReasons(name string) []string {
+	var reasons []string
 	nameUpper := strings.ToUpper(name)
 
 	// Check for common release markers
@@ -500,22 +664,22 @@ func isReleaseGroupFolder(name string) bool {
 
 	for _, marker := range markers {
 		if strings.Contains(nameUpper, marker) {
-			return true
+			reasons = append(reasons, fmt.Sprintf("release marker %q", marker))
 		}
 	}
 
 	// Check for hyphenated release group at end (e.g., "-GROUP")
 	if strings.Contains(name, "-") && !strings.Contains(name, " - ") {
-		return true
+		reasons = append(reasons, "hyphenated release group suffix")
 	}
 
 	// Check for dots as separators (release naming style)
 	dotCount := strings.Count(name, ".")
 	if dotCount >= 3 {
-		return true
+		reasons = append(reasons, fmt.Sprintf("dot-separated name (%d dots)", dotCount))
 	}
 
-	return false
+	return reasons
 }
 
 // hasYear checks if string contains a 4-digit year
@@ -537,13 +701,13 @@ func ApplyMovieCompliance(issue ComplianceIssue) error {
 
 // ApplyMovieComplianceWithProgress applies the suggested fix for a compliance issue with progress reporting
 func ApplyMovieComplianceWithProgress(issue ComplianceIssue, progressCh chan<- ScanProgress) error {
-	pr := NewProgressReporterWithInterval(progressCh, "compliance_movies", 200*time.Millisecond)
+	pr := NewProgressReporterWithInterval(progressCh, OpComplianceMovies, 200*time.Millisecond)
 	pr.StageUpdate("applying", fmt.Sprintf("Applying compliance fix for: %s", issue.Path))
 	if err := applyMovieComplianceInternal(issue); err != nil {
 		pr.LogError(err, fmt.Sprintf("Failed to apply compliance: %s", issue.Path))
 		return err
 	}
-	pr.SendSeverityImmediate("info", fmt.Sprintf("Fixed compliance for: %s", issue.Path))
+	pr.SendSeverityImmediate(SeverityInfo, fmt.Sprintf("Fixed compliance for: %s", issue.Path))
 	return nil
 }
 
@@ -559,7 +723,7 @@ func ApplyMovieComplianceWithReporter(issue ComplianceIssue, pr *ProgressReporte
 		return err
 	}
 	if pr != nil {
-		pr.SendSeverityImmediate("info", fmt.Sprintf("Fixed compliance for: %s", issue.Path))
+		pr.SendSeverityImmediate(SeverityInfo, fmt.Sprintf("Fixed compliance for: %s", issue.Path))
 	}
 	return nil
 }
@@ -568,6 +732,8 @@ func applyMovieComplianceInternal(issue ComplianceIssue) error {
 	if issue.Type != "movie" {
 		return fmt.Errorf("not a movie compliance issue")
 	}
+	// Subtitles, nfo and artwork named after the video follow it
+	companions := CompanionMoves(issue.Path, issue.SuggestedPath)
 
 	// Create parent directory if it doesn't exist
 	targetDir := filepath.Dir(issue.SuggestedPath)
@@ -597,6 +763,7 @@ func applyMovieComplianceInternal(issue ComplianceIssue) error {
 			if err := os.Remove(issue.Path); err != nil {
 				return fmt.Errorf("failed to remove hardlinked duplicate: %w", err)
 			}
+			moveCompanions(companions)
 
 			// Clean up empty directory
 			originalDir := filepath.Dir(issue.Path)
@@ -615,6 +782,7 @@ func applyMovieComplianceInternal(issue ComplianceIssue) error {
 	if err := os.Rename(issue.Path, issue.SuggestedPath); err != nil {
 		return fmt.Errorf("failed to move file: %w", err)
 	}
+	moveCompanions(companions)
 
 	// If original directory is now empty (and not library root), remove it
 	originalDir := filepath.Dir(issue.Path)
@@ -634,13 +802,13 @@ func ApplyTVCompliance(issue ComplianceIssue) error {
 
 // ApplyTVComplianceWithProgress applies the suggested fix for a TV compliance issue with progress broadcaster
 func ApplyTVComplianceWithProgress(issue ComplianceIssue, progressCh chan<- ScanProgress) error {
-	pr := NewProgressReporterWithInterval(progressCh, "compliance_tv", 200*time.Millisecond)
+	pr := NewProgressReporterWithInterval(progressCh, OpComplianceTV, 200*time.Millisecond)
 	pr.StageUpdate("applying", fmt.Sprintf("Applying TV compliance fix for: %s", issue.Path))
 	if err := applyTVComplianceInternal(issue); err != nil {
 		pr.LogError(err, fmt.Sprintf("Failed to apply TV compliance: %s", issue.Path))
 		return err
 	}
-	pr.SendSeverityImmediate("info", fmt.Sprintf("Fixed compliance for: %s", issue.Path))
+	pr.SendSeverityImmediate(SeverityInfo, fmt.Sprintf("Fixed compliance for: %s", issue.Path))
 	return nil
 }
 
@@ -656,7 +824,7 @@ func ApplyTVComplianceWithReporter(issue ComplianceIssue, pr *ProgressReporter)
 		return err
 	}
 	if pr != nil {
-		pr.SendSeverityImmediate("info", fmt.Sprintf("Fixed compliance for: %s", issue.Path))
+		pr.SendSeverityImmediate(SeverityInfo, fmt.Sprintf("Fixed compliance for: %s", issue.Path))
 	}
 	return nil
 }
@@ -665,6 +833,8 @@ func applyTVComplianceInternal(issue ComplianceIssue) error {
 	if issue.Type != "tv" {
 		return fmt.Errorf("not a TV compliance issue")
 	}
+	// Subtitles, nfo and artwork named after the episode follow it
+	companions := CompanionMoves(issue.Path, issue.SuggestedPath)
 
 	// Parse target path components
 	targetSeasonDir := filepath.Dir(issue.SuggestedPath)
@@ -717,6 +887,7 @@ func applyTVComplianceInternal(issue ComplianceIssue) error {
 			if err := os.Remove(issue.Path); err != nil {
 				return fmt.Errorf("failed to remove hardlinked duplicate: %w", err)
 			}
+			moveCompanions(companions)
 
 			// Clean up empty directories
 			originalDir := filepath.Dir(issue.Path)
@@ -741,6 +912,7 @@ func applyTVComplianceInternal(issue ComplianceIssue) error {
 	if err := os.Rename(issue.Path, issue.SuggestedPath); err != nil {
 		return fmt.Errorf("failed to move file: %w", err)
 	}
+	moveCompanions(companions)
 
 	// If original directory is now empty, remove it
 	originalDir := filepath.Dir(issue.Path)
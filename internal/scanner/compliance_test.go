@@ -446,11 +446,12 @@ func TestIsSampleFile(t *testing.T) {
 	}{
 		{"/path/to/Sample.Movie.2024.mkv", true},
 		{"/path/to/sample.mkv", true},
-		{"/path/to/Movie.2024.Trailer.mkv", true},
-		{"/path/to/Extra.Behind.The.Scenes.mkv", true},
-		{"/path/to/Deleted.Scene.mkv", true},
-		{"/path/to/Making.Of.Documentary.mkv", true},
-		{"/path/to/Interview.With.Director.mkv", true},
+		{"/path/to/Sample/Movie.2024.mkv", true},
+		// Trailers and other extras are not samples (isExtraFile)
+		{"/path/to/Movie.2024.Trailer.mkv", false},
+		{"/path/to/Extra.Behind.The.Scenes.mkv", false},
+		{"/path/to/Deleted.Scene.mkv", false},
+		{"/path/to/Extraction.2020.mkv", false},
 		{"/path/to/Movie.Name.2024.mkv", false},
 		{"/path/to/Normal.Movie.mkv", false},
 	}
@@ -0,0 +1,134 @@
+package scanner
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// editionMarkers map the edition tags found in movie names to the label
+// Jellyfin shows for the version, tried in order
+var editionMarkers = []struct {
+	pattern *regexp.Regexp
+	label   string
+}{
+	{regexp.MustCompile(`(?i)\{edition-([^}]+)\}`), ""}, // Plex-style {edition-Label}, labelled as written
+	{regexp.MustCompile(`(?i)\bdirector'?s[ ._-]*cut\b|\bDC\b`), "Director's Cut"},
+	{regexp.MustCompile(`(?i)\bfinal[ ._-]*cut\b`), "Final Cut"},
+	{regexp.MustCompile(`(?i)\bultimate[ ._-]*(cut|edition)\b`), "Ultimate Edition"},
+	{regexp.MustCompile(`(?i)\bcollector'?s[ ._-]*edition\b`), "Collector's Edition"},
+	{regexp.MustCompile(`(?i)\b(\d+(st|nd|rd|th)[ ._-]*)?anniversary[ ._-]*edition\b`), "Anniversary Edition"},
+	{regexp.MustCompile(`(?i)\bspecial[ ._-]*edition\b`), "Special Edition"},
+	{regexp.MustCompile(`(?i)\bextended([ ._-]*(cut|edition|version))?\b`), "Extended"},
+	{regexp.MustCompile(`(?i)\btheatrical([ ._-]*(cut|edition|version))?\b`), "Theatrical"},
+	{regexp.MustCompile(`(?i)\bunrated\b`), "Unrated"},
+	{regexp.MustCompile(`(?i)\buncut\b`), "Uncut"},
+	{regexp.MustCompile(`(?i)\bcriterion\b`), "Criterion"},
+	{regexp.MustCompile(`(?i)\bimax\b`), "IMAX"},
+	{regexp.MustCompile(`(?i)\bremastered\b`), "Remastered"},
+}
+
+var (
+	allowVersions   bool
+	allowVersionsMu sync.RWMutex
+)
+
+// SetAllowVersions sets whether copies of a movie in another edition or
+// resolution are kept as Jellyfin versions instead of reported as duplicates
+func SetAllowVersions(allow bool) {
+	allowVersionsMu.Lock()
+	defer allowVersionsMu.Unlock()
+	allowVersions = allow
+}
+
+// GetAllowVersions returns the setting set with SetAllowVersions
+func GetAllowVersions() bool {
+	allowVersionsMu.RLock()
+	defer allowVersionsMu.RUnlock()
+	return allowVersions
+}
+
+// ExtractEdition returns the edition a movie file or folder name is tagged
+// with ("Director's Cut", "Extended", ...), or "" when it has none. Only
+// the part after the year is searched, so titles such as "The Final Cut
+// (2004)" are not mistaken for editions
+func ExtractEdition(name string) string {
+	if year := ExtractYear(name); year != "" {
+		name = name[strings.LastIndex(name, year)+len(year):]
+	}
+	for _, marker := range editionMarkers {
+		match := marker.pattern.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+		if marker.label == "" {
+			return strings.TrimSpace(match[1])
+		}
+		return marker.label
+	}
+	return ""
+}
+
+// fileEdition returns the edition of a movie file, from its filename or
+// else its folder
+func fileEdition(path string) string {
+	if edition := ExtractEdition(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))); edition != "" {
+		return edition
+	}
+	return ExtractEdition(filepath.Base(filepath.Dir(path)))
+}
+
+// splitVersions divides a group of copies of one movie into the copies of
+// each edition and resolution, which Jellyfin can show as versions. A copy
+// of unknown resolution and no edition can't be told apart from the
+// others, so the group is returned whole
+func splitVersions(files []MovieFile) [][]MovieFile {
+	var keys []string
+	byKey := make(map[string][]MovieFile)
+	for _, file := range files {
+		key := versionKey(file)
+		if key == "" {
+			return [][]MovieFile{files}
+		}
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		byKey[key] = append(byKey[key], file)
+	}
+
+	groups := make([][]MovieFile, 0, len(keys))
+	for _, key := range keys {
+		groups = append(groups, byKey[key])
+	}
+	return groups
+}
+
+// versionKey names the version a copy of a movie is: its edition and
+// resolution ("Director's Cut 2160p"), or "" when it has neither
+func versionKey(file MovieFile) string {
+	var parts []string
+	if edition := fileEdition(file.Path); edition != "" {
+		parts = append(parts, edition)
+	}
+	if file.Resolution != "" && file.Resolution != "unknown" {
+		parts = append(parts, file.Resolution)
+	}
+	return strings.Join(parts, " ")
+}
+
+// versionName adds the file's edition to a suggested "Title (Year)" movie
+// path, as "<folder>/<folder> - <edition><ext>", when it has one
+func versionName(suggestedPath, sourcePath string) string {
+	edition := sanitizeEpisodeTitle(fileEdition(sourcePath))
+	if edition == "" {
+		return suggestedPath
+	}
+	return withVersionLabel(suggestedPath, edition)
+}
+
+// withVersionLabel names a suggested movie path as a version of its folder
+func withVersionLabel(suggestedPath, label string) string {
+	dir := filepath.Dir(suggestedPath)
+	return filepath.Join(dir, filepath.Base(dir)+versionSeparator+label+filepath.Ext(suggestedPath))
+}
@@ -0,0 +1,108 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractEdition(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Apocalypse.Now.1979.Final.Cut.2160p.UHD.BluRay.x265", "Final Cut"},
+		{"Aliens.1986.Directors.Cut.1080p", "Director's Cut"},
+		{"Aliens (1986) - Director's Cut", "Director's Cut"},
+		{"The.Lord.of.the.Rings.2001.EXTENDED.1080p", "Extended"},
+		{"Amadeus 1984 {edition-Director's Cut}", "Director's Cut"},
+		{"The Final Cut (2004)", ""},
+		{"Heat (1995)", ""},
+		{"Heat.1995.1080p.BluRay", ""},
+	}
+	for _, tt := range tests {
+		if got := ExtractEdition(tt.name); got != tt.want {
+			t.Errorf("ExtractEdition(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestScanMoviesAllowVersions(t *testing.T) {
+	tmpDir := t.TempDir()
+	heat := filepath.Join(tmpDir, "Heat (1995)")
+	aliens := filepath.Join(tmpDir, "Aliens (1986)")
+	os.MkdirAll(heat, 0755)
+	os.MkdirAll(aliens, 0755)
+	for _, path := range []string{
+		filepath.Join(heat, "Heat.1995.2160p.mkv"),
+		filepath.Join(heat, "Heat.1995.1080p.mkv"),
+		filepath.Join(aliens, "Aliens.1986.1080p.mkv"),
+		filepath.Join(aliens, "Aliens.1986.Directors.Cut.1080p.mkv"),
+		filepath.Join(aliens, "Aliens.1986.Directors.Cut.1080p.REPACK.mkv"),
+	} {
+		os.WriteFile(path, []byte("video"), 0644)
+	}
+
+	duplicates, err := ScanMovies([]string{tmpDir})
+	if err != nil {
+		t.Fatalf("ScanMovies() error: %v", err)
+	}
+	if len(duplicates) != 2 {
+		t.Fatalf("Expected both movies flagged without allow_versions, got %d groups", len(duplicates))
+	}
+
+	SetAllowVersions(true)
+	defer SetAllowVersions(false)
+	duplicates, err = ScanMovies([]string{tmpDir})
+	if err != nil {
+		t.Fatalf("ScanMovies() error: %v", err)
+	}
+	if len(duplicates) != 1 || len(duplicates[0].Files) != 2 || duplicates[0].Version != "Director's Cut 1080p" {
+		t.Fatalf("Expected only the two 1080p director's cuts flagged, got %+v", duplicates)
+	}
+	if MovieDuplicateID(duplicates[0]) == MovieDuplicateID(MovieDuplicate{NormalizedName: duplicates[0].NormalizedName, Year: "1986"}) {
+		t.Error("Expected a version group to have its own ID")
+	}
+}
+
+func TestMovieComplianceAllowVersions(t *testing.T) {
+	tmpDir := t.TempDir()
+	heat := filepath.Join(tmpDir, "Heat (1995)")
+	os.MkdirAll(heat, 0755)
+	for _, path := range []string{
+		filepath.Join(tmpDir, "Apocalypse.Now.1979.Final.Cut.2160p.mkv"),
+		filepath.Join(heat, "Heat.1995.1080p.mkv"),
+		filepath.Join(heat, "Heat.1995.2160p.mkv"),
+		filepath.Join(heat, "Heat.1995.Theatrical.mkv"),
+	} {
+		os.WriteFile(path, []byte("video"), 0644)
+	}
+	SetAllowVersions(true)
+	defer SetAllowVersions(false)
+
+	issues, err := ScanMovieCompliance([]string{tmpDir})
+	if err != nil {
+		t.Fatalf("ScanMovieCompliance() error: %v", err)
+	}
+	got := make(map[string]string)
+	for _, issue := range issues {
+		if issue.SuggestedAction != "reorganize" {
+			t.Errorf("Expected %s renamed as a version, got %s: %s", issue.Path, issue.SuggestedAction, issue.Problem)
+		}
+		got[filepath.Base(issue.Path)] = issue.SuggestedPath
+	}
+	want := map[string]string{
+		"Apocalypse.Now.1979.Final.Cut.2160p.mkv": filepath.Join(tmpDir, "Apocalypse Now (1979)", "Apocalypse Now (1979) - Final Cut.mkv"),
+		"Heat.1995.Theatrical.mkv":                filepath.Join(heat, "Heat (1995) - Theatrical.mkv"),
+	}
+	for file, path := range want {
+		if got[file] != path {
+			t.Errorf("Expected %s suggested as %s, got %s", file, path, got[file])
+		}
+	}
+	// One Heat copy takes the plain name, the other its resolution
+	plain, labelled := filepath.Join(heat, "Heat (1995).mkv"), filepath.Join(heat, "Heat (1995) - 2160p.mkv")
+	if a, b := got["Heat.1995.1080p.mkv"], got["Heat.1995.2160p.mkv"]; !(a == plain && b == labelled) {
+		t.Errorf("Expected the 1080p copy named %s and the 2160p copy %s, got %s and %s", plain, labelled, a, b)
+	}
+}
@@ -0,0 +1,290 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxEpisodePages caps the pages fetched for one series' episode list
+const maxEpisodePages = 50
+
+// TVDBEpisode is one episode from a TVDB series episode list
+type TVDBEpisode struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	SeasonNumber int    `json:"seasonNumber"`
+	Number       int    `json:"number"`
+	Aired        string `json:"aired"` // first air date, "2006-01-02"; empty when not yet scheduled
+}
+
+// tvdbEpisodesResponse is one page of /series/{id}/episodes
+type tvdbEpisodesResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Episodes []TVDBEpisode `json:"episodes"`
+	} `json:"data"`
+	Links struct {
+		Next *string `json:"next"`
+	} `json:"links"`
+}
+
+var (
+	episodeTitles   bool
+	episodeTitlesMu sync.RWMutex
+)
+
+// SetEpisodeTitles enables appending TVDB episode titles to suggested
+// episode filenames (naming.episode_titles); it needs a TVDB key
+func SetEpisodeTitles(enabled bool) {
+	episodeTitlesMu.Lock()
+	defer episodeTitlesMu.Unlock()
+	episodeTitles = enabled
+}
+
+// episodeTitlesEnabled reports whether SetEpisodeTitles turned titles on
+func episodeTitlesEnabled() bool {
+	episodeTitlesMu.RLock()
+	defer episodeTitlesMu.RUnlock()
+	return episodeTitles
+}
+
+// rateLimiter spaces requests at least interval apart
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// Wait blocks until the next request may be sent
+func (l *rateLimiter) Wait() {
+	l.mu.Lock()
+	now := time.Now()
+	wait := l.next.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	l.next = now.Add(wait + l.interval)
+	l.mu.Unlock()
+	time.Sleep(wait)
+}
+
+// tvdbEpisodeLimiter paces episode list requests; a long series takes
+// several pages and a library can hold hundreds of series
+var tvdbEpisodeLimiter = &rateLimiter{interval: 250 * time.Millisecond}
+
+// episodeKey identifies an episode within a series
+type episodeKey struct {
+	season  int
+	episode int
+}
+
+// episodeListCache holds the TVDB episode lists per show name for the
+// session. A nil list records a show whose series could not be found
+type episodeListCache struct {
+	mu    sync.Mutex
+	shows map[string][]TVDBEpisode
+}
+
+var episodeCache = &episodeListCache{shows: make(map[string][]TVDBEpisode)}
+
+func (c *episodeListCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.shows = make(map[string][]TVDBEpisode)
+}
+
+// Episodes fetches every episode of a TVDB series (English titles where
+// TVDB has them), following the list's pages
+func (c *TVDBClient) Episodes(seriesID string) ([]TVDBEpisode, error) {
+	if c.APIKey == "" {
+		return nil, fmt.Errorf("TVDB API key not configured")
+	}
+
+	var episodes []TVDBEpisode
+	for page := 0; page < maxEpisodePages; page++ {
+		var resp tvdbEpisodesResponse
+		path := fmt.Sprintf("/series/%s/episodes/default/eng?page=%d", seriesID, page)
+		if err := c.getWithRetry(path, &resp, 3); err != nil {
+			return nil, err
+		}
+		episodes = append(episodes, resp.Data.Episodes...)
+		if resp.Links.Next == nil || *resp.Links.Next == "" || len(resp.Data.Episodes) == 0 {
+			break
+		}
+	}
+	return episodes, nil
+}
+
+// getWithRetry GETs path into out, logging in as needed and backing off on
+// rate limits. Requests are paced by tvdbEpisodeLimiter and count towards
+// the TVDB circuit breaker
+func (c *TVDBClient) getWithRetry(path string, out any, maxRetries int) error {
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if err := tvdbBreaker.Allow(); err != nil {
+			return err
+		}
+		if attempt > 0 {
+			backoff := time.Duration(math.Pow(2, float64(attempt-1))) * time.Second
+			time.Sleep(backoff)
+		}
+
+		if c.Token == "" {
+			if err := c.Login(); err != nil {
+				if isUnreachable(err) {
+					tvdbBreaker.Failure()
+				}
+				lastErr = fmt.Errorf("failed to authenticate: %w", err)
+				continue
+			}
+		}
+
+		req, err := http.NewRequest("GET", c.BaseURL+path, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		req.Header.Set("Accept", "application/json")
+
+		tvdbEpisodeLimiter.Wait()
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			tvdbBreaker.Failure()
+			lastErr = fmt.Errorf("API request failed: %w", describeRequestError(err))
+			continue
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			tvdbBreaker.Failure()
+		} else {
+			tvdbBreaker.Success()
+		}
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			err := json.NewDecoder(resp.Body).Decode(out)
+			resp.Body.Close()
+			if err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+			return nil
+		case http.StatusUnauthorized:
+			c.Token = ""
+			lastErr = fmt.Errorf("authentication expired, retrying")
+		case http.StatusTooManyRequests:
+			lastErr = fmt.Errorf("rate limited")
+		default:
+			body, _ := io.ReadAll(resp.Body)
+			lastErr = fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		}
+		resp.Body.Close()
+	}
+	return fmt.Errorf("failed after %d retries: %w", maxRetries, lastErr)
+}
+
+// LookupEpisodeTitle returns the TVDB title of an episode of show, or ""
+// when episode titles are off, no TVDB key is set or the episode is unknown
+func LookupEpisodeTitle(show string, season, episode int) string {
+	if !episodeTitlesEnabled() {
+		return ""
+	}
+	episodes, _ := showEpisodes(show)
+	for _, ep := range episodes {
+		if ep.SeasonNumber == season && ep.Number == episode {
+			return sanitizeEpisodeTitle(ep.Name)
+		}
+	}
+	return ""
+}
+
+// showEpisodes returns the TVDB episode list of show, or nil when no TVDB
+// key is set or no series matches. Each show's list is fetched once per
+// session, or once per run of lookups for the same show in low-memory mode.
+// ErrAPIOffline is returned, and nothing cached, while TVDB is unreachable
+func showEpisodes(show string) ([]TVDBEpisode, error) {
+	tvdbKey, _, _ := apiKeys()
+	if tvdbKey == "" {
+		return nil, nil
+	}
+
+	episodeCache.mu.Lock()
+	defer episodeCache.mu.Unlock()
+	if episodes, ok := episodeCache.shows[show]; ok {
+		return episodes, nil
+	}
+	episodes, err := fetchSeriesEpisodes(NewTVDBClient(tvdbKey), show)
+	if errors.Is(err, ErrAPIOffline) {
+		// Retried on the next scan rather than cached as missing
+		return nil, err
+	}
+	if LowMemory() {
+		// Shows are scanned one folder at a time; keep only the current one
+		episodeCache.shows = make(map[string][]TVDBEpisode)
+	}
+	episodeCache.shows[show] = episodes
+	return episodes, nil
+}
+
+// fetchSeriesEpisodes finds show on TVDB and loads its episode list
+// Only a series whose name (and year, when show has one) matches is used
+func fetchSeriesEpisodes(client *TVDBClient, show string) ([]TVDBEpisode, error) {
+	name := strings.TrimSpace(removeYear(show))
+	year := ExtractYear(show)
+
+	results, err := client.SearchSeries(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var seriesID string
+	for _, series := range results {
+		if NormalizeName(series.Name) != NormalizeName(name) {
+			continue
+		}
+		if year != "" && series.Year != "" && series.Year != year {
+			continue
+		}
+		seriesID = tvdbSeriesID(series)
+		break
+	}
+	if seriesID == "" {
+		return nil, fmt.Errorf("no TVDB series matches %q", show)
+	}
+	return client.Episodes(seriesID)
+}
+
+// tvdbSeriesID returns the numeric id used by the series endpoints
+func tvdbSeriesID(series TVDBSeries) string {
+	if series.TVDBID != "" {
+		return series.TVDBID
+	}
+	return strings.TrimPrefix(series.ID, "series-")
+}
+
+// sanitizeEpisodeTitle makes an episode title safe for a filename
+func sanitizeEpisodeTitle(title string) string {
+	title = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return ' '
+		}
+		return r
+	}, title)
+	title = strings.Join(strings.Fields(title), " ")
+	if runes := []rune(title); len(runes) > 100 {
+		title = strings.TrimSpace(string(runes[:100]))
+	}
+	return strings.TrimRight(title, ". ")
+}
+
+// suggestedEpisodeFilename is the profile's episode filename, with the TVDB
+// episode title appended when episode titles are enabled and known
+func suggestedEpisodeFilename(profile NamingProfile, show string, season, episode int, ext string) string {
+	return profile.EpisodeFilenameWithTitle(show, season, episode, LookupEpisodeTitle(show, season, episode), ext)
+}
@@ -0,0 +1,126 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newEpisodeTestServer mocks the TVDB login, search and episode endpoints
+// for Firefly, split over two pages
+func newEpisodeTestServer(t *testing.T, episodeRequests *int32) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"success","data":{"token":"test-token"}}`)
+	})
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"success","data":[
+			{"id":"series-1","tvdb_id":"1","name":"Firefly Lane","year":"2021"},
+			{"id":"series-78874","tvdb_id":"78874","name":"Firefly","year":"2002"}]}`)
+	})
+	mux.HandleFunc("/series/78874/episodes/default/eng", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(episodeRequests, 1)
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("page") == "0" {
+			fmt.Fprint(w, `{"status":"success","data":{"episodes":[
+				{"id":1,"name":"Serenity","seasonNumber":1,"number":1},
+				{"id":2,"name":"The Train Job","seasonNumber":1,"number":2}]},
+				"links":{"next":"page=1"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"success","data":{"episodes":[
+			{"id":3,"name":"Bushwhacked: Part 1?","seasonNumber":1,"number":3}]},
+			"links":{"next":null}}`)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestLookupEpisodeTitle(t *testing.T) {
+	var episodeRequests int32
+	server := newEpisodeTestServer(t, &episodeRequests)
+	defer server.Close()
+
+	origURL := TVDBBaseURL
+	origInterval := tvdbEpisodeLimiter.interval
+	TVDBBaseURL = server.URL
+	tvdbEpisodeLimiter.interval = time.Millisecond
+	ClearAPICache()
+	ResetAPICircuit()
+	defer func() {
+		TVDBBaseURL = origURL
+		tvdbEpisodeLimiter.interval = origInterval
+		SetAPIKeys("", "", "")
+		SetEpisodeTitles(false)
+		ClearAPICache()
+	}()
+
+	SetAPIKeys("test-key", "", "")
+	if got := LookupEpisodeTitle("Firefly (2002)", 1, 1); got != "" {
+		t.Errorf("Expected no title while episode titles are off, got %q", got)
+	}
+
+	SetEpisodeTitles(true)
+	tests := []struct {
+		season, episode int
+		want            string
+	}{
+		{1, 1, "Serenity"},
+		{1, 2, "The Train Job"},
+		{1, 3, "Bushwhacked Part 1"},
+		{2, 1, ""},
+	}
+	for _, tt := range tests {
+		if got := LookupEpisodeTitle("Firefly (2002)", tt.season, tt.episode); got != tt.want {
+			t.Errorf("LookupEpisodeTitle(S%02dE%02d) = %q, want %q", tt.season, tt.episode, got, tt.want)
+		}
+	}
+	if got := atomic.LoadInt32(&episodeRequests); got != 2 {
+		t.Errorf("Expected the two episode pages fetched once, got %d requests", got)
+	}
+
+	got := suggestedEpisodeFilename(ProfileJellyfin, "Firefly (2002)", 1, 2, ".mkv")
+	if want := "Firefly (2002) S01E02 - The Train Job.mkv"; got != want {
+		t.Errorf("suggestedEpisodeFilename() = %q, want %q", got, want)
+	}
+
+	// A show with no matching series gets no titles
+	if got := LookupEpisodeTitle("Serenity Valley", 1, 1); got != "" {
+		t.Errorf("Expected no title for an unmatched show, got %q", got)
+	}
+}
+
+func TestEpisodeFilenameWithTitle(t *testing.T) {
+	if got := ProfileJellyfin.EpisodeFilenameWithTitle("Lost", 1, 1, "Pilot", ".mkv"); got != "Lost S01E01 - Pilot.mkv" {
+		t.Errorf("jellyfin titled filename = %q", got)
+	}
+	if got := ProfileEmby.EpisodeFilenameWithTitle("Lost", 1, 1, "Pilot", ".mkv"); got != "Lost - S01E01 - Pilot.mkv" {
+		t.Errorf("emby titled filename = %q", got)
+	}
+	if got := ProfileJellyfin.EpisodeFilenameWithTitle("Lost", 1, 1, "", ".mkv"); got != "Lost S01E01.mkv" {
+		t.Errorf("untitled filename = %q", got)
+	}
+}
+
+func TestSanitizeEpisodeTitle(t *testing.T) {
+	tests := map[string]string{
+		"Pilot":                    "Pilot",
+		"What/Ever: Part 2":        "What Ever Part 2",
+		`Who Are "You"?`:           "Who Are You",
+		"To Be Continued...":       "To Be Continued",
+		"  Spaced   \t  Out  ":     "Spaced Out",
+		"<Mirror|Mirror>":          "Mirror Mirror",
+		"Ends With Question Mark?": "Ends With Question Mark",
+	}
+	for in, want := range tests {
+		if got := sanitizeEpisodeTitle(in); got != want {
+			t.Errorf("sanitizeEpisodeTitle(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
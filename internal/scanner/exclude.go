@@ -0,0 +1,119 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/ignore"
+)
+
+// IgnoreFile lists patterns, relative to the folder holding it, that scans
+// skip and cleans never touch. An empty one excludes the whole folder
+const IgnoreFile = ".jellysinkignore"
+
+// ignoreFileTTL is how long a folder's ignore file is trusted before it is
+// read again, so a long-running daemon sees edits
+const ignoreFileTTL = time.Minute
+
+var (
+	excludePatterns   []ignore.Pattern
+	excludePatternsMu sync.RWMutex
+
+	ignoreFiles   = map[string]ignoreFileEntry{}
+	ignoreFilesMu sync.Mutex
+)
+
+// ignoreFileEntry is a folder's parsed ignore file
+type ignoreFileEntry struct {
+	found    bool
+	patterns []ignore.Pattern
+	err      error // unreadable or invalid; the whole folder is excluded
+	loaded   time.Time
+}
+
+// SetExcludePatterns replaces the patterns scans skip and cleans refuse
+// (scan.exclude)
+func SetExcludePatterns(patterns []ignore.Pattern) {
+	excludePatternsMu.Lock()
+	defer excludePatternsMu.Unlock()
+	excludePatterns = patterns
+}
+
+// GetExcludePatterns returns the patterns set with SetExcludePatterns
+func GetExcludePatterns() []ignore.Pattern {
+	excludePatternsMu.RLock()
+	defer excludePatternsMu.RUnlock()
+	return excludePatterns
+}
+
+// ignoreFileIn returns the ignore file of dir, read at most once per
+// ignoreFileTTL
+func ignoreFileIn(dir string) ignoreFileEntry {
+	ignoreFilesMu.Lock()
+	defer ignoreFilesMu.Unlock()
+	if entry, ok := ignoreFiles[dir]; ok && time.Since(entry.loaded) < ignoreFileTTL {
+		return entry
+	}
+
+	entry := ignoreFileEntry{loaded: time.Now()}
+	f, err := os.Open(filepath.Join(dir, IgnoreFile))
+	if err == nil {
+		entry.found = true
+		entry.patterns, entry.err = ignore.Parse(f)
+		f.Close()
+	} else if !os.IsNotExist(err) {
+		entry.found, entry.err = true, err
+	}
+	ignoreFiles[dir] = entry
+	return entry
+}
+
+// ExcludedBy returns what keeps path, a folder when isDir, out of scans and
+// cleans: a scan.exclude pattern or an ignore file in a folder above it.
+// Returns "" when nothing does
+func ExcludedBy(path string, isDir bool) string {
+	path = filepath.Clean(path)
+	for _, p := range GetExcludePatterns() {
+		if p.Match(filepath.ToSlash(path), isDir) {
+			return fmt.Sprintf("scan.exclude %q", p.String())
+		}
+	}
+
+	dir := filepath.Dir(path)
+	if isDir {
+		// An empty ignore file excludes the folder holding it
+		if entry := ignoreFileIn(path); entry.found && len(entry.patterns) == 0 {
+			return ignoreFileReason(path, entry)
+		}
+	}
+	for ; ; dir = filepath.Dir(dir) {
+		if entry := ignoreFileIn(dir); entry.found {
+			if len(entry.patterns) == 0 {
+				return ignoreFileReason(dir, entry)
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err == nil {
+				for _, p := range entry.patterns {
+					if p.Match(filepath.ToSlash(rel), isDir) {
+						return fmt.Sprintf("%s (%q)", filepath.Join(dir, IgnoreFile), p.String())
+					}
+				}
+			}
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return ""
+		}
+	}
+}
+
+// ignoreFileReason names the ignore file in dir that excludes all of it
+func ignoreFileReason(dir string, entry ignoreFileEntry) string {
+	file := filepath.Join(dir, IgnoreFile)
+	if entry.err != nil {
+		return fmt.Sprintf("%s (unreadable, so the whole folder: %v)", file, entry.err)
+	}
+	return file
+}
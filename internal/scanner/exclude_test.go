@@ -0,0 +1,86 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/ignore"
+)
+
+func TestExclusionsNeverWalked(t *testing.T) {
+	patterns, err := ignore.CompileAll([]string{"**/Extras/**", "*.iso"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetExcludePatterns(patterns)
+	t.Cleanup(func() { SetExcludePatterns(nil) })
+
+	libRoot := t.TempDir()
+	files := []string{
+		"Heat (1995)/Heat (1995).mkv",
+		"Heat (1995)/Extras/Heat.1995.Behind.The.Scenes.mkv",
+		"Heat (1995)/Heat.1995.Disc.iso",
+		"Alien (1979)/Alien.1979.1080p.x264-GROUP.mkv",
+		"Alien (1979)/Alien.1979.2160p.REMUX-GROUP.mkv",
+		"Protected Show/Protected.Show.2020.1080p-GROUP.mkv",
+	}
+	for _, rel := range files {
+		path := filepath.Join(libRoot, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ignoreFiles := map[string]string{
+		"Alien (1979)":   "# keep the remux archive\n*REMUX*\n",
+		"Protected Show": "",
+	}
+	for dir, content := range ignoreFiles {
+		if err := os.WriteFile(filepath.Join(libRoot, dir, IgnoreFile), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := CollectLibraryStats(libRoot, "movies", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.FileCount != 2 {
+		t.Errorf("Expected only Heat and the Alien 1080p copy to be counted, got %d", stats.FileCount)
+	}
+
+	tests := []struct {
+		rel   string
+		isDir bool
+		want  string
+	}{
+		{"Heat (1995)/Heat (1995).mkv", false, ""},
+		{"Heat (1995)/Extras", true, `scan.exclude "**/Extras/**"`},
+		{"Heat (1995)/Heat.1995.Disc.iso", false, `scan.exclude "*.iso"`},
+		{"Alien (1979)/Alien.1979.2160p.REMUX-GROUP.mkv", false, `.jellysinkignore ("*REMUX*")`},
+		{"Alien (1979)/Alien.1979.1080p.x264-GROUP.mkv", false, ""},
+		{"Protected Show", true, filepath.Join("Protected Show", IgnoreFile)},
+		{"Protected Show/Protected.Show.2020.1080p-GROUP.mkv", false, filepath.Join("Protected Show", IgnoreFile)},
+	}
+	for _, tt := range tests {
+		got := ExcludedBy(filepath.Join(libRoot, tt.rel), tt.isDir)
+		if (tt.want == "") != (got == "") || !strings.HasSuffix(got, tt.want) {
+			t.Errorf("ExcludedBy(%s) = %q, want %q", tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestInvalidIgnoreFileExcludesFolder(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, IgnoreFile), []byte("[unclosed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got := ExcludedBy(filepath.Join(dir, "Movie (2020).mkv"), false)
+	if !strings.Contains(got, "unreadable") || !strings.Contains(got, "line 1") {
+		t.Errorf("Expected a broken ignore file to exclude its folder, got %q", got)
+	}
+}
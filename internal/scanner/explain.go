@@ -0,0 +1,259 @@
+package scanner
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// traceFunc receives intermediate parsing values for explain mode
+// A nil traceFunc is valid and discards everything
+type traceFunc func(label, value string)
+
+// step records a parsing step if tracing is enabled
+func (t traceFunc) step(label, value string) {
+	if t != nil {
+		t(label, value)
+	}
+}
+
+// ExplainStep is a single labelled step in an explanation
+type ExplainStep struct {
+	Label string
+	Value string
+}
+
+// Explanation describes why a finding was produced: the rule that fired,
+// the tokens/regexes it matched and the intermediate parsing steps
+type Explanation struct {
+	FindingID   string
+	Subject     string
+	Rule        string
+	Description string
+	Matches     []string
+	Steps       []ExplainStep
+}
+
+// ruleDescriptions maps rule identifiers to human-readable descriptions
+var ruleDescriptions = map[string]string{
+	RuleMovieReleaseGroupFolder: "Parent folder looks like a release name (resolution/codec/source markers, hyphen group suffix or dot separators)",
+	RuleMovieInLibraryRoot:      "Movie file sits directly in the library root instead of its own folder",
+	RuleMovieFolderMismatch:     "Folder name and filename disagree after cleaning",
+	RuleMovieYearFormat:         "Folder has a year but not in (YYYY) form",
+	RuleMovieTargetCollision:    "Another file already wants the same suggested target path",
+	RuleMovieTrailerName:        "Trailer isn't named <movie>-trailer, the form Jellyfin lists as an extra",
+	RuleMovieExtraFolder:        "Featurette, interview or other extra isn't in an extras folder (Featurettes, Behind The Scenes, ...)",
+	RuleTVSeasonFolder:          "Episode is not inside a 'Season ##' folder matching its S##E## tag",
+	RuleTVReleaseGroupFilename:  "Episode filename looks like a release name",
+	RuleTVTitleMismatch:         "Show folder title and filename title conflict",
+	RuleTVExtraFolder:           "Featurette, interview or other extra isn't in an extras folder of its show or season",
+	RuleTVSpecialEpisode:        "Special in a Specials folder has no S00E## tag, so Jellyfin doesn't file it under season 0",
+	RuleTVDateEpisode:           "Daily-show episode isn't named '<show> YYYY-MM-DD', the air-date form Jellyfin matches",
+	RuleTVShowFolderYear:        "Show folder has no '(Year)'; the year is the first-air year TVDB or TMDB lists for the series",
+	RuleAnimeEpisodeFolder:      "Anime episode is not in its 'Show (Year)' folder, or its season folder when tagged S##E##",
+	RuleAnimeReleaseTags:        "Anime episode filename carries release tags ([Group] prefix, CRC suffix, quality tags)",
+	RuleOrphanedSidecar:         "Subtitle, nfo or artwork file doesn't share its base name with any video in its folder",
+}
+
+// RuleDescription returns the description for a rule identifier
+func RuleDescription(rule string) string {
+	if desc, ok := ruleDescriptions[rule]; ok {
+		return desc
+	}
+	return "Unknown rule (report may predate rule tracking)"
+}
+
+// ExplainComplianceIssue reconstructs how a compliance issue was derived
+func ExplainComplianceIssue(issue ComplianceIssue) Explanation {
+	exp := Explanation{
+		FindingID:   issue.ID,
+		Subject:     issue.Path,
+		Rule:        issue.Rule,
+		Description: RuleDescription(issue.Rule),
+	}
+	if exp.Rule == "" {
+		exp.Rule = "unknown"
+	}
+
+	filename := filepath.Base(issue.Path)
+	parentDir := filepath.Base(filepath.Dir(issue.Path))
+	exp.Steps = append(exp.Steps,
+		ExplainStep{"severity", issue.EffectiveSeverity()},
+		ExplainStep{"filename", filename},
+		ExplainStep{"parent folder", parentDir},
+	)
+
+	switch {
+	case issue.Rule == RuleOrphanedSidecar:
+		for _, video := range folderVideos(filepath.Dir(issue.Path)) {
+			exp.Steps = append(exp.Steps, ExplainStep{"video in folder", filepath.Base(video)})
+		}
+		exp.Steps = append(exp.Steps, ExplainStep{"kept tags", sidecarTags(filename)})
+	case issue.Rule == RuleAnimeEpisodeFolder || issue.Rule == RuleAnimeReleaseTags:
+		ep, _ := ParseAnimeFilename(filename)
+		if ep.Group != "" {
+			exp.Matches = append(exp.Matches, fmt.Sprintf("group tag regex %s matched %q", animeGroupRegex.String(), "["+ep.Group+"]"))
+		}
+		if ep.CRC != "" {
+			exp.Matches = append(exp.Matches, fmt.Sprintf("CRC regex %s matched %q", animeCRCRegex.String(), ep.CRC))
+		}
+		episode := fmt.Sprintf("S%02dE%02d", ep.Season, ep.Episode)
+		if ep.Absolute {
+			episode = fmt.Sprintf("%d (absolute)", ep.Episode)
+		}
+		exp.Steps = append(exp.Steps,
+			ExplainStep{"episode", episode},
+			ExplainStep{"show title (filename)", ep.Show},
+		)
+	case issue.Type == "tv":
+		if season, episode, found := ExtractEpisodeInfo(filename); found {
+			regex := episodeSERegex
+			if !regex.MatchString(filename) {
+				regex = episodeXRegex
+			}
+			exp.Matches = append(exp.Matches, fmt.Sprintf("episode regex %s matched %q", regex.String(), regex.FindString(filename)))
+			exp.Steps = append(exp.Steps, ExplainStep{"episode", fmt.Sprintf("S%02dE%02d", season, episode)})
+		} else if date, found := ExtractAirDate(filename); found {
+			exp.Matches = append(exp.Matches, fmt.Sprintf("air date regex %s matched %q", airDateRegex.String(), airDateRegex.FindString(fileStem(filename))))
+			exp.Steps = append(exp.Steps, ExplainStep{"air date", date})
+		}
+		show, year := ExtractTVShowTitle(filename)
+		exp.Steps = append(exp.Steps, ExplainStep{"show title (filename)", show})
+		if year != "" {
+			exp.Steps = append(exp.Steps, ExplainStep{"show year (filename)", year})
+		}
+		for _, reason := range releaseGroupReasons(filename) {
+			exp.Matches = append(exp.Matches, "filename: "+reason)
+		}
+	default:
+		for _, reason := range releaseGroupReasons(parentDir) {
+			exp.Matches = append(exp.Matches, "folder: "+reason)
+		}
+		if yearParenRegex.MatchString(parentDir) {
+			exp.Matches = append(exp.Matches, fmt.Sprintf("year regex %s matched %q", yearParenRegex.String(), yearParenRegex.FindString(parentDir)))
+		}
+
+		source := parentDir
+		if issue.Rule == RuleMovieInLibraryRoot || issue.Rule == RuleMovieFolderMismatch && hasYear(filename) && !hasYearInParentheses(parentDir) {
+			source = filename
+		}
+		cleanMovieName(source, func(label, value string) {
+			exp.Steps = append(exp.Steps, ExplainStep{"clean: " + label, value})
+		})
+	}
+
+	exp.Matches = append(exp.Matches, matchedReleasePatterns(filename)...)
+	exp.Steps = append(exp.Steps,
+		ExplainStep{"suggested path", issue.SuggestedPath},
+		ExplainStep{"suggested action", issue.SuggestedAction},
+	)
+
+	return exp
+}
+
+// ExplainMovieDuplicate shows how the files in a movie group ended up together
+func ExplainMovieDuplicate(dup MovieDuplicate) Explanation {
+	exp := Explanation{
+		FindingID:   dup.ID,
+		Subject:     strings.TrimSpace(dup.NormalizedName + " " + dup.Year),
+		Rule:        "movie.duplicate",
+		Description: "Files share the same normalized title and year (group key name|year)",
+	}
+
+	scores := movieKeepScores(dup.Files)
+	for i, file := range dup.Files {
+		title := filepath.Base(filepath.Dir(file.Path))
+		exp.Steps = append(exp.Steps, ExplainStep{fmt.Sprintf("file %d", i+1), file.Path})
+		normalizeName(title, func(label, value string) {
+			exp.Steps = append(exp.Steps, ExplainStep{"  normalize: " + label, value})
+		})
+		exp.Steps = append(exp.Steps, ExplainStep{"  year", ExtractYear(title)})
+		if file.Probe != nil {
+			exp.Steps = append(exp.Steps, ExplainStep{"  probed", file.Probe.Summary()})
+		}
+		exp.Steps = append(exp.Steps, ExplainStep{"  keep score", fmt.Sprintf("%d", scores[i])})
+	}
+
+	exp.Steps = append(exp.Steps, ExplainStep{"group key", scopedGroupKey(dup.Within, dup.NormalizedName+"|"+dup.Year)})
+	exp.Steps = append(exp.Steps, ExplainStep{"scope", dup.Scope.Label()})
+	if len(dup.Files) > 0 {
+		exp.Steps = append(exp.Steps, ExplainStep{"keeper", dup.Files[0].Path + " (highest score)"})
+	}
+
+	return exp
+}
+
+// ExplainTVDuplicate shows how the files in an episode group ended up together
+func ExplainTVDuplicate(dup TVDuplicate) Explanation {
+	exp := Explanation{
+		FindingID:   dup.ID,
+		Subject:     dup.ShowName + " " + dup.EpisodeLabel(),
+		Rule:        "tv.duplicate",
+		Description: "Files share the same normalized show name and S##E## (group key show|S##E##)",
+	}
+
+	scores := tvKeepScores(dup.Files)
+	for i, file := range dup.Files {
+		filename := filepath.Base(file.Path)
+		exp.Steps = append(exp.Steps, ExplainStep{fmt.Sprintf("file %d", i+1), file.Path})
+		if episodeSERegex.MatchString(filename) {
+			exp.Steps = append(exp.Steps, ExplainStep{"  episode match", fmt.Sprintf("%s -> %q", episodeSERegex.String(), episodeSERegex.FindString(filename))})
+		} else if episodeXRegex.MatchString(filename) {
+			exp.Steps = append(exp.Steps, ExplainStep{"  episode match", fmt.Sprintf("%s -> %q", episodeXRegex.String(), episodeXRegex.FindString(filename))})
+		}
+		normalizeName(extractShowNameFromPath(file.Path), func(label, value string) {
+			exp.Steps = append(exp.Steps, ExplainStep{"  normalize: " + label, value})
+		})
+		if file.Probe != nil {
+			exp.Steps = append(exp.Steps, ExplainStep{"  probed", file.Probe.Summary()})
+		}
+		exp.Steps = append(exp.Steps, ExplainStep{"  keep score", fmt.Sprintf("%d", scores[i])})
+	}
+
+	exp.Steps = append(exp.Steps, ExplainStep{"group key", scopedGroupKey(dup.Within, tvEpisodeKey(dup.ShowName, dup.Season, dup.Episode, dup.Absolute))})
+	exp.Steps = append(exp.Steps, ExplainStep{"scope", dup.Scope.Label()})
+	if len(dup.Files) > 0 {
+		exp.Steps = append(exp.Steps, ExplainStep{"keeper", dup.Files[0].Path + " (highest score)"})
+	}
+
+	return exp
+}
+
+// matchedReleasePatterns lists the release-tag regexes that match a name
+func matchedReleasePatterns(name string) []string {
+	var matches []string
+	for _, re := range releasePatterns {
+		if token := re.FindString(name); token != "" {
+			matches = append(matches, fmt.Sprintf("release regex %s matched %q", re.String(), token))
+		}
+	}
+	return matches
+}
+
+// String renders the explanation as plain text
+func (e Explanation) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Finding:  %s\n", e.Subject))
+	if e.FindingID != "" {
+		sb.WriteString(fmt.Sprintf("ID:       %s\n", e.FindingID))
+	}
+	sb.WriteString(fmt.Sprintf("Rule:     %s\n", e.Rule))
+	sb.WriteString(fmt.Sprintf("          %s\n", e.Description))
+
+	if len(e.Matches) > 0 {
+		sb.WriteString("Matched:\n")
+		for _, m := range e.Matches {
+			sb.WriteString(fmt.Sprintf("  - %s\n", m))
+		}
+	}
+
+	if len(e.Steps) > 0 {
+		sb.WriteString("Steps:\n")
+		for _, step := range e.Steps {
+			sb.WriteString(fmt.Sprintf("  %-28s %s\n", step.Label+":", step.Value))
+		}
+	}
+
+	return sb.String()
+}
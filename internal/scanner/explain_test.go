@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCleanMovieNameTraceMatchesResult(t *testing.T) {
+	var steps []ExplainStep
+	got := cleanMovieName("The.Matrix.1999.1080p.BluRay.x264-GROUP", func(label, value string) {
+		steps = append(steps, ExplainStep{label, value})
+	})
+
+	if got != CleanMovieName("The.Matrix.1999.1080p.BluRay.x264-GROUP") {
+		t.Errorf("Traced clean returned %q, untraced returned %q", got, CleanMovieName("The.Matrix.1999.1080p.BluRay.x264-GROUP"))
+	}
+	if len(steps) == 0 {
+		t.Fatal("Expected trace steps to be recorded")
+	}
+	if steps[len(steps)-1].Value != got {
+		t.Errorf("Expected last trace step to equal result %q, got %q", got, steps[len(steps)-1].Value)
+	}
+}
+
+func TestExplainComplianceIssue(t *testing.T) {
+	libRoot := t.TempDir()
+	dir := filepath.Join(libRoot, "The.Matrix.1999.1080p.BluRay.x264-GROUP")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "The.Matrix.1999.1080p.BluRay.x264-GROUP.mkv")
+
+	issue := checkMovieCompliance(path, libRoot)
+	if issue == nil {
+		t.Fatal("Expected compliance issue for release-group folder")
+	}
+	if issue.Rule != RuleMovieReleaseGroupFolder {
+		t.Errorf("Expected rule %s, got %s", RuleMovieReleaseGroupFolder, issue.Rule)
+	}
+
+	exp := ExplainComplianceIssue(*issue)
+	if exp.Rule != RuleMovieReleaseGroupFolder {
+		t.Errorf("Expected explanation rule %s, got %s", RuleMovieReleaseGroupFolder, exp.Rule)
+	}
+	if len(exp.Matches) == 0 {
+		t.Error("Expected matched tokens in explanation")
+	}
+
+	text := exp.String()
+	for _, want := range []string{"Rule:", "Matched:", "1080P", "clean: "} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected explanation to contain %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestExplainLegacyIssue(t *testing.T) {
+	exp := ExplainComplianceIssue(ComplianceIssue{Path: "/movies/Film/Film.mkv", Type: "movie"})
+	if exp.Rule != "unknown" {
+		t.Errorf("Expected unknown rule for legacy issue, got %s", exp.Rule)
+	}
+}
+
+func TestExplainTVDuplicate(t *testing.T) {
+	dup := TVDuplicate{
+		ShowName: "lost",
+		Season:   1,
+		Episode:  2,
+		Files: []TVFile{
+			{Path: "/tv/Lost/Season 01/Lost S01E02.mkv"},
+			{Path: "/tv/Lost/Season 01/Lost.1x02.mkv"},
+		},
+	}
+
+	text := ExplainTVDuplicate(dup).String()
+	if !strings.Contains(text, "lost|S01E02") {
+		t.Errorf("Expected group key in explanation:\n%s", text)
+	}
+	if !strings.Contains(text, `"S01E02"`) || !strings.Contains(text, `"1x02"`) {
+		t.Errorf("Expected both episode regex matches in explanation:\n%s", text)
+	}
+}
@@ -0,0 +1,112 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Jellyfin lists the videos in a movie's, show's or season's extras folders
+// ("Featurettes", "Behind The Scenes", ...) or named with an extras suffix
+// ("-featurette") as extras. Extras are never copies of the feature or
+// junk; loose ones are moved into the folder for their kind
+
+// extraFolders are the extras folders Jellyfin knows, by lower-case name
+var extraFolders = map[string]string{
+	"behind the scenes": "Behind The Scenes",
+	"deleted scenes":    "Deleted Scenes",
+	"interviews":        "Interviews",
+	"scenes":            "Scenes",
+	"shorts":            "Shorts",
+	"featurettes":       "Featurettes",
+	"clips":             "Clips",
+	"other":             "Other",
+	"extras":            "Extras",
+}
+
+var (
+	// "Heat (1995)-featurette", "Heat (1995)-behindthescenes"
+	extraSuffixRegex = regexp.MustCompile(`(?i)-(behindthescenes|deleted|deletedscene|featurette|interview|scene|short|clip|other|extra)$`)
+
+	// extraMarkers map the names of loose extras to the folder they belong
+	// in, tried in order
+	extraMarkers = []struct {
+		pattern *regexp.Regexp
+		folder  string
+	}{
+		{regexp.MustCompile(`(?i)(^|[ ._-])(behind[ ._-]*the[ ._-]*scenes|making[ ._-]*of)([ ._-]|$)`), "Behind The Scenes"},
+		{regexp.MustCompile(`(?i)(^|[ ._-])deleted[ ._-]*scenes?([ ._-]|$)`), "Deleted Scenes"},
+		{regexp.MustCompile(`(?i)(^|[ ._-])interviews?([ ._-]|$)`), "Interviews"},
+		{regexp.MustCompile(`(?i)(^|[ ._-])featurettes?([ ._-]|$)`), "Featurettes"},
+		{regexp.MustCompile(`(?i)(^|[ ._-])(extras?|bonus)([ ._-]|$)`), "Extras"},
+	}
+)
+
+// isNamedExtra reports whether the video at path follows Jellyfin's extras
+// convention: an extras suffix, or a place in an extras folder
+func isNamedExtra(path string) bool {
+	_, inFolder := extraFolders[strings.ToLower(filepath.Base(filepath.Dir(path)))]
+	return inFolder || extraSuffixRegex.MatchString(fileStem(path))
+}
+
+// looseExtraFolder returns the extras folder a video named like an extra
+// belongs in, or "" when its name doesn't look like one. Only the part
+// after the episode tag or year is searched, so titles such as "Interview
+// with the Vampire (1994)" or "Extras S01E01" are not mistaken for extras
+func looseExtraFolder(path string) string {
+	stem := fileStem(path)
+	if loc := episodeSERegex.FindStringIndex(stem); loc != nil {
+		stem = stem[loc[1]:]
+	} else if year := ExtractYear(stem); year != "" {
+		stem = stem[strings.LastIndex(stem, year)+len(year):]
+	}
+	for _, marker := range extraMarkers {
+		if marker.pattern.MatchString(stem) {
+			return marker.folder
+		}
+	}
+	return ""
+}
+
+// isExtraFile reports whether the video at path is a trailer or other
+// extra, named by the convention or not. As with trailers, the size cap
+// keeps full-length features out
+func isExtraFile(path string, size int64) bool {
+	return isTrailerFile(path, size) || isNamedExtra(path) || (looseExtraFolder(path) != "" && size < maxSampleSize)
+}
+
+// checkStrayExtra suggests moving an extra that isn't in an extras folder
+// into the one for its kind, next to it, so Jellyfin lists it as an extra
+// instead of a movie or episode. As with trailers, extras in folders that
+// need fixing first, or loose in the library root, are left alone
+func checkStrayExtra(path, libRoot, mediaType string) *ComplianceIssue {
+	folder := looseExtraFolder(path)
+	dir := filepath.Dir(path)
+	if folder == "" || isNamedExtra(path) || dir == libRoot || isReleaseGroupFolder(filepath.Base(dir)) {
+		return nil
+	}
+	if mediaType == "movie" && !hasYearInParentheses(filepath.Base(dir)) {
+		return nil
+	}
+
+	rule := RuleMovieExtraFolder
+	if mediaType == "tv" {
+		rule = RuleTVExtraFolder
+	}
+	issue := &ComplianceIssue{
+		Path:            path,
+		Type:            mediaType,
+		Problem:         fmt.Sprintf("Extra not in a %s folder (Jellyfin doesn't list it as an extra)", folder),
+		Severity:        IssueSeverityInfo,
+		Rule:            rule,
+		SuggestedPath:   filepath.Join(dir, folder, filepath.Base(path)),
+		SuggestedAction: "reorganize",
+	}
+	if _, err := os.Lstat(issue.SuggestedPath); err == nil {
+		issue.Problem = fmt.Sprintf("Extra not in a %s folder (%s is taken)", folder, filepath.Join(folder, filepath.Base(path)))
+		issue.SuggestedAction = "manual_review"
+	}
+	return issue
+}
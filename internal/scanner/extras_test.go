@@ -0,0 +1,107 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsExtraFile(t *testing.T) {
+	mb := int64(1 << 20)
+	tests := []struct {
+		path   string
+		size   int64
+		folder string // extras folder for a loose extra
+		want   bool
+	}{
+		{"/movies/Heat (1995)/Featurettes/Shooting the Bank Scene.mkv", 900 * mb, "", true},
+		{"/movies/Heat (1995)/behind the scenes/Heat.mkv", 80 * mb, "", true},
+		{"/movies/Heat (1995)/Heat (1995)-featurette.mkv", 80 * mb, "", true},
+		{"/movies/Heat (1995)/Heat (1995)-trailer.mkv", 80 * mb, "", true},
+		{"/movies/Heat (1995)/Heat.1995.Making.Of.mkv", 80 * mb, "Behind The Scenes", true},
+		{"/movies/Heat (1995)/Heat.1995.Deleted.Scenes.mkv", 80 * mb, "Deleted Scenes", true},
+		{"/movies/Heat (1995)/Interview With Michael Mann.mkv", 80 * mb, "Interviews", true},
+		{"/movies/Heat (1995)/Heat.1995.Bonus.mkv", 80 * mb, "Extras", true},
+		{"/tv/Lost (2004)/Season 01/Lost.S01E01.Behind.The.Scenes.mkv", 80 * mb, "Behind The Scenes", true},
+		// Too large to be an extra
+		{"/movies/Heat (1995)/Heat.1995.Making.Of.mkv", 2 << 30, "Behind The Scenes", false},
+		// Titles before the year or episode tag don't count
+		{"/movies/Interview with the Vampire (1994)/Interview with the Vampire (1994).mkv", 80 * mb, "", false},
+		{"/movies/Extraction (2020)/Extraction (2020).mkv", 80 * mb, "", false},
+		{"/tv/Extras (2005)/Season 01/Extras (2005) S01E01.mkv", 80 * mb, "", false},
+		{"/movies/Heat (1995)/Heat (1995).mkv", 80 * mb, "", false},
+	}
+	for _, tt := range tests {
+		if got := looseExtraFolder(tt.path); got != tt.folder && !isNamedExtra(tt.path) && !isNamedTrailer(tt.path) {
+			t.Errorf("looseExtraFolder(%q) = %q, want %q", tt.path, got, tt.folder)
+		}
+		if got := isExtraFile(tt.path, tt.size); got != tt.want {
+			t.Errorf("isExtraFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestExtrasInLibraries(t *testing.T) {
+	root := t.TempDir()
+	movies, tv := filepath.Join(root, "movies"), filepath.Join(root, "tv")
+	write := func(rel string) string {
+		path := filepath.Join(root, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	write("movies/Heat (1995)/Heat (1995).mkv")
+	write("movies/Heat (1995)/Featurettes/Heat.1995.Featurette.mkv")
+	write("movies/Heat (1995)/Heat (1995)-interview.mkv")
+	loose := write("movies/Heat (1995)/Heat.1995.Making.Of.1080p.mkv")
+	write("movies/Alien (1979)/Alien (1979).mkv")
+	write("movies/Alien (1979)/Behind The Scenes/Alien (1979).mkv")
+	write("tv/Lost (2004)/Season 01/Lost (2004) S01E01.mkv")
+	looseEpisode := write("tv/Lost (2004)/Season 01/Lost.S01E01.Deleted.Scenes.mkv")
+
+	// Extras are not copies of the feature or the episode
+	duplicates, err := ScanMovies([]string{movies})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(duplicates) != 0 {
+		t.Errorf("Expected no movie duplicates, got %+v", duplicates)
+	}
+	tvDuplicates, err := ScanTVShows([]string{tv})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tvDuplicates) != 0 {
+		t.Errorf("Expected no episode duplicates, got %+v", tvDuplicates)
+	}
+
+	// Only the loose extras are moved, into the folder for their kind
+	movieIssues, err := ScanMovieCompliance([]string{movies})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tvIssues, err := ScanTVCompliance([]string{tv})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		loose:        filepath.Join(movies, "Heat (1995)", "Behind The Scenes", "Heat.1995.Making.Of.1080p.mkv"),
+		looseEpisode: filepath.Join(tv, "Lost (2004)", "Season 01", "Deleted Scenes", "Lost.S01E01.Deleted.Scenes.mkv"),
+	}
+	for _, issue := range append(movieIssues, tvIssues...) {
+		target, ok := want[issue.Path]
+		if !ok {
+			t.Errorf("Unexpected issue for %s: %s", issue.Path, issue.Problem)
+			continue
+		}
+		if issue.SuggestedPath != target || issue.SuggestedAction != "reorganize" {
+			t.Errorf("Expected %s moved to %s, got %s (%s)", issue.Path, target, issue.SuggestedPath, issue.SuggestedAction)
+		}
+		delete(want, issue.Path)
+	}
+	for path := range want {
+		t.Errorf("Expected an issue for %s", path)
+	}
+}
@@ -0,0 +1,144 @@
+package scanner
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// letterFolds covers letters that Unicode does not decompose into base + accent
+var letterFolds = strings.NewReplacer(
+	"ß", "ss", "æ", "ae", "Æ", "AE", "œ", "oe", "Œ", "OE",
+	"ø", "o", "Ø", "O", "ł", "l", "Ł", "L", "đ", "d", "Đ", "D",
+	"þ", "th", "Þ", "TH", "ı", "i",
+)
+
+// apostropheReplacer drops apostrophes so "Ocean's" and "Oceans" normalize alike
+var apostropheReplacer = strings.NewReplacer("'", "", "’", "", "‘", "", "ʼ", "", "`", "", "´", "")
+
+// FoldTitle strips diacritics and folds ligatures ("Océan" -> "Ocean",
+// "Æon" -> "AEon") so titles with and without accents compare equal
+func FoldTitle(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return letterFolds.Replace(norm.NFC.String(b.String()))
+}
+
+// SameTitle is the exact-title confirmation behind a normalized-name match.
+// Both titles must share a non-empty normalized key, and where their letters
+// differ one side must be unaccented: "Océan's Eleven" matches "Oceans Eleven",
+// but "Pokémon" and "Pokèmon" (or two titles that normalize to nothing) do not
+func SameTitle(a, b string) bool {
+	key := NormalizeName(a)
+	if key == "" || key != NormalizeName(b) {
+		return false
+	}
+
+	la, lb := []rune(titleLetters(a)), []rune(titleLetters(b))
+	if len(la) != len(lb) {
+		// Ligatures, roman numerals or word substitutions changed the length;
+		// the normalized keys already agree
+		return true
+	}
+	for i := range la {
+		if la[i] != lb[i] && la[i] >= utf8.RuneSelf && lb[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// titleLetters returns the lowercase letters of a title (accents kept),
+// ignoring release tags, the year, digits, punctuation and spacing
+func titleLetters(name string) string {
+	name = strings.ToLower(removeYear(StripReleaseGroup(name)))
+	var b strings.Builder
+	for _, r := range norm.NFC.String(name) {
+		if unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// titleGroupKey is the key a file is collected under until its duplicate
+// group is confirmed: the group key and the exact title. Titles that
+// normalize to nothing match nothing, so their files are kept apart by path
+func titleGroupKey(key, normalized, title, path string) string {
+	if normalized == "" {
+		title += "\x00" + path
+	}
+	return key + "\x00" + title
+}
+
+// confirmTitleGroups merges the groups collected under titleGroupKey into
+// duplicate groups. SameTitle is not transitive ("Pokémon" and "Pokèmon"
+// both match "Pokemon" but not each other), so the titles under one key are
+// taken in sorted order and each joins the first group whose every title it
+// matches, or opens a numbered sibling ("key#2"). Groups then come out the
+// same however the files were found, as the parallel scans need. merge
+// appends from's files to into
+func confirmTitleGroups[G any](collected map[string]*G, merge func(into, from *G)) map[string]*G {
+	titles := make(map[string][]string)
+	for collectedKey := range collected {
+		key, rest, _ := strings.Cut(collectedKey, "\x00")
+		titles[key] = append(titles[key], rest)
+	}
+
+	groups := make(map[string]*G, len(collected))
+	for key, rests := range titles {
+		sort.Strings(rests)
+		var members [][]string // titles of each confirmed group under key
+		for _, rest := range rests {
+			title, _, byPath := strings.Cut(rest, "\x00")
+			n := -1
+			if !byPath {
+				n = slices.IndexFunc(members, func(group []string) bool {
+					for _, other := range group {
+						if !SameTitle(other, title) {
+							return false
+						}
+					}
+					return true
+				})
+			}
+			if n < 0 {
+				members = append(members, nil)
+				n = len(members) - 1
+			}
+			members[n] = append(members[n], title)
+
+			confirmed := key
+			if n > 0 {
+				confirmed = fmt.Sprintf("%s#%d", key, n+1)
+			}
+			from := collected[key+"\x00"+rest]
+			if into, ok := groups[confirmed]; ok {
+				merge(into, from)
+			} else {
+				groups[confirmed] = from
+			}
+		}
+	}
+	return groups
+}
+
+// mergeMovieGroup adds from's copies to into
+func mergeMovieGroup(into, from *MovieDuplicate) {
+	into.Files = append(into.Files, from.Files...)
+}
+
+// mergeTVGroup adds from's copies to into
+func mergeTVGroup(into, from *TVDuplicate) {
+	into.Files = append(into.Files, from.Files...)
+}
@@ -0,0 +1,134 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFoldTitle(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Océan's Eleven", "Ocean's Eleven"},
+		{"Amélie", "Amelie"},
+		{"Pokémon", "Pokemon"},
+		{"Æon Flux", "AEon Flux"},
+		{"Die Straße", "Die Strasse"},
+		{"Søren", "Soren"},
+		{"Łódź", "Lodz"},
+		{"Crème Brûlée", "Creme Brulee"},
+		{"千と千尋の神隠し", "千と千尋の神隠し"},
+	}
+
+	for _, tt := range tests {
+		if got := FoldTitle(tt.input); got != tt.expected {
+			t.Errorf("FoldTitle(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+// Tricky titles: pairs that must group together and pairs that must not
+func TestSameTitleCorpus(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"Ocean's Eleven (2001)", "Oceans Eleven (2001)", true},
+		{"Ocean's Eleven (2001)", "Océan's Eleven (2001)", true},
+		{"Ocean’s Eleven", "Ocean's.Eleven.2001.1080p.BluRay.x264-GROUP", true},
+		{"Amélie (2001)", "Amelie.2001.720p.BluRay", true},
+		{"Pokémon Detective Pikachu", "Pokemon Detective Pikachu", true},
+		{"Æon Flux", "Aeon Flux", true},
+		{"Léon The Professional", "Leon the Professional", true},
+		{"Schindler's List", "Schindlers List", true},
+		{"Fast & Furious", "Fast and Furious", true},
+		{"Les Misérables (2012)", "Les.Miserables.2012.1080p", true},
+
+		{"Pokémon", "Pokèmon", false},
+		{"Ocean's Eleven", "Ocean's Twelve", false},
+		{"千と千尋の神隠し", "もののけ姫", false},
+		{"Три богатыря", "Ирония судьбы", false},
+		{"", "", false},
+		{"1080p BluRay x264", "720p WEB-DL", false},
+	}
+
+	for _, tt := range tests {
+		if got := SameTitle(tt.a, tt.b); got != tt.same {
+			t.Errorf("SameTitle(%q, %q) = %v, want %v (keys %q / %q)",
+				tt.a, tt.b, got, tt.same, NormalizeName(tt.a), NormalizeName(tt.b))
+		}
+	}
+}
+
+func TestNormalizeNameKeepsNonLatinTitles(t *testing.T) {
+	if got := NormalizeName("千と千尋の神隠し (2001)"); got != "千と千尋の神隠し" {
+		t.Errorf("NormalizeName dropped non-Latin title, got %q", got)
+	}
+	if got := NormalizeName("Océan's Eleven (2001)"); got != "oceans eleven" {
+		t.Errorf("NormalizeName(%q) = %q, want %q", "Océan's Eleven (2001)", got, "oceans eleven")
+	}
+}
+
+func TestScanMoviesGroupsAccentVariants(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := []string{
+		"Ocean's Eleven (2001)/Ocean's Eleven (2001).mkv",
+		"Oceans Eleven (2001)/Oceans Eleven (2001).mkv",
+		"Océan's Eleven (2001)/Océan's Eleven (2001).mkv",
+		"Pokémon (1998)/Pokémon (1998).mkv",
+		"Pokèmon (1998)/Pokèmon (1998).mkv",
+	}
+	for _, f := range files {
+		path := filepath.Join(tmpDir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	duplicates, err := ScanMovies([]string{tmpDir})
+	if err != nil {
+		t.Fatalf("ScanMovies failed: %v", err)
+	}
+	if len(duplicates) != 1 {
+		t.Fatalf("Expected 1 duplicate group, got %d: %+v", len(duplicates), duplicates)
+	}
+	if duplicates[0].NormalizedName != "oceans eleven" || len(duplicates[0].Files) != 3 {
+		t.Errorf("Expected 3 Ocean's Eleven variants grouped, got %+v", duplicates[0])
+	}
+
+	parallel, err := ScanMoviesParallel(t.Context(), []string{tmpDir}, DefaultParallelConfig())
+	if err != nil {
+		t.Fatalf("ScanMoviesParallel failed: %v", err)
+	}
+	if len(parallel) != 1 || len(parallel[0].Files) != 3 {
+		t.Errorf("Expected parallel scan to group the same way, got %+v", parallel)
+	}
+}
+
+func TestConfirmTitleGroupsIsOrderIndependent(t *testing.T) {
+	// "Pokemon" matches both accented spellings, which don't match each other
+	titles := []string{"Pokémon (1998)", "Pokèmon (1998)", "Pokemon (1998)"}
+	for run := 0; run < 20; run++ {
+		collected := make(map[string]*MovieDuplicate)
+		for i := range titles {
+			title := titles[(i+run)%len(titles)]
+			collected[titleGroupKey("pokemon|1998", "pokemon", title, "/movies/"+title+"/movie.mkv")] = &MovieDuplicate{
+				Files: []MovieFile{{Path: "/movies/" + title + "/movie.mkv"}},
+			}
+		}
+
+		groups := confirmTitleGroups(collected, mergeMovieGroup)
+		first, second := groups["pokemon|1998"], groups["pokemon|1998#2"]
+		if len(groups) != 2 || first == nil || second == nil || len(first.Files) != 2 || len(second.Files) != 1 {
+			t.Fatalf("Run %d: expected Pokemon and Pokèmon grouped with Pokémon apart, got %+v", run, groups)
+		}
+		if second.Files[0].Path != "/movies/Pokémon (1998)/movie.mkv" {
+			t.Fatalf("Run %d: expected Pokémon in the #2 group, got %+v", run, second.Files)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FreezeMarker is the file that freezes the folder holding it. Scans still
+// report findings below a frozen folder, but cleans, auto-cleans and batch
+// renames never delete, rename or move anything there
+const FreezeMarker = ".jellysink-freeze"
+
+var (
+	freezeTagged   func(path string) bool
+	freezeTaggedMu sync.RWMutex
+)
+
+// SetFreezeTagged sets the check for paths frozen by tag (nil: only marker
+// files freeze)
+func SetFreezeTagged(fn func(path string) bool) {
+	freezeTaggedMu.Lock()
+	defer freezeTaggedMu.Unlock()
+	freezeTagged = fn
+}
+
+// FreezeMarkerFor returns the marker file freezing path, found in path
+// itself or any folder above it, or ""
+func FreezeMarkerFor(path string) string {
+	for p := filepath.Clean(path); ; p = filepath.Dir(p) {
+		marker := filepath.Join(p, FreezeMarker)
+		if _, err := os.Lstat(marker); err == nil {
+			return marker
+		}
+		if parent := filepath.Dir(p); parent == p {
+			return ""
+		}
+	}
+}
+
+// FrozenBy returns why path is frozen ("frozen by <marker>" or "tagged
+// frozen"), or "" when it may be changed
+func FrozenBy(path string) string {
+	if marker := FreezeMarkerFor(path); marker != "" {
+		return "frozen by " + marker
+	}
+	freezeTaggedMu.RLock()
+	tagged := freezeTagged
+	freezeTaggedMu.RUnlock()
+	if tagged != nil && tagged(path) {
+		return "tagged frozen"
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFrozenBy(t *testing.T) {
+	tmpDir := t.TempDir()
+	frozen := filepath.Join(tmpDir, "Firefly (2002)")
+	tagged := filepath.Join(tmpDir, "Serenity (2005)")
+	os.MkdirAll(filepath.Join(frozen, "Season 01"), 0755)
+	os.MkdirAll(tagged, 0755)
+	os.WriteFile(filepath.Join(frozen, FreezeMarker), nil, 0644)
+
+	episode := filepath.Join(frozen, "Season 01", "Firefly S01E01.mkv")
+	if reason := FrozenBy(episode); reason != "frozen by "+filepath.Join(frozen, FreezeMarker) {
+		t.Errorf("FrozenBy(episode) = %q", reason)
+	}
+	if reason := FrozenBy(tagged); reason != "" {
+		t.Errorf("FrozenBy(untagged) = %q, want \"\"", reason)
+	}
+
+	SetFreezeTagged(func(path string) bool { return strings.HasPrefix(path, tagged) })
+	t.Cleanup(func() { SetFreezeTagged(nil) })
+	if reason := FrozenBy(filepath.Join(tagged, "serenity.mkv")); reason != "tagged frozen" {
+		t.Errorf("FrozenBy(tagged) = %q, want \"tagged frozen\"", reason)
+	}
+}
+
+func TestApplyManualTVRenameSkipsFrozenShows(t *testing.T) {
+	tmpDir := t.TempDir()
+	showFolder := filepath.Join(tmpDir, "Degrassi (2001)")
+	episode := filepath.Join(showFolder, "Season 01", "Degrassi S01E01.mkv")
+	os.MkdirAll(filepath.Dir(episode), 0755)
+	os.WriteFile(episode, []byte("test"), 0644)
+	os.WriteFile(filepath.Join(showFolder, FreezeMarker), nil, 0644)
+
+	results, err := ApplyManualTVRename(tmpDir, "Degrassi", "Degrassi The Next Generation", false)
+	if err != nil {
+		t.Fatalf("ApplyManualTVRename() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Success || !strings.Contains(results[0].Error, "frozen") {
+		t.Errorf("Expected one refused folder rename, got %+v", results)
+	}
+	if _, err := os.Stat(episode); err != nil {
+		t.Errorf("Expected the frozen show left alone: %v", err)
+	}
+}
@@ -151,7 +151,7 @@ func init() {
 
 	// Pre-compile commonly used regexes
 	collapseSpacesRegex = regexp.MustCompile(`\s+`)
-	removePunctRegex = regexp.MustCompile(`[^\w\s]`)
+	removePunctRegex = regexp.MustCompile(`[^\p{L}\p{N}\s]`)
 	yearParenRegex = regexp.MustCompile(`\((\d{4})\)`)
 	yearBracketRegex = regexp.MustCompile(`\[(\d{4})\]`)
 	yearDotRegex = regexp.MustCompile(`\.(\d{4})\.`)
@@ -185,14 +185,23 @@ func init() {
 // NormalizeName normalizes a media name for fuzzy matching
 // Handles case, punctuation, roman numerals, word substitutions
 func NormalizeName(name string) string {
+	return normalizeName(name, nil)
+}
+
+// normalizeName implements NormalizeName, reporting intermediate values to trace when set
+func normalizeName(name string, trace traceFunc) string {
+	trace.step("input", name)
+
 	// Strip release group info first (includes resolution)
 	name = StripReleaseGroup(name)
+	trace.step("strip release group", name)
 
 	// Remove year if present
 	name = removeYear(name)
+	trace.step("remove year", name)
 
-	// Lowercase
-	name = strings.ToLower(name)
+	// Lowercase and fold accents/ligatures ("Océan" -> "ocean")
+	name = FoldTitle(strings.ToLower(name))
 
 	// Roman numeral to number conversion
 	romanMap := map[string]string{
@@ -221,14 +230,18 @@ func NormalizeName(name string) string {
 	for old, new := range substitutions {
 		name = strings.ReplaceAll(name, old, new)
 	}
+	trace.step("lowercase + substitutions", name)
 
-	// Remove punctuation (keep only alphanumeric and spaces)
+	// Drop apostrophes, then remove other punctuation (keep only letters, digits and spaces)
+	name = apostropheReplacer.Replace(name)
 	name = removePunctRegex.ReplaceAllString(name, " ")
 
 	// Collapse multiple spaces
 	name = collapseSpacesRegex.ReplaceAllString(name, " ")
 
-	return strings.TrimSpace(name)
+	name = strings.TrimSpace(name)
+	trace.step("normalized", name)
+	return name
 }
 
 // ExtractYear extracts year from various formats
@@ -734,6 +747,13 @@ func IsGarbageTitle(title string) bool {
 // CleanMovieName converts release group folder to clean Jellyfin format
 // Example: "Movie.Name.2024.1080p.BluRay.x264-GROUP" -> "Movie Name (2024)"
 func CleanMovieName(name string) string {
+	return cleanMovieName(name, nil)
+}
+
+// cleanMovieName implements CleanMovieName, reporting intermediate values to trace when set
+func cleanMovieName(name string, trace traceFunc) string {
+	trace.step("input", name)
+
 	// Strip file extension FIRST (if present)
 	ext := strings.ToLower(filepath.Ext(name))
 	videoExts := []string{".mkv", ".mp4", ".avi", ".m4v", ".mov", ".wmv", ".flv", ".webm", ".mpg", ".mpeg"}
@@ -746,6 +766,7 @@ func CleanMovieName(name string) string {
 
 	// Extract year first (before any modifications)
 	year := ExtractYear(name)
+	trace.step("extracted year", year)
 
 	// If year exists, only keep the part of the string before the year.
 	// This removes resolution/codecs/release group tokens that come AFTER the year.
@@ -774,11 +795,13 @@ func CleanMovieName(name string) string {
 				}
 			}
 			name = strings.TrimSpace(name[:startIdx])
+			trace.step("truncate at year", name)
 		}
 	}
 
 	// Strip release group info (handles dots, resolution, codecs, etc.)
 	name = StripReleaseGroup(name)
+	trace.step("strip release group", name)
 
 	// Remove only the specific release year (preserves years in titles like "2049")
 	name = removeSpecificYear(name, year)
@@ -789,6 +812,7 @@ func CleanMovieName(name string) string {
 
 	// Strip orphaned release groups that weren't caught by patterns
 	name = stripOrphanedReleaseGroups(name)
+	trace.step("strip orphaned groups", name)
 
 	// Trim again after orphan removal (including trailing hyphens)
 	name = strings.TrimRight(name, "-")
@@ -796,6 +820,7 @@ func CleanMovieName(name string) string {
 
 	// Title case with custom handling for ordinals
 	name = titleCaseWithOrdinals(name)
+	trace.step("title case", name)
 
 	// Clean up any remaining double dots (can happen with abbreviations like "D.E.B.S..")
 	for strings.Contains(name, "..") {
@@ -805,8 +830,9 @@ func CleanMovieName(name string) string {
 
 	// Add year if found
 	if year != "" {
-		return name + " (" + year + ")"
+		name = name + " (" + year + ")"
 	}
+	trace.step("result", name)
 
 	return name
 }
@@ -815,6 +841,7 @@ func CleanMovieName(name string) string {
 // - Ordinal numbers (1st, 2nd, 25th)
 // - Abbreviations (U.S., R.I.P.D., D.E.B.S.)
 // - Uppercase acronyms (8MM, RIPD, USA)
+// Articles and short prepositions mid-title stay lowercase (see SetLowercaseWords)
 func titleCaseWithOrdinals(s string) string {
 	// Case-insensitive ordinal detection
 	ordinalRegex := regexp.MustCompile(`(?i)\b(\d+)(st|nd|rd|th)\b`)
@@ -890,7 +917,8 @@ func titleCaseWithOrdinals(s string) string {
 		s = strings.ReplaceAll(s, placeholder, orig)
 	}
 
-	return s
+	// Keep small words lowercase mid-title ("The Lord of the Rings")
+	return applyLowercaseExceptions(s)
 }
 
 // ExtractEpisodeInfo extracts S##E## from filename
@@ -18,7 +18,7 @@ func TestReleaseGroupRemoval(t *testing.T) {
 		{
 			name:     "Princess with 3Audio MA5 1",
 			input:    "The Princess And The Frog 2009 1080p BluRay x264 3Audio DTS-HD MA5 1",
-			expected: "The Princess And The Frog (2009)",
+			expected: "The Princess and the Frog (2009)",
 		},
 		{
 			name:     "Moon with RightSiZE",
@@ -34,19 +34,19 @@ func TestReleaseGroupRemoval(t *testing.T) {
 		{
 			name:     "Invasion Plus Commentary",
 			input:    "Invasion.of.the.Body.Snatchers.1956.DVDRip.Plus.Commentary.x264-MaG-Chamele0n.mkv",
-			expected: "Invasion Of The Body Snatchers (1956)",
+			expected: "Invasion of the Body Snatchers (1956)",
 		},
 		// Men at Work - hyphenated group and multi-hyphen group
 		{
 			name:     "Men At Work psychd-ml",
 			input:    "men.at.work.1990.720p.bluray.x264-psychd-ml.mkv",
-			expected: "Men At Work (1990)",
+			expected: "Men at Work (1990)",
 		},
 		// Idea of You - NORDiC should be stripped
 		{
 			name:     "Idea of You Nordic",
 			input:    "The.Idea.of.You.2024.NORDiC.1080p.WEB-DL.H.265.DDP5.1-CiNEMiX.mkv",
-			expected: "The Idea Of You (2024)",
+			expected: "The Idea of You (2024)",
 		},
 		// Vite Vendute - foreign filename, prefer parent
 		{
@@ -79,7 +79,7 @@ func TestReleaseGroupRemoval(t *testing.T) {
 		{
 			name:     "R.I.P.D. 2",
 			input:    "R.I.P.D.2.Rise.of.the.Damned.2022.BluRay.720p.DTS.x264-MTeam.mkv",
-			expected: "R.I.P.D. 2 Rise Of The Damned (2022)",
+			expected: "R.I.P.D. 2 Rise of the Damned (2022)",
 		},
 		{
 			name:     "Le Comte de Monte-Cristo",
@@ -100,7 +100,7 @@ func TestCleanMovieName(t *testing.T) {
 		{"The Matrix 1999 2160p UHD BluRay x265 HDR10", "The Matrix (1999)"},
 		{"Inception.2010.1080p.BluRay.x264.DTS-HD.MA.5.1", "Inception (2010)"},
 		{"21st Century", "21st Century"},
-		{"The Man Who Fell to Earth 1976 HEVC D3FiL3R (iso)", "The Man Who Fell To Earth (1976)"},
+		{"The Man Who Fell to Earth 1976 HEVC D3FiL3R (iso)", "The Man Who Fell to Earth (1976)"},
 		{"Blade.Runner.2049.2017.2160p.BluRay.REMUX.HEVC.DTS-HD.MA.TrueHD.7.1.Atmos-FGT", "Blade Runner 2049 (2017)"},
 		{"The.Matrix.1999.REMASTERED.1080p.BluRay.x265.10bit.HDR.DTS-X.7.1-YTS", "The Matrix (1999)"},
 	}
@@ -0,0 +1,131 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Torrent setups hardlink each download into the library, so one file can
+// have several paths. Deleting a path of a file with other links frees no
+// space; only deleting every link does
+
+// fileLink returns the "device:inode" identity and link count of a file with
+// more than one hard link, or "" and 0 for a file with a single link
+func fileLink(info os.FileInfo) (string, int) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || stat.Nlink < 2 {
+		return "", 0
+	}
+	return fmt.Sprintf("%d:%d", stat.Dev, stat.Ino), int(stat.Nlink)
+}
+
+// linkedCopy is the part of a duplicate copy the space accounting needs
+type linkedCopy struct {
+	size  int64
+	inode string
+	links int
+}
+
+// reclaimableSizes returns the bytes deleting each copy but the keeper
+// (index 0) frees. A copy that is a hardlink of the keeper frees nothing,
+// and nor do copies of a file with links outside the group. The links of
+// one file deleted together free its size once, counted on the first
+func reclaimableSizes(copies []linkedCopy) []int64 {
+	sizes := make([]int64, len(copies))
+	if len(copies) < 2 {
+		return sizes
+	}
+
+	deleted := make(map[string]int)
+	for _, c := range copies[1:] {
+		if c.inode != "" {
+			deleted[c.inode]++
+		}
+	}
+	counted := make(map[string]bool)
+	for i := 1; i < len(copies); i++ {
+		c := copies[i]
+		switch {
+		case c.inode == "":
+			sizes[i] = c.size
+		case c.inode == copies[0].inode || deleted[c.inode] < c.links || counted[c.inode]:
+			// Another link of the file stays
+		default:
+			sizes[i] = c.size
+			counted[c.inode] = true
+		}
+	}
+	return sizes
+}
+
+// sameLinks reports whether every copy is a hardlink of the keeper
+func sameLinks(copies []linkedCopy) bool {
+	if len(copies) < 2 || copies[0].inode == "" {
+		return false
+	}
+	for _, c := range copies[1:] {
+		if c.inode != copies[0].inode {
+			return false
+		}
+	}
+	return true
+}
+
+func (d MovieDuplicate) copies() []linkedCopy {
+	copies := make([]linkedCopy, len(d.Files))
+	for i, file := range d.Files {
+		copies[i] = linkedCopy{file.Size, file.Inode, file.Links}
+	}
+	return copies
+}
+
+func (d TVDuplicate) copies() []linkedCopy {
+	copies := make([]linkedCopy, len(d.Files))
+	for i, file := range d.Files {
+		copies[i] = linkedCopy{file.Size, file.Inode, file.Links}
+	}
+	return copies
+}
+
+// ReclaimableSizes returns the bytes deleting each file but the keeper
+// frees, by index into Files; hardlinks of files that stay free nothing
+func (d MovieDuplicate) ReclaimableSizes() []int64 {
+	return reclaimableSizes(d.copies())
+}
+
+// ReclaimableSizes returns the bytes deleting each file but the keeper
+// frees, by index into Files; hardlinks of files that stay free nothing
+func (d TVDuplicate) ReclaimableSizes() []int64 {
+	return reclaimableSizes(d.copies())
+}
+
+// Reclaimable returns the bytes deleting every file but the keeper frees
+func (d MovieDuplicate) Reclaimable() int64 {
+	return sumSizes(d.ReclaimableSizes())
+}
+
+// Reclaimable returns the bytes deleting every file but the keeper frees
+func (d TVDuplicate) Reclaimable() int64 {
+	return sumSizes(d.ReclaimableSizes())
+}
+
+// Hardlinked reports whether every file of the group is a hardlink of one
+// file, so cleaning it only removes paths
+func (d MovieDuplicate) Hardlinked() bool {
+	return sameLinks(d.copies())
+}
+
+// Hardlinked reports whether every file of the group is a hardlink of one
+// file, so cleaning it only removes paths
+func (d TVDuplicate) Hardlinked() bool {
+	return sameLinks(d.copies())
+}
+
+func sumSizes(sizes []int64) int64 {
+	var total int64
+	for _, size := range sizes {
+		total += size
+	}
+	return total
+}
@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReclaimableSizes(t *testing.T) {
+	tests := []struct {
+		name   string
+		copies []linkedCopy
+		want   []int64
+	}{
+		{"separate files", []linkedCopy{{10, "", 0}, {8, "", 0}, {6, "", 0}}, []int64{0, 8, 6}},
+		{"link of the keeper", []linkedCopy{{10, "1:5", 2}, {10, "1:5", 2}}, []int64{0, 0}},
+		{"links deleted together", []linkedCopy{{10, "", 0}, {8, "1:7", 2}, {8, "1:7", 2}}, []int64{0, 8, 0}},
+		{"link outside the group", []linkedCopy{{10, "", 0}, {8, "1:7", 2}}, []int64{0, 0}},
+	}
+	for _, tt := range tests {
+		if got := reclaimableSizes(tt.copies); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: reclaimableSizes() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHardlinkedDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	keeper := filepath.Join(dir, "Heat (1995)", "Heat (1995).mkv")
+	link := filepath.Join(dir, "Heat.1995.1080p", "Heat.1995.1080p.mkv")
+	copied := filepath.Join(dir, "Heat.1995.720p", "Heat.1995.720p.mkv")
+	for _, path := range []string{keeper, copied} {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte("heat"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.MkdirAll(filepath.Dir(link), 0755)
+	if err := os.Link(keeper, link); err != nil {
+		t.Skipf("hardlinks not supported: %v", err)
+	}
+
+	var dup MovieDuplicate
+	for _, path := range []string{keeper, link, copied} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dup.Files = append(dup.Files, parseMovieFile(path, info))
+	}
+	if dup.Files[0].Inode == "" || dup.Files[0].Inode != dup.Files[1].Inode || dup.Files[0].Links != 2 {
+		t.Fatalf("Expected the keeper and its link to share an inode, got %+v", dup.Files[:2])
+	}
+	if dup.Files[2].Inode != "" {
+		t.Errorf("Expected no inode for a file with one link, got %q", dup.Files[2].Inode)
+	}
+	if got := dup.Reclaimable(); got != 4 {
+		t.Errorf("Reclaimable() = %d, want only the separate copy's 4 bytes", got)
+	}
+	if dup.Hardlinked() {
+		t.Error("Expected a group with a separate copy not to be all hardlinks")
+	}
+	dup.Files = dup.Files[:2]
+	if !dup.Hardlinked() || dup.Reclaimable() != 0 {
+		t.Errorf("Expected a group of hardlinks to free nothing, got %d", dup.Reclaimable())
+	}
+}
@@ -0,0 +1,113 @@
+package scanner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// apiTransport is shared by every metadata API client; nil uses a clone of
+// http.DefaultTransport (which honours HTTP_PROXY/HTTPS_PROXY/NO_PROXY)
+var (
+	apiTransport   http.RoundTripper
+	apiTransportMu sync.RWMutex
+)
+
+// SetAPIHTTPOptions configures the proxy and extra CA bundle used by the
+// TVDB/OMDB/TMDB clients (api.proxy_url, api.ca_bundle). An empty proxyURL falls
+// back to the HTTP(S)_PROXY environment variables; caFile is a PEM bundle
+// added to the system roots, e.g. for a TLS-intercepting corporate proxy
+func SetAPIHTTPOptions(proxyURL, caFile string) error {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if proxyURL != "" {
+		u, err := ParseProxyURL(proxyURL)
+		if err != nil {
+			return err
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+
+	if caFile != "" {
+		pool, err := loadCABundle(caFile)
+		if err != nil {
+			return err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	apiTransportMu.Lock()
+	defer apiTransportMu.Unlock()
+	apiTransport = transport
+	return nil
+}
+
+// ParseProxyURL checks a proxy URL has an http, https or socks5 scheme and a host
+func ParseProxyURL(proxyURL string) (*url.URL, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", proxyURL, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https or socks5", proxyURL)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", proxyURL)
+	}
+	return u, nil
+}
+
+// loadCABundle returns the system roots plus the certificates in caFile
+func loadCABundle(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates found in CA bundle %s", caFile)
+	}
+	return pool, nil
+}
+
+// newAPIHTTPClient returns an HTTP client using the configured API transport
+func newAPIHTTPClient() *http.Client {
+	apiTransportMu.RLock()
+	defer apiTransportMu.RUnlock()
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: apiTransport,
+	}
+}
+
+// describeRequestError turns TLS failures into actionable messages; other
+// errors are returned unchanged
+func describeRequestError(err error) error {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidCert x509.CertificateInvalidError
+	var recordHeader tls.RecordHeaderError
+
+	switch {
+	case errors.As(err, &unknownAuthority):
+		return fmt.Errorf("TLS certificate signed by an unknown authority (behind a TLS-intercepting proxy? set api.ca_bundle): %w", err)
+	case errors.As(err, &hostnameErr):
+		return fmt.Errorf("TLS certificate is not valid for this host: %w", err)
+	case errors.As(err, &invalidCert):
+		return fmt.Errorf("TLS certificate is invalid or expired: %w", err)
+	case errors.As(err, &recordHeader):
+		return fmt.Errorf("TLS handshake failed, server did not speak TLS (check api.proxy_url scheme): %w", err)
+	}
+	return err
+}
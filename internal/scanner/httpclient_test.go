@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func omdbOKHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"Title":"Firefly","Year":"2002"}`)
+	})
+}
+
+func TestSetAPIHTTPOptionsRejectsBadInput(t *testing.T) {
+	defer SetAPIHTTPOptions("", "")
+
+	for _, proxy := range []string{"proxy.lan:3128", "ftp://proxy.lan", "http://"} {
+		if err := SetAPIHTTPOptions(proxy, ""); err == nil {
+			t.Errorf("Expected error for proxy %q", proxy)
+		}
+	}
+
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetAPIHTTPOptions("", notPEM); err == nil || !strings.Contains(err.Error(), "no PEM certificates") {
+		t.Errorf("Expected PEM error, got %v", err)
+	}
+	if err := SetAPIHTTPOptions("", filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+		t.Error("Expected error for missing CA bundle")
+	}
+}
+
+func TestAPIClientCustomCABundle(t *testing.T) {
+	defer SetAPIHTTPOptions("", "")
+
+	server := httptest.NewTLSServer(omdbOKHandler())
+	defer server.Close()
+
+	// Untrusted certificate: the error should say so and point at ca_bundle
+	client := NewOMDBClient("test-key")
+	client.BaseURL = server.URL
+	err := client.VerifyKey()
+	if err == nil || !strings.Contains(err.Error(), "unknown authority") || !strings.Contains(err.Error(), "api.ca_bundle") {
+		t.Fatalf("Expected descriptive TLS error, got %v", err)
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certPEM, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetAPIHTTPOptions("", bundle); err != nil {
+		t.Fatalf("SetAPIHTTPOptions failed: %v", err)
+	}
+
+	client = NewOMDBClient("test-key")
+	client.BaseURL = server.URL
+	if err := client.VerifyKey(); err != nil {
+		t.Errorf("Expected request to succeed with CA bundle, got %v", err)
+	}
+}
+
+func TestAPIClientProxyURL(t *testing.T) {
+	defer SetAPIHTTPOptions("", "")
+
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		omdbOKHandler().ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	if err := SetAPIHTTPOptions(proxy.URL, ""); err != nil {
+		t.Fatalf("SetAPIHTTPOptions failed: %v", err)
+	}
+
+	client := NewOMDBClient("test-key")
+	client.BaseURL = "http://omdb.example.invalid/"
+	if err := client.VerifyKey(); err != nil {
+		t.Fatalf("Expected request through proxy to succeed, got %v", err)
+	}
+	if proxiedHost != "omdb.example.invalid" {
+		t.Errorf("Expected proxy to receive request for omdb.example.invalid, got %q", proxiedHost)
+	}
+}
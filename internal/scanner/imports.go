@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// upgradeMarkerExts are the suffixes Sonarr and Radarr give the file an
+// upgrade replaces while they swap the new copy in
+var upgradeMarkerExts = map[string]bool{".backup~": true}
+
+// ImportInProgress returns a file Sonarr, Radarr or a download client is
+// still writing next to path, or below it when path is a folder, or "".
+// Partial files untouched for a day are abandoned rather than in progress
+func ImportInProgress(path string) string {
+	now := time.Now()
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return importingBelow(path, now)
+	}
+	return importingIn(filepath.Dir(path), now)
+}
+
+// importingIn returns the first in-progress file directly inside dir
+func importingIn(dir string, now time.Time) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && isImporting(entry.Name(), info, now) {
+			return filepath.Join(dir, entry.Name())
+		}
+	}
+	return ""
+}
+
+// importingBelow returns the first in-progress file anywhere below dir
+func importingBelow(dir string, now time.Time) string {
+	var found string
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && isImporting(d.Name(), info, now) {
+			found = path
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// isImporting reports whether name is a partial download, import copy or
+// upgrade backup that was written to recently
+func isImporting(name string, info os.FileInfo, now time.Time) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	if !partialDownloadExts[ext] && !upgradeMarkerExts[ext] {
+		return false
+	}
+	return now.Sub(info.ModTime()) < partialDownloadAge
+}
@@ -0,0 +1,45 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestImportInProgress(t *testing.T) {
+	tmpDir := t.TempDir()
+	movie := filepath.Join(tmpDir, "Heat (1995)")
+	season := filepath.Join(tmpDir, "Firefly (2002)", "Season 01")
+	stale := filepath.Join(tmpDir, "Alien (1979)")
+	for _, dir := range []string{movie, season, stale} {
+		os.MkdirAll(dir, 0755)
+	}
+
+	// Radarr copying an upgrade in, qBittorrent downloading into a season
+	importing := filepath.Join(movie, "Heat (1995) 2160p.mkv.partial~")
+	downloading := filepath.Join(season, "Firefly S01E02.mkv.!qB")
+	abandoned := filepath.Join(stale, "Alien (1979).mkv.partial~")
+	for _, path := range []string{importing, downloading, abandoned, filepath.Join(movie, "Heat (1995) 1080p.mkv")} {
+		os.WriteFile(path, []byte("video"), 0644)
+	}
+	old := time.Now().Add(-2 * partialDownloadAge)
+	os.Chtimes(abandoned, old, old)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{filepath.Join(movie, "Heat (1995) 1080p.mkv"), importing},
+		{movie, importing},
+		{filepath.Join(season, "Firefly S01E01.mkv"), downloading},
+		{filepath.Dir(season), downloading}, // show folder renames cover its seasons
+		{filepath.Join(stale, "Alien (1979).mkv"), ""},
+		{filepath.Join(tmpDir, "Missing (2000)", "missing.mkv"), ""},
+	}
+	for _, tt := range tests {
+		if got := ImportInProgress(tt.path); got != tt.want {
+			t.Errorf("ImportInProgress(%s) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,584 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/jsonstream"
+	"github.com/Nomadcxx/jellysink/internal/oplog"
+)
+
+// IndexFile is the file-state index incremental scans compare against, in
+// the data directory
+const IndexFile = "index.json"
+
+// indexVersion changes when what the index records changes; an index of
+// another version is rebuilt
+const indexVersion = 1
+
+// DefaultIndexPath returns where the scan index is kept between runs
+func DefaultIndexPath() string {
+	return filepath.Join(oplog.DataDir(), IndexFile)
+}
+
+// ScanIndex records every video file as of the last incremental scan, and
+// the report that scan wrote
+type ScanIndex struct {
+	Version  int
+	Settings string                 // fingerprint of the settings the report was made with
+	Report   string                 // report written by the scan that last updated the index
+	Files    map[string]*IndexEntry // video files by path
+}
+
+// IndexEntry is one video file in the scan index
+type IndexEntry struct {
+	Size    int64
+	ModTime time.Time
+	Group   string     `json:",omitempty"` // duplicate group the file is scanned into, e.g. "movie:heat|1995"
+	Probe   *MediaInfo `json:",omitempty"` // ffprobe metadata, reused while the file is unchanged
+}
+
+// NewScanIndex returns an empty scan index
+func NewScanIndex() *ScanIndex {
+	return &ScanIndex{Version: indexVersion, Files: make(map[string]*IndexEntry)}
+}
+
+// LoadScanIndex reads the scan index at path. A missing index, or one
+// written by another version, is empty
+func LoadScanIndex(path string) (*ScanIndex, error) {
+	var idx ScanIndex
+	if LowMemory() {
+		// Decoded file by file instead of reading the whole index first
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			return NewScanIndex(), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read scan index: %w", err)
+		}
+		defer f.Close()
+		if err := jsonstream.Decode(bufio.NewReader(f), &idx); err != nil {
+			return nil, fmt.Errorf("failed to parse scan index %s: %w", path, err)
+		}
+	} else {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			return NewScanIndex(), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read scan index: %w", err)
+		}
+		if err := json.Unmarshal(data, &idx); err != nil {
+			return nil, fmt.Errorf("failed to parse scan index %s: %w", path, err)
+		}
+	}
+	if idx.Version != indexVersion {
+		return NewScanIndex(), nil
+	}
+	for path, entry := range idx.Files {
+		if entry == nil {
+			delete(idx.Files, path)
+		}
+	}
+	if idx.Files == nil {
+		idx.Files = make(map[string]*IndexEntry)
+	}
+	return &idx, nil
+}
+
+// ScanIndexCurrent reports whether the index at path is in the format this
+// version writes. One in another format is dropped by the next incremental
+// scan, which then rescans every file; a missing index counts as current
+func ScanIndexCurrent(path string) (bool, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read scan index: %w", err)
+	}
+	defer f.Close()
+
+	var header struct{ Version int }
+	if err := json.NewDecoder(bufio.NewReader(f)).Decode(&header); err != nil {
+		return false, fmt.Errorf("failed to parse scan index %s: %w", path, err)
+	}
+	return header.Version == indexVersion, nil
+}
+
+// Reset empties the index and records settings, so the next incremental
+// scan rescans everything
+func (idx *ScanIndex) Reset(settings string) {
+	*idx = *NewScanIndex()
+	idx.Settings = settings
+}
+
+// Save writes the index to path, replacing it atomically
+func (idx *ScanIndex) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create scan index directory: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if LowMemory() {
+		// Encoded file by file straight to disk
+		f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to write scan index: %w", err)
+		}
+		err = jsonstream.Encode(f, idx, "")
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(tmpPath)
+			return fmt.Errorf("failed to write scan index: %w", err)
+		}
+	} else {
+		data, err := json.Marshal(idx)
+		if err != nil {
+			return fmt.Errorf("failed to marshal scan index: %w", err)
+		}
+		if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+			return fmt.Errorf("failed to write scan index: %w", err)
+		}
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace scan index: %w", err)
+	}
+	return nil
+}
+
+// RunIncrementalScan rescans only the top-level folders of each library
+// whose video files changed since idx was saved, and takes every other
+// finding from previous, the result of that scan. Without a previous
+// result everything is scanned. idx is updated in place; save it with the
+// report so the next incremental scan builds on both
+func RunIncrementalScan(ctx context.Context, moviePaths, tvPaths []string, idx *ScanIndex, previous *ScanResult, progressCh chan<- ScanProgress) (*ScanResult, error) {
+	var pr *ProgressReporter
+	if progressCh != nil {
+		pr = NewProgressReporter(progressCh, OpIndexing)
+		pr.StageUpdate("detecting", "Checking for changes since the last scan...")
+	}
+
+	inc, err := detectChanges(ctx, idx, moviePaths, tvPaths)
+	if err != nil {
+		return nil, err
+	}
+	if previous == nil {
+		inc.scope = nil
+		if pr != nil {
+			pr.Complete("No earlier incremental scan to build on, scanning everything")
+		}
+	} else {
+		inc.previous = previous
+		if pr != nil {
+			pr.Complete(fmt.Sprintf("%d new or changed and %d removed files, rescanning %d of %d folders",
+				inc.changed, inc.removed, len(inc.scope.dirty), inc.units))
+		}
+	}
+
+	setScanScope(inc.scope)
+	defer setScanScope(nil)
+
+	result, err := runScan(ctx, moviePaths, tvPaths, inc, nil, progressCh)
+	if err != nil {
+		return nil, err
+	}
+	inc.record(idx, result)
+	return result, nil
+}
+
+// incrementalScan is what an incremental scan knows before its stages run
+type incrementalScan struct {
+	scope     *scanScope             // folders to rescan; nil = everything
+	files     map[string]*IndexEntry // every video file found now
+	previous  *ScanResult            // findings for the folders not rescanned
+	artifacts bool                   // take artifacts still on disk from previous instead of searching again
+
+	changed, removed int // video files new or changed, and gone, since the index
+	units            int // top-level folders and loose files found
+}
+
+// detectChanges walks the libraries comparing every video file with idx,
+// and marks the top-level folders to rescan: those with new, changed or
+// removed files, those sharing a duplicate group with one, and TV folders
+// without video files (orphan candidates)
+func detectChanges(ctx context.Context, idx *ScanIndex, moviePaths, tvPaths []string) (*incrementalScan, error) {
+	inc := &incrementalScan{files: make(map[string]*IndexEntry)}
+	scope := &scanScope{roots: make(map[string]bool), dirty: make(map[string]bool), videoRoots: make(map[string]bool)}
+	units := make(map[string]bool)
+	hasVideos := make(map[string]bool) // TV folders -> whether they hold video files
+	dirtyGroups := make(map[string]bool)
+
+	walk := func(root string, tv bool) error {
+		// Inaccessible libraries are left to the scan stages to report
+		if _, err := os.Stat(root); err != nil {
+			return nil
+		}
+		scope.roots[root] = true
+		return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if skip, skipErr := walkSkip(root, path, info); skip {
+				return skipErr
+			}
+			unit := scopeUnit(root, path)
+			if unit == "" {
+				return nil
+			}
+			units[unit] = true
+			if info.IsDir() {
+				if tv && !hasVideos[unit] {
+					hasVideos[unit] = false
+				}
+				return nil
+			}
+			if !isVideoFile(path) {
+				return nil
+			}
+			hasVideos[unit] = true
+
+			entry := &IndexEntry{Size: info.Size(), ModTime: info.ModTime(), Group: indexGroup(root, path, tv)}
+			if old := idx.Files[path]; old != nil && old.Size == entry.Size && old.ModTime.Equal(entry.ModTime) {
+				entry.Probe = old.Probe
+			} else {
+				inc.changed++
+				scope.markVideo(unit)
+				if entry.Group != "" {
+					dirtyGroups[entry.Group] = true
+				}
+			}
+			inc.files[path] = entry
+			return nil
+		})
+	}
+	for _, root := range moviePaths {
+		if err := walk(root, false); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("error checking %s for changes: %w", root, err)
+		}
+	}
+	for _, root := range tvPaths {
+		if err := walk(root, true); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("error checking %s for changes: %w", root, err)
+		}
+	}
+
+	for path, old := range idx.Files {
+		if inc.files[path] != nil {
+			continue
+		}
+		inc.removed++
+		if unit := scope.unit(path); unit != "" {
+			scope.markVideo(unit)
+		}
+		if old.Group != "" {
+			dirtyGroups[old.Group] = true
+		}
+	}
+	// A changed copy regroups every copy of the title, wherever it is
+	for path, entry := range inc.files {
+		if entry.Group != "" && dirtyGroups[entry.Group] {
+			scope.markVideo(scope.unit(path))
+		}
+	}
+	for unit, videos := range hasVideos {
+		if !videos {
+			scope.dirty[unit] = true
+		}
+	}
+
+	inc.scope = scope
+	inc.units = len(units)
+	return inc, nil
+}
+
+// indexGroup returns the duplicate group a video file is scanned into,
+// before title confirmation may split it, or "" when it is not grouped
+func indexGroup(root, path string, tv bool) string {
+	if tv && isAnimeLibrary(root) {
+		ep, ok := ParseAnimeFilename(filepath.Base(path))
+		if !ok {
+			return ""
+		}
+		show, _ := animeLocalTitle(root, path, ep)
+		return "tv:" + NormalizeName(show)
+	}
+	if tv {
+		if _, _, found := ExtractEpisodeInfo(filepath.Base(path)); !found {
+			return ""
+		}
+		return "tv:" + NormalizeName(extractShowNameFromPath(path))
+	}
+	title := movieGroupTitle(root, path)
+	return "movie:" + NormalizeName(title) + "|" + ExtractYear(title)
+}
+
+// paths returns the libraries the duplicate and compliance stages scan:
+// those with video files to rescan, and those the index could not read
+func (inc *incrementalScan) paths(roots []string) []string {
+	if inc == nil || inc.scope == nil {
+		return roots
+	}
+	var paths []string
+	for _, root := range roots {
+		if inc.scope.videoRoots[root] || !inc.scope.roots[root] {
+			paths = append(paths, root)
+		}
+	}
+	return paths
+}
+
+// orphanPaths returns the TV libraries the orphaned folder stage scans
+func (inc *incrementalScan) orphanPaths(roots []string) []string {
+	if inc == nil || inc.scope == nil {
+		return roots
+	}
+	var paths []string
+	for _, root := range roots {
+		if inc.scope.rootDirty(root) || !inc.scope.roots[root] {
+			paths = append(paths, root)
+		}
+	}
+	return paths
+}
+
+// reuseMovieProbes fills in the ffprobe metadata the index kept for
+// unchanged files, so only new and changed files are probed
+func (inc *incrementalScan) reuseMovieProbes(duplicates []MovieDuplicate) {
+	if inc == nil {
+		return
+	}
+	for i := range duplicates {
+		for j := range duplicates[i].Files {
+			file := &duplicates[i].Files[j]
+			if entry := inc.files[file.Path]; entry != nil && file.Probe == nil {
+				file.Probe = entry.Probe
+			}
+		}
+	}
+}
+
+// reuseTVProbes is reuseMovieProbes for episode groups
+func (inc *incrementalScan) reuseTVProbes(duplicates []TVDuplicate) {
+	if inc == nil {
+		return
+	}
+	for i := range duplicates {
+		for j := range duplicates[i].Files {
+			file := &duplicates[i].Files[j]
+			if entry := inc.files[file.Path]; entry != nil && file.Probe == nil {
+				file.Probe = entry.Probe
+			}
+		}
+	}
+}
+
+// knownProbe returns the ffprobe metadata the index kept for an unchanged
+// file, or nil
+func (inc *incrementalScan) knownProbe(path string) *MediaInfo {
+	if inc == nil {
+		return nil
+	}
+	if entry := inc.files[path]; entry != nil {
+		return entry.Probe
+	}
+	return nil
+}
+
+// keepProbes records ffprobe metadata in the index entries of its files
+func (inc *incrementalScan) keepProbes(probed map[string]*MediaInfo) {
+	if inc == nil {
+		return
+	}
+	for path, info := range probed {
+		if entry := inc.files[path]; entry != nil {
+			entry.Probe = info
+		}
+	}
+}
+
+// merge adds the previous result's findings for the folders that were not
+// rescanned. Duplicate groups never straddle the two: a changed copy has
+// every folder of its group rescanned
+func (inc *incrementalScan) merge(result *ScanResult) {
+	if inc == nil || inc.scope == nil || inc.previous == nil {
+		return
+	}
+	keeps := inc.scope.keeps
+
+	for _, dup := range inc.previous.MovieDuplicates {
+		kept := len(dup.Files) > 0
+		for _, file := range dup.Files {
+			kept = kept && keeps(file.Path)
+		}
+		if kept {
+			result.MovieDuplicates = append(result.MovieDuplicates, dup)
+		}
+	}
+	for _, dup := range inc.previous.TVDuplicates {
+		kept := len(dup.Files) > 0
+		for _, file := range dup.Files {
+			kept = kept && keeps(file.Path)
+		}
+		if kept {
+			result.TVDuplicates = append(result.TVDuplicates, dup)
+		}
+	}
+	for _, issue := range inc.previous.ComplianceIssues {
+		if keeps(issue.Path) {
+			result.ComplianceIssues = append(result.ComplianceIssues, issue)
+		}
+	}
+	for _, show := range inc.previous.AmbiguousTVShows {
+		if show != nil && keeps(show.FolderPath) {
+			result.AmbiguousTVShows = append(result.AmbiguousTVShows, show)
+		}
+	}
+	for _, movie := range inc.previous.AmbiguousMovies {
+		if movie != nil && keeps(movie.Path) {
+			result.AmbiguousMovies = append(result.AmbiguousMovies, movie)
+		}
+	}
+	for _, orphan := range inc.previous.OrphanFolders {
+		if keeps(orphan.Path) {
+			result.OrphanFolders = append(result.OrphanFolders, orphan)
+		}
+	}
+	for _, finding := range inc.previous.Quality {
+		if keeps(finding.Path) {
+			result.Quality = append(result.Quality, finding)
+		}
+	}
+	for _, show := range inc.previous.Completeness {
+		if keeps(show.Path) {
+			result.Completeness = append(result.Completeness, show)
+		}
+	}
+	if inc.artifacts {
+		for _, artifact := range inc.previous.Artifacts {
+			if _, err := os.Lstat(artifact.Path); err == nil {
+				result.Artifacts = append(result.Artifacts, artifact)
+			}
+		}
+	}
+}
+
+// record updates idx with the files found and the ffprobe metadata of the
+// duplicates in result
+func (inc *incrementalScan) record(idx *ScanIndex, result *ScanResult) {
+	for _, dup := range result.MovieDuplicates {
+		for _, file := range dup.Files {
+			if entry := inc.files[file.Path]; entry != nil && file.Probe != nil {
+				entry.Probe = file.Probe
+			}
+		}
+	}
+	for _, dup := range result.TVDuplicates {
+		for _, file := range dup.Files {
+			if entry := inc.files[file.Path]; entry != nil && file.Probe != nil {
+				entry.Probe = file.Probe
+			}
+		}
+	}
+	idx.Version = indexVersion
+	idx.Files = inc.files
+}
+
+// scanScope is the part of each library an incremental scan rescans: its
+// "units" are a library's top-level folders and loose files
+type scanScope struct {
+	roots      map[string]bool // libraries the scope applies to
+	dirty      map[string]bool // units to rescan
+	videoRoots map[string]bool // libraries with units whose video files changed
+}
+
+// Scope of the running incremental scan; nil = scans see everything
+var (
+	activeScope   *scanScope
+	activeScopeMu sync.RWMutex
+)
+
+// setScanScope limits walks to scope until it is set back to nil
+func setScanScope(scope *scanScope) {
+	activeScopeMu.Lock()
+	defer activeScopeMu.Unlock()
+	activeScope = scope
+}
+
+// outOfScope reports whether path, in the library root, is left out of the
+// running incremental scan
+func outOfScope(root, path string) bool {
+	activeScopeMu.RLock()
+	scope := activeScope
+	activeScopeMu.RUnlock()
+	if scope == nil || !scope.roots[root] {
+		return false
+	}
+	unit := scopeUnit(root, path)
+	return unit != "" && !scope.dirty[unit]
+}
+
+// scopeUnit returns the top-level folder or loose file of root that path
+// is in, or "" for root itself and paths outside it
+func scopeUnit(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	first, _, _ := strings.Cut(rel, string(filepath.Separator))
+	return filepath.Join(root, first)
+}
+
+// unit returns the unit path is in, in whichever library holds it
+func (s *scanScope) unit(path string) string {
+	for root := range s.roots {
+		if unit := scopeUnit(root, path); unit != "" {
+			return unit
+		}
+	}
+	return ""
+}
+
+// markVideo marks a unit whose video files changed for rescanning
+func (s *scanScope) markVideo(unit string) {
+	if unit == "" {
+		return
+	}
+	s.dirty[unit] = true
+	s.videoRoots[filepath.Dir(unit)] = true
+}
+
+// rootDirty reports whether any unit of root is rescanned
+func (s *scanScope) rootDirty(root string) bool {
+	for unit := range s.dirty {
+		if filepath.Dir(unit) == root {
+			return true
+		}
+	}
+	return false
+}
+
+// keeps reports whether a previous finding at path still stands: it is in
+// a library being scanned, in a unit that was not rescanned
+func (s *scanScope) keeps(path string) bool {
+	unit := s.unit(path)
+	return unit != "" && !s.dirty[unit]
+}
@@ -0,0 +1,135 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIncrementalScanRescansOnlyChangedFolders(t *testing.T) {
+	root := t.TempDir()
+	movies, tv := filepath.Join(root, "movies"), filepath.Join(root, "tv")
+	write := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("movies/Heat (1995)/Heat (1995).mkv", "keeper")
+	write("movies/Heat.1995.1080p-GRP/Heat.1995.1080p-GRP.mkv", "copy")
+	write("movies/Alien (1979)/Alien (1979).mkv", "alien")
+	write("tv/Lost (2004)/Season 01/Lost (2004) S01E01.mkv", "pilot")
+	write("tv/Firefly (2002)/tvshow.nfo", "nfo")
+
+	fakeFFprobe(t, map[string]string{
+		"Heat (1995).mkv":         probeJSON("hevc", 1920, 1080, 9_000_000, 6, 10200),
+		"Heat.1995.1080p-GRP.mkv": probeJSON("h264", 1920, 1080, 4_000_000, 2, 10200),
+	})
+	var probed []string
+	inner := runFFprobe
+	runFFprobe = func(ctx context.Context, binary, path string) ([]byte, error) {
+		probed = append(probed, filepath.Base(path))
+		return inner(ctx, binary, path)
+	}
+	t.Cleanup(func() { runFFprobe = inner })
+
+	ctx := context.Background()
+	idx := NewScanIndex()
+	scan := func(previous *ScanResult) *ScanResult {
+		t.Helper()
+		probed = nil
+		result, err := RunIncrementalScan(ctx, []string{movies}, []string{tv}, idx, previous, nil)
+		if err != nil {
+			t.Fatalf("RunIncrementalScan() failed: %v", err)
+		}
+		return result
+	}
+	issuePaths := func(result *ScanResult) map[string]bool {
+		paths := make(map[string]bool)
+		for _, issue := range result.ComplianceIssues {
+			paths[issue.Path] = true
+		}
+		return paths
+	}
+
+	// Without a previous scan everything is scanned, like a full scan
+	first := scan(nil)
+	full, err := RunFullScan(ctx, []string{movies}, []string{tv}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.MovieDuplicates) != 1 || len(first.ComplianceIssues) != len(full.ComplianceIssues) || len(first.OrphanFolders) != 1 {
+		t.Fatalf("Expected the first incremental scan to match a full scan, got %+v", first)
+	}
+	if len(idx.Files) != 4 {
+		t.Errorf("Expected every video file indexed, got %d", len(idx.Files))
+	}
+
+	// Nothing changed: the previous findings stand and nothing is probed
+	second := scan(first)
+	if len(probed) != 0 {
+		t.Errorf("Expected no probes without changes, got %v", probed)
+	}
+	if len(second.MovieDuplicates) != 1 || second.MovieDuplicates[0].ID != first.MovieDuplicates[0].ID ||
+		len(second.ComplianceIssues) != len(first.ComplianceIssues) || len(second.OrphanFolders) != 1 {
+		t.Errorf("Expected the previous findings unchanged, got %+v", second)
+	}
+
+	// A new release folder is checked; the untouched folders are not walked,
+	// so a finding planted in one of them is carried over
+	write("movies/Big.Fish.2003-GRP/Big.Fish.2003-GRP.mkv", "new")
+	planted := ComplianceIssue{Path: filepath.Join(movies, "Alien (1979)", "Alien (1979).mkv"), Type: "movie", Problem: "planted"}
+	second.ComplianceIssues = append(second.ComplianceIssues, planted)
+	third := scan(second)
+	paths := issuePaths(third)
+	if !paths[filepath.Join(movies, "Big.Fish.2003-GRP", "Big.Fish.2003-GRP.mkv")] {
+		t.Error("Expected the new release folder flagged")
+	}
+	if !paths[planted.Path] {
+		t.Error("Expected the unchanged folder taken from the previous scan")
+	}
+	if len(third.MovieDuplicates) != 1 || len(probed) != 0 {
+		t.Errorf("Expected the Heat duplicates kept without probing, got %d groups and probes %v", len(third.MovieDuplicates), probed)
+	}
+
+	// Changing one Heat copy rescans both, probing only the changed one,
+	// and a removed movie takes its findings with it
+	future := time.Now().Add(time.Hour)
+	write("movies/Heat (1995)/Heat (1995).mkv", "a longer keeper")
+	os.Chtimes(filepath.Join(movies, "Heat (1995)", "Heat (1995).mkv"), future, future)
+	os.RemoveAll(filepath.Join(movies, "Alien (1979)"))
+	fourth := scan(third)
+	if len(probed) != 1 || probed[0] != "Heat (1995).mkv" {
+		t.Errorf("Expected only the changed copy probed, got %v", probed)
+	}
+	if len(fourth.MovieDuplicates) != 1 || fourth.MovieDuplicates[0].Files[1].Probe == nil {
+		t.Errorf("Expected the regrouped duplicates with both probes, got %+v", fourth.MovieDuplicates)
+	}
+	if issuePaths(fourth)[planted.Path] {
+		t.Error("Expected the removed movie's finding dropped")
+	}
+	if outOfScope(movies, planted.Path) {
+		t.Error("Expected the scan scope cleared after the scan")
+	}
+
+	// The index survives a round trip
+	indexPath := filepath.Join(t.TempDir(), IndexFile)
+	idx.Report = "/reports/latest.json"
+	if err := idx.Save(indexPath); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadScanIndex(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Report != idx.Report || len(loaded.Files) != len(idx.Files) {
+		t.Errorf("Expected the saved index reloaded, got %+v", loaded)
+	}
+	heat := loaded.Files[filepath.Join(movies, "Heat.1995.1080p-GRP", "Heat.1995.1080p-GRP.mkv")]
+	if heat == nil || heat.Probe == nil || heat.Group != "movie:heat|1995" {
+		t.Errorf("Expected the probe and group kept, got %+v", heat)
+	}
+}
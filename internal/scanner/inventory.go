@@ -0,0 +1,134 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// inventory is what one walk of the libraries found: the video and sidecar
+// files that pass the scan exclusions, library by library in walk order.
+// The duplicate and compliance passes read it instead of each counting and
+// walking the trees again
+type inventory struct {
+	libraries map[string]*libraryInventory
+}
+
+// libraryInventory is one walked library root
+type libraryInventory struct {
+	err    error // the root could not be read, so nothing below it was walked
+	files  []inventoryFile
+	videos int
+}
+
+// inventoryFile is a video or sidecar file the walk found
+type inventoryFile struct {
+	path string
+	info os.FileInfo
+}
+
+// takeInventory walks each of paths once, the libraries side by side. A
+// root that cannot be read is recorded for the passes to report; an error
+// below it ends the walk
+func takeInventory(ctx context.Context, paths []string, progressCh chan<- ScanProgress) (*inventory, error) {
+	var pr *ProgressReporter
+	if progressCh != nil {
+		pr = NewProgressReporterWithInterval(progressCh, OpWalkingLibraries, 200*time.Millisecond)
+		pr.StageUpdate("walking", fmt.Sprintf("Walking %d library path(s)...", len(paths)))
+	}
+
+	inv := &inventory{libraries: make(map[string]*libraryInventory, len(paths))}
+	for _, path := range paths {
+		inv.libraries[path] = &libraryInventory{}
+	}
+
+	var found int64
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var walkErr error
+	sem := make(chan struct{}, defaultWorkers())
+	for path, lib := range inv.libraries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if err := lib.walk(ctx, path, &found, pr); err != nil {
+				errOnce.Do(func() { walkErr = err })
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Cancellation takes precedence over wrapped walk errors
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	if pr != nil {
+		pr.Complete(fmt.Sprintf("Found %d video files", found))
+	}
+	return inv, nil
+}
+
+// walk fills lib with the video and sidecar files below root
+func (lib *libraryInventory) walk(ctx context.Context, root string, found *int64, pr *ProgressReporter) error {
+	if _, err := os.Stat(root); err != nil {
+		lib.err = err
+		return nil
+	}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err != nil {
+			if pr != nil {
+				pr.LogError(err, fmt.Sprintf("Error accessing path during walk: %s", path))
+			}
+			return err
+		}
+		if skip, skipErr := walkSkip(root, path, info); skip || info.IsDir() {
+			return skipErr
+		}
+
+		switch {
+		case isVideoFile(path):
+			lib.videos++
+			if n := atomic.AddInt64(found, 1); pr != nil && n%500 == 0 {
+				pr.Send(SeverityInfo, fmt.Sprintf("Walking libraries... (%d video files found so far)", n))
+			}
+		case !isSidecarFile(path):
+			return nil
+		}
+		lib.files = append(lib.files, inventoryFile{path: path, info: info})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error scanning %s: %w", root, err)
+	}
+	return nil
+}
+
+// library returns what the walk found below root. Roots that were not
+// walked read as inaccessible
+func (inv *inventory) library(root string) *libraryInventory {
+	if lib, ok := inv.libraries[root]; ok {
+		return lib
+	}
+	return &libraryInventory{err: fmt.Errorf("%s: %w", root, os.ErrNotExist)}
+}
+
+// videos counts the video files below the readable roots among paths
+func (inv *inventory) videos(paths []string) int {
+	total := 0
+	for _, path := range paths {
+		total += inv.library(path).videos
+	}
+	return total
+}
@@ -0,0 +1,128 @@
+package scanner
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Inventory export formats
+const (
+	InventoryCSV   = "csv"
+	InventoryJSONL = "jsonl"
+)
+
+// InventoryRecord is one video file of the libraries as a scan reads it
+type InventoryRecord struct {
+	Kind       string    `json:"kind"`    // movie or episode
+	Library    string    `json:"library"` // the library path the file was found under
+	Path       string    `json:"path"`
+	Size       int64     `json:"size"`
+	ModTime    time.Time `json:"mtime"`
+	Title      string    `json:"title"` // the movie or show title the duplicate check groups by
+	Year       string    `json:"year,omitempty"`
+	Season     int       `json:"season,omitempty"`
+	Episode    int       `json:"episode,omitempty"` // numbered across the series when Absolute
+	Absolute   bool      `json:"absolute,omitempty"`
+	Resolution string    `json:"resolution,omitempty"` // from the filename
+}
+
+// Inventory record kinds
+const (
+	KindMovie   = "movie"
+	KindEpisode = "episode"
+)
+
+// InventoryRecords walks the libraries once, the way a scan does, and
+// returns every video file with the title and episode it is grouped under.
+// Trailers are left out; TV files without an episode number are kept with
+// no season or episode
+func InventoryRecords(ctx context.Context, moviePaths, tvPaths []string, progressCh chan<- ScanProgress) ([]InventoryRecord, error) {
+	inv, err := takeInventory(ctx, append(append([]string{}, moviePaths...), tvPaths...), progressCh)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []InventoryRecord
+	for _, libPath := range moviePaths {
+		for _, file := range inv.library(libPath).files {
+			if !isVideoFile(file.path) || isExtraFile(file.path, file.info.Size()) {
+				continue
+			}
+			record := inventoryRecord(KindMovie, libPath, file)
+			title := CleanMovieName(movieGroupTitle(libPath, file.path))
+			record.Year = ExtractYear(title)
+			record.Title = strings.TrimSuffix(title, " ("+record.Year+")")
+			records = append(records, record)
+		}
+	}
+	for _, libPath := range tvPaths {
+		anime := isAnimeLibrary(libPath)
+		for _, file := range inv.library(libPath).files {
+			if !isVideoFile(file.path) {
+				continue
+			}
+			record := inventoryRecord(KindEpisode, libPath, file)
+			name := filepath.Base(file.path)
+			if ep, ok := ParseAnimeFilename(name); anime && ok {
+				record.Season, record.Episode, record.Absolute = ep.Season, ep.Episode, ep.Absolute
+				record.Title, record.Year = animeLocalTitle(libPath, file.path, ep)
+			} else {
+				if !anime {
+					record.Season, record.Episode, _ = ExtractEpisodeInfo(name)
+				}
+				record.Title = extractShowNameFromPath(file.path)
+			}
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// inventoryRecord fills in what a record takes from the file itself
+func inventoryRecord(kind, libPath string, file inventoryFile) InventoryRecord {
+	return InventoryRecord{
+		Kind:       kind,
+		Library:    libPath,
+		Path:       file.path,
+		Size:       file.info.Size(),
+		ModTime:    file.info.ModTime(),
+		Resolution: ExtractResolution(file.path),
+	}
+}
+
+// WriteInventory writes records as CSV with a header row, or as JSON lines
+func WriteInventory(w io.Writer, records []InventoryRecord, format string) error {
+	switch format {
+	case InventoryCSV:
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"kind", "library", "path", "size", "mtime", "title", "year", "season", "episode", "absolute", "resolution"})
+		for _, r := range records {
+			season, episode := "", ""
+			if r.Kind == KindEpisode && r.Episode > 0 {
+				season, episode = strconv.Itoa(r.Season), strconv.Itoa(r.Episode)
+			}
+			cw.Write([]string{
+				r.Kind, r.Library, r.Path, strconv.FormatInt(r.Size, 10), r.ModTime.Format(time.RFC3339),
+				r.Title, r.Year, season, episode, strconv.FormatBool(r.Absolute), r.Resolution,
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	case InventoryJSONL:
+		enc := json.NewEncoder(w)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown inventory format %q (want csv or jsonl)", format)
+}
@@ -0,0 +1,134 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInventoryFeedsEveryPass(t *testing.T) {
+	root := t.TempDir()
+	movies, tv := filepath.Join(root, "movies"), filepath.Join(root, "tv")
+	for _, rel := range []string{
+		"movies/Up.2009.720p-GRP/Up.2009.720p-GRP.mkv",
+		"movies/Up.2009.720p-GRP/Up.2009.720p-GRP.srt",
+		"movies/Up.2009.720p-GRP/notes.txt",
+		"tv/Lost (2004)/Season 01/Lost (2004) S01E01.mkv",
+	} {
+		path := filepath.Join(root, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	missing := filepath.Join(root, "unmounted")
+
+	inv, err := takeInventory(context.Background(), []string{movies, tv, missing}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := inv.videos([]string{movies, tv, missing}); got != 2 {
+		t.Errorf("Expected 2 videos, got %d", got)
+	}
+	if files := inv.library(movies).files; len(files) != 2 {
+		t.Errorf("Expected the movie and its subtitle kept, got %+v", files)
+	}
+	if err := inv.library(missing).err; !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the missing root recorded as inaccessible, got %v", err)
+	}
+
+	// A cancelled walk returns the cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := takeInventory(ctx, []string{movies}, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the walk cancelled, got %v", err)
+	}
+
+	// A full scan times the walk and every section it ran
+	result, err := RunFullScan(context.Background(), []string{movies}, []string{tv}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.ComplianceIssues) == 0 {
+		t.Error("Expected the movie compliance pass to read the inventory")
+	}
+	var passes []string
+	for _, timing := range result.PassTimings {
+		passes = append(passes, timing.Pass)
+	}
+	want := []string{PassWalk, string(SectionMovieDuplicates), string(SectionTVDuplicates), string(SectionMovieCompliance),
+		string(SectionTVCompliance), string(SectionOrphans), string(SectionArtifacts)}
+	if len(passes) != len(want) {
+		t.Fatalf("Expected timings for %v, got %v", want, passes)
+	}
+	for i := range want {
+		if passes[i] != want[i] {
+			t.Errorf("Expected timing %d for %s, got %s", i, want[i], passes[i])
+		}
+	}
+}
+
+func TestInventoryExport(t *testing.T) {
+	root := t.TempDir()
+	movies, tv := filepath.Join(root, "movies"), filepath.Join(root, "tv")
+	for _, rel := range []string{
+		"movies/Heat.1995.1080p.BluRay.x264-GRP/Heat.1995.1080p.BluRay.x264-GRP.mkv",
+		"movies/Heat.1995.1080p.BluRay.x264-GRP/Heat.1995.1080p.BluRay.x264-GRP.srt",
+		"tv/Lost (2004)/Season 01/Lost (2004) S01E02 720p.mkv",
+		"tv/Lost (2004)/Extras/behind the scenes.mkv",
+	} {
+		path := filepath.Join(root, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err := InventoryRecords(context.Background(), []string{movies}, []string{tv}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected the 3 video files, got %+v", records)
+	}
+	movie := records[0]
+	if movie.Kind != KindMovie || movie.Title != "Heat" || movie.Year != "1995" || movie.Resolution != "1080p" || movie.Library != movies {
+		t.Errorf("Unexpected movie record %+v", movie)
+	}
+	var episode InventoryRecord
+	for _, r := range records[1:] {
+		if r.Episode > 0 {
+			episode = r
+		}
+	}
+	if episode.Kind != KindEpisode || episode.Title != "Lost" || episode.Season != 1 || episode.Episode != 2 || episode.Resolution != "720p" || episode.ModTime.IsZero() {
+		t.Errorf("Unexpected episode record %+v", episode)
+	}
+
+	var csvOut strings.Builder
+	if err := WriteInventory(&csvOut, records, InventoryCSV); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "kind,library,path,size,mtime,title") {
+		t.Errorf("Expected a header and 3 rows, got:\n%s", csvOut.String())
+	}
+
+	var jsonOut strings.Builder
+	if err := WriteInventory(&jsonOut, records, InventoryJSONL); err != nil {
+		t.Fatal(err)
+	}
+	lines = strings.Split(strings.TrimSpace(jsonOut.String()), "\n")
+	var decoded InventoryRecord
+	if len(lines) != 3 || json.Unmarshal([]byte(lines[0]), &decoded) != nil || decoded.Path != movie.Path {
+		t.Errorf("Expected one JSON object per line, got:\n%s", jsonOut.String())
+	}
+
+	if err := WriteInventory(&jsonOut, records, "xml"); err == nil {
+		t.Error("Expected an unknown format refused")
+	}
+}
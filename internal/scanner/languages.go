@@ -0,0 +1,147 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// LanguageRules are the audio and subtitle languages a library should have,
+// checked against the streams ffprobe reports ([languages])
+type LanguageRules struct {
+	RequiredAudio     []string // a file needs an audio track in one of these
+	RequiredSubtitles []string // a file needs a subtitle track in one of these
+	Wanted            []string // other languages worth keeping; tracks outside these and the required ones are unwanted
+	MaxUnwantedTracks int      // files with more unwanted audio and subtitle tracks are bloat candidates; 0 = off
+}
+
+// Enabled reports whether any rule is set, and files need probing for it
+func (r LanguageRules) Enabled() bool {
+	return len(r.RequiredAudio) > 0 || len(r.RequiredSubtitles) > 0 || r.MaxUnwantedTracks > 0
+}
+
+var (
+	languageRules   LanguageRules
+	languageRulesMu sync.RWMutex
+)
+
+// SetLanguageRules sets the language rules scans check files against
+func SetLanguageRules(rules LanguageRules) {
+	languageRulesMu.Lock()
+	defer languageRulesMu.Unlock()
+	languageRules = rules
+}
+
+// GetLanguageRules returns the rules set with SetLanguageRules
+func GetLanguageRules() LanguageRules {
+	languageRulesMu.RLock()
+	defer languageRulesMu.RUnlock()
+	return languageRules
+}
+
+// undeterminedLanguage is the ISO 639-2 code of an untagged track
+const undeterminedLanguage = "und"
+
+// languageAliases map ISO 639-1 codes and ISO 639-2 bibliographic codes to
+// the terminology code, so "en", "eng", "ger" and "deu" compare as expected
+var languageAliases = map[string]string{
+	"ar": "ara", "bg": "bul", "cs": "ces", "da": "dan", "de": "deu", "el": "ell",
+	"en": "eng", "es": "spa", "et": "est", "fa": "fas", "fi": "fin", "fr": "fra",
+	"he": "heb", "hi": "hin", "hr": "hrv", "hu": "hun", "id": "ind", "is": "isl",
+	"it": "ita", "ja": "jpn", "ko": "kor", "lt": "lit", "lv": "lav", "ms": "msa",
+	"nl": "nld", "no": "nor", "pl": "pol", "pt": "por", "ro": "ron", "ru": "rus",
+	"sk": "slk", "sl": "slv", "sr": "srp", "sv": "swe", "th": "tha", "tr": "tur",
+	"uk": "ukr", "vi": "vie", "zh": "zho",
+
+	"alb": "sqi", "arm": "hye", "baq": "eus", "bur": "mya", "chi": "zho", "cze": "ces",
+	"dut": "nld", "fre": "fra", "geo": "kat", "ger": "deu", "gre": "ell", "ice": "isl",
+	"mac": "mkd", "mao": "mri", "may": "msa", "per": "fas", "rum": "ron", "slo": "slk",
+	"tib": "bod", "wel": "cym",
+}
+
+// NormalizeLanguage returns the ISO 639-2/T code of a language tag, lower
+// case; an empty tag is "und"
+func NormalizeLanguage(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return undeterminedLanguage
+	}
+	if alias, ok := languageAliases[code]; ok {
+		return alias
+	}
+	return code
+}
+
+// languageSet normalizes codes into a lookup set
+func languageSet(codes []string) map[string]bool {
+	set := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		set[NormalizeLanguage(code)] = true
+	}
+	return set
+}
+
+// checkLanguages returns the language findings for a probed video file
+func checkLanguages(path, mediaType string, info *MediaInfo, rules LanguageRules) []QualityFinding {
+	var findings []QualityFinding
+	finding := func(kind, detail string) {
+		findings = append(findings, QualityFinding{Path: path, Type: mediaType, Kind: kind, Detail: detail})
+	}
+
+	if len(rules.RequiredAudio) > 0 && !hasLanguage(info.AudioLanguages, languageSet(rules.RequiredAudio)) {
+		finding(QualityMissingAudio, fmt.Sprintf("no %s audio (has %s)", strings.Join(rules.RequiredAudio, "/"), describeTracks(info.AudioLanguages)))
+	}
+	if len(rules.RequiredSubtitles) > 0 && !hasLanguage(info.SubtitleLanguages, languageSet(rules.RequiredSubtitles)) {
+		finding(QualityMissingSubtitles, fmt.Sprintf("no %s subtitles (has %s)", strings.Join(rules.RequiredSubtitles, "/"), describeTracks(info.SubtitleLanguages)))
+	}
+
+	if rules.MaxUnwantedTracks > 0 {
+		wanted := languageSet(append(append(append([]string{}, rules.RequiredAudio...), rules.RequiredSubtitles...), rules.Wanted...))
+		var unwanted []string
+		for _, language := range append(append([]string{}, info.AudioLanguages...), info.SubtitleLanguages...) {
+			// Untagged tracks can't be told apart from wanted ones
+			if code := NormalizeLanguage(language); code != undeterminedLanguage && !wanted[code] {
+				unwanted = append(unwanted, code)
+			}
+		}
+		if len(unwanted) > rules.MaxUnwantedTracks {
+			finding(QualityUnwantedTracks, fmt.Sprintf("%d unwanted language tracks (%s)", len(unwanted), describeTracks(unwanted)))
+		}
+	}
+	return findings
+}
+
+// hasLanguage reports whether any track is in one of the languages
+func hasLanguage(tracks []string, languages map[string]bool) bool {
+	for _, track := range tracks {
+		if languages[NormalizeLanguage(track)] {
+			return true
+		}
+	}
+	return false
+}
+
+// describeTracks lists track languages with their counts, e.g.
+// "eng, fra x2, und", or "none"
+func describeTracks(tracks []string) string {
+	if len(tracks) == 0 {
+		return "none"
+	}
+	var order []string
+	counts := make(map[string]int)
+	for _, track := range tracks {
+		code := NormalizeLanguage(track)
+		if counts[code] == 0 {
+			order = append(order, code)
+		}
+		counts[code]++
+	}
+	parts := make([]string, len(order))
+	for i, code := range order {
+		parts[i] = code
+		if counts[code] > 1 {
+			parts[i] += fmt.Sprintf(" x%d", counts[code])
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,108 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeLanguage(t *testing.T) {
+	tests := map[string]string{
+		"en":   "eng",
+		"ENG":  "eng",
+		"ger":  "deu",
+		"deu":  "deu",
+		" fr ": "fra",
+		"":     "und",
+		"jpn":  "jpn",
+	}
+	for code, expected := range tests {
+		if got := NormalizeLanguage(code); got != expected {
+			t.Errorf("NormalizeLanguage(%q) = %q, want %q", code, got, expected)
+		}
+	}
+}
+
+func TestCheckLanguages(t *testing.T) {
+	rules := LanguageRules{
+		RequiredAudio:     []string{"en"},
+		RequiredSubtitles: []string{"eng"},
+		Wanted:            []string{"jpn"},
+		MaxUnwantedTracks: 2,
+	}
+	kinds := func(info MediaInfo) []string {
+		var kinds []string
+		for _, finding := range checkLanguages("/movies/Heat (1995)/Heat (1995).mkv", "movie", &info, rules) {
+			kinds = append(kinds, finding.Kind)
+		}
+		return kinds
+	}
+
+	if got := kinds(MediaInfo{AudioLanguages: []string{"eng", "jpn"}, SubtitleLanguages: []string{"eng"}}); len(got) != 0 {
+		t.Errorf("Expected no findings for a file with the required languages, got %v", got)
+	}
+	if got := kinds(MediaInfo{AudioLanguages: []string{"jpn"}, SubtitleLanguages: []string{"eng"}}); len(got) != 1 || got[0] != QualityMissingAudio {
+		t.Errorf("Expected missing audio, got %v", got)
+	}
+	if got := kinds(MediaInfo{AudioLanguages: []string{"eng"}}); len(got) != 1 || got[0] != QualityMissingSubtitles {
+		t.Errorf("Expected missing subtitles, got %v", got)
+	}
+
+	// Untagged tracks are never counted as unwanted
+	bloated := MediaInfo{
+		AudioLanguages:    []string{"eng", "fra", "ita", "und"},
+		SubtitleLanguages: []string{"eng", "und", "und"},
+	}
+	if got := kinds(bloated); len(got) != 0 {
+		t.Errorf("Expected two unwanted tracks to be allowed, got %v", got)
+	}
+	bloated.SubtitleLanguages = append(bloated.SubtitleLanguages, "spa", "fra")
+	findings := checkLanguages("/movies/Heat (1995)/Heat (1995).mkv", "movie", &bloated, rules)
+	if len(findings) != 1 || findings[0].Kind != QualityUnwantedTracks {
+		t.Fatalf("Expected unwanted tracks, got %+v", findings)
+	}
+	if findings[0].Detail != "4 unwanted language tracks (fra x2, ita, spa)" {
+		t.Errorf("Unexpected detail %q", findings[0].Detail)
+	}
+}
+
+func TestFullScanReportsLanguageFindings(t *testing.T) {
+	root := t.TempDir()
+	movies := filepath.Join(root, "movies")
+	for _, rel := range []string{"Heat (1995)/Heat (1995).mkv", "Ran (1985)/Ran (1985).mkv", "Ran (1985)/Ran (1985)-trailer.mkv"} {
+		path := filepath.Join(movies, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	withLanguages := func(audio string) string {
+		return fmt.Sprintf(`{"streams": [{"codec_type": "video", "codec_name": "hevc", "width": 1920, "height": 1080},
+			{"codec_type": "audio", "codec_name": "ac3", "channels": 6, "tags": {"language": %q}}],
+			"format": {"duration": "7200", "bit_rate": "8000000"}}`, audio)
+	}
+	fakeFFprobe(t, map[string]string{
+		"Heat (1995).mkv": withLanguages("eng"),
+		"Ran (1985).mkv":  withLanguages("jpn"),
+	})
+	SetLanguageRules(LanguageRules{RequiredAudio: []string{"eng"}})
+	t.Cleanup(func() { SetLanguageRules(LanguageRules{}) })
+
+	result, err := RunFullScan(context.Background(), []string{movies}, nil, nil)
+	if err != nil {
+		t.Fatalf("RunFullScan() failed: %v", err)
+	}
+	if len(result.Quality) != 1 {
+		t.Fatalf("Expected one quality finding, got %+v", result.Quality)
+	}
+	finding := result.Quality[0]
+	if filepath.Base(finding.Path) != "Ran (1985).mkv" || finding.Type != "movie" || finding.Kind != QualityMissingAudio {
+		t.Errorf("Unexpected finding %+v", finding)
+	}
+	if finding.Detail != "no eng audio (has jpn)" {
+		t.Errorf("Unexpected detail %q", finding.Detail)
+	}
+}
@@ -40,7 +40,7 @@ func ScanLooseFiles(paths []string) ([]LooseFile, error) {
 func ScanLooseFilesWithProgress(paths []string, progressCh chan<- ScanProgress) ([]LooseFile, error) {
 	var pr *ProgressReporter
 	if progressCh != nil {
-		pr = NewProgressReporterWithInterval(progressCh, "loose_files", 200*time.Millisecond)
+		pr = NewProgressReporterWithInterval(progressCh, OpLooseFiles, 200*time.Millisecond)
 		pr.StageUpdate("scanning", "Scanning for loose files...")
 
 		total, err := CountVideoFilesWithProgress(paths, pr)
@@ -70,6 +70,10 @@ func ScanLooseFilesWithProgress(paths []string, progressCh chan<- ScanProgress)
 				return err
 			}
 
+			if skip, skipErr := walkSkip(libPath, path, info); skip {
+				return skipErr
+			}
+
 			if info.IsDir() || !isVideoFile(path) {
 				return nil
 			}
@@ -79,8 +83,8 @@ func ScanLooseFilesWithProgress(paths []string, progressCh chan<- ScanProgress)
 				pr.Update(filesProcessed, fmt.Sprintf("Checking: %s", filepath.Base(path)))
 			}
 
-			// Skip sample files
-			if isSampleFile(path) {
+			// Skip sample files and extras
+			if isSampleFile(path) || isExtraFile(path, info.Size()) {
 				return nil
 			}
 
@@ -141,8 +145,8 @@ func isLooseFile(path, libPath string) bool {
 
 	// Check for TV shows - should be in Season## folder
 	if len(parts) == 3 {
-		seasonFolder := parts[1] // Should be "Season 01", "Season 02", etc.
-		if strings.HasPrefix(strings.ToLower(seasonFolder), "season") {
+		seasonFolder := parts[1] // Should be "Season 01", "Season 02", etc., or "Specials"
+		if strings.HasPrefix(strings.ToLower(seasonFolder), "season") || isSpecialsFolder(seasonFolder) {
 			return false // Proper structure
 		}
 		// Not in Season folder = loose
@@ -201,8 +205,9 @@ func classifyLooseFile(path, libPath string) *LooseFile {
 			folderName = fmt.Sprintf("%s (%s)", showName, year)
 		}
 
-		seasonFolder := fmt.Sprintf("Season %02d", season)
-		episodeFilename := fmt.Sprintf("%s S%02dE%02d%s", folderName, season, episode, filepath.Ext(filename))
+		profile := GetNamingProfile()
+		seasonFolder := profile.SeasonFolder(season)
+		episodeFilename := suggestedEpisodeFilename(profile, folderName, season, episode, filepath.Ext(filename))
 
 		loose.SuggestedPath = filepath.Join(libPath, folderName, seasonFolder, episodeFilename)
 		loose.Action = "organize"
@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"math"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// Low-memory mode (performance.low_memory) keeps jellysink inside the
+// 512MB-1GB of a small ARM NAS: fewer workers, capped log buffers, no
+// session-long catalogs, streamed report JSON and a soft heap limit
+
+const (
+	// lowMemoryLogLines caps every in-memory log buffer
+	lowMemoryLogLines = 200
+	// lowMemoryHeapLimit is the soft heap limit the GC works to stay under
+	lowMemoryHeapLimit = 256 << 20
+	// lowMemoryGCPercent makes the GC run after 50% heap growth instead of 100%
+	lowMemoryGCPercent = 50
+)
+
+var (
+	lowMemory   bool
+	lowMemoryMu sync.RWMutex
+)
+
+// SetLowMemory turns low-memory mode on or off. Turning it on also tunes
+// the garbage collector unless GOGC or GOMEMLIMIT say otherwise
+func SetLowMemory(enabled bool) {
+	lowMemoryMu.Lock()
+	defer lowMemoryMu.Unlock()
+	if enabled == lowMemory {
+		return
+	}
+	lowMemory = enabled
+
+	gcPercent, heapLimit := 100, int64(math.MaxInt64)
+	if enabled {
+		gcPercent, heapLimit = lowMemoryGCPercent, lowMemoryHeapLimit
+	}
+	if os.Getenv("GOGC") == "" {
+		debug.SetGCPercent(gcPercent)
+	}
+	if os.Getenv("GOMEMLIMIT") == "" {
+		debug.SetMemoryLimit(heapLimit)
+	}
+}
+
+// LowMemory reports whether low-memory mode is on
+func LowMemory() bool {
+	lowMemoryMu.RLock()
+	defer lowMemoryMu.RUnlock()
+	return lowMemory
+}
+
+// LogLimit returns how many lines a log buffer sized for limit may keep:
+// limit itself, or lowMemoryLogLines when low-memory mode is on and limit
+// is larger. A limit of 0 means unbounded
+func LogLimit(limit int) int {
+	if LowMemory() && (limit == 0 || limit > lowMemoryLogLines) {
+		return lowMemoryLogLines
+	}
+	return limit
+}
+
+// lowMemoryWorkers caps a worker count at 2 in low-memory mode
+func lowMemoryWorkers(n int) int {
+	if LowMemory() && n > 2 {
+		return 2
+	}
+	return n
+}
+
+// defaultWorkers is the worker count of a parallel scan that sets none
+func defaultWorkers() int {
+	return lowMemoryWorkers(runtime.NumCPU())
+}
@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLowMemoryLimits(t *testing.T) {
+	SetLowMemory(true)
+	defer SetLowMemory(false)
+
+	for _, class := range []storageClass{storageSSD, storageHDD, storageUnknown} {
+		if initial, max := class.workerRange(); initial != 1 || max != 2 {
+			t.Errorf("%s: workerRange() = %d, %d, want 1, 2", class, initial, max)
+		}
+	}
+	if initial, max := storageNetwork.workerRange(); initial != 1 || max != 1 {
+		t.Errorf("network: workerRange() = %d, %d, want 1, 1", initial, max)
+	}
+	if got := LogLimit(1000); got != lowMemoryLogLines {
+		t.Errorf("LogLimit(1000) = %d, want %d", got, lowMemoryLogLines)
+	}
+	if got := LogLimit(50); got != 50 {
+		t.Errorf("LogLimit(50) = %d, want 50", got)
+	}
+
+	// Error messages are capped, the count is not
+	pr := NewProgressReporter(make(chan ScanProgress, 1000), OpScanningMovies)
+	for i := 0; i < lowMemoryLogLines+50; i++ {
+		pr.recordError(fmt.Sprintf("error %d", i))
+	}
+	if len(pr.errors) != lowMemoryLogLines || pr.errorsEncountered != lowMemoryLogLines+50 {
+		t.Errorf("Expected %d kept of %d errors, got %d of %d", lowMemoryLogLines, lowMemoryLogLines+50, len(pr.errors), pr.errorsEncountered)
+	}
+	if last := pr.errors[len(pr.errors)-1]; last != fmt.Sprintf("error %d", lowMemoryLogLines+49) {
+		t.Errorf("Expected the newest error kept, got %q", last)
+	}
+
+	// The scan index is streamed to and from disk unchanged
+	path := filepath.Join(t.TempDir(), IndexFile)
+	idx := NewScanIndex()
+	idx.Report = "/reports/a.json"
+	idx.Files["/movies/b.mkv"] = &IndexEntry{Size: 2, ModTime: time.Unix(200, 0).UTC(), Group: "movie:b|"}
+	idx.Files["/movies/a.mkv"] = &IndexEntry{Size: 1, ModTime: time.Unix(100, 0).UTC()}
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	streamed, _ := os.ReadFile(path)
+	if want, _ := json.Marshal(idx); string(streamed) != string(want) {
+		t.Errorf("Streamed index differs:\n%s\nwant\n%s", streamed, want)
+	}
+	loaded, err := LoadScanIndex(path)
+	if err != nil || !reflect.DeepEqual(loaded, idx) {
+		t.Errorf("LoadScanIndex() = %+v, %v", loaded, err)
+	}
+
+	SetLowMemory(false)
+	if initial, max := storageSSD.workerRange(); initial != 4 || max != 8 {
+		t.Errorf("Expected SSD defaults back, got %d, %d", initial, max)
+	}
+	if got := LogLimit(0); got != 0 {
+		t.Errorf("LogLimit(0) = %d, want unbounded", got)
+	}
+	pr.LogError(errors.New("boom"), "after")
+	if len(pr.errors) != lowMemoryLogLines+1 {
+		t.Errorf("Expected errors uncapped again, got %d", len(pr.errors))
+	}
+}
@@ -0,0 +1,176 @@
+package scanner
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// unconfirmedMovieConfidence is the confidence recorded on the issue of a
+// movie whose title was not confirmed, so autoclean's min_confidence can
+// hold it back
+const unconfirmedMovieConfidence = 0.5
+
+// MovieTitleResolution is a movie the scanner would rename to a title TMDB
+// or OMDB could not confirm. It is left for review rather than renamed on
+// trust
+type MovieTitleResolution struct {
+	Path         string // the movie file
+	Title        string // title the scanner suggests
+	Year         string
+	APITitle     string `json:",omitempty"` // closest movie the provider found; empty when it found none
+	APIYear      string `json:",omitempty"`
+	Provider     string // "TMDB" or "OMDB"
+	Reason       string
+	UserDecision DecisionType
+	CustomTitle  string `json:",omitempty"` // set with DecisionCustomTitle
+}
+
+// ChosenName returns the "Title (Year)" the review settled on, or "" when
+// the movie is skipped or not yet decided
+func (r *MovieTitleResolution) ChosenName() string {
+	var title, year string
+	switch r.UserDecision {
+	case DecisionSuggestedTitle:
+		title, year = r.Title, r.Year
+	case DecisionAPITitle:
+		title, year = r.APITitle, r.APIYear
+	case DecisionCustomTitle:
+		return sanitizeEpisodeTitle(strings.TrimSpace(r.CustomTitle))
+	}
+	if title = sanitizeEpisodeTitle(title); title == "" {
+		return ""
+	}
+	if year != "" {
+		return fmt.Sprintf("%s (%s)", title, year)
+	}
+	return title
+}
+
+// RenameMovieIssue points a movie reorganize issue at folder and file name
+// instead of the suggested one, in the same library. A version label in the
+// suggested filename is kept
+func RenameMovieIssue(issue ComplianceIssue, name string) ComplianceIssue {
+	library := filepath.Dir(filepath.Dir(issue.SuggestedPath))
+	target := filepath.Join(library, name, name+filepath.Ext(issue.Path))
+	if label, ok := versionLabel(filepath.Base(filepath.Dir(issue.SuggestedPath)), issue.SuggestedPath); ok {
+		target = withVersionLabel(target, label)
+	}
+	issue.SuggestedPath = target
+	return issue
+}
+
+// verifyMovieName checks a reorganize suggestion's movie title against TMDB,
+// or OMDB when TMDB is not configured or fails, and adopts the provider's
+// spelling, and its year when the suggestion has none. Only a result whose
+// title normalizes to the suggested one (and whose year matches, when known)
+// is trusted, and a missing year is only filled in when a single result
+// matches. A provider that answers without a match returns the movie for
+// review; lookups that fail leave the suggestion as it was
+func verifyMovieName(issue *ComplianceIssue, pr *ProgressReporter) *MovieTitleResolution {
+	_, omdbKey, tmdbKey := apiKeys()
+	if issue.SuggestedAction != "reorganize" {
+		return nil
+	}
+
+	suggestedDir := filepath.Dir(issue.SuggestedPath)
+	current := filepath.Base(suggestedDir)
+	year := ExtractYear(current)
+	title := strings.TrimSpace(strings.TrimSuffix(current, "("+year+")"))
+	if year == "" {
+		title = current
+	}
+
+	provider, results, ok := searchMovie(title, year, tmdbKey, omdbKey, pr)
+	if !ok {
+		return nil
+	}
+
+	var matches []TMDBResult
+	for _, r := range results {
+		if NormalizeName(r.DisplayTitle()) == NormalizeName(title) && (year == "" || r.Year() == year) {
+			matches = append(matches, r)
+		}
+	}
+	if len(matches) == 0 {
+		resolution := &MovieTitleResolution{Path: issue.Path, Title: title, Year: year, Provider: provider}
+		resolution.Reason = fmt.Sprintf("%s has no movie named '%s'", provider, current)
+		if len(results) > 0 {
+			resolution.APITitle, resolution.APIYear = results[0].DisplayTitle(), results[0].Year()
+			resolution.Reason = fmt.Sprintf("%s found '%s' for '%s'", provider, formatMovieName(resolution.APITitle, resolution.APIYear), current)
+		}
+		return resolution
+	}
+	if year == "" && len(matches) > 1 {
+		return nil
+	}
+
+	verified := sanitizeEpisodeTitle(matches[0].DisplayTitle())
+	if verified == "" {
+		return nil
+	}
+	name := formatMovieName(verified, matches[0].Year())
+	if name == current {
+		return nil
+	}
+	newDir := filepath.Join(filepath.Dir(suggestedDir), name)
+	issue.SuggestedPath = filepath.Join(newDir, name+filepath.Ext(issue.Path))
+	issue.Problem += fmt.Sprintf(" (title verified with %s)", provider)
+	return nil
+}
+
+// searchMovie looks title up on TMDB, then OMDB, returning the provider
+// that answered and its results. ok is false when neither could be asked
+func searchMovie(title, year, tmdbKey, omdbKey string, pr *ProgressReporter) (provider string, results []TMDBResult, ok bool) {
+	if tmdbKey != "" && !tmdbBreaker.IsOpen() {
+		results, err := NewTMDBClient(tmdbKey).SearchMovie(title, year)
+		if err == nil {
+			return "TMDB", results, true
+		}
+		if pr != nil {
+			logVerifyFailure(pr, tmdbBreaker, err)
+		}
+	}
+
+	if omdbKey != "" && !omdbBreaker.IsOpen() {
+		result, err := NewOMDBClient(omdbKey).SearchMovie(title, year)
+		switch {
+		case err == nil:
+			// Year() reads the first four digits of OMDB's year
+			return "OMDB", []TMDBResult{{Title: result.Title, ReleaseDate: result.Year}}, true
+		case strings.Contains(strings.ToLower(err.Error()), "not found"):
+			// "Movie not found!" is an answer
+			return "OMDB", nil, true
+		case pr != nil:
+			logVerifyFailure(pr, omdbBreaker, err)
+		}
+	}
+	return "", nil, false
+}
+
+// formatMovieName renders "Title (Year)", or the bare title without a year
+func formatMovieName(title, year string) string {
+	if year == "" {
+		return title
+	}
+	return fmt.Sprintf("%s (%s)", title, year)
+}
+
+// DecideIssue applies the review's decision to the movie's reorganize issue:
+// a chosen title renames it and confirms it, a skipped movie is held for
+// manual review, and an undecided one is returned unchanged
+func (r *MovieTitleResolution) DecideIssue(issue ComplianceIssue) ComplianceIssue {
+	switch r.UserDecision {
+	case DecisionSkipped:
+		issue.SuggestedAction = "manual_review"
+	case DecisionSuggestedTitle, DecisionAPITitle, DecisionCustomTitle:
+		name := r.ChosenName()
+		if name == "" {
+			return issue
+		}
+		issue = RenameMovieIssue(issue, name)
+		issue.SuggestedAction = "reorganize"
+		issue.Confidence = 1
+	}
+	return issue
+}
@@ -0,0 +1,101 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVerifyMovieNameReview(t *testing.T) {
+	tmdbServer(t, map[string][]TMDBResult{
+		"/search/movie?Amelie": {{ID: 194, Title: "Amélie", ReleaseDate: "2001-04-25"}},
+	})
+	SetAPIKeys("", "", "test-key")
+	defer SetAPIKeys("", "", "")
+
+	issue := &ComplianceIssue{Path: "/movies/release/file.mkv", SuggestedPath: "/movies/Amelie (1999)/Amelie (1999).mkv", SuggestedAction: "reorganize"}
+	resolution := verifyMovieName(issue, nil)
+	if resolution == nil {
+		t.Fatal("Expected a title TMDB could not confirm to be returned for review")
+	}
+	if resolution.Title != "Amelie" || resolution.Year != "1999" || resolution.APITitle != "Amélie" || resolution.APIYear != "2001" || resolution.Provider != "TMDB" {
+		t.Errorf("Unexpected resolution %+v", resolution)
+	}
+
+	issue = &ComplianceIssue{Path: "/movies/release/file.mkv", SuggestedPath: "/movies/Home Video (2020)/Home Video (2020).mkv", SuggestedAction: "reorganize"}
+	if resolution = verifyMovieName(issue, nil); resolution == nil || resolution.APITitle != "" {
+		t.Errorf("Expected an unknown title returned without a TMDB title, got %+v", resolution)
+	}
+}
+
+func TestVerifyMovieNameFallsBackToOMDB(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("type") != "movie" {
+			t.Errorf("Expected a movie search, got %s", r.URL.RawQuery)
+		}
+		switch r.URL.Query().Get("t") {
+		case "Whiplash":
+			w.Write([]byte(`{"Title":"Whiplash","Year":"2014","imdbID":"tt2582802","Response":"True"}`))
+		default:
+			w.Write([]byte(`{"Response":"False","Error":"Movie not found!"}`))
+		}
+	}))
+	defer server.Close()
+	origURL := OMDBBaseURL
+	OMDBBaseURL = server.URL
+	ClearAPICache()
+	ResetAPICircuit()
+	SetAPIKeys("", "omdb-key", "")
+	defer func() {
+		OMDBBaseURL = origURL
+		ClearAPICache()
+		ResetAPICircuit()
+		SetAPIKeys("", "", "")
+	}()
+
+	issue := &ComplianceIssue{Path: "/movies/release/file.mkv", SuggestedPath: "/movies/Whiplash/Whiplash.mkv", SuggestedAction: "reorganize"}
+	if resolution := verifyMovieName(issue, nil); resolution != nil {
+		t.Errorf("Expected OMDB to confirm the title, got %+v", resolution)
+	}
+	if want := "/movies/Whiplash (2014)/Whiplash (2014).mkv"; issue.SuggestedPath != want {
+		t.Errorf("Expected %s, got %s", want, issue.SuggestedPath)
+	}
+
+	issue = &ComplianceIssue{Path: "/movies/release/file.mkv", SuggestedPath: "/movies/Home Video (2020)/Home Video (2020).mkv", SuggestedAction: "reorganize"}
+	if resolution := verifyMovieName(issue, nil); resolution == nil || resolution.Provider != "OMDB" {
+		t.Errorf("Expected a title OMDB has no movie for returned for review, got %+v", resolution)
+	}
+}
+
+func TestMovieTitleDecideIssue(t *testing.T) {
+	issue := ComplianceIssue{
+		Path:            "/movies/Amelie.1999.1080p/Amelie.1999.1080p.mkv",
+		SuggestedPath:   "/movies/Amelie (1999)/Amelie (1999).mkv",
+		SuggestedAction: "reorganize",
+		Confidence:      unconfirmedMovieConfidence,
+	}
+	resolution := &MovieTitleResolution{Title: "Amelie", Year: "1999", APITitle: "Amélie", APIYear: "2001"}
+
+	tests := []struct {
+		decision   DecisionType
+		custom     string
+		wantPath   string
+		wantAction string
+	}{
+		{DecisionNone, "", "/movies/Amelie (1999)/Amelie (1999).mkv", "reorganize"},
+		{DecisionSuggestedTitle, "", "/movies/Amelie (1999)/Amelie (1999).mkv", "reorganize"},
+		{DecisionAPITitle, "", "/movies/Amélie (2001)/Amélie (2001).mkv", "reorganize"},
+		{DecisionCustomTitle, "Amélie: Director's Cut (2001)", "/movies/Amélie Director's Cut (2001)/Amélie Director's Cut (2001).mkv", "reorganize"},
+		{DecisionSkipped, "", "/movies/Amelie (1999)/Amelie (1999).mkv", "manual_review"},
+	}
+	for _, tt := range tests {
+		resolution.UserDecision, resolution.CustomTitle = tt.decision, tt.custom
+		got := resolution.DecideIssue(issue)
+		if got.SuggestedPath != tt.wantPath || got.SuggestedAction != tt.wantAction {
+			t.Errorf("Decision %d: expected %s (%s), got %s (%s)", tt.decision, tt.wantPath, tt.wantAction, got.SuggestedPath, got.SuggestedAction)
+		}
+		if decided := tt.decision != DecisionNone && tt.decision != DecisionSkipped; decided != (got.EffectiveConfidence() == 1) {
+			t.Errorf("Decision %d: unexpected confidence %v", tt.decision, got.EffectiveConfidence())
+		}
+	}
+}
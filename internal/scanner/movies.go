@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,17 +16,27 @@ const (
 
 // MovieDuplicate represents a group of duplicate movies
 type MovieDuplicate struct {
-	NormalizedName string      // Normalized movie name for grouping
-	Year           string      // Movie year
-	Files          []MovieFile // All versions found
+	ID              string            // Stable group ID (hash of normalized name and year)
+	NormalizedName  string            // Normalized movie name for grouping
+	Year            string            // Movie year
+	Files           []MovieFile       // All versions found
+	Strategy        DuplicateStrategy `json:",omitempty"` // per-group choice; empty follows the global strategy
+	Scope           DuplicateScope    `json:",omitempty"` // scope the copies were grouped in
+	Within          string            `json:",omitempty"` // folder or library path a same-folder or library group is confined to
+	Version         string            `json:",omitempty"` // edition and resolution the copies share, when allow_versions split the movie's copies into versions
+	Excluded        bool              `json:",omitempty"` // left out of cleans by the user; every file is kept
+	MergeCompanions *bool             `json:",omitempty"` // per-group choice to move deleted copies' subtitles and artwork to the keeper; nil follows the global setting
 }
 
 // MovieFile represents a single movie file
 type MovieFile struct {
-	Path       string // Full path to file
-	Size       int64  // File size in bytes
-	Resolution string // 1080p, 720p, etc.
-	IsEmpty    bool   // True if 0 bytes or missing
+	Path       string     // Full path to file
+	Size       int64      // File size in bytes
+	Resolution string     // 1080p, 720p, etc. (probed when ffprobe is available, else from the filename)
+	IsEmpty    bool       // True if 0 bytes or missing
+	Probe      *MediaInfo `json:",omitempty"` // ffprobe metadata, nil when not probed
+	Inode      string     `json:",omitempty"` // "device:inode" of a file with several hard links
+	Links      int        `json:",omitempty"` // hard links to the file, when more than one
 }
 
 // ScanMovies scans movie library paths for duplicates
@@ -36,9 +47,19 @@ func ScanMovies(paths []string) ([]MovieDuplicate, error) {
 
 // ScanMoviesWithProgress scans movie library paths with progress reporting
 func ScanMoviesWithProgress(paths []string, progressCh chan<- ScanProgress) ([]MovieDuplicate, error) {
+	inv, err := takeInventory(context.Background(), paths, nil)
+	if err != nil {
+		return nil, err
+	}
+	return scanMovieInventory(inv, paths, progressCh)
+}
+
+// scanMovieInventory groups the movie files an inventory holds below paths
+// into duplicates
+func scanMovieInventory(inv *inventory, paths []string, progressCh chan<- ScanProgress) ([]MovieDuplicate, error) {
 	var pr *ProgressReporter
 	if progressCh != nil {
-		pr = NewProgressReporterWithInterval(progressCh, "scanning_movies", 200*time.Millisecond)
+		pr = NewProgressReporterWithInterval(progressCh, OpScanningMovies, 200*time.Millisecond)
 		pr.StageUpdate("validating", "Validating library paths...")
 
 		if err := ValidateBeforeScan(paths, "movie scan", pr); err != nil {
@@ -46,49 +67,35 @@ func ScanMoviesWithProgress(paths []string, progressCh chan<- ScanProgress) ([]M
 			return nil, fmt.Errorf("validation failed: %w", err)
 		}
 
-		pr.StageUpdate("counting_files", "Counting movie files...")
-		total, err := CountVideoFilesWithProgress(paths, pr)
-		if err != nil {
-			pr.LogCritical(err, "Failed to count movie files")
-			return nil, fmt.Errorf("failed to count files: %w", err)
-		}
-
+		total := inv.videos(paths)
 		if total == 0 {
-			pr.Send("warn", "No video files found in accessible paths")
+			pr.Send(SeverityWarn, "No video files found in accessible paths")
 			return []MovieDuplicate{}, nil
 		}
 
 		pr.Start(total, fmt.Sprintf("Scanning %d movie files...", total))
 	}
 
-	movieGroups := make(map[string]*MovieDuplicate)
+	scope := GetDuplicateScopes().Movies
+	movieGroups := make(map[string]*MovieDuplicate) // by titleGroupKey until confirmed
 	filesProcessed := 0
 
 	for _, libPath := range paths {
-		if _, err := os.Stat(libPath); err != nil {
+		lib := inv.library(libPath)
+		if lib.err != nil {
 			if pr != nil {
-				pr.Send("warn", fmt.Sprintf("Skipping inaccessible path: %s", libPath))
+				pr.Send(SeverityWarn, fmt.Sprintf("Skipping inaccessible path: %s", libPath))
 			}
 			continue
 		}
 
-		// Walk directory tree
-		err := filepath.Walk(libPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				if pr != nil {
-					pr.LogError(err, fmt.Sprintf("Error accessing path during walk: %s", path))
-				}
-				return err
-			}
-
-			// Skip directories
-			if info.IsDir() {
-				return nil
-			}
+		for _, file := range lib.files {
+			path, info := file.path, file.info
 
-			// Only process video files
-			if !isVideoFile(path) {
-				return nil
+			// Only process video files; trailers and featurettes are the
+			// movie's extras, not copies of it
+			if !isVideoFile(path) || isExtraFile(path, info.Size()) {
+				continue
 			}
 
 			filesProcessed++
@@ -99,23 +106,14 @@ func ScanMoviesWithProgress(paths []string, progressCh chan<- ScanProgress) ([]M
 			// Extract movie info from filename/path
 			movieFile := parseMovieFile(path, info)
 
-			// Extract movie title from parent directory name (Jellyfin format)
-			// or from filename if file is loose in library root
-			parentDir := filepath.Dir(path)
-			parentDirName := filepath.Base(parentDir)
-
-			// Check if file is in library root (loose file)
-			// Compare parent dir to library path
-			movieTitle := parentDirName
-			if parentDir == libPath || parentDir == "." || parentDir == "/" {
-				// File is loose in library root - use filename
-				movieTitle = filepath.Base(path)
-			}
+			movieTitle := movieGroupTitle(libPath, path)
 
-			// Create group key: normalized_name|year
+			// Create group key: normalized_name|year within the duplicate
+			// scope, confirmed against the group's titles once all are known
 			normalized := NormalizeName(movieTitle)
 			year := ExtractYear(movieTitle)
-			key := normalized + "|" + year
+			within := scope.within(libPath, path)
+			key := titleGroupKey(scopedGroupKey(within, normalized+"|"+year), normalized, movieTitle, path)
 
 			// Add to group
 			if _, exists := movieGroups[key]; !exists {
@@ -123,23 +121,40 @@ func ScanMoviesWithProgress(paths []string, progressCh chan<- ScanProgress) ([]M
 					NormalizedName: normalized,
 					Year:           year,
 					Files:          []MovieFile{},
+					Scope:          scope,
+					Within:         within,
 				}
 			}
 			movieGroups[key].Files = append(movieGroups[key].Files, movieFile)
-
-			return nil
-		})
-
-		if err != nil {
-			return nil, fmt.Errorf("error scanning %s: %w", libPath, err)
 		}
 	}
 
-	// Filter to only duplicates (2+ files per group)
+	movieGroups = confirmTitleGroups(movieGroups, mergeMovieGroup)
+
+	// Filter to only duplicates (2+ files per group), skipping copies already
+	// filed as Jellyfin versions of one movie. With allow_versions, copies
+	// in another edition or resolution are versions too
+	allow := GetAllowVersions()
 	var duplicates []MovieDuplicate
 	for _, group := range movieGroups {
-		if len(group.Files) > 1 {
+		if len(group.Files) < 2 || isMultiVersionSet(group.Files) {
+			continue
+		}
+		if !allow {
 			duplicates = append(duplicates, *group)
+			continue
+		}
+		versions := splitVersions(group.Files)
+		for _, files := range versions {
+			if len(files) < 2 {
+				continue
+			}
+			dup := *group
+			dup.Files = files
+			if len(versions) > 1 {
+				dup.Version = versionKey(files[0])
+			}
+			duplicates = append(duplicates, dup)
 		}
 	}
 
@@ -150,13 +165,27 @@ func ScanMoviesWithProgress(paths []string, progressCh chan<- ScanProgress) ([]M
 	return duplicates, nil
 }
 
+// movieGroupTitle returns the title a movie file is grouped under: its
+// parent directory name (Jellyfin format), or the filename when the file is
+// loose in the library root
+func movieGroupTitle(libPath, path string) string {
+	parentDir := filepath.Dir(path)
+	if parentDir == libPath || parentDir == "." || parentDir == "/" {
+		return filepath.Base(path)
+	}
+	return filepath.Base(parentDir)
+}
+
 // parseMovieFile extracts metadata from movie file
 func parseMovieFile(path string, info os.FileInfo) MovieFile {
+	inode, links := fileLink(info)
 	return MovieFile{
 		Path:       path,
 		Size:       info.Size(),
 		Resolution: ExtractResolution(path),
 		IsEmpty:    info.Size() == 0,
+		Inode:      inode,
+		Links:      links,
 	}
 }
 
@@ -184,11 +213,12 @@ func MarkKeepDelete(duplicates []MovieDuplicate) []MovieDuplicate {
 		group := &duplicates[i]
 
 		// Find best file (largest non-empty with highest resolution)
+		scores := movieKeepScores(group.Files)
 		bestIdx := 0
-		bestScore := scoreMovieFile(group.Files[0])
+		bestScore := scores[0]
 
 		for j := 1; j < len(group.Files); j++ {
-			score := scoreMovieFile(group.Files[j])
+			score := scores[j]
 			if score > bestScore {
 				bestScore = score
 				bestIdx = j
@@ -204,6 +234,22 @@ func MarkKeepDelete(duplicates []MovieDuplicate) []MovieDuplicate {
 	return duplicates
 }
 
+// movieKeepScores scores every file of a group, marking copies that are
+// much shorter than the others as incomplete
+func movieKeepScores(files []MovieFile) []int {
+	infos := make([]*MediaInfo, len(files))
+	for i, file := range files {
+		infos[i] = file.Probe
+	}
+	longest := longestDuration(infos...)
+
+	scores := make([]int, len(files))
+	for i, file := range files {
+		scores[i] = scoreMovieFile(file) + truncatedPenalty(file.Probe, longest)
+	}
+	return scores
+}
+
 // scoreMovieFile assigns quality score for comparison
 // Higher score = better to keep
 func scoreMovieFile(file MovieFile) int {
@@ -244,6 +290,9 @@ func scoreMovieFile(file MovieFile) int {
 		score += int(sizeGB) * 100
 	}
 
+	// Probed codec, bitrate and audio break ties within a resolution
+	score += probeScore(file.Probe)
+
 	return score
 }
 
@@ -263,14 +312,16 @@ func GetDeleteList(duplicates []MovieDuplicate) []string {
 }
 
 // GetSpaceToFree calculates total bytes that can be freed
+// Groups kept as multi-version sets free nothing, and nor do hardlinks of
+// files that stay
 func GetSpaceToFree(duplicates []MovieDuplicate) int64 {
 	var total int64
 
 	for _, group := range duplicates {
-		// Skip first file (it's the keeper)
-		for i := 1; i < len(group.Files); i++ {
-			total += group.Files[i].Size
+		if group.KeepsAllVersions() || group.Excluded {
+			continue
 		}
+		total += group.Reclaimable()
 	}
 
 	return total
@@ -0,0 +1,215 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NamingProfile selects the media server naming convention used for
+// compliance checks and suggested paths
+type NamingProfile string
+
+const (
+	// ProfileJellyfin expects "Season 01" folders and "Show S01E01" filenames
+	ProfileJellyfin NamingProfile = "jellyfin"
+	// ProfileEmby expects "Season 1" folders and "Show - S01E01" filenames
+	// Emby also treats "Specials" as season 0
+	ProfileEmby NamingProfile = "emby"
+)
+
+// AllNamingProfiles lists the supported profiles
+var AllNamingProfiles = []NamingProfile{ProfileJellyfin, ProfileEmby}
+
+var (
+	namingProfile   = ProfileJellyfin
+	namingProfileMu sync.RWMutex
+)
+
+// ParseNamingProfile validates a profile name (case-insensitive)
+func ParseNamingProfile(s string) (NamingProfile, error) {
+	switch NamingProfile(strings.ToLower(strings.TrimSpace(s))) {
+	case ProfileJellyfin:
+		return ProfileJellyfin, nil
+	case ProfileEmby:
+		return ProfileEmby, nil
+	default:
+		return "", fmt.Errorf("invalid naming profile: %s (must be jellyfin or emby)", s)
+	}
+}
+
+// SetNamingProfile sets the profile used by compliance checks
+func SetNamingProfile(p NamingProfile) {
+	namingProfileMu.Lock()
+	defer namingProfileMu.Unlock()
+	namingProfile = p
+}
+
+// GetNamingProfile returns the active naming profile
+func GetNamingProfile() NamingProfile {
+	namingProfileMu.RLock()
+	defer namingProfileMu.RUnlock()
+	return namingProfile
+}
+
+// SeasonFolder returns the canonical season folder name for the profile
+func (p NamingProfile) SeasonFolder(season int) string {
+	if p == ProfileEmby {
+		if season == 0 {
+			return "Specials"
+		}
+		return fmt.Sprintf("Season %d", season)
+	}
+	return fmt.Sprintf("Season %02d", season)
+}
+
+// IsSeasonFolder reports whether a folder name is acceptable for the season
+// Emby accepts padded and unpadded numbers; Jellyfin keeps the strict form
+func (p NamingProfile) IsSeasonFolder(name string, season int) bool {
+	if name == p.SeasonFolder(season) {
+		return true
+	}
+	if p == ProfileEmby {
+		return name == fmt.Sprintf("Season %02d", season) || name == fmt.Sprintf("Season %d", season)
+	}
+	return false
+}
+
+// EpisodeFilename returns the canonical episode filename for the profile
+func (p NamingProfile) EpisodeFilename(show string, season, episode int, ext string) string {
+	if p == ProfileEmby {
+		return fmt.Sprintf("%s - S%02dE%02d%s", show, season, episode, ext)
+	}
+	return fmt.Sprintf("%s S%02dE%02d%s", show, season, episode, ext)
+}
+
+// DateEpisodeFilename returns the canonical filename of a daily show's
+// episode, named by its YYYY-MM-DD air date
+func (p NamingProfile) DateEpisodeFilename(show, date, ext string) string {
+	if p == ProfileEmby {
+		return fmt.Sprintf("%s - %s%s", show, date, ext)
+	}
+	return fmt.Sprintf("%s %s%s", show, date, ext)
+}
+
+// EpisodeFilenameWithTitle appends " - <title>" to the canonical episode
+// filename; an empty title gives the plain filename
+func (p NamingProfile) EpisodeFilenameWithTitle(show string, season, episode int, title, ext string) string {
+	if title == "" {
+		return p.EpisodeFilename(show, season, episode, ext)
+	}
+	return p.EpisodeFilename(show, season, episode, "") + " - " + title + ext
+}
+
+// IgnoreMarkerFile makes Jellyfin and Emby skip the folder containing it
+const IgnoreMarkerFile = ".ignore"
+
+// DefaultExcludedDirs are NAS, system and artwork folders media servers
+// never treat as library content. Matched case-insensitively by folder name
+var DefaultExcludedDirs = []string{
+	"@eaDir",                    // Synology thumbnail index
+	"#recycle",                  // Synology recycle bin
+	"#snapshot",                 // Synology snapshots
+	"@Recycle",                  // QNAP recycle bin
+	"@Recently-Snapshot",        // QNAP snapshots
+	".@__thumb",                 // QNAP thumbnails
+	".Recycle.Bin",              // NAS recycle bins
+	"@tmp",                      // Synology temp
+	"extrafanart",               // Kodi/Emby artwork
+	"extrathumbs",               // Kodi/Emby artwork
+	"lost+found",                // ext filesystem recovery
+	"$RECYCLE.BIN",              // Windows recycle bin on SMB shares
+	"System Volume Information", // Windows metadata on SMB shares
+}
+
+var (
+	excludedDirs   = dirNameSet(DefaultExcludedDirs)
+	excludedDirsMu sync.RWMutex
+)
+
+// SetExcludedDirs replaces the folder names skipped during walks
+// (libraries.exclude_dirs); an empty list disables name-based exclusion
+func SetExcludedDirs(names []string) {
+	excludedDirsMu.Lock()
+	defer excludedDirsMu.Unlock()
+	excludedDirs = dirNameSet(names)
+}
+
+// GetExcludedDirs returns the folder names currently skipped during walks
+func GetExcludedDirs() []string {
+	excludedDirsMu.RLock()
+	defer excludedDirsMu.RUnlock()
+	names := make([]string, 0, len(excludedDirs))
+	for name := range excludedDirs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func dirNameSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			set[strings.ToLower(name)] = true
+		}
+	}
+	return set
+}
+
+// isExcludedDirName reports whether a folder name is on the exclusion list
+func isExcludedDirName(name string) bool {
+	excludedDirsMu.RLock()
+	defer excludedDirsMu.RUnlock()
+	return excludedDirs[strings.ToLower(name)]
+}
+
+// IsIgnoredDir reports whether a directory should never be walked:
+// hidden folders, excluded system/NAS folders, folders with a .ignore marker
+// and folders excluded by a pattern or .jellysinkignore file (ExcludedBy)
+func IsIgnoredDir(path string) bool {
+	name := filepath.Base(path)
+	if strings.HasPrefix(name, ".") || isExcludedDirName(name) {
+		return true
+	}
+	if _, err := os.Stat(filepath.Join(path, IgnoreMarkerFile)); err == nil {
+		return true
+	}
+	return ExcludedBy(path, true) != ""
+}
+
+// IsIgnoredFile reports whether a file should never be flagged
+// Hidden files include macOS "._Movie.mkv" resource forks
+func IsIgnoredFile(path string) bool {
+	return strings.HasPrefix(filepath.Base(path), ".") || ExcludedBy(path, false) != ""
+}
+
+// walkSkip tells a filepath.Walk callback whether to skip path and what to
+// return when it does. The walk root itself is never skipped. During an
+// incremental scan, so is everything it takes from the previous report.
+// Folders the walk goes on to list are paced on cloud mounts
+func walkSkip(root, path string, info os.FileInfo) (bool, error) {
+	if path == root {
+		if info.IsDir() {
+			paceListing(path)
+		}
+		return false, nil
+	}
+	if outOfScope(root, path) {
+		if info.IsDir() {
+			return true, filepath.SkipDir
+		}
+		return true, nil
+	}
+	if info.IsDir() {
+		if IsIgnoredDir(path) {
+			return true, filepath.SkipDir
+		}
+		paceListing(path)
+		return false, nil
+	}
+	return IsIgnoredFile(path), nil
+}
@@ -0,0 +1,149 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseNamingProfile(t *testing.T) {
+	if p, err := ParseNamingProfile(" Emby "); err != nil || p != ProfileEmby {
+		t.Errorf("ParseNamingProfile(Emby) = %q, %v", p, err)
+	}
+	if p, err := ParseNamingProfile("jellyfin"); err != nil || p != ProfileJellyfin {
+		t.Errorf("ParseNamingProfile(jellyfin) = %q, %v", p, err)
+	}
+	if _, err := ParseNamingProfile("kodi"); err == nil {
+		t.Error("Expected error for unknown profile")
+	}
+}
+
+func TestNamingProfileConventions(t *testing.T) {
+	if got := ProfileJellyfin.SeasonFolder(1); got != "Season 01" {
+		t.Errorf("Jellyfin season folder = %q", got)
+	}
+	if got := ProfileEmby.SeasonFolder(1); got != "Season 1" {
+		t.Errorf("Emby season folder = %q", got)
+	}
+	if got := ProfileEmby.SeasonFolder(0); got != "Specials" {
+		t.Errorf("Emby season 0 folder = %q", got)
+	}
+
+	if ProfileJellyfin.IsSeasonFolder("Season 1", 1) {
+		t.Error("Jellyfin profile should require zero-padded season folders")
+	}
+	if !ProfileEmby.IsSeasonFolder("Season 01", 1) || !ProfileEmby.IsSeasonFolder("Season 1", 1) {
+		t.Error("Emby profile should accept padded and unpadded season folders")
+	}
+
+	if got := ProfileEmby.EpisodeFilename("Lost", 1, 2, ".mkv"); got != "Lost - S01E02.mkv" {
+		t.Errorf("Emby episode filename = %q", got)
+	}
+	if got := ProfileJellyfin.EpisodeFilename("Lost", 1, 2, ".mkv"); got != "Lost S01E02.mkv" {
+		t.Errorf("Jellyfin episode filename = %q", got)
+	}
+}
+
+func TestEmbyProfileSeasonCompliance(t *testing.T) {
+	defer SetNamingProfile(GetNamingProfile())
+
+	libRoot := t.TempDir()
+	path := filepath.Join(libRoot, "Lost", "Season 1", "Lost - S01E02.mkv")
+	resolution := &TVTitleResolution{ResolvedTitle: "Lost"}
+
+	SetNamingProfile(ProfileJellyfin)
+	issue := checkTVComplianceWithResolution(path, libRoot, 1, 2, resolution)
+	if issue == nil || issue.Rule != RuleTVSeasonFolder {
+		t.Fatalf("Expected season folder issue under jellyfin, got %+v", issue)
+	}
+	if filepath.Base(filepath.Dir(issue.SuggestedPath)) != "Season 01" {
+		t.Errorf("Expected Season 01 suggestion, got %s", issue.SuggestedPath)
+	}
+
+	SetNamingProfile(ProfileEmby)
+	if issue := checkTVComplianceWithResolution(path, libRoot, 1, 2, resolution); issue != nil {
+		t.Errorf("Expected Emby-style episode to be compliant, got %+v", issue)
+	}
+}
+
+func TestIgnoredFoldersNeverWalked(t *testing.T) {
+	libRoot := t.TempDir()
+	files := []string{
+		"Good Movie (2020)/Good Movie (2020).mkv",
+		"Good Movie (2020)/@eaDir/Good Movie (2020).mkv/SYNOVIDEO_VIDEO_SCREENSHOT.mkv",
+		"Good Movie (2020)/extrafanart/fanart.mkv",
+		"Good Movie (2020)/._Good Movie (2020).mkv",
+		".Trash-1000/Bad.Movie.2019.1080p.x264-GROUP.mkv",
+		"Ignored Collection/Bad.Movie.2018.1080p.x264-GROUP.mkv",
+	}
+	for _, rel := range files {
+		path := filepath.Join(libRoot, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(libRoot, "Ignored Collection", IgnoreMarkerFile), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := ScanMovieCompliance([]string{libRoot})
+	if err != nil {
+		t.Fatalf("ScanMovieCompliance failed: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Expected no issues from ignored folders, got %+v", issues)
+	}
+
+	stats, err := CollectLibraryStats(libRoot, "movies", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.FileCount != 1 {
+		t.Errorf("Expected only the real movie to be counted, got %d", stats.FileCount)
+	}
+
+	if !IsIgnoredDir(filepath.Join(libRoot, "Ignored Collection")) {
+		t.Error("Expected folder with .ignore marker to be ignored")
+	}
+	if IsIgnoredDir(filepath.Join(libRoot, "Good Movie (2020)")) {
+		t.Error("Expected regular movie folder to be walked")
+	}
+}
+
+func TestExcludedDirsConfigurable(t *testing.T) {
+	defer SetExcludedDirs(DefaultExcludedDirs)
+
+	libRoot := t.TempDir()
+	for _, name := range []string{"@eaDir", ".@__thumb", ".Recycle.Bin", "#Recycle", "Featurettes"} {
+		if err := os.MkdirAll(filepath.Join(libRoot, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"@eaDir", ".@__thumb", ".Recycle.Bin", "#Recycle"} {
+		if !IsIgnoredDir(filepath.Join(libRoot, name)) {
+			t.Errorf("Expected %s to be excluded by default", name)
+		}
+	}
+	if IsIgnoredDir(filepath.Join(libRoot, "Featurettes")) {
+		t.Error("Expected Featurettes to be walked by default")
+	}
+
+	SetExcludedDirs([]string{"featurettes"})
+	if !IsIgnoredDir(filepath.Join(libRoot, "Featurettes")) {
+		t.Error("Expected custom exclusion to match case-insensitively")
+	}
+	if IsIgnoredDir(filepath.Join(libRoot, "@eaDir")) {
+		t.Error("Expected @eaDir to be walked once removed from the list")
+	}
+	if !IsIgnoredDir(filepath.Join(libRoot, ".@__thumb")) {
+		t.Error("Expected hidden folders to stay excluded regardless of the list")
+	}
+
+	if got := GetExcludedDirs(); len(got) != 1 || got[0] != "featurettes" {
+		t.Errorf("GetExcludedDirs() = %v", got)
+	}
+}
@@ -3,6 +3,7 @@ package scanner
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // ScanResult contains all scan results and statistics
@@ -11,99 +12,287 @@ type ScanResult struct {
 	TVDuplicates     []TVDuplicate
 	ComplianceIssues []ComplianceIssue
 	AmbiguousTVShows []*TVTitleResolution
+	AmbiguousMovies  []*MovieTitleResolution // movie titles TMDB/OMDB did not confirm
+	OrphanFolders    []OrphanFolder          // TV show/season folders without video files
+	Artifacts        []Artifact              // leftover junk: empty folders, orphaned metadata, samples, partial downloads
+	Quality          []QualityFinding        // informational library-quality findings, such as missing languages
+	Completeness     []ShowCompleteness      // aired episodes on disk per show, when a TVDB key is set
+	APIDiagnostics   []APIProviderStats      // per-provider TVDB/OMDB/TMDB lookup outcomes
+	PassTimings      []PassTiming            // how long the library walk and each section took
 
 	TotalDuplicates    int
 	TotalFilesToDelete int
 	SpaceToFree        int64
 }
 
+// PassWalk names the library walk among the pass timings
+const PassWalk = "walk"
+
+// PassTiming is how long one pass of a scan took: the library walk
+// (PassWalk) or one of the ScanSections. Sections taken from a cancelled
+// scan are not timed again
+type PassTiming struct {
+	Pass     string
+	Duration time.Duration
+}
+
 // RunFullScan orchestrates all scan operations with progress reporting and cancellation support
+// A scan cancelled after some sections finished returns a *CancelledScanError
+// holding their findings
 func RunFullScan(ctx context.Context, moviePaths, tvPaths []string, progressCh chan<- ScanProgress) (*ScanResult, error) {
+	return runScan(ctx, moviePaths, tvPaths, nil, nil, progressCh)
+}
+
+// runScan runs the scan stages. An incremental scan (inc) limits them to
+// the libraries with folders to rescan and merges in its previous findings;
+// a resumed scan (resume) skips the sections a cancelled scan completed
+func runScan(ctx context.Context, moviePaths, tvPaths []string, inc *incrementalScan, resume *resumedScan, progressCh chan<- ScanProgress) (*ScanResult, error) {
 	result := &ScanResult{}
+	orphanPaths := inc.orphanPaths(tvPaths)
+	// Artifacts aren't in the scan index, so every library is searched
+	artifactMovies, artifactTV := moviePaths, tvPaths
+	if inc != nil && inc.artifacts {
+		artifactMovies, artifactTV = nil, nil
+	}
+	moviePaths, tvPaths = inc.paths(moviePaths), inc.paths(tvPaths)
 
-	// Stage 1: Scan movies for duplicates
-	if len(moviePaths) > 0 {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
+	// A section counts as completed only when it was not cut short
+	var completed []ScanSection
+	complete := func(section ScanSection) {
+		if ctx.Err() == nil {
+			completed = append(completed, section)
+		}
+	}
+	cancelled := func() error {
+		err := ctx.Err()
+		if err == nil {
+			return nil
+		}
+		inc.merge(result)
+		return checkpoint(result, completed, err)
+	}
+
+	// APIs that were offline last time get another chance every scan
+	ResetAPICircuit()
+
+	// Lookups verified by earlier runs are reused until their TTL runs out
+	if err := LoadAPICache(); err != nil {
+		warnAPICache(progressCh, err)
+	}
+
+	// Libraries on cloud mounts are scanned with the cloud-safe profile
+	if progressCh != nil {
+		for _, line := range DescribeCloudPaths(append(append([]string{}, artifactMovies...), artifactTV...)) {
+			NewProgressReporter(progressCh, OpReportGeneration).SendSeverityImmediate(SeverityInfo, line)
+		}
+	}
+
+	// One walk of the libraries feeds every duplicate and compliance pass
+	started := time.Now()
+	timed := func(pass string) {
+		result.PassTimings = append(result.PassTimings, PassTiming{Pass: pass, Duration: time.Since(started)})
+		started = time.Now()
+	}
+	var walkPaths []string
+	if !resume.done(SectionMovieDuplicates) || !resume.done(SectionMovieCompliance) || !resume.done(SectionQuality) {
+		walkPaths = append(walkPaths, moviePaths...)
+	}
+	if !resume.done(SectionTVDuplicates) || !resume.done(SectionTVCompliance) || !resume.done(SectionQuality) || !resume.done(SectionCompleteness) {
+		walkPaths = append(walkPaths, tvPaths...)
+	}
+	inv := &inventory{}
+	if len(walkPaths) > 0 {
+		var err error
+		if inv, err = takeInventory(ctx, walkPaths, progressCh); err != nil {
+			if err := cancelled(); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("library walk failed: %w", err)
 		}
+		timed(PassWalk)
+	}
 
-		movieDuplicates, err := ScanMoviesWithProgress(moviePaths, progressCh)
+	// Stage 1: Scan movies for duplicates
+	if err := cancelled(); err != nil {
+		return nil, err
+	}
+	if resume.done(SectionMovieDuplicates) {
+		resume.reuse(SectionMovieDuplicates, result)
+	} else if len(moviePaths) > 0 {
+		movieDuplicates, err := scanMovieInventory(inv, moviePaths, progressCh)
 		if err != nil {
 			return nil, fmt.Errorf("movie duplicate scan failed: %w", err)
 		}
+		// Rank copies on what they contain when ffprobe is available
+		inc.reuseMovieProbes(movieDuplicates)
+		ProbeMovieDuplicates(ctx, movieDuplicates, progressCh)
 		result.MovieDuplicates = MarkKeepDelete(movieDuplicates)
+		timed(string(SectionMovieDuplicates))
 	}
+	complete(SectionMovieDuplicates)
 
 	// Stage 2: Scan TV shows for duplicates
-	if len(tvPaths) > 0 {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
-
-		tvDuplicates, err := ScanTVShowsWithProgress(tvPaths, progressCh)
+	if err := cancelled(); err != nil {
+		return nil, err
+	}
+	if resume.done(SectionTVDuplicates) {
+		resume.reuse(SectionTVDuplicates, result)
+	} else if len(tvPaths) > 0 {
+		tvDuplicates, err := scanTVInventory(inv, tvPaths, progressCh)
 		if err != nil {
 			return nil, fmt.Errorf("TV duplicate scan failed: %w", err)
 		}
+		inc.reuseTVProbes(tvDuplicates)
+		ProbeTVDuplicates(ctx, tvDuplicates, progressCh)
 		result.TVDuplicates = MarkKeepDeleteTV(tvDuplicates)
+		timed(string(SectionTVDuplicates))
 	}
+	complete(SectionTVDuplicates)
 
 	// Stage 3: Movie compliance check
-	if len(moviePaths) > 0 {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
-
+	if err := cancelled(); err != nil {
+		return nil, err
+	}
+	if resume.done(SectionMovieCompliance) {
+		resume.reuse(SectionMovieCompliance, result)
+	} else if len(moviePaths) > 0 {
 		// Exclude files marked for deletion
 		filesToDelete := GetDeleteList(result.MovieDuplicates)
 
-		complianceIssues, err := ScanMovieComplianceWithProgress(moviePaths, progressCh, filesToDelete...)
+		movieComplianceResult, err := scanMovieComplianceInventory(inv, moviePaths, progressCh, filesToDelete...)
 		if err != nil {
 			return nil, fmt.Errorf("movie compliance scan failed: %w", err)
 		}
-		result.ComplianceIssues = append(result.ComplianceIssues, complianceIssues...)
+		result.ComplianceIssues = append(result.ComplianceIssues, movieComplianceResult.Issues...)
+		result.AmbiguousMovies = movieComplianceResult.AmbiguousMovies
+		timed(string(SectionMovieCompliance))
 	}
+	complete(SectionMovieCompliance)
 
 	// Stage 4: TV compliance check
-	if len(tvPaths) > 0 {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
-
+	if err := cancelled(); err != nil {
+		return nil, err
+	}
+	if resume.done(SectionTVCompliance) {
+		resume.reuse(SectionTVCompliance, result)
+	} else if len(tvPaths) > 0 {
 		// Exclude files marked for deletion
 		tvFilesToDelete := GetTVDeleteList(result.TVDuplicates)
 
-		tvComplianceResult, err := ScanTVComplianceWithAmbiguous(tvPaths, progressCh, tvFilesToDelete...)
+		tvComplianceResult, err := scanTVComplianceInventory(inv, tvPaths, progressCh, tvFilesToDelete...)
 		if err != nil {
 			return nil, fmt.Errorf("TV compliance scan failed: %w", err)
 		}
 		result.ComplianceIssues = append(result.ComplianceIssues, tvComplianceResult.Issues...)
 		result.AmbiguousTVShows = tvComplianceResult.AmbiguousTVShows
+		timed(string(SectionTVCompliance))
 	}
+	complete(SectionTVCompliance)
+
+	// Stage 5: Orphaned TV show and season folders
+	if err := cancelled(); err != nil {
+		return nil, err
+	}
+	if resume.done(SectionOrphans) {
+		resume.reuse(SectionOrphans, result)
+	} else if len(orphanPaths) > 0 {
+		orphans, err := ScanOrphanFoldersWithProgress(orphanPaths, progressCh)
+		if err != nil {
+			return nil, fmt.Errorf("orphaned folder scan failed: %w", err)
+		}
+		result.OrphanFolders = orphans
+		timed(string(SectionOrphans))
+	}
+	complete(SectionOrphans)
+
+	// Stage 6: Leftover junk files and empty folders
+	if err := cancelled(); err != nil {
+		return nil, err
+	}
+	if resume.done(SectionArtifacts) {
+		resume.reuse(SectionArtifacts, result)
+	} else if len(artifactMovies)+len(artifactTV) > 0 {
+		artifacts, err := ScanArtifactsWithProgress(artifactMovies, artifactTV, progressCh)
+		if err != nil {
+			return nil, fmt.Errorf("artifact scan failed: %w", err)
+		}
+		result.Artifacts = artifacts
+		timed(string(SectionArtifacts))
+	}
+	complete(SectionArtifacts)
+
+	// Stage 7: Library quality of the probed streams
+	if err := cancelled(); err != nil {
+		return nil, err
+	}
+	if resume.done(SectionQuality) {
+		resume.reuse(SectionQuality, result)
+	} else if qualityEnabled() && len(moviePaths)+len(tvPaths) > 0 {
+		findings, probed := scanQualityInventory(ctx, inv, moviePaths, tvPaths, inc.knownProbe, progressCh)
+		inc.keepProbes(probed)
+		result.Quality = findings
+		timed(string(SectionQuality))
+	}
+	complete(SectionQuality)
+
+	// Stage 8: Aired episodes on disk, against TVDB's episode lists
+	if err := cancelled(); err != nil {
+		return nil, err
+	}
+	if resume.done(SectionCompleteness) {
+		resume.reuse(SectionCompleteness, result)
+	} else if completenessEnabled() && len(tvPaths) > 0 {
+		result.Completeness = scanCompletenessInventory(inv, tvPaths, time.Now(), progressCh)
+		timed(string(SectionCompleteness))
+	}
+	complete(SectionCompleteness)
+	if len(completed) < len(ScanSections) {
+		if err := cancelled(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Findings in folders an incremental scan did not rescan still stand
+	inc.merge(result)
+
+	result.APIDiagnostics = APIDiagnostics()
+	if err := SaveAPICache(); err != nil {
+		warnAPICache(progressCh, err)
+	}
+
+	// Stable IDs and ordering so reports diff cleanly between runs
+	AssignIDs(result)
+	SortResults(result)
 
 	// Calculate statistics
+	tallyResult(result)
+
+	return result, nil
+}
+
+// warnAPICache reports a persisted API cache that could not be used; the
+// scan carries on with the session cache
+func warnAPICache(progressCh chan<- ScanProgress, err error) {
+	if progressCh == nil {
+		return
+	}
+	NewProgressReporter(progressCh, OpReportGeneration).SendSeverityImmediate(SeverityWarn, fmt.Sprintf("API cache ignored: %v", err))
+}
+
+// tallyResult fills in the result's duplicate, file and space totals
+func tallyResult(result *ScanResult) {
 	result.TotalDuplicates = len(result.MovieDuplicates) + len(result.TVDuplicates)
 
 	for _, dup := range result.MovieDuplicates {
-		result.TotalFilesToDelete += len(dup.Files) - 1
-		for i := 1; i < len(dup.Files); i++ {
-			result.SpaceToFree += dup.Files[i].Size
+		if dup.KeepsAllVersions() {
+			continue
 		}
+		result.TotalFilesToDelete += len(dup.Files) - 1
+		result.SpaceToFree += dup.Reclaimable()
 	}
 
 	for _, dup := range result.TVDuplicates {
 		result.TotalFilesToDelete += len(dup.Files) - 1
-		for i := 1; i < len(dup.Files); i++ {
-			result.SpaceToFree += dup.Files[i].Size
-		}
+		result.SpaceToFree += dup.Reclaimable()
 	}
-
-	return result, nil
 }
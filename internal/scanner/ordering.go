@@ -0,0 +1,132 @@
+package scanner
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// idLength is the number of hex characters kept from the key hash
+const idLength = 12
+
+// stableID hashes a normalized key into a short, run-independent identifier
+func stableID(prefix, key string) string {
+	sum := sha1.Sum([]byte(strings.ToLower(key)))
+	return prefix + "-" + hex.EncodeToString(sum[:])[:idLength]
+}
+
+// MovieDuplicateID returns the stable ID for a movie duplicate group
+// Groups confined to a folder or library also key on that path
+func MovieDuplicateID(dup MovieDuplicate) string {
+	key := dup.NormalizedName + "|" + dup.Year
+	if dup.Version != "" {
+		key += "|" + dup.Version
+	}
+	return stableID("mov", scopedGroupKey(dup.Within, key))
+}
+
+// TVDuplicateID returns the stable ID for a TV episode duplicate group
+func TVDuplicateID(dup TVDuplicate) string {
+	if dup.Absolute {
+		return stableID("tv", scopedGroupKey(dup.Within, fmt.Sprintf("%s|abs|%d", dup.ShowName, dup.Episode)))
+	}
+	return stableID("tv", scopedGroupKey(dup.Within, fmt.Sprintf("%s|%d|%d", dup.ShowName, dup.Season, dup.Episode)))
+}
+
+// ComplianceIssueID returns the stable ID for a compliance issue
+// Keyed on type and current path so the same file keeps its ID across scans
+func ComplianceIssueID(issue ComplianceIssue) string {
+	return stableID("cmp", issue.Type+"|"+issue.Path)
+}
+
+// AssignIDs fills in stable IDs on all duplicate groups and compliance issues
+func AssignIDs(result *ScanResult) {
+	for i := range result.MovieDuplicates {
+		result.MovieDuplicates[i].ID = MovieDuplicateID(result.MovieDuplicates[i])
+	}
+	for i := range result.TVDuplicates {
+		result.TVDuplicates[i].ID = TVDuplicateID(result.TVDuplicates[i])
+	}
+	for i := range result.ComplianceIssues {
+		result.ComplianceIssues[i].ID = ComplianceIssueID(result.ComplianceIssues[i])
+	}
+}
+
+// SortResults orders every report section deterministically
+// Groups are sorted by their natural key; within a group the keeper stays at
+// index 0 and the remaining files are sorted by path. Titles and paths are
+// compared per SetSortOptions
+func SortResults(result *ScanResult) {
+	order := newTitleOrder()
+
+	sort.SliceStable(result.MovieDuplicates, func(i, j int) bool {
+		a, b := result.MovieDuplicates[i], result.MovieDuplicates[j]
+		if c := order.compare(a.NormalizedName, b.NormalizedName); c != 0 {
+			return c < 0
+		}
+		if a.Year != b.Year {
+			return a.Year < b.Year
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return order.comparePaths(a.Within, b.Within) < 0
+	})
+	for i := range result.MovieDuplicates {
+		files := result.MovieDuplicates[i].Files
+		if len(files) > 2 {
+			rest := files[1:]
+			sort.SliceStable(rest, func(a, b int) bool { return order.comparePaths(rest[a].Path, rest[b].Path) < 0 })
+		}
+	}
+
+	sort.SliceStable(result.TVDuplicates, func(i, j int) bool {
+		a, b := result.TVDuplicates[i], result.TVDuplicates[j]
+		if c := order.compare(a.ShowName, b.ShowName); c != 0 {
+			return c < 0
+		}
+		if a.Season != b.Season {
+			return a.Season < b.Season
+		}
+		if a.Episode != b.Episode {
+			return a.Episode < b.Episode
+		}
+		return order.comparePaths(a.Within, b.Within) < 0
+	})
+	for i := range result.TVDuplicates {
+		files := result.TVDuplicates[i].Files
+		if len(files) > 2 {
+			rest := files[1:]
+			sort.SliceStable(rest, func(a, b int) bool { return order.comparePaths(rest[a].Path, rest[b].Path) < 0 })
+		}
+	}
+
+	sort.SliceStable(result.ComplianceIssues, func(i, j int) bool {
+		a, b := result.ComplianceIssues[i], result.ComplianceIssues[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return order.comparePaths(a.Path, b.Path) < 0
+	})
+
+	sort.SliceStable(result.AmbiguousTVShows, func(i, j int) bool {
+		return order.comparePaths(result.AmbiguousTVShows[i].FolderPath, result.AmbiguousTVShows[j].FolderPath) < 0
+	})
+
+	sort.SliceStable(result.AmbiguousMovies, func(i, j int) bool {
+		return order.comparePaths(result.AmbiguousMovies[i].Path, result.AmbiguousMovies[j].Path) < 0
+	})
+
+	sort.SliceStable(result.Quality, func(i, j int) bool {
+		if c := order.comparePaths(result.Quality[i].Path, result.Quality[j].Path); c != 0 {
+			return c < 0
+		}
+		return result.Quality[i].Kind < result.Quality[j].Kind
+	})
+
+	sort.SliceStable(result.Completeness, func(i, j int) bool {
+		return order.comparePaths(result.Completeness[i].Path, result.Completeness[j].Path) < 0
+	})
+}
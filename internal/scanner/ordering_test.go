@@ -0,0 +1,139 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStableIDsAreDeterministic(t *testing.T) {
+	a := MovieDuplicate{NormalizedName: "the matrix", Year: "1999"}
+	b := MovieDuplicate{NormalizedName: "the matrix", Year: "1999", Files: []MovieFile{{Path: "/x.mkv"}}}
+
+	if MovieDuplicateID(a) != MovieDuplicateID(b) {
+		t.Errorf("Expected same ID for same normalized key, got %s and %s", MovieDuplicateID(a), MovieDuplicateID(b))
+	}
+
+	c := MovieDuplicate{NormalizedName: "the matrix", Year: "2003"}
+	if MovieDuplicateID(a) == MovieDuplicateID(c) {
+		t.Errorf("Expected different IDs for different years")
+	}
+
+	if !strings.HasPrefix(MovieDuplicateID(a), "mov-") {
+		t.Errorf("Expected movie ID prefix 'mov-', got %s", MovieDuplicateID(a))
+	}
+
+	tv1 := TVDuplicate{ShowName: "Lost", Season: 1, Episode: 2}
+	tv2 := TVDuplicate{ShowName: "Lost", Season: 1, Episode: 3}
+	if TVDuplicateID(tv1) == TVDuplicateID(tv2) {
+		t.Errorf("Expected different IDs for different episodes")
+	}
+
+	issue := ComplianceIssue{Path: "/movies/Film.2020.mkv", Type: "movie", Problem: "bad name"}
+	moved := issue
+	moved.Problem = "different wording"
+	if ComplianceIssueID(issue) != ComplianceIssueID(moved) {
+		t.Errorf("Expected compliance ID to depend only on type and path")
+	}
+}
+
+func TestSortResultsOrdersSections(t *testing.T) {
+	result := &ScanResult{
+		MovieDuplicates: []MovieDuplicate{
+			{NormalizedName: "zodiac", Year: "2007", Files: []MovieFile{{Path: "/keep.mkv"}, {Path: "/z.mkv"}, {Path: "/a.mkv"}}},
+			{NormalizedName: "alien", Year: "1979"},
+		},
+		TVDuplicates: []TVDuplicate{
+			{ShowName: "Lost", Season: 2, Episode: 1},
+			{ShowName: "Lost", Season: 1, Episode: 5},
+			{ShowName: "Dexter", Season: 3, Episode: 1},
+		},
+		ComplianceIssues: []ComplianceIssue{
+			{Type: "tv", Path: "/tv/b"},
+			{Type: "movie", Path: "/movies/b"},
+			{Type: "movie", Path: "/movies/a"},
+		},
+		AmbiguousTVShows: []*TVTitleResolution{
+			{FolderPath: "/tv/Show B"},
+			{FolderPath: "/tv/Show A"},
+		},
+	}
+
+	AssignIDs(result)
+	SortResults(result)
+
+	if result.MovieDuplicates[0].NormalizedName != "alien" {
+		t.Errorf("Expected alien first, got %s", result.MovieDuplicates[0].NormalizedName)
+	}
+	zodiac := result.MovieDuplicates[1]
+	if zodiac.Files[0].Path != "/keep.mkv" {
+		t.Errorf("Expected keeper to stay at index 0, got %s", zodiac.Files[0].Path)
+	}
+	if zodiac.Files[1].Path != "/a.mkv" || zodiac.Files[2].Path != "/z.mkv" {
+		t.Errorf("Expected delete candidates sorted by path, got %s, %s", zodiac.Files[1].Path, zodiac.Files[2].Path)
+	}
+	if zodiac.ID == "" {
+		t.Error("Expected ID to be assigned")
+	}
+
+	tv := result.TVDuplicates
+	if tv[0].ShowName != "Dexter" || tv[1].Season != 1 || tv[2].Season != 2 {
+		t.Errorf("Unexpected TV order: %+v", tv)
+	}
+
+	issues := result.ComplianceIssues
+	if issues[0].Path != "/movies/a" || issues[1].Path != "/movies/b" || issues[2].Type != "tv" {
+		t.Errorf("Unexpected compliance order: %+v", issues)
+	}
+	for _, issue := range issues {
+		if issue.ID != ComplianceIssueID(issue) {
+			t.Errorf("Expected ID %s, got %s", ComplianceIssueID(issue), issue.ID)
+		}
+	}
+
+	if result.AmbiguousTVShows[0].FolderPath != "/tv/Show A" {
+		t.Errorf("Expected ambiguous shows sorted by folder, got %s", result.AmbiguousTVShows[0].FolderPath)
+	}
+}
+
+func TestSortResultsCollatesTitles(t *testing.T) {
+	if err := SetSortOptions("sv", true); err != nil {
+		t.Fatal(err)
+	}
+	defer SetSortOptions("", false)
+
+	result := &ScanResult{
+		TVDuplicates: []TVDuplicate{
+			{ShowName: "Öresund"},
+			{ShowName: "the Office"},
+			{ShowName: "Zorro"},
+			{ShowName: "Rocky 10"},
+			{ShowName: "Rocky 2"},
+			{ShowName: "Athena"},
+		},
+		ComplianceIssues: []ComplianceIssue{
+			{Type: "movie", Path: "/movies/The Matrix (1999)/x.mkv"},
+			{Type: "movie", Path: "/movies/Élite (2018)/x.mkv"},
+			{Type: "movie", Path: "/movies/Zodiac (2007)/x.mkv"},
+		},
+	}
+	SortResults(result)
+
+	var shows []string
+	for _, dup := range result.TVDuplicates {
+		shows = append(shows, dup.ShowName)
+	}
+	// Swedish sorts Ö after Z; "the Office" files under O
+	want := []string{"Athena", "the Office", "Rocky 2", "Rocky 10", "Zorro", "Öresund"}
+	if strings.Join(shows, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected %v, got %v", want, shows)
+	}
+
+	issues := result.ComplianceIssues
+	if issues[0].Path != "/movies/Élite (2018)/x.mkv" || issues[1].Path != "/movies/The Matrix (1999)/x.mkv" {
+		t.Errorf("Expected paths collated folder by folder, got %+v", issues)
+	}
+
+	if err := SetSortOptions("not a locale", false); err == nil {
+		t.Error("Expected an invalid locale to be rejected")
+	}
+}
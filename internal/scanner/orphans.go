@@ -0,0 +1,132 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// OrphanFolder is a TV show or season folder left without any video files,
+// typically after episodes were deleted by hand. Jellyfin still lists them
+type OrphanFolder struct {
+	Path  string // Full path to the folder
+	Kind  string // "show" or "season"
+	Files int    // Leftover files (nfo, artwork, subtitles)
+	Size  int64  // Total size of the leftover files
+}
+
+// seasonFolderPattern matches the season folder names Jellyfin and Emby recognize
+var seasonFolderPattern = regexp.MustCompile(`(?i)^(season[ ._-]*\d+|specials|s\d{1,2})$`)
+
+// ScanOrphanFolders finds show folders with no video files and season
+// folders with no episodes in the given TV library roots
+func ScanOrphanFolders(paths []string) ([]OrphanFolder, error) {
+	return ScanOrphanFoldersWithProgress(paths, nil)
+}
+
+// ScanOrphanFoldersWithProgress finds orphaned show and season folders with progress reporting
+func ScanOrphanFoldersWithProgress(paths []string, progressCh chan<- ScanProgress) ([]OrphanFolder, error) {
+	var pr *ProgressReporter
+	if progressCh != nil {
+		pr = NewProgressReporterWithInterval(progressCh, OpScanningTV, 200*time.Millisecond)
+		pr.StageUpdate("orphans", "Looking for empty show and season folders...")
+	}
+
+	var orphans []OrphanFolder
+	for _, libPath := range paths {
+		paceListing(libPath)
+		shows, err := os.ReadDir(libPath)
+		if err != nil {
+			if pr != nil {
+				pr.Send(SeverityWarn, fmt.Sprintf("Skipping inaccessible path: %s", libPath))
+			}
+			continue
+		}
+
+		for _, show := range shows {
+			showPath := filepath.Join(libPath, show.Name())
+			if !show.IsDir() || IsIgnoredDir(showPath) || outOfScope(libPath, showPath) {
+				continue
+			}
+
+			stats, err := folderContents(showPath)
+			if err != nil {
+				if pr != nil {
+					pr.LogError(err, fmt.Sprintf("Error reading show folder: %s", showPath))
+				}
+				continue
+			}
+			if stats.videos == 0 {
+				orphans = append(orphans, OrphanFolder{Path: showPath, Kind: "show", Files: stats.files, Size: stats.size})
+				continue
+			}
+
+			paceListing(showPath)
+			seasons, err := os.ReadDir(showPath)
+			if err != nil {
+				continue
+			}
+			for _, season := range seasons {
+				seasonPath := filepath.Join(showPath, season.Name())
+				if !season.IsDir() || !seasonFolderPattern.MatchString(season.Name()) || IsIgnoredDir(seasonPath) {
+					continue
+				}
+				stats, err := folderContents(seasonPath)
+				if err != nil || stats.videos > 0 {
+					continue
+				}
+				orphans = append(orphans, OrphanFolder{Path: seasonPath, Kind: "season", Files: stats.files, Size: stats.size})
+			}
+		}
+	}
+
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].Path < orphans[j].Path })
+
+	if pr != nil {
+		pr.Send(SeverityInfo, fmt.Sprintf("Found %d orphaned show/season folders", len(orphans)))
+	}
+	return orphans, nil
+}
+
+// IsOrphanFolder reports whether dir still holds no video files
+// Cleaners re-check this right before deleting a reported folder
+func IsOrphanFolder(dir string) (bool, error) {
+	stats, err := folderContents(dir)
+	if err != nil {
+		return false, err
+	}
+	return stats.videos == 0, nil
+}
+
+// folderStats counts what a folder tree holds
+type folderStats struct {
+	videos int
+	files  int
+	size   int64
+}
+
+// folderContents walks dir counting video files and leftover files.
+// Ignored subfolders are still counted, never treated as empty
+func folderContents(dir string) (folderStats, error) {
+	var stats folderStats
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			paceListing(path)
+			return nil
+		}
+		if isVideoFile(path) {
+			stats.videos++
+			return nil
+		}
+		stats.files++
+		stats.size += info.Size()
+		return nil
+	})
+	return stats, err
+}
@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanOrphanFolders(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"Firefly (2002)/tvshow.nfo":                     "nfo",
+		"Firefly (2002)/Season 01/poster.jpg":           "art",
+		"Lost (2004)/Season 01/Lost (2004) S01E01.mkv":  "video",
+		"Lost (2004)/Season 02/season.nfo":              "nfo",
+		"Lost (2004)/Extras/notes.txt":                  "not a season",
+		"Lost (2004)/Specials/Lost (2004) S00E01.mkv":   "video",
+		"Dark (2017)/Season 1/Dark (2017) S01E01.mkv":   "video",
+		".Trash/Old Show/tvshow.nfo":                    "ignored",
+		"Ignored Show (2001)/" + IgnoreMarkerFile:       "",
+		"Ignored Show (2001)/Season 01/season.nfo":      "nfo",
+		"Nested (2010)/Season 01/Extras/Featurette.mkv": "video",
+	}
+	for rel, content := range files {
+		path := filepath.Join(tmpDir, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+	os.MkdirAll(filepath.Join(tmpDir, "Empty Show (2020)"), 0755)
+
+	orphans, err := ScanOrphanFolders([]string{tmpDir})
+	if err != nil {
+		t.Fatalf("ScanOrphanFolders() error: %v", err)
+	}
+
+	want := []OrphanFolder{
+		{Path: filepath.Join(tmpDir, "Empty Show (2020)"), Kind: "show"},
+		{Path: filepath.Join(tmpDir, "Firefly (2002)"), Kind: "show", Files: 2, Size: 6},
+		{Path: filepath.Join(tmpDir, "Lost (2004)", "Season 02"), Kind: "season", Files: 1, Size: 3},
+	}
+	if len(orphans) != len(want) {
+		t.Fatalf("ScanOrphanFolders() = %+v, want %+v", orphans, want)
+	}
+	for i := range want {
+		if orphans[i] != want[i] {
+			t.Errorf("orphan %d = %+v, want %+v", i, orphans[i], want[i])
+		}
+	}
+}
+
+func TestIsOrphanFolder(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, "season.nfo"), []byte("nfo"), 0644)
+
+	if orphaned, err := IsOrphanFolder(tmpDir); err != nil || !orphaned {
+		t.Errorf("IsOrphanFolder() = %v, %v, want true", orphaned, err)
+	}
+
+	os.WriteFile(filepath.Join(tmpDir, "Show S01E01.mkv"), []byte("video"), 0644)
+	if orphaned, _ := IsOrphanFolder(tmpDir); orphaned {
+		t.Error("A folder with an episode is not orphaned")
+	}
+
+	if _, err := IsOrphanFolder(filepath.Join(tmpDir, "missing")); err == nil {
+		t.Error("Expected an error for a missing folder")
+	}
+}
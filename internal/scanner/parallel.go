@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -13,26 +12,26 @@ import (
 
 // ParallelConfig holds configuration for parallel scanning
 type ParallelConfig struct {
-	Workers int // Number of concurrent workers (default: number of CPUs)
+	Workers int // Number of concurrent workers (default: number of CPUs, at most 2 in low-memory mode)
 }
 
 // DefaultParallelConfig returns optimal parallel scanning configuration
 func DefaultParallelConfig() ParallelConfig {
 	return ParallelConfig{
-		Workers: runtime.NumCPU(),
+		Workers: defaultWorkers(),
 	}
 }
 
 // ScanMoviesParallelWithProgress scans movie libraries in parallel and reports progress
 func ScanMoviesParallelWithProgress(ctx context.Context, paths []string, config ParallelConfig, progressCh chan<- ScanProgress) ([]MovieDuplicate, error) {
 	if config.Workers <= 0 {
-		config.Workers = runtime.NumCPU()
+		config.Workers = defaultWorkers()
 	}
 
 	// Create progress reporter when channel provided
 	var pr *ProgressReporter
 	if progressCh != nil {
-		pr = NewProgressReporterWithInterval(progressCh, "scanning_movies", 200*time.Millisecond)
+		pr = NewProgressReporterWithInterval(progressCh, OpScanningMovies, 200*time.Millisecond)
 		pr.send(0, "Counting movie files...")
 		total, err := CountVideoFiles(paths)
 		if err != nil {
@@ -46,7 +45,7 @@ func ScanMoviesParallelWithProgress(ctx context.Context, paths []string, config
 
 	// Shared data structure (protected by mutex)
 	var mu sync.Mutex
-	movieGroups := make(map[string]*MovieDuplicate)
+	movieGroups := make(map[string]*MovieDuplicate) // by titleGroupKey until confirmed
 
 	// Worker counters
 	var filesProcessed int64
@@ -107,6 +106,11 @@ func ScanMoviesParallelWithProgress(ctx context.Context, paths []string, config
 	wg.Wait()
 	close(errChan)
 
+	// Cancellation takes precedence over wrapped walk errors
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Check for errors
 	if scanErr != nil {
 		if pr != nil {
@@ -115,6 +119,8 @@ func ScanMoviesParallelWithProgress(ctx context.Context, paths []string, config
 		return nil, scanErr
 	}
 
+	movieGroups = confirmTitleGroups(movieGroups, mergeMovieGroup)
+
 	// Filter to only duplicates (2+ files per group)
 	var duplicates []MovieDuplicate
 	for _, group := range movieGroups {
@@ -162,6 +168,10 @@ func scanMoviePathParallelWithProgress(ctx context.Context, libPath string, movi
 			return err
 		}
 
+		if skip, skipErr := walkSkip(libPath, path, info); skip {
+			return skipErr
+		}
+
 		// Skip directories
 		if info.IsDir() {
 			return nil
@@ -172,6 +182,11 @@ func scanMoviePathParallelWithProgress(ctx context.Context, libPath string, movi
 			return nil
 		}
 
+		// Trailers and featurettes are the movie's extras, not copies of it
+		if isExtraFile(path, info.Size()) {
+			return nil
+		}
+
 		// Extract movie info from filename/path
 		movieFile := parseMovieFile(path, info)
 
@@ -192,10 +207,10 @@ func scanMoviePathParallelWithProgress(ctx context.Context, libPath string, movi
 		// Create group key: normalized_name|year
 		normalized := NormalizeName(movieTitle)
 		year := ExtractYear(movieTitle)
-		key := normalized + "|" + year
 
 		// Thread-safe access to shared map
 		mu.Lock()
+		key := titleGroupKey(normalized+"|"+year, normalized, movieTitle, path)
 		if _, exists := movieGroups[key]; !exists {
 			movieGroups[key] = &MovieDuplicate{
 				NormalizedName: normalized,
@@ -228,12 +243,12 @@ func scanMoviePathParallel(ctx context.Context, libPath string, movieGroups map[
 // ScanTVShowsParallelWithProgress scans TV libraries in parallel and reports progress
 func ScanTVShowsParallelWithProgress(ctx context.Context, paths []string, config ParallelConfig, progressCh chan<- ScanProgress) ([]TVDuplicate, error) {
 	if config.Workers <= 0 {
-		config.Workers = runtime.NumCPU()
+		config.Workers = defaultWorkers()
 	}
 
 	var pr *ProgressReporter
 	if progressCh != nil {
-		pr = NewProgressReporterWithInterval(progressCh, "scanning_tv", 200*time.Millisecond)
+		pr = NewProgressReporterWithInterval(progressCh, OpScanningTV, 200*time.Millisecond)
 		pr.send(0, "Counting TV files...")
 		total, err := CountVideoFiles(paths)
 		if err != nil {
@@ -247,7 +262,7 @@ func ScanTVShowsParallelWithProgress(ctx context.Context, paths []string, config
 
 	// Shared data structure (protected by mutex)
 	var mu sync.Mutex
-	episodeGroups := make(map[string]*TVDuplicate)
+	episodeGroups := make(map[string]*TVDuplicate) // by titleGroupKey until confirmed
 
 	// Worker counters
 	var filesProcessed int64
@@ -308,6 +323,11 @@ func ScanTVShowsParallelWithProgress(ctx context.Context, paths []string, config
 	wg.Wait()
 	close(errChan)
 
+	// Cancellation takes precedence over wrapped walk errors
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Check for errors
 	if scanErr != nil {
 		if pr != nil {
@@ -316,6 +336,8 @@ func ScanTVShowsParallelWithProgress(ctx context.Context, paths []string, config
 		return nil, scanErr
 	}
 
+	episodeGroups = confirmTitleGroups(episodeGroups, mergeTVGroup)
+
 	// Filter to only duplicates (2+ files per episode)
 	var duplicates []TVDuplicate
 	for _, group := range episodeGroups {
@@ -363,13 +385,17 @@ func scanTVPathParallelWithProgress(ctx context.Context, libPath string, episode
 			return err
 		}
 
+		if skip, skipErr := walkSkip(libPath, path, info); skip {
+			return skipErr
+		}
+
 		// Skip directories
 		if info.IsDir() {
 			return nil
 		}
 
-		// Only process video files
-		if !isVideoFile(path) {
+		// Only process video files; extras are not copies of the episode
+		if !isVideoFile(path) || isExtraFile(path, info.Size()) {
 			return nil
 		}
 
@@ -396,11 +422,11 @@ func scanTVPathParallelWithProgress(ctx context.Context, libPath string, episode
 		// Normalize show name
 		normalized := NormalizeName(showName)
 
-		// Create group key: normalized_show|S##E##
-		key := fmt.Sprintf("%s|S%02dE%02d", normalized, season, episode)
-
 		// Thread-safe access to shared map
 		mu.Lock()
+		// Create group key: normalized_show|S##E##, confirmed against the
+		// group's titles once the scan has found them all
+		key := titleGroupKey(fmt.Sprintf("%s|S%02dE%02d", normalized, season, episode), normalized, showName, path)
 		if _, exists := episodeGroups[key]; !exists {
 			episodeGroups[key] = &TVDuplicate{
 				ShowName: normalized,
@@ -0,0 +1,442 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Files in duplicate groups are probed with ffprobe when it is installed, so
+// the keeper is chosen on what the file holds rather than what its name
+// claims. Without ffprobe, ranking falls back to the filename and size
+
+const (
+	probeTimeout = 30 * time.Second // per file; network shares can be slow to open
+	probeWorkers = 4                // per mount, when its storage can't be told apart
+	// truncatedRatio is how much shorter than the longest copy a file may
+	// run before it is treated as incomplete
+	truncatedRatio = 0.9
+)
+
+var (
+	ffprobePath   string // empty = look up on PATH, "off" = never probe
+	ffprobePathMu sync.RWMutex
+)
+
+// SetFFprobe sets the ffprobe binary used to inspect duplicates
+// (duplicates.ffprobe): empty looks it up on PATH, "off" disables probing
+func SetFFprobe(path string) {
+	ffprobePathMu.Lock()
+	defer ffprobePathMu.Unlock()
+	ffprobePath = strings.TrimSpace(path)
+}
+
+// ffprobeBinary returns the ffprobe to run, or "" when probing is off or
+// ffprobe is not installed
+func ffprobeBinary() string {
+	ffprobePathMu.RLock()
+	path := ffprobePath
+	ffprobePathMu.RUnlock()
+
+	switch path {
+	case "off":
+		return ""
+	case "":
+		found, err := exec.LookPath("ffprobe")
+		if err != nil {
+			return ""
+		}
+		return found
+	}
+	return path
+}
+
+// FFprobeStatus describes which ffprobe scans will use, for config output
+func FFprobeStatus() string {
+	if binary := ffprobeBinary(); binary != "" {
+		return binary
+	}
+	ffprobePathMu.RLock()
+	defer ffprobePathMu.RUnlock()
+	if ffprobePath == "off" {
+		return "off (ranking by filename)"
+	}
+	return "not found on PATH (ranking by filename)"
+}
+
+// runFFprobe returns ffprobe's JSON description of a file; tests replace it
+var runFFprobe = func(ctx context.Context, binary, path string) ([]byte, error) {
+	return exec.CommandContext(ctx, binary,
+		"-v", "error",
+		"-print_format", "json",
+		"-show_format", "-show_streams",
+		path).Output()
+}
+
+// MediaInfo is what ffprobe found in a video file
+type MediaInfo struct {
+	VideoCodec    string        // e.g. hevc, h264, av1
+	Width         int           // pixels
+	Height        int           // pixels
+	Bitrate       int64         // overall bits per second
+	AudioCodec    string        `json:",omitempty"` // codec of the audio stream with the most channels
+	AudioChannels int           `json:",omitempty"` // most channels of any audio stream
+	Duration      time.Duration `json:",omitempty"`
+
+	AudioLanguages    []string `json:",omitempty"` // language tag of each audio stream, "und" when untagged
+	SubtitleLanguages []string `json:",omitempty"` // language tag of each subtitle stream
+}
+
+// hasLanguages reports whether the probe recorded stream languages; probes
+// kept in the scan index from before languages were read have none
+func (m *MediaInfo) hasLanguages() bool {
+	return m.AudioCodec == "" || len(m.AudioLanguages) > 0
+}
+
+// Resolution buckets the frame size like the filename markers do. Width
+// counts too, so a 1920x800 scope release is still 1080p
+func (m *MediaInfo) Resolution() string {
+	switch {
+	case m.Width >= 3200 || m.Height >= 1800:
+		return "2160p"
+	case m.Width >= 1700 || m.Height >= 1000:
+		return "1080p"
+	case m.Width >= 1100 || m.Height >= 700:
+		return "720p"
+	case m.Height > 0:
+		return "480p"
+	}
+	return "unknown"
+}
+
+// Summary describes the probed streams in one line, e.g.
+// "hevc 1920x1080, 8.1 Mb/s, 6ch eac3, 2h01m"
+func (m *MediaInfo) Summary() string {
+	var parts []string
+	video := m.VideoCodec
+	if m.Width > 0 && m.Height > 0 {
+		video = strings.TrimSpace(fmt.Sprintf("%s %dx%d", video, m.Width, m.Height))
+	}
+	if video != "" {
+		parts = append(parts, video)
+	}
+	if m.Bitrate > 0 {
+		parts = append(parts, fmt.Sprintf("%.1f Mb/s", float64(m.Bitrate)/1e6))
+	}
+	if m.AudioChannels > 0 {
+		parts = append(parts, strings.TrimSpace(fmt.Sprintf("%dch %s", m.AudioChannels, m.AudioCodec)))
+	}
+	if m.Duration > 0 {
+		minutes := int(m.Duration.Round(time.Minute).Minutes())
+		parts = append(parts, fmt.Sprintf("%dh%02dm", minutes/60, minutes%60))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ffprobeOutput is the part of ffprobe's JSON that MediaInfo needs
+type ffprobeOutput struct {
+	Streams []struct {
+		CodecType   string `json:"codec_type"`
+		CodecName   string `json:"codec_name"`
+		Width       int    `json:"width"`
+		Height      int    `json:"height"`
+		Channels    int    `json:"channels"`
+		BitRate     string `json:"bit_rate"`
+		Disposition struct {
+			AttachedPic int `json:"attached_pic"`
+		} `json:"disposition"`
+		Tags struct {
+			Language string `json:"language"`
+		} `json:"tags"`
+	} `json:"streams"`
+	Format struct {
+		Duration string `json:"duration"`
+		BitRate  string `json:"bit_rate"`
+	} `json:"format"`
+}
+
+// parseFFprobe turns ffprobe's JSON into MediaInfo
+func parseFFprobe(data []byte) (*MediaInfo, error) {
+	var out ffprobeOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	info := &MediaInfo{}
+	var videoBitrate int64
+	for _, s := range out.Streams {
+		switch s.CodecType {
+		case "video":
+			// Cover art is stored as a video stream; the first real one wins
+			if s.Disposition.AttachedPic != 0 || info.VideoCodec != "" {
+				continue
+			}
+			info.VideoCodec = s.CodecName
+			info.Width = s.Width
+			info.Height = s.Height
+			videoBitrate, _ = strconv.ParseInt(s.BitRate, 10, 64)
+		case "audio":
+			if s.Channels > info.AudioChannels {
+				info.AudioChannels = s.Channels
+				info.AudioCodec = s.CodecName
+			}
+			info.AudioLanguages = append(info.AudioLanguages, NormalizeLanguage(s.Tags.Language))
+		case "subtitle":
+			info.SubtitleLanguages = append(info.SubtitleLanguages, NormalizeLanguage(s.Tags.Language))
+		}
+	}
+	if info.VideoCodec == "" {
+		return nil, fmt.Errorf("no video stream found")
+	}
+
+	// Containers like MKV often only carry the overall bitrate
+	info.Bitrate, _ = strconv.ParseInt(out.Format.BitRate, 10, 64)
+	if info.Bitrate == 0 {
+		info.Bitrate = videoBitrate
+	}
+	if seconds, err := strconv.ParseFloat(out.Format.Duration, 64); err == nil && seconds > 0 {
+		info.Duration = time.Duration(seconds * float64(time.Second))
+	}
+	return info, nil
+}
+
+// ProbeFile inspects a video file with ffprobe
+func ProbeFile(ctx context.Context, binary, path string) (*MediaInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	data, err := runFFprobe(ctx, binary, path)
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe failed on %s: %w", path, err)
+	}
+	info, err := parseFFprobe(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return info, nil
+}
+
+// ProbeMovieDuplicates fills in the probed metadata of every file in the
+// groups; files that already carry it (from the scan index) are not probed
+// again. Probed resolution replaces the one parsed from the filename
+func ProbeMovieDuplicates(ctx context.Context, duplicates []MovieDuplicate, progressCh chan<- ScanProgress) {
+	var paths []string
+	for _, group := range duplicates {
+		for _, file := range group.Files {
+			if !file.IsEmpty && file.Probe == nil {
+				paths = append(paths, file.Path)
+			}
+		}
+	}
+
+	probed := probeFiles(ctx, paths, "duplicate files", progressCh)
+	for i := range duplicates {
+		for j := range duplicates[i].Files {
+			file := &duplicates[i].Files[j]
+			if info, ok := probed[file.Path]; ok {
+				file.Probe = info
+			}
+			if file.Probe != nil {
+				if res := file.Probe.Resolution(); res != "unknown" {
+					file.Resolution = res
+				}
+			}
+		}
+	}
+}
+
+// ProbeTVDuplicates is ProbeMovieDuplicates for episode groups
+func ProbeTVDuplicates(ctx context.Context, duplicates []TVDuplicate, progressCh chan<- ScanProgress) {
+	var paths []string
+	for _, group := range duplicates {
+		for _, file := range group.Files {
+			if !file.IsEmpty && file.Probe == nil {
+				paths = append(paths, file.Path)
+			}
+		}
+	}
+
+	probed := probeFiles(ctx, paths, "duplicate files", progressCh)
+	for i := range duplicates {
+		for j := range duplicates[i].Files {
+			file := &duplicates[i].Files[j]
+			if info, ok := probed[file.Path]; ok {
+				file.Probe = info
+			}
+			if file.Probe != nil {
+				if res := file.Probe.Resolution(); res != "unknown" {
+					file.Resolution = res
+				}
+			}
+		}
+	}
+}
+
+// probeFiles probes paths concurrently, each mount with its own worker
+// count (see mountTuner); what names the files in progress messages. Files
+// ffprobe cannot read are left out and keep their filename-based ranking
+func probeFiles(ctx context.Context, paths []string, what string, progressCh chan<- ScanProgress) map[string]*MediaInfo {
+	probed := make(map[string]*MediaInfo)
+	if len(paths) == 0 {
+		return probed
+	}
+	binary := ffprobeBinary()
+	if binary == "" {
+		return probed
+	}
+
+	var pr *ProgressReporter
+	if progressCh != nil {
+		pr = NewProgressReporterWithInterval(progressCh, OpProbingMedia, 200*time.Millisecond)
+		pr.Start(len(paths), fmt.Sprintf("Probing %d %s with ffprobe...", len(paths), what))
+	}
+
+	// Group the files by mount, keeping their order
+	mounts := loadMounts()
+	var order []mountInfo
+	byMount := make(map[mountInfo][]string)
+	for _, path := range paths {
+		m := mountFor(mounts, path)
+		if _, ok := byMount[m]; !ok {
+			order = append(order, m)
+		}
+		byMount[m] = append(byMount[m], path)
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		done int
+	)
+	for _, m := range order {
+		class := classifyMount(m)
+		if class == storageCloud && CloudSafe() {
+			// Probing would download part of every file from the remote
+			mu.Lock()
+			done += len(byMount[m])
+			if pr != nil {
+				pr.SendSeverityImmediate(SeverityInfo, fmt.Sprintf("Not probing %d files on cloud mount %s (%s): ranking them by filename",
+					len(byMount[m]), m.label(), m.FSType))
+				pr.Update(done, fmt.Sprintf("Probed %d/%d files", done, len(paths)))
+			}
+			mu.Unlock()
+			continue
+		}
+		tuner := newMountTuner(m, class, time.Now)
+		if pr != nil {
+			pr.SendSeverityImmediate(SeverityInfo, fmt.Sprintf("Probing %d files on %s (%s, %s) with %d workers",
+				len(byMount[m]), m.label(), m.FSType, class, tuner.workers()))
+		}
+
+		pathCh := make(chan string)
+		for w := 0; w < tuner.max; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for path := range pathCh {
+					tuner.acquire()
+					info, err := ProbeFile(ctx, binary, path)
+					tuned := tuner.release()
+
+					mu.Lock()
+					done++
+					if err == nil {
+						probed[path] = info
+					} else if pr != nil && ctx.Err() == nil {
+						pr.SendSeverityImmediate(SeverityWarn, err.Error())
+					}
+					if pr != nil {
+						if tuned != "" {
+							pr.SendSeverityImmediate(SeverityInfo, tuned)
+						}
+						pr.Update(done, fmt.Sprintf("Probed %d/%d files", done, len(paths)))
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+
+		wg.Add(1)
+		go func(paths []string) {
+			defer wg.Done()
+			defer close(pathCh)
+			for _, path := range paths {
+				if ctx.Err() != nil {
+					return
+				}
+				pathCh <- path
+			}
+		}(byMount[m])
+	}
+	wg.Wait()
+
+	if pr != nil {
+		pr.Complete(fmt.Sprintf("Probed %d of %d %s", len(probed), len(paths), what))
+	}
+	return probed
+}
+
+// probeScore ranks copies of the same resolution: higher bitrate, a more
+// efficient codec and more audio channels win. It stays below the gap
+// between resolutions, so it never outweighs a higher resolution
+func probeScore(info *MediaInfo) int {
+	if info == nil {
+		return 0
+	}
+	score := 0
+
+	// 1 point per Mb/s, capped
+	mbps := int(info.Bitrate / 1_000_000)
+	if mbps > 40 {
+		mbps = 40
+	}
+	score += mbps
+
+	switch strings.ToLower(info.VideoCodec) {
+	case "hevc", "h265", "av1":
+		score += 15
+	case "vp9":
+		score += 10
+	case "h264":
+		score += 5
+	}
+
+	// 2 points per channel past stereo: 5.1 = +8, 7.1 = +12
+	if info.AudioChannels > 2 {
+		channels := info.AudioChannels
+		if channels > 8 {
+			channels = 8
+		}
+		score += (channels - 2) * 2
+	}
+
+	return score
+}
+
+// truncatedPenalty marks a copy that runs well short of the longest probed
+// copy in its group as incomplete, ranking it below every full copy
+func truncatedPenalty(info *MediaInfo, longest time.Duration) int {
+	if info == nil || info.Duration == 0 || longest == 0 {
+		return 0
+	}
+	if float64(info.Duration) < float64(longest)*truncatedRatio {
+		return -500
+	}
+	return 0
+}
+
+// longestDuration returns the longest probed duration, 0 if none was probed
+func longestDuration(infos ...*MediaInfo) time.Duration {
+	var longest time.Duration
+	for _, info := range infos {
+		if info != nil && info.Duration > longest {
+			longest = info.Duration
+		}
+	}
+	return longest
+}
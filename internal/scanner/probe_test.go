@@ -0,0 +1,184 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFFprobe(t *testing.T) {
+	data := []byte(`{
+		"streams": [
+			{"codec_type": "video", "codec_name": "mjpeg", "width": 600, "height": 900, "disposition": {"attached_pic": 1}},
+			{"codec_type": "video", "codec_name": "hevc", "width": 1920, "height": 800, "bit_rate": "7000000", "disposition": {"attached_pic": 0}},
+			{"codec_type": "audio", "codec_name": "aac", "channels": 2, "tags": {"language": "ger"}},
+			{"codec_type": "audio", "codec_name": "eac3", "channels": 6, "tags": {"language": "eng"}},
+			{"codec_type": "subtitle", "codec_name": "subrip"}
+		],
+		"format": {"duration": "7265.300000", "bit_rate": "8100000"}
+	}`)
+
+	info, err := parseFFprobe(data)
+	if err != nil {
+		t.Fatalf("parseFFprobe() error: %v", err)
+	}
+	want := MediaInfo{
+		VideoCodec:    "hevc",
+		Width:         1920,
+		Height:        800,
+		Bitrate:       8100000,
+		AudioCodec:    "eac3",
+		AudioChannels: 6,
+		Duration:      time.Duration(7265.3 * float64(time.Second)),
+
+		AudioLanguages:    []string{"deu", "eng"},
+		SubtitleLanguages: []string{"und"},
+	}
+	if !reflect.DeepEqual(*info, want) {
+		t.Errorf("parseFFprobe() = %+v, want %+v", *info, want)
+	}
+	if got := info.Resolution(); got != "1080p" {
+		t.Errorf("Resolution() = %s, want 1080p for a scope frame", got)
+	}
+	if got := info.Summary(); got != "hevc 1920x800, 8.1 Mb/s, 6ch eac3, 2h01m" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	if _, err := parseFFprobe([]byte(`{"streams": [{"codec_type": "audio", "channels": 2}]}`)); err == nil {
+		t.Error("Expected an error for a file without video")
+	}
+}
+
+func TestMediaInfoResolution(t *testing.T) {
+	tests := []struct {
+		width, height int
+		expected      string
+	}{
+		{3840, 2160, "2160p"},
+		{3840, 1600, "2160p"},
+		{1920, 1080, "1080p"},
+		{1280, 720, "720p"},
+		{1280, 536, "720p"},
+		{720, 480, "480p"},
+		{0, 0, "unknown"},
+	}
+	for _, tt := range tests {
+		info := MediaInfo{Width: tt.width, Height: tt.height}
+		if got := info.Resolution(); got != tt.expected {
+			t.Errorf("Resolution() for %dx%d = %s, want %s", tt.width, tt.height, got, tt.expected)
+		}
+	}
+}
+
+// fakeFFprobe answers probes from canned output keyed by file name
+func fakeFFprobe(t *testing.T, outputs map[string]string) {
+	t.Helper()
+	original := runFFprobe
+	runFFprobe = func(ctx context.Context, binary, path string) ([]byte, error) {
+		out, ok := outputs[filepath.Base(path)]
+		if !ok {
+			return nil, fmt.Errorf("exit status 1")
+		}
+		return []byte(out), nil
+	}
+	SetFFprobe("/usr/bin/ffprobe")
+	t.Cleanup(func() {
+		runFFprobe = original
+		SetFFprobe("")
+	})
+}
+
+func probeJSON(codec string, width, height int, bitrate int64, channels int, seconds float64) string {
+	return fmt.Sprintf(`{"streams": [{"codec_type": "video", "codec_name": %q, "width": %d, "height": %d},
+		{"codec_type": "audio", "codec_name": "ac3", "channels": %d}],
+		"format": {"duration": "%f", "bit_rate": "%d"}}`, codec, width, height, channels, seconds, bitrate)
+}
+
+func TestProbedDuplicatesRankByStreams(t *testing.T) {
+	fakeFFprobe(t, map[string]string{
+		// Labelled 2160p but actually an upscaled-name 720p encode
+		"Heat.1995.2160p.mkv": probeJSON("h264", 1280, 720, 4_000_000, 2, 10200),
+		"Heat.1995.720p.mkv":  probeJSON("hevc", 1920, 1080, 9_000_000, 6, 10200),
+	})
+
+	gb := int64(1024 * 1024 * 1024)
+	dups := []MovieDuplicate{{
+		NormalizedName: "heat",
+		Year:           "1995",
+		Files: []MovieFile{
+			{Path: "/movies/Heat (1995)/Heat.1995.2160p.mkv", Size: 8 * gb, Resolution: "2160p"},
+			{Path: "/movies/Heat.1995.720p/Heat.1995.720p.mkv", Size: 6 * gb, Resolution: "720p"},
+		},
+	}}
+
+	ProbeMovieDuplicates(context.Background(), dups, nil)
+	if dups[0].Files[0].Resolution != "720p" || dups[0].Files[1].Resolution != "1080p" {
+		t.Fatalf("Expected probed resolutions to replace filename ones, got %s and %s",
+			dups[0].Files[0].Resolution, dups[0].Files[1].Resolution)
+	}
+
+	marked := MarkKeepDelete(dups)
+	if keeper := marked[0].Files[0]; !strings.HasSuffix(keeper.Path, "Heat.1995.720p.mkv") {
+		t.Errorf("Expected the file that is really 1080p kept, got %s", keeper.Path)
+	}
+	if marked[0].Files[0].Probe == nil || marked[0].Files[0].Probe.AudioChannels != 6 {
+		t.Errorf("Expected probed metadata on the keeper, got %+v", marked[0].Files[0].Probe)
+	}
+}
+
+func TestProbedDuplicatesSkipTruncatedCopies(t *testing.T) {
+	fakeFFprobe(t, map[string]string{
+		"Show.S01E01.Bluray.mkv": probeJSON("hevc", 1920, 1080, 12_000_000, 6, 1200),
+		"Show.S01E01.HDTV.mkv":   probeJSON("h264", 1920, 1080, 5_000_000, 2, 2700),
+	})
+
+	dups := []TVDuplicate{{
+		ShowName: "show",
+		Season:   1,
+		Episode:  1,
+		Files: []TVFile{
+			{Path: "/tv/Show/Season 01/Show.S01E01.Bluray.mkv", Size: 3 << 30, Resolution: "1080p", Source: "BLURAY"},
+			{Path: "/tv/Show/Season 01/Show.S01E01.HDTV.mkv", Size: 1 << 30, Resolution: "1080p", Source: "HDTV"},
+		},
+	}}
+
+	ProbeTVDuplicates(context.Background(), dups, nil)
+	marked := MarkKeepDeleteTV(dups)
+	if keeper := marked[0].Files[0]; keeper.Source != "HDTV" {
+		t.Errorf("Expected the complete HDTV copy kept over the cut-short Bluray, got %s", keeper.Path)
+	}
+
+	exp := ExplainTVDuplicate(marked[0])
+	var probed int
+	for _, step := range exp.Steps {
+		if strings.TrimSpace(step.Label) == "probed" {
+			probed++
+		}
+	}
+	if probed != 2 {
+		t.Errorf("Expected probed steps for both files, got %d", probed)
+	}
+}
+
+func TestProbeFallsBackWithoutFFprobe(t *testing.T) {
+	fakeFFprobe(t, map[string]string{})
+	SetFFprobe("off")
+
+	dups := []MovieDuplicate{{Files: []MovieFile{
+		{Path: "/movies/a.mkv", Size: 1, Resolution: "1080p"},
+		{Path: "/movies/b.mkv", Size: 1, Resolution: "720p"},
+	}}}
+	ProbeMovieDuplicates(context.Background(), dups, nil)
+	for _, file := range dups[0].Files {
+		if file.Probe != nil {
+			t.Errorf("Expected no probing when ffprobe is off, got %+v", file.Probe)
+		}
+	}
+	if FFprobeStatus() != "off (ranking by filename)" {
+		t.Errorf("FFprobeStatus() = %q", FFprobeStatus())
+	}
+}
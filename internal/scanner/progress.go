@@ -10,13 +10,13 @@ import (
 
 // ScanProgress represents real-time scan progress
 type ScanProgress struct {
-	Operation  string  // "scanning_movies", "scanning_tv", "compliance_movies", "compliance_tv", "generating_report"
-	Stage      string  // "counting_files", "scanning", "analyzing", "complete"
-	Current    int     // Current file/item number
-	Total      int     // Total files/items
-	Percentage float64 // 0-100
-	Message    string  // Human-readable status
-	Severity   string  // "info", "warn", "error", "critical"
+	Operation  ProgressOperation // See Op* constants
+	Stage      string            // "counting_files", "scanning", "analyzing", "complete"
+	Current    int               // Current file/item number
+	Total      int               // Total files/items
+	Percentage float64           // 0-100
+	Message    string            // Human-readable status
+	Severity   ProgressSeverity  // See Severity* constants
 
 	// Statistics
 	DuplicatesFound   int
@@ -34,6 +34,9 @@ type ScanProgress struct {
 	// UI Alert flag - if true, TUI should show modal alert
 	ShowAlert bool
 	AlertType string // "error", "critical", "warning"
+
+	// Relayed marks progress streamed from a scan running in another process
+	Relayed bool `json:",omitempty"`
 }
 
 // LogLevel controls which messages get sent
@@ -48,7 +51,7 @@ const (
 // ProgressReporter helps send progress updates
 type ProgressReporter struct {
 	ch        chan<- ScanProgress
-	operation string
+	operation ProgressOperation
 	startTime time.Time
 	total     int
 
@@ -95,7 +98,7 @@ func ParseLogLevel(s string) (LogLevel, error) {
 	}
 }
 
-func NewProgressReporter(ch chan<- ScanProgress, operation string) *ProgressReporter {
+func NewProgressReporter(ch chan<- ScanProgress, operation ProgressOperation) *ProgressReporter {
 	return &ProgressReporter{
 		ch:          ch,
 		operation:   operation,
@@ -107,7 +110,7 @@ func NewProgressReporter(ch chan<- ScanProgress, operation string) *ProgressRepo
 }
 
 // NewProgressReporterWithInterval creates a new reporter with specified minimum interval between UI updates
-func NewProgressReporterWithInterval(ch chan<- ScanProgress, operation string, minInterval time.Duration) *ProgressReporter {
+func NewProgressReporterWithInterval(ch chan<- ScanProgress, operation ProgressOperation, minInterval time.Duration) *ProgressReporter {
 	pr := NewProgressReporter(ch, operation)
 	pr.minInterval = minInterval
 	return pr
@@ -139,6 +142,7 @@ func (pr *ProgressReporter) Complete(message string) {
 		Total:          pr.total,
 		Percentage:     100.0,
 		Message:        message,
+		Severity:       SeverityInfo,
 		StartTime:      pr.startTime,
 		ElapsedSeconds: int(time.Since(pr.startTime).Seconds()),
 	}
@@ -155,7 +159,7 @@ func (pr *ProgressReporter) StageUpdate(stage, message string) {
 		Total:             pr.total,
 		Percentage:        pr.calculatePercentage(),
 		Message:           message,
-		Severity:          "info",
+		Severity:          SeverityInfo,
 		StartTime:         pr.startTime,
 		ElapsedSeconds:    int(time.Since(pr.startTime).Seconds()),
 		FilesProcessed:    pr.filesProcessed,
@@ -178,7 +182,7 @@ func (pr *ProgressReporter) StageUpdate(stage, message string) {
 
 // send helper for building and sending progress (info severity)
 func (pr *ProgressReporter) send(current int, message string) {
-	pr.sendSeverity(current, message, "info")
+	pr.sendSeverity(current, message, SeverityInfo)
 }
 
 // sendSeverity builds and sends progress with the specified severity
@@ -190,7 +194,7 @@ func (pr *ProgressReporter) calculatePercentage() float64 {
 	return (float64(pr.filesProcessed) / float64(pr.total)) * 100.0
 }
 
-func (pr *ProgressReporter) sendSeverity(current int, message, severity string) {
+func (pr *ProgressReporter) sendSeverity(current int, message string, severity ProgressSeverity) {
 	// Apply log level filtering
 	if !pr.shouldSend(severity) {
 		return
@@ -231,32 +235,23 @@ func (pr *ProgressReporter) sendSeverity(current int, message, severity string)
 }
 
 // shouldSend checks if message should be sent based on log level and severity
-func (pr *ProgressReporter) shouldSend(severity string) bool {
-	switch pr.logLevel {
-	case LogLevelQuiet:
-		return severity == "error" || severity == "critical"
-	case LogLevelNormal:
-		return severity != "debug"
-	case LogLevelVerbose:
-		return true
-	default:
-		return true
-	}
+func (pr *ProgressReporter) shouldSend(severity ProgressSeverity) bool {
+	return severity.AtLeast(pr.logLevel.MinSeverity())
 }
 
 // SendSeverityImmediate sends a message bypassing throttling and log level filtering
 // Useful for critical errors that need immediate UI attention
-func (pr *ProgressReporter) SendSeverityImmediate(severity, message string) {
+func (pr *ProgressReporter) SendSeverityImmediate(severity ProgressSeverity, message string) {
 	percentage := 0.0
 	if pr.total > 0 {
 		percentage = (float64(pr.filesProcessed) / float64(pr.total)) * 100.0
 	}
 
 	// Set alert flags for critical/error severities
-	showAlert := severity == "critical" || severity == "error"
+	showAlert := severity.IsError()
 	alertType := ""
 	if showAlert {
-		alertType = severity
+		alertType = string(severity)
 	}
 
 	progress := ScanProgress{
@@ -281,15 +276,25 @@ func (pr *ProgressReporter) SendSeverityImmediate(severity, message string) {
 	pr.ch <- progress
 }
 
+// recordError counts an error and keeps its message, dropping the oldest
+// messages past the low-memory log cap
+func (pr *ProgressReporter) recordError(msg string) {
+	pr.errorsEncountered++
+	if limit := LogLimit(0); limit > 0 && len(pr.errors) >= limit {
+		// Copied rather than resliced: sent progress updates share the old array
+		pr.errors = append(pr.errors[:0:0], pr.errors[len(pr.errors)-limit+1:]...)
+	}
+	pr.errors = append(pr.errors, msg)
+}
+
 // LogError records an error and sends immediate error message (bypasses throttling)
 func (pr *ProgressReporter) LogError(err error, message string) {
 	fullMsg := message
 	if err != nil {
 		fullMsg = fmt.Sprintf("%s: %v", message, err)
 	}
-	pr.errorsEncountered++
-	pr.errors = append(pr.errors, fullMsg)
-	pr.SendSeverityImmediate("error", fullMsg)
+	pr.recordError(fullMsg)
+	pr.SendSeverityImmediate(SeverityError, fullMsg)
 }
 
 // LogCritical records a critical error and sends immediate critical message
@@ -298,9 +303,8 @@ func (pr *ProgressReporter) LogCritical(err error, message string) {
 	if err != nil {
 		fullMsg = fmt.Sprintf("%s: %v", message, err)
 	}
-	pr.errorsEncountered++
-	pr.errors = append(pr.errors, fullMsg)
-	pr.SendSeverityImmediate("critical", fullMsg)
+	pr.recordError(fullMsg)
+	pr.SendSeverityImmediate(SeverityCritical, fullMsg)
 }
 
 // SetMinInterval sets the minimum interval between UI updates
@@ -309,7 +313,7 @@ func (pr *ProgressReporter) SetMinInterval(d time.Duration) {
 }
 
 // Send sends a progress message with a specific severity (respects log level filter and throttling)
-func (pr *ProgressReporter) Send(severity, message string) {
+func (pr *ProgressReporter) Send(severity ProgressSeverity, message string) {
 	pr.sendSeverity(pr.filesProcessed, message, severity)
 }
 
@@ -329,7 +333,7 @@ func CountVideoFilesWithProgress(paths []string, pr *ProgressReporter) (int, err
 		if _, err := os.Stat(libPath); err != nil {
 			skippedPaths = append(skippedPaths, libPath)
 			if pr != nil {
-				pr.Send("warn", fmt.Sprintf("Skipping inaccessible path: %s (%v)", libPath, err))
+				pr.Send(SeverityWarn, fmt.Sprintf("Skipping inaccessible path: %s (%v)", libPath, err))
 			}
 			continue
 		}
@@ -338,22 +342,26 @@ func CountVideoFilesWithProgress(paths []string, pr *ProgressReporter) (int, err
 		err := filepath.Walk(libPath, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				if pr != nil {
-					pr.Send("warn", fmt.Sprintf("Error accessing %s: %v (continuing)", path, err))
+					pr.Send(SeverityWarn, fmt.Sprintf("Error accessing %s: %v (continuing)", path, err))
 				}
 				return nil
 			}
 
+			if skip, skipErr := walkSkip(libPath, path, info); skip {
+				return skipErr
+			}
+
 			if info.IsDir() {
 				directoriesScanned++
 				if pr != nil && directoriesScanned%100 == 0 {
-					pr.Send("info", fmt.Sprintf("Counting files... (%d found so far)", count))
+					pr.Send(SeverityInfo, fmt.Sprintf("Counting files... (%d found so far)", count))
 				}
 			}
 
 			if !info.IsDir() && isVideoFile(path) {
 				count++
 				if pr != nil && count%500 == 0 {
-					pr.Send("info", fmt.Sprintf("Counting files... (%d found so far)", count))
+					pr.Send(SeverityInfo, fmt.Sprintf("Counting files... (%d found so far)", count))
 				}
 			}
 
@@ -362,7 +370,7 @@ func CountVideoFilesWithProgress(paths []string, pr *ProgressReporter) (int, err
 
 		if err != nil {
 			if pr != nil {
-				pr.Send("warn", fmt.Sprintf("Error walking %s: %v (continuing)", libPath, err))
+				pr.Send(SeverityWarn, fmt.Sprintf("Error walking %s: %v (continuing)", libPath, err))
 			}
 		}
 	}
@@ -372,7 +380,7 @@ func CountVideoFilesWithProgress(paths []string, pr *ProgressReporter) (int, err
 	}
 
 	if len(skippedPaths) > 0 && pr != nil {
-		pr.Send("warn", fmt.Sprintf("Completed with %d skipped paths", len(skippedPaths)))
+		pr.Send(SeverityWarn, fmt.Sprintf("Completed with %d skipped paths", len(skippedPaths)))
 	}
 
 	return count, nil
@@ -40,3 +40,51 @@ func TestProgressReporting(t *testing.T) {
 		t.Errorf("Expected percentage 100, got %.2f", progress3.Percentage)
 	}
 }
+
+func TestProgressSeverityOrdering(t *testing.T) {
+	if !scanner.SeverityCritical.AtLeast(scanner.SeverityError) {
+		t.Error("expected critical >= error")
+	}
+	if scanner.SeverityDebug.AtLeast(scanner.SeverityInfo) {
+		t.Error("expected debug < info")
+	}
+	if !scanner.SeveritySuccess.AtLeast(scanner.SeverityInfo) || scanner.SeveritySuccess.AtLeast(scanner.SeverityWarn) {
+		t.Error("expected success to rank as info")
+	}
+	if !scanner.ProgressSeverity("").AtLeast(scanner.SeverityInfo) {
+		t.Error("expected empty severity to rank as info")
+	}
+
+	if sev, err := scanner.ParseProgressSeverity("Warning"); err != nil || sev != scanner.SeverityWarn {
+		t.Errorf("expected warning to parse as warn, got %q (%v)", sev, err)
+	}
+	if _, err := scanner.ParseProgressSeverity("loud"); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+
+	if scanner.LogLevelQuiet.MinSeverity() != scanner.SeverityError {
+		t.Errorf("expected quiet to map to error, got %s", scanner.LogLevelQuiet.MinSeverity())
+	}
+}
+
+func TestFilterProgress(t *testing.T) {
+	in := make(chan scanner.ScanProgress, 10)
+	in <- scanner.ScanProgress{Operation: scanner.OpScanningMovies, Severity: scanner.SeverityInfo, Message: "movies info"}
+	in <- scanner.ScanProgress{Operation: scanner.OpScanningMovies, Severity: scanner.SeverityError, Message: "movies error"}
+	in <- scanner.ScanProgress{Operation: scanner.OpScanningTV, Severity: scanner.SeverityCritical, Message: "tv critical"}
+	close(in)
+
+	filter := scanner.ProgressFilter{
+		MinSeverity: scanner.SeverityWarn,
+		Operations:  []scanner.ProgressOperation{scanner.OpScanningMovies},
+	}
+
+	var got []string
+	for p := range scanner.FilterProgress(in, filter) {
+		got = append(got, p.Message)
+	}
+
+	if len(got) != 1 || got[0] != "movies error" {
+		t.Errorf("expected only 'movies error', got %v", got)
+	}
+}
@@ -0,0 +1,140 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProgressSeverity is the severity of a progress message
+type ProgressSeverity string
+
+// Progress severities from least to most severe
+const (
+	SeverityDebug    ProgressSeverity = "debug"
+	SeverityInfo     ProgressSeverity = "info"
+	SeverityWarn     ProgressSeverity = "warn"
+	SeverityError    ProgressSeverity = "error"
+	SeverityCritical ProgressSeverity = "critical"
+
+	// SeveritySuccess marks a completed action; it ranks as info
+	SeveritySuccess ProgressSeverity = "success"
+)
+
+// ProgressOperation identifies which operation a progress message belongs to
+type ProgressOperation string
+
+// Known progress operations
+const (
+	OpIndexing          ProgressOperation = "indexing"          // incremental scans comparing files with the scan index
+	OpWalkingLibraries  ProgressOperation = "walking_libraries" // the one walk the duplicate and compliance passes read
+	OpScanningMovies    ProgressOperation = "scanning_movies"
+	OpScanningTV        ProgressOperation = "scanning_tv"
+	OpProbingMedia      ProgressOperation = "probing_media"
+	OpComplianceMovies  ProgressOperation = "compliance_movies"
+	OpComplianceTV      ProgressOperation = "compliance_tv"
+	OpLooseFiles        ProgressOperation = "loose_files"
+	OpScanningArtifacts ProgressOperation = "scanning_artifacts"
+	OpReportGeneration  ProgressOperation = "report_generation"
+	OpCleaning          ProgressOperation = "cleaning"
+	OpBatchRename       ProgressOperation = "batch_rename"
+	OpBackupLibrary     ProgressOperation = "backup_library"
+	OpVerifyBackup      ProgressOperation = "verify_backup"
+	OpRevertBackup      ProgressOperation = "revert_backup"
+	OpJellyfinCompare   ProgressOperation = "jellyfin_compare"
+	OpScan              ProgressOperation = "scan" // Whole-scan messages emitted by UIs
+	OpUnknown           ProgressOperation = ""
+)
+
+// ParseProgressSeverity validates a severity name (case-insensitive)
+func ParseProgressSeverity(s string) (ProgressSeverity, error) {
+	switch ProgressSeverity(strings.ToLower(strings.TrimSpace(s))) {
+	case SeverityDebug:
+		return SeverityDebug, nil
+	case SeverityInfo:
+		return SeverityInfo, nil
+	case SeverityWarn, "warning":
+		return SeverityWarn, nil
+	case SeverityError:
+		return SeverityError, nil
+	case SeverityCritical:
+		return SeverityCritical, nil
+	default:
+		return SeverityInfo, fmt.Errorf("invalid progress severity: %s (must be debug, info, warn, error, or critical)", s)
+	}
+}
+
+// Rank orders severities; unknown or empty severities rank as info
+func (s ProgressSeverity) Rank() int {
+	switch s {
+	case SeverityDebug:
+		return 0
+	case SeverityWarn:
+		return 2
+	case SeverityError:
+		return 3
+	case SeverityCritical:
+		return 4
+	default:
+		return 1
+	}
+}
+
+// AtLeast reports whether s is at or above min
+func (s ProgressSeverity) AtLeast(min ProgressSeverity) bool {
+	return s.Rank() >= min.Rank()
+}
+
+// IsError reports whether s is error or critical
+func (s ProgressSeverity) IsError() bool {
+	return s.AtLeast(SeverityError)
+}
+
+// MinSeverity returns the lowest severity a log level lets through
+func (l LogLevel) MinSeverity() ProgressSeverity {
+	switch l {
+	case LogLevelQuiet:
+		return SeverityError
+	case LogLevelVerbose:
+		return SeverityDebug
+	default:
+		return SeverityInfo
+	}
+}
+
+// ProgressFilter selects progress messages by severity and operation
+// Used by the CLI, TUI and daemon so each output channel can have its own minimum
+type ProgressFilter struct {
+	MinSeverity ProgressSeverity
+	Operations  []ProgressOperation // Empty means all operations
+}
+
+// Allows reports whether the filter passes the progress message
+func (f ProgressFilter) Allows(p ScanProgress) bool {
+	if !p.Severity.AtLeast(f.MinSeverity) {
+		return false
+	}
+	if len(f.Operations) == 0 {
+		return true
+	}
+	for _, op := range f.Operations {
+		if op == p.Operation {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterProgress forwards messages from in to a new channel when the filter
+// allows them. The returned channel is closed once in is closed
+func FilterProgress(in <-chan ScanProgress, filter ProgressFilter) <-chan ScanProgress {
+	out := make(chan ScanProgress, cap(in))
+	go func() {
+		defer close(out)
+		for p := range in {
+			if filter.Allows(p) {
+				out <- p
+			}
+		}
+	}()
+	return out
+}
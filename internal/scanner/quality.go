@@ -0,0 +1,215 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Library-quality findings are informational: they point at files worth a
+// look, and cleans never act on them
+const (
+	QualityMissingAudio     = "missing_audio"     // no audio track in a required language
+	QualityMissingSubtitles = "missing_subtitles" // no subtitle track in a required language
+	QualityUnwantedTracks   = "unwanted_tracks"   // more unwanted language tracks than allowed
+	QualityBitrateOutlier   = "bitrate_outlier"   // an episode far larger or smaller per minute than the rest of its season
+)
+
+// QualityFinding is a library-quality note about one video file
+type QualityFinding struct {
+	Path   string
+	Type   string // "movie" or "tv"
+	Kind   string // one of the Quality* kinds
+	Detail string // what was found, e.g. "no eng audio (has jpn)"
+}
+
+// minOutlierSeason is the fewest episodes a season needs before its
+// episodes are compared with one another
+const minOutlierSeason = 3
+
+var (
+	bitrateOutlierFactor   float64
+	bitrateOutlierFactorMu sync.RWMutex
+)
+
+// SetBitrateOutlierFactor sets how many times larger or smaller per minute
+// than its season's median an episode must be to be reported; 0 turns the
+// check off
+func SetBitrateOutlierFactor(factor float64) {
+	bitrateOutlierFactorMu.Lock()
+	defer bitrateOutlierFactorMu.Unlock()
+	bitrateOutlierFactor = factor
+}
+
+// GetBitrateOutlierFactor returns the factor set with SetBitrateOutlierFactor
+func GetBitrateOutlierFactor() float64 {
+	bitrateOutlierFactorMu.RLock()
+	defer bitrateOutlierFactorMu.RUnlock()
+	return bitrateOutlierFactor
+}
+
+// qualityEnabled reports whether a scan has quality checks to run
+func qualityEnabled() bool {
+	return GetLanguageRules().Enabled() || GetBitrateOutlierFactor() > 0
+}
+
+// qualityVideo is a video file the quality checks look at
+type qualityVideo struct {
+	path      string
+	mediaType string
+	size      int64
+}
+
+// scanQualityInventory probes the videos an inventory holds below the movie
+// and TV libraries, checks their streams against the language rules and
+// compares the episodes of each season. known returns the probe an earlier
+// scan kept for an unchanged file, or nil; the files probed now are
+// returned so the scan index can keep them
+func scanQualityInventory(ctx context.Context, inv *inventory, moviePaths, tvPaths []string, known func(string) *MediaInfo, progressCh chan<- ScanProgress) ([]QualityFinding, map[string]*MediaInfo) {
+	rules := GetLanguageRules()
+	factor := GetBitrateOutlierFactor()
+	if !rules.Enabled() && factor <= 0 {
+		return nil, nil
+	}
+
+	// Only the language rules look at movies
+	type library struct {
+		paths     []string
+		mediaType string
+	}
+	var libraries []library
+	if rules.Enabled() {
+		libraries = append(libraries, library{moviePaths, "movie"})
+	}
+	libraries = append(libraries, library{tvPaths, "tv"})
+	var videos []qualityVideo
+	for _, libs := range libraries {
+		for _, libPath := range libs.paths {
+			lib := inv.library(libPath)
+			if lib.err != nil {
+				continue
+			}
+			for _, file := range lib.files {
+				if isVideoFile(file.path) && !isSampleFile(file.path) && !isExtraFile(file.path, file.info.Size()) && file.info.Size() > 0 {
+					videos = append(videos, qualityVideo{file.path, libs.mediaType, file.info.Size()})
+				}
+			}
+		}
+	}
+
+	// Without ffprobe the seasons are still compared, by size alone
+	infos := make(map[string]*MediaInfo, len(videos))
+	var probed map[string]*MediaInfo
+	if ffprobeBinary() != "" {
+		var paths []string
+		for _, v := range videos {
+			if info := known(v.path); info != nil && info.hasLanguages() {
+				infos[v.path] = info
+			} else {
+				paths = append(paths, v.path)
+			}
+		}
+		probed = probeFiles(ctx, paths, "video files", progressCh)
+		for path, info := range probed {
+			infos[path] = info
+		}
+	}
+
+	var findings []QualityFinding
+	if rules.Enabled() {
+		for _, v := range videos {
+			if info := infos[v.path]; info != nil {
+				findings = append(findings, checkLanguages(v.path, v.mediaType, info, rules)...)
+			}
+		}
+	}
+	if factor > 0 {
+		var episodes []qualityVideo
+		for _, v := range videos {
+			if v.mediaType == "tv" {
+				episodes = append(episodes, v)
+			}
+		}
+		findings = append(findings, bitrateOutliers(episodes, infos, factor)...)
+	}
+
+	if progressCh != nil {
+		pr := NewProgressReporterWithInterval(progressCh, OpProbingMedia, 200*time.Millisecond)
+		pr.SendSeverityImmediate(SeverityInfo, fmt.Sprintf("Library quality: %d findings in %d video files", len(findings), len(videos)))
+	}
+	return findings, probed
+}
+
+// bitrateOutliers reports the episodes whose size per minute is more than
+// factor times above or below the median of their season folder: a
+// low-quality fill-in, or a remux among encodes. Seasons are compared by
+// size per minute when every episode has a probed duration, and by plain
+// size otherwise, since the episodes of a season run about as long
+func bitrateOutliers(episodes []qualityVideo, infos map[string]*MediaInfo, factor float64) []QualityFinding {
+	var seasons []string
+	bySeason := make(map[string][]qualityVideo)
+	for _, episode := range episodes {
+		season := filepath.Dir(episode.path)
+		if _, ok := bySeason[season]; !ok {
+			seasons = append(seasons, season)
+		}
+		bySeason[season] = append(bySeason[season], episode)
+	}
+
+	var findings []QualityFinding
+	for _, season := range seasons {
+		episodes := bySeason[season]
+		if len(episodes) < minOutlierSeason {
+			continue
+		}
+
+		perMinute := true
+		for _, episode := range episodes {
+			if info := infos[episode.path]; info == nil || info.Duration < time.Minute {
+				perMinute = false
+				break
+			}
+		}
+		rates := make([]float64, len(episodes))
+		for i, episode := range episodes {
+			rates[i] = float64(episode.size) / (1 << 20)
+			if perMinute {
+				rates[i] /= infos[episode.path].Duration.Minutes()
+			}
+		}
+		sorted := append([]float64(nil), rates...)
+		sort.Float64s(sorted)
+		median := sorted[len(sorted)/2]
+		if len(sorted)%2 == 0 {
+			median = (sorted[len(sorted)/2-1] + median) / 2
+		}
+
+		unit := "MB"
+		if perMinute {
+			unit = "MB/min"
+		}
+		for i, episode := range episodes {
+			var direction string
+			var ratio float64
+			switch {
+			case rates[i] > median*factor:
+				direction, ratio = "larger", rates[i]/median
+			case rates[i] < median/factor:
+				direction, ratio = "smaller", median/rates[i]
+			default:
+				continue
+			}
+			findings = append(findings, QualityFinding{
+				Path: episode.path,
+				Type: "tv",
+				Kind: QualityBitrateOutlier,
+				Detail: fmt.Sprintf("%.1f %s, %.1fx %s than the season median of %.1f %s",
+					rates[i], unit, ratio, direction, median, unit),
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,59 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBitrateOutliers(t *testing.T) {
+	mb := int64(1 << 20)
+	season := "/tv/Lost (2004)/Season 01/"
+	episodes := []qualityVideo{
+		{season + "Lost (2004) S01E01.mkv", "tv", 1400 * mb},
+		{season + "Lost (2004) S01E02.mkv", "tv", 700 * mb},
+		{season + "Lost (2004) S01E03.mkv", "tv", 720 * mb},
+		{season + "Lost (2004) S01E04.mkv", "tv", 9000 * mb}, // a remux among encodes
+		{season + "Lost (2004) S01E05.mkv", "tv", 150 * mb},  // a low-quality fill-in
+		// Too few episodes to compare
+		{"/tv/Lost (2004)/Specials/Lost (2004) S00E01.mkv", "tv", 100 * mb},
+		{"/tv/Lost (2004)/Specials/Lost (2004) S00E02.mkv", "tv", 5000 * mb},
+	}
+	infos := map[string]*MediaInfo{
+		season + "Lost (2004) S01E01.mkv": {Duration: 86 * time.Minute}, // a double-length pilot
+		season + "Lost (2004) S01E02.mkv": {Duration: 43 * time.Minute},
+		season + "Lost (2004) S01E03.mkv": {Duration: 44 * time.Minute},
+		season + "Lost (2004) S01E04.mkv": {Duration: 43 * time.Minute},
+		season + "Lost (2004) S01E05.mkv": {Duration: 42 * time.Minute},
+	}
+
+	flagged := func(infos map[string]*MediaInfo) map[string]string {
+		t.Helper()
+		found := make(map[string]string)
+		for _, finding := range bitrateOutliers(episodes, infos, 3) {
+			if finding.Kind != QualityBitrateOutlier || finding.Type != "tv" {
+				t.Errorf("Unexpected finding %+v", finding)
+			}
+			found[strings.TrimPrefix(finding.Path, season)] = finding.Detail
+		}
+		return found
+	}
+
+	// By size per minute the long pilot is in line with the others
+	found := flagged(infos)
+	if len(found) != 2 || found["Lost (2004) S01E04.mkv"] == "" || found["Lost (2004) S01E05.mkv"] == "" {
+		t.Fatalf("Expected the remux and the fill-in flagged, got %v", found)
+	}
+	if detail := found["Lost (2004) S01E04.mkv"]; !strings.Contains(detail, "MB/min") || !strings.Contains(detail, "larger") {
+		t.Errorf("Unexpected detail %q", detail)
+	}
+	if detail := found["Lost (2004) S01E05.mkv"]; !strings.Contains(detail, "smaller") {
+		t.Errorf("Unexpected detail %q", detail)
+	}
+
+	// Without durations the season is compared by size
+	found = flagged(nil)
+	if len(found) != 2 || !strings.HasSuffix(found["Lost (2004) S01E04.mkv"], " MB") {
+		t.Errorf("Expected the season compared by size, got %v", found)
+	}
+}
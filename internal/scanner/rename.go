@@ -1,12 +1,15 @@
 package scanner
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
 )
 
 // RenameResult tracks a single rename operation
@@ -157,7 +160,7 @@ func PreviewTVRename(basePath, oldTitle, newTitle string) (*RenamePreview, error
 
 	if preview.MatchCount == 0 {
 		preview.ErrorMessage = fmt.Sprintf("no folders matching '%s' found in %s", oldTitle, basePath)
-		return preview, fmt.Errorf(preview.ErrorMessage)
+		return preview, errors.New(preview.ErrorMessage)
 	}
 
 	// Check for duplicate target paths (multiple sources renaming to same destination)
@@ -188,6 +191,10 @@ func countEpisodesInFolder(folderPath string) int {
 		if err != nil {
 			return nil
 		}
+
+		if skip, skipErr := walkSkip(folderPath, path, info); skip {
+			return skipErr
+		}
 		if !info.IsDir() && isVideoFile(path) && episodePattern.MatchString(filepath.Base(path)) {
 			count++
 		}
@@ -197,6 +204,30 @@ func countEpisodesInFolder(folderPath string) int {
 	return count
 }
 
+// LogRenames appends rename results to the rename log at path
+func LogRenames(path string, results []RenameResult) error {
+	now := time.Now()
+	entries := make([]oplog.Entry, 0, len(results))
+	for _, r := range results {
+		entry := oplog.Entry{
+			Timestamp: now,
+			Op:        "rename",
+			OldPath:   r.OldPath,
+			NewPath:   r.NewPath,
+			Result:    oplog.ResultOK,
+		}
+		if r.IsFolder {
+			entry.Op = "rename-folder"
+		}
+		if !r.Success {
+			entry.Result = oplog.ResultFailed
+			entry.Error = r.Error
+		}
+		entries = append(entries, entry)
+	}
+	return oplog.Append(path, entries...)
+}
+
 // ApplyManualTVRename renames folders and episode files for a TV show
 func ApplyManualTVRename(basePath, oldTitle, newTitle string, dryRun bool) ([]RenameResult, error) {
 	return ApplyManualTVRenameWithProgress(basePath, oldTitle, newTitle, dryRun, nil)
@@ -234,7 +265,7 @@ func ApplyManualTVRenameWithProgress(basePath, oldTitle, newTitle string, dryRun
 		if snapshot != nil {
 			snapshot.Metadata.Status = "completed"
 			if err := snapshot.Save(); err != nil && pr != nil {
-				pr.Send("warn", fmt.Sprintf("Failed to save rename log: %v", err))
+				pr.Send(SeverityWarn, fmt.Sprintf("Failed to save rename log: %v", err))
 			}
 		}
 	}()
@@ -281,7 +312,7 @@ func ApplyManualTVRenameWithProgress(basePath, oldTitle, newTitle string, dryRun
 	if err != nil {
 		// If symlink resolution fails, use cleaned path but log warning
 		if pr != nil {
-			pr.Send("warn", fmt.Sprintf("Could not resolve symlinks for %s: %v", basePath, err))
+			pr.Send(SeverityWarn, fmt.Sprintf("Could not resolve symlinks for %s: %v", basePath, err))
 		}
 		realBasePath = basePath
 	}
@@ -318,9 +349,9 @@ func ApplyManualTVRenameWithProgress(basePath, oldTitle, newTitle string, dryRun
 			errMsg = fmt.Sprintf("library validation failed: %s", report.ErrorMessage)
 		}
 		if pr != nil {
-			pr.LogError(fmt.Errorf(errMsg), "SAFETY CHECK FAILED: Library validation")
+			pr.LogError(errors.New(errMsg), "SAFETY CHECK FAILED: Library validation")
 		}
-		return results, fmt.Errorf(errMsg)
+		return results, errors.New(errMsg)
 	}
 
 	// Use realBasePath for all subsequent operations
@@ -369,6 +400,22 @@ func ApplyManualTVRenameWithProgress(basePath, oldTitle, newTitle string, dryRun
 		newFolderName := fmt.Sprintf("%s (%s)", newTitle, year)
 		newFolderPath := filepath.Join(basePath, newFolderName)
 
+		// Frozen shows keep their names, as does everything inside them
+		if reason := FrozenBy(path); reason != "" {
+			err := fmt.Errorf("refusing to rename %s: %s", path, reason)
+			if pr != nil {
+				pr.LogError(err, "Show is frozen")
+			}
+			results = append(results, RenameResult{
+				OldPath:  path,
+				NewPath:  newFolderPath,
+				IsFolder: true,
+				Success:  false,
+				Error:    reason,
+			})
+			continue
+		}
+
 		// Check if target path already exists (and is not the same as source)
 		if _, err := os.Stat(newFolderPath); err == nil && newFolderPath != path {
 			err := fmt.Errorf("target path already exists: %s", newFolderPath)
@@ -451,6 +498,13 @@ func ApplyManualTVRenameWithProgress(basePath, oldTitle, newTitle string, dryRun
 		return results, err
 	}
 
+	// Library managers follow the renamed folders (SetRenameNotifier)
+	if !dryRun {
+		if err := NotifyRenames(results); err != nil && pr != nil {
+			pr.Send(SeverityWarn, fmt.Sprintf("Library manager not updated: %v", err))
+		}
+	}
+
 	if pr != nil {
 		pr.Complete(fmt.Sprintf("Rename complete: %d operations", len(results)))
 	}
@@ -463,10 +517,14 @@ func renameEpisodesInFolder(folderPath, oldTitle, newTitle string, dryRun bool)
 	return renameEpisodesInFolderWithProgress(folderPath, oldTitle, newTitle, dryRun, nil, nil)
 }
 
-// renameEpisodesInFolderWithProgress renames all episode files inside a folder with progress reporting
+// renameEpisodesInFolderWithProgress renames all episode files inside a folder with progress reporting.
+// Subtitles, nfo and artwork named after an episode are renamed with it
 func renameEpisodesInFolderWithProgress(folderPath, oldTitle, newTitle string, dryRun bool, snapshot *BackupSnapshot, pr *ProgressReporter) ([]RenameResult, error) {
 	var results []RenameResult
 
+	// Collect the episodes first: renaming sidecars mid-walk would pull
+	// files out from under the walk
+	var episodes []string
 	err := filepath.Walk(folderPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			if pr != nil {
@@ -475,78 +533,98 @@ func renameEpisodesInFolderWithProgress(folderPath, oldTitle, newTitle string, d
 			return err
 		}
 
-		if info.IsDir() {
-			return nil
+		if skip, skipErr := walkSkip(folderPath, path, info); skip {
+			return skipErr
 		}
 
+		if !info.IsDir() && isVideoFile(path) {
+			episodes = append(episodes, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return results, err
+	}
+
+	episodePattern := regexp.MustCompile(`(?i)(S\d{2}E\d{2})`)
+	normalizedOld := strings.ToLower(oldTitle)
+
+	// rename moves one file, recording the outcome
+	rename := func(path, newPath string) bool {
+		if !dryRun {
+			if err := os.Rename(path, newPath); err != nil {
+				if pr != nil {
+					pr.LogError(err, fmt.Sprintf("Failed to rename: %s", filepath.Base(path)))
+				}
+				results = append(results, RenameResult{
+					OldPath:  path,
+					NewPath:  newPath,
+					IsFolder: false,
+					Success:  false,
+					Error:    err.Error(),
+				})
+				// Record failed episode rename
+				if snapshot != nil {
+					snapshot.RecordOperation("rename", path, newPath, false, err)
+				}
+				return false
+			}
+			// Record successful episode rename
+			if snapshot != nil {
+				snapshot.RecordOperation("rename", path, newPath, true, nil)
+			}
+		}
+
+		results = append(results, RenameResult{
+			OldPath:  path,
+			NewPath:  newPath,
+			IsFolder: false,
+			Success:  true,
+		})
+		return true
+	}
+
+	for _, path := range episodes {
 		fileName := filepath.Base(path)
 		ext := filepath.Ext(fileName)
 		nameWithoutExt := strings.TrimSuffix(fileName, ext)
 
-		episodePattern := regexp.MustCompile(`(?i)(S\d{2}E\d{2})`)
 		if !episodePattern.MatchString(nameWithoutExt) {
-			return nil
+			continue
 		}
 
 		normalizedFileName := strings.ToLower(nameWithoutExt)
-		normalizedOld := strings.ToLower(oldTitle)
-
-		if strings.Contains(normalizedFileName, normalizedOld) {
-			newFileName := strings.Replace(nameWithoutExt, oldTitle, newTitle, 1)
-
-			if strings.ToLower(nameWithoutExt) != strings.ToLower(newFileName) {
-				newFileName = nameWithoutExt
-				parts := episodePattern.Split(nameWithoutExt, -1)
-				episodeCode := episodePattern.FindString(nameWithoutExt)
-
-				if len(parts) > 0 && episodeCode != "" {
-					suffix := ""
-					if len(parts) > 1 {
-						suffix = parts[1]
-					}
-					newFileName = newTitle + " " + episodeCode + suffix
-				}
-			}
+		if !strings.Contains(normalizedFileName, normalizedOld) {
+			continue
+		}
 
-			newFileName = newFileName + ext
-			newPath := filepath.Join(filepath.Dir(path), newFileName)
-
-			if !dryRun {
-				if err := os.Rename(path, newPath); err != nil {
-					if pr != nil {
-						pr.LogError(err, fmt.Sprintf("Failed to rename: %s", fileName))
-					}
-					results = append(results, RenameResult{
-						OldPath:  path,
-						NewPath:  newPath,
-						IsFolder: false,
-						Success:  false,
-						Error:    err.Error(),
-					})
-					// Record failed episode rename
-					if snapshot != nil {
-						snapshot.RecordOperation("rename", path, newPath, false, err)
-					}
-					return nil
-				}
-				// Record successful episode rename
-				if snapshot != nil {
-					snapshot.RecordOperation("rename", path, newPath, true, nil)
+		newFileName := strings.Replace(nameWithoutExt, oldTitle, newTitle, 1)
+
+		if strings.ToLower(nameWithoutExt) != strings.ToLower(newFileName) {
+			newFileName = nameWithoutExt
+			parts := episodePattern.Split(nameWithoutExt, -1)
+			episodeCode := episodePattern.FindString(nameWithoutExt)
+
+			if len(parts) > 0 && episodeCode != "" {
+				suffix := ""
+				if len(parts) > 1 {
+					suffix = parts[1]
 				}
+				newFileName = newTitle + " " + episodeCode + suffix
 			}
-
-			results = append(results, RenameResult{
-				OldPath:  path,
-				NewPath:  newPath,
-				IsFolder: false,
-				Success:  true,
-			})
 		}
 
-		return nil
-	})
+		newPath := filepath.Join(filepath.Dir(path), newFileName+ext)
+		companions := CompanionMoves(path, newPath)
+		if !rename(path, newPath) {
+			continue
+		}
+		for _, companion := range companions {
+			rename(companion.Source, companion.Target)
+		}
+	}
 
-	return results, err
+	return results, nil
 }
 
 // ValidateTVShowTitle checks if a title is valid for use
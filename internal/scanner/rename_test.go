@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
 )
 
 func TestValidateTVShowTitle(t *testing.T) {
@@ -172,3 +174,25 @@ func TestRenameEpisodesInFolder(t *testing.T) {
 		}
 	}
 }
+
+func TestLogRenames(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "rename.log")
+	results := []RenameResult{
+		{OldPath: "/tv/Show", NewPath: "/tv/Show (2020)", IsFolder: true, Success: true},
+		{OldPath: "/tv/Show (2020)/a.mkv", NewPath: "/tv/Show (2020)/b.mkv", Error: "permission denied"},
+	}
+	if err := LogRenames(logPath, results); err != nil {
+		t.Fatalf("LogRenames() error: %v", err)
+	}
+
+	entries, err := oplog.Read(logPath)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Expected 2 log entries, got %+v, %v", entries, err)
+	}
+	if entries[0].Op != "rename-folder" || entries[0].Result != oplog.ResultOK || entries[0].NewPath != "/tv/Show (2020)" {
+		t.Errorf("Unexpected folder entry: %+v", entries[0])
+	}
+	if entries[1].Op != "rename" || entries[1].Result != oplog.ResultFailed || entries[1].Error != "permission denied" {
+		t.Errorf("Unexpected failed entry: %+v", entries[1])
+	}
+}
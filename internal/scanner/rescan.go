@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+)
+
+// RescanFolders rescans only the top-level library folders holding paths,
+// such as the files a clean or batch rename just changed, and takes every
+// other finding from previous. Every folder of a duplicate group with a
+// copy in one of them is rescanned too. Artifacts are not searched for
+// again; those still on disk are kept. Returns nil when no path is inside
+// the libraries
+func RescanFolders(ctx context.Context, moviePaths, tvPaths, paths []string, previous *ScanResult, progressCh chan<- ScanProgress) (*ScanResult, error) {
+	scope := &scanScope{roots: make(map[string]bool), dirty: make(map[string]bool), videoRoots: make(map[string]bool)}
+	for _, root := range moviePaths {
+		scope.roots[root] = true
+	}
+	for _, root := range tvPaths {
+		scope.roots[root] = true
+	}
+	for _, path := range paths {
+		scope.markVideo(scope.unit(path))
+	}
+	if len(scope.dirty) == 0 {
+		return nil, nil
+	}
+	if previous == nil {
+		previous = &ScanResult{}
+	}
+
+	inc := &incrementalScan{scope: scope, files: make(map[string]*IndexEntry), previous: previous, artifacts: true}
+	markGroups(scope, previous)
+	// Copies that were not touched keep their ffprobe metadata
+	for _, dup := range previous.MovieDuplicates {
+		for _, file := range dup.Files {
+			if file.Probe != nil {
+				inc.files[file.Path] = &IndexEntry{Size: file.Size, Probe: file.Probe}
+			}
+		}
+	}
+	for _, dup := range previous.TVDuplicates {
+		for _, file := range dup.Files {
+			if file.Probe != nil {
+				inc.files[file.Path] = &IndexEntry{Size: file.Size, Probe: file.Probe}
+			}
+		}
+	}
+
+	if progressCh != nil {
+		NewProgressReporter(progressCh, OpIndexing).Complete(fmt.Sprintf("Rescanning %d changed folder(s)", len(scope.dirty)))
+	}
+
+	setScanScope(scope)
+	defer setScanScope(nil)
+
+	return runScan(ctx, moviePaths, tvPaths, inc, nil, progressCh)
+}
+
+// markGroups marks every folder of a previous duplicate group with a copy
+// in a folder being rescanned, until no group straddles the two
+func markGroups(scope *scanScope, previous *ScanResult) {
+	var groups [][]string
+	for _, dup := range previous.MovieDuplicates {
+		paths := make([]string, len(dup.Files))
+		for i, file := range dup.Files {
+			paths[i] = file.Path
+		}
+		groups = append(groups, paths)
+	}
+	for _, dup := range previous.TVDuplicates {
+		paths := make([]string, len(dup.Files))
+		for i, file := range dup.Files {
+			paths[i] = file.Path
+		}
+		groups = append(groups, paths)
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, paths := range groups {
+			touched := false
+			for _, path := range paths {
+				if unit := scope.unit(path); unit != "" && scope.dirty[unit] {
+					touched = true
+					break
+				}
+			}
+			if !touched {
+				continue
+			}
+			for _, path := range paths {
+				if unit := scope.unit(path); unit != "" && !scope.dirty[unit] {
+					scope.markVideo(unit)
+					changed = true
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRescanFolders(t *testing.T) {
+	root := t.TempDir()
+	movies, tv := filepath.Join(root, "movies"), filepath.Join(root, "tv")
+	write := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("movies/Heat (1995)/Heat (1995).mkv", "a longer keeper")
+	write("movies/Heat.1995.720p-GRP/Heat.1995.720p-GRP.mkv", "copy")
+	write("movies/Big.Fish.2003-GRP/Big.Fish.2003-GRP.mkv", "fish")
+	write("movies/Alien (1979)/Alien (1979).mkv", "alien")
+	write("tv/Lost (2004)/Season 01/Lost (2004) S01E01.mkv", "pilot")
+
+	ctx := context.Background()
+	previous, err := RunFullScan(ctx, []string{movies}, []string{tv}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(previous.MovieDuplicates) != 1 {
+		t.Fatalf("Expected the Heat copies grouped, got %+v", previous.MovieDuplicates)
+	}
+	flagged := false
+	for _, issue := range previous.ComplianceIssues {
+		flagged = flagged || filepath.Base(issue.Path) == "Big.Fish.2003-GRP.mkv"
+	}
+	if !flagged {
+		t.Fatal("Expected the Big Fish release folder flagged")
+	}
+	// Untouched folders are not walked, so their findings are carried over
+	planted := ComplianceIssue{Path: filepath.Join(movies, "Alien (1979)", "Alien (1979).mkv"), Type: "movie", Problem: "planted"}
+	previous.ComplianceIssues = append(previous.ComplianceIssues, planted)
+	gone := Artifact{Path: filepath.Join(movies, "Heat (1995)", "Thumbs.db")}
+	kept := Artifact{Path: filepath.Join(movies, "Alien (1979)")}
+	previous.Artifacts = []Artifact{gone, kept}
+
+	// Paths outside the libraries leave nothing to rescan
+	if result, err := RescanFolders(ctx, []string{movies}, []string{tv}, []string{filepath.Join(root, "elsewhere", "file.mkv")}, previous, nil); result != nil || err != nil {
+		t.Errorf("Expected no rescan outside the libraries, got %+v, %v", result, err)
+	}
+
+	// A clean deleted the Heat copy and fixed Big Fish
+	copyPath := filepath.Join(movies, "Heat.1995.720p-GRP", "Heat.1995.720p-GRP.mkv")
+	oldFish := filepath.Join(movies, "Big.Fish.2003-GRP", "Big.Fish.2003-GRP.mkv")
+	newFish := filepath.Join(movies, "Big Fish (2003)", "Big Fish (2003).mkv")
+	os.RemoveAll(filepath.Dir(copyPath))
+	os.MkdirAll(filepath.Dir(newFish), 0755)
+	if err := os.Rename(oldFish, newFish); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(filepath.Dir(oldFish))
+
+	result, err := RescanFolders(ctx, []string{movies}, []string{tv}, []string{copyPath, oldFish, newFish}, previous, nil)
+	if err != nil {
+		t.Fatalf("RescanFolders() failed: %v", err)
+	}
+	if len(result.MovieDuplicates) != 0 {
+		t.Errorf("Expected the Heat group resolved, got %+v", result.MovieDuplicates)
+	}
+	paths := make(map[string]bool)
+	for _, issue := range result.ComplianceIssues {
+		paths[issue.Path] = true
+	}
+	if paths[oldFish] || paths[newFish] {
+		t.Errorf("Expected Big Fish fixed, got %v", paths)
+	}
+	if !paths[planted.Path] {
+		t.Error("Expected the untouched folder's finding kept")
+	}
+	if len(result.Artifacts) != 1 || result.Artifacts[0].Path != kept.Path {
+		t.Errorf("Expected only the artifact still on disk kept, got %+v", result.Artifacts)
+	}
+	if outOfScope(movies, planted.Path) {
+		t.Error("Expected the scan scope cleared after the rescan")
+	}
+}
+
+func TestRescanFoldersFollowsDuplicateGroups(t *testing.T) {
+	root := t.TempDir()
+	movies := filepath.Join(root, "movies")
+	for _, rel := range []string{
+		"Heat (1995)/Heat (1995).mkv",
+		"Heat.1995.720p-GRP/Heat.1995.720p-GRP.mkv",
+		"Heat.1995.480p-OLD/Heat.1995.480p-OLD.mkv",
+	} {
+		path := filepath.Join(movies, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(rel), 0644)
+	}
+
+	ctx := context.Background()
+	previous, err := RunFullScan(ctx, []string{movies}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(previous.MovieDuplicates) != 1 || len(previous.MovieDuplicates[0].Files) != 3 {
+		t.Fatalf("Expected three Heat copies grouped, got %+v", previous.MovieDuplicates)
+	}
+
+	// Only one copy went; the two left in folders nobody touched are
+	// still duplicates of each other
+	removed := filepath.Join(movies, "Heat.1995.480p-OLD", "Heat.1995.480p-OLD.mkv")
+	os.RemoveAll(filepath.Dir(removed))
+	result, err := RescanFolders(ctx, []string{movies}, nil, []string{removed}, previous, nil)
+	if err != nil {
+		t.Fatalf("RescanFolders() failed: %v", err)
+	}
+	if len(result.MovieDuplicates) != 1 || len(result.MovieDuplicates[0].Files) != 2 {
+		t.Errorf("Expected the two remaining copies regrouped, got %+v", result.MovieDuplicates)
+	}
+}
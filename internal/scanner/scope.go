@@ -0,0 +1,98 @@
+package scanner
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DuplicateScope limits which copies of a title are grouped as duplicates
+type DuplicateScope string
+
+const (
+	// ScopeSameFolder only groups copies in the same folder (safest)
+	ScopeSameFolder DuplicateScope = "same-folder"
+	// ScopeLibrary groups copies anywhere in the same library path
+	ScopeLibrary DuplicateScope = "library"
+	// ScopeCrossLibrary groups copies across every library path of the same type
+	ScopeCrossLibrary DuplicateScope = "cross-library"
+)
+
+// DuplicateScopes holds the scope movie and TV duplicates are grouped in
+type DuplicateScopes struct {
+	Movies DuplicateScope
+	TV     DuplicateScope
+}
+
+var (
+	duplicateScopes   = DuplicateScopes{Movies: ScopeCrossLibrary, TV: ScopeCrossLibrary}
+	duplicateScopesMu sync.RWMutex
+)
+
+// ParseDuplicateScope validates a scope name (case-insensitive)
+func ParseDuplicateScope(s string) (DuplicateScope, error) {
+	switch scope := DuplicateScope(strings.ToLower(strings.TrimSpace(s))); scope {
+	case ScopeSameFolder, ScopeLibrary, ScopeCrossLibrary:
+		return scope, nil
+	default:
+		return "", fmt.Errorf("invalid duplicate scope: %s (must be same-folder, library or cross-library)", s)
+	}
+}
+
+// SetDuplicateScopes sets the scopes duplicate scans group copies in
+func SetDuplicateScopes(scopes DuplicateScopes) {
+	duplicateScopesMu.Lock()
+	defer duplicateScopesMu.Unlock()
+	duplicateScopes = scopes
+}
+
+// GetDuplicateScopes returns the scopes duplicate scans group copies in
+func GetDuplicateScopes() DuplicateScopes {
+	duplicateScopesMu.RLock()
+	defer duplicateScopesMu.RUnlock()
+	return duplicateScopes
+}
+
+// Label describes the scope in reports. Groups from reports written
+// before scopes existed were grouped across libraries
+func (s DuplicateScope) Label() string {
+	switch s {
+	case ScopeSameFolder:
+		return "same folder"
+	case ScopeLibrary:
+		return "same library"
+	default:
+		return "across libraries"
+	}
+}
+
+// Describe labels a group confined to within, e.g. "same library (/mnt/movies)"
+func (s DuplicateScope) Describe(within string) string {
+	if within == "" {
+		return s.Label()
+	}
+	return fmt.Sprintf("%s (%s)", s.Label(), within)
+}
+
+// within returns the folder or library path that confines a copy at path
+// found in libPath; cross-library groups are not confined
+func (s DuplicateScope) within(libPath, path string) string {
+	switch s {
+	case ScopeSameFolder:
+		return filepath.Dir(path)
+	case ScopeLibrary:
+		return libPath
+	default:
+		return ""
+	}
+}
+
+// scopedGroupKey prefixes key with the path that confines the group, so
+// copies outside the scope get a group of their own
+func scopedGroupKey(within, key string) string {
+	if within == "" {
+		return key
+	}
+	return within + "|" + key
+}
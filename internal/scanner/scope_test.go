@@ -0,0 +1,80 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDuplicateScopes(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel string) {
+		path := filepath.Join(root, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Two Heat copies side by side plus one in each of two libraries
+	write("movies/Heat (1995)/Heat (1995).mkv")
+	write("movies/Heat (1995)/Heat.1995.720p.mkv")
+	write("movies/Heat.1995.1080p-GRP/Heat.1995.1080p-GRP.mkv")
+	write("movies-4k/Heat (1995)/Heat (1995).mkv")
+	write("tv/Lost/Season 01/Lost S01E01.mkv")
+	write("tv-archive/Lost/Season 01/Lost S01E01.mkv")
+	libraries := []string{filepath.Join(root, "movies"), filepath.Join(root, "movies-4k")}
+	shows := []string{filepath.Join(root, "tv"), filepath.Join(root, "tv-archive")}
+
+	t.Cleanup(func() { SetDuplicateScopes(DuplicateScopes{Movies: ScopeCrossLibrary, TV: ScopeCrossLibrary}) })
+	tests := []struct {
+		scope      DuplicateScope
+		groupSizes []int  // movie group sizes, sorted by path
+		within     string // first movie group's confinement
+		tvGroups   int
+	}{
+		{ScopeCrossLibrary, []int{4}, "", 1},
+		{ScopeLibrary, []int{3}, libraries[0], 0},
+		{ScopeSameFolder, []int{2}, filepath.Join(libraries[0], "Heat (1995)"), 0},
+	}
+	for _, tt := range tests {
+		SetDuplicateScopes(DuplicateScopes{Movies: tt.scope, TV: tt.scope})
+		movies, err := ScanMovies(libraries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		episodes, err := ScanTVShows(shows)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(movies) != len(tt.groupSizes) {
+			t.Fatalf("%s: got %d movie groups, want %d", tt.scope, len(movies), len(tt.groupSizes))
+		}
+		for i, size := range tt.groupSizes {
+			if len(movies[i].Files) != size {
+				t.Errorf("%s: group %d has %d copies, want %d", tt.scope, i, len(movies[i].Files), size)
+			}
+		}
+		if movies[0].Scope != tt.scope || movies[0].Within != tt.within {
+			t.Errorf("%s: group labeled %q within %q, want within %q", tt.scope, movies[0].Scope, movies[0].Within, tt.within)
+		}
+		if len(episodes) != tt.tvGroups {
+			t.Errorf("%s: got %d TV groups, want %d", tt.scope, len(episodes), tt.tvGroups)
+		}
+	}
+
+	// Cross-library groups keep the IDs they had before scopes existed
+	cross := MovieDuplicate{NormalizedName: "heat", Year: "1995", Scope: ScopeCrossLibrary}
+	if MovieDuplicateID(cross) != MovieDuplicateID(MovieDuplicate{NormalizedName: "heat", Year: "1995"}) {
+		t.Error("Expected cross-library IDs unchanged")
+	}
+	library := MovieDuplicate{NormalizedName: "heat", Year: "1995", Scope: ScopeLibrary, Within: libraries[1]}
+	if MovieDuplicateID(library) == MovieDuplicateID(cross) {
+		t.Error("Expected groups confined to a library to get their own ID")
+	}
+	if got := library.Scope.Describe(library.Within); got != "same library ("+libraries[1]+")" {
+		t.Errorf("Describe() = %q", got)
+	}
+	if _, err := ParseDuplicateScope("everywhere"); err == nil {
+		t.Error("Expected an unknown scope rejected")
+	}
+}
@@ -0,0 +1,116 @@
+package scanner
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Compliance issue severities
+// info: cosmetic, Jellyfin still matches the item
+// warn: likely to confuse matching or metadata lookup
+// error: breaks matching or needs manual review
+const (
+	IssueSeverityInfo  = "info"
+	IssueSeverityWarn  = "warn"
+	IssueSeverityError = "error"
+)
+
+// AllIssueSeverities lists severities from least to most severe
+var AllIssueSeverities = []string{IssueSeverityInfo, IssueSeverityWarn, IssueSeverityError}
+
+// ParseIssueSeverity validates a severity name (case-insensitive)
+func ParseIssueSeverity(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case IssueSeverityInfo:
+		return IssueSeverityInfo, nil
+	case IssueSeverityWarn, "warning":
+		return IssueSeverityWarn, nil
+	case IssueSeverityError:
+		return IssueSeverityError, nil
+	default:
+		return "", fmt.Errorf("invalid severity: %s (must be info, warn, or error)", s)
+	}
+}
+
+// EffectiveSeverity returns the issue severity, deriving one for reports
+// written before severities existed
+func (c ComplianceIssue) EffectiveSeverity() string {
+	if c.Severity != "" {
+		return c.Severity
+	}
+	if c.SuggestedAction == "manual_review" {
+		return IssueSeverityError
+	}
+	return IssueSeverityWarn
+}
+
+// EffectiveConfidence returns how sure the scan is of the suggested path,
+// from 0 to 1. Issues left for manual review score 0; issues without a
+// recorded confidence follow fixed naming rules and score 1
+func (c ComplianceIssue) EffectiveConfidence() float64 {
+	switch {
+	case c.SuggestedAction == "manual_review":
+		return 0
+	case c.Confidence == 0:
+		return 1
+	default:
+		return math.Min(math.Max(c.Confidence, 0), 1)
+	}
+}
+
+// IssueSeverityRank orders severities for filtering and sorting (higher is more severe)
+func IssueSeverityRank(severity string) int {
+	switch severity {
+	case IssueSeverityInfo:
+		return 0
+	case IssueSeverityWarn:
+		return 1
+	case IssueSeverityError:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// FilterIssuesByMinSeverity returns issues at or above the given severity
+// An empty minimum returns all issues
+func FilterIssuesByMinSeverity(issues []ComplianceIssue, min string) []ComplianceIssue {
+	if min == "" {
+		return issues
+	}
+
+	minRank := IssueSeverityRank(min)
+	var filtered []ComplianceIssue
+	for _, issue := range issues {
+		if IssueSeverityRank(issue.EffectiveSeverity()) >= minRank {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
+
+// FilterIssuesBySeverities returns issues whose severity is in the allowed set
+func FilterIssuesBySeverities(issues []ComplianceIssue, allowed []string) []ComplianceIssue {
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, s := range allowed {
+		allowedSet[strings.ToLower(s)] = true
+	}
+
+	var filtered []ComplianceIssue
+	for _, issue := range issues {
+		if allowedSet[issue.EffectiveSeverity()] {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
+
+// CountIssuesBySeverity tallies issues per severity
+func CountIssuesBySeverity(issues []ComplianceIssue) map[string]int {
+	counts := make(map[string]int, len(AllIssueSeverities))
+	for _, issue := range issues {
+		counts[issue.EffectiveSeverity()]++
+	}
+	return counts
+}
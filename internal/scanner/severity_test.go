@@ -0,0 +1,107 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseIssueSeverity(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"info", IssueSeverityInfo, false},
+		{"WARN", IssueSeverityWarn, false},
+		{"warning", IssueSeverityWarn, false},
+		{" error ", IssueSeverityError, false},
+		{"fatal", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseIssueSeverity(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseIssueSeverity(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseIssueSeverity(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFilterIssuesBySeverity(t *testing.T) {
+	issues := []ComplianceIssue{
+		{Path: "/a", Severity: IssueSeverityInfo},
+		{Path: "/b", Severity: IssueSeverityWarn},
+		{Path: "/c", Severity: IssueSeverityError},
+		{Path: "/legacy-review", SuggestedAction: "manual_review"},
+		{Path: "/legacy-rename", SuggestedAction: "rename"},
+	}
+
+	if got := FilterIssuesByMinSeverity(issues, ""); len(got) != 5 {
+		t.Errorf("Expected all 5 issues with empty minimum, got %d", len(got))
+	}
+	if got := FilterIssuesByMinSeverity(issues, IssueSeverityWarn); len(got) != 4 {
+		t.Errorf("Expected 4 issues at warn or above, got %d", len(got))
+	}
+	if got := FilterIssuesByMinSeverity(issues, IssueSeverityError); len(got) != 2 {
+		t.Errorf("Expected 2 error issues (including legacy manual_review), got %d", len(got))
+	}
+
+	got := FilterIssuesBySeverities(issues, []string{"info"})
+	if len(got) != 1 || got[0].Path != "/a" {
+		t.Errorf("Expected only the info issue, got %+v", got)
+	}
+	if got := FilterIssuesBySeverities(issues, nil); len(got) != 0 {
+		t.Errorf("Expected no issues with empty allow list, got %d", len(got))
+	}
+
+	counts := CountIssuesBySeverity(issues)
+	if counts[IssueSeverityError] != 2 || counts[IssueSeverityWarn] != 2 || counts[IssueSeverityInfo] != 1 {
+		t.Errorf("Unexpected severity counts: %v", counts)
+	}
+}
+
+func TestComplianceIssueSeverities(t *testing.T) {
+	libRoot := t.TempDir()
+
+	// File directly in library root breaks matching
+	rootFile := filepath.Join(libRoot, "Some.Movie.2020.mkv")
+	if issue := checkMovieCompliance(rootFile, libRoot); issue == nil || issue.Severity != IssueSeverityError {
+		t.Errorf("Expected error severity for file in library root, got %+v", issue)
+	}
+
+	// Year without parentheses is cosmetic
+	dir := filepath.Join(libRoot, "Some Movie 2020")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cosmetic := filepath.Join(dir, "Some Movie 2020.mkv")
+	if issue := checkMovieCompliance(cosmetic, libRoot); issue == nil || issue.Severity != IssueSeverityInfo {
+		t.Errorf("Expected info severity for year without parentheses, got %+v", issue)
+	}
+}
+
+func TestEffectiveConfidence(t *testing.T) {
+	tests := []struct {
+		issue ComplianceIssue
+		want  float64
+	}{
+		{ComplianceIssue{SuggestedAction: "rename"}, 1},
+		{ComplianceIssue{SuggestedAction: "reorganize", Confidence: 0.85}, 0.85},
+		{ComplianceIssue{SuggestedAction: "manual_review", Confidence: 0.95}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.issue.EffectiveConfidence(); got != tt.want {
+			t.Errorf("EffectiveConfidence(%+v) = %v, want %v", tt.issue, got, tt.want)
+		}
+	}
+
+	// A garbage show title scores below zero but is recorded as barely confident
+	if got := titleConfidence(&TVTitleResolution{Confidence: -0.2}); got <= 0 || got > 0.05 {
+		t.Errorf("titleConfidence(-0.2) = %v, want just above 0", got)
+	}
+}
@@ -0,0 +1,165 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	showFolderYears   bool
+	showFolderYearsMu sync.RWMutex
+)
+
+// SetShowFolderYears enables suggesting "(Year)" for show folders that lack
+// it (naming.show_folder_years); the year comes from TVDB or TMDB
+func SetShowFolderYears(enabled bool) {
+	showFolderYearsMu.Lock()
+	defer showFolderYearsMu.Unlock()
+	showFolderYears = enabled
+}
+
+// showFolderYearsEnabled reports whether SetShowFolderYears turned the rule
+// on and a key for a provider with first-air years is set
+func showFolderYearsEnabled() bool {
+	showFolderYearsMu.RLock()
+	enabled := showFolderYears
+	showFolderYearsMu.RUnlock()
+	tvdbKey, _, tmdbKey := apiKeys()
+	return enabled && (tvdbKey != "" || tmdbKey != "")
+}
+
+// lookupShowYears returns the first-air years of the series named title,
+// from TVDB, or TMDB when TVDB has none. Several years mean several series
+// share the name
+func lookupShowYears(title string) ([]string, error) {
+	tvdbKey, _, tmdbKey := apiKeys()
+	seen := make(map[string]bool)
+	var years []string
+	add := func(name, year string) {
+		if NormalizeName(name) == NormalizeName(title) && year != "" && !seen[year] {
+			seen[year] = true
+			years = append(years, year)
+		}
+	}
+
+	if tvdbKey != "" {
+		results, err := NewTVDBClient(tvdbKey).SearchSeries(title)
+		if errors.Is(err, ErrAPIOffline) {
+			return nil, err
+		}
+		for _, series := range results {
+			add(series.Name, series.Year)
+		}
+	}
+	if len(years) == 0 && tmdbKey != "" {
+		results, err := NewTMDBClient(tmdbKey).SearchSeries(title)
+		if errors.Is(err, ErrAPIOffline) {
+			return nil, err
+		}
+		for _, series := range results {
+			add(series.DisplayTitle(), series.Year())
+		}
+	}
+	sort.Strings(years)
+	return years, nil
+}
+
+// checkShowFolderYears suggests renaming each show folder without a
+// "(Year)" to "Title (Year)", with the year of the one series of that name.
+// Names several series share are left for review. The renames are returned
+// in folder order; a clean applies them before the fixes below the folders
+// (see ShowFolderRenames)
+func checkShowFolderYears(showDirs []string, pr *ProgressReporter) []ComplianceIssue {
+	var issues []ComplianceIssue
+	for _, showDir := range showDirs {
+		name := filepath.Base(showDir)
+		if hasYearInParentheses(name) {
+			continue
+		}
+		title, _ := ExtractTVShowTitle(name)
+		if title == "" {
+			continue
+		}
+
+		years, err := lookupShowYears(title)
+		if errors.Is(err, ErrAPIOffline) {
+			if pr != nil {
+				pr.SendSeverityImmediate(SeverityWarn, "Metadata APIs are unreachable: show folder years left out of this scan")
+			}
+			return issues
+		}
+		if len(years) == 0 {
+			continue
+		}
+
+		issue := ComplianceIssue{
+			Path:            showDir,
+			Type:            "tv",
+			Problem:         fmt.Sprintf("Show folder has no year (first aired %s)", years[0]),
+			Severity:        IssueSeverityInfo,
+			Rule:            RuleTVShowFolderYear,
+			SuggestedPath:   filepath.Join(filepath.Dir(showDir), fmt.Sprintf("%s (%s)", title, years[0])),
+			SuggestedAction: "rename",
+		}
+		if len(years) > 1 {
+			issue.Problem = fmt.Sprintf("Show folder has no year (several series are named %s: %s)", title, strings.Join(years, ", "))
+			issue.SuggestedAction = "manual_review"
+		} else if _, err := os.Lstat(issue.SuggestedPath); err == nil {
+			issue.Problem = fmt.Sprintf("Show folder has no year (%s is taken)", filepath.Base(issue.SuggestedPath))
+			issue.SuggestedAction = "manual_review"
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// PathRebaser maps paths below folders renamed earlier in a clean to where
+// they are after the renames
+type PathRebaser struct {
+	from, to []string
+}
+
+// Add records the rename of folder from to to
+func (r *PathRebaser) Add(from, to string) {
+	r.from = append(r.from, filepath.Clean(from))
+	r.to = append(r.to, filepath.Clean(to))
+}
+
+// Rebase returns path as it is after the recorded folder renames
+func (r *PathRebaser) Rebase(path string) string {
+	if r == nil || path == "" {
+		return path
+	}
+	for i, from := range r.from {
+		if path == from {
+			path = r.to[i]
+		} else if strings.HasPrefix(path, from+string(filepath.Separator)) {
+			path = r.to[i] + path[len(from):]
+		}
+	}
+	return path
+}
+
+// ShowFolderRenames returns a rebaser for the show folder renames among
+// issues, which a clean applies before the other fixes below those folders.
+// Plans list those fixes at their rebased paths
+func ShowFolderRenames(issues []ComplianceIssue) *PathRebaser {
+	var rebaser PathRebaser
+	for _, issue := range issues {
+		if IsShowFolderRename(issue) {
+			rebaser.Add(issue.Path, issue.SuggestedPath)
+		}
+	}
+	return &rebaser
+}
+
+// IsShowFolderRename reports whether issue renames a show folder to add its
+// year
+func IsShowFolderRename(issue ComplianceIssue) bool {
+	return issue.Rule == RuleTVShowFolderYear && issue.SuggestedAction == "rename"
+}
@@ -0,0 +1,104 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestShowFolderYears(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"success","data":{"token":"test-token"}}`)
+	})
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("query") {
+		case "Firefly":
+			fmt.Fprint(w, `{"status":"success","data":[{"id":"series-78874","name":"Firefly","year":"2002"},
+				{"id":"series-1","name":"Firefly Lane","year":"2021"}]}`)
+		case "Doctor Who":
+			fmt.Fprint(w, `{"status":"success","data":[{"id":"series-76107","name":"Doctor Who","year":"1963"},
+				{"id":"series-78804","name":"Doctor Who","year":"2005"}]}`)
+		default:
+			fmt.Fprint(w, `{"status":"success","data":[]}`)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	origURL := TVDBBaseURL
+	TVDBBaseURL = server.URL
+	ClearAPICache()
+	ResetAPICircuit()
+	defer func() {
+		TVDBBaseURL = origURL
+		SetAPIKeys("", "", "")
+		SetShowFolderYears(false)
+		ClearAPICache()
+	}()
+
+	tv := filepath.Join(t.TempDir(), "tv")
+	for _, rel := range []string{
+		"Firefly/Season 1/Firefly S01E01.mkv",
+		"Doctor Who/Season 01/Doctor Who S01E01.mkv",
+		"Lost (2004)/Season 01/Lost (2004) S01E01.mkv",
+		"Unknown Show/Season 01/Unknown Show S01E01.mkv",
+	} {
+		path := filepath.Join(tv, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Off by default, and without an API key
+	SetAPIKeys("test-key", "", "")
+	issues, err := ScanTVCompliance([]string{tv})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range issues {
+		if issue.Rule == RuleTVShowFolderYear {
+			t.Fatalf("Expected no show folder years while off, got %+v", issue)
+		}
+	}
+
+	SetShowFolderYears(true)
+	issues, err = ScanTVCompliance([]string{tv})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("Expected 3 issues, got %+v", issues)
+	}
+	// Show folder renames come first
+	firefly, doctorWho, season := issues[0], issues[1], issues[2]
+	if filepath.Base(firefly.Path) != "Firefly" {
+		firefly, doctorWho = doctorWho, firefly
+	}
+	if firefly.Rule != RuleTVShowFolderYear || firefly.SuggestedAction != "rename" ||
+		firefly.SuggestedPath != filepath.Join(tv, "Firefly (2002)") {
+		t.Errorf("Expected Firefly renamed to Firefly (2002), got %+v", firefly)
+	}
+	if doctorWho.Rule != RuleTVShowFolderYear || doctorWho.SuggestedAction != "manual_review" {
+		t.Errorf("Expected Doctor Who left for review, got %+v", doctorWho)
+	}
+	if season.Rule != RuleTVSeasonFolder {
+		t.Fatalf("Expected the season folder fix last, got %+v", season)
+	}
+
+	// The episode fix follows the renamed folder
+	renames := ShowFolderRenames(issues)
+	if got := renames.Rebase(season.Path); got != filepath.Join(tv, "Firefly (2002)", "Season 1", "Firefly S01E01.mkv") {
+		t.Errorf("Rebase(%s) = %s", season.Path, got)
+	}
+	if got := renames.Rebase(season.SuggestedPath); got != filepath.Join(tv, "Firefly (2002)", "Season 01", "Firefly S01E01.mkv") {
+		t.Errorf("Rebase(%s) = %s", season.SuggestedPath, got)
+	}
+	if got := renames.Rebase(filepath.Join(tv, "Firefly Lane", "x.mkv")); got != filepath.Join(tv, "Firefly Lane", "x.mkv") {
+		t.Errorf("Expected a sibling folder left alone, got %s", got)
+	}
+}
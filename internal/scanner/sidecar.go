@@ -0,0 +1,327 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Sidecar files belong to the video they share a base name with:
+// "Movie (2020).en.srt", "Movie (2020).nfo", "Movie (2020)-poster.jpg".
+// They follow the video when it is renamed or moved, and are reported when
+// no video in their folder claims them
+
+var (
+	subtitleExts = map[string]bool{
+		".srt": true, ".ass": true, ".ssa": true, ".sub": true, ".idx": true,
+		".vtt": true, ".sup": true, ".smi": true,
+	}
+	artworkExts = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".tbn": true, ".webp": true}
+
+	// "-poster", "-thumb" and friends after a video's base name
+	artworkSuffixRegex = regexp.MustCompile(`(?i)-(poster|thumb|fanart|banner|landscape|clearlogo|clearart|logo|disc|backdrop)$`)
+	// Language and flag tags between a subtitle's base name and extension
+	subtitleTagRegex = regexp.MustCompile(`(?i)^([a-z]{2,3}(-[a-z]{2,4})?|forced|sdh|cc|hi|default|foreign)$`)
+	// Artwork and nfo files that describe a folder rather than one video
+	folderSidecarRegex = regexp.MustCompile(`(?i)^(poster|folder|fanart|backdrop|banner|logo|landscape|clearart|clearlogo|disc|thumb|cover|tvshow|season|movie|collection|season\d+|season-(all|specials))(-[a-z]+)?$`)
+)
+
+// isSidecarFile reports whether path is a subtitle, nfo or artwork file
+func isSidecarFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return subtitleExts[ext] || artworkExts[ext] || ext == ".nfo"
+}
+
+// companionSuffix returns what follows base in a sidecar's filename, e.g.
+// ".en.srt" or "-poster.jpg", or false when name isn't one of base's sidecars
+func companionSuffix(base, name string) (string, bool) {
+	if !isSidecarFile(name) {
+		return "", false
+	}
+	rest, ok := strings.CutPrefix(name, base)
+	if !ok || rest == "" || (rest[0] != '.' && rest[0] != '-') {
+		return "", false
+	}
+	return rest, true
+}
+
+// fileStem returns a filename without its extension
+func fileStem(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// folderVideos returns the paths of the videos directly in dir
+func folderVideos(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var videos []string
+	for _, entry := range entries {
+		if !entry.IsDir() && isVideoFile(entry.Name()) {
+			videos = append(videos, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return videos
+}
+
+// ownerVideo returns the video among videos that claims the sidecar name.
+// The longest base name wins, so "Movie-2.srt" isn't claimed by "Movie"
+func ownerVideo(videos []string, name string) (string, bool) {
+	owner, found := "", false
+	for _, video := range videos {
+		if _, ok := companionSuffix(fileStem(video), name); ok && (!found || len(fileStem(video)) > len(fileStem(owner))) {
+			owner, found = video, true
+		}
+	}
+	return owner, found
+}
+
+// CompanionMove is a sidecar file and the name it takes next to its video
+type CompanionMove struct {
+	Source string
+	Target string
+}
+
+// CompanionMoves returns the sidecars of the video at videoPath and where
+// they go when the video becomes newVideoPath
+func CompanionMoves(videoPath, newVideoPath string) []CompanionMove {
+	if !isVideoFile(videoPath) || videoPath == newVideoPath {
+		return nil
+	}
+	dir := filepath.Dir(videoPath)
+	base, newBase := fileStem(videoPath), fileStem(newVideoPath)
+	videos := folderVideos(dir)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var moves []CompanionMove
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		suffix, ok := companionSuffix(base, entry.Name())
+		if !ok {
+			continue
+		}
+		if owner, _ := ownerVideo(videos, entry.Name()); fileStem(owner) != base {
+			continue
+		}
+		moves = append(moves, CompanionMove{
+			Source: filepath.Join(dir, entry.Name()),
+			Target: filepath.Join(filepath.Dir(newVideoPath), newBase+suffix),
+		})
+	}
+	return moves
+}
+
+var (
+	mergeCompanions   = true
+	mergeCompanionsMu sync.RWMutex
+)
+
+// SetMergeCompanions sets whether cleans move the sidecars of deleted
+// duplicates to the keeper, for groups without their own choice
+func SetMergeCompanions(merge bool) {
+	mergeCompanionsMu.Lock()
+	defer mergeCompanionsMu.Unlock()
+	mergeCompanions = merge
+}
+
+// GetMergeCompanions returns the global companion merge setting
+func GetMergeCompanions() bool {
+	mergeCompanionsMu.RLock()
+	defer mergeCompanionsMu.RUnlock()
+	return mergeCompanions
+}
+
+// MergesCompanions reports whether a clean moves the subtitles and artwork
+// of the deleted copies to the keeper: the group's own choice, or the
+// global setting
+func (d MovieDuplicate) MergesCompanions() bool {
+	if d.MergeCompanions != nil {
+		return *d.MergeCompanions
+	}
+	return GetMergeCompanions()
+}
+
+// MergesCompanions reports whether a clean moves the subtitles and artwork
+// of the deleted copies to the keeper: the group's own choice, or the
+// global setting
+func (d TVDuplicate) MergesCompanions() bool {
+	if d.MergeCompanions != nil {
+		return *d.MergeCompanions
+	}
+	return GetMergeCompanions()
+}
+
+// CompanionMerges returns the subtitles and artwork of a duplicate about to
+// be deleted that the keeper lacks, renamed to sit next to the keeper. A
+// sidecar whose name the keeper already has (its own ".en.srt" or
+// "-poster.jpg") stays behind, as do nfo files, which describe the copy
+// being deleted
+func CompanionMerges(deleted, keeper string) []CompanionMove {
+	var merges []CompanionMove
+	for _, move := range CompanionMoves(deleted, keeper) {
+		if strings.EqualFold(filepath.Ext(move.Source), ".nfo") {
+			continue
+		}
+		if _, err := os.Lstat(move.Target); err == nil {
+			continue
+		}
+		merges = append(merges, move)
+	}
+	return merges
+}
+
+// ApplyCompanionMoves moves sidecars to their new names, skipping any whose
+// target has been taken meanwhile
+func ApplyCompanionMoves(moves []CompanionMove) {
+	moveCompanions(moves)
+}
+
+// moveCompanions moves sidecars after their video has moved. A sidecar
+// whose target is taken or that can't be moved stays put; the next scan
+// reports it as orphaned
+func moveCompanions(moves []CompanionMove) {
+	for _, move := range moves {
+		if _, err := os.Lstat(move.Target); err == nil {
+			continue
+		}
+		_ = os.Rename(move.Source, move.Target)
+	}
+}
+
+// sidecarTags returns the tail of an orphaned sidecar's name worth keeping
+// when it is renamed after a video: language and flag tags of a subtitle
+// (".en.forced"), the artwork kind ("-poster"), then the extension
+func sidecarTags(name string) string {
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	if artworkExts[strings.ToLower(ext)] {
+		return artworkSuffixRegex.FindString(stem) + ext
+	}
+	if !subtitleExts[strings.ToLower(ext)] {
+		return ext
+	}
+	tags := ""
+	for i := 0; i < 3; i++ {
+		tag := filepath.Ext(stem)
+		if tag == "" || !subtitleTagRegex.MatchString(tag[1:]) {
+			break
+		}
+		tags = tag + tags
+		stem = strings.TrimSuffix(stem, tag)
+	}
+	return tags + ext
+}
+
+// isOrphanCandidate reports whether a sidecar should belong to one video.
+// Folder artwork and nfo files (poster.jpg, tvshow.nfo) and artwork
+// without a "-poster"-style kind describe the folder instead
+func isOrphanCandidate(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	stem := strings.TrimSuffix(name, filepath.Ext(name))
+	if folderSidecarRegex.MatchString(stem) {
+		return false
+	}
+	if artworkExts[ext] {
+		return artworkSuffixRegex.MatchString(stem)
+	}
+	return true
+}
+
+// checkOrphanedSidecar reports a sidecar that no video in its folder claims.
+// With one video in the folder it is renamed to match; with several the
+// closest name is only suggested. Folders without videos are left to
+// the orphaned folder scan
+func checkOrphanedSidecar(path, issueType string, videos []string) *ComplianceIssue {
+	name := filepath.Base(path)
+	if len(videos) == 0 || !isOrphanCandidate(name) {
+		return nil
+	}
+	if _, claimed := ownerVideo(videos, name); claimed {
+		return nil
+	}
+
+	closest := fileStem(videos[0])
+	for _, video := range videos[1:] {
+		if commonPrefixLen(fileStem(video), name) > commonPrefixLen(closest, name) {
+			closest = fileStem(video)
+		}
+	}
+	issue := &ComplianceIssue{
+		Path:            path,
+		Type:            issueType,
+		Problem:         "Sidecar file matches no video in its folder",
+		Severity:        IssueSeverityWarn,
+		Rule:            RuleOrphanedSidecar,
+		SuggestedPath:   filepath.Join(filepath.Dir(path), closest+sidecarTags(name)),
+		SuggestedAction: "rename",
+	}
+	if len(videos) > 1 {
+		issue.Problem = fmt.Sprintf("Sidecar file matches none of the %d videos in its folder", len(videos))
+		issue.SuggestedAction = "manual_review"
+	} else if _, err := os.Lstat(issue.SuggestedPath); err == nil {
+		issue.Problem = fmt.Sprintf("Sidecar file matches no video in its folder (%s is taken)", filepath.Base(issue.SuggestedPath))
+		issue.SuggestedAction = "manual_review"
+	}
+	return issue
+}
+
+// folderVideoCache remembers folderVideos per folder during one scan
+type folderVideoCache map[string][]string
+
+// videos returns the videos directly in dir
+func (c folderVideoCache) videos(dir string) []string {
+	videos, ok := c[dir]
+	if !ok {
+		videos = folderVideos(dir)
+		c[dir] = videos
+	}
+	return videos
+}
+
+// commonPrefixLen returns how many leading bytes a and b share
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// retargetOrphanedSidecars points orphaned sidecar renames at the name
+// their video is about to get, so fixing both in either order ends with
+// the pair side by side
+func retargetOrphanedSidecars(issues []ComplianceIssue) {
+	videoFixes := make(map[string]string) // current video path -> suggested path
+	for _, issue := range issues {
+		if issue.Rule != RuleOrphanedSidecar && issue.SuggestedAction != "manual_review" {
+			videoFixes[issue.Path] = issue.SuggestedPath
+		}
+	}
+	for i := range issues {
+		issue := &issues[i]
+		if issue.Rule != RuleOrphanedSidecar || issue.SuggestedAction == "manual_review" {
+			continue
+		}
+		dir := filepath.Dir(issue.Path)
+		tags := sidecarTags(filepath.Base(issue.Path))
+		for _, video := range folderVideos(dir) {
+			target, ok := videoFixes[video]
+			if !ok || fileStem(video)+tags != filepath.Base(issue.SuggestedPath) {
+				continue
+			}
+			issue.SuggestedPath = filepath.Join(filepath.Dir(target), fileStem(target)+tags)
+			if filepath.Dir(target) != dir {
+				issue.SuggestedAction = "reorganize"
+			}
+		}
+	}
+}
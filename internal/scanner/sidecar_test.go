@@ -0,0 +1,156 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompanionMoves(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel string) string {
+		path := filepath.Join(root, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	video := write("Heat.1995.1080p/Heat.1995.1080p.mkv")
+	subtitle := write("Heat.1995.1080p/Heat.1995.1080p.en.forced.srt")
+	nfo := write("Heat.1995.1080p/Heat.1995.1080p.nfo")
+	poster := write("Heat.1995.1080p/Heat.1995.1080p-poster.jpg")
+	write("Heat.1995.1080p/Heat.1995.1080p-extended.mkv")
+	extended := write("Heat.1995.1080p/Heat.1995.1080p-extended.srt")
+
+	target := filepath.Join(root, "Heat (1995)", "Heat (1995).mkv")
+	moves := CompanionMoves(video, target)
+	want := map[string]string{
+		subtitle: filepath.Join(root, "Heat (1995)", "Heat (1995).en.forced.srt"),
+		nfo:      filepath.Join(root, "Heat (1995)", "Heat (1995).nfo"),
+		poster:   filepath.Join(root, "Heat (1995)", "Heat (1995)-poster.jpg"),
+	}
+	if len(moves) != len(want) {
+		t.Fatalf("CompanionMoves() = %+v, want %d moves", moves, len(want))
+	}
+	for _, move := range moves {
+		if want[move.Source] != move.Target {
+			t.Errorf("Expected %s to go to %s, got %s", move.Source, want[move.Source], move.Target)
+		}
+	}
+
+	// The compliance fix takes the sidecars along; the other cut's stay
+	issue := ComplianceIssue{Type: "movie", Path: video, SuggestedPath: target, SuggestedAction: "reorganize"}
+	if err := ApplyMovieCompliance(issue); err != nil {
+		t.Fatal(err)
+	}
+	for source, target := range want {
+		if _, err := os.Stat(target); err != nil {
+			t.Errorf("Expected %s moved to %s: %v", source, target, err)
+		}
+	}
+	if _, err := os.Stat(extended); err != nil {
+		t.Errorf("Expected the extended cut's subtitle left alone: %v", err)
+	}
+}
+
+func TestOrphanedSidecars(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel string) string {
+		path := filepath.Join(root, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	write("Heat (1995)/Heat (1995).mkv")
+	write("Heat (1995)/poster.jpg")
+	write("Heat (1995)/movie.nfo")
+	orphan := write("Heat (1995)/Heat.1995.BluRay-GRP.en.srt")
+	write("Alien.1979.720p/alien.1979.720p.mkv")
+	movedOrphan := write("Alien.1979.720p/Alien.1979.720p.BluRay.nfo")
+	write("Twins (1988)/Twins (1988) - 1080p.mkv")
+	write("Twins (1988)/Twins (1988) - 720p.mkv")
+	ambiguous := write("Twins (1988)/twins.sub")
+
+	issues, err := ScanMovieCompliance([]string{root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byPath := make(map[string]ComplianceIssue)
+	for _, issue := range issues {
+		if issue.Rule == RuleOrphanedSidecar {
+			byPath[issue.Path] = issue
+		}
+	}
+	if len(byPath) != 3 {
+		t.Fatalf("Expected 3 orphaned sidecars (folder artwork and nfo aside), got %+v", byPath)
+	}
+	if issue := byPath[orphan]; issue.SuggestedAction != "rename" || issue.SuggestedPath != filepath.Join(root, "Heat (1995)", "Heat (1995).en.srt") {
+		t.Errorf("Expected the subtitle renamed after the only video, got %+v", issue)
+	}
+	// The video is moving too, so the nfo follows it to its new folder
+	if issue := byPath[movedOrphan]; issue.SuggestedAction != "reorganize" || filepath.Dir(issue.SuggestedPath) != filepath.Join(root, "Alien (1979)") {
+		t.Errorf("Expected the nfo retargeted at the video's new name, got %+v", issue)
+	}
+	if issue := byPath[ambiguous]; issue.SuggestedAction != "manual_review" {
+		t.Errorf("Expected a sidecar among several videos left for review, got %+v", issue)
+	}
+}
+
+func TestManualTVRenameSidecars(t *testing.T) {
+	root := t.TempDir()
+	season := filepath.Join(root, "Degrassi (2001)", "Season 01")
+	os.MkdirAll(season, 0755)
+	for _, name := range []string{"Degrassi S01E01.mkv", "Degrassi S01E01.en.srt", "Degrassi S01E01-thumb.jpg"} {
+		os.WriteFile(filepath.Join(season, name), []byte(name), 0644)
+	}
+
+	results, err := ApplyManualTVRename(root, "Degrassi", "Degrassi Junior High", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 4 {
+		t.Errorf("Expected the episode, two sidecars and the folder renamed, got %+v", results)
+	}
+	renamed := filepath.Join(root, "Degrassi Junior High (2001)", "Season 01")
+	for _, name := range []string{"Degrassi Junior High S01E01.mkv", "Degrassi Junior High S01E01.en.srt", "Degrassi Junior High S01E01-thumb.jpg"} {
+		if _, err := os.Stat(filepath.Join(renamed, name)); err != nil {
+			t.Errorf("Expected %s: %v", name, err)
+		}
+	}
+}
+
+func TestCompanionMerges(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel string) string {
+		path := filepath.Join(root, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	keeper := write("Heat (1995)/Heat (1995).mkv")
+	write("Heat (1995)/Heat (1995)-poster.jpg")
+	deleted := write("Heat.1995.720p/heat.720p.mkv")
+	subtitle := write("Heat.1995.720p/heat.720p.en.srt")
+	write("Heat.1995.720p/heat.720p-poster.jpg")
+	write("Heat.1995.720p/heat.720p.nfo")
+
+	// The keeper has its own poster and the nfo describes the deleted copy
+	merges := CompanionMerges(deleted, keeper)
+	want := filepath.Join(root, "Heat (1995)", "Heat (1995).en.srt")
+	if len(merges) != 1 || merges[0].Source != subtitle || merges[0].Target != want {
+		t.Fatalf("CompanionMerges() = %+v, want only the subtitle moved to %s", merges, want)
+	}
+
+	ApplyCompanionMoves(merges)
+	if _, err := os.Stat(want); err != nil {
+		t.Errorf("Expected the subtitle next to the keeper: %v", err)
+	}
+	if merges := CompanionMerges(deleted, keeper); len(merges) != 0 {
+		t.Errorf("CompanionMerges() = %+v after merging, want nothing left", merges)
+	}
+}
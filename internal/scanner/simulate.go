@@ -0,0 +1,175 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// SimulatedRoot is the made-up library root simulated scans report paths
+// under; nothing is read from or written to it
+const SimulatedRoot = "/jellysink-simulation"
+
+// SimulatedLibrary sizes the synthetic library SimulateScan builds in memory
+type SimulatedLibrary struct {
+	Movies          int           // movie folders
+	Shows           int           // TV shows
+	EpisodesPerShow int           // episodes in each show, ten per season
+	FileSizeMB      int64         // apparent size of each keeper copy
+	Delay           time.Duration // pause per simulated file, to watch progress
+}
+
+// DefaultSimulatedLibrary is a library large enough to fill every report
+// section and small enough to scan in a moment
+func DefaultSimulatedLibrary() SimulatedLibrary {
+	return SimulatedLibrary{Movies: 50, Shows: 10, EpisodesPerShow: 10, FileSizeMB: 2048}
+}
+
+// MoviesPath returns the simulated movie library root
+func (l SimulatedLibrary) MoviesPath() string {
+	return filepath.Join(SimulatedRoot, "movies")
+}
+
+// TVPath returns the simulated TV library root
+func (l SimulatedLibrary) TVPath() string {
+	return filepath.Join(SimulatedRoot, "tv")
+}
+
+// SimulateScan produces the result a scan of lib would, without touching
+// the disk or any API: every fifth movie and fourth episode has a lower
+// quality duplicate, and some movies and shows break the naming rules.
+// Duplicates are ranked and totalled like a real scan's
+func SimulateScan(ctx context.Context, lib SimulatedLibrary, progressCh chan<- ScanProgress) (*ScanResult, error) {
+	result := &ScanResult{}
+	profile := GetNamingProfile()
+	size := lib.FileSizeMB * 1024 * 1024
+
+	var pr *ProgressReporter
+	if progressCh != nil {
+		pr = NewProgressReporter(progressCh, OpScanningMovies)
+		pr.Start(lib.Movies, fmt.Sprintf("Simulating %d movies under %s", lib.Movies, lib.MoviesPath()))
+	}
+	for i := 1; i <= lib.Movies; i++ {
+		if err := simulatedDelay(ctx, lib.Delay); err != nil {
+			return nil, err
+		}
+
+		title := fmt.Sprintf("Simulated Movie %03d", i)
+		year := 1980 + i%40
+		name := fmt.Sprintf("%s (%d)", title, year)
+		clean := filepath.Join(lib.MoviesPath(), name, name+".mkv")
+		release := fmt.Sprintf("Simulated.Movie.%03d.%d.720p.WEB-DL.x264-SIM", i, year)
+
+		switch {
+		case i%5 == 0:
+			result.MovieDuplicates = append(result.MovieDuplicates, MovieDuplicate{
+				NormalizedName: NormalizeName(name),
+				Year:           fmt.Sprint(year),
+				Files: []MovieFile{
+					{Path: filepath.Join(lib.MoviesPath(), release, release+".mkv"), Size: size / 2, Resolution: "720p"},
+					{Path: filepath.Join(lib.MoviesPath(), name, name+" 1080p.mkv"), Size: size, Resolution: "1080p"},
+				},
+			})
+		case i%11 == 0:
+			result.ComplianceIssues = append(result.ComplianceIssues, ComplianceIssue{
+				Path:            filepath.Join(lib.MoviesPath(), release+".mkv"),
+				Type:            "movie",
+				Problem:         "Movie file directly in library root (should be in subfolder)",
+				Severity:        IssueSeverityError,
+				Rule:            RuleMovieInLibraryRoot,
+				SuggestedPath:   clean,
+				SuggestedAction: "reorganize",
+			})
+		case i%7 == 3:
+			result.ComplianceIssues = append(result.ComplianceIssues, ComplianceIssue{
+				Path:            filepath.Join(lib.MoviesPath(), release, release+".mkv"),
+				Type:            "movie",
+				Problem:         "Release group folder naming (contains resolution/codec/source markers)",
+				Severity:        IssueSeverityWarn,
+				Rule:            RuleMovieReleaseGroupFolder,
+				SuggestedPath:   clean,
+				SuggestedAction: "reorganize",
+			})
+		}
+		if pr != nil {
+			pr.Update(i, fmt.Sprintf("Processing: %s", name))
+		}
+	}
+	if pr != nil {
+		pr.Complete(fmt.Sprintf("Found %d duplicate groups", len(result.MovieDuplicates)))
+	}
+
+	episodes := lib.Shows * lib.EpisodesPerShow
+	if progressCh != nil {
+		pr = NewProgressReporter(progressCh, OpScanningTV)
+		pr.Start(episodes, fmt.Sprintf("Simulating %d episodes under %s", episodes, lib.TVPath()))
+	}
+	processed := 0
+	for i := 1; i <= lib.Shows; i++ {
+		show := fmt.Sprintf("Simulated Show %02d (%d)", i, 2000+i%20)
+		for e := 1; e <= lib.EpisodesPerShow; e++ {
+			if err := simulatedDelay(ctx, lib.Delay); err != nil {
+				return nil, err
+			}
+
+			season, episode := (e-1)/10+1, (e-1)%10+1
+			seasonDir := filepath.Join(lib.TVPath(), show, profile.SeasonFolder(season))
+			clean := filepath.Join(seasonDir, profile.EpisodeFilename(show, season, episode, ".mkv"))
+			release := filepath.Join(seasonDir, fmt.Sprintf("Simulated.Show.%02d.S%02dE%02d.720p.HDTV.x264-SIM.mkv", i, season, episode))
+
+			switch {
+			case e%4 == 0:
+				result.TVDuplicates = append(result.TVDuplicates, TVDuplicate{
+					ShowName: NormalizeName(show),
+					Season:   season,
+					Episode:  episode,
+					Files: []TVFile{
+						{Path: release, Size: size / 8, Resolution: "720p", Source: "HDTV"},
+						{Path: clean, Size: size / 4, Resolution: "1080p", Source: "WEB-DL"},
+					},
+				})
+			case i%3 == 0 && e%4 == 2:
+				result.ComplianceIssues = append(result.ComplianceIssues, ComplianceIssue{
+					Path:            release,
+					Type:            "tv",
+					Problem:         "Release group naming in filename",
+					Severity:        IssueSeverityWarn,
+					Rule:            RuleTVReleaseGroupFilename,
+					SuggestedPath:   clean,
+					SuggestedAction: "rename",
+				})
+			}
+			processed++
+			if pr != nil {
+				pr.Update(processed, fmt.Sprintf("Processing: %s", filepath.Base(clean)))
+			}
+		}
+	}
+	if pr != nil {
+		pr.Complete(fmt.Sprintf("Found %d duplicate episodes", len(result.TVDuplicates)))
+	}
+
+	result.MovieDuplicates = MarkKeepDelete(result.MovieDuplicates)
+	result.TVDuplicates = MarkKeepDeleteTV(result.TVDuplicates)
+	AssignIDs(result)
+	SortResults(result)
+	tallyResult(result)
+
+	return result, nil
+}
+
+// simulatedDelay waits d, returning early when ctx is cancelled
+func simulatedDelay(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
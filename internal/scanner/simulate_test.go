@@ -0,0 +1,62 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSimulateScan(t *testing.T) {
+	lib := DefaultSimulatedLibrary()
+	progressCh := make(chan ScanProgress, 1000)
+	result, err := SimulateScan(context.Background(), lib, progressCh)
+	close(progressCh)
+	if err != nil {
+		t.Fatalf("SimulateScan failed: %v", err)
+	}
+
+	if len(result.MovieDuplicates) != 10 || len(result.TVDuplicates) != 20 {
+		t.Errorf("Expected 10 movie and 20 episode duplicates, got %d and %d", len(result.MovieDuplicates), len(result.TVDuplicates))
+	}
+	if len(result.ComplianceIssues) != 18 {
+		t.Errorf("Expected 18 compliance issues, got %d", len(result.ComplianceIssues))
+	}
+	if result.TotalDuplicates != 30 || result.TotalFilesToDelete != 30 || result.SpaceToFree == 0 {
+		t.Errorf("Unexpected totals: %d groups, %d files, %d bytes", result.TotalDuplicates, result.TotalFilesToDelete, result.SpaceToFree)
+	}
+
+	for _, dup := range result.MovieDuplicates {
+		if dup.ID == "" || dup.Files[0].Resolution != "1080p" {
+			t.Errorf("Expected an ID and the 1080p copy kept, got %+v", dup)
+		}
+	}
+	for _, issue := range result.ComplianceIssues {
+		if !strings.HasPrefix(issue.Path, SimulatedRoot) || !strings.HasPrefix(issue.SuggestedPath, SimulatedRoot) {
+			t.Errorf("Expected paths under %s, got %+v", SimulatedRoot, issue)
+		}
+	}
+
+	updates := 0
+	for range progressCh {
+		updates++
+	}
+	if updates == 0 {
+		t.Error("Expected progress updates")
+	}
+}
+
+func TestSimulateScanCancels(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lib := SimulatedLibrary{Movies: 1000, Delay: time.Second}
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	start := time.Now()
+	if _, err := SimulateScan(ctx, lib, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("Expected the delay cut short by cancellation")
+	}
+}
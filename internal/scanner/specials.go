@@ -0,0 +1,120 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Jellyfin files a show's specials as season 0, in "Season 00", and matches
+// daily shows' episodes by air date ("Show 2024-05-12") instead of S##E##
+
+var (
+	// "Specials", "Special", "Season 0", "Season 00", "S00"
+	specialsFolderRegex = regexp.MustCompile(`(?i)^(specials?|season[ ._-]*0+|s0+)$`)
+
+	// "Show - Special 3", "Show.SP03", "Show E03", "Episode 3" in a specials
+	// folder
+	specialNumberRegex = regexp.MustCompile(`(?i)(?:^|[ ._-])(?:e|ep|episode|special|sp)[ ._-]*(\d{1,3})(?:[ ._\-\[(]|$)`)
+
+	// "2024-05-12", "2024.05.12", "2024 05 12"
+	airDateRegex = regexp.MustCompile(`(?:^|[ ._\-\[(])((?:19|20)\d{2})[ ._-](0[1-9]|1[0-2])[ ._-](0[1-9]|[12]\d|3[01])(?:[ ._\-\])]|$)`)
+)
+
+// isSpecialsFolder reports whether a folder name holds a show's specials
+func isSpecialsFolder(name string) bool {
+	return specialsFolderRegex.MatchString(strings.TrimSpace(name))
+}
+
+// ExtractAirDate returns the air date in a daily show's episode filename as
+// YYYY-MM-DD
+func ExtractAirDate(filename string) (string, bool) {
+	matches := airDateRegex.FindStringSubmatch(strings.TrimSuffix(filename, filepath.Ext(filename)))
+	if matches == nil {
+		return "", false
+	}
+	return matches[1] + "-" + matches[2] + "-" + matches[3], true
+}
+
+// showFolderOf returns the show folder an episode sits in, the first folder
+// below the library root, or "" for files directly in the root
+func showFolderOf(path, libRoot string) string {
+	rel, err := filepath.Rel(libRoot, path)
+	if err != nil || !strings.Contains(rel, string(filepath.Separator)) || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return filepath.Join(libRoot, strings.SplitN(rel, string(filepath.Separator), 2)[0])
+}
+
+// checkSpecial suggests filing a special that carries no S##E## tag, in a
+// specials folder of its show, as season 0 of the show: "Specials/Show -
+// Special 3.mkv" becomes "Season 00/Show S00E03.mkv". Specials without a
+// number are left alone
+func checkSpecial(path, libRoot string) *ComplianceIssue {
+	showDir := showFolderOf(path, libRoot)
+	if showDir == "" || filepath.Dir(filepath.Dir(path)) != showDir || !isSpecialsFolder(filepath.Base(filepath.Dir(path))) {
+		return nil
+	}
+	matches := specialNumberRegex.FindStringSubmatch(fileStem(path))
+	if matches == nil {
+		return nil
+	}
+	episode, _ := strconv.Atoi(matches[1])
+	show, _ := ExtractTVShowTitle(filepath.Base(showDir))
+	if show == "" || episode == 0 {
+		return nil
+	}
+
+	profile := GetNamingProfile()
+	issue := &ComplianceIssue{
+		Path:            path,
+		Type:            "tv",
+		Problem:         fmt.Sprintf("Special not tagged S00E%02d (Jellyfin doesn't match it to the show's specials)", episode),
+		Severity:        IssueSeverityWarn,
+		Rule:            RuleTVSpecialEpisode,
+		SuggestedPath:   filepath.Join(showDir, profile.SeasonFolder(0), suggestedEpisodeFilename(profile, show, 0, episode, filepath.Ext(path))),
+		SuggestedAction: "reorganize",
+	}
+	if _, err := os.Lstat(issue.SuggestedPath); err == nil {
+		issue.Problem = fmt.Sprintf("Special not tagged S00E%02d (%s is taken)", episode, filepath.Base(issue.SuggestedPath))
+		issue.SuggestedAction = "manual_review"
+	}
+	return issue
+}
+
+// checkDateEpisode suggests renaming a daily show's episode to the
+// "Show YYYY-MM-DD" form Jellyfin matches by air date, in place. Episodes
+// loose in the library root are left to the loose-file scan
+func checkDateEpisode(path, libRoot string) *ComplianceIssue {
+	date, found := ExtractAirDate(filepath.Base(path))
+	showDir := showFolderOf(path, libRoot)
+	if !found || showDir == "" {
+		return nil
+	}
+	show, _ := ExtractTVShowTitle(filepath.Base(showDir))
+	if show == "" {
+		return nil
+	}
+
+	target := GetNamingProfile().DateEpisodeFilename(show, date, filepath.Ext(path))
+	if filepath.Base(path) == target {
+		return nil
+	}
+	issue := &ComplianceIssue{
+		Path:            path,
+		Type:            "tv",
+		Problem:         fmt.Sprintf("Daily episode not named by air date (%s)", date),
+		Severity:        IssueSeverityWarn,
+		Rule:            RuleTVDateEpisode,
+		SuggestedPath:   filepath.Join(filepath.Dir(path), target),
+		SuggestedAction: "rename",
+	}
+	if _, err := os.Lstat(issue.SuggestedPath); err == nil && !strings.EqualFold(issue.SuggestedPath, path) {
+		issue.Problem = fmt.Sprintf("Daily episode not named by air date (%s is taken)", target)
+		issue.SuggestedAction = "manual_review"
+	}
+	return issue
+}
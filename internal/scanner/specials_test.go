@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractAirDate(t *testing.T) {
+	tests := map[string]string{
+		"The Daily Show 2024-05-12.mkv":            "2024-05-12",
+		"the.daily.show.2024.05.12.720p.web.mkv":   "2024-05-12",
+		"Last Week Tonight [2023 11 05] Guest.mkv": "2023-11-05",
+		"Heat (1995).mkv":                          "",
+		"Show S01E01 2024-05-12.mkv":               "2024-05-12",
+		"Show 2024-13-12.mkv":                      "",
+		"Show 20240512.mkv":                        "",
+	}
+	for filename, expected := range tests {
+		if got, _ := ExtractAirDate(filename); got != expected {
+			t.Errorf("ExtractAirDate(%q) = %q, want %q", filename, got, expected)
+		}
+	}
+}
+
+func TestSpecialsAndDailyEpisodes(t *testing.T) {
+	tv := filepath.Join(t.TempDir(), "tv")
+	write := func(rel string) string {
+		path := filepath.Join(tv, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	tagged := write("Firefly (2002)/Specials/Firefly S00E01.mkv")
+	untagged := write("Firefly (2002)/Specials/Firefly - Special 2.mkv")
+	write("Firefly (2002)/Specials/Firefly - Gag Reel.mkv")
+	write("Firefly (2002)/Season 00/Firefly S00E03.mkv")
+	daily := write("The Daily Show/Season 2024/the.daily.show.2024.05.12.720p.web.mkv")
+	write("The Daily Show/Season 2024/The Daily Show 2024-05-13.mkv")
+	write("the.daily.show.2024.05.14.mkv")
+
+	issues, err := ScanTVCompliance([]string{tv})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]struct{ rule, target, action string }{
+		tagged:   {RuleTVSeasonFolder, filepath.Join(tv, "Firefly", "Season 00", "Firefly S00E01.mkv"), "reorganize"},
+		untagged: {RuleTVSpecialEpisode, filepath.Join(tv, "Firefly (2002)", "Season 00", "Firefly S00E02.mkv"), "reorganize"},
+		daily:    {RuleTVDateEpisode, filepath.Join(tv, "The Daily Show", "Season 2024", "The Daily Show 2024-05-12.mkv"), "rename"},
+	}
+	for _, issue := range issues {
+		expected, ok := want[issue.Path]
+		if !ok {
+			t.Errorf("Unexpected issue for %s: %s", issue.Path, issue.Problem)
+			continue
+		}
+		if issue.Rule != expected.rule || issue.SuggestedPath != expected.target || issue.SuggestedAction != expected.action {
+			t.Errorf("Expected %s %s to %s, got %s %s to %s", expected.rule, expected.action, expected.target,
+				issue.Rule, issue.SuggestedAction, issue.SuggestedPath)
+		}
+		delete(want, issue.Path)
+	}
+	for path := range want {
+		t.Errorf("Expected an issue for %s", path)
+	}
+
+	// Episodes in a specials folder are in place
+	if isLooseFile(filepath.Join(tv, "Firefly (2002)", "Specials", "Firefly S00E01.mkv"), tv) {
+		t.Error("Expected a specials folder to count as a season folder")
+	}
+}
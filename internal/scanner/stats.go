@@ -0,0 +1,164 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// StatsItem is a single file in a library statistics listing
+type StatsItem struct {
+	Path string
+	Size int64
+}
+
+// LibraryStats summarizes the video files under one library path
+type LibraryStats struct {
+	Path         string
+	LibraryType  string // "movies" or "tv"
+	FileCount    int
+	TotalSize    int64
+	ByResolution map[string]int
+	ByCodec      map[string]int
+	ByContainer  map[string]int
+	Largest      []StatsItem // Biggest files, largest first
+}
+
+// codecAliases maps filename codec markers to a canonical codec name
+// Checked in order so HEVC tags win when a name carries several markers
+var codecAliases = []struct {
+	codec   string
+	markers []string
+}{
+	{"hevc", []string{"x265", "h265", "hevc"}},
+	{"av1", []string{"av1"}},
+	{"h264", []string{"x264", "h264", "avc"}},
+	{"vp9", []string{"vp9"}},
+	{"xvid", []string{"xvid", "divx"}},
+}
+
+// ExtractCodec extracts the video codec from a filename (hevc, h264, etc.)
+func ExtractCodec(name string) string {
+	lower := strings.ToLower(filepath.Base(name))
+	lower = strings.NewReplacer("h.265", "h265", "h.264", "h264").Replace(lower)
+
+	tokens := make(map[string]bool)
+	for _, token := range strings.FieldsFunc(lower, func(r rune) bool {
+		return r == '.' || r == ' ' || r == '-' || r == '_' || r == '[' || r == ']' || r == '(' || r == ')'
+	}) {
+		tokens[token] = true
+	}
+
+	for _, alias := range codecAliases {
+		for _, marker := range alias.markers {
+			if tokens[marker] {
+				return alias.codec
+			}
+		}
+	}
+
+	return "unknown"
+}
+
+// ExtractContainer returns the lowercase file extension without the dot
+func ExtractContainer(path string) string {
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+}
+
+// CollectLibraryStats walks a library path and tallies its video files
+// Samples and trailers are skipped; topN bounds the largest-items list
+func CollectLibraryStats(path, libraryType string, topN int) (LibraryStats, error) {
+	stats := LibraryStats{
+		Path:         path,
+		LibraryType:  libraryType,
+		ByResolution: make(map[string]int),
+		ByCodec:      make(map[string]int),
+		ByContainer:  make(map[string]int),
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return stats, fmt.Errorf("library path not accessible: %w", err)
+	}
+
+	var items []StatsItem
+	err := filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			// Skip unreadable entries rather than aborting the whole library
+			return nil
+		}
+
+		if skip, skipErr := walkSkip(path, filePath, info); skip {
+			return skipErr
+		}
+		if info.IsDir() || !isVideoFile(filePath) || isSampleFile(filePath) || isExtraFile(filePath, info.Size()) {
+			return nil
+		}
+
+		stats.FileCount++
+		stats.TotalSize += info.Size()
+		stats.ByResolution[ExtractResolution(filePath)]++
+		stats.ByCodec[ExtractCodec(filePath)]++
+		stats.ByContainer[ExtractContainer(filePath)]++
+		items = append(items, StatsItem{Path: filePath, Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return stats, fmt.Errorf("failed to walk %s: %w", path, err)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Size != items[j].Size {
+			return items[i].Size > items[j].Size
+		}
+		return items[i].Path < items[j].Path
+	})
+	if len(items) > topN {
+		items = items[:topN]
+	}
+	stats.Largest = items
+
+	return stats, nil
+}
+
+// ListVideoFiles returns the video files under a library path, skipping
+// samples and the same ignored folders as a scan
+func ListVideoFiles(path string) ([]string, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("library path not accessible: %w", err)
+	}
+
+	var files []string
+	err := filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if skip, skipErr := walkSkip(path, filePath, info); skip {
+			return skipErr
+		}
+		if !info.IsDir() && isVideoFile(filePath) && !isSampleFile(filePath) && !isExtraFile(filePath, info.Size()) {
+			files = append(files, filePath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", path, err)
+	}
+	return files, nil
+}
+
+// SortedCounts returns the keys of a breakdown map, most common first
+func SortedCounts(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
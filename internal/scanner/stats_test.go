@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractCodec(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Movie.2020.1080p.BluRay.x264-GROUP.mkv", "h264"},
+		{"Movie 2020 2160p WEB-DL H.265.mkv", "hevc"},
+		{"Show.S01E01.720p.HEVC.x265.mkv", "hevc"},
+		{"Movie (2020) [AV1].mp4", "av1"},
+		{"Old.Movie.1999.DVDRip.XviD.avi", "xvid"},
+		{"Movie (2020).mkv", "unknown"},
+		{"Avcalanche (2020).mkv", "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := ExtractCodec(tt.name); got != tt.want {
+			t.Errorf("ExtractCodec(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCollectLibraryStats(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]int{
+		"Big Movie (2020)/Big.Movie.2020.2160p.x265.mkv":    300,
+		"Mid Movie (2019)/Mid.Movie.2019.1080p.x264.mkv":    200,
+		"Small Movie (2018)/Small.Movie.2018.720p.x264.mp4": 100,
+		"Small Movie (2018)/Small.Movie.2018.sample.mkv":    50,
+		"Small Movie (2018)/Small.Movie.2018.720p.x264.nfo": 10,
+		"Tiny Movie (2017)/Tiny Movie (2017).avi":           5,
+	}
+	for rel, size := range files {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := CollectLibraryStats(root, "movies", 2)
+	if err != nil {
+		t.Fatalf("CollectLibraryStats failed: %v", err)
+	}
+
+	if stats.FileCount != 4 {
+		t.Errorf("Expected 4 video files (sample and nfo skipped), got %d", stats.FileCount)
+	}
+	if stats.TotalSize != 605 {
+		t.Errorf("Expected total size 605, got %d", stats.TotalSize)
+	}
+	if stats.ByResolution["2160p"] != 1 || stats.ByResolution["unknown"] != 1 {
+		t.Errorf("Unexpected resolution breakdown: %v", stats.ByResolution)
+	}
+	if stats.ByCodec["h264"] != 2 || stats.ByCodec["hevc"] != 1 {
+		t.Errorf("Unexpected codec breakdown: %v", stats.ByCodec)
+	}
+	if stats.ByContainer["mkv"] != 2 || stats.ByContainer["mp4"] != 1 || stats.ByContainer["avi"] != 1 {
+		t.Errorf("Unexpected container breakdown: %v", stats.ByContainer)
+	}
+	if len(stats.Largest) != 2 || stats.Largest[0].Size != 300 || stats.Largest[1].Size != 200 {
+		t.Errorf("Expected two largest files (300, 200), got %+v", stats.Largest)
+	}
+
+	if keys := SortedCounts(stats.ByCodec); keys[0] != "h264" {
+		t.Errorf("Expected most common codec first, got %v", keys)
+	}
+
+	if _, err := CollectLibraryStats(filepath.Join(root, "missing"), "movies", 5); err == nil {
+		t.Error("Expected error for missing library path")
+	}
+}
@@ -0,0 +1,161 @@
+package scanner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
+)
+
+// Duplicate previews show one frame of each copy, grabbed with ffmpeg, so
+// copies can be told apart by eye before one is deleted. Frames are cached
+// by path, size and modification time, so a replaced file gets a new one
+
+const (
+	// ThumbnailDirName holds cached frames, in the data directory
+	ThumbnailDirName = "thumbnails"
+	thumbnailWidth   = 320
+	thumbnailTimeout = 60 * time.Second
+	// thumbnailOffset is where frames are grabbed in files of unknown
+	// length, past most intros and studio logos
+	thumbnailOffset = 2 * time.Minute
+)
+
+// ErrNoFFmpeg means thumbnails cannot be made on this machine
+var ErrNoFFmpeg = errors.New("ffmpeg not found (install it for duplicate previews)")
+
+// DefaultThumbnailDir returns where thumbnails are cached
+func DefaultThumbnailDir() string {
+	return filepath.Join(oplog.DataDir(), ThumbnailDirName)
+}
+
+// ffmpegBinary returns the ffmpeg next to the configured ffprobe, or the one
+// on PATH, or ""
+func ffmpegBinary() string {
+	if probe := ffprobeBinary(); probe != "" {
+		sibling := filepath.Join(filepath.Dir(probe), "ffmpeg")
+		if info, err := os.Stat(sibling); err == nil && !info.IsDir() {
+			return sibling
+		}
+	}
+	found, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return ""
+	}
+	return found
+}
+
+// ThumbnailsAvailable reports whether ffmpeg is installed to grab frames
+func ThumbnailsAvailable() bool {
+	return ffmpegBinary() != ""
+}
+
+// runFFmpeg grabs one frame of path at offset into out as a JPEG; tests
+// replace it
+var runFFmpeg = func(ctx context.Context, binary, path string, offset time.Duration, out string) error {
+	cmd := exec.CommandContext(ctx, binary,
+		"-v", "error", "-y",
+		"-ss", strconv.FormatFloat(offset.Seconds(), 'f', 1, 64),
+		"-i", path,
+		"-frames:v", "1",
+		"-vf", fmt.Sprintf("scale=%d:-2", thumbnailWidth),
+		"-q:v", "5",
+		"-f", "image2", out)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		line, _, _ := strings.Cut(string(output), "\n")
+		return fmt.Errorf("%w: %s", err, line)
+	}
+	return nil
+}
+
+// Thumbnail returns a JPEG frame of the video at path, from the cache in
+// dir or grabbed with ffmpeg. duration, when known from ffprobe, places the
+// frame a tenth of the way in. Files on a cloud mount are not read while
+// performance.cloud_safe is on
+func Thumbnail(ctx context.Context, dir, path string, duration time.Duration) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a folder", path)
+	}
+	if mount, ok := cloudMount(path); ok && CloudSafe() {
+		return nil, fmt.Errorf("no preview of %s: %s is a cloud mount (performance.cloud_safe)", path, mount.Point)
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%d", path, info.Size(), info.ModTime().UnixNano())))
+	cached := filepath.Join(dir, hex.EncodeToString(sum[:16])+".jpg")
+	if data, err := os.ReadFile(cached); err == nil && len(data) > 0 {
+		// Touched so pruning keeps thumbnails that are still viewed
+		now := time.Now()
+		os.Chtimes(cached, now, now)
+		return data, nil
+	}
+
+	binary := ffmpegBinary()
+	if binary == "" {
+		return nil, ErrNoFFmpeg
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create thumbnail directory: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, thumbnailTimeout)
+	defer cancel()
+	offset := thumbnailOffset
+	if duration > 0 {
+		offset = duration / 10
+	}
+	tmpPath := cached + ".tmp"
+	defer os.Remove(tmpPath)
+	// A file shorter than the offset yields no frame, so try its start
+	for _, at := range []time.Duration{offset, 0} {
+		if err = runFFmpeg(ctx, binary, path, at, tmpPath); err != nil {
+			continue
+		}
+		data, readErr := os.ReadFile(tmpPath)
+		if readErr != nil || len(data) == 0 {
+			err = fmt.Errorf("no frame at %s", at)
+			continue
+		}
+		if err := os.Rename(tmpPath, cached); err != nil {
+			return nil, fmt.Errorf("failed to cache thumbnail: %w", err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("ffmpeg failed on %s: %w", path, err)
+}
+
+// PruneThumbnails removes cached thumbnails not viewed within maxAge and
+// returns how many were removed
+func PruneThumbnails(dir string, maxAge time.Duration) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read thumbnail directory: %w", err)
+	}
+	cutoff := time.Now().Add(-maxAge)
+	removed := 0
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if os.Remove(filepath.Join(dir, entry.Name())) == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
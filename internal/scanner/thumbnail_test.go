@@ -0,0 +1,69 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestThumbnail(t *testing.T) {
+	// A configured ffprobe makes the ffmpeg next to it the one used
+	bin := t.TempDir()
+	os.WriteFile(filepath.Join(bin, "ffprobe"), nil, 0755)
+	os.WriteFile(filepath.Join(bin, "ffmpeg"), nil, 0755)
+	SetFFprobe(filepath.Join(bin, "ffprobe"))
+	t.Cleanup(func() { SetFFprobe("") })
+
+	var offsets []time.Duration
+	orig := runFFmpeg
+	runFFmpeg = func(ctx context.Context, binary, path string, offset time.Duration, out string) error {
+		if binary != filepath.Join(bin, "ffmpeg") {
+			t.Errorf("Ran %s, want the ffmpeg next to ffprobe", binary)
+		}
+		offsets = append(offsets, offset)
+		if offset > 0 {
+			return errors.New("no frame past the end")
+		}
+		return os.WriteFile(out, []byte("jpeg"), 0644)
+	}
+	t.Cleanup(func() { runFFmpeg = orig })
+
+	video := filepath.Join(t.TempDir(), "Heat (1995).mkv")
+	os.WriteFile(video, []byte("video"), 0644)
+	cache := t.TempDir()
+
+	data, err := Thumbnail(context.Background(), cache, video, 100*time.Minute)
+	if err != nil || string(data) != "jpeg" {
+		t.Fatalf("Thumbnail() = %q, %v", data, err)
+	}
+	// A tenth of the way in first, then the start of a short file
+	if len(offsets) != 2 || offsets[0] != 10*time.Minute || offsets[1] != 0 {
+		t.Errorf("Grabbed frames at %v, want 10m then 0s", offsets)
+	}
+
+	// Cached: ffmpeg is not run again until the file changes
+	offsets = nil
+	if data, err := Thumbnail(context.Background(), cache, video, 0); err != nil || string(data) != "jpeg" || len(offsets) != 0 {
+		t.Errorf("Expected the cached thumbnail, got %q, %v after %d runs", data, err, len(offsets))
+	}
+	os.WriteFile(video, []byte("replaced video"), 0644)
+	Thumbnail(context.Background(), cache, video, 0)
+	if len(offsets) == 0 || offsets[0] != thumbnailOffset {
+		t.Errorf("Expected a new frame of the replaced file at %s, got %v", thumbnailOffset, offsets)
+	}
+
+	if _, err := Thumbnail(context.Background(), cache, filepath.Join(cache, "missing.mkv"), 0); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected not-exist for a missing file, got %v", err)
+	}
+
+	// Pruning removes thumbnails not viewed lately
+	entries, _ := os.ReadDir(cache)
+	old := time.Now().Add(-40 * 24 * time.Hour)
+	os.Chtimes(filepath.Join(cache, entries[0].Name()), old, old)
+	if removed, err := PruneThumbnails(cache, 30*24*time.Hour); err != nil || removed != 1 {
+		t.Errorf("PruneThumbnails() = %d, %v; want 1 removed", removed, err)
+	}
+}
@@ -0,0 +1,108 @@
+package scanner
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultLowercaseWords are the short articles, conjunctions and prepositions
+// kept lowercase mid-title ("The Lord of the Rings"), matching TVDB/IMDB style
+var DefaultLowercaseWords = []string{
+	"a", "an", "the",
+	"and", "but", "or", "nor", "for", "so", "yet",
+	"as", "at", "by", "in", "of", "off", "on", "per", "to", "up", "via", "vs",
+	"from", "into", "onto", "with",
+}
+
+var (
+	lowercaseWords   = wordSet(DefaultLowercaseWords)
+	lowercaseWordsMu sync.RWMutex
+)
+
+// SetLowercaseWords replaces the words kept lowercase mid-title
+// (naming.lowercase_words); an empty list capitalizes every word
+func SetLowercaseWords(words []string) {
+	lowercaseWordsMu.Lock()
+	defer lowercaseWordsMu.Unlock()
+	lowercaseWords = wordSet(words)
+}
+
+// GetLowercaseWords returns the words currently kept lowercase mid-title
+func GetLowercaseWords() []string {
+	lowercaseWordsMu.RLock()
+	defer lowercaseWordsMu.RUnlock()
+	words := make([]string, 0, len(lowercaseWords))
+	for word := range lowercaseWords {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words
+}
+
+func wordSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			set[strings.ToLower(word)] = true
+		}
+	}
+	return set
+}
+
+// applyLowercaseExceptions lowercases exception words in a title-cased string.
+// The first and last words, words starting a subtitle (after ":" or " - ")
+// and preserved acronyms ("OF", "AND") keep their capitals. "The" is only
+// lowercased after another small word ("Lord of the Rings"): straight after
+// a name it starts a subtitle whose separator was lost to the release name
+// ("Spider-Man The Animated Series"), and only the API knows otherwise
+func applyLowercaseExceptions(s string) string {
+	lowercaseWordsMu.RLock()
+	defer lowercaseWordsMu.RUnlock()
+	if len(lowercaseWords) == 0 {
+		return s
+	}
+
+	words := strings.Split(s, " ")
+	for i := 1; i < len(words)-1; i++ {
+		word := words[i]
+		lower := strings.ToLower(word)
+		if !lowercaseWords[lower] || word != capitalizeFirst(lower) {
+			continue
+		}
+		prev := words[i-1]
+		if prev == "-" || strings.HasSuffix(prev, ":") || prev == "" {
+			continue
+		}
+		if lower == "the" && !lowercaseWords[strings.ToLower(prev)] {
+			continue
+		}
+		words[i] = lower
+	}
+	return strings.Join(words, " ")
+}
+
+// capitalizeFirst uppercases the first letter of a lowercase word
+func capitalizeFirst(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	if r == utf8.RuneError {
+		return word
+	}
+	return string(unicode.ToUpper(r)) + word[size:]
+}
+
+// CanonicalTitle returns the casing of an API-verified title from this
+// session's lookups when it matches title case-insensitively, so local
+// parsing never overrides e.g. "Marvel's Agents of S.H.I.E.L.D."
+func (c *APICache) CanonicalTitle(title string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, entry := range c.cache {
+		if entry.Verified && entry.Title != "" && !c.expired(entry) && strings.EqualFold(entry.Title, title) {
+			return entry.Title
+		}
+	}
+	return title
+}
@@ -0,0 +1,71 @@
+package scanner
+
+import (
+	"testing"
+)
+
+func TestTitleCaseLowercaseExceptions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"the lord of the rings", "The Lord of the Rings"},
+		{"THE.LORD.OF.THE.RINGS", "THE.LORD.OF.THE.RINGS"},
+		{"of mice and men", "Of Mice and Men"},
+		{"what are you waiting for", "What Are You Waiting For"},
+		{"mission: impossible - the final reckoning", "Mission: Impossible - The Final Reckoning"},
+		{"star wars: a new hope", "Star Wars: A New Hope"},
+		{"alien vs predator", "Alien vs Predator"},
+		{"the 40th anniversary of the show", "The 40th Anniversary of the Show"},
+		{"beauty and the beast", "Beauty and the Beast"},
+		{"degrassi the next generation", "Degrassi The Next Generation"},
+		{"spider-man the animated series", "Spider-Man The Animated Series"},
+	}
+
+	for _, tt := range tests {
+		if got := titleCaseWithOrdinals(tt.input); got != tt.expected {
+			t.Errorf("titleCaseWithOrdinals(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+
+	if got := CleanMovieName("the.lord.of.the.rings.2001.1080p.BluRay.x264-GROUP"); got != "The Lord of the Rings (2001)" {
+		t.Errorf("CleanMovieName = %q", got)
+	}
+}
+
+func TestSetLowercaseWords(t *testing.T) {
+	defer SetLowercaseWords(DefaultLowercaseWords)
+
+	SetLowercaseWords([]string{"Of", " "})
+	if got := GetLowercaseWords(); len(got) != 1 || got[0] != "of" {
+		t.Errorf("GetLowercaseWords() = %v, want [of]", got)
+	}
+	if got := titleCaseWithOrdinals("the lord of the rings"); got != "The Lord of The Rings" {
+		t.Errorf("Custom list: got %q", got)
+	}
+
+	SetLowercaseWords([]string{})
+	if got := titleCaseWithOrdinals("the lord of the rings"); got != "The Lord Of The Rings" {
+		t.Errorf("Empty list should capitalize every word, got %q", got)
+	}
+}
+
+func TestExtractTVShowTitlePrefersAPICasing(t *testing.T) {
+	ClearAPICache()
+	defer ClearAPICache()
+
+	if got, _ := ExtractTVShowTitle("Its.Always.Sunny.In.Philadelphia.S01E01.mkv"); got != "Its Always Sunny in Philadelphia" {
+		t.Fatalf("Expected local casing before any lookup, got %q", got)
+	}
+
+	globalAPICache.Set("tvdb:Its Always Sunny", &APICacheEntry{Title: "ITS ALWAYS SUNNY IN PHILADELPHIA", Verified: false})
+	globalAPICache.Set("tvdb:Its Always Sunny in Philadelphia", &APICacheEntry{
+		Title:    "Its Always Sunny In Philadelphia",
+		Verified: true,
+	})
+
+	got, _ := ExtractTVShowTitle("Its.Always.Sunny.In.Philadelphia.S01E01.mkv")
+	if got != "Its Always Sunny In Philadelphia" {
+		t.Errorf("Expected verified API casing, got %q", got)
+	}
+}
@@ -0,0 +1,294 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TMDBClient handles TMDB (The Movie Database) API requests
+// The key is either a v3 API key or a v4 read access token
+type TMDBClient struct {
+	APIKey     string
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// TMDBResult is a movie or TV series from a TMDB search
+type TMDBResult struct {
+	ID           int    `json:"id"`
+	Title        string `json:"title"`          // movies
+	Name         string `json:"name"`           // series
+	ReleaseDate  string `json:"release_date"`   // movies, YYYY-MM-DD
+	FirstAirDate string `json:"first_air_date"` // series, YYYY-MM-DD
+}
+
+// DisplayTitle returns the movie title or series name
+func (r TMDBResult) DisplayTitle() string {
+	if r.Title != "" {
+		return r.Title
+	}
+	return r.Name
+}
+
+// Year returns the release or first air year, or "" when unknown
+func (r TMDBResult) Year() string {
+	date := r.ReleaseDate
+	if date == "" {
+		date = r.FirstAirDate
+	}
+	if len(date) < 4 {
+		return ""
+	}
+	return date[:4]
+}
+
+// TMDBSearchResult represents a search response from TMDB
+type TMDBSearchResult struct {
+	Results []TMDBResult `json:"results"`
+}
+
+// NewTMDBClient creates a new TMDB API client
+func NewTMDBClient(apiKey string) *TMDBClient {
+	return &TMDBClient{
+		APIKey:     apiKey,
+		BaseURL:    TMDBBaseURL,
+		HTTPClient: newAPIHTTPClient(),
+	}
+}
+
+// newRequest builds a GET request authenticated with the key: v4 read
+// access tokens (JWTs) go in the Authorization header, v3 keys in the query
+func (c *TMDBClient) newRequest(path string, query url.Values) (*http.Request, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	isToken := strings.HasPrefix(c.APIKey, "eyJ")
+	if !isToken {
+		query.Set("api_key", c.APIKey)
+	}
+
+	req, err := http.NewRequest("GET", c.BaseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if isToken {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+	return req, nil
+}
+
+// VerifyKey performs a single uncached request to check the API key is accepted
+func (c *TMDBClient) VerifyKey() error {
+	if c.APIKey == "" {
+		return fmt.Errorf("TMDB API key not configured")
+	}
+
+	req, err := c.newRequest("/configuration", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", describeRequestError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("TMDB rejected the API key")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SearchSeries searches TMDB for a TV series by name with retry logic
+func (c *TMDBClient) SearchSeries(name string) ([]TMDBResult, error) {
+	return c.SearchWithRetry("tv", name, "", 3)
+}
+
+// SearchMovie searches TMDB for a movie by title and (optional) year with
+// retry logic
+func (c *TMDBClient) SearchMovie(title, year string) ([]TMDBResult, error) {
+	return c.SearchWithRetry("movie", title, year, 3)
+}
+
+// SearchWithRetry searches TMDB's "movie" or "tv" index with configurable
+// retry count. Only the best match is cached, so movie searches, whose other
+// results verifyMovieName also weighs, are cached only when unambiguous
+func (c *TMDBClient) SearchWithRetry(kind, name, year string, maxRetries int) ([]TMDBResult, error) {
+	if c.APIKey == "" {
+		return nil, fmt.Errorf("TMDB API key not configured")
+	}
+
+	cacheKey := "tmdb:" + kind + ":" + name + "|" + year
+	if cached, ok := globalAPICache.Get(cacheKey); ok {
+		if cached.Verified {
+			id, _ := strconv.Atoi(cached.ID)
+			result := TMDBResult{ID: id, Title: cached.Title}
+			if kind == "tv" {
+				result = TMDBResult{ID: id, Name: cached.Title}
+			}
+			if cached.Year != "" {
+				result.ReleaseDate = cached.Year + "-01-01"
+			}
+			return []TMDBResult{result}, nil
+		}
+		return nil, fmt.Errorf("cached: %s", cached.Reason)
+	}
+
+	query := url.Values{"query": {name}}
+	if year != "" {
+		if kind == "tv" {
+			query.Set("first_air_date_year", year)
+		} else {
+			query.Set("year", year)
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		// Offline: give up without caching so the next scan tries again
+		if err := tmdbBreaker.Allow(); err != nil {
+			if lastErr == nil {
+				apiStats.skip(tmdbBreaker.Name)
+			} else {
+				apiStats.record(tmdbBreaker.Name, lastErr)
+			}
+			return nil, err
+		}
+		if attempt > 0 {
+			backoff := time.Duration(math.Pow(2, float64(attempt-1))) * time.Second
+			time.Sleep(backoff)
+		}
+
+		req, err := c.newRequest("/search/"+kind, query)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to create request: %w", err)
+			continue
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			tmdbBreaker.Failure()
+			lastErr = fmt.Errorf("API request failed: %w", describeRequestError(err))
+			continue
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			tmdbBreaker.Failure()
+		} else {
+			tmdbBreaker.Success()
+		}
+
+		// A rejected key will not be accepted on retry
+		if resp.StatusCode == http.StatusUnauthorized {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("TMDB rejected the API key")
+			break
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("rate limited")
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			lastErr = fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+			continue
+		}
+
+		var result TMDBSearchResult
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("failed to parse response: %w", err)
+			continue
+		}
+		resp.Body.Close()
+
+		if len(result.Results) == 1 || (kind == "tv" && len(result.Results) > 0) {
+			best := result.Results[0]
+			globalAPICache.Set(cacheKey, &APICacheEntry{
+				Title:      best.DisplayTitle(),
+				Year:       best.Year(),
+				ID:         strconv.Itoa(best.ID),
+				Verified:   true,
+				Confidence: 0.95,
+				Timestamp:  time.Now(),
+			})
+		}
+
+		apiStats.record(tmdbBreaker.Name, nil)
+		return result.Results, nil
+	}
+
+	apiStats.record(tmdbBreaker.Name, lastErr)
+	globalAPICache.Set(cacheKey, &APICacheEntry{
+		Verified:  false,
+		Reason:    lastErr.Error(),
+		Timestamp: time.Now(),
+	})
+
+	return nil, fmt.Errorf("failed after %d retries: %w", maxRetries, lastErr)
+}
+
+// verifyWithTMDB uses TMDB API to verify title with retry and caching
+func verifyWithTMDB(resolution *TVTitleResolution, apiKey string) error {
+	if apiKey == "" {
+		return fmt.Errorf("TMDB API key not configured")
+	}
+
+	client := NewTMDBClient(apiKey)
+
+	folderResults, folderErr := client.SearchSeries(resolution.FolderMatch.Title)
+	filenameResults, filenameErr := client.SearchSeries(resolution.FilenameMatch.Title)
+
+	if folderErr != nil && filenameErr != nil {
+		return fmt.Errorf("failed to search both titles (folder: %v, filename: %v)", folderErr, filenameErr)
+	}
+
+	if len(folderResults) == 0 && len(filenameResults) == 0 {
+		return fmt.Errorf("no results found for either title")
+	}
+
+	if len(folderResults) > 0 && len(filenameResults) == 0 {
+		resolution.ResolvedTitle = folderResults[0].DisplayTitle()
+		resolution.APIVerified = true
+		resolution.IsAmbiguous = false
+		resolution.Confidence = 0.95
+		resolution.Reason = fmt.Sprintf("TMDB verified: '%s' (folder match, no filename match)", resolution.ResolvedTitle)
+		return nil
+	}
+
+	if len(filenameResults) > 0 && len(folderResults) == 0 {
+		resolution.ResolvedTitle = filenameResults[0].DisplayTitle()
+		resolution.APIVerified = true
+		resolution.IsAmbiguous = false
+		resolution.Confidence = 0.95
+		resolution.Reason = fmt.Sprintf("TMDB verified: '%s' (filename match, no folder match)", resolution.ResolvedTitle)
+		return nil
+	}
+
+	if folderResults[0].ID == filenameResults[0].ID {
+		resolution.ResolvedTitle = folderResults[0].DisplayTitle()
+		resolution.APIVerified = true
+		resolution.IsAmbiguous = false
+		resolution.Confidence = 1.0
+		resolution.Reason = fmt.Sprintf("TMDB verified: '%s' (both match same series)", resolution.ResolvedTitle)
+		return nil
+	}
+
+	recordAPIConflict(resolution, "TMDB", folderResults[0].DisplayTitle(), filenameResults[0].DisplayTitle())
+	return nil
+}
@@ -0,0 +1,121 @@
+package scanner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// tmdbServer answers /search/<kind> with results keyed by query, and records
+// how each request authenticated
+func tmdbServer(t *testing.T, results map[string][]TMDBResult) *[]string {
+	t.Helper()
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key := r.URL.Query().Get("api_key"); key != "" {
+			auth = append(auth, "query:"+key)
+		} else {
+			auth = append(auth, r.Header.Get("Authorization"))
+		}
+		json.NewEncoder(w).Encode(TMDBSearchResult{Results: results[r.URL.Path+"?"+r.URL.Query().Get("query")]})
+	}))
+	t.Cleanup(server.Close)
+
+	origURL := TMDBBaseURL
+	TMDBBaseURL = server.URL
+	ClearAPICache()
+	ResetAPICircuit()
+	t.Cleanup(func() {
+		TMDBBaseURL = origURL
+		ClearAPICache()
+		ResetAPICircuit()
+	})
+	return &auth
+}
+
+func TestVerifyTVShowTitleFallsBackToTMDB(t *testing.T) {
+	auth := tmdbServer(t, map[string][]TMDBResult{
+		"/search/tv?Degrassi":                     {{ID: 1, Name: "Degrassi: The Next Generation", FirstAirDate: "2001-10-14"}},
+		"/search/tv?Degrassi the Next Generation": {{ID: 1, Name: "Degrassi: The Next Generation", FirstAirDate: "2001-10-14"}},
+	})
+
+	resolution := &TVTitleResolution{
+		ResolvedTitle: "Degrassi",
+		FolderMatch:   &TVTitleMatch{Title: "Degrassi"},
+		FilenameMatch: &TVTitleMatch{Title: "Degrassi the Next Generation"},
+		IsAmbiguous:   true,
+	}
+	if err := VerifyTVShowTitle(resolution, "", "", "v3-key"); err != nil {
+		t.Fatalf("VerifyTVShowTitle() failed: %v", err)
+	}
+	if !resolution.APIVerified || resolution.IsAmbiguous || resolution.Confidence != 1.0 {
+		t.Errorf("Expected TMDB to verify both titles as one series, got %+v", resolution)
+	}
+	if resolution.ResolvedTitle != "Degrassi: The Next Generation" {
+		t.Errorf("Expected TMDB's series name, got %s", resolution.ResolvedTitle)
+	}
+	if len(*auth) != 2 || (*auth)[0] != "query:v3-key" {
+		t.Errorf("Expected the v3 key sent as api_key, got %v", *auth)
+	}
+}
+
+func TestTMDBReadAccessToken(t *testing.T) {
+	auth := tmdbServer(t, nil)
+
+	if _, err := NewTMDBClient("eyJhbGciOiJIUzI1NiJ9.token").SearchSeries("Lost"); err != nil {
+		t.Fatalf("SearchSeries() failed: %v", err)
+	}
+	if len(*auth) != 1 || (*auth)[0] != "Bearer eyJhbGciOiJIUzI1NiJ9.token" {
+		t.Errorf("Expected the v4 token sent as a bearer token, got %v", *auth)
+	}
+}
+
+func TestVerifyMovieName(t *testing.T) {
+	auth := tmdbServer(t, map[string][]TMDBResult{
+		"/search/movie?Amelie": {{ID: 194, Title: "Amélie", ReleaseDate: "2001-04-25"}},
+		"/search/movie?Heat": {
+			{ID: 949, Title: "Heat", ReleaseDate: "1995-12-15"},
+			{ID: 1, Title: "Heat", ReleaseDate: "1986-03-14"},
+		},
+		"/search/movie?Whiplash": {{ID: 244786, Title: "Whiplash", ReleaseDate: "2014-10-10"}},
+	})
+	SetAPIKeys("", "", "test-key")
+	defer SetAPIKeys("", "", "")
+
+	tests := []struct {
+		name      string
+		suggested string
+		want      string
+	}{
+		{"adopts TMDB spelling", "/movies/Amelie (2001)/Amelie (2001).mkv", "/movies/Amélie (2001)/Amélie (2001).mkv"},
+		{"fills in a missing year", "/movies/Whiplash/Whiplash.mkv", "/movies/Whiplash (2014)/Whiplash (2014).mkv"},
+		{"ignores ambiguous titles without a year", "/movies/Heat/Heat.mkv", "/movies/Heat/Heat.mkv"},
+		{"ignores other years", "/movies/Amelie (1999)/Amelie (1999).mkv", "/movies/Amelie (1999)/Amelie (1999).mkv"},
+		{"ignores unknown titles", "/movies/Home Video (2020)/Home Video (2020).mkv", "/movies/Home Video (2020)/Home Video (2020).mkv"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := &ComplianceIssue{
+				Path:            "/movies/release/file.mkv",
+				Problem:         "Release group folder naming",
+				SuggestedPath:   tt.suggested,
+				SuggestedAction: "reorganize",
+			}
+			verifyMovieName(issue, nil)
+			if issue.SuggestedPath != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, issue.SuggestedPath)
+			}
+			if changed := issue.Problem != "Release group folder naming"; changed != (tt.want != tt.suggested) {
+				t.Errorf("Unexpected problem text %q", issue.Problem)
+			}
+		})
+	}
+
+	// Only reorganize suggestions are looked up
+	requests := len(*auth)
+	verifyMovieName(&ComplianceIssue{SuggestedPath: "/movies/Amelie (2001)/Amelie (2001).mkv", SuggestedAction: "manual_review"}, nil)
+	if len(*auth) != requests {
+		t.Error("Expected no lookup for a manual review issue")
+	}
+}
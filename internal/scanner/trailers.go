@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Jellyfin plays "Movie (2010)-trailer.mkv" in a movie's folder, or any
+// video in its trailers folder, as the movie's trailer. Trailers are extras,
+// never copies of the movie or junk; stray ones are renamed to that form
+
+var (
+	// "Heat (1995)-trailer"
+	trailerSuffixRegex = regexp.MustCompile(`(?i)-trailer$`)
+	// "Heat.1995.Trailer.1080p", "trailer2", "Heat (1995) - Trailer"
+	trailerNameRegex = regexp.MustCompile(`(?i)(^|[ ._-])trailer\d*([ ._-]|$)`)
+	// Folder of extras Jellyfin takes as trailers
+	trailerFolderRegex = regexp.MustCompile(`(?i)^trailers$`)
+)
+
+// isNamedTrailer reports whether the video at path follows Jellyfin's
+// trailer convention: a -trailer suffix, or a place in a trailers folder
+func isNamedTrailer(path string) bool {
+	return trailerSuffixRegex.MatchString(fileStem(path)) || trailerFolderRegex.MatchString(filepath.Base(filepath.Dir(path)))
+}
+
+// isTrailerFile reports whether the video at path is a trailer, named by
+// the convention or not. The size cap keeps full-length movies with
+// "Trailer" in the title, such as Trailer Park Boys, out
+func isTrailerFile(path string, size int64) bool {
+	return isNamedTrailer(path) || (trailerNameRegex.MatchString(fileStem(path)) && size < maxSampleSize)
+}
+
+// checkStrayTrailer suggests renaming a trailer that doesn't follow the
+// convention to "<movie folder>-trailer", so Jellyfin lists it as an extra
+// instead of a movie. Trailers in folders that need fixing first, or loose
+// in the library root, are left until the movie has its folder
+func checkStrayTrailer(path, libRoot string) *ComplianceIssue {
+	dir := filepath.Dir(path)
+	folder := filepath.Base(dir)
+	if isNamedTrailer(path) || dir == libRoot || isReleaseGroupFolder(folder) || !hasYearInParentheses(folder) {
+		return nil
+	}
+
+	issue := &ComplianceIssue{
+		Path:            path,
+		Type:            "movie",
+		Problem:         "Trailer not named <movie>-trailer (Jellyfin lists it as a movie)",
+		Severity:        IssueSeverityInfo,
+		Rule:            RuleMovieTrailerName,
+		SuggestedPath:   filepath.Join(dir, folder+"-trailer"+filepath.Ext(path)),
+		SuggestedAction: "rename",
+	}
+	if _, err := os.Lstat(issue.SuggestedPath); err == nil {
+		issue.Problem = fmt.Sprintf("Trailer not named <movie>-trailer (%s is taken)", filepath.Base(issue.SuggestedPath))
+		issue.SuggestedAction = "manual_review"
+	}
+	return issue
+}
@@ -0,0 +1,97 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIsTrailerFile(t *testing.T) {
+	tests := []struct {
+		path  string
+		size  int64
+		named bool
+		want  bool
+	}{
+		{"/movies/Heat (1995)/Heat (1995)-trailer.mkv", 50 << 20, true, true},
+		{"/movies/Heat (1995)/Heat (1995)-Trailer.mp4", 1 << 30, true, true},
+		{"/movies/Heat (1995)/trailers/Teaser.mkv", 50 << 20, true, true},
+		{"/movies/Heat (1995)/Heat.1995.Trailer.1080p.mkv", 50 << 20, false, true},
+		{"/movies/Heat (1995)/trailer2.mkv", 50 << 20, false, true},
+		{"/movies/Heat (1995)/Heat (1995).mkv", 50 << 20, false, false},
+		// Full-length movies with the word in their title are not trailers
+		{"/movies/Trailer Park Boys (2006)/Trailer Park Boys (2006).mkv", 2 << 30, false, false},
+		{"/movies/Trailerpark (2010)/Trailerpark (2010).mkv", 50 << 20, false, false},
+	}
+	for _, tt := range tests {
+		if got := isNamedTrailer(tt.path); got != tt.named {
+			t.Errorf("isNamedTrailer(%q) = %v, want %v", tt.path, got, tt.named)
+		}
+		if got := isTrailerFile(tt.path, tt.size); got != tt.want {
+			t.Errorf("isTrailerFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestTrailersInMovieFolders(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel string) string {
+		path := filepath.Join(root, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	write("Heat (1995)/Heat (1995).mkv")
+	named := write("Heat (1995)/Heat (1995)-trailer.mkv")
+	write("Alien (1979)/Alien (1979).mkv")
+	stray := write("Alien (1979)/Alien.1979.Trailer.1080p.mkv")
+	write("Alien (1979)/trailers/Teaser.mkv")
+	write("Big.Fish.2003.1080p-GRP/Big.Fish.2003.1080p-GRP.mkv")
+	unfixed := write("Big.Fish.2003.1080p-GRP/Big.Fish.Trailer.mkv")
+
+	// Trailers are not copies of their movie
+	duplicates, err := ScanMovies([]string{root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(duplicates) != 0 {
+		t.Errorf("Expected no duplicate groups, got %+v", duplicates)
+	}
+
+	issues, err := ScanMovieCompliance([]string{root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var trailerIssues []ComplianceIssue
+	for _, issue := range issues {
+		if issue.Path == named || issue.Path == unfixed {
+			t.Errorf("Expected no issue for %s, got %+v", issue.Path, issue)
+		}
+		if issue.Rule == RuleMovieTrailerName {
+			trailerIssues = append(trailerIssues, issue)
+		}
+	}
+	want := filepath.Join(root, "Alien (1979)", "Alien (1979)-trailer.mkv")
+	if len(trailerIssues) != 1 || trailerIssues[0].Path != stray || trailerIssues[0].SuggestedPath != want || trailerIssues[0].SuggestedAction != "rename" {
+		t.Fatalf("Expected the stray Alien trailer renamed to %s, got %+v", want, trailerIssues)
+	}
+
+	// Named trailers are never sample clips, even in a samples folder
+	info, err := os.Stat(named)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sampled := filepath.Join(root, "Heat (1995)", "Sample", "Heat (1995)-trailer.mkv")
+	if kind := artifactKind(sampled, info, time.Now()); kind != "" {
+		t.Errorf("Expected a named trailer not taken for junk, got %q", kind)
+	}
+
+	// A taken name leaves the rename to the user
+	write("Alien (1979)/Alien (1979)-trailer.mkv")
+	if issue := checkStrayTrailer(stray, root); issue == nil || issue.SuggestedAction != "manual_review" {
+		t.Errorf("Expected manual review when the name is taken, got %+v", issue)
+	}
+}
@@ -199,7 +199,7 @@ func TestVerifyTVShowTitle_FolderMatchOnly(t *testing.T) {
 		Confidence:  0.5,
 	}
 
-	err := VerifyTVShowTitle(resolution, apiKey, "")
+	err := VerifyTVShowTitle(resolution, apiKey, "", "")
 	if err != nil {
 		t.Fatalf("VerifyTVShowTitle() failed: %v", err)
 	}
@@ -240,7 +240,7 @@ func TestVerifyTVShowTitle_BothMatch(t *testing.T) {
 		Confidence:  0.9,
 	}
 
-	err := VerifyTVShowTitle(resolution, apiKey, "")
+	err := VerifyTVShowTitle(resolution, apiKey, "", "")
 	if err != nil {
 		t.Fatalf("VerifyTVShowTitle() failed: %v", err)
 	}
@@ -281,7 +281,7 @@ func TestVerifyTVShowTitle_DegrassiConflict(t *testing.T) {
 		Confidence:  0.6,
 	}
 
-	err := VerifyTVShowTitle(resolution, apiKey, "")
+	err := VerifyTVShowTitle(resolution, apiKey, "", "")
 	if err != nil {
 		t.Fatalf("VerifyTVShowTitle() failed: %v", err)
 	}
@@ -361,7 +361,7 @@ func TestVerifyTVShowTitle_WithOMDBFallback(t *testing.T) {
 		Confidence:  0.9,
 	}
 
-	err := VerifyTVShowTitle(resolution, tvdbKey, omdbKey)
+	err := VerifyTVShowTitle(resolution, tvdbKey, omdbKey, "")
 	if err != nil {
 		t.Fatalf("VerifyTVShowTitle() failed: %v", err)
 	}
@@ -395,7 +395,7 @@ func TestVerifyTVShowTitle_TVDBWithOMDBFallback(t *testing.T) {
 		Confidence:  0.9,
 	}
 
-	err := VerifyTVShowTitle(resolution, tvdbKey, omdbKey)
+	err := VerifyTVShowTitle(resolution, tvdbKey, omdbKey, "")
 	if err != nil {
 		t.Fatalf("VerifyTVShowTitle() failed: %v", err)
 	}
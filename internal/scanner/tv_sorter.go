@@ -15,23 +15,26 @@ import (
 	"time"
 )
 
-// APICache stores API verification results for the current session
+// APICache stores API verification results for the current session, and
+// across runs when persisted (see SetAPICachePersistence)
 type APICache struct {
 	mu    sync.RWMutex
 	cache map[string]*APICacheEntry
+	ttl   time.Duration // verified entries older than this are ignored; 0 = never expire
 }
 
 // APICacheEntry represents a cached API lookup result
 type APICacheEntry struct {
 	Title      string
 	Year       string
+	ID         string // provider id of the matched series, when known
 	Verified   bool
 	Confidence float64
 	Reason     string
 	Timestamp  time.Time
 }
 
-// Global cache for API lookups (session-scoped)
+// Global cache for API lookups (session-scoped unless persisted)
 var globalAPICache = &APICache{
 	cache: make(map[string]*APICacheEntry),
 }
@@ -41,6 +44,9 @@ func (c *APICache) Get(key string) (*APICacheEntry, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	entry, ok := c.cache[key]
+	if ok && c.expired(entry) {
+		return nil, false
+	}
 	return entry, ok
 }
 
@@ -58,12 +64,24 @@ func (c *APICache) Clear() {
 	c.cache = make(map[string]*APICacheEntry)
 }
 
+// DropFailures removes cached failed lookups so they are retried, keeping verified titles
+func (c *APICache) DropFailures() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range c.cache {
+		if !entry.Verified {
+			delete(c.cache, key)
+		}
+	}
+}
+
 // TVTitleMatch represents a potential title match with confidence score
 type TVTitleMatch struct {
 	Title      string  // Extracted title
 	Source     string  // "folder" or "filename"
 	Confidence float64 // 0.0 to 1.0 (higher = more confident)
 	Year       string  // Extracted year if present
+	APITitle   string  `json:",omitempty"` // series an API matched for this title, recorded on conflicts
 }
 
 // DecisionType represents user's resolution choice
@@ -75,6 +93,8 @@ const (
 	DecisionFilenameTitle
 	DecisionCustomTitle
 	DecisionSkipped
+	DecisionSuggestedTitle // movies: the name the scanner suggested
+	DecisionAPITitle       // movies: the title TMDB or OMDB found
 )
 
 // TVTitleResolution contains the resolved show name and metadata
@@ -85,7 +105,9 @@ type TVTitleResolution struct {
 	IsAmbiguous   bool          // True if needs manual review
 	Confidence    float64       // Overall confidence (0.0 to 1.0)
 	APIVerified   bool          // True if verified via TVDB/OMDB
+	APIStatus     string        `json:",omitempty"` // why verification did not run, e.g. "skipped: offline"
 	Reason        string        // Explanation for resolution choice
+	JellyfinTitle string        `json:",omitempty"` // series title Jellyfin already has for this show
 
 	UserDecision  DecisionType // User's choice
 	CustomTitle   string       // Custom title if DecisionCustomTitle
@@ -126,6 +148,9 @@ func ExtractTVShowTitle(name string) (title string, year string) {
 	// Collapse spaces and trim
 	name = strings.TrimSpace(collapseSpacesRegex.ReplaceAllString(name, " "))
 
+	// Prefer the casing of a title already verified by TVDB/OMDB this session
+	name = globalAPICache.CanonicalTitle(name)
+
 	return name, year
 }
 
@@ -442,6 +467,7 @@ func GetAmbiguousTVShows(resolutions []*TVTitleResolution) []*TVTitleResolution
 // ClearAPICache clears the session API cache
 func ClearAPICache() {
 	globalAPICache.Clear()
+	episodeCache.clear()
 }
 
 // TVDBSearchResult represents a search result from TVDB API
@@ -464,10 +490,43 @@ type TVDBSeries struct {
 	Type         string   `json:"type"`
 }
 
+// Metadata API base URLs used by new clients
+// Overridable so integration tests and the demo can point at a mock server
+var (
+	TVDBBaseURL    = "https://api4.thetvdb.com/v4"
+	OMDBBaseURL    = "https://www.omdbapi.com/"
+	TMDBBaseURL    = "https://api.themoviedb.org/3"
+	AniListBaseURL = "https://graphql.anilist.co"
+)
+
+// API keys used to verify ambiguous TV titles (and, with TMDB, movie
+// titles) during scans
+var (
+	scanTVDBKey string
+	scanOMDBKey string
+	scanTMDBKey string
+	apiKeysMu   sync.RWMutex
+)
+
+// SetAPIKeys sets the TVDB/OMDB/TMDB keys used during scans; empty keys disable that provider
+func SetAPIKeys(tvdbKey, omdbKey, tmdbKey string) {
+	apiKeysMu.Lock()
+	defer apiKeysMu.Unlock()
+	scanTVDBKey, scanOMDBKey, scanTMDBKey = tvdbKey, omdbKey, tmdbKey
+}
+
+// apiKeys returns the keys set by SetAPIKeys
+func apiKeys() (tvdbKey, omdbKey, tmdbKey string) {
+	apiKeysMu.RLock()
+	defer apiKeysMu.RUnlock()
+	return scanTVDBKey, scanOMDBKey, scanTMDBKey
+}
+
 // TVDBClient handles TVDB API requests
 type TVDBClient struct {
 	APIKey     string
 	Token      string
+	BaseURL    string
 	HTTPClient *http.Client
 }
 
@@ -482,10 +541,9 @@ type TVDBLoginResponse struct {
 // NewTVDBClient creates a new TVDB API client
 func NewTVDBClient(apiKey string) *TVDBClient {
 	return &TVDBClient{
-		APIKey: apiKey,
-		HTTPClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		APIKey:     apiKey,
+		BaseURL:    TVDBBaseURL,
+		HTTPClient: newAPIHTTPClient(),
 	}
 }
 
@@ -495,7 +553,7 @@ func (c *TVDBClient) Login() error {
 		return fmt.Errorf("TVDB API key not configured")
 	}
 
-	loginURL := "https://api4.thetvdb.com/v4/login"
+	loginURL := c.BaseURL + "/login"
 
 	payload := map[string]string{
 		"apikey": c.APIKey,
@@ -516,7 +574,7 @@ func (c *TVDBClient) Login() error {
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("login request failed: %w", err)
+		return fmt.Errorf("login request failed: %w", describeRequestError(err))
 	}
 	defer resp.Body.Close()
 
@@ -552,13 +610,22 @@ func (c *TVDBClient) SearchSeriesWithRetry(name string, maxRetries int) ([]TVDBS
 	cacheKey := "tvdb:" + name
 	if cached, ok := globalAPICache.Get(cacheKey); ok {
 		if cached.Verified {
-			return []TVDBSeries{{Name: cached.Title, Year: cached.Year}}, nil
+			return []TVDBSeries{{Name: cached.Title, Year: cached.Year, ID: cached.ID}}, nil
 		}
 		return nil, fmt.Errorf("cached: %s", cached.Reason)
 	}
 
 	var lastErr error
 	for attempt := 0; attempt <= maxRetries; attempt++ {
+		// Offline: give up without caching so the next scan tries again
+		if err := tvdbBreaker.Allow(); err != nil {
+			if lastErr == nil {
+				apiStats.skip(tvdbBreaker.Name)
+			} else {
+				apiStats.record(tvdbBreaker.Name, lastErr)
+			}
+			return nil, err
+		}
 		if attempt > 0 {
 			backoff := time.Duration(math.Pow(2, float64(attempt-1))) * time.Second
 			time.Sleep(backoff)
@@ -566,13 +633,16 @@ func (c *TVDBClient) SearchSeriesWithRetry(name string, maxRetries int) ([]TVDBS
 
 		if c.Token == "" {
 			if err := c.Login(); err != nil {
+				if isUnreachable(err) {
+					tvdbBreaker.Failure()
+				}
 				lastErr = fmt.Errorf("failed to authenticate: %w", err)
 				continue
 			}
 		}
 
 		encodedName := url.QueryEscape(name)
-		apiURL := fmt.Sprintf("https://api4.thetvdb.com/v4/search?query=%s&type=series", encodedName)
+		apiURL := fmt.Sprintf("%s/search?query=%s&type=series", c.BaseURL, encodedName)
 
 		req, err := http.NewRequest("GET", apiURL, nil)
 		if err != nil {
@@ -585,9 +655,15 @@ func (c *TVDBClient) SearchSeriesWithRetry(name string, maxRetries int) ([]TVDBS
 
 		resp, err := c.HTTPClient.Do(req)
 		if err != nil {
-			lastErr = fmt.Errorf("API request failed: %w", err)
+			tvdbBreaker.Failure()
+			lastErr = fmt.Errorf("API request failed: %w", describeRequestError(err))
 			continue
 		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			tvdbBreaker.Failure()
+		} else {
+			tvdbBreaker.Success()
+		}
 
 		if resp.StatusCode == http.StatusUnauthorized {
 			c.Token = ""
@@ -621,15 +697,18 @@ func (c *TVDBClient) SearchSeriesWithRetry(name string, maxRetries int) ([]TVDBS
 			globalAPICache.Set(cacheKey, &APICacheEntry{
 				Title:      result.Data[0].Name,
 				Year:       result.Data[0].Year,
+				ID:         result.Data[0].ID,
 				Verified:   true,
 				Confidence: 0.95,
 				Timestamp:  time.Now(),
 			})
 		}
 
+		apiStats.record(tvdbBreaker.Name, nil)
 		return result.Data, nil
 	}
 
+	apiStats.record(tvdbBreaker.Name, lastErr)
 	globalAPICache.Set(cacheKey, &APICacheEntry{
 		Verified:  false,
 		Reason:    lastErr.Error(),
@@ -642,10 +721,11 @@ func (c *TVDBClient) SearchSeriesWithRetry(name string, maxRetries int) ([]TVDBS
 // OMDBClient handles OMDB API requests
 type OMDBClient struct {
 	APIKey     string
+	BaseURL    string
 	HTTPClient *http.Client
 }
 
-// OMDBSeries represents a TV series from OMDB
+// OMDBSeries represents a TV series, or a movie, from OMDB
 type OMDBSeries struct {
 	Title  string `json:"Title"`
 	Year   string `json:"Year"`
@@ -657,11 +737,44 @@ type OMDBSeries struct {
 // NewOMDBClient creates a new OMDB API client
 func NewOMDBClient(apiKey string) *OMDBClient {
 	return &OMDBClient{
-		APIKey: apiKey,
-		HTTPClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		APIKey:     apiKey,
+		BaseURL:    OMDBBaseURL,
+		HTTPClient: newAPIHTTPClient(),
+	}
+}
+
+// VerifyKey performs a single uncached request to check the API key is accepted
+// OMDB has no login endpoint, so this is the closest equivalent to TVDB's Login
+func (c *OMDBClient) VerifyKey() error {
+	if c.APIKey == "" {
+		return fmt.Errorf("OMDB API key not configured")
+	}
+
+	apiURL := fmt.Sprintf("%s?i=tt0411008&apikey=%s", c.BaseURL, url.QueryEscape(c.APIKey))
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", describeRequestError(err))
+	}
+	defer resp.Body.Close()
+
+	var result OMDBSeries
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to parse response (status %d): %w", resp.StatusCode, err)
 	}
+	if result.Error != "" {
+		return fmt.Errorf("OMDB error: %s", result.Error)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	return nil
 }
 
 // SearchSeries searches OMDB for a series by name with retry logic
@@ -671,11 +784,25 @@ func (c *OMDBClient) SearchSeries(name string) (*OMDBSeries, error) {
 
 // SearchSeriesWithRetry searches OMDB with configurable retry count
 func (c *OMDBClient) SearchSeriesWithRetry(name string, maxRetries int) (*OMDBSeries, error) {
+	return c.searchWithRetry("series", name, "", maxRetries)
+}
+
+// SearchMovie looks up a movie on OMDB by title and (optional) year with
+// retry logic
+func (c *OMDBClient) SearchMovie(title, year string) (*OMDBSeries, error) {
+	return c.searchWithRetry("movie", title, year, 3)
+}
+
+// searchWithRetry looks up the "series" or "movie" OMDB has under name
+func (c *OMDBClient) searchWithRetry(kind, name, year string, maxRetries int) (*OMDBSeries, error) {
 	if c.APIKey == "" {
 		return nil, fmt.Errorf("OMDB API key not configured")
 	}
 
 	cacheKey := "omdb:" + name
+	if kind != "series" {
+		cacheKey = "omdb:" + kind + ":" + name + "|" + year
+	}
 	if cached, ok := globalAPICache.Get(cacheKey); ok {
 		if cached.Verified {
 			return &OMDBSeries{Title: cached.Title, Year: cached.Year}, nil
@@ -685,13 +812,25 @@ func (c *OMDBClient) SearchSeriesWithRetry(name string, maxRetries int) (*OMDBSe
 
 	var lastErr error
 	for attempt := 0; attempt <= maxRetries; attempt++ {
+		// Offline: give up without caching so the next scan tries again
+		if err := omdbBreaker.Allow(); err != nil {
+			if lastErr == nil {
+				apiStats.skip(omdbBreaker.Name)
+			} else {
+				apiStats.record(omdbBreaker.Name, lastErr)
+			}
+			return nil, err
+		}
 		if attempt > 0 {
 			backoff := time.Duration(math.Pow(2, float64(attempt-1))) * time.Second
 			time.Sleep(backoff)
 		}
 
 		encodedName := url.QueryEscape(name)
-		apiURL := fmt.Sprintf("https://www.omdbapi.com/?t=%s&type=series&apikey=%s", encodedName, c.APIKey)
+		apiURL := fmt.Sprintf("%s?t=%s&type=%s&apikey=%s", c.BaseURL, encodedName, kind, c.APIKey)
+		if year != "" {
+			apiURL += "&y=" + year
+		}
 
 		req, err := http.NewRequest("GET", apiURL, nil)
 		if err != nil {
@@ -703,9 +842,15 @@ func (c *OMDBClient) SearchSeriesWithRetry(name string, maxRetries int) (*OMDBSe
 
 		resp, err := c.HTTPClient.Do(req)
 		if err != nil {
-			lastErr = fmt.Errorf("API request failed: %w", err)
+			omdbBreaker.Failure()
+			lastErr = fmt.Errorf("API request failed: %w", describeRequestError(err))
 			continue
 		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			omdbBreaker.Failure()
+		} else {
+			omdbBreaker.Success()
+		}
 
 		if resp.StatusCode == http.StatusTooManyRequests {
 			resp.Body.Close()
@@ -730,6 +875,12 @@ func (c *OMDBClient) SearchSeriesWithRetry(name string, maxRetries int) (*OMDBSe
 
 		if result.Error != "" {
 			lastErr = fmt.Errorf("OMDB error: %s", result.Error)
+			// "Series not found!" is an answer; anything else (bad key, limit reached) is a failure
+			if strings.Contains(strings.ToLower(result.Error), "not found") {
+				apiStats.record(omdbBreaker.Name, nil)
+			} else {
+				apiStats.record(omdbBreaker.Name, lastErr)
+			}
 			globalAPICache.Set(cacheKey, &APICacheEntry{
 				Verified:  false,
 				Reason:    result.Error,
@@ -746,9 +897,11 @@ func (c *OMDBClient) SearchSeriesWithRetry(name string, maxRetries int) (*OMDBSe
 			Timestamp:  time.Now(),
 		})
 
+		apiStats.record(omdbBreaker.Name, nil)
 		return &result, nil
 	}
 
+	apiStats.record(omdbBreaker.Name, lastErr)
 	globalAPICache.Set(cacheKey, &APICacheEntry{
 		Verified:  false,
 		Reason:    lastErr.Error(),
@@ -758,52 +911,93 @@ func (c *OMDBClient) SearchSeriesWithRetry(name string, maxRetries int) (*OMDBSe
 	return nil, fmt.Errorf("failed after %d retries: %w", maxRetries, lastErr)
 }
 
-// VerifyTVShowTitle uses TVDB (with OMDB fallback) to verify and resolve a TV show title
-func VerifyTVShowTitle(resolution *TVTitleResolution, tvdbKey, omdbKey string) error {
-	return VerifyTVShowTitleWithReporter(resolution, tvdbKey, omdbKey, nil)
+// VerifyTVShowTitle uses TVDB (with OMDB, then TMDB fallback) to verify and resolve a TV show title
+func VerifyTVShowTitle(resolution *TVTitleResolution, tvdbKey, omdbKey, tmdbKey string) error {
+	return VerifyTVShowTitleWithReporter(resolution, tvdbKey, omdbKey, tmdbKey, nil)
 }
 
-// VerifyTVShowTitleWithReporter verifies title using TVDB/OMDB and reports errors to provided ProgressReporter
-func VerifyTVShowTitleWithReporter(resolution *TVTitleResolution, tvdbKey, omdbKey string, pr *ProgressReporter) error {
-	if tvdbKey == "" && omdbKey == "" {
-		err := fmt.Errorf("no API keys configured (TVDB or OMDB required)")
+// VerifyTVShowTitleWithReporter verifies title using TVDB/OMDB/TMDB and reports errors to provided ProgressReporter
+func VerifyTVShowTitleWithReporter(resolution *TVTitleResolution, tvdbKey, omdbKey, tmdbKey string, pr *ProgressReporter) error {
+	if tvdbKey == "" && omdbKey == "" && tmdbKey == "" {
+		err := fmt.Errorf("no API keys configured (TVDB, OMDB or TMDB required)")
 		if pr != nil {
 			pr.LogError(err, "no API keys configured")
 		}
 		return err
 	}
 
-	// Try TVDB first
-	if tvdbKey != "" {
+	// Try TVDB first (skipped once its circuit breaker has tripped)
+	if tvdbKey != "" && !tvdbBreaker.IsOpen() {
 		if err := verifyWithTVDB(resolution, tvdbKey); err == nil {
 			if pr != nil {
-				pr.SendSeverityImmediate("info", fmt.Sprintf("TVDB verified: %s", resolution.ResolvedTitle))
+				pr.SendSeverityImmediate(SeverityInfo, fmt.Sprintf("TVDB verified: %s", resolution.ResolvedTitle))
 			}
 			return nil
 		} else if pr != nil {
-			pr.LogError(err, "TVDB verification failed")
+			logVerifyFailure(pr, tvdbBreaker, err)
 		}
 	}
 
 	// Fallback to OMDB if TVDB fails
-	if omdbKey != "" {
+	if omdbKey != "" && !omdbBreaker.IsOpen() {
 		if err := verifyWithOMDB(resolution, omdbKey); err == nil {
 			if pr != nil {
-				pr.SendSeverityImmediate("info", fmt.Sprintf("OMDB verified: %s", resolution.ResolvedTitle))
+				pr.SendSeverityImmediate(SeverityInfo, fmt.Sprintf("OMDB verified: %s", resolution.ResolvedTitle))
+			}
+			return nil
+		} else if pr != nil {
+			logVerifyFailure(pr, omdbBreaker, err)
+		}
+	}
+
+	// Last resort: TMDB
+	if tmdbKey != "" && !tmdbBreaker.IsOpen() {
+		if err := verifyWithTMDB(resolution, tmdbKey); err == nil {
+			if pr != nil {
+				pr.SendSeverityImmediate(SeverityInfo, fmt.Sprintf("TMDB verified: %s", resolution.ResolvedTitle))
 			}
 			return nil
 		} else if pr != nil {
-			pr.LogError(err, "OMDB verification failed")
+			logVerifyFailure(pr, tmdbBreaker, err)
 		}
 	}
 
-	err := fmt.Errorf("both TVDB and OMDB verification failed")
+	// Every configured provider is unreachable: mark the title instead of failing it
+	if (tvdbKey == "" || tvdbBreaker.IsOpen()) && (omdbKey == "" || omdbBreaker.IsOpen()) && (tmdbKey == "" || tmdbBreaker.IsOpen()) {
+		resolution.APIStatus = ErrAPIOffline.Error()
+		return fmt.Errorf("API verification %w", ErrAPIOffline)
+	}
+
+	err := fmt.Errorf("TVDB, OMDB and TMDB verification failed")
 	if pr != nil {
-		pr.LogError(err, "verification failed for both services")
+		pr.LogError(err, "verification failed for every configured service")
 	}
 	return err
 }
 
+// logVerifyFailure reports a failed lookup, or a single warning when the
+// failure tripped the provider's breaker
+func logVerifyFailure(pr *ProgressReporter, breaker *CircuitBreaker, err error) {
+	if breaker.IsOpen() {
+		pr.SendSeverityImmediate(SeverityWarn, fmt.Sprintf("%s unreachable (%v), skipping %s verification for the rest of this scan", breaker.Name, err, breaker.Name))
+		return
+	}
+	pr.LogError(err, fmt.Sprintf("%s verification failed", breaker.Name))
+}
+
+// recordAPIConflict marks resolution as matching two different series, the
+// folder's and the filename's; it stays ambiguous unless autoResolveConflict
+// can settle it
+func recordAPIConflict(resolution *TVTitleResolution, provider, folderSeries, filenameSeries string) {
+	resolution.FolderMatch.APITitle = folderSeries
+	resolution.FilenameMatch.APITitle = filenameSeries
+	resolution.ResolvedTitle = folderSeries
+	resolution.APIVerified = true
+	resolution.IsAmbiguous = true
+	resolution.Confidence = 0.6
+	resolution.Reason = fmt.Sprintf("%s conflict: '%s' (folder) vs '%s' (filename) - different series", provider, folderSeries, filenameSeries)
+}
+
 // verifyWithTVDB uses TVDB API to verify title with retry and caching
 func verifyWithTVDB(resolution *TVTitleResolution, apiKey string) error {
 	if apiKey == "" {
@@ -851,11 +1045,7 @@ func verifyWithTVDB(resolution *TVTitleResolution, apiKey string) error {
 			return nil
 		}
 
-		resolution.ResolvedTitle = folderResults[0].Name
-		resolution.APIVerified = true
-		resolution.IsAmbiguous = true
-		resolution.Confidence = 0.6
-		resolution.Reason = fmt.Sprintf("TVDB conflict: '%s' (folder) vs '%s' (filename) - different series", folderResults[0].Name, filenameResults[0].Name)
+		recordAPIConflict(resolution, "TVDB", folderResults[0].Name, filenameResults[0].Name)
 		return nil
 	}
 
@@ -905,11 +1095,7 @@ func verifyWithOMDB(resolution *TVTitleResolution, apiKey string) error {
 			return nil
 		}
 
-		resolution.ResolvedTitle = folderResult.Title
-		resolution.APIVerified = true
-		resolution.IsAmbiguous = true
-		resolution.Confidence = 0.6
-		resolution.Reason = fmt.Sprintf("OMDB conflict: '%s' (folder) vs '%s' (filename) - different series", folderResult.Title, filenameResult.Title)
+		recordAPIConflict(resolution, "OMDB", folderResult.Title, filenameResult.Title)
 		return nil
 	}
 
@@ -32,7 +32,7 @@ func TestExtractTVShowTitle(t *testing.T) {
 		{
 			name:          "Show with subtitle",
 			input:         "Marvels Agents of SHIELD (2013)",
-			expectedTitle: "Marvels Agents Of SHIELD",
+			expectedTitle: "Marvels Agents of SHIELD",
 			expectedYear:  "2013",
 		},
 		{
@@ -247,7 +247,7 @@ func TestResolveTVShowTitle_EdgeCases(t *testing.T) {
 			name:        "Abbreviation in folder vs full in filename",
 			filePath:    "/storage/SHIELD (2013)/Season 01/Agents of SHIELD S01E01.mkv",
 			expectAmb:   true,
-			expectTitle: "Agents Of SHIELD",
+			expectTitle: "Agents of SHIELD",
 		},
 		{
 			name:        "Release group markers in filename",
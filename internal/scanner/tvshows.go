@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,19 +11,46 @@ import (
 
 // TVDuplicate represents a group of duplicate TV episodes
 type TVDuplicate struct {
-	ShowName string   // Normalized show name
-	Season   int      // Season number
-	Episode  int      // Episode number
-	Files    []TVFile // All versions found
+	ID              string         // Stable group ID (hash of show, season and episode)
+	ShowName        string         // Normalized show name
+	Season          int            // Season number
+	Episode         int            // Episode number
+	Absolute        bool           `json:",omitempty"` // anime episode numbered across the series; Season is 0
+	Files           []TVFile       // All versions found
+	Scope           DuplicateScope `json:",omitempty"` // scope the copies were grouped in
+	Within          string         `json:",omitempty"` // folder or library path a same-folder or library group is confined to
+	Excluded        bool           `json:",omitempty"` // left out of cleans by the user; every file is kept
+	MergeCompanions *bool          `json:",omitempty"` // per-group choice to move deleted copies' subtitles and artwork to the keeper; nil follows the global setting
 }
 
 // TVFile represents a single TV episode file
 type TVFile struct {
-	Path       string // Full path to file
-	Size       int64  // File size in bytes
-	Resolution string // 1080p, 720p, etc.
-	Source     string // BluRay, WEB-DL, HDTV, etc.
-	IsEmpty    bool   // True if 0 bytes or missing
+	Path       string     // Full path to file
+	Size       int64      // File size in bytes
+	Resolution string     // 1080p, 720p, etc. (probed when ffprobe is available, else from the filename)
+	Source     string     // BluRay, WEB-DL, HDTV, etc.
+	IsEmpty    bool       // True if 0 bytes or missing
+	Probe      *MediaInfo `json:",omitempty"` // ffprobe metadata, nil when not probed
+	Inode      string     `json:",omitempty"` // "device:inode" of a file with several hard links
+	Links      int        `json:",omitempty"` // hard links to the file, when more than one
+}
+
+// EpisodeLabel returns "S01E02", or "E120" for an episode numbered across
+// the series
+func (d TVDuplicate) EpisodeLabel() string {
+	if d.Absolute {
+		return fmt.Sprintf("E%02d", d.Episode)
+	}
+	return fmt.Sprintf("S%02dE%02d", d.Season, d.Episode)
+}
+
+// tvEpisodeKey is the group key of an episode: show|S##E##, or show|E##
+// for an episode numbered across the series
+func tvEpisodeKey(show string, season, episode int, absolute bool) string {
+	if absolute {
+		return fmt.Sprintf("%s|E%02d", show, episode)
+	}
+	return fmt.Sprintf("%s|S%02dE%02d", show, season, episode)
 }
 
 // ScanTVShows scans TV library paths for duplicate episodes
@@ -33,9 +61,19 @@ func ScanTVShows(paths []string) ([]TVDuplicate, error) {
 
 // ScanTVShowsWithProgress scans TV library paths with progress reporting
 func ScanTVShowsWithProgress(paths []string, progressCh chan<- ScanProgress) ([]TVDuplicate, error) {
+	inv, err := takeInventory(context.Background(), paths, nil)
+	if err != nil {
+		return nil, err
+	}
+	return scanTVInventory(inv, paths, progressCh)
+}
+
+// scanTVInventory groups the episode files an inventory holds below paths
+// into duplicates
+func scanTVInventory(inv *inventory, paths []string, progressCh chan<- ScanProgress) ([]TVDuplicate, error) {
 	var pr *ProgressReporter
 	if progressCh != nil {
-		pr = NewProgressReporterWithInterval(progressCh, "scanning_tv", 200*time.Millisecond)
+		pr = NewProgressReporterWithInterval(progressCh, OpScanningTV, 200*time.Millisecond)
 		pr.StageUpdate("validating", "Validating library paths...")
 
 		if err := ValidateBeforeScan(paths, "TV scan", pr); err != nil {
@@ -43,49 +81,36 @@ func ScanTVShowsWithProgress(paths []string, progressCh chan<- ScanProgress) ([]
 			return nil, fmt.Errorf("validation failed: %w", err)
 		}
 
-		pr.StageUpdate("counting_files", "Counting TV files...")
-		total, err := CountVideoFilesWithProgress(paths, pr)
-		if err != nil {
-			pr.LogCritical(err, "Failed to count TV files")
-			return nil, fmt.Errorf("failed to count TV files: %w", err)
-		}
-
+		total := inv.videos(paths)
 		if total == 0 {
-			pr.Send("warn", "No video files found in accessible paths")
+			pr.Send(SeverityWarn, "No video files found in accessible paths")
 			return []TVDuplicate{}, nil
 		}
 
 		pr.Start(total, fmt.Sprintf("Scanning %d TV files for duplicates...", total))
 	}
 
-	episodeGroups := make(map[string]*TVDuplicate)
+	episodeGroups := make(map[string]*TVDuplicate) // by titleGroupKey until confirmed
+	scope := GetDuplicateScopes().TV
 	filesProcessed := 0
 
 	for _, libPath := range paths {
-		if _, err := os.Stat(libPath); err != nil {
+		lib := inv.library(libPath)
+		if lib.err != nil {
 			if pr != nil {
-				pr.Send("warn", fmt.Sprintf("Skipping inaccessible path: %s", libPath))
+				pr.Send(SeverityWarn, fmt.Sprintf("Skipping inaccessible path: %s", libPath))
 			}
 			continue
 		}
+		anime := isAnimeLibrary(libPath)
 
-		// Walk directory tree
-		err := filepath.Walk(libPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				if pr != nil {
-					pr.LogError(err, fmt.Sprintf("Error accessing path during walk: %s", path))
-				}
-				return err
-			}
-
-			// Skip directories
-			if info.IsDir() {
-				return nil
-			}
+		for _, file := range lib.files {
+			path, info := file.path, file.info
 
-			// Only process video files
-			if !isVideoFile(path) {
-				return nil
+			// Only process video files; extras such as
+			// "Show.S01E01.Behind.The.Scenes.mkv" are not copies of the episode
+			if !isVideoFile(path) || isExtraFile(path, info.Size()) {
+				continue
 			}
 
 			filesProcessed++
@@ -93,27 +118,43 @@ func ScanTVShowsWithProgress(paths []string, progressCh chan<- ScanProgress) ([]
 				pr.Update(filesProcessed, fmt.Sprintf("Processing: %s", filepath.Base(path)))
 			}
 
-			// Extract episode info from filename
-			season, episode, found := ExtractEpisodeInfo(filepath.Base(path))
-			if !found {
-				// Not a TV episode format, skip
-				return nil
+			// Extract episode info from filename; anime releases carry
+			// [Group] and CRC tags and may be numbered across the series
+			var season, episode int
+			var absolute bool
+			var showName string
+			if anime {
+				ep, ok := ParseAnimeFilename(filepath.Base(path))
+				if !ok {
+					continue
+				}
+				season, episode, absolute = ep.Season, ep.Episode, ep.Absolute
+				showName, _ = animeLocalTitle(libPath, path, ep)
+			} else {
+				var found bool
+				season, episode, found = ExtractEpisodeInfo(filepath.Base(path))
+				if !found {
+					// Not a TV episode format, skip
+					continue
+				}
+
+				// Extract show name intelligently using title resolution logic
+				// This handles both:
+				// 1. Jellyfin structure: Show Name (Year)/Season ##/episode.mkv
+				// 2. Flat structure: Show.Name.S01E01.mkv (no Season folder)
+				showName = extractShowNameFromPath(path)
 			}
 
 			// Parse TV file metadata
 			tvFile := parseTVFile(path, info)
 
-			// Extract show name intelligently using title resolution logic
-			// This handles both:
-			// 1. Jellyfin structure: Show Name (Year)/Season ##/episode.mkv
-			// 2. Flat structure: Show.Name.S01E01.mkv (no Season folder)
-			showName := extractShowNameFromPath(path)
-
 			// Normalize show name
 			normalized := NormalizeName(showName)
 
-			// Create group key: normalized_show|S##E##
-			key := fmt.Sprintf("%s|S%02dE%02d", normalized, season, episode)
+			// Create group key: normalized_show|S##E## within the duplicate
+			// scope, confirmed against the group's titles once all are known
+			within := scope.within(libPath, path)
+			key := titleGroupKey(scopedGroupKey(within, tvEpisodeKey(normalized, season, episode, absolute)), normalized, showName, path)
 
 			// Add to group
 			if _, exists := episodeGroups[key]; !exists {
@@ -121,19 +162,18 @@ func ScanTVShowsWithProgress(paths []string, progressCh chan<- ScanProgress) ([]
 					ShowName: normalized,
 					Season:   season,
 					Episode:  episode,
+					Absolute: absolute,
 					Files:    []TVFile{},
+					Scope:    scope,
+					Within:   within,
 				}
 			}
 			episodeGroups[key].Files = append(episodeGroups[key].Files, tvFile)
-
-			return nil
-		})
-
-		if err != nil {
-			return nil, fmt.Errorf("error scanning %s: %w", libPath, err)
 		}
 	}
 
+	episodeGroups = confirmTitleGroups(episodeGroups, mergeTVGroup)
+
 	// Filter to only duplicates (2+ files per episode)
 	var duplicates []TVDuplicate
 	for _, group := range episodeGroups {
@@ -183,12 +223,15 @@ func extractShowNameFromPath(path string) string {
 
 // parseTVFile extracts metadata from TV episode file
 func parseTVFile(path string, info os.FileInfo) TVFile {
+	inode, links := fileLink(info)
 	return TVFile{
 		Path:       path,
 		Size:       info.Size(),
 		Resolution: ExtractResolution(path),
 		Source:     extractSource(path),
 		IsEmpty:    info.Size() == 0,
+		Inode:      inode,
+		Links:      links,
 	}
 }
 
@@ -221,11 +264,12 @@ func MarkKeepDeleteTV(duplicates []TVDuplicate) []TVDuplicate {
 		group := &duplicates[i]
 
 		// Find best file (highest quality score)
+		scores := tvKeepScores(group.Files)
 		bestIdx := 0
-		bestScore := scoreTVFile(group.Files[0])
+		bestScore := scores[0]
 
 		for j := 1; j < len(group.Files); j++ {
-			score := scoreTVFile(group.Files[j])
+			score := scores[j]
 			if score > bestScore {
 				bestScore = score
 				bestIdx = j
@@ -239,6 +283,22 @@ func MarkKeepDeleteTV(duplicates []TVDuplicate) []TVDuplicate {
 	return duplicates
 }
 
+// tvKeepScores scores every file of a group, marking copies that are much
+// shorter than the others as incomplete
+func tvKeepScores(files []TVFile) []int {
+	infos := make([]*MediaInfo, len(files))
+	for i, file := range files {
+		infos[i] = file.Probe
+	}
+	longest := longestDuration(infos...)
+
+	scores := make([]int, len(files))
+	for i, file := range files {
+		scores[i] = scoreTVFile(file) + truncatedPenalty(file.Probe, longest)
+	}
+	return scores
+}
+
 // scoreTVFile assigns quality score for TV episodes
 // Higher score = better to keep
 func scoreTVFile(file TVFile) int {
@@ -285,6 +345,9 @@ func scoreTVFile(file TVFile) int {
 	}
 	score += int(sizeGB)
 
+	// Probed codec, bitrate and audio break ties within a resolution
+	score += probeScore(file.Probe)
+
 	return score
 }
 
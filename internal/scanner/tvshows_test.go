@@ -260,13 +260,13 @@ func TestTVShowAbbreviationPreservation(t *testing.T) {
 		input    string
 		expected string
 	}{
-		{"Marvels.Agents.of.S.H.I.E.L.D.2013.1080p.BluRay-GROUP", "Marvels Agents Of S.H.I.E.L.D. (2013)"},
+		{"Marvels.Agents.of.S.H.I.E.L.D.2013.1080p.BluRay-GROUP", "Marvels Agents of S.H.I.E.L.D. (2013)"},
 		{"S.W.A.T.2017.720p.WEB-DL", "S.W.A.T. (2017)"},
 		{"N.C.I.S.2003", "N.C.I.S. (2003)"},
 		{"C.S.I.Crime.Scene.Investigation.2000", "C.S.I. Crime Scene Investigation (2000)"},
 		{"FBI.2018.1080p.HDTV", "FBI (2018)"},
 		{"SWAT.2017", "SWAT (2017)"},
-		{"Marvel's.Agents.of.SHIELD.S01E01", "Marvel's Agents Of SHIELD"},
+		{"Marvel's.Agents.of.SHIELD.S01E01", "Marvel's Agents of SHIELD"},
 		{"The.X-Files.1993", "The X-Files (1993)"},
 		{"Spider-Man.The.Animated.Series.1994", "Spider-Man The Animated Series (1994)"},
 		{"Star.Trek.TNG.1987", "Star Trek TNG (1987)"},
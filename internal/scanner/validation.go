@@ -151,6 +151,10 @@ func quickCountVideos(path string) (int, int64) {
 			return filepath.SkipDir
 		}
 
+		if skip, skipErr := walkSkip(path, p, info); skip {
+			return skipErr
+		}
+
 		if !info.IsDir() && isVideoFile(p) {
 			count++
 			totalSize += info.Size()
@@ -164,7 +168,7 @@ func quickCountVideos(path string) (int, int64) {
 
 func ValidateBeforeScan(paths []string, operation string, pr *ProgressReporter) error {
 	if pr != nil {
-		pr.Send("info", fmt.Sprintf("Validating %d library paths for %s...", len(paths), operation))
+		pr.Send(SeverityInfo, fmt.Sprintf("Validating %d library paths for %s...", len(paths), operation))
 	}
 
 	requireWritable := strings.Contains(operation, "rename") ||
@@ -175,15 +179,15 @@ func ValidateBeforeScan(paths []string, operation string, pr *ProgressReporter)
 
 	if pr != nil {
 		if report.AccessiblePaths > 0 {
-			pr.Send("info", fmt.Sprintf("✓ %d/%d paths accessible", report.AccessiblePaths, report.TotalPaths))
+			pr.Send(SeverityInfo, fmt.Sprintf("✓ %d/%d paths accessible", report.AccessiblePaths, report.TotalPaths))
 		}
 
 		for _, inaccessible := range report.InaccessiblePaths {
-			pr.Send("warn", fmt.Sprintf("✗ Skipping %s: %v", inaccessible.Path, inaccessible.Error))
+			pr.Send(SeverityWarn, fmt.Sprintf("✗ Skipping %s: %v", inaccessible.Path, inaccessible.Error))
 		}
 
 		for _, warning := range report.Warnings {
-			pr.Send("warn", warning)
+			pr.Send(SeverityWarn, warning)
 		}
 
 		if !report.CanProceed {
@@ -0,0 +1,193 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DuplicateStrategy selects what a clean does with the extra copies in a
+// movie duplicate group
+type DuplicateStrategy string
+
+const (
+	// StrategyDelete deletes every copy but the keeper
+	StrategyDelete DuplicateStrategy = "delete"
+	// StrategyMultiVersion keeps every copy, renamed into the keeper's folder
+	// using Jellyfin's multi-version convention ("Movie (2010) - 1080p.mkv")
+	StrategyMultiVersion DuplicateStrategy = "multi-version"
+)
+
+// versionSeparator joins the movie folder name and a version label
+const versionSeparator = " - "
+
+var (
+	duplicateStrategy   = StrategyDelete
+	duplicateStrategyMu sync.RWMutex
+)
+
+// ParseDuplicateStrategy validates a strategy name (case-insensitive)
+func ParseDuplicateStrategy(s string) (DuplicateStrategy, error) {
+	switch DuplicateStrategy(strings.ToLower(strings.TrimSpace(s))) {
+	case StrategyDelete:
+		return StrategyDelete, nil
+	case StrategyMultiVersion:
+		return StrategyMultiVersion, nil
+	default:
+		return "", fmt.Errorf("invalid duplicate strategy: %s (must be delete or multi-version)", s)
+	}
+}
+
+// SetDuplicateStrategy sets the strategy for groups without their own choice
+func SetDuplicateStrategy(s DuplicateStrategy) {
+	duplicateStrategyMu.Lock()
+	defer duplicateStrategyMu.Unlock()
+	duplicateStrategy = s
+}
+
+// GetDuplicateStrategy returns the global duplicate strategy
+func GetDuplicateStrategy() DuplicateStrategy {
+	duplicateStrategyMu.RLock()
+	defer duplicateStrategyMu.RUnlock()
+	return duplicateStrategy
+}
+
+// EffectiveStrategy returns the group's own strategy, or the global one
+func (d MovieDuplicate) EffectiveStrategy() DuplicateStrategy {
+	if d.Strategy != "" {
+		return d.Strategy
+	}
+	return GetDuplicateStrategy()
+}
+
+// KeepsAllVersions reports whether a clean keeps every file of the group
+func (d MovieDuplicate) KeepsAllVersions() bool {
+	return d.EffectiveStrategy() == StrategyMultiVersion
+}
+
+// VersionRename moves one copy of a movie to its multi-version name
+type VersionRename struct {
+	Source string
+	Target string
+}
+
+// MultiVersionPlan returns the renames that file every copy in dup as a
+// version of the keeper: "<folder>/<folder> - <label><ext>", where folder is
+// the keeper's "Title (Year)" folder and label its resolution. Files already
+// named as versions of that folder keep their name and label
+func MultiVersionPlan(dup MovieDuplicate) ([]VersionRename, error) {
+	if len(dup.Files) < 2 {
+		return nil, nil
+	}
+
+	dir := filepath.Dir(dup.Files[0].Path)
+	folder := filepath.Base(dir)
+	if !hasYearInParentheses(folder) || CleanMovieName(folder) != folder {
+		return nil, fmt.Errorf("keeper folder %q is not a \"Title (Year)\" movie folder; fix its naming first", folder)
+	}
+
+	used := make(map[string]bool)
+	named := make([]bool, len(dup.Files))
+	for i, file := range dup.Files {
+		if filepath.Dir(file.Path) != dir {
+			continue
+		}
+		if label, ok := versionLabel(folder, file.Path); ok {
+			used[strings.ToLower(label)] = true
+			named[i] = true
+		}
+	}
+
+	var renames []VersionRename
+	sources := make(map[string]bool, len(dup.Files))
+	for _, file := range dup.Files {
+		sources[file.Path] = true
+	}
+	for i, file := range dup.Files {
+		if named[i] {
+			continue
+		}
+		label := uniqueLabel(resolutionLabel(file.Resolution), used)
+		target := filepath.Join(dir, folder+versionSeparator+label+filepath.Ext(file.Path))
+		if sources[target] {
+			return nil, fmt.Errorf("version name %s is taken by another copy", filepath.Base(target))
+		}
+		renames = append(renames, VersionRename{Source: file.Path, Target: target})
+	}
+	return renames, nil
+}
+
+// ApplyVersionRename moves a copy and its sidecars to its version name,
+// refusing to overwrite and removing the old folder once it is empty
+func ApplyVersionRename(r VersionRename) error {
+	if _, err := os.Stat(r.Target); err == nil {
+		return fmt.Errorf("target file already exists: %s", r.Target)
+	}
+	companions := CompanionMoves(r.Source, r.Target)
+	if err := os.Rename(r.Source, r.Target); err != nil {
+		return fmt.Errorf("failed to rename %s: %w", r.Source, err)
+	}
+	moveCompanions(companions)
+
+	originalDir := filepath.Dir(r.Source)
+	if originalDir != filepath.Dir(r.Target) {
+		if entries, err := os.ReadDir(originalDir); err == nil && len(entries) == 0 {
+			_ = os.Remove(originalDir)
+		}
+	}
+	return nil
+}
+
+// versionLabel returns the label of a file named "<folder> - <label><ext>"
+func versionLabel(folder, path string) (string, bool) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	label, ok := strings.CutPrefix(name, folder+versionSeparator)
+	if !ok || strings.TrimSpace(label) == "" {
+		return "", false
+	}
+	return label, true
+}
+
+// isMultiVersionSet reports whether files are all in one folder and named
+// after it, which Jellyfin shows as versions of a single movie
+func isMultiVersionSet(files []MovieFile) bool {
+	if len(files) < 2 {
+		return false
+	}
+	dir := filepath.Dir(files[0].Path)
+	folder := filepath.Base(dir)
+	versions := 0
+	for _, file := range files {
+		if filepath.Dir(file.Path) != dir {
+			return false
+		}
+		if _, ok := versionLabel(folder, file.Path); ok {
+			versions++
+			continue
+		}
+		if strings.TrimSuffix(filepath.Base(file.Path), filepath.Ext(file.Path)) != folder {
+			return false
+		}
+	}
+	return versions > 0
+}
+
+// resolutionLabel turns a file resolution into a version label
+func resolutionLabel(resolution string) string {
+	if resolution == "" || resolution == "unknown" {
+		return "Version"
+	}
+	return resolution
+}
+
+// uniqueLabel returns label, numbered when already used, and marks it used
+func uniqueLabel(label string, used map[string]bool) string {
+	candidate := label
+	for n := 2; used[strings.ToLower(candidate)]; n++ {
+		candidate = fmt.Sprintf("%s %d", label, n)
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
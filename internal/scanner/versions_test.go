@@ -0,0 +1,151 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMultiVersionPlan(t *testing.T) {
+	dup := MovieDuplicate{
+		NormalizedName: "heat",
+		Year:           "1995",
+		Files: []MovieFile{
+			{Path: "/movies/Heat (1995)/Heat (1995).mkv", Resolution: "2160p"},
+			{Path: "/movies/Heat (1995)/Heat (1995) - Directors Cut.mkv", Resolution: "1080p"},
+			{Path: "/movies/heat.1995.1080p.mp4", Resolution: "1080p"},
+			{Path: "/movies/Heat.1995.BluRay/heat.mkv", Resolution: "1080p"},
+			{Path: "/movies/Heat.1995.DVD/heat.avi", Resolution: "unknown"},
+		},
+	}
+
+	renames, err := MultiVersionPlan(dup)
+	if err != nil {
+		t.Fatalf("MultiVersionPlan() error: %v", err)
+	}
+
+	want := []VersionRename{
+		{"/movies/Heat (1995)/Heat (1995).mkv", "/movies/Heat (1995)/Heat (1995) - 2160p.mkv"},
+		{"/movies/heat.1995.1080p.mp4", "/movies/Heat (1995)/Heat (1995) - 1080p.mp4"},
+		{"/movies/Heat.1995.BluRay/heat.mkv", "/movies/Heat (1995)/Heat (1995) - 1080p 2.mkv"},
+		{"/movies/Heat.1995.DVD/heat.avi", "/movies/Heat (1995)/Heat (1995) - Version.avi"},
+	}
+	if len(renames) != len(want) {
+		t.Fatalf("MultiVersionPlan() = %v, want %v", renames, want)
+	}
+	for i := range want {
+		if renames[i] != want[i] {
+			t.Errorf("rename %d = %v, want %v", i, renames[i], want[i])
+		}
+	}
+
+	// The keeper must already sit in a clean "Title (Year)" folder
+	dup.Files[0].Path = "/movies/Heat.1995.2160p.UHD/heat.mkv"
+	if _, err := MultiVersionPlan(dup); err == nil {
+		t.Error("Expected an error for a keeper outside a Title (Year) folder")
+	}
+}
+
+func TestIsMultiVersionSet(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		want  bool
+	}{
+		{"versions", []string{"/m/Heat (1995)/Heat (1995) - 2160p.mkv", "/m/Heat (1995)/Heat (1995) - 1080p.mkv"}, true},
+		{"plain and version", []string{"/m/Heat (1995)/Heat (1995).mkv", "/m/Heat (1995)/Heat (1995) - 1080p.mkv"}, true},
+		{"no version names", []string{"/m/Heat (1995)/Heat (1995).mkv", "/m/Heat (1995)/heat.1080p.mkv"}, false},
+		{"two folders", []string{"/m/Heat (1995)/Heat (1995) - 2160p.mkv", "/m/Heat/Heat (1995) - 1080p.mkv"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var files []MovieFile
+			for _, p := range tt.paths {
+				files = append(files, MovieFile{Path: p})
+			}
+			if got := isMultiVersionSet(files); got != tt.want {
+				t.Errorf("isMultiVersionSet(%v) = %v, want %v", tt.paths, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuplicateStrategy(t *testing.T) {
+	if _, err := ParseDuplicateStrategy("Multi-Version"); err != nil {
+		t.Errorf("ParseDuplicateStrategy(Multi-Version) error: %v", err)
+	}
+	if _, err := ParseDuplicateStrategy("merge"); err == nil {
+		t.Error("Expected an error for an unknown strategy")
+	}
+
+	defer SetDuplicateStrategy(StrategyDelete)
+	dup := MovieDuplicate{}
+	if dup.KeepsAllVersions() {
+		t.Error("Groups should follow the delete strategy by default")
+	}
+	SetDuplicateStrategy(StrategyMultiVersion)
+	if !dup.KeepsAllVersions() {
+		t.Error("Groups without a choice should follow the global strategy")
+	}
+	dup.Strategy = StrategyDelete
+	if dup.KeepsAllVersions() {
+		t.Error("A group's own strategy should win over the global one")
+	}
+}
+
+func TestScanMoviesSkipsVersionSets(t *testing.T) {
+	tmpDir := t.TempDir()
+	heat := filepath.Join(tmpDir, "Heat (1995)")
+	alien := filepath.Join(tmpDir, "Alien (1979)")
+	os.MkdirAll(heat, 0755)
+	os.MkdirAll(alien, 0755)
+	for _, path := range []string{
+		filepath.Join(heat, "Heat (1995) - 2160p.mkv"),
+		filepath.Join(heat, "Heat (1995) - 1080p.mkv"),
+		filepath.Join(alien, "Alien (1979).mkv"),
+		filepath.Join(alien, "Alien.1979.720p.mkv"),
+	} {
+		os.WriteFile(path, []byte("video"), 0644)
+	}
+
+	duplicates, err := ScanMovies([]string{tmpDir})
+	if err != nil {
+		t.Fatalf("ScanMovies() error: %v", err)
+	}
+	if len(duplicates) != 1 || duplicates[0].Year != "1979" {
+		t.Errorf("Expected only the Alien group, got %+v", duplicates)
+	}
+
+	// Version names are compliant and not flagged for a rename
+	if issue := checkMovieCompliance(filepath.Join(heat, "Heat (1995) - 1080p.mkv"), tmpDir); issue != nil {
+		t.Errorf("Version file flagged: %s", issue.Problem)
+	}
+}
+
+func TestApplyVersionRename(t *testing.T) {
+	tmpDir := t.TempDir()
+	target := filepath.Join(tmpDir, "Heat (1995)")
+	oldDir := filepath.Join(tmpDir, "Heat.1995.BluRay")
+	os.MkdirAll(target, 0755)
+	os.MkdirAll(oldDir, 0755)
+	source := filepath.Join(oldDir, "heat.mkv")
+	os.WriteFile(source, []byte("video"), 0644)
+
+	r := VersionRename{Source: source, Target: filepath.Join(target, "Heat (1995) - 1080p.mkv")}
+	if err := ApplyVersionRename(r); err != nil {
+		t.Fatalf("ApplyVersionRename() error: %v", err)
+	}
+	if _, err := os.Stat(r.Target); err != nil {
+		t.Error("Version file not at its new name")
+	}
+	if _, err := os.Stat(oldDir); !os.IsNotExist(err) {
+		t.Error("Expected the emptied folder to be removed")
+	}
+
+	// Existing files are never overwritten
+	os.WriteFile(source, []byte("other"), 0644)
+	if err := ApplyVersionRename(r); err == nil {
+		t.Error("Expected an error when the version name is taken")
+	}
+}
@@ -0,0 +1,246 @@
+// Package schema generates JSON Schema documents from Go types, so tools
+// outside jellysink can validate the report, config and plan formats
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect generated documents declare
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Options controls how Go types map onto the schema
+type Options struct {
+	Title       string
+	Description string
+	// Tag is the struct tag naming fields: "json" (the default) or "toml"
+	Tag string
+	// Required lists fields without omitempty as required (JSON output
+	// always contains them)
+	Required bool
+	// Strict rejects properties that are not struct fields, catching typos
+	Strict bool
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// generator collects the named struct types referenced from the root
+type generator struct {
+	opts  Options
+	defs  map[string]any
+	names map[reflect.Type]string
+}
+
+// Generate returns the schema for v's type. Non-zero fields of v become the
+// properties' defaults, so passing a type's default value documents them
+func Generate(v any, opts Options) map[string]any {
+	if opts.Tag == "" {
+		opts.Tag = "json"
+	}
+	g := &generator{opts: opts, defs: make(map[string]any), names: make(map[reflect.Type]string)}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	root := g.structSchema(rv.Type(), rv)
+
+	doc := map[string]any{"$schema": Draft}
+	if opts.Title != "" {
+		doc["title"] = opts.Title
+	}
+	if opts.Description != "" {
+		doc["description"] = opts.Description
+	}
+	for k, v := range root {
+		doc[k] = v
+	}
+	if len(g.defs) > 0 {
+		doc["$defs"] = g.defs
+	}
+	return doc
+}
+
+// typeSchema returns the schema for t; named structs other than the root
+// are referenced from $defs
+func (g *generator) typeSchema(t reflect.Type) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == durationType:
+		return map[string]any{"type": "integer", "description": "nanoseconds"}
+	case t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType):
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if g.opts.Tag == "json" {
+			return nullable(g.typeSchema(t.Elem()))
+		}
+		return g.typeSchema(t.Elem())
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		s := map[string]any{"type": "array", "items": g.typeSchema(t.Elem())}
+		if t.Kind() == reflect.Slice && g.opts.Tag == "json" {
+			// nil slices encode as null
+			return nullable(s)
+		}
+		return s
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t, reflect.Value{})
+		}
+		return map[string]any{"$ref": "#/$defs/" + g.define(t)}
+	}
+	// Interfaces and anything else accept any value
+	return map[string]any{}
+}
+
+// define adds a named struct to $defs, returning its name there
+func (g *generator) define(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, taken := g.defs[name]; taken {
+		name = t.String() // package-qualified, e.g. scanner.MediaInfo
+	}
+	g.names[t] = name
+	g.defs[name] = nil // reserved while recursing into self-references
+	g.defs[name] = g.structSchema(t, reflect.Value{})
+	return name
+}
+
+// structSchema describes a struct's fields; v, when valid, supplies defaults
+func (g *generator) structSchema(t reflect.Type, v reflect.Value) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+	g.addFields(t, v, properties, &required)
+
+	s := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	if g.opts.Strict {
+		s["additionalProperties"] = false
+	}
+	return s
+}
+
+// addFields adds t's fields, flattening embedded structs the way
+// encoding/json does
+func (g *generator) addFields(t reflect.Type, v reflect.Value, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitempty, skip := g.fieldName(field)
+		if skip {
+			continue
+		}
+
+		var fv reflect.Value
+		if v.IsValid() {
+			fv = v.Field(i)
+		}
+		// Embedded structs' fields are promoted even when the type is unexported
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && name == "" {
+			g.addFields(field.Type, fv, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		var prop map[string]any
+		switch {
+		case fv.IsValid() && !fv.IsZero() && isPlainStruct(field.Type):
+			// Inlined so its fields can carry their own defaults
+			prop = g.structSchema(field.Type, fv)
+		case fv.IsValid() && !fv.IsZero():
+			prop = g.typeSchema(field.Type)
+			if def, ok := defaultValue(fv); ok {
+				prop = withDefault(prop, def)
+			}
+		default:
+			prop = g.typeSchema(field.Type)
+		}
+		properties[name] = prop
+		if g.opts.Required && !omitempty {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// fieldName reads the field's name and omitempty flag from the struct tag
+func (g *generator) fieldName(field reflect.StructField) (name string, omitempty, skip bool) {
+	tag, ok := field.Tag.Lookup(g.opts.Tag)
+	if !ok {
+		return "", false, false
+	}
+	if tag == "-" {
+		return "", false, true
+	}
+	name, rest, _ := strings.Cut(tag, ",")
+	for _, opt := range strings.Split(rest, ",") {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, false
+}
+
+// isPlainStruct reports whether t is a struct encoded field by field
+func isPlainStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType &&
+		!t.Implements(jsonMarshalerType) && !t.Implements(textMarshalerType)
+}
+
+// defaultValue returns v as a JSON value for "default", skipping values
+// with no plain JSON form
+func defaultValue(v reflect.Value) (any, bool) {
+	switch v.Kind() {
+	case reflect.Struct, reflect.Pointer, reflect.Interface, reflect.Map:
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+// withDefault copies s and sets its default
+func withDefault(s map[string]any, def any) map[string]any {
+	out := make(map[string]any, len(s)+1)
+	for k, v := range s {
+		out[k] = v
+	}
+	out["default"] = def
+	return out
+}
+
+// nullable also accepts null in place of s
+func nullable(s map[string]any) map[string]any {
+	return map[string]any{"anyOf": []any{s, map[string]any{"type": "null"}}}
+}
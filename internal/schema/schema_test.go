@@ -0,0 +1,104 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testFile struct {
+	Path string
+	Size int64 `json:",omitempty"`
+}
+
+type testEmbedded struct {
+	Note string `json:"note"`
+}
+
+type testReport struct {
+	testEmbedded
+	Timestamp time.Time
+	Files     []testFile
+	Keeper    *testFile `json:"keeper,omitempty"`
+	Extra     map[string]int
+	Secret    string `json:"-"`
+	hidden    string
+}
+
+type testSection struct {
+	URL     string   `toml:"url"`
+	Enabled bool     `toml:"enabled"`
+	Words   []string `toml:"words"`
+}
+
+type testConfig struct {
+	Sonarr testSection `toml:"sonarr"`
+	Radarr testSection `toml:"radarr"`
+	Limit  int         `toml:"limit"`
+}
+
+// roundTrip returns the schema as decoded JSON, as a validator would see it
+func roundTrip(t *testing.T, doc map[string]any) map[string]any {
+	t.Helper()
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("schema does not encode: %v", err)
+	}
+	var out map[string]any
+	json.Unmarshal(data, &out)
+	return out
+}
+
+func TestGenerateJSON(t *testing.T) {
+	doc := roundTrip(t, Generate(testReport{}, Options{Title: "report", Required: true}))
+
+	if doc["$schema"] != Draft || doc["title"] != "report" || doc["type"] != "object" {
+		t.Errorf("Unexpected header: %v", doc)
+	}
+	props := doc["properties"].(map[string]any)
+	for _, name := range []string{"note", "Timestamp", "Files", "keeper", "Extra"} {
+		if _, ok := props[name]; !ok {
+			t.Errorf("Expected property %s, got %v", name, props)
+		}
+	}
+	for _, name := range []string{"Secret", "hidden", "testEmbedded"} {
+		if _, ok := props[name]; ok {
+			t.Errorf("Unexpected property %s", name)
+		}
+	}
+	if ts := props["Timestamp"].(map[string]any); ts["format"] != "date-time" {
+		t.Errorf("Expected a date-time timestamp, got %v", ts)
+	}
+	if !reflect.DeepEqual(doc["required"], []any{"note", "Timestamp", "Files", "Extra"}) {
+		t.Errorf("Expected fields without omitempty required, got %v", doc["required"])
+	}
+
+	file := doc["$defs"].(map[string]any)["testFile"].(map[string]any)
+	if !reflect.DeepEqual(file["required"], []any{"Path"}) {
+		t.Errorf("Expected the shared struct defined once with its own required fields, got %v", file)
+	}
+}
+
+func TestGenerateTOMLDefaults(t *testing.T) {
+	defaults := testConfig{Sonarr: testSection{Enabled: true}, Limit: 10}
+	doc := roundTrip(t, Generate(defaults, Options{Tag: "toml", Strict: true}))
+
+	if doc["additionalProperties"] != false || doc["required"] != nil {
+		t.Errorf("Expected a strict schema without required fields, got %v", doc)
+	}
+	props := doc["properties"].(map[string]any)
+	if limit := props["limit"].(map[string]any); limit["default"] != float64(10) {
+		t.Errorf("Expected limit default 10, got %v", limit)
+	}
+	sonarr := props["sonarr"].(map[string]any)["properties"].(map[string]any)
+	if sonarr["enabled"].(map[string]any)["default"] != true {
+		t.Errorf("Expected the inlined section's default, got %v", sonarr)
+	}
+	if words := sonarr["words"].(map[string]any); words["type"] != "array" {
+		t.Errorf("Expected a plain array for TOML, got %v", words)
+	}
+	if ref := props["radarr"].(map[string]any)["$ref"]; ref != "#/$defs/testSection" {
+		t.Errorf("Expected the section without defaults referenced, got %v", props["radarr"])
+	}
+}
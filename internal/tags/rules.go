@@ -0,0 +1,232 @@
+package tags
+
+import (
+	"sync"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// FreezeTag freezes a file or folder like a scanner.FreezeMarker file:
+// findings below it are still reported but nothing there is changed
+const FreezeTag = "frozen"
+
+// Policy tags override, for the tagged movie or show, how cleans resolve
+// its duplicate groups, ahead of the global strategy
+const (
+	KeepSmallestTag = "keep-smallest" // the smallest copy is the keeper
+	KeepAllTag      = "keep-all"      // every copy is kept: movies as Jellyfin versions, episodes left alone
+)
+
+// Rules ties the tag store to the tags the keep-policy and protected-path
+// checks look for. A nil *Rules matches nothing
+type Rules struct {
+	Store     *Store
+	Keep      []string // tagged files are preferred as keepers and never deleted
+	Protected []string // tagged files and folders are never deleted or renamed
+}
+
+// firstOf returns the first tag in names that path carries
+func (r *Rules) firstOf(path string, names []string) string {
+	if r == nil || len(names) == 0 {
+		return ""
+	}
+	for _, t := range r.Store.Effective(path) {
+		for _, name := range names {
+			if t == name {
+				return t
+			}
+		}
+	}
+	return ""
+}
+
+// KeepTag returns the keep tag path carries, or ""
+func (r *Rules) KeepTag(path string) string {
+	return r.firstOf(path, r.keep())
+}
+
+// ProtectingTag returns the protected tag path carries, or ""
+func (r *Rules) ProtectingTag(path string) string {
+	return r.firstOf(path, r.protected())
+}
+
+// Frozen reports whether path or a folder above it carries FreezeTag
+func (r *Rules) Frozen(path string) bool {
+	return r != nil && r.Store.Has(path, FreezeTag)
+}
+
+func (r *Rules) keep() []string {
+	if r == nil {
+		return nil
+	}
+	return r.Keep
+}
+
+func (r *Rules) protected() []string {
+	if r == nil {
+		return nil
+	}
+	return r.Protected
+}
+
+// PreferKeepers moves a keep-tagged file into the keeper slot (index 0) of
+// every duplicate group whose current keeper is untagged. Among several
+// tagged files the first in report order wins. Returns the groups changed
+func (r *Rules) PreferKeepers(movies []scanner.MovieDuplicate, tv []scanner.TVDuplicate) int {
+	if r == nil || len(r.Keep) == 0 {
+		return 0
+	}
+
+	changed := 0
+	for i := range movies {
+		files := movies[i].Files
+		if idx := r.keeperIndex(len(files), func(j int) string { return files[j].Path }); idx > 0 {
+			files[0], files[idx] = files[idx], files[0]
+			changed++
+		}
+	}
+	for i := range tv {
+		files := tv[i].Files
+		if idx := r.keeperIndex(len(files), func(j int) string { return files[j].Path }); idx > 0 {
+			files[0], files[idx] = files[idx], files[0]
+			changed++
+		}
+	}
+	return changed
+}
+
+// Policy returns the policy tag path carries, or "". Among both, keep-all
+// wins, since it deletes nothing
+func (r *Rules) Policy(path string) string {
+	if r == nil {
+		return ""
+	}
+	policy := ""
+	for _, t := range r.Store.Effective(path) {
+		switch t {
+		case KeepAllTag:
+			return t
+		case KeepSmallestTag:
+			policy = t
+		}
+	}
+	return policy
+}
+
+// ApplyPolicies resolves the duplicate groups holding a policy-tagged file
+// by its policy: keep-smallest moves the smallest non-empty copy into the
+// keeper slot, keep-all keeps movie copies as versions and leaves episode
+// groups out of cleans. A strategy already chosen for a movie group is
+// left as it is. Returns the groups changed
+func (r *Rules) ApplyPolicies(movies []scanner.MovieDuplicate, tv []scanner.TVDuplicate) int {
+	if r == nil {
+		return 0
+	}
+
+	changed := 0
+	for i := range movies {
+		dup := &movies[i]
+		switch r.groupPolicy(len(dup.Files), func(j int) string { return dup.Files[j].Path }) {
+		case KeepAllTag:
+			if dup.Strategy == "" {
+				dup.Strategy = scanner.StrategyMultiVersion
+				changed++
+			}
+		case KeepSmallestTag:
+			idx := smallestIndex(len(dup.Files), func(j int) (int64, bool) { return dup.Files[j].Size, dup.Files[j].IsEmpty })
+			if idx > 0 {
+				dup.Files[0], dup.Files[idx] = dup.Files[idx], dup.Files[0]
+				changed++
+			}
+		}
+	}
+	for i := range tv {
+		dup := &tv[i]
+		switch r.groupPolicy(len(dup.Files), func(j int) string { return dup.Files[j].Path }) {
+		case KeepAllTag:
+			if !dup.Excluded {
+				dup.Excluded = true
+				changed++
+			}
+		case KeepSmallestTag:
+			idx := smallestIndex(len(dup.Files), func(j int) (int64, bool) { return dup.Files[j].Size, dup.Files[j].IsEmpty })
+			if idx > 0 {
+				dup.Files[0], dup.Files[idx] = dup.Files[idx], dup.Files[0]
+				changed++
+			}
+		}
+	}
+	return changed
+}
+
+// groupPolicy returns the policy of a duplicate group: keep-all when any
+// copy is tagged it, else keep-smallest when any copy is tagged that
+func (r *Rules) groupPolicy(n int, path func(int) string) string {
+	policy := ""
+	for j := 0; j < n; j++ {
+		switch r.Policy(path(j)) {
+		case KeepAllTag:
+			return KeepAllTag
+		case KeepSmallestTag:
+			policy = KeepSmallestTag
+		}
+	}
+	return policy
+}
+
+// smallestIndex returns the index of the smallest non-empty file, the
+// first on ties, or 0 when every file is empty
+func smallestIndex(n int, file func(int) (size int64, empty bool)) int {
+	best := -1
+	var bestSize int64
+	for j := 0; j < n; j++ {
+		size, empty := file(j)
+		if empty {
+			continue
+		}
+		if best < 0 || size < bestSize {
+			best, bestSize = j, size
+		}
+	}
+	if best < 0 {
+		return 0
+	}
+	return best
+}
+
+// keeperIndex returns the index of the first keep-tagged file, or 0 when
+// the current keeper is tagged or no file is
+func (r *Rules) keeperIndex(n int, path func(int) string) int {
+	for j := 0; j < n; j++ {
+		if r.KeepTag(path(j)) != "" {
+			return j
+		}
+	}
+	return 0
+}
+
+// Current rules used by cleans and report loading
+var (
+	currentRules   *Rules
+	currentRulesMu sync.RWMutex
+)
+
+// SetRules installs the rules returned by CurrentRules (nil disables tags)
+// and has scanner.FrozenBy honor their FreezeTag
+func SetRules(r *Rules) {
+	currentRulesMu.Lock()
+	defer currentRulesMu.Unlock()
+	currentRules = r
+	if r == nil {
+		scanner.SetFreezeTagged(nil)
+	} else {
+		scanner.SetFreezeTagged(r.Frozen)
+	}
+}
+
+// CurrentRules returns the installed rules, or nil
+func CurrentRules() *Rules {
+	currentRulesMu.RLock()
+	defer currentRulesMu.RUnlock()
+	return currentRules
+}
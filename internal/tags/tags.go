@@ -0,0 +1,182 @@
+// Package tags stores user-assigned tags on media files and folders
+package tags
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
+)
+
+// Store is a file-backed set of tags per path. Tags on a folder apply to
+// everything inside it. A nil *Store has no tags
+type Store struct {
+	path string
+
+	mu   sync.RWMutex
+	tags map[string][]string // cleaned path -> sorted tags
+}
+
+// DefaultPath returns the tag file next to the operation log
+func DefaultPath() string {
+	return filepath.Join(oplog.DataDir(), "tags.json")
+}
+
+// Normalize lowercases tag and checks it is made of a-z, 0-9, - and _
+func Normalize(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", fmt.Errorf("tag is empty")
+	}
+	for _, r := range tag {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return "", fmt.Errorf("invalid tag: %q (use letters, digits, - and _)", tag)
+		}
+	}
+	return tag, nil
+}
+
+// Load reads the tag file at path; a missing file is an empty store
+func Load(path string) (*Store, error) {
+	s := &Store{path: path, tags: make(map[string][]string)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to read tags: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.tags); err != nil {
+		return s, fmt.Errorf("failed to parse tags: %w", err)
+	}
+	if s.tags == nil {
+		s.tags = make(map[string][]string)
+	}
+	return s, nil
+}
+
+// Save writes the store back to its file atomically
+func (s *Store) Save() error {
+	if s == nil {
+		return fmt.Errorf("no tag store loaded")
+	}
+	s.mu.RLock()
+	data, err := json.MarshalIndent(s.tags, "", "  ")
+	s.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create tag directory: %w", err)
+	}
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write tags: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace tags: %w", err)
+	}
+	return nil
+}
+
+// Add tags path with tag
+func (s *Store) Add(path, tag string) error {
+	if s == nil {
+		return fmt.Errorf("no tag store loaded")
+	}
+	tag, err := Normalize(tag)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path = filepath.Clean(path)
+	for _, t := range s.tags[path] {
+		if t == tag {
+			return nil
+		}
+	}
+	s.tags[path] = append(s.tags[path], tag)
+	sort.Strings(s.tags[path])
+	return nil
+}
+
+// Remove drops tag from path, reporting whether it was set
+func (s *Store) Remove(path, tag string) bool {
+	if s == nil {
+		return false
+	}
+	tag = strings.ToLower(strings.TrimSpace(tag))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path = filepath.Clean(path)
+	current := s.tags[path]
+	for i, t := range current {
+		if t == tag {
+			current = append(current[:i:i], current[i+1:]...)
+			if len(current) == 0 {
+				delete(s.tags, path)
+			} else {
+				s.tags[path] = current
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Tags returns the tags set directly on path
+func (s *Store) Tags(path string) []string {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string(nil), s.tags[filepath.Clean(path)]...)
+}
+
+// Effective returns the tags on path and on every folder above it, sorted
+// and without repeats
+func (s *Store) Effective(path string) []string {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var result []string
+	for p := filepath.Clean(path); ; p = filepath.Dir(p) {
+		for _, t := range s.tags[p] {
+			if !seen[t] {
+				seen[t] = true
+				result = append(result, t)
+			}
+		}
+		if parent := filepath.Dir(p); parent == p {
+			break
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Has reports whether path or one of its folders carries tag
+func (s *Store) Has(path, tag string) bool {
+	for _, t := range s.Effective(path) {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package tags
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestStoreAddRemoveAndInherit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tags.json")
+	store, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load of a missing file failed: %v", err)
+	}
+
+	if err := store.Add("/tv/Firefly", "Never-Touch"); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := store.Add("/tv/Firefly/Season 01/Firefly S01E01.mkv", "keep-4k"); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := store.Add("/tv/Firefly", "bad tag"); err == nil {
+		t.Error("Expected an error for a tag with a space")
+	}
+
+	got := store.Effective("/tv/Firefly/Season 01/Firefly S01E01.mkv")
+	if want := []string{"keep-4k", "never-touch"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Effective = %v, want %v", got, want)
+	}
+	if store.Has("/tv/Fireflyer/x.mkv", "never-touch") {
+		t.Error("Folder tag leaked to a sibling with a common prefix")
+	}
+
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := reloaded.Tags("/tv/Firefly/"); !reflect.DeepEqual(got, []string{"never-touch"}) {
+		t.Errorf("Reloaded tags = %v", got)
+	}
+
+	if !reloaded.Remove("/tv/Firefly", "never-touch") || reloaded.Remove("/tv/Firefly", "never-touch") {
+		t.Error("Expected Remove to report the tag once")
+	}
+	if reloaded.Has("/tv/Firefly/Season 01/Firefly S01E01.mkv", "never-touch") {
+		t.Error("Removed folder tag still applies")
+	}
+
+	var none *Store
+	if none.Has("/x", "keep") || none.Effective("/x") != nil {
+		t.Error("Expected a nil store to have no tags")
+	}
+}
+
+func TestRules(t *testing.T) {
+	store, _ := Load(filepath.Join(t.TempDir(), "tags.json"))
+	store.Add("/movies/Heat (1995)/Heat 1080p.mkv", "keep-4k")
+	store.Add("/movies/Alien (1979)", "never-touch")
+
+	rules := &Rules{Store: store, Keep: []string{"keep", "keep-4k"}, Protected: []string{"never-touch"}}
+	if got := rules.KeepTag("/movies/Heat (1995)/Heat 1080p.mkv"); got != "keep-4k" {
+		t.Errorf("KeepTag = %q, want keep-4k", got)
+	}
+	if got := rules.ProtectingTag("/movies/Alien (1979)/Alien.mkv"); got != "never-touch" {
+		t.Errorf("ProtectingTag = %q, want never-touch", got)
+	}
+
+	movies := []scanner.MovieDuplicate{
+		{Files: []scanner.MovieFile{{Path: "/movies/Heat (1995)/Heat 2160p.mkv"}, {Path: "/movies/Heat (1995)/Heat 1080p.mkv"}}},
+		{Files: []scanner.MovieFile{{Path: "/movies/Alien (1979)/Alien.mkv"}, {Path: "/movies/alien.mkv"}}},
+	}
+	if changed := rules.PreferKeepers(movies, nil); changed != 1 {
+		t.Errorf("Expected 1 group changed, got %d", changed)
+	}
+	if movies[0].Files[0].Path != "/movies/Heat (1995)/Heat 1080p.mkv" {
+		t.Errorf("Expected the keep-tagged file as keeper, got %s", movies[0].Files[0].Path)
+	}
+	if movies[1].Files[0].Path != "/movies/Alien (1979)/Alien.mkv" {
+		t.Error("Group without keep tags was reordered")
+	}
+
+	var none *Rules
+	if none.KeepTag("/x") != "" || none.ProtectingTag("/x") != "" || none.PreferKeepers(movies, nil) != 0 {
+		t.Error("Expected nil rules to match nothing")
+	}
+}
+
+func TestApplyPolicies(t *testing.T) {
+	store, _ := Load(filepath.Join(t.TempDir(), "tags.json"))
+	store.Add("/movies/Heat (1995)", KeepSmallestTag)
+	store.Add("/movies/Alien (1979)", KeepAllTag)
+	store.Add("/tv/Firefly", KeepAllTag)
+	store.Add("/tv/Firefly/Season 01/Firefly S01E02.mkv", KeepSmallestTag)
+	store.Add("/tv/Lost", KeepSmallestTag)
+	rules := &Rules{Store: store}
+
+	if got := rules.Policy("/tv/Firefly/Season 01/Firefly S01E02.mkv"); got != KeepAllTag {
+		t.Errorf("Policy = %q, want keep-all to win", got)
+	}
+
+	movies := []scanner.MovieDuplicate{
+		{Files: []scanner.MovieFile{
+			{Path: "/movies/Heat (1995)/Heat 2160p.mkv", Size: 4000},
+			{Path: "/movies/Heat (1995)/Heat empty.mkv", IsEmpty: true},
+			{Path: "/movies/Heat (1995)/Heat 720p.mkv", Size: 1000},
+		}},
+		{Files: []scanner.MovieFile{{Path: "/movies/Alien (1979)/Alien.mkv"}, {Path: "/movies/alien.mkv"}}},
+		{Files: []scanner.MovieFile{{Path: "/movies/Ran (1985)/Ran.mkv"}, {Path: "/movies/ran.mkv"}}, Strategy: scanner.StrategyDelete},
+	}
+	tv := []scanner.TVDuplicate{
+		{Files: []scanner.TVFile{{Path: "/tv/Firefly/Season 01/Firefly S01E01.mkv"}, {Path: "/tv/firefly.s01e01.mkv"}}},
+		{Files: []scanner.TVFile{{Path: "/tv/Lost/Season 01/Lost S01E01.mkv", Size: 500}, {Path: "/tv/Lost/Season 01/lost.s01e01.mkv", Size: 300}}},
+	}
+	if changed := rules.ApplyPolicies(movies, tv); changed != 4 {
+		t.Errorf("Expected 4 groups changed, got %d", changed)
+	}
+	if movies[0].Files[0].Path != "/movies/Heat (1995)/Heat 720p.mkv" {
+		t.Errorf("Expected the smallest non-empty copy as keeper, got %s", movies[0].Files[0].Path)
+	}
+	if !movies[1].KeepsAllVersions() {
+		t.Error("Expected the keep-all movie kept as versions")
+	}
+	if movies[2].Strategy != scanner.StrategyDelete {
+		t.Error("Expected a group's own strategy to be left alone")
+	}
+	if !tv[0].Excluded {
+		t.Error("Expected the keep-all show's group left out of cleans")
+	}
+	if tv[1].Files[0].Path != "/tv/Lost/Season 01/lost.s01e01.mkv" {
+		t.Errorf("Expected the smallest episode as keeper, got %s", tv[1].Files[0].Path)
+	}
+
+	var none *Rules
+	if none.Policy("/x") != "" || none.ApplyPolicies(movies, tv) != 0 {
+		t.Error("Expected nil rules to match nothing")
+	}
+}
@@ -1,6 +1,10 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // ASCII art for jellysink header as single string to preserve exact formatting
 const jellysinkASCII = `  ████              ████   ████                            ████                ████
@@ -44,3 +48,68 @@ func FormatASCIIHeaderWithSubtext(subtext string) string {
 
 	return header + "\n\n" + subtitle
 }
+
+const (
+	// BannerMinWidth is the narrowest screen the ASCII banner is drawn on;
+	// narrower screens get a one-line title instead
+	BannerMinWidth = 100
+	// MinScreenWidth is the narrowest screen the TUI draws
+	MinScreenWidth = 60
+	// bannerLines is the height of the ASCII banner
+	bannerLines = 9
+	// bannerScreenHeight is the shortest screen laid out under the full banner
+	bannerScreenHeight = 25
+)
+
+var (
+	showBanner   = true
+	showBannerMu sync.RWMutex
+)
+
+// SetShowBanner turns the ASCII banner on or off (ui.show_banner); off,
+// every screen starts with the one-line title
+func SetShowBanner(show bool) {
+	showBannerMu.Lock()
+	defer showBannerMu.Unlock()
+	showBanner = show
+}
+
+// bannerShown reports whether a screen width columns wide gets the ASCII
+// banner. 0 is a width not known yet
+func bannerShown(width int) bool {
+	showBannerMu.RLock()
+	defer showBannerMu.RUnlock()
+	return showBanner && (width == 0 || width >= BannerMinWidth)
+}
+
+// Banner renders the header of a screen width columns wide: the ASCII
+// banner, or a one-line title on narrow screens or with ui.show_banner off
+func Banner(width int) string {
+	if bannerShown(width) {
+		return FormatASCIIHeader()
+	}
+	return lipgloss.NewStyle().
+		Foreground(RAMARed).
+		Bold(true).
+		Render("▌ JELLYSINK")
+}
+
+// BannerHeight returns the lines Banner(width) takes
+func BannerHeight(width int) int {
+	if bannerShown(width) {
+		return bannerLines
+	}
+	return 1
+}
+
+// bannerSaved returns the lines a collapsed banner frees on a screen width
+// columns wide, for layouts sized around the full banner
+func bannerSaved(width int) int {
+	return bannerLines - BannerHeight(width)
+}
+
+// minScreenHeight returns the shortest screen width columns wide the TUI
+// draws
+func minScreenHeight(width int) int {
+	return bannerScreenHeight - bannerSaved(width)
+}
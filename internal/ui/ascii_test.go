@@ -0,0 +1,46 @@
+package ui_test
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/ui"
+)
+
+func TestBannerCollapses(t *testing.T) {
+	t.Cleanup(func() { ui.SetShowBanner(true) })
+
+	if got := ui.BannerHeight(ui.BannerMinWidth); got != 9 || !strings.Contains(ui.Banner(ui.BannerMinWidth), "████") {
+		t.Errorf("Expected the ASCII banner at %d columns, got %d lines", ui.BannerMinWidth, got)
+	}
+	narrow := ui.Banner(ui.BannerMinWidth - 1)
+	if ui.BannerHeight(ui.BannerMinWidth-1) != 1 || strings.Contains(narrow, "\n") || !strings.Contains(narrow, "JELLYSINK") {
+		t.Errorf("Expected a one-line title below %d columns, got %q", ui.BannerMinWidth, narrow)
+	}
+
+	ui.SetShowBanner(false)
+	if wide := ui.Banner(200); strings.Contains(wide, "████") || ui.BannerHeight(200) != 1 {
+		t.Errorf("Expected the one-line title with show_banner off, got %q", wide)
+	}
+}
+
+func TestMenuFitsNarrowTerminal(t *testing.T) {
+	view := func(width, height int) string {
+		ret, _ := ui.NewMenuModel(config.DefaultConfig()).Update(tea.WindowSizeMsg{Width: width, Height: height})
+		return ret.View()
+	}
+
+	// 80x24 was too small for the banner; the one-line title fits
+	if out := view(80, 24); strings.Contains(out, "Terminal too small") || strings.Contains(out, "████") {
+		t.Errorf("Expected the menu with a one-line title at 80x24:\n%s", out)
+	}
+	if out := view(120, 30); !strings.Contains(out, "████") {
+		t.Error("Expected the ASCII banner at 120x30")
+	}
+	if out := view(ui.MinScreenWidth-1, 40); !strings.Contains(out, "Terminal too small") {
+		t.Errorf("Expected a resize warning below %d columns", ui.MinScreenWidth)
+	}
+}
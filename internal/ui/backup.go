@@ -115,7 +115,7 @@ func (m BackupMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.error = ""
 				return m, nil
 			}
-			return NewMenuModel(m.config), nil
+			return m, Pop()
 
 		case "enter":
 			if m.mode == "menu" {
@@ -127,7 +127,7 @@ func (m BackupMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		listHeight := msg.Height - 16
+		listHeight := msg.Height - 16 + bannerSaved(msg.Width)
 		if listHeight < 8 {
 			listHeight = 8
 		}
@@ -226,7 +226,7 @@ func (m BackupMenuModel) handleSelection(title string) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "Back to Main Menu":
-		return NewMenuModel(m.config), nil
+		return m, Pop()
 	}
 
 	return m, nil
@@ -236,10 +236,10 @@ func (m BackupMenuModel) createBackup() tea.Msg {
 	m.creating = true
 
 	var paths []string
-	for _, path := range m.config.Libraries.Movies.Paths {
+	for _, path := range m.config.MoviePaths() {
 		paths = append(paths, path)
 	}
-	for _, path := range m.config.Libraries.TV.Paths {
+	for _, path := range m.config.ShowPaths() {
 		paths = append(paths, path)
 	}
 
@@ -288,7 +288,7 @@ func (m BackupMenuModel) View() string {
 
 	var content strings.Builder
 
-	content.WriteString(FormatASCIIHeader())
+	content.WriteString(Banner(m.width))
 	content.WriteString("\n\n")
 
 	switch m.mode {
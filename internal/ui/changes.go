@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// loadPrevious reads the report this scan was compared with, once
+func (m *Model) loadPrevious() {
+	if m.previous != nil || m.previousErr != nil || m.report.Changes == nil {
+		return
+	}
+	previous, err := reporter.ReadReport(m.report.Changes.Previous)
+	if err != nil {
+		m.previousErr = err
+		return
+	}
+	m.previous = &previous
+}
+
+// renderChanges renders what is new, resolved and still pending since the
+// previous scan of the same libraries
+func (m Model) renderChanges() string {
+	var sb strings.Builder
+
+	sb.WriteString(TitleStyle.Render("CHANGES SINCE THE PREVIOUS SCAN") + "\n\n")
+
+	changes := m.report.Changes
+	if changes == nil {
+		return sb.String() + MutedStyle.Render("This report was not compared with a previous scan.") + "\n"
+	}
+	sb.WriteString(ContentStyle.Render(changes.Headline()) + "\n")
+	sb.WriteString(MutedStyle.Render("Compared with: ") + ContentStyle.Render(changes.Previous) + "\n\n")
+
+	if m.previousErr != nil {
+		sb.WriteString(ErrorStyle.Render(fmt.Sprintf("Cannot list the changes: %v", m.previousErr)) + "\n")
+		return sb.String()
+	}
+	if placeholder, pending := m.detailsPlaceholder(); pending {
+		return sb.String() + placeholder + "\n"
+	}
+	if m.previous == nil {
+		return sb.String()
+	}
+
+	diff := reporter.DiffReports(*m.previous, m.report)
+	sb.WriteString(InfoStyle.Render("Reclaimable space: ") +
+		StatStyle.Render(formatBytes(diff.SpaceToFreeBefore)) + " → " + StatStyle.Render(formatBytes(diff.SpaceToFreeAfter)) + "\n")
+	if diff.SpaceFreed > 0 {
+		sb.WriteString(InfoStyle.Render("Freed by cleaning the previous report: ") + StatStyle.Render(formatBytes(diff.SpaceFreed)) + "\n")
+	}
+	sb.WriteString("\n")
+
+	renderDiffFindings(&sb, "NEW", WarningStyle, diff.Added)
+	renderDiffFindings(&sb, "RESOLVED", SuccessStyle, diff.Resolved)
+	renderDiffFindings(&sb, "STILL PENDING", MutedStyle, diff.Pending)
+	if len(diff.Added)+len(diff.Resolved)+len(diff.Pending) == 0 {
+		sb.WriteString(MutedStyle.Render("No findings in either scan.") + "\n")
+	}
+
+	return sb.String()
+}
+
+// renderDiffFindings lists one section of a report diff
+func renderDiffFindings(sb *strings.Builder, heading string, style lipgloss.Style, findings []reporter.DiffFinding) {
+	if len(findings) == 0 {
+		return
+	}
+	sb.WriteString(TitleStyle.Render(fmt.Sprintf("%s (%d)", heading, len(findings))) + "\n")
+	for _, finding := range findings {
+		line := fmt.Sprintf("  %s %s", style.Render("["+finding.Kind+"]"), ContentStyle.Render(finding.Title))
+		if finding.Size > 0 {
+			line += " " + StatStyle.Render(formatBytes(finding.Size))
+		}
+		sb.WriteString(line + "\n")
+		if finding.Path != "" {
+			sb.WriteString("     " + MutedStyle.Render(finding.Path) + "\n")
+		}
+	}
+	sb.WriteString("\n")
+}
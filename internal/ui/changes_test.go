@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestChangesView(t *testing.T) {
+	previous := reporter.Report{
+		Timestamp: time.Date(2026, 3, 1, 4, 0, 0, 0, time.UTC),
+		ComplianceIssues: []scanner.ComplianceIssue{
+			{Path: "/tv/Firefly/Season 1", Problem: "Season folder not padded", SuggestedAction: "rename"},
+		},
+	}
+	previousPath := filepath.Join(t.TempDir(), "previous.json")
+	if err := reporter.WriteReport(previousPath, previous); err != nil {
+		t.Fatal(err)
+	}
+
+	report := reporter.Report{
+		Timestamp: time.Date(2026, 3, 8, 4, 0, 0, 0, time.UTC),
+		MovieDuplicates: []scanner.MovieDuplicate{{
+			NormalizedName: "alien",
+			Year:           "1979",
+			Files: []scanner.MovieFile{
+				{Path: "/movies/Alien (1979)/Alien (1979).mkv", Size: 3000},
+				{Path: "/movies/alien.1979.mkv", Size: 1500},
+			},
+		}},
+	}
+	report.RecountTotals()
+	diff := reporter.DiffReports(previous, report)
+	changes := diff.Changes()
+	changes.Previous = previousPath
+	report.Changes = &changes
+
+	m := NewModel(report)
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 60})
+	if summary := model.(Model).renderSummary(); !strings.Contains(summary, "1 new duplicates, 0 resolved; 0 new naming issues, 1 fixed") {
+		t.Errorf("Expected the summary to show the changes headline:\n%s", summary)
+	}
+	if footer := model.View(); !strings.Contains(footer, "F6") {
+		t.Error("Expected F6 in the summary footer")
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyF6})
+	if got := model.(Model); got.mode != ViewChanges {
+		t.Fatalf("Expected F6 to open the changes view, mode = %v", got.mode)
+	}
+	view := model.(Model).renderChanges()
+	for _, want := range []string{"NEW (1)", "alien (1979)", "RESOLVED (1)", "Season folder not padded"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Changes view missing %q:\n%s", want, view)
+		}
+	}
+
+	// A previous report that has since been removed is reported, not fatal
+	report.Changes.Previous = filepath.Join(t.TempDir(), "gone.json")
+	model, _ = NewModel(report).Update(tea.WindowSizeMsg{Width: 160, Height: 60})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyF6})
+	if view := model.(Model).renderChanges(); !strings.Contains(view, "Cannot list the changes") {
+		t.Errorf("Expected a missing previous report to be reported:\n%s", view)
+	}
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// conflictImpact is how much of the library a title conflict holds up
+type conflictImpact struct {
+	Files   int // episode files using the show's title
+	Seasons int // season folders those files are in
+	Issues  int // other compliance issues under the show folder
+}
+
+// impactOf measures conflict against the report's compliance issues. The
+// conflict's own title mismatch issues are not counted as pending
+func impactOf(conflict *scanner.TVTitleResolution, issues []scanner.ComplianceIssue) conflictImpact {
+	impact := conflictImpact{Files: len(conflict.AffectedFiles)}
+
+	seasons := make(map[string]bool)
+	for _, path := range conflict.AffectedFiles {
+		seasons[filepath.Dir(path)] = true
+	}
+	impact.Seasons = len(seasons)
+
+	if conflict.FolderPath != "" {
+		prefix := conflict.FolderPath + string(filepath.Separator)
+		for _, issue := range issues {
+			if issue.Rule != scanner.RuleTVTitleMismatch && strings.HasPrefix(issue.Path, prefix) {
+				impact.Issues++
+			}
+		}
+	}
+	return impact
+}
+
+// String describes the impact, e.g. "affects 127 files across 6 seasons"
+func (i conflictImpact) String() string {
+	s := fmt.Sprintf("affects %d %s across %d %s", i.Files, plural(i.Files, "file"), i.Seasons, plural(i.Seasons, "season"))
+	if i.Issues > 0 {
+		s += fmt.Sprintf(", %d pending compliance %s", i.Issues, plural(i.Issues, "issue"))
+	}
+	return s
+}
+
+// plural adds an "s" to word unless n is 1
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+// sortConflictsByImpact puts the decisions that unblock the most first:
+// shows with pending compliance issues, then by files and seasons affected.
+// Equal conflicts keep the report's order
+func sortConflictsByImpact(conflicts []*scanner.TVTitleResolution, issues []scanner.ComplianceIssue) {
+	impacts := make(map[*scanner.TVTitleResolution]conflictImpact, len(conflicts))
+	for _, c := range conflicts {
+		impacts[c] = impactOf(c, issues)
+	}
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		a, b := impacts[conflicts[i]], impacts[conflicts[j]]
+		if (a.Issues > 0) != (b.Issues > 0) {
+			return a.Issues > 0
+		}
+		if a.Files != b.Files {
+			return a.Files > b.Files
+		}
+		return a.Seasons > b.Seasons
+	})
+}
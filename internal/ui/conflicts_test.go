@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestConflictReviewOrdersByImpact(t *testing.T) {
+	conflict := func(folder string, files ...string) *scanner.TVTitleResolution {
+		return &scanner.TVTitleResolution{
+			ResolvedTitle: folder,
+			FolderMatch:   &scanner.TVTitleMatch{Title: folder},
+			FilenameMatch: &scanner.TVTitleMatch{Title: folder + " Extended"},
+			IsAmbiguous:   true,
+			FolderPath:    "/tv/" + folder,
+			AffectedFiles: files,
+		}
+	}
+	report := reporter.Report{
+		AmbiguousTVShows: []*scanner.TVTitleResolution{
+			conflict("Alpha", "/tv/Alpha/Season 01/a.mkv"),
+			conflict("Bravo", "/tv/Bravo/Season 01/b1.mkv", "/tv/Bravo/Season 01/b2.mkv", "/tv/Bravo/Season 02/b3.mkv"),
+			conflict("Charlie", "/tv/Charlie/Season 01/c.mkv"),
+		},
+		ComplianceIssues: []scanner.ComplianceIssue{
+			{Path: "/tv/Alpha/Season 01/a.mkv", Rule: scanner.RuleTVTitleMismatch},
+			{Path: "/tv/Charlie/Season 01/c.mkv", Rule: scanner.RuleTVTitleMismatch},
+			{Path: "/tv/Charlie/Season 01/c.mkv", Rule: scanner.RuleTVSeasonFolder},
+		},
+	}
+
+	m := NewModel(report)
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyF3})
+	m = model.(Model)
+
+	var order []string
+	for _, c := range m.conflicts {
+		order = append(order, c.FolderMatch.Title)
+	}
+	if strings.Join(order, ",") != "Charlie,Bravo,Alpha" {
+		t.Errorf("Expected pending issues, then files affected, to order the queue, got %v", order)
+	}
+	if !strings.Contains(m.renderConflictReview(), "affects 1 file across 1 season, 1 pending compliance issue") {
+		t.Error("Expected the first conflict's impact in the review")
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if view := model.(Model).renderConflictReview(); !strings.Contains(view, "affects 3 files across 2 seasons") {
+		t.Errorf("Expected the second conflict's impact in the review, got:\n%s", view)
+	}
+}
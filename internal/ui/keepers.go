@@ -0,0 +1,226 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+	"github.com/Nomadcxx/jellysink/internal/tags"
+)
+
+// duplicateCursor locates the tag cursor in the duplicates view: the movie
+// or TV group holding it and the file's index in that group. group is -1
+// when the cursor is unset
+type duplicateCursor struct {
+	tv    bool
+	group int
+	file  int
+	start int // tag index of the group's first file
+}
+
+// duplicateAtCursor returns the group and file under the tag cursor
+func (m Model) duplicateAtCursor() duplicateCursor {
+	if m.mode != ViewDuplicates || m.tagCursor < 0 {
+		return duplicateCursor{group: -1}
+	}
+	index := 0
+	for i, dup := range m.report.MovieDuplicates {
+		if m.tagCursor < index+len(dup.Files) {
+			return duplicateCursor{group: i, file: m.tagCursor - index, start: index}
+		}
+		index += len(dup.Files)
+	}
+	for i, dup := range m.report.TVDuplicates {
+		if m.tagCursor < index+len(dup.Files) {
+			return duplicateCursor{tv: true, group: i, file: m.tagCursor - index, start: index}
+		}
+		index += len(dup.Files)
+	}
+	return duplicateCursor{group: -1}
+}
+
+// groupStarts lists the tag index of each duplicate group's first file
+func (m Model) groupStarts() []int {
+	var starts []int
+	index := 0
+	for _, dup := range m.report.MovieDuplicates {
+		starts = append(starts, index)
+		index += len(dup.Files)
+	}
+	for _, dup := range m.report.TVDuplicates {
+		starts = append(starts, index)
+		index += len(dup.Files)
+	}
+	return starts
+}
+
+// moveGroupCursor steps the tag cursor to the first file of the next or
+// previous duplicate group, wrapping at either end
+func (m *Model) moveGroupCursor(delta int) {
+	starts := m.groupStarts()
+	if len(starts) == 0 {
+		return
+	}
+	current := -1
+	if m.tagCursor >= 0 {
+		for i, start := range starts {
+			if start <= m.tagCursor {
+				current = i
+			}
+		}
+	}
+	switch {
+	case current < 0:
+		current = 0
+	case delta < 0 && m.tagCursor > starts[current]:
+		// Back to the top of the group the cursor is in
+	default:
+		current = (current + delta + len(starts)) % len(starts)
+	}
+	m.tagCursor = starts[current]
+	m.tagStatus = ""
+	m.refreshTagView()
+}
+
+// chooseKeeper makes the file under the cursor its group's keeper; the
+// rest of the group is deleted (or renamed around it, for versions)
+func (m *Model) chooseKeeper() {
+	at := m.duplicateAtCursor()
+	if at.group < 0 {
+		m.tagStatus = WarningStyle.Render("Select the file to keep first (Tab)")
+		m.refreshTagView()
+		return
+	}
+	if at.file == 0 {
+		m.tagStatus = MutedStyle.Render("This file is already the keeper")
+		m.refreshTagView()
+		return
+	}
+
+	var keeper, replaced string
+	var excluded bool
+	if at.tv {
+		files := m.report.TVDuplicates[at.group].Files
+		files[0], files[at.file] = files[at.file], files[0]
+		keeper, replaced = files[0].Path, files[at.file].Path
+		excluded = m.report.TVDuplicates[at.group].Excluded
+	} else {
+		files := m.report.MovieDuplicates[at.group].Files
+		files[0], files[at.file] = files[at.file], files[0]
+		keeper, replaced = files[0].Path, files[at.file].Path
+		excluded = m.report.MovieDuplicates[at.group].Excluded
+	}
+	m.tagCursor = at.start
+	m.report.RecountTotals()
+
+	status := SuccessStyle.Render(fmt.Sprintf("✓ Keeping %s", filepath.Base(keeper)))
+	if tag := tags.CurrentRules().KeepTag(replaced); tag != "" && !excluded {
+		status = WarningStyle.Render(fmt.Sprintf("Keeping %s, but %s is tagged %q and the clean will not delete it", filepath.Base(keeper), filepath.Base(replaced), tag))
+	}
+	m.tagStatus = m.saveDecisions(status)
+	m.refreshTagView()
+}
+
+// toggleExcluded leaves the group under the cursor out of cleans, or puts
+// it back in
+func (m *Model) toggleExcluded() {
+	if m.tagCursor < 0 && len(m.taggablePaths()) > 0 {
+		m.tagCursor = 0
+	}
+	at := m.duplicateAtCursor()
+	if at.group < 0 {
+		return
+	}
+
+	var excluded *bool
+	var name string
+	if at.tv {
+		dup := &m.report.TVDuplicates[at.group]
+		excluded, name = &dup.Excluded, dup.ShowName+" "+dup.EpisodeLabel()
+	} else {
+		dup := &m.report.MovieDuplicates[at.group]
+		excluded, name = &dup.Excluded, filepath.Base(filepath.Dir(dup.Files[0].Path))
+	}
+	*excluded = !*excluded
+	m.report.RecountTotals()
+
+	status := SuccessStyle.Render(fmt.Sprintf("✓ %s: back in the clean", name))
+	if *excluded {
+		status = SuccessStyle.Render(fmt.Sprintf("✓ %s: excluded from cleaning, every file is kept", name))
+	}
+	m.tagStatus = m.saveDecisions(status)
+	m.refreshTagView()
+}
+
+// toggleCompanions switches whether cleaning the group under the cursor
+// moves the subtitles and artwork of its deleted copies to the keeper
+func (m *Model) toggleCompanions() {
+	if m.tagCursor < 0 && len(m.taggablePaths()) > 0 {
+		m.tagCursor = 0
+	}
+	at := m.duplicateAtCursor()
+	if at.group < 0 {
+		return
+	}
+
+	var merge bool
+	var name string
+	var paths []string
+	if at.tv {
+		dup := &m.report.TVDuplicates[at.group]
+		merge = !dup.MergesCompanions()
+		dup.MergeCompanions = &merge
+		name = dup.ShowName + " " + dup.EpisodeLabel()
+		for _, file := range dup.Files {
+			paths = append(paths, file.Path)
+		}
+	} else {
+		dup := &m.report.MovieDuplicates[at.group]
+		merge = !dup.MergesCompanions()
+		dup.MergeCompanions = &merge
+		name = filepath.Base(filepath.Dir(dup.Files[0].Path))
+		for _, file := range dup.Files {
+			paths = append(paths, file.Path)
+		}
+	}
+
+	status := SuccessStyle.Render(fmt.Sprintf("✓ %s: subtitles and artwork stay in the deleted copies' folders", name))
+	if merge {
+		moves := 0
+		for _, deleted := range paths[1:] {
+			moves += len(scanner.CompanionMerges(deleted, paths[0]))
+		}
+		status = SuccessStyle.Render(fmt.Sprintf("✓ %s: %d subtitle and artwork files move to the keeper", name, moves))
+	}
+	m.tagStatus = m.saveDecisions(status)
+	m.refreshTagView()
+}
+
+// renderCompanionMerges lists beneath a deleted copy the sidecars the
+// keeper lacks: moved to the keeper, or left behind when the group doesn't
+// merge them
+func renderCompanionMerges(deleted, keeper string, merge bool) string {
+	var sb strings.Builder
+	for _, move := range scanner.CompanionMerges(deleted, keeper) {
+		if merge {
+			sb.WriteString(MutedStyle.Render("           + "+filepath.Base(move.Source)+" -> keeper as "+filepath.Base(move.Target)) + "\n")
+		} else {
+			sb.WriteString(WarningStyle.Render("           - "+filepath.Base(move.Source)+" stays behind (C moves it to the keeper)") + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// saveDecisions writes the duplicate decisions into the report file so
+// `jellysink clean` honors them, returning status or the failure to save
+func (m Model) saveDecisions(status string) string {
+	if m.reportPath == "" {
+		return status
+	}
+	if err := reporter.SaveDuplicateDecisions(m.reportPath, m.report); err != nil {
+		return ErrorStyle.Render(fmt.Sprintf("Failed to save the choice to the report: %v", err))
+	}
+	return status
+}
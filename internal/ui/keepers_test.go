@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestDuplicateDecisionsSavedToReport(t *testing.T) {
+	report := reporter.Report{
+		MovieDuplicates: []scanner.MovieDuplicate{{
+			NormalizedName: "heat",
+			Year:           "1995",
+			Files: []scanner.MovieFile{
+				{Path: "/movies/Heat (1995)/Heat (1995) - 2160p.mkv", Size: 4000, Resolution: "2160p"},
+				{Path: "/movies/Heat (1995)/Heat (1995) - 1080p.mkv", Size: 2000, Resolution: "1080p"},
+				{Path: "/movies/Heat (1995)/Heat (1995) - 720p.mkv", Size: 1000, Resolution: "720p"},
+			},
+		}},
+		TVDuplicates: []scanner.TVDuplicate{{
+			ShowName: "firefly",
+			Season:   1,
+			Episode:  1,
+			Files: []scanner.TVFile{
+				{Path: "/tv/Firefly/Season 01/Firefly S01E01.mkv", Size: 500},
+				{Path: "/tv/Firefly/Season 01/firefly.s01e01.mkv", Size: 300},
+			},
+		}},
+	}
+	report.RecountTotals()
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+	if err := reporter.WriteReport(reportPath, report); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(report)
+	m.SetReportPath(reportPath)
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	key := func(k tea.KeyMsg) { model, _ = model.Update(k) }
+	key(tea.KeyMsg{Type: tea.KeyF1})
+
+	// Right selects the movie group; Tab moves to its 1080p copy
+	key(tea.KeyMsg{Type: tea.KeyRight})
+	key(tea.KeyMsg{Type: tea.KeyTab})
+	key(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(Model)
+	if keeper := m.report.MovieDuplicates[0].Files[0]; keeper.Resolution != "1080p" {
+		t.Fatalf("Expected Enter to keep the 1080p copy, keeper is %s", keeper.Path)
+	}
+	if m.tagCursor != 0 || m.report.SpaceToFree != 4000+1000+300 {
+		t.Errorf("Expected the cursor on the new keeper and 5300 bytes to free, got cursor %d, %d bytes", m.tagCursor, m.report.SpaceToFree)
+	}
+
+	// Right again reaches the TV group, which E excludes
+	key(tea.KeyMsg{Type: tea.KeyRight})
+	key(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	m = model.(Model)
+	if !m.report.TVDuplicates[0].Excluded || m.report.TotalFilesToDelete != 2 {
+		t.Fatalf("Expected E to exclude the TV group, got %+v, %d files to delete", m.report.TVDuplicates[0], m.report.TotalFilesToDelete)
+	}
+	if view := m.renderDuplicates(); !strings.Contains(view, "EXCLUDED") {
+		t.Errorf("Expected the excluded group marked in the view:\n%s", view)
+	}
+
+	// Both decisions are in the report file for `jellysink clean`
+	saved, err := reporter.ReadReport(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.MovieDuplicates[0].Files[0].Resolution != "1080p" || !saved.TVDuplicates[0].Excluded {
+		t.Errorf("Expected the keeper and the exclusion saved, got %+v and %+v", saved.MovieDuplicates[0].Files[0], saved.TVDuplicates[0])
+	}
+	if saved.TotalFilesToDelete != 2 || saved.SpaceToFree != 5000 {
+		t.Errorf("Expected the saved totals recounted, got %d files, %d bytes", saved.TotalFilesToDelete, saved.SpaceToFree)
+	}
+
+	// Left from the TV group wraps back through the movie group
+	key(tea.KeyMsg{Type: tea.KeyLeft})
+	if at := model.(Model).duplicateAtCursor(); at.tv || at.group != 0 {
+		t.Errorf("Expected Left to select the movie group, got %+v", at)
+	}
+}
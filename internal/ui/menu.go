@@ -2,10 +2,10 @@ package ui
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -17,7 +17,6 @@ import (
 
 	"github.com/Nomadcxx/jellysink/internal/config"
 	"github.com/Nomadcxx/jellysink/internal/daemon"
-	"github.com/Nomadcxx/jellysink/internal/reporter"
 	"github.com/Nomadcxx/jellysink/internal/scanner"
 )
 
@@ -31,6 +30,24 @@ func (i MenuItem) Title() string       { return i.title }
 func (i MenuItem) Description() string { return i.desc }
 func (i MenuItem) FilterValue() string { return i.title }
 
+// demoMode hides menu entries that touch the real system (systemd timers,
+// scan schedule, API keys) while exploring a sandbox library
+var demoMode bool
+
+// demoHiddenItems are menu entries removed in demo mode
+var demoHiddenItems = map[string]bool{
+	"Configure Frequency":    true,
+	"Enable/Disable Daemon":  true,
+	"Configure API Keys":     true,
+	"Undo Last Clean":        true,
+	"Attach to Running Scan": true,
+}
+
+// EnableDemoMode switches the menu into sandbox mode for `jellysink demo`
+func EnableDemoMode() {
+	demoMode = true
+}
+
 // MenuModel represents the main menu TUI
 type MenuModel struct {
 	list       list.Model
@@ -46,12 +63,15 @@ type MenuModel struct {
 func NewMenuModel(cfg *config.Config) MenuModel {
 	items := []list.Item{
 		MenuItem{title: "Run Manual Scan", desc: "Scan your media libraries for duplicates and compliance issues"},
+		MenuItem{title: "Attach to Running Scan", desc: "Watch the live progress of a scheduled scan already in progress"},
 		MenuItem{title: "View Last Report", desc: "View the most recent scan report"},
+		MenuItem{title: "Library Stats", desc: "File counts, sizes and quality breakdown per library"},
 		MenuItem{title: "Manage Backups", desc: "Create, view, and revert library backups"},
-		MenuItem{title: "Configure Frequency", desc: "Set automatic scan frequency (daily/weekly/biweekly)"},
+		MenuItem{title: "Undo Last Clean", desc: "Restore the files changed by the most recent clean"},
+		MenuItem{title: "Configure Frequency", desc: "Set automatic scan frequency (daily/weekly/biweekly/custom)"},
 		MenuItem{title: "Enable/Disable Daemon", desc: "Toggle automatic background scanning"},
 		MenuItem{title: "Configure Libraries", desc: "Add or remove media library paths"},
-		MenuItem{title: "Configure API Keys", desc: "Set TVDB/OMDB API keys for metadata resolution"},
+		MenuItem{title: "Configure API Keys", desc: "Set TVDB/OMDB/TMDB API keys for metadata resolution"},
 		MenuItem{title: "Exit", desc: "Quit jellysink"},
 	}
 
@@ -69,8 +89,20 @@ func NewMenuModel(cfg *config.Config) MenuModel {
 	delegate.Styles.NormalDesc = lipgloss.NewStyle().
 		Foreground(RAMAMuted)
 
+	title := "JELLYSINK MAIN MENU"
+	if demoMode {
+		var visible []list.Item
+		for _, item := range items {
+			if !demoHiddenItems[item.(MenuItem).title] {
+				visible = append(visible, item)
+			}
+		}
+		items = visible
+		title = "JELLYSINK DEMO (SANDBOX LIBRARY)"
+	}
+
 	l := list.New(items, delegate, 80, 20)
-	l.Title = "JELLYSINK MAIN MENU"
+	l.Title = title
 	l.Styles.Title = TitleStyle
 	l.SetShowHelp(false)
 	l.SetFilteringEnabled(false)
@@ -92,6 +124,11 @@ type scanStatusMsg struct {
 	err        error
 }
 
+// attachCheckMsg reports whether there is a running scan to attach to
+type attachCheckMsg struct {
+	running bool
+}
+
 // progressTickMsg is sent periodically to update progress animation
 type progressTickMsg struct{}
 
@@ -130,31 +167,34 @@ func (m MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		// Calculate list height after accounting for other content:
-		// ASCII header (9 lines) + spacing (2) + footer (3) + padding (2) = 16 lines
-		listHeight := msg.Height - 16
+		// Banner (9 lines, 1 collapsed) + spacing (2) + footer (3) + padding (2) = 16 lines
+		listHeight := msg.Height - 16 + bannerSaved(msg.Width)
 		if listHeight < 8 {
 			listHeight = 8
 		}
 		m.list.SetSize(msg.Width-4, listHeight)
 		return m, nil
 
+	case attachCheckMsg:
+		if !msg.running {
+			return m, tea.Printf("No scan is running")
+		}
+		return m, Push(NewAttachModel(m.config))
+
 	case scanStatusMsg:
 		// Scan completed
 		if msg.err != nil {
 			// Show error and return to menu
 			return m, tea.Printf("Scan failed: %v", msg.err)
 		}
-		// Load report JSON and switch to report view
-		report, err := loadReportJSON(msg.reportPath)
-		if err != nil {
-			return m, tea.Printf("Failed to load report: %v", err)
-		}
+		// Read the report summary off the UI goroutine
+		return m, loadReportCmd(msg.reportPath)
 
-		// Create report view model and transfer dimensions
-		reportModel := NewModel(report)
-		return reportModel, func() tea.Msg {
-			return tea.WindowSizeMsg{Width: m.width, Height: m.height}
+	case reportLoadedMsg:
+		if msg.err != nil {
+			return m, tea.Printf("Failed to load report: %v", msg.err)
 		}
+		return m, Push(newReportModel(msg))
 	}
 
 	var cmd tea.Cmd
@@ -166,49 +206,34 @@ func (m MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m MenuModel) handleSelection(title string) (tea.Model, tea.Cmd) {
 	switch title {
 	case "Run Manual Scan":
-		scanningModel := NewScanningModel(m.config)
-		scanningModel.width = m.width
-		scanningModel.height = m.height
-		return scanningModel, scanningModel.Init()
+		return m, Push(NewScanningModel(m.config))
+
+	case "Attach to Running Scan":
+		return m, checkScanRunning
 
 	case "View Last Report":
 		return m, m.viewLastReport
 
+	case "Library Stats":
+		return m, Push(NewLibraryStatsModel(m.config))
+
 	case "Manage Backups":
-		backupModel := NewBackupMenuModel(m.config)
-		backupModel.width = m.width
-		backupModel.height = m.height
-		return backupModel, backupModel.Init()
+		return m, Push(NewBackupMenuModel(m.config))
+
+	case "Undo Last Clean":
+		return m, Push(NewUndoModel())
 
 	case "Configure Frequency":
-		freqModel := NewFrequencyMenuModel(m.config)
-		freqModel.width = m.width
-		freqModel.height = m.height
-		return freqModel, nil
+		return m, Push(NewFrequencyMenuModel(m.config))
 
 	case "Enable/Disable Daemon":
-		daemonModel := NewDaemonMenuModel(m.config)
-		daemonModel.width = m.width
-		daemonModel.height = m.height
-		return daemonModel, nil
+		return m, Push(NewDaemonMenuModel(m.config))
 
 	case "Configure Libraries":
-		libModel := NewLibraryMenuModel(m.config)
-		libModel.width = m.width
-		libModel.height = m.height
-		// Set initial list size
-		listHeight := m.height - 16
-		if listHeight < 8 {
-			listHeight = 8
-		}
-		libModel.list.SetSize(m.width-4, listHeight)
-		return libModel, nil
+		return m, Push(NewLibraryMenuModel(m.config))
 
 	case "Configure API Keys":
-		apiModel := NewAPIConfigModel(m.config)
-		apiModel.width = m.width
-		apiModel.height = m.height
-		return apiModel, apiModel.Init()
+		return m, Push(NewAPIConfigModel(m.config))
 
 	case "Exit":
 		m.cancel()
@@ -218,26 +243,31 @@ func (m MenuModel) handleSelection(title string) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// checkScanRunning looks for a scan to attach to off the UI goroutine
+func checkScanRunning() tea.Msg {
+	return attachCheckMsg{running: daemon.New(nil).ScanRunning()}
+}
+
 // viewLastReport finds and displays the most recent report
 func (m MenuModel) viewLastReport() tea.Msg {
-	// Find most recent JSON report
-	var scanResultsPath string
-
-	// If running with sudo, use the real user's home directory
-	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" {
-		scanResultsPath = "/home/" + sudoUser + "/.local/share/jellysink/scan_results"
-	} else {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return scanStatusMsg{err: fmt.Errorf("failed to get home directory: %w", err)}
-		}
-		scanResultsPath = homeDir + "/.local/share/jellysink/scan_results"
+	reportPath, err := findLatestReport()
+	if err != nil {
+		return scanStatusMsg{err: err}
 	}
 
+	// Load and return as scanStatusMsg to trigger report view
+	return scanStatusMsg{reportPath: reportPath, err: nil}
+}
+
+// findLatestReport returns the path of the most recent JSON report
+// Looks in the configured [reports] dir (real user's home when running as root)
+func findLatestReport() (string, error) {
+	scanResultsPath := daemon.GetReportDir()
+
 	// List all JSON files
 	files, err := os.ReadDir(scanResultsPath)
 	if err != nil {
-		return scanStatusMsg{err: fmt.Errorf("failed to read scan results directory: %w", err)}
+		return "", fmt.Errorf("failed to read scan results directory: %w", err)
 	}
 
 	// Filter for JSON files and find most recent
@@ -261,35 +291,17 @@ func (m MenuModel) viewLastReport() tea.Msg {
 	}
 
 	if mostRecent == "" {
-		return scanStatusMsg{err: fmt.Errorf("no scan reports found in %s", scanResultsPath)}
+		return "", fmt.Errorf("no scan reports found in %s", scanResultsPath)
 	}
 
-	reportPath := scanResultsPath + "/" + mostRecent
-
-	// Load and return as scanStatusMsg to trigger report view
-	return scanStatusMsg{reportPath: reportPath, err: nil}
-}
-
-// loadReportJSON loads a report from a JSON file
-func loadReportJSON(path string) (reporter.Report, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return reporter.Report{}, fmt.Errorf("failed to read report: %w", err)
-	}
-
-	var report reporter.Report
-	if err := json.Unmarshal(data, &report); err != nil {
-		return reporter.Report{}, fmt.Errorf("failed to parse report: %w", err)
-	}
-
-	return report, nil
+	return filepath.Join(scanResultsPath, mostRecent), nil
 }
 
 // View renders the menu
 func (m MenuModel) View() string {
-	// Minimum dimensions for ASCII art: 100 width x 25 height
-	const minWidth = 100
-	const minHeight = 25
+	// Minimum dimensions, lower once the banner collapses to one line
+	minWidth := MinScreenWidth
+	minHeight := minScreenHeight(m.width)
 
 	// Check if terminal is too small (only after dimensions are set)
 	if m.width > 0 && m.height > 0 && (m.width < minWidth || m.height < minHeight) {
@@ -309,8 +321,8 @@ func (m MenuModel) View() string {
 
 	var content strings.Builder
 
-	// Show ASCII header
-	content.WriteString(FormatASCIIHeader())
+	// Show the banner
+	content.WriteString(Banner(m.width))
 	content.WriteString("\n\n")
 
 	// Add menu list
@@ -349,7 +361,11 @@ func (m MenuModel) renderWithStatusPopup(baseView string) string {
 	popup.WriteString("\n")
 
 	popup.WriteString(InfoStyle.Render("Daemon:") + "\n")
-	popup.WriteString(fmt.Sprintf("  Scan frequency: %s\n", SuccessStyle.Render(m.config.Daemon.ScanFrequency)))
+	if m.config.Daemon.ScanSchedule != "" {
+		popup.WriteString(fmt.Sprintf("  Scan schedule: %s\n", SuccessStyle.Render(m.config.Daemon.ScanSchedule)))
+	} else {
+		popup.WriteString(fmt.Sprintf("  Scan frequency: %s\n", SuccessStyle.Render(m.config.Daemon.ScanFrequency)))
+	}
 
 	// Show daemon status
 	daemonStatus := getDaemonStatusString()
@@ -390,10 +406,15 @@ type FrequencyMenuModel struct {
 
 // NewFrequencyMenuModel creates frequency selection menu
 func NewFrequencyMenuModel(cfg *config.Config) FrequencyMenuModel {
+	scanTime := cfg.Daemon.ScanTime
+	if scanTime == "" {
+		scanTime = "02:00"
+	}
 	items := []list.Item{
-		MenuItem{title: "Daily", desc: "Scan every day at 2:00 AM"},
-		MenuItem{title: "Weekly", desc: "Scan every Sunday at 2:00 AM"},
-		MenuItem{title: "Biweekly", desc: "Scan every other Sunday at 2:00 AM"},
+		MenuItem{title: "Daily", desc: "Scan every day at " + scanTime},
+		MenuItem{title: "Weekly", desc: "Scan every Sunday at " + scanTime},
+		MenuItem{title: "Biweekly", desc: "Scan every other Sunday at " + scanTime},
+		MenuItem{title: "Custom Schedule", desc: "Enter a cron expression or OnCalendar string"},
 		MenuItem{title: "Back", desc: "Return to main menu"},
 	}
 
@@ -433,24 +454,28 @@ func (m FrequencyMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q", "esc":
-			return NewMenuModel(m.config), nil
+			return m, Pop()
 
 		case "enter":
 			selected := m.list.SelectedItem().(MenuItem)
 			freq := strings.ToLower(selected.title)
 			if freq == "back" {
-				return NewMenuModel(m.config), nil
+				return m, Pop()
+			}
+			if freq == "custom schedule" {
+				return m, Push(NewScheduleInputModel(m.config))
 			}
 			m.config.Daemon.ScanFrequency = freq
+			m.config.Daemon.ScanSchedule = ""
 			config.Save(m.config)
-			return NewMenuModel(m.config), tea.Printf("Scan frequency set to %s", freq)
+			return m, tea.Batch(Pop(), tea.Printf("Scan frequency set to %s", freq), syncTimerCmd(m.config))
 		}
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		// ASCII header (9) + spacing (2) + footer (3) + padding (2) = 16 lines
-		listHeight := msg.Height - 16
+		// Banner (9, 1 collapsed) + spacing (2) + footer (3) + padding (2) = 16 lines
+		listHeight := msg.Height - 16 + bannerSaved(msg.Width)
 		if listHeight < 8 {
 			listHeight = 8
 		}
@@ -464,8 +489,8 @@ func (m FrequencyMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m FrequencyMenuModel) View() string {
 	// Minimum dimensions check
-	const minWidth = 100
-	const minHeight = 25
+	minWidth := MinScreenWidth
+	minHeight := minScreenHeight(m.width)
 
 	if m.width > 0 && m.height > 0 && (m.width < minWidth || m.height < minHeight) {
 		warningStyle := lipgloss.NewStyle().
@@ -483,10 +508,13 @@ func (m FrequencyMenuModel) View() string {
 	}
 
 	var content strings.Builder
-	content.WriteString(FormatASCIIHeader())
+	content.WriteString(Banner(m.width))
 	content.WriteString("\n\n")
 	content.WriteString(m.list.View())
 	content.WriteString("\n\n")
+	if schedule, err := daemon.ConfiguredSchedule(m.config.Daemon); err == nil {
+		content.WriteString(renderSchedulePreview(schedule) + "\n")
+	}
 
 	// Footer help text
 	footer := MutedStyle.Render("↑/↓: Navigate  •  Enter: Select  •  Esc: Back  •  Q/Ctrl+C: Quit")
@@ -499,6 +527,156 @@ func (m FrequencyMenuModel) View() string {
 	return mainStyle.Render(content.String())
 }
 
+// schedulePreviewRuns is how many upcoming scans the schedule screens list
+const schedulePreviewRuns = 5
+
+// renderSchedulePreview lists the next scans of a schedule
+func renderSchedulePreview(schedule daemon.Schedule) string {
+	var b strings.Builder
+	b.WriteString(InfoStyle.Render("Scanning "+schedule.String()+"; next scans:") + "\n")
+	for _, next := range schedule.NextN(time.Now(), schedulePreviewRuns) {
+		b.WriteString("  " + MutedStyle.Render(next.Format("Mon 2006-01-02 15:04")) + "\n")
+	}
+	return b.String()
+}
+
+// syncTimerCmd moves an installed jellysink.timer onto the saved schedule.
+// Writing the unit needs root, so failing to is only reported
+func syncTimerCmd(cfg *config.Config) tea.Cmd {
+	schedule, err := daemon.ConfiguredSchedule(cfg.Daemon)
+	if err != nil {
+		return nil
+	}
+	rewritten, err := daemon.SyncSystemdTimer(daemon.SystemdTimerPath, schedule)
+	if err != nil {
+		return tea.Printf("jellysink.timer not updated (%v); rerun the installer or jellysinkd --daemon as root", err)
+	}
+	if rewritten {
+		return tea.Printf("jellysink.timer now scans %s", schedule)
+	}
+	return nil
+}
+
+// ScheduleInputModel edits daemon.scan_schedule, previewing the next scans
+// as the schedule is typed
+type ScheduleInputModel struct {
+	textInput textinput.Model
+	config    *config.Config
+	width     int
+	height    int
+	err       string
+}
+
+// NewScheduleInputModel creates the custom schedule editor
+func NewScheduleInputModel(cfg *config.Config) ScheduleInputModel {
+	ti := textinput.New()
+	ti.Placeholder = "Mon,Thu 03:30"
+	ti.SetValue(cfg.Daemon.ScanSchedule)
+	ti.Focus()
+	ti.CharLimit = 200
+	ti.Width = 60
+
+	ti.PromptStyle = lipgloss.NewStyle().Foreground(RAMARed)
+	ti.TextStyle = lipgloss.NewStyle().Foreground(RAMAForeground)
+	ti.PlaceholderStyle = lipgloss.NewStyle().Foreground(RAMAMuted)
+
+	return ScheduleInputModel{
+		textInput: ti,
+		config:    cfg,
+	}
+}
+
+func (m ScheduleInputModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// schedule parses the typed schedule; empty falls back to the frequency
+func (m ScheduleInputModel) schedule() (daemon.Schedule, error) {
+	daemonCfg := m.config.Daemon
+	daemonCfg.ScanSchedule = strings.TrimSpace(m.textInput.Value())
+	return daemon.ConfiguredSchedule(daemonCfg)
+}
+
+func (m ScheduleInputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return m, Pop()
+
+		case "enter":
+			schedule, err := m.schedule()
+			if err != nil {
+				m.err = err.Error()
+				return m, nil
+			}
+			m.config.Daemon.ScanSchedule = strings.TrimSpace(m.textInput.Value())
+			if err := config.Save(m.config); err != nil {
+				m.err = fmt.Sprintf("Failed to save config: %v", err)
+				return m, nil
+			}
+			return m, tea.Batch(Pop(), tea.Printf("Scanning %s", schedule), syncTimerCmd(m.config))
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	m.textInput, cmd = m.textInput.Update(msg)
+	m.err = ""
+	return m, cmd
+}
+
+func (m ScheduleInputModel) View() string {
+	minWidth := MinScreenWidth
+	minHeight := minScreenHeight(m.width)
+
+	if m.width > 0 && m.height > 0 && (m.width < minWidth || m.height < minHeight) {
+		warningStyle := lipgloss.NewStyle().
+			Foreground(ColorWarning).
+			Bold(true).
+			Align(lipgloss.Center, lipgloss.Center).
+			Width(m.width).
+			Height(m.height)
+
+		warning := fmt.Sprintf(
+			"Terminal too small!\n\nMinimum: %dx%d\nCurrent: %dx%d\n\nPlease resize your terminal.",
+			minWidth, minHeight, m.width, m.height,
+		)
+		return warningStyle.Render(warning)
+	}
+
+	var content strings.Builder
+	content.WriteString(Banner(m.width))
+	content.WriteString("\n\n")
+	content.WriteString(TitleStyle.Render("CUSTOM SCAN SCHEDULE") + "\n\n")
+	content.WriteString(InfoStyle.Render("Enter a cron expression (\"30 3 * * 1,4\") or OnCalendar string (\"Mon,Thu 03:30\").") + "\n")
+	content.WriteString(MutedStyle.Render("Leave empty to scan on the scan_frequency preset.") + "\n\n")
+	content.WriteString(m.textInput.View())
+	content.WriteString("\n\n")
+
+	if schedule, err := m.schedule(); err != nil {
+		content.WriteString(ErrorStyle.Render("✗ "+err.Error()) + "\n\n")
+	} else {
+		content.WriteString(renderSchedulePreview(schedule) + "\n")
+	}
+	if m.err != "" {
+		content.WriteString(ErrorStyle.Render("✗ "+m.err) + "\n\n")
+	}
+
+	footer := MutedStyle.Render("Enter: Save  •  Esc: Back")
+	content.WriteString(footer)
+
+	mainStyle := lipgloss.NewStyle().
+		Padding(1, 2).
+		Width(m.width - 4)
+
+	return mainStyle.Render(content.String())
+}
+
 // DaemonMenuModel handles daemon enable/disable
 type DaemonMenuModel struct {
 	list   list.Model
@@ -552,28 +730,35 @@ func (m DaemonMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q", "esc":
-			return NewMenuModel(m.config), nil
+			return m, Pop()
 
 		case "enter":
 			selected := m.list.SelectedItem().(MenuItem)
 			switch selected.title {
 			case "Back":
-				return NewMenuModel(m.config), nil
+				return m, Pop()
 			case "Enable Daemon":
 				// Enable and start the timer
 				cmd := exec.Command("systemctl", "enable", "--now", "jellysink.timer")
 				if err := cmd.Run(); err != nil {
-					return NewMenuModel(m.config), tea.Printf("Failed to enable daemon: %v", err)
+					return m, tea.Batch(Pop(), tea.Printf("Failed to enable daemon: %v", err))
 				}
-				return NewMenuModel(m.config), tea.Printf("Daemon enabled successfully")
+				return m, tea.Batch(Pop(), tea.Printf("Daemon enabled successfully"))
 			case "Disable Daemon":
 				// Disable and stop the timer
 				cmd := exec.Command("systemctl", "disable", "--now", "jellysink.timer")
 				if err := cmd.Run(); err != nil {
-					return NewMenuModel(m.config), tea.Printf("Failed to disable daemon: %v", err)
+					return m, tea.Batch(Pop(), tea.Printf("Failed to disable daemon: %v", err))
 				}
-				return NewMenuModel(m.config), tea.Printf("Daemon disabled successfully")
+				return m, tea.Batch(Pop(), tea.Printf("Daemon disabled successfully"))
 			case "Daemon Status":
+				// A jellysinkd --daemon serving the API answers for itself
+				if m.config.Daemon.HTTPAddr != "" {
+					status, err := daemon.FetchAPIStatus(context.Background(), m.config.Daemon.HTTPAddr, m.config.Daemon.HTTPToken)
+					if err == nil {
+						return m, tea.Printf("%s", formatAPIStatus(status))
+					}
+				}
 				// Show detailed status
 				timerActive, serviceActive := checkDaemonStatus()
 				statusMsg := fmt.Sprintf("Timer: %s, Service: %s",
@@ -588,8 +773,8 @@ func (m DaemonMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		// ASCII header (9) + status section (5) + footer (3) + padding (2) = 19 lines
-		listHeight := msg.Height - 19
+		// Banner (9, 1 collapsed) + status section (5) + footer (3) + padding (2) = 19 lines
+		listHeight := msg.Height - 19 + bannerSaved(msg.Width)
 		if listHeight < 6 {
 			listHeight = 6
 		}
@@ -603,8 +788,8 @@ func (m DaemonMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m DaemonMenuModel) View() string {
 	// Minimum dimensions check
-	const minWidth = 100
-	const minHeight = 25
+	minWidth := MinScreenWidth
+	minHeight := minScreenHeight(m.width)
 
 	if m.width > 0 && m.height > 0 && (m.width < minWidth || m.height < minHeight) {
 		warningStyle := lipgloss.NewStyle().
@@ -622,7 +807,7 @@ func (m DaemonMenuModel) View() string {
 	}
 
 	var content strings.Builder
-	content.WriteString(FormatASCIIHeader())
+	content.WriteString(Banner(m.width))
 	content.WriteString("\n\n")
 
 	// Show current daemon status with markers
@@ -712,62 +897,21 @@ func (m LibraryMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q", "esc":
-			mainMenu := NewMenuModel(m.config)
-			mainMenu.width = m.width
-			mainMenu.height = m.height
-			listHeight := m.height - 16
-			if listHeight < 8 {
-				listHeight = 8
-			}
-			mainMenu.list.SetSize(m.width-4, listHeight)
-			return mainMenu, nil
+			return m, Pop()
 
 		case "enter":
 			selected := m.list.SelectedItem().(MenuItem)
 			switch selected.title {
 			case "Back":
-				mainMenu := NewMenuModel(m.config)
-				mainMenu.width = m.width
-				mainMenu.height = m.height
-				listHeight := m.height - 16
-				if listHeight < 8 {
-					listHeight = 8
-				}
-				mainMenu.list.SetSize(m.width-4, listHeight)
-				return mainMenu, nil
+				return m, Pop()
 			case "Add Movie Library":
-				addModel := NewAddPathModel(m.config, "movie")
-				addModel.width = m.width
-				addModel.height = m.height
-				return addModel, nil
+				return m, Push(NewAddPathModel(m.config, "movie"))
 			case "Add TV Library":
-				addModel := NewAddPathModel(m.config, "tv")
-				addModel.width = m.width
-				addModel.height = m.height
-				return addModel, nil
+				return m, Push(NewAddPathModel(m.config, "tv"))
 			case "Remove Library":
-				removeModel := NewRemovePathModel(m.config)
-				removeModel.width = m.width
-				removeModel.height = m.height
-				// Set initial list size for remove model
-				listHeight := m.height - 16
-				if listHeight < 8 {
-					listHeight = 8
-				}
-				removeModel.list.SetSize(m.width-4, listHeight)
-				return removeModel, nil
+				return m, Push(NewRemovePathModel(m.config))
 			case "List Libraries":
-				listModel := NewListLibrariesModel(m.config)
-				listModel.width = m.width
-				listModel.height = m.height
-				// Initialize viewport immediately with dimensions
-				headerHeight := 15
-				footerHeight := 4
-				listModel.viewport = viewport.New(m.width-4, m.height-headerHeight-footerHeight)
-				listModel.viewport.Style = lipgloss.NewStyle().Padding(0, 1)
-				listModel.viewport.SetContent(listModel.buildLibraryList())
-				listModel.ready = true
-				return listModel, nil
+				return m, Push(NewListLibrariesModel(m.config))
 			default:
 				// Let list handle other keys
 				var cmd tea.Cmd
@@ -784,8 +928,8 @@ func (m LibraryMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		// ASCII header (9) + spacing (2) + footer (3) + padding (2) = 16 lines
-		listHeight := msg.Height - 16
+		// Banner (9, 1 collapsed) + spacing (2) + footer (3) + padding (2) = 16 lines
+		listHeight := msg.Height - 16 + bannerSaved(msg.Width)
 		if listHeight < 8 {
 			listHeight = 8
 		}
@@ -798,8 +942,8 @@ func (m LibraryMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m LibraryMenuModel) View() string {
 	// Minimum dimensions check
-	const minWidth = 100
-	const minHeight = 25
+	minWidth := MinScreenWidth
+	minHeight := minScreenHeight(m.width)
 
 	if m.width > 0 && m.height > 0 && (m.width < minWidth || m.height < minHeight) {
 		warningStyle := lipgloss.NewStyle().
@@ -818,8 +962,8 @@ func (m LibraryMenuModel) View() string {
 
 	var content strings.Builder
 
-	// Show ASCII header
-	content.WriteString(FormatASCIIHeader())
+	// Show the banner
+	content.WriteString(Banner(m.width))
 	content.WriteString("\n\n")
 
 	// Show menu list directly (library preview removed - use "List Libraries" option instead)
@@ -880,27 +1024,11 @@ func (m AddPathModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q":
-			libModel := NewLibraryMenuModel(m.config)
-			libModel.width = m.width
-			libModel.height = m.height
-			listHeight := m.height - 16
-			if listHeight < 8 {
-				listHeight = 8
-			}
-			libModel.list.SetSize(m.width-4, listHeight)
-			return libModel, nil
+			return m, Pop()
 
 		case "esc":
 			// Cancel and return to library menu
-			libModel := NewLibraryMenuModel(m.config)
-			libModel.width = m.width
-			libModel.height = m.height
-			listHeight := m.height - 16
-			if listHeight < 8 {
-				listHeight = 8
-			}
-			libModel.list.SetSize(m.width-4, listHeight)
-			return libModel, nil
+			return m, Pop()
 
 		case "enter":
 			// Validate and add path
@@ -978,8 +1106,8 @@ func (m AddPathModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m AddPathModel) View() string {
 	// Minimum dimensions check
-	const minWidth = 100
-	const minHeight = 25
+	minWidth := MinScreenWidth
+	minHeight := minScreenHeight(m.width)
 
 	if m.width > 0 && m.height > 0 && (m.width < minWidth || m.height < minHeight) {
 		warningStyle := lipgloss.NewStyle().
@@ -998,8 +1126,8 @@ func (m AddPathModel) View() string {
 
 	var content strings.Builder
 
-	// Show ASCII header
-	content.WriteString(FormatASCIIHeader())
+	// Show the banner
+	content.WriteString(Banner(m.width))
 	content.WriteString("\n\n")
 
 	// Title
@@ -1142,14 +1270,14 @@ func (m RemovePathModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q", "esc":
-			return NewLibraryMenuModel(m.config), nil
+			return m, Pop()
 
 		case "enter":
 			selected := m.list.SelectedItem()
 
 			// Handle Back option
 			if menuItem, ok := selected.(MenuItem); ok && menuItem.title == "Back" {
-				return NewLibraryMenuModel(m.config), nil
+				return m, Pop()
 			}
 
 			// Handle path removal
@@ -1175,19 +1303,19 @@ func (m RemovePathModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				// Save config
 				if err := config.Save(m.config); err != nil {
-					return NewLibraryMenuModel(m.config), tea.Printf("Failed to save: %v", err)
+					return m, tea.Batch(Pop(), tea.Printf("Failed to save: %v", err))
 				}
 
 				// Return to library menu with success message
-				return NewLibraryMenuModel(m.config), tea.Printf("Removed %s library path: %s", pathItem.libraryType, pathItem.path)
+				return m, tea.Batch(Pop(), tea.Printf("Removed %s library path: %s", pathItem.libraryType, pathItem.path))
 			}
 		}
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		// ASCII header (9) + spacing (2) + footer (3) + padding (2) = 16 lines
-		listHeight := msg.Height - 16
+		// Banner (9, 1 collapsed) + spacing (2) + footer (3) + padding (2) = 16 lines
+		listHeight := msg.Height - 16 + bannerSaved(msg.Width)
 		if listHeight < 8 {
 			listHeight = 8
 		}
@@ -1201,8 +1329,8 @@ func (m RemovePathModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m RemovePathModel) View() string {
 	// Minimum dimensions check
-	const minWidth = 100
-	const minHeight = 25
+	minWidth := MinScreenWidth
+	minHeight := minScreenHeight(m.width)
 
 	if m.width > 0 && m.height > 0 && (m.width < minWidth || m.height < minHeight) {
 		warningStyle := lipgloss.NewStyle().
@@ -1221,8 +1349,8 @@ func (m RemovePathModel) View() string {
 
 	var content strings.Builder
 
-	// Show ASCII header
-	content.WriteString(FormatASCIIHeader())
+	// Show the banner
+	content.WriteString(Banner(m.width))
 	content.WriteString("\n\n")
 
 	// Show warning
@@ -1258,6 +1386,21 @@ func checkDaemonStatus() (timerActive bool, serviceActive bool) {
 	return timerActive, serviceActive
 }
 
+// formatAPIStatus summarizes the status reported by jellysinkd's API
+func formatAPIStatus(status *daemon.APIStatus) string {
+	msg := fmt.Sprintf("jellysinkd (pid %d): %s, scanning %s", status.PID, status.State, status.Schedule)
+	if status.ScanRunning && status.State != daemon.ServiceScanning {
+		msg += ", another scan is running"
+	}
+	if !status.NextScan.IsZero() {
+		msg += ", next scan " + status.NextScan.Local().Format("Mon 2006-01-02 15:04")
+	}
+	if status.LastError != "" {
+		msg += ", last error: " + status.LastError
+	}
+	return msg
+}
+
 // getDaemonStatusString returns a formatted status string for display
 func getDaemonStatusString() string {
 	timerActive, serviceActive := checkDaemonStatus()
@@ -1309,16 +1452,7 @@ func (m ListLibrariesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q", "esc":
-			// Transfer dimensions when returning to menu
-			libModel := NewLibraryMenuModel(m.config)
-			libModel.width = m.width
-			libModel.height = m.height
-			listHeight := m.height - 16
-			if listHeight < 8 {
-				listHeight = 8
-			}
-			libModel.list.SetSize(m.width-4, listHeight)
-			return libModel, nil
+			return m, Pop()
 		}
 
 	case tea.WindowSizeMsg:
@@ -1327,8 +1461,8 @@ func (m ListLibrariesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		if !m.ready {
 			// Initialize viewport with content
-			headerHeight := 15 // ASCII header + title + padding
-			footerHeight := 4  // Help text + padding
+			headerHeight := 15 - bannerSaved(msg.Width) // banner + title + padding
+			footerHeight := 4                           // Help text + padding
 			m.viewport = viewport.New(msg.Width-4, msg.Height-headerHeight-footerHeight)
 			m.viewport.Style = lipgloss.NewStyle().
 				Padding(0, 1)
@@ -1338,7 +1472,7 @@ func (m ListLibrariesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.ready = true
 		} else {
 			m.viewport.Width = msg.Width - 4
-			m.viewport.Height = msg.Height - 19
+			m.viewport.Height = msg.Height - 19 + bannerSaved(msg.Width)
 		}
 	}
 
@@ -1382,8 +1516,8 @@ func (m ListLibrariesModel) View() string {
 	}
 
 	// Minimum dimensions check
-	const minWidth = 100
-	const minHeight = 25
+	minWidth := MinScreenWidth
+	minHeight := minScreenHeight(m.width)
 
 	if m.width > 0 && m.height > 0 && (m.width < minWidth || m.height < minHeight) {
 		warningStyle := lipgloss.NewStyle().
@@ -1402,8 +1536,8 @@ func (m ListLibrariesModel) View() string {
 
 	var content strings.Builder
 
-	// Show ASCII header
-	content.WriteString(FormatASCIIHeader())
+	// Show the banner
+	content.WriteString(Banner(m.width))
 	content.WriteString("\n\n")
 
 	// Title
@@ -1439,7 +1573,7 @@ type ScanStats struct {
 }
 
 type opState struct {
-	Operation  string
+	Operation  scanner.ProgressOperation
 	Stage      string
 	Percentage float64
 	Message    string
@@ -1447,9 +1581,9 @@ type opState struct {
 
 type LogLine struct {
 	Timestamp string
-	Operation string
+	Operation scanner.ProgressOperation
 	Message   string
-	Severity  string // info, warn, error
+	Severity  scanner.ProgressSeverity
 }
 
 type ScanningModel struct {
@@ -1464,12 +1598,14 @@ type ScanningModel struct {
 	currentProgress scanner.ScanProgress
 
 	// Per-operation stage/status
-	opStates map[string]opState
-	opOrder  []string
+	opStates map[scanner.ProgressOperation]opState
+	opOrder  []scanner.ProgressOperation
 
 	// Viewport and logs
-	viewport  viewport.Model
-	logBuffer []LogLine // Up to 1000 lines with severity
+	viewport     viewport.Model
+	logBuffer    []LogLine              // Up to maxScanLogLines lines with severity
+	renderedLogs []string               // Styled logBuffer lines, rendered once on append
+	logFilter    scanner.ProgressFilter // Which messages reach the log (progress.tui_min_severity)
 
 	// Live statistics
 	stats ScanStats
@@ -1489,8 +1625,18 @@ type ScanningModel struct {
 
 	// Channel management
 	channelClosed bool
+
+	// following is set once progress is relayed from a scan another
+	// process started; this scan is queued behind it
+	following bool
+
+	// attach watches a running scan instead of starting one
+	attach bool
 }
 
+// IsFollowing returns whether the screen shows another process's scan
+func (m ScanningModel) IsFollowing() bool { return m.following }
+
 // AlertMessage returns the current alert message (for tests and external packages)
 func (m ScanningModel) AlertMessage() string { return m.alertMsg }
 
@@ -1514,14 +1660,23 @@ func (m *ScanningModel) safeCloseProgressCh() {
 // NewScanningModel creates a new scanning screen
 func NewScanningModel(cfg *config.Config) ScanningModel {
 	ctx, cancel := context.WithCancel(context.Background())
+
+	logFilter := scanner.ProgressFilter{MinSeverity: scanner.SeverityInfo}
+	if cfg != nil && cfg.Progress.TUIMinSeverity != "" {
+		if sev, err := scanner.ParseProgressSeverity(cfg.Progress.TUIMinSeverity); err == nil {
+			logFilter.MinSeverity = sev
+		}
+	}
+
 	return ScanningModel{
 		config:          cfg,
+		logFilter:       logFilter,
 		ctx:             ctx,
 		cancel:          cancel,
 		progressCh:      make(chan scanner.ScanProgress, 100),
 		currentProgress: scanner.ScanProgress{},
-		opStates:        make(map[string]opState),
-		opOrder:         []string{},
+		opStates:        make(map[scanner.ProgressOperation]opState),
+		opOrder:         []scanner.ProgressOperation{},
 		viewport:        viewport.Model{},
 		logBuffer:       []LogLine{},
 		stats:           ScanStats{},
@@ -1531,41 +1686,102 @@ func NewScanningModel(cfg *config.Config) ScanningModel {
 	}
 }
 
+// NewAttachModel creates a scanning screen that mirrors the scan already
+// running in another process
+func NewAttachModel(cfg *config.Config) ScanningModel {
+	m := NewScanningModel(cfg)
+	m.attach = true
+	return m
+}
+
 // Init starts the scan
 func (m ScanningModel) Init() tea.Cmd {
 	return tea.Batch(m.runScan, m.waitForProgress)
 }
 
-// waitForProgress listens for progress updates
+// progressFrameInterval caps how often progress redraws the scan screen (~10 fps)
+const progressFrameInterval = 100 * time.Millisecond
+
+// maxProgressBatch bounds how many progress messages are coalesced into one frame
+const maxProgressBatch = 1000
+
+// maxScanLogLines is the scan log scrollback (capped lower in low-memory mode)
+const maxScanLogLines = 1000
+
+// progressBatchMsg carries progress messages coalesced into a single frame
+type progressBatchMsg []scanner.ScanProgress
+
+// waitForProgress listens for progress updates and coalesces them into frames
 func (m ScanningModel) waitForProgress() tea.Msg {
-	progress, ok := <-m.progressCh
-	if !ok {
+	batch := collectProgressBatch(m.progressCh, progressFrameInterval)
+	if batch == nil {
 		// Channel closed, no more progress
 		return nil
 	}
-	return progress
+	return batch
 }
 
-func (m ScanningModel) renderLogs() string {
-	var lines []string
-	for _, l := range m.logBuffer {
-		raw := fmt.Sprintf("%s %s [%s] %s", l.Timestamp, l.Operation, strings.ToUpper(l.Severity), l.Message)
-		var styled string
-		switch l.Severity {
-		case "error":
-			styled = ErrorStyle.Render(raw)
-		case "warn":
-			styled = WarningStyle.Render(raw)
-		default:
-			styled = MutedStyle.Render(raw)
+// collectProgressBatch blocks for the first message, then gathers whatever
+// else arrives within one frame. Alerts end the frame early so they show at once
+func collectProgressBatch(ch <-chan scanner.ScanProgress, frame time.Duration) progressBatchMsg {
+	first, ok := <-ch
+	if !ok {
+		return nil
+	}
+	batch := progressBatchMsg{first}
+	if first.ShowAlert {
+		return batch
+	}
+
+	deadline := time.NewTimer(frame)
+	defer deadline.Stop()
+
+	for len(batch) < maxProgressBatch {
+		select {
+		case p, ok := <-ch:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, p)
+			if p.ShowAlert {
+				return batch
+			}
+		case <-deadline.C:
+			return batch
 		}
-		lines = append(lines, styled)
 	}
-	return strings.Join(lines, "\n")
+	return batch
+}
+
+// renderLogLine styles a single scan log line
+func renderLogLine(l LogLine) string {
+	raw := fmt.Sprintf("%s %s [%s] %s", l.Timestamp, l.Operation, strings.ToUpper(string(l.Severity)), l.Message)
+	switch {
+	case l.Severity.IsError():
+		return ErrorStyle.Render(raw)
+	case l.Severity == scanner.SeverityWarn:
+		return WarningStyle.Render(raw)
+	default:
+		return MutedStyle.Render(raw)
+	}
 }
 
-// runScan executes the scan in background
+func (m ScanningModel) renderLogs() string {
+	return strings.Join(m.renderedLogs, "\n")
+}
+
+// runScan executes the scan in background, or follows the running one
 func (m ScanningModel) runScan() tea.Msg {
+	if m.attach {
+		reportPath, err := daemon.New(nil).Attach(m.ctx, m.progressCh)
+		close(m.progressCh)
+		if err == context.Canceled {
+			// Detached; the scan keeps running elsewhere
+			return nil
+		}
+		return scanStatusMsg{reportPath: reportPath, err: err}
+	}
+
 	d := daemon.New(m.config)
 	reportPath, err := d.RunScanWithProgress(m.ctx, m.progressCh)
 	close(m.progressCh) // Signal no more progress updates
@@ -1594,6 +1810,11 @@ func (m ScanningModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "ctrl+c":
 			m.cancel()
 			return m, tea.Quit
+		case "esc":
+			if m.attach {
+				m.cancel()
+				return m, Pop()
+			}
 		case "up", "k":
 			m.viewport, _ = m.viewport.Update(msg)
 			return m, nil
@@ -1614,8 +1835,8 @@ func (m ScanningModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 
 		// Initialize or resize viewport for logs
-		headerHeight := 10 // ASCII header + progress area
-		footerHeight := 4  // Help/footer
+		headerHeight := 10 - bannerSaved(msg.Width) // banner + progress area
+		footerHeight := 4                           // Help/footer
 		vpWidth := msg.Width - 4
 		vpHeight := msg.Height - headerHeight - footerHeight
 		if vpHeight < 4 {
@@ -1633,98 +1854,110 @@ func (m ScanningModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case scanner.ScanProgress:
-		// Real progress update received
-		m.currentProgress = msg
-
-		// Update live stats
-		m.stats.FilesProcessed = msg.FilesProcessed
-		m.stats.DuplicatesFound = msg.DuplicatesFound
-		m.stats.ComplianceIssues = msg.ComplianceIssues
-		m.stats.ErrorsEncountered = msg.ErrorsEncountered
-
-		// Start time: prefer progress StartTime if provided
-		if m.startTime.IsZero() && !msg.StartTime.IsZero() {
-			m.startTime = msg.StartTime
-		}
+		// Single progress update (tests and direct sends)
+		m.applyProgress(msg)
+		m.refreshLogView()
+		return m, m.waitForProgress
 
-		// Calculate ETA
-		if msg.Total > 0 && msg.Current > 0 {
-			elapsed := time.Since(m.startTime)
-			rate := float64(msg.Current) / elapsed.Seconds()
-			remaining := msg.Total - msg.Current
-			if rate > 0 {
-				m.eta = time.Duration(float64(remaining)/rate) * time.Second
-			}
+	case progressBatchMsg:
+		// One frame's worth of progress: apply all, redraw once
+		for _, p := range msg {
+			m.applyProgress(p)
 		}
+		m.refreshLogView()
+		return m, m.waitForProgress
 
-		// Handle alert requests
-		if msg.ShowAlert {
-			m.showAlert = true
-			m.alertType = msg.AlertType
-			m.alertMsg = msg.Message
-			m.alertBuffer = append(m.alertBuffer, msg.Message)
-			if len(m.alertBuffer) > 10 {
-				m.alertBuffer = m.alertBuffer[len(m.alertBuffer)-10:]
-			}
+	case scanStatusMsg:
+		// Scan completed - switch to report view
+		if msg.err != nil {
+			return m, tea.Printf("Scan failed: %v", msg.err)
 		}
 
-		// Update operation state
-		op := msg.Operation
-		st := opState{
-			Operation:  op,
-			Stage:      msg.Stage,
-			Percentage: msg.Percentage,
-			Message:    msg.Message,
-		}
-		if _, exists := m.opStates[op]; !exists {
-			m.opOrder = append(m.opOrder, op)
-		}
-		m.opStates[op] = st
+		// Read the report summary off the UI goroutine
+		return m, loadReportCmd(msg.reportPath)
 
-		// Add to log buffer (max 1000 lines)
-		// Build structured log entry
-		logEntry := LogLine{
-			Timestamp: fmt.Sprintf("%02d:%02d", msg.ElapsedSeconds/60, msg.ElapsedSeconds%60),
-			Operation: msg.Operation,
-			Message:   msg.Message,
-			Severity:  msg.Severity,
+	case reportLoadedMsg:
+		if msg.err != nil {
+			return m, tea.Printf("Failed to load report: %v", msg.err)
 		}
+		return m, Replace(newReportModel(msg))
+	}
 
-		m.logBuffer = append(m.logBuffer, logEntry)
-		if len(m.logBuffer) > 1000 {
-			m.logBuffer = m.logBuffer[len(m.logBuffer)-1000:]
-		}
+	return m, nil
+}
 
-		// Update viewport content and auto-scroll if available
-		if m.viewport.Width > 0 && m.viewport.Height > 0 {
-			m.viewport.SetContent(m.renderLogs())
-			// Auto-scroll to bottom when new message arrives
-			m.viewport.GotoBottom()
-		}
+// applyProgress folds a progress message into the model state without redrawing
+func (m *ScanningModel) applyProgress(p scanner.ScanProgress) {
+	m.currentProgress = p
+	if p.Relayed {
+		m.following = true
+	}
 
-		// Continue waiting for more progress
-		return m, m.waitForProgress
+	// Update live stats
+	m.stats.FilesProcessed = p.FilesProcessed
+	m.stats.DuplicatesFound = p.DuplicatesFound
+	m.stats.ComplianceIssues = p.ComplianceIssues
+	m.stats.ErrorsEncountered = p.ErrorsEncountered
 
-	case scanStatusMsg:
-		// Scan completed - switch to report view
-		if msg.err != nil {
-			return m, tea.Printf("Scan failed: %v", msg.err)
+	// Start time: prefer progress StartTime if provided
+	if m.startTime.IsZero() && !p.StartTime.IsZero() {
+		m.startTime = p.StartTime
+	}
+
+	// Calculate ETA
+	if p.Total > 0 && p.Current > 0 {
+		m.eta = EstimateETA(m.startTime, time.Now(), p.Current, p.Total)
+	}
+
+	// Handle alert requests
+	if p.ShowAlert {
+		m.showAlert = true
+		m.alertType = p.AlertType
+		m.alertMsg = p.Message
+		m.alertBuffer = append(m.alertBuffer, p.Message)
+		if len(m.alertBuffer) > 10 {
+			m.alertBuffer = m.alertBuffer[len(m.alertBuffer)-10:]
 		}
+	}
 
-		// Load report and switch to report view
-		report, err := loadReportJSON(msg.reportPath)
-		if err != nil {
-			return m, tea.Printf("Failed to load report: %v", err)
+	// Update operation state
+	op := p.Operation
+	st := opState{
+		Operation:  op,
+		Stage:      p.Stage,
+		Percentage: p.Percentage,
+		Message:    p.Message,
+	}
+	if _, exists := m.opStates[op]; !exists {
+		m.opOrder = append(m.opOrder, op)
+	}
+	m.opStates[op] = st
+
+	// Add to log buffer if it passes the TUI severity filter
+	// Lines are styled once here so each redraw only joins strings
+	if m.logFilter.Allows(p) {
+		logEntry := LogLine{
+			Timestamp: fmt.Sprintf("%02d:%02d", p.ElapsedSeconds/60, p.ElapsedSeconds%60),
+			Operation: p.Operation,
+			Message:   p.Message,
+			Severity:  p.Severity,
 		}
 
-		// Create report model with dimensions
-		reportModel := NewModel(report)
-		return reportModel, func() tea.Msg {
-			return tea.WindowSizeMsg{Width: m.width, Height: m.height}
+		m.logBuffer = append(m.logBuffer, logEntry)
+		m.renderedLogs = append(m.renderedLogs, renderLogLine(logEntry))
+		if limit := scanner.LogLimit(maxScanLogLines); len(m.logBuffer) > limit {
+			m.logBuffer = m.logBuffer[len(m.logBuffer)-limit:]
+			m.renderedLogs = m.renderedLogs[len(m.renderedLogs)-limit:]
 		}
 	}
+}
 
-	return m, nil
+// refreshLogView pushes the log to the viewport and follows the tail
+func (m *ScanningModel) refreshLogView() {
+	if m.viewport.Width > 0 && m.viewport.Height > 0 {
+		m.viewport.SetContent(m.renderLogs())
+		m.viewport.GotoBottom()
+	}
 }
 
 // View renders the scanning screen
@@ -1735,8 +1968,8 @@ func (m ScanningModel) View() string {
 
 	var content strings.Builder
 
-	// Show ASCII header
-	content.WriteString(FormatASCIIHeader())
+	// Show the banner
+	content.WriteString(Banner(m.width))
 	content.WriteString("\n\n")
 
 	// Progress header
@@ -1745,7 +1978,19 @@ func (m ScanningModel) View() string {
 		Foreground(RAMARed).
 		Align(lipgloss.Center).
 		Width(m.width - 8)
-	content.WriteString(progressHeaderStyle.Render("SCANNING LIBRARIES"))
+	noteStyle := MutedStyle.Align(lipgloss.Center).Width(m.width - 8)
+	switch {
+	case m.attach:
+		content.WriteString(progressHeaderStyle.Render("ATTACHED TO RUNNING SCAN"))
+		content.WriteString("\n")
+		content.WriteString(noteStyle.Render("Watching a scan started elsewhere; its report opens here when it finishes"))
+	case m.following:
+		content.WriteString(progressHeaderStyle.Render("FOLLOWING RUNNING SCAN"))
+		content.WriteString("\n")
+		content.WriteString(noteStyle.Render("Your scan is queued behind a scan already in progress; its report opens here when it finishes"))
+	default:
+		content.WriteString(progressHeaderStyle.Render("SCANNING LIBRARIES"))
+	}
 	content.WriteString("\n\n")
 
 	// Operation stages
@@ -1786,59 +2031,20 @@ func (m ScanningModel) View() string {
 		content.WriteString("\n\n")
 	}
 
-	// Current phase
-	if m.currentProgress.Message != "" {
-		phaseStyle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(ColorInfo).
-			Align(lipgloss.Center).
-			Width(m.width - 8)
-		content.WriteString(phaseStyle.Render(m.currentProgress.Message))
-		content.WriteString("\n\n")
-	}
-
-	// Progress bar (50 characters wide)
-	barWidth := int(m.currentProgress.Percentage * 50 / 100)
-	if barWidth > 50 {
-		barWidth = 50
-	}
-	if barWidth < 0 {
-		barWidth = 0
-	}
-	progressBar := strings.Repeat("█", barWidth) + strings.Repeat("░", 50-barWidth)
-	progressBarStyle := lipgloss.NewStyle().
-		Foreground(RAMARed).
-		Align(lipgloss.Center).
-		Width(m.width - 8)
-	content.WriteString(progressBarStyle.Render(fmt.Sprintf("[%s] %.1f%%", progressBar, m.currentProgress.Percentage)))
-	content.WriteString("\n\n")
-
-	// Live statistics
-	statsStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(RAMARed).
-		Padding(0, 2).
-		Width(m.width - 10)
-	statsContent := fmt.Sprintf(
-		"Files Processed: %d  |  Duplicates: %d  |  Compliance Issues: %d  |  Errors: %d",
-		m.stats.FilesProcessed,
-		m.stats.DuplicatesFound,
-		m.stats.ComplianceIssues,
-		m.stats.ErrorsEncountered,
-	)
-	content.WriteString(statsStyle.Render(statsContent))
-	content.WriteString("\n\n")
-
-	// ETA display
-	if m.eta > 0 {
-		etaStyle := lipgloss.NewStyle().
-			Foreground(ColorInfo).
-			Align(lipgloss.Center).
-			Width(m.width - 8)
-		etaText := fmt.Sprintf("Estimated Time Remaining: %s", m.eta.Round(time.Second))
-		content.WriteString(etaStyle.Render(etaText))
-		content.WriteString("\n\n")
+	// Progress bar, current phase, live statistics and ETA
+	progress := Progress{
+		Percent: m.currentProgress.Percentage,
+		Status:  m.currentProgress.Message,
+		ETA:     m.eta,
+		Stats: []ProgressStat{
+			{Label: "Files Processed", Value: fmt.Sprintf("%d", m.stats.FilesProcessed)},
+			{Label: "Duplicates", Value: fmt.Sprintf("%d", m.stats.DuplicatesFound)},
+			{Label: "Compliance Issues", Value: fmt.Sprintf("%d", m.stats.ComplianceIssues)},
+			{Label: "Errors", Value: fmt.Sprintf("%d", m.stats.ErrorsEncountered)},
+		},
 	}
+	content.WriteString(progress.View(m.width - 8))
+	content.WriteString("\n")
 
 	// Scrolling log viewport
 	logTitleStyle := lipgloss.NewStyle().
@@ -1857,6 +2063,9 @@ func (m ScanningModel) View() string {
 		Width(m.width - 8)
 
 	helpText := "↑/↓: Scroll logs  •  PgUp/PgDn: Page scroll  •  Ctrl+C: Cancel"
+	if m.attach {
+		helpText = "↑/↓: Scroll logs  •  PgUp/PgDn: Page scroll  •  Esc: Detach  •  Ctrl+C: Quit"
+	}
 	content.WriteString(helpStyle.Render(helpText))
 
 	// Wrap in main container
@@ -1953,6 +2162,7 @@ func NewAPIConfigModel(cfg *config.Config) APIConfigModel {
 	items := []list.Item{
 		MenuItem{title: "Configure TVDB API", desc: "Set TVDB API key for TV show metadata verification"},
 		MenuItem{title: "Configure OMDB API", desc: "Set OMDB API key for movie metadata verification"},
+		MenuItem{title: "Configure TMDB API", desc: "Set TMDB API key for movie and TV title verification"},
 		MenuItem{title: "View API Status", desc: "Check configured API keys and their status"},
 		MenuItem{title: "Back", desc: "Return to main menu"},
 	}
@@ -1992,28 +2202,21 @@ func (m APIConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q", "esc":
-			return NewMenuModel(m.config), nil
+			return m, Pop()
 
 		case "enter":
 			selected := m.list.SelectedItem().(MenuItem)
 			switch selected.title {
 			case "Back":
-				return NewMenuModel(m.config), nil
+				return m, Pop()
 			case "Configure TVDB API":
-				tvdbModel := NewSetAPIKeyModel(m.config, "tvdb")
-				tvdbModel.width = m.width
-				tvdbModel.height = m.height
-				return tvdbModel, tvdbModel.Init()
+				return m, Push(NewSetAPIKeyModel(m.config, "tvdb"))
 			case "Configure OMDB API":
-				omdbModel := NewSetAPIKeyModel(m.config, "omdb")
-				omdbModel.width = m.width
-				omdbModel.height = m.height
-				return omdbModel, omdbModel.Init()
+				return m, Push(NewSetAPIKeyModel(m.config, "omdb"))
+			case "Configure TMDB API":
+				return m, Push(NewSetAPIKeyModel(m.config, "tmdb"))
 			case "View API Status":
-				statusModel := NewAPIStatusModel(m.config)
-				statusModel.width = m.width
-				statusModel.height = m.height
-				return statusModel, nil
+				return m, Push(NewAPIStatusModel(m.config))
 			default:
 				return m, nil
 			}
@@ -2022,7 +2225,7 @@ func (m APIConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		listHeight := msg.Height - 16
+		listHeight := msg.Height - 16 + bannerSaved(msg.Width)
 		if listHeight < 8 {
 			listHeight = 8
 		}
@@ -2035,8 +2238,8 @@ func (m APIConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m APIConfigModel) View() string {
-	const minWidth = 100
-	const minHeight = 25
+	minWidth := MinScreenWidth
+	minHeight := minScreenHeight(m.width)
 
 	if m.width > 0 && m.height > 0 && (m.width < minWidth || m.height < minHeight) {
 		warningStyle := lipgloss.NewStyle().
@@ -2054,7 +2257,7 @@ func (m APIConfigModel) View() string {
 	}
 
 	var content strings.Builder
-	content.WriteString(FormatASCIIHeader())
+	content.WriteString(Banner(m.width))
 	content.WriteString("\n\n")
 	content.WriteString(m.list.View())
 	content.WriteString("\n\n")
@@ -2112,10 +2315,10 @@ func (m SetAPIKeyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q":
-			return NewAPIConfigModel(m.config), nil
+			return m, Pop()
 
 		case "esc":
-			return NewAPIConfigModel(m.config), nil
+			return m, Pop()
 
 		case "ctrl+u":
 			m.textInput.SetValue("")
@@ -2143,6 +2346,9 @@ func (m SetAPIKeyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else if m.apiType == "omdb" {
 				m.config.API.OMDB.APIKey = apiKey
 				m.config.API.OMDB.Enabled = true
+			} else if m.apiType == "tmdb" {
+				m.config.API.TMDB.APIKey = apiKey
+				m.config.API.TMDB.Enabled = true
 			}
 
 			if err := config.Save(m.config); err != nil {
@@ -2167,8 +2373,8 @@ func (m SetAPIKeyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m SetAPIKeyModel) View() string {
-	const minWidth = 100
-	const minHeight = 25
+	minWidth := MinScreenWidth
+	minHeight := minScreenHeight(m.width)
 
 	if m.width > 0 && m.height > 0 && (m.width < minWidth || m.height < minHeight) {
 		warningStyle := lipgloss.NewStyle().
@@ -2187,7 +2393,7 @@ func (m SetAPIKeyModel) View() string {
 
 	var content strings.Builder
 
-	content.WriteString(FormatASCIIHeader())
+	content.WriteString(Banner(m.width))
 	content.WriteString("\n\n")
 
 	apiName := strings.ToUpper(m.apiType)
@@ -2205,6 +2411,13 @@ func (m SetAPIKeyModel) View() string {
 		guidance.WriteString("  2. Navigate to 'API Keys' in your dashboard\n")
 		guidance.WriteString("  3. Generate a new API key (v4)\n")
 		guidance.WriteString("  4. Copy and paste it below\n")
+	} else if m.apiType == "tmdb" {
+		guidance.WriteString("TMDB (The Movie Database) provides movie and TV metadata for title verification.\n")
+		guidance.WriteString("Get your free API key at: https://www.themoviedb.org/settings/api\n\n")
+		guidance.WriteString("Steps:\n")
+		guidance.WriteString("  1. Create a free account at TMDB\n")
+		guidance.WriteString("  2. Request an API key under Settings > API\n")
+		guidance.WriteString("  3. Copy the API key or the read access token and paste it below\n")
 	} else {
 		guidance.WriteString("OMDB (Open Movie Database) provides movie metadata for title verification.\n")
 		guidance.WriteString("Get your free API key at: https://www.omdbapi.com/apikey.aspx\n\n")
@@ -2221,6 +2434,10 @@ func (m SetAPIKeyModel) View() string {
 		if m.config.API.TVDB.APIKey != "" {
 			currentKey = "***" + m.config.API.TVDB.APIKey[len(m.config.API.TVDB.APIKey)-4:]
 		}
+	} else if m.apiType == "tmdb" {
+		if m.config.API.TMDB.APIKey != "" {
+			currentKey = "***" + m.config.API.TMDB.APIKey[len(m.config.API.TMDB.APIKey)-4:]
+		}
 	} else {
 		if m.config.API.OMDB.APIKey != "" {
 			currentKey = "***" + m.config.API.OMDB.APIKey[len(m.config.API.OMDB.APIKey)-4:]
@@ -2275,7 +2492,7 @@ func (m APIStatusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q", "esc":
-			return NewAPIConfigModel(m.config), nil
+			return m, Pop()
 		}
 
 	case tea.WindowSizeMsg:
@@ -2287,8 +2504,8 @@ func (m APIStatusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m APIStatusModel) View() string {
-	const minWidth = 100
-	const minHeight = 25
+	minWidth := MinScreenWidth
+	minHeight := minScreenHeight(m.width)
 
 	if m.width > 0 && m.height > 0 && (m.width < minWidth || m.height < minHeight) {
 		warningStyle := lipgloss.NewStyle().
@@ -2307,7 +2524,7 @@ func (m APIStatusModel) View() string {
 
 	var content strings.Builder
 
-	content.WriteString(FormatASCIIHeader())
+	content.WriteString(Banner(m.width))
 	content.WriteString("\n\n")
 
 	content.WriteString(TitleStyle.Render("API CONFIGURATION STATUS") + "\n\n")
@@ -2334,6 +2551,17 @@ func (m APIStatusModel) View() string {
 	}
 	content.WriteString("\n")
 
+	content.WriteString(InfoStyle.Render("TMDB (The Movie Database) API:") + "\n")
+	if m.config.API.TMDB.Enabled && m.config.API.TMDB.APIKey != "" {
+		maskedKey := "***" + m.config.API.TMDB.APIKey[len(m.config.API.TMDB.APIKey)-4:]
+		content.WriteString("  " + FormatStatusOK("Configured") + " - Key: " + MutedStyle.Render(maskedKey) + "\n")
+		content.WriteString("  Used for: Movie title verification and TV title fallback\n")
+	} else {
+		content.WriteString("  " + FormatStatusInfo("Not configured") + "\n")
+		content.WriteString("  Get your key at: https://www.themoviedb.org/settings/api\n")
+	}
+	content.WriteString("\n")
+
 	var notes strings.Builder
 	notes.WriteString("Note: API keys are optional but recommended for TV show title resolution.\n")
 	notes.WriteString("Without API keys, jellysink will use local heuristics for title matching.\n\n")
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// scanProgressFixture simulates the progress stream of a 100k-file scan
+func scanProgressFixture(n int) []scanner.ScanProgress {
+	msgs := make([]scanner.ScanProgress, n)
+	for i := range msgs {
+		msgs[i] = scanner.ScanProgress{
+			Operation:      scanner.OpScanningMovies,
+			Stage:          "scanning",
+			Current:        i + 1,
+			Total:          n,
+			Percentage:     float64(i+1) / float64(n) * 100,
+			Message:        fmt.Sprintf("Processing: Movie.%06d.1080p.BluRay.x264-GROUP.mkv", i),
+			Severity:       scanner.SeverityInfo,
+			FilesProcessed: i + 1,
+			ElapsedSeconds: i / 1000,
+		}
+	}
+	return msgs
+}
+
+func newSizedScanningModel() ScanningModel {
+	m := NewScanningModel(config.DefaultConfig())
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return model.(ScanningModel)
+}
+
+// BenchmarkScanProgressPerMessage renders a frame for every progress message
+func BenchmarkScanProgressPerMessage(b *testing.B) {
+	msgs := scanProgressFixture(100000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var model tea.Model = newSizedScanningModel()
+		for _, p := range msgs {
+			model, _ = model.Update(p)
+		}
+	}
+}
+
+// BenchmarkScanProgressBatched coalesces the same stream into ~10 fps frames
+// 100k files over a ~60s scan is roughly 170 messages per 100ms frame
+func BenchmarkScanProgressBatched(b *testing.B) {
+	msgs := scanProgressFixture(100000)
+	const frame = 170
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var model tea.Model = newSizedScanningModel()
+		for start := 0; start < len(msgs); start += frame {
+			end := min(start+frame, len(msgs))
+			model, _ = model.Update(progressBatchMsg(msgs[start:end]))
+		}
+	}
+}
+
+func TestCollectProgressBatch(t *testing.T) {
+	ch := make(chan scanner.ScanProgress, 10)
+	for _, p := range scanProgressFixture(5) {
+		ch <- p
+	}
+	if batch := collectProgressBatch(ch, 10*time.Millisecond); len(batch) != 5 {
+		t.Errorf("Expected 5 messages coalesced into one frame, got %d", len(batch))
+	}
+
+	// Alerts end the frame immediately
+	ch <- scanner.ScanProgress{Message: "first"}
+	ch <- scanner.ScanProgress{Message: "alert", ShowAlert: true}
+	ch <- scanner.ScanProgress{Message: "next frame"}
+	batch := collectProgressBatch(ch, time.Second)
+	if len(batch) != 2 || !batch[1].ShowAlert {
+		t.Errorf("Expected frame to end at the alert, got %+v", batch)
+	}
+
+	close(ch)
+	if batch := collectProgressBatch(ch, 10*time.Millisecond); len(batch) != 1 {
+		t.Errorf("Expected remaining message before close, got %d", len(batch))
+	}
+	if batch := collectProgressBatch(ch, 10*time.Millisecond); batch != nil {
+		t.Errorf("Expected nil batch on closed channel, got %+v", batch)
+	}
+}
+
+func TestBatchedProgressMatchesPerMessage(t *testing.T) {
+	msgs := scanProgressFixture(1500)
+
+	var single tea.Model = newSizedScanningModel()
+	for _, p := range msgs {
+		single, _ = single.Update(p)
+	}
+	batched, _ := tea.Model(newSizedScanningModel()).Update(progressBatchMsg(msgs))
+
+	s, b := single.(ScanningModel), batched.(ScanningModel)
+	if s.stats != b.stats {
+		t.Errorf("Stats differ: %+v vs %+v", s.stats, b.stats)
+	}
+	if len(b.logBuffer) != maxScanLogLines || len(b.renderedLogs) != maxScanLogLines {
+		t.Errorf("Expected %d log lines, got %d buffered / %d rendered", maxScanLogLines, len(b.logBuffer), len(b.renderedLogs))
+	}
+	if s.renderLogs() != b.renderLogs() {
+		t.Error("Expected identical log output")
+	}
+}
@@ -1,6 +1,7 @@
 package ui_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -44,3 +45,33 @@ func TestAlertModalShowsAndDismisses(t *testing.T) {
 		t.Fatalf("expected alert dismissed, got '%s'", newModel2.AlertMessage())
 	}
 }
+
+func TestRelayedProgressShowsFollowing(t *testing.T) {
+	m := ui.NewScanningModel(config.DefaultConfig())
+	m.SetSize(120, 40)
+
+	ret, _ := m.Update(scanner.ScanProgress{Operation: "scanning_movies", Stage: "scanning", Message: "Scanning movies", Relayed: true})
+	newModel := ret.(ui.ScanningModel)
+	if !newModel.IsFollowing() {
+		t.Fatal("expected relayed progress to mark the scan as followed")
+	}
+	if !strings.Contains(newModel.View(), "FOLLOWING RUNNING SCAN") {
+		t.Error("expected the followed scan header")
+	}
+}
+
+func TestAttachModelDetachesOnEsc(t *testing.T) {
+	m := ui.NewAttachModel(config.DefaultConfig())
+	m.SetSize(120, 40)
+	if !strings.Contains(m.View(), "ATTACHED TO RUNNING SCAN") {
+		t.Error("expected the attached scan header")
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("expected Esc to detach back to the menu")
+	}
+	if _, quit := cmd().(tea.QuitMsg); quit {
+		t.Error("expected Esc to detach, not quit")
+	}
+}
@@ -0,0 +1,195 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// updateMovieReview handles keys on the movie title review, including the
+// custom title being typed. Each decision is applied to the movie's
+// compliance issue and saved into the report file as it is made
+func (m Model) updateMovieReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	movies := m.report.AmbiguousMovies
+	if m.editingTitle {
+		switch msg.String() {
+		case "esc":
+			m.editingTitle = false
+			m.titleInput.Blur()
+		case "enter":
+			value := strings.TrimSpace(m.titleInput.Value())
+			if value == "" {
+				return m, nil
+			}
+			movie := movies[m.movieReviewIndex]
+			movie.UserDecision, movie.CustomTitle = scanner.DecisionCustomTitle, value
+			m.decideMovie()
+			m.editingTitle = false
+			m.titleInput.Blur()
+			m.titleInput.SetValue("")
+		default:
+			var cmd tea.Cmd
+			m.titleInput, cmd = m.titleInput.Update(msg)
+			m.viewport.SetContent(m.renderMovieReview())
+			return m, cmd
+		}
+		m.viewport.SetContent(m.renderMovieReview())
+		return m, nil
+	}
+
+	movie := movies[m.movieReviewIndex]
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc":
+		m.mode = ViewSummary
+		m.viewport.SetContent(m.renderSummary())
+		return m, nil
+	case "left", "p":
+		if m.movieReviewIndex > 0 {
+			m.movieReviewIndex--
+		}
+	case "right", "n":
+		if m.movieReviewIndex < len(movies)-1 {
+			m.movieReviewIndex++
+		}
+	case "1":
+		movie.UserDecision = scanner.DecisionSuggestedTitle
+		m.decideMovie()
+	case "2":
+		if movie.APITitle == "" {
+			return m, nil
+		}
+		movie.UserDecision = scanner.DecisionAPITitle
+		m.decideMovie()
+	case "s":
+		movie.UserDecision = scanner.DecisionSkipped
+		m.decideMovie()
+	case "e":
+		m.editingTitle = true
+		m.titleInput.SetValue(movie.CustomTitle)
+		m.titleInput.Focus()
+		m.viewport.SetContent(m.renderMovieReview())
+		return m, textinput.Blink
+	case "enter":
+		if m.moviesDecided() == len(movies) {
+			m.mode = ViewSummary
+			m.viewport.SetContent(m.renderSummary())
+			m.viewport.GotoTop()
+			return m, nil
+		}
+	default:
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+	m.viewport.SetContent(m.renderMovieReview())
+	m.viewport.GotoTop()
+	return m, nil
+}
+
+// decideMovie applies the current movie's decision to its compliance issue,
+// saves it into the report file and moves on to the next movie
+func (m *Model) decideMovie() {
+	movie := m.report.AmbiguousMovies[m.movieReviewIndex]
+	// The issues are shared with the cached report until copied
+	issues := append([]scanner.ComplianceIssue(nil), m.report.ComplianceIssues...)
+	for i, issue := range issues {
+		if issue.Type == "movie" && issue.Path == movie.Path {
+			issues[i] = movie.DecideIssue(issue)
+		}
+	}
+	m.report.ComplianceIssues = issues
+
+	m.movieReviewStatus = ""
+	if m.reportPath != "" {
+		if err := reporter.SaveMovieTitleDecisions(m.reportPath, m.report); err != nil {
+			m.movieReviewStatus = ErrorStyle.Render(fmt.Sprintf("Failed to save the choice to the report: %v", err))
+			return
+		}
+	}
+	if m.movieReviewIndex < len(m.report.AmbiguousMovies)-1 {
+		m.movieReviewIndex++
+	}
+}
+
+// moviesDecided counts the reviewed movies with a decision
+func (m Model) moviesDecided() int {
+	decided := 0
+	for _, movie := range m.report.AmbiguousMovies {
+		if movie.UserDecision != scanner.DecisionNone {
+			decided++
+		}
+	}
+	return decided
+}
+
+// renderMovieReview renders the movie whose title TMDB or OMDB could not
+// confirm, with the titles it can be renamed to
+func (m Model) renderMovieReview() string {
+	var sb strings.Builder
+
+	movies := m.report.AmbiguousMovies
+	if len(movies) == 0 {
+		sb.WriteString(SuccessStyle.Render("✓ No movie titles to review") + "\n")
+		return sb.String()
+	}
+	movie := movies[m.movieReviewIndex]
+
+	sb.WriteString(TitleStyle.Render("MOVIE TITLE NOT CONFIRMED") + "\n\n")
+	sb.WriteString(InfoStyle.Render(fmt.Sprintf("Reviewing movie %d of %d (%d decided)", m.movieReviewIndex+1, len(movies), m.moviesDecided())) + "\n")
+	sb.WriteString(MutedStyle.Render(movie.Path) + "\n")
+	sb.WriteString(WarningStyle.Render(movie.Reason) + "\n\n")
+
+	option := func(key, label, name string, decision scanner.DecisionType) {
+		sb.WriteString(InfoStyle.Render(fmt.Sprintf("Option %s: %s", key, label)) + "\n")
+		sb.WriteString("  " + ContentStyle.Render(name) + "\n")
+		if movie.UserDecision == decision {
+			sb.WriteString(SuccessStyle.Render("  ✓ SELECTED") + "\n")
+		} else {
+			sb.WriteString(MutedStyle.Render(fmt.Sprintf("  Press '%s' to select", key)) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	option("1", "Suggested Title", movieName(movie.Title, movie.Year), scanner.DecisionSuggestedTitle)
+	if movie.APITitle != "" {
+		option("2", movie.Provider+" Title", movieName(movie.APITitle, movie.APIYear), scanner.DecisionAPITitle)
+	}
+
+	sb.WriteString(InfoStyle.Render("Option 3: Custom Title") + "\n")
+	switch {
+	case m.editingTitle:
+		sb.WriteString("  " + m.titleInput.View() + "\n")
+		sb.WriteString(MutedStyle.Render("  Type \"Title (Year)\"; Enter to save, Esc to cancel") + "\n")
+	case movie.UserDecision == scanner.DecisionCustomTitle:
+		sb.WriteString("  " + SuccessStyle.Render(movie.CustomTitle) + "\n")
+		sb.WriteString(SuccessStyle.Render("  ✓ SELECTED") + "\n")
+	default:
+		sb.WriteString(MutedStyle.Render("  Press 'E' to enter custom title") + "\n")
+	}
+	sb.WriteString("\n")
+
+	if movie.UserDecision == scanner.DecisionSkipped {
+		sb.WriteString(WarningStyle.Render("Skipped: the movie is left as it is") + "\n\n")
+	}
+	if m.movieReviewStatus != "" {
+		sb.WriteString(m.movieReviewStatus + "\n\n")
+	}
+	if m.moviesDecided() == len(movies) {
+		sb.WriteString(SuccessStyle.Render("✓ All movies decided. Cleaning renames them to the chosen titles.") + "\n")
+	}
+	return sb.String()
+}
+
+// movieName renders "Title (Year)", or the bare title without a year
+func movieName(title, year string) string {
+	if year == "" {
+		return title
+	}
+	return fmt.Sprintf("%s (%s)", title, year)
+}
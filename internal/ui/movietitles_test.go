@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestMovieTitleReviewSavedToReport(t *testing.T) {
+	report := reporter.Report{
+		ComplianceIssues: []scanner.ComplianceIssue{
+			{Path: "/movies/Amelie.1999/Amelie.1999.mkv", Type: "movie", SuggestedPath: "/movies/Amelie (1999)/Amelie (1999).mkv", SuggestedAction: "reorganize", Confidence: 0.5},
+			{Path: "/movies/Home.Video.2020/Home.Video.2020.mkv", Type: "movie", SuggestedPath: "/movies/Home Video (2020)/Home Video (2020).mkv", SuggestedAction: "reorganize", Confidence: 0.5},
+		},
+		AmbiguousMovies: []*scanner.MovieTitleResolution{
+			{Path: "/movies/Amelie.1999/Amelie.1999.mkv", Title: "Amelie", Year: "1999", APITitle: "Amélie", APIYear: "2001", Provider: "TMDB"},
+			{Path: "/movies/Home.Video.2020/Home.Video.2020.mkv", Title: "Home Video", Year: "2020", Provider: "TMDB"},
+		},
+	}
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+	if err := reporter.WriteReport(reportPath, report); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(report)
+	m.SetReportPath(reportPath)
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	key := func(k tea.KeyMsg) { model, _ = model.Update(k) }
+	runes := func(s string) { key(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}) }
+	key(tea.KeyMsg{Type: tea.KeyF7})
+	if model.(Model).mode != ViewMovieReview {
+		t.Fatal("Expected F7 to open the movie title review")
+	}
+
+	// 2 takes TMDB's title and moves on; the second movie has none, so 2
+	// does nothing there and S skips it
+	runes("2")
+	runes("2")
+	runes("s")
+	m = model.(Model)
+	if m.moviesDecided() != 2 {
+		t.Fatalf("Expected both movies decided, got %d", m.moviesDecided())
+	}
+
+	saved, err := reporter.ReadReport(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := saved.ComplianceIssues[0]; got.SuggestedPath != "/movies/Amélie (2001)/Amélie (2001).mkv" || got.EffectiveConfidence() != 1 {
+		t.Errorf("Expected the TMDB title confirmed in the report, got %+v", got)
+	}
+	if got := saved.ComplianceIssues[1]; got.SuggestedAction != "manual_review" {
+		t.Errorf("Expected the skipped movie held for manual review, got %+v", got)
+	}
+	if saved.AmbiguousMovies[1].UserDecision != scanner.DecisionSkipped {
+		t.Errorf("Expected the skip saved, got %+v", saved.AmbiguousMovies[1])
+	}
+
+	// E enters a custom title for the selected movie
+	key(tea.KeyMsg{Type: tea.KeyLeft})
+	runes("e")
+	model, _ = model.(Model).Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	runes("Amelie (2001)")
+	key(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(Model)
+	if got := m.report.ComplianceIssues[0].SuggestedPath; got != "/movies/Amelie (2001)/Amelie (2001).mkv" {
+		t.Errorf("Expected the custom title, got %s", got)
+	}
+
+	key(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.(Model).mode != ViewSummary {
+		t.Error("Expected Enter to return to the summary once every movie is decided")
+	}
+}
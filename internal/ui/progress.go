@@ -0,0 +1,168 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Sizes shared by every progress screen (scan, clean, rename, installer)
+const (
+	ProgressBarWidth = 50
+	progressLogTail  = 20
+)
+
+// ProgressBarStyle colors the filled and empty cells of every progress bar
+var ProgressBarStyle = lipgloss.NewStyle().Foreground(RAMARed)
+
+// ProgressStat is one labelled counter on the progress stats line
+type ProgressStat struct {
+	Label string
+	Value string
+}
+
+// Progress is the shared progress component: bar, status, stats line, ETA
+// and log tail. Screens feed it with Apply and draw it with View
+type Progress struct {
+	Percent float64
+	Status  string
+	Current int
+	Total   int
+	Errors  int
+	ETA     time.Duration
+	Stats   []ProgressStat // replaces the default processed/errors line when set
+
+	LogTitle string    // heading above the log tail; empty hides the tail
+	Logs     []LogLine // capped at MaxLogs
+	MaxLogs  int       // 0 = maxScanLogLines
+
+	start time.Time
+}
+
+// Apply folds a progress message into the component and logs it
+func (p *Progress) Apply(msg scanner.ScanProgress) {
+	p.Percent = msg.Percentage
+	p.Status = msg.Message
+	p.Current = msg.Current
+	p.Total = msg.Total
+	p.Errors = msg.ErrorsEncountered
+
+	if p.start.IsZero() {
+		p.start = msg.StartTime
+		if p.start.IsZero() {
+			p.start = time.Now()
+		}
+	}
+	if msg.Total > 0 && msg.Current > 0 {
+		p.ETA = EstimateETA(p.start, time.Now(), msg.Current, msg.Total)
+	}
+
+	p.AppendLog(LogLine{
+		Timestamp: fmt.Sprintf("%02d:%02d", msg.ElapsedSeconds/60, msg.ElapsedSeconds%60),
+		Operation: msg.Operation,
+		Message:   msg.Message,
+		Severity:  msg.Severity,
+	})
+}
+
+// AppendLog adds a line to the log, dropping the oldest past MaxLogs
+func (p *Progress) AppendLog(l LogLine) {
+	limit := p.MaxLogs
+	if limit <= 0 {
+		limit = maxScanLogLines
+	}
+	limit = scanner.LogLimit(limit)
+	p.Logs = append(p.Logs, l)
+	if len(p.Logs) > limit {
+		p.Logs = p.Logs[len(p.Logs)-limit:]
+	}
+}
+
+// View renders the component; width > 0 centers the bar, status and stats
+func (p Progress) View(width int) string {
+	center := lipgloss.NewStyle()
+	if width > 0 {
+		center = center.Width(width).Align(lipgloss.Center)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(center.Render(RenderProgressBar(p.Percent)) + "\n")
+	if p.Status != "" {
+		sb.WriteString(center.Render(InfoStyle.Render(p.Status)) + "\n")
+	}
+	sb.WriteString(center.Render(renderProgressStats(p.stats())) + "\n")
+	if p.ETA > 0 {
+		sb.WriteString(center.Render(renderProgressETA(p.ETA)) + "\n")
+	}
+
+	if p.LogTitle != "" {
+		sb.WriteString("\n" + TitleStyle.Render(p.LogTitle) + "\n")
+		sb.WriteString(strings.Repeat("─", 80) + "\n")
+		start := 0
+		if len(p.Logs) > progressLogTail {
+			start = len(p.Logs) - progressLogTail
+		}
+		for _, l := range p.Logs[start:] {
+			sb.WriteString(renderLogLine(l) + "\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// stats returns the custom stats or the default processed/errors counters
+func (p Progress) stats() []ProgressStat {
+	if len(p.Stats) > 0 {
+		return p.Stats
+	}
+	processed := fmt.Sprintf("%d", p.Current)
+	if p.Total > 0 {
+		processed = fmt.Sprintf("%d/%d", p.Current, p.Total)
+	}
+	return []ProgressStat{
+		{Label: "Processed", Value: processed},
+		{Label: "Errors", Value: fmt.Sprintf("%d", p.Errors)},
+	}
+}
+
+// RenderProgressBar draws a fixed-width bar followed by the percentage
+func RenderProgressBar(percent float64) string {
+	filled := int(percent * ProgressBarWidth / 100)
+	if filled > ProgressBarWidth {
+		filled = ProgressBarWidth
+	}
+	if filled < 0 {
+		filled = 0
+	}
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", ProgressBarWidth-filled)
+	return ProgressBarStyle.Render(fmt.Sprintf("[%s] %5.1f%%", bar, percent))
+}
+
+// EstimateETA projects the remaining time from the rate so far
+// Returns 0 when there is nothing to estimate or the work is done
+func EstimateETA(start, now time.Time, current, total int) time.Duration {
+	if start.IsZero() || current <= 0 || total <= current {
+		return 0
+	}
+	elapsed := now.Sub(start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	rate := float64(current) / elapsed
+	return time.Duration(float64(total-current)/rate) * time.Second
+}
+
+func renderProgressStats(stats []ProgressStat) string {
+	parts := make([]string, len(stats))
+	for i, s := range stats {
+		parts[i] = MutedStyle.Render(s.Label+":") + " " + ContentStyle.Render(s.Value)
+	}
+	return strings.Join(parts, MutedStyle.Render("  |  "))
+}
+
+func renderProgressETA(eta time.Duration) string {
+	return InfoStyle.Render(fmt.Sprintf("Estimated Time Remaining: %s", eta.Round(time.Second)))
+}
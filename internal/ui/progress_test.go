@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestRenderProgressBarFixedWidth(t *testing.T) {
+	want := lipgloss.Width(RenderProgressBar(0))
+	for _, pct := range []float64{-5, 33.3, 100, 150} {
+		if got := lipgloss.Width(RenderProgressBar(pct)); got != want {
+			t.Errorf("RenderProgressBar(%v) width = %d, want %d", pct, got, want)
+		}
+	}
+}
+
+func TestEstimateETA(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(10 * time.Second)
+
+	if got := EstimateETA(start, now, 25, 100); got != 30*time.Second {
+		t.Errorf("EstimateETA = %v, want 30s", got)
+	}
+	if got := EstimateETA(start, now, 100, 100); got != 0 {
+		t.Errorf("Expected no ETA once done, got %v", got)
+	}
+	if got := EstimateETA(time.Time{}, now, 25, 100); got != 0 {
+		t.Errorf("Expected no ETA without a start time, got %v", got)
+	}
+}
+
+func TestProgressApplyAndView(t *testing.T) {
+	p := Progress{LogTitle: "CLEANING LOG", MaxLogs: 3}
+	for i := 1; i <= 5; i++ {
+		p.Apply(scanner.ScanProgress{
+			Operation:  scanner.OpCleaning,
+			Current:    i,
+			Total:      10,
+			Percentage: float64(i * 10),
+			Message:    "Deleting file " + strings.Repeat("x", i),
+			Severity:   scanner.SeverityInfo,
+			StartTime:  time.Now().Add(-time.Minute),
+		})
+	}
+
+	if len(p.Logs) != 3 || p.Logs[0].Message != "Deleting file xxx" {
+		t.Errorf("Expected the last 3 log lines, got %+v", p.Logs)
+	}
+	if p.ETA <= 0 {
+		t.Error("Expected an ETA once progress has a rate")
+	}
+
+	view := p.View(0)
+	for _, want := range []string{"50.0%", "Processed:", "5/10", "CLEANING LOG", "Deleting file xxxxx"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected view to contain %q:\n%s", want, view)
+		}
+	}
+
+	p.LogTitle = ""
+	if strings.Contains(p.View(0), "[INFO]") {
+		t.Error("Expected log tail hidden without a log title")
+	}
+}
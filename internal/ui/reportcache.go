@@ -0,0 +1,226 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// reportDetails holds the large sections of a JSON report undecoded until a
+// view needs them
+type reportDetails struct {
+	MovieDuplicates  json.RawMessage
+	TVDuplicates     json.RawMessage
+	ComplianceIssues json.RawMessage
+	LooseFiles       json.RawMessage
+}
+
+// reportHeader decodes a report without its detail sections; the outer
+// RawMessage fields shadow the embedded report's slices of the same name
+type reportHeader struct {
+	reporter.Report
+	MovieDuplicates  json.RawMessage
+	TVDuplicates     json.RawMessage
+	ComplianceIssues json.RawMessage
+	LooseFiles       json.RawMessage
+}
+
+// decode fills the detail sections of report
+func (d *reportDetails) decode(report *reporter.Report) error {
+	sections := []struct {
+		raw  json.RawMessage
+		into any
+	}{
+		{d.MovieDuplicates, &report.MovieDuplicates},
+		{d.TVDuplicates, &report.TVDuplicates},
+		{d.ComplianceIssues, &report.ComplianceIssues},
+		{d.LooseFiles, &report.LooseFiles},
+	}
+	for _, s := range sections {
+		if len(s.raw) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(s.raw, s.into); err != nil {
+			return fmt.Errorf("failed to parse report: %w", err)
+		}
+	}
+	return nil
+}
+
+// reportCacheEntry is a parsed report remembered for the rest of the session;
+// size and modification time detect rewrites such as MarkCleaned
+type reportCacheEntry struct {
+	modTime time.Time
+	size    int64
+	report  reporter.Report
+	details *reportDetails // nil once the detail sections are decoded
+}
+
+// reportCache memoizes parsed reports across menu navigation
+var reportCache = struct {
+	sync.Mutex
+	entries map[string]*reportCacheEntry
+}{entries: make(map[string]*reportCacheEntry)}
+
+// cachedReport returns the cache entry for path if the file is unchanged
+func cachedReport(path string, info os.FileInfo) (*reportCacheEntry, bool) {
+	reportCache.Lock()
+	defer reportCache.Unlock()
+	entry, ok := reportCache.entries[path]
+	if !ok || !entry.modTime.Equal(info.ModTime()) || entry.size != info.Size() {
+		return nil, false
+	}
+	return entry, true
+}
+
+// loadReportSummary reads the summary fields of a report, leaving the detail
+// sections undecoded; details is nil when the session already parsed them.
+// Low-memory mode streams the whole report in and caches nothing, since
+// the raw sections and cached reports would stay in memory for the session
+func loadReportSummary(path string) (reporter.Report, *reportDetails, error) {
+	if scanner.LowMemory() {
+		report, err := reporter.ReadReport(path)
+		return report, nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return reporter.Report{}, nil, fmt.Errorf("failed to read report: %w", err)
+	}
+	if entry, ok := cachedReport(path, info); ok {
+		return copyReport(entry.report), entry.details, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return reporter.Report{}, nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	var header reportHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return reporter.Report{}, nil, fmt.Errorf("failed to parse report: %w", err)
+	}
+	details := &reportDetails{
+		MovieDuplicates:  header.MovieDuplicates,
+		TVDuplicates:     header.TVDuplicates,
+		ComplianceIssues: header.ComplianceIssues,
+		LooseFiles:       header.LooseFiles,
+	}
+
+	reportCache.Lock()
+	reportCache.entries[path] = &reportCacheEntry{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		report:  header.Report,
+		details: details,
+	}
+	reportCache.Unlock()
+
+	return copyReport(header.Report), details, nil
+}
+
+// loadReportDetails decodes the detail sections of a summary loaded by
+// loadReportSummary and remembers the full report for the session
+func loadReportDetails(path string, report reporter.Report, details *reportDetails) (reporter.Report, error) {
+	if details == nil {
+		return report, nil
+	}
+	if err := details.decode(&report); err != nil {
+		return reporter.Report{}, err
+	}
+
+	reportCache.Lock()
+	if entry, ok := reportCache.entries[path]; ok && entry.details == details {
+		entry.report.MovieDuplicates = report.MovieDuplicates
+		entry.report.TVDuplicates = report.TVDuplicates
+		entry.report.ComplianceIssues = report.ComplianceIssues
+		entry.report.LooseFiles = report.LooseFiles
+		entry.details = nil
+	}
+	reportCache.Unlock()
+
+	return report, nil
+}
+
+// loadReportJSON loads a full report from a JSON file, reusing the session cache
+func loadReportJSON(path string) (reporter.Report, error) {
+	report, details, err := loadReportSummary(path)
+	if err != nil {
+		return reporter.Report{}, err
+	}
+	return loadReportDetails(path, report, details)
+}
+
+// copyReport gives a view its own ambiguous show and movie entries, which the
+// conflict and movie title reviews edit in place, so decisions never leak
+// into the cached report
+func copyReport(report reporter.Report) reporter.Report {
+	if report.AmbiguousTVShows != nil {
+		shows := make([]*scanner.TVTitleResolution, len(report.AmbiguousTVShows))
+		for i, show := range report.AmbiguousTVShows {
+			if show != nil {
+				c := *show
+				show = &c
+			}
+			shows[i] = show
+		}
+		report.AmbiguousTVShows = shows
+	}
+	if report.AmbiguousMovies != nil {
+		movies := make([]*scanner.MovieTitleResolution, len(report.AmbiguousMovies))
+		for i, movie := range report.AmbiguousMovies {
+			if movie != nil {
+				c := *movie
+				movie = &c
+			}
+			movies[i] = movie
+		}
+		report.AmbiguousMovies = movies
+	}
+	return report
+}
+
+// reportLoadedMsg carries a report whose summary has been read from disk
+type reportLoadedMsg struct {
+	path    string
+	report  reporter.Report
+	details *reportDetails // nil when the detail sections are already decoded
+	err     error
+}
+
+// reportDetailsMsg carries the decoded detail sections of a lazily loaded report
+type reportDetailsMsg struct {
+	path   string
+	report reporter.Report
+	err    error
+}
+
+// loadReportCmd reads a report summary off the UI goroutine
+func loadReportCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		report, details, err := loadReportSummary(path)
+		return reportLoadedMsg{path: path, report: report, details: details, err: err}
+	}
+}
+
+// newReportModel returns a report view showing the summary straight away;
+// its Init decodes the remaining sections in the background
+func newReportModel(msg reportLoadedMsg) Model {
+	m := NewModel(msg.report)
+	m.SetReportPath(msg.path)
+	m.details = msg.details
+	return m
+}
+
+// loadDetails decodes the pending detail sections of the model's report
+func (m Model) loadDetails() tea.Msg {
+	report, err := loadReportDetails(m.reportPath, m.report, m.details)
+	return reportDetailsMsg{path: m.reportPath, report: report, err: err}
+}
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/jellyfin"
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func writeReportFixture(t *testing.T, report reporter.Report) string {
+	t.Helper()
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func reportFixture() reporter.Report {
+	return reporter.Report{
+		Timestamp:       time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		LibraryType:     "tv",
+		LibraryPaths:    []string{"/media/tv"},
+		TotalDuplicates: 1,
+		MovieDuplicates: []scanner.MovieDuplicate{{NormalizedName: "heat", Year: "1995"}},
+		ComplianceIssues: []scanner.ComplianceIssue{
+			{Path: "/media/tv/firefly.s01e01.mkv", Problem: "Loose episode", Severity: scanner.IssueSeverityWarn},
+		},
+		AmbiguousTVShows: []*scanner.TVTitleResolution{{ResolvedTitle: "Degrassi"}},
+	}
+}
+
+func TestLoadReportSummaryIsLazyAndMemoized(t *testing.T) {
+	path := writeReportFixture(t, reportFixture())
+
+	report, details, err := loadReportSummary(path)
+	if err != nil {
+		t.Fatalf("loadReportSummary failed: %v", err)
+	}
+	if details == nil || report.ComplianceIssues != nil || report.MovieDuplicates != nil {
+		t.Fatal("Expected detail sections left undecoded")
+	}
+	if report.LibraryType != "tv" || report.TotalDuplicates != 1 || len(report.AmbiguousTVShows) != 1 {
+		t.Errorf("Expected summary fields decoded, got %+v", report)
+	}
+
+	// Conflict review edits resolutions in place; the cache must not see it
+	report.AmbiguousTVShows[0].UserDecision = scanner.DecisionFolderTitle
+
+	full, err := loadReportDetails(path, report, details)
+	if err != nil {
+		t.Fatalf("loadReportDetails failed: %v", err)
+	}
+	if len(full.ComplianceIssues) != 1 || len(full.MovieDuplicates) != 1 {
+		t.Errorf("Expected detail sections decoded, got %+v", full)
+	}
+
+	// Later navigation reuses the parsed report
+	again, details, err := loadReportSummary(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details != nil || len(again.ComplianceIssues) != 1 {
+		t.Error("Expected the fully parsed report from the session cache")
+	}
+	if again.AmbiguousTVShows[0].UserDecision != scanner.DecisionNone {
+		t.Error("Expected cached ambiguous shows isolated from view edits")
+	}
+
+	// Rewriting the file (e.g. MarkCleaned) invalidates the entry
+	changed := reportFixture()
+	changed.ComplianceIssues = append(changed.ComplianceIssues, scanner.ComplianceIssue{Path: "/media/tv/other.mkv"})
+	data, _ := json.Marshal(changed)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := loadReportJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.ComplianceIssues) != 2 {
+		t.Errorf("Expected rewritten report reloaded, got %d issues", len(reloaded.ComplianceIssues))
+	}
+}
+
+func TestReportModelShowsSummaryBeforeDetails(t *testing.T) {
+	path := writeReportFixture(t, reportFixture())
+	report, details, err := loadReportSummary(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := newReportModel(reportLoadedMsg{path: path, report: report, details: details})
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = model.(Model)
+	summary := m.renderSummary()
+	if !strings.Contains(summary, "Loading report details...") || strings.Contains(summary, "firefly.s01e01") {
+		t.Error("Expected summary rendered with details pending")
+	}
+
+	// Cleaning needs the duplicates, so it waits for the details
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.(Model).mode != ViewSummary {
+		t.Error("Expected clean options unavailable while details load")
+	}
+
+	cmd := m.Init()
+	if cmd == nil {
+		t.Fatal("Expected Init to load the detail sections")
+	}
+	model, _ = m.Update(cmd())
+	m = model.(Model)
+	if m.details != nil || !strings.Contains(m.renderSummary(), "firefly.s01e01") {
+		t.Error("Expected details merged into the summary")
+	}
+	if m.Init() != nil {
+		t.Error("Expected nothing left to load")
+	}
+}
+
+func TestJellyfinFixesView(t *testing.T) {
+	report := reportFixture()
+	m := NewModel(report)
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyF5})
+	if model.(Model).mode != ViewSummary {
+		t.Error("Expected F5 ignored without Jellyfin findings")
+	}
+
+	report.Jellyfin = &jellyfin.Comparison{MissingFromServer: []string{"/media/tv/other.mkv", "/media/tv/firefly.s01e01.mkv"}}
+	m = NewModel(report)
+	model, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if !strings.Contains(model.View(), "F5 Jellyfin") {
+		t.Error("Expected F5 in the summary footer")
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyF5})
+	m = model.(Model)
+	if m.mode != ViewJellyfinFixes {
+		t.Fatal("Expected F5 to open the Jellyfin fixes view")
+	}
+	view := m.renderJellyfinFixes()
+	fixed, manual := strings.Index(view, "firefly.s01e01.mkv"), strings.Index(view, "other.mkv")
+	if fixed < 0 || manual < 0 || fixed > manual {
+		t.Errorf("Expected the file with a compliance fix listed first:\n%s", view)
+	}
+}
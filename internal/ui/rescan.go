@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+	"github.com/Nomadcxx/jellysink/internal/tags"
+)
+
+// Libraries the report view rescans changed folders in
+var (
+	rescanMovies, rescanTV []string
+	rescanMu               sync.RWMutex
+)
+
+// SetLibraryPaths sets the movie and TV libraries in which the report view
+// rescans the folders a clean or batch rename changed. Without any, the
+// report is left as scanned
+func SetLibraryPaths(movies, tv []string) {
+	rescanMu.Lock()
+	defer rescanMu.Unlock()
+	rescanMovies, rescanTV = movies, tv
+}
+
+// libraryPaths returns the libraries set with SetLibraryPaths
+func libraryPaths() (movies, tv []string) {
+	rescanMu.RLock()
+	defer rescanMu.RUnlock()
+	return rescanMovies, rescanTV
+}
+
+// rescanCompleteMsg carries the findings of a rescan of changed folders;
+// result is nil when none of them is in a library
+type rescanCompleteMsg struct {
+	result *scanner.ScanResult
+	err    error
+}
+
+// startRescan rescans the folders holding paths off the UI goroutine so
+// the open report shows what a clean or rename left. Returns nil when
+// there is nothing to rescan
+func (m *Model) startRescan(paths []string) tea.Cmd {
+	movies, tv := libraryPaths()
+	if len(paths) == 0 || len(movies)+len(tv) == 0 || m.report.Simulated {
+		return nil
+	}
+	previous := &scanner.ScanResult{
+		MovieDuplicates:  m.report.MovieDuplicates,
+		TVDuplicates:     m.report.TVDuplicates,
+		ComplianceIssues: m.report.ComplianceIssues,
+		AmbiguousTVShows: m.report.AmbiguousTVShows,
+		AmbiguousMovies:  m.report.AmbiguousMovies,
+		OrphanFolders:    m.report.OrphanFolders,
+		Artifacts:        m.report.Artifacts,
+		Quality:          m.report.Quality,
+		Completeness:     m.report.Completeness,
+	}
+
+	m.rescanning = true
+	m.rescanStatus = InfoStyle.Render("Rescanning the changed folders...")
+	return func() tea.Msg {
+		result, err := scanner.RescanFolders(context.Background(), movies, tv, paths, previous, nil)
+		return rescanCompleteMsg{result: result, err: err}
+	}
+}
+
+// applyRescan replaces the report's findings with those of a rescan
+func (m *Model) applyRescan(msg rescanCompleteMsg) {
+	m.rescanning = false
+	switch {
+	case msg.err != nil:
+		m.rescanStatus = WarningStyle.Render(fmt.Sprintf("⚠ Could not rescan the changed folders: %v (run a new scan to confirm the changes)", msg.err))
+		return
+	case msg.result == nil:
+		m.rescanStatus = ""
+		return
+	}
+
+	result := msg.result
+	m.report.MovieDuplicates = result.MovieDuplicates
+	m.report.TVDuplicates = result.TVDuplicates
+	m.report.ComplianceIssues = result.ComplianceIssues
+	m.report.AmbiguousTVShows = result.AmbiguousTVShows
+	m.report.AmbiguousMovies = result.AmbiguousMovies
+	m.report.OrphanFolders = result.OrphanFolders
+	m.report.Artifacts = result.Artifacts
+	m.report.Quality = result.Quality
+	m.report.Completeness = result.Completeness
+	m.report.TotalDuplicates = result.TotalDuplicates
+	m.report.TotalFilesToDelete = result.TotalFilesToDelete
+	m.report.SpaceToFree = result.SpaceToFree
+	reporter.ApplyKeepTags(&m.report, tags.CurrentRules())
+
+	m.conflicts = make([]*scanner.TVTitleResolution, len(m.report.AmbiguousTVShows))
+	copy(m.conflicts, m.report.AmbiguousTVShows)
+	m.currentConflictIndex = 0
+	m.batchReviewCursor = 0
+	m.editedTitles = make(map[int]string)
+
+	m.rescanStatus = SuccessStyle.Render(fmt.Sprintf("✓ Changed folders rescanned: %d duplicate group(s), %d compliance issue(s) and %d show(s) to review remain",
+		m.report.TotalDuplicates, len(m.report.ComplianceIssues), len(m.report.AmbiguousTVShows)))
+}
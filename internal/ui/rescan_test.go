@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestCleanRescansChangedFolders(t *testing.T) {
+	movies := filepath.Join(t.TempDir(), "movies")
+	for _, rel := range []string{
+		"Heat (1995)/Heat (1995).mkv",
+		"Heat.1995.720p-GRP/Heat.1995.720p-GRP.mkv",
+		"Big.Fish.2003-GRP/Big.Fish.2003-GRP.mkv",
+	} {
+		path := filepath.Join(movies, rel)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(rel), 0644)
+	}
+	scan, err := scanner.RunFullScan(context.Background(), []string{movies}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scan.MovieDuplicates) != 1 || len(scan.ComplianceIssues) == 0 {
+		t.Fatalf("Expected a duplicate group and compliance issues, got %+v", scan)
+	}
+	report := reporter.Report{
+		MovieDuplicates:  scan.MovieDuplicates,
+		ComplianceIssues: scan.ComplianceIssues,
+		TotalDuplicates:  scan.TotalDuplicates,
+	}
+
+	SetLibraryPaths([]string{movies}, nil)
+	t.Cleanup(func() { SetLibraryPaths(nil, nil) })
+
+	m := NewModel(report)
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	// A clean deleted the extra Heat copy
+	removed := filepath.Join(movies, "Heat.1995.720p-GRP", "Heat.1995.720p-GRP.mkv")
+	os.RemoveAll(filepath.Dir(removed))
+	model, cmd := model.Update(cleanCompleteMsg{result: "done", changed: []string{removed}})
+	if cmd == nil {
+		t.Fatal("Expected a rescan after the clean")
+	}
+	if !model.(Model).rescanning {
+		t.Error("Expected the rescan marked as running")
+	}
+
+	model, _ = model.Update(cmd())
+	m = model.(Model)
+	if m.rescanning || len(m.report.MovieDuplicates) != 0 || m.report.TotalDuplicates != 0 {
+		t.Errorf("Expected the resolved duplicates gone from the report, got %+v", m.report.MovieDuplicates)
+	}
+	// Findings in folders the clean did not touch stand
+	if len(m.report.ComplianceIssues) == 0 {
+		t.Error("Expected the untouched compliance issues kept")
+	}
+	if !strings.Contains(m.renderSummary(), "Changed folders rescanned") {
+		t.Error("Expected the summary to say the report was updated")
+	}
+
+	// Dry runs and paths outside the libraries change nothing
+	if _, cmd := model.Update(cleanCompleteMsg{result: "preview"}); cmd != nil {
+		t.Error("Expected no rescan without changed paths")
+	}
+	_, cmd = model.Update(cleanCompleteMsg{changed: []string{"/elsewhere/file.mkv"}})
+	if model, _ = model.Update(cmd()); model.(Model).rescanStatus != "" {
+		t.Errorf("Expected no status without a rescan, got %q", model.(Model).rescanStatus)
+	}
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+)
+
+// Router is the root model of the menu TUI. It keeps a stack of screens and
+// remembers the terminal size, sending it to every screen it shows so
+// screens never copy width/height between each other
+type Router struct {
+	stack   []tea.Model
+	size    tea.WindowSizeMsg
+	hasSize bool
+}
+
+// navPushMsg opens a screen on top of the current one
+type navPushMsg struct{ model tea.Model }
+
+// navPopMsg returns to the previous screen
+type navPopMsg struct{}
+
+// navReplaceMsg swaps the current screen for another
+type navReplaceMsg struct{ model tea.Model }
+
+// Push opens model on top of the current screen
+func Push(model tea.Model) tea.Cmd {
+	return func() tea.Msg { return navPushMsg{model: model} }
+}
+
+// Pop returns to the previous screen; popping the last screen quits
+func Pop() tea.Cmd {
+	return func() tea.Msg { return navPopMsg{} }
+}
+
+// Replace swaps the current screen for model, e.g. scan progress -> report
+func Replace(model tea.Model) tea.Cmd {
+	return func() tea.Msg { return navReplaceMsg{model: model} }
+}
+
+// NewRouter starts a navigation stack with root as the bottom screen
+func NewRouter(root tea.Model) Router {
+	return Router{stack: []tea.Model{root}}
+}
+
+// NewMenuRouter returns the main menu wrapped in a router, ready for tea.NewProgram
+func NewMenuRouter(cfg *config.Config) Router {
+	return NewRouter(NewMenuModel(cfg))
+}
+
+// Init initializes the root screen
+func (r Router) Init() tea.Cmd {
+	return r.top().Init()
+}
+
+// Update handles navigation and forwards everything else to the top screen
+func (r Router) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		r.size = msg
+		r.hasSize = true
+
+	case navPushMsg:
+		r.stack = append(r.stack, msg.model)
+		return r, r.show(msg.model.Init())
+
+	case navPopMsg:
+		if len(r.stack) <= 1 {
+			return r, tea.Quit
+		}
+		r.stack = r.stack[:len(r.stack)-1]
+		return r, r.show(nil)
+
+	case navReplaceMsg:
+		r.stack[len(r.stack)-1] = msg.model
+		return r, r.show(msg.model.Init())
+	}
+
+	top, cmd := r.top().Update(msg)
+	r.stack[len(r.stack)-1] = top
+	return r, cmd
+}
+
+// View renders the top screen
+func (r Router) View() string {
+	return r.top().View()
+}
+
+// Depth returns the number of screens on the stack
+func (r Router) Depth() int {
+	return len(r.stack)
+}
+
+// Top returns the screen currently shown
+func (r Router) Top() tea.Model {
+	return r.top()
+}
+
+func (r Router) top() tea.Model {
+	return r.stack[len(r.stack)-1]
+}
+
+// show sizes the newly revealed top screen before it is drawn
+func (r *Router) show(initCmd tea.Cmd) tea.Cmd {
+	if !r.hasSize {
+		return initCmd
+	}
+	top, cmd := r.top().Update(r.size)
+	r.stack[len(r.stack)-1] = top
+	return tea.Batch(initCmd, cmd)
+}
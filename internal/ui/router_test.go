@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+)
+
+// routerRun feeds msg to the router and follows navigation commands
+func routerRun(t *testing.T, r Router, msg tea.Msg) Router {
+	t.Helper()
+	model, cmd := r.Update(msg)
+	r = model.(Router)
+	if cmd == nil {
+		return r
+	}
+	switch next := cmd().(type) {
+	case navPushMsg, navPopMsg, navReplaceMsg:
+		return routerRun(t, r, next)
+	}
+	return r
+}
+
+func TestRouterSizesEveryScreen(t *testing.T) {
+	cfg := config.DefaultConfig()
+	r := NewMenuRouter(cfg)
+	r = routerRun(t, r, tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	r = routerRun(t, r, navPushMsg{model: NewLibraryMenuModel(cfg)})
+	r = routerRun(t, r, navPushMsg{model: NewAddPathModel(cfg, "movie")})
+	if r.Depth() != 3 {
+		t.Fatalf("Expected 3 screens on the stack, got %d", r.Depth())
+	}
+	if add := r.Top().(AddPathModel); add.width != 120 || add.height != 40 {
+		t.Errorf("Pushed screen not sized: %dx%d", add.width, add.height)
+	}
+
+	// Resize while the child is shown, then go back
+	r = routerRun(t, r, tea.WindowSizeMsg{Width: 100, Height: 30})
+	r = routerRun(t, r, tea.KeyMsg{Type: tea.KeyEsc})
+	lib, ok := r.Top().(LibraryMenuModel)
+	if !ok {
+		t.Fatalf("Expected library menu after esc, got %T", r.Top())
+	}
+	if lib.width != 100 || lib.height != 30 {
+		t.Errorf("Revealed screen kept stale size: %dx%d", lib.width, lib.height)
+	}
+
+	r = routerRun(t, r, tea.KeyMsg{Type: tea.KeyEsc})
+	if menu, ok := r.Top().(MenuModel); !ok || menu.width != 100 || r.Depth() != 1 {
+		t.Errorf("Expected sized main menu at the root, got %T (depth %d)", r.Top(), r.Depth())
+	}
+}
+
+func TestRouterReplaceAndQuit(t *testing.T) {
+	cfg := config.DefaultConfig()
+	r := NewMenuRouter(cfg)
+	r = routerRun(t, r, tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	r = routerRun(t, r, navPushMsg{model: NewFrequencyMenuModel(cfg)})
+	r = routerRun(t, r, navReplaceMsg{model: NewDaemonMenuModel(cfg)})
+	if d, ok := r.Top().(DaemonMenuModel); !ok || d.width != 80 || r.Depth() != 2 {
+		t.Fatalf("Expected sized daemon menu replacing frequency menu, got %T (depth %d)", r.Top(), r.Depth())
+	}
+
+	r = routerRun(t, r, navPopMsg{})
+	_, cmd := r.Update(navPopMsg{})
+	if cmd == nil {
+		t.Fatal("Expected quit when popping the root screen")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("Expected tea.QuitMsg when popping the root screen")
+	}
+}
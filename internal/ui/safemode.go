@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+)
+
+// safeModeDisabledItems are the main menu entries that read the config;
+// safe mode lists them but refuses them until config.toml validates
+var safeModeDisabledItems = map[string]bool{
+	"Run Manual Scan":        true,
+	"Attach to Running Scan": true,
+	"View Last Report":       true,
+	"Manage Backups":         true,
+	"Undo Last Clean":        true,
+}
+
+// configCheckedMsg carries the config re-read after an edit
+type configCheckedMsg struct {
+	cfg *config.Config
+	err error
+}
+
+// SafeModeModel is the restricted main menu shown when config.toml doesn't
+// load or validate. It shows the error, opens the file in an editor and
+// re-checks it after each edit; scans and cleans stay off until it
+// validates, then the normal main menu takes over
+type SafeModeModel struct {
+	list   list.Model
+	path   string               // config.toml
+	err    error                // why the config doesn't load or validate
+	apply  func(*config.Config) // installs a config once it validates
+	notice string               // outcome of the last action
+	width  int
+	height int
+}
+
+// NewSafeModeRouter returns the safe mode menu for the config at path,
+// which failed with err, wrapped in a router. apply is called with the
+// config once it validates, before the main menu opens
+func NewSafeModeRouter(path string, err error, apply func(*config.Config)) Router {
+	return NewRouter(NewSafeModeModel(path, err, apply))
+}
+
+// NewSafeModeModel creates the safe mode menu
+func NewSafeModeModel(path string, err error, apply func(*config.Config)) SafeModeModel {
+	items := []list.Item{
+		MenuItem{title: "Edit Config File", desc: "Open config.toml in $EDITOR; it is checked again when you save and quit"},
+		MenuItem{title: "Check Again", desc: "Re-read config.toml after editing it elsewhere"},
+	}
+	for _, title := range []string{"Run Manual Scan", "Attach to Running Scan", "View Last Report", "Manage Backups", "Undo Last Clean"} {
+		items = append(items, MenuItem{title: title, desc: "Disabled until config.toml validates"})
+	}
+	items = append(items, MenuItem{title: "Exit", desc: "Quit jellysink"})
+
+	// Create delegate with RAMA theme styling
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = lipgloss.NewStyle().
+		Foreground(RAMABackground).
+		Background(RAMARed).
+		Bold(true)
+	delegate.Styles.SelectedDesc = lipgloss.NewStyle().
+		Foreground(RAMABackground).
+		Background(RAMAFireRed)
+	delegate.Styles.NormalTitle = lipgloss.NewStyle().
+		Foreground(RAMAForeground)
+	delegate.Styles.NormalDesc = lipgloss.NewStyle().
+		Foreground(RAMAMuted)
+
+	l := list.New(items, delegate, 80, 12)
+	l.Title = "JELLYSINK SAFE MODE"
+	l.Styles.Title = TitleStyle
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowTitle(true)
+
+	return SafeModeModel{list: l, path: path, err: err, apply: apply}
+}
+
+func (m SafeModeModel) Init() tea.Cmd {
+	return nil
+}
+
+// checkConfig reads config.toml again and validates it
+func checkConfig() tea.Msg {
+	cfg, err := config.Load()
+	if err == nil {
+		err = cfg.Validate()
+	}
+	return configCheckedMsg{cfg: cfg, err: err}
+}
+
+// configEditor returns the command that edits path: $VISUAL, $EDITOR, or
+// nano or vi when neither is set
+func configEditor(path string) *exec.Cmd {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return exec.Command(fields[0], append(fields[1:], path)...)
+		}
+	}
+	if _, err := exec.LookPath("nano"); err == nil {
+		return exec.Command("nano", path)
+	}
+	return exec.Command("vi", path)
+}
+
+func (m SafeModeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "enter":
+			return m.handleSelection(m.list.SelectedItem().(MenuItem).title)
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.list.SetSize(msg.Width-4, 12)
+		return m, nil
+
+	case configCheckedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.notice = ""
+			return m, nil
+		}
+		if m.apply != nil {
+			m.apply(msg.cfg)
+		}
+		return m, Replace(NewMenuModel(msg.cfg))
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+// handleSelection processes safe mode menu selections
+func (m SafeModeModel) handleSelection(title string) (tea.Model, tea.Cmd) {
+	switch {
+	case title == "Edit Config File":
+		return m, tea.ExecProcess(configEditor(m.path), func(err error) tea.Msg {
+			if err != nil {
+				return configCheckedMsg{err: fmt.Errorf("editor failed: %w", err)}
+			}
+			return checkConfig()
+		})
+	case title == "Check Again":
+		return m, checkConfig
+	case safeModeDisabledItems[title]:
+		m.notice = fmt.Sprintf("%s is disabled until config.toml validates", title)
+	case title == "Exit":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m SafeModeModel) View() string {
+	var content strings.Builder
+
+	content.WriteString(Banner(m.width))
+	content.WriteString("\n\n")
+	content.WriteString(FormatStatusFail("config.toml has errors: jellysink started in safe mode") + "\n\n")
+	content.WriteString(InfoStyle.Render("File: ") + ContentStyle.Render(m.path) + "\n")
+	content.WriteString(InfoStyle.Render("Error: ") + ErrorStyle.Render(m.err.Error()) + "\n\n")
+	content.WriteString(MutedStyle.Render("Nothing is scanned, cleaned or written until the file validates. Fix it and the main menu opens.") + "\n\n")
+
+	content.WriteString(m.list.View())
+	content.WriteString("\n\n")
+	if m.notice != "" {
+		content.WriteString(FormatStatusWarn(m.notice) + "\n\n")
+	}
+	content.WriteString(MutedStyle.Render("↑/↓: Navigate  •  Enter: Select  •  Q/Ctrl+C: Quit"))
+
+	return lipgloss.NewStyle().Padding(1, 2).Width(m.width - 4).Render(content.String())
+}
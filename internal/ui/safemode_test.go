@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+)
+
+func TestSafeModeUntilConfigValidates(t *testing.T) {
+	var applied *config.Config
+	r := NewSafeModeRouter("/home/user/.config/jellysink/config.toml",
+		errors.New("invalid scan frequency: hourly (must be daily, weekly, or biweekly)"),
+		func(cfg *config.Config) { applied = cfg })
+	r = routerRun(t, r, tea.WindowSizeMsg{Width: 120, Height: 40})
+	if view := r.View(); !strings.Contains(view, "invalid scan frequency: hourly") {
+		t.Fatalf("Expected the validation error in safe mode:\n%s", view)
+	}
+
+	// Down twice to "Run Manual Scan"
+	r = routerRun(t, r, tea.KeyMsg{Type: tea.KeyDown})
+	r = routerRun(t, r, tea.KeyMsg{Type: tea.KeyDown})
+	r = routerRun(t, r, tea.KeyMsg{Type: tea.KeyEnter})
+	if _, ok := r.Top().(SafeModeModel); !ok || r.Depth() != 1 {
+		t.Fatalf("Expected scans refused in safe mode, got %T", r.Top())
+	}
+	if view := r.View(); !strings.Contains(view, "Run Manual Scan is disabled") {
+		t.Errorf("Expected the refusal shown:\n%s", view)
+	}
+
+	r = routerRun(t, r, configCheckedMsg{err: errors.New("invalid scan time: 25:00 (must be HH:MM, 24-hour)")})
+	if view := r.View(); !strings.Contains(view, "invalid scan time: 25:00") || applied != nil {
+		t.Errorf("Expected the new error after a failed check:\n%s", view)
+	}
+
+	cfg := config.DefaultConfig()
+	r = routerRun(t, r, configCheckedMsg{cfg: cfg})
+	if _, ok := r.Top().(MenuModel); !ok || applied != cfg {
+		t.Errorf("Expected the main menu once the config validates, got %T", r.Top())
+	}
+}
@@ -0,0 +1,324 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// statsLargestItems is how many of the biggest files are listed per library
+const statsLargestItems = 5
+
+// staleScanAge is when a library's last scan is flagged as stale
+const staleScanAge = 7 * 24 * time.Hour
+
+// statsIncompleteShows is how many of the least complete shows are listed
+// per TV library
+const statsIncompleteShows = 10
+
+// libraryStatsMsg carries the collected statistics back to the model
+type libraryStatsMsg struct {
+	stats    []scanner.LibraryStats
+	failures []string // Libraries that could not be read, in config order
+	lastScan time.Time
+	scanned  map[string]bool // Library paths covered by the last report
+
+	completeness []scanner.ShowCompleteness // from the last report, when it had a TVDB key
+}
+
+// LibraryStatsModel shows per-library totals and quality breakdowns
+type LibraryStatsModel struct {
+	viewport viewport.Model
+	config   *config.Config
+	width    int
+	height   int
+	ready    bool
+	loading  bool
+	result   libraryStatsMsg
+}
+
+// NewLibraryStatsModel creates the library statistics screen
+func NewLibraryStatsModel(cfg *config.Config) LibraryStatsModel {
+	return LibraryStatsModel{
+		config:  cfg,
+		loading: true,
+	}
+}
+
+func (m LibraryStatsModel) Init() tea.Cmd {
+	return m.collectStats
+}
+
+// collectStats walks every configured library and reads the last report for freshness
+func (m LibraryStatsModel) collectStats() tea.Msg {
+	msg := libraryStatsMsg{
+		scanned: make(map[string]bool),
+	}
+
+	collect := func(paths []string, libraryType string) {
+		for _, path := range paths {
+			stats, err := scanner.CollectLibraryStats(path, libraryType, statsLargestItems)
+			if err != nil {
+				msg.failures = append(msg.failures, fmt.Sprintf("%s: %v", path, err))
+				continue
+			}
+			msg.stats = append(msg.stats, stats)
+		}
+	}
+	collect(m.config.MoviePaths(), "movies")
+	collect(m.config.ShowPaths(), "tv")
+
+	// Freshness comes from the last report; a missing report just means never scanned
+	if reportPath, err := findLatestReport(); err == nil {
+		if report, _, err := loadReportSummary(reportPath); err == nil {
+			msg.lastScan = report.Timestamp
+			msg.completeness = report.Completeness
+			for _, path := range report.LibraryPaths {
+				msg.scanned[path] = true
+			}
+		}
+	}
+
+	return msg
+}
+
+func (m LibraryStatsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			return m, Pop()
+		case "r":
+			if !m.loading {
+				m.loading = true
+				m.resizeViewport()
+				return m, m.collectStats
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.resizeViewport()
+		return m, nil
+
+	case libraryStatsMsg:
+		m.loading = false
+		m.result = msg
+		m.resizeViewport()
+		return m, nil
+	}
+
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// resizeViewport creates or resizes the viewport once dimensions are known
+func (m *LibraryStatsModel) resizeViewport() {
+	if m.width == 0 || m.height == 0 {
+		return
+	}
+
+	headerHeight := 15 - bannerSaved(m.width) // banner + title + padding
+	footerHeight := 4                         // Help text + padding
+	if !m.ready {
+		m.viewport = viewport.New(m.width-4, m.height-headerHeight-footerHeight)
+		m.viewport.Style = lipgloss.NewStyle().Padding(0, 1)
+		m.ready = true
+	} else {
+		m.viewport.Width = m.width - 4
+		m.viewport.Height = m.height - headerHeight - footerHeight
+	}
+	m.viewport.SetContent(m.buildStats())
+}
+
+// buildStats renders every library section
+func (m LibraryStatsModel) buildStats() string {
+	if m.loading {
+		return MutedStyle.Render("Collecting library statistics...")
+	}
+
+	var b strings.Builder
+
+	if len(m.result.stats) == 0 && len(m.result.failures) == 0 {
+		b.WriteString(FormatStatusInfo("No libraries configured") + "\n")
+		return b.String()
+	}
+
+	var totalFiles int
+	var totalSize int64
+	for _, stats := range m.result.stats {
+		totalFiles += stats.FileCount
+		totalSize += stats.TotalSize
+	}
+	b.WriteString(StatStyle.Render(fmt.Sprintf("All libraries: %d files, %s", totalFiles, formatBytes(totalSize))) + "\n")
+	b.WriteString(m.freshnessLine("") + "\n\n")
+
+	for _, stats := range m.result.stats {
+		label := "Movies"
+		if stats.LibraryType == "tv" {
+			label = "TV"
+		}
+		b.WriteString(InfoStyle.Render(fmt.Sprintf("%s: %s", label, stats.Path)) + "\n")
+		b.WriteString(fmt.Sprintf("  Files: %d   Size: %s\n", stats.FileCount, formatBytes(stats.TotalSize)))
+		b.WriteString("  " + m.freshnessLine(stats.Path) + "\n")
+		b.WriteString(formatBreakdown("Resolution", stats.ByResolution, stats.FileCount))
+		b.WriteString(formatBreakdown("Codec", stats.ByCodec, stats.FileCount))
+		b.WriteString(formatBreakdown("Container", stats.ByContainer, stats.FileCount))
+		if stats.LibraryType == "tv" {
+			b.WriteString(m.completenessLines(stats.Path))
+		}
+
+		if len(stats.Largest) > 0 {
+			b.WriteString("  Largest:\n")
+			for _, item := range stats.Largest {
+				b.WriteString(fmt.Sprintf("    %10s  %s\n", formatBytes(item.Size), MutedStyle.Render(item.Path)))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	for _, failure := range m.result.failures {
+		b.WriteString(FormatStatusFail(failure) + "\n")
+	}
+
+	return b.String()
+}
+
+// freshnessLine describes how recently a library (or any library, for "") was scanned
+func (m LibraryStatsModel) freshnessLine(path string) string {
+	if m.result.lastScan.IsZero() {
+		return FormatStatusWarn("Never scanned")
+	}
+	if path != "" && !m.result.scanned[path] {
+		return FormatStatusWarn("Not included in the last scan")
+	}
+
+	age := time.Since(m.result.lastScan)
+	text := fmt.Sprintf("Last scan: %s (%s ago)", m.result.lastScan.Format("2006-01-02 15:04"), formatAge(age))
+	if age > staleScanAge {
+		return FormatStatusWarn(text)
+	}
+	return FormatStatusOK(text)
+}
+
+// completenessLines renders how many of the aired episodes of a TV
+// library's shows the last scan found, with the least complete shows
+func (m LibraryStatsModel) completenessLines(path string) string {
+	var shows []scanner.ShowCompleteness
+	var have, aired int
+	complete := 0
+	for _, show := range m.result.completeness {
+		if filepath.Dir(show.Path) != path {
+			continue
+		}
+		have += show.Have
+		aired += show.Aired
+		if show.Complete() {
+			complete++
+		} else {
+			shows = append(shows, show)
+		}
+	}
+	total := complete + len(shows)
+	if total == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("  %-11s %.0f%% of aired episodes, %d of %d shows complete\n",
+		"Complete:", float64(have)*100/float64(aired), complete, total))
+	sort.SliceStable(shows, func(i, j int) bool {
+		return shows[i].Percent() < shows[j].Percent()
+	})
+	if len(shows) > statsIncompleteShows {
+		shows = shows[:statsIncompleteShows]
+	}
+	for _, show := range shows {
+		b.WriteString(fmt.Sprintf("    %4.0f%%  %s %s\n", show.Percent(), filepath.Base(show.Path),
+			MutedStyle.Render(fmt.Sprintf("(%d of %d)", show.Have, show.Aired))))
+	}
+	return b.String()
+}
+
+// formatBreakdown renders one "Label: a 60%, b 40%" line, most common first
+func formatBreakdown(label string, counts map[string]int, total int) string {
+	if total == 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, key := range scanner.SortedCounts(counts) {
+		parts = append(parts, fmt.Sprintf("%s %d (%.0f%%)", key, counts[key], float64(counts[key])/float64(total)*100))
+	}
+	return fmt.Sprintf("  %-11s %s\n", label+":", strings.Join(parts, ", "))
+}
+
+// formatAge renders a duration as a short human age (3m, 5h, 2d)
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+func (m LibraryStatsModel) View() string {
+	// Minimum dimensions check
+	minWidth := MinScreenWidth
+	minHeight := minScreenHeight(m.width)
+
+	if m.width > 0 && m.height > 0 && (m.width < minWidth || m.height < minHeight) {
+		warningStyle := lipgloss.NewStyle().
+			Foreground(ColorWarning).
+			Bold(true).
+			Align(lipgloss.Center, lipgloss.Center).
+			Width(m.width).
+			Height(m.height)
+
+		warning := fmt.Sprintf(
+			"Terminal too small!\n\nMinimum: %dx%d\nCurrent: %dx%d\n\nPlease resize your terminal.",
+			minWidth, minHeight, m.width, m.height,
+		)
+		return warningStyle.Render(warning)
+	}
+
+	var content strings.Builder
+
+	// Show the banner
+	content.WriteString(Banner(m.width))
+	content.WriteString("\n\n")
+
+	content.WriteString(TitleStyle.Render("LIBRARY STATISTICS") + "\n\n")
+
+	if m.ready {
+		content.WriteString(m.viewport.View())
+	} else {
+		content.WriteString(m.buildStats())
+	}
+	content.WriteString("\n\n")
+
+	footer := MutedStyle.Render("↑/↓/PgUp/PgDn: Scroll  •  R: Refresh  •  Esc: Back  •  Q/Ctrl+C: Quit")
+	content.WriteString(footer)
+
+	// Wrap in padding
+	mainStyle := lipgloss.NewStyle().
+		Padding(1, 2).
+		Width(m.width - 4)
+
+	return mainStyle.Render(content.String())
+}
@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/tags"
+)
+
+// tagCursorMark flags the file or folder the tag prompt applies to
+const tagCursorMark = "▶"
+
+// taggablePaths lists the paths the tag cursor walks in the current view:
+// every duplicate file, or every visible compliance issue
+func (m Model) taggablePaths() []string {
+	var paths []string
+	switch m.mode {
+	case ViewDuplicates:
+		for _, dup := range m.report.MovieDuplicates {
+			for _, file := range dup.Files {
+				paths = append(paths, file.Path)
+			}
+		}
+		for _, dup := range m.report.TVDuplicates {
+			for _, file := range dup.Files {
+				paths = append(paths, file.Path)
+			}
+		}
+	case ViewCompliance:
+		for _, issue := range m.visibleComplianceIssues() {
+			paths = append(paths, issue.Path)
+		}
+	}
+	return paths
+}
+
+// tagPrefix returns the cursor mark for the index-th taggable path
+func (m Model) tagPrefix(index int) string {
+	if index == m.tagCursor {
+		return HighlightStyle.Render(tagCursorMark)
+	}
+	return " "
+}
+
+// tagSuffix lists the tags on path (including its folders' tags)
+func (m Model) tagSuffix(path string) string {
+	rules := tags.CurrentRules()
+	if rules == nil {
+		return ""
+	}
+	list := rules.Store.Effective(path)
+	if len(list) == 0 {
+		return ""
+	}
+	return " " + InfoStyle.Render("#"+strings.Join(list, " #"))
+}
+
+// renderTagPrompt shows the tag input or the outcome of the last edit
+func (m Model) renderTagPrompt() string {
+	if m.editingTag {
+		paths := m.taggablePaths()
+		if m.tagCursor >= 0 && m.tagCursor < len(paths) {
+			return HighlightStyle.Render("Tag "+paths[m.tagCursor]) + "\n" +
+				m.tagInput.View() + "\n" +
+				MutedStyle.Render("Space-separated tags; prefix a tag with - to remove it") + "\n\n"
+		}
+	}
+	if m.tagStatus != "" {
+		return m.tagStatus + "\n\n"
+	}
+	return ""
+}
+
+// moveTagCursor steps the tag cursor, wrapping at either end
+func (m *Model) moveTagCursor(delta int) {
+	n := len(m.taggablePaths())
+	if n == 0 {
+		return
+	}
+	if m.tagCursor < 0 {
+		m.tagCursor = 0
+	} else {
+		m.tagCursor = (m.tagCursor + delta + n) % n
+	}
+	m.tagStatus = ""
+	m.refreshTagView()
+}
+
+// titleTag reports whether tag applies to a whole show or movie folder
+func titleTag(tag string) bool {
+	return tag == tags.FreezeTag || tag == tags.KeepAllTag || tag == tags.KeepSmallestTag
+}
+
+// applyTagInput adds (or, with a - prefix, removes) each tag in value on
+// the selected path, saves the store and re-applies the keep tags
+func (m *Model) applyTagInput(value string) {
+	paths := m.taggablePaths()
+	if m.tagCursor < 0 || m.tagCursor >= len(paths) {
+		return
+	}
+	rules := tags.CurrentRules()
+	if rules == nil || rules.Store == nil {
+		m.tagStatus = ErrorStyle.Render("Tags are not available (no tag store loaded)")
+		return
+	}
+	path := paths[m.tagCursor]
+	// Shows and movies are frozen, and take a policy, as a whole
+	folder := titleFolder(path, m.report.LibraryPaths)
+
+	for _, tag := range strings.Fields(value) {
+		if strings.HasPrefix(tag, "-") {
+			tag = strings.TrimPrefix(tag, "-")
+			rules.Store.Remove(path, tag)
+			if titleTag(tag) {
+				rules.Store.Remove(folder, tag)
+			}
+			continue
+		}
+		target := path
+		if titleTag(tag) {
+			target = folder
+		}
+		if err := rules.Store.Add(target, tag); err != nil {
+			m.tagStatus = ErrorStyle.Render(err.Error())
+			return
+		}
+	}
+	if err := rules.Store.Save(); err != nil {
+		m.tagStatus = ErrorStyle.Render(fmt.Sprintf("Failed to save tags: %v", err))
+		return
+	}
+
+	// A new keeper reorders its group; keep the cursor on the tagged path
+	reporter.ApplyKeepTags(&m.report, rules)
+	for i, p := range m.taggablePaths() {
+		if p == path {
+			m.tagCursor = i
+			break
+		}
+	}
+	m.tagStatus = SuccessStyle.Render(fmt.Sprintf("✓ %s: %s", path, formatTagList(rules.Store.Effective(path))))
+	if rules.Frozen(path) {
+		m.tagStatus += "\n" + MutedStyle.Render("Frozen: cleans and renames leave everything in "+folder+" alone")
+	}
+}
+
+// titleFolder returns the show or movie folder holding path: the top
+// folder below its library, path itself for a file loose in the library,
+// or path's own folder when it is in no library
+func titleFolder(path string, libraryPaths []string) string {
+	for _, lib := range libraryPaths {
+		lib = filepath.Clean(lib)
+		rel, err := filepath.Rel(lib, path)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		top, _, _ := strings.Cut(rel, string(filepath.Separator))
+		return filepath.Join(lib, top)
+	}
+	return filepath.Dir(path)
+}
+
+// refreshTagView redraws the current view and scrolls the cursor into sight
+func (m *Model) refreshTagView() {
+	m.refreshDetailsView()
+	if m.tagCursor < 0 || m.viewport.Height <= 0 {
+		return
+	}
+
+	var content string
+	switch m.mode {
+	case ViewDuplicates:
+		content = m.renderDuplicates()
+	case ViewCompliance:
+		content = m.renderCompliance()
+	default:
+		return
+	}
+	for i, line := range strings.Split(content, "\n") {
+		if strings.Contains(line, tagCursorMark) {
+			if i < m.viewport.YOffset || i >= m.viewport.YOffset+m.viewport.Height {
+				m.viewport.SetYOffset(max(0, i-m.viewport.Height/2))
+			}
+			return
+		}
+	}
+}
+
+// formatTagList joins tags for display, or "no tags"
+func formatTagList(list []string) string {
+	if len(list) == 0 {
+		return "no tags"
+	}
+	return strings.Join(list, ", ")
+}
+
+// tagInputFooter is the footer while the tag prompt is open
+func tagInputFooter() string {
+	return FormatFooter(
+		FormatKeybinding("Type", "Tags"),
+		FormatKeybinding("Enter", "Save"),
+		FormatKeybinding("Esc", "Cancel"),
+	)
+}
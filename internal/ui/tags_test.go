@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+	"github.com/Nomadcxx/jellysink/internal/tags"
+)
+
+func TestTagFromDuplicatesView(t *testing.T) {
+	tagPath := filepath.Join(t.TempDir(), "tags.json")
+	store, _ := tags.Load(tagPath)
+	tags.SetRules(&tags.Rules{Store: store, Keep: []string{"keep"}})
+	defer tags.SetRules(nil)
+
+	report := reporter.Report{
+		MovieDuplicates: []scanner.MovieDuplicate{{
+			NormalizedName: "heat",
+			Year:           "1995",
+			Files: []scanner.MovieFile{
+				{Path: "/movies/Heat 2160p.mkv", Size: 4000},
+				{Path: "/movies/Heat 1080p.mkv", Size: 2000},
+			},
+		}},
+	}
+	report.RecountTotals()
+
+	m := NewModel(report)
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyF1})
+	if !strings.Contains(model.View(), "Tab/T Tag") {
+		t.Error("Expected the tag keys in the duplicates footer")
+	}
+
+	// Select the 1080p copy and tag it
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if !model.(Model).editingTag {
+		t.Fatal("Expected T to open the tag prompt")
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("keep to-replace")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(Model)
+
+	if m.editingTag {
+		t.Error("Expected Enter to close the tag prompt")
+	}
+	reloaded, err := tags.Load(tagPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Tags("/movies/Heat 1080p.mkv"); len(got) != 2 {
+		t.Errorf("Expected both tags saved, got %v", got)
+	}
+	if m.report.MovieDuplicates[0].Files[0].Path != "/movies/Heat 1080p.mkv" || m.report.SpaceToFree != 4000 {
+		t.Error("Expected the keep-tagged copy to become the keeper")
+	}
+	if view := m.renderDuplicates(); !strings.Contains(view, "#keep #to-replace") {
+		t.Errorf("Expected tags shown next to the file:\n%s", view)
+	}
+
+	// A leading - removes a tag
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-to-replace")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := store.Tags("/movies/Heat 1080p.mkv"); len(got) != 1 || got[0] != "keep" {
+		t.Errorf("Expected to-replace removed, got %v", got)
+	}
+}
+
+func TestFreezeTagFreezesTitleFolder(t *testing.T) {
+	store, _ := tags.Load(filepath.Join(t.TempDir(), "tags.json"))
+	tags.SetRules(&tags.Rules{Store: store})
+	defer tags.SetRules(nil)
+
+	report := reporter.Report{
+		LibraryPaths: []string{"/tv"},
+		TVDuplicates: []scanner.TVDuplicate{{
+			ShowName: "firefly",
+			Season:   1,
+			Episode:  1,
+			Files: []scanner.TVFile{
+				{Path: "/tv/Firefly (2002)/Season 01/Firefly S01E01.mkv", Size: 500},
+				{Path: "/tv/Firefly (2002)/Season 01/firefly.s01e01.mkv", Size: 300},
+			},
+		}},
+	}
+
+	model, _ := NewModel(report).Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyF1})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tags.FreezeTag)})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if got := store.Tags("/tv/Firefly (2002)"); len(got) != 1 || got[0] != tags.FreezeTag {
+		t.Errorf("Expected the show folder frozen, got %v", got)
+	}
+	if reason := scanner.FrozenBy("/tv/Firefly (2002)/Season 01/firefly.s01e01.mkv"); reason != "tagged frozen" {
+		t.Errorf("FrozenBy() = %q, want \"tagged frozen\"", reason)
+	}
+
+	// Removing the tag from any file unfreezes the show
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-" + tags.FreezeTag)})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if store.Has("/tv/Firefly (2002)", tags.FreezeTag) {
+		t.Error("Expected -frozen to unfreeze the show")
+	}
+}
@@ -11,8 +11,10 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/Nomadcxx/jellysink/internal/cleaner"
+	"github.com/Nomadcxx/jellysink/internal/oplog"
 	"github.com/Nomadcxx/jellysink/internal/reporter"
 	"github.com/Nomadcxx/jellysink/internal/scanner"
+	"github.com/Nomadcxx/jellysink/internal/tags"
 )
 
 // Custom messages for progress updates
@@ -22,7 +24,9 @@ type renameProgressMsg scanner.ScanProgress
 type scanCompleteMsg reporter.Report
 type scanErrorMsg error
 type renameCompleteMsg struct {
-	result string
+	result  string
+	errors  []error
+	changed []string // old and new paths of everything renamed
 }
 
 // ViewMode represents the current TUI view
@@ -40,11 +44,19 @@ const (
 	ViewCleanOptions
 	ViewCleanConfirm
 	ViewCleaning
+	ViewWastedSpace
+	ViewJellyfinFixes
+	ViewChanges
+	ViewWalkthrough
+	ViewMovieReview
 )
 
 // Model represents the TUI state
 type Model struct {
 	report                 reporter.Report
+	reportPath             string         // JSON report file, when loaded from disk
+	details                *reportDetails // detail sections still being decoded
+	detailsErr             error
 	mode                   ViewMode
 	viewport               viewport.Model
 	ready                  bool
@@ -55,33 +67,61 @@ type Model struct {
 	editingTitle           bool
 	titleInput             textinput.Model
 	editedTitles           map[int]string
+	severityFilter         string // Minimum compliance severity shown ("" = all)
+	explain                bool   // Show rule/parsing explanation under each finding
+
+	// Tagging state (duplicates and compliance views)
+	tagCursor  int // index into taggablePaths, -1 = no selection
+	editingTag bool
+	tagInput   textinput.Model
+	tagStatus  string // outcome of the last tag edit
+
+	// Previous scan of the same libraries, read on the first F6
+	previous    *reporter.Report
+	previousErr error
 
 	// New conflict resolution state
 	currentConflictIndex int
 	conflicts            []*scanner.TVTitleResolution
 	batchReviewCursor    int
 
+	// Movie title review (report.AmbiguousMovies)
+	movieReviewIndex  int
+	movieReviewStatus string // failure to save the last decision
+
 	// Scanning state
-	scanning        bool
-	scanLogs        []LogLine
-	currentProgress string
-	progressPercent float64
-	cancelled       bool
+	scanning  bool
+	progress  Progress // shared by the scan, clean and rename screens
+	cancelled bool
 
 	// Cleaning state
 	cleaning          bool
 	cleanProgressCh   chan scanner.ScanProgress
+	cleanDoneCh       chan cleanCompleteMsg // final result, sent before cleanProgressCh closes
 	cleanResult       string
 	dryRun            bool
 	cleanOptionCursor int // 0 = Dry Run, 1 = Full Clean
 
+	// First-run safety: full cleans locked, dry run followed by a walkthrough
+	firstRun        bool
+	walkthroughPage int
+
 	// Batch rename state
 	renaming         bool
 	renameProgressCh chan scanner.ScanProgress
+	renameDoneCh     chan renameCompleteMsg // final result, sent before renameProgressCh closes
 	renameResult     string
 	renameErrors     []error
+
+	// Rescan of the folders a clean or batch rename changed
+	rescanning   bool
+	rescanStatus string
 }
 
+// SetReportPath records the JSON report file the model was loaded from
+// so a completed clean can be written back into it
+func (m *Model) SetReportPath(path string) { m.reportPath = path }
+
 // NewModel creates a new TUI model with a scan report
 func NewModel(report reporter.Report) Model {
 	ti := textinput.New()
@@ -89,20 +129,34 @@ func NewModel(report reporter.Report) Model {
 	ti.CharLimit = 200
 	ti.Width = 60
 
+	tagInput := textinput.New()
+	tagInput.Placeholder = "keep-4k never-touch -to-replace"
+	tagInput.CharLimit = 200
+	tagInput.Width = 60
+
 	conflicts := make([]*scanner.TVTitleResolution, len(report.AmbiguousTVShows))
 	copy(conflicts, report.AmbiguousTVShows)
 
+	// Files tagged since the scan take over the keeper slot
+	reporter.ApplyKeepTags(&report, tags.CurrentRules())
+
 	return Model{
 		report:       report,
 		mode:         ViewSummary,
 		titleInput:   ti,
 		editedTitles: make(map[int]string),
 		conflicts:    conflicts,
+		tagCursor:    -1,
+		tagInput:     tagInput,
+		firstRun:     isFirstRun(),
 	}
 }
 
-// Init initializes the TUI
+// Init initializes the TUI, decoding any detail sections not yet loaded
 func (m Model) Init() tea.Cmd {
+	if m.details != nil {
+		return m.loadDetails
+	}
 	return nil
 }
 
@@ -111,20 +165,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case progressMsg:
 		// Update scanning progress
-		m.currentProgress = msg.Message
-		m.progressPercent = msg.Percentage
-
-		// Add to log buffer (keep last 100 lines)
-		logEntry := LogLine{
-			Timestamp: fmt.Sprintf("%02d:%02d", msg.ElapsedSeconds/60, msg.ElapsedSeconds%60),
-			Operation: msg.Operation,
-			Message:   msg.Message,
-			Severity:  msg.Severity,
-		}
-		m.scanLogs = append(m.scanLogs, logEntry)
-		if len(m.scanLogs) > 1000 {
-			m.scanLogs = m.scanLogs[len(m.scanLogs)-1000:]
-		}
+		m.progress.Apply(scanner.ScanProgress(msg))
 
 		// Update viewport content
 		if m.mode == ViewScanning {
@@ -141,35 +182,41 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.viewport.SetContent(m.renderSummary())
 		return m, nil
 
+	case reportDetailsMsg:
+		if msg.path != m.reportPath || m.details == nil {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.detailsErr = msg.err
+		} else {
+			m.details = nil
+			m.report.MovieDuplicates = msg.report.MovieDuplicates
+			m.report.TVDuplicates = msg.report.TVDuplicates
+			m.report.ComplianceIssues = msg.report.ComplianceIssues
+			m.report.LooseFiles = msg.report.LooseFiles
+			reporter.ApplyKeepTags(&m.report, tags.CurrentRules())
+		}
+		if m.ready {
+			m.refreshDetailsView()
+		}
+		return m, nil
+
 	case scanErrorMsg:
 		// Scan error - show error and exit
 		m.scanning = false
-		m.scanLogs = append(m.scanLogs, LogLine{Timestamp: fmt.Sprintf("%02d:%02d", 0, 0), Operation: "scan", Message: fmt.Sprintf("ERROR: %v", msg), Severity: "error"})
+		m.progress.AppendLog(LogLine{Timestamp: fmt.Sprintf("%02d:%02d", 0, 0), Operation: scanner.OpScan, Message: fmt.Sprintf("ERROR: %v", msg), Severity: scanner.SeverityError})
 		m.viewport.SetContent(m.renderScanning())
 		return m, nil
 
 	case cleanProgressMsg:
 		// Update cleaning progress (similar to scanning progress)
-		m.currentProgress = msg.Message
-		m.progressPercent = msg.Percentage
-
-		// Add to log buffer
-		logEntry := LogLine{
-			Timestamp: fmt.Sprintf("%02d:%02d", msg.ElapsedSeconds/60, msg.ElapsedSeconds%60),
-			Operation: msg.Operation,
-			Message:   msg.Message,
-			Severity:  msg.Severity,
-		}
-		m.scanLogs = append(m.scanLogs, logEntry)
-		if len(m.scanLogs) > 100 {
-			m.scanLogs = m.scanLogs[1:]
-		}
+		m.progress.Apply(scanner.ScanProgress(msg))
 
 		// Update viewport content
 		m.viewport.SetContent(m.renderCleaning())
 
 		// Continue listening for progress
-		return m, waitForCleanProgress(m.cleanProgressCh)
+		return m, waitForCleanProgress(m.cleanProgressCh, m.cleanDoneCh)
 
 	case cleanCompleteMsg:
 		// Cleaning finished
@@ -177,36 +224,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.result != "" {
 			m.cleanResult = msg.result
 		}
+		if msg.err != nil {
+			m.cleanResult = ErrorStyle.Render(fmt.Sprintf("✗ Cleanup failed: %v", msg.err))
+		}
+		if msg.summary != nil {
+			m.report.Cleaned = msg.summary
+		}
 		// If cleanResult is still empty, set a default message
 		if m.cleanResult == "" {
 			m.cleanResult = SuccessStyle.Render("✓ Cleanup completed")
 		}
+		// The report is brought up to date with what the clean changed
+		cmd := m.startRescan(msg.changed)
 		m.viewport.SetContent(m.renderCleaning())
-		return m, nil
+		return m, cmd
 
 	case renameProgressMsg:
 		// Batch rename progress update
-		progress := scanner.ScanProgress(msg)
-		m.currentProgress = progress.Message
-		m.progressPercent = progress.Percentage
-
-		// Add to log buffer
-		logEntry := LogLine{
-			Timestamp: fmt.Sprintf("%02d:%02d", progress.ElapsedSeconds/60, progress.ElapsedSeconds%60),
-			Operation: progress.Operation,
-			Message:   progress.Message,
-			Severity:  progress.Severity,
-		}
-		m.scanLogs = append(m.scanLogs, logEntry)
-		if len(m.scanLogs) > 100 {
-			m.scanLogs = m.scanLogs[1:]
-		}
+		m.progress.Apply(scanner.ScanProgress(msg))
 
 		// Update viewport content
 		m.viewport.SetContent(m.renderBatchRenaming())
 
 		// Continue listening for progress
-		return m, waitForRenameProgress(m.renameProgressCh)
+		return m, waitForRenameProgress(m.renameProgressCh, m.renameDoneCh)
 
 	case renameCompleteMsg:
 		// Batch rename finished
@@ -214,14 +255,57 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.result != "" {
 			m.renameResult = msg.result
 		}
+		m.renameErrors = msg.errors
 		// If renameResult is still empty, set a default message
 		if m.renameResult == "" {
 			m.renameResult = SuccessStyle.Render("✓ Batch rename completed")
 		}
+		cmd := m.startRescan(msg.changed)
 		m.viewport.SetContent(m.renderBatchRenaming())
+		return m, cmd
+
+	case rescanCompleteMsg:
+		m.applyRescan(msg)
+		switch m.mode {
+		case ViewCleaning:
+			m.viewport.SetContent(m.renderCleaning())
+		case ViewBatchRenaming:
+			m.viewport.SetContent(m.renderBatchRenaming())
+		case ViewSummary:
+			m.viewport.SetContent(m.renderSummary())
+		}
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.editingTag {
+			switch msg.String() {
+			case "esc":
+				m.editingTag = false
+				m.tagInput.Blur()
+				m.refreshTagView()
+				return m, nil
+
+			case "enter":
+				m.applyTagInput(m.tagInput.Value())
+				m.editingTag = false
+				m.tagInput.Blur()
+				m.tagInput.SetValue("")
+				m.refreshTagView()
+				return m, nil
+
+			default:
+				var cmd tea.Cmd
+				m.tagInput, cmd = m.tagInput.Update(msg)
+				m.refreshTagView()
+				return m, cmd
+			}
+		}
+
+		// The movie title review handles its own custom title input
+		if m.mode == ViewMovieReview {
+			return m.updateMovieReview(msg)
+		}
+
 		if m.editingTitle {
 			switch msg.String() {
 			case "esc":
@@ -260,11 +344,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// The first dry run leads into the walkthrough, whatever the key
+		if m.mode == ViewCleaning && !m.cleaning && m.dryRun && m.firstRun && msg.String() != "ctrl+c" {
+			m.mode = ViewWalkthrough
+			m.walkthroughPage = 0
+			m.viewport.SetContent(m.renderWalkthrough())
+			m.viewport.GotoTop()
+			return m, nil
+		}
+		if m.mode == ViewWalkthrough {
+			return m.updateWalkthrough(msg)
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			if m.mode == ViewScanning {
 				m.cancelled = true
-				m.scanLogs = append(m.scanLogs, LogLine{Timestamp: "", Operation: "scan", Message: "Cancelling scan...", Severity: "warn"})
+				m.progress.AppendLog(LogLine{Timestamp: "", Operation: scanner.OpScan, Message: "Cancelling scan...", Severity: scanner.SeverityWarn})
 			}
 			return m, tea.Quit
 
@@ -304,18 +400,56 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "f1":
 			m.mode = ViewDuplicates
+			m.tagCursor, m.tagStatus = -1, ""
 			m.viewport.SetContent(m.renderDuplicates())
 			m.viewport.GotoTop()
 			return m, nil
 
 		case "f2":
 			m.mode = ViewCompliance
+			m.tagCursor, m.tagStatus = -1, ""
 			m.viewport.SetContent(m.renderCompliance())
 			m.viewport.GotoTop()
 			return m, nil
 
+		case "f4":
+			m.mode = ViewWastedSpace
+			m.viewport.SetContent(m.renderWastedSpace())
+			m.viewport.GotoTop()
+			return m, nil
+
+		case "f5":
+			if m.report.Jellyfin.HasFindings() {
+				m.mode = ViewJellyfinFixes
+				m.viewport.SetContent(m.renderJellyfinFixes())
+				m.viewport.GotoTop()
+			}
+			return m, nil
+
+		case "f6":
+			if m.report.Changes != nil {
+				m.loadPrevious()
+				m.mode = ViewChanges
+				m.viewport.SetContent(m.renderChanges())
+				m.viewport.GotoTop()
+			}
+			return m, nil
+
+		case "f7":
+			// Movie titles need the compliance issues they rename
+			if len(m.report.AmbiguousMovies) > 0 && m.details == nil {
+				m.mode = ViewMovieReview
+				m.movieReviewIndex, m.movieReviewStatus = 0, ""
+				m.viewport.SetContent(m.renderMovieReview())
+				m.viewport.GotoTop()
+			}
+			return m, nil
+
 		case "f3":
 			if len(m.conflicts) > 0 {
+				// Highest-impact decisions first; decisions already made stay
+				// on their conflicts
+				sortConflictsByImpact(m.conflicts, m.report.ComplianceIssues)
 				m.mode = ViewConflictReview
 				m.currentConflictIndex = 0
 				m.viewport.SetContent(m.renderConflictReview())
@@ -338,7 +472,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			}
-			if m.mode == ViewDuplicates || m.mode == ViewCompliance {
+			if m.mode == ViewDuplicates || m.mode == ViewCompliance || m.mode == ViewWastedSpace || m.mode == ViewChanges {
 				m.viewport.LineUp(1)
 				return m, nil
 			}
@@ -352,25 +486,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 			if m.mode == ViewCleanOptions {
-				if m.cleanOptionCursor < 1 {
+				if m.cleanOptionCursor < 1 && !m.firstRun {
 					m.cleanOptionCursor++
 					m.viewport.SetContent(m.renderCleanOptions())
 				}
 				return m, nil
 			}
-			if m.mode == ViewDuplicates || m.mode == ViewCompliance {
+			if m.mode == ViewDuplicates || m.mode == ViewCompliance || m.mode == ViewWastedSpace || m.mode == ViewChanges {
 				m.viewport.LineDown(1)
 				return m, nil
 			}
 
 		case "pgup":
-			if m.mode == ViewDuplicates || m.mode == ViewCompliance || m.mode == ViewManualIntervention {
+			if m.mode == ViewDuplicates || m.mode == ViewCompliance || m.mode == ViewManualIntervention || m.mode == ViewWastedSpace || m.mode == ViewChanges {
 				m.viewport.ViewUp()
 				return m, nil
 			}
 
 		case "pgdown":
-			if m.mode == ViewDuplicates || m.mode == ViewCompliance || m.mode == ViewManualIntervention {
+			if m.mode == ViewDuplicates || m.mode == ViewCompliance || m.mode == ViewManualIntervention || m.mode == ViewWastedSpace || m.mode == ViewChanges {
 				m.viewport.ViewDown()
 				return m, nil
 			}
@@ -406,9 +540,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.viewport.SetContent(m.renderManualIntervention())
 				return m, textinput.Blink
 			}
+			// Leave the selected duplicate group out of cleans
+			if m.mode == ViewDuplicates {
+				m.toggleExcluded()
+			}
 			return m, nil
 
 		case "enter":
+			// Keep the selected duplicate instead of the scanner's choice
+			if m.mode == ViewDuplicates {
+				m.chooseKeeper()
+				return m, nil
+			}
 			if m.mode == ViewConflictReview && !m.editingTitle {
 				allDecided := true
 				for _, c := range m.conflicts {
@@ -432,8 +575,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			}
-			// Enter in summary mode triggers clean options
-			if m.mode == ViewSummary {
+			// Enter in summary mode triggers clean options (once the
+			// duplicates to clean have been decoded); simulated reports
+			// have nothing on disk to clean
+			if m.mode == ViewSummary && m.details == nil && !m.report.Simulated {
 				m.mode = ViewCleanOptions
 				m.cleanOptionCursor = 0
 				m.viewport.SetContent(m.renderCleanOptions())
@@ -447,7 +592,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.dryRun = true
 					m.mode = ViewCleaning
 					m.cleaning = true
-					m.scanLogs = []LogLine{}
+					m.progress = Progress{LogTitle: "PREVIEW LOG (No Changes Made)"}
 					m.viewport.SetContent(m.renderCleaning())
 					return m, m.runCleaning()
 				} else {
@@ -463,7 +608,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.mode == ViewCleanConfirm {
 				m.mode = ViewCleaning
 				m.cleaning = true
-				m.scanLogs = []LogLine{} // Clear previous logs
+				m.progress = Progress{LogTitle: "CLEANING LOG"} // Clear previous progress
 				m.viewport.SetContent(m.renderCleaning())
 				return m, m.runCleaning()
 			}
@@ -471,7 +616,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.mode == ViewBatchSummary {
 				m.mode = ViewBatchRenaming
 				m.renaming = true
-				m.scanLogs = []LogLine{}
+				m.progress = Progress{LogTitle: "RENAME LOG"}
 				m.viewport.SetContent(m.renderBatchRenaming())
 				return m, m.runBatchRename()
 			}
@@ -498,7 +643,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.dryRun = true
 				m.mode = ViewCleaning
 				m.cleaning = true
-				m.scanLogs = []LogLine{}
+				m.progress = Progress{LogTitle: "PREVIEW LOG (No Changes Made)"}
 				m.viewport.SetContent(m.renderCleaning())
 				return m, m.runCleaning()
 			}
@@ -514,8 +659,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			}
-			// Full clean selected from clean options
-			if m.mode == ViewCleanOptions {
+			// Full clean selected from clean options, once unlocked
+			if m.mode == ViewCleanOptions && !m.firstRun {
 				m.dryRun = false
 				m.mode = ViewCleanConfirm
 				m.viewport.SetContent(m.renderCleanConfirm())
@@ -533,6 +678,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			}
+			if m.mode == ViewDuplicates {
+				m.moveGroupCursor(1)
+			}
 			return m, nil
 
 		case "left":
@@ -544,6 +692,77 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			}
+			if m.mode == ViewDuplicates {
+				m.moveGroupCursor(-1)
+			}
+			return m, nil
+
+		case "v":
+			// Cycle compliance severity filter: all -> warn -> error -> all
+			if m.mode == ViewCompliance {
+				switch m.severityFilter {
+				case "":
+					m.severityFilter = scanner.IssueSeverityWarn
+				case scanner.IssueSeverityWarn:
+					m.severityFilter = scanner.IssueSeverityError
+				default:
+					m.severityFilter = ""
+				}
+				m.tagCursor = -1
+				m.viewport.SetContent(m.renderCompliance())
+				m.viewport.GotoTop()
+			}
+			return m, nil
+
+		case "tab", "shift+tab":
+			// Select the file or folder to tag
+			if m.mode == ViewDuplicates || m.mode == ViewCompliance {
+				if msg.String() == "tab" {
+					m.moveTagCursor(1)
+				} else {
+					m.moveTagCursor(-1)
+				}
+			}
+			return m, nil
+
+		case "t":
+			// Tag the selected file or folder
+			if (m.mode == ViewDuplicates || m.mode == ViewCompliance) && len(m.taggablePaths()) > 0 {
+				if m.tagCursor < 0 {
+					m.tagCursor = 0
+				}
+				m.editingTag = true
+				m.tagStatus = ""
+				m.tagInput.Focus()
+				m.refreshTagView()
+				return m, textinput.Blink
+			}
+			return m, nil
+
+		case "c":
+			// Move the selected group's subtitles and artwork to its keeper, or not
+			if m.mode == ViewDuplicates {
+				m.toggleCompanions()
+			}
+			return m, nil
+
+		case "m":
+			// Keep every copy of the selected movie as a Jellyfin version
+			if m.mode == ViewDuplicates {
+				m.toggleVersions()
+			}
+			return m, nil
+
+		case "x":
+			// Toggle explain mode in detail views
+			if m.mode == ViewDuplicates || m.mode == ViewCompliance {
+				m.explain = !m.explain
+				if m.mode == ViewDuplicates {
+					m.viewport.SetContent(m.renderDuplicates())
+				} else {
+					m.viewport.SetContent(m.renderCompliance())
+				}
+			}
 			return m, nil
 
 		case "n":
@@ -626,28 +845,48 @@ func (m Model) View() string {
 	switch m.mode {
 	case ViewSummary:
 		header = "" // No header, title is in content below ASCII art
+		keys := []string{
+			FormatKeybinding("F1", "Duplicates"),
+			FormatKeybinding("F2", "Compliance"),
+		}
 		if len(m.report.AmbiguousTVShows) > 0 {
-			footer = FormatFooter(
-				FormatKeybinding("F1", "Duplicates"),
-				FormatKeybinding("F2", "Compliance"),
+			keys = append(keys,
 				FormatKeybinding("F3", "Manual Fixes"),
-				FormatKeybinding("Esc", "Exit"),
+				FormatKeybinding("F4", "Wasted Space"),
 			)
 		} else {
-			footer = FormatFooter(
-				FormatKeybinding("F1", "Duplicates"),
-				FormatKeybinding("F2", "Compliance"),
+			keys = append(keys,
+				FormatKeybinding("F4", "Wasted Space"),
 				FormatKeybinding("Enter", "Clean"),
-				FormatKeybinding("Esc", "Exit"),
 			)
 		}
+		if m.report.Jellyfin.HasFindings() {
+			keys = append(keys, FormatKeybinding("F5", "Jellyfin"))
+		}
+		if m.report.Changes != nil {
+			keys = append(keys, FormatKeybinding("F6", "Changes"))
+		}
+		if len(m.report.AmbiguousMovies) > 0 {
+			keys = append(keys, FormatKeybinding("F7", "Movie Titles"))
+		}
+		footer = FormatFooter(append(keys, FormatKeybinding("Esc", "Exit"))...)
 
 	case ViewDuplicates:
 		header = FormatHeader("DUPLICATE REPORT (DETAILED)")
 		scrollInfo := fmt.Sprintf("%d%%", int(m.viewport.ScrollPercent()*100))
+		if m.editingTag {
+			footer = tagInputFooter()
+			break
+		}
 		footer = FormatFooter(
-			FormatKeybinding("↑↓", "Scroll"),
-			FormatKeybinding("PgUp/PgDn", "Page"),
+			FormatKeybinding("↑↓/PgUp/PgDn", "Scroll"),
+			FormatKeybinding("←→", "Group"),
+			FormatKeybinding("Tab/T", "Tag"),
+			FormatKeybinding("Enter", "Keep"),
+			FormatKeybinding("E", "Exclude"),
+			FormatKeybinding("M", "Versions"),
+			FormatKeybinding("C", "Subs/Art"),
+			FormatKeybinding("X", "Explain"),
 			FormatKeybinding("Esc", "Back"),
 			MutedStyle.Render(scrollInfo),
 		)
@@ -655,6 +894,43 @@ func (m Model) View() string {
 	case ViewCompliance:
 		header = FormatHeader("COMPLIANCE REPORT (DETAILED)")
 		scrollInfo := fmt.Sprintf("%d%%", int(m.viewport.ScrollPercent()*100))
+		if m.editingTag {
+			footer = tagInputFooter()
+			break
+		}
+		footer = FormatFooter(
+			FormatKeybinding("↑↓", "Scroll"),
+			FormatKeybinding("PgUp/PgDn", "Page"),
+			FormatKeybinding("Tab/T", "Tag"),
+			FormatKeybinding("V", "Severity"),
+			FormatKeybinding("X", "Explain"),
+			FormatKeybinding("Esc", "Back"),
+			MutedStyle.Render(scrollInfo),
+		)
+
+	case ViewJellyfinFixes:
+		header = FormatHeader("JELLYFIN: FIX THESE FIRST")
+		scrollInfo := fmt.Sprintf("%d%%", int(m.viewport.ScrollPercent()*100))
+		footer = FormatFooter(
+			FormatKeybinding("↑↓", "Scroll"),
+			FormatKeybinding("PgUp/PgDn", "Page"),
+			FormatKeybinding("Esc", "Back"),
+			MutedStyle.Render(scrollInfo),
+		)
+
+	case ViewChanges:
+		header = FormatHeader("CHANGES SINCE THE PREVIOUS SCAN")
+		scrollInfo := fmt.Sprintf("%d%%", int(m.viewport.ScrollPercent()*100))
+		footer = FormatFooter(
+			FormatKeybinding("↑↓", "Scroll"),
+			FormatKeybinding("PgUp/PgDn", "Page"),
+			FormatKeybinding("Esc", "Back"),
+			MutedStyle.Render(scrollInfo),
+		)
+
+	case ViewWastedSpace:
+		header = FormatHeader("WASTED SPACE BY FOLDER")
+		scrollInfo := fmt.Sprintf("%d%%", int(m.viewport.ScrollPercent()*100))
 		footer = FormatFooter(
 			FormatKeybinding("↑↓", "Scroll"),
 			FormatKeybinding("PgUp/PgDn", "Page"),
@@ -745,11 +1021,43 @@ func (m Model) View() string {
 			footer = FormatFooter(
 				MutedStyle.Render("Please wait..."),
 			)
+		} else if m.dryRun && m.firstRun {
+			footer = FormatFooter(
+				FormatKeybinding("Any key", "Walkthrough"),
+			)
 		} else {
 			footer = FormatFooter(
 				FormatKeybinding("Any key", "Return to Menu"),
 			)
 		}
+
+	case ViewMovieReview:
+		header = FormatHeader("MOVIE TITLE REVIEW")
+		if m.editingTitle {
+			footer = FormatFooter(
+				FormatKeybinding("Type", "Edit"),
+				FormatKeybinding("Enter", "Save"),
+				FormatKeybinding("Esc", "Cancel"),
+			)
+		} else {
+			progressInfo := fmt.Sprintf("%d/%d", m.movieReviewIndex+1, len(m.report.AmbiguousMovies))
+			footer = FormatFooter(
+				FormatKeybinding("1/2/E", "Select"),
+				FormatKeybinding("←→", "Navigate"),
+				FormatKeybinding("S", "Skip"),
+				FormatKeybinding("Esc", "Back"),
+				MutedStyle.Render(progressInfo),
+			)
+		}
+
+	case ViewWalkthrough:
+		header = FormatHeader(fmt.Sprintf("FIRST-RUN WALKTHROUGH %d/%d", m.walkthroughPage+1, walkthroughPages))
+		footer = FormatFooter(
+			FormatKeybinding("←→", "Page"),
+			FormatKeybinding("↑↓", "Scroll"),
+			FormatKeybinding("Enter", "Next"),
+			FormatKeybinding("Esc", "Back to Summary"),
+		)
 	}
 
 	// Build full view
@@ -765,8 +1073,8 @@ func (m Model) View() string {
 func (m Model) renderSummary() string {
 	var sb strings.Builder
 
-	// ASCII header
-	sb.WriteString(FormatASCIIHeader() + "\n\n")
+	// Banner
+	sb.WriteString(Banner(m.width) + "\n\n")
 
 	// Title with different background to separate from ASCII art
 	titleStyle := lipgloss.NewStyle().
@@ -776,6 +1084,20 @@ func (m Model) renderSummary() string {
 		Padding(0, 1)
 	sb.WriteString(titleStyle.Render("JELLYSINK SCAN SUMMARY") + "\n\n")
 
+	// Already-cleaned reports carry a banner so they are not cleaned twice by accident
+	if m.report.Cleaned != nil {
+		sb.WriteString(WarningStyle.Render("⚠ "+m.report.Cleaned.Banner()) + "\n\n")
+	}
+	if m.report.Partial != nil {
+		sb.WriteString(WarningStyle.Render("⚠ "+m.report.Partial.Banner()) + "\n\n")
+	}
+	if m.report.Simulated {
+		sb.WriteString(WarningStyle.Render("⚠ Simulated report from jellysinkd --test: its files do not exist and cleaning is disabled") + "\n\n")
+	}
+	if m.rescanStatus != "" {
+		sb.WriteString(m.rescanStatus + "\n\n")
+	}
+
 	// Timestamp and library info
 	sb.WriteString(InfoStyle.Render("Generated: ") + ContentStyle.Render(m.report.Timestamp.Format("2006-01-02 15:04:05")) + "\n")
 	sb.WriteString(InfoStyle.Render("Library: ") + ContentStyle.Render(m.report.LibraryType) + "\n")
@@ -797,13 +1119,22 @@ func (m Model) renderSummary() string {
 	}
 	sb.WriteString("\n\n")
 
+	// What changed since the previous scan of these libraries
+	if changes := m.report.Changes; changes != nil {
+		sb.WriteString(TitleStyle.Render("SINCE LAST SCAN") + "\n")
+		sb.WriteString(ContentStyle.Render(changes.Headline()) + "\n")
+		sb.WriteString(InfoStyle.Render("Press F6 for details.") + "\n\n")
+	}
+
 	// Duplicates section
 	sb.WriteString(TitleStyle.Render("DUPLICATES") + "\n")
 	sb.WriteString(InfoStyle.Render("Groups found: ") + StatStyle.Render(fmt.Sprintf("%d", m.report.TotalDuplicates)) + "\n")
 	sb.WriteString(InfoStyle.Render("Files to delete: ") + StatStyle.Render(fmt.Sprintf("%d", m.report.TotalFilesToDelete)) + "\n")
 	sb.WriteString(InfoStyle.Render("Space to free: ") + StatStyle.Render(formatBytes(m.report.SpaceToFree)) + "\n\n")
 
-	if m.report.TotalDuplicates > 0 {
+	if placeholder, pending := m.detailsPlaceholder(); pending {
+		sb.WriteString(placeholder + "\n\n")
+	} else if m.report.TotalDuplicates > 0 {
 		sb.WriteString(MutedStyle.Render("Top 5 examples:") + "\n")
 		// Get top offenders
 		offenders := getTopOffenders(m.report)
@@ -828,10 +1159,97 @@ func (m Model) renderSummary() string {
 		sb.WriteString(WarningStyle.Render("These shows have conflicting titles that could not be auto-resolved.") + "\n")
 		sb.WriteString(InfoStyle.Render("Press F3 to review and fix these issues.") + "\n\n")
 	}
+	if len(m.report.AmbiguousMovies) > 0 {
+		sb.WriteString(TitleStyle.Render("⚠ MOVIE TITLES TO REVIEW") + "\n")
+		sb.WriteString(WarningStyle.Render(fmt.Sprintf("Movies whose title was not confirmed: %d", len(m.report.AmbiguousMovies))) + "\n")
+		sb.WriteString(InfoStyle.Render("Press F7 to choose their titles; undecided ones are renamed as suggested.") + "\n\n")
+	}
+
+	// API diagnostics (only when verification was degraded)
+	if m.report.APIDegraded() {
+		sb.WriteString(TitleStyle.Render("⚠ API DIAGNOSTICS") + "\n")
+		for _, d := range m.report.APIDiagnostics {
+			style := StatStyle
+			if d.Degraded() {
+				style = WarningStyle
+			}
+			sb.WriteString(InfoStyle.Render(d.Provider+": ") + style.Render(fmt.Sprintf("%d succeeded, %d failed, %d skipped (offline)", d.Successes, d.Failures, d.Skipped)) + "\n")
+			if d.LastError != "" {
+				sb.WriteString(MutedStyle.Render("  Last error: ") + ErrorStyle.Render(d.LastError) + "\n")
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	// Jellyfin comparison (only when enabled for the scan)
+	if cmp := m.report.Jellyfin; cmp != nil {
+		sb.WriteString(TitleStyle.Render("JELLYFIN COMPARISON") + "\n")
+		if cmp.Error != "" {
+			sb.WriteString(ErrorStyle.Render("Comparison failed: "+cmp.Error) + "\n\n")
+		} else {
+			sb.WriteString(InfoStyle.Render("On disk but not in Jellyfin: ") + StatStyle.Render(fmt.Sprintf("%d", len(cmp.MissingFromServer))) + "\n")
+			sb.WriteString(InfoStyle.Render("In Jellyfin but missing on disk: ") + StatStyle.Render(fmt.Sprintf("%d", len(cmp.MissingOnDisk))) + "\n")
+			if len(cmp.MissingFromServer) > 0 {
+				sb.WriteString(MutedStyle.Render("Unmatched files are usually a naming problem; fixing the compliance issues below helps Jellyfin match them.") + "\n")
+			}
+			if cmp.HasFindings() {
+				sb.WriteString(InfoStyle.Render("Press F5 for the fix-these-first list.") + "\n")
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	// Orphaned show/season folders, deleted with the rest of the clean
+	if len(m.report.OrphanFolders) > 0 {
+		sb.WriteString(TitleStyle.Render("ORPHANED FOLDERS") + "\n")
+		sb.WriteString(InfoStyle.Render("Show/season folders without videos: ") + StatStyle.Render(fmt.Sprintf("%d", len(m.report.OrphanFolders))) + "\n")
+		limit := 5
+		if len(m.report.OrphanFolders) < limit {
+			limit = len(m.report.OrphanFolders)
+		}
+		for i := 0; i < limit; i++ {
+			orphan := m.report.OrphanFolders[i]
+			sb.WriteString(fmt.Sprintf("  %s %s %s\n",
+				WarningStyle.Render(fmt.Sprintf("%d.", i+1)),
+				MutedStyle.Render("["+strings.ToUpper(orphan.Kind)+"]"),
+				ContentStyle.Render(orphan.Path)))
+		}
+		sb.WriteString(MutedStyle.Render("Cleaning deletes these folders with their leftover nfo/artwork.") + "\n\n")
+	}
+
+	// Leftover junk, removed separately with its own preview
+	if len(m.report.Artifacts) > 0 {
+		sb.WriteString(TitleStyle.Render("ARTIFACTS") + "\n")
+		sb.WriteString(InfoStyle.Render("Leftover junk files and empty folders: ") + StatStyle.Render(fmt.Sprintf("%d", len(m.report.Artifacts))) + "\n")
+		limit := 5
+		if len(m.report.Artifacts) < limit {
+			limit = len(m.report.Artifacts)
+		}
+		for i := 0; i < limit; i++ {
+			artifact := m.report.Artifacts[i]
+			sb.WriteString(fmt.Sprintf("  %s %s %s\n",
+				WarningStyle.Render(fmt.Sprintf("%d.", i+1)),
+				MutedStyle.Render("["+strings.ToUpper(artifact.Kind)+"]"),
+				ContentStyle.Render(artifact.Path)))
+		}
+		sb.WriteString(MutedStyle.Render("Cleaning leaves these alone; preview their removal with: jellysink artifacts <report>") + "\n\n")
+	}
 
 	// Compliance section
 	sb.WriteString(TitleStyle.Render("COMPLIANCE ISSUES") + "\n")
-	sb.WriteString(InfoStyle.Render("Files to rename: ") + StatStyle.Render(fmt.Sprintf("%d", len(m.report.ComplianceIssues))) + "\n\n")
+	if placeholder, pending := m.detailsPlaceholder(); pending {
+		sb.WriteString(placeholder + "\n\n")
+		return sb.String()
+	}
+	sb.WriteString(InfoStyle.Render("Files to rename: ") + StatStyle.Render(fmt.Sprintf("%d", len(m.report.ComplianceIssues))) + "\n")
+	if len(m.report.ComplianceIssues) > 0 {
+		counts := scanner.CountIssuesBySeverity(m.report.ComplianceIssues)
+		sb.WriteString(InfoStyle.Render("By severity: ") +
+			ErrorStyle.Render(fmt.Sprintf("%d error", counts[scanner.IssueSeverityError])) + ", " +
+			WarningStyle.Render(fmt.Sprintf("%d warn", counts[scanner.IssueSeverityWarn])) + ", " +
+			InfoStyle.Render(fmt.Sprintf("%d info", counts[scanner.IssueSeverityInfo])) + "\n")
+	}
+	sb.WriteString("\n")
 
 	if len(m.report.ComplianceIssues) > 0 {
 		sb.WriteString(MutedStyle.Render("First 5 examples:") + "\n")
@@ -857,39 +1275,116 @@ func (m Model) renderSummary() string {
 	return sb.String()
 }
 
+// detailsPlaceholder returns the text shown in place of detail sections that
+// are still being decoded, and whether they are pending
+func (m Model) detailsPlaceholder() (string, bool) {
+	if m.details == nil {
+		return "", false
+	}
+	if m.detailsErr != nil {
+		return ErrorStyle.Render(fmt.Sprintf("Failed to load report details: %v", m.detailsErr)), true
+	}
+	return MutedStyle.Render("Loading report details..."), true
+}
+
+// refreshDetailsView redraws the current view once detail sections arrive
+func (m *Model) refreshDetailsView() {
+	switch m.mode {
+	case ViewSummary:
+		m.viewport.SetContent(m.renderSummary())
+	case ViewDuplicates:
+		m.viewport.SetContent(m.renderDuplicates())
+	case ViewCompliance:
+		m.viewport.SetContent(m.renderCompliance())
+	case ViewWastedSpace:
+		m.viewport.SetContent(m.renderWastedSpace())
+	case ViewJellyfinFixes:
+		m.viewport.SetContent(m.renderJellyfinFixes())
+	case ViewChanges:
+		m.viewport.SetContent(m.renderChanges())
+	}
+}
+
 // renderDuplicates renders the duplicates detail view
 func (m Model) renderDuplicates() string {
 	var sb strings.Builder
 
 	sb.WriteString(TitleStyle.Render("MOVIE DUPLICATES") + "\n\n")
 
+	if placeholder, pending := m.detailsPlaceholder(); pending {
+		return sb.String() + placeholder + "\n"
+	}
+
 	if len(m.report.MovieDuplicates) == 0 && len(m.report.TVDuplicates) == 0 {
 		sb.WriteString(MutedStyle.Render("No duplicates found.") + "\n")
 		return sb.String()
 	}
 
+	sb.WriteString(m.renderTagPrompt())
+	tagIndex := 0
+
 	// Render movie duplicates
 	for _, dup := range m.report.MovieDuplicates {
 		title := dup.NormalizedName
 		if dup.Year != "" {
 			title = title + " (" + dup.Year + ")"
 		}
-		sb.WriteString(HighlightStyle.Render(fmt.Sprintf("%s (%d versions)", title, len(dup.Files))) + "\n")
+		if dup.Version != "" {
+			title += " - " + dup.Version
+		}
+		sb.WriteString(HighlightStyle.Render(fmt.Sprintf("%s (%d versions)", title, len(dup.Files))) +
+			MutedStyle.Render(" - "+dup.Scope.Describe(dup.Within)) + excludedMark(dup.Excluded) + "\n")
+
+		if dup.KeepsAllVersions() && !dup.Excluded {
+			targets, err := versionTargets(dup)
+			if err != nil {
+				sb.WriteString(WarningStyle.Render(fmt.Sprintf("  Cannot keep as versions: %v", err)) + "\n")
+			}
+			for _, file := range dup.Files {
+				sb.WriteString(fmt.Sprintf("%s %s [%s] [%s] %s%s\n",
+					m.tagPrefix(tagIndex),
+					InfoStyle.Render("VERSION:"),
+					StatStyle.Render(formatBytes(file.Size)),
+					InfoStyle.Render(file.Resolution),
+					ContentStyle.Render(file.Path),
+					m.tagSuffix(file.Path)))
+				if target, ok := targets[file.Path]; ok {
+					sb.WriteString(MutedStyle.Render("           -> "+target) + "\n")
+				}
+				sb.WriteString(renderProbe(file.Probe))
+				tagIndex++
+			}
+			if m.explain {
+				sb.WriteString(renderExplanation(scanner.ExplainMovieDuplicate(dup)))
+			}
+			sb.WriteString("\n")
+			continue
+		}
 
 		for i, file := range dup.Files {
-			if i == 0 {
-				sb.WriteString(fmt.Sprintf("  %s [%s] [%s] %s\n",
+			if i == 0 || dup.Excluded {
+				sb.WriteString(fmt.Sprintf("%s %s [%s] [%s] %s%s\n",
+					m.tagPrefix(tagIndex),
 					SuccessStyle.Render("KEEP:  "),
 					StatStyle.Render(formatBytes(file.Size)),
 					InfoStyle.Render(file.Resolution),
-					ContentStyle.Render(file.Path)))
+					ContentStyle.Render(file.Path),
+					m.tagSuffix(file.Path)))
 			} else {
-				sb.WriteString(fmt.Sprintf("  %s [%s] [%s] %s\n",
+				sb.WriteString(fmt.Sprintf("%s %s [%s] [%s] %s%s\n",
+					m.tagPrefix(tagIndex),
 					ErrorStyle.Render("DELETE:"),
 					StatStyle.Render(formatBytes(file.Size)),
 					InfoStyle.Render(file.Resolution),
-					MutedStyle.Render(file.Path)))
+					MutedStyle.Render(file.Path),
+					m.tagSuffix(file.Path)))
+				sb.WriteString(renderCompanionMerges(file.Path, dup.Files[0].Path, dup.MergesCompanions()))
 			}
+			sb.WriteString(renderProbe(file.Probe))
+			tagIndex++
+		}
+		if m.explain {
+			sb.WriteString(renderExplanation(scanner.ExplainMovieDuplicate(dup)))
 		}
 		sb.WriteString("\n")
 	}
@@ -899,25 +1394,36 @@ func (m Model) renderDuplicates() string {
 		sb.WriteString(TitleStyle.Render("TV EPISODE DUPLICATES") + "\n\n")
 
 		for _, dup := range m.report.TVDuplicates {
-			title := fmt.Sprintf("%s S%02dE%02d", dup.ShowName, dup.Season, dup.Episode)
-			sb.WriteString(HighlightStyle.Render(fmt.Sprintf("%s (%d versions)", title, len(dup.Files))) + "\n")
+			title := dup.ShowName + " " + dup.EpisodeLabel()
+			sb.WriteString(HighlightStyle.Render(fmt.Sprintf("%s (%d versions)", title, len(dup.Files))) +
+				MutedStyle.Render(" - "+dup.Scope.Describe(dup.Within)) + excludedMark(dup.Excluded) + "\n")
 
 			for i, file := range dup.Files {
-				if i == 0 {
-					sb.WriteString(fmt.Sprintf("  %s [%s] [%s] [%s] %s\n",
+				if i == 0 || dup.Excluded {
+					sb.WriteString(fmt.Sprintf("%s %s [%s] [%s] [%s] %s%s\n",
+						m.tagPrefix(tagIndex),
 						SuccessStyle.Render("KEEP:  "),
 						StatStyle.Render(formatBytes(file.Size)),
 						InfoStyle.Render(file.Resolution),
 						InfoStyle.Render(file.Source),
-						ContentStyle.Render(file.Path)))
+						ContentStyle.Render(file.Path),
+						m.tagSuffix(file.Path)))
 				} else {
-					sb.WriteString(fmt.Sprintf("  %s [%s] [%s] [%s] %s\n",
+					sb.WriteString(fmt.Sprintf("%s %s [%s] [%s] [%s] %s%s\n",
+						m.tagPrefix(tagIndex),
 						ErrorStyle.Render("DELETE:"),
 						StatStyle.Render(formatBytes(file.Size)),
 						InfoStyle.Render(file.Resolution),
 						InfoStyle.Render(file.Source),
-						MutedStyle.Render(file.Path)))
+						MutedStyle.Render(file.Path),
+						m.tagSuffix(file.Path)))
+					sb.WriteString(renderCompanionMerges(file.Path, dup.Files[0].Path, dup.MergesCompanions()))
 				}
+				sb.WriteString(renderProbe(file.Probe))
+				tagIndex++
+			}
+			if m.explain {
+				sb.WriteString(renderExplanation(scanner.ExplainTVDuplicate(dup)))
 			}
 			sb.WriteString("\n")
 		}
@@ -926,36 +1432,195 @@ func (m Model) renderDuplicates() string {
 	return sb.String()
 }
 
+// wasteTreeDepth limits the TUI tree to library / show or movie / season
+const wasteTreeDepth = 3
+
+// wasteBarWidth is the width of the heat bar next to each folder
+const wasteBarWidth = 20
+
+// renderWastedSpace renders reclaimable space per folder as a heat-shaded tree
+func (m Model) renderWastedSpace() string {
+	var sb strings.Builder
+
+	sb.WriteString(TitleStyle.Render("WASTED SPACE BY FOLDER") + "\n\n")
+
+	if placeholder, pending := m.detailsPlaceholder(); pending {
+		return sb.String() + placeholder + "\n"
+	}
+
+	tree := reporter.BuildWasteTree(m.report)
+	if tree.Files == 0 || tree.Bytes == 0 {
+		sb.WriteString(MutedStyle.Render("No reclaimable space found.") + "\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("%s %s in %d files\n\n",
+		MutedStyle.Render("Reclaimable:"), StatStyle.Render(formatBytes(tree.Bytes)), tree.Files))
+
+	for _, row := range tree.Flatten(wasteTreeDepth) {
+		share := float64(row.Node.Bytes) / float64(tree.Bytes)
+		filled := min(max(int(share*wasteBarWidth), 1), wasteBarWidth)
+		bar := strings.Repeat("█", filled) + strings.Repeat("░", wasteBarWidth-filled)
+
+		// Hotter colours for folders holding a bigger share of the total
+		barStyle := MutedStyle
+		switch {
+		case share >= 0.25:
+			barStyle = ErrorStyle
+		case share >= 0.10:
+			barStyle = WarningStyle
+		case share >= 0.02:
+			barStyle = InfoStyle
+		}
+
+		name := row.Node.Name
+		if row.Depth == 0 {
+			name = HighlightStyle.Render(row.Node.Path)
+		}
+		sb.WriteString(fmt.Sprintf("%s %10s %5.1f%% %s%s\n",
+			barStyle.Render(bar),
+			StatStyle.Render(formatBytes(row.Node.Bytes)),
+			share*100,
+			strings.Repeat("  ", row.Depth),
+			name))
+	}
+
+	return sb.String()
+}
+
 // renderCompliance renders the compliance detail view
 func (m Model) renderCompliance() string {
 	var sb strings.Builder
 
 	sb.WriteString(TitleStyle.Render("NON-COMPLIANT FILES AND FOLDERS") + "\n\n")
 
+	if placeholder, pending := m.detailsPlaceholder(); pending {
+		return sb.String() + placeholder + "\n"
+	}
+
 	if len(m.report.ComplianceIssues) == 0 {
 		sb.WriteString(SuccessStyle.Render("✓ All files follow Jellyfin naming conventions") + "\n")
 		return sb.String()
 	}
 
-	sb.WriteString(InfoStyle.Render(fmt.Sprintf("Total issues: %d", len(m.report.ComplianceIssues))) + "\n\n")
+	issues := m.visibleComplianceIssues()
+	filterLabel := "all"
+	if m.severityFilter != "" {
+		filterLabel = m.severityFilter + " and above"
+	}
+	sb.WriteString(InfoStyle.Render(fmt.Sprintf("Total issues: %d", len(m.report.ComplianceIssues))) + "  " +
+		MutedStyle.Render(fmt.Sprintf("Showing: %d (%s)", len(issues), filterLabel)) + "\n\n")
+	sb.WriteString(m.renderTagPrompt())
 
-	for i, issue := range m.report.ComplianceIssues {
-		sb.WriteString(fmt.Sprintf("%s %s %s\n",
+	for i, issue := range issues {
+		severity := issue.EffectiveSeverity()
+		sb.WriteString(fmt.Sprintf("%s %s %s %s\n",
 			WarningStyle.Render(fmt.Sprintf("%d.", i+1)),
 			MutedStyle.Render(fmt.Sprintf("[%s]", strings.ToUpper(issue.Type))),
+			severityStyle(severity).Render(fmt.Sprintf("[%s]", strings.ToUpper(severity))),
 			ContentStyle.Render(issue.Problem)))
 
-		sb.WriteString(fmt.Sprintf("   %s %s\n",
+		sb.WriteString(fmt.Sprintf(" %s %s %s%s\n",
+			m.tagPrefix(i),
 			MutedStyle.Render("Current: "),
-			ErrorStyle.Render(issue.Path)))
+			ErrorStyle.Render(issue.Path),
+			m.tagSuffix(issue.Path)))
 
 		sb.WriteString(fmt.Sprintf("   %s %s\n",
 			MutedStyle.Render("Fixed:   "),
 			SuccessStyle.Render(issue.SuggestedPath)))
 
-		sb.WriteString(fmt.Sprintf("   %s %s\n\n",
+		sb.WriteString(fmt.Sprintf("   %s %s\n",
 			MutedStyle.Render("Action:  "),
 			InfoStyle.Render(issue.SuggestedAction)))
+
+		if m.explain {
+			sb.WriteString(renderExplanation(scanner.ExplainComplianceIssue(issue)))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// renderJellyfinFixes lists files Jellyfin failed to match, those with
+// compliance fixes first, since those renames restore missing metadata
+func (m Model) renderJellyfinFixes() string {
+	var sb strings.Builder
+
+	sb.WriteString(TitleStyle.Render("JELLYFIN: FIX THESE FIRST") + "\n\n")
+
+	if placeholder, pending := m.detailsPlaceholder(); pending {
+		return sb.String() + placeholder + "\n"
+	}
+
+	fixes := reporter.UnmatchedFixes(m.report)
+	fixable := 0
+	for _, fix := range fixes {
+		if len(fix.Issues) > 0 {
+			fixable++
+		}
+	}
+	sb.WriteString(InfoStyle.Render(fmt.Sprintf("Files Jellyfin did not match: %d", len(fixes))) + "  " +
+		MutedStyle.Render(fmt.Sprintf("With suggested fixes: %d", fixable)) + "\n")
+	sb.WriteString(MutedStyle.Render("Applying these renames lets Jellyfin match the files and fetch their metadata.") + "\n\n")
+
+	for i, fix := range fixes {
+		if i == fixable {
+			sb.WriteString(TitleStyle.Render("NO SUGGESTED FIX (check these by hand)") + "\n\n")
+		}
+		sb.WriteString(fmt.Sprintf("%s %s\n", WarningStyle.Render(fmt.Sprintf("%d.", i+1)), ContentStyle.Render(fix.Path)))
+		for _, issue := range fix.Issues {
+			severity := issue.EffectiveSeverity()
+			sb.WriteString(fmt.Sprintf("   %s %s\n",
+				severityStyle(severity).Render(fmt.Sprintf("[%s]", strings.ToUpper(severity))),
+				ContentStyle.Render(issue.Problem)))
+			sb.WriteString(fmt.Sprintf("   %s %s\n", MutedStyle.Render("Fixed:   "), SuccessStyle.Render(issue.SuggestedPath)))
+		}
+		sb.WriteString("\n")
+	}
+
+	if missing := m.report.Jellyfin.MissingOnDisk; len(missing) > 0 {
+		sb.WriteString(TitleStyle.Render("IN JELLYFIN BUT MISSING ON DISK") + "\n")
+		sb.WriteString(MutedStyle.Render("Rescan the library in Jellyfin to remove these entries.") + "\n\n")
+		for _, item := range missing {
+			sb.WriteString("  " + ErrorStyle.Render(item.Path) + "\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// excludedMark flags a duplicate group left out of cleans
+func excludedMark(excluded bool) string {
+	if !excluded {
+		return ""
+	}
+	return " " + WarningStyle.Render("[EXCLUDED: every file is kept]")
+}
+
+// renderProbe shows a duplicate's probed streams beneath its path
+func renderProbe(info *scanner.MediaInfo) string {
+	if info == nil {
+		return ""
+	}
+	return MutedStyle.Render("           probed: "+info.Summary()) + "\n"
+}
+
+// renderExplanation renders an indented explain block beneath a finding
+func renderExplanation(exp scanner.Explanation) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("   %s %s\n", MutedStyle.Render("Rule:    "), InfoStyle.Render(exp.Rule)))
+	sb.WriteString(fmt.Sprintf("   %s %s\n", MutedStyle.Render("         "), MutedStyle.Render(exp.Description)))
+	if exp.FindingID != "" {
+		sb.WriteString(fmt.Sprintf("   %s %s\n", MutedStyle.Render("ID:      "), MutedStyle.Render(exp.FindingID)))
+	}
+	for _, match := range exp.Matches {
+		sb.WriteString(fmt.Sprintf("   %s %s\n", MutedStyle.Render("Matched: "), WarningStyle.Render(match)))
+	}
+	for _, step := range exp.Steps {
+		sb.WriteString(fmt.Sprintf("   %s %s\n", MutedStyle.Render(fmt.Sprintf("%-26s", step.Label+":")), ContentStyle.Render(step.Value)))
 	}
 
 	return sb.String()
@@ -1022,12 +1687,23 @@ func (m Model) renderManualIntervention() string {
 				prefix,
 				MutedStyle.Render("API says:     "),
 				WarningStyle.Render("API returned conflicting results")))
+		} else if resolution.APIStatus != "" {
+			sb.WriteString(fmt.Sprintf("%s   %s %s\n",
+				prefix,
+				MutedStyle.Render("API says:     "),
+				WarningStyle.Render("Verification "+resolution.APIStatus+" (retried on the next scan)")))
 		} else {
 			sb.WriteString(fmt.Sprintf("%s   %s %s\n",
 				prefix,
 				MutedStyle.Render("API says:     "),
 				MutedStyle.Render("Could not verify (API key not configured or failed)")))
 		}
+		if resolution.JellyfinTitle != "" {
+			sb.WriteString(fmt.Sprintf("%s   %s %s\n",
+				prefix,
+				MutedStyle.Render("Jellyfin has: "),
+				SuccessStyle.Render(resolution.JellyfinTitle)))
+		}
 
 		if isSelected && m.editingTitle {
 			sb.WriteString(fmt.Sprintf("%s   %s %s\n",
@@ -1081,33 +1757,13 @@ func (m Model) renderManualIntervention() string {
 func (m Model) renderScanning() string {
 	var sb strings.Builder
 
-	// ASCII header
-	sb.WriteString(FormatASCIIHeader() + "\n\n")
-
-	// Progress bar
-	progressBar := renderProgressBar(m.progressPercent, 50)
-	sb.WriteString(progressBar + "\n")
-	sb.WriteString(fmt.Sprintf("  %s %.1f%%\n\n", m.currentProgress, m.progressPercent))
+	// Banner
+	sb.WriteString(Banner(m.width) + "\n\n")
 
-	// Log viewport (last 20 lines)
-	sb.WriteString(TitleStyle.Render("SCAN LOG") + "\n")
-	sb.WriteString(strings.Repeat("─", 80) + "\n")
-
-	startIdx := 0
-	if len(m.scanLogs) > 20 {
-		startIdx = len(m.scanLogs) - 20
-	}
-
-	for i := startIdx; i < len(m.scanLogs); i++ {
-		entry := m.scanLogs[i]
-		var lineStyle = MutedStyle
-		if entry.Severity == "error" {
-			lineStyle = ErrorStyle
-		} else if entry.Severity == "warn" {
-			lineStyle = WarningStyle
-		}
-		sb.WriteString(lineStyle.Render(fmt.Sprintf("%s %s [%s] %s", entry.Timestamp, entry.Operation, strings.ToUpper(entry.Severity), entry.Message)) + "\n")
-	}
+	// Progress bar, stats and log tail
+	progress := m.progress
+	progress.LogTitle = "SCAN LOG"
+	sb.WriteString(progress.View(0) + "\n")
 
 	if m.cancelled {
 		sb.WriteString("\n" + ErrorStyle.Render("Scan cancelled by user") + "\n")
@@ -1116,26 +1772,6 @@ func (m Model) renderScanning() string {
 	return sb.String()
 }
 
-// renderProgressBar creates a text-based progress bar
-func renderProgressBar(percent float64, width int) string {
-	filled := int((percent / 100.0) * float64(width))
-	if filled > width {
-		filled = width
-	}
-
-	bar := "["
-	for i := 0; i < width; i++ {
-		if i < filled {
-			bar += "█"
-		} else {
-			bar += " "
-		}
-	}
-	bar += "]"
-
-	return SuccessStyle.Render(bar)
-}
-
 // Helper functions
 
 func formatBytes(bytes int64) string {
@@ -1157,6 +1793,23 @@ func getTopOffenders(report reporter.Report) []reporter.Offender {
 	return reporter.GetTopOffenders(report)
 }
 
+// visibleComplianceIssues returns compliance issues passing the severity filter
+func (m Model) visibleComplianceIssues() []scanner.ComplianceIssue {
+	return scanner.FilterIssuesByMinSeverity(m.report.ComplianceIssues, m.severityFilter)
+}
+
+// severityStyle picks the style used for a compliance severity marker
+func severityStyle(severity string) lipgloss.Style {
+	switch severity {
+	case scanner.IssueSeverityError:
+		return ErrorStyle
+	case scanner.IssueSeverityWarn:
+		return WarningStyle
+	default:
+		return InfoStyle
+	}
+}
+
 // ShouldClean returns whether the user requested a clean operation
 func (m Model) ShouldClean() bool {
 	return m.shouldClean
@@ -1188,9 +1841,12 @@ func (m Model) renderCleanOptions() string {
 		sb.WriteString("\n")
 	}
 
-	if len(m.report.ComplianceIssues) > 0 {
+	if issues := m.visibleComplianceIssues(); len(issues) > 0 {
 		sb.WriteString(MutedStyle.Render("Compliance Fixes:") + "\n")
-		sb.WriteString(fmt.Sprintf("  • %s files/folders to be renamed or reorganized\n", StatStyle.Render(fmt.Sprintf("%d", len(m.report.ComplianceIssues)))))
+		sb.WriteString(fmt.Sprintf("  • %s files/folders to be renamed or reorganized\n", StatStyle.Render(fmt.Sprintf("%d", len(issues)))))
+		if m.severityFilter != "" {
+			sb.WriteString(fmt.Sprintf("  • Only %s and above (severity filter)\n", severityStyle(m.severityFilter).Render(m.severityFilter)))
+		}
 		sb.WriteString("\n")
 	}
 
@@ -1218,10 +1874,16 @@ func (m Model) renderCleanOptions() string {
 	if m.cleanOptionCursor == 1 {
 		selectedStyle = WarningStyle
 	}
-	sb.WriteString(cursor + " " + selectedStyle.Render("2. FULL CLEAN") + " - Execute all operations\n")
-	sb.WriteString("     • Deletes duplicate files\n")
-	sb.WriteString("     • Renames/reorganizes for compliance\n")
-	sb.WriteString("     • ⚠ CANNOT BE UNDONE\n\n")
+	if m.firstRun {
+		sb.WriteString(cursor + " " + MutedStyle.Render("2. FULL CLEAN - Locked on a new install") + "\n")
+		sb.WriteString(MutedStyle.Render("     • Run the dry run first: a walkthrough explains its results") + "\n")
+		sb.WriteString(MutedStyle.Render("     • Then set first_run_walkthrough = false under [ui] to unlock") + "\n\n")
+	} else {
+		sb.WriteString(cursor + " " + selectedStyle.Render("2. FULL CLEAN") + " - Execute all operations\n")
+		sb.WriteString("     • Deletes duplicate files\n")
+		sb.WriteString("     • Renames/reorganizes for compliance\n")
+		sb.WriteString("     • ⚠ CANNOT BE UNDONE\n\n")
+	}
 
 	sb.WriteString(strings.Repeat("─", 80) + "\n\n")
 
@@ -1247,9 +1909,9 @@ func (m Model) renderCleanConfirm() string {
 		sb.WriteString("\n")
 	}
 
-	if len(m.report.ComplianceIssues) > 0 {
+	if issues := m.visibleComplianceIssues(); len(issues) > 0 {
 		sb.WriteString(InfoStyle.Render("Compliance Fixes:") + "\n")
-		sb.WriteString(fmt.Sprintf("  • %s files/folders will be renamed or reorganized\n", StatStyle.Render(fmt.Sprintf("%d", len(m.report.ComplianceIssues)))))
+		sb.WriteString(fmt.Sprintf("  • %s files/folders will be renamed or reorganized\n", StatStyle.Render(fmt.Sprintf("%d", len(issues)))))
 		sb.WriteString("\n")
 	}
 
@@ -1266,8 +1928,8 @@ func (m Model) renderCleanConfirm() string {
 func (m Model) renderCleaning() string {
 	var sb strings.Builder
 
-	// ASCII header
-	sb.WriteString(FormatASCIIHeader() + "\n\n")
+	// Banner
+	sb.WriteString(Banner(m.width) + "\n\n")
 
 	if m.cleaning {
 		// Show title based on mode
@@ -1278,44 +1940,8 @@ func (m Model) renderCleaning() string {
 			sb.WriteString(TitleStyle.Render("CLEANING IN PROGRESS") + "\n\n")
 		}
 
-		// Show progress bar
-		progressBar := renderProgressBar(m.progressPercent, 50)
-		sb.WriteString(progressBar + "\n")
-		sb.WriteString(fmt.Sprintf("  %s %.1f%%\n\n", m.currentProgress, m.progressPercent))
-
-		// Show cleaning log (last 20 lines)
-		if m.dryRun {
-			sb.WriteString(TitleStyle.Render("PREVIEW LOG (No Changes Made)") + "\n")
-		} else {
-			sb.WriteString(TitleStyle.Render("CLEANING LOG") + "\n")
-		}
-		sb.WriteString(strings.Repeat("─", 80) + "\n")
-
-		startIdx := 0
-		if len(m.scanLogs) > 20 {
-			startIdx = len(m.scanLogs) - 20
-		}
-
-		for _, log := range m.scanLogs[startIdx:] {
-			var prefix string
-			switch log.Severity {
-			case "error":
-				prefix = ErrorStyle.Render("✗")
-			case "warn":
-				prefix = WarningStyle.Render("⚠")
-			case "success":
-				prefix = SuccessStyle.Render("✓")
-			default:
-				prefix = InfoStyle.Render("•")
-			}
-
-			timeStr := ""
-			if log.Timestamp != "" {
-				timeStr = MutedStyle.Render(fmt.Sprintf("[%s] ", log.Timestamp))
-			}
-
-			sb.WriteString(fmt.Sprintf("%s %s%s\n", prefix, timeStr, log.Message))
-		}
+		// Show progress bar, stats and cleaning log
+		sb.WriteString(m.progress.View(0))
 	} else {
 		// Cleaning complete
 		if m.dryRun {
@@ -1325,7 +1951,14 @@ func (m Model) renderCleaning() string {
 			sb.WriteString(TitleStyle.Render("CLEANUP COMPLETE") + "\n\n")
 		}
 		sb.WriteString(m.cleanResult + "\n\n")
-		sb.WriteString(MutedStyle.Render("Press any key to exit") + "\n")
+		if m.rescanStatus != "" {
+			sb.WriteString(m.rescanStatus + "\n\n")
+		}
+		if m.dryRun && m.firstRun {
+			sb.WriteString(MutedStyle.Render("Press any key for a walkthrough of these results") + "\n")
+		} else {
+			sb.WriteString(MutedStyle.Render("Press any key to exit") + "\n")
+		}
 	}
 
 	return sb.String()
@@ -1336,26 +1969,45 @@ func (m *Model) runCleaning() tea.Cmd {
 	// Configure cleaner with safe defaults
 	cfg := cleaner.DefaultConfig()
 	cfg.DryRun = m.dryRun // Use the dryRun flag from model
+	cfg.OrphanFolders = m.report.OrphanFolders
 
-	// Create progress channel and store in model
-	m.cleanProgressCh = make(chan scanner.ScanProgress, 100)
+	// Create progress and result channels and store in model
+	progressCh := make(chan scanner.ScanProgress, 100)
+	doneCh := make(chan cleanCompleteMsg, 1)
+	m.cleanProgressCh = progressCh
+	m.cleanDoneCh = doneCh
+	report := m.report
+	reportPath := m.reportPath
+	issues := m.visibleComplianceIssues()
 
 	// Start cleaning in goroutine
 	go func() {
+		// The result is queued before the progress channel closes
+		done := cleanCompleteMsg{}
+		defer close(progressCh)
+		defer func() { doneCh <- done }()
+
 		result, err := cleaner.CleanWithProgress(
-			m.report.MovieDuplicates,
-			m.report.TVDuplicates,
-			m.report.ComplianceIssues,
+			report.MovieDuplicates,
+			report.TVDuplicates,
+			issues,
 			cfg,
-			m.cleanProgressCh,
+			progressCh,
 		)
-		close(m.cleanProgressCh)
-
-		// Send final result through a special completion progress message
 		if err != nil {
-			// Error case will be handled by the final message
+			done.err = err
 			return
 		}
+		if !result.DryRun {
+			for _, op := range result.Operations {
+				if op.Completed {
+					done.changed = append(done.changed, op.Source)
+					if op.Destination != "" {
+						done.changed = append(done.changed, op.Destination)
+					}
+				}
+			}
+		}
 
 		// Build result summary and send as final progress message
 		var sb strings.Builder
@@ -1365,37 +2017,108 @@ func (m *Model) runCleaning() tea.Cmd {
 
 			// Calculate totals from operations
 			totalDuplicates := 0
+			totalLinked := 0
+			totalVersions := 0
+			totalFolders := 0
 			totalCompliance := 0
 			for _, op := range result.Operations {
-				if op.Type == "delete" {
+				if op.Status != "" {
+					continue
+				}
+				switch op.Type {
+				case "delete":
 					totalDuplicates++
-				} else {
+				case "link":
+					totalLinked++
+				case "version":
+					totalVersions++
+				case "delete-folder":
+					totalFolders++
+				default:
 					totalCompliance++
 				}
 			}
 
 			sb.WriteString(fmt.Sprintf("  • Duplicates would be deleted: %s\n", StatStyle.Render(fmt.Sprintf("%d", totalDuplicates))))
+			if totalLinked > 0 {
+				sb.WriteString(fmt.Sprintf("  • Duplicates would be replaced with hardlinks: %s\n", StatStyle.Render(fmt.Sprintf("%d", totalLinked))))
+			}
+			if totalVersions > 0 {
+				sb.WriteString(fmt.Sprintf("  • Copies would be kept as versions: %s\n", StatStyle.Render(fmt.Sprintf("%d", totalVersions))))
+			}
 			sb.WriteString(fmt.Sprintf("  • Compliance issues would be fixed: %s\n", StatStyle.Render(fmt.Sprintf("%d", totalCompliance))))
+			if totalFolders > 0 {
+				sb.WriteString(fmt.Sprintf("  • Orphaned folders would be deleted: %s\n", StatStyle.Render(fmt.Sprintf("%d", totalFolders))))
+			}
 
 			// Calculate potential space from duplicate operations
-			potentialSpace := int64(0)
-			for _, dup := range m.report.MovieDuplicates {
-				for i := 1; i < len(dup.Files); i++ {
-					potentialSpace += dup.Files[i].Size
-				}
-			}
-			for _, dup := range m.report.TVDuplicates {
-				for i := 1; i < len(dup.Files); i++ {
-					potentialSpace += dup.Files[i].Size
+			potentialSpace := scanner.GetSpaceToFree(report.MovieDuplicates)
+			for _, dup := range report.TVDuplicates {
+				if dup.Excluded {
+					continue
 				}
+				potentialSpace += dup.Reclaimable()
 			}
 			sb.WriteString(fmt.Sprintf("  • Space would be freed: %s\n", SuccessStyle.Render(formatBytes(potentialSpace))))
 		} else {
 			sb.WriteString(SuccessStyle.Render("✓ Cleanup completed successfully!") + "\n\n")
 			sb.WriteString(InfoStyle.Render("Results:") + "\n")
 			sb.WriteString(fmt.Sprintf("  • Duplicates deleted: %s\n", StatStyle.Render(fmt.Sprintf("%d", result.DuplicatesDeleted))))
+			if result.DuplicatesLinked > 0 {
+				sb.WriteString(fmt.Sprintf("  • Duplicates replaced with hardlinks: %s\n", StatStyle.Render(fmt.Sprintf("%d", result.DuplicatesLinked))))
+			}
+			if result.VersionsKept > 0 {
+				sb.WriteString(fmt.Sprintf("  • Copies kept as versions: %s\n", StatStyle.Render(fmt.Sprintf("%d", result.VersionsKept))))
+			}
+			if result.CompanionsMerged > 0 {
+				sb.WriteString(fmt.Sprintf("  • Subtitles and artwork moved to keepers: %s\n", StatStyle.Render(fmt.Sprintf("%d", result.CompanionsMerged))))
+			}
 			sb.WriteString(fmt.Sprintf("  • Compliance fixed: %s\n", StatStyle.Render(fmt.Sprintf("%d", result.ComplianceFixed))))
+			if result.FoldersRemoved > 0 {
+				sb.WriteString(fmt.Sprintf("  • Orphaned folders deleted: %s\n", StatStyle.Render(fmt.Sprintf("%d", result.FoldersRemoved))))
+			}
 			sb.WriteString(fmt.Sprintf("  • Space freed: %s\n", SuccessStyle.Render(formatBytes(result.SpaceFreed))))
+			if result.JournalID != "" {
+				sb.WriteString(fmt.Sprintf("  • %s\n", MutedStyle.Render("Undo from the main menu or with: jellysink undo "+result.JournalID)))
+			}
+			if result.LibraryRefreshed {
+				sb.WriteString(fmt.Sprintf("  • %s\n", SuccessStyle.Render("Jellyfin library scan requested")))
+			} else if result.RefreshErr != nil {
+				sb.WriteString(fmt.Sprintf("  • %s\n", WarningStyle.Render(fmt.Sprintf("Jellyfin library scan request failed: %v", result.RefreshErr))))
+			}
+		}
+
+		if len(result.Deferred) > 0 {
+			sb.WriteString(fmt.Sprintf("\n%s\n", WarningStyle.Render(fmt.Sprintf("⚠ %d file(s) deferred because they are in use:", len(result.Deferred)))))
+			for i, path := range result.Deferred {
+				if i >= 5 {
+					sb.WriteString(MutedStyle.Render(fmt.Sprintf("  ... and %d more\n", len(result.Deferred)-5)))
+					break
+				}
+				sb.WriteString(MutedStyle.Render(fmt.Sprintf("  • %s\n", path)))
+			}
+		}
+
+		if len(result.Importing) > 0 {
+			sb.WriteString(fmt.Sprintf("\n%s\n", WarningStyle.Render(fmt.Sprintf("⚠ %d file(s) deferred while an import writes into their folder (next scan picks them up):", len(result.Importing)))))
+			for i, path := range result.Importing {
+				if i >= 5 {
+					sb.WriteString(MutedStyle.Render(fmt.Sprintf("  ... and %d more\n", len(result.Importing)-5)))
+					break
+				}
+				sb.WriteString(MutedStyle.Render(fmt.Sprintf("  • %s\n", path)))
+			}
+		}
+
+		if len(result.Protected) > 0 {
+			sb.WriteString(fmt.Sprintf("\n%s\n", WarningStyle.Render(fmt.Sprintf("⚠ %d path(s) skipped because they are protected:", len(result.Protected)))))
+			for i, path := range result.Protected {
+				if i >= 5 {
+					sb.WriteString(MutedStyle.Render(fmt.Sprintf("  ... and %d more\n", len(result.Protected)-5)))
+					break
+				}
+				sb.WriteString(MutedStyle.Render(fmt.Sprintf("  • %s\n", path)))
+			}
 		}
 
 		if len(result.Errors) > 0 {
@@ -1409,22 +2132,35 @@ func (m *Model) runCleaning() tea.Cmd {
 			}
 		}
 
-		m.cleanResult = sb.String()
+		// Record a real clean in the report file so later views show it
+		if !result.DryRun {
+			summary := reporter.NewCleanSummary(result.DuplicatesDeleted, result.ComplianceFixed, result.SpaceFreed, result.Errors)
+			summary.Deferred = result.Deferred
+			summary.Importing = result.Importing
+			summary.Protected = result.Protected
+			summary.VersionsKept = result.VersionsKept
+			summary.FoldersRemoved = result.FoldersRemoved
+			done.summary = &summary
+			if reportPath != "" {
+				if err := reporter.MarkCleaned(reportPath, summary); err != nil {
+					sb.WriteString("\n" + WarningStyle.Render(fmt.Sprintf("⚠ Could not mark report as cleaned: %v", err)) + "\n")
+				}
+			}
+		}
+
+		done.result = sb.String()
 	}()
 
 	// Wait for first progress message
-	return waitForCleanProgress(m.cleanProgressCh)
+	return waitForCleanProgress(m.cleanProgressCh, m.cleanDoneCh)
 }
 
-func waitForCleanProgress(progressCh chan scanner.ScanProgress) tea.Cmd {
+func waitForCleanProgress(progressCh chan scanner.ScanProgress, doneCh chan cleanCompleteMsg) tea.Cmd {
 	return func() tea.Msg {
 		progress, ok := <-progressCh
 		if !ok {
 			// Channel closed, cleaning is complete
-			return cleanCompleteMsg{
-				result: "", // Result already set in model
-				err:    nil,
-			}
+			return <-doneCh
 		}
 		return cleanProgressMsg(progress)
 	}
@@ -1432,58 +2168,31 @@ func waitForCleanProgress(progressCh chan scanner.ScanProgress) tea.Cmd {
 
 // cleanCompleteMsg is sent when cleaning finishes
 type cleanCompleteMsg struct {
-	result string
-	err    error
+	result  string
+	summary *reporter.CleanSummary // nil for dry runs and failures
+	changed []string               // paths a real clean deleted, renamed or moved
+	err     error
 }
 
 // renderBatchRenaming renders the batch rename progress view
 func (m Model) renderBatchRenaming() string {
 	var sb strings.Builder
 
-	// ASCII header
-	sb.WriteString(FormatASCIIHeader() + "\n\n")
+	// Banner
+	sb.WriteString(Banner(m.width) + "\n\n")
 
 	if m.renaming {
 		sb.WriteString(TitleStyle.Render("BATCH RENAMING IN PROGRESS") + "\n\n")
 
-		// Show progress bar
-		progressBar := renderProgressBar(m.progressPercent, 50)
-		sb.WriteString(progressBar + "\n")
-		sb.WriteString(fmt.Sprintf("  %s %.1f%%\n\n", m.currentProgress, m.progressPercent))
-
-		// Show rename log (last 20 lines)
-		sb.WriteString(TitleStyle.Render("RENAME LOG") + "\n")
-		sb.WriteString(strings.Repeat("─", 80) + "\n")
-
-		startIdx := 0
-		if len(m.scanLogs) > 20 {
-			startIdx = len(m.scanLogs) - 20
-		}
-
-		for _, log := range m.scanLogs[startIdx:] {
-			var prefix string
-			switch log.Severity {
-			case "error":
-				prefix = ErrorStyle.Render("✗")
-			case "warn":
-				prefix = WarningStyle.Render("⚠")
-			case "success":
-				prefix = SuccessStyle.Render("✓")
-			default:
-				prefix = InfoStyle.Render("•")
-			}
-
-			timeStr := ""
-			if log.Timestamp != "" {
-				timeStr = MutedStyle.Render(fmt.Sprintf("[%s] ", log.Timestamp))
-			}
-
-			sb.WriteString(fmt.Sprintf("%s %s%s\n", prefix, timeStr, log.Message))
-		}
+		// Show progress bar, stats and rename log
+		sb.WriteString(m.progress.View(0))
 	} else {
 		// Renaming complete
 		sb.WriteString(TitleStyle.Render("BATCH RENAME COMPLETE") + "\n\n")
 		sb.WriteString(m.renameResult + "\n\n")
+		if m.rescanStatus != "" {
+			sb.WriteString(m.rescanStatus + "\n\n")
+		}
 		sb.WriteString(MutedStyle.Render("Press Enter to return to summary") + "\n")
 	}
 
@@ -1492,18 +2201,27 @@ func (m Model) renderBatchRenaming() string {
 
 // runBatchRename executes the batch rename operation
 func (m *Model) runBatchRename() tea.Cmd {
-	// Create progress channel and store in model
-	m.renameProgressCh = make(chan scanner.ScanProgress, 100)
+	// Create progress and result channels and store in model
+	progressCh := make(chan scanner.ScanProgress, 100)
+	doneCh := make(chan renameCompleteMsg, 1)
+	m.renameProgressCh = progressCh
+	m.renameDoneCh = doneCh
 
 	// Start renaming in goroutine
 	go func() {
+		// The result is queued before the progress channel closes
+		done := renameCompleteMsg{}
+		defer close(progressCh)
+		defer func() { doneCh <- done }()
+
 		var allResults []scanner.RenameResult
 		var allErrors []error
 		totalConflicts := 0
 		successCount := 0
 		errorCount := 0
+		logPath := oplog.DefaultPath(oplog.RenameLog)
 
-		pr := scanner.NewProgressReporter(m.renameProgressCh, "batch_rename")
+		pr := scanner.NewProgressReporter(progressCh, scanner.OpBatchRename)
 		pr.Start(len(m.conflicts), "Starting batch rename")
 
 		// Process each conflict resolution
@@ -1534,7 +2252,7 @@ func (m *Model) runBatchRename() tea.Cmd {
 			}
 
 			if newTitle == "" {
-				pr.SendSeverityImmediate("error", fmt.Sprintf("No valid title for: %s", conflict.FolderPath))
+				pr.SendSeverityImmediate(scanner.SeverityError, fmt.Sprintf("No valid title for: %s", conflict.FolderPath))
 				errorCount++
 				continue
 			}
@@ -1547,7 +2265,7 @@ func (m *Model) runBatchRename() tea.Cmd {
 			// If FolderPath is already a storage root (e.g., /mnt/STORAGE1), then:
 			// basePath becomes /mnt, which is DISASTROUS
 			if conflict.FolderPath == "" {
-				pr.SendSeverityImmediate("error", "Empty folder path - skipping")
+				pr.SendSeverityImmediate(scanner.SeverityError, "Empty folder path - skipping")
 				errorCount++
 				continue
 			}
@@ -1556,7 +2274,7 @@ func (m *Model) runBatchRename() tea.Cmd {
 			// e.g., /mnt/STORAGE1/TVSHOWS/Show Name (Year)
 			parts := strings.Split(strings.TrimPrefix(conflict.FolderPath, "/"), "/")
 			if len(parts) < 3 {
-				pr.SendSeverityImmediate("error", fmt.Sprintf("Invalid folder depth (too shallow): %s - SKIPPING", conflict.FolderPath))
+				pr.SendSeverityImmediate(scanner.SeverityError, fmt.Sprintf("Invalid folder depth (too shallow): %s - SKIPPING", conflict.FolderPath))
 				errorCount++
 				continue
 			}
@@ -1568,7 +2286,7 @@ func (m *Model) runBatchRename() tea.Cmd {
 			if !strings.Contains(strings.ToUpper(basePathName), "TVSHOW") &&
 				!strings.Contains(strings.ToUpper(basePathName), "TV") &&
 				!strings.Contains(strings.ToUpper(basePathName), "SERIES") {
-				pr.SendSeverityImmediate("error", fmt.Sprintf("basePath doesn't look like TV library: %s - SKIPPING", basePath))
+				pr.SendSeverityImmediate(scanner.SeverityError, fmt.Sprintf("basePath doesn't look like TV library: %s - SKIPPING", basePath))
 				errorCount++
 				continue
 			}
@@ -1585,22 +2303,24 @@ func (m *Model) runBatchRename() tea.Cmd {
 			)
 
 			if err != nil {
-				pr.SendSeverityImmediate("error", fmt.Sprintf("Failed to rename %s: %v", oldTitle, err))
+				pr.SendSeverityImmediate(scanner.SeverityError, fmt.Sprintf("Failed to rename %s: %v", oldTitle, err))
 				allErrors = append(allErrors, err)
 				errorCount++
 			} else if len(results) == 0 {
-				pr.SendSeverityImmediate("warn", fmt.Sprintf("No files or folder found to rename for: %s", oldTitle))
+				pr.SendSeverityImmediate(scanner.SeverityWarn, fmt.Sprintf("No files or folder found to rename for: %s", oldTitle))
 				allErrors = append(allErrors, fmt.Errorf("no files renamed for %s", oldTitle))
 				errorCount++
 			} else {
 				allResults = append(allResults, results...)
 				successCount++
-				pr.SendSeverityImmediate("success", fmt.Sprintf("Renamed: %s → %s (%d files)", oldTitle, newTitle, len(results)))
+				pr.SendSeverityImmediate(scanner.SeveritySuccess, fmt.Sprintf("Renamed: %s → %s (%d files)", oldTitle, newTitle, len(results)))
+			}
+			if err := scanner.LogRenames(logPath, results); err != nil {
+				pr.SendSeverityImmediate(scanner.SeverityWarn, fmt.Sprintf("Failed to write rename log: %v", err))
 			}
 		}
 
 		pr.Complete("Batch rename complete")
-		close(m.renameProgressCh)
 
 		// Build result summary
 		var sb strings.Builder
@@ -1617,6 +2337,7 @@ func (m *Model) runBatchRename() tea.Cmd {
 			sb.WriteString(fmt.Sprintf("  • Failed: %s\n", ErrorStyle.Render(fmt.Sprintf("%d", errorCount))))
 		}
 		sb.WriteString(fmt.Sprintf("  • Total file operations: %s\n", StatStyle.Render(fmt.Sprintf("%d", len(allResults)))))
+		sb.WriteString(fmt.Sprintf("  • Operation log: %s\n", MutedStyle.Render(logPath)))
 
 		if len(allErrors) > 0 {
 			sb.WriteString(fmt.Sprintf("\n%s\n", ErrorStyle.Render(fmt.Sprintf("✗ %d error(s) occurred:", len(allErrors)))))
@@ -1629,22 +2350,25 @@ func (m *Model) runBatchRename() tea.Cmd {
 			}
 		}
 
-		m.renameResult = sb.String()
-		m.renameErrors = allErrors
+		done.result = sb.String()
+		done.errors = allErrors
+		for _, r := range allResults {
+			if r.Success {
+				done.changed = append(done.changed, r.OldPath, r.NewPath)
+			}
+		}
 	}()
 
 	// Wait for first progress message
-	return waitForRenameProgress(m.renameProgressCh)
+	return waitForRenameProgress(m.renameProgressCh, m.renameDoneCh)
 }
 
-func waitForRenameProgress(progressCh chan scanner.ScanProgress) tea.Cmd {
+func waitForRenameProgress(progressCh chan scanner.ScanProgress, doneCh chan renameCompleteMsg) tea.Cmd {
 	return func() tea.Msg {
 		progress, ok := <-progressCh
 		if !ok {
 			// Channel closed, renaming is complete
-			return renameCompleteMsg{
-				result: "", // Result already set in model
-			}
+			return <-doneCh
 		}
 		return renameProgressMsg(progress)
 	}
@@ -1663,7 +2387,11 @@ func (m Model) renderConflictReview() string {
 
 	sb.WriteString(TitleStyle.Render("TV SHOW TITLE CONFLICT") + "\n\n")
 
-	sb.WriteString(InfoStyle.Render(fmt.Sprintf("Reviewing conflict %d of %d", m.currentConflictIndex+1, len(m.conflicts))) + "\n\n")
+	sb.WriteString(InfoStyle.Render(fmt.Sprintf("Reviewing conflict %d of %d", m.currentConflictIndex+1, len(m.conflicts))) + "\n")
+	if conflict.FolderPath != "" {
+		sb.WriteString(MutedStyle.Render(conflict.FolderPath+" - ") + StatStyle.Render(impactOf(conflict, m.report.ComplianceIssues).String()) + "\n")
+	}
+	sb.WriteString("\n")
 
 	sb.WriteString(HighlightStyle.Render("⚠ CONFLICTING TITLES DETECTED") + "\n\n")
 
@@ -1715,9 +2443,14 @@ func (m Model) renderConflictReview() string {
 
 	if conflict.APIVerified {
 		sb.WriteString(WarningStyle.Render("⚠ API returned conflicting results") + "\n")
+	} else if conflict.APIStatus != "" {
+		sb.WriteString(WarningStyle.Render("⚠ API verification "+conflict.APIStatus) + "\n")
 	} else {
 		sb.WriteString(MutedStyle.Render("ℹ API verification unavailable") + "\n")
 	}
+	if conflict.JellyfinTitle != "" {
+		sb.WriteString(SuccessStyle.Render("✓ Jellyfin already has this show as: "+conflict.JellyfinTitle) + "\n")
+	}
 
 	sb.WriteString("\n")
 	sb.WriteString(strings.Repeat("─", 80) + "\n\n")
@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Nomadcxx/jellysink/internal/cleaner"
+)
+
+// undoJournalMsg carries the newest clean that can still be undone
+type undoJournalMsg struct {
+	journal *cleaner.Journal
+	err     error
+}
+
+// undoDoneMsg carries the outcome of an undo
+type undoDoneMsg struct {
+	result cleaner.UndoResult
+	err    error
+}
+
+// UndoModel shows the last clean and replays its journal in reverse on confirmation
+type UndoModel struct {
+	width   int
+	height  int
+	loading bool
+	running bool
+	journal *cleaner.Journal
+	err     error
+	done    *cleaner.UndoResult
+}
+
+// NewUndoModel creates the "Undo Last Clean" screen
+func NewUndoModel() UndoModel {
+	return UndoModel{loading: true}
+}
+
+func (m UndoModel) Init() tea.Cmd {
+	return loadLastJournal
+}
+
+// loadLastJournal finds the newest clean with operations left to undo
+func loadLastJournal() tea.Msg {
+	journal, err := cleaner.LatestJournal(cleaner.DefaultConfig().JournalDir)
+	return undoJournalMsg{journal: journal, err: err}
+}
+
+// runUndo restores the loaded clean
+func (m UndoModel) runUndo() tea.Msg {
+	result, err := cleaner.Undo(cleaner.DefaultConfig(), m.journal.ID)
+	return undoDoneMsg{result: result, err: err}
+}
+
+func (m UndoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.running {
+			return m, nil
+		}
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			return m, Pop()
+		case "enter":
+			if m.journal != nil && m.done == nil && m.err == nil {
+				m.running = true
+				return m, m.runUndo
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case undoJournalMsg:
+		m.loading = false
+		m.journal = msg.journal
+		m.err = msg.err
+
+	case undoDoneMsg:
+		m.running = false
+		if msg.err != nil {
+			m.err = msg.err
+		} else {
+			m.done = &msg.result
+		}
+	}
+	return m, nil
+}
+
+func (m UndoModel) View() string {
+	var content strings.Builder
+
+	content.WriteString(Banner(m.width))
+	content.WriteString("\n\n")
+	content.WriteString(TitleStyle.Render("UNDO LAST CLEAN") + "\n\n")
+
+	switch {
+	case m.loading:
+		content.WriteString(MutedStyle.Render("Looking for the last clean...") + "\n")
+	case m.running:
+		content.WriteString(FormatStatusWarn("Restoring files...") + "\n")
+	case m.done != nil:
+		content.WriteString(FormatStatusOK(fmt.Sprintf("Restored %d operations from clean %s", m.done.Restored, m.done.JournalID)) + "\n")
+		if m.done.LibraryRefreshed {
+			content.WriteString(FormatStatusOK("Jellyfin library scan requested") + "\n")
+		} else if m.done.RefreshErr != nil {
+			content.WriteString(FormatStatusWarn(fmt.Sprintf("Jellyfin library scan request failed: %v", m.done.RefreshErr)) + "\n")
+		}
+		if len(m.done.Errors) > 0 {
+			content.WriteString("\n" + WarningStyle.Render(fmt.Sprintf("⚠ %d not restored (undo again once resolved):", len(m.done.Errors))) + "\n")
+			for i, err := range m.done.Errors {
+				if i >= 5 {
+					content.WriteString(MutedStyle.Render(fmt.Sprintf("  ... and %d more", len(m.done.Errors)-5)) + "\n")
+					break
+				}
+				content.WriteString(ErrorStyle.Render(fmt.Sprintf("  • %v", err)) + "\n")
+			}
+		}
+	case m.err != nil:
+		content.WriteString(FormatStatusInfo(m.err.Error()) + "\n")
+	default:
+		content.WriteString(InfoStyle.Render("Clean: ") + ContentStyle.Render(m.journal.ID) + "\n")
+		content.WriteString(InfoStyle.Render("Started: ") + ContentStyle.Render(m.journal.StartedAt.Format("2006-01-02 15:04:05")) + "\n")
+		content.WriteString(InfoStyle.Render("Operations to restore: ") + StatStyle.Render(fmt.Sprintf("%d", m.journal.Pending())) + "\n\n")
+
+		counts := make(map[string]int)
+		for _, entry := range m.journal.Entries {
+			if !entry.Undone && !entry.Purged {
+				counts[entry.Type]++
+			}
+		}
+		for _, kind := range []string{"delete", "delete-folder", "version", "rename", "reorganize", "unlink", "link"} {
+			if counts[kind] > 0 {
+				content.WriteString(fmt.Sprintf("  • %s: %s\n", undoLabel(kind), StatStyle.Render(fmt.Sprintf("%d", counts[kind]))))
+			}
+		}
+		content.WriteString("\n" + WarningStyle.Render("Deleted files come back from the trash and renamed files return to their old names.") + "\n")
+	}
+
+	content.WriteString("\n")
+	if m.journal != nil && m.done == nil && m.err == nil && !m.running {
+		content.WriteString(MutedStyle.Render("Enter: Undo  •  Esc: Back  •  Q/Ctrl+C: Quit"))
+	} else {
+		content.WriteString(MutedStyle.Render("Esc: Back  •  Q/Ctrl+C: Quit"))
+	}
+
+	mainStyle := lipgloss.NewStyle().
+		Padding(1, 2).
+		Width(m.width - 4)
+
+	return mainStyle.Render(content.String())
+}
+
+// undoLabel describes a journaled operation type for the undo summary
+func undoLabel(kind string) string {
+	switch kind {
+	case "delete":
+		return "Deleted files to restore"
+	case "delete-folder":
+		return "Orphaned folders to restore"
+	case "version":
+		return "Versions to rename back"
+	case "unlink":
+		return "Hardlinks to recreate"
+	case "link":
+		return "Duplicates hardlinked to their keeper"
+	default:
+		return "Compliance fixes to revert"
+	}
+}
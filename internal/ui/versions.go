@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// movieGroupAtCursor returns the index of the movie duplicate group holding
+// the tag cursor, or -1 when the cursor is on a TV episode or unset
+func (m Model) movieGroupAtCursor() int {
+	if at := m.duplicateAtCursor(); !at.tv {
+		return at.group
+	}
+	return -1
+}
+
+// toggleVersions switches the selected movie group between deleting the
+// extra copies and keeping them all as Jellyfin versions
+func (m *Model) toggleVersions() {
+	if m.tagCursor < 0 && len(m.taggablePaths()) > 0 {
+		m.tagCursor = 0
+	}
+	i := m.movieGroupAtCursor()
+	if i < 0 {
+		m.tagStatus = WarningStyle.Render("Keeping versions applies to movie duplicates only (Tab selects a group)")
+		m.refreshTagView()
+		return
+	}
+
+	dup := &m.report.MovieDuplicates[i]
+	var status string
+	if dup.KeepsAllVersions() {
+		dup.Strategy = scanner.StrategyDelete
+		status = SuccessStyle.Render(fmt.Sprintf("✓ %s: keep the best copy, delete the rest", filepath.Base(filepath.Dir(dup.Files[0].Path))))
+	} else {
+		dup.Strategy = scanner.StrategyMultiVersion
+		if _, err := scanner.MultiVersionPlan(*dup); err != nil {
+			status = WarningStyle.Render(fmt.Sprintf("Keeping all versions, but the clean will skip this group: %v", err))
+		} else {
+			status = SuccessStyle.Render(fmt.Sprintf("✓ %s: keep every copy as a Jellyfin version", filepath.Base(filepath.Dir(dup.Files[0].Path))))
+		}
+	}
+	m.report.RecountTotals()
+	m.tagStatus = m.saveDecisions(status)
+	m.refreshTagView()
+}
+
+// versionTargets maps each file of a multi-version group to its new name
+func versionTargets(dup scanner.MovieDuplicate) (map[string]string, error) {
+	renames, err := scanner.MultiVersionPlan(dup)
+	if err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(renames))
+	for _, r := range renames {
+		targets[r.Source] = filepath.Base(r.Target)
+	}
+	return targets, nil
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestToggleVersionsFromDuplicatesView(t *testing.T) {
+	report := reporter.Report{
+		MovieDuplicates: []scanner.MovieDuplicate{{
+			NormalizedName: "heat",
+			Year:           "1995",
+			Files: []scanner.MovieFile{
+				{Path: "/movies/Heat (1995)/Heat (1995).mkv", Size: 4000, Resolution: "2160p"},
+				{Path: "/movies/Heat.1995.1080p/heat.mkv", Size: 2000, Resolution: "1080p"},
+			},
+		}},
+		TVDuplicates: []scanner.TVDuplicate{{
+			ShowName: "firefly",
+			Season:   1,
+			Episode:  1,
+			Files: []scanner.TVFile{
+				{Path: "/tv/Firefly/Season 01/Firefly S01E01.mkv", Size: 500},
+				{Path: "/tv/Firefly/Season 01/firefly.s01e01.mkv", Size: 300},
+			},
+		}},
+	}
+	report.RecountTotals()
+
+	m := NewModel(report)
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyF1})
+	if !strings.Contains(model.View(), "M Versions") {
+		t.Error("Expected the versions key in the duplicates footer")
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	m = model.(Model)
+	if m.report.MovieDuplicates[0].Strategy != scanner.StrategyMultiVersion {
+		t.Fatal("Expected M to keep the movie group as versions")
+	}
+	if m.report.SpaceToFree != 300 || m.report.TotalFilesToDelete != 1 {
+		t.Errorf("Expected only the TV copy counted for deletion, got %d files, %d bytes",
+			m.report.TotalFilesToDelete, m.report.SpaceToFree)
+	}
+	if view := m.renderDuplicates(); !strings.Contains(view, "Heat (1995) - 1080p.mkv") {
+		t.Errorf("Expected the version names in the duplicates view:\n%s", view)
+	}
+
+	// TV groups cannot be kept as versions
+	for i := 0; i < 3; i++ {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if !strings.Contains(model.(Model).tagStatus, "movie duplicates only") {
+		t.Errorf("Expected a movies-only notice, got %q", model.(Model).tagStatus)
+	}
+
+	// M again on the movie switches back to deleting
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if model.(Model).report.MovieDuplicates[0].KeepsAllVersions() {
+		t.Error("Expected a second M to switch the group back to delete")
+	}
+}
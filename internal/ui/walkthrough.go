@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
+)
+
+// Walkthrough is what the first-run safety walkthrough explains about an
+// install: how far jellysinkd's unattended auto-clean may go and what
+// cleans are kept away from
+type Walkthrough struct {
+	Enabled bool // ui.first_run_walkthrough
+
+	AutoCleanSeverities   []string // daemon.auto_clean_severities
+	AutoCleanConfirmScans int      // daemon.auto_clean_confirm_scans
+	IdenticalOnly         bool     // autoclean.identical_only
+	MinAgeDays            int      // autoclean.min_age_days
+	MaxDeleteGB           float64  // autoclean.max_delete_gb
+
+	ProtectedPaths []string // cleaner.protected_paths
+	ProtectedTags  []string // tags.protected
+}
+
+// walkthroughPages is how many screens the walkthrough has
+const walkthroughPages = 3
+
+var (
+	walkthrough   Walkthrough
+	walkthroughMu sync.RWMutex
+)
+
+// SetWalkthrough sets what the first-run walkthrough explains, and whether
+// it is on at all
+func SetWalkthrough(w Walkthrough) {
+	walkthroughMu.Lock()
+	defer walkthroughMu.Unlock()
+	walkthrough = w
+}
+
+// currentWalkthrough returns the settings set with SetWalkthrough
+func currentWalkthrough() Walkthrough {
+	walkthroughMu.RLock()
+	defer walkthroughMu.RUnlock()
+	return walkthrough
+}
+
+// isFirstRun reports whether the report view keeps full cleans locked: the
+// walkthrough is on and no clean has been logged on this install yet
+func isFirstRun() bool {
+	if !currentWalkthrough().Enabled {
+		return false
+	}
+	_, err := os.Stat(oplog.DefaultPath(oplog.OperationsLog))
+	return os.IsNotExist(err)
+}
+
+// updateWalkthrough pages through the walkthrough; leaving it returns to
+// the summary
+func (m Model) updateWalkthrough(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "left", "p":
+		if m.walkthroughPage > 0 {
+			m.walkthroughPage--
+		}
+	case "right", "n", "enter":
+		if m.walkthroughPage < walkthroughPages-1 {
+			m.walkthroughPage++
+		} else if msg.String() == "enter" {
+			m.mode = ViewSummary
+			m.viewport.SetContent(m.renderSummary())
+			return m, nil
+		}
+	case "esc":
+		m.mode = ViewSummary
+		m.viewport.SetContent(m.renderSummary())
+		return m, nil
+	default:
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+	m.viewport.SetContent(m.renderWalkthrough())
+	m.viewport.GotoTop()
+	return m, nil
+}
+
+// renderWalkthrough renders the current page of the first-run walkthrough
+func (m Model) renderWalkthrough() string {
+	var sb strings.Builder
+	w := currentWalkthrough()
+
+	switch m.walkthroughPage {
+	case 0:
+		sb.WriteString(TitleStyle.Render("1. READING THE REPORT") + "\n\n")
+		sb.WriteString(InfoStyle.Render("The dry run only read your libraries. Before any clean, check each section:") + "\n\n")
+		sb.WriteString(HighlightStyle.Render("Duplicates (F1)") + "\n")
+		sb.WriteString("  Copies of the same movie or episode. The best copy of each group is kept;\n")
+		sb.WriteString("  a clean deletes the rest. Enter picks another keeper, E excludes a group.\n\n")
+		sb.WriteString(HighlightStyle.Render("Compliance (F2)") + "\n")
+		sb.WriteString("  Files and folders Jellyfin may misidentify, with the name a clean renames\n")
+		sb.WriteString("  them to. V filters by severity, X shows why each was flagged.\n\n")
+		sb.WriteString(HighlightStyle.Render("Manual fixes (F3) and wasted space (F4)") + "\n")
+		sb.WriteString("  Shows whose title jellysink could not settle on are never renamed until you\n")
+		sb.WriteString("  choose one. Wasted space shows which folders the duplicates sit in.\n")
+
+	case 1:
+		sb.WriteString(TitleStyle.Render("2. WHAT A CLEAN WOULD DO") + "\n\n")
+		sb.WriteString(m.cleanResult + "\n\n")
+		sb.WriteString(InfoStyle.Render("A full clean carries out the same operations:") + "\n")
+		sb.WriteString("  • Deleted files are moved to the trash, not removed, until retention_days pass\n")
+		sb.WriteString("  • " + StatStyle.Render("jellysink undo last") + " reverts a whole clean, " + StatStyle.Render("jellysink trash restore") + " single files\n")
+		sb.WriteString("  • Every operation is logged to operations.log\n\n")
+		sb.WriteString(InfoStyle.Render("Unattended auto-clean:") + "\n")
+		sb.WriteString("  When jellysinkd runs without a display it cleans on its own, but only:\n")
+		severities := "none"
+		if len(w.AutoCleanSeverities) > 0 {
+			severities = strings.Join(w.AutoCleanSeverities, ", ")
+		}
+		sb.WriteString(fmt.Sprintf("  • Compliance issues of severity: %s\n", StatStyle.Render(severities)))
+		sb.WriteString(fmt.Sprintf("  • Duplicate groups found unchanged in %s scan(s) in a row\n", StatStyle.Render(fmt.Sprintf("%d", w.AutoCleanConfirmScans))))
+		if w.IdenticalOnly {
+			sb.WriteString("  • Only copies identical byte for byte to the kept one\n")
+		}
+		if w.MinAgeDays > 0 {
+			sb.WriteString(fmt.Sprintf("  • Only files not modified in the last %s day(s)\n", StatStyle.Render(fmt.Sprintf("%d", w.MinAgeDays))))
+		}
+		if w.MaxDeleteGB > 0 {
+			sb.WriteString(fmt.Sprintf("  • At most %s GB deleted per run\n", StatStyle.Render(fmt.Sprintf("%g", w.MaxDeleteGB))))
+		}
+		sb.WriteString(MutedStyle.Render("  Tighten these under [daemon] and [autoclean] in config.toml.") + "\n")
+
+	case 2:
+		sb.WriteString(TitleStyle.Render("3. PROTECTING WHAT MATTERS") + "\n\n")
+		sb.WriteString(InfoStyle.Render("Cleans never delete, rename or move:") + "\n")
+		if len(w.ProtectedPaths) > 0 {
+			for _, path := range w.ProtectedPaths {
+				sb.WriteString("  • " + ContentStyle.Render(path) + "\n")
+			}
+		} else {
+			sb.WriteString(MutedStyle.Render("  • No protected paths yet: list folders under protected_paths in [cleaner]") + "\n")
+		}
+		if len(w.ProtectedTags) > 0 {
+			sb.WriteString(fmt.Sprintf("  • Files tagged %s (T in the report, or jellysink tag <path> <tag>)\n", StatStyle.Render(strings.Join(w.ProtectedTags, ", "))))
+		}
+		sb.WriteString("  • Anything matched by [scan] exclude or a .jellysinkignore file\n\n")
+		sb.WriteString(WarningStyle.Render("⚠ Full cleans stay locked on this install until you unlock them:") + "\n\n")
+		sb.WriteString("  [ui]\n")
+		sb.WriteString("  first_run_walkthrough = false\n\n")
+		sb.WriteString(MutedStyle.Render("jellysink config shows where config.toml is. After the first clean the walkthrough\nis not shown again.") + "\n")
+	}
+
+	return sb.String()
+}
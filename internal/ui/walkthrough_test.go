@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Nomadcxx/jellysink/internal/oplog"
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestFirstRunWalkthrough(t *testing.T) {
+	t.Setenv(oplog.DataDirEnv, t.TempDir())
+	SetWalkthrough(Walkthrough{Enabled: true, AutoCleanSeverities: []string{"error"}, AutoCleanConfirmScans: 2, ProtectedPaths: []string{"/movies/Favourites"}})
+	t.Cleanup(func() { SetWalkthrough(Walkthrough{}) })
+
+	report := reporter.Report{
+		MovieDuplicates: []scanner.MovieDuplicate{{
+			NormalizedName: "heat",
+			Year:           "1995",
+			Files: []scanner.MovieFile{
+				{Path: "/movies/Heat (1995)/Heat (1995).mkv", Size: 4000, Resolution: "2160p"},
+				{Path: "/movies/Heat.1995.1080p/heat.mkv", Size: 2000, Resolution: "1080p"},
+			},
+		}},
+	}
+	report.RecountTotals()
+	key := func(model tea.Model, k string) tea.Model {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "right":
+			msg = tea.KeyMsg{Type: tea.KeyRight}
+		}
+		model, _ = model.Update(msg)
+		return model
+	}
+
+	// On a new install full cleans are locked
+	model, _ := NewModel(report).Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = key(model, "enter")
+	if !strings.Contains(model.(Model).renderCleanOptions(), "Locked on a new install") {
+		t.Error("Expected the full clean shown as locked")
+	}
+	model = key(key(model, "down"), "2")
+	if m := model.(Model); m.mode != ViewCleanOptions || m.cleanOptionCursor != 0 {
+		t.Fatalf("Expected the full clean refused, got mode %d cursor %d", m.mode, m.cleanOptionCursor)
+	}
+
+	// The dry run leads into the walkthrough
+	model = key(model, "enter")
+	if m := model.(Model); !m.dryRun || m.mode != ViewCleaning {
+		t.Fatal("Expected Enter to start a dry run")
+	}
+	model, _ = model.Update(cleanCompleteMsg{result: "1 duplicate would be deleted"})
+	model = key(model, "x")
+	m := model.(Model)
+	if m.mode != ViewWalkthrough {
+		t.Fatalf("Expected the walkthrough after the dry run, got mode %d", m.mode)
+	}
+	model = key(model, "right")
+	page := model.(Model).renderWalkthrough()
+	if !strings.Contains(page, "1 duplicate would be deleted") || !strings.Contains(page, "error") {
+		t.Errorf("Expected the dry run results and auto-clean limits, got:\n%s", page)
+	}
+	model = key(model, "right")
+	if page := model.(Model).renderWalkthrough(); !strings.Contains(page, "/movies/Favourites") || !strings.Contains(page, "first_run_walkthrough = false") {
+		t.Errorf("Expected the protections and how to unlock, got:\n%s", page)
+	}
+	model = key(model, "enter")
+	if model.(Model).mode != ViewSummary {
+		t.Error("Expected Enter on the last page to return to the summary")
+	}
+
+	// Once a clean has been logged, or the flag is off, full cleans unlock
+	os.WriteFile(oplog.DefaultPath(oplog.OperationsLog), nil, 0644)
+	if isFirstRun() {
+		t.Error("Expected a logged clean to end the first run")
+	}
+	os.Remove(oplog.DefaultPath(oplog.OperationsLog))
+	SetWalkthrough(Walkthrough{})
+	model, _ = NewModel(report).Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = key(key(model, "enter"), "2")
+	if model.(Model).mode != ViewCleanConfirm {
+		t.Error("Expected the full clean unlocked with the walkthrough off")
+	}
+}
@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/reporter"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+func TestRenderWastedSpaceNothingReclaimable(t *testing.T) {
+	// Zero-size copies have files to delete but no bytes to share out
+	report := reporter.Report{
+		MovieDuplicates: []scanner.MovieDuplicate{{
+			NormalizedName: "heat",
+			Year:           "1995",
+			Files: []scanner.MovieFile{
+				{Path: "/movies/Heat (1995)/Heat (1995).mkv"},
+				{Path: "/movies/Heat.1995.720p/heat.mkv"},
+			},
+		}},
+	}
+
+	view := NewModel(report).renderWastedSpace()
+	if !strings.Contains(view, "No reclaimable space found.") {
+		t.Errorf("Expected the empty message for a tree without bytes:\n%s", view)
+	}
+}
@@ -0,0 +1,107 @@
+//go:build integration
+
+package test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Nomadcxx/jellysink/internal/cleaner"
+	"github.com/Nomadcxx/jellysink/internal/config"
+	"github.com/Nomadcxx/jellysink/internal/daemon"
+	"github.com/Nomadcxx/jellysink/internal/fixtures"
+	"github.com/Nomadcxx/jellysink/internal/scanner"
+)
+
+// TestEndToEndScanReportClean exercises scan -> report -> API verification ->
+// clean against the synthetic fixture library and the mock metadata API
+// Run with: go test ./... -tags integration
+func TestEndToEndScanReportClean(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SUDO_USER", "")
+
+	lib, err := fixtures.Generate(filepath.Join(home, "library"))
+	if err != nil {
+		t.Fatalf("Failed to generate fixture library: %v", err)
+	}
+
+	api := fixtures.NewMockAPI()
+	defer api.Close()
+	defer api.Install()()
+
+	// Scan
+	cfg := config.DefaultConfig()
+	cfg.Libraries.Movies.Paths = []string{lib.MoviesPath}
+	cfg.Libraries.TV.Paths = []string{lib.TVPath}
+
+	reportPath, err := daemon.New(cfg).RunScan(context.Background())
+	if err != nil {
+		t.Fatalf("RunScan failed: %v", err)
+	}
+
+	// Report
+	report := loadReport(t, reportPath)
+	if len(report.MovieDuplicates) != fixtures.ExpectedMovieDuplicates {
+		t.Errorf("Expected %d movie duplicate groups, got %d", fixtures.ExpectedMovieDuplicates, len(report.MovieDuplicates))
+	}
+	if len(report.TVDuplicates) != fixtures.ExpectedTVDuplicates {
+		t.Errorf("Expected %d TV duplicate groups, got %d", fixtures.ExpectedTVDuplicates, len(report.TVDuplicates))
+	}
+	if len(report.AmbiguousTVShows) != fixtures.ExpectedAmbiguousShows {
+		t.Fatalf("Expected %d ambiguous shows, got %d", fixtures.ExpectedAmbiguousShows, len(report.AmbiguousTVShows))
+	}
+
+	// API verification against the mock server
+	resolution := report.AmbiguousTVShows[0]
+	if err := scanner.VerifyTVShowTitle(resolution, fixtures.MockAPIKey, "", ""); err != nil {
+		t.Fatalf("Mock API verification failed: %v", err)
+	}
+	if !resolution.APIVerified {
+		t.Error("Expected ambiguous show to be API verified")
+	}
+
+	// Clean
+	var deleted []string
+	for _, dup := range report.MovieDuplicates {
+		for _, f := range dup.Files[1:] {
+			deleted = append(deleted, f.Path)
+		}
+	}
+	for _, dup := range report.TVDuplicates {
+		for _, f := range dup.Files[1:] {
+			deleted = append(deleted, f.Path)
+		}
+	}
+
+	cleanConfig := cleaner.DefaultConfig()
+	cleanConfig.ProtectedPaths = []string{}
+	cleanConfig.LogPath = filepath.Join(home, "operations.log")
+
+	result, err := cleaner.Clean(report.MovieDuplicates, report.TVDuplicates, report.ComplianceIssues, cleanConfig)
+	if err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+	if result.DuplicatesDeleted != len(deleted) {
+		t.Errorf("Expected %d duplicates deleted, got %d", len(deleted), result.DuplicatesDeleted)
+	}
+	for _, path := range deleted {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be deleted", path)
+		}
+	}
+
+	// Rescan: duplicates are gone
+	rescan, err := scanner.RunFullScan(context.Background(), []string{lib.MoviesPath}, []string{lib.TVPath}, nil)
+	if err != nil {
+		t.Fatalf("Rescan failed: %v", err)
+	}
+	if rescan.TotalDuplicates != 0 {
+		t.Errorf("Expected no duplicates after clean, got %d", rescan.TotalDuplicates)
+	}
+	if len(rescan.ComplianceIssues) >= len(report.ComplianceIssues) {
+		t.Errorf("Expected fewer compliance issues after clean, had %d now %d", len(report.ComplianceIssues), len(rescan.ComplianceIssues))
+	}
+}